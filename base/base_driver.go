@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -24,6 +26,16 @@ type Driver struct {
 	SchemasMu   sync.RWMutex
 	Logger      logger.Logger
 	dbLogger    *DBLogger // For SQL-specific logging
+
+	// ChangePollInterval controls the polling change feed (defaults to DefaultChangePollInterval,
+	// or DefaultFullScanPollInterval when a watched model has no @updatedAt field)
+	ChangePollInterval time.Duration
+
+	statementTimeout time.Duration
+	maxIncludeDepth  int
+
+	monitor   *connectionMonitor // Health checks, once started
+	monitorMu sync.Mutex
 }
 
 // NewDriver creates a new base driver instance
@@ -60,6 +72,10 @@ func (b *Driver) RegisterSchema(modelName string, sch *schema.Schema) error {
 	defer b.SchemasMu.Unlock()
 
 	b.Schemas[modelName] = sch
+	for _, other := range b.Schemas {
+		typeUUIDForeignKeys(sch, other)
+		typeUUIDForeignKeys(other, sch)
+	}
 
 	// Register with field mapper
 	// First try direct cast to DefaultFieldMapper
@@ -79,6 +95,24 @@ func (b *Driver) RegisterSchema(modelName string, sch *schema.Schema) error {
 	return nil
 }
 
+// typeUUIDForeignKeys gives the untyped foreign keys of s referencing fields of referenced
+// holding UUIDs the native type @db.Uuid, so that their columns take the UUID type of the
+// columns they reference
+func typeUUIDForeignKeys(s, referenced *schema.Schema) {
+	for _, relation := range s.Relations {
+		if relation.Model != referenced.Name ||
+			(relation.Type != schema.RelationManyToOne && relation.Type != schema.RelationOneToOne) {
+			continue
+		}
+		localField, relatedField := relation.JoinFields(s)
+		field, target := s.GetFieldByName(localField), referenced.GetFieldByName(relatedField)
+		if field != nil && target != nil && target.HoldsUUID() && !field.HoldsUUID() &&
+			field.Type == schema.FieldTypeString && field.DbType == "" {
+			field.DbType = "@db.Uuid"
+		}
+	}
+}
+
 // GetSchema returns a registered schema
 func (b *Driver) GetSchema(modelName string) (*schema.Schema, error) {
 	b.SchemasMu.RLock()
@@ -96,11 +130,7 @@ func (b *Driver) GetModels() []string {
 	b.SchemasMu.RLock()
 	defer b.SchemasMu.RUnlock()
 
-	models := make([]string, 0, len(b.Schemas))
-	for modelName := range b.Schemas {
-		models = append(models, modelName)
-	}
-	return models
+	return slices.Sorted(maps.Keys(b.Schemas))
 }
 
 // GetModelSchema returns schema for a model (alias for GetSchema)
@@ -108,6 +138,58 @@ func (b *Driver) GetModelSchema(modelName string) (*schema.Schema, error) {
 	return b.GetSchema(modelName)
 }
 
+// GetModelField returns a field of a registered model
+func (b *Driver) GetModelField(modelName, fieldName string) (*schema.Field, error) {
+	sch, err := b.GetSchema(modelName)
+	if err != nil {
+		return nil, err
+	}
+	field := sch.GetFieldByName(fieldName)
+	if field == nil {
+		return nil, fmt.Errorf("field %s not found in model %s", fieldName, modelName)
+	}
+	return field, nil
+}
+
+// GetModelRelations returns the relations of a registered model, sorted by name
+func (b *Driver) GetModelRelations(modelName string) ([]types.RelationInfo, error) {
+	sch, err := b.GetSchema(modelName)
+	if err != nil {
+		return nil, err
+	}
+	relations := make([]types.RelationInfo, 0, len(sch.Relations))
+	for _, name := range slices.Sorted(maps.Keys(sch.Relations)) {
+		info, _ := b.resolveRelation(sch, name)
+		relations = append(relations, info)
+	}
+	return relations, nil
+}
+
+// ResolveRelation returns a relation of a registered model with the schema of its related
+// model and the fields joining them
+func (b *Driver) ResolveRelation(modelName, relationName string) (types.RelationInfo, error) {
+	sch, err := b.GetSchema(modelName)
+	if err != nil {
+		return types.RelationInfo{}, err
+	}
+	return b.resolveRelation(sch, relationName)
+}
+
+func (b *Driver) resolveRelation(sch *schema.Schema, relationName string) (types.RelationInfo, error) {
+	relation, err := sch.GetRelation(relationName)
+	if err != nil {
+		return types.RelationInfo{}, fmt.Errorf("model %s: %w", sch.Name, err)
+	}
+	info := types.RelationInfo{Model: sch.Name, Name: relationName, Relation: relation}
+	if relation.Model != "" {
+		info.Related, _ = b.GetSchema(relation.Model)
+	}
+	if relation.Type != schema.RelationManyToMany {
+		info.LocalField, info.RelatedField = relation.JoinFields(sch)
+	}
+	return info, nil
+}
+
 // CreateModel creates a single model table in the database
 func (b *Driver) CreateModel(ctx context.Context, db types.Database, modelName string) error {
 	// Get the schema for this model
@@ -377,6 +459,7 @@ func (b *Driver) Ping(ctx context.Context) error {
 
 // Close closes the database connection
 func (b *Driver) Close() error {
+	b.StopHealthCheck()
 	if b.DB != nil {
 		return b.DB.Close()
 	}
@@ -396,6 +479,19 @@ func (b *Driver) Exec(query string, args ...any) (sql.Result, error) {
 	return result, err
 }
 
+// ExecContext executes a raw SQL statement, canceled when ctx is done
+func (b *Driver) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	start := time.Now()
+	result, err := b.DB.ExecContext(ctx, query, args...)
+	duration := time.Since(start)
+
+	if b.dbLogger != nil {
+		b.dbLogger.LogSQLContext(ctx, query, args, duration)
+	}
+
+	return result, err
+}
+
 // Query executes a raw SQL query that returns rows
 func (b *Driver) Query(query string, args ...any) (*sql.Rows, error) {
 	start := time.Now()
@@ -422,11 +518,59 @@ func (b *Driver) QueryRow(query string, args ...any) *sql.Row {
 	return row
 }
 
+// QueryContext executes a raw SQL query that returns rows, canceled when ctx is done
+func (b *Driver) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := b.DB.QueryContext(ctx, query, args...)
+	duration := time.Since(start)
+
+	if b.dbLogger != nil {
+		b.dbLogger.LogSQLContext(ctx, query, args, duration)
+	}
+
+	return rows, err
+}
+
+// QueryRowContext executes a raw SQL query that returns a single row, canceled when ctx is done
+func (b *Driver) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	start := time.Now()
+	row := b.DB.QueryRowContext(ctx, query, args...)
+	duration := time.Since(start)
+
+	if b.dbLogger != nil {
+		b.dbLogger.LogSQLContext(ctx, query, args, duration)
+	}
+
+	return row
+}
+
+// SetStatementTimeout sets the default timeout of statements run by queries without a
+// timeout of their own. Drivers that enforce it on the server apply it when connecting.
+func (b *Driver) SetStatementTimeout(timeout time.Duration) {
+	b.statementTimeout = timeout
+}
+
+// GetStatementTimeout returns the default statement timeout, 0 when there is none
+func (b *Driver) GetStatementTimeout() time.Duration {
+	return b.statementTimeout
+}
+
+// SetMaxIncludeDepth sets how many relations include paths may go through, 0 for
+// types.DefaultMaxIncludeDepth
+func (b *Driver) SetMaxIncludeDepth(depth int) {
+	b.maxIncludeDepth = depth
+}
+
+// GetMaxIncludeDepth returns the include depth limit, 0 when the default applies
+func (b *Driver) GetMaxIncludeDepth() int {
+	return b.maxIncludeDepth
+}
+
 // SetLogger sets the logger for the driver
 func (b *Driver) SetLogger(l logger.Logger) {
 	b.Logger = l
 	// Create or update the DB logger
-	b.dbLogger = NewDBLogger(l)
+	b.dbLogger = NewDBLogger(b.Log())
 }
 
 // GetLogger returns the logger for the driver
@@ -434,6 +578,12 @@ func (b *Driver) GetLogger() logger.Logger {
 	return b.Logger
 }
 
+// Log returns the logger for the diagnostics of the driver, which prefixes messages with
+// the driver type. It logs nothing until a logger is set.
+func (b *Driver) Log() logger.Logger {
+	return logger.WithComponent(b.Logger, string(b.DriverType))
+}
+
 // syncSchemasWithDeferredConstraints handles circular dependencies by creating tables without FK first
 func (b *Driver) syncSchemasWithDeferredConstraints(ctx context.Context, db types.Database, schemas map[string]*schema.Schema, currentTableMap map[string]bool) error {
 	migrator := db.GetMigrator()
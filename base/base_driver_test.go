@@ -0,0 +1,72 @@
+package base
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rediwo/redi-orm/prisma"
+	"github.com/rediwo/redi-orm/types"
+)
+
+func TestSchemaRegistryInspection(t *testing.T) {
+	schemas, err := prisma.ParseSchema(`
+model User {
+  id    Int    @id @default(autoincrement())
+  email String
+  posts Post[]
+}
+
+model Post {
+  id       Int  @id @default(autoincrement())
+  authorId Int
+  author   User @relation(fields: [authorId], references: [id])
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	driver := NewDriver("test://", types.DriverType("test"))
+	for _, name := range []string{"User", "Post"} {
+		if err := driver.RegisterSchema(name, schemas[name]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if models := driver.GetModels(); !reflect.DeepEqual(models, []string{"Post", "User"}) {
+		t.Errorf("Expected sorted models, got %v", models)
+	}
+
+	field, err := driver.GetModelField("User", "email")
+	if err != nil || field.Name != "email" {
+		t.Errorf("Expected the email field, got %v, %v", field, err)
+	}
+	if _, err := driver.GetModelField("User", "missing"); err == nil {
+		t.Error("Expected an error for an unknown field")
+	}
+	if _, err := driver.GetModelField("Missing", "id"); err == nil {
+		t.Error("Expected an error for an unknown model")
+	}
+
+	relations, err := driver.GetModelRelations("User")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(relations) != 1 || relations[0].Name != "posts" {
+		t.Fatalf("Expected the posts relation, got %+v", relations)
+	}
+	posts := relations[0]
+	if posts.Model != "User" || posts.Related != schemas["Post"] || posts.LocalField != "id" || posts.RelatedField != "authorId" {
+		t.Errorf("Unexpected posts relation %+v", posts)
+	}
+
+	author, err := driver.ResolveRelation("Post", "author")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if author.Related != schemas["User"] || author.LocalField != "authorId" || author.RelatedField != "id" {
+		t.Errorf("Unexpected author relation %+v", author)
+	}
+	if _, err := driver.ResolveRelation("Post", "missing"); err == nil {
+		t.Error("Expected an error for an unknown relation")
+	}
+}
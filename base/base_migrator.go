@@ -149,6 +149,15 @@ func (b *BaseMigrator) CompareSchema(existingTable *types.TableInfo, desiredSche
 	return plan, nil
 }
 
+// quoteIdentifier quotes a name with the quoter of the database-specific migrator, if it
+// has one
+func (b *BaseMigrator) quoteIdentifier(name string) string {
+	if quoter, ok := b.specific.(types.IdentifierQuoter); ok {
+		return quoter.QuoteIdentifier(name)
+	}
+	return name
+}
+
 // GenerateMigrationSQL generates SQL statements for a migration plan
 // This provides a common implementation that databases can override if needed
 func (b *BaseMigrator) GenerateMigrationSQL(plan *types.MigrationPlan) ([]string, error) {
@@ -166,7 +175,7 @@ func (b *BaseMigrator) GenerateMigrationSQL(plan *types.MigrationPlan) ([]string
 		}
 
 		columnDef := b.specific.GenerateColumnDefinitionFromColumnInfo(*change.NewColumn)
-		sql := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", change.TableName, columnDef)
+		sql := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", b.quoteIdentifier(change.TableName), columnDef)
 		sqlStatements = append(sqlStatements, sql)
 	}
 
@@ -0,0 +1,384 @@
+package base
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
+)
+
+// DefaultChangePollInterval is the interval used by the polling change feed
+const DefaultChangePollInterval = time.Second
+
+// DefaultFullScanPollInterval is the interval of the polling change feed when it watches a
+// model without an @updatedAt field, which it reads in full on every poll, and
+// ChangePollInterval is not set
+const DefaultFullScanPollInterval = 30 * time.Second
+
+// changePollLookback is how far before the latest @updatedAt value seen a poll reads again
+const changePollLookback = 5 * time.Second
+
+// ChangeFeed is a reusable types.ChangeStream implementation used by drivers
+type ChangeFeed struct {
+	events chan types.ChangeEvent
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+	mu     sync.Mutex
+	err    error
+}
+
+// NewChangeFeed creates a change feed bound to ctx. The returned context is
+// cancelled when the feed is closed and should be used by the producer.
+func NewChangeFeed(ctx context.Context) (*ChangeFeed, context.Context) {
+	feedCtx, cancel := context.WithCancel(ctx)
+	return &ChangeFeed{
+		events: make(chan types.ChangeEvent, 64),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}, feedCtx
+}
+
+// Events returns the event channel
+func (f *ChangeFeed) Events() <-chan types.ChangeEvent {
+	return f.events
+}
+
+// Err returns the error that stopped the feed
+func (f *ChangeFeed) Err() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.err
+}
+
+// Close stops the feed and waits for the producer to finish
+func (f *ChangeFeed) Close() error {
+	f.cancel()
+	<-f.done
+	return nil
+}
+
+// Emit sends an event to consumers. It returns false when the feed has been stopped.
+func (f *ChangeFeed) Emit(ctx context.Context, event types.ChangeEvent) bool {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	select {
+	case f.events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Finish marks the producer as done, recording err (if any) and closing the event channel
+func (f *ChangeFeed) Finish(err error) {
+	f.once.Do(func() {
+		if err != nil && err != context.Canceled {
+			f.mu.Lock()
+			f.err = err
+			f.mu.Unlock()
+		}
+		close(f.events)
+		close(f.done)
+	})
+}
+
+// ResolveWatchModels validates the requested models, defaulting to all registered models
+func (b *Driver) ResolveWatchModels(models []string) ([]string, error) {
+	if len(models) == 0 {
+		models = b.GetModels()
+	}
+	if len(models) == 0 {
+		return nil, fmt.Errorf("no models registered to watch")
+	}
+	for _, modelName := range models {
+		if _, err := b.GetSchema(modelName); err != nil {
+			return nil, err
+		}
+	}
+	return models, nil
+}
+
+// Watch implements a polling change feed for drivers without native change
+// notifications. Models with an @updatedAt field are polled on it as a cursor: each poll
+// reads the rows changed since the last one and counts the rows, reading the primary keys
+// only when the count shows deletes. Models without one are read in full and diffed by
+// primary key on every poll, so their cost grows with the table; they are polled every
+// DefaultFullScanPollInterval unless ChangePollInterval is set.
+func (b *Driver) Watch(ctx context.Context, db types.Database, models ...string) (types.ChangeStream, error) {
+	models, err := b.ResolveWatchModels(models)
+	if err != nil {
+		return nil, err
+	}
+
+	// Take the initial snapshots synchronously so that changes made right
+	// after Watch returns are reported
+	pollers := make([]*modelPoller, 0, len(models))
+	interval := b.ChangePollInterval
+	for _, modelName := range models {
+		poller, err := b.newModelPoller(ctx, db, modelName)
+		if err != nil {
+			return nil, err
+		}
+		pollers = append(pollers, poller)
+		if interval <= 0 && poller.updatedAt == "" {
+			interval = DefaultFullScanPollInterval
+		}
+	}
+	if interval <= 0 {
+		interval = DefaultChangePollInterval
+	}
+
+	feed, feedCtx := NewChangeFeed(ctx)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-feedCtx.Done():
+				feed.Finish(feedCtx.Err())
+				return
+			case <-ticker.C:
+			}
+
+			for _, poller := range pollers {
+				events, err := poller.poll(feedCtx, db)
+				if err != nil {
+					if feedCtx.Err() != nil {
+						feed.Finish(feedCtx.Err())
+					} else {
+						feed.Finish(err)
+					}
+					return
+				}
+				for _, event := range events {
+					if !feed.Emit(feedCtx, event) {
+						feed.Finish(feedCtx.Err())
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return feed, nil
+}
+
+// modelPoller holds the rows of a watched model seen by the polling change feed
+type modelPoller struct {
+	driver    *Driver
+	model     string
+	schema    *schema.Schema
+	updatedAt string // @updatedAt field polled as a cursor, empty to read the model in full
+	rows      map[string]rowSnapshot
+	cursor    time.Time // Latest @updatedAt value seen
+}
+
+// newModelPoller reads the current rows of a model
+func (b *Driver) newModelPoller(ctx context.Context, db types.Database, modelName string) (*modelPoller, error) {
+	sch, err := b.GetSchema(modelName)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := b.snapshotModel(ctx, db, modelName)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &modelPoller{driver: b, model: modelName, schema: sch, rows: rows}
+	if fields := sch.GetUpdatedAtFields(); len(fields) > 0 {
+		p.updatedAt = fields[0]
+		for _, row := range rows {
+			p.advance(row.data)
+		}
+	}
+	return p, nil
+}
+
+// poll returns the changes of the model since the last poll
+func (p *modelPoller) poll(ctx context.Context, db types.Database) ([]types.ChangeEvent, error) {
+	if p.updatedAt == "" {
+		current, err := p.driver.snapshotModel(ctx, db, p.model)
+		if err != nil {
+			return nil, err
+		}
+		events := diffSnapshots(p.model, p.rows, current)
+		p.rows = current
+		return events, nil
+	}
+
+	// Rows committed late with an earlier @updatedAt value are read again within the
+	// lookback; rows that did not change since are skipped by their fingerprint
+	query := db.Model(p.model).Select()
+	if !p.cursor.IsZero() {
+		query = query.WhereCondition(db.Model(p.model).Where(p.updatedAt).GreaterThanOrEqual(p.cursor.Add(-changePollLookback)))
+	}
+	var changed []map[string]any
+	if err := query.FindMany(ctx, &changed); err != nil {
+		return nil, fmt.Errorf("failed to poll model %s: %w", p.model, err)
+	}
+
+	var events []types.ChangeEvent
+	now := time.Now()
+	for _, row := range changed {
+		fingerprint, err := json.Marshal(row)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fingerprint row of model %s: %w", p.model, err)
+		}
+		key := rowKey(p.schema, row)
+		old, existed := p.rows[key]
+		switch {
+		case !existed:
+			events = append(events, types.ChangeEvent{Model: p.model, Operation: types.ChangeCreate, Data: row, Timestamp: now})
+		case old.fingerprint != string(fingerprint):
+			events = append(events, types.ChangeEvent{Model: p.model, Operation: types.ChangeUpdate, Data: row, Timestamp: now})
+		default:
+			continue
+		}
+		p.rows[key] = rowSnapshot{data: row, fingerprint: string(fingerprint)}
+		p.advance(row)
+	}
+
+	// Fewer rows than seen means some were deleted, found by reading the primary keys
+	count, err := db.Model(p.model).Select().Count(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to poll model %s: %w", p.model, err)
+	}
+	if int(count) < len(p.rows) {
+		deleted, err := p.deletedKeys(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range deleted {
+			events = append(events, types.ChangeEvent{Model: p.model, Operation: types.ChangeDelete, Data: p.rows[key].data, Timestamp: now})
+			delete(p.rows, key)
+		}
+	}
+	return events, nil
+}
+
+// advance moves the cursor to the @updatedAt value of row when it is later
+func (p *modelPoller) advance(row map[string]any) {
+	if t, err := utils.ToTime(row[p.updatedAt]); err == nil && t.After(p.cursor) {
+		p.cursor = t
+	}
+}
+
+// deletedKeys returns the keys of the rows seen that are no longer in the model
+func (p *modelPoller) deletedKeys(ctx context.Context, db types.Database) ([]string, error) {
+	keyFields := p.schema.CompositeKey
+	if len(keyFields) == 0 {
+		pk, err := p.schema.GetPrimaryKey()
+		if err != nil {
+			return nil, err
+		}
+		keyFields = []string{pk.Name}
+	}
+
+	var keys []map[string]any
+	if err := db.Model(p.model).Select(keyFields...).FindMany(ctx, &keys); err != nil {
+		return nil, fmt.Errorf("failed to poll model %s: %w", p.model, err)
+	}
+	existing := make(map[string]bool, len(keys))
+	for _, row := range keys {
+		existing[rowKey(p.schema, row)] = true
+	}
+
+	var deleted []string
+	for key := range p.rows {
+		if !existing[key] {
+			deleted = append(deleted, key)
+		}
+	}
+	sort.Strings(deleted)
+	return deleted, nil
+}
+
+// rowSnapshot holds a row and its fingerprint for change detection
+type rowSnapshot struct {
+	data        map[string]any
+	fingerprint string
+}
+
+// snapshotModel loads all rows of a model keyed by primary key
+func (b *Driver) snapshotModel(ctx context.Context, db types.Database, modelName string) (map[string]rowSnapshot, error) {
+	sch, err := b.GetSchema(modelName)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]any
+	if err := db.Model(modelName).Select().FindMany(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("failed to poll model %s: %w", modelName, err)
+	}
+
+	snap := make(map[string]rowSnapshot, len(rows))
+	for _, row := range rows {
+		fingerprint, err := json.Marshal(row)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fingerprint row of model %s: %w", modelName, err)
+		}
+		snap[rowKey(sch, row)] = rowSnapshot{data: row, fingerprint: string(fingerprint)}
+	}
+	return snap, nil
+}
+
+// rowKey builds a stable key from the primary key fields of a row
+func rowKey(sch *schema.Schema, row map[string]any) string {
+	keyFields := sch.CompositeKey
+	if len(keyFields) == 0 {
+		if pk, err := sch.GetPrimaryKey(); err == nil {
+			keyFields = []string{pk.Name}
+		}
+	}
+	key := make([]any, len(keyFields))
+	for i, fieldName := range keyFields {
+		key[i] = row[fieldName]
+	}
+	encoded, _ := json.Marshal(key)
+	return string(encoded)
+}
+
+// diffSnapshots returns the events needed to go from previous to current
+func diffSnapshots(modelName string, previous, current map[string]rowSnapshot) []types.ChangeEvent {
+	var events []types.ChangeEvent
+	now := time.Now()
+
+	keys := make([]string, 0, len(current))
+	for key := range current {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		row := current[key]
+		old, existed := previous[key]
+		switch {
+		case !existed:
+			events = append(events, types.ChangeEvent{Model: modelName, Operation: types.ChangeCreate, Data: row.data, Timestamp: now})
+		case old.fingerprint != row.fingerprint:
+			events = append(events, types.ChangeEvent{Model: modelName, Operation: types.ChangeUpdate, Data: row.data, Timestamp: now})
+		}
+	}
+
+	deleted := make([]string, 0)
+	for key := range previous {
+		if _, exists := current[key]; !exists {
+			deleted = append(deleted, key)
+		}
+	}
+	sort.Strings(deleted)
+	for _, key := range deleted {
+		events = append(events, types.ChangeEvent{Model: modelName, Operation: types.ChangeDelete, Data: previous[key].data, Timestamp: now})
+	}
+
+	return events
+}
@@ -0,0 +1,113 @@
+package base
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rediwo/redi-orm/logger"
+	"github.com/rediwo/redi-orm/types"
+)
+
+const (
+	// minReconnectDelay is the first delay between the pings of a reconnecting connection,
+	// doubling up to the health check interval
+	minReconnectDelay = 100 * time.Millisecond
+
+	// maxPingTimeout bounds each health check ping
+	maxPingTimeout = 5 * time.Second
+)
+
+// connectionMonitor pings a database periodically and tracks the state of its connection
+type connectionMonitor struct {
+	mu     sync.Mutex
+	status types.ConnectionStatus
+	stop   chan struct{}
+	once   sync.Once
+}
+
+// StartHealthCheck pings the database every interval, reconnecting when it stops answering
+func (b *Driver) StartHealthCheck(interval time.Duration) (stop func()) {
+	return b.MonitorConnection(interval, b.Ping)
+}
+
+// MonitorConnection starts the health checks of StartHealthCheck with ping, for drivers that
+// ping their server otherwise. A failed ping makes the connection reconnecting: pings retry
+// with exponential backoff until one succeeds. The pools of database/sql redial the server
+// for them, dropping the connections its restart broke. Starting health checks again
+// replaces the previous ones.
+func (b *Driver) MonitorConnection(interval time.Duration, ping func(ctx context.Context) error) (stop func()) {
+	m := &connectionMonitor{
+		status: types.ConnectionStatus{State: types.ConnectionConnected, Since: time.Now()},
+		stop:   make(chan struct{}),
+	}
+
+	b.monitorMu.Lock()
+	if b.monitor != nil {
+		b.monitor.halt()
+	}
+	b.monitor = m
+	b.monitorMu.Unlock()
+
+	go m.run(interval, ping, b.Log())
+	return m.halt
+}
+
+// ConnectionStatus returns the status of the connection, once health checks are started
+func (b *Driver) ConnectionStatus() (types.ConnectionStatus, bool) {
+	b.monitorMu.Lock()
+	m := b.monitor
+	b.monitorMu.Unlock()
+	if m == nil {
+		return types.ConnectionStatus{}, false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status, true
+}
+
+// StopHealthCheck stops the health checks of the database, if started
+func (b *Driver) StopHealthCheck() {
+	b.monitorMu.Lock()
+	defer b.monitorMu.Unlock()
+	if b.monitor != nil {
+		b.monitor.halt()
+		b.monitor = nil
+	}
+}
+
+func (m *connectionMonitor) halt() {
+	m.once.Do(func() { close(m.stop) })
+}
+
+func (m *connectionMonitor) run(interval time.Duration, ping func(ctx context.Context) error, log logger.Logger) {
+	delay := interval
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-time.After(delay):
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), min(interval, maxPingTimeout))
+		err := ping(ctx)
+		cancel()
+
+		m.mu.Lock()
+		switch {
+		case err == nil && m.status.State == types.ConnectionReconnecting:
+			log.Info("Reconnected to the database after %v", time.Since(m.status.Since).Round(time.Millisecond))
+			m.status = types.ConnectionStatus{State: types.ConnectionConnected, Since: time.Now(), Reconnects: m.status.Reconnects + 1}
+			delay = interval
+		case err != nil && m.status.State == types.ConnectionConnected:
+			m.status = types.ConnectionStatus{State: types.ConnectionReconnecting, Since: time.Now(), Reconnects: m.status.Reconnects, Error: err.Error()}
+			log.Warn("Database ping failed, reconnecting: %v", err)
+			delay = min(minReconnectDelay, interval)
+		case err != nil:
+			m.status.Error = err.Error()
+			delay = min(delay*2, interval)
+		}
+		m.mu.Unlock()
+	}
+}
@@ -0,0 +1,64 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rediwo/redi-orm/types"
+)
+
+func TestMonitorConnection(t *testing.T) {
+	driver := NewDriver("", types.DriverSQLite)
+	if _, ok := driver.ConnectionStatus(); ok {
+		t.Fatal("ConnectionStatus() reported a status before health checks started")
+	}
+
+	// The server goes down after the first ping, and comes back after three more
+	var pings atomic.Int32
+	ping := func(ctx context.Context) error {
+		if n := pings.Add(1); n > 1 && n <= 4 {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+	stop := driver.MonitorConnection(5*time.Millisecond, ping)
+	defer stop()
+
+	waitFor := func(condition func(types.ConnectionStatus) bool) types.ConnectionStatus {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			status, ok := driver.ConnectionStatus()
+			if ok && condition(status) {
+				return status
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("ConnectionStatus() = %+v, condition not met", status)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	status := waitFor(func(s types.ConnectionStatus) bool { return s.State == types.ConnectionReconnecting })
+	if status.Error != "connection refused" {
+		t.Errorf("reconnecting status error = %q, want the ping error", status.Error)
+	}
+	status = waitFor(func(s types.ConnectionStatus) bool { return s.Reconnects == 1 })
+	if status.State != types.ConnectionConnected || status.Error != "" {
+		t.Errorf("status after reconnecting = %+v, want connected", status)
+	}
+
+	// Stopped health checks no longer ping, but for one that was due
+	driver.StopHealthCheck()
+	if _, ok := driver.ConnectionStatus(); ok {
+		t.Error("ConnectionStatus() reported a status after health checks stopped")
+	}
+	count := pings.Load()
+	time.Sleep(20 * time.Millisecond)
+	if pings.Load() > count+1 {
+		t.Error("health checks pinged after they stopped")
+	}
+}
@@ -1,6 +1,7 @@
 package base
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -23,12 +24,21 @@ func NewDBLogger(l logger.Logger) *DBLogger {
 
 // LogSQL logs SQL query with parameters and duration
 func (l *DBLogger) LogSQL(sql string, args []any, duration time.Duration) {
+	l.logSQL("", sql, args, duration)
+}
+
+// LogSQLContext logs SQL like LogSQL, prefixed with the correlation ID of ctx, if any
+func (l *DBLogger) LogSQLContext(ctx context.Context, sql string, args []any, duration time.Duration) {
+	l.logSQL(correlationPrefix(ctx), sql, args, duration)
+}
+
+func (l *DBLogger) logSQL(prefix, sql string, args []any, duration time.Duration) {
 	if l.GetLevel() >= logger.LogLevelDebug {
 		// Format SQL for readability
 		formattedSQL := strings.TrimSpace(sql)
 
 		// Log the SQL with duration
-		l.Debug("SQL (%v):\n%s", duration, formattedSQL)
+		l.Debug("%sSQL (%v):\n%s", prefix, duration, formattedSQL)
 
 		// Log parameters if any
 		if len(args) > 0 {
@@ -36,14 +46,27 @@ func (l *DBLogger) LogSQL(sql string, args []any, duration time.Duration) {
 			for i, arg := range args {
 				argsStr[i] = fmt.Sprintf("%v", arg)
 			}
-			l.Debug("Args: [%s]", strings.Join(argsStr, ", "))
+			l.Debug("%sArgs: [%s]", prefix, strings.Join(argsStr, ", "))
 		}
 	}
 }
 
 // LogCommand logs a command (like MongoDB commands) with duration
 func (l *DBLogger) LogCommand(command string, duration time.Duration) {
+	l.LogCommandContext(context.Background(), command, duration)
+}
+
+// LogCommandContext logs a command like LogCommand, prefixed with the correlation ID of ctx, if any
+func (l *DBLogger) LogCommandContext(ctx context.Context, command string, duration time.Duration) {
 	if l.GetLevel() >= logger.LogLevelDebug {
-		l.Debug("Command (%v):\n%s", duration, command)
+		l.Debug("%sCommand (%v):\n%s", correlationPrefix(ctx), duration, command)
+	}
+}
+
+// correlationPrefix returns "[id] " for the correlation ID of ctx (see logger.WithCorrelationID)
+func correlationPrefix(ctx context.Context) string {
+	if id := logger.CorrelationID(ctx); id != "" {
+		return "[" + id + "] "
 	}
+	return ""
 }
@@ -0,0 +1,48 @@
+package base
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+)
+
+// StaleMigrationLockAge is the age after which a migration lock that is not released with the
+// connection holding it is considered left by a process that crashed, and taken over
+const StaleMigrationLockAge = 10 * time.Minute
+
+// MigrationLockPollInterval is how often a process waiting for a migration lock tries again
+var MigrationLockPollInterval = 500 * time.Millisecond
+
+// WaitForMigrationLock calls tryLock until it takes the lock, giving up after timeout or
+// when ctx is done
+func WaitForMigrationLock(ctx context.Context, timeout time.Duration, tryLock func() (bool, error)) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		locked, err := tryLock()
+		if err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		if locked {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s waiting for the migration lock", timeout)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(MigrationLockPollInterval):
+		}
+	}
+}
+
+// MigrationLockOwner identifies this process as the holder of a migration lock
+func MigrationLockOwner() string {
+	host, _ := os.Hostname()
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return fmt.Sprintf("%s:%d:%s", host, os.Getpid(), hex.EncodeToString(suffix))
+}
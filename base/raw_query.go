@@ -0,0 +1,30 @@
+package base
+
+import (
+	"context"
+
+	"github.com/rediwo/redi-orm/types"
+)
+
+// errorRawQuery is returned by Raw when the statement cannot be prepared; every
+// execution method reports the preparation error
+type errorRawQuery struct {
+	err error
+}
+
+// NewErrorRawQuery creates a raw query that fails with err when executed
+func NewErrorRawQuery(err error) types.RawQuery {
+	return &errorRawQuery{err: err}
+}
+
+func (q *errorRawQuery) Exec(ctx context.Context) (types.Result, error) {
+	return types.Result{}, q.err
+}
+
+func (q *errorRawQuery) Find(ctx context.Context, dest any) error {
+	return q.err
+}
+
+func (q *errorRawQuery) FindOne(ctx context.Context, dest any) error {
+	return q.err
+}
@@ -39,6 +39,15 @@ func (tu *TransactionUtils) CreateMany(ctx context.Context, modelName string, da
 		return types.Result{}, fmt.Errorf("failed to get schema for model %s: %w", modelName, err)
 	}
 
+	// Check validation rules before touching the database
+	for _, record := range data {
+		if recordMap, ok := record.(map[string]any); ok {
+			if err := schema.ValidateData(recordMap); err != nil {
+				return types.Result{}, err
+			}
+		}
+	}
+
 	// Get table name
 	tableName, err := tu.db.ResolveTableName(modelName)
 	if err != nil {
@@ -79,6 +88,11 @@ func (tu *TransactionUtils) UpdateMany(ctx context.Context, modelName string, co
 	if !ok {
 		return types.Result{}, fmt.Errorf("update data must be a map")
 	}
+	if modelSchema, err := tu.db.GetSchema(modelName); err == nil {
+		if err := modelSchema.ValidateData(dataMap); err != nil {
+			return types.Result{}, err
+		}
+	}
 
 	// Build UPDATE SQL
 	sql, args, err := tu.buildUpdateSQL(tableName, modelName, dataMap, condition)
@@ -221,7 +235,8 @@ func (tu *TransactionUtils) buildUpdateSQL(tableName, modelName string, data map
 				db:        tu.db,
 				modelName: modelName,
 			},
-			ModelName: modelName,
+			ModelName:    modelName,
+			Capabilities: tu.db.GetCapabilities(),
 		}
 		whereSQL, whereArgs := condition.ToSQL(conditionContext)
 		if whereSQL != "" {
@@ -252,7 +267,8 @@ func (tu *TransactionUtils) buildDeleteSQL(tableName, modelName string, conditio
 				db:        tu.db,
 				modelName: modelName,
 			},
-			ModelName: modelName,
+			ModelName:    modelName,
+			Capabilities: tu.db.GetCapabilities(),
 		}
 		whereSQL, whereArgs := condition.ToSQL(conditionContext)
 		if whereSQL != "" {
@@ -40,6 +40,30 @@ func (m *mockCapabilities) GetNullsOrderingSQL(direction types.Order, nullsFirst
 	return "NULLS LAST"
 }
 
+func (m *mockCapabilities) GetUpsertSQL(conflictColumns []string, updateColumns []string) string {
+	return ""
+}
+
+func (m *mockCapabilities) GetSkipDuplicatesSQL(insertColumns []string) string {
+	return ""
+}
+
+func (m *mockCapabilities) GetArrayAppendSQL(quotedColumn string) string {
+	return ""
+}
+
+func (m *mockCapabilities) GetArrayFilterSQL(operator string, quotedColumn string) string {
+	return ""
+}
+
+func (m *mockCapabilities) EncodeArrayValue(values []any) (any, error) {
+	return values, nil
+}
+
+func (m *mockCapabilities) DecodeArrayValue(value any) ([]any, error) {
+	return nil, nil
+}
+
 func (m *mockCapabilities) RequiresLimitForOffset() bool {
 	return m.driverType == "mysql"
 }
@@ -102,6 +126,14 @@ func (m *mockCapabilities) SupportsTransactions() bool {
 	return true
 }
 
+func (m *mockCapabilities) SupportsSavepoints() bool {
+	return true
+}
+
+func (m *mockCapabilities) SupportsTransactionalDDL() bool {
+	return false
+}
+
 func (m *mockCapabilities) SupportsNestedDocuments() bool {
 	return false
 }
@@ -114,6 +146,22 @@ func (m *mockCapabilities) SupportsForeignKeys() bool {
 	return m.driverType != "sqlite"
 }
 
+func (m *mockCapabilities) SupportsUpsert() bool {
+	return true
+}
+
+func (m *mockCapabilities) SupportsJSONOperators() bool {
+	return false
+}
+
+func (m *mockCapabilities) SupportsFullText() bool {
+	return false
+}
+
+func (m *mockCapabilities) MaxParameters() int {
+	return 0
+}
+
 func (m *mockCapabilities) SupportsAggregationPipeline() bool {
 	return false
 }
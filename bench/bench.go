@@ -0,0 +1,332 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"slices"
+	"time"
+
+	"github.com/rediwo/redi-orm/types"
+)
+
+// DefaultRecords is the number of authors seeded by default, each with PostsPerAuthor posts
+const DefaultRecords = 1000
+
+// DefaultIterations is the number of timed runs of each benchmark by default
+const DefaultIterations = 10
+
+// PostsPerAuthor is the number of posts seeded for each author
+const PostsPerAuthor = 5
+
+// batchSize is the number of records inserted per query
+const batchSize = 500
+
+// schemaSource holds the models of the benchmarks, named so that they do not clash with the
+// models of the database they run against
+const schemaSource = `
+model BenchAuthor {
+  id    Int         @id
+  name  String
+  email String      @unique
+  age   Int
+  posts BenchPost[]
+
+  @@map("bench_authors")
+}
+
+model BenchPost {
+  id       Int         @id
+  title    String
+  views    Int
+  authorId Int
+  author   BenchAuthor @relation(fields: [authorId], references: [id])
+
+  @@map("bench_posts")
+}
+
+model BenchEvent {
+  id      Int    @id @default(autoincrement())
+  kind    String
+  payload String
+  score   Float
+
+  @@map("bench_events")
+}
+`
+
+// models are the models of the benchmarks, referencing models last
+var models = []string{"BenchPost", "BenchAuthor", "BenchEvent"}
+
+// Benchmark is an operation whose duration is measured
+type Benchmark struct {
+	Name        string
+	Description string
+
+	run   func(ctx context.Context, s *Suite) error
+	reset func(ctx context.Context, s *Suite) error // Optional: undoes a run, untimed
+}
+
+// Benchmarks are the benchmarks of the suite, in the order they run
+var Benchmarks = []Benchmark{
+	{
+		Name:        "insert",
+		Description: "Insert the records of BenchEvent in batches of 500",
+		run: func(ctx context.Context, s *Suite) error {
+			events := make([]any, s.opts.Records)
+			for i := range events {
+				events[i] = map[string]any{
+					"kind":    fmt.Sprintf("kind-%d", i%10),
+					"payload": fmt.Sprintf("event %d", i),
+					"score":   float64(i%100) / 4,
+				}
+			}
+			return insertBatches(ctx, s.db, "BenchEvent", events)
+		},
+		reset: func(ctx context.Context, s *Suite) error {
+			events := s.db.Model("BenchEvent")
+			_, err := events.Delete().WhereCondition(events.Where("id").GreaterThan(0)).Exec(ctx)
+			return err
+		},
+	},
+	{
+		Name:        "findManyInclude",
+		Description: "Find all authors with their posts included",
+		run: func(ctx context.Context, s *Suite) error {
+			var authors []map[string]any
+			err := s.db.Model("BenchAuthor").Select().Include("posts").OrderBy("id", types.ASC).FindMany(ctx, &authors)
+			if err == nil && len(authors) != s.opts.Records {
+				err = fmt.Errorf("found %d authors instead of %d", len(authors), s.opts.Records)
+			}
+			return err
+		},
+	},
+	{
+		Name:        "groupBy",
+		Description: "Count posts and sum their views by author",
+		run: func(ctx context.Context, s *Suite) error {
+			var groups []map[string]any
+			return s.db.Model("BenchPost").Aggregate().GroupBy("authorId").
+				CountAll("posts").Sum("views", "views").Exec(ctx, &groups)
+		},
+	},
+	{
+		Name:        "raw",
+		Description: "Run a raw SQL query of the 100 most viewed posts",
+		run: func(ctx context.Context, s *Suite) error {
+			var posts []map[string]any
+			return s.db.Raw("SELECT id, title, views FROM bench_posts WHERE views >= ? ORDER BY views DESC LIMIT 100", 50).
+				Find(ctx, &posts)
+		},
+	},
+}
+
+// Options configures a run of the benchmarks
+type Options struct {
+	Records    int      // Authors to seed and events to insert, DefaultRecords by default
+	Iterations int      // Timed runs of each benchmark, DefaultIterations by default
+	Benchmarks []string // Benchmarks to run, all by default
+}
+
+// Result holds the timings of a benchmark
+type Result struct {
+	Name       string  `json:"name"`
+	Iterations int     `json:"iterations"`
+	NsPerOp    int64   `json:"nsPerOp"`
+	MinNs      int64   `json:"minNs"`
+	MaxNs      int64   `json:"maxNs"`
+	OpsPerSec  float64 `json:"opsPerSec"`
+}
+
+// Report holds the results of a run of the benchmarks against a database
+type Report struct {
+	Driver    string    `json:"driver"`
+	Records   int       `json:"records"`
+	GoVersion string    `json:"goVersion"`
+	Platform  string    `json:"platform"`
+	StartedAt time.Time `json:"startedAt"`
+	Results   []Result  `json:"results"`
+}
+
+// Suite is the data of the benchmarks seeded in a database
+type Suite struct {
+	db   types.Database
+	opts Options
+}
+
+// NewSuite registers the models of the benchmarks with db, recreates their tables and seeds
+// them with the same records every time. Close drops the tables.
+func NewSuite(ctx context.Context, db types.Database, opts Options) (*Suite, error) {
+	if opts.Records <= 0 {
+		opts.Records = DefaultRecords
+	}
+	if opts.Iterations <= 0 {
+		opts.Iterations = DefaultIterations
+	}
+	for _, name := range opts.Benchmarks {
+		if find(name) == nil {
+			return nil, fmt.Errorf("unknown benchmark %s", name)
+		}
+	}
+
+	if err := db.LoadSchema(ctx, schemaSource); err != nil {
+		return nil, fmt.Errorf("failed to load the models of the benchmarks: %w", err)
+	}
+	s := &Suite{db: db, opts: opts}
+	if err := s.dropModels(ctx); err != nil {
+		return nil, err
+	}
+	if err := db.SyncSchemas(ctx); err != nil {
+		return nil, fmt.Errorf("failed to create the tables of the benchmarks: %w", err)
+	}
+
+	authors := make([]any, opts.Records)
+	posts := make([]any, 0, opts.Records*PostsPerAuthor)
+	for i := range authors {
+		authors[i] = map[string]any{
+			"id":    i + 1,
+			"name":  fmt.Sprintf("Author %d", i+1),
+			"email": fmt.Sprintf("author%d@example.com", i+1),
+			"age":   20 + i%50,
+		}
+		for j := range PostsPerAuthor {
+			id := i*PostsPerAuthor + j + 1
+			posts = append(posts, map[string]any{
+				"id":       id,
+				"title":    fmt.Sprintf("Post %d", id),
+				"views":    id * 7 % 100,
+				"authorId": i + 1,
+			})
+		}
+	}
+	if err := insertBatches(ctx, db, "BenchAuthor", authors); err != nil {
+		return nil, fmt.Errorf("failed to seed authors: %w", err)
+	}
+	if err := insertBatches(ctx, db, "BenchPost", posts); err != nil {
+		return nil, fmt.Errorf("failed to seed posts: %w", err)
+	}
+	return s, nil
+}
+
+// Run runs a benchmark once
+func (s *Suite) Run(ctx context.Context, name string) error {
+	b := find(name)
+	if b == nil {
+		return fmt.Errorf("unknown benchmark %s", name)
+	}
+	if err := b.run(ctx, s); err != nil {
+		return fmt.Errorf("benchmark %s failed: %w", name, err)
+	}
+	return nil
+}
+
+// Reset undoes the changes of a run of a benchmark, if it makes any
+func (s *Suite) Reset(ctx context.Context, name string) error {
+	b := find(name)
+	if b == nil || b.reset == nil {
+		return nil
+	}
+	if err := b.reset(ctx, s); err != nil {
+		return fmt.Errorf("failed to reset benchmark %s: %w", name, err)
+	}
+	return nil
+}
+
+// Close drops the tables of the benchmarks
+func (s *Suite) Close(ctx context.Context) error {
+	return s.dropModels(ctx)
+}
+
+// Measure runs the selected benchmarks after a warm-up run of each, and times their
+// iterations
+func (s *Suite) Measure(ctx context.Context) (*Report, error) {
+	report := &Report{
+		Driver:    s.db.GetDriverType(),
+		Records:   s.opts.Records,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+		StartedAt: time.Now().UTC(),
+		Results:   []Result{},
+	}
+	for _, b := range Benchmarks {
+		if len(s.opts.Benchmarks) > 0 && !slices.Contains(s.opts.Benchmarks, b.Name) {
+			continue
+		}
+
+		result := Result{Name: b.Name, Iterations: s.opts.Iterations}
+		var total time.Duration
+		for i := -1; i < s.opts.Iterations; i++ {
+			start := time.Now()
+			if err := s.Run(ctx, b.Name); err != nil {
+				return nil, err
+			}
+			elapsed := time.Since(start)
+			if err := s.Reset(ctx, b.Name); err != nil {
+				return nil, err
+			}
+			if i < 0 {
+				continue // Warm-up
+			}
+
+			total += elapsed
+			if result.MinNs == 0 || elapsed.Nanoseconds() < result.MinNs {
+				result.MinNs = elapsed.Nanoseconds()
+			}
+			result.MaxNs = max(result.MaxNs, elapsed.Nanoseconds())
+		}
+		result.NsPerOp = total.Nanoseconds() / int64(s.opts.Iterations)
+		if result.NsPerOp > 0 {
+			result.OpsPerSec = float64(time.Second) / float64(result.NsPerOp)
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+// Run seeds the data of the benchmarks in db, measures them and drops their tables
+func Run(ctx context.Context, db types.Database, opts Options) (*Report, error) {
+	s, err := NewSuite(ctx, db, opts)
+	if err != nil {
+		return nil, err
+	}
+	report, err := s.Measure(ctx)
+	if closeErr := s.Close(ctx); err == nil && closeErr != nil {
+		err = closeErr
+	}
+	return report, err
+}
+
+// dropModels drops the tables of the benchmarks
+func (s *Suite) dropModels(ctx context.Context) error {
+	for _, name := range models {
+		if err := s.db.DropModel(ctx, name); err != nil {
+			return fmt.Errorf("failed to drop the table of %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// insertBatches inserts records into modelName, batchSize at a time
+func insertBatches(ctx context.Context, db types.Database, modelName string, records []any) error {
+	for start := 0; start < len(records); start += batchSize {
+		end := min(start+batchSize, len(records))
+		query := db.Model(modelName).Insert(records[start])
+		if end > start+1 {
+			query = query.Values(records[start+1 : end]...)
+		}
+		if _, err := query.Exec(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// find returns the benchmark with name, or nil
+func find(name string) *Benchmark {
+	for i := range Benchmarks {
+		if Benchmarks[i].Name == name {
+			return &Benchmarks[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package bench
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rediwo/redi-orm/database"
+	_ "github.com/rediwo/redi-orm/drivers/mongodb"
+	_ "github.com/rediwo/redi-orm/drivers/mysql"
+	_ "github.com/rediwo/redi-orm/drivers/postgresql"
+	_ "github.com/rediwo/redi-orm/drivers/sqlite"
+	"github.com/rediwo/redi-orm/test"
+)
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	db, err := database.NewFromURI("sqlite://:memory:")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	if err := db.Connect(ctx); err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer db.Close()
+
+	report, err := Run(ctx, db, Options{Records: 20, Iterations: 2})
+	if err != nil {
+		t.Fatalf("Failed to run the benchmarks: %v", err)
+	}
+	if report.Driver != "sqlite" || report.Records != 20 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	var names []string
+	for _, result := range report.Results {
+		names = append(names, result.Name)
+		if result.Iterations != 2 || result.NsPerOp <= 0 || result.MinNs > result.NsPerOp || result.MaxNs < result.NsPerOp {
+			t.Errorf("Unexpected timings of %s: %+v", result.Name, result)
+		}
+	}
+	if !slices.Equal(names, []string{"insert", "findManyInclude", "groupBy", "raw"}) {
+		t.Errorf("Expected all benchmarks to run in order, got %v", names)
+	}
+
+	// The tables of the benchmarks are dropped
+	tables, err := db.GetMigrator().GetTables()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, table := range tables {
+		if strings.HasPrefix(table, "bench_") {
+			t.Errorf("Expected table %s to be dropped", table)
+		}
+	}
+
+	// Benchmarks can be selected, and unknown ones are rejected
+	report, err = Run(ctx, db, Options{Records: 5, Iterations: 1, Benchmarks: []string{"raw"}})
+	if err != nil {
+		t.Fatalf("Failed to run the raw benchmark: %v", err)
+	}
+	if len(report.Results) != 1 || report.Results[0].Name != "raw" {
+		t.Errorf("Expected only the raw benchmark, got %+v", report.Results)
+	}
+	if _, err := Run(ctx, db, Options{Benchmarks: []string{"update"}}); err == nil {
+		t.Error("Expected an error for an unknown benchmark")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	baseline := &Report{Results: []Result{
+		{Name: "insert", NsPerOp: 1000},
+		{Name: "groupBy", NsPerOp: 1000},
+		{Name: "raw", NsPerOp: 1000},
+	}}
+	current := &Report{Results: []Result{
+		{Name: "insert", NsPerOp: 1500},
+		{Name: "groupBy", NsPerOp: 1100},
+		{Name: "raw", NsPerOp: 500},
+		{Name: "findManyInclude", NsPerOp: 9000},
+	}}
+
+	regressions := Compare(baseline, current, DefaultTolerance)
+	if len(regressions) != 1 || regressions[0].Name != "insert" || regressions[0].Change != 0.5 {
+		t.Errorf("Expected insert to regress by 50%%, got %+v", regressions)
+	}
+
+	// Reports survive a round trip through JSON
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := WriteReport(path, current); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(Compare(loaded, current, 0)) != 0 {
+		t.Errorf("Expected no regressions against the same report, got %+v", loaded)
+	}
+}
+
+// BenchmarkDrivers runs the benchmarks against every driver, skipping the databases that
+// are not running. Start them with `make docker-up`.
+func BenchmarkDrivers(b *testing.B) {
+	ctx := context.Background()
+	for _, driver := range []string{"sqlite", "mysql", "postgresql", "mongodb"} {
+		b.Run(driver, func(b *testing.B) {
+			uri := "sqlite://" + filepath.Join(b.TempDir(), "bench.db")
+			if driver != "sqlite" {
+				uri = test.GetTestDatabaseUri(driver)
+			}
+			db, err := database.NewFromURI(uri)
+			if err != nil {
+				b.Fatalf("Failed to create database: %v", err)
+			}
+			if err := db.Connect(ctx); err != nil {
+				b.Skipf("%s is not available: %v", driver, err)
+			}
+			defer db.Close()
+
+			suite, err := NewSuite(ctx, db, Options{})
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer suite.Close(ctx)
+
+			for _, benchmark := range Benchmarks {
+				b.Run(benchmark.Name, func(b *testing.B) {
+					for b.Loop() {
+						if err := suite.Run(ctx, benchmark.Name); err != nil {
+							b.Fatal(err)
+						}
+						b.StopTimer()
+						if err := suite.Reset(ctx, benchmark.Name); err != nil {
+							b.Fatal(err)
+						}
+						b.StartTimer()
+					}
+				})
+			}
+		})
+	}
+}
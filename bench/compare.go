@@ -0,0 +1,65 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// DefaultTolerance is the slowdown of a benchmark over its baseline, as a fraction, that is
+// not reported as a regression by default
+const DefaultTolerance = 0.2
+
+// Regression is a benchmark slower than in a baseline report
+type Regression struct {
+	Name       string  `json:"name"`
+	BaselineNs int64   `json:"baselineNs"`
+	CurrentNs  int64   `json:"currentNs"`
+	Change     float64 `json:"change"` // Slowdown as a fraction of the baseline, e.g. 0.5 for 50% slower
+}
+
+// Compare returns the benchmarks of current whose time per operation exceeds the one of
+// baseline by more than tolerance, a fraction. Benchmarks missing from either report are
+// not compared.
+func Compare(baseline, current *Report, tolerance float64) []Regression {
+	var regressions []Regression
+	for _, result := range current.Results {
+		for _, base := range baseline.Results {
+			if base.Name != result.Name || base.NsPerOp <= 0 {
+				continue
+			}
+			change := float64(result.NsPerOp-base.NsPerOp) / float64(base.NsPerOp)
+			if change > tolerance {
+				regressions = append(regressions, Regression{
+					Name:       result.Name,
+					BaselineNs: base.NsPerOp,
+					CurrentNs:  result.NsPerOp,
+					Change:     change,
+				})
+			}
+		}
+	}
+	return regressions
+}
+
+// LoadReport reads a report written as JSON
+func LoadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse benchmark report %s: %w", path, err)
+	}
+	return &report, nil
+}
+
+// WriteReport writes a report as indented JSON
+func WriteReport(path string, report *Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCommandFlags(t *testing.T) {
+	for _, c := range commands {
+		o := &options{}
+		flags := c.flagSet(o)
+		for _, name := range c.flagNames() {
+			if flags.Lookup(name) == nil {
+				t.Errorf("Expected %s to have flag --%s", c.name, name)
+			}
+		}
+
+		var help bytes.Buffer
+		c.writeHelp(&help)
+		if !strings.Contains(help.String(), "redi-orm "+c.name) {
+			t.Errorf("Expected the help of %s to show its usage, got:\n%s", c.name, help.String())
+		}
+	}
+
+	// Commands only have their own flags
+	o := &options{}
+	flags := findCommand("migrate:status").flagSet(o)
+	if flags.Lookup("port") != nil {
+		t.Error("Expected migrate:status to have no --port flag")
+	}
+	flags.SetOutput(&bytes.Buffer{})
+	if err := flags.Parse([]string{"--db=sqlite://./app.db", "--json"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	defer func() { jsonOutput = false }()
+	if o.dbURI != "sqlite://./app.db" || !jsonOutput {
+		t.Errorf("Expected flags to be bound to the options, got %q, %v", o.dbURI, jsonOutput)
+	}
+
+	// --json is an alias of --output=json
+	if err := flags.Parse([]string{"--output=text"}); err != nil || jsonOutput {
+		t.Errorf("Expected --output=text to print text, got %v, %v", err, jsonOutput)
+	}
+	if err := flags.Parse([]string{"--output=json"}); err != nil || !jsonOutput {
+		t.Errorf("Expected --output=json to print JSON, got %v, %v", err, jsonOutput)
+	}
+	if err := (outputFormat{}).Set("xml"); err == nil {
+		t.Error("Expected --output=xml to be rejected")
+	}
+	if generate := findCommand("generate").flagSet(o).Lookup("output"); generate.DefValue != "./generated" {
+		t.Errorf("Expected generate to keep --output as its directory, got %q", generate.DefValue)
+	}
+
+	// Config values of flags the command does not have are ignored, as is the output
+	// directory of generate for --output
+	config := &Config{DB: "sqlite://./file.db"}
+	config.Server.Port = "8080"
+	config.Generate.Output = "./models"
+	if err := applyConfig(flags, config, "redi-orm.yaml"); err != nil {
+		t.Errorf("applyConfig() error = %v", err)
+	}
+}
+
+func TestTakeTimeout(t *testing.T) {
+	o := &options{}
+	args := takeTimeout(o, []string{"app.js", "--timeout", "5000", "--verbose"})
+	if o.timeout != 5000 || strings.Join(args, " ") != "app.js --verbose" {
+		t.Errorf("Expected the timeout to be taken out, got %d, %q", o.timeout, args)
+	}
+}
+
+func TestWriteCompletion(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		var script bytes.Buffer
+		if err := writeCompletion(&script, shell); err != nil {
+			t.Fatalf("writeCompletion(%s) error = %v", shell, err)
+		}
+		for _, expected := range []string{"migrate:status", "updated-at-triggers", "upsert"} {
+			if !strings.Contains(script.String(), expected) {
+				t.Errorf("Expected the %s completion to contain %q", shell, expected)
+			}
+		}
+	}
+	if err := writeCompletion(&bytes.Buffer{}, "powershell"); err == nil {
+		t.Error("Expected an error for an unsupported shell")
+	}
+}
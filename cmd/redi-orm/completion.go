@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// completionFlag is a flag of a command in completion scripts
+type completionFlag struct {
+	name    string
+	usage   string
+	isBool  bool
+	isPath  bool
+	choices []string
+}
+
+// flagChoices are the values completed for flags that take one of a few
+var flagChoices = map[string][]string{
+	"mode":          {"auto", "file"},
+	"target":        {"go", "ts"},
+	"format":        {"csv", "ndjson"},
+	"on-duplicate":  {"error", "skip", "upsert"},
+	"rate-limit-by": {"ip", "api-key"},
+	"log-level":     {"debug", "info", "warn", "error", "none"},
+}
+
+// completionFlags returns the flags of the command as completion scripts describe them
+func (c *command) completionFlags() []completionFlag {
+	var flags []completionFlag
+	fs := c.flagSet(&options{})
+	for _, name := range c.flagNames() {
+		f := fs.Lookup(name)
+		boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool })
+		flag := completionFlag{
+			name:    name,
+			usage:   f.Usage,
+			isBool:  ok && boolFlag.IsBoolFlag(),
+			isPath:  pathFlags[name] || name == "config" || name == "file",
+			choices: flagChoices[name],
+		}
+		if _, ok := f.Value.(outputFormat); ok {
+			// Unlike the output directory of generate
+			flag.isPath, flag.choices = false, []string{"text", "json"}
+		}
+		flags = append(flags, flag)
+	}
+	return flags
+}
+
+// argChoices returns the values completed for the first argument of the command, or nil
+// to complete file names
+func (c *command) argChoices() []string {
+	switch c.name {
+	case "help":
+		return commandNames()
+	case "completion":
+		return []string{"bash", "zsh", "fish"}
+	}
+	return nil
+}
+
+// writeCompletion writes the completion script of shell for the commands and their flags
+func writeCompletion(w io.Writer, shell string) error {
+	switch shell {
+	case "bash":
+		writeBashCompletion(w)
+	case "zsh":
+		writeZshCompletion(w)
+	case "fish":
+		writeFishCompletion(w)
+	default:
+		return fmt.Errorf("unsupported shell: %s (supported: bash, zsh, fish)", shell)
+	}
+	return nil
+}
+
+func commandNames() []string {
+	names := make([]string, len(commands))
+	for i, c := range commands {
+		names[i] = c.name
+	}
+	return names
+}
+
+func writeBashCompletion(w io.Writer) {
+	fmt.Fprintf(w, `# bash completion for redi-orm
+_redi_orm() {
+    local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"
+    COMP_WORDBREAKS="${COMP_WORDBREAKS//:/}"
+
+    if [[ $COMP_CWORD -eq 1 ]]; then
+        COMPREPLY=($(compgen -W "%s" -- "$cur"))
+        return
+    fi
+
+    local flags="" args=""
+    case "${COMP_WORDS[1]}" in
+`, strings.Join(commandNames(), " "))
+	for _, c := range commands {
+		var names []string
+		for _, f := range c.completionFlags() {
+			names = append(names, "--"+f.name)
+		}
+		fmt.Fprintf(w, "        %s) flags=%q", c.name, strings.Join(names, " "))
+		if choices := c.argChoices(); choices != nil {
+			fmt.Fprintf(w, " args=%q", strings.Join(choices, " "))
+		}
+		fmt.Fprint(w, " ;;\n")
+	}
+	fmt.Fprint(w, `    esac
+
+    case "$prev" in
+`)
+	for _, name := range slices.Sorted(maps.Keys(flagChoices)) {
+		fmt.Fprintf(w, "        --%s) COMPREPLY=($(compgen -W %q -- \"$cur\")); return ;;\n", name, strings.Join(flagChoices[name], " "))
+	}
+	fmt.Fprint(w, `    esac
+
+    if [[ $cur == -* ]]; then
+        COMPREPLY=($(compgen -W "$flags" -- "$cur"))
+    elif [[ -n $args ]]; then
+        COMPREPLY=($(compgen -W "$args" -- "$cur"))
+    else
+        COMPREPLY=($(compgen -f -- "$cur"))
+    fi
+}
+complete -o filenames -F _redi_orm redi-orm
+`)
+}
+
+func writeZshCompletion(w io.Writer) {
+	fmt.Fprint(w, `#compdef redi-orm
+
+_redi_orm() {
+    local -a commands
+    commands=(
+`)
+	for _, c := range commands {
+		fmt.Fprintf(w, "        %s\n", zshQuote(strings.ReplaceAll(c.name, ":", `\:`)+":"+strings.ReplaceAll(c.summary, "\n", " ")))
+	}
+	fmt.Fprint(w, `    )
+
+    if (( CURRENT == 2 )); then
+        _describe 'command' commands
+        return
+    fi
+
+    case "${words[2]}" in
+`)
+	for _, c := range commands {
+		fmt.Fprintf(w, "        %s)\n            _arguments", c.name)
+		for _, f := range c.completionFlags() {
+			spec := "--" + f.name
+			description := strings.NewReplacer("[", `\[`, "]", `\]`).Replace(f.usage)
+			switch {
+			case f.isBool:
+				spec += "[" + description + "]"
+			case f.choices != nil:
+				spec += "=[" + description + "]:" + f.name + ":(" + strings.Join(f.choices, " ") + ")"
+			case f.isPath:
+				spec += "=[" + description + "]:" + f.name + ":_files"
+			default:
+				spec += "=[" + description + "]:" + f.name + ": "
+			}
+			fmt.Fprintf(w, " \\\n                %s", zshQuote(spec))
+		}
+		if choices := c.argChoices(); choices != nil {
+			fmt.Fprintf(w, " \\\n                %s", zshQuote("1:"+strings.TrimSuffix(strings.Trim(c.args, "[]<>"), "...")+":("+strings.Join(choices, " ")+")"))
+		} else if c.args != "" {
+			fmt.Fprint(w, " \\\n                '*:file:_files'")
+		}
+		fmt.Fprint(w, "\n            ;;\n")
+	}
+	fmt.Fprint(w, `    esac
+}
+
+_redi_orm "$@"
+`)
+}
+
+func writeFishCompletion(w io.Writer) {
+	fmt.Fprintf(w, "# fish completion for redi-orm\ncomplete -c redi-orm -f\n")
+	for _, c := range commands {
+		fmt.Fprintf(w, "complete -c redi-orm -n __fish_use_subcommand -a %s -d %s\n", fishQuote(c.name), fishQuote(strings.SplitN(c.summary, "\n", 2)[0]))
+	}
+	for _, c := range commands {
+		for _, f := range c.completionFlags() {
+			fmt.Fprintf(w, "complete -c redi-orm -n %s -l %s -d %s", fishQuote("__fish_seen_subcommand_from "+c.name), f.name, fishQuote(f.usage))
+			switch {
+			case f.isBool:
+			case f.choices != nil:
+				fmt.Fprintf(w, " -x -a %s", fishQuote(strings.Join(f.choices, " ")))
+			case f.isPath:
+				fmt.Fprint(w, " -r -F")
+			default:
+				fmt.Fprint(w, " -x")
+			}
+			fmt.Fprintln(w)
+		}
+		if choices := c.argChoices(); choices != nil {
+			fmt.Fprintf(w, "complete -c redi-orm -n %s -a %s\n", fishQuote("__fish_seen_subcommand_from "+c.name), fishQuote(strings.Join(choices, " ")))
+		} else if c.args != "" {
+			fmt.Fprintf(w, "complete -c redi-orm -n %s -F\n", fishQuote("__fish_seen_subcommand_from "+c.name))
+		}
+	}
+}
+
+// zshQuote quotes s for zsh with single quotes
+func zshQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishQuote quotes s for fish with single quotes
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
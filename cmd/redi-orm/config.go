@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/rediwo/redi-orm/prisma"
+	"github.com/rediwo/redi-orm/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigFiles are looked up in the working directory when --config is not given
+var defaultConfigFiles = []string{"redi-orm.yaml", "redi-orm.yml"}
+
+// Config holds command defaults read from a project config file. Values are kept as
+// text and set through the flags they stand for, so they are validated the same way.
+type Config struct {
+	DB                string `yaml:"db"`
+	Schema            string `yaml:"schema"`
+	Migrations        string `yaml:"migrations"`
+	Mode              string `yaml:"mode"`
+	LogLevel          string `yaml:"logLevel"`
+	UpdatedAtTriggers string `yaml:"updatedAtTriggers"`
+
+	Server struct {
+		Port               string `yaml:"port"`
+		Playground         string `yaml:"playground"`
+		CORS               string `yaml:"cors"`
+		Masking            string `yaml:"masking"`
+		Listen             string `yaml:"listen"`
+		RateLimit          string `yaml:"rateLimit"`
+		RateLimitBy        string `yaml:"rateLimitBy"`
+		RateLimitEndpoints string `yaml:"rateLimitEndpoints"`
+		TrustProxy         string `yaml:"trustProxy"`
+		Audit              string `yaml:"audit"`
+		DrainTimeout       string `yaml:"drainTimeout"`
+		HealthInterval     string `yaml:"healthInterval"`
+		TLSCert            string `yaml:"tlsCert"`
+		TLSKey             string `yaml:"tlsKey"`
+		ClientCA           string `yaml:"clientCA"`
+	} `yaml:"server"`
+
+	Pull struct {
+		SampleSize string `yaml:"sampleSize"`
+	} `yaml:"pull"`
+
+	Generate struct {
+		Target  string `yaml:"target"`
+		Output  string `yaml:"output"`
+		Package string `yaml:"package"`
+	} `yaml:"generate"`
+
+	// Connections are additional databases by name, which server requests select with
+	// the X-Connection-Name header
+	Connections map[string]string `yaml:"connections"`
+}
+
+// flagValues returns the config values by the name of the flag they set
+func (c *Config) flagValues() map[string]string {
+	values := map[string]string{
+		"db":                   c.DB,
+		"schema":               c.Schema,
+		"migrations":           c.Migrations,
+		"mode":                 c.Mode,
+		"log-level":            c.LogLevel,
+		"updated-at-triggers":  c.UpdatedAtTriggers,
+		"port":                 c.Server.Port,
+		"playground":           c.Server.Playground,
+		"cors":                 c.Server.CORS,
+		"masking":              c.Server.Masking,
+		"listen":               c.Server.Listen,
+		"rate-limit":           c.Server.RateLimit,
+		"rate-limit-by":        c.Server.RateLimitBy,
+		"rate-limit-endpoints": c.Server.RateLimitEndpoints,
+		"trust-proxy":          c.Server.TrustProxy,
+		"audit":                c.Server.Audit,
+		"drain-timeout":        c.Server.DrainTimeout,
+		"health-interval":      c.Server.HealthInterval,
+		"tls-cert":             c.Server.TLSCert,
+		"tls-key":              c.Server.TLSKey,
+		"client-ca":            c.Server.ClientCA,
+		"sample-size":          c.Pull.SampleSize,
+		"target":               c.Generate.Target,
+		"output":               c.Generate.Output,
+		"package":              c.Generate.Package,
+	}
+	for name, value := range values {
+		if value == "" {
+			delete(values, name)
+		}
+	}
+	return values
+}
+
+// pathFlags are the flags whose relative values in a config file are relative to the file
+var pathFlags = map[string]bool{
+	"schema":     true,
+	"migrations": true,
+	"masking":    true,
+	"output":     true,
+	"tls-cert":   true,
+	"tls-key":    true,
+	"client-ca":  true,
+}
+
+// LoadConfig reads a config file, replacing ${VAR} and ${VAR:-default} in its values
+// with environment variables
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	for _, value := range []*string{
+		&config.DB, &config.Schema, &config.Migrations, &config.Mode, &config.LogLevel, &config.UpdatedAtTriggers,
+		&config.Server.Port, &config.Server.Playground, &config.Server.CORS, &config.Server.Masking, &config.Server.Listen, &config.Server.DrainTimeout, &config.Server.HealthInterval,
+		&config.Server.RateLimit, &config.Server.RateLimitBy, &config.Server.RateLimitEndpoints, &config.Server.TrustProxy,
+		&config.Server.TLSCert, &config.Server.TLSKey, &config.Server.ClientCA,
+		&config.Pull.SampleSize,
+		&config.Generate.Target, &config.Generate.Output, &config.Generate.Package,
+	} {
+		if *value, err = expandEnv(*value); err != nil {
+			return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+	}
+	for name, uri := range config.Connections {
+		if config.Connections[name], err = expandEnv(uri); err != nil {
+			return nil, fmt.Errorf("invalid config file %s: connection %s: %w", path, name, err)
+		}
+	}
+
+	return &config, nil
+}
+
+var envPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// expandEnv replaces ${VAR} with the value of the environment variable VAR, and
+// ${VAR:-default} with default when VAR is unset or empty
+func expandEnv(value string) (string, error) {
+	var missing []string
+	expanded := envPattern.ReplaceAllStringFunc(value, func(match string) string {
+		parts := envPattern.FindStringSubmatch(match)
+		if env := os.Getenv(parts[1]); env != "" {
+			return env
+		}
+		if parts[2] == "" {
+			missing = append(missing, parts[1])
+		}
+		return parts[3]
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variable %s is not set", strings.Join(missing, ", "))
+	}
+	return expanded, nil
+}
+
+// findConfigFile returns the config file to use: the given path, or a default file in the
+// working directory if there is one
+func findConfigFile(path string) (string, error) {
+	if path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("config file not found: %s", path)
+		}
+		return path, nil
+	}
+	for _, name := range defaultConfigFiles {
+		if _, err := os.Stat(name); err == nil {
+			return name, nil
+		}
+	}
+	return "", nil
+}
+
+// applyConfig sets the flags that were not given on the command line from the config
+// file, so flags always override file values. Values of flags the command does not have
+// are ignored.
+func applyConfig(flags *flag.FlagSet, config *Config, configPath string) error {
+	given := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) {
+		given[f.Name] = true
+	})
+
+	baseDir := filepath.Dir(configPath)
+	for name, value := range config.flagValues() {
+		if given[name] || flags.Lookup(name) == nil {
+			continue
+		}
+		if _, ok := flags.Lookup(name).Value.(outputFormat); ok {
+			// generate.output is the output directory of generate, not the output format
+			continue
+		}
+		if pathFlags[name] && !filepath.IsAbs(value) {
+			value = filepath.Join(baseDir, value)
+		}
+		if err := flags.Set(name, value); err != nil {
+			return fmt.Errorf("invalid value %q for %s in config file %s: %w", value, name, configPath, err)
+		}
+	}
+	return nil
+}
+
+// resolveDatabaseURI reads env:NAME URIs from the environment, and falls back to the url of
+// the datasource block of the schema when no URI is given
+func resolveDatabaseURI(dbURI, schemaPath string) (string, error) {
+	if dbURI == "" {
+		if _, err := os.Stat(schemaPath); err != nil {
+			return "", nil
+		}
+		url, err := prisma.DatabaseURLFromPath(schemaPath)
+		if err != nil {
+			return "", err
+		}
+		dbURI = url
+	}
+	return utils.ResolveEnvURI(dbURI)
+}
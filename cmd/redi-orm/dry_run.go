@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rediwo/redi-orm/database"
+	"github.com/rediwo/redi-orm/migration"
+	"github.com/rediwo/redi-orm/types"
+)
+
+// pendingEntry is a pending file migration in JSON results of dry runs
+type pendingEntry struct {
+	Version string `json:"version"`
+	Name    string `json:"name"`
+	SQL     string `json:"sql"`
+}
+
+func runMigrateDryRun(ctx context.Context, dbURI, schemaPath, migrationsDir, mode, name string, triggers, interactive bool) {
+	if interactive && types.MigrationMode(mode) == types.MigrationModeFile {
+		log.Fatal("Error: --interactive reviews the changes of auto-migration and cannot be used with --mode=file")
+	}
+	if interactive && name == "" {
+		log.Fatal("Error: --name flag is required with --interactive, to name the migration of the approved changes")
+	}
+
+	// Create database connection
+	db, err := database.NewFromURI(dbURI)
+	if err != nil {
+		log.Fatalf("Failed to create database: %v", err)
+	}
+
+	// Connect to database
+	if err := db.Connect(ctx); err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// Load schema from file
+	schemas, err := loadSchemaFromFile(schemaPath)
+	if err != nil {
+		log.Fatalf("Failed to load schema: %v", err)
+	}
+
+	// Register schemas with database
+	for _, schema := range schemas {
+		if err := db.RegisterSchema(schema.Name, schema); err != nil {
+			log.Fatalf("Failed to register schema %s: %v", schema.Name, err)
+		}
+	}
+
+	result := struct {
+		DryRun    bool             `json:"dryRun"`
+		Changes   []changeEntry    `json:"changes,omitempty"`
+		Pending   []pendingEntry   `json:"pending,omitempty"`
+		Approved  []changeEntry    `json:"approved,omitempty"`
+		Migration string           `json:"migration,omitempty"`
+		Applied   []migrationEntry `json:"applied"`
+	}{DryRun: true, Applied: []migrationEntry{}}
+
+	// File migrations show the SQL of the pending ones
+	if types.MigrationMode(mode) == types.MigrationModeFile {
+		runner, err := migration.New(db, types.MigrationOptions{Mode: types.MigrationModeFile, MigrationsDir: migrationsDir})
+		if err != nil {
+			log.Fatalf("Failed to create migration runner: %v", err)
+		}
+		pending, err := runner.PendingMigrations()
+		if err != nil {
+			log.Fatalf("Failed to get pending migrations: %v", err)
+		}
+		for _, m := range pending {
+			result.Pending = append(result.Pending, pendingEntry{Version: m.Version, Name: m.Name, SQL: m.UpSQL})
+		}
+		printResult(result, func() {
+			if len(pending) == 0 {
+				fmt.Println("No pending migrations.")
+				return
+			}
+			for _, m := range pending {
+				fmt.Printf("-- Migration %s_%s\n%s\n\n", m.Version, m.Name, strings.TrimSpace(m.UpSQL))
+			}
+			fmt.Println("Dry run completed. No changes were applied.")
+		})
+		return
+	}
+
+	// Auto-migration shows the statements it would execute
+	options := types.MigrationOptions{
+		Mode:              types.MigrationModeFile,
+		MigrationsDir:     migrationsDir,
+		UpdatedAtTriggers: triggers,
+		Logger:            migrationLogger(),
+	}
+	manager, err := migration.NewManager(db, options)
+	if err != nil {
+		log.Fatalf("Failed to create migration manager: %v", err)
+	}
+	changes, err := manager.Plan(schemas)
+	if err != nil {
+		log.Fatalf("Failed to compute schema changes: %v", err)
+	}
+	result.Changes = newChangeEntries(changes)
+
+	if !interactive {
+		printResult(result, func() {
+			if len(changes) == 0 {
+				fmt.Println("No schema changes detected.")
+				return
+			}
+			for i, change := range changes {
+				writeChange(os.Stdout, i, len(changes), change)
+			}
+			fmt.Println("Dry run completed. No changes were applied.")
+		})
+		return
+	}
+
+	// Review the changes one by one, writing those approved to a migration file. Prompts go
+	// to stderr, so that stdout only has the result.
+	approved, err := approveChanges(bufio.NewReader(os.Stdin), os.Stderr, changes)
+	if err != nil {
+		log.Fatalf("Failed to read approval: %v", err)
+	}
+	result.Approved = newChangeEntries(approved)
+	var file *types.MigrationFile
+	if len(approved) > 0 {
+		if file, err = manager.GenerateMigrationFromChanges(name, approved, schemas); err != nil {
+			log.Fatalf("Failed to generate migration: %v", err)
+		}
+		result.Migration = filepath.Join(migrationsDir, file.Version+"_"+file.Name)
+	}
+	printResult(result, func() {
+		if file == nil {
+			fmt.Println("No changes approved, no migration written.")
+			return
+		}
+		fmt.Printf("Wrote %d of %d changes to %s\n", len(approved), len(changes), result.Migration)
+		fmt.Println("Apply it with redi-orm migrate:apply.")
+	})
+}
+
+// writeChange writes the statement of a schema change, numbered as the ith of n
+func writeChange(w io.Writer, i, n int, change types.SchemaChange) {
+	label := change.TableName
+	if change.ColumnName != "" {
+		label += "." + change.ColumnName
+	} else if change.IndexName != "" {
+		label += " " + change.IndexName
+	}
+	sql := strings.TrimSpace(change.SQL)
+	if !strings.HasSuffix(sql, ";") {
+		sql += ";"
+	}
+	fmt.Fprintf(w, "-- %d/%d %s %s\n%s\n\n", i+1, n, change.Type, label, sql)
+}
+
+// approveChanges shows each change and asks whether to include it, returning those approved
+func approveChanges(in *bufio.Reader, out io.Writer, changes []types.SchemaChange) ([]types.SchemaChange, error) {
+	var approved []types.SchemaChange
+	for i, change := range changes {
+		writeChange(out, i, len(changes), change)
+		for {
+			fmt.Fprint(out, "Include this change? [y]es, [n]o, [a]ll remaining, [q]uit: ")
+			answer, err := in.ReadString('\n')
+			if err == io.EOF && answer == "" {
+				// Input ended: keep what was approved so far
+				fmt.Fprintln(out)
+				return approved, nil
+			}
+			if err != nil && err != io.EOF {
+				return nil, err
+			}
+			switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "y", "yes":
+				approved = append(approved, change)
+			case "n", "no":
+			case "a", "all":
+				return append(approved, changes[i:]...), nil
+			case "q", "quit":
+				return approved, nil
+			default:
+				continue
+			}
+			fmt.Fprintln(out)
+			break
+		}
+	}
+	return approved, nil
+}
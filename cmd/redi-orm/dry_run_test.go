@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rediwo/redi-orm/types"
+)
+
+func TestApproveChanges(t *testing.T) {
+	changes := []types.SchemaChange{
+		{Type: types.ChangeTypeCreateTable, TableName: "users", SQL: "CREATE TABLE users (id INTEGER)"},
+		{Type: types.ChangeTypeAddColumn, TableName: "posts", ColumnName: "title", SQL: "ALTER TABLE posts ADD COLUMN title TEXT"},
+		{Type: types.ChangeTypeAddIndex, TableName: "posts", IndexName: "idx_posts_title", SQL: "CREATE INDEX idx_posts_title ON posts (title)"},
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"SomeApproved", "y\nn\nyes\n", []string{"users", "posts idx_posts_title"}},
+		{"InvalidAnswersAskAgain", "maybe\nn\ny\nn\n", []string{"posts.title"}},
+		{"AllRemaining", "n\na\n", []string{"posts.title", "posts idx_posts_title"}},
+		{"Quit", "y\nq\n", []string{"users"}},
+		{"InputEnds", "y\n", []string{"users"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			approved, err := approveChanges(bufio.NewReader(strings.NewReader(tt.input)), &out, changes)
+			if err != nil {
+				t.Fatalf("approveChanges() error = %v", err)
+			}
+			var labels []string
+			for _, change := range approved {
+				label := change.TableName
+				if change.ColumnName != "" {
+					label += "." + change.ColumnName
+				} else if change.IndexName != "" {
+					label += " " + change.IndexName
+				}
+				labels = append(labels, label)
+			}
+			if strings.Join(labels, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected %v to be approved, got %v", tt.expected, labels)
+			}
+		})
+	}
+
+	// Each change is shown with its statement
+	var out bytes.Buffer
+	approveChanges(bufio.NewReader(strings.NewReader("n\nn\nn\n")), &out, changes)
+	if !strings.Contains(out.String(), "-- 2/3 ADD_COLUMN posts.title\nALTER TABLE posts ADD COLUMN title TEXT;\n") {
+		t.Errorf("Expected the changes to be shown, got:\n%s", out.String())
+	}
+}
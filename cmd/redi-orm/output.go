@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/rediwo/redi-orm/logger"
+	"github.com/rediwo/redi-orm/types"
+)
+
+// Output flags shared by the commands
+var (
+	// jsonOutput prints the result of commands as a JSON document on stdout (--output json
+	// or --json), and informational messages on stderr
+	jsonOutput bool
+	// quiet drops informational messages (--quiet). Results, warnings and errors are still
+	// printed.
+	quiet bool
+)
+
+// outputFormat is the value of --output: text, or json as with --json
+type outputFormat struct{}
+
+func (outputFormat) String() string {
+	if jsonOutput {
+		return "json"
+	}
+	return "text"
+}
+
+func (outputFormat) Set(value string) error {
+	switch value {
+	case "text":
+		jsonOutput = false
+	case "json":
+		jsonOutput = true
+	default:
+		return fmt.Errorf("expected text or json")
+	}
+	return nil
+}
+
+// infoWriter returns where informational messages go: stdout, stderr when stdout carries
+// JSON, or nowhere with --quiet
+func infoWriter() io.Writer {
+	switch {
+	case quiet:
+		return io.Discard
+	case jsonOutput:
+		return os.Stderr
+	default:
+		return os.Stdout
+	}
+}
+
+// infof prints an informational message
+func infof(format string, args ...any) {
+	fmt.Fprintf(infoWriter(), format, args...)
+}
+
+// printResult prints the result of a command: as JSON with --output json, or with text otherwise
+func printResult(result any, text func()) {
+	if !jsonOutput {
+		text()
+		return
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		log.Fatalf("Failed to write JSON: %v", err)
+	}
+}
+
+// newLogger creates a logger for informational messages, which go to stderr with JSON output
+// and are dropped with --quiet
+func newLogger(prefix, logLevel string) logger.Logger {
+	l := logger.NewDefaultLogger(prefix)
+	if jsonOutput {
+		l.SetOutput(os.Stderr)
+	}
+	l.SetLevel(logger.ParseLogLevel(logLevel))
+	return l
+}
+
+// migrationLogger returns the logger of migrations: the standard logger on stderr by
+// default, and one dropping informational messages with --quiet
+func migrationLogger() logger.Logger {
+	if !quiet {
+		return nil
+	}
+	return newLogger("Migration", "warn")
+}
+
+// progressf prints the progress of commands whose stdout carries data or JSON on stderr,
+// unless --quiet
+func progressf(format string, args ...any) {
+	if !quiet {
+		fmt.Fprintf(os.Stderr, format, args...)
+	}
+}
+
+// migrationEntry is an applied migration in JSON results
+type migrationEntry struct {
+	Version   string    `json:"version"`
+	Name      string    `json:"name"`
+	AppliedAt time.Time `json:"appliedAt,omitzero"`
+}
+
+func newMigrationEntries(migrations []types.Migration) []migrationEntry {
+	entries := make([]migrationEntry, len(migrations))
+	for i, m := range migrations {
+		entries[i] = migrationEntry{Version: m.Version, Name: m.Name, AppliedAt: m.AppliedAt}
+	}
+	return entries
+}
+
+// changeEntry is a schema change of auto-migration in JSON results
+type changeEntry struct {
+	Type   types.ChangeType `json:"type"`
+	Table  string           `json:"table,omitempty"`
+	Column string           `json:"column,omitempty"`
+	Index  string           `json:"index,omitempty"`
+	SQL    string           `json:"sql,omitempty"`
+}
+
+func newChangeEntries(changes []types.SchemaChange) []changeEntry {
+	entries := make([]changeEntry, len(changes))
+	for i, change := range changes {
+		entries[i] = changeEntry{Type: change.Type, Table: change.TableName, Column: change.ColumnName, Index: change.IndexName, SQL: change.SQL}
+	}
+	return entries
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+)
+
+// captureStdout returns what fn writes to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Pipe() error = %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	fn()
+	w.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Failed to read stdout: %v", err)
+	}
+	return string(data)
+}
+
+func TestPrintResult(t *testing.T) {
+	defer func() { jsonOutput, quiet = false, false }()
+	result := struct {
+		Applied []migrationEntry `json:"applied"`
+	}{Applied: newMigrationEntries(nil)}
+	text := func() { infof("informational\n"); os.Stdout.WriteString("text\n") }
+
+	if got := captureStdout(t, func() { printResult(result, text) }); got != "informational\ntext\n" {
+		t.Errorf("Expected the text result, got %q", got)
+	}
+
+	quiet = true
+	if got := captureStdout(t, func() { printResult(result, text) }); got != "text\n" {
+		t.Errorf("Expected --quiet to drop informational messages only, got %q", got)
+	}
+
+	quiet, jsonOutput = false, true
+	got := captureStdout(t, func() {
+		infof("informational\n")
+		printResult(result, text)
+	})
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(got), &decoded); err != nil {
+		t.Fatalf("Expected stdout to be JSON, got %q: %v", got, err)
+	}
+	if applied, ok := decoded["applied"].([]any); !ok || len(applied) != 0 {
+		t.Errorf("Expected an empty applied array, got %v", decoded)
+	}
+}
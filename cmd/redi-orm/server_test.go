@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServeHTTPDrainsInFlightRequests(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.Write([]byte("done"))
+	})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- serveHTTP(ctx, server, listener, 5*time.Second)
+	}()
+
+	response := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String())
+		if err != nil {
+			response <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		response <- string(body)
+	}()
+
+	// Shut down while the request is running, then let it finish
+	<-started
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	if _, err := net.Dial("tcp", listener.Addr().String()); err == nil {
+		t.Error("Expected the listener to be closed during the drain")
+	}
+	close(release)
+
+	if body := <-response; body != "done" {
+		t.Errorf("Expected the in-flight request to finish, got %q", body)
+	}
+	if err := <-served; err != nil {
+		t.Errorf("serveHTTP() error = %v", err)
+	}
+}
+
+func TestServeHTTPDrainTimeout(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+
+	// A request that does not end with its context
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- serveHTTP(ctx, server, listener, 100*time.Millisecond)
+	}()
+	go http.Get("http://" + listener.Addr().String())
+
+	<-started
+	cancel()
+	select {
+	case err := <-served:
+		if err == nil || !strings.Contains(err.Error(), "did not finish") {
+			t.Errorf("Expected a drain timeout error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("serveHTTP() did not return after the drain timeout")
+	}
+}
+
+func TestServeHTTPEndsStreams(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+
+	// A stream writes events until its request context ends
+	started := make(chan struct{})
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		close(started)
+		<-r.Context().Done()
+	})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- serveHTTP(ctx, server, listener, 5*time.Second)
+	}()
+	resp, err := http.Get("http://" + listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	<-started
+	start := time.Now()
+	cancel()
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("serveHTTP() error = %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Expected the shutdown to end the stream, took %s", elapsed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("serveHTTP() waited for the drain timeout with an open stream")
+	}
+}
+
+func TestListen(t *testing.T) {
+	listener, err := listen("127.0.0.1:0", 4000)
+	if err != nil {
+		t.Fatalf("listen() error = %v", err)
+	}
+	if url := serverURL(listener, true); !strings.HasPrefix(url, "https://localhost:") || strings.HasSuffix(url, ":4000") {
+		t.Errorf("Expected the URL of the listen address, got %s", url)
+	}
+	listener.Close()
+
+	// A socket left behind by a stopped server is replaced
+	path := filepath.Join(t.TempDir(), "redi.sock")
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	listener, err = listen("unix://"+path, 4000)
+	if err != nil {
+		t.Fatalf("listen() error = %v", err)
+	}
+	defer listener.Close()
+	if url := serverURL(listener, false); url != "unix://"+path {
+		t.Errorf("Expected the socket URL, got %s", url)
+	}
+	if _, err := listen("unix://"+path, 4000); err == nil {
+		t.Error("Expected an error for a socket another server listens on")
+	}
+
+	// Requests reach the server through the socket
+	go http.Serve(listener, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", path)
+		},
+	}}
+	resp, err := client.Get("http://localhost/healthz")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer resp.Body.Close()
+	if body, _ := io.ReadAll(resp.Body); string(body) != "ok" {
+		t.Errorf("Expected a response through the socket, got %q", body)
+	}
+}
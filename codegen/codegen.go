@@ -0,0 +1,103 @@
+package codegen
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/rediwo/redi-orm/prisma"
+	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/utils"
+)
+
+// generatedHeader marks generated files so tools and reviewers skip them
+const generatedHeader = "Code generated by redi-orm generate. DO NOT EDIT."
+
+// File is a single generated output file
+type File struct {
+	Name    string
+	Content []byte
+}
+
+// commonInitialisms are upper-cased when they end a Go identifier segment
+var commonInitialisms = map[string]string{
+	"Id":   "ID",
+	"Url":  "URL",
+	"Uri":  "URI",
+	"Api":  "API",
+	"Json": "JSON",
+	"Html": "HTML",
+	"Http": "HTTP",
+	"Ip":   "IP",
+	"Uuid": "UUID",
+	"Sql":  "SQL",
+}
+
+// goIdentifier converts a schema name (camelCase or snake_case) to an exported Go identifier
+func goIdentifier(name string) string {
+	pascal := utils.ToPascalCase(utils.ToSnakeCase(name))
+	if pascal == "" {
+		return "X"
+	}
+
+	// Split into words at upper-case boundaries and fix known initialisms
+	var words []string
+	start := 0
+	for i := 1; i < len(pascal); i++ {
+		if pascal[i] >= 'A' && pascal[i] <= 'Z' {
+			words = append(words, pascal[start:i])
+			start = i
+		}
+	}
+	words = append(words, pascal[start:])
+	for i, word := range words {
+		if initialism, ok := commonInitialisms[word]; ok {
+			words[i] = initialism
+		}
+	}
+
+	ident := strings.Join(words, "")
+	if ident[0] >= '0' && ident[0] <= '9' {
+		ident = "X" + ident
+	}
+	return ident
+}
+
+// sortedModels returns the schemas of a definition ordered by model name
+func sortedModels(def *prisma.Definition) []*schema.Schema {
+	names := make([]string, 0, len(def.Schemas))
+	for name := range def.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	models := make([]*schema.Schema, len(names))
+	for i, name := range names {
+		models[i] = def.Schemas[name]
+	}
+	return models
+}
+
+// sortedRelationNames returns relation names of a schema in stable order
+func sortedRelationNames(s *schema.Schema) []string {
+	names := make([]string, 0, len(s.Relations))
+	for name := range s.Relations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortedEnumNames returns enum names of a definition in stable order
+func sortedEnumNames(def *prisma.Definition) []string {
+	names := make([]string, 0, len(def.Enums))
+	for name := range def.Enums {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isToMany reports whether a relation yields a list of related records
+func isToMany(r schema.Relation) bool {
+	return r.Type == schema.RelationOneToMany || r.Type == schema.RelationManyToMany
+}
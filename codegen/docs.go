@@ -0,0 +1,398 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"html/template"
+	"strings"
+
+	"github.com/rediwo/redi-orm/prisma"
+	"github.com/rediwo/redi-orm/schema"
+)
+
+// Documentation formats
+const (
+	DocsMarkdown = "markdown"
+	DocsHTML     = "html"
+)
+
+// DocsOptions configures schema documentation generation
+type DocsOptions struct {
+	// FileName is the name of the generated file (default: "schema.md", or "schema.html" for HTML)
+	FileName string
+	// Title heads the document (default: "Schema")
+	Title string
+	// Format is DocsMarkdown (the default) or DocsHTML
+	Format string
+}
+
+// GenerateDocs renders documentation of the models and enums of def, with a Mermaid ER
+// diagram of the models and their relations
+func GenerateDocs(def *prisma.Definition, opts DocsOptions) ([]File, error) {
+	if opts.Format == "" {
+		opts.Format = DocsMarkdown
+	}
+	if opts.Title == "" {
+		opts.Title = "Schema"
+	}
+
+	var buf bytes.Buffer
+	switch opts.Format {
+	case DocsMarkdown:
+		if opts.FileName == "" {
+			opts.FileName = "schema.md"
+		}
+		docsMarkdown(&buf, def, opts.Title)
+	case DocsHTML:
+		if opts.FileName == "" {
+			opts.FileName = "schema.html"
+		}
+		if err := docsHTMLTemplate.Execute(&buf, docsHTMLPage{Title: opts.Title, Def: def}); err != nil {
+			return nil, fmt.Errorf("failed to render documentation: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported documentation format: %s (supported: %s, %s)", opts.Format, DocsMarkdown, DocsHTML)
+	}
+
+	return []File{{Name: opts.FileName, Content: buf.Bytes()}}, nil
+}
+
+// docsMarkdown writes the Markdown document of def
+func docsMarkdown(buf *bytes.Buffer, def *prisma.Definition, title string) {
+	fmt.Fprintf(buf, "<!-- %s -->\n\n# %s\n", generatedHeader, title)
+
+	models := sortedModels(def)
+	if len(models) > 0 {
+		fmt.Fprintf(buf, "\n## Diagram\n\n```mermaid\n%s```\n", MermaidDiagram(def))
+		buf.WriteString("\n## Models\n")
+		for _, s := range models {
+			docsModel(buf, s)
+		}
+	}
+
+	if enumNames := sortedEnumNames(def); len(enumNames) > 0 {
+		buf.WriteString("\n## Enums\n")
+		for _, name := range enumNames {
+			fmt.Fprintf(buf, "\n### %s\n\n", name)
+			for _, value := range def.Enums[name] {
+				fmt.Fprintf(buf, "- `%s`\n", value)
+			}
+		}
+	}
+}
+
+// docsModel writes the Markdown section of a model
+func docsModel(buf *bytes.Buffer, s *schema.Schema) {
+	fmt.Fprintf(buf, "\n### %s\n\n", s.Name)
+	if s.Comment != "" {
+		fmt.Fprintf(buf, "%s\n\n", s.Comment)
+	}
+	fmt.Fprintf(buf, "Table: `%s`\n\n", s.TableName)
+
+	buf.WriteString("| Field | Type | Attributes | Description |\n")
+	buf.WriteString("| --- | --- | --- | --- |\n")
+	for _, f := range s.Fields {
+		fmt.Fprintf(buf, "| %s | `%s` | %s | %s |\n", f.Name, docsFieldType(f), docsFieldAttributes(f, markdownCode), docsCell(f.Comment))
+	}
+
+	if len(s.CompositeKey) > 0 {
+		fmt.Fprintf(buf, "\nPrimary key: %s\n", docsFieldList(s.CompositeKey, markdownCode))
+	}
+
+	if relationNames := sortedRelationNames(s); len(relationNames) > 0 {
+		buf.WriteString("\n**Relations**\n\n")
+		for _, name := range relationNames {
+			fmt.Fprintf(buf, "- `%s`: %s\n", name, docsRelation(s, s.Relations[name]))
+		}
+	}
+
+	if len(s.Indexes) > 0 {
+		buf.WriteString("\n**Indexes**\n\n")
+		for _, index := range s.Indexes {
+			fmt.Fprintf(buf, "- %s\n", docsIndex(index, markdownCode))
+		}
+	}
+}
+
+// markdownCode and htmlCode format text as inline code
+func markdownCode(text string) string {
+	return "`" + text + "`"
+}
+
+func htmlCode(text string) string {
+	return "<code>" + html.EscapeString(text) + "</code>"
+}
+
+// docsFieldType returns the type of a field as written in schemas
+func docsFieldType(f schema.Field) string {
+	fieldType := string(f.Type)
+	if f.Enum != "" {
+		fieldType = f.Enum
+		if f.Type == schema.FieldTypeStringArray {
+			fieldType += "[]"
+		}
+	}
+	if f.Nullable {
+		fieldType += "?"
+	}
+	return fieldType
+}
+
+// docsFieldAttributes returns the attributes of a field that matter to readers, with values
+// formatted by code
+func docsFieldAttributes(f schema.Field, code func(string) string) string {
+	var attributes []string
+	if f.PrimaryKey {
+		attributes = append(attributes, "id")
+	}
+	if f.AutoIncrement {
+		attributes = append(attributes, "autoincrement")
+	}
+	if f.Unique {
+		attributes = append(attributes, "unique")
+	}
+	if f.Default != nil {
+		attributes = append(attributes, "default: "+code(fmt.Sprint(f.Default)))
+	}
+	if f.UpdatedAt {
+		attributes = append(attributes, "updatedAt")
+	}
+	if f.DbType != "" {
+		attributes = append(attributes, code(f.DbType))
+	}
+	if f.Map != "" {
+		attributes = append(attributes, "column: "+code(f.Map))
+	}
+	return strings.Join(attributes, ", ")
+}
+
+// docsRelation describes a relation of s
+func docsRelation(s *schema.Schema, r schema.Relation) string {
+	target := r.Model
+	if r.IsPolymorphic() && target == "" {
+		target = strings.Join(r.Models, " | ")
+	}
+	if isToMany(r) {
+		target += "[]"
+	}
+	description := fmt.Sprintf("%s (%s)", target, r.Type)
+	if localField, relatedField := r.JoinFields(s); localField != "" && relatedField != "" && r.Type != schema.RelationManyToMany {
+		description += fmt.Sprintf(", %s → %s", localField, relatedField)
+	}
+	return description
+}
+
+// docsIndex describes an index, with names formatted by code
+func docsIndex(index schema.Index, code func(string) string) string {
+	kind := "index"
+	if index.Unique {
+		kind = "unique"
+	}
+	description := fmt.Sprintf("%s on %s", kind, docsFieldList(index.Fields, code))
+	if index.Name != "" {
+		description += fmt.Sprintf(" (%s)", code(index.Name))
+	}
+	return description
+}
+
+// docsFieldList returns field names formatted by code
+func docsFieldList(fields []string, code func(string) string) string {
+	formatted := make([]string, len(fields))
+	for i, field := range fields {
+		formatted[i] = code(field)
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// docsCell escapes text for a Markdown table cell
+func docsCell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.ReplaceAll(text, "\n", " ")
+}
+
+// MermaidDiagram returns a Mermaid ER diagram of the models of def with their fields and
+// relations. Each relation is drawn once, from the model holding its foreign key.
+func MermaidDiagram(def *prisma.Definition) string {
+	var buf bytes.Buffer
+	buf.WriteString("erDiagram\n")
+	models := sortedModels(def)
+	for _, s := range models {
+		foreignKeys := make(map[string]bool)
+		for _, r := range s.Relations {
+			if mermaidOwnsRelation(s, r) {
+				foreignKeys[r.ForeignKey] = true
+			}
+		}
+
+		fmt.Fprintf(&buf, "    %s {\n", s.Name)
+		for _, f := range s.Fields {
+			fieldType := strings.TrimSuffix(docsFieldType(f), "?")
+			line := fmt.Sprintf("        %s %s", fieldType, f.Name)
+			var keys []string
+			if f.PrimaryKey || contains(s.CompositeKey, f.Name) {
+				keys = append(keys, "PK")
+			}
+			if foreignKeys[f.Name] {
+				keys = append(keys, "FK")
+			}
+			if f.Unique {
+				keys = append(keys, "UK")
+			}
+			if len(keys) > 0 {
+				line += " " + strings.Join(keys, ", ")
+			}
+			if f.Nullable {
+				line += ` "optional"`
+			}
+			buf.WriteString(line + "\n")
+		}
+		buf.WriteString("    }\n")
+	}
+
+	for _, s := range models {
+		for _, name := range sortedRelationNames(s) {
+			r := s.Relations[name]
+			switch {
+			case r.Model == "" && r.IsPolymorphic():
+				for _, model := range r.Models {
+					fmt.Fprintf(&buf, "    %s }o--o| %s : %q\n", s.Name, model, name)
+				}
+			case r.Type == schema.RelationManyToMany:
+				// Both sides are many-to-many; draw the relation from the first model by name
+				if s.Name <= r.Model {
+					fmt.Fprintf(&buf, "    %s }o--o{ %s : %q\n", s.Name, r.Model, name)
+				}
+			case mermaidOwnsRelation(s, r):
+				parent := "||"
+				if localField, _ := r.JoinFields(s); localField != "" {
+					if f := s.GetFieldByName(localField); f != nil && f.Nullable {
+						parent = "|o"
+					}
+				}
+				children := "o{"
+				if r.Type == schema.RelationOneToOne {
+					children = "o|"
+				}
+				fmt.Fprintf(&buf, "    %s %s--%s %s : %q\n", r.Model, parent, children, s.Name, name)
+			}
+		}
+	}
+	return buf.String()
+}
+
+// mermaidOwnsRelation reports whether s holds the foreign key of a relation other than
+// many-to-many
+func mermaidOwnsRelation(s *schema.Schema, r schema.Relation) bool {
+	if r.Type == schema.RelationManyToOne {
+		return true
+	}
+	if r.Type == schema.RelationOneToOne {
+		_, err := s.GetField(r.ForeignKey)
+		return err == nil
+	}
+	return false
+}
+
+// docsHTMLPage is the data of the HTML template
+type docsHTMLPage struct {
+	Title string
+	Def   *prisma.Definition
+}
+
+var docsHTMLTemplate = template.Must(template.New("docs").Funcs(template.FuncMap{
+	"header":    func() string { return generatedHeader },
+	"models":    sortedModels,
+	"enumNames": sortedEnumNames,
+	"diagram":   MermaidDiagram,
+	"fieldType": docsFieldType,
+	"relationNames": func(s *schema.Schema) []string {
+		return sortedRelationNames(s)
+	},
+	"relation": func(s *schema.Schema, name string) string {
+		return docsRelation(s, s.Relations[name])
+	},
+	"attributes": func(f schema.Field) template.HTML {
+		return template.HTML(docsFieldAttributes(f, htmlCode))
+	},
+	"indexDescription": func(index schema.Index) template.HTML {
+		return template.HTML(docsIndex(index, htmlCode))
+	},
+	"fieldList": func(fields []string) template.HTML {
+		return template.HTML(docsFieldList(fields, htmlCode))
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="utf-8"/>
+    <meta name="generator" content="{{header}}"/>
+    <meta name="viewport" content="width=device-width, initial-scale=1"/>
+    <title>{{.Title}}</title>
+    <style>
+        body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 960px; margin: 2em auto; padding: 0 1em; color: #24292f; }
+        table { border-collapse: collapse; width: 100%; margin: 1em 0; }
+        th, td { border: 1px solid #d0d7de; padding: 6px 12px; text-align: left; }
+        th { background: #f6f8fa; }
+        code { background: #f6f8fa; padding: 0.1em 0.3em; border-radius: 4px; }
+    </style>
+    <script type="module">
+        import mermaid from 'https://cdn.jsdelivr.net/npm/mermaid@11/dist/mermaid.esm.min.mjs';
+        mermaid.initialize({ startOnLoad: true });
+    </script>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{- $models := models .Def}}
+{{- if $models}}
+<h2>Diagram</h2>
+<pre class="mermaid">
+{{diagram .Def}}</pre>
+<h2>Models</h2>
+{{- range $models}}
+{{- $model := .}}
+<h3 id="{{.Name}}">{{.Name}}</h3>
+{{- if .Comment}}
+<p>{{.Comment}}</p>
+{{- end}}
+<p>Table: <code>{{.TableName}}</code></p>
+<table>
+<tr><th>Field</th><th>Type</th><th>Attributes</th><th>Description</th></tr>
+{{- range .Fields}}
+<tr><td>{{.Name}}</td><td><code>{{fieldType .}}</code></td><td>{{attributes .}}</td><td>{{.Comment}}</td></tr>
+{{- end}}
+</table>
+{{- if .CompositeKey}}
+<p>Primary key: {{fieldList .CompositeKey}}</p>
+{{- end}}
+{{- with relationNames .}}
+<p><strong>Relations</strong></p>
+<ul>
+{{- range .}}
+<li><code>{{.}}</code>: {{relation $model .}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .Indexes}}
+<p><strong>Indexes</strong></p>
+<ul>
+{{- range .Indexes}}
+<li>{{indexDescription .}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- end}}
+{{- end}}
+{{- with enumNames .Def}}
+<h2>Enums</h2>
+{{- range .}}
+<h3 id="{{.}}">{{.}}</h3>
+<ul>
+{{- range index $.Def.Enums .}}
+<li><code>{{.}}</code></li>
+{{- end}}
+</ul>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,120 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rediwo/redi-orm/prisma"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateDocs(t *testing.T) {
+	def, err := prisma.ParseDefinition(`
+/// Registered users
+model User {
+  id    Int     @id @default(autoincrement())
+  /// Login address
+  email String  @unique
+  name  String?
+  role  Role    @default(USER)
+  posts Post[]
+
+  @@index([name])
+}
+
+model Post {
+  id       Int    @id @default(autoincrement())
+  authorId Int
+  author   User   @relation(fields: [authorId], references: [id])
+}
+
+enum Role {
+  USER
+  ADMIN
+}
+`)
+	require.NoError(t, err)
+
+	files, err := GenerateDocs(def, DocsOptions{})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "schema.md", files[0].Name)
+
+	content := string(files[0].Content)
+	assert.True(t, strings.HasPrefix(content, "<!-- "+generatedHeader+" -->\n\n# Schema\n"))
+	assert.Contains(t, content, "### User\n\nRegistered users\n\nTable: `users`\n")
+	assert.Contains(t, content, "| id | `int` | id, autoincrement |  |\n")
+	assert.Contains(t, content, "| email | `string` | unique | Login address |\n")
+	assert.Contains(t, content, "| name | `string?` |  |  |\n")
+	assert.Contains(t, content, "| role | `Role` | default: `USER` |  |\n")
+	assert.Contains(t, content, "- `posts`: Post[] (oneToMany)")
+	assert.Contains(t, content, "- `author`: User (manyToOne), authorId → id\n")
+	assert.Contains(t, content, "**Indexes**\n\n- index on `name`")
+	assert.Contains(t, content, "## Enums\n\n### Role\n\n- `USER`\n- `ADMIN`\n")
+
+	// Models are ordered by name
+	assert.Less(t, strings.Index(content, "### Post"), strings.Index(content, "### User"))
+
+	// The diagram precedes the models
+	assert.Contains(t, content, "## Diagram\n\n```mermaid\nerDiagram\n    Post {\n")
+	assert.Less(t, strings.Index(content, "## Diagram"), strings.Index(content, "## Models"))
+}
+
+func TestGenerateDocsHTML(t *testing.T) {
+	def, err := prisma.ParseDefinition(testSchema + `
+/// Public <b>profile</b>
+model Profile {
+  id     Int    @id @default(autoincrement())
+  bio    String
+}
+`)
+	require.NoError(t, err)
+
+	files, err := GenerateDocs(def, DocsOptions{Format: DocsHTML, Title: "Blog"})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "schema.html", files[0].Name)
+
+	content := string(files[0].Content)
+	assert.True(t, strings.HasPrefix(content, "<!DOCTYPE html>\n"))
+	assert.Contains(t, content, "<title>Blog</title>")
+	assert.Contains(t, content, "mermaid.initialize")
+	assert.Contains(t, content, "<pre class=\"mermaid\">\nerDiagram\n")
+	assert.Contains(t, content, "<tr><td>role</td><td><code>Role</code></td><td>default: <code>USER</code></td><td></td></tr>")
+	assert.Contains(t, content, "<li><code>author</code>: User (manyToOne), authorId → id</li>")
+	assert.Contains(t, content, "<h3 id=\"Role\">Role</h3>\n<ul>\n<li><code>USER</code></li>")
+
+	// Schema text is escaped
+	assert.Contains(t, content, "<p>Public &lt;b&gt;profile&lt;/b&gt;</p>")
+
+	_, err = GenerateDocs(def, DocsOptions{Format: "pdf"})
+	assert.EqualError(t, err, "unsupported documentation format: pdf (supported: markdown, html)")
+}
+
+func TestMermaidDiagram(t *testing.T) {
+	def, err := prisma.ParseDefinition(testSchema + `
+model Profile {
+  id     Int     @id @default(autoincrement())
+  userId Int?    @unique
+  user   User?   @relation(fields: [userId], references: [id])
+}
+
+model Tag {
+  id    Int    @id @default(autoincrement())
+  posts Post[]
+}
+`)
+	require.NoError(t, err)
+
+	diagram := MermaidDiagram(def)
+	assert.True(t, strings.HasPrefix(diagram, "erDiagram\n"))
+	assert.Contains(t, diagram, "    User {\n        int id PK\n        string email UK\n        string name \"optional\"\n        Role role\n")
+	assert.Contains(t, diagram, "        int authorId FK\n")
+	assert.Contains(t, diagram, "        int userId FK, UK \"optional\"\n")
+
+	// Relations are drawn once, from the model holding the foreign key
+	assert.Contains(t, diagram, "    User ||--o{ Post : \"author\"\n")
+	assert.Contains(t, diagram, "    User |o--o{ Profile : \"user\"\n")
+	assert.NotContains(t, diagram, ": \"posts\"")
+}
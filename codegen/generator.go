@@ -0,0 +1,109 @@
+package codegen
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/rediwo/redi-orm/prisma"
+)
+
+// Generator generates files from a schema for the generator blocks naming its provider.
+// Custom generators are made available with RegisterGenerator.
+type Generator interface {
+	Generate(def *prisma.Definition, config GeneratorConfig) ([]File, error)
+}
+
+// GeneratorFunc adapts a function to the Generator interface
+type GeneratorFunc func(def *prisma.Definition, config GeneratorConfig) ([]File, error)
+
+// Generate calls f
+func (f GeneratorFunc) Generate(def *prisma.Definition, config GeneratorConfig) ([]File, error) {
+	return f(def, config)
+}
+
+// GeneratorConfig is a generator block of a schema
+type GeneratorConfig struct {
+	Name     string
+	Provider string
+	Output   string            // Output directory as written in the schema, may be empty
+	Options  map[string]string // The other properties, such as package for Go
+}
+
+var (
+	generatorsMu sync.RWMutex
+	generators   = map[string]Generator{
+		"go": GeneratorFunc(func(def *prisma.Definition, config GeneratorConfig) ([]File, error) {
+			return GenerateGo(def, GoOptions{Package: config.Options["package"]})
+		}),
+		"ts": GeneratorFunc(func(def *prisma.Definition, config GeneratorConfig) ([]File, error) {
+			return GenerateTypeScript(def, TypeScriptOptions{FileName: config.Options["fileName"]})
+		}),
+		"openapi": GeneratorFunc(func(def *prisma.Definition, config GeneratorConfig) ([]File, error) {
+			return GenerateOpenAPI(def, OpenAPIOptions{
+				FileName: config.Options["fileName"],
+				Title:    config.Options["title"],
+				Version:  config.Options["version"],
+			})
+		}),
+		"docs": GeneratorFunc(func(def *prisma.Definition, config GeneratorConfig) ([]File, error) {
+			return GenerateDocs(def, DocsOptions{
+				FileName: config.Options["fileName"],
+				Title:    config.Options["title"],
+				Format:   config.Options["format"],
+			})
+		}),
+	}
+)
+
+// RegisterGenerator makes g run the generator blocks whose provider is provider, replacing
+// the generator registered before, built-in ones included
+func RegisterGenerator(provider string, g Generator) {
+	generatorsMu.Lock()
+	defer generatorsMu.Unlock()
+	generators[provider] = g
+}
+
+// LookupGenerator returns the generator registered for provider
+func LookupGenerator(provider string) (Generator, bool) {
+	generatorsMu.RLock()
+	defer generatorsMu.RUnlock()
+	g, ok := generators[provider]
+	return g, ok
+}
+
+// GeneratorProviders returns the providers of the registered generators, sorted
+func GeneratorProviders() []string {
+	generatorsMu.RLock()
+	defer generatorsMu.RUnlock()
+	providers := make([]string, 0, len(generators))
+	for provider := range generators {
+		providers = append(providers, provider)
+	}
+	slices.Sort(providers)
+	return providers
+}
+
+// GeneratorConfigs returns the generator blocks of def in the order they are declared
+func GeneratorConfigs(def *prisma.Definition) ([]GeneratorConfig, error) {
+	configs := make([]GeneratorConfig, 0, len(def.Generators))
+	for _, block := range def.Generators {
+		properties, err := block.Config()
+		if err != nil {
+			return nil, err
+		}
+		config := GeneratorConfig{
+			Name:     block.Name,
+			Provider: properties["provider"],
+			Output:   properties["output"],
+			Options:  properties,
+		}
+		delete(config.Options, "provider")
+		delete(config.Options, "output")
+		if config.Provider == "" {
+			return nil, fmt.Errorf("generator %s has no provider", block.Name)
+		}
+		configs = append(configs, config)
+	}
+	return configs, nil
+}
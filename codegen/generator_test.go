@@ -0,0 +1,69 @@
+package codegen
+
+import (
+	"testing"
+
+	"github.com/rediwo/redi-orm/prisma"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratorConfigs(t *testing.T) {
+	t.Setenv("DOCS_TITLE", "Blog")
+	def, err := prisma.ParseDefinition(`
+generator client {
+  provider = "go"
+  output   = "./db"
+  package  = "db"
+}
+
+generator docs {
+  provider = "docs"
+  title    = env("DOCS_TITLE")
+}
+` + testSchema)
+	require.NoError(t, err)
+
+	configs, err := GeneratorConfigs(def)
+	require.NoError(t, err)
+	assert.Equal(t, []GeneratorConfig{
+		{Name: "client", Provider: "go", Output: "./db", Options: map[string]string{"package": "db"}},
+		{Name: "docs", Provider: "docs", Options: map[string]string{"title": "Blog"}},
+	}, configs)
+
+	g, ok := LookupGenerator("docs")
+	require.True(t, ok)
+	files, err := g.Generate(def, configs[1])
+	require.NoError(t, err)
+	assert.Contains(t, string(files[0].Content), "# Blog\n")
+
+	// Blocks must name a provider
+	def, err = prisma.ParseDefinition("generator client {\n  output = \"./db\"\n}\n" + testSchema)
+	require.NoError(t, err)
+	_, err = GeneratorConfigs(def)
+	assert.EqualError(t, err, "generator client has no provider")
+}
+
+func TestRegisterGenerator(t *testing.T) {
+	_, ok := LookupGenerator("custom")
+	assert.False(t, ok)
+	assert.Equal(t, []string{"docs", "go", "openapi", "ts"}, GeneratorProviders())
+
+	RegisterGenerator("custom", GeneratorFunc(func(def *prisma.Definition, config GeneratorConfig) ([]File, error) {
+		return []File{{Name: "models.txt", Content: []byte(config.Options["prefix"] + sortedModels(def)[0].Name)}}, nil
+	}))
+	t.Cleanup(func() {
+		generatorsMu.Lock()
+		delete(generators, "custom")
+		generatorsMu.Unlock()
+	})
+
+	def, err := prisma.ParseDefinition(testSchema)
+	require.NoError(t, err)
+	g, ok := LookupGenerator("custom")
+	require.True(t, ok)
+	files, err := g.Generate(def, GeneratorConfig{Name: "models", Provider: "custom", Options: map[string]string{"prefix": "model "}})
+	require.NoError(t, err)
+	assert.Equal(t, []File{{Name: "models.txt", Content: []byte("model Post")}}, files)
+	assert.Contains(t, GeneratorProviders(), "custom")
+}
@@ -0,0 +1,355 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+
+	"github.com/rediwo/redi-orm/prisma"
+	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/utils"
+)
+
+// GoOptions configures Go client generation
+type GoOptions struct {
+	// Package is the package name of the generated files (default: "models")
+	Package string
+}
+
+// GenerateGo renders typed Go structs, enums and query builders for every model in def
+func GenerateGo(def *prisma.Definition, opts GoOptions) ([]File, error) {
+	if opts.Package == "" {
+		opts.Package = "models"
+	}
+
+	g := &goGenerator{def: def, pkg: opts.Package}
+
+	var files []File
+	if len(def.Enums) > 0 {
+		content, err := g.render(g.enumsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate enums: %w", err)
+		}
+		files = append(files, File{Name: "enums.go", Content: content})
+	}
+
+	for _, s := range sortedModels(def) {
+		s := s
+		content, err := g.render(func(buf *bytes.Buffer) { g.modelFile(buf, s) })
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate model %s: %w", s.Name, err)
+		}
+		files = append(files, File{Name: utils.ToSnakeCase(s.Name) + ".go", Content: content})
+	}
+
+	content, err := g.render(g.clientFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate client: %w", err)
+	}
+	files = append(files, File{Name: "client.go", Content: content})
+
+	return files, nil
+}
+
+// goGenerator holds state shared by the Go file writers
+type goGenerator struct {
+	def *prisma.Definition
+	pkg string
+}
+
+// render runs a writer and gofmt's the result
+func (g *goGenerator) render(write func(buf *bytes.Buffer)) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// %s\n\npackage %s\n\n", generatedHeader, g.pkg)
+	write(&buf)
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated code does not compile: %w\n%s", err, buf.String())
+	}
+	return formatted, nil
+}
+
+// enumsFile writes all enum types
+func (g *goGenerator) enumsFile(buf *bytes.Buffer) {
+	for _, name := range sortedEnumNames(g.def) {
+		typeName := goIdentifier(name)
+		values := g.def.Enums[name]
+
+		fmt.Fprintf(buf, "// %s is the %s enum\ntype %s string\n\n", typeName, name, typeName)
+		buf.WriteString("const (\n")
+		for _, value := range values {
+			fmt.Fprintf(buf, "\t%s%s %s = %q\n", typeName, goIdentifier(strings.ToLower(value)), typeName, value)
+		}
+		buf.WriteString(")\n\n")
+
+		fmt.Fprintf(buf, "// %sValues returns all values of the %s enum\n", typeName, name)
+		fmt.Fprintf(buf, "func %sValues() []%s {\n\treturn []%s{", typeName, typeName, typeName)
+		for i, value := range values {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			fmt.Fprintf(buf, "%s%s", typeName, goIdentifier(strings.ToLower(value)))
+		}
+		buf.WriteString("}\n}\n\n")
+
+		fmt.Fprintf(buf, "// IsValid reports whether v is a declared %s value\n", name)
+		fmt.Fprintf(buf, "func (v %s) IsValid() bool {\n\tfor _, value := range %sValues() {\n\t\tif v == value {\n\t\t\treturn true\n\t\t}\n\t}\n\treturn false\n}\n\n", typeName, typeName)
+	}
+}
+
+// goFieldType returns the Go type used for a schema field
+func (g *goGenerator) goFieldType(f schema.Field) string {
+	var base string
+	if f.Enum != "" {
+		base = goIdentifier(f.Enum)
+		if f.Type == schema.FieldTypeStringArray {
+			return "[]" + base
+		}
+	} else {
+		switch f.Type {
+		case schema.FieldTypeString, schema.FieldTypeObjectId:
+			base = "string"
+		case schema.FieldTypeInt:
+			base = "int"
+		case schema.FieldTypeInt64:
+			base = "int64"
+		case schema.FieldTypeFloat:
+			base = "float64"
+		case schema.FieldTypeDecimal, schema.FieldTypeDecimal128:
+			// Decimals are read as their exact text
+			base = "string"
+		case schema.FieldTypeBool:
+			base = "bool"
+		case schema.FieldTypeDateTime, schema.FieldTypeTimestamp:
+			base = "time.Time"
+		case schema.FieldTypeJSON:
+			// JSON is kept as its encoded text so it scans from every driver
+			base = "string"
+		case schema.FieldTypeBytes:
+			return "[]byte"
+		case schema.FieldTypeDocument:
+			return "map[string]any"
+		case schema.FieldTypeArray:
+			return "[]any"
+		case schema.FieldTypeStringArray:
+			return "[]string"
+		case schema.FieldTypeIntArray:
+			return "[]int"
+		case schema.FieldTypeInt64Array:
+			return "[]int64"
+		case schema.FieldTypeFloatArray:
+			return "[]float64"
+		case schema.FieldTypeDecimalArray:
+			return "[]string"
+		case schema.FieldTypeBoolArray:
+			return "[]bool"
+		case schema.FieldTypeDateTimeArray:
+			return "[]time.Time"
+		default:
+			base = "string"
+		}
+	}
+
+	if f.Nullable {
+		return "*" + base
+	}
+	return base
+}
+
+// modelImports returns the standard library imports needed by a model file
+func (g *goGenerator) modelImports(s *schema.Schema) []string {
+	imports := []string{"context", "fmt"}
+	for _, f := range s.Fields {
+		goType := g.goFieldType(f)
+		if strings.Contains(goType, "time.Time") && !contains(imports, "time") {
+			imports = append(imports, "time")
+		}
+	}
+	return imports
+}
+
+// modelFile writes the struct, field constants, client and query builder of a model
+func (g *goGenerator) modelFile(buf *bytes.Buffer, s *schema.Schema) {
+	typeName := goIdentifier(s.Name)
+
+	buf.WriteString("import (\n")
+	for _, imp := range g.modelImports(s) {
+		fmt.Fprintf(buf, "\t%q\n", imp)
+	}
+	buf.WriteString("\n\t\"github.com/rediwo/redi-orm/types\"\n)\n\n")
+
+	// Struct
+	fmt.Fprintf(buf, "// %s is the typed representation of the %s model\ntype %s struct {\n", typeName, s.Name, typeName)
+	for _, f := range s.Fields {
+		jsonTag := f.Name
+		if f.Nullable {
+			jsonTag += ",omitempty"
+		}
+		fmt.Fprintf(buf, "\t%s %s `db:%q json:%q`\n", goIdentifier(f.Name), g.goFieldType(f), f.GetColumnName(), jsonTag)
+	}
+	if relationNames := sortedRelationNames(s); len(relationNames) > 0 {
+		buf.WriteString("\n\t// Relations (populated when included)\n")
+		for _, name := range relationNames {
+			rel := s.Relations[name]
+			relType := "*" + goIdentifier(rel.Model)
+			if isToMany(rel) {
+				relType = "[]" + goIdentifier(rel.Model)
+			}
+			fmt.Fprintf(buf, "\t%s %s `db:\"-\" json:%q`\n", goIdentifier(name), relType, name+",omitempty")
+		}
+	}
+	buf.WriteString("}\n\n")
+
+	// Field names
+	fmt.Fprintf(buf, "// %sField is a schema field name of the %s model\ntype %sField string\n\n", typeName, s.Name, typeName)
+	buf.WriteString("const (\n")
+	for _, f := range s.Fields {
+		fmt.Fprintf(buf, "\t%sField%s %sField = %q\n", typeName, goIdentifier(f.Name), typeName, f.Name)
+	}
+	buf.WriteString(")\n\n")
+
+	// Relation names
+	if relationNames := sortedRelationNames(s); len(relationNames) > 0 {
+		fmt.Fprintf(buf, "// Relation names of the %s model, for use with Include\nconst (\n", s.Name)
+		for _, name := range relationNames {
+			fmt.Fprintf(buf, "\t%sRelation%s = %q\n", typeName, goIdentifier(name), name)
+		}
+		buf.WriteString(")\n\n")
+	}
+
+	// toMap
+	fmt.Fprintf(buf, "// toMap converts the record to schema field data, skipping unset optional and generated values\n")
+	fmt.Fprintf(buf, "func (r *%s) toMap() map[string]any {\n\tdata := make(map[string]any)\n", typeName)
+	for _, f := range s.Fields {
+		ident := goIdentifier(f.Name)
+		goType := g.goFieldType(f)
+
+		// Drivers expect plain values, so dereference pointers and unwrap enum types
+		value := "r." + ident
+		if f.Nullable && strings.HasPrefix(goType, "*") {
+			value = "*" + value
+		}
+		if f.Enum != "" && f.Type != schema.FieldTypeStringArray {
+			value = "string(" + value + ")"
+		}
+
+		switch {
+		case f.Nullable || strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map["):
+			fmt.Fprintf(buf, "\tif r.%s != nil {\n\t\tdata[%q] = %s\n\t}\n", ident, f.Name, value)
+		case f.AutoIncrement || f.Default != nil:
+			fmt.Fprintf(buf, "\tif r.%s != %s {\n\t\tdata[%q] = %s\n\t}\n", ident, zeroValue(goType), f.Name, value)
+		default:
+			fmt.Fprintf(buf, "\tdata[%q] = %s\n", f.Name, value)
+		}
+	}
+	buf.WriteString("\treturn data\n}\n\n")
+
+	g.clientType(buf, s)
+	g.queryType(buf, s)
+}
+
+// clientType writes the per-model client
+func (g *goGenerator) clientType(buf *bytes.Buffer, s *schema.Schema) {
+	typeName := goIdentifier(s.Name)
+	client := typeName + "Client"
+
+	fmt.Fprintf(buf, "// %s provides typed access to the %s model\ntype %s struct {\n\tdb types.Database\n}\n\n", client, s.Name, client)
+	fmt.Fprintf(buf, "// New%s creates a typed client for the %s model\nfunc New%s(db types.Database) *%s {\n\treturn &%s{db: db}\n}\n\n", client, s.Name, client, client, client)
+
+	fmt.Fprintf(buf, "// Where starts a condition on a %s field\nfunc (c *%s) Where(field %sField) types.FieldCondition {\n\treturn c.db.Model(%q).Where(string(field))\n}\n\n", s.Name, client, typeName, s.Name)
+
+	fmt.Fprintf(buf, "// Query starts a typed query on the %s model\nfunc (c *%s) Query() *%sQuery {\n\treturn &%sQuery{query: c.db.Model(%q).Select()}\n}\n\n", s.Name, client, typeName, typeName, s.Name)
+
+	// Create
+	fmt.Fprintf(buf, "// Create inserts a record\nfunc (c *%s) Create(ctx context.Context, record *%s) (types.Result, error) {\n", client, typeName)
+	fmt.Fprintf(buf, "\tresult, err := c.db.Model(%q).Insert(record.toMap()).Exec(ctx)\n\tif err != nil {\n\t\treturn result, fmt.Errorf(\"failed to create %s: %%w\", err)\n\t}\n", s.Name, s.Name)
+	if pk, err := s.GetPrimaryKey(); err == nil && pk.AutoIncrement {
+		goType := g.goFieldType(*pk)
+		if goType == "int" || goType == "int64" {
+			fmt.Fprintf(buf, "\tif record.%s == 0 {\n\t\trecord.%s = %s(result.LastInsertID)\n\t}\n", goIdentifier(pk.Name), goIdentifier(pk.Name), goType)
+		}
+	}
+	buf.WriteString("\treturn result, nil\n}\n\n")
+
+	// Primary key helpers
+	pk, err := s.GetPrimaryKey()
+	if err != nil {
+		return
+	}
+	pkType := g.goFieldType(*pk)
+
+	fmt.Fprintf(buf, "// FindUnique returns the %s with the given primary key\nfunc (c *%s) FindUnique(ctx context.Context, %s %s) (*%s, error) {\n", s.Name, client, "key", pkType, typeName)
+	fmt.Fprintf(buf, "\treturn c.Query().Where(c.Where(%sField%s).Equals(key)).FindFirst(ctx)\n}\n\n", typeName, goIdentifier(pk.Name))
+
+	fmt.Fprintf(buf, "// Update sets the given fields on the %s with the given primary key\nfunc (c *%s) Update(ctx context.Context, key %s, data map[%sField]any) (types.Result, error) {\n", s.Name, client, pkType, typeName)
+	buf.WriteString("\tvalues := make(map[string]any, len(data))\n\tfor field, value := range data {\n\t\tvalues[string(field)] = value\n\t}\n")
+	fmt.Fprintf(buf, "\tquery := c.db.Model(%q).Update(values)\n\treturn query.WhereCondition(query.Where(string(%sField%s)).Equals(key)).Exec(ctx)\n}\n\n", s.Name, typeName, goIdentifier(pk.Name))
+
+	fmt.Fprintf(buf, "// Delete removes the %s with the given primary key\nfunc (c *%s) Delete(ctx context.Context, key %s) (types.Result, error) {\n", s.Name, client, pkType)
+	fmt.Fprintf(buf, "\tquery := c.db.Model(%q).Delete()\n\treturn query.WhereCondition(query.Where(string(%sField%s)).Equals(key)).Exec(ctx)\n}\n\n", s.Name, typeName, goIdentifier(pk.Name))
+}
+
+// queryType writes the per-model query builder
+func (g *goGenerator) queryType(buf *bytes.Buffer, s *schema.Schema) {
+	typeName := goIdentifier(s.Name)
+	query := typeName + "Query"
+
+	fmt.Fprintf(buf, "// %s is a typed query builder for the %s model\ntype %s struct {\n\tquery types.SelectQuery\n}\n\n", query, s.Name, query)
+
+	fmt.Fprintf(buf, "// Where adds conditions that must all match\nfunc (q *%s) Where(conditions ...types.Condition) *%s {\n\tfor _, condition := range conditions {\n\t\tq.query = q.query.WhereCondition(condition)\n\t}\n\treturn q\n}\n\n", query, query)
+	fmt.Fprintf(buf, "// OrderBy sorts by a %s field\nfunc (q *%s) OrderBy(field %sField, direction types.Order) *%s {\n\tq.query = q.query.OrderBy(string(field), direction)\n\treturn q\n}\n\n", s.Name, query, typeName, query)
+	fmt.Fprintf(buf, "// Include loads the given relations\nfunc (q *%s) Include(relations ...string) *%s {\n\tq.query = q.query.Include(relations...)\n\treturn q\n}\n\n", query, query)
+	fmt.Fprintf(buf, "// Limit caps the number of returned records\nfunc (q *%s) Limit(limit int) *%s {\n\tq.query = q.query.Limit(limit)\n\treturn q\n}\n\n", query, query)
+	fmt.Fprintf(buf, "// Offset skips the given number of records\nfunc (q *%s) Offset(offset int) *%s {\n\tq.query = q.query.Offset(offset)\n\treturn q\n}\n\n", query, query)
+
+	fmt.Fprintf(buf, "// FindMany returns all matching records\nfunc (q *%s) FindMany(ctx context.Context) ([]%s, error) {\n\tvar records []%s\n\tif err := q.query.FindMany(ctx, &records); err != nil {\n\t\treturn nil, err\n\t}\n\treturn records, nil\n}\n\n", query, typeName, typeName)
+	fmt.Fprintf(buf, "// FindFirst returns the first matching record\nfunc (q *%s) FindFirst(ctx context.Context) (*%s, error) {\n\tvar record %s\n\tif err := q.query.FindFirst(ctx, &record); err != nil {\n\t\treturn nil, err\n\t}\n\treturn &record, nil\n}\n\n", query, typeName, typeName)
+	fmt.Fprintf(buf, "// Count returns the number of matching records\nfunc (q *%s) Count(ctx context.Context) (int64, error) {\n\treturn q.query.Count(ctx)\n}\n", query)
+}
+
+// clientFile writes the root client bundling all model clients
+func (g *goGenerator) clientFile(buf *bytes.Buffer) {
+	models := sortedModels(g.def)
+
+	buf.WriteString("import \"github.com/rediwo/redi-orm/types\"\n\n")
+	buf.WriteString("// Client bundles typed clients for every model\ntype Client struct {\n")
+	for _, s := range models {
+		fmt.Fprintf(buf, "\t%s *%sClient\n", goIdentifier(s.Name), goIdentifier(s.Name))
+	}
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("// NewClient creates typed clients on top of a connected database\nfunc NewClient(db types.Database) *Client {\n\treturn &Client{\n")
+	for _, s := range models {
+		fmt.Fprintf(buf, "\t\t%s: New%sClient(db),\n", goIdentifier(s.Name), goIdentifier(s.Name))
+	}
+	buf.WriteString("\t}\n}\n")
+}
+
+// zeroValue returns the Go zero value literal for a non-pointer type
+func zeroValue(goType string) string {
+	switch goType {
+	case "int", "int64", "float64":
+		return "0"
+	case "bool":
+		return "false"
+	case "time.Time":
+		return "(time.Time{})"
+	case "string":
+		return `""`
+	default:
+		// Enum types are string based
+		return `""`
+	}
+}
+
+// contains reports whether list contains value
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
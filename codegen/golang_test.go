@@ -0,0 +1,91 @@
+package codegen
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/rediwo/redi-orm/prisma"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchema = `
+enum Role {
+  USER
+  ADMIN
+}
+
+model User {
+  id        Int      @id @default(autoincrement())
+  email     String   @unique
+  name      String?
+  role      Role     @default(USER)
+  createdAt DateTime @default(now())
+  posts     Post[]
+}
+
+model Post {
+  id       Int    @id @default(autoincrement())
+  title    String
+  authorId Int
+  author   User   @relation(fields: [authorId], references: [id])
+}
+`
+
+func TestGoIdentifier(t *testing.T) {
+	tests := map[string]string{
+		"id":        "ID",
+		"userId":    "UserID",
+		"createdAt": "CreatedAt",
+		"user_name": "UserName",
+		"avatarUrl": "AvatarURL",
+		"User":      "User",
+	}
+	for input, expected := range tests {
+		assert.Equal(t, expected, goIdentifier(input), input)
+	}
+}
+
+func TestGenerateGo(t *testing.T) {
+	def, err := prisma.ParseDefinition(testSchema)
+	require.NoError(t, err)
+
+	files, err := GenerateGo(def, GoOptions{Package: "db"})
+	require.NoError(t, err)
+
+	contents := make(map[string]string)
+	for _, file := range files {
+		_, err := parser.ParseFile(token.NewFileSet(), file.Name, file.Content, 0)
+		require.NoError(t, err, "generated %s must parse", file.Name)
+		assert.True(t, strings.HasPrefix(string(file.Content), "// "+generatedHeader))
+		assert.Contains(t, string(file.Content), "package db")
+		contents[file.Name] = string(file.Content)
+	}
+
+	require.Contains(t, contents, "enums.go")
+	require.Contains(t, contents, "user.go")
+	require.Contains(t, contents, "post.go")
+	require.Contains(t, contents, "client.go")
+
+	// Enums
+	assert.Contains(t, contents["enums.go"], "type Role string")
+	assert.Contains(t, contents["enums.go"], `RoleAdmin Role = "ADMIN"`)
+
+	// Structs use column names for scanning and field names for JSON
+	user := contents["user.go"]
+	assert.Contains(t, user, "CreatedAt time.Time `db:\"created_at\" json:\"createdAt\"`")
+	assert.Contains(t, user, "Name      *string")
+	assert.Contains(t, user, "Role      Role")
+	assert.Contains(t, user, "Posts []Post `db:\"-\" json:\"posts,omitempty\"`")
+	assert.Contains(t, user, "func (c *UserClient) FindUnique(ctx context.Context, key int) (*User, error)")
+	assert.Contains(t, user, "record.ID = int(result.LastInsertID)")
+	assert.Contains(t, user, `data["role"] = string(r.Role)`)
+
+	post := contents["post.go"]
+	assert.Contains(t, post, "Author *User `db:\"-\" json:\"author,omitempty\"`")
+	assert.Contains(t, post, `PostFieldAuthorID PostField = "authorId"`)
+
+	assert.Contains(t, contents["client.go"], "User: NewUserClient(db)")
+}
@@ -0,0 +1,348 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/rediwo/redi-orm/prisma"
+	"github.com/rediwo/redi-orm/schema"
+)
+
+// OpenAPIOptions configures OpenAPI generation
+type OpenAPIOptions struct {
+	// FileName is the name of the generated document (default: "openapi.json")
+	FileName string
+	// Title and Version describe the API (defaults: "RediORM REST API" and "1.0.0")
+	Title   string
+	Version string
+}
+
+// GenerateOpenAPI renders an OpenAPI 3.0 document of the REST API served for the models of
+// def under /api
+func GenerateOpenAPI(def *prisma.Definition, opts OpenAPIOptions) ([]File, error) {
+	if opts.FileName == "" {
+		opts.FileName = "openapi.json"
+	}
+	if opts.Title == "" {
+		opts.Title = "RediORM REST API"
+	}
+	if opts.Version == "" {
+		opts.Version = "1.0.0"
+	}
+
+	schemas := map[string]any{
+		"Error": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"success": map[string]any{"type": "boolean"},
+				"error": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"code":    map[string]any{"type": "string"},
+						"message": map[string]any{"type": "string"},
+						"details": map[string]any{"type": "string"},
+					},
+				},
+			},
+		},
+		"Pagination": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"page":  map[string]any{"type": "integer"},
+				"limit": map[string]any{"type": "integer"},
+				"total": map[string]any{"type": "integer"},
+				"pages": map[string]any{"type": "integer"},
+			},
+		},
+	}
+	for _, name := range sortedEnumNames(def) {
+		schemas[name] = map[string]any{"type": "string", "enum": def.Enums[name]}
+	}
+
+	paths := map[string]any{}
+	for _, s := range sortedModels(def) {
+		schemas[s.Name] = openAPIModel(s)
+		schemas[s.Name+"CreateInput"] = openAPIInput(s, true)
+		schemas[s.Name+"UpdateInput"] = openAPIInput(s, false)
+		paths["/api/"+s.Name] = openAPICollectionPath(s)
+		paths["/api/"+s.Name+"/{id}"] = openAPIRecordPath(s)
+		paths["/api/"+s.Name+"/batch"] = openAPIBatchPath(s)
+	}
+
+	document := map[string]any{
+		"openapi":    "3.0.3",
+		"info":       map[string]any{"title": opts.Title, "version": opts.Version},
+		"paths":      paths,
+		"components": map[string]any{"schemas": schemas},
+	}
+	content, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI document: %w", err)
+	}
+	return []File{{Name: opts.FileName, Content: append(content, '\n')}}, nil
+}
+
+// openAPIRef returns a reference to a component schema
+func openAPIRef(name string) map[string]any {
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+// openAPIFieldType returns the schema of a field value
+func openAPIFieldType(f schema.Field) map[string]any {
+	var fieldSchema map[string]any
+	switch {
+	case f.Enum != "" && f.Type == schema.FieldTypeStringArray:
+		fieldSchema = map[string]any{"type": "array", "items": openAPIRef(f.Enum)}
+	case f.Enum != "":
+		fieldSchema = openAPIRef(f.Enum)
+	default:
+		fieldSchema = openAPIScalarType(f.Type)
+	}
+	if f.Nullable {
+		if _, isRef := fieldSchema["$ref"]; isRef {
+			// Siblings of $ref are ignored in OpenAPI 3.0
+			fieldSchema = map[string]any{"allOf": []any{fieldSchema}}
+		}
+		fieldSchema["nullable"] = true
+	}
+	if f.Comment != "" {
+		fieldSchema["description"] = f.Comment
+	}
+	return fieldSchema
+}
+
+// openAPIScalarType returns the schema of values of a field type
+func openAPIScalarType(fieldType schema.FieldType) map[string]any {
+	switch fieldType {
+	case schema.FieldTypeString, schema.FieldTypeObjectId:
+		return map[string]any{"type": "string"}
+	case schema.FieldTypeDecimal, schema.FieldTypeDecimal128:
+		// Decimals are read as their exact text
+		return map[string]any{"type": "string", "format": "decimal"}
+	case schema.FieldTypeInt:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case schema.FieldTypeInt64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case schema.FieldTypeFloat:
+		return map[string]any{"type": "number", "format": "double"}
+	case schema.FieldTypeBool:
+		return map[string]any{"type": "boolean"}
+	case schema.FieldTypeDateTime, schema.FieldTypeTimestamp:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case schema.FieldTypeBytes:
+		return map[string]any{"type": "string", "format": "byte"}
+	case schema.FieldTypeDocument:
+		return map[string]any{"type": "object"}
+	case schema.FieldTypeArray:
+		return map[string]any{"type": "array", "items": map[string]any{}}
+	case schema.FieldTypeStringArray:
+		return map[string]any{"type": "array", "items": openAPIScalarType(schema.FieldTypeString)}
+	case schema.FieldTypeDecimalArray:
+		return map[string]any{"type": "array", "items": openAPIScalarType(schema.FieldTypeDecimal)}
+	case schema.FieldTypeIntArray:
+		return map[string]any{"type": "array", "items": openAPIScalarType(schema.FieldTypeInt)}
+	case schema.FieldTypeInt64Array:
+		return map[string]any{"type": "array", "items": openAPIScalarType(schema.FieldTypeInt64)}
+	case schema.FieldTypeFloatArray:
+		return map[string]any{"type": "array", "items": openAPIScalarType(schema.FieldTypeFloat)}
+	case schema.FieldTypeBoolArray:
+		return map[string]any{"type": "array", "items": openAPIScalarType(schema.FieldTypeBool)}
+	case schema.FieldTypeDateTimeArray:
+		return map[string]any{"type": "array", "items": openAPIScalarType(schema.FieldTypeDateTime)}
+	default:
+		// JSON values are passed through untyped
+		return map[string]any{}
+	}
+}
+
+// openAPIModel returns the schema of the records of a model, with its relations when included
+func openAPIModel(s *schema.Schema) map[string]any {
+	properties := map[string]any{}
+	required := []string{}
+	for _, f := range s.Fields {
+		properties[f.Name] = openAPIFieldType(f)
+		required = append(required, f.Name)
+	}
+	for _, name := range sortedRelationNames(s) {
+		relation := s.Relations[name]
+		if relation.Model == "" {
+			// Polymorphic relations hold records of several models
+			properties[name] = map[string]any{"type": "object"}
+			continue
+		}
+		if isToMany(relation) {
+			properties[name] = map[string]any{"type": "array", "items": openAPIRef(relation.Model)}
+		} else {
+			properties[name] = openAPIRef(relation.Model)
+		}
+	}
+
+	model := map[string]any{"type": "object", "properties": properties, "required": required}
+	if s.Comment != "" {
+		model["description"] = s.Comment
+	}
+	return model
+}
+
+// openAPIInput returns the schema of the data creating or updating a record. Creates
+// require the fields without a default; updates set any of them.
+func openAPIInput(s *schema.Schema, create bool) map[string]any {
+	properties := map[string]any{}
+	required := []string{}
+	for _, f := range s.Fields {
+		properties[f.Name] = openAPIFieldType(f)
+		if create && !f.Nullable && !f.AutoIncrement && !f.UpdatedAt && f.Default == nil {
+			required = append(required, f.Name)
+		}
+	}
+	input := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		input["required"] = required
+	}
+	return input
+}
+
+// openAPIResponse returns a response whose data is described by dataSchema
+func openAPIResponse(description string, dataSchema map[string]any) map[string]any {
+	properties := map[string]any{
+		"success": map[string]any{"type": "boolean"},
+		"data":    dataSchema,
+	}
+	if dataSchema["type"] == "array" {
+		properties["pagination"] = openAPIRef("Pagination")
+	}
+	return map[string]any{
+		"description": description,
+		"content": map[string]any{
+			"application/json": map[string]any{
+				"schema": map[string]any{"type": "object", "properties": properties},
+			},
+		},
+	}
+}
+
+// openAPIErrorResponse returns a response holding an error
+func openAPIErrorResponse(description string) map[string]any {
+	return map[string]any{
+		"description": description,
+		"content": map[string]any{
+			"application/json": map[string]any{"schema": openAPIRef("Error")},
+		},
+	}
+}
+
+// openAPIBody returns a request body holding data
+func openAPIBody(dataSchema map[string]any) map[string]any {
+	return map[string]any{
+		"required": true,
+		"content": map[string]any{
+			"application/json": map[string]any{
+				"schema": map[string]any{
+					"type":       "object",
+					"required":   []string{"data"},
+					"properties": map[string]any{"data": dataSchema},
+				},
+			},
+		},
+	}
+}
+
+// openAPIQueryParameter returns a query parameter
+func openAPIQueryParameter(name, description string, paramSchema map[string]any) map[string]any {
+	return map[string]any{"name": name, "in": "query", "description": description, "schema": paramSchema}
+}
+
+// openAPICollectionPath returns the operations listing and creating records of a model
+func openAPICollectionPath(s *schema.Schema) map[string]any {
+	text := map[string]any{"type": "string"}
+	return map[string]any{
+		"get": map[string]any{
+			"operationId": "list" + s.Name,
+			"summary":     "List " + s.Name + " records",
+			"tags":        []string{s.Name},
+			"parameters": []any{
+				openAPIQueryParameter("where", `Filter as JSON, such as {"age":{"gt":25}}`, text),
+				openAPIQueryParameter("sort", "Fields to sort by, descending when prefixed with -", text),
+				openAPIQueryParameter("select", "Fields to return, separated by commas", text),
+				openAPIQueryParameter("include", "Relations to load, separated by commas or as JSON", text),
+				openAPIQueryParameter("page", "Page to return, from 1", map[string]any{"type": "integer", "default": 1}),
+				openAPIQueryParameter("limit", "Records per page", map[string]any{"type": "integer", "default": 50, "maximum": 1000}),
+				openAPIQueryParameter("meta", "Report the total and the page in the metadata", map[string]any{"type": "boolean"}),
+			},
+			"responses": map[string]any{
+				"200": openAPIResponse("The records", map[string]any{"type": "array", "items": openAPIRef(s.Name)}),
+				"400": openAPIErrorResponse("Invalid query"),
+			},
+		},
+		"post": map[string]any{
+			"operationId": "create" + s.Name,
+			"summary":     "Create a " + s.Name + " record",
+			"tags":        []string{s.Name},
+			"requestBody": openAPIBody(openAPIRef(s.Name + "CreateInput")),
+			"responses": map[string]any{
+				"201": openAPIResponse("The created record", openAPIRef(s.Name)),
+				"400": openAPIErrorResponse("Invalid data"),
+			},
+		},
+	}
+}
+
+// openAPIRecordPath returns the operations on a record of a model selected by its key
+func openAPIRecordPath(s *schema.Schema) map[string]any {
+	idSchema := map[string]any{"type": "string"}
+	if primaryKey, err := s.GetPrimaryKey(); err == nil && len(s.CompositeKey) == 0 {
+		idSchema = openAPIScalarType(primaryKey.Type)
+	}
+	return map[string]any{
+		"parameters": []any{
+			map[string]any{"name": "id", "in": "path", "required": true, "schema": idSchema},
+		},
+		"get": map[string]any{
+			"operationId": "get" + s.Name,
+			"summary":     "Get a " + s.Name + " record",
+			"tags":        []string{s.Name},
+			"responses": map[string]any{
+				"200": openAPIResponse("The record", openAPIRef(s.Name)),
+				"404": openAPIErrorResponse("Record not found"),
+			},
+		},
+		"put": map[string]any{
+			"operationId": "update" + s.Name,
+			"summary":     "Update a " + s.Name + " record",
+			"tags":        []string{s.Name},
+			"requestBody": openAPIBody(openAPIRef(s.Name + "UpdateInput")),
+			"responses": map[string]any{
+				"200": openAPIResponse("The updated record", openAPIRef(s.Name)),
+				"404": openAPIErrorResponse("Record not found"),
+			},
+		},
+		"delete": map[string]any{
+			"operationId": "delete" + s.Name,
+			"summary":     "Delete a " + s.Name + " record",
+			"tags":        []string{s.Name},
+			"responses": map[string]any{
+				"200": openAPIResponse("The record was deleted", map[string]any{"type": "object"}),
+				"404": openAPIErrorResponse("Record not found"),
+			},
+		},
+	}
+}
+
+// openAPIBatchPath returns the operation creating several records of a model
+func openAPIBatchPath(s *schema.Schema) map[string]any {
+	return map[string]any{
+		"post": map[string]any{
+			"operationId": "createMany" + s.Name,
+			"summary":     "Create " + s.Name + " records in one transaction",
+			"tags":        []string{s.Name},
+			"requestBody": openAPIBody(map[string]any{"type": "array", "items": openAPIRef(s.Name + "CreateInput")}),
+			"responses": map[string]any{
+				"201": openAPIResponse("The number of created records", map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"created": map[string]any{"type": "integer"}},
+				}),
+				"400": openAPIErrorResponse("Invalid data"),
+			},
+		},
+	}
+}
@@ -0,0 +1,54 @@
+package codegen
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/rediwo/redi-orm/prisma"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateOpenAPI(t *testing.T) {
+	def, err := prisma.ParseDefinition(testSchema)
+	require.NoError(t, err)
+
+	files, err := GenerateOpenAPI(def, OpenAPIOptions{Title: "Blog"})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "openapi.json", files[0].Name)
+
+	var document map[string]any
+	require.NoError(t, json.Unmarshal(files[0].Content, &document))
+	assert.Equal(t, "3.0.3", document["openapi"])
+	assert.Equal(t, map[string]any{"title": "Blog", "version": "1.0.0"}, document["info"])
+
+	// Models, inputs and enums
+	schemas := document["components"].(map[string]any)["schemas"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string", "enum": []any{"USER", "ADMIN"}}, schemas["Role"])
+
+	user := schemas["User"].(map[string]any)["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "integer", "format": "int32"}, user["id"])
+	assert.Equal(t, map[string]any{"type": "string", "nullable": true}, user["name"])
+	assert.Equal(t, map[string]any{"$ref": "#/components/schemas/Role"}, user["role"])
+	assert.Equal(t, map[string]any{"type": "string", "format": "date-time"}, user["createdAt"])
+	assert.Equal(t, map[string]any{"type": "array", "items": map[string]any{"$ref": "#/components/schemas/Post"}}, user["posts"])
+
+	// Creates require the fields without a default
+	assert.Equal(t, []any{"email"}, schemas["UserCreateInput"].(map[string]any)["required"])
+	assert.Equal(t, []any{"title", "authorId"}, schemas["PostCreateInput"].(map[string]any)["required"])
+	assert.NotContains(t, schemas["UserUpdateInput"], "required")
+
+	// Paths of the REST API
+	paths := document["paths"].(map[string]any)
+	assert.Contains(t, paths["/api/User"], "get")
+	assert.Contains(t, paths["/api/User"], "post")
+	assert.Contains(t, paths["/api/User/{id}"], "put")
+	assert.Contains(t, paths["/api/User/{id}"], "delete")
+	assert.Contains(t, paths["/api/Post/batch"], "post")
+
+	create := paths["/api/User"].(map[string]any)["post"].(map[string]any)
+	assert.Contains(t, create["responses"], "201")
+	idParameter := paths["/api/User/{id}"].(map[string]any)["parameters"].([]any)[0].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "integer", "format": "int32"}, idParameter["schema"])
+}
@@ -0,0 +1,423 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/rediwo/redi-orm/prisma"
+	"github.com/rediwo/redi-orm/schema"
+)
+
+// TypeScriptOptions configures TypeScript declaration generation
+type TypeScriptOptions struct {
+	// FileName is the name of the generated declaration file (default: "redi-orm.d.ts")
+	FileName string
+}
+
+// GenerateTypeScript renders .d.ts declarations of the redi/orm module with typed models
+// for scripts executed by `redi-orm run`
+func GenerateTypeScript(def *prisma.Definition, opts TypeScriptOptions) ([]File, error) {
+	if opts.FileName == "" {
+		opts.FileName = "redi-orm.d.ts"
+	}
+
+	g := &tsGenerator{def: def}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// %s\n\n", generatedHeader)
+	buf.WriteString("declare module 'redi/orm' {\n")
+	buf.WriteString(tsRuntimeDeclarations)
+	g.enums(&buf)
+	for _, s := range sortedModels(def) {
+		g.model(&buf, s)
+	}
+	g.models(&buf)
+	buf.WriteString("}\n")
+
+	return []File{{Name: opts.FileName, Content: buf.Bytes()}}, nil
+}
+
+// tsGenerator holds state shared by the TypeScript writers
+type tsGenerator struct {
+	def *prisma.Definition
+}
+
+// enums writes a string literal union per enum
+func (g *tsGenerator) enums(buf *bytes.Buffer) {
+	for _, name := range sortedEnumNames(g.def) {
+		values := make([]string, len(g.def.Enums[name]))
+		for i, value := range g.def.Enums[name] {
+			values[i] = fmt.Sprintf("'%s'", value)
+		}
+		fmt.Fprintf(buf, "  export type %s = %s;\n\n", name, strings.Join(values, " | "))
+	}
+}
+
+// tsFieldType returns the TypeScript type of a field value
+func (g *tsGenerator) tsFieldType(f schema.Field) string {
+	if f.Enum != "" {
+		if f.Type == schema.FieldTypeStringArray {
+			return f.Enum + "[]"
+		}
+		return f.Enum
+	}
+
+	switch f.Type {
+	case schema.FieldTypeString, schema.FieldTypeObjectId, schema.FieldTypeDecimal, schema.FieldTypeDecimal128:
+		// Decimals are read as their exact text
+		return "string"
+	case schema.FieldTypeInt, schema.FieldTypeInt64, schema.FieldTypeFloat:
+		return "number"
+	case schema.FieldTypeBool:
+		return "boolean"
+	case schema.FieldTypeDateTime, schema.FieldTypeTimestamp:
+		return "Date"
+	case schema.FieldTypeDocument:
+		return "Record<string, any>"
+	case schema.FieldTypeArray:
+		return "any[]"
+	case schema.FieldTypeStringArray, schema.FieldTypeDecimalArray:
+		return "string[]"
+	case schema.FieldTypeIntArray, schema.FieldTypeInt64Array, schema.FieldTypeFloatArray:
+		return "number[]"
+	case schema.FieldTypeBoolArray:
+		return "boolean[]"
+	case schema.FieldTypeDateTimeArray:
+		return "Date[]"
+	default:
+		// JSON and binary values are passed through untyped
+		return "any"
+	}
+}
+
+// tsInputType widens a field type for inputs, where dates may also be ISO strings
+func (g *tsGenerator) tsInputType(f schema.Field) string {
+	tsType := g.tsFieldType(f)
+	switch tsType {
+	case "Date":
+		return "Date | string"
+	case "Date[]":
+		return "(Date | string)[]"
+	}
+	return tsType
+}
+
+// tsFilterType returns the where filter accepted for a field
+func (g *tsGenerator) tsFilterType(f schema.Field) string {
+	tsType := g.tsFieldType(f)
+	switch {
+	case strings.HasSuffix(tsType, "[]"):
+		return tsType
+	case f.Enum != "":
+		return fmt.Sprintf("%s | EnumFilter<%s>", f.Enum, f.Enum)
+	case tsType == "string":
+		return "string | StringFilter"
+	case tsType == "number":
+		return "number | NumberFilter"
+	case tsType == "boolean":
+		return "boolean | BoolFilter"
+	case tsType == "Date":
+		return "Date | string | DateTimeFilter"
+	default:
+		return "any"
+	}
+}
+
+// model writes the record type, inputs and delegate of a model
+func (g *tsGenerator) model(buf *bytes.Buffer, s *schema.Schema) {
+	name := s.Name
+	relationNames := sortedRelationNames(s)
+
+	// Record
+	fmt.Fprintf(buf, "  /** %s record */\n  export interface %s {\n", name, name)
+	for _, f := range s.Fields {
+		tsType := g.tsFieldType(f)
+		if f.Nullable {
+			tsType += " | null"
+		}
+		fmt.Fprintf(buf, "    %s: %s;\n", f.Name, tsType)
+	}
+	for _, relName := range relationNames {
+		rel := s.Relations[relName]
+		relType := rel.Model + " | null"
+		if isToMany(rel) {
+			relType = rel.Model + "[]"
+		}
+		fmt.Fprintf(buf, "    /** Present when included */\n    %s?: %s;\n", relName, relType)
+	}
+	buf.WriteString("  }\n\n")
+
+	// Scalar field names
+	fieldNames := make([]string, len(s.Fields))
+	for i, f := range s.Fields {
+		fieldNames[i] = fmt.Sprintf("'%s'", f.Name)
+	}
+	fmt.Fprintf(buf, "  export type %sScalarField = %s;\n\n", name, strings.Join(fieldNames, " | "))
+
+	// Where
+	fmt.Fprintf(buf, "  export interface %sWhereInput {\n", name)
+	fmt.Fprintf(buf, "    AND?: %sWhereInput | %sWhereInput[];\n    OR?: %sWhereInput[];\n    NOT?: %sWhereInput | %sWhereInput[];\n", name, name, name, name, name)
+	for _, f := range s.Fields {
+		filter := g.tsFilterType(f)
+		if f.Nullable {
+			filter += " | null"
+		}
+		fmt.Fprintf(buf, "    %s?: %s;\n", f.Name, filter)
+	}
+	for _, relName := range relationNames {
+		rel := s.Relations[relName]
+		if isToMany(rel) {
+			fmt.Fprintf(buf, "    %s?: ListRelationFilter<%sWhereInput>;\n", relName, rel.Model)
+		} else {
+			fmt.Fprintf(buf, "    %s?: %sWhereInput | null;\n", relName, rel.Model)
+		}
+	}
+	buf.WriteString("  }\n\n")
+
+	// Select, include and order
+	fmt.Fprintf(buf, "  export type %sSelect = { [K in %sScalarField]?: boolean }", name, name)
+	if len(relationNames) > 0 {
+		fmt.Fprintf(buf, " & %sInclude", name)
+	}
+	buf.WriteString(";\n\n")
+
+	fmt.Fprintf(buf, "  export interface %sInclude {\n", name)
+	for _, relName := range relationNames {
+		rel := s.Relations[relName]
+		fmt.Fprintf(buf, "    %s?: boolean | IncludeOptions<%sWhereInput, %sSelect, %sInclude, %sOrderByInput>;\n",
+			relName, rel.Model, rel.Model, rel.Model, rel.Model)
+	}
+	buf.WriteString("  }\n\n")
+
+	fmt.Fprintf(buf, "  export type %sOrderByInput = { [K in %sScalarField]?: SortOrder };\n\n", name, name)
+
+	// Create and update inputs
+	fmt.Fprintf(buf, "  export interface %sCreateInput {\n", name)
+	for _, f := range s.Fields {
+		optional := ""
+		if f.Nullable || f.AutoIncrement || f.Default != nil {
+			optional = "?"
+		}
+		tsType := g.tsInputType(f)
+		if f.Nullable {
+			tsType += " | null"
+		}
+		fmt.Fprintf(buf, "    %s%s: %s;\n", f.Name, optional, tsType)
+	}
+	for _, relName := range relationNames {
+		fmt.Fprintf(buf, "    %s?: NestedWrite;\n", relName)
+	}
+	buf.WriteString("  }\n\n")
+
+	fmt.Fprintf(buf, "  export interface %sUpdateInput {\n", name)
+	for _, f := range s.Fields {
+		tsType := g.tsInputType(f)
+		if f.Nullable {
+			tsType += " | null"
+		}
+		fmt.Fprintf(buf, "    %s?: %s;\n", f.Name, tsType)
+	}
+	for _, relName := range relationNames {
+		fmt.Fprintf(buf, "    %s?: NestedWrite;\n", relName)
+	}
+	buf.WriteString("  }\n\n")
+
+	// Args
+	fmt.Fprintf(buf, `  export interface %[1]sFindManyArgs {
+    where?: %[1]sWhereInput;
+    select?: %[1]sSelect;
+    include?: %[1]sInclude;
+    orderBy?: %[1]sOrderByInput | %[1]sOrderByInput[];
+    take?: number;
+    skip?: number;
+    distinct?: %[1]sScalarField[];
+  }
+
+  export interface %[1]sAggregateArgs {
+    where?: %[1]sWhereInput;
+    orderBy?: %[1]sOrderByInput | %[1]sOrderByInput[];
+    take?: number;
+    skip?: number;
+    _count?: true | { [K in %[1]sScalarField | '_all']?: boolean };
+    _avg?: { [K in %[1]sScalarField]?: boolean };
+    _sum?: { [K in %[1]sScalarField]?: boolean };
+    _min?: { [K in %[1]sScalarField]?: boolean };
+    _max?: { [K in %[1]sScalarField]?: boolean };
+  }
+
+  export interface %[1]sGroupByArgs extends %[1]sAggregateArgs {
+    by: %[1]sScalarField[];
+    having?: Record<string, any>;
+  }
+
+`, name)
+
+	// Delegate
+	fmt.Fprintf(buf, `  /** Operations available on db.models.%[1]s */
+  export interface %[1]sDelegate {
+    create(args: { data: %[1]sCreateInput; select?: %[1]sSelect; include?: %[1]sInclude }): Promise<%[1]s>;
+    createMany(args: { data: %[1]sCreateInput[]; skipDuplicates?: boolean }): Promise<BatchPayload>;
+    createManyAndReturn(args: { data: %[1]sCreateInput[]; skipDuplicates?: boolean; select?: %[1]sSelect }): Promise<%[1]s[]>;
+    findUnique(args: { where: %[1]sWhereInput; select?: %[1]sSelect; include?: %[1]sInclude }): Promise<%[1]s | null>;
+    findFirst(args?: %[1]sFindManyArgs): Promise<%[1]s | null>;
+    findMany(args?: %[1]sFindManyArgs): Promise<%[1]s[]>;
+    count(args?: { where?: %[1]sWhereInput }): Promise<number>;
+    aggregate(args: %[1]sAggregateArgs): Promise<Record<string, any>>;
+    groupBy(args: %[1]sGroupByArgs): Promise<Record<string, any>[]>;
+    update(args: { where: %[1]sWhereInput; data: %[1]sUpdateInput; select?: %[1]sSelect; include?: %[1]sInclude }): Promise<%[1]s>;
+    updateMany(args: { where?: %[1]sWhereInput; data: %[1]sUpdateInput }): Promise<BatchPayload>;
+    updateManyAndReturn(args: { where?: %[1]sWhereInput; data: %[1]sUpdateInput; select?: %[1]sSelect }): Promise<%[1]s[]>;
+    upsert(args: { where: %[1]sWhereInput; create: %[1]sCreateInput; update: %[1]sUpdateInput; select?: %[1]sSelect; include?: %[1]sInclude }): Promise<%[1]s>;
+    delete(args: { where: %[1]sWhereInput; select?: %[1]sSelect; include?: %[1]sInclude }): Promise<%[1]s>;
+    deleteMany(args?: { where?: %[1]sWhereInput }): Promise<BatchPayload>;
+  }
+
+`, name)
+}
+
+// models writes the typed models map and change events
+func (g *tsGenerator) models(buf *bytes.Buffer) {
+	models := sortedModels(g.def)
+
+	buf.WriteString("  export interface Models {\n")
+	for _, s := range models {
+		fmt.Fprintf(buf, "    %s: %sDelegate;\n", s.Name, s.Name)
+	}
+	buf.WriteString("  }\n\n")
+
+	names := make([]string, len(models))
+	for i, s := range models {
+		names[i] = fmt.Sprintf("'%s'", s.Name)
+	}
+	fmt.Fprintf(buf, "  export type ModelName = %s;\n", strings.Join(names, " | "))
+}
+
+// tsRuntimeDeclarations describes the schema independent part of the redi/orm module
+const tsRuntimeDeclarations = `  export type SortOrder = 'asc' | 'desc';
+
+  export interface StringFilter {
+    equals?: string | null;
+    not?: string | StringFilter | null;
+    in?: string[];
+    notIn?: string[];
+    contains?: string;
+    startsWith?: string;
+    endsWith?: string;
+    lt?: string;
+    lte?: string;
+    gt?: string;
+    gte?: string;
+  }
+
+  export interface NumberFilter {
+    equals?: number | null;
+    not?: number | NumberFilter | null;
+    in?: number[];
+    notIn?: number[];
+    lt?: number;
+    lte?: number;
+    gt?: number;
+    gte?: number;
+  }
+
+  export interface BoolFilter {
+    equals?: boolean | null;
+    not?: boolean | BoolFilter | null;
+  }
+
+  export interface DateTimeFilter {
+    equals?: Date | string | null;
+    not?: Date | string | DateTimeFilter | null;
+    in?: (Date | string)[];
+    notIn?: (Date | string)[];
+    lt?: Date | string;
+    lte?: Date | string;
+    gt?: Date | string;
+    gte?: Date | string;
+  }
+
+  export interface EnumFilter<T> {
+    equals?: T | null;
+    not?: T | EnumFilter<T> | null;
+    in?: T[];
+    notIn?: T[];
+  }
+
+  export interface ListRelationFilter<W> {
+    some?: W;
+    every?: W;
+    none?: W;
+  }
+
+  export interface IncludeOptions<W, S, I, O> {
+    where?: W;
+    select?: S;
+    include?: I;
+    orderBy?: O | O[];
+    take?: number;
+    skip?: number;
+  }
+
+  /** Nested relation writes such as create, connect or disconnect */
+  export type NestedWrite = Record<string, any>;
+
+  export interface BatchPayload {
+    count: number;
+  }
+
+  export interface ExecResult {
+    rowsAffected: number;
+  }
+
+  export interface ChangeEvent {
+    model: string;
+    operation: 'create' | 'update' | 'delete';
+    data: Record<string, any>;
+    timestamp: string;
+  }
+
+  export interface Subscription {
+    close(): void;
+  }
+
+  export interface Logger {
+    levels: { NONE: number; ERROR: number; WARN: number; INFO: number; DEBUG: number };
+    setLevel(level: number | string): void;
+    setOutput(output: string): void;
+    debug(message: string, ...args: any[]): void;
+    info(message: string, ...args: any[]): void;
+    warn(message: string, ...args: any[]): void;
+    error(message: string, ...args: any[]): void;
+  }
+
+  export interface Transaction {
+    models: Models;
+    transaction<T>(fn: (tx: Transaction) => Promise<T>): Promise<T>;
+  }
+
+  export interface Database {
+    models: Models;
+    driverType: string;
+    connect(): Promise<void>;
+    close(): Promise<void>;
+    loadSchema(schema: string): Promise<void>;
+    loadSchemaFrom(path: string): Promise<void>;
+    syncSchemas(): Promise<void>;
+    ping(): Promise<void>;
+    createModel(modelName: ModelName): Promise<void>;
+    dropModel(modelName: ModelName): Promise<void>;
+    getModels(): ModelName[];
+    queryRaw<T = Record<string, any>>(sql: string, ...args: any[]): Promise<T[]>;
+    executeRaw(sql: string, ...args: any[]): Promise<ExecResult>;
+    transaction<T>(fn: (tx: Transaction) => Promise<T>): Promise<T>;
+    watch(callback: (event: ChangeEvent) => void): Promise<Subscription>;
+    watch(models: ModelName[], callback: (event: ChangeEvent) => void): Promise<Subscription>;
+    setLogger(logger: Logger): void;
+    getLogger(): Logger | null;
+  }
+
+  export function fromUri(uri: string): Database;
+  export function createLogger(name?: string): Logger;
+  export const LogLevel: { NONE: number; ERROR: number; WARN: number; INFO: number; DEBUG: number };
+
+`
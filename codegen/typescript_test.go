@@ -0,0 +1,44 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rediwo/redi-orm/prisma"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateTypeScript(t *testing.T) {
+	def, err := prisma.ParseDefinition(testSchema)
+	require.NoError(t, err)
+
+	files, err := GenerateTypeScript(def, TypeScriptOptions{})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "redi-orm.d.ts", files[0].Name)
+
+	content := string(files[0].Content)
+	assert.True(t, strings.HasPrefix(content, "// "+generatedHeader))
+	assert.Contains(t, content, "declare module 'redi/orm' {")
+	assert.Contains(t, content, "export function fromUri(uri: string): Database;")
+
+	// Enums and records
+	assert.Contains(t, content, "export type Role = 'USER' | 'ADMIN';")
+	assert.Contains(t, content, "    name: string | null;\n")
+	assert.Contains(t, content, "    role: Role;\n")
+	assert.Contains(t, content, "    createdAt: Date;\n")
+	assert.Contains(t, content, "    posts?: Post[];\n")
+	assert.Contains(t, content, "    author?: User | null;\n")
+
+	// Inputs mark generated and optional fields as optional
+	assert.Contains(t, content, "export interface UserCreateInput {\n    id?: number;\n    email: string;\n")
+	assert.Contains(t, content, "    posts?: ListRelationFilter<PostWhereInput>;\n")
+
+	// Delegates and models map
+	assert.Contains(t, content, "findMany(args?: UserFindManyArgs): Promise<User[]>;")
+	assert.Contains(t, content, "export interface Models {\n    Post: PostDelegate;\n    User: UserDelegate;\n  }")
+
+	// Braces must balance for the declaration to be valid
+	assert.Equal(t, strings.Count(content, "{"), strings.Count(content, "}"))
+}
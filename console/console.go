@@ -0,0 +1,422 @@
+package console
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	js "github.com/dop251/goja"
+	"github.com/dop251/goja_nodejs/eventloop"
+	"github.com/dop251/goja_nodejs/require"
+	"github.com/rediwo/redi-orm/database"
+	_ "github.com/rediwo/redi-orm/modules/orm" // Provides require('redi/orm')
+	"github.com/rediwo/redi-orm/sql"
+	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi/filesystem"
+	"github.com/rediwo/redi/handlers"
+	_ "github.com/rediwo/redi/modules/console" // Provides console.log
+)
+
+// MaxHistory is the number of entries kept in the history file
+const MaxHistory = 1000
+
+const (
+	prompt             = "redi-orm> "
+	continuationPrompt = "...> "
+)
+
+const help = `Enter JavaScript or SQL. Multi-line input continues until brackets are closed.
+
+JavaScript runs with the runtime of redi-orm run. The database is db, its models are
+models and each model is also a global unless the name is taken, e.g.
+  User.findMany({ where: { age: { gte: 18 } }, take: 10 })
+Promises are awaited and their result printed, so await is optional.
+
+Lines starting with SELECT, INSERT, UPDATE or DELETE run as SQL.
+
+Commands:
+  .sql <statement>  Run any SQL statement, such as CREATE INDEX or PRAGMA
+  .models           List the models and their tables
+  .history          List the entered commands
+  .help             Show this help
+  .exit             Exit (or Ctrl-D)
+`
+
+// connectionCount numbers the connections of consoles, which must have unique names
+var connectionCount atomic.Int64
+
+// Options configures a console
+type Options struct {
+	HistoryFile string // File the entered commands are kept in between sessions, none by default
+	Interactive bool   // Print a banner and prompts, for terminals
+	BasePath    string // Directory require() resolves relative paths from, the working directory by default
+}
+
+// Console evaluates JavaScript with the ORM runtime and SQL against a database, and prints
+// the results as tables
+type Console struct {
+	db         types.Database
+	opts       Options
+	connection string
+	loop       *eventloop.EventLoop
+	history    []string
+}
+
+// New starts a console for db. The console takes ownership of db: Close closes it.
+func New(db types.Database, opts Options) (*Console, error) {
+	if opts.BasePath == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		opts.BasePath = wd
+	}
+
+	c := &Console{
+		db:         db,
+		opts:       opts,
+		connection: fmt.Sprintf("console-%d", connectionCount.Add(1)),
+		loop:       eventloop.NewEventLoop(),
+	}
+	if err := database.RegisterDatabase(c.connection, db); err != nil {
+		return nil, err
+	}
+	c.loop.Start()
+
+	// Set up the runtime like redi-orm run, with the database and its models as globals
+	errs := make(chan error, 1)
+	c.loop.RunOnLoop(func(vm *js.Runtime) {
+		manager := handlers.NewVMManager(filesystem.NewOSFileSystem("/"), "console")
+		if _, err := manager.SetupRegistry(c.loop, vm, opts.BasePath); err != nil {
+			errs <- err
+			return
+		}
+		vm.Set("console", require.Require(vm, "console"))
+		if _, err := vm.RunString(fmt.Sprintf(`var db = require('redi/orm').fromConnection(%q); var models = db.models;`, c.connection)); err != nil {
+			errs <- err
+			return
+		}
+		for _, name := range db.GetModels() {
+			if value := vm.Get(name); value == nil || js.IsUndefined(value) {
+				vm.Set(name, vm.Get("models").ToObject(vm).Get(name))
+			}
+		}
+		errs <- nil
+	})
+	if err := <-errs; err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to set up the JavaScript runtime: %w", err)
+	}
+
+	if opts.HistoryFile != "" {
+		if content, err := os.ReadFile(opts.HistoryFile); err == nil {
+			for _, line := range strings.Split(string(content), "\n") {
+				if entry, err := strconv.Unquote(line); err == nil && entry != "" {
+					c.history = append(c.history, entry)
+				}
+			}
+			if len(c.history) > MaxHistory {
+				c.history = c.history[len(c.history)-MaxHistory:]
+			}
+		}
+	}
+	return c, nil
+}
+
+// Close stops the runtime and closes the database
+func (c *Console) Close() error {
+	c.loop.Stop()
+	return database.Unregister(c.connection)
+}
+
+// History returns the entered commands, oldest first, including those of previous sessions
+// kept in the history file
+func (c *Console) History() []string {
+	return slices.Clone(c.history)
+}
+
+// Run reads entries from in until its end or .exit, and writes their results to out. Errors
+// of entries are printed and do not stop the console.
+func (c *Console) Run(ctx context.Context, in io.Reader, out io.Writer) error {
+	if c.opts.Interactive {
+		fmt.Fprintf(out, "Connected to %s with %d models. Enter .help for help.\n", c.db.GetDriverType(), len(c.db.GetModels()))
+	}
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	var entry strings.Builder
+	for {
+		if c.opts.Interactive {
+			if entry.Len() == 0 {
+				fmt.Fprint(out, prompt)
+			} else {
+				fmt.Fprint(out, continuationPrompt)
+			}
+		}
+		if !scanner.Scan() {
+			if c.opts.Interactive {
+				fmt.Fprintln(out)
+			}
+			return scanner.Err()
+		}
+		if entry.Len() > 0 {
+			entry.WriteByte('\n')
+		}
+		entry.WriteString(scanner.Text())
+		input := strings.TrimSpace(entry.String())
+		if input != "" && !strings.HasPrefix(input, ".") && !balanced(input) {
+			continue
+		}
+		entry.Reset()
+		if input == "" {
+			continue
+		}
+
+		if input == ".exit" || input == ".quit" {
+			return nil
+		}
+		c.addHistory(input)
+		if err := c.Eval(ctx, input, out); err != nil {
+			fmt.Fprintf(out, "Error: %v\n", err)
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// Eval evaluates an entry: a command, SQL or JavaScript
+func (c *Console) Eval(ctx context.Context, input string, out io.Writer) error {
+	command, argument, _ := strings.Cut(input, " ")
+	switch command {
+	case ".help":
+		fmt.Fprint(out, help)
+		return nil
+	case ".models":
+		return c.printModels(out)
+	case ".history":
+		for i, entry := range c.history {
+			fmt.Fprintf(out, "%5d  %s\n", i+1, strings.ReplaceAll(entry, "\n", "\n       "))
+		}
+		return nil
+	case ".sql":
+		return c.evalSQL(ctx, strings.TrimSpace(argument), out)
+	}
+	if strings.HasPrefix(command, ".") {
+		return fmt.Errorf("unknown command %s, enter .help for help", command)
+	}
+	if sql.DetectSQL(input) {
+		return c.evalSQL(ctx, input, out)
+	}
+	return c.evalJS(input, out)
+}
+
+// addHistory records an entry, and saves the history file
+func (c *Console) addHistory(entry string) {
+	if len(c.history) > 0 && c.history[len(c.history)-1] == entry {
+		return
+	}
+	c.history = append(c.history, entry)
+	if len(c.history) > MaxHistory {
+		c.history = c.history[1:]
+	}
+	if c.opts.HistoryFile == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(c.opts.HistoryFile), 0o755); err != nil {
+		return
+	}
+	// Entries are kept one per line, quoted so that multi-line entries fit
+	var content strings.Builder
+	for _, entry := range c.history {
+		content.WriteString(strconv.Quote(entry))
+		content.WriteByte('\n')
+	}
+	os.WriteFile(c.opts.HistoryFile, []byte(content.String()), 0o600)
+}
+
+// printModels lists the models of the database
+func (c *Console) printModels(out io.Writer) error {
+	models := c.db.GetModels()
+	rows := make([][]any, 0, len(models))
+	for _, name := range models {
+		sch, err := c.db.GetSchema(name)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, []any{name, sch.TableName, len(sch.Fields)})
+	}
+	fmt.Fprint(out, FormatTable([]string{"model", "table", "fields"}, rows))
+	return nil
+}
+
+// evalSQL runs a statement, printing the rows of queries and the number of rows affected by
+// other statements
+func (c *Console) evalSQL(ctx context.Context, statement string, out io.Writer) error {
+	if statement == "" {
+		return fmt.Errorf("usage: .sql <statement>")
+	}
+	if !returnsRows(statement) {
+		result, err := c.db.Raw(statement).Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if sql.DetectSQL(statement) {
+			fmt.Fprintf(out, "%d rows affected\n", result.RowsAffected)
+		} else {
+			fmt.Fprintln(out, "OK")
+		}
+		return nil
+	}
+
+	// Rows keep the order of their columns, except on MongoDB which translates SQL
+	if c.db.GetDriverType() != string(types.DriverMongoDB) {
+		rows, err := c.db.Query(statement)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		columns, err := rows.Columns()
+		if err != nil {
+			return err
+		}
+		var values [][]any
+		for rows.Next() {
+			row := make([]any, len(columns))
+			pointers := make([]any, len(columns))
+			for i := range row {
+				pointers[i] = &row[i]
+			}
+			if err := rows.Scan(pointers...); err != nil {
+				return err
+			}
+			for i, value := range row {
+				if data, ok := value.([]byte); ok {
+					row[i] = string(data)
+				}
+			}
+			values = append(values, row)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		fmt.Fprint(out, FormatTable(columns, values))
+		return nil
+	}
+
+	var records []map[string]any
+	if err := c.db.Raw(statement).Find(ctx, &records); err != nil {
+		return err
+	}
+	fmt.Fprint(out, FormatRecords(records))
+	return nil
+}
+
+// returnsRows reports whether a statement is a query
+func returnsRows(statement string) bool {
+	keyword, _, _ := strings.Cut(strings.TrimLeft(statement, "( \t\n"), " ")
+	switch strings.ToUpper(strings.TrimSpace(keyword)) {
+	case "SELECT", "WITH", "SHOW", "DESCRIBE", "DESC", "EXPLAIN", "PRAGMA", "VALUES", "TABLE":
+		return true
+	}
+	return false
+}
+
+// evalJS runs JavaScript, waiting for the result of promises
+func (c *Console) evalJS(code string, out io.Writer) error {
+	// Promises are awaited anyway, and top-level await is not supported by scripts
+	if rest, ok := strings.CutPrefix(code, "await "); ok {
+		code = rest
+	}
+
+	type result struct {
+		value any
+		err   error
+	}
+	done := make(chan result, 1)
+	c.loop.RunOnLoop(func(vm *js.Runtime) {
+		value, err := vm.RunString(code)
+		if err != nil {
+			done <- result{err: err}
+			return
+		}
+		if _, ok := value.Export().(*js.Promise); !ok {
+			done <- result{value: exportValue(value)}
+			return
+		}
+		then, _ := js.AssertFunction(value.ToObject(vm).Get("then"))
+		onFulfilled := vm.ToValue(func(call js.FunctionCall) js.Value {
+			done <- result{value: exportValue(call.Argument(0))}
+			return js.Undefined()
+		})
+		onRejected := vm.ToValue(func(call js.FunctionCall) js.Value {
+			done <- result{err: fmt.Errorf("%s", call.Argument(0).String())}
+			return js.Undefined()
+		})
+		if _, err := then(value, onFulfilled, onRejected); err != nil {
+			done <- result{err: err}
+		}
+	})
+
+	r := <-done
+	if r.err != nil {
+		return r.err
+	}
+	fmt.Fprint(out, FormatValue(r.value))
+	return nil
+}
+
+// undefined is the exported value of undefined, which prints nothing
+type undefined struct{}
+
+// exportValue converts a JavaScript value to Go on the loop
+func exportValue(value js.Value) any {
+	if value == nil || js.IsUndefined(value) {
+		return undefined{}
+	}
+	return value.Export()
+}
+
+// balanced reports whether the brackets of code outside strings and comments are closed
+func balanced(code string) bool {
+	depth := 0
+	var quote rune
+	escaped := false
+	runes := []rune(code)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if quote != 0 {
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\':
+				escaped = true
+			case r == quote:
+				quote = 0
+			}
+			continue
+		}
+		switch r {
+		case '\'', '"', '`':
+			quote = r
+		case '/':
+			if i+1 < len(runes) && runes[i+1] == '/' {
+				for i < len(runes) && runes[i] != '\n' {
+					i++
+				}
+			}
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		}
+	}
+	return depth <= 0 && quote == 0
+}
@@ -0,0 +1,145 @@
+package console
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rediwo/redi-orm/database"
+	_ "github.com/rediwo/redi-orm/drivers/sqlite"
+)
+
+func newTestConsole(t *testing.T, opts Options) *Console {
+	t.Helper()
+	ctx := context.Background()
+	db, err := database.NewFromURI("sqlite://:memory:")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	if err := db.Connect(ctx); err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	if err := db.LoadSchema(ctx, `
+model User {
+  id    Int    @id @default(autoincrement())
+  name  String
+  email String @unique
+}
+`); err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	if err := db.SyncSchemas(ctx); err != nil {
+		t.Fatalf("Failed to sync schemas: %v", err)
+	}
+
+	c, err := New(db, opts)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestConsoleRun(t *testing.T) {
+	historyFile := filepath.Join(t.TempDir(), "history")
+	c := newTestConsole(t, Options{HistoryFile: historyFile})
+
+	input := `User.create({ data: { name: "Alice", email: "alice@example.com" } })
+await models.User.create({
+  data: { name: "Bob", email: "bob@example.com" }
+})
+User.findMany({ orderBy: { id: "asc" } })
+SELECT name, email FROM users WHERE name = 'Bob'
+UPDATE users SET name = 'Robert' WHERE id = 2
+.sql CREATE INDEX idx_users_name ON users (name)
+User.count()
+missing()
+.models
+.exit
+User.count()
+`
+	var out bytes.Buffer
+	if err := c.Run(context.Background(), strings.NewReader(input), &out); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	output := out.String()
+
+	for _, expected := range []string{
+		" id | email             | name\n----+-------------------+-------\n 1  | alice@example.com | Alice\n 2  | bob@example.com   | Bob\n(2 rows)\n",
+		" name | email\n------+-----------------\n Bob  | bob@example.com\n(1 row)\n",
+		"1 rows affected\n",
+		"OK\n",
+		"\n2\n",
+		"Error: ReferenceError: missing is not defined",
+		" User  | users | 3",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+	if strings.Count(output, "\n2\n") != 1 {
+		t.Errorf("Expected input after .exit to be ignored, got:\n%s", output)
+	}
+
+	// Entries are kept in the history file, multi-line ones included
+	history := c.History()
+	if len(history) != 9 || !strings.HasPrefix(history[1], "await models.User.create({\n") {
+		t.Errorf("Expected 9 history entries, got %q", history)
+	}
+	content, err := os.ReadFile(historyFile)
+	if err != nil {
+		t.Fatalf("Failed to read history file: %v", err)
+	}
+	if !strings.Contains(string(content), `"User.findMany({ orderBy: { id: \"asc\" } })"`+"\n") {
+		t.Errorf("Expected the history file to contain the entries, got:\n%s", content)
+	}
+
+	// The history of previous sessions is loaded
+	reopened := newTestConsole(t, Options{HistoryFile: historyFile})
+	if got := reopened.History(); len(got) != len(history) || got[1] != history[1] {
+		t.Errorf("Expected the history of the previous session, got %q", got)
+	}
+}
+
+func TestBalanced(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected bool
+	}{
+		{`User.findMany()`, true},
+		{`User.findMany({`, false},
+		{`User.findMany({ where: { name: "}" } })`, true},
+		{`[1, 2`, false},
+		{"`multi\nline`", true},
+		{"`multi", false},
+		{`x // (`, true},
+	}
+	for _, tt := range tests {
+		if got := balanced(tt.code); got != tt.expected {
+			t.Errorf("balanced(%q) = %v, expected %v", tt.code, got, tt.expected)
+		}
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		value    any
+		expected string
+	}{
+		{undefined{}, ""},
+		{"text", "text\n"},
+		{int64(42), "42\n"},
+		{nil, "null\n"},
+		{[]any{1, 2}, "[\n  1,\n  2\n]\n"},
+		{map[string]any{"name": "Ann\nLee", "id": 1, "tags": []any{"a"}}, " id | name     | tags\n----+----------+-------\n 1  | Ann\\nLee | [\"a\"]\n(1 row)\n"},
+		{[]any{}, "[]\n"},
+	}
+	for _, tt := range tests {
+		if got := FormatValue(tt.value); got != tt.expected {
+			t.Errorf("FormatValue(%v) = %q, expected %q", tt.value, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package console
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rediwo/redi-orm/utils"
+)
+
+// maxCellWidth is the number of characters after which cells are truncated
+const maxCellWidth = 60
+
+// FormatValue formats the result of an entry: records as a table, and other values as JSON
+func FormatValue(value any) string {
+	switch v := value.(type) {
+	case undefined:
+		return ""
+	case string:
+		return v + "\n"
+	case map[string]any:
+		return FormatRecords([]map[string]any{v})
+	case []map[string]any:
+		return FormatRecords(v)
+	case []any:
+		records := make([]map[string]any, 0, len(v))
+		for _, item := range v {
+			record, ok := item.(map[string]any)
+			if !ok {
+				break
+			}
+			records = append(records, record)
+		}
+		if len(v) > 0 && len(records) == len(v) {
+			return FormatRecords(records)
+		}
+	}
+
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Sprintln(value)
+	}
+	return string(data) + "\n"
+}
+
+// FormatRecords formats records as a table whose columns are their keys, id first
+func FormatRecords(records []map[string]any) string {
+	var columns []string
+	for _, record := range records {
+		for key := range record {
+			if !slices.Contains(columns, key) {
+				columns = append(columns, key)
+			}
+		}
+	}
+	slices.SortFunc(columns, func(a, b string) int {
+		switch {
+		case a == b:
+			return 0
+		case a == "id":
+			return -1
+		case b == "id":
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+
+	rows := make([][]any, len(records))
+	for i, record := range records {
+		rows[i] = make([]any, len(columns))
+		for j, column := range columns {
+			rows[i][j] = record[column]
+		}
+	}
+	return FormatTable(columns, rows)
+}
+
+// FormatTable formats rows as a table with a header and a row count, like psql
+func FormatTable(columns []string, rows [][]any) string {
+	cells := make([][]string, len(rows))
+	widths := make([]int, len(columns))
+	for i, column := range columns {
+		widths[i] = utf8.RuneCountInString(column)
+	}
+	for i, row := range rows {
+		cells[i] = make([]string, len(columns))
+		for j := range columns {
+			var value any
+			if j < len(row) {
+				value = row[j]
+			}
+			cells[i][j] = formatCell(value)
+			widths[j] = max(widths[j], utf8.RuneCountInString(cells[i][j]))
+		}
+	}
+
+	var b strings.Builder
+	writeRow := func(values []string) {
+		for i, value := range values {
+			if i > 0 {
+				b.WriteString("|")
+			}
+			b.WriteString(" ")
+			b.WriteString(value)
+			if i < len(values)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(value)+1))
+			}
+		}
+		b.WriteString("\n")
+	}
+	writeRow(columns)
+	for i, width := range widths {
+		if i > 0 {
+			b.WriteString("+")
+		}
+		b.WriteString(strings.Repeat("-", width+2))
+	}
+	b.WriteString("\n")
+	for _, row := range cells {
+		writeRow(row)
+	}
+	if len(rows) == 1 {
+		b.WriteString("(1 row)\n")
+	} else {
+		fmt.Fprintf(&b, "(%d rows)\n", len(rows))
+	}
+	return b.String()
+}
+
+// formatCell formats a value as a single line cell
+func formatCell(value any) string {
+	var s string
+	switch v := value.(type) {
+	case nil:
+		s = "null"
+	case string:
+		s = v
+	case []byte:
+		s = utils.EncodeBytes(v)
+	case time.Time:
+		s = v.Format(time.RFC3339Nano)
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		s = fmt.Sprint(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			s = fmt.Sprint(v)
+		} else {
+			s = string(data)
+		}
+	}
+
+	s = strings.NewReplacer("\n", "\\n", "\r", "\\r", "\t", "\\t").Replace(s)
+	if utf8.RuneCountInString(s) > maxCellWidth {
+		s = string([]rune(s)[:maxCellWidth-1]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// connections holds the named connections of the process
+var (
+	connections   = make(map[string]Database)
+	connectionsMu sync.RWMutex
+)
+
+// Register connects to the database at uri and registers it under name, so that servers
+// and the JavaScript runtime can select it by name
+func Register(name, uri string) (Database, error) {
+	db, err := NewFromURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Connect(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", name, err)
+	}
+	if err := RegisterDatabase(name, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// RegisterDatabase registers an existing database instance under name
+func RegisterDatabase(name string, db Database) error {
+	if name == "" {
+		return fmt.Errorf("connection name is required")
+	}
+
+	connectionsMu.Lock()
+	defer connectionsMu.Unlock()
+	if _, exists := connections[name]; exists {
+		return fmt.Errorf("connection %s is already registered", name)
+	}
+	connections[name] = db
+	return nil
+}
+
+// Connection returns the database registered under name
+func Connection(name string) (Database, error) {
+	connectionsMu.RLock()
+	defer connectionsMu.RUnlock()
+	db, exists := connections[name]
+	if !exists {
+		return nil, fmt.Errorf("connection %s is not registered", name)
+	}
+	return db, nil
+}
+
+// Connections returns the names of the registered connections in sorted order
+func Connections() []string {
+	connectionsMu.RLock()
+	defer connectionsMu.RUnlock()
+	names := make([]string, 0, len(connections))
+	for name := range connections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Unregister removes the connection registered under name and closes it
+func Unregister(name string) error {
+	connectionsMu.Lock()
+	db, exists := connections[name]
+	delete(connections, name)
+	connectionsMu.Unlock()
+
+	if !exists {
+		return fmt.Errorf("connection %s is not registered", name)
+	}
+	return db.Close()
+}
+
+type connectionKey struct{}
+
+// WithConnection returns a context that selects db for the operations run with it, such
+// as the resolvers of a GraphQL request
+func WithConnection(ctx context.Context, db Database) context.Context {
+	return context.WithValue(ctx, connectionKey{}, db)
+}
+
+// ConnectionFromContext returns the database selected by WithConnection, or nil
+func ConnectionFromContext(ctx context.Context) Database {
+	if ctx == nil {
+		return nil
+	}
+	db, _ := ctx.Value(connectionKey{}).(Database)
+	return db
+}
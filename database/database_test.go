@@ -3,8 +3,10 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	_ "github.com/rediwo/redi-orm/drivers/mysql"      // Import MySQL driver for tests
 	_ "github.com/rediwo/redi-orm/drivers/postgresql" // Import PostgreSQL driver for tests
@@ -41,6 +43,24 @@ func (c *mockCapabilities) NeedsTypeConversion() bool { return false }
 func (c *mockCapabilities) GetNullsOrderingSQL(direction types.Order, nullsFirst bool) string {
 	return ""
 }
+func (c *mockCapabilities) GetUpsertSQL(conflictColumns []string, updateColumns []string) string {
+	return ""
+}
+func (c *mockCapabilities) GetSkipDuplicatesSQL(insertColumns []string) string {
+	return ""
+}
+func (c *mockCapabilities) GetArrayAppendSQL(quotedColumn string) string {
+	return ""
+}
+func (c *mockCapabilities) GetArrayFilterSQL(operator string, quotedColumn string) string {
+	return ""
+}
+func (c *mockCapabilities) EncodeArrayValue(values []any) (any, error) {
+	return values, nil
+}
+func (c *mockCapabilities) DecodeArrayValue(value any) ([]any, error) {
+	return nil, nil
+}
 func (c *mockCapabilities) IsSystemIndex(indexName string) bool { return false }
 func (c *mockCapabilities) IsSystemTable(tableName string) bool { return false }
 func (c *mockCapabilities) GetDriverType() types.DriverType     { return "mock" }
@@ -49,10 +69,16 @@ func (c *mockCapabilities) GetSupportedSchemes() []string       { return []strin
 // NoSQL features
 func (c *mockCapabilities) IsNoSQL() bool                     { return false }
 func (c *mockCapabilities) SupportsTransactions() bool        { return true }
+func (c *mockCapabilities) SupportsSavepoints() bool          { return true }
+func (c *mockCapabilities) SupportsTransactionalDDL() bool    { return false }
 func (c *mockCapabilities) SupportsNestedDocuments() bool     { return false }
 func (c *mockCapabilities) SupportsArrayFields() bool         { return false }
 func (c *mockCapabilities) SupportsAggregationPipeline() bool { return false }
 func (c *mockCapabilities) SupportsForeignKeys() bool         { return true }
+func (c *mockCapabilities) SupportsUpsert() bool              { return true }
+func (c *mockCapabilities) SupportsJSONOperators() bool       { return false }
+func (c *mockCapabilities) SupportsFullText() bool            { return false }
+func (c *mockCapabilities) MaxParameters() int                { return 0 }
 
 func (m *mockDatabase) Connect(ctx context.Context) error {
 	m.connected = true
@@ -111,6 +137,18 @@ func (m *mockDatabase) GetModelSchema(modelName string) (*schema.Schema, error)
 	return nil, fmt.Errorf("schema not found")
 }
 
+func (m *mockDatabase) GetModelField(modelName, fieldName string) (*schema.Field, error) {
+	return nil, fmt.Errorf("schema not found")
+}
+
+func (m *mockDatabase) GetModelRelations(modelName string) ([]types.RelationInfo, error) {
+	return nil, fmt.Errorf("schema not found")
+}
+
+func (m *mockDatabase) ResolveRelation(modelName, relationName string) (types.RelationInfo, error) {
+	return types.RelationInfo{}, fmt.Errorf("schema not found")
+}
+
 func (m *mockDatabase) ResolveTableName(modelName string) (string, error) {
 	return "", nil
 }
@@ -363,6 +401,57 @@ func TestNewFromURI(t *testing.T) {
 	})
 }
 
+func TestStatementTimeout(t *testing.T) {
+	t.Run("URI parameter", func(t *testing.T) {
+		uri, timeout, err := extractStatementTimeout("postgresql://localhost/app?sslmode=disable&statementTimeout=5s")
+		require.NoError(t, err)
+		assert.Equal(t, "postgresql://localhost/app?sslmode=disable", uri)
+		assert.Equal(t, 5*time.Second, timeout)
+
+		uri, _, err = extractStatementTimeout("sqlite://:memory:?statementTimeout=250ms")
+		require.NoError(t, err)
+		assert.Equal(t, "sqlite://:memory:", uri)
+
+		_, _, err = extractStatementTimeout("sqlite://:memory:?statementTimeout=soon")
+		assert.Error(t, err)
+
+		db, err := NewFromURI("sqlite://:memory:?statementTimeout=5s")
+		require.NoError(t, err)
+		assert.Equal(t, 5*time.Second, db.(types.StatementTimeouter).GetStatementTimeout())
+	})
+
+	t.Run("Query timeouts", func(t *testing.T) {
+		db, err := NewFromURI("sqlite://:memory:")
+		require.NoError(t, err)
+		ctx := context.Background()
+		require.NoError(t, db.Connect(ctx))
+		defer db.Close()
+
+		require.NoError(t, db.RegisterSchema("Note", schema.New("Note").
+			AddField(schema.Field{Name: "id", Type: schema.FieldTypeInt, PrimaryKey: true, AutoIncrement: true}).
+			AddField(schema.Field{Name: "title", Type: schema.FieldTypeString})))
+		require.NoError(t, db.SyncSchemas(ctx))
+		_, err = db.Model("Note").Insert(map[string]any{"title": "first"}).Exec(ctx)
+		require.NoError(t, err)
+
+		var notes []map[string]any
+		err = db.Model("Note").Select().WithTimeout(time.Nanosecond).FindMany(ctx, &notes)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), "expected the query to time out, got %v", err)
+
+		// The default applies to queries without a timeout of their own
+		require.NoError(t, SetStatementTimeout(db, time.Nanosecond))
+		_, err = db.Model("Note").Count(ctx)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), "expected the default timeout, got %v", err)
+		err = db.Model("Note").Select().WithTimeout(time.Minute).FindMany(ctx, &notes)
+		assert.NoError(t, err)
+		assert.Len(t, notes, 1)
+	})
+
+	t.Run("Unsupported driver", func(t *testing.T) {
+		assert.Error(t, SetStatementTimeout(&mockDatabase{}, time.Second))
+	})
+}
+
 func TestDefaultDriversLoaded(t *testing.T) {
 	// This test verifies that default drivers are automatically loaded
 	// We'll try to create instances to verify they're registered
@@ -489,3 +578,70 @@ func TestIntegrationWithRealDatabase(t *testing.T) {
 		assert.Equal(t, "test", rows[0].Name)
 	})
 }
+
+func TestQueryWithArgs(t *testing.T) {
+	db, err := NewFromURI("sqlite://:memory:")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, db.Connect(ctx))
+	defer db.Close()
+
+	_, err = db.Raw(`CREATE TABLE "test" ("id" INTEGER PRIMARY KEY, "name" TEXT)`).Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.Raw(`INSERT INTO "test" ("name") VALUES ('a'), ('b'), ('c')`).Exec(ctx)
+	require.NoError(t, err)
+
+	// Values are bound as arguments, slices expanded into one per element
+	rows, err := QueryWithArgs(ctx, db, `SELECT "name" FROM "test" WHERE "name" IN (?) OR "name" = ? ORDER BY "name" LIMIT ?`,
+		[]string{"a", "x"}, "c; DROP TABLE test", 5)
+	require.NoError(t, err)
+	var names []string
+	for rows.Next() {
+		var name string
+		require.NoError(t, rows.Scan(&name))
+		names = append(names, name)
+	}
+	rows.Close()
+	assert.Equal(t, []string{"a"}, names)
+
+	// Statements carrying more than the generated query fail instead of running
+	for _, query := range []string{
+		`SELECT "name" FROM "test"; DROP TABLE "test"`,
+		`SELECT "name" FROM "test" -- WHERE "name" = ?`,
+		`SELECT "name" FROM "test" /* comment */`,
+		`SELECT "name" FROM "test" WHERE "name" = 'a`,
+	} {
+		_, err := QueryWithArgs(ctx, db, query)
+		assert.Error(t, err, query)
+	}
+	_, err = QueryWithArgs(ctx, db, `SELECT "name" FROM "test" WHERE "name" = ';--'`)
+	assert.NoError(t, err, "Quoted strings are left alone")
+}
+
+func TestConnectionRegistry(t *testing.T) {
+	db, err := Register("registry-test", "sqlite://:memory:")
+	require.NoError(t, err)
+	assert.NoError(t, db.Ping(context.Background()))
+
+	_, err = Register("registry-test", "sqlite://:memory:")
+	assert.Error(t, err, "names are registered once")
+
+	found, err := Connection("registry-test")
+	require.NoError(t, err)
+	assert.Same(t, db, found)
+	assert.Contains(t, Connections(), "registry-test")
+
+	ctx := WithConnection(context.Background(), db)
+	assert.Same(t, db, ConnectionFromContext(ctx))
+	assert.Nil(t, ConnectionFromContext(context.Background()))
+
+	require.NoError(t, Unregister("registry-test"))
+	_, err = Connection("registry-test")
+	assert.Error(t, err)
+	assert.Error(t, Unregister("registry-test"))
+
+	_, err = Register("bad-uri", "unknown://nowhere")
+	assert.Error(t, err)
+	assert.NotContains(t, Connections(), "bad-uri")
+}
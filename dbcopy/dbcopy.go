@@ -0,0 +1,266 @@
+package dbcopy
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/rediwo/redi-orm/export"
+	"github.com/rediwo/redi-orm/importer"
+	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
+)
+
+// Options configures a copy
+type Options struct {
+	Models      []string // Models to copy, all the models of the source by default
+	BatchSize   int      // Records read and inserted at a time, export.DefaultBatchSize by default
+	OnDuplicate string   // Records that exist in the target: importer.OnDuplicateError (default), OnDuplicateSkip or OnDuplicateUpsert by primary key
+
+	// Optional: called after each batch with the number of records of the model copied so far
+	Progress func(model string, copied int)
+}
+
+// Result counts the records copied by model
+type Result struct {
+	Models  []string       // Models in the order they were copied
+	Copied  map[string]int // Records written by model
+	Skipped map[string]int // Records that existed in the target by model, with OnDuplicateSkip
+}
+
+// Copy creates the models of from in to and copies their records. The schemas registered
+// with from that to does not have are registered with it, and the tables of to are synced.
+// Models are copied after the models their foreign keys reference, in batches, with values
+// converted to the types of their fields. Auto-increment sequences of the target continue
+// after the copied ids. Batches are not written in a transaction: a copy that fails leaves
+// the batches written before the error.
+func Copy(ctx context.Context, from, to types.Database, opts Options) (*Result, error) {
+	return copyDatabase(ctx, from, to, opts, nil)
+}
+
+// filter selects the records of a batch to write, after they are converted to the types of
+// their fields, and may rewrite them
+type filter func(sch *schema.Schema, records []map[string]any) []map[string]any
+
+// copyDatabase copies the records of from to to that keep selects, all of them when nil
+func copyDatabase(ctx context.Context, from, to types.Database, opts Options, keep filter) (*Result, error) {
+	schemas := make(map[string]*schema.Schema)
+	for _, name := range from.GetModels() {
+		sch, err := from.GetSchema(name)
+		if err != nil {
+			return nil, err
+		}
+		schemas[name] = sch
+		if _, err := to.GetSchema(name); err != nil {
+			if err := to.RegisterSchema(name, sch); err != nil {
+				return nil, fmt.Errorf("failed to register model %s with the target: %w", name, err)
+			}
+		}
+	}
+	if err := to.SyncSchemas(ctx); err != nil {
+		return nil, fmt.Errorf("failed to sync the schema of the target: %w", err)
+	}
+
+	models := Order(schemas)
+	if len(opts.Models) > 0 {
+		for _, name := range opts.Models {
+			if schemas[name] == nil {
+				return nil, fmt.Errorf("model %s not found in the source", name)
+			}
+		}
+		models = slices.DeleteFunc(models, func(name string) bool {
+			return !slices.Contains(opts.Models, name)
+		})
+	}
+	switch opts.OnDuplicate {
+	case "":
+		opts.OnDuplicate = importer.OnDuplicateError
+	case importer.OnDuplicateError, importer.OnDuplicateSkip, importer.OnDuplicateUpsert:
+	default:
+		return nil, fmt.Errorf("invalid duplicate strategy %q: must be %s, %s or %s", opts.OnDuplicate, importer.OnDuplicateError, importer.OnDuplicateSkip, importer.OnDuplicateUpsert)
+	}
+
+	result := &Result{
+		Models:  models,
+		Copied:  make(map[string]int),
+		Skipped: make(map[string]int),
+	}
+	for _, name := range models {
+		if err := copyModel(ctx, from, to, schemas[name], opts, keep, result); err != nil {
+			return result, fmt.Errorf("failed to copy %s: %w", name, err)
+		}
+	}
+	return result, nil
+}
+
+// copyModel copies the records of a model
+func copyModel(ctx context.Context, from, to types.Database, sch *schema.Schema, opts Options, keep filter, result *Result) error {
+	conflictFields := sch.CompositeKey
+	if pk, err := sch.GetPrimaryKey(); err == nil && len(conflictFields) == 0 {
+		conflictFields = []string{pk.Name}
+	}
+	if opts.OnDuplicate == importer.OnDuplicateUpsert && len(conflictFields) == 0 {
+		return fmt.Errorf("upserts require a primary key")
+	}
+
+	err := export.Batches(ctx, from, sch.Name, from.Model(sch.Name).Select(), opts.BatchSize, func(records []map[string]any) error {
+		for _, record := range records {
+			for _, field := range sch.Fields {
+				value, ok := record[field.Name]
+				if !ok {
+					continue
+				}
+				converted, err := Coerce(field, value)
+				if err != nil {
+					return fmt.Errorf("%s: %w", field.Name, err)
+				}
+				record[field.Name] = converted
+			}
+		}
+		if keep != nil {
+			records = keep(sch, records)
+		}
+		written, skipped, err := importer.Write(ctx, to, sch.Name, records, opts.OnDuplicate, conflictFields)
+		result.Copied[sch.Name] += written
+		result.Skipped[sch.Name] += skipped
+		if err != nil {
+			return err
+		}
+		if opts.Progress != nil {
+			opts.Progress(sch.Name, result.Copied[sch.Name])
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if syncer, ok := to.(types.SequenceSyncer); ok {
+		return syncer.SyncSequences(ctx, sch.Name)
+	}
+	return nil
+}
+
+// Order returns the names of schemas ordered so that models come after the models their
+// foreign keys reference. Models in reference cycles keep alphabetical order.
+func Order(schemas map[string]*schema.Schema) []string {
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	// Models referenced by the foreign keys of each model
+	references := make(map[string][]string)
+	for _, name := range names {
+		for _, relation := range foreignKeys(schemas, name) {
+			if relation.Model != name {
+				references[name] = append(references[name], relation.Model)
+			}
+		}
+	}
+
+	ordered := make([]string, 0, len(names))
+	done := make(map[string]bool)
+	for len(ordered) < len(names) {
+		progress := false
+		for _, name := range names {
+			if done[name] || slices.ContainsFunc(references[name], func(model string) bool { return !done[model] }) {
+				continue
+			}
+			ordered = append(ordered, name)
+			done[name] = true
+			progress = true
+		}
+		if !progress {
+			// A reference cycle: the first model of the cycle goes first
+			for _, name := range names {
+				if !done[name] {
+					ordered = append(ordered, name)
+					done[name] = true
+					break
+				}
+			}
+		}
+	}
+	return ordered
+}
+
+// foreignKeys returns the relations of a model whose foreign key is one of its fields, in the
+// order of their names
+func foreignKeys(schemas map[string]*schema.Schema, name string) []schema.Relation {
+	sch := schemas[name]
+	var relations []schema.Relation
+	for _, relationName := range slices.Sorted(maps.Keys(sch.Relations)) {
+		relation := sch.Relations[relationName]
+		if schemas[relation.Model] == nil {
+			continue
+		}
+		if relation.Type != schema.RelationManyToOne && relation.Type != schema.RelationOneToOne {
+			continue
+		}
+		// The foreign key of one-to-one relations is in one of the models
+		if sch.GetFieldByName(relation.ForeignKey) == nil ||
+			(relation.Type == schema.RelationOneToOne && relation.Model != name && schemas[relation.Model].GetFieldByName(relation.ForeignKey) != nil) {
+			continue
+		}
+		relations = append(relations, relation)
+	}
+	return relations
+}
+
+// Coerce converts a value read from one database to the type of its field, so that it can be
+// written to a database of another driver: SQLite returns booleans as integers and JSON as
+// text, for example.
+func Coerce(field schema.Field, value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch field.Type {
+	case schema.FieldTypeBool:
+		if _, ok := value.(bool); !ok {
+			return utils.ToBool(value), nil
+		}
+	case schema.FieldTypeInt, schema.FieldTypeInt64:
+		switch value.(type) {
+		case int64, int:
+		default:
+			return utils.ToInt64(value), nil
+		}
+	case schema.FieldTypeFloat:
+		if _, ok := value.(float64); !ok {
+			return utils.ToFloat64(value), nil
+		}
+	case schema.FieldTypeDecimal, schema.FieldTypeDecimal128:
+		return utils.ToDecimalString(value)
+	case schema.FieldTypeDateTime, schema.FieldTypeTimestamp:
+		return utils.ToTime(value)
+	case schema.FieldTypeString:
+		if data, ok := value.([]byte); ok {
+			return string(data), nil
+		}
+	case schema.FieldTypeBytes:
+		return utils.DecodeBytes(value)
+	case schema.FieldTypeJSON, schema.FieldTypeDocument, schema.FieldTypeArray,
+		schema.FieldTypeStringArray, schema.FieldTypeIntArray, schema.FieldTypeInt64Array, schema.FieldTypeFloatArray,
+		schema.FieldTypeBoolArray, schema.FieldTypeDecimalArray, schema.FieldTypeDateTimeArray:
+		// Stored as JSON text by some drivers
+		var text []byte
+		switch v := value.(type) {
+		case string:
+			text = []byte(v)
+		case []byte:
+			text = v
+		default:
+			return value, nil
+		}
+		var decoded any
+		if err := utils.UnmarshalJSON(text, &decoded); err != nil {
+			return value, nil
+		}
+		return decoded, nil
+	}
+	return value, nil
+}
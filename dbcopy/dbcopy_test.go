@@ -0,0 +1,271 @@
+package dbcopy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rediwo/redi-orm/database"
+	_ "github.com/rediwo/redi-orm/drivers/sqlite"
+	"github.com/rediwo/redi-orm/importer"
+	"github.com/rediwo/redi-orm/masking"
+	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/types"
+)
+
+const testSchema = `
+model Author {
+  id    Int    @id @default(autoincrement())
+  name  String
+  posts Post[]
+}
+
+model Post {
+  id        Int      @id @default(autoincrement())
+  title     String
+  published Boolean  @default(false)
+  createdAt DateTime @default(now())
+  authorId  Int
+  author    Author   @relation(fields: [authorId], references: [id])
+}
+`
+
+func newTestDatabase(t *testing.T, schemaContent string) database.Database {
+	t.Helper()
+	ctx := context.Background()
+	db, err := database.NewFromURI("sqlite://:memory:")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	if err := db.Connect(ctx); err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if schemaContent != "" {
+		if err := db.LoadSchema(ctx, schemaContent); err != nil {
+			t.Fatalf("Failed to load schema: %v", err)
+		}
+		if err := db.SyncSchemas(ctx); err != nil {
+			t.Fatalf("Failed to sync schemas: %v", err)
+		}
+	}
+	return db
+}
+
+func TestCopy(t *testing.T) {
+	ctx := context.Background()
+	from := newTestDatabase(t, testSchema)
+	for i, name := range []string{"Alice", "Bob", "Carol"} {
+		if _, err := from.Model("Author").Insert(map[string]any{"name": name}).Exec(ctx); err != nil {
+			t.Fatalf("Failed to insert author: %v", err)
+		}
+		if _, err := from.Model("Post").Insert(map[string]any{"title": "Post by " + name, "published": i%2 == 0, "authorId": i + 1}).Exec(ctx); err != nil {
+			t.Fatalf("Failed to insert post: %v", err)
+		}
+	}
+
+	to := newTestDatabase(t, "")
+	var progress []int
+	result, err := Copy(ctx, from, to, Options{
+		BatchSize: 2,
+		Progress: func(model string, copied int) {
+			if model == "Post" {
+				progress = append(progress, copied)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	if !slices.Equal(result.Models, []string{"Author", "Post"}) {
+		t.Errorf("Expected authors to be copied before posts, got %v", result.Models)
+	}
+	if result.Copied["Author"] != 3 || result.Copied["Post"] != 3 {
+		t.Errorf("Expected 3 authors and 3 posts copied, got %v", result.Copied)
+	}
+	if !slices.Equal(progress, []int{2, 3}) {
+		t.Errorf("Expected progress after each batch of 2 posts, got %v", progress)
+	}
+
+	var posts []map[string]any
+	if err := to.Model("Post").Select().OrderBy("id", types.ASC).FindMany(ctx, &posts); err != nil {
+		t.Fatalf("Failed to find posts: %v", err)
+	}
+	if len(posts) != 3 || posts[2]["title"] != "Post by Carol" || posts[2]["authorId"] != int64(3) {
+		t.Errorf("Expected the posts to be copied with their ids, got %v", posts)
+	}
+
+	// Copying again skips the records of the target
+	result, err = Copy(ctx, from, to, Options{OnDuplicate: importer.OnDuplicateSkip, Models: []string{"Author"}})
+	if err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	if result.Copied["Author"] != 0 || result.Skipped["Author"] != 3 || len(result.Models) != 1 {
+		t.Errorf("Expected the 3 authors to be skipped, got %+v", result)
+	}
+
+	if _, err := Copy(ctx, from, to, Options{}); err == nil {
+		t.Error("Expected an error for records that exist in the target")
+	}
+	if _, err := Copy(ctx, from, to, Options{Models: []string{"Comment"}}); err == nil {
+		t.Error("Expected an error for an unknown model")
+	}
+}
+
+func TestOrder(t *testing.T) {
+	db := newTestDatabase(t, `
+model User {
+  id      Int      @id @default(autoincrement())
+  profile Profile?
+  comments Comment[]
+  managerId Int?
+  manager User?  @relation("Management", fields: [managerId], references: [id])
+  reports User[] @relation("Management")
+}
+
+model Profile {
+  id     Int  @id @default(autoincrement())
+  userId Int  @unique
+  user   User @relation(fields: [userId], references: [id])
+}
+
+model Comment {
+  id     Int  @id @default(autoincrement())
+  userId Int
+  user   User @relation(fields: [userId], references: [id])
+}
+`)
+
+	schemas := make(map[string]*schema.Schema)
+	for _, name := range db.GetModels() {
+		sch, err := db.GetSchema(name)
+		if err != nil {
+			t.Fatalf("Failed to get schema: %v", err)
+		}
+		schemas[name] = sch
+	}
+	order := Order(schemas)
+	if !slices.Equal(order, []string{"User", "Comment", "Profile"}) {
+		t.Errorf("Expected users before the models that reference them, got %v", order)
+	}
+}
+
+func TestCoerce(t *testing.T) {
+	tests := []struct {
+		fieldType schema.FieldType
+		value     any
+		expected  any
+	}{
+		{schema.FieldTypeBool, int64(1), true},
+		{schema.FieldTypeBool, int64(0), false},
+		{schema.FieldTypeInt, "42", int64(42)},
+		{schema.FieldTypeFloat, int64(2), float64(2)},
+		{schema.FieldTypeString, []byte("text"), "text"},
+		{schema.FieldTypeDecimal, 1.5, "1.5"},
+		{schema.FieldTypeDateTime, "2024-01-02T03:04:05Z", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{schema.FieldTypeString, nil, nil},
+	}
+	for _, tt := range tests {
+		got, err := Coerce(schema.Field{Name: "field", Type: tt.fieldType}, tt.value)
+		if err != nil {
+			t.Errorf("Coerce(%s, %v) error = %v", tt.fieldType, tt.value, err)
+			continue
+		}
+		if expected, ok := tt.expected.(time.Time); ok {
+			if got, ok := got.(time.Time); !ok || !got.Equal(expected) {
+				t.Errorf("Coerce(%s, %v) = %v, want %v", tt.fieldType, tt.value, got, expected)
+			}
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("Coerce(%s, %v) = %v (%T), want %v", tt.fieldType, tt.value, got, got, tt.expected)
+		}
+	}
+
+	got, err := Coerce(schema.Field{Name: "tags", Type: schema.FieldTypeStringArray}, `["a","b"]`)
+	if err != nil {
+		t.Fatalf("Coerce() error = %v", err)
+	}
+	if tags, ok := got.([]any); !ok || len(tags) != 2 || tags[0] != "a" {
+		t.Errorf("Expected JSON text to be decoded, got %v", got)
+	}
+}
+
+func TestSample(t *testing.T) {
+	ctx := context.Background()
+	from := newTestDatabase(t, `
+model Author {
+  id       Int    @id @default(autoincrement())
+  email    String @unique
+  posts    Post[] @relation("Posts")
+  reviews  Post[] @relation("Reviews")
+}
+
+model Post {
+  id         Int     @id @default(autoincrement())
+  title      String
+  authorId   Int
+  author     Author  @relation("Posts", fields: [authorId], references: [id])
+  reviewerId Int?
+  reviewer   Author? @relation("Reviews", fields: [reviewerId], references: [id])
+}
+`)
+	for i := 1; i <= 100; i++ {
+		if _, err := from.Model("Author").Insert(map[string]any{"email": fmt.Sprintf("author%d@example.com", i)}).Exec(ctx); err != nil {
+			t.Fatalf("Failed to insert author: %v", err)
+		}
+	}
+	for i := 1; i <= 100; i++ {
+		post := map[string]any{"title": fmt.Sprintf("Post %d", i), "authorId": i, "reviewerId": 101 - i}
+		if _, err := from.Model("Post").Insert(post).Exec(ctx); err != nil {
+			t.Fatalf("Failed to insert post: %v", err)
+		}
+	}
+
+	to := newTestDatabase(t, "")
+	policy := &masking.Policy{Fields: map[string]masking.Strategy{"Author.email": masking.StrategyEmail}}
+	result, err := Sample(ctx, from, to, SampleOptions{Percent: 20, Seed: 1, Policy: policy})
+	if err != nil {
+		t.Fatalf("Sample() error = %v", err)
+	}
+	if copied := result.Copied["Author"]; copied == 0 || copied == 100 {
+		t.Errorf("Expected about 20 of the 100 authors, got %d", copied)
+	}
+
+	var authors []map[string]any
+	if err := to.Model("Author").Select().FindMany(ctx, &authors); err != nil {
+		t.Fatalf("Failed to find authors: %v", err)
+	}
+	ids := make(map[int64]bool)
+	for _, author := range authors {
+		ids[author["id"].(int64)] = true
+		email := author["email"].(string)
+		if !strings.HasSuffix(email, "@example.com") || strings.HasPrefix(email, "author") {
+			t.Errorf("Expected emails to be anonymized, got %s", email)
+		}
+	}
+
+	var posts []map[string]any
+	if err := to.Model("Post").Select().FindMany(ctx, &posts); err != nil {
+		t.Fatalf("Failed to find posts: %v", err)
+	}
+	if len(posts) != len(authors) {
+		t.Errorf("Expected the posts of the %d sampled authors, got %d", len(authors), len(posts))
+	}
+	for _, post := range posts {
+		if !ids[post["authorId"].(int64)] {
+			t.Errorf("Expected posts of sampled authors only, got %v", post)
+		}
+		if reviewer, ok := post["reviewerId"].(int64); ok && !ids[reviewer] {
+			t.Errorf("Expected reviewers that were not sampled to be cleared, got %v", post)
+		}
+	}
+
+	if _, err := Sample(ctx, from, to, SampleOptions{Percent: 0}); err == nil {
+		t.Error("Expected an error for a percentage of 0")
+	}
+}
@@ -0,0 +1,130 @@
+package dbcopy
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	mathrand "math/rand/v2"
+
+	"github.com/rediwo/redi-orm/masking"
+	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/types"
+)
+
+// SampleOptions configures a sample
+type SampleOptions struct {
+	Options
+	Percent float64         // Percentage of the records of models without required foreign keys to copy
+	Seed    uint64          // Seed of the selection of records, random when 0
+	Policy  *masking.Policy // Optional: sensitive fields anonymized with masking.Anonymize
+}
+
+// Sample copies a subset of the records of from to to like Copy, with the sensitive fields of
+// the policy anonymized. Percent of the records of models without required foreign keys are
+// selected at random, and the records of other models are copied when the records they
+// reference were. Optional foreign keys to records that were not copied are cleared, so that
+// the sample has no dangling references. Pseudonyms use a random key per sample: equal values
+// get equal pseudonyms within a sample, but not across samples.
+func Sample(ctx context.Context, from, to types.Database, opts SampleOptions) (*Result, error) {
+	if opts.Percent <= 0 || opts.Percent > 100 {
+		return nil, fmt.Errorf("invalid percentage %v: must be greater than 0 and at most 100", opts.Percent)
+	}
+	seed := opts.Seed
+	if seed == 0 {
+		seed = mathrand.Uint64()
+	}
+	s := &sampler{
+		rate:       opts.Percent / 100,
+		random:     mathrand.New(mathrand.NewPCG(seed, seed)),
+		policy:     opts.Policy,
+		schemas:    make(map[string]*schema.Schema),
+		referenced: make(map[string]map[string]map[string]bool),
+	}
+	if _, err := rand.Read(s.key[:]); err != nil {
+		return nil, err
+	}
+
+	for _, name := range from.GetModels() {
+		sch, err := from.GetSchema(name)
+		if err != nil {
+			return nil, err
+		}
+		s.schemas[name] = sch
+	}
+	for name, sch := range s.schemas {
+		for _, relation := range foreignKeys(s.schemas, name) {
+			if s.referenced[relation.Model] == nil {
+				s.referenced[relation.Model] = make(map[string]map[string]bool)
+			}
+			s.referenced[relation.Model][relation.References] = make(map[string]bool)
+		}
+		// Anonymized values that are not strings are cleared
+		for _, field := range sch.Fields {
+			if _, ok := opts.Policy.FieldStrategy(name, field.Name); ok && field.Type != schema.FieldTypeString && !field.Nullable {
+				return nil, fmt.Errorf("sensitive field %s.%s must be a string or optional to be anonymized", name, field.Name)
+			}
+		}
+	}
+
+	return copyDatabase(ctx, from, to, opts.Options, s.keep)
+}
+
+// sampler selects and anonymizes the records of a sample
+type sampler struct {
+	rate    float64
+	random  *mathrand.Rand
+	policy  *masking.Policy
+	key     [32]byte
+	schemas map[string]*schema.Schema
+	// Values of the fields referenced by foreign keys of the copied records, by model and field
+	referenced map[string]map[string]map[string]bool
+}
+
+func (s *sampler) keep(sch *schema.Schema, records []map[string]any) []map[string]any {
+	relations := foreignKeys(s.schemas, sch.Name)
+	selected := true
+	for _, relation := range relations {
+		if field := sch.GetFieldByName(relation.ForeignKey); !field.Nullable && relation.Model != sch.Name {
+			// Selected through the records they reference
+			selected = false
+		}
+	}
+
+	kept := records[:0]
+	for _, record := range records {
+		if selected && s.random.Float64() >= s.rate {
+			continue
+		}
+		if !s.resolve(sch, relations, record) {
+			continue
+		}
+		for field, values := range s.referenced[sch.Name] {
+			if value := record[field]; value != nil {
+				values[fmt.Sprint(value)] = true
+			}
+		}
+		for _, field := range sch.Fields {
+			if strategy, ok := s.policy.FieldStrategy(sch.Name, field.Name); ok && record[field.Name] != nil {
+				record[field.Name] = masking.Anonymize(strategy, record[field.Name], s.key[:])
+			}
+		}
+		kept = append(kept, record)
+	}
+	return kept
+}
+
+// resolve reports whether the records a record references were copied, and clears optional
+// foreign keys to records that were not
+func (s *sampler) resolve(sch *schema.Schema, relations []schema.Relation, record map[string]any) bool {
+	for _, relation := range relations {
+		value := record[relation.ForeignKey]
+		if value == nil || s.referenced[relation.Model][relation.References][fmt.Sprint(value)] {
+			continue
+		}
+		if !sch.GetFieldByName(relation.ForeignKey).Nullable {
+			return false
+		}
+		record[relation.ForeignKey] = nil
+	}
+	return true
+}
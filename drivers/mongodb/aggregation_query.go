@@ -4,30 +4,13 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/rediwo/redi-orm/query"
 	"github.com/rediwo/redi-orm/types"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
-// Local type definitions to match query package private types
-type aggregation struct {
-	Type      string
-	FieldName string
-	Alias     string
-}
-
-type aggregationOrder struct {
-	Type      string
-	FieldName string
-	Direction types.Order
-}
-
-type OrderClause struct {
-	FieldName string
-	Direction types.Order
-}
-
 // MongoDBaggregationQuery implements AggregationQuery for MongoDB
 type MongoDBaggregationQuery struct {
 	*query.AggregationQueryImpl
@@ -49,6 +32,9 @@ func NewMongoDBaggregationQuery(baseQuery *query.ModelQueryImpl, db *MongoDB, fi
 
 // Override execution method to use MongoDB aggregation pipeline
 func (q *MongoDBaggregationQuery) Exec(ctx context.Context, dest any) error {
+	ctx, cancel := q.StatementContext(ctx)
+	defer cancel()
+
 	// Get collection name
 	tableName, err := q.fieldMapper.ModelToTable(q.modelName)
 	if err != nil {
@@ -84,15 +70,22 @@ func (q *MongoDBaggregationQuery) buildAggregationPipeline() ([]bson.M, error) {
 	pipeline := []bson.M{}
 
 	// Add $match stage for WHERE conditions
-	if matchStage, err := q.buildMatchStage(); err == nil && matchStage != nil {
-		pipeline = append(pipeline, bson.M{"$match": matchStage})
+	matchStages, err := q.buildMatchStages()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build match stage: %w", err)
 	}
+	pipeline = append(pipeline, matchStages...)
 
 	// Add $group stage
 	if groupStage, err := q.buildGroupStage(); err == nil && groupStage != nil {
 		pipeline = append(pipeline, groupStage)
 	}
 
+	// Turn the value sets of distinct counts into counts
+	if countStage := q.buildDistinctCountStage(); countStage != nil {
+		pipeline = append(pipeline, bson.M{"$addFields": countStage})
+	}
+
 	// Add $match stage for HAVING conditions after $group
 	if havingStage, err := q.buildHavingStage(); err == nil && havingStage != nil {
 		pipeline = append(pipeline, bson.M{"$match": havingStage})
@@ -116,30 +109,24 @@ func (q *MongoDBaggregationQuery) buildAggregationPipeline() ([]bson.M, error) {
 	return pipeline, nil
 }
 
-// buildMatchStage builds $match stage for WHERE conditions
-func (q *MongoDBaggregationQuery) buildMatchStage() (bson.M, error) {
+// buildMatchStages builds the stages filtering documents on WHERE conditions
+func (q *MongoDBaggregationQuery) buildMatchStages() ([]bson.M, error) {
 	conditions := q.GetConditions()
 	if len(conditions) == 0 {
 		return nil, nil
 	}
 
-	// Use the same filter building logic as SelectQuery
-	// This is a simplified implementation
-	filter := bson.M{}
-
-	for _, condition := range conditions {
-		// Convert condition to MongoDB filter
-		// This is a basic implementation - full implementation would handle all condition types
-		conditionSQL, _ := condition.ToSQL(nil)
-		if conditionSQL != "" {
-			// For now, skip complex conditions in aggregation queries
+	// Combine all conditions with AND, as in select queries
+	var combined types.Condition
+	for i, cond := range conditions {
+		if i == 0 {
+			combined = cond
+		} else {
+			combined = combined.And(cond)
 		}
 	}
 
-	if len(filter) == 0 {
-		return nil, nil
-	}
-	return filter, nil
+	return NewMongoDBQueryBuilder(q.db).ConditionToPipeline(combined, q.modelName)
 }
 
 // buildGroupStage builds $group stage with aggregations
@@ -195,7 +182,13 @@ func (q *MongoDBaggregationQuery) buildGroupStage() (bson.M, error) {
 
 			switch strings.ToUpper(agg.Type) {
 			case "COUNT":
-				aggExpr = bson.M{"$sum": bson.M{"$cond": []any{bson.M{"$ne": []any{"$" + columnName, nil}}, 1, 0}}}
+				if agg.Distinct {
+					// Collected here, then counted by the distinct count stage
+					aggExpr = bson.M{"$addToSet": "$" + columnName}
+					break
+				}
+				// Missing and null values sort below every other value
+				aggExpr = bson.M{"$sum": bson.M{"$cond": []any{bson.M{"$gt": []any{"$" + columnName, nil}}, 1, 0}}}
 			case "SUM":
 				aggExpr = bson.M{"$sum": "$" + columnName}
 			case "AVG":
@@ -225,6 +218,23 @@ func (q *MongoDBaggregationQuery) buildGroupStage() (bson.M, error) {
 	return bson.M{"$group": group}, nil
 }
 
+// buildDistinctCountStage counts the non-null values collected for COUNT(DISTINCT field)
+func (q *MongoDBaggregationQuery) buildDistinctCountStage() bson.M {
+	stage := bson.M{}
+	for _, agg := range q.GetAggregations() {
+		if agg.Distinct && agg.FieldName != "" {
+			stage[agg.Alias] = bson.M{"$size": bson.M{"$filter": bson.M{
+				"input": "$" + agg.Alias,
+				"cond":  bson.M{"$ne": []any{"$$this", nil}},
+			}}}
+		}
+	}
+	if len(stage) == 0 {
+		return nil
+	}
+	return stage
+}
+
 // buildHavingStage builds $match stage for HAVING conditions
 func (q *MongoDBaggregationQuery) buildHavingStage() (bson.M, error) {
 	havingCondition := q.GetHaving()
@@ -275,13 +285,8 @@ func (q *MongoDBaggregationQuery) buildSortStage() bson.D {
 			direction = -1
 		}
 
-		// Map field name to column name
-		columnName, err := q.fieldMapper.SchemaToColumn(q.modelName, order.FieldName)
-		if err != nil {
-			columnName = order.FieldName
-		}
-
-		sort = append(sort, bson.E{Key: columnName, Value: direction})
+		// Grouped fields are output under their schema field names
+		sort = append(sort, bson.E{Key: order.FieldName, Value: direction})
 	}
 
 	// Add aggregation field ordering
@@ -301,37 +306,23 @@ func (q *MongoDBaggregationQuery) buildSortStage() bson.D {
 
 // Helper methods to access aggregation query internals
 func (q *MongoDBaggregationQuery) GetGroupBy() []string {
-	// Access the private field through reflection or use a workaround
-	// For now, we'll use the ModelQueryImpl's groupBy field
-	return q.ModelQueryImpl.GetGroupBy()
+	return q.AggregationQueryImpl.GetGroupBy()
 }
 
-func (q *MongoDBaggregationQuery) GetAggregations() []aggregation {
-	// Since aggregations field is private, return empty slice for now
-	// The aggregation pipeline will be built based on the query methods called
-	return []aggregation{}
+func (q *MongoDBaggregationQuery) GetAggregations() []query.Aggregation {
+	return q.AggregationQueryImpl.GetAggregations()
 }
 
 func (q *MongoDBaggregationQuery) GetHaving() types.Condition {
 	return q.AggregationQueryImpl.GetHaving()
 }
 
-func (q *MongoDBaggregationQuery) GetOrderBy() []OrderClause {
-	// Convert from query.OrderClause to local OrderClause
-	orderBy := q.AggregationQueryImpl.GetOrderBy()
-	result := make([]OrderClause, len(orderBy))
-	for i, o := range orderBy {
-		result[i] = OrderClause{
-			FieldName: o.FieldName,
-			Direction: o.Direction,
-		}
-	}
-	return result
+func (q *MongoDBaggregationQuery) GetOrderBy() []query.OrderClause {
+	return q.AggregationQueryImpl.GetOrderBy()
 }
 
-func (q *MongoDBaggregationQuery) GetAggregationOrders() []aggregationOrder {
-	// Since this method doesn't exist, return empty slice
-	return []aggregationOrder{}
+func (q *MongoDBaggregationQuery) GetAggregationOrders() []query.AggregationOrder {
+	return q.AggregationQueryImpl.GetAggregationOrders()
 }
 
 func (q *MongoDBaggregationQuery) GetConditions() []types.Condition {
@@ -385,6 +376,16 @@ func (q *MongoDBaggregationQuery) Count(fieldName string, alias string) types.Ag
 	}
 }
 
+func (q *MongoDBaggregationQuery) CountDistinct(fieldName string, alias string) types.AggregationQuery {
+	newBase := q.AggregationQueryImpl.CountDistinct(fieldName, alias).(*query.AggregationQueryImpl)
+	return &MongoDBaggregationQuery{
+		AggregationQueryImpl: newBase,
+		db:                   q.db,
+		fieldMapper:          q.fieldMapper,
+		modelName:            q.modelName,
+	}
+}
+
 func (q *MongoDBaggregationQuery) CountAll(alias string) types.AggregationQuery {
 	newBase := q.AggregationQueryImpl.CountAll(alias).(*query.AggregationQueryImpl)
 	return &MongoDBaggregationQuery{
@@ -499,6 +500,21 @@ func (q *MongoDBaggregationQuery) Offset(offset int) types.AggregationQuery {
 	}
 }
 
+func (q *MongoDBaggregationQuery) WithTimeout(timeout time.Duration) types.AggregationQuery {
+	newBase := q.AggregationQueryImpl.WithTimeout(timeout).(*query.AggregationQueryImpl)
+	return &MongoDBaggregationQuery{
+		AggregationQueryImpl: newBase,
+		db:                   q.db,
+		fieldMapper:          q.fieldMapper,
+		modelName:            q.modelName,
+	}
+}
+
+// ToSQL returns the MongoDB command of the query, without executing it
+func (q *MongoDBaggregationQuery) ToSQL() (string, []any, error) {
+	return q.BuildSQL()
+}
+
 func (q *MongoDBaggregationQuery) BuildSQL() (string, []any, error) {
 	// This should not be called for MongoDB, but implement for interface compliance
 	return "", nil, fmt.Errorf("BuildSQL not supported for MongoDB aggregation queries")
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/rediwo/redi-orm/types"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 // MongoDBCapabilities implements DriverCapabilities for MongoDB
@@ -41,6 +42,26 @@ func (c *MongoDBCapabilities) SupportsForeignKeys() bool {
 	return false
 }
 
+func (c *MongoDBCapabilities) SupportsUpsert() bool {
+	// update with the upsert option
+	return true
+}
+
+func (c *MongoDBCapabilities) SupportsJSONOperators() bool {
+	// Nested document fields are queried natively
+	return true
+}
+
+func (c *MongoDBCapabilities) SupportsFullText() bool {
+	// $text queries on text indexes
+	return true
+}
+
+func (c *MongoDBCapabilities) MaxParameters() int {
+	// Commands carry values inline, so there is no parameter limit
+	return 0
+}
+
 // Identifier quoting
 func (c *MongoDBCapabilities) QuoteIdentifier(name string) string {
 	// MongoDB doesn't quote identifiers
@@ -70,6 +91,44 @@ func (c *MongoDBCapabilities) GetNullsOrderingSQL(direction types.Order, nullsFi
 	return ""
 }
 
+func (c *MongoDBCapabilities) GetUpsertSQL(conflictColumns []string, updateColumns []string) string {
+	// MongoDB upserts are update commands with the upsert option
+	return ""
+}
+
+func (c *MongoDBCapabilities) GetSkipDuplicatesSQL(insertColumns []string) string {
+	// MongoDB skips duplicates with an unordered insertMany
+	return ""
+}
+
+func (c *MongoDBCapabilities) GetArrayAppendSQL(quotedColumn string) string {
+	// MongoDB appends to arrays with $push
+	return ""
+}
+
+func (c *MongoDBCapabilities) GetArrayFilterSQL(operator string, quotedColumn string) string {
+	// MongoDB filters arrays with $all, $in and $size
+	return ""
+}
+
+func (c *MongoDBCapabilities) EncodeArrayValue(values []any) (any, error) {
+	// MongoDB stores lists as native arrays
+	return values, nil
+}
+
+func (c *MongoDBCapabilities) DecodeArrayValue(value any) ([]any, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []any:
+		return v, nil
+	case bson.A:
+		return []any(v), nil
+	default:
+		return nil, fmt.Errorf("cannot decode %T as an array", value)
+	}
+}
+
 // Index/Table detection
 func (c *MongoDBCapabilities) IsSystemIndex(indexName string) bool {
 	// MongoDB system indexes
@@ -100,6 +159,15 @@ func (c *MongoDBCapabilities) SupportsTransactions() bool {
 	return true
 }
 
+func (c *MongoDBCapabilities) SupportsSavepoints() bool {
+	// MongoDB transactions cannot be partially rolled back
+	return false
+}
+
+func (c *MongoDBCapabilities) SupportsTransactionalDDL() bool {
+	return false
+}
+
 func (c *MongoDBCapabilities) SupportsNestedDocuments() bool {
 	return true
 }
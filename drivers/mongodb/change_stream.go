@@ -0,0 +1,126 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rediwo/redi-orm/base"
+	"github.com/rediwo/redi-orm/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// changeStreamDocument is the subset of a MongoDB change event we consume
+type changeStreamDocument struct {
+	OperationType string `bson:"operationType"`
+	FullDocument  bson.M `bson:"fullDocument"`
+	DocumentKey   bson.M `bson:"documentKey"`
+	Ns            struct {
+		Coll string `bson:"coll"`
+	} `bson:"ns"`
+}
+
+// Watch opens a MongoDB change stream for the given models.
+// Change streams require a replica set or sharded cluster.
+func (m *MongoDB) Watch(ctx context.Context, models ...string) (types.ChangeStream, error) {
+	if m.client == nil {
+		return nil, fmt.Errorf("not connected to MongoDB")
+	}
+
+	models, err := m.ResolveWatchModels(models)
+	if err != nil {
+		return nil, err
+	}
+
+	collectionToModel := make(map[string]string, len(models))
+	collections := make([]string, 0, len(models))
+	for _, modelName := range models {
+		collectionName := m.getCollectionName(modelName)
+		collectionToModel[collectionName] = modelName
+		collections = append(collections, collectionName)
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"ns.coll":       bson.M{"$in": collections},
+			"operationType": bson.M{"$in": []string{"insert", "update", "replace", "delete"}},
+		}}},
+	}
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+
+	stream, err := m.client.Database(m.dbName).Watch(ctx, pipeline, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open change stream (a replica set is required): %w", err)
+	}
+
+	feed, feedCtx := base.NewChangeFeed(ctx)
+	go func() {
+		defer stream.Close(context.Background())
+		for stream.Next(feedCtx) {
+			var doc changeStreamDocument
+			if err := stream.Decode(&doc); err != nil {
+				feed.Finish(fmt.Errorf("failed to decode change event: %w", err))
+				return
+			}
+
+			modelName, watched := collectionToModel[doc.Ns.Coll]
+			if !watched {
+				continue
+			}
+
+			event, ok := m.convertChangeEvent(modelName, &doc)
+			if !ok {
+				continue
+			}
+			if !feed.Emit(feedCtx, event) {
+				break
+			}
+		}
+
+		if feedCtx.Err() != nil {
+			feed.Finish(feedCtx.Err())
+			return
+		}
+		feed.Finish(stream.Err())
+	}()
+
+	return feed, nil
+}
+
+// convertChangeEvent maps a MongoDB change document to a change event using schema field names
+func (m *MongoDB) convertChangeEvent(modelName string, doc *changeStreamDocument) (types.ChangeEvent, bool) {
+	var op types.ChangeOperation
+	switch doc.OperationType {
+	case "insert":
+		op = types.ChangeCreate
+	case "update", "replace":
+		op = types.ChangeUpdate
+	case "delete":
+		op = types.ChangeDelete
+	default:
+		return types.ChangeEvent{}, false
+	}
+
+	source := doc.FullDocument
+	if source == nil {
+		// Deleted documents (or documents removed before lookup) only carry their key
+		source = doc.DocumentKey
+	}
+
+	data := make(map[string]any, len(source))
+	for key, value := range source {
+		data[key] = convertBSONToGoTypes(value)
+	}
+	if mapped, err := m.FieldMapper.MapColumnToSchemaData(modelName, data); err == nil {
+		data = mapped
+	}
+
+	return types.ChangeEvent{
+		Model:     modelName,
+		Operation: op,
+		Data:      data,
+		Timestamp: time.Now(),
+	}, true
+}
@@ -3,8 +3,12 @@ package mongodb
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"time"
 
+	"github.com/rediwo/redi-orm/utils"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // MongoDBCommand represents a MongoDB operation command
@@ -14,8 +18,8 @@ type MongoDBCommand struct {
 	Documents    []any    `json:"documents,omitempty"`    // For insert operations
 	Filter       bson.M   `json:"filter,omitempty"`       // For find/update/delete
 	Update       bson.M   `json:"update,omitempty"`       // For update operations
-	Pipeline     []bson.M `json:"pipeline,omitempty"`     // For aggregate operations
-	Options      bson.M   `json:"options,omitempty"`      // Operation options (limit, skip, sort, etc.)
+	Pipeline     []bson.M `json:"pipeline,omitempty"`     // For aggregate operations and pipeline updates
+	Options      bson.M   `json:"options,omitempty"`      // Operation options (limit, skip, sort, upsert, ordered, skipDuplicates, etc.)
 	Fields       []string `json:"fields,omitempty"`       // Field names for projection
 	LastInsertID int64    `json:"lastInsertId,omitempty"` // For passing generated ID from insert query
 }
@@ -49,7 +53,7 @@ func (c *MongoDBCommand) ToJSON() (string, error) {
 	if len(c.Pipeline) > 0 {
 		pipeline := make([]any, len(c.Pipeline))
 		for i, stage := range c.Pipeline {
-			pipeline[i] = convertBSONTypes(stage)
+			pipeline[i] = convertBSONTypes(encodeSortStage(stage))
 		}
 		jsonCmd["pipeline"] = pipeline
 	}
@@ -119,5 +123,145 @@ func (c *MongoDBCommand) FromJSON(jsonStr string) error {
 	if err := json.Unmarshal([]byte(jsonStr), c); err != nil {
 		return fmt.Errorf("failed to unmarshal MongoDB command: %w", err)
 	}
+
+	// Binary data, decimals, 64-bit integers and dates are carried through JSON as extended JSON
+	for i, doc := range c.Documents {
+		c.Documents[i] = restoreTypedValues(doc)
+	}
+	if c.Filter != nil {
+		c.Filter = restoreTypedValues(c.Filter).(bson.M)
+	}
+	if c.Update != nil {
+		c.Update = restoreTypedValues(c.Update).(bson.M)
+	}
+	for i, stage := range c.Pipeline {
+		c.Pipeline[i] = restoreTypedValues(decodeSortStage(stage)).(bson.M)
+	}
 	return nil
 }
+
+// encodeSortStage carries the keys of a $sort stage as a list of single-key documents, as a
+// JSON object does not keep the order the keys apply in
+func encodeSortStage(stage bson.M) bson.M {
+	sort, ok := stage["$sort"].(bson.D)
+	if !ok || len(stage) != 1 {
+		return stage
+	}
+	keys := make([]any, len(sort))
+	for i, elem := range sort {
+		keys[i] = bson.M{elem.Key: elem.Value}
+	}
+	return bson.M{"$sort": keys}
+}
+
+// decodeSortStage restores the $sort stages encoded by encodeSortStage
+func decodeSortStage(stage bson.M) bson.M {
+	keys, ok := stage["$sort"].([]any)
+	if !ok || len(stage) != 1 {
+		return stage
+	}
+	sort := bson.D{}
+	for _, key := range keys {
+		if doc, ok := key.(map[string]any); ok {
+			for field, direction := range doc {
+				sort = append(sort, bson.E{Key: field, Value: direction})
+			}
+		}
+	}
+	return bson.M{"$sort": sort}
+}
+
+// binaryValue wraps binary data in an extended JSON $binary document, which keeps it
+// binary when the command is passed on as JSON
+func binaryValue(data []byte) bson.M {
+	return bson.M{"$binary": bson.M{"base64": utils.EncodeBytes(data), "subType": "00"}}
+}
+
+// binaryData returns the bytes of BSON binary data; other values are returned unchanged
+func binaryData(value any) any {
+	if binary, ok := value.(primitive.Binary); ok {
+		return binary.Data
+	}
+	return value
+}
+
+// decimalValue wraps the text of a decimal in an extended JSON $numberDecimal document,
+// which is stored as a BSON decimal instead of going through float64
+func decimalValue(text string) bson.M {
+	return bson.M{"$numberDecimal": text}
+}
+
+// longValue wraps a 64-bit integer in an extended JSON $numberLong document, which keeps
+// integers beyond 2^53 exact when the command is passed on as JSON
+func longValue(n int64) bson.M {
+	return bson.M{"$numberLong": strconv.FormatInt(n, 10)}
+}
+
+// dateValue wraps a time in an extended JSON $date document, which is stored as a BSON date
+// instead of the text a time.Time becomes in JSON
+func dateValue(t time.Time) bson.M {
+	return bson.M{"$date": t.UTC().Format(time.RFC3339Nano)}
+}
+
+// restoreTypedValues replaces the $binary, $numberDecimal, $numberLong, $date and $oid
+// documents made by binaryValue, decimalValue, longValue, dateValue and objectIDValue with
+// their BSON values, in documents and lists decoded from JSON
+func restoreTypedValues(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		if typed, ok := restoreTypedValue(v); ok {
+			return typed
+		}
+		for key, item := range v {
+			v[key] = restoreTypedValues(item)
+		}
+		return v
+	case bson.M:
+		for key, item := range v {
+			v[key] = restoreTypedValues(item)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = restoreTypedValues(item)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// restoreTypedValue returns the BSON value of a single extended JSON document
+func restoreTypedValue(doc map[string]any) (any, bool) {
+	if len(doc) != 1 {
+		return nil, false
+	}
+	if binary, ok := doc["$binary"].(map[string]any); ok {
+		if encoded, ok := binary["base64"].(string); ok {
+			if data, err := utils.DecodeBytes(encoded); err == nil {
+				return primitive.Binary{Subtype: bson.TypeBinaryGeneric, Data: data}, true
+			}
+		}
+	}
+	if text, ok := doc["$numberDecimal"].(string); ok {
+		if d, err := primitive.ParseDecimal128(text); err == nil {
+			return d, true
+		}
+	}
+	if text, ok := doc["$numberLong"].(string); ok {
+		if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return n, true
+		}
+	}
+	if text, ok := doc["$date"].(string); ok {
+		if t, err := time.Parse(time.RFC3339Nano, text); err == nil {
+			return primitive.NewDateTimeFromTime(t), true
+		}
+	}
+	if hex, ok := doc["$oid"].(string); ok {
+		if oid, err := primitive.ObjectIDFromHex(hex); err == nil {
+			return oid, true
+		}
+	}
+	return nil, false
+}
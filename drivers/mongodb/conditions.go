@@ -197,6 +197,61 @@ func (f *MongoDBFieldCondition) Between(min, max any) types.Condition {
 	}
 }
 
+// Has creates a condition matching lists that contain the value
+func (f *MongoDBFieldCondition) Has(value any) types.Condition {
+	return &MongoDBCondition{
+		fieldName: f.fieldName,
+		modelName: f.modelName,
+		operator:  "has",
+		value:     value,
+		db:        f.db,
+	}
+}
+
+// HasSome creates a condition matching lists that contain any of the values
+func (f *MongoDBFieldCondition) HasSome(values ...any) types.Condition {
+	return &MongoDBCondition{
+		fieldName: f.fieldName,
+		modelName: f.modelName,
+		operator:  "hasSome",
+		value:     values,
+		db:        f.db,
+	}
+}
+
+// HasEvery creates a condition matching lists that contain all of the values
+func (f *MongoDBFieldCondition) HasEvery(values ...any) types.Condition {
+	return &MongoDBCondition{
+		fieldName: f.fieldName,
+		modelName: f.modelName,
+		operator:  "hasEvery",
+		value:     values,
+		db:        f.db,
+	}
+}
+
+// IsEmpty creates a condition matching empty lists
+func (f *MongoDBFieldCondition) IsEmpty() types.Condition {
+	return &MongoDBCondition{
+		fieldName: f.fieldName,
+		modelName: f.modelName,
+		operator:  "isEmpty",
+		value:     nil,
+		db:        f.db,
+	}
+}
+
+// IsNotEmpty creates a condition matching lists with at least one element
+func (f *MongoDBFieldCondition) IsNotEmpty() types.Condition {
+	return &MongoDBCondition{
+		fieldName: f.fieldName,
+		modelName: f.modelName,
+		operator:  "isNotEmpty",
+		value:     nil,
+		db:        f.db,
+	}
+}
+
 // GetFieldName returns the field name
 func (f *MongoDBFieldCondition) GetFieldName() string {
 	return f.fieldName
@@ -256,29 +311,29 @@ func (c *MongoDBCondition) ToSQL(ctx *types.ConditionContext) (string, []any) {
 		if c.value == nil {
 			filter = bson.M{columnName: nil}
 		} else {
-			filter = bson.M{columnName: c.value}
+			filter = bson.M{columnName: c.filterValue(c.value)}
 		}
 	case "ne":
-		filter = bson.M{columnName: bson.M{"$ne": c.value}}
+		filter = bson.M{columnName: bson.M{"$ne": c.filterValue(c.value)}}
 	case "gt":
-		filter = bson.M{columnName: bson.M{"$gt": c.value}}
+		filter = bson.M{columnName: bson.M{"$gt": c.filterValue(c.value)}}
 	case "gte":
-		filter = bson.M{columnName: bson.M{"$gte": c.value}}
+		filter = bson.M{columnName: bson.M{"$gte": c.filterValue(c.value)}}
 	case "lt":
-		filter = bson.M{columnName: bson.M{"$lt": c.value}}
+		filter = bson.M{columnName: bson.M{"$lt": c.filterValue(c.value)}}
 	case "lte":
-		filter = bson.M{columnName: bson.M{"$lte": c.value}}
+		filter = bson.M{columnName: bson.M{"$lte": c.filterValue(c.value)}}
 	case "in":
 		if values, ok := c.value.([]any); ok {
-			filter = bson.M{columnName: bson.M{"$in": values}}
+			filter = bson.M{columnName: bson.M{"$in": c.filterValues(values)}}
 		} else {
-			filter = bson.M{columnName: bson.M{"$in": []any{c.value}}}
+			filter = bson.M{columnName: bson.M{"$in": []any{c.filterValue(c.value)}}}
 		}
 	case "nin":
 		if values, ok := c.value.([]any); ok {
-			filter = bson.M{columnName: bson.M{"$nin": values}}
+			filter = bson.M{columnName: bson.M{"$nin": c.filterValues(values)}}
 		} else {
-			filter = bson.M{columnName: bson.M{"$nin": []any{c.value}}}
+			filter = bson.M{columnName: bson.M{"$nin": []any{c.filterValue(c.value)}}}
 		}
 	case "regex":
 		pattern := fmt.Sprintf("%v", c.value)
@@ -289,7 +344,7 @@ func (c *MongoDBCondition) ToSQL(ctx *types.ConditionContext) (string, []any) {
 		// Direct regex pattern (already processed)
 		pattern := fmt.Sprintf("%v", c.value)
 		// Use case-sensitive matching for string operators
-		fmt.Printf("[DEBUG] MongoDB filter for %s: field=%s, pattern=%s\n", c.operator, columnName, pattern)
+		c.db.Log().Debug("Filter for %s: field=%s, pattern=%s", c.operator, columnName, pattern)
 		filter = bson.M{columnName: bson.M{"$regex": pattern}}
 	case "null":
 		filter = bson.M{columnName: nil}
@@ -297,10 +352,12 @@ func (c *MongoDBCondition) ToSQL(ctx *types.ConditionContext) (string, []any) {
 		filter = bson.M{columnName: bson.M{"$ne": nil}}
 	case "between":
 		if values, ok := c.value.([]any); ok && len(values) == 2 {
-			filter = bson.M{columnName: bson.M{"$gte": values[0], "$lte": values[1]}}
+			filter = bson.M{columnName: bson.M{"$gte": c.filterValue(values[0]), "$lte": c.filterValue(values[1])}}
 		} else {
 			filter = bson.M{"$comment": "invalid between values"}
 		}
+	case "has", "hasSome", "hasEvery", "isEmpty", "isNotEmpty":
+		filter = arrayFilter(columnName, c.operator, c.value)
 	default:
 		filter = bson.M{"$comment": fmt.Sprintf("unsupported operator: %s", c.operator)}
 	}
@@ -314,6 +371,64 @@ func (c *MongoDBCondition) ToSQL(ctx *types.ConditionContext) (string, []any) {
 	return string(jsonBytes), nil
 }
 
+// filterValue converts a value compared with the field of the condition to its stored form
+func (c *MongoDBCondition) filterValue(value any) any {
+	return mapFilterValue(c.db, c.modelName, c.fieldName, value)
+}
+
+// filterValues is filterValue for the values of an in or not in condition
+func (c *MongoDBCondition) filterValues(values []any) []any {
+	return mapFilterValues(c.db, c.modelName, c.fieldName, values)
+}
+
+// mapFilterValue converts a value compared with a field to the form MapValueToColumn stores
+// it in, so that dates, decimals and binary data are compared as BSON values. Values that
+// cannot be converted are compared as they are.
+func mapFilterValue(db *MongoDB, modelName, fieldName string, value any) any {
+	if db == nil || value == nil {
+		return value
+	}
+	mapper, ok := db.GetFieldMapper().(*MongoDBFieldMapper)
+	if !ok {
+		return value
+	}
+	mapped, err := mapper.MapValueToColumn(modelName, fieldName, value)
+	if err != nil {
+		return value
+	}
+	return mapped
+}
+
+// mapFilterValues is mapFilterValue for a list of values
+func mapFilterValues(db *MongoDB, modelName, fieldName string, values []any) []any {
+	mapped := make([]any, len(values))
+	for i, value := range values {
+		mapped[i] = mapFilterValue(db, modelName, fieldName, value)
+	}
+	return mapped
+}
+
+// arrayFilter returns the filter for a list operator on an array field
+func arrayFilter(columnName, operator string, value any) bson.M {
+	values, _ := value.([]any)
+	switch operator {
+	case "has":
+		return bson.M{columnName: value}
+	case "hasSome":
+		return bson.M{columnName: bson.M{"$in": values}}
+	case "hasEvery":
+		if len(values) == 0 {
+			// $all with no values matches nothing, but every list contains all of no elements
+			return bson.M{columnName: bson.M{"$type": "array"}}
+		}
+		return bson.M{columnName: bson.M{"$all": values}}
+	case "isEmpty":
+		return bson.M{columnName: bson.M{"$size": 0}}
+	default:
+		return bson.M{columnName + ".0": bson.M{"$exists": true}}
+	}
+}
+
 // convertLikeToRegex converts SQL LIKE patterns to MongoDB regex
 func convertLikeToRegex(pattern string) string {
 	// Escape regex special characters first, except % and _
@@ -24,9 +24,7 @@ func TestMongoDBConformance(t *testing.T) {
 		},
 		URI: uri,
 		SkipTests: map[string]bool{
-			"TestDropModel": true, // MongoDB auto-creates collections on insert
-			// Savepoints are not supported in MongoDB
-			"TestSavepoints":                 true,
+			"TestDropModel":                  true, // MongoDB auto-creates collections on insert
 			"TestNotNullConstraintViolation": true, // MongoDB doesn't enforce NOT NULL at DB level
 			"TestInvalidFieldName":           true, // MongoDB allows any field names
 			"TestInvalidModelName":           true, // MongoDB doesn't validate model names
@@ -44,6 +42,7 @@ func TestMongoDBConformance(t *testing.T) {
 			"TestGenerateDropIndexSQL":   true,
 			"TestApplyMigration":         true,
 			"TestMigrationWorkflow":      true,
+			"TestReservedWordMigration":  true,
 			// SQL-specific tests not applicable to MongoDB
 			"TestRawQueryErrorHandling": true, // MongoDB uses JSON queries, not SQL syntax validation
 			"TestGenerateColumnSQL":     true, // MongoDB doesn't use SQL column definitions
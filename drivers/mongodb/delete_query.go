@@ -28,6 +28,11 @@ func NewMongoDBDeleteQuery(baseQuery *query.ModelQueryImpl, db *MongoDB, fieldMa
 	}
 }
 
+// ToSQL returns the MongoDB command of the query, without executing it
+func (q *MongoDBDeleteQuery) ToSQL() (string, []any, error) {
+	return q.BuildSQL()
+}
+
 // BuildSQL builds a MongoDB delete command instead of SQL
 func (q *MongoDBDeleteQuery) BuildSQL() (string, []any, error) {
 	// Get collection name
@@ -90,13 +95,26 @@ func (q *MongoDBDeleteQuery) buildFilter() (bson.M, error) {
 	return qb.ConditionToFilter(combined, q.modelName)
 }
 
-// Exec executes the delete query
+// Exec executes the delete query, first applying the onDelete actions of relations
+// referencing the deleted documents
 func (q *MongoDBDeleteQuery) Exec(ctx context.Context) (types.Result, error) {
+	ctx, cancel := q.StatementContext(ctx)
+	defer cancel()
+	defer types.ForgetDeduplicated(ctx)
+
 	sql, args, err := q.BuildSQL()
 	if err != nil {
 		return types.Result{}, fmt.Errorf("failed to build MongoDB command: %w", err)
 	}
 
+	filter, err := q.buildFilter()
+	if err != nil {
+		return types.Result{}, fmt.Errorf("failed to build filter: %w", err)
+	}
+	if err := q.db.applyOnDelete(ctx, q.modelName, filter, 0); err != nil {
+		return types.Result{}, err
+	}
+
 	rawQuery := q.db.Raw(sql, args...)
 	result, err := rawQuery.Exec(ctx)
 	if err != nil {
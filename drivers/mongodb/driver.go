@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/rediwo/redi-orm/base"
 	"github.com/rediwo/redi-orm/registry"
@@ -34,9 +35,12 @@ func init() {
 // MongoDB implements the Database interface for MongoDB
 type MongoDB struct {
 	*base.Driver
-	client    *mongo.Client
-	nativeURI string
-	dbName    string
+	client     *mongo.Client
+	nativeURI  string
+	dbName     string
+	session    mongo.Session       // set on copies bound to a transaction
+	txSupport  *transactionSupport // shared by transaction-bound copies
+	idStrategy IDStrategy          // of string primary keys, see SetIDStrategy
 }
 
 // NewMongoDB creates a new MongoDB database instance
@@ -56,6 +60,7 @@ func NewMongoDB(nativeURI string) (*MongoDB, error) {
 		Driver:    baseDriver,
 		nativeURI: nativeURI,
 		dbName:    dbName,
+		txSupport: &transactionSupport{},
 	}
 
 	// Replace base field mapper with MongoDB-specific field mapper
@@ -87,6 +92,7 @@ func (m *MongoDB) Connect(ctx context.Context) error {
 
 // Close closes the MongoDB connection
 func (m *MongoDB) Close() error {
+	m.StopHealthCheck()
 	if m.client != nil {
 		return m.client.Disconnect(context.Background())
 	}
@@ -101,6 +107,12 @@ func (m *MongoDB) Ping(ctx context.Context) error {
 	return m.client.Ping(ctx, nil)
 }
 
+// StartHealthCheck pings MongoDB every interval, reconnecting when it stops answering. The
+// driver redials the servers of the deployment for the pings.
+func (m *MongoDB) StartHealthCheck(interval time.Duration) (stop func()) {
+	return m.MonitorConnection(interval, m.Ping)
+}
+
 // SyncSchemas synchronizes all loaded schemas with the database
 func (m *MongoDB) SyncSchemas(ctx context.Context) error {
 	// In MongoDB, we don't create tables, but we can create collections and indexes
@@ -165,11 +177,15 @@ func (m *MongoDB) Model(modelName string) types.ModelQuery {
 
 // Raw creates a new raw query
 func (m *MongoDB) Raw(command string, args ...any) types.RawQuery {
-	return NewMongoDBRawQuery(m.client.Database(m.dbName), nil, m, command, args...)
+	return NewMongoDBRawQuery(m.client.Database(m.dbName), m.session, m, command, args...)
 }
 
-// Begin starts a new transaction
+// Begin starts a new transaction. Transactions need a replica set or sharded cluster.
 func (m *MongoDB) Begin(ctx context.Context) (types.Transaction, error) {
+	if err := m.checkTransactionSupport(ctx); err != nil {
+		return nil, err
+	}
+
 	session, err := m.client.StartSession()
 	if err != nil {
 		return nil, fmt.Errorf("failed to start session: %w", err)
@@ -183,36 +199,22 @@ func (m *MongoDB) Begin(ctx context.Context) (types.Transaction, error) {
 	return NewMongoDBTransaction(session, m), nil
 }
 
-// Transaction executes a function within a transaction
+// Transaction executes a function within a transaction, committing when it returns nil
+// and aborting otherwise
 func (m *MongoDB) Transaction(ctx context.Context, fn func(tx types.Transaction) error) error {
-	session, err := m.client.StartSession()
+	tx, err := m.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to start session: %w", err)
+		return err
 	}
-	defer session.EndSession(ctx)
-
-	// Start transaction manually for better control
-	err = session.StartTransaction()
-	if err != nil {
-		return fmt.Errorf("failed to start transaction: %w", err)
-	}
-
-	// Create transaction wrapper
-	tx := NewMongoDBTransaction(session, m)
-
-	// Execute the function
-	fnErr := fn(tx)
 
-	if fnErr != nil {
-		// Rollback on error
-		if abortErr := session.AbortTransaction(ctx); abortErr != nil {
+	if fnErr := fn(tx); fnErr != nil {
+		if abortErr := tx.Rollback(ctx); abortErr != nil {
 			return fmt.Errorf("failed to abort transaction (original error: %w): %v", fnErr, abortErr)
 		}
 		return fnErr
 	}
 
-	// Commit on success
-	if commitErr := session.CommitTransaction(ctx); commitErr != nil {
+	if commitErr := tx.Commit(ctx); commitErr != nil {
 		return fmt.Errorf("failed to commit transaction: %w", commitErr)
 	}
 
@@ -397,6 +399,8 @@ func (m *MongoDB) fieldToJSONSchema(field schema.Field) bson.M {
 		jsonSchema["bsonType"] = "array"
 	case schema.FieldTypeDecimal, schema.FieldTypeDecimal128:
 		jsonSchema["bsonType"] = "decimal"
+	case schema.FieldTypeBytes:
+		jsonSchema["bsonType"] = "binData"
 	default:
 		// For array types
 		if strings.HasSuffix(string(field.Type), "[]") {
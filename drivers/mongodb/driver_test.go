@@ -133,9 +133,99 @@ func TestMongoDB_Capabilities(t *testing.T) {
 
 	assert.True(t, caps.IsNoSQL())
 	assert.True(t, caps.SupportsTransactions())
+	assert.False(t, caps.SupportsSavepoints())
 	assert.True(t, caps.SupportsNestedDocuments())
 	assert.True(t, caps.SupportsArrayFields())
 	assert.True(t, caps.SupportsAggregationPipeline())
 	assert.False(t, caps.SupportsDistinctOn())
 	assert.Equal(t, "mongodb", string(caps.GetDriverType()))
 }
+
+func TestMongoDB_SupportsTransactions(t *testing.T) {
+	assert.True(t, supportsTransactions("rs0", ""), "replica set member")
+	assert.True(t, supportsTransactions("", "isdbgrid"), "mongos router")
+	assert.False(t, supportsTransactions("", ""), "standalone server")
+}
+
+func TestMongoDB_ReferentialActions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping MongoDB test in short mode")
+	}
+
+	db, err := NewMongoDB(getTestMongoDBURI())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = db.Connect(ctx)
+	if err != nil {
+		t.Skipf("MongoDB not available: %v", err)
+	}
+	defer db.Close()
+	cleanupTables(t, db)
+	defer cleanupTables(t, db)
+
+	err = db.LoadSchema(ctx, `
+model User {
+  id       Int       @id
+  posts    Post[]
+  profiles Profile[]
+}
+
+model Post {
+  id       Int       @id
+  authorId Int
+  author   User      @relation(fields: [authorId], references: [id], onDelete: Cascade)
+  comments Comment[]
+}
+
+model Comment {
+  id     Int  @id
+  postId Int
+  post   Post @relation(fields: [postId], references: [id], onDelete: Restrict)
+}
+
+model Profile {
+  id     Int   @id
+  userId Int?
+  user   User? @relation(fields: [userId], references: [id], onDelete: SetNull)
+}`)
+	require.NoError(t, err)
+	require.NoError(t, db.SyncSchemas(ctx))
+
+	insert := func(model string, data map[string]any) {
+		_, err := db.Model(model).Insert(data).Exec(ctx)
+		require.NoError(t, err)
+	}
+	count := func(model string) int64 {
+		n, err := db.Model(model).Count(ctx)
+		require.NoError(t, err)
+		return n
+	}
+	deleteUser := func(id int) error {
+		_, err := db.Model("User").Delete().WhereCondition(db.Model("User").Where("id").Equals(id)).Exec(ctx)
+		return err
+	}
+
+	insert("User", map[string]any{"id": 1})
+	insert("User", map[string]any{"id": 2})
+	insert("Post", map[string]any{"id": 1, "authorId": 1})
+	insert("Post", map[string]any{"id": 2, "authorId": 2})
+	insert("Comment", map[string]any{"id": 1, "postId": 2})
+	insert("Profile", map[string]any{"id": 1, "userId": 1})
+
+	// Posts are deleted with their author, and profiles no longer reference them
+	require.NoError(t, deleteUser(1))
+	assert.Equal(t, int64(1), count("User"))
+	assert.Equal(t, int64(1), count("Post"))
+	var profiles []map[string]any
+	require.NoError(t, db.Model("Profile").Select().FindMany(ctx, &profiles))
+	require.Len(t, profiles, 1)
+	assert.Nil(t, profiles[0]["userId"])
+
+	// Cascading to a post with comments is restricted
+	err = deleteUser(2)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "referenced by Comment.post")
+	assert.Equal(t, int64(1), count("User"))
+	assert.Equal(t, int64(1), count("Post"))
+}
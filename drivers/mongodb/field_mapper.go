@@ -9,6 +9,7 @@ import (
 	"github.com/rediwo/redi-orm/types"
 	"github.com/rediwo/redi-orm/utils"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
@@ -123,6 +124,9 @@ func (m *MongoDBFieldMapper) MapSchemaToColumnData(modelName string, data map[st
 		// Single primary key
 		pkField := primaryKeyFields[0]
 		if value, exists := data[pkField.Name]; exists {
+			if m.db.holdsObjectID(schema, &pkField) {
+				value = objectIDValue(value)
+			}
 			mapped["_id"] = value
 		}
 	} else if len(primaryKeyFields) > 1 {
@@ -153,7 +157,9 @@ func (m *MongoDBFieldMapper) MapSchemaToColumnData(modelName string, data map[st
 			if err != nil {
 				columnName = field
 			}
-			mapped[columnName] = value
+			if mapped[columnName], err = m.MapValueToColumn(modelName, field, value); err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -182,7 +188,7 @@ func (m *MongoDBFieldMapper) MapColumnToSchemaData(modelName string, data map[st
 		if len(primaryKeyFields) == 1 {
 			// Single primary key
 			pkField := primaryKeyFields[0]
-			mapped[pkField.Name] = idValue
+			mapped[pkField.Name] = mapValueFromColumn(&pkField, idValue)
 		} else if len(primaryKeyFields) > 1 {
 			// Composite primary key - extract from object
 			if compositeKey, ok := idValue.(map[string]any); ok {
@@ -217,6 +223,9 @@ func (m *MongoDBFieldMapper) MapColumnToSchemaData(modelName string, data map[st
 			if err != nil {
 				fieldName = column
 			}
+			if field, err := schema.GetFieldByPath(fieldName); err == nil {
+				value = mapValueFromColumn(field, value)
+			}
 			mapped[fieldName] = value
 		}
 	}
@@ -254,6 +263,169 @@ func (m *MongoDBFieldMapper) MapColumnToSchemaData(modelName string, data map[st
 	return mapped, nil
 }
 
+// MapValueToColumn converts a field value (or a value at a path into a composite type
+// field) to its stored form. Binary values become BSON binary data, decimals BSON decimals,
+// 64-bit integers BSON longs and DateTime values BSON dates in UTC, and embedded documents
+// of composite types are mapped with MapCompositeToColumns. Hex strings of fields holding
+// ObjectIDs become ObjectIDs.
+func (m *MongoDBFieldMapper) MapValueToColumn(modelName, fieldPath string, value any) (any, error) {
+	s, err := m.db.GetSchema(modelName)
+	if err != nil {
+		return value, nil
+	}
+	field, err := s.GetFieldByPath(fieldPath)
+	if err != nil || value == nil {
+		return m.MapCompositeToColumns(modelName, fieldPath, value), nil
+	}
+	if m.db.holdsObjectID(s, field) {
+		return objectIDValue(value), nil
+	}
+	switch field.Type {
+	case schema.FieldTypeBytes:
+		data, err := utils.DecodeBytes(value)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", fieldPath, err)
+		}
+		return binaryValue(data), nil
+	case schema.FieldTypeDecimal, schema.FieldTypeDecimal128:
+		text, err := utils.ToDecimalString(value)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", fieldPath, err)
+		}
+		return decimalValue(text), nil
+	case schema.FieldTypeInt64:
+		n, err := utils.ToBigInt(value)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", fieldPath, err)
+		}
+		return longValue(n), nil
+	case schema.FieldTypeDateTime:
+		t, err := utils.ToTime(value)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", fieldPath, err)
+		}
+		switch field.NativeType() {
+		case "Date":
+			t = utils.UTCDate(t)
+		case "Time":
+			t = utils.UTCTimeOfDay(t)
+		}
+		return dateValue(t), nil
+	}
+	return m.MapCompositeToColumns(modelName, fieldPath, value), nil
+}
+
+// mapValueFromColumn converts a stored value back to the value of its field
+func mapValueFromColumn(field *schema.Field, value any) any {
+	if field.Composite != nil {
+		return mapCompositeValue(field.Composite, value, false)
+	}
+	switch field.Type {
+	case schema.FieldTypeString, schema.FieldTypeObjectId:
+		// ObjectIDs are returned as hex strings
+		return objectIDHex(value)
+	case schema.FieldTypeBytes:
+		return binaryData(value)
+	case schema.FieldTypeDecimal, schema.FieldTypeDecimal128:
+		if d, ok := value.(primitive.Decimal128); ok {
+			return d.String()
+		}
+		if text, err := utils.ToDecimalString(value); err == nil {
+			return text
+		}
+	case schema.FieldTypeDateTime:
+		if d, ok := value.(primitive.DateTime); ok {
+			value = d.Time()
+		}
+		// Dates written as text before they were stored as BSON dates are parsed too
+		if t, err := utils.ToTime(value); err == nil {
+			switch field.NativeType() {
+			case "Date":
+				return utils.UTCDate(t)
+			case "Time":
+				return utils.UTCTimeOfDay(t)
+			}
+			return t.UTC()
+		}
+	}
+	return value
+}
+
+// MapCompositeToColumns maps the field names of embedded documents held by a composite
+// type field (or a path into one) to their stored keys. Other values are returned unchanged.
+func (m *MongoDBFieldMapper) MapCompositeToColumns(modelName, fieldPath string, value any) any {
+	s, err := m.db.GetSchema(modelName)
+	if err != nil {
+		return value
+	}
+	field, err := s.GetFieldByPath(fieldPath)
+	if err != nil || field.Composite == nil {
+		return value
+	}
+	return mapCompositeValue(field.Composite, value, true)
+}
+
+// mapCompositeValue renames the keys of embedded documents, and of lists of them, from
+// field names to stored keys (toColumns) or back. Unknown keys are kept.
+func mapCompositeValue(composite *schema.CompositeType, value any, toColumns bool) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return mapCompositeDocument(composite, v, toColumns)
+	case bson.M:
+		return mapCompositeDocument(composite, v, toColumns)
+	case bson.D:
+		mapped := make(bson.D, len(v))
+		for i, elem := range v {
+			key, value := mapCompositeKey(composite, elem.Key, elem.Value, toColumns)
+			mapped[i] = bson.E{Key: key, Value: value}
+		}
+		return mapped
+	case []any:
+		return mapCompositeList(composite, v, toColumns)
+	case bson.A:
+		return mapCompositeList(composite, v, toColumns)
+	default:
+		return value
+	}
+}
+
+func mapCompositeDocument(composite *schema.CompositeType, document map[string]any, toColumns bool) map[string]any {
+	mapped := make(map[string]any, len(document))
+	for key, value := range document {
+		key, value = mapCompositeKey(composite, key, value, toColumns)
+		mapped[key] = value
+	}
+	return mapped
+}
+
+func mapCompositeList(composite *schema.CompositeType, list []any, toColumns bool) []any {
+	mapped := make([]any, len(list))
+	for i, item := range list {
+		mapped[i] = mapCompositeValue(composite, item, toColumns)
+	}
+	return mapped
+}
+
+func mapCompositeKey(composite *schema.CompositeType, key string, value any, toColumns bool) (string, any) {
+	var field *schema.Field
+	if toColumns {
+		field = composite.GetFieldByName(key)
+	} else {
+		field = composite.GetFieldByColumnName(key)
+	}
+	if field == nil {
+		return key, value
+	}
+
+	if field.Composite != nil {
+		value = mapCompositeValue(field.Composite, value, toColumns)
+	}
+	if toColumns {
+		return field.GetColumnName(), value
+	}
+	return field.Name, value
+}
+
 // BuildMongoDBFilter builds a MongoDB filter from schema field names
 // This properly handles primary key field mapping to _id
 func (m *MongoDBFieldMapper) BuildMongoDBFilter(modelName string, conditions map[string]any) (bson.M, error) {
@@ -293,6 +465,9 @@ func (m *MongoDBFieldMapper) BuildMongoDBFilter(modelName string, conditions map
 			// Single primary key - directly map to _id
 			pkField := primaryKeyFields[0]
 			if value, exists := pkConditions[pkField.Name]; exists {
+				if m.db.holdsObjectID(schema, &pkField) {
+					value = objectIDValue(value)
+				}
 				filter["_id"] = value
 			}
 		} else if len(primaryKeyFields) > 1 {
@@ -367,7 +542,7 @@ func (m *MongoDBFieldMapper) GetAutoIncrementFieldName(modelName string) string
 
 // GetSequenceCollectionName returns the name of the sequence collection
 func (m *MongoDBFieldMapper) GetSequenceCollectionName() string {
-	return "redi_sequences"
+	return sequenceCollectionName
 }
 
 // GenerateNextSequenceValue generates the next sequence value for auto-increment fields
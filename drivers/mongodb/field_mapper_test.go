@@ -0,0 +1,396 @@
+package mongodb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rediwo/redi-orm/prisma"
+	"github.com/rediwo/redi-orm/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCompositeFieldMapping(t *testing.T) {
+	db, err := NewMongoDB("mongodb://localhost:27017/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemas, err := prisma.ParseSchema(`
+type Address {
+  zipCode String
+  geo     Geo?
+}
+
+type Geo {
+  lat Float @map("latitude")
+}
+
+model Customer {
+  id        Int       @id @default(autoincrement())
+  homeAddress Address
+  past      Address[] @map("past_addresses")
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.RegisterSchema("Customer", schemas["Customer"]); err != nil {
+		t.Fatal(err)
+	}
+	mapper := db.GetFieldMapper()
+
+	column, err := mapper.SchemaToColumn("Customer", "homeAddress.geo.lat")
+	if err != nil || column != "home_address.geo.latitude" {
+		t.Errorf("Expected the stored path, got %q (%v)", column, err)
+	}
+
+	data := map[string]any{
+		"id":          1,
+		"homeAddress": map[string]any{"zipCode": "75001", "geo": map[string]any{"lat": 48.8}},
+		"past":        []any{map[string]any{"zipCode": "69001"}},
+	}
+	document, err := mapper.MapSchemaToColumnData("Customer", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]any{
+		"_id":            1,
+		"home_address":   map[string]any{"zip_code": "75001", "geo": map[string]any{"latitude": 48.8}},
+		"past_addresses": []any{map[string]any{"zip_code": "69001"}},
+	}
+	if !reflect.DeepEqual(document, expected) {
+		t.Errorf("Expected %v, got %v", expected, document)
+	}
+
+	// Documents read back use the field names, whichever BSON type the driver decoded
+	stored := map[string]any{
+		"_id":            1,
+		"home_address":   bson.M{"zip_code": "75001", "geo": bson.D{{Key: "latitude", Value: 48.8}}},
+		"past_addresses": bson.A{bson.M{"zip_code": "69001"}},
+	}
+	record, err := mapper.MapColumnToSchemaData("Customer", stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address := record["homeAddress"].(map[string]any); address["zipCode"] != "75001" || address["geo"].(bson.D)[0].Key != "lat" {
+		t.Errorf("Expected mapped address, got %v", record["homeAddress"])
+	}
+	if past := record["past"].([]any); past[0].(map[string]any)["zipCode"] != "69001" {
+		t.Errorf("Expected mapped past addresses, got %v", record["past"])
+	}
+
+	// Dot paths filter and project on the stored keys
+	query := db.Model("Customer").
+		Select("homeAddress.zipCode").
+		WhereCondition(types.NewFieldCondition("Customer", "homeAddress.geo.lat").GreaterThan(40))
+	command, _, err := query.BuildSQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, part := range []string{`"home_address.geo.latitude":{"$gt":40}`, `"fields":["home_address.zip_code"]`} {
+		if !strings.Contains(command, part) {
+			t.Errorf("Expected %s in %s", part, command)
+		}
+	}
+}
+
+func TestBinaryFieldMapping(t *testing.T) {
+	db, err := NewMongoDB("mongodb://localhost:27017/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemas, err := prisma.ParseSchema(`
+model File {
+  id   Int    @id @default(autoincrement())
+  name String
+  data Bytes
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.RegisterSchema("File", schemas["File"]); err != nil {
+		t.Fatal(err)
+	}
+
+	// Base64 text and []byte are both written as binary data, which survives the JSON command
+	for _, value := range []any{"AAH/", []byte{0, 1, 255}} {
+		sql, _, err := db.Model("File").Insert(map[string]any{"id": 1, "name": "a.bin", "data": value}).BuildSQL()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var cmd MongoDBCommand
+		if err := cmd.FromJSON(sql); err != nil {
+			t.Fatal(err)
+		}
+		document := cmd.Documents[0].(map[string]any)
+		expected := primitive.Binary{Subtype: bson.TypeBinaryGeneric, Data: []byte{0, 1, 255}}
+		if !reflect.DeepEqual(document["data"], expected) {
+			t.Errorf("Expected binary data for %v, got %#v", value, document["data"])
+		}
+		if document["name"] != "a.bin" {
+			t.Errorf("Expected other fields unchanged, got %v", document["name"])
+		}
+	}
+
+	sql, _, err := db.Model("File").Update(map[string]any{"data": "aGk="}).BuildSQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cmd MongoDBCommand
+	if err := cmd.FromJSON(sql); err != nil {
+		t.Fatal(err)
+	}
+	if set := cmd.Update["$set"].(map[string]any); !reflect.DeepEqual(set["data"], primitive.Binary{Data: []byte("hi")}) {
+		t.Errorf("Expected binary data in $set, got %#v", set["data"])
+	}
+
+	if _, _, err := db.Model("File").Insert(map[string]any{"data": "not base64!"}).BuildSQL(); err == nil {
+		t.Error("Expected an error for invalid base64 data")
+	}
+
+	// Binary data read back is returned as []byte
+	record, err := db.GetFieldMapper().MapColumnToSchemaData("File", map[string]any{
+		"_id":  1,
+		"data": primitive.Binary{Data: []byte("hi")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, ok := record["data"].([]byte); !ok || string(data) != "hi" {
+		t.Errorf("Expected []byte, got %#v", record["data"])
+	}
+}
+
+func TestDecimalAndBigIntFieldMapping(t *testing.T) {
+	db, err := NewMongoDB("mongodb://localhost:27017/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemas, err := prisma.ParseSchema(`
+model Account {
+  id      Int     @id @default(autoincrement())
+  balance Decimal
+  total   BigInt
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.RegisterSchema("Account", schemas["Account"]); err != nil {
+		t.Fatal(err)
+	}
+
+	// Decimals are stored as BSON decimals and large integers stay exact through the JSON command
+	sql, _, err := db.Model("Account").Insert(map[string]any{
+		"id":      1,
+		"balance": "12345678901234567.89",
+		"total":   int64(9007199254740993),
+	}).BuildSQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cmd MongoDBCommand
+	if err := cmd.FromJSON(sql); err != nil {
+		t.Fatal(err)
+	}
+	document := cmd.Documents[0].(map[string]any)
+	if d, ok := document["balance"].(primitive.Decimal128); !ok || d.String() != "12345678901234567.89" {
+		t.Errorf("Expected a BSON decimal, got %#v", document["balance"])
+	}
+	if document["total"] != int64(9007199254740993) {
+		t.Errorf("Expected an exact int64, got %#v", document["total"])
+	}
+
+	if _, _, err := db.Model("Account").Insert(map[string]any{"balance": "ten"}).BuildSQL(); err == nil {
+		t.Error("Expected an error for an invalid decimal")
+	}
+
+	// BSON decimals read back are returned as their text
+	balance, _ := primitive.ParseDecimal128("19.99")
+	record, err := db.GetFieldMapper().MapColumnToSchemaData("Account", map[string]any{
+		"_id":     1,
+		"balance": balance,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record["balance"] != "19.99" {
+		t.Errorf("Expected decimal text, got %#v", record["balance"])
+	}
+}
+
+func TestDateTimeFieldMapping(t *testing.T) {
+	db, err := NewMongoDB("mongodb://localhost:27017/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemas, err := prisma.ParseSchema(`
+model Event {
+  id       Int      @id @default(autoincrement())
+  startsAt DateTime
+  day      DateTime @db.Date
+  opensAt  DateTime @db.Time
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.RegisterSchema("Event", schemas["Event"]); err != nil {
+		t.Fatal(err)
+	}
+
+	// Times are stored as BSON dates in UTC; @db.Date and @db.Time keep only the date or time of day
+	startsAt := time.Date(2024, 3, 1, 8, 30, 0, 0, time.FixedZone("UTC+2", 2*60*60))
+	sql, _, err := db.Model("Event").Insert(map[string]any{
+		"id":       1,
+		"startsAt": startsAt,
+		"day":      startsAt,
+		"opensAt":  "2024-03-01T08:30:00+02:00",
+	}).BuildSQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cmd MongoDBCommand
+	if err := cmd.FromJSON(sql); err != nil {
+		t.Fatal(err)
+	}
+	document := cmd.Documents[0].(map[string]any)
+	expected := map[string]time.Time{
+		"starts_at": time.Date(2024, 3, 1, 6, 30, 0, 0, time.UTC),
+		"day":       time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		"opens_at":  time.Date(1970, 1, 1, 6, 30, 0, 0, time.UTC),
+	}
+	for column, want := range expected {
+		if value, ok := document[column].(primitive.DateTime); !ok || !value.Time().Equal(want) {
+			t.Errorf("Expected BSON date %v for %s, got %#v", want, column, document[column])
+		}
+	}
+
+	// Filter values are compared as BSON dates too
+	selectQuery := db.Model("Event").Select()
+	sql, _, err = selectQuery.WhereCondition(selectQuery.Where("startsAt").GreaterThan(startsAt)).BuildSQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd = MongoDBCommand{}
+	if err := cmd.FromJSON(sql); err != nil {
+		t.Fatal(err)
+	}
+	condition, _ := cmd.Filter["starts_at"].(map[string]any)
+	if value, ok := condition["$gt"].(primitive.DateTime); !ok || !value.Time().Equal(startsAt) {
+		t.Errorf("Expected a BSON date in the filter, got %#v", cmd.Filter)
+	}
+
+	// BSON dates and dates written as text are read back in UTC
+	record, err := db.GetFieldMapper().MapColumnToSchemaData("Event", map[string]any{
+		"_id":       1,
+		"starts_at": primitive.NewDateTimeFromTime(startsAt),
+		"day":       "2024-03-01T23:00:00-02:00",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, ok := record["startsAt"].(time.Time); !ok || value.Location() != time.UTC || !value.Equal(startsAt) {
+		t.Errorf("Expected a UTC time, got %#v", record["startsAt"])
+	}
+	if record["day"] != time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("Expected the UTC date, got %#v", record["day"])
+	}
+}
+
+func TestIDStrategy(t *testing.T) {
+	db, err := NewMongoDB("mongodb://localhost:27017/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemas, err := prisma.ParseSchema(`
+model User {
+  id    String @id
+  posts Post[]
+}
+
+model Post {
+  id       String @id @default(uuid())
+  authorId String
+  author   User   @relation(fields: [authorId], references: [id])
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"User", "Post"} {
+		if err := db.RegisterSchema(name, schemas[name]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if db.modelIDStrategy(schemas["User"]) != IDStrategyObjectID || db.modelIDStrategy(schemas["Post"]) != IDStrategyUUID {
+		t.Fatal("Expected ObjectIDs for User and UUIDs for Post")
+	}
+
+	// Hex strings of ObjectID keys and the foreign keys referencing them are stored as ObjectIDs
+	oid := primitive.NewObjectID()
+	sql, _, err := db.Model("Post").Insert(map[string]any{"authorId": oid.Hex()}).BuildSQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cmd MongoDBCommand
+	if err := cmd.FromJSON(sql); err != nil {
+		t.Fatal(err)
+	}
+	document := cmd.Documents[0].(map[string]any)
+	if document["author_id"] != oid {
+		t.Errorf("Expected an ObjectID foreign key, got %#v", document["author_id"])
+	}
+	if id, ok := document["_id"].(string); !ok || len(id) != 36 {
+		t.Errorf("Expected a generated UUID, got %#v", document["_id"])
+	}
+
+	// Filters on them compare ObjectIDs
+	selectQuery := db.Model("User").Select()
+	sql, _, err = selectQuery.WhereCondition(selectQuery.Where("id").Equals(oid.Hex())).BuildSQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd = MongoDBCommand{}
+	if err := cmd.FromJSON(sql); err != nil {
+		t.Fatal(err)
+	}
+	if cmd.Filter["_id"] != oid {
+		t.Errorf("Expected an ObjectID in the filter, got %#v", cmd.Filter)
+	}
+
+	// Other strings are kept as they are
+	selectQuery = db.Model("User").Select()
+	sql, _, err = selectQuery.WhereCondition(selectQuery.Where("id").Equals("alice")).BuildSQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd = MongoDBCommand{}
+	if err := cmd.FromJSON(sql); err != nil {
+		t.Fatal(err)
+	}
+	if cmd.Filter["_id"] != "alice" {
+		t.Errorf("Expected the string in the filter, got %#v", cmd.Filter)
+	}
+
+	// ObjectIDs are read back as hex strings
+	record, err := db.GetFieldMapper().MapColumnToSchemaData("Post", map[string]any{"_id": "p1", "author_id": oid})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record["authorId"] != oid.Hex() {
+		t.Errorf("Expected a hex string, got %#v", record["authorId"])
+	}
+
+	// Under the UUID strategy, string keys are generated UUIDs
+	db.SetIDStrategy(IDStrategyUUID)
+	sql, _, err = db.Model("User").Insert(map[string]any{}).BuildSQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd = MongoDBCommand{}
+	if err := cmd.FromJSON(sql); err != nil {
+		t.Fatal(err)
+	}
+	if id, ok := cmd.Documents[0].(map[string]any)["_id"].(string); !ok || len(id) != 36 {
+		t.Errorf("Expected a generated UUID, got %#v", cmd.Documents[0])
+	}
+}
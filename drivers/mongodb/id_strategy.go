@@ -0,0 +1,121 @@
+package mongodb
+
+import (
+	"github.com/rediwo/redi-orm/schema"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IDStrategy sets how the string primary keys of models are stored and generated
+type IDStrategy string
+
+const (
+	// IDStrategyObjectID stores keys as ObjectIDs, generated when the data sets none. Records
+	// return them as hex strings, and hex strings written or compared with them are converted.
+	IDStrategyObjectID IDStrategy = "objectId"
+
+	// IDStrategyUUID stores keys as strings, generating UUIDs when the data sets none
+	IDStrategyUUID IDStrategy = "uuid"
+)
+
+// SetIDStrategy sets the ID strategy of the string primary keys of models that don't choose
+// one themselves, with @db.ObjectId or @default(uuid()). It defaults to IDStrategyObjectID.
+func (m *MongoDB) SetIDStrategy(strategy IDStrategy) {
+	m.idStrategy = strategy
+}
+
+// GetIDStrategy returns the ID strategy of models that don't choose one themselves
+func (m *MongoDB) GetIDStrategy() IDStrategy {
+	if m.idStrategy == "" {
+		return IDStrategyObjectID
+	}
+	return m.idStrategy
+}
+
+// modelIDStrategy returns the ID strategy of the primary key of s, or "" when its keys are
+// not strings, like auto-increment integers and composite keys
+func (m *MongoDB) modelIDStrategy(s *schema.Schema) IDStrategy {
+	if len(s.CompositeKey) > 0 {
+		return ""
+	}
+	primaryKey, err := s.GetPrimaryKey()
+	if err != nil {
+		return ""
+	}
+	switch {
+	case isObjectIDField(primaryKey):
+		return IDStrategyObjectID
+	case primaryKey.GeneratesUUID():
+		return IDStrategyUUID
+	case primaryKey.Type == schema.FieldTypeString && !primaryKey.AutoIncrement && primaryKey.Default == nil:
+		return m.GetIDStrategy()
+	default:
+		return ""
+	}
+}
+
+// holdsObjectID reports whether field of s stores ObjectIDs: fields of the ObjectId type or
+// @db.ObjectId, primary keys of the ObjectID strategy, and the foreign keys referencing them
+func (m *MongoDB) holdsObjectID(s *schema.Schema, field *schema.Field) bool {
+	if m.storesObjectID(s, field) {
+		return true
+	}
+	if field.Type != schema.FieldTypeString {
+		return false
+	}
+	for _, relation := range s.Relations {
+		if relation.Type != schema.RelationManyToOne && relation.Type != schema.RelationOneToOne {
+			continue
+		}
+		localField, relatedField := relation.JoinFields(s)
+		if localField != field.Name {
+			continue
+		}
+		related, err := m.GetSchema(relation.Model)
+		if err != nil {
+			continue
+		}
+		if target := related.GetFieldByName(relatedField); target != nil && m.storesObjectID(related, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// storesObjectID reports whether field of s stores ObjectIDs by its own type or by the ID
+// strategy of its model
+func (m *MongoDB) storesObjectID(s *schema.Schema, field *schema.Field) bool {
+	if isObjectIDField(field) {
+		return true
+	}
+	return field.PrimaryKey && m.modelIDStrategy(s) == IDStrategyObjectID
+}
+
+// isObjectIDField reports whether field is of the ObjectId type, or a string of @db.ObjectId
+func isObjectIDField(field *schema.Field) bool {
+	return field.Type == schema.FieldTypeObjectId ||
+		(field.Type == schema.FieldTypeString && field.NativeType() == "ObjectId")
+}
+
+// objectIDValue wraps the hex string of an ObjectID in an extended JSON $oid document, which
+// is stored as an ObjectID when the command is passed on as JSON. Other values are returned
+// unchanged.
+func objectIDValue(value any) any {
+	switch v := value.(type) {
+	case primitive.ObjectID:
+		return bson.M{"$oid": v.Hex()}
+	case string:
+		if _, err := primitive.ObjectIDFromHex(v); err == nil {
+			return bson.M{"$oid": v}
+		}
+	}
+	return value
+}
+
+// objectIDHex returns the hex string of an ObjectID; other values are returned unchanged
+func objectIDHex(value any) any {
+	if oid, ok := value.(primitive.ObjectID); ok {
+		return oid.Hex()
+	}
+	return value
+}
@@ -0,0 +1,278 @@
+package mongodb
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/types"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// includeNode is an included relation, with the relations included below it
+type includeNode struct {
+	name     string
+	path     string
+	children []*includeNode
+}
+
+// includeTree arranges the include paths of the query into a tree, in the order their
+// relations are first included. Paths only given as include options come last, sorted.
+func (q *MongoDBSelectQuery) includeTree() []*includeNode {
+	paths := slices.Clone(q.GetIncludes())
+	var optionPaths []string
+	for path := range q.GetIncludeOptions() {
+		if !slices.Contains(paths, path) {
+			optionPaths = append(optionPaths, path)
+		}
+	}
+	slices.Sort(optionPaths)
+	paths = append(paths, optionPaths...)
+
+	var roots []*includeNode
+	for _, path := range paths {
+		nodes := &roots
+		prefix := ""
+		for _, name := range strings.Split(path, ".") {
+			if prefix != "" {
+				prefix += "."
+			}
+			prefix += name
+
+			i := slices.IndexFunc(*nodes, func(n *includeNode) bool { return n.name == name })
+			if i < 0 {
+				*nodes = append(*nodes, &includeNode{name: name, path: prefix})
+				i = len(*nodes) - 1
+			}
+			nodes = &(*nodes)[i].children
+		}
+	}
+	return roots
+}
+
+// buildRelationLookup builds the $lookup of an included relation of modelName, whose
+// pipeline looks up the relations included below it in turn. Relations holding a single
+// record are unwound. Relations the model does not have are skipped.
+func (q *MongoDBSelectQuery) buildRelationLookup(modelName string, node *includeNode) ([]bson.M, error) {
+	currentSchema, err := q.db.GetSchema(modelName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema for model %s: %w", modelName, err)
+	}
+	relation, exists := currentSchema.Relations[node.name]
+	if !exists {
+		return nil, nil
+	}
+	if relation.IsPolymorphic() {
+		return nil, fmt.Errorf("relation %s of model %s is polymorphic, which MongoDB includes do not support", node.name, modelName)
+	}
+	if relation.Type == schema.RelationManyToMany {
+		return nil, fmt.Errorf("many-to-many relations are not yet supported in MongoDB includes")
+	}
+
+	relatedCollection, err := q.fieldMapper.ModelToTable(relation.Model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection name for model %s: %w", relation.Model, err)
+	}
+
+	references := relation.References
+	if references == "" {
+		references = "id"
+	}
+
+	// The foreign key is in the related collection for one-to-many relations and for
+	// one-to-one relations the other side holds
+	var localColumn, foreignColumn string
+	many := relation.Type == schema.RelationOneToMany
+	if many || (relation.Type == schema.RelationOneToOne && currentSchema.GetFieldByName(relation.ForeignKey) == nil) {
+		localColumn = q.lookupColumn(modelName, references)
+		foreignColumn = q.lookupColumn(relation.Model, relation.ForeignKey)
+
+		// For composite keys, MongoDB stores the foreign key under _id
+		if relatedSchema, err := q.db.GetSchema(relation.Model); err == nil && len(relatedSchema.CompositeKey) > 1 &&
+			slices.Contains(relatedSchema.CompositeKey, relation.ForeignKey) {
+			foreignColumn = "_id." + foreignColumn
+		}
+	} else {
+		localColumn = q.lookupColumn(modelName, relation.ForeignKey)
+		foreignColumn = q.lookupColumn(relation.Model, references)
+	}
+
+	opt := q.GetIncludeOptions()[node.path]
+	hasOptions := opt != nil && (opt.Where != nil || len(opt.OrderBy) > 0 || opt.Limit != nil || opt.Offset != nil || len(opt.Select) > 0)
+
+	var lookup bson.M
+	if !hasOptions && len(node.children) == 0 {
+		// Use simple $lookup without filtering
+		lookup = bson.M{
+			"from":         relatedCollection,
+			"localField":   localColumn,
+			"foreignField": foreignColumn,
+			"as":           node.name,
+		}
+	} else {
+		pipeline, err := q.relationPipeline(relation.Model, node, opt, foreignColumn)
+		if err != nil {
+			return nil, err
+		}
+		lookup = bson.M{
+			"from":     relatedCollection,
+			"let":      bson.M{"localField": "$" + localColumn},
+			"pipeline": pipeline,
+			"as":       node.name,
+		}
+	}
+
+	stages := []bson.M{{"$lookup": lookup}}
+	if !many {
+		// Convert the array to a single document
+		stages = append(stages, bson.M{
+			"$unwind": bson.M{
+				"path":                       "$" + node.name,
+				"preserveNullAndEmptyArrays": true,
+			},
+		})
+	}
+	return stages, nil
+}
+
+// relationPipeline builds the $lookup pipeline of an included relation to modelName:
+// the join condition, the include options, then the lookups of nested relations
+func (q *MongoDBSelectQuery) relationPipeline(modelName string, node *includeNode, opt *types.IncludeOption, foreignColumn string) ([]bson.M, error) {
+	pipeline := []bson.M{{
+		"$match": bson.M{
+			"$expr": bson.M{
+				"$eq": []any{"$" + foreignColumn, "$$localField"},
+			},
+		},
+	}}
+
+	if opt != nil {
+		if opt.Where != nil {
+			qb := NewMongoDBQueryBuilder(q.db)
+			whereFilter, err := qb.ConditionToFilter(opt.Where, modelName)
+			if err != nil {
+				return nil, fmt.Errorf("failed to build where filter for include %s: %w", node.path, err)
+			}
+			if len(whereFilter) > 0 {
+				pipeline = append(pipeline, bson.M{"$match": whereFilter})
+			}
+		}
+
+		if len(opt.OrderBy) > 0 {
+			orderBy := bson.D{}
+			for _, order := range opt.OrderBy {
+				direction := 1
+				if order.Direction == types.DESC {
+					direction = -1
+				}
+				orderBy = append(orderBy, bson.E{Key: q.lookupColumn(modelName, order.Field), Value: direction})
+			}
+			pipeline = append(pipeline, bson.M{"$sort": orderBy})
+		}
+
+		if opt.Offset != nil && *opt.Offset > 0 {
+			pipeline = append(pipeline, bson.M{"$skip": *opt.Offset})
+		}
+		if opt.Limit != nil && *opt.Limit > 0 {
+			pipeline = append(pipeline, bson.M{"$limit": *opt.Limit})
+		}
+	}
+
+	for _, child := range node.children {
+		stages, err := q.buildRelationLookup(modelName, child)
+		if err != nil {
+			return nil, err
+		}
+		pipeline = append(pipeline, stages...)
+	}
+
+	// Selected fields keep the nested relations, projected last so that their keys are
+	// still there when they are looked up
+	if opt != nil && len(opt.Select) > 0 {
+		projection := bson.M{}
+		for _, field := range opt.Select {
+			projection[q.lookupColumn(modelName, field)] = 1
+		}
+		for _, child := range node.children {
+			projection[child.name] = 1
+		}
+		pipeline = append(pipeline, bson.M{"$project": projection})
+	}
+
+	return pipeline, nil
+}
+
+// lookupColumn returns the column of a field in lookups, _id for the primary key
+func (q *MongoDBSelectQuery) lookupColumn(modelName, fieldName string) string {
+	column, err := q.fieldMapper.SchemaToColumn(modelName, fieldName)
+	if err != nil {
+		column = fieldName
+	}
+	if column == "id" {
+		column = "_id"
+	}
+	return column
+}
+
+// mapIncludedDocuments maps the column names of the documents of included relations of
+// modelName back to field names, level by level. Relations holding a single record are set
+// to it or nil, as $unwind leaves out relations without a match, and the others to arrays.
+func (q *MongoDBSelectQuery) mapIncludedDocuments(modelName string, document map[string]any, nodes []*includeNode) error {
+	currentSchema, err := q.db.GetSchema(modelName)
+	if err != nil {
+		return nil
+	}
+
+	for _, node := range nodes {
+		relation, exists := currentSchema.Relations[node.name]
+		if !exists {
+			continue
+		}
+
+		var documents []map[string]any
+		switch data := document[node.name].(type) {
+		case map[string]any:
+			documents = append(documents, data)
+		case []any:
+			for _, item := range data {
+				if itemMap, ok := item.(map[string]any); ok {
+					documents = append(documents, itemMap)
+				}
+			}
+		}
+
+		if relation.Type == schema.RelationOneToMany {
+			if _, ok := document[node.name].([]any); !ok {
+				document[node.name] = []any{}
+			}
+		} else {
+			document[node.name] = nil
+			if len(documents) > 0 {
+				documents = documents[:1]
+				document[node.name] = documents[0]
+			}
+		}
+
+		for _, related := range documents {
+			mappedData, err := q.fieldMapper.MapColumnToSchemaData(relation.Model, related)
+			if err != nil {
+				return fmt.Errorf("failed to map included fields for %s: %w", node.path, err)
+			}
+
+			// Replace contents
+			for key := range related {
+				delete(related, key)
+			}
+			for key, value := range mappedData {
+				related[key] = value
+			}
+
+			if err := q.mapIncludedDocuments(relation.Model, related, node.children); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
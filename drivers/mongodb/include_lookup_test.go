@@ -0,0 +1,131 @@
+package mongodb
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rediwo/redi-orm/prisma"
+)
+
+func TestNestedIncludeLookups(t *testing.T) {
+	db, err := NewMongoDB("mongodb://localhost:27017/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemas, err := prisma.ParseSchema(`
+model User {
+  id       Int       @id
+  name     String    @map("full_name")
+  posts    Post[]
+  comments Comment[]
+}
+
+model Post {
+  id       Int       @id
+  authorId Int
+  author   User      @relation(fields: [authorId], references: [id])
+  comments Comment[]
+}
+
+model Comment {
+  id       Int  @id
+  postId   Int
+  authorId Int
+  post     Post @relation(fields: [postId], references: [id])
+  author   User @relation(fields: [authorId], references: [id])
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, s := range schemas {
+		if err := db.RegisterSchema(name, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	query := db.Model("User").Select().Include("posts.comments.author", "posts.author").(*MongoDBSelectQuery)
+	command, _, err := query.BuildSQL()
+	if err != nil {
+		t.Fatalf("Failed to build the include command: %v", err)
+	}
+	var cmd struct {
+		Pipeline []map[string]any `json:"pipeline"`
+	}
+	if err := json.Unmarshal([]byte(command), &cmd); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each level looks up the next inside its pipeline, and single relations are unwound
+	lookupIn := func(stages []any, as string) (map[string]any, bool) {
+		for i, stage := range stages {
+			lookup, ok := stage.(map[string]any)["$lookup"].(map[string]any)
+			if !ok || lookup["as"] != as {
+				continue
+			}
+			unwound := false
+			if i+1 < len(stages) {
+				_, unwound = stages[i+1].(map[string]any)["$unwind"]
+			}
+			return lookup, unwound
+		}
+		t.Fatalf("Expected a $lookup of %s in %v", as, stages)
+		return nil, false
+	}
+	stages := make([]any, len(cmd.Pipeline))
+	for i, stage := range cmd.Pipeline {
+		stages[i] = stage
+	}
+	posts, unwound := lookupIn(stages, "posts")
+	if unwound {
+		t.Error("Expected posts not to be unwound")
+	}
+	comments, _ := lookupIn(posts["pipeline"].([]any), "comments")
+	if _, unwound := lookupIn(posts["pipeline"].([]any), "author"); !unwound {
+		t.Error("Expected posts.author to be unwound")
+	}
+	author, unwound := lookupIn(comments["pipeline"].([]any), "author")
+	if !unwound || author["from"] != "users" {
+		t.Errorf("Expected posts.comments.author to be an unwound lookup of users, got %v", author)
+	}
+
+	// Included documents are mapped to field names at every level
+	document := map[string]any{
+		"_id": 1,
+		"posts": []any{map[string]any{
+			"_id":       2,
+			"author_id": 1,
+			"comments": []any{map[string]any{
+				"_id":    3,
+				"author": map[string]any{"_id": 1, "full_name": "Alice"},
+			}, map[string]any{
+				"_id": 4,
+			}},
+		}},
+	}
+	if err := query.mapSingleDocumentFields("User", document); err != nil {
+		t.Fatal(err)
+	}
+	post := document["posts"].([]any)[0].(map[string]any)
+	if post["id"] != 2 || post["authorId"] != 1 {
+		t.Errorf("Expected the post to be mapped, got %v", post)
+	}
+	commentAuthor := post["comments"].([]any)[0].(map[string]any)["author"].(map[string]any)
+	if commentAuthor["id"] != 1 || commentAuthor["name"] != "Alice" {
+		t.Errorf("Expected the comment author to be mapped, got %v", commentAuthor)
+	}
+
+	// Single relations without a match are nil, as $unwind leaves them out
+	if author, exists := post["comments"].([]any)[1].(map[string]any)["author"]; !exists || author != nil {
+		t.Errorf("Expected a nil author for the comment without one, got %v", author)
+	}
+	if author, exists := post["author"]; !exists || author != nil {
+		t.Errorf("Expected a nil author for the post without one, got %v", author)
+	}
+
+	// Includes deeper than the limit fail
+	db.SetMaxIncludeDepth(2)
+	if _, _, err := query.BuildSQL(); err == nil || !strings.Contains(err.Error(), "more than the limit of 2") {
+		t.Errorf("Expected an include depth error, got %v", err)
+	}
+}
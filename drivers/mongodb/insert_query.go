@@ -8,6 +8,7 @@ import (
 
 	"github.com/rediwo/redi-orm/query"
 	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
@@ -48,6 +49,19 @@ func (q *MongoDBInsertQuery) Values(data ...any) types.InsertQuery {
 	}
 }
 
+// OnConflict sets the conflict resolution action
+func (q *MongoDBInsertQuery) OnConflict(action types.ConflictAction) types.InsertQuery {
+	newBase := q.InsertQueryImpl.OnConflict(action)
+	return &MongoDBInsertQuery{
+		InsertQueryImpl: newBase.(*query.InsertQueryImpl),
+		data:            q.data,
+		db:              q.db,
+		fieldMapper:     q.fieldMapper,
+		modelName:       q.modelName,
+		lastInsertID:    q.lastInsertID,
+	}
+}
+
 // GetModelName returns the model name from the base query
 func (q *MongoDBInsertQuery) GetModelName() string {
 	if q.InsertQueryImpl == nil {
@@ -56,6 +70,11 @@ func (q *MongoDBInsertQuery) GetModelName() string {
 	return q.InsertQueryImpl.GetModelName()
 }
 
+// ToSQL returns the MongoDB command of the query, without executing it
+func (q *MongoDBInsertQuery) ToSQL() (string, []any, error) {
+	return q.BuildSQL()
+}
+
 // BuildSQL builds a MongoDB insert command instead of SQL
 func (q *MongoDBInsertQuery) BuildSQL() (string, []any, error) {
 	if len(q.data) == 0 {
@@ -85,6 +104,10 @@ func (q *MongoDBInsertQuery) BuildSQL() (string, []any, error) {
 		Documents:    documents,
 		LastInsertID: q.lastInsertID,
 	}
+	if q.GetConflictAction() == types.ConflictDoNothing {
+		// An unordered insert keeps going past duplicate key errors, which are then ignored
+		cmd.Options = bson.M{"ordered": false, "skipDuplicates": true}
+	}
 
 	// Convert to JSON
 	jsonCmd, err := cmd.ToJSON()
@@ -97,41 +120,14 @@ func (q *MongoDBInsertQuery) BuildSQL() (string, []any, error) {
 
 // convertToDocument converts input data to a MongoDB document
 func (q *MongoDBInsertQuery) convertToDocument(data any) (bson.M, error) {
-	// Convert data to map
-	var dataMap map[string]any
-	switch v := data.(type) {
-	case map[string]any:
-		dataMap = v
-	case bson.M:
-		dataMap = v
-	default:
-		// Use reflection to convert struct to map
-		dataMap = make(map[string]any)
-		val := reflect.ValueOf(data)
-		if val.Kind() == reflect.Ptr {
-			val = val.Elem()
-		}
-		if val.Kind() != reflect.Struct {
-			return nil, fmt.Errorf("unsupported data type: %T", data)
-		}
-
-		typ := val.Type()
-		for i := 0; i < val.NumField(); i++ {
-			field := typ.Field(i)
-			fieldName := field.Name
-
-			// Check for db tag
-			if tag := field.Tag.Get("db"); tag != "" && tag != "-" {
-				fieldName = tag
-			} else if tag := field.Tag.Get("json"); tag != "" && tag != "-" {
-				fieldName = tag
-			}
-
-			fieldValue := val.Field(i).Interface()
-			if !val.Field(i).IsZero() {
-				dataMap[fieldName] = fieldValue
-			}
-		}
+	dataMap, err := documentData(data)
+	if err != nil {
+		return nil, err
+	}
+	// Omitted lists are empty, as with the SQL drivers
+	dataMap = query.WithListDefaults(q.db, q.modelName, dataMap).(map[string]any)
+	if err := query.ValidateData(q.db, q.modelName, dataMap); err != nil {
+		return nil, err
 	}
 
 	// Use MongoDB field mapper for proper _id handling
@@ -144,6 +140,16 @@ func (q *MongoDBInsertQuery) convertToDocument(data any) (bson.M, error) {
 	if schema, err := q.db.GetSchema(q.modelName); err == nil {
 		// First, apply default values for fields not provided
 		for _, field := range schema.Fields {
+			if _, exists := dataMap[field.Name]; !exists && field.UpdatedAt {
+				dataMap[field.Name] = time.Now()
+				continue
+			}
+			if _, exists := dataMap[field.Name]; !exists &&
+				(field.GeneratesUUID() || (field.PrimaryKey && q.db.modelIDStrategy(schema) == IDStrategyUUID)) {
+				// UUIDs are stored as strings rather than ObjectIDs
+				dataMap[field.Name] = utils.NewUUID()
+				continue
+			}
 			if _, exists := dataMap[field.Name]; !exists && field.Default != nil {
 				// Apply default value
 				switch v := field.Default.(type) {
@@ -188,6 +194,10 @@ func (q *MongoDBInsertQuery) convertToDocument(data any) (bson.M, error) {
 
 // Exec executes the insert query
 func (q *MongoDBInsertQuery) Exec(ctx context.Context) (types.Result, error) {
+	ctx, cancel := q.StatementContext(ctx)
+	defer cancel()
+	defer types.ForgetDeduplicated(ctx)
+
 	sql, args, err := q.BuildSQL()
 	if err != nil {
 		return types.Result{}, fmt.Errorf("failed to build MongoDB command: %w", err)
@@ -249,3 +259,44 @@ func (q *MongoDBInsertQuery) GetDatabase() types.Database {
 	}
 	return nil
 }
+
+// documentData converts a map or struct to a map keyed by schema field names
+func documentData(data any) (map[string]any, error) {
+	// Convert data to map
+	var dataMap map[string]any
+	switch v := data.(type) {
+	case map[string]any:
+		dataMap = v
+	case bson.M:
+		dataMap = v
+	default:
+		// Use reflection to convert struct to map
+		dataMap = make(map[string]any)
+		val := reflect.ValueOf(data)
+		if val.Kind() == reflect.Ptr {
+			val = val.Elem()
+		}
+		if val.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("unsupported data type: %T", data)
+		}
+
+		typ := val.Type()
+		for i := 0; i < val.NumField(); i++ {
+			field := typ.Field(i)
+			fieldName := field.Name
+
+			// Check for db tag
+			if tag := field.Tag.Get("db"); tag != "" && tag != "-" {
+				fieldName = tag
+			} else if tag := field.Tag.Get("json"); tag != "" && tag != "-" {
+				fieldName = tag
+			}
+
+			fieldValue := val.Field(i).Interface()
+			if !val.Field(i).IsZero() {
+				dataMap[fieldName] = fieldValue
+			}
+		}
+	}
+	return dataMap, nil
+}
@@ -0,0 +1,59 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rediwo/redi-orm/base"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// migrationLockCollection holds the document of the process migrating the database
+const migrationLockCollection = "redi_migration_lock"
+
+// LockMigrations takes the migration lock by inserting its document, whose unique _id makes
+// other processes wait. Locks older than base.StaleMigrationLockAge are left by processes that
+// crashed while migrating, and taken over.
+func (m *MongoDB) LockMigrations(ctx context.Context, timeout time.Duration) (func() error, error) {
+	if m.client == nil {
+		return nil, fmt.Errorf("not connected to MongoDB")
+	}
+
+	collection := m.client.Database(m.dbName).Collection(migrationLockCollection)
+	owner := base.MigrationLockOwner()
+	err := base.WaitForMigrationLock(ctx, timeout, func() (bool, error) {
+		_, err := collection.InsertOne(ctx, bson.M{"_id": "migrations", "owner": owner, "lockedAt": time.Now()})
+		if err == nil {
+			return true, nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return false, err
+		}
+
+		var lock struct {
+			LockedAt time.Time `bson:"lockedAt"`
+		}
+		if err := collection.FindOne(ctx, bson.M{"_id": "migrations"}).Decode(&lock); err != nil {
+			if err == mongo.ErrNoDocuments {
+				// Released in the meantime
+				return false, nil
+			}
+			return false, err
+		}
+		if time.Since(lock.LockedAt) > base.StaleMigrationLockAge {
+			_, err := collection.DeleteOne(ctx, bson.M{"_id": "migrations", "lockedAt": lock.LockedAt})
+			return false, err
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		_, err := collection.DeleteOne(context.Background(), bson.M{"_id": "migrations", "owner": owner})
+		return err
+	}, nil
+}
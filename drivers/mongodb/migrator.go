@@ -345,8 +345,8 @@ func (m *MongoDBMigrator) MapDatabaseTypeToFieldType(dbType string) schema.Field
 		return schema.FieldTypeDateTime
 	case "objectid":
 		return schema.FieldTypeObjectId
-	case "bindata", "binary":
-		return schema.FieldTypeBinary
+	case "bindata", "binary", "bytes":
+		return schema.FieldTypeBytes
 	case "object", "document":
 		return schema.FieldTypeDocument
 	case "array":
@@ -3,6 +3,7 @@ package mongodb
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/rediwo/redi-orm/query"
 	"github.com/rediwo/redi-orm/types"
@@ -49,6 +50,11 @@ func (q *MongoDBModelQuery) Delete() types.DeleteQuery {
 	return NewMongoDBDeleteQuery(q.ModelQueryImpl, q.db, q.fieldMapper, q.modelName)
 }
 
+// Upsert creates a MongoDB-specific upsert query
+func (q *MongoDBModelQuery) Upsert(create any, update any, conflictFields ...string) types.UpsertQuery {
+	return NewMongoDBUpsertQuery(q.ModelQueryImpl, create, update, conflictFields, q.db, q.fieldMapper, q.modelName)
+}
+
 // Aggregate creates a MongoDB-specific aggregation query
 func (q *MongoDBModelQuery) Aggregate() types.AggregationQuery {
 	return NewMongoDBaggregationQuery(q.ModelQueryImpl, q.db, q.fieldMapper, q.modelName)
@@ -82,6 +88,36 @@ func (q *MongoDBModelQuery) WhereRaw(sql string, args ...any) types.ModelQuery {
 	}
 }
 
+func (q *MongoDBModelQuery) OrWhere(condition types.Condition) types.ModelQuery {
+	return q.withBase(q.ModelQueryImpl.OrWhere(condition))
+}
+
+func (q *MongoDBModelQuery) WhereNot(condition types.Condition) types.ModelQuery {
+	return q.withBase(q.ModelQueryImpl.WhereNot(condition))
+}
+
+func (q *MongoDBModelQuery) WhereAny(conditions ...types.Condition) types.ModelQuery {
+	return q.withBase(q.ModelQueryImpl.WhereAny(conditions...))
+}
+
+// WhereGroup builds the group on a MongoDB model query, for MongoDB-specific conditions
+func (q *MongoDBModelQuery) WhereGroup(fn func(group types.ModelQuery) types.ModelQuery) types.ModelQuery {
+	group := fn(NewMongoDBModelQuery(q.db, q.modelName))
+	return q.withBase(q.ModelQueryImpl.WhereGroup(func(types.ModelQuery) types.ModelQuery {
+		return group
+	}))
+}
+
+// withBase returns a MongoDB model query of base
+func (q *MongoDBModelQuery) withBase(base types.ModelQuery) types.ModelQuery {
+	return &MongoDBModelQuery{
+		ModelQueryImpl: base.(*query.ModelQueryImpl),
+		db:             q.db,
+		fieldMapper:    q.fieldMapper,
+		modelName:      q.modelName,
+	}
+}
+
 func (q *MongoDBModelQuery) Include(relations ...string) types.ModelQuery {
 	newBase := q.ModelQueryImpl.Include(relations...).(*query.ModelQueryImpl)
 	return &MongoDBModelQuery{
@@ -146,6 +182,16 @@ func (q *MongoDBModelQuery) Offset(offset int) types.ModelQuery {
 	}
 }
 
+func (q *MongoDBModelQuery) WithTimeout(timeout time.Duration) types.ModelQuery {
+	newBase := q.ModelQueryImpl.WithTimeout(timeout).(*query.ModelQueryImpl)
+	return &MongoDBModelQuery{
+		ModelQueryImpl: newBase,
+		db:             q.db,
+		fieldMapper:    q.fieldMapper,
+		modelName:      q.modelName,
+	}
+}
+
 // Override execution methods to ensure MongoDB-specific queries are used
 func (q *MongoDBModelQuery) Count(ctx context.Context) (int64, error) {
 	// Create a select query that preserves all conditions from the model query
@@ -232,6 +278,9 @@ func (q *MongoDBModelQuery) Max(ctx context.Context, fieldName string) (any, err
 
 // aggregateField performs aggregation on a field using MongoDB aggregation pipeline
 func (q *MongoDBModelQuery) aggregateField(ctx context.Context, fieldName, operation string) (any, error) {
+	ctx, cancel := q.StatementContext(ctx)
+	defer cancel()
+
 	// Get collection name
 	tableName, err := q.fieldMapper.ModelToTable(q.modelName)
 	if err != nil {
@@ -262,14 +311,11 @@ func (q *MongoDBModelQuery) aggregateField(ctx context.Context, fieldName, opera
 			}
 		}
 
-		filter, err := qb.ConditionToFilter(combined, q.modelName)
+		stages, err := qb.ConditionToPipeline(combined, q.modelName)
 		if err != nil {
 			return nil, fmt.Errorf("failed to build filter: %w", err)
 		}
-
-		if len(filter) > 0 {
-			pipeline = append(pipeline, bson.M{"$match": filter})
-		}
+		pipeline = append(pipeline, stages...)
 	}
 
 	// Add aggregation stage
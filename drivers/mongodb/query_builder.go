@@ -5,13 +5,20 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/rediwo/redi-orm/schema"
 	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
 // MongoDBQueryBuilder converts SQL-like queries to MongoDB operations
 type MongoDBQueryBuilder struct {
 	db *MongoDB
+
+	// Relation conditions are matched on related documents joined by $lookup stages, which
+	// only aggregation pipelines can hold
+	withLookups bool
+	lookups     []bson.M
 }
 
 // NewMongoDBQueryBuilder creates a new query builder
@@ -70,6 +77,10 @@ func (qb *MongoDBQueryBuilder) conditionToFilterInternal(condition types.Conditi
 		return qb.handleNotCondition(c, ctx)
 	case *types.MappedFieldCondition:
 		return qb.handleMappedFieldCondition(c, ctx)
+	case *types.ArrayFieldCondition:
+		return qb.handleArrayFieldCondition(c, ctx)
+	case *types.RelationCondition:
+		return qb.handleRelationCondition(c, ctx)
 	default:
 		// Try to convert using the condition's ToSQL method and parse it
 		if ctx == nil || ctx.ModelName == "" || qb.db == nil {
@@ -262,6 +273,113 @@ func (qb *MongoDBQueryBuilder) handleNotCondition(cond *types.NotCondition, ctx
 	return result, nil
 }
 
+// handleArrayFieldCondition handles list filters (has, hasSome, hasEvery, isEmpty)
+func (qb *MongoDBQueryBuilder) handleArrayFieldCondition(cond *types.ArrayFieldCondition, ctx *MongoDBConditionContext) (bson.M, error) {
+	if cond == nil || ctx == nil || qb.db == nil {
+		return bson.M{}, nil
+	}
+
+	modelName := cond.ModelName
+	if modelName == "" {
+		modelName = ctx.ModelName
+	}
+	columnName, err := qb.db.GetFieldMapper().SchemaToColumn(modelName, cond.FieldName)
+	if err != nil {
+		// Use field name as-is if mapping fails
+		columnName = cond.FieldName
+	}
+	return arrayFilter(columnName, cond.Operator, cond.Value), nil
+}
+
+// handleRelationCondition joins the documents of the relation with a $lookup stage, limited
+// to the first one matching the nested condition, and filters on whether one was found
+func (qb *MongoDBQueryBuilder) handleRelationCondition(cond *types.RelationCondition, ctx *MongoDBConditionContext) (bson.M, error) {
+	if cond == nil || ctx == nil || qb.db == nil {
+		return bson.M{}, nil
+	}
+	if !qb.withLookups {
+		return nil, fmt.Errorf("filtering on relation %s is only supported in find queries on MongoDB", cond.Relation)
+	}
+
+	modelName := cond.ModelName
+	if modelName == "" {
+		modelName = ctx.ModelName
+	}
+	localSchema, err := qb.db.GetSchema(modelName)
+	if err != nil {
+		return nil, err
+	}
+	relation, err := localSchema.GetRelation(cond.Relation)
+	if err != nil {
+		return nil, err
+	}
+	if relation.Type != schema.RelationManyToOne && relation.Type != schema.RelationOneToOne {
+		return nil, fmt.Errorf("relation %s of model %s is not a to-one relation", cond.Relation, modelName)
+	}
+	collection, err := qb.db.GetFieldMapper().ModelToTable(relation.Model)
+	if err != nil {
+		return nil, err
+	}
+
+	localField, relatedField := relation.JoinFields(localSchema)
+	pipeline := []bson.M{{"$match": bson.M{"$expr": bson.M{
+		"$eq": []string{"$" + qb.documentColumn(relation.Model, relatedField), "$$localValue"},
+	}}}}
+	if cond.Condition != nil {
+		stages, err := qb.ConditionToPipeline(cond.Condition, relation.Model)
+		if err != nil {
+			return nil, err
+		}
+		pipeline = append(pipeline, stages...)
+	}
+	pipeline = append(pipeline, bson.M{"$limit": 1})
+
+	as := fmt.Sprintf("__rel_%d", len(qb.lookups))
+	qb.lookups = append(qb.lookups, bson.M{"$lookup": bson.M{
+		"from":     collection,
+		"let":      bson.M{"localValue": "$" + qb.documentColumn(modelName, localField)},
+		"pipeline": pipeline,
+		"as":       as,
+	}})
+	return bson.M{as + ".0": bson.M{"$exists": cond.Exists()}}, nil
+}
+
+// documentColumn returns the name of the document field storing fieldName of modelName
+func (qb *MongoDBQueryBuilder) documentColumn(modelName, fieldName string) string {
+	column, err := qb.db.GetFieldMapper().SchemaToColumn(modelName, fieldName)
+	if err != nil {
+		column = fieldName
+	}
+	if column == "id" {
+		column = "_id"
+	}
+	return column
+}
+
+// ConditionToPipeline converts condition to the stages of an aggregation pipeline filtering
+// documents: the $lookup stages joining the relations it filters on, a $match stage, and a
+// $project stage removing the joined documents again
+func (qb *MongoDBQueryBuilder) ConditionToPipeline(condition types.Condition, modelName string) ([]bson.M, error) {
+	builder := &MongoDBQueryBuilder{db: qb.db, withLookups: true}
+	filter, err := builder.ConditionToFilter(condition, modelName)
+	if err != nil {
+		return nil, err
+	}
+
+	stages := builder.lookups
+	if len(filter) > 0 {
+		stages = append(stages, bson.M{"$match": filter})
+	}
+	if len(builder.lookups) > 0 {
+		project := bson.M{}
+		for i := range builder.lookups {
+			project[fmt.Sprintf("__rel_%d", i)] = 0
+		}
+		stages = append(stages, bson.M{"$project": project})
+	}
+	return stages, nil
+}
+
 // handleMappedFieldCondition converts field-specific conditions
 func (qb *MongoDBQueryBuilder) handleMappedFieldCondition(cond *types.MappedFieldCondition, ctx *MongoDBConditionContext) (bson.M, error) {
 	if cond == nil || ctx == nil || qb.db == nil {
@@ -290,6 +408,10 @@ func (qb *MongoDBQueryBuilder) handleMappedFieldCondition(cond *types.MappedFiel
 		columnName = fieldName
 	}
 
+	// Values compared with the field are converted to their stored form; LIKE patterns are
+	// matched against text and keep the original arguments
+	values := mapFilterValues(qb.db, ctx.ModelName, fieldName, args)
+
 	// Analyze the SQL to determine the operation type
 	sqlUpper := strings.ToUpper(sql)
 
@@ -297,19 +419,24 @@ func (qb *MongoDBQueryBuilder) handleMappedFieldCondition(cond *types.MappedFiel
 	// NOTE: Check NOT IN before IN to avoid false matches
 	if strings.Contains(sqlUpper, "NOT IN (") {
 		// Handle NOT IN operation: "name NOT IN (?,?,?)" with args [val1, val2, val3]
-		fmt.Printf("[MongoDB Query] NOT IN operation: field=%s, column=%s, args=%v\n", fieldName, columnName, args)
-		return bson.M{columnName: bson.M{"$nin": args}}, nil
+		qb.db.Log().Debug("NOT IN operation: field=%s, column=%s, args=%v", fieldName, columnName, args)
+		return bson.M{columnName: bson.M{"$nin": values}}, nil
 
 	} else if strings.Contains(sqlUpper, " IN (") {
 		// Handle IN operation: "name IN (?,?,?)" with args [val1, val2, val3]
-		fmt.Printf("[MongoDB Query] IN operation: field=%s, column=%s, args=%v\n", fieldName, columnName, args)
-		return bson.M{columnName: bson.M{"$in": args}}, nil
+		qb.db.Log().Debug("IN operation: field=%s, column=%s, args=%v", fieldName, columnName, args)
+		return bson.M{columnName: bson.M{"$in": values}}, nil
 
 	} else if strings.Contains(sqlUpper, " LIKE ") {
 		// Handle LIKE operation: "name LIKE ?" with args ["%pattern%"]
 		if len(args) > 0 {
 			pattern := fmt.Sprintf("%v", args[0])
 
+			// Patterns of contains, startsWith and endsWith escape the wildcards of their value
+			if strings.Contains(sqlUpper, " ESCAPE ") {
+				return bson.M{columnName: bson.M{"$regex": utils.LikeToRegexp(pattern, utils.LikeEscape)}}, nil
+			}
+
 			// Detect string operation patterns and convert appropriately
 			if strings.HasPrefix(pattern, "%") && !strings.HasSuffix(pattern, "%") {
 				// EndsWith: %value -> value$
@@ -349,43 +476,43 @@ func (qb *MongoDBQueryBuilder) handleMappedFieldCondition(cond *types.MappedFiel
 
 	} else if strings.Contains(sqlUpper, " = ") {
 		// Handle equality: "field = ?"
-		if len(args) > 0 {
-			if args[0] == nil {
+		if len(values) > 0 {
+			if values[0] == nil {
 				return bson.M{columnName: nil}, nil
 			}
-			return bson.M{columnName: args[0]}, nil
+			return bson.M{columnName: values[0]}, nil
 		}
 		return bson.M{columnName: nil}, nil
 
 	} else if strings.Contains(sqlUpper, " != ") || strings.Contains(sqlUpper, " <> ") {
 		// Handle inequality: "field != ?" or "field <> ?"
-		if len(args) > 0 {
-			return bson.M{columnName: bson.M{"$ne": args[0]}}, nil
+		if len(values) > 0 {
+			return bson.M{columnName: bson.M{"$ne": values[0]}}, nil
 		}
 		return bson.M{columnName: bson.M{"$ne": nil}}, nil
 
 	} else if strings.Contains(sqlUpper, " > ") {
 		// Handle greater than: "field > ?"
-		if len(args) > 0 {
-			return bson.M{columnName: bson.M{"$gt": args[0]}}, nil
+		if len(values) > 0 {
+			return bson.M{columnName: bson.M{"$gt": values[0]}}, nil
 		}
 
 	} else if strings.Contains(sqlUpper, " >= ") {
 		// Handle greater than or equal: "field >= ?"
-		if len(args) > 0 {
-			return bson.M{columnName: bson.M{"$gte": args[0]}}, nil
+		if len(values) > 0 {
+			return bson.M{columnName: bson.M{"$gte": values[0]}}, nil
 		}
 
 	} else if strings.Contains(sqlUpper, " < ") {
 		// Handle less than: "field < ?"
-		if len(args) > 0 {
-			return bson.M{columnName: bson.M{"$lt": args[0]}}, nil
+		if len(values) > 0 {
+			return bson.M{columnName: bson.M{"$lt": values[0]}}, nil
 		}
 
 	} else if strings.Contains(sqlUpper, " <= ") {
 		// Handle less than or equal: "field <= ?"
-		if len(args) > 0 {
-			return bson.M{columnName: bson.M{"$lte": args[0]}}, nil
+		if len(values) > 0 {
+			return bson.M{columnName: bson.M{"$lte": values[0]}}, nil
 		}
 	}
 
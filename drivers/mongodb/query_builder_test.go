@@ -0,0 +1,144 @@
+package mongodb
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rediwo/redi-orm/prisma"
+	"github.com/rediwo/redi-orm/types"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestRelationConditionLookups(t *testing.T) {
+	db, err := NewMongoDB("mongodb://localhost:27017/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemas, err := prisma.ParseSchema(`
+model User {
+  id    Int    @id
+  name  String @map("full_name")
+  posts Post[]
+}
+
+model Post {
+  id       Int   @id
+  authorId Int?
+  author   User? @relation(fields: [authorId], references: [id])
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, s := range schemas {
+		if err := db.RegisterSchema(name, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	post := db.Model("Post")
+	condition := types.Is("author", types.NewFieldCondition("", "name").Equals("Alice"))
+	command, _, err := post.Select().WhereCondition(condition).BuildSQL()
+	if err != nil {
+		t.Fatalf("Failed to build the find command: %v", err)
+	}
+	var cmd struct {
+		Operation string           `json:"operation"`
+		Pipeline  []map[string]any `json:"pipeline"`
+	}
+	if err := json.Unmarshal([]byte(command), &cmd); err != nil {
+		t.Fatal(err)
+	}
+
+	// The related documents are looked up, matched, and projected away again
+	if cmd.Operation != "aggregate" || len(cmd.Pipeline) != 3 {
+		t.Fatalf("Expected a pipeline of $lookup, $match and $project, got %s", command)
+	}
+	lookup, _ := cmd.Pipeline[0]["$lookup"].(map[string]any)
+	if lookup["from"] != "users" || lookup["as"] != "__rel_0" {
+		t.Errorf("Unexpected $lookup %v", lookup)
+	}
+	if let, _ := lookup["let"].(map[string]any); let["localValue"] != "$author_id" {
+		t.Errorf("Expected the lookup to join on author_id, got %v", lookup["let"])
+	}
+	pipeline, _ := json.Marshal(lookup["pipeline"])
+	if !strings.Contains(string(pipeline), `"$_id"`) || !strings.Contains(string(pipeline), `"full_name":"Alice"`) {
+		t.Errorf("Expected the lookup pipeline to match _id and full_name, got %s", pipeline)
+	}
+	match, _ := json.Marshal(cmd.Pipeline[1]["$match"])
+	if string(match) != `{"__rel_0.0":{"$exists":true}}` {
+		t.Errorf("Unexpected $match %s", match)
+	}
+	if _, ok := cmd.Pipeline[2]["$project"]; !ok {
+		t.Errorf("Expected a $project stage, got %v", cmd.Pipeline[2])
+	}
+
+	// Updates filter without a pipeline, so relation conditions are rejected
+	if _, _, err := post.Update(map[string]any{"authorId": 1}).WhereCondition(condition).BuildSQL(); err == nil {
+		t.Error("Expected updates filtering on relations to fail")
+	}
+}
+
+func TestComputedSortStages(t *testing.T) {
+	db, err := NewMongoDB("mongodb://localhost:27017/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemas, err := prisma.ParseSchema(`
+model User {
+  id    Int     @id
+  name  String? @map("full_name")
+  posts Post[]
+}
+
+model Post {
+  id       Int @id
+  authorId Int
+  author   User @relation(fields: [authorId], references: [id])
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, s := range schemas {
+		if err := db.RegisterSchema(name, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	command, _, err := db.Model("User").Select().
+		OrderByRelationCount("posts", types.DESC).
+		OrderByNulls("name", types.ASC, types.NullsFirst).BuildSQL()
+	if err != nil {
+		t.Fatalf("Failed to build the find command: %v", err)
+	}
+
+	// The sort keys survive the command passing through JSON in order
+	var cmd MongoDBCommand
+	if err := cmd.FromJSON(command); err != nil {
+		t.Fatal(err)
+	}
+	if cmd.Operation != "aggregate" || len(cmd.Pipeline) != 4 {
+		t.Fatalf("Expected a pipeline of $lookup, $addFields, $sort and $project, got %s", command)
+	}
+	lookup, _ := cmd.Pipeline[0]["$lookup"].(map[string]any)
+	if lookup["from"] != "posts" || lookup["localField"] != "_id" || lookup["foreignField"] != "author_id" {
+		t.Errorf("Unexpected $lookup %v", lookup)
+	}
+	sort, ok := cmd.Pipeline[2]["$sort"].(bson.D)
+	if !ok {
+		t.Fatalf("Expected an ordered $sort, got %#v", cmd.Pipeline[2])
+	}
+	var keys []string
+	for _, elem := range sort {
+		keys = append(keys, fmt.Sprintf("%s:%v", elem.Key, elem.Value))
+	}
+	if strings.Join(keys, " ") != "__count_0:-1 __nulls_1:-1 full_name:1" {
+		t.Errorf("Unexpected sort keys %v", keys)
+	}
+
+	// Only one-to-many relations are counted
+	if _, _, err := db.Model("Post").Select().OrderByRelationCount("author", types.ASC).BuildSQL(); err == nil {
+		t.Error("Expected ordering by the count of a many-to-one relation to fail")
+	}
+}
@@ -2,13 +2,17 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/rediwo/redi-orm/base"
+	"github.com/rediwo/redi-orm/logger"
 	"github.com/rediwo/redi-orm/sql"
 	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -35,8 +39,17 @@ func NewMongoDBRawQuery(database *mongo.Database, session mongo.Session, mongoDb
 	}
 }
 
+// log returns the logger of the database, which logs nothing without one
+func (q *MongoDBRawQuery) log() logger.Logger {
+	if q.mongoDb == nil {
+		return logger.NewNullLogger()
+	}
+	return q.mongoDb.Log()
+}
+
 // Exec executes a MongoDB command
 func (q *MongoDBRawQuery) Exec(ctx context.Context) (types.Result, error) {
+	defer types.ForgetDeduplicated(ctx)
 	// Check if input is SQL statement
 	if sql.DetectSQL(q.command) {
 		return q.executeSQLCommand(ctx)
@@ -45,8 +58,7 @@ func (q *MongoDBRawQuery) Exec(ctx context.Context) (types.Result, error) {
 	// Parse as MongoDB JSON command
 	var cmd MongoDBCommand
 	if err := cmd.FromJSON(q.command); err != nil {
-		// Log the command for debugging
-		fmt.Printf("[MongoDB] Failed to parse command: %s\n", q.command)
+		q.log().Error("Failed to parse command: %s", q.command)
 		return types.Result{}, fmt.Errorf("failed to parse MongoDB command: %w", err)
 	}
 
@@ -108,9 +120,7 @@ func (q *MongoDBRawQuery) FindOne(ctx context.Context, dest any) error {
 	var cmd MongoDBCommand
 	if err := cmd.FromJSON(q.command); err != nil {
 		// Log the command for debugging
-		if q.mongoDb != nil && q.mongoDb.GetLogger() != nil {
-			q.mongoDb.GetLogger().Error("MongoDB FindOne - Failed to parse command: %s", q.command)
-		}
+		q.log().Error("FindOne: failed to parse command: %s", q.command)
 		return fmt.Errorf("failed to parse MongoDB command: %w", err)
 	}
 
@@ -149,26 +159,35 @@ func (q *MongoDBRawQuery) executeInsert(ctx context.Context, collection *mongo.C
 		}
 	}
 
-	var result *mongo.InsertManyResult
 	var err error
 
 	// Log the command
 	if q.mongoDb != nil && q.mongoDb.GetLogger() != nil {
-		dbLogger := base.NewDBLogger(q.mongoDb.GetLogger())
+		dbLogger := base.NewDBLogger(q.mongoDb.Log())
 		cmdJSON, _ := cmd.ToJSON()
 		defer func() {
-			dbLogger.LogCommand(cmdJSON, time.Since(start))
+			dbLogger.LogCommandContext(ctx, cmdJSON, time.Since(start))
 		}()
 	}
 
+	opts := options.InsertMany()
+	skipDuplicates, _ := cmd.Options["skipDuplicates"].(bool)
+	if ordered, ok := cmd.Options["ordered"].(bool); ok {
+		opts.SetOrdered(ordered)
+	}
+
 	if q.session != nil {
 		// Use session context directly for transactions
 		sessionCtx := mongo.NewSessionContext(ctx, q.session)
-		result, err = collection.InsertMany(sessionCtx, documents)
+		_, err = collection.InsertMany(sessionCtx, documents, opts)
 	} else {
-		result, err = collection.InsertMany(ctx, documents)
+		_, err = collection.InsertMany(ctx, documents, opts)
 	}
 
+	duplicates := 0
+	if err != nil && skipDuplicates {
+		duplicates, err = countDuplicateKeyErrors(err)
+	}
 	if err != nil {
 		return types.Result{}, fmt.Errorf("failed to insert documents: %w", err)
 	}
@@ -179,16 +198,40 @@ func (q *MongoDBRawQuery) executeInsert(ctx context.Context, collection *mongo.C
 		lastInsertID = cmd.LastInsertID
 	}
 
-	return types.Result{
-		RowsAffected: int64(len(result.InsertedIDs)),
+	result := types.Result{
+		RowsAffected: int64(len(documents) - duplicates),
 		LastInsertID: lastInsertID,
-	}, nil
+	}
+	if duplicates == 0 {
+		// The _id of every document, given or generated ObjectIDs as hex strings
+		for _, doc := range documents {
+			if docMap, ok := doc.(map[string]any); ok {
+				result.InsertedIDs = append(result.InsertedIDs, objectIDHex(docMap["_id"]))
+			}
+		}
+	}
+	return result, nil
+}
+
+// countDuplicateKeyErrors returns the number of rejected documents when every write error of
+// an insert is a duplicate key error, and the error itself otherwise
+func countDuplicateKeyErrors(err error) (int, error) {
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil {
+		return 0, err
+	}
+	for _, writeErr := range bulkErr.WriteErrors {
+		if !mongo.IsDuplicateKeyError(writeErr) {
+			return 0, err
+		}
+	}
+	return len(bulkErr.WriteErrors), nil
 }
 
 // executeUpdate handles update operations
 func (q *MongoDBRawQuery) executeUpdate(ctx context.Context, collection *mongo.Collection, cmd *MongoDBCommand) (types.Result, error) {
 	start := time.Now()
-	if cmd.Update == nil {
+	if cmd.Update == nil && len(cmd.Pipeline) == 0 {
 		return types.Result{}, fmt.Errorf("update requires update document")
 	}
 
@@ -198,33 +241,44 @@ func (q *MongoDBRawQuery) executeUpdate(ctx context.Context, collection *mongo.C
 		filter = bson.M{}
 	}
 
-	// Ensure update document has proper MongoDB update operators
-	updateDoc := cmd.Update
-	if _, hasOperator := updateDoc["$set"]; !hasOperator {
-		// Wrap in $set if no operators present
-		updateDoc = bson.M{"$set": updateDoc}
+	// Pipeline updates compute new values from the existing document
+	var updateDoc any = cmd.Pipeline
+	if cmd.Update != nil {
+		// Ensure update document has proper MongoDB update operators
+		updateDoc = cmd.Update
+		if !hasUpdateOperator(cmd.Update) {
+			// Wrap in $set if no operators present
+			updateDoc = bson.M{"$set": cmd.Update}
+		}
 	}
 
 	// Log the command
 	if q.mongoDb != nil && q.mongoDb.GetLogger() != nil {
-		dbLogger := base.NewDBLogger(q.mongoDb.GetLogger())
+		dbLogger := base.NewDBLogger(q.mongoDb.Log())
 		cmdJSON, _ := cmd.ToJSON()
 		defer func() {
-			dbLogger.LogCommand(cmdJSON, time.Since(start))
+			dbLogger.LogCommandContext(ctx, cmdJSON, time.Since(start))
 		}()
 	}
 
-	var result *mongo.UpdateResult
-	var err error
-
 	if q.session != nil {
 		// Use session context directly for transactions
-		sessionCtx := mongo.NewSessionContext(ctx, q.session)
-		result, err = collection.UpdateMany(sessionCtx, filter, updateDoc)
-	} else {
-		result, err = collection.UpdateMany(ctx, filter, updateDoc)
+		ctx = mongo.NewSessionContext(ctx, q.session)
+	}
+
+	// Upserts update or insert the single document matching the filter
+	if upsert, _ := cmd.Options["upsert"].(bool); upsert {
+		result, err := collection.UpdateOne(ctx, filter, updateDoc, options.Update().SetUpsert(true))
+		if err != nil {
+			return types.Result{}, fmt.Errorf("failed to upsert document: %w", err)
+		}
+		if result.UpsertedCount > 0 {
+			return types.Result{RowsAffected: result.UpsertedCount, LastInsertID: cmd.LastInsertID}, nil
+		}
+		return types.Result{RowsAffected: result.MatchedCount}, nil
 	}
 
+	result, err := collection.UpdateMany(ctx, filter, updateDoc)
 	if err != nil {
 		return types.Result{}, fmt.Errorf("failed to update documents: %w", err)
 	}
@@ -234,6 +288,16 @@ func (q *MongoDBRawQuery) executeUpdate(ctx context.Context, collection *mongo.C
 	}, nil
 }
 
+// hasUpdateOperator reports whether an update document uses operators such as $set or $inc
+func hasUpdateOperator(update bson.M) bool {
+	for key := range update {
+		if strings.HasPrefix(key, "$") {
+			return true
+		}
+	}
+	return false
+}
+
 // executeDelete handles delete operations
 func (q *MongoDBRawQuery) executeDelete(ctx context.Context, collection *mongo.Collection, cmd *MongoDBCommand) (types.Result, error) {
 	start := time.Now()
@@ -243,10 +307,10 @@ func (q *MongoDBRawQuery) executeDelete(ctx context.Context, collection *mongo.C
 
 	// Log the command
 	if q.mongoDb != nil && q.mongoDb.GetLogger() != nil {
-		dbLogger := base.NewDBLogger(q.mongoDb.GetLogger())
+		dbLogger := base.NewDBLogger(q.mongoDb.Log())
 		cmdJSON, _ := cmd.ToJSON()
 		defer func() {
-			dbLogger.LogCommand(cmdJSON, time.Since(start))
+			dbLogger.LogCommandContext(ctx, cmdJSON, time.Since(start))
 		}()
 	}
 
@@ -270,6 +334,16 @@ func (q *MongoDBRawQuery) executeDelete(ctx context.Context, collection *mongo.C
 	}, nil
 }
 
+// maxTimeFromContext returns the time left until the deadline of ctx, which is sent to the
+// server as maxTimeMS so that it stops the operation too
+func maxTimeFromContext(ctx context.Context) (time.Duration, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+	return max(time.Until(deadline), time.Millisecond), true
+}
+
 // executeFind handles find operations
 func (q *MongoDBRawQuery) executeFind(ctx context.Context, collection *mongo.Collection, cmd *MongoDBCommand, dest any) error {
 	// Check if collection exists by trying to list collections
@@ -281,6 +355,9 @@ func (q *MongoDBRawQuery) executeFind(ctx context.Context, collection *mongo.Col
 	}
 
 	opts := options.Find()
+	if maxTime, ok := maxTimeFromContext(ctx); ok {
+		opts.SetMaxTime(maxTime)
+	}
 
 	// Apply options from command
 	if cmd.Options != nil {
@@ -292,7 +369,7 @@ func (q *MongoDBRawQuery) executeFind(ctx context.Context, collection *mongo.Col
 				limit := int64(limitFloat)
 				opts.SetLimit(limit)
 			} else {
-				fmt.Printf("[MongoDB Find] Limit data type issue: %T = %v\n", limitData, limitData)
+				q.log().Warn("Find: ignoring limit of unsupported type %T: %v", limitData, limitData)
 			}
 		}
 		if skip, ok := cmd.Options["skip"].(int64); ok {
@@ -303,7 +380,7 @@ func (q *MongoDBRawQuery) executeFind(ctx context.Context, collection *mongo.Col
 				skip := int64(skipFloat)
 				opts.SetSkip(skip)
 			} else {
-				fmt.Printf("[MongoDB Find] Skip data type issue: %T = %v\n", skipData, skipData)
+				q.log().Warn("Find: ignoring skip of unsupported type %T: %v", skipData, skipData)
 			}
 		}
 		if sort, ok := cmd.Options["sort"].(bson.D); ok {
@@ -346,7 +423,7 @@ func (q *MongoDBRawQuery) executeFind(ctx context.Context, collection *mongo.Col
 					opts.SetSort(sortDoc)
 				}
 			} else {
-				fmt.Printf("[MongoDB Find] Sort data type issue: %T = %v\n", sortData, sortData)
+				q.log().Warn("Find: ignoring sort of unsupported type %T: %v", sortData, sortData)
 			}
 		}
 	}
@@ -437,6 +514,9 @@ func (q *MongoDBRawQuery) executeFind(ctx context.Context, collection *mongo.Col
 // executeFindOne handles find one operations
 func (q *MongoDBRawQuery) executeFindOne(ctx context.Context, collection *mongo.Collection, cmd *MongoDBCommand, dest any) error {
 	opts := options.FindOne()
+	if maxTime, ok := maxTimeFromContext(ctx); ok {
+		opts.SetMaxTime(maxTime)
+	}
 
 	// Apply options from command
 	if cmd.Options != nil {
@@ -507,6 +587,9 @@ func (q *MongoDBRawQuery) executeAggregate(ctx context.Context, collection *mong
 	}
 
 	opts := options.Aggregate()
+	if maxTime, ok := maxTimeFromContext(ctx); ok {
+		opts.SetMaxTime(maxTime)
+	}
 
 	var cursor *mongo.Cursor
 	var err error
@@ -570,13 +653,27 @@ func (q *MongoDBRawQuery) executeAggregate(ctx context.Context, collection *mong
 	return nil
 }
 
+// parseSQL binds named parameters, expands slice arguments into IN lists and parses the statement
+func (q *MongoDBRawQuery) parseSQL() (sql.SQLStatement, []any, error) {
+	command, args, err := utils.ExpandSQLArgs(q.command, q.args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parser := sql.NewParser(command)
+	stmt, err := parser.Parse()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse SQL: %w", err)
+	}
+	return stmt, args, nil
+}
+
 // executeSQLCommand parses and executes a SQL command
 func (q *MongoDBRawQuery) executeSQLCommand(ctx context.Context) (types.Result, error) {
 	// Parse SQL statement
-	parser := sql.NewParser(q.command)
-	stmt, err := parser.Parse()
+	stmt, args, err := q.parseSQL()
 	if err != nil {
-		return types.Result{}, fmt.Errorf("failed to parse SQL: %w", err)
+		return types.Result{}, err
 	}
 
 	// Translate SQL to MongoDB command
@@ -586,8 +683,8 @@ func (q *MongoDBRawQuery) executeSQLCommand(ctx context.Context) (types.Result,
 
 	translator := NewMongoDBSQLTranslator(q.mongoDb)
 	// Set arguments for parameter substitution
-	if len(q.args) > 0 {
-		translator.SetArgs(q.args)
+	if len(args) > 0 {
+		translator.SetArgs(args)
 	}
 	mongoCmd, err := translator.TranslateToCommand(stmt)
 	if err != nil {
@@ -601,15 +698,13 @@ func (q *MongoDBRawQuery) executeSQLCommand(ctx context.Context) (types.Result,
 // executeSQLFind executes SQL query for Find operations
 func (q *MongoDBRawQuery) executeSQLFind(ctx context.Context, dest any) error {
 	// Parse SQL statement
-	parser := sql.NewParser(q.command)
-	stmt, err := parser.Parse()
+	stmt, args, err := q.parseSQL()
 	if err != nil {
-		return fmt.Errorf("failed to parse SQL: %w", err)
+		return err
 	}
 
-	// Only SELECT statements are supported for Find
-	selectStmt, ok := stmt.(*sql.SelectStatement)
-	if !ok {
+	// Only SELECT statements (including UNION, INTERSECT and EXCEPT) are supported for Find
+	if stmt.GetType() != sql.StatementTypeSelect {
 		return fmt.Errorf("only SELECT statements are supported for Find operations")
 	}
 
@@ -620,10 +715,10 @@ func (q *MongoDBRawQuery) executeSQLFind(ctx context.Context, dest any) error {
 
 	translator := NewMongoDBSQLTranslator(q.mongoDb)
 	// Set arguments for parameter substitution
-	if len(q.args) > 0 {
-		translator.SetArgs(q.args)
+	if len(args) > 0 {
+		translator.SetArgs(args)
 	}
-	mongoCmd, err := translator.TranslateToCommand(selectStmt)
+	mongoCmd, err := translator.TranslateToCommand(stmt)
 	if err != nil {
 		return fmt.Errorf("failed to translate SQL to MongoDB command: %w", err)
 	}
@@ -649,15 +744,13 @@ func (q *MongoDBRawQuery) executeSQLFind(ctx context.Context, dest any) error {
 // executeSQLFindOne executes SQL query for FindOne operations
 func (q *MongoDBRawQuery) executeSQLFindOne(ctx context.Context, dest any) error {
 	// Parse SQL statement
-	parser := sql.NewParser(q.command)
-	stmt, err := parser.Parse()
+	stmt, args, err := q.parseSQL()
 	if err != nil {
-		return fmt.Errorf("failed to parse SQL: %w", err)
+		return err
 	}
 
-	// Only SELECT statements are supported for FindOne
-	selectStmt, ok := stmt.(*sql.SelectStatement)
-	if !ok {
+	// Only SELECT statements (including UNION, INTERSECT and EXCEPT) are supported for FindOne
+	if stmt.GetType() != sql.StatementTypeSelect {
 		return fmt.Errorf("only SELECT statements are supported for FindOne operations")
 	}
 
@@ -668,10 +761,10 @@ func (q *MongoDBRawQuery) executeSQLFindOne(ctx context.Context, dest any) error
 
 	translator := NewMongoDBSQLTranslator(q.mongoDb)
 	// Set arguments for parameter substitution
-	if len(q.args) > 0 {
-		translator.SetArgs(q.args)
+	if len(args) > 0 {
+		translator.SetArgs(args)
 	}
-	mongoCmd, err := translator.TranslateToCommand(selectStmt)
+	mongoCmd, err := translator.TranslateToCommand(stmt)
 	if err != nil {
 		return fmt.Errorf("failed to translate SQL to MongoDB command: %w", err)
 	}
@@ -837,8 +930,8 @@ func convertBSONToGoTypes(v any) any {
 		}
 		return result
 	case primitive.DateTime:
-		// Convert BSON DateTime to time.Time
-		return val.Time()
+		// Convert BSON DateTime to time.Time, in UTC like the SQL drivers
+		return val.Time().UTC()
 	case int32:
 		// Convert int32 to int64 for consistency
 		return int64(val)
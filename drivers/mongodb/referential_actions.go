@@ -0,0 +1,155 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rediwo/redi-orm/schema"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxCascadeDepth bounds cascading deletes, which would not end on documents referencing
+// each other in a cycle
+const maxCascadeDepth = 64
+
+// referencingRelation is a relation of a model whose foreign key references another model
+type referencingRelation struct {
+	model    string
+	name     string
+	relation schema.Relation
+}
+
+// referencingRelations returns the relations with an onDelete action referencing modelName.
+// In the prisma relation mode, relations without one take no action, refusing the delete as
+// foreign keys would. Those refusing deletes come first, so that deletes they refuse change
+// nothing.
+func (m *MongoDB) referencingRelations(modelName string) []referencingRelation {
+	var refs []referencingRelation
+	for _, model := range m.GetModels() {
+		s, err := m.GetSchema(model)
+		if err != nil {
+			continue
+		}
+		for name, relation := range s.Relations {
+			if relation.Model != modelName || (relation.OnDelete == "" && !s.EmulatesForeignKeys()) || s.GetFieldByName(relation.ForeignKey) == nil {
+				continue
+			}
+			refs = append(refs, referencingRelation{model: model, name: name, relation: relation})
+		}
+	}
+
+	restricts := func(ref referencingRelation) bool {
+		switch ref.relation.OnDelete {
+		case "", schema.ActionRestrict, schema.ActionNoAction:
+			return true
+		}
+		return false
+	}
+	sort.SliceStable(refs, func(i, j int) bool {
+		if restricts(refs[i]) != restricts(refs[j]) {
+			return restricts(refs[i])
+		}
+		return refs[i].model+"."+refs[i].name < refs[j].model+"."+refs[j].name
+	})
+	return refs
+}
+
+// applyOnDelete applies the onDelete actions of the relations referencing the documents of
+// modelName matching filter, before they are deleted. MongoDB has no foreign keys, so
+// cascades, SetNull and SetDefault are applied here, and Restrict refuses the delete.
+func (m *MongoDB) applyOnDelete(ctx context.Context, modelName string, filter bson.M, depth int) error {
+	refs := m.referencingRelations(modelName)
+	if len(refs) == 0 {
+		return nil
+	}
+	if depth > maxCascadeDepth {
+		return fmt.Errorf("cascading delete of %s nested more than %d levels, the documents may reference each other in a cycle", modelName, maxCascadeDepth)
+	}
+	if m.session != nil {
+		ctx = mongo.NewSessionContext(ctx, m.session)
+	}
+
+	collectionName, err := m.FieldMapper.ModelToTable(modelName)
+	if err != nil {
+		return fmt.Errorf("failed to resolve collection name: %w", err)
+	}
+	db := m.client.Database(m.dbName)
+
+	for _, ref := range refs {
+		references := ref.relation.References
+		if references == "" {
+			references = "id"
+		}
+		referencedColumn, err := m.FieldMapper.SchemaToColumn(modelName, references)
+		if err != nil {
+			return err
+		}
+		values, err := db.Collection(collectionName).Distinct(ctx, referencedColumn, filter)
+		if err != nil {
+			return fmt.Errorf("failed to find the deleted %s: %w", modelName, err)
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		refCollectionName, err := m.FieldMapper.ModelToTable(ref.model)
+		if err != nil {
+			return fmt.Errorf("failed to resolve collection name: %w", err)
+		}
+		foreignKeyColumn, err := m.FieldMapper.SchemaToColumn(ref.model, ref.relation.ForeignKey)
+		if err != nil {
+			return err
+		}
+		refCollection := db.Collection(refCollectionName)
+		refFilter := bson.M{foreignKeyColumn: bson.M{"$in": values}}
+
+		switch ref.relation.OnDelete {
+		case "", schema.ActionRestrict, schema.ActionNoAction:
+			count, err := refCollection.CountDocuments(ctx, refFilter, options.Count().SetLimit(1))
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				return fmt.Errorf("cannot delete %s: it is referenced by %s.%s", modelName, ref.model, ref.name)
+			}
+
+		case schema.ActionCascade:
+			if err := m.applyOnDelete(ctx, ref.model, refFilter, depth+1); err != nil {
+				return err
+			}
+			if _, err := refCollection.DeleteMany(ctx, refFilter); err != nil {
+				return fmt.Errorf("failed to cascade delete to %s: %w", ref.model, err)
+			}
+
+		case schema.ActionSetNull, schema.ActionSetDefault:
+			var value any
+			if ref.relation.OnDelete == schema.ActionSetDefault {
+				value = m.foreignKeyDefault(ref.model, ref.relation.ForeignKey)
+			}
+			if _, err := refCollection.UpdateMany(ctx, refFilter, bson.M{"$set": bson.M{foreignKeyColumn: value}}); err != nil {
+				return fmt.Errorf("failed to update %s.%s: %w", ref.model, ref.relation.ForeignKey, err)
+			}
+		}
+	}
+	return nil
+}
+
+// foreignKeyDefault returns the default value of a foreign key field, or nil without one
+func (m *MongoDB) foreignKeyDefault(modelName, fieldName string) any {
+	s, err := m.GetSchema(modelName)
+	if err != nil {
+		return nil
+	}
+	field := s.GetFieldByName(fieldName)
+	if field == nil {
+		return nil
+	}
+	if v, ok := field.Default.(string); ok && (v == "now()" || v == "CURRENT_TIMESTAMP") {
+		return time.Now()
+	}
+	return field.Default
+}
@@ -0,0 +1,401 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// sequenceCollectionName is the collection holding the auto-increment sequences, keyed by collection name
+const sequenceCollectionName = "redi_sequences"
+
+// collectionIndex is an index definition as listed by MongoDB, with its keys in order
+type collectionIndex struct {
+	Name   string `bson:"name"`
+	Key    bson.D `bson:"key"`
+	Unique bool   `bson:"unique"`
+}
+
+// fieldSample holds the values of one document key across the sampled documents
+type fieldSample struct {
+	key     string
+	values  []any // Non-null values
+	present int   // Number of documents holding a non-null value
+}
+
+// InferSchemas infers the schema of every collection from up to sampleSize randomly
+// sampled documents. Field types and optionality follow the sampled values, embedded
+// documents become composite types, and keys that are not the column names of the
+// camelCase field names are kept with @map.
+func (m *MongoDBMigrator) InferSchemas(sampleSize int) ([]*schema.Schema, error) {
+	if sampleSize <= 0 {
+		sampleSize = types.DefaultSampleSize
+	}
+	ctx := context.Background()
+
+	collections, err := m.GetTables()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(collections)
+
+	sequences, err := m.sequencedCollections(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var schemas []*schema.Schema
+	for _, name := range collections {
+		if m.IsSystemTable(name) || name == sequenceCollectionName {
+			continue
+		}
+
+		documents, err := m.sampleDocuments(ctx, name, sampleSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sample collection %s: %w", name, err)
+		}
+		indexes, err := m.listIndexes(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list indexes of collection %s: %w", name, err)
+		}
+
+		schemas = append(schemas, m.inferSchema(name, documents, indexes, sequences[name]))
+	}
+
+	return schemas, nil
+}
+
+// sampleDocuments returns up to size randomly selected documents of a collection
+func (m *MongoDBMigrator) sampleDocuments(ctx context.Context, collection string, size int) ([]bson.D, error) {
+	pipeline := mongo.Pipeline{{{Key: "$sample", Value: bson.D{{Key: "size", Value: size}}}}}
+	cursor, err := m.database.Collection(collection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []bson.D
+	if err := cursor.All(ctx, &documents); err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+// listIndexes returns the indexes of a collection
+func (m *MongoDBMigrator) listIndexes(ctx context.Context, collection string) ([]collectionIndex, error) {
+	cursor, err := m.database.Collection(collection).Indexes().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var indexes []collectionIndex
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return nil, err
+	}
+	return indexes, nil
+}
+
+// sequencedCollections returns the collections with auto-increment sequences
+func (m *MongoDBMigrator) sequencedCollections(ctx context.Context) (map[string]bool, error) {
+	cursor, err := m.database.Collection(sequenceCollectionName).Find(ctx, bson.D{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sequences: %w", err)
+	}
+
+	var sequences []struct {
+		ID any `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &sequences); err != nil {
+		return nil, fmt.Errorf("failed to read sequences: %w", err)
+	}
+
+	collections := make(map[string]bool, len(sequences))
+	for _, sequence := range sequences {
+		if name, ok := sequence.ID.(string); ok {
+			collections[name] = true
+		}
+	}
+	return collections, nil
+}
+
+// inferSchema infers the schema of a collection from sampled documents and its indexes.
+// autoIncrement reports whether the collection has an auto-increment sequence.
+func (m *MongoDBMigrator) inferSchema(collection string, documents []bson.D, indexes []collectionIndex, autoIncrement bool) *schema.Schema {
+	s := schema.New(utils.Singularize(utils.ToPascalCase(collection)))
+	s.WithTableName(collection)
+
+	composites := make(map[string]*schema.CompositeType)
+	for _, field := range m.inferFields(s.Name, documents, composites) {
+		if field.Map == "_id" {
+			// A single primary key is stored as _id
+			field.Map = ""
+			field.Name = "id"
+			field.PrimaryKey = true
+			field.Nullable = false
+			if autoIncrement && (field.Type == schema.FieldTypeInt || field.Type == schema.FieldTypeInt64) {
+				field.Type = schema.FieldTypeInt
+				field.AutoIncrement = true
+			}
+		}
+		s.AddField(field)
+	}
+
+	for _, index := range indexes {
+		if m.IsPrimaryKeyIndex(index.Name) {
+			continue
+		}
+
+		// Indexes over nested paths or on fields missing from the samples cannot be declared
+		var fieldNames []string
+		for _, key := range index.Key {
+			field := findFieldByColumn(s, key.Key)
+			if field == nil {
+				break
+			}
+			fieldNames = append(fieldNames, field.Name)
+		}
+		if len(fieldNames) != len(index.Key) {
+			continue
+		}
+
+		if index.Unique && len(fieldNames) == 1 {
+			s.GetFieldByName(fieldNames[0]).Unique = true
+			continue
+		}
+
+		// Names MongoDB generated from the keys are not kept
+		name := index.Name
+		if name == defaultIndexName(index.Key) {
+			name = utils.GenerateIndexName("", fieldNames, index.Unique, "")
+		}
+		s.AddIndex(schema.Index{Name: name, Fields: fieldNames, Unique: index.Unique})
+	}
+
+	return s
+}
+
+// inferFields infers fields from the keys of documents, in order of first appearance.
+// Embedded documents become composite types named after the owner and the field.
+func (m *MongoDBMigrator) inferFields(owner string, documents []bson.D, composites map[string]*schema.CompositeType) []schema.Field {
+	var fields []schema.Field
+	taken := make(map[string]bool)
+	for _, sample := range collectFieldSamples(documents) {
+		field := m.inferField(owner, sample, composites, taken)
+		field.Nullable = sample.present < len(documents)
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// inferField infers the field holding the sampled values of a key
+func (m *MongoDBMigrator) inferField(owner string, sample *fieldSample, composites map[string]*schema.CompositeType, taken map[string]bool) schema.Field {
+	name := fieldName(sample.key)
+	for base, i := name, 2; taken[name]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	taken[name] = true
+
+	field := schema.Field{Name: name}
+	if sample.key != utils.ToSnakeCase(name) {
+		field.Map = sample.key
+	}
+
+	kinds := valueKinds(sample.values)
+	switch {
+	case len(kinds) == 1 && kinds[0] == "object":
+		field.Type = schema.FieldTypeDocument
+		field.Composite = m.inferCompositeType(owner+utils.ToPascalCase(name), sample.values, composites)
+	case len(kinds) == 1 && kinds[0] == "array":
+		var elements []any
+		for _, value := range sample.values {
+			for _, element := range value.(bson.A) {
+				if element != nil {
+					elements = append(elements, element)
+				}
+			}
+		}
+
+		elementKinds := valueKinds(elements)
+		if len(elementKinds) == 1 && elementKinds[0] == "object" {
+			field.Type = schema.FieldTypeArray
+			field.Composite = m.inferCompositeType(owner+utils.ToPascalCase(name), elements, composites)
+		} else if kind, ok := scalarKind(elementKinds); ok && arrayFieldType(m.MapDatabaseTypeToFieldType(kind)) != "" {
+			field.Type = arrayFieldType(m.MapDatabaseTypeToFieldType(kind))
+			if kind == "objectId" {
+				field.DbType = "@db.ObjectId"
+			}
+		} else {
+			field.Type = schema.FieldTypeJSON
+		}
+	default:
+		kind, ok := scalarKind(kinds)
+		switch {
+		case ok:
+			field.Type = m.MapDatabaseTypeToFieldType(kind)
+			if kind == "objectId" {
+				field.DbType = "@db.ObjectId"
+			}
+		case len(kinds) > 1:
+			field.Type = schema.FieldTypeJSON
+			field.Comment = "Multiple types found in sampled documents: " + strings.Join(kinds, ", ")
+		default:
+			// Only null values were sampled
+			field.Type = schema.FieldTypeJSON
+		}
+	}
+
+	return field
+}
+
+// inferCompositeType infers a composite type from embedded documents
+func (m *MongoDBMigrator) inferCompositeType(name string, values []any, composites map[string]*schema.CompositeType) *schema.CompositeType {
+	documents := make([]bson.D, len(values))
+	for i, value := range values {
+		documents[i] = value.(bson.D)
+	}
+
+	composite := &schema.CompositeType{Name: name}
+	composites[name] = composite
+	composite.Fields = m.inferFields(name, documents, composites)
+	return composite
+}
+
+// collectFieldSamples gathers the values of every key of the documents, in order of first appearance
+func collectFieldSamples(documents []bson.D) []*fieldSample {
+	var samples []*fieldSample
+	byKey := make(map[string]*fieldSample)
+	for _, document := range documents {
+		for _, elem := range document {
+			sample, ok := byKey[elem.Key]
+			if !ok {
+				sample = &fieldSample{key: elem.Key}
+				byKey[elem.Key] = sample
+				samples = append(samples, sample)
+			}
+			if elem.Value != nil {
+				sample.values = append(sample.values, elem.Value)
+				sample.present++
+			}
+		}
+	}
+	return samples
+}
+
+// valueKinds returns the sorted BSON type aliases of values
+func valueKinds(values []any) []string {
+	var kinds []string
+	for _, value := range values {
+		kind := bsonKind(value)
+		if !slices.Contains(kinds, kind) {
+			kinds = append(kinds, kind)
+		}
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// bsonKind returns the BSON type alias of a decoded value
+func bsonKind(value any) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case int32:
+		return "int"
+	case int64:
+		return "long"
+	case float64:
+		return "double"
+	case primitive.Decimal128:
+		return "decimal"
+	case bool:
+		return "bool"
+	case primitive.DateTime:
+		return "date"
+	case primitive.Timestamp:
+		return "timestamp"
+	case primitive.ObjectID:
+		return "objectId"
+	case primitive.Binary:
+		return "binData"
+	case bson.D:
+		return "object"
+	case bson.A:
+		return "array"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// scalarKind returns the kind that holds values of all kinds: numbers widen to the
+// widest sampled number type, and dates and timestamps are both dates
+func scalarKind(kinds []string) (string, bool) {
+	if len(kinds) == 1 {
+		return kinds[0], kinds[0] != "object" && kinds[0] != "array"
+	}
+
+	numeric := []string{"int", "long", "double", "decimal"}
+	if len(kinds) > 0 && !slices.ContainsFunc(kinds, func(kind string) bool { return !slices.Contains(numeric, kind) }) {
+		for _, kind := range slices.Backward(numeric) {
+			if slices.Contains(kinds, kind) {
+				return kind, true
+			}
+		}
+	}
+	if slices.Equal(kinds, []string{"date", "timestamp"}) {
+		return "date", true
+	}
+	return "", false
+}
+
+// arrayFieldType returns the list type holding values of a scalar type, or "" if there is
+// none. ObjectId lists are stored as string lists.
+func arrayFieldType(fieldType schema.FieldType) schema.FieldType {
+	if fieldType == schema.FieldTypeObjectId {
+		return schema.FieldTypeStringArray
+	}
+	return schema.ArrayFieldType(fieldType)
+}
+
+// fieldName converts a document key to a camelCase field name, replacing characters
+// that are not valid in identifiers
+func fieldName(key string) string {
+	name := utils.ToCamelCase(strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, key))
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "field" + name
+	}
+	return name
+}
+
+// findFieldByColumn returns the field stored under a document key
+func findFieldByColumn(s *schema.Schema, key string) *schema.Field {
+	for i := range s.Fields {
+		field := &s.Fields[i]
+		if field.GetColumnName() == key || (field.PrimaryKey && key == "_id") {
+			return field
+		}
+	}
+	return nil
+}
+
+// defaultIndexName returns the name MongoDB gives an index over keys, e.g. email_1_createdAt_-1
+func defaultIndexName(keys bson.D) string {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = fmt.Sprintf("%s_%v", key.Key, key.Value)
+	}
+	return strings.Join(parts, "_")
+}
@@ -0,0 +1,104 @@
+package mongodb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rediwo/redi-orm/schema"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSchemaInference(t *testing.T) {
+	migrator := &MongoDBMigrator{}
+	now := primitive.NewDateTimeFromTime(time.Now())
+
+	documents := []bson.D{
+		{
+			{Key: "_id", Value: int64(1)},
+			{Key: "email", Value: "alice@example.com"},
+			{Key: "firstName", Value: "Alice"},
+			{Key: "score", Value: int32(10)},
+			{Key: "address", Value: bson.D{{Key: "city", Value: "Paris"}, {Key: "zip", Value: "75001"}}},
+			{Key: "tags", Value: bson.A{"admin", "staff"}},
+			{Key: "orders", Value: bson.A{bson.D{{Key: "total", Value: 9.5}}}},
+			{Key: "extra", Value: "text"},
+			{Key: "created_at", Value: now},
+		},
+		{
+			{Key: "_id", Value: int64(2)},
+			{Key: "email", Value: "bob@example.com"},
+			{Key: "firstName", Value: nil},
+			{Key: "score", Value: 7.5},
+			{Key: "address", Value: bson.D{{Key: "city", Value: "Lyon"}}},
+			{Key: "tags", Value: bson.A{}},
+			{Key: "orders", Value: bson.A{bson.D{{Key: "total", Value: int32(3)}, {Key: "ownerId", Value: primitive.NewObjectID()}}}},
+			{Key: "extra", Value: int32(1)},
+			{Key: "created_at", Value: now},
+		},
+	}
+	indexes := []collectionIndex{
+		{Name: "_id_", Key: bson.D{{Key: "_id", Value: int32(1)}}},
+		{Name: "email_1", Key: bson.D{{Key: "email", Value: int32(1)}}, Unique: true},
+		{Name: "score_-1_created_at_1", Key: bson.D{{Key: "score", Value: int32(-1)}, {Key: "created_at", Value: int32(1)}}},
+		{Name: "city_idx", Key: bson.D{{Key: "address.city", Value: int32(1)}}},
+	}
+
+	s := migrator.inferSchema("users", documents, indexes, true)
+	if s.Name != "User" || s.TableName != "users" {
+		t.Fatalf("Expected model User for users, got %s for %s", s.Name, s.TableName)
+	}
+
+	expected := []struct {
+		name      string
+		fieldType schema.FieldType
+		nullable  bool
+		column    string
+	}{
+		{"id", schema.FieldTypeInt, false, "id"},
+		{"email", schema.FieldTypeString, false, "email"},
+		{"firstName", schema.FieldTypeString, true, "firstName"},
+		{"score", schema.FieldTypeFloat, false, "score"},
+		{"address", schema.FieldTypeDocument, false, "address"},
+		{"tags", schema.FieldTypeStringArray, false, "tags"},
+		{"orders", schema.FieldTypeArray, false, "orders"},
+		{"extra", schema.FieldTypeJSON, false, "extra"},
+		{"createdAt", schema.FieldTypeDateTime, false, "created_at"},
+	}
+	if len(s.Fields) != len(expected) {
+		t.Fatalf("Expected %d fields, got %+v", len(expected), s.Fields)
+	}
+	for i, exp := range expected {
+		field := s.Fields[i]
+		if field.Name != exp.name || field.Type != exp.fieldType || field.Nullable != exp.nullable || field.GetColumnName() != exp.column {
+			t.Errorf("Field %d: expected %+v, got %+v", i, exp, field)
+		}
+	}
+
+	if id := s.Fields[0]; !id.PrimaryKey || !id.AutoIncrement {
+		t.Errorf("Expected an auto-increment primary key, got %+v", id)
+	}
+	if email := s.GetFieldByName("email"); !email.Unique {
+		t.Error("Expected the unique email index to mark the field unique")
+	}
+	if extra := s.GetFieldByName("extra"); extra.Comment == "" {
+		t.Error("Expected a comment on the field with mixed types")
+	}
+
+	address := s.GetFieldByName("address").Composite
+	if address == nil || address.Name != "UserAddress" || len(address.Fields) != 2 || !address.Fields[1].Nullable {
+		t.Errorf("Expected UserAddress with an optional zip, got %+v", address)
+	}
+	orders := s.GetFieldByName("orders").Composite
+	if orders == nil || orders.Name != "UserOrders" || orders.Fields[0].Type != schema.FieldTypeFloat {
+		t.Fatalf("Expected UserOrders with a Float total, got %+v", orders)
+	}
+	if owner := orders.Fields[1]; owner.Type != schema.FieldTypeObjectId || owner.DbType != "@db.ObjectId" || !owner.Nullable {
+		t.Errorf("Expected an optional ObjectId owner, got %+v", owner)
+	}
+
+	// Indexes over nested paths are skipped, and generated names are replaced
+	if len(s.Indexes) != 1 || s.Indexes[0].Name != "idx__score_createdAt" || len(s.Indexes[0].Fields) != 2 {
+		t.Errorf("Expected the score index, got %+v", s.Indexes)
+	}
+}
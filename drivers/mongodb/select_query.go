@@ -4,11 +4,12 @@ import (
 	"context"
 	"fmt"
 	"reflect"
-	"strings"
+	"time"
 
 	"github.com/rediwo/redi-orm/query"
 	"github.com/rediwo/redi-orm/schema"
 	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
@@ -31,6 +32,11 @@ func NewMongoDBSelectQuery(baseQuery *query.ModelQueryImpl, fieldNames []string,
 	}
 }
 
+// ToSQL returns the MongoDB command of the query, without executing it
+func (q *MongoDBSelectQuery) ToSQL() (string, []any, error) {
+	return q.BuildSQL()
+}
+
 // BuildSQL builds a MongoDB find/aggregate command instead of SQL
 func (q *MongoDBSelectQuery) BuildSQL() (string, []any, error) {
 	// Get collection name
@@ -57,6 +63,15 @@ func (q *MongoDBSelectQuery) BuildSQL() (string, []any, error) {
 
 // buildFindCommand builds a simple find command
 func (q *MongoDBSelectQuery) buildFindCommand(collection string) (string, []any, error) {
+	// Filters on relations join the related documents, which takes a pipeline
+	stages, err := q.buildMatchStages()
+	if err != nil {
+		return "", nil, err
+	}
+	if len(stages) > 1 || q.needsSortStages() {
+		return q.buildAggregateCommand(collection)
+	}
+
 	// Build filter from conditions
 	filter, err := q.buildFilter()
 	if err != nil {
@@ -120,9 +135,11 @@ func (q *MongoDBSelectQuery) buildAggregateCommand(collection string) (string, [
 	pipeline := []bson.M{}
 
 	// Add $match stage for WHERE conditions
-	if filter, err := q.buildFilter(); err == nil && len(filter) > 0 {
-		pipeline = append(pipeline, bson.M{"$match": filter})
+	stages, err := q.buildMatchStages()
+	if err != nil {
+		return "", nil, err
 	}
+	pipeline = append(pipeline, stages...)
 
 	// Add $group stage for GROUP BY
 	if groupStage := q.buildGroupStage(); groupStage != nil {
@@ -194,11 +211,11 @@ func (q *MongoDBSelectQuery) buildAggregateCommand(collection string) (string, [
 	}
 
 	// Add $sort stage (after DISTINCT processing to ensure proper field references)
-	if sortDoc := q.buildSort(); sortDoc != nil {
-		// sortDoc is already a bson.D, which is the correct format for $sort
-		sortStage := bson.M{"$sort": sortDoc}
-		pipeline = append(pipeline, sortStage)
+	sortStages, err := q.buildSortStages()
+	if err != nil {
+		return "", nil, err
 	}
+	pipeline = append(pipeline, sortStages...)
 
 	// Add $skip stage
 	if offset := q.GetOffset(); offset > 0 {
@@ -249,6 +266,26 @@ func (q *MongoDBSelectQuery) buildFilter() (bson.M, error) {
 	return qb.ConditionToFilter(combined, q.modelName)
 }
 
+// buildMatchStages builds the pipeline stages filtering documents on WHERE conditions
+func (q *MongoDBSelectQuery) buildMatchStages() ([]bson.M, error) {
+	conditions := q.GetConditions()
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+
+	// Combine all conditions with AND
+	var combined types.Condition
+	for i, cond := range conditions {
+		if i == 0 {
+			combined = cond
+		} else {
+			combined = combined.And(cond)
+		}
+	}
+
+	return NewMongoDBQueryBuilder(q.db).ConditionToPipeline(combined, q.modelName)
+}
+
 // buildSort builds MongoDB sort document
 func (q *MongoDBSelectQuery) buildSort() bson.D {
 	orderBy := q.GetOrderBy()
@@ -267,6 +304,108 @@ func (q *MongoDBSelectQuery) buildSort() bson.D {
 	return sort
 }
 
+// needsSortStages reports whether the ordering sorts on keys computed in a pipeline, for
+// null placement and relation counts
+func (q *MongoDBSelectQuery) needsSortStages() bool {
+	for _, order := range q.GetOrderBy() {
+		if order.RelationCount || order.Nulls != types.NullsDefault {
+			return true
+		}
+	}
+	return false
+}
+
+// buildSortStages builds the stages sorting documents: the $lookup and $addFields stages
+// computing the sort keys of relation counts and null placement, the $sort stage, and a
+// $project stage removing the computed keys again
+func (q *MongoDBSelectQuery) buildSortStages() ([]bson.M, error) {
+	orderBy := q.GetOrderBy()
+	if len(orderBy) == 0 {
+		return nil, nil
+	}
+
+	var stages []bson.M
+	computed := bson.M{}
+	sort := bson.D{}
+	for i, order := range orderBy {
+		direction := 1
+		if order.Direction == types.DESC {
+			direction = -1
+		}
+
+		if order.RelationCount {
+			key := fmt.Sprintf("__count_%d", i)
+			lookup, err := q.buildRelationCountLookup(order.Field, key)
+			if err != nil {
+				return nil, err
+			}
+			stages = append(stages, lookup)
+			computed[key] = bson.M{"$size": "$" + key}
+			sort = append(sort, bson.E{Key: key, Value: direction})
+			continue
+		}
+
+		columnName, err := q.GetFieldMapper().SchemaToColumn(q.GetModelName(), order.Field)
+		if err != nil {
+			columnName = order.Field
+		}
+		if order.Nulls != types.NullsDefault {
+			// 1 for null and missing values, sorted after the others unless nulls come first
+			key := fmt.Sprintf("__nulls_%d", i)
+			computed[key] = bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{bson.M{"$ifNull": bson.A{"$" + columnName, nil}}, nil}}, 1, 0,
+			}}
+			nullsDirection := 1
+			if order.Nulls == types.NullsFirst {
+				nullsDirection = -1
+			}
+			sort = append(sort, bson.E{Key: key, Value: nullsDirection})
+		}
+		sort = append(sort, bson.E{Key: columnName, Value: direction})
+	}
+
+	if len(computed) > 0 {
+		stages = append(stages, bson.M{"$addFields": computed})
+	}
+	stages = append(stages, bson.M{"$sort": sort})
+	if len(computed) > 0 {
+		project := bson.M{}
+		for key := range computed {
+			project[key] = 0
+		}
+		stages = append(stages, bson.M{"$project": project})
+	}
+	return stages, nil
+}
+
+// buildRelationCountLookup builds the $lookup stage joining the documents of a one-to-many
+// relation as, to be counted
+func (q *MongoDBSelectQuery) buildRelationCountLookup(relationName, as string) (bson.M, error) {
+	localSchema, err := q.db.GetSchema(q.modelName)
+	if err != nil {
+		return nil, err
+	}
+	relation, err := localSchema.GetRelation(relationName)
+	if err != nil {
+		return nil, err
+	}
+	if relation.Type != schema.RelationOneToMany {
+		return nil, fmt.Errorf("ordering by the count of relation %s requires a one-to-many relation", relationName)
+	}
+	collection, err := q.fieldMapper.ModelToTable(relation.Model)
+	if err != nil {
+		return nil, err
+	}
+
+	localField, relatedField := relation.JoinFields(localSchema)
+	return bson.M{"$lookup": bson.M{
+		"from":         collection,
+		"localField":   q.lookupColumn(q.modelName, localField),
+		"foreignField": q.lookupColumn(relation.Model, relatedField),
+		"as":           as,
+	}}, nil
+}
+
 // buildGroupStage builds $group stage for aggregation
 func (q *MongoDBSelectQuery) buildGroupStage() bson.M {
 	groupBy := q.GetGroupBy()
@@ -422,105 +561,33 @@ func (q *MongoDBSelectQuery) buildIncludeCommand(collection string) (string, []a
 	pipeline := []bson.M{}
 
 	// Add $match stage for WHERE conditions
-	if filter, err := q.buildFilter(); err == nil && len(filter) > 0 {
-		pipeline = append(pipeline, bson.M{"$match": filter})
-	}
-
-	// Add $lookup stages for each include (including nested ones)
-	includeOptions := q.SelectQueryImpl.GetIncludeOptions()
-	simpleIncludes := q.GetIncludes()
-
-	// Group includes by their root relation to avoid conflicts
-	// For example, both "comments" and "comments.user" should be handled together
-	rootIncludes := make(map[string][]string)
-
-	// First, process include options to identify all paths
-	for includePath := range includeOptions {
-		if strings.Contains(includePath, ".") {
-			// Extract root relation (e.g., "comments" from "comments.user")
-			root := strings.Split(includePath, ".")[0]
-			rootIncludes[root] = append(rootIncludes[root], includePath)
-		} else {
-			// Simple include from include options
-			rootIncludes[includePath] = append(rootIncludes[includePath], includePath)
-		}
+	stages, err := q.buildMatchStages()
+	if err != nil {
+		return "", nil, err
 	}
+	pipeline = append(pipeline, stages...)
 
-	// Then, add simple includes only if they don't already have nested versions
-	for _, includePath := range simpleIncludes {
-		if _, exists := rootIncludes[includePath]; !exists {
-			// Only add if this root doesn't already have entries from include options
-			rootIncludes[includePath] = append(rootIncludes[includePath], includePath)
-		}
+	// Add $lookup stages for each include, nesting the lookups of deeper relations
+	if err := types.CheckIncludeDepth(q.db, q.GetIncludes()); err != nil {
+		return "", nil, err
 	}
-
-	// Process each root relation once, prioritizing nested includes
-	processedIncludes := make(map[string]bool)
-	for rootRelation, paths := range rootIncludes {
-		// Skip if this is actually a nested path (e.g., "comments.user")
-		if strings.Contains(rootRelation, ".") {
-			continue
-		}
-		// Check if we have nested includes for this root relation
-		var nestedPath string
-		hasNested := false
-		for _, path := range paths {
-			if strings.Contains(path, ".") {
-				nestedPath = path
-				hasNested = true
-				break // Use the first nested path we find
-			}
-		}
-
-		if hasNested {
-			// Process nested include (this will handle the root relation too)
-			lookupStages, err := q.buildNestedLookupStages(nestedPath)
-			if err != nil {
-				// Skip problematic includes for now, but continue processing
-				continue
-			}
-			pipeline = append(pipeline, lookupStages...)
-
-			// Mark all paths for this root as processed
-			for _, path := range paths {
-				processedIncludes[path] = true
-			}
-		} else {
-			// Process simple include
-			lookupStage, err := q.buildLookupStage(rootRelation)
-			if err != nil {
-				// Skip problematic includes for now, but continue processing
-				continue
-			}
-			if lookupStage != nil {
-				pipeline = append(pipeline, lookupStage)
-
-				// Add unwind stage for many-to-one and one-to-one relations
-				shouldUnwind, err := q.shouldUnwindRelation(rootRelation)
-				if err == nil && shouldUnwind {
-					unwindStage := bson.M{
-						"$unwind": bson.M{
-							"path":                       "$" + rootRelation,
-							"preserveNullAndEmptyArrays": true,
-						},
-					}
-					pipeline = append(pipeline, unwindStage)
-				}
-
-				processedIncludes[rootRelation] = true
-			}
+	for _, node := range q.includeTree() {
+		stages, err := q.buildRelationLookup(q.modelName, node)
+		if err != nil {
+			return "", nil, err
 		}
+		pipeline = append(pipeline, stages...)
 	}
 
 	// Note: We already add unwind stages inline after each lookup
 	// so we don't need addUnwindStages here
 
 	// Add $sort stage
-	if sortDoc := q.buildSort(); sortDoc != nil {
-		// sortDoc is already a bson.D, which is the correct format for $sort
-		sortStage := bson.M{"$sort": sortDoc}
-		pipeline = append(pipeline, sortStage)
+	sortStages, err := q.buildSortStages()
+	if err != nil {
+		return "", nil, err
 	}
+	pipeline = append(pipeline, sortStages...)
 
 	// Add $skip stage
 	if offset := q.GetOffset(); offset > 0 {
@@ -548,775 +615,20 @@ func (q *MongoDBSelectQuery) buildIncludeCommand(collection string) (string, []a
 	return jsonCmd, nil, nil
 }
 
-// buildLookupStage creates a $lookup stage for a given relation
-func (q *MongoDBSelectQuery) buildLookupStage(relationName string) (bson.M, error) {
-	// Get the current model's schema
-	currentSchema, err := q.db.GetSchema(q.modelName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get schema for model %s: %w", q.modelName, err)
-	}
-
-	// Get the relation definition
-	relation, exists := currentSchema.Relations[relationName]
-	if !exists {
-		return nil, fmt.Errorf("relation %s not found in model %s", relationName, q.modelName)
-	}
-
-	// Get the related model's collection name
-	relatedCollection, err := q.fieldMapper.ModelToTable(relation.Model)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get collection name for model %s: %w", relation.Model, err)
-	}
-
-	// Check if there are include options for this relation
-	includeOptions := q.SelectQueryImpl.GetIncludeOptions()
-	includeOpt, hasIncludeOpt := includeOptions[relationName]
-	var whereFilter bson.M
-	var orderBy bson.D
-	var limitValue *int
-	var skipValue *int
-	var projection bson.M
-
-	if hasIncludeOpt {
-		// Build where filter if present
-		if includeOpt.Where != nil {
-			qb := NewMongoDBQueryBuilder(q.db)
-			whereFilter, err = qb.ConditionToFilter(includeOpt.Where, relation.Model)
-			if err != nil {
-				return nil, fmt.Errorf("failed to build where filter for include %s: %w", relationName, err)
-			}
-		}
-
-		// Build order by if present
-		if len(includeOpt.OrderBy) > 0 {
-			orderBy = bson.D{}
-			for _, order := range includeOpt.OrderBy {
-				// Map field name to column name
-				columnName, err := q.fieldMapper.SchemaToColumn(relation.Model, order.Field)
-				if err != nil {
-					columnName = order.Field
-				}
-
-				direction := 1
-				if order.Direction == types.DESC {
-					direction = -1
-				}
-
-				orderBy = append(orderBy, bson.E{Key: columnName, Value: direction})
-			}
-		}
-
-		// Build projection if select fields are specified
-		if len(includeOpt.Select) > 0 {
-			projection = bson.M{}
-			for _, field := range includeOpt.Select {
-				// Map field name to column name
-				columnName, err := q.fieldMapper.SchemaToColumn(relation.Model, field)
-				if err != nil {
-					columnName = field
-				}
-				projection[columnName] = 1
-			}
-		}
-
-		// Get limit and skip values
-		limitValue = includeOpt.Limit
-		skipValue = includeOpt.Offset
-	}
-
-	// Build the $lookup stage based on relation type
-	switch relation.Type {
-	case schema.RelationOneToMany:
-		// For one-to-many, we look up from the related collection
-		// where the foreign key in the related collection matches our primary key
-
-		// Get the local field (usually the primary key)
-		localField := relation.References
-		if localField == "" {
-			localField = "id"
-		}
-		// Map to column name
-		localColumn, err := q.fieldMapper.SchemaToColumn(q.modelName, localField)
-		if err != nil {
-			localColumn = localField
-		}
-		// MongoDB uses _id for primary key
-		if localColumn == "id" {
-			localColumn = "_id"
-		}
-
-		// Get the foreign key column in the related collection
-		foreignColumn, err := q.fieldMapper.SchemaToColumn(relation.Model, relation.ForeignKey)
-		if err != nil {
-			foreignColumn = relation.ForeignKey
-		}
-
-		// Check if the foreign key is part of a composite primary key in the related model
-		relatedSchema, err := q.db.GetSchema(relation.Model)
-		if err == nil && len(relatedSchema.CompositeKey) > 1 {
-			// Check if the foreign key is part of the composite key
-			for _, keyField := range relatedSchema.CompositeKey {
-				if keyField == relation.ForeignKey {
-					// For composite keys, MongoDB stores them under _id
-					foreignColumn = "_id." + foreignColumn
-					break
-				}
-			}
-		}
-
-		// Check if we need pipeline style lookup (for filtering, ordering, pagination, or projection)
-		needsPipeline := len(whereFilter) > 0 || len(orderBy) > 0 || limitValue != nil || skipValue != nil || len(projection) > 0
-
-		if needsPipeline {
-			// Build pipeline with stages
-			pipeline := []bson.M{}
-
-			// Add match stage for the join condition
-			pipeline = append(pipeline, bson.M{
-				"$match": bson.M{
-					"$expr": bson.M{
-						"$eq": []any{"$" + foreignColumn, "$$localField"},
-					},
-				},
-			})
-
-			// Add match stage for where conditions
-			if len(whereFilter) > 0 {
-				pipeline = append(pipeline, bson.M{
-					"$match": whereFilter,
-				})
-			}
-
-			// Add sort stage
-			if len(orderBy) > 0 {
-				pipeline = append(pipeline, bson.M{
-					"$sort": orderBy,
-				})
-			}
-
-			// Add skip stage
-			if skipValue != nil && *skipValue > 0 {
-				pipeline = append(pipeline, bson.M{
-					"$skip": *skipValue,
-				})
-			}
-
-			// Add limit stage
-			if limitValue != nil && *limitValue > 0 {
-				pipeline = append(pipeline, bson.M{
-					"$limit": *limitValue,
-				})
-			}
-
-			// Add projection stage if select fields are specified
-			if len(projection) > 0 {
-				pipeline = append(pipeline, bson.M{
-					"$project": projection,
-				})
-			}
-
-			return bson.M{
-				"$lookup": bson.M{
-					"from":     relatedCollection,
-					"let":      bson.M{"localField": "$" + localColumn},
-					"pipeline": pipeline,
-					"as":       relationName,
-				},
-			}, nil
-		} else {
-			// Use simple $lookup without filtering
-			lookupStage := bson.M{
-				"$lookup": bson.M{
-					"from":         relatedCollection,
-					"localField":   localColumn,
-					"foreignField": foreignColumn,
-					"as":           relationName,
-				},
-			}
-			return lookupStage, nil
-		}
-
-	case schema.RelationManyToOne:
-		// For many-to-one, we look up from the related collection
-		// where our foreign key matches the primary key in the related collection
-
-		// Get the foreign key column in our collection
-		foreignColumn, err := q.fieldMapper.SchemaToColumn(q.modelName, relation.ForeignKey)
-		if err != nil {
-			foreignColumn = relation.ForeignKey
-		}
-
-		// Get the referenced field in the related collection (usually primary key)
-		referencedField := relation.References
-		if referencedField == "" {
-			referencedField = "id"
-		}
-		// Map to column name
-		referencedColumn, err := q.fieldMapper.SchemaToColumn(relation.Model, referencedField)
-		if err != nil {
-			referencedColumn = referencedField
-		}
-		// MongoDB uses _id for primary key
-		if referencedColumn == "id" {
-			referencedColumn = "_id"
-		}
-
-		// Check if we need pipeline style lookup (for filtering, ordering, pagination, or projection)
-		needsPipeline := len(whereFilter) > 0 || len(orderBy) > 0 || limitValue != nil || skipValue != nil || len(projection) > 0
-
-		if needsPipeline {
-			// Build pipeline with stages
-			pipeline := []bson.M{}
-
-			// Add match stage for the join condition
-			pipeline = append(pipeline, bson.M{
-				"$match": bson.M{
-					"$expr": bson.M{
-						"$eq": []any{"$" + referencedColumn, "$$localField"},
-					},
-				},
-			})
-
-			// Add match stage for where conditions
-			if len(whereFilter) > 0 {
-				pipeline = append(pipeline, bson.M{
-					"$match": whereFilter,
-				})
-			}
-
-			// Add sort stage
-			if len(orderBy) > 0 {
-				pipeline = append(pipeline, bson.M{
-					"$sort": orderBy,
-				})
-			}
-
-			// Add skip stage
-			if skipValue != nil && *skipValue > 0 {
-				pipeline = append(pipeline, bson.M{
-					"$skip": *skipValue,
-				})
-			}
-
-			// Add limit stage
-			if limitValue != nil && *limitValue > 0 {
-				pipeline = append(pipeline, bson.M{
-					"$limit": *limitValue,
-				})
-			}
-
-			// Add projection stage if select fields are specified
-			if len(projection) > 0 {
-				pipeline = append(pipeline, bson.M{
-					"$project": projection,
-				})
-			}
-
-			return bson.M{
-				"$lookup": bson.M{
-					"from":     relatedCollection,
-					"let":      bson.M{"localField": "$" + foreignColumn},
-					"pipeline": pipeline,
-					"as":       relationName,
-				},
-			}, nil
-		} else {
-			// Use simple $lookup without filtering
-			return bson.M{
-				"$lookup": bson.M{
-					"from":         relatedCollection,
-					"localField":   foreignColumn,
-					"foreignField": referencedColumn,
-					"as":           relationName,
-				},
-			}, nil
-		}
-
-	case schema.RelationOneToOne:
-		// One-to-one is similar to many-to-one but we expect single result
-		// The implementation is the same as many-to-one
-
-		// Get the foreign key column
-		foreignColumn, err := q.fieldMapper.SchemaToColumn(q.modelName, relation.ForeignKey)
-		if err != nil {
-			foreignColumn = relation.ForeignKey
-		}
-
-		// Get the referenced field in the related collection
-		referencedField := relation.References
-		if referencedField == "" {
-			referencedField = "id"
-		}
-		referencedColumn, err := q.fieldMapper.SchemaToColumn(relation.Model, referencedField)
-		if err != nil {
-			referencedColumn = referencedField
-		}
-		if referencedColumn == "id" {
-			referencedColumn = "_id"
-		}
-
-		// Check if we need pipeline style lookup (for filtering, ordering, pagination, or projection)
-		needsPipeline := len(whereFilter) > 0 || len(orderBy) > 0 || limitValue != nil || skipValue != nil || len(projection) > 0
-
-		if needsPipeline {
-			// Build pipeline with stages
-			pipeline := []bson.M{}
-
-			// Add match stage for the join condition
-			pipeline = append(pipeline, bson.M{
-				"$match": bson.M{
-					"$expr": bson.M{
-						"$eq": []any{"$" + referencedColumn, "$$localField"},
-					},
-				},
-			})
-
-			// Add match stage for where conditions
-			if len(whereFilter) > 0 {
-				pipeline = append(pipeline, bson.M{
-					"$match": whereFilter,
-				})
-			}
-
-			// Add sort stage
-			if len(orderBy) > 0 {
-				pipeline = append(pipeline, bson.M{
-					"$sort": orderBy,
-				})
-			}
-
-			// Add skip stage
-			if skipValue != nil && *skipValue > 0 {
-				pipeline = append(pipeline, bson.M{
-					"$skip": *skipValue,
-				})
-			}
-
-			// Add limit stage
-			if limitValue != nil && *limitValue > 0 {
-				pipeline = append(pipeline, bson.M{
-					"$limit": *limitValue,
-				})
-			}
-
-			// Add projection stage if select fields are specified
-			if len(projection) > 0 {
-				pipeline = append(pipeline, bson.M{
-					"$project": projection,
-				})
-			}
-
-			return bson.M{
-				"$lookup": bson.M{
-					"from":     relatedCollection,
-					"let":      bson.M{"localField": "$" + foreignColumn},
-					"pipeline": pipeline,
-					"as":       relationName,
-				},
-			}, nil
-		} else {
-			// Use simple $lookup without filtering
-			return bson.M{
-				"$lookup": bson.M{
-					"from":         relatedCollection,
-					"localField":   foreignColumn,
-					"foreignField": referencedColumn,
-					"as":           relationName,
-				},
-			}, nil
-		}
-
-	case schema.RelationManyToMany:
-		// Many-to-many requires a join table, which is more complex
-		// For now, return an error
-		return nil, fmt.Errorf("many-to-many relations are not yet supported in MongoDB includes")
-
-	default:
-		return nil, fmt.Errorf("unsupported relation type: %v", relation.Type)
-	}
-}
-
-// buildNestedLookupStages builds $lookup stages for nested includes like "comments.author"
-func (q *MongoDBSelectQuery) buildNestedLookupStages(nestedPath string) ([]bson.M, error) {
-	parts := strings.Split(nestedPath, ".")
-	if len(parts) < 2 {
-		return nil, fmt.Errorf("invalid nested path, expected at least 2 parts, got: %s", nestedPath)
-	}
-
-	// For now, we handle the first two parts and let the field mapper handle deeper nesting
-	parentRelation := parts[0]
-	childRelation := parts[1]
-
-	// The strategy is to modify the parent relation's $lookup to include
-	// a nested $lookup in its pipeline for the child relation
-
-	// Get parent relation info
-	currentSchema, err := q.db.GetSchema(q.modelName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get schema for model %s: %w", q.modelName, err)
-	}
-
-	parentRel, exists := currentSchema.Relations[parentRelation]
-	if !exists {
-		return nil, fmt.Errorf("parent relation %s not found in model %s", parentRelation, q.modelName)
-	}
-
-	// Get child relation info
-	parentSchema, err := q.db.GetSchema(parentRel.Model)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get schema for parent model %s: %w", parentRel.Model, err)
-	}
-
-	childRel, exists := parentSchema.Relations[childRelation]
-	if !exists {
-		return nil, fmt.Errorf("child relation %s not found in parent model %s", childRelation, parentRel.Model)
-	}
-
-	// Check if there are include options for the parent relation
-	includeOptions := q.SelectQueryImpl.GetIncludeOptions()
-	parentIncludeOpt, _ := includeOptions[parentRelation]
-
-	// Build $lookup with nested pipeline and parent options
-	lookupStage, err := q.buildLookupWithNestedRelation(parentRelation, parentRel, childRelation, childRel, parentIncludeOpt)
-	if err != nil {
-		return nil, err
-	}
-
-	stages := []bson.M{lookupStage}
-
-	// For many-to-one parent relations, add unwind stage
-	if parentRel.Type == schema.RelationManyToOne {
-		unwindStage := bson.M{
-			"$unwind": bson.M{
-				"path":                       "$" + parentRelation,
-				"preserveNullAndEmptyArrays": true,
-			},
-		}
-		stages = append(stages, unwindStage)
-	}
-
-	return stages, nil
-}
-
-// buildLookupWithNestedRelation builds a $lookup stage that includes a nested $lookup for child relations
-func (q *MongoDBSelectQuery) buildLookupWithNestedRelation(parentRelation string, parentRel schema.Relation, childRelation string, childRel schema.Relation, parentIncludeOpt *types.IncludeOption) (bson.M, error) {
-	// Get collection names
-	parentCollection, err := q.fieldMapper.ModelToTable(parentRel.Model)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get collection name for parent model %s: %w", parentRel.Model, err)
-	}
-
-	childCollection, err := q.fieldMapper.ModelToTable(childRel.Model)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get collection name for child model %s: %w", childRel.Model, err)
-	}
-
-	// Build the nested $lookup pipeline
-	var nestedPipeline []bson.M
-
-	// Add the child relation lookup
-	switch childRel.Type {
-	case schema.RelationManyToOne:
-		// For many-to-one (e.g., comment.author), we lookup from the child collection
-		// where the child's foreign key matches the parent's primary key
-
-		// Get the foreign key column in the parent collection
-		foreignColumn, err := q.fieldMapper.SchemaToColumn(parentRel.Model, childRel.ForeignKey)
-		if err != nil {
-			foreignColumn = childRel.ForeignKey
-		}
-
-		// Get the referenced column in the child collection
-		referencedColumn := childRel.References
-		if referencedColumn == "" {
-			referencedColumn = "id"
-		}
-		referencedColumn, err = q.fieldMapper.SchemaToColumn(childRel.Model, referencedColumn)
-		if err != nil {
-			referencedColumn = childRel.References
-		}
-		// MongoDB uses _id for primary key
-		if referencedColumn == "id" {
-			referencedColumn = "_id"
-		}
-
-		// Add nested $lookup for child relation
-		nestedPipeline = append(nestedPipeline, bson.M{
-			"$lookup": bson.M{
-				"from":         childCollection,
-				"localField":   foreignColumn,
-				"foreignField": referencedColumn,
-				"as":           childRelation,
-			},
-		})
-
-		// Convert array to single object for many-to-one relations
-		nestedPipeline = append(nestedPipeline, bson.M{
-			"$unwind": bson.M{
-				"path":                       "$" + childRelation,
-				"preserveNullAndEmptyArrays": true,
-			},
-		})
-
-	case schema.RelationOneToMany:
-		// For one-to-many (e.g., user.posts from comments), we lookup from the child collection
-		// where the child's foreign key matches our id
-
-		// Get the referenced column (primary key) in the parent collection
-		referencedColumn := childRel.References
-		if referencedColumn == "" {
-			referencedColumn = "id"
-		}
-		referencedColumn, err = q.fieldMapper.SchemaToColumn(parentRel.Model, referencedColumn)
-		if err != nil {
-			referencedColumn = childRel.References
-		}
-		// MongoDB uses _id for primary key
-		if referencedColumn == "id" {
-			referencedColumn = "_id"
-		}
-
-		// Get the foreign key column in the child collection
-		foreignColumn, err := q.fieldMapper.SchemaToColumn(childRel.Model, childRel.ForeignKey)
-		if err != nil {
-			foreignColumn = childRel.ForeignKey
-		}
-
-		// Add nested $lookup for child relation
-		nestedPipeline = append(nestedPipeline, bson.M{
-			"$lookup": bson.M{
-				"from":         childCollection,
-				"localField":   referencedColumn,
-				"foreignField": foreignColumn,
-				"as":           childRelation,
-			},
-		})
-
-	default:
-		return nil, fmt.Errorf("unsupported child relation type: %v", childRel.Type)
-	}
-
-	// Now build the main $lookup for the parent relation with the nested pipeline
-	switch parentRel.Type {
-	case schema.RelationOneToMany:
-		// Get the local field (usually the primary key)
-		localField := parentRel.References
-		if localField == "" {
-			localField = "id"
-		}
-		localColumn, err := q.fieldMapper.SchemaToColumn(q.modelName, localField)
-		if err != nil {
-			localColumn = localField
-		}
-		// MongoDB uses _id for primary key
-		if localColumn == "id" {
-			localColumn = "_id"
-		}
-
-		// Get the foreign key column in the parent collection
-		foreignColumn, err := q.fieldMapper.SchemaToColumn(parentRel.Model, parentRel.ForeignKey)
-		if err != nil {
-			foreignColumn = parentRel.ForeignKey
-		}
-
-		// Build parent pipeline with optional filters
-		parentPipeline := []bson.M{
-			{
-				"$match": bson.M{
-					"$expr": bson.M{
-						"$eq": []any{"$" + foreignColumn, "$$localField"},
-					},
-				},
-			},
-		}
-
-		// Add parent relation options if specified
-		if parentIncludeOpt != nil {
-			// Add where filter
-			if parentIncludeOpt.Where != nil {
-				qb := NewMongoDBQueryBuilder(q.db)
-				whereFilter, err := qb.ConditionToFilter(parentIncludeOpt.Where, parentRel.Model)
-				if err == nil && len(whereFilter) > 0 {
-					parentPipeline = append(parentPipeline, bson.M{
-						"$match": whereFilter,
-					})
-				}
-			}
-
-			// Add order by
-			if len(parentIncludeOpt.OrderBy) > 0 {
-				orderBy := bson.D{}
-				for _, order := range parentIncludeOpt.OrderBy {
-					columnName, err := q.fieldMapper.SchemaToColumn(parentRel.Model, order.Field)
-					if err != nil {
-						columnName = order.Field
-					}
-					direction := 1
-					if order.Direction == types.DESC {
-						direction = -1
-					}
-					orderBy = append(orderBy, bson.E{Key: columnName, Value: direction})
-				}
-				parentPipeline = append(parentPipeline, bson.M{
-					"$sort": orderBy,
-				})
-			}
-
-			// Add skip
-			if parentIncludeOpt.Offset != nil && *parentIncludeOpt.Offset > 0 {
-				parentPipeline = append(parentPipeline, bson.M{
-					"$skip": *parentIncludeOpt.Offset,
-				})
-			}
-
-			// Add limit
-			if parentIncludeOpt.Limit != nil && *parentIncludeOpt.Limit > 0 {
-				parentPipeline = append(parentPipeline, bson.M{
-					"$limit": *parentIncludeOpt.Limit,
-				})
-			}
-
-			// Add projection
-			if len(parentIncludeOpt.Select) > 0 {
-				projection := bson.M{}
-				for _, field := range parentIncludeOpt.Select {
-					columnName, err := q.fieldMapper.SchemaToColumn(parentRel.Model, field)
-					if err != nil {
-						columnName = field
-					}
-					projection[columnName] = 1
-				}
-				parentPipeline = append(parentPipeline, bson.M{
-					"$project": projection,
-				})
-			}
-		}
-
-		// Use pipeline-style $lookup to include nested relations
-		return bson.M{
-			"$lookup": bson.M{
-				"from":     parentCollection,
-				"let":      bson.M{"localField": "$" + localColumn},
-				"pipeline": append(parentPipeline, nestedPipeline...),
-				"as":       parentRelation,
-			},
-		}, nil
-
-	case schema.RelationManyToOne:
-		// Get the foreign key column in the current collection
-		foreignColumn, err := q.fieldMapper.SchemaToColumn(q.modelName, parentRel.ForeignKey)
-		if err != nil {
-			foreignColumn = parentRel.ForeignKey
-		}
-
-		// Get the referenced column in the parent collection
-		referencedColumn := parentRel.References
-		if referencedColumn == "" {
-			referencedColumn = "id"
-		}
-		referencedColumn, err = q.fieldMapper.SchemaToColumn(parentRel.Model, referencedColumn)
-		if err != nil {
-			referencedColumn = parentRel.References
-		}
-		// MongoDB uses _id for primary key
-		if referencedColumn == "id" {
-			referencedColumn = "_id"
-		}
-
-		// Build parent pipeline with optional filters
-		parentPipeline := []bson.M{
-			{
-				"$match": bson.M{
-					"$expr": bson.M{
-						"$eq": []any{"$" + referencedColumn, "$$localField"},
-					},
-				},
-			},
-		}
-
-		// Add parent relation options if specified
-		if parentIncludeOpt != nil {
-			// Add where filter
-			if parentIncludeOpt.Where != nil {
-				qb := NewMongoDBQueryBuilder(q.db)
-				whereFilter, err := qb.ConditionToFilter(parentIncludeOpt.Where, parentRel.Model)
-				if err == nil && len(whereFilter) > 0 {
-					parentPipeline = append(parentPipeline, bson.M{
-						"$match": whereFilter,
-					})
-				}
-			}
-
-			// Add order by
-			if len(parentIncludeOpt.OrderBy) > 0 {
-				orderBy := bson.D{}
-				for _, order := range parentIncludeOpt.OrderBy {
-					columnName, err := q.fieldMapper.SchemaToColumn(parentRel.Model, order.Field)
-					if err != nil {
-						columnName = order.Field
-					}
-					direction := 1
-					if order.Direction == types.DESC {
-						direction = -1
-					}
-					orderBy = append(orderBy, bson.E{Key: columnName, Value: direction})
-				}
-				parentPipeline = append(parentPipeline, bson.M{
-					"$sort": orderBy,
-				})
-			}
-
-			// Add skip
-			if parentIncludeOpt.Offset != nil && *parentIncludeOpt.Offset > 0 {
-				parentPipeline = append(parentPipeline, bson.M{
-					"$skip": *parentIncludeOpt.Offset,
-				})
-			}
-
-			// Add limit
-			if parentIncludeOpt.Limit != nil && *parentIncludeOpt.Limit > 0 {
-				parentPipeline = append(parentPipeline, bson.M{
-					"$limit": *parentIncludeOpt.Limit,
-				})
-			}
-
-			// Add projection
-			if len(parentIncludeOpt.Select) > 0 {
-				projection := bson.M{}
-				for _, field := range parentIncludeOpt.Select {
-					columnName, err := q.fieldMapper.SchemaToColumn(parentRel.Model, field)
-					if err != nil {
-						columnName = field
-					}
-					projection[columnName] = 1
-				}
-				parentPipeline = append(parentPipeline, bson.M{
-					"$project": projection,
-				})
-			}
-		}
+// FindMany executes the query and returns multiple results
+func (q *MongoDBSelectQuery) FindMany(ctx context.Context, dest any) error {
+	ctx, cancel := q.StatementContext(ctx)
+	defer cancel()
 
-		// Use pipeline-style $lookup to include nested relations
-		lookupStage := bson.M{
-			"$lookup": bson.M{
-				"from":     parentCollection,
-				"let":      bson.M{"localField": "$" + foreignColumn},
-				"pipeline": append(parentPipeline, nestedPipeline...),
-				"as":       parentRelation,
-			},
+	// Included relations arrive as nested documents, so structs are hydrated from mapped documents
+	if q.hasIncludes() && !isMapSliceDest(dest) {
+		var records []map[string]any
+		if err := q.FindMany(ctx, &records); err != nil {
+			return err
 		}
-
-		// For many-to-one relations, we need to unwind after the lookup
-		// Return both the lookup and unwind stages
-		return lookupStage, nil
-
-	default:
-		return nil, fmt.Errorf("unsupported parent relation type: %v", parentRel.Type)
+		return utils.HydrateStructs(records, dest)
 	}
-}
 
-// FindMany executes the query and returns multiple results
-func (q *MongoDBSelectQuery) FindMany(ctx context.Context, dest any) error {
 	sql, args, err := q.BuildSQL()
 	if err != nil {
 		return fmt.Errorf("failed to build MongoDB command: %w", err)
@@ -1339,6 +651,17 @@ func (q *MongoDBSelectQuery) FindMany(ctx context.Context, dest any) error {
 
 // FindFirst executes the query and returns the first result
 func (q *MongoDBSelectQuery) FindFirst(ctx context.Context, dest any) error {
+	ctx, cancel := q.StatementContext(ctx)
+	defer cancel()
+
+	if q.hasIncludes() && !isMapDest(dest) {
+		var record map[string]any
+		if err := q.FindFirst(ctx, &record); err != nil {
+			return err
+		}
+		return utils.HydrateStruct(record, dest)
+	}
+
 	// Add limit 1 for efficiency
 	v := reflect.ValueOf(q.SelectQueryImpl).Elem()
 	limitField := v.FieldByName("limit")
@@ -1366,6 +689,21 @@ func (q *MongoDBSelectQuery) FindFirst(ctx context.Context, dest any) error {
 	return nil
 }
 
+// hasIncludes reports whether the query loads relations
+func (q *MongoDBSelectQuery) hasIncludes() bool {
+	return len(q.GetIncludes()) > 0 || len(q.GetIncludeOptions()) > 0
+}
+
+// isMapSliceDest reports whether dest is *[]map[string]any
+func isMapSliceDest(dest any) bool {
+	return reflect.TypeOf(dest) == reflect.TypeOf(&[]map[string]any{})
+}
+
+// isMapDest reports whether dest is *map[string]any
+func isMapDest(dest any) bool {
+	return reflect.TypeOf(dest) == reflect.TypeOf(&map[string]any{})
+}
+
 // mapColumnNamesToSchemaFields maps column names to schema field names in query results
 func (q *MongoDBSelectQuery) mapColumnNamesToSchemaFields(dest any) error {
 	// Only process []map[string]any destinations
@@ -1416,236 +754,7 @@ func (q *MongoDBSelectQuery) mapSingleDocumentFields(modelName string, document
 	}
 
 	// Process included relations (including nested ones)
-	includes := q.GetIncludes()
-
-	// First, identify which simple includes have nested versions
-	// e.g., if we have both "comments" and "comments.user", skip the simple "comments"
-	nestedRoots := make(map[string]bool)
-	for _, includePath := range includes {
-		if strings.Contains(includePath, ".") {
-			root := strings.Split(includePath, ".")[0]
-			nestedRoots[root] = true
-		}
-	}
-
-	for _, includePath := range includes {
-		if strings.Contains(includePath, ".") {
-			// Handle nested includes like "comments.user"
-			err := q.mapNestedIncludedFields(document, includePath)
-			if err != nil {
-				return fmt.Errorf("failed to map nested included fields for %s: %w", includePath, err)
-			}
-		} else {
-			// Skip simple includes that have nested versions
-			if nestedRoots[includePath] {
-				continue
-			}
-
-			// Handle simple includes like "comments"
-			if includedData, exists := document[includePath]; exists {
-				// Get the target model name for this include
-				targetModelName, err := q.getIncludeTargetModel(includePath)
-				if err != nil {
-					continue // Skip if we can't determine the target model
-				}
-
-				// Map fields for included data
-				err = q.mapIncludedFields(targetModelName, includedData)
-				if err != nil {
-					return fmt.Errorf("failed to map included fields for %s: %w", includePath, err)
-				}
-			}
-		}
-	}
-
-	return nil
-}
-
-// mapNestedIncludedFields maps field names for nested includes like "comments.user"
-func (q *MongoDBSelectQuery) mapNestedIncludedFields(document map[string]any, nestedPath string) error {
-	parts := strings.Split(nestedPath, ".")
-	if len(parts) < 1 {
-		return fmt.Errorf("invalid nested path, got empty path")
-	}
-
-	// For a path like "departments.teams.members", we process only the first relation
-	// "departments" and then recursively handle "teams.members" in the Department context
-	parentRelation := parts[0]
-
-	// The remaining path is everything after the first part
-	remainingPath := ""
-	if len(parts) > 1 {
-		remainingPath = strings.Join(parts[1:], ".")
-	}
-
-	// Check if the parent relation exists in the document
-	parentData, exists := document[parentRelation]
-	if !exists {
-		return nil // Parent relation not present, nothing to map
-	}
-
-	// Get the parent relation's target model
-	parentModelName, err := q.getIncludeTargetModel(parentRelation)
-	if err != nil {
-		return fmt.Errorf("failed to get parent model for %s: %w", parentRelation, err)
-	}
-	// Debug logging
-	// utils.LogDebug("Processing nested path %s: parentRelation=%s, parentModel=%s, currentQueryModel=%s",
-	//	nestedPath, parentRelation, parentModelName, q.modelName)
-
-	// Map the parent relation data first
-	err = q.mapIncludedFields(parentModelName, parentData)
-	if err != nil {
-		return fmt.Errorf("failed to map parent relation fields: %w", err)
-	}
-
-	// If there's a remaining path, recursively handle it in the context of the parent model
-	if remainingPath != "" {
-		// Create a new query context for the parent model
-		parentQuery := &MongoDBSelectQuery{
-			db:          q.db,
-			fieldMapper: q.fieldMapper,
-			modelName:   parentModelName,
-		}
-		// utils.LogDebug("Recursing to parent context: remainingPath=%s, newContext=%s", remainingPath, parentModelName)
-
-		// Check if the remaining path has multiple parts (nested) or just one part (simple relation)
-		remainingParts := strings.Split(remainingPath, ".")
-		if len(remainingParts) == 1 {
-			// This is the final level - handle as a simple include
-			err = parentQuery.mapSimpleNestedRelation(parentData, remainingPath)
-			if err != nil {
-				return fmt.Errorf("failed to map final nested relation %s: %w", remainingPath, err)
-			}
-		} else {
-			// This has more nesting - recurse with mapNestedIncludedFields
-			// Handle both single documents and arrays
-			switch data := parentData.(type) {
-			case map[string]any:
-				// Single parent document
-				err = parentQuery.mapNestedIncludedFields(data, remainingPath)
-				if err != nil {
-					return fmt.Errorf("failed to map deeper nested fields: %w", err)
-				}
-			case []any:
-				// Array of parent documents
-				for _, item := range data {
-					if itemMap, ok := item.(map[string]any); ok {
-						err = parentQuery.mapNestedIncludedFields(itemMap, remainingPath)
-						if err != nil {
-							return fmt.Errorf("failed to map deeper nested fields: %w", err)
-						}
-					}
-				}
-			}
-		}
-	}
-
-	return nil
-}
-
-// mapSimpleNestedRelation handles the final level of a nested relation (like "user" in "comments.user")
-func (q *MongoDBSelectQuery) mapSimpleNestedRelation(parentData any, relationName string) error {
-	// Handle both single documents and arrays
-	switch data := parentData.(type) {
-	case map[string]any:
-		// Single parent document - check if the relation exists
-		if relationData, exists := data[relationName]; exists {
-			// Get the target model for this relation
-			targetModelName, err := q.getIncludeTargetModel(relationName)
-			if err != nil {
-				return fmt.Errorf("failed to get target model for %s: %w", relationName, err)
-			}
-
-			// Map the relation data
-			err = q.mapIncludedFields(targetModelName, relationData)
-			if err != nil {
-				return fmt.Errorf("failed to map relation fields for %s: %w", relationName, err)
-			}
-		}
-
-	case []any:
-		// Array of parent documents
-		for _, item := range data {
-			if itemMap, ok := item.(map[string]any); ok {
-				if relationData, exists := itemMap[relationName]; exists {
-					// Get the target model for this relation
-					targetModelName, err := q.getIncludeTargetModel(relationName)
-					if err != nil {
-						return fmt.Errorf("failed to get target model for %s: %w", relationName, err)
-					}
-
-					// Map the relation data
-					err = q.mapIncludedFields(targetModelName, relationData)
-					if err != nil {
-						return fmt.Errorf("failed to map relation fields for %s: %w", relationName, err)
-					}
-				}
-			}
-		}
-	}
-
-	return nil
-}
-
-// mapIncludedFields maps field names for included relation data
-func (q *MongoDBSelectQuery) mapIncludedFields(targetModelName string, includedData any) error {
-	// Handle both single included document and arrays of included documents
-	switch data := includedData.(type) {
-	case map[string]any:
-		// Single included document
-		mappedData, err := q.fieldMapper.MapColumnToSchemaData(targetModelName, data)
-		if err != nil {
-			return err
-		}
-
-		// Replace contents
-		for key := range data {
-			delete(data, key)
-		}
-		for key, value := range mappedData {
-			data[key] = value
-		}
-
-	case []any:
-		// Array of included documents
-		for _, item := range data {
-			if itemMap, ok := item.(map[string]any); ok {
-				mappedData, err := q.fieldMapper.MapColumnToSchemaData(targetModelName, itemMap)
-				if err != nil {
-					return err
-				}
-
-				// Replace contents
-				for key := range itemMap {
-					delete(itemMap, key)
-				}
-				for key, value := range mappedData {
-					itemMap[key] = value
-				}
-			}
-		}
-	}
-
-	return nil
-}
-
-// getIncludeTargetModel determines the target model name for an include path
-func (q *MongoDBSelectQuery) getIncludeTargetModel(includePath string) (string, error) {
-	// Get the schema for the main model through the database
-	schema, err := q.db.GetSchema(q.modelName)
-	if err != nil {
-		return "", err
-	}
-
-	// Look for the relation with this name
-	for relationName, relation := range schema.Relations {
-		if relationName == includePath {
-			return relation.Model, nil
-		}
-	}
-
-	return "", fmt.Errorf("relation %s not found in model %s", includePath, q.modelName)
+	return q.mapIncludedDocuments(modelName, document, q.includeTree())
 }
 
 // mapSingleColumnNamesToSchemaFields maps column names to schema field names for single result
@@ -1674,6 +783,9 @@ func (q *MongoDBSelectQuery) mapSingleColumnNamesToSchemaFields(dest any) error
 
 // Count executes a count query
 func (q *MongoDBSelectQuery) Count(ctx context.Context) (int64, error) {
+	ctx, cancel := q.StatementContext(ctx)
+	defer cancel()
+
 	// Get collection name
 	tableName, err := q.fieldMapper.ModelToTable(q.modelName)
 	if err != nil {
@@ -1681,18 +793,13 @@ func (q *MongoDBSelectQuery) Count(ctx context.Context) (int64, error) {
 	}
 
 	// Build filter from conditions
-	filter, err := q.buildFilter()
+	stages, err := q.buildMatchStages()
 	if err != nil {
 		return 0, fmt.Errorf("failed to build filter: %w", err)
 	}
 
 	// For count, we use aggregation pipeline with $count stage
-	pipeline := []bson.M{}
-
-	// Add $match stage if there's a filter
-	if len(filter) > 0 {
-		pipeline = append(pipeline, bson.M{"$match": filter})
-	}
+	pipeline := append([]bson.M{}, stages...)
 
 	// Add $count stage
 	pipeline = append(pipeline, bson.M{"$count": "count"})
@@ -1784,6 +891,26 @@ func (q *MongoDBSelectQuery) OrderBy(fieldName string, direction types.Order) ty
 	}
 }
 
+func (q *MongoDBSelectQuery) OrderByNulls(fieldName string, direction types.Order, nulls types.NullsOrder) types.SelectQuery {
+	newBase := q.SelectQueryImpl.OrderByNulls(fieldName, direction, nulls).(*query.SelectQueryImpl)
+	return &MongoDBSelectQuery{
+		SelectQueryImpl: newBase,
+		db:              q.db,
+		fieldMapper:     q.fieldMapper,
+		modelName:       q.modelName,
+	}
+}
+
+func (q *MongoDBSelectQuery) OrderByRelationCount(relationName string, direction types.Order) types.SelectQuery {
+	newBase := q.SelectQueryImpl.OrderByRelationCount(relationName, direction).(*query.SelectQueryImpl)
+	return &MongoDBSelectQuery{
+		SelectQueryImpl: newBase,
+		db:              q.db,
+		fieldMapper:     q.fieldMapper,
+		modelName:       q.modelName,
+	}
+}
+
 func (q *MongoDBSelectQuery) GroupBy(fieldNames ...string) types.SelectQuery {
 	newBase := q.SelectQueryImpl.GroupBy(fieldNames...).(*query.SelectQueryImpl)
 	return &MongoDBSelectQuery{
@@ -1834,6 +961,29 @@ func (q *MongoDBSelectQuery) Distinct() types.SelectQuery {
 	}
 }
 
+// RelationLoadStrategy is accepted for API compatibility. MongoDB always loads includes
+// with $lookup stages inside the single aggregation, so both strategies behave the same.
+func (q *MongoDBSelectQuery) RelationLoadStrategy(strategy types.RelationLoadStrategy) types.SelectQuery {
+	newBase := q.SelectQueryImpl.RelationLoadStrategy(strategy).(*query.SelectQueryImpl)
+	return &MongoDBSelectQuery{
+		SelectQueryImpl: newBase,
+		db:              q.db,
+		fieldMapper:     q.fieldMapper,
+		modelName:       q.modelName,
+	}
+}
+
+// WithTimeout cancels the query after timeout. The deadline is sent to the server as maxTimeMS.
+func (q *MongoDBSelectQuery) WithTimeout(timeout time.Duration) types.SelectQuery {
+	newBase := q.SelectQueryImpl.WithTimeout(timeout).(*query.SelectQueryImpl)
+	return &MongoDBSelectQuery{
+		SelectQueryImpl: newBase,
+		db:              q.db,
+		fieldMapper:     q.fieldMapper,
+		modelName:       q.modelName,
+	}
+}
+
 func (q *MongoDBSelectQuery) DistinctOn(fieldNames ...string) types.SelectQuery {
 	newBase := q.SelectQueryImpl.DistinctOn(fieldNames...).(*query.SelectQueryImpl)
 	return &MongoDBSelectQuery{
@@ -1853,21 +1003,3 @@ func contains(slice []string, value string) bool {
 	}
 	return false
 }
-
-// shouldUnwindRelation determines if a relation should be unwound (converted from array to single object)
-func (q *MongoDBSelectQuery) shouldUnwindRelation(relationName string) (bool, error) {
-	// Get the current model's schema
-	currentSchema, err := q.db.GetSchema(q.modelName)
-	if err != nil {
-		return false, fmt.Errorf("failed to get schema for model %s: %w", q.modelName, err)
-	}
-
-	// Get the relation definition
-	relation, exists := currentSchema.Relations[relationName]
-	if !exists {
-		return false, fmt.Errorf("relation %s not found in model %s", relationName, q.modelName)
-	}
-
-	// Unwind for many-to-one and one-to-one relations (they should return single objects)
-	return relation.Type == schema.RelationManyToOne || relation.Type == schema.RelationOneToOne, nil
-}
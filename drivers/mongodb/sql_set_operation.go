@@ -0,0 +1,207 @@
+package mongodb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rediwo/redi-orm/sql"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// translateSetOperation converts UNION, INTERSECT and EXCEPT into an aggregation on the
+// collection of the first query; the other queries are appended with $unionWith.
+//
+// Rows are matched by value, so every query is reshaped to the column names of the first
+// one (columns pair up by position as in SQL). Duplicate handling groups on the whole row
+// and counts how often it appears on each side of the operator.
+func (t *MongoDBSQLTranslator) translateSetOperation(stmt *sql.SetOperationStatement) (*MongoDBCommand, error) {
+	columns := t.setColumns(stmt)
+
+	argIndex := 0
+	collection, pipeline, err := t.setPipeline(stmt, columns, &argIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	// ORDER BY, OFFSET and LIMIT apply to the combined rows
+	if len(stmt.OrderBy) > 0 {
+		sortStage, err := t.setOrderBy(stmt.OrderBy, columns)
+		if err != nil {
+			return nil, fmt.Errorf("failed to translate ORDER BY: %w", err)
+		}
+		pipeline = append(pipeline, bson.M{"$sort": sortStage})
+	}
+	if stmt.Offset != nil && *stmt.Offset > 0 {
+		pipeline = append(pipeline, bson.M{"$skip": *stmt.Offset})
+	}
+	if stmt.Limit != nil && *stmt.Limit > 0 {
+		pipeline = append(pipeline, bson.M{"$limit": *stmt.Limit})
+	}
+
+	if err := t.validateParameterSubstitution(pipeline); err != nil {
+		return nil, err
+	}
+
+	return &MongoDBCommand{
+		Operation:  "aggregate",
+		Collection: collection,
+		Pipeline:   pipeline,
+	}, nil
+}
+
+// setColumns returns the output column names of the first query, nil for SELECT *
+func (t *MongoDBSQLTranslator) setColumns(stmt sql.SQLStatement) []string {
+	for {
+		switch s := stmt.(type) {
+		case *sql.SetOperationStatement:
+			stmt = s.Left
+		case *sql.SelectStatement:
+			if sql.IsSelectAll(s.Fields) {
+				return nil
+			}
+			columns := make([]string, len(s.Fields))
+			for i, field := range s.Fields {
+				columns[i] = selectOutputName(field)
+			}
+			return columns
+		default:
+			return nil
+		}
+	}
+}
+
+// selectOutputName returns the name a SELECT field is projected as
+func selectOutputName(field sql.SelectField) string {
+	if field.Alias != "" {
+		return field.Alias
+	}
+	if parts := strings.Split(field.Expression, "."); len(parts) == 2 {
+		return parts[1]
+	}
+	return field.Expression
+}
+
+// setPipeline returns the collection and pipeline producing the rows of stmt
+func (t *MongoDBSQLTranslator) setPipeline(stmt sql.SQLStatement, columns []string, argIndex *int) (string, []bson.M, error) {
+	switch s := stmt.(type) {
+	case *sql.SelectStatement:
+		return t.setOperandPipeline(s, columns, argIndex)
+
+	case *sql.SetOperationStatement:
+		collection, pipeline, err := t.setPipeline(s.Left, columns, argIndex)
+		if err != nil {
+			return "", nil, err
+		}
+		rightCollection, rightPipeline, err := t.setPipeline(s.Right, columns, argIndex)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if s.Operator == sql.SetOperatorUnion {
+			pipeline = append(pipeline, bson.M{"$unionWith": bson.M{"coll": rightCollection, "pipeline": rightPipeline}})
+			if !s.All {
+				pipeline = append(pipeline,
+					bson.M{"$group": bson.M{"_id": "$$ROOT"}},
+					bson.M{"$replaceRoot": bson.M{"newRoot": "$_id"}},
+				)
+			}
+			return collection, pipeline, nil
+		}
+
+		// Tag each row with its side, then count the occurrences per side
+		pipeline = append(pipeline, bson.M{"$replaceRoot": bson.M{"newRoot": bson.M{"row": "$$ROOT", "side": 0}}})
+		rightPipeline = append(rightPipeline, bson.M{"$replaceRoot": bson.M{"newRoot": bson.M{"row": "$$ROOT", "side": 1}}})
+		pipeline = append(pipeline,
+			bson.M{"$unionWith": bson.M{"coll": rightCollection, "pipeline": rightPipeline}},
+			bson.M{"$group": bson.M{
+				"_id":   "$row",
+				"left":  bson.M{"$sum": bson.M{"$cond": []any{bson.M{"$eq": []any{"$side", 0}}, 1, 0}}},
+				"right": bson.M{"$sum": bson.M{"$cond": []any{bson.M{"$eq": []any{"$side", 1}}, 1, 0}}},
+			}},
+		)
+
+		switch {
+		case !s.All && s.Operator == sql.SetOperatorIntersect:
+			pipeline = append(pipeline, bson.M{"$match": bson.M{"left": bson.M{"$gt": 0}, "right": bson.M{"$gt": 0}}})
+		case !s.All:
+			pipeline = append(pipeline, bson.M{"$match": bson.M{"left": bson.M{"$gt": 0}, "right": 0}})
+		default:
+			// ALL keeps min(left, right) copies for INTERSECT and left - right for EXCEPT
+			copies := bson.M{"$min": []any{"$left", "$right"}}
+			if s.Operator == sql.SetOperatorExcept {
+				copies = bson.M{"$max": []any{bson.M{"$subtract": []any{"$left", "$right"}}, 0}}
+			}
+			pipeline = append(pipeline,
+				bson.M{"$project": bson.M{"copies": bson.M{"$range": []any{0, copies}}}},
+				bson.M{"$unwind": "$copies"},
+			)
+		}
+		pipeline = append(pipeline, bson.M{"$replaceRoot": bson.M{"newRoot": "$_id"}})
+		return collection, pipeline, nil
+	}
+
+	return "", nil, fmt.Errorf("unsupported statement in set operation: %T", stmt)
+}
+
+// setOperandPipeline translates one SELECT of a set operation and renames its columns to
+// the columns of the first query
+func (t *MongoDBSQLTranslator) setOperandPipeline(stmt *sql.SelectStatement, columns []string, argIndex *int) (string, []bson.M, error) {
+	if sql.IsSelectAll(stmt.Fields) != (columns == nil) ||
+		(columns != nil && len(stmt.Fields) != len(columns)) {
+		return "", nil, fmt.Errorf("each query in a set operation must select the same number of columns")
+	}
+
+	cmd, err := t.translateSelectWithArgs(stmt, argIndex)
+	if err != nil {
+		return "", nil, err
+	}
+	pipeline := cmd.Pipeline
+	if pipeline == nil {
+		pipeline = []bson.M{}
+	}
+
+	// Build the row with only the selected columns so _id does not make rows distinct
+	if columns != nil {
+		row := bson.D{}
+		for i, field := range stmt.Fields {
+			row = append(row, bson.E{Key: columns[i], Value: "$" + selectOutputName(field)})
+		}
+		pipeline = append(pipeline, bson.M{"$replaceRoot": bson.M{"newRoot": row}})
+	}
+
+	return cmd.Collection, pipeline, nil
+}
+
+// setOrderBy converts ORDER BY of a set operation, which may only name result columns
+// or their positions
+func (t *MongoDBSQLTranslator) setOrderBy(orderBy []*sql.OrderByClause, columns []string) (bson.D, error) {
+	sortStage := bson.D{}
+	for _, clause := range orderBy {
+		direction := 1
+		if clause.Direction == sql.OrderDirectionDesc {
+			direction = -1
+		}
+
+		name := clause.Field
+		if expr := clause.Expr; expr != nil {
+			position, ok := expr.Value.(int64)
+			if !ok || expr.Operator != "" || expr.Function != "" {
+				return nil, fmt.Errorf("ORDER BY of a set operation must reference result columns")
+			}
+			if columns == nil || position < 1 || position > int64(len(columns)) {
+				return nil, fmt.Errorf("ORDER BY position %d is not in select list", position)
+			}
+			name = columns[position-1]
+		} else if columns == nil {
+			mapped, err := t.mapFieldName(name)
+			if err == nil {
+				name = mapped
+			}
+		} else if parts := strings.Split(name, "."); len(parts) == 2 {
+			name = parts[1]
+		}
+
+		sortStage = append(sortStage, bson.E{Key: name, Value: direction})
+	}
+	return sortStage, nil
+}
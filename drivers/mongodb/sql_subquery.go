@@ -0,0 +1,148 @@
+package mongodb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rediwo/redi-orm/sql"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// subqueryScope resolves column references inside a correlated subquery. Columns of the
+// subquery table become "$field" paths, columns of the enclosing table become $lookup
+// variables ("$$outer_field") declared in variables.
+type subqueryScope struct {
+	inner     sql.TableRef
+	outer     sql.TableRef
+	variables bson.M
+}
+
+// refersTo reports whether a qualifier names table (by alias, or by table name when unaliased)
+func refersTo(qualifier string, table sql.TableRef) bool {
+	return qualifier == table.Alias || (table.Alias == "" && qualifier == table.Table)
+}
+
+// isColumn reports whether value is a qualified column reference of either table
+func (s *subqueryScope) isColumn(value string) bool {
+	parts := strings.Split(value, ".")
+	if len(parts) != 2 {
+		return false
+	}
+	return refersTo(parts[0], s.inner) || refersTo(parts[0], s.outer)
+}
+
+// field returns the aggregation expression for a column referenced inside the subquery
+func (s *subqueryScope) field(t *MongoDBSQLTranslator, name string) string {
+	fieldName, err := t.mapFieldName(name)
+	if err != nil {
+		fieldName = name
+	}
+
+	parts := strings.Split(name, ".")
+	if len(parts) != 2 || refersTo(parts[0], s.inner) || !refersTo(parts[0], s.outer) {
+		return "$" + fieldName
+	}
+
+	// Variable names must start with a lowercase letter
+	variable := "outer_" + strings.TrimPrefix(fieldName, "_")
+	s.variables[variable] = "$" + fieldName
+	return "$$" + variable
+}
+
+// isCorrelated reports whether the subquery WHERE references the enclosing table
+func (t *MongoDBSQLTranslator) isCorrelated(subquery *sql.SelectStatement) bool {
+	if t.outerTable == nil || subquery.Where == nil {
+		return false
+	}
+	scope := &subqueryScope{inner: subquery.From, outer: *t.outerTable}
+	return referencesOuter(subquery.Where, scope)
+}
+
+// referencesOuter walks a WHERE tree looking for columns qualified with the outer table
+func referencesOuter(where *sql.WhereClause, scope *subqueryScope) bool {
+	if where == nil {
+		return false
+	}
+	if where.Condition == nil {
+		return referencesOuter(where.Left, scope) || referencesOuter(where.Right, scope)
+	}
+
+	isOuter := func(name string) bool {
+		parts := strings.Split(name, ".")
+		return len(parts) == 2 && !refersTo(parts[0], scope.inner) && refersTo(parts[0], scope.outer)
+	}
+	cond := where.Condition
+	if isOuter(cond.Field) {
+		return true
+	}
+	value, ok := cond.Value.(string)
+	return ok && isOuter(value)
+}
+
+// translateLookupSubquery converts [NOT] EXISTS (SELECT ...) and field [NOT] IN (SELECT ...)
+// with a correlated subquery into a $lookup that stops at the first matching document,
+// then matches on whether the lookup found one. field is empty for EXISTS.
+func (t *MongoDBSQLTranslator) translateLookupSubquery(field string, subquery *sql.SelectStatement, argIndex *int, negated bool) (bson.M, error) {
+	name, err := t.subqueryLookup(subquery, field, argIndex)
+	if err != nil {
+		return nil, err
+	}
+	if negated {
+		return bson.M{name: bson.M{"$size": 0}}, nil
+	}
+	return bson.M{name: bson.M{"$ne": bson.A{}}}, nil
+}
+
+// subqueryLookup queues the $lookup stage evaluating subquery for each outer document and
+// returns the name of the temporary array field holding its (at most one) match. When
+// inField is set the selected column must also equal that outer field.
+func (t *MongoDBSQLTranslator) subqueryLookup(subquery *sql.SelectStatement, inField string, argIndex *int) (string, error) {
+	if t.outerTable == nil {
+		return "", fmt.Errorf("EXISTS and correlated subqueries are only supported in SELECT WHERE clauses")
+	}
+	if len(subquery.Joins) > 0 || len(subquery.GroupBy) > 0 || subquery.Having != nil {
+		return "", fmt.Errorf("subqueries with JOIN, GROUP BY or HAVING are not supported here")
+	}
+
+	scope := &subqueryScope{inner: subquery.From, outer: *t.outerTable, variables: bson.M{}}
+	var conditions []any
+	if subquery.Where != nil {
+		condition, err := t.conditionExpression(subquery.Where, scope, argIndex)
+		if err != nil {
+			return "", fmt.Errorf("failed to translate subquery WHERE clause: %w", err)
+		}
+		conditions = append(conditions, condition)
+	}
+	if inField != "" {
+		if len(subquery.Fields) != 1 || subquery.Fields[0].Expression == "*" {
+			return "", fmt.Errorf("IN subquery must select exactly one column")
+		}
+		scope.variables["outer_in"] = "$" + inField
+		selected := scope.field(t, subquery.Fields[0].Expression)
+		conditions = append(conditions, bson.M{"$eq": []any{selected, "$$outer_in"}})
+	}
+
+	pipeline := []bson.M{}
+	switch len(conditions) {
+	case 0:
+	case 1:
+		pipeline = append(pipeline, bson.M{"$match": bson.M{"$expr": conditions[0]}})
+	default:
+		pipeline = append(pipeline, bson.M{"$match": bson.M{"$expr": bson.M{"$and": conditions}}})
+	}
+	pipeline = append(pipeline, bson.M{"$limit": 1}, bson.M{"$project": bson.M{"_id": 1}})
+
+	name := fmt.Sprintf("__subquery_%d", len(t.subqueryFields))
+	lookup := bson.M{
+		"from":     t.getCollectionName(subquery.From.Table),
+		"pipeline": pipeline,
+		"as":       name,
+	}
+	if len(scope.variables) > 0 {
+		lookup["let"] = scope.variables
+	}
+
+	t.subqueryStages = append(t.subqueryStages, bson.M{"$lookup": lookup})
+	t.subqueryFields = append(t.subqueryFields, name)
+	return name, nil
+}
@@ -2,6 +2,7 @@ package mongodb
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/rediwo/redi-orm/sql"
@@ -13,6 +14,11 @@ import (
 type MongoDBSQLTranslator struct {
 	db   *MongoDB
 	args []any // SQL parameters for substitution
+
+	// State for EXISTS and correlated subqueries while a SELECT WHERE clause is translated
+	outerTable     *sql.TableRef // FROM table of the enclosing SELECT
+	subqueryStages []bson.M      // $lookup stages to run before the $match
+	subqueryFields []string      // temporary lookup results removed after the $match
 }
 
 // NewMongoDBSQLTranslator creates a new MongoDB SQL translator
@@ -45,6 +51,8 @@ func (t *MongoDBSQLTranslator) TranslateToCommand(stmt sql.SQLStatement) (*Mongo
 	switch s := stmt.(type) {
 	case *sql.SelectStatement:
 		return t.translateSelect(s)
+	case *sql.SetOperationStatement:
+		return t.translateSetOperation(s)
 	case *sql.InsertStatement:
 		return t.translateInsert(s)
 	case *sql.UpdateStatement:
@@ -141,6 +149,13 @@ func (t *MongoDBSQLTranslator) checkForUnsubstitutedParams(doc any) error {
 
 // translateSelect converts SELECT statement to MongoDB aggregation pipeline
 func (t *MongoDBSQLTranslator) translateSelect(stmt *sql.SelectStatement) (*MongoDBCommand, error) {
+	argIndex := 0
+	return t.translateSelectWithArgs(stmt, &argIndex)
+}
+
+// translateSelectWithArgs converts SELECT to a MongoDB find or aggregate command, consuming
+// parameters from argIndex so several statements can share one argument list
+func (t *MongoDBSQLTranslator) translateSelectWithArgs(stmt *sql.SelectStatement, argIndex *int) (*MongoDBCommand, error) {
 	pipeline := []bson.M{}
 
 	// Convert table name to collection name
@@ -152,15 +167,27 @@ func (t *MongoDBSQLTranslator) translateSelect(stmt *sql.SelectStatement) (*Mong
 	}
 
 	// WHERE clause → $match stage (before joins for optimization)
-	argIndex := 0
 	if stmt.Where != nil {
-		matchStage, err := t.translateWhereToMatchWithArgs(stmt.Where, &argIndex)
+		t.outerTable = &stmt.From
+		t.subqueryStages, t.subqueryFields = nil, nil
+		matchStage, err := t.translateWhereToMatchWithArgs(stmt.Where, argIndex)
+		t.outerTable = nil
 		if err != nil {
 			return nil, fmt.Errorf("failed to translate WHERE clause: %w", err)
 		}
+
+		// EXISTS and correlated subqueries are evaluated by $lookup before matching
+		pipeline = append(pipeline, t.subqueryStages...)
 		if len(matchStage) > 0 {
 			pipeline = append(pipeline, bson.M{"$match": matchStage})
 		}
+		if len(t.subqueryFields) > 0 {
+			exclude := bson.M{}
+			for _, name := range t.subqueryFields {
+				exclude[name] = 0
+			}
+			pipeline = append(pipeline, bson.M{"$project": exclude})
+		}
 	}
 
 	// JOIN → $lookup stages (must be before GROUP BY)
@@ -176,43 +203,53 @@ func (t *MongoDBSQLTranslator) translateSelect(stmt *sql.SelectStatement) (*Mong
 	hasGroupBy := len(stmt.GroupBy) > 0
 	isAggregation := t.isAggregationQuery(stmt)
 
+	// Window functions are evaluated after grouping; the other stages only see plain fields
+	fields := withoutWindowFunctions(stmt.Fields)
+
+	// Stages of grouped queries, extended by ORDER BY terms that need extra group values
+	var groupStage, groupProject bson.M
+
 	// GROUP BY with aggregation → $group stage
 	if hasGroupBy && isAggregation {
-		groupStage, err := t.translateGroupByWithAggregation(stmt.GroupBy, stmt.Fields, stmt.From, stmt.Joins)
+		var err error
+		groupStage, err = t.translateGroupByWithAggregation(stmt.GroupBy, fields, stmt.From, stmt.Joins)
 		if err != nil {
 			return nil, fmt.Errorf("failed to translate GROUP BY with aggregation: %w", err)
 		}
 		pipeline = append(pipeline, bson.M{"$group": groupStage})
 
 		// Add $project stage to restructure the result from GROUP BY
-		projectStage, err := t.translateGroupByProject(stmt.GroupBy, stmt.Fields)
+		projectStage, err := t.translateGroupByProject(stmt.GroupBy, fields, len(stmt.GroupBy) == 1)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create GROUP BY projection: %w", err)
 		}
 		if len(projectStage) > 0 {
 			pipeline = append(pipeline, bson.M{"$project": projectStage})
+			groupProject = projectStage
 		}
 	} else if hasGroupBy {
 		// GROUP BY without aggregation functions → $group stage with first values
-		groupStage, err := t.translateGroupBy(stmt.GroupBy)
+		var err error
+		groupStage, err = t.translateGroupBy(stmt.GroupBy)
 		if err != nil {
 			return nil, fmt.Errorf("failed to translate GROUP BY: %w", err)
 		}
 		pipeline = append(pipeline, bson.M{"$group": groupStage})
 
 		// Add $project stage to restructure the result from GROUP BY
-		projectStage, err := t.translateGroupByProject(stmt.GroupBy, stmt.Fields)
+		projectStage, err := t.translateGroupByProject(stmt.GroupBy, fields, false)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create GROUP BY projection: %w", err)
 		}
 		if len(projectStage) > 0 {
 			pipeline = append(pipeline, bson.M{"$project": projectStage})
+			groupProject = projectStage
 		}
 	}
 
 	// HAVING → $match stage (after group)
 	if stmt.Having != nil {
-		havingStage, err := t.translateHavingClause(stmt.Having, stmt.Fields, &argIndex)
+		havingStage, err := t.translateHavingClause(stmt.Having, fields, argIndex)
 		if err != nil {
 			return nil, fmt.Errorf("failed to translate HAVING clause: %w", err)
 		}
@@ -221,13 +258,44 @@ func (t *MongoDBSQLTranslator) translateSelect(stmt *sql.SelectStatement) (*Mong
 		}
 	}
 
-	// ORDER BY → $sort stage
-	if len(stmt.OrderBy) > 0 {
-		sortStage, err := t.translateOrderBy(stmt.OrderBy)
+	// Window functions → $setWindowFields stages
+	if len(fields) != len(stmt.Fields) {
+		if isAggregation && !hasGroupBy {
+			return nil, fmt.Errorf("window functions cannot be combined with aggregates without GROUP BY")
+		}
+		windowStages, err := t.translateWindowFields(stmt, hasGroupBy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to translate window function: %w", err)
+		}
+		pipeline = append(pipeline, windowStages...)
+	}
+
+	// ORDER BY → $sort stage; aggregations without GROUP BY return a single row
+	if len(stmt.OrderBy) > 0 && (hasGroupBy || !isAggregation) {
+		order := &orderByContext{
+			stmt:     stmt,
+			grouped:  hasGroupBy,
+			group:    groupStage,
+			project:  groupProject,
+			computed: bson.M{},
+		}
+		sortStage, err := t.translateOrderBy(order)
 		if err != nil {
 			return nil, fmt.Errorf("failed to translate ORDER BY: %w", err)
 		}
+		if len(order.computed) > 0 {
+			pipeline = append(pipeline, bson.M{"$addFields": order.computed})
+		}
 		pipeline = append(pipeline, bson.M{"$sort": sortStage})
+
+		// Drop the temporary sort values
+		if len(order.hidden) > 0 {
+			exclude := bson.M{}
+			for _, name := range order.hidden {
+				exclude[name] = 0
+			}
+			pipeline = append(pipeline, bson.M{"$project": exclude})
+		}
 	}
 
 	// OFFSET → $skip stage
@@ -266,7 +334,10 @@ func (t *MongoDBSQLTranslator) translateSelect(stmt *sql.SelectStatement) (*Mong
 			if err != nil {
 				return nil, fmt.Errorf("failed to translate SELECT fields: %w", err)
 			}
-			pipeline = append(pipeline, bson.M{"$project": projectStage})
+			// SELECT *, ... keeps every field
+			if len(projectStage) > 0 {
+				pipeline = append(pipeline, bson.M{"$project": projectStage})
+			}
 		}
 	}
 
@@ -276,7 +347,7 @@ func (t *MongoDBSQLTranslator) translateSelect(stmt *sql.SelectStatement) (*Mong
 		emptyPipeline := []bson.M{}
 		if stmt.Where != nil {
 			// Check if WHERE clause has any conditions that might contain unsubstituted parameters
-			whereMatch, err := t.translateWhereToMatchWithArgs(stmt.Where, &argIndex)
+			whereMatch, err := t.translateWhereToMatchWithArgs(stmt.Where, argIndex)
 			if err != nil {
 				return nil, fmt.Errorf("failed to validate WHERE clause: %w", err)
 			}
@@ -515,6 +586,10 @@ func (t *MongoDBSQLTranslator) translateConditionWithArgs(cond *sql.Condition, a
 	}
 
 	switch strings.ToUpper(cond.Operator) {
+	case "EXISTS":
+		return t.translateLookupSubquery("", cond.Subquery, argIndex, false)
+	case "NOT EXISTS":
+		return t.translateLookupSubquery("", cond.Subquery, argIndex, true)
 	case "=":
 		value := t.substituteValue(cond.Value, argIndex)
 		return bson.M{fieldName: value}, nil
@@ -540,8 +615,10 @@ func (t *MongoDBSQLTranslator) translateConditionWithArgs(cond *sql.Condition, a
 		return bson.M{fieldName: bson.M{"$regex": pattern, "$options": "i"}}, nil
 	case "IN":
 		if cond.Subquery != nil {
-			// Handle subquery: field IN (SELECT ...)
-			// We need to execute the subquery and get its results
+			// Correlated subqueries run per document via $lookup; others are executed once
+			if t.isCorrelated(cond.Subquery) {
+				return t.translateLookupSubquery(fieldName, cond.Subquery, argIndex, false)
+			}
 			return t.translateSubqueryCondition(fieldName, cond.Subquery, argIndex, false)
 		} else {
 			// For IN clause, substitute all values in the array
@@ -552,6 +629,12 @@ func (t *MongoDBSQLTranslator) translateConditionWithArgs(cond *sql.Condition, a
 			return bson.M{fieldName: bson.M{"$in": values}}, nil
 		}
 	case "NOT IN":
+		if cond.Subquery != nil {
+			if t.isCorrelated(cond.Subquery) {
+				return t.translateLookupSubquery(fieldName, cond.Subquery, argIndex, true)
+			}
+			return t.translateSubqueryCondition(fieldName, cond.Subquery, argIndex, true)
+		}
 		// For NOT IN clause, substitute all values in the array
 		values := make([]any, len(cond.Values))
 		for i, v := range cond.Values {
@@ -567,27 +650,298 @@ func (t *MongoDBSQLTranslator) translateConditionWithArgs(cond *sql.Condition, a
 	}
 }
 
-// translateOrderBy converts ORDER BY to MongoDB $sort
-func (t *MongoDBSQLTranslator) translateOrderBy(orderBy []*sql.OrderByClause) (bson.M, error) {
-	sortStage := bson.M{}
+// orderByContext holds the query state ORDER BY terms are resolved against
+type orderByContext struct {
+	stmt     *sql.SelectStatement
+	grouped  bool
+	group    bson.M   // $group stage of grouped queries
+	project  bson.M   // $project stage following $group, if any
+	computed bson.M   // computed sort values added before $sort
+	hidden   []string // temporary fields removed after $sort
+	temps    int      // number of temporary names handed out
+}
 
-	for _, clause := range orderBy {
-		fieldName, err := t.mapFieldName(clause.Field)
-		if err != nil {
-			fieldName = clause.Field // fallback
-		}
+// tempName returns a fresh name for a temporary sort field
+func (o *orderByContext) tempName() string {
+	o.temps++
+	return fmt.Sprintf("%s%d", sortValuePrefix, o.temps)
+}
 
+// sortValuePrefix names the temporary fields holding computed sort values
+const sortValuePrefix = "__order_"
+
+// scalarFunctionOperators maps SQL scalar functions usable in ORDER BY to aggregation operators
+var scalarFunctionOperators = map[string]string{
+	"LOWER":    "$toLower",
+	"UPPER":    "$toUpper",
+	"LENGTH":   "$strLenCP",
+	"ABS":      "$abs",
+	"COALESCE": "$ifNull",
+	"ROUND":    "$round",
+}
+
+// arithmeticOperators maps SQL arithmetic operators to aggregation operators
+var arithmeticOperators = map[string]string{
+	"+": "$add",
+	"-": "$subtract",
+	"*": "$multiply",
+	"/": "$divide",
+	"%": "$mod",
+}
+
+// translateOrderBy converts ORDER BY to an ordered MongoDB $sort. Terms that are not plain
+// fields are computed into temporary fields recorded in the context.
+func (t *MongoDBSQLTranslator) translateOrderBy(order *orderByContext) (bson.D, error) {
+	sortStage := bson.D{}
+
+	for _, clause := range order.stmt.OrderBy {
 		direction := 1 // ASC
 		if clause.Direction == sql.OrderDirectionDesc {
 			direction = -1 // DESC
 		}
 
-		sortStage[fieldName] = direction
+		expr := clause.Expr
+		if expr == nil {
+			expr = &sql.Expression{Field: clause.Field}
+		}
+
+		// ORDER BY 2 refers to the second selected column
+		if position, ok := expr.Value.(int64); ok && expr.Operator == "" && expr.Function == "" {
+			field, err := t.selectFieldAt(order.stmt.Fields, position)
+			if err != nil {
+				return nil, err
+			}
+			expr = field
+		}
+
+		value, err := t.orderByValue(expr, order, order.grouped)
+		if err != nil {
+			return nil, err
+		}
+
+		// Plain paths sort directly, anything else is computed first
+		if path, ok := value.(string); ok && strings.HasPrefix(path, "$") {
+			sortStage = append(sortStage, bson.E{Key: path[1:], Value: direction})
+			continue
+		}
+		name := order.tempName()
+		order.computed[name] = value
+		order.hidden = append(order.hidden, name)
+		sortStage = append(sortStage, bson.E{Key: name, Value: direction})
 	}
 
 	return sortStage, nil
 }
 
+// selectFieldAt returns the expression of the 1-based SELECT position used in ORDER BY
+func (t *MongoDBSQLTranslator) selectFieldAt(fields []sql.SelectField, position int64) (*sql.Expression, error) {
+	if position < 1 || position > int64(len(fields)) || fields[position-1].Expression == "*" {
+		return nil, fmt.Errorf("ORDER BY position %d is not in select list", position)
+	}
+	field := fields[position-1]
+	if field.Alias != "" {
+		return &sql.Expression{Field: field.Alias}, nil
+	}
+	return &sql.Expression{Field: field.Expression}, nil
+}
+
+// orderByValue resolves an ORDER BY expression to a field path ("$name") or an aggregation
+// expression. grouped is false for arguments of aggregates, which are evaluated per document.
+func (t *MongoDBSQLTranslator) orderByValue(expr *sql.Expression, order *orderByContext, grouped bool) (any, error) {
+	switch {
+	case expr.Operator != "":
+		operator, ok := arithmeticOperators[expr.Operator]
+		if !ok {
+			return nil, fmt.Errorf("unsupported operator in ORDER BY: %s", expr.Operator)
+		}
+		left, err := t.orderByValue(expr.Left, order, grouped)
+		if err != nil {
+			return nil, err
+		}
+		right, err := t.orderByValue(expr.Right, order, grouped)
+		if err != nil {
+			return nil, err
+		}
+		return bson.M{operator: []any{left, right}}, nil
+
+	case expr.Function != "":
+		return t.orderByFunction(expr, order, grouped)
+
+	case expr.Field != "":
+		if grouped {
+			return t.groupedOrderByField(expr.Field, order)
+		}
+
+		// Aliases of selected columns sort by the underlying field
+		for _, field := range order.stmt.Fields {
+			if field.Alias == expr.Field && field.Expression != "*" && !t.isFunctionCall(field.Expression) {
+				return "$" + t.fieldPath(field.Expression, order.stmt.From, order.stmt.Joins), nil
+			}
+		}
+		return "$" + t.fieldPath(expr.Field, order.stmt.From, order.stmt.Joins), nil
+	}
+
+	// Strings starting with $ would be read as field paths
+	if s, ok := expr.Value.(string); ok && strings.HasPrefix(s, "$") {
+		return bson.M{"$literal": s}, nil
+	}
+	return expr.Value, nil
+}
+
+// orderByFunction resolves aggregate and scalar function calls in ORDER BY
+func (t *MongoDBSQLTranslator) orderByFunction(expr *sql.Expression, order *orderByContext, grouped bool) (any, error) {
+	name := strings.ToUpper(expr.Function)
+
+	switch name {
+	case "COUNT", "SUM", "AVG", "MIN", "MAX":
+		if !grouped {
+			return nil, fmt.Errorf("aggregate %s in ORDER BY requires GROUP BY", expr.String())
+		}
+
+		// Reuse the aggregate computed for the SELECT list
+		for _, field := range order.stmt.Fields {
+			if strings.EqualFold(strings.ReplaceAll(field.Expression, " ", ""), strings.ReplaceAll(expr.String(), " ", "")) {
+				return "$" + t.groupOutputName(field), nil
+			}
+		}
+
+		accumulator, err := t.orderByAccumulator(name, expr, order)
+		if err != nil {
+			return nil, err
+		}
+		hidden := order.tempName()
+		order.group[hidden] = accumulator
+		return t.exposeGroupValue(hidden, "$"+hidden, order), nil
+	}
+
+	operator, ok := scalarFunctionOperators[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported function in ORDER BY: %s", expr.Function)
+	}
+	args := make([]any, len(expr.Args))
+	for i, arg := range expr.Args {
+		value, err := t.orderByValue(arg, order, grouped)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = value
+	}
+	if len(args) == 1 && operator != "$round" {
+		return bson.M{operator: args[0]}, nil
+	}
+	return bson.M{operator: args}, nil
+}
+
+// orderByAccumulator builds the $group accumulator for an aggregate only used in ORDER BY
+func (t *MongoDBSQLTranslator) orderByAccumulator(name string, expr *sql.Expression, order *orderByContext) (bson.M, error) {
+	if len(expr.Args) != 1 {
+		return nil, fmt.Errorf("%s expects one argument", expr.Function)
+	}
+	if name == "COUNT" && expr.Args[0].Field == "*" {
+		return bson.M{"$sum": 1}, nil
+	}
+
+	value, err := t.orderByValue(expr.Args[0], order, false)
+	if err != nil {
+		return nil, err
+	}
+
+	switch name {
+	case "COUNT":
+		return bson.M{"$sum": bson.M{"$cond": []any{bson.M{"$ne": []any{value, nil}}, 1, 0}}}, nil
+	case "SUM":
+		return bson.M{"$sum": value}, nil
+	case "AVG":
+		return bson.M{"$avg": value}, nil
+	case "MIN":
+		return bson.M{"$min": value}, nil
+	default:
+		return bson.M{"$max": value}, nil
+	}
+}
+
+// groupedOrderByField resolves a column reference after GROUP BY, where only selected
+// columns and group keys are available
+func (t *MongoDBSQLTranslator) groupedOrderByField(name string, order *orderByContext) (any, error) {
+	for _, field := range order.stmt.Fields {
+		if field.Alias == name {
+			return "$" + name, nil
+		}
+	}
+	for _, field := range order.stmt.Fields {
+		if field.Expression == name {
+			return "$" + t.groupOutputName(field), nil
+		}
+	}
+
+	// Group keys that are not selected are read from _id
+	for _, gbField := range order.stmt.GroupBy {
+		if gbField != name {
+			continue
+		}
+		ref := "$_id"
+		if len(order.stmt.GroupBy) > 1 || !t.isAggregationQuery(order.stmt) {
+			ref += "." + strings.ReplaceAll(gbField, ".", "_")
+		}
+		return t.exposeGroupValue(order.tempName(), ref, order), nil
+	}
+
+	return nil, fmt.Errorf("ORDER BY field %s must appear in GROUP BY or the select list", name)
+}
+
+// exposeGroupValue makes a value of the $group output available to $sort under name
+func (t *MongoDBSQLTranslator) exposeGroupValue(name, ref string, order *orderByContext) string {
+	if order.project == nil {
+		// Values added to $group stay in the output until removed after sorting
+		if ref == "$"+name {
+			order.hidden = append(order.hidden, name)
+		}
+		return ref
+	}
+	order.project[name] = ref
+	order.hidden = append(order.hidden, name)
+	return "$" + name
+}
+
+// groupOutputName returns the key a SELECT field has after the GROUP BY projection
+func (t *MongoDBSQLTranslator) groupOutputName(field sql.SelectField) string {
+	if field.Alias != "" {
+		return field.Alias
+	}
+	if !t.isFunctionCall(field.Expression) && strings.Contains(field.Expression, ".") {
+		parts := strings.Split(field.Expression, ".")
+		return parts[len(parts)-1]
+	}
+	return field.Expression
+}
+
+// fieldPath returns the document path of a possibly qualified column; joined tables are
+// nested under their alias by $lookup
+func (t *MongoDBSQLTranslator) fieldPath(field string, fromTable sql.TableRef, joins []*sql.JoinClause) string {
+	parts := strings.Split(field, ".")
+	if len(parts) != 2 {
+		fieldName, err := t.mapFieldName(field)
+		if err != nil {
+			return field
+		}
+		return fieldName
+	}
+
+	tableAlias, fieldName := parts[0], parts[1]
+	if fieldName == "id" {
+		fieldName = "_id"
+	}
+	if tableAlias == fromTable.Alias || (fromTable.Alias == "" && tableAlias == fromTable.Table) {
+		return fieldName
+	}
+	for _, join := range joins {
+		if join.Table.Alias == tableAlias || (join.Table.Alias == "" && join.Table.Table == tableAlias) {
+			return tableAlias + "." + fieldName
+		}
+	}
+	return fieldName
+}
+
 // translateGroupBy converts GROUP BY to MongoDB $group
 func (t *MongoDBSQLTranslator) translateGroupBy(groupBy []string) (bson.M, error) {
 	groupStage := bson.M{
@@ -743,6 +1097,13 @@ func (t *MongoDBSQLTranslator) translateProject(fields []sql.SelectField, fromTa
 			return bson.M{}, nil
 		}
 
+		// Window function results are computed into their output field
+		if field.Window != nil {
+			name := windowOutputName(field)
+			projectStage[name] = "$" + name
+			continue
+		}
+
 		// Use alias if provided, otherwise extract field name without table prefix
 		outputName := field.Expression
 		if field.Alias != "" {
@@ -811,6 +1172,9 @@ func (t *MongoDBSQLTranslator) translateProject(fields []sql.SelectField, fromTa
 // isAggregationQuery checks if the query contains aggregation functions
 func (t *MongoDBSQLTranslator) isAggregationQuery(stmt *sql.SelectStatement) bool {
 	for _, field := range stmt.Fields {
+		if field.Window != nil {
+			continue
+		}
 		expr := strings.ToUpper(field.Expression)
 		if strings.HasPrefix(expr, "COUNT(") || strings.HasPrefix(expr, "SUM(") ||
 			strings.HasPrefix(expr, "AVG(") || strings.HasPrefix(expr, "MIN(") ||
@@ -926,22 +1290,46 @@ func (t *MongoDBSQLTranslator) translateInsert(stmt *sql.InsertStatement) (*Mong
 	}, nil
 }
 
-// translateUpdate converts UPDATE statement to MongoDB command
+// translateUpdate converts UPDATE statement to MongoDB command. Literal assignments map to
+// $set and self-referencing arithmetic (views = views + 1) to $inc/$mul; any other computed
+// value turns the update into a pipeline whose $set is evaluated against the original document.
 func (t *MongoDBSQLTranslator) translateUpdate(stmt *sql.UpdateStatement) (*MongoDBCommand, error) {
 	// Convert table name to collection name
 	collection := t.getCollectionName(stmt.Table)
 
-	// Build update document with parameter substitution
-	updateDoc := bson.M{"$set": bson.M{}}
+	setDoc := bson.M{}
+	incDoc := bson.M{}
+	mulDoc := bson.M{}
+	computed := bson.M{}
+
+	// Substitute parameters in SET clause first (as they appear first in SQL)
 	argIndex := 0
-	for field, value := range stmt.Set {
+	for _, field := range t.updateColumns(stmt) {
 		fieldName, err := t.mapFieldName(field)
 		if err != nil {
 			fieldName = field // fallback
 		}
-		// Substitute parameters in SET clause first (as they appear first in SQL)
-		substitutedValue := t.substituteValue(value, &argIndex)
-		updateDoc["$set"].(bson.M)[fieldName] = substitutedValue
+
+		expr, ok := stmt.Set[field].(*sql.Expression)
+		if !ok {
+			setDoc[fieldName] = t.substituteValue(stmt.Set[field], &argIndex)
+			continue
+		}
+
+		if operator, amount, ok := t.atomicUpdate(field, expr, &argIndex); ok {
+			if operator == "$inc" {
+				incDoc[fieldName] = amount
+			} else {
+				mulDoc[fieldName] = amount
+			}
+			continue
+		}
+
+		value, err := t.updateExpression(expr, &argIndex)
+		if err != nil {
+			return nil, fmt.Errorf("failed to translate SET %s: %w", field, err)
+		}
+		computed[fieldName] = value
 	}
 
 	// Now handle WHERE clause parameters
@@ -954,12 +1342,290 @@ func (t *MongoDBSQLTranslator) translateUpdate(stmt *sql.UpdateStatement) (*Mong
 		}
 	}
 
-	return &MongoDBCommand{
+	cmd := &MongoDBCommand{
 		Operation:  "update",
 		Collection: collection,
 		Filter:     filter,
-		Update:     updateDoc,
-	}, nil
+	}
+
+	if len(computed) == 0 {
+		updateDoc := bson.M{}
+		if len(setDoc) > 0 || (len(incDoc) == 0 && len(mulDoc) == 0) {
+			updateDoc["$set"] = setDoc
+		}
+		if len(incDoc) > 0 {
+			updateDoc["$inc"] = incDoc
+		}
+		if len(mulDoc) > 0 {
+			updateDoc["$mul"] = mulDoc
+		}
+		cmd.Update = updateDoc
+		return cmd, nil
+	}
+
+	// Pipeline update: every assignment becomes an aggregation expression
+	for fieldName, value := range setDoc {
+		computed[fieldName] = literalExpression(value)
+	}
+	for fieldName, amount := range incDoc {
+		computed[fieldName] = bson.M{"$add": []any{"$" + fieldName, amount}}
+	}
+	for fieldName, factor := range mulDoc {
+		computed[fieldName] = bson.M{"$multiply": []any{"$" + fieldName, factor}}
+	}
+	cmd.Pipeline = []bson.M{{"$set": computed}}
+	return cmd, nil
+}
+
+// updateColumns returns the assigned columns in statement order
+func (t *MongoDBSQLTranslator) updateColumns(stmt *sql.UpdateStatement) []string {
+	if len(stmt.Columns) == len(stmt.Set) {
+		return stmt.Columns
+	}
+	columns := make([]string, 0, len(stmt.Set))
+	for field := range stmt.Set {
+		columns = append(columns, field)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// atomicUpdate recognizes field = field + n, field = field - n and field = field * n with a
+// literal or parameter n, which map to $inc and $mul
+func (t *MongoDBSQLTranslator) atomicUpdate(field string, expr *sql.Expression, argIndex *int) (string, any, bool) {
+	if expr.Operator != "+" && expr.Operator != "-" && expr.Operator != "*" {
+		return "", nil, false
+	}
+
+	isField := func(e *sql.Expression) bool {
+		return e.Field == field && e.Function == "" && e.Operator == ""
+	}
+	isLiteral := func(e *sql.Expression) bool {
+		return e.Field == "" && e.Function == "" && e.Operator == "" && len(e.Whens) == 0
+	}
+
+	var operand *sql.Expression
+	switch {
+	case isField(expr.Left) && isLiteral(expr.Right):
+		operand = expr.Right
+	case isLiteral(expr.Left) && isField(expr.Right) && expr.Operator != "-":
+		operand = expr.Left
+	default:
+		return "", nil, false
+	}
+
+	// Restore the parameter position when the operand is not numeric so the pipeline
+	// translation substitutes it again
+	start := *argIndex
+	value := t.substituteValue(operand.Value, argIndex)
+	if !isNumber(value) {
+		*argIndex = start
+		return "", nil, false
+	}
+
+	switch expr.Operator {
+	case "+":
+		return "$inc", value, true
+	case "-":
+		negated, ok := negateNumber(value)
+		if !ok {
+			*argIndex = start
+			return "", nil, false
+		}
+		return "$inc", negated, true
+	}
+	return "$mul", value, true
+}
+
+// updateExpression converts a computed SET value to an aggregation expression
+func (t *MongoDBSQLTranslator) updateExpression(expr *sql.Expression, argIndex *int) (any, error) {
+	switch {
+	case len(expr.Whens) > 0:
+		branches := make([]bson.M, 0, len(expr.Whens))
+		for _, when := range expr.Whens {
+			condition, err := t.conditionExpression(when.Condition, nil, argIndex)
+			if err != nil {
+				return nil, err
+			}
+			result, err := t.updateExpression(when.Result, argIndex)
+			if err != nil {
+				return nil, err
+			}
+			branches = append(branches, bson.M{"case": condition, "then": result})
+		}
+		var defaultValue any
+		if expr.Else != nil {
+			value, err := t.updateExpression(expr.Else, argIndex)
+			if err != nil {
+				return nil, err
+			}
+			defaultValue = value
+		}
+		return bson.M{"$switch": bson.M{"branches": branches, "default": defaultValue}}, nil
+
+	case expr.Operator != "":
+		operator, ok := arithmeticOperators[expr.Operator]
+		if !ok {
+			return nil, fmt.Errorf("unsupported operator: %s", expr.Operator)
+		}
+		left, err := t.updateExpression(expr.Left, argIndex)
+		if err != nil {
+			return nil, err
+		}
+		right, err := t.updateExpression(expr.Right, argIndex)
+		if err != nil {
+			return nil, err
+		}
+		return bson.M{operator: []any{left, right}}, nil
+
+	case expr.Function != "":
+		operator, ok := scalarFunctionOperators[strings.ToUpper(expr.Function)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported function: %s", expr.Function)
+		}
+		args := make([]any, len(expr.Args))
+		for i, arg := range expr.Args {
+			value, err := t.updateExpression(arg, argIndex)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = value
+		}
+		if len(args) == 1 && operator != "$round" {
+			return bson.M{operator: args[0]}, nil
+		}
+		return bson.M{operator: args}, nil
+
+	case expr.Field != "":
+		fieldName, err := t.mapFieldName(expr.Field)
+		if err != nil {
+			fieldName = expr.Field
+		}
+		return "$" + fieldName, nil
+	}
+
+	return literalExpression(t.substituteValue(expr.Value, argIndex)), nil
+}
+
+// conditionExpression converts a WHERE-style condition to a boolean aggregation expression.
+// scope is set inside correlated subqueries to resolve qualified column references.
+func (t *MongoDBSQLTranslator) conditionExpression(where *sql.WhereClause, scope *subqueryScope, argIndex *int) (any, error) {
+	switch strings.ToUpper(where.Operator) {
+	case "AND", "OR":
+		left, err := t.conditionExpression(where.Left, scope, argIndex)
+		if err != nil {
+			return nil, err
+		}
+		right, err := t.conditionExpression(where.Right, scope, argIndex)
+		if err != nil {
+			return nil, err
+		}
+		return bson.M{"$" + strings.ToLower(where.Operator): []any{left, right}}, nil
+	case "NOT":
+		inner, err := t.conditionExpression(where.Left, scope, argIndex)
+		if err != nil {
+			return nil, err
+		}
+		return bson.M{"$not": []any{inner}}, nil
+	}
+
+	cond := where.Condition
+	if cond == nil {
+		return true, nil
+	}
+	if cond.Subquery != nil {
+		return nil, fmt.Errorf("subqueries are not supported in CASE conditions")
+	}
+
+	var field string
+	if scope != nil {
+		field = scope.field(t, cond.Field)
+	} else {
+		fieldName, err := t.mapFieldName(cond.Field)
+		if err != nil {
+			fieldName = cond.Field
+		}
+		field = "$" + fieldName
+	}
+
+	comparisons := map[string]string{"=": "$eq", "!=": "$ne", "<>": "$ne", ">": "$gt", ">=": "$gte", "<": "$lt", "<=": "$lte"}
+	operator := strings.ToUpper(cond.Operator)
+	if op, ok := comparisons[operator]; ok {
+		// Inside subqueries the right side may be a column, e.g. p.user_id = u.id
+		if column, ok := cond.Value.(string); ok && scope != nil && scope.isColumn(column) {
+			return bson.M{op: []any{field, scope.field(t, column)}}, nil
+		}
+		return bson.M{op: []any{field, literalExpression(t.substituteValue(cond.Value, argIndex))}}, nil
+	}
+
+	switch operator {
+	case "LIKE":
+		pattern, ok := t.substituteValue(cond.Value, argIndex).(string)
+		if !ok {
+			return nil, fmt.Errorf("LIKE requires a string pattern")
+		}
+		return bson.M{"$regexMatch": bson.M{"input": field, "regex": t.convertLikeToRegex(pattern), "options": "i"}}, nil
+	case "IN", "NOT IN":
+		values := make([]any, len(cond.Values))
+		for i, v := range cond.Values {
+			values[i] = t.substituteValue(v, argIndex)
+		}
+		in := bson.M{"$in": []any{field, bson.M{"$literal": values}}}
+		if operator == "NOT IN" {
+			return bson.M{"$not": []any{in}}, nil
+		}
+		return in, nil
+	case "IS NULL":
+		// Missing fields compare lower than null, so this matches both
+		return bson.M{"$lte": []any{field, nil}}, nil
+	case "IS NOT NULL":
+		return bson.M{"$gt": []any{field, nil}}, nil
+	}
+	return nil, fmt.Errorf("unsupported operator in condition: %s", cond.Operator)
+}
+
+// literalExpression protects literal values that aggregation would read as field paths
+// or expressions
+func literalExpression(value any) any {
+	switch v := value.(type) {
+	case string:
+		if strings.HasPrefix(v, "$") {
+			return bson.M{"$literal": v}
+		}
+	case bson.M, map[string]any, []any:
+		return bson.M{"$literal": v}
+	}
+	return value
+}
+
+// isNumber reports whether value is a Go numeric type
+func isNumber(value any) bool {
+	switch value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return true
+	}
+	return false
+}
+
+// negateNumber returns -value for signed numeric types
+func negateNumber(value any) (any, bool) {
+	switch v := value.(type) {
+	case int:
+		return -v, true
+	case int8:
+		return -v, true
+	case int16:
+		return -v, true
+	case int32:
+		return -v, true
+	case int64:
+		return -v, true
+	case float32:
+		return -v, true
+	case float64:
+		return -v, true
+	}
+	return nil, false
 }
 
 // translateDelete converts DELETE statement to MongoDB command
@@ -1243,8 +1909,9 @@ func (t *MongoDBSQLTranslator) translateSubqueryCondition(fieldName string, subq
 	}, nil
 }
 
-// translateGroupByProject creates a $project stage after GROUP BY to restructure the result
-func (t *MongoDBSQLTranslator) translateGroupByProject(groupBy []string, fields []sql.SelectField) (bson.M, error) {
+// translateGroupByProject creates a $project stage after GROUP BY to restructure the result.
+// scalarKey is set when the $group _id holds the single group value instead of a document.
+func (t *MongoDBSQLTranslator) translateGroupByProject(groupBy []string, fields []sql.SelectField, scalarKey bool) (bson.M, error) {
 	projectStage := bson.M{}
 
 	// First, map all the SELECT fields to their expected names
@@ -1271,8 +1938,12 @@ func (t *MongoDBSQLTranslator) translateGroupByProject(groupBy []string, fields
 			for _, gbField := range groupBy {
 				if field.Expression == gbField {
 					// This field is in GROUP BY, so it's in _id
-					safeKey := strings.ReplaceAll(gbField, ".", "_")
-					projectStage[alias] = "$_id." + safeKey
+					if scalarKey {
+						projectStage[alias] = "$_id"
+					} else {
+						safeKey := strings.ReplaceAll(gbField, ".", "_")
+						projectStage[alias] = "$_id." + safeKey
+					}
 					found = true
 					break
 				}
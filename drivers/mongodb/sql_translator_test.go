@@ -140,8 +140,8 @@ func TestMongoDBSQLTranslator_TranslateSelect(t *testing.T) {
 				Operation:  "aggregate",
 				Collection: "users",
 				Pipeline: []bson.M{
-					{"$sort": bson.M{
-						"name": 1,
+					{"$sort": bson.D{
+						{Key: "name", Value: 1},
 					}},
 				},
 			},
@@ -167,8 +167,8 @@ func TestMongoDBSQLTranslator_TranslateSelect(t *testing.T) {
 					{"$match": bson.M{
 						"age": bson.M{"$gt": int64(25)},
 					}},
-					{"$sort": bson.M{
-						"name": -1,
+					{"$sort": bson.D{
+						{Key: "name", Value: -1},
 					}},
 					{"$limit": 5},
 					{"$project": bson.M{
@@ -202,6 +202,395 @@ func TestMongoDBSQLTranslator_TranslateSelect(t *testing.T) {
 	}
 }
 
+func TestMongoDBSQLTranslator_TranslateOrderBy(t *testing.T) {
+	baseDriver := base.NewDriver("test://", types.DriverMongoDB)
+	baseDriver.FieldMapper = &mockFieldMapper{}
+	db := &MongoDB{
+		Driver: baseDriver,
+	}
+	translator := NewMongoDBSQLTranslator(db)
+
+	tests := []struct {
+		name     string
+		sql      string
+		expected []bson.M
+	}{
+		{
+			name: "aggregate alias",
+			sql:  "SELECT user_id, COUNT(*) AS post_count FROM posts GROUP BY user_id ORDER BY COUNT(*) DESC, user_id",
+			expected: []bson.M{
+				{"$group": bson.M{
+					"_id":        "$user_id",
+					"post_count": bson.M{"$sum": 1},
+				}},
+				{"$project": bson.M{
+					"user_id":    "$_id",
+					"post_count": "$post_count",
+					"_id":        0,
+				}},
+				{"$sort": bson.D{
+					{Key: "post_count", Value: -1},
+					{Key: "user_id", Value: 1},
+				}},
+			},
+		},
+		{
+			name: "aggregate not in select list",
+			sql:  "SELECT user_id FROM posts GROUP BY user_id ORDER BY SUM(views) DESC",
+			expected: []bson.M{
+				{"$group": bson.M{
+					"_id":       bson.M{"user_id": "$user_id"},
+					"__order_1": bson.M{"$sum": "$views"},
+				}},
+				{"$project": bson.M{
+					"user_id":   "$_id.user_id",
+					"_id":       0,
+					"__order_1": "$__order_1",
+				}},
+				{"$sort": bson.D{
+					{Key: "__order_1", Value: -1},
+				}},
+				{"$project": bson.M{"__order_1": 0}},
+				{"$project": bson.M{"user_id": "$user_id"}},
+			},
+		},
+		{
+			name: "computed expression",
+			sql:  "SELECT * FROM posts ORDER BY views * 2 + 1 DESC",
+			expected: []bson.M{
+				{"$addFields": bson.M{
+					"__order_1": bson.M{"$add": []any{
+						bson.M{"$multiply": []any{"$views", int64(2)}},
+						int64(1),
+					}},
+				}},
+				{"$sort": bson.D{
+					{Key: "__order_1", Value: -1},
+				}},
+				{"$project": bson.M{"__order_1": 0}},
+			},
+		},
+		{
+			name: "select alias and position",
+			sql:  "SELECT name AS n, age FROM users ORDER BY 2 DESC, n",
+			expected: []bson.M{
+				{"$sort": bson.D{
+					{Key: "age", Value: -1},
+					{Key: "name", Value: 1},
+				}},
+				{"$project": bson.M{
+					"n":   "$name",
+					"age": "$age",
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt, err := sql.NewParser(tt.sql).Parse()
+			require.NoError(t, err)
+
+			result, err := translator.translateSelect(stmt.(*sql.SelectStatement))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Pipeline)
+		})
+	}
+
+	t.Run("qualified join fields", func(t *testing.T) {
+		stmt, err := sql.NewParser("SELECT p.title, u.name FROM posts p JOIN users u ON p.user_id = u.id ORDER BY u.name, p.title DESC").Parse()
+		require.NoError(t, err)
+
+		result, err := translator.translateSelect(stmt.(*sql.SelectStatement))
+		require.NoError(t, err)
+		assert.Contains(t, result.Pipeline, bson.M{"$sort": bson.D{
+			{Key: "u.name", Value: 1},
+			{Key: "title", Value: -1},
+		}})
+	})
+
+	t.Run("invalid terms", func(t *testing.T) {
+		for _, query := range []string{
+			"SELECT * FROM users ORDER BY COUNT(*)",
+			"SELECT name FROM users ORDER BY 3",
+			"SELECT role, COUNT(*) FROM users GROUP BY role ORDER BY name",
+		} {
+			stmt, err := sql.NewParser(query).Parse()
+			require.NoError(t, err)
+
+			_, err = translator.translateSelect(stmt.(*sql.SelectStatement))
+			assert.Error(t, err, query)
+		}
+	})
+}
+
+func TestMongoDBSQLTranslator_TranslateSubqueries(t *testing.T) {
+	baseDriver := base.NewDriver("test://", types.DriverMongoDB)
+	baseDriver.FieldMapper = &mockFieldMapper{}
+	db := &MongoDB{
+		Driver: baseDriver,
+	}
+	translator := NewMongoDBSQLTranslator(db)
+
+	tests := []struct {
+		name     string
+		sql      string
+		args     []any
+		expected []bson.M
+	}{
+		{
+			name: "correlated EXISTS",
+			sql:  "SELECT * FROM users u WHERE EXISTS (SELECT 1 FROM posts p WHERE p.user_id = u.id AND p.published = ?)",
+			args: []any{true},
+			expected: []bson.M{
+				{"$lookup": bson.M{
+					"from": "posts",
+					"let":  bson.M{"outer_id": "$_id"},
+					"pipeline": []bson.M{
+						{"$match": bson.M{"$expr": bson.M{"$and": []any{
+							bson.M{"$eq": []any{"$user_id", "$$outer_id"}},
+							bson.M{"$eq": []any{"$published", true}},
+						}}}},
+						{"$limit": 1},
+						{"$project": bson.M{"_id": 1}},
+					},
+					"as": "__subquery_0",
+				}},
+				{"$match": bson.M{"__subquery_0": bson.M{"$ne": bson.A{}}}},
+				{"$project": bson.M{"__subquery_0": 0}},
+			},
+		},
+		{
+			name: "NOT EXISTS combined with a column condition",
+			sql:  "SELECT * FROM users WHERE age > ? AND NOT EXISTS (SELECT 1 FROM posts WHERE posts.user_id = users.id)",
+			args: []any{18},
+			expected: []bson.M{
+				{"$lookup": bson.M{
+					"from": "posts",
+					"let":  bson.M{"outer_id": "$_id"},
+					"pipeline": []bson.M{
+						{"$match": bson.M{"$expr": bson.M{"$eq": []any{"$user_id", "$$outer_id"}}}},
+						{"$limit": 1},
+						{"$project": bson.M{"_id": 1}},
+					},
+					"as": "__subquery_0",
+				}},
+				{"$match": bson.M{"$and": []bson.M{
+					{"age": bson.M{"$gt": 18}},
+					{"__subquery_0": bson.M{"$size": 0}},
+				}}},
+				{"$project": bson.M{"__subquery_0": 0}},
+			},
+		},
+		{
+			name: "correlated IN",
+			sql:  "SELECT * FROM users u WHERE u.id IN (SELECT p.user_id FROM posts p WHERE p.views > u.age)",
+			expected: []bson.M{
+				{"$lookup": bson.M{
+					"from": "posts",
+					"let":  bson.M{"outer_age": "$age", "outer_in": "$_id"},
+					"pipeline": []bson.M{
+						{"$match": bson.M{"$expr": bson.M{"$and": []any{
+							bson.M{"$gt": []any{"$views", "$$outer_age"}},
+							bson.M{"$eq": []any{"$user_id", "$$outer_in"}},
+						}}}},
+						{"$limit": 1},
+						{"$project": bson.M{"_id": 1}},
+					},
+					"as": "__subquery_0",
+				}},
+				{"$match": bson.M{"__subquery_0": bson.M{"$ne": bson.A{}}}},
+				{"$project": bson.M{"__subquery_0": 0}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt, err := sql.NewParser(tt.sql).Parse()
+			require.NoError(t, err)
+
+			translator.SetArgs(tt.args)
+			result, err := translator.translateSelect(stmt.(*sql.SelectStatement))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Pipeline)
+		})
+	}
+
+	t.Run("uncorrelated NOT IN keeps subquery marker", func(t *testing.T) {
+		stmt, err := sql.NewParser("SELECT * FROM users WHERE id NOT IN (SELECT user_id FROM posts)").Parse()
+		require.NoError(t, err)
+
+		translator.SetArgs(nil)
+		result, err := translator.translateSelect(stmt.(*sql.SelectStatement))
+		require.NoError(t, err)
+		marker := result.Pipeline[0]["$match"].(bson.M)["__subquery__"].(bson.M)
+		assert.Equal(t, "$nin", marker["operator"])
+	})
+
+	t.Run("EXISTS outside SELECT", func(t *testing.T) {
+		stmt, err := sql.NewParser("DELETE FROM users WHERE EXISTS (SELECT 1 FROM posts WHERE posts.user_id = users.id)").Parse()
+		require.NoError(t, err)
+
+		_, err = translator.TranslateToCommand(stmt)
+		assert.Error(t, err)
+	})
+}
+
+func TestMongoDBSQLTranslator_TranslateSetOperations(t *testing.T) {
+	baseDriver := base.NewDriver("test://", types.DriverMongoDB)
+	baseDriver.FieldMapper = &mockFieldMapper{}
+	db := &MongoDB{
+		Driver: baseDriver,
+	}
+	translator := NewMongoDBSQLTranslator(db)
+
+	translate := func(t *testing.T, query string, args ...any) *MongoDBCommand {
+		stmt, err := sql.NewParser(query).Parse()
+		require.NoError(t, err)
+		translator.SetArgs(args)
+		cmd, err := translator.TranslateToCommand(stmt)
+		require.NoError(t, err)
+		return cmd
+	}
+
+	t.Run("UNION ALL", func(t *testing.T) {
+		cmd := translate(t, "SELECT name FROM users WHERE age > ? UNION ALL SELECT title FROM posts WHERE views > ? ORDER BY 1 DESC LIMIT 3", 18, 10)
+		assert.Equal(t, "aggregate", cmd.Operation)
+		assert.Equal(t, "users", cmd.Collection)
+		assert.Equal(t, []bson.M{
+			{"$match": bson.M{"age": bson.M{"$gt": 18}}},
+			{"$project": bson.M{"name": "$name"}},
+			{"$replaceRoot": bson.M{"newRoot": bson.D{{Key: "name", Value: "$name"}}}},
+			{"$unionWith": bson.M{"coll": "posts", "pipeline": []bson.M{
+				{"$match": bson.M{"views": bson.M{"$gt": 10}}},
+				{"$project": bson.M{"title": "$title"}},
+				{"$replaceRoot": bson.M{"newRoot": bson.D{{Key: "name", Value: "$title"}}}},
+			}}},
+			{"$sort": bson.D{{Key: "name", Value: -1}}},
+			{"$limit": 3},
+		}, cmd.Pipeline)
+	})
+
+	t.Run("UNION removes duplicates", func(t *testing.T) {
+		cmd := translate(t, "SELECT * FROM users UNION SELECT * FROM admins")
+		assert.Equal(t, []bson.M{
+			{"$unionWith": bson.M{"coll": "admins", "pipeline": []bson.M{}}},
+			{"$group": bson.M{"_id": "$$ROOT"}},
+			{"$replaceRoot": bson.M{"newRoot": "$_id"}},
+		}, cmd.Pipeline)
+	})
+
+	t.Run("INTERSECT and EXCEPT", func(t *testing.T) {
+		cmd := translate(t, "SELECT email FROM users INTERSECT SELECT email FROM admins")
+		require.Len(t, cmd.Pipeline, 7)
+		assert.Equal(t, bson.M{"$replaceRoot": bson.M{"newRoot": bson.M{"row": "$$ROOT", "side": 0}}}, cmd.Pipeline[2])
+		assert.Equal(t, bson.M{"$match": bson.M{"left": bson.M{"$gt": 0}, "right": bson.M{"$gt": 0}}}, cmd.Pipeline[5])
+		assert.Equal(t, bson.M{"$replaceRoot": bson.M{"newRoot": "$_id"}}, cmd.Pipeline[6])
+
+		cmd = translate(t, "SELECT email FROM users EXCEPT SELECT email FROM admins")
+		assert.Contains(t, cmd.Pipeline, bson.M{"$match": bson.M{"left": bson.M{"$gt": 0}, "right": 0}})
+
+		cmd = translate(t, "SELECT email FROM users EXCEPT ALL SELECT email FROM admins")
+		assert.Contains(t, cmd.Pipeline, bson.M{"$project": bson.M{"copies": bson.M{"$range": []any{0,
+			bson.M{"$max": []any{bson.M{"$subtract": []any{"$left", "$right"}}, 0}}}}}})
+		assert.Contains(t, cmd.Pipeline, bson.M{"$unwind": "$copies"})
+	})
+
+	t.Run("column count mismatch", func(t *testing.T) {
+		stmt, err := sql.NewParser("SELECT name, email FROM users UNION SELECT title FROM posts").Parse()
+		require.NoError(t, err)
+		_, err = translator.TranslateToCommand(stmt)
+		assert.Error(t, err)
+	})
+}
+
+func TestMongoDBSQLTranslator_TranslateWindowFunctions(t *testing.T) {
+	baseDriver := base.NewDriver("test://", types.DriverMongoDB)
+	baseDriver.FieldMapper = &mockFieldMapper{}
+	db := &MongoDB{
+		Driver: baseDriver,
+	}
+	translator := NewMongoDBSQLTranslator(db)
+
+	tests := []struct {
+		name     string
+		sql      string
+		expected []bson.M
+	}{
+		{
+			name: "ranking per partition",
+			sql:  "SELECT title, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY views DESC) AS rn, RANK() OVER (PARTITION BY user_id ORDER BY views DESC) AS rank FROM posts ORDER BY rn",
+			expected: []bson.M{
+				{"$setWindowFields": bson.M{
+					"partitionBy": "$user_id",
+					"sortBy":      bson.D{{Key: "views", Value: -1}},
+					"output": bson.M{
+						"rn":   bson.M{"$documentNumber": bson.M{}},
+						"rank": bson.M{"$rank": bson.M{}},
+					},
+				}},
+				{"$sort": bson.D{{Key: "rn", Value: 1}}},
+				{"$project": bson.M{"title": "$title", "rn": "$rn", "rank": "$rank"}},
+			},
+		},
+		{
+			name: "running totals and offsets",
+			sql:  "SELECT id, SUM(views) OVER (ORDER BY id) AS running, COUNT(*) OVER () AS total, LAG(views, 2, 0) OVER (ORDER BY id) AS prev FROM posts",
+			expected: []bson.M{
+				{"$setWindowFields": bson.M{
+					"sortBy": bson.D{{Key: "_id", Value: 1}},
+					"output": bson.M{
+						"running": bson.M{"$sum": "$views", "window": bson.M{"documents": bson.A{"unbounded", "current"}}},
+						"prev":    bson.M{"$shift": bson.M{"output": "$views", "by": int64(-2), "default": int64(0)}},
+					},
+				}},
+				{"$setWindowFields": bson.M{
+					"output": bson.M{"total": bson.M{"$sum": 1}},
+				}},
+				{"$project": bson.M{"id": "$_id", "running": "$running", "total": "$total", "prev": "$prev"}},
+			},
+		},
+		{
+			name: "window over grouped rows",
+			sql:  "SELECT user_id, SUM(views) AS total, DENSE_RANK() OVER (ORDER BY SUM(views) DESC) AS position FROM posts GROUP BY user_id",
+			expected: []bson.M{
+				{"$group": bson.M{"_id": "$user_id", "total": bson.M{"$sum": "$views"}}},
+				{"$project": bson.M{"user_id": "$_id", "total": "$total", "_id": 0}},
+				{"$setWindowFields": bson.M{
+					"sortBy": bson.D{{Key: "total", Value: -1}},
+					"output": bson.M{"position": bson.M{"$denseRank": bson.M{}}},
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt, err := sql.NewParser(tt.sql).Parse()
+			require.NoError(t, err)
+
+			result, err := translator.translateSelect(stmt.(*sql.SelectStatement))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Pipeline)
+		})
+	}
+
+	t.Run("invalid windows", func(t *testing.T) {
+		for _, query := range []string{
+			"SELECT ROW_NUMBER() OVER (PARTITION BY user_id) AS rn FROM posts",
+			"SELECT NTILE(4) OVER (ORDER BY views) AS bucket FROM posts",
+			"SELECT COUNT(*), ROW_NUMBER() OVER (ORDER BY id) FROM posts",
+		} {
+			stmt, err := sql.NewParser(query).Parse()
+			require.NoError(t, err)
+
+			_, err = translator.translateSelect(stmt.(*sql.SelectStatement))
+			assert.Error(t, err, query)
+		}
+	})
+}
+
 func TestMongoDBSQLTranslator_TranslateInsert(t *testing.T) {
 	baseDriver := base.NewDriver("test://", types.DriverMongoDB)
 	baseDriver.FieldMapper = &mockFieldMapper{}
@@ -279,6 +668,7 @@ func TestMongoDBSQLTranslator_TranslateUpdate(t *testing.T) {
 	tests := []struct {
 		name     string
 		sql      string
+		args     []any
 		expected *MongoDBCommand
 	}{
 		{
@@ -312,6 +702,49 @@ func TestMongoDBSQLTranslator_TranslateUpdate(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "UPDATE with atomic increments",
+			sql:  "UPDATE posts SET views = views + 1, likes = likes - ?, score = score * 2, title = ? WHERE id = ?",
+			args: []any{3, "Updated", 7},
+			expected: &MongoDBCommand{
+				Operation:  "update",
+				Collection: "posts",
+				Filter: bson.M{
+					"_id": 7,
+				},
+				Update: bson.M{
+					"$set": bson.M{"title": "Updated"},
+					"$inc": bson.M{"views": int64(1), "likes": -3},
+					"$mul": bson.M{"score": int64(2)},
+				},
+			},
+		},
+		{
+			name: "UPDATE with expressions and CASE",
+			sql:  "UPDATE posts SET views = views + 1, score = views * 2 + likes, status = CASE WHEN views >= ? THEN 'hot' WHEN published = false THEN 'draft' ELSE status END",
+			args: []any{100},
+			expected: &MongoDBCommand{
+				Operation:  "update",
+				Collection: "posts",
+				Filter:     bson.M{},
+				Pipeline: []bson.M{
+					{"$set": bson.M{
+						"views": bson.M{"$add": []any{"$views", int64(1)}},
+						"score": bson.M{"$add": []any{
+							bson.M{"$multiply": []any{"$views", int64(2)}},
+							"$likes",
+						}},
+						"status": bson.M{"$switch": bson.M{
+							"branches": []bson.M{
+								{"case": bson.M{"$gte": []any{"$views", 100}}, "then": "hot"},
+								{"case": bson.M{"$eq": []any{"$published", false}}, "then": "draft"},
+							},
+							"default": "$status",
+						}},
+					}},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -323,6 +756,7 @@ func TestMongoDBSQLTranslator_TranslateUpdate(t *testing.T) {
 			updateStmt, ok := stmt.(*sql.UpdateStatement)
 			require.True(t, ok)
 
+			translator.SetArgs(tt.args)
 			result, err := translator.translateUpdate(updateStmt)
 			require.NoError(t, err)
 
@@ -330,6 +764,7 @@ func TestMongoDBSQLTranslator_TranslateUpdate(t *testing.T) {
 			assert.Equal(t, tt.expected.Collection, result.Collection)
 			assert.Equal(t, tt.expected.Filter, result.Filter)
 			assert.Equal(t, tt.expected.Update, result.Update)
+			assert.Equal(t, tt.expected.Pipeline, result.Pipeline)
 		})
 	}
 }
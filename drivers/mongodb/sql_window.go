@@ -0,0 +1,242 @@
+package mongodb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rediwo/redi-orm/sql"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// windowRankOperators maps ranking functions to $setWindowFields operators
+var windowRankOperators = map[string]string{
+	"ROW_NUMBER": "$documentNumber",
+	"RANK":       "$rank",
+	"DENSE_RANK": "$denseRank",
+}
+
+// windowAccumulators maps aggregate window functions to $setWindowFields accumulators
+var windowAccumulators = map[string]string{
+	"SUM":         "$sum",
+	"AVG":         "$avg",
+	"MIN":         "$min",
+	"MAX":         "$max",
+	"COUNT":       "$sum",
+	"FIRST_VALUE": "$first",
+	"LAST_VALUE":  "$last",
+}
+
+// hasWindowFunctions reports whether any SELECT field is a window function
+func hasWindowFunctions(fields []sql.SelectField) bool {
+	for _, field := range fields {
+		if field.Window != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutWindowFunctions returns the SELECT fields that are not window functions
+func withoutWindowFunctions(fields []sql.SelectField) []sql.SelectField {
+	if !hasWindowFunctions(fields) {
+		return fields
+	}
+	plain := make([]sql.SelectField, 0, len(fields))
+	for _, field := range fields {
+		if field.Window == nil {
+			plain = append(plain, field)
+		}
+	}
+	return plain
+}
+
+// windowOutputName returns the field holding a window function result; unaliased
+// functions use the lowercase function name
+func windowOutputName(field sql.SelectField) string {
+	if field.Alias != "" {
+		return field.Alias
+	}
+	return strings.ToLower(field.Window.Function.Function)
+}
+
+// translateWindowFields converts window functions into $setWindowFields stages, one per
+// distinct OVER clause. grouped is set when the windows run over GROUP BY result rows.
+func (t *MongoDBSQLTranslator) translateWindowFields(stmt *sql.SelectStatement, grouped bool) ([]bson.M, error) {
+	var stages []bson.M
+	outputs := map[string]bson.M{} // OVER clause → output of its stage
+
+	for _, field := range stmt.Fields {
+		if field.Window == nil {
+			continue
+		}
+
+		over := field.Window.OverString()
+		output, ok := outputs[over]
+		if !ok {
+			spec, err := t.windowSpec(field.Window, stmt, grouped)
+			if err != nil {
+				return nil, err
+			}
+			output = bson.M{}
+			spec["output"] = output
+			stages = append(stages, bson.M{"$setWindowFields": spec})
+			outputs[over] = output
+		}
+
+		operator, err := t.windowOperator(field.Window, stmt, grouped)
+		if err != nil {
+			return nil, err
+		}
+		output[windowOutputName(field)] = operator
+	}
+
+	return stages, nil
+}
+
+// windowSpec builds the partitionBy and sortBy of a $setWindowFields stage
+func (t *MongoDBSQLTranslator) windowSpec(window *sql.WindowSpec, stmt *sql.SelectStatement, grouped bool) (bson.M, error) {
+	spec := bson.M{}
+
+	switch len(window.PartitionBy) {
+	case 0:
+	case 1:
+		spec["partitionBy"] = "$" + t.windowField(window.PartitionBy[0], stmt, grouped)
+	default:
+		partition := bson.M{}
+		for _, name := range window.PartitionBy {
+			path := t.windowField(name, stmt, grouped)
+			partition[strings.ReplaceAll(path, ".", "_")] = "$" + path
+		}
+		spec["partitionBy"] = partition
+	}
+
+	if len(window.OrderBy) > 0 {
+		sortBy := bson.D{}
+		for _, clause := range window.OrderBy {
+			direction := 1
+			if clause.Direction == sql.OrderDirectionDesc {
+				direction = -1
+			}
+
+			name := clause.Field
+			if clause.Expr != nil {
+				// Grouped windows may sort by an aggregate of the select list
+				field, ok := selectFieldFor(stmt.Fields, clause.Expr.String())
+				if !ok || !grouped {
+					return nil, fmt.Errorf("unsupported ORDER BY term in window: %s", clause.Expr.String())
+				}
+				name = selectOutputName(field)
+			}
+			sortBy = append(sortBy, bson.E{Key: t.windowField(name, stmt, grouped), Value: direction})
+		}
+		spec["sortBy"] = sortBy
+	}
+
+	return spec, nil
+}
+
+// windowOperator converts the window function call to its $setWindowFields output operator
+func (t *MongoDBSQLTranslator) windowOperator(window *sql.WindowSpec, stmt *sql.SelectStatement, grouped bool) (bson.M, error) {
+	function := strings.ToUpper(window.Function.Function)
+	args := window.Function.Args
+
+	argField := func(i int) (string, error) {
+		if i >= len(args) || args[i].Field == "" || args[i].Field == "*" {
+			return "", fmt.Errorf("%s requires a column argument", function)
+		}
+		return "$" + t.windowField(args[i].Field, stmt, grouped), nil
+	}
+
+	if operator, ok := windowRankOperators[function]; ok {
+		if len(window.OrderBy) == 0 {
+			return nil, fmt.Errorf("%s requires ORDER BY in its window", function)
+		}
+		return bson.M{operator: bson.M{}}, nil
+	}
+
+	switch function {
+	case "LAG", "LEAD":
+		if len(window.OrderBy) == 0 {
+			return nil, fmt.Errorf("%s requires ORDER BY in its window", function)
+		}
+		output, err := argField(0)
+		if err != nil {
+			return nil, err
+		}
+
+		offset := int64(1)
+		if len(args) > 1 {
+			n, ok := args[1].Value.(int64)
+			if !ok {
+				return nil, fmt.Errorf("%s offset must be an integer", function)
+			}
+			offset = n
+		}
+		if function == "LAG" {
+			offset = -offset
+		}
+
+		shift := bson.M{"output": output, "by": offset}
+		if len(args) > 2 {
+			if args[2].Value == "?" {
+				return nil, fmt.Errorf("parameters are not supported in window functions")
+			}
+			shift["default"] = args[2].Value
+		}
+		return bson.M{"$shift": shift}, nil
+	}
+
+	accumulator, ok := windowAccumulators[function]
+	if !ok {
+		return nil, fmt.Errorf("unsupported window function: %s", function)
+	}
+
+	var value any
+	switch {
+	case function == "COUNT" && (len(args) == 0 || args[0].Field == "*"):
+		value = 1
+	case function == "COUNT":
+		// COUNT(column) skips nulls
+		field, err := argField(0)
+		if err != nil {
+			return nil, err
+		}
+		value = bson.M{"$cond": []any{bson.M{"$gt": []any{field, nil}}, 1, 0}}
+	default:
+		field, err := argField(0)
+		if err != nil {
+			return nil, err
+		}
+		value = field
+	}
+
+	operator := bson.M{accumulator: value}
+	// With ORDER BY the SQL default frame runs from the partition start to the current row
+	if len(window.OrderBy) > 0 {
+		operator["window"] = bson.M{"documents": bson.A{"unbounded", "current"}}
+	}
+	return operator, nil
+}
+
+// windowField resolves a column referenced in a window to a document path. Grouped
+// windows see the GROUP BY output, which is keyed by select names.
+func (t *MongoDBSQLTranslator) windowField(name string, stmt *sql.SelectStatement, grouped bool) string {
+	if !grouped {
+		return t.fieldPath(name, stmt.From, stmt.Joins)
+	}
+	if field, ok := selectFieldFor(stmt.Fields, name); ok {
+		return selectOutputName(field)
+	}
+	parts := strings.Split(name, ".")
+	return parts[len(parts)-1]
+}
+
+// selectFieldFor finds the select field with the given expression
+func selectFieldFor(fields []sql.SelectField, expression string) (sql.SelectField, bool) {
+	for _, field := range fields {
+		if field.Window == nil && strings.EqualFold(field.Expression, expression) {
+			return field, true
+		}
+	}
+	return sql.SelectField{}, false
+}
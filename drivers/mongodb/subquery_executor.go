@@ -44,10 +44,10 @@ func (e *SubqueryExecutor) ExecuteSubquery(ctx context.Context, subquery *sql.Se
 	var results []map[string]any
 	switch mongoCmd.Operation {
 	case "find":
-		rawQuery := NewMongoDBRawQuery(e.db.client.Database(e.db.dbName), nil, e.db, "", args...)
+		rawQuery := NewMongoDBRawQuery(e.db.client.Database(e.db.dbName), e.db.session, e.db, "", args...)
 		err = rawQuery.executeFind(ctx, collection, mongoCmd, &results)
 	case "aggregate":
-		rawQuery := NewMongoDBRawQuery(e.db.client.Database(e.db.dbName), nil, e.db, "", args...)
+		rawQuery := NewMongoDBRawQuery(e.db.client.Database(e.db.dbName), e.db.session, e.db, "", args...)
 		err = rawQuery.executeAggregate(ctx, collection, mongoCmd, &results)
 	default:
 		return nil, fmt.Errorf("unsupported subquery operation: %s", mongoCmd.Operation)
@@ -3,70 +3,86 @@ package mongodb
 import (
 	"context"
 	"fmt"
+	"sync"
 
+	"github.com/rediwo/redi-orm/query"
 	"github.com/rediwo/redi-orm/types"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// MongoDBTransaction implements the Transaction interface for MongoDB
+// MongoDBTransaction implements the Transaction interface for MongoDB using a client
+// session with a multi-document transaction
 type MongoDBTransaction struct {
 	session mongo.Session
-	db      *MongoDB
-	ctx     context.Context
+	db      *MongoDB // bound to session, so every query built from it joins the transaction
+	done    bool
 }
 
-// NewMongoDBTransaction creates a new MongoDB transaction
+// NewMongoDBTransaction creates a new MongoDB transaction for a session with a started transaction
 func NewMongoDBTransaction(session mongo.Session, db *MongoDB) *MongoDBTransaction {
 	return &MongoDBTransaction{
 		session: session,
-		db:      db,
+		db:      db.withSession(session),
 	}
 }
 
 // Model creates a new model query within the transaction
 func (t *MongoDBTransaction) Model(modelName string) types.ModelQuery {
-	// Create a MongoDB model query
-	mongoModelQuery := NewMongoDBModelQuery(t.db, modelName)
-	// Wrap it to use the session
-	return &transactionModelQuery{
-		ModelQuery: mongoModelQuery,
-		session:    t.session,
-		db:         t.db,
-	}
+	return NewMongoDBModelQuery(t.db, modelName)
 }
 
 // Raw creates a new raw query within the transaction
 func (t *MongoDBTransaction) Raw(sql string, args ...any) types.RawQuery {
-	// MongoDB doesn't use SQL, so this would be a raw MongoDB command
-	return NewMongoDBRawQuery(t.db.client.Database(t.db.dbName), t.session, t.db, sql, args...)
+	return t.db.Raw(sql, args...)
 }
 
-// Commit commits the transaction
+// Commit commits the transaction and ends its session
 func (t *MongoDBTransaction) Commit(ctx context.Context) error {
+	defer types.ForgetDeduplicated(ctx)
+	if t.done {
+		return fmt.Errorf("transaction has already been committed or rolled back")
+	}
+	t.done = true
+	defer t.session.EndSession(ctx)
 	return t.session.CommitTransaction(ctx)
 }
 
-// Rollback rolls back the transaction
+// Rollback aborts the transaction and ends its session. Rolling back a finished
+// transaction is a no-op, so it can be deferred after Commit.
 func (t *MongoDBTransaction) Rollback(ctx context.Context) error {
+	defer types.ForgetDeduplicated(ctx)
+	if t.done {
+		return nil
+	}
+	t.done = true
+	defer t.session.EndSession(ctx)
 	return t.session.AbortTransaction(ctx)
 }
 
-// Savepoint creates a savepoint (not supported in MongoDB)
+// Savepoint creates a savepoint (not supported in MongoDB, see SupportsSavepoints)
 func (t *MongoDBTransaction) Savepoint(ctx context.Context, name string) error {
-	return fmt.Errorf("savepoints are not supported in MongoDB")
+	return fmt.Errorf("MongoDB: %w", types.ErrSavepointsNotSupported)
 }
 
-// RollbackTo rolls back to a savepoint (not supported in MongoDB)
+// RollbackTo rolls back to a savepoint (not supported in MongoDB, see SupportsSavepoints)
 func (t *MongoDBTransaction) RollbackTo(ctx context.Context, name string) error {
-	return fmt.Errorf("savepoints are not supported in MongoDB")
+	return fmt.Errorf("MongoDB: %w", types.ErrSavepointsNotSupported)
 }
 
 // CreateMany performs batch insert within the transaction
 func (t *MongoDBTransaction) CreateMany(ctx context.Context, modelName string, data []any) (types.Result, error) {
 	collection := t.db.client.Database(t.db.dbName).Collection(t.db.getCollectionName(modelName))
 
+	for _, record := range data {
+		if recordMap, ok := record.(map[string]any); ok {
+			if err := query.ValidateData(t.db, modelName, recordMap); err != nil {
+				return types.Result{}, err
+			}
+		}
+	}
+
 	// Convert data to documents
 	documents := make([]any, len(data))
 	copy(documents, data)
@@ -89,6 +105,12 @@ func (t *MongoDBTransaction) UpdateMany(ctx context.Context, modelName string, c
 	// Convert condition to MongoDB filter
 	filter := t.db.conditionToFilter(condition)
 
+	if dataMap, ok := data.(map[string]any); ok {
+		if err := query.ValidateData(t.db, modelName, dataMap); err != nil {
+			return types.Result{}, err
+		}
+	}
+
 	// Convert data to update document
 	update := bson.M{"$set": data}
 
@@ -121,192 +143,60 @@ func (t *MongoDBTransaction) DeleteMany(ctx context.Context, modelName string, c
 	}, nil
 }
 
-// transactionModelQuery wraps a ModelQuery to use the transaction session
-type transactionModelQuery struct {
-	types.ModelQuery
-	session mongo.Session
-	db      *MongoDB
-}
+// errTransactionsUnsupported is returned when the server is a standalone mongod
+var errTransactionsUnsupported = fmt.Errorf("MongoDB transactions require a replica set or sharded cluster; " +
+	"the server is a standalone instance (start mongod with --replSet, or use a single-node replica set for development)")
 
-// Select creates a select query that uses the transaction session
-func (t *transactionModelQuery) Select(fields ...string) types.SelectQuery {
-	baseSelect := t.ModelQuery.Select(fields...)
-	return &transactionSelectQuery{
-		SelectQuery: baseSelect,
-		session:     t.session,
-		db:          t.db,
-	}
+// transactionSupport caches whether the connected deployment supports transactions
+type transactionSupport struct {
+	mu      sync.Mutex
+	checked bool
+	err     error
 }
 
-// Insert creates an insert query that uses the transaction session
-func (t *transactionModelQuery) Insert(data any) types.InsertQuery {
-	baseInsert := t.ModelQuery.Insert(data)
-	return &transactionInsertQuery{
-		InsertQuery: baseInsert,
-		session:     t.session,
-		db:          t.db,
+// checkTransactionSupport asks the server for its topology once and reports an error
+// on standalone servers, which reject multi-document transactions
+func (m *MongoDB) checkTransactionSupport(ctx context.Context) error {
+	if m.client == nil {
+		return fmt.Errorf("not connected to MongoDB")
 	}
-}
-
-// Update creates an update query that uses the transaction session
-func (t *transactionModelQuery) Update(data any) types.UpdateQuery {
-	baseUpdate := t.ModelQuery.Update(data)
-	return &transactionUpdateQuery{
-		UpdateQuery: baseUpdate,
-		session:     t.session,
-		db:          t.db,
-	}
-}
 
-// Delete creates a delete query that uses the transaction session
-func (t *transactionModelQuery) Delete() types.DeleteQuery {
-	baseDelete := t.ModelQuery.Delete()
-	return &transactionDeleteQuery{
-		DeleteQuery: baseDelete,
-		session:     t.session,
-		db:          t.db,
+	m.txSupport.mu.Lock()
+	defer m.txSupport.mu.Unlock()
+	if m.txSupport.checked {
+		return m.txSupport.err
 	}
-}
-
-// transactionSelectQuery wraps a SelectQuery to use the transaction session
-type transactionSelectQuery struct {
-	types.SelectQuery
-	session mongo.Session
-	db      *MongoDB
-}
 
-// FindMany executes the query within the transaction
-func (t *transactionSelectQuery) FindMany(ctx context.Context, dest any) error {
-	// Get the MongoDB-specific select query
-	mongoSelect, ok := t.SelectQuery.(*MongoDBSelectQuery)
-	if !ok {
-		return fmt.Errorf("expected MongoDBSelectQuery, got %T", t.SelectQuery)
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
 	}
-
-	// Build the command
-	sql, _, err := mongoSelect.BuildSQL()
+	admin := m.client.Database("admin")
+	err := admin.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
 	if err != nil {
-		return fmt.Errorf("failed to build command: %w", err)
-	}
-
-	// Create a raw query with session
-	rawQuery := NewMongoDBRawQuery(t.db.client.Database(t.db.dbName), t.session, t.db, sql)
-	return rawQuery.Find(ctx, dest)
-}
-
-// FindOne executes the query within the transaction and returns a single result
-func (t *transactionSelectQuery) FindOne(ctx context.Context, dest any) error {
-	// Get the MongoDB-specific select query
-	mongoSelect, ok := t.SelectQuery.(*MongoDBSelectQuery)
-	if !ok {
-		return fmt.Errorf("expected MongoDBSelectQuery, got %T", t.SelectQuery)
+		// Servers before 4.4.2 only know the legacy command
+		err = admin.RunCommand(ctx, bson.D{{Key: "isMaster", Value: 1}}).Decode(&hello)
 	}
-
-	// Build the command
-	sql, _, err := mongoSelect.BuildSQL()
 	if err != nil {
-		return fmt.Errorf("failed to build command: %w", err)
+		return fmt.Errorf("failed to detect MongoDB topology: %w", err)
 	}
 
-	// Create a raw query with session
-	rawQuery := NewMongoDBRawQuery(t.db.client.Database(t.db.dbName), t.session, t.db, sql)
-	return rawQuery.FindOne(ctx, dest)
-}
-
-// transactionInsertQuery wraps an InsertQuery to use the transaction session
-type transactionInsertQuery struct {
-	types.InsertQuery
-	session mongo.Session
-	db      *MongoDB
-}
-
-// Exec executes the insert within the transaction
-func (t *transactionInsertQuery) Exec(ctx context.Context) (types.Result, error) {
-	// Get the MongoDB-specific insert query
-	mongoInsert, ok := t.InsertQuery.(*MongoDBInsertQuery)
-	if !ok {
-		return types.Result{}, fmt.Errorf("expected MongoDBInsertQuery, got %T", t.InsertQuery)
-	}
-
-	// Build the command
-	sql, args, err := mongoInsert.BuildSQL()
-	if err != nil {
-		return types.Result{}, fmt.Errorf("failed to build command: %w", err)
+	m.txSupport.checked = true
+	if !supportsTransactions(hello.SetName, hello.Msg) {
+		m.txSupport.err = errTransactionsUnsupported
 	}
-
-	// Create a raw query with session
-	rawQuery := NewMongoDBRawQuery(t.db.client.Database(t.db.dbName), t.session, t.db, sql, args...)
-	return rawQuery.Exec(ctx)
-}
-
-// transactionUpdateQuery wraps an UpdateQuery to use the transaction session
-type transactionUpdateQuery struct {
-	types.UpdateQuery
-	session mongo.Session
-	db      *MongoDB
+	return m.txSupport.err
 }
 
-// WhereCondition overrides the base WhereCondition to maintain transaction wrapper
-func (t *transactionUpdateQuery) WhereCondition(condition types.Condition) types.UpdateQuery {
-	baseQuery := t.UpdateQuery.WhereCondition(condition)
-	return &transactionUpdateQuery{
-		UpdateQuery: baseQuery,
-		session:     t.session,
-		db:          t.db,
-	}
+// supportsTransactions reports whether a hello response describes a replica set member
+// or a mongos router
+func supportsTransactions(setName, msg string) bool {
+	return setName != "" || msg == "isdbgrid"
 }
 
-// Exec executes the update within the transaction
-func (t *transactionUpdateQuery) Exec(ctx context.Context) (types.Result, error) {
-	// Get the MongoDB-specific update query
-	mongoUpdate, ok := t.UpdateQuery.(*MongoDBUpdateQuery)
-	if !ok {
-		return types.Result{}, fmt.Errorf("expected MongoDBUpdateQuery, got %T", t.UpdateQuery)
-	}
-
-	// Build the command
-	sql, args, err := mongoUpdate.BuildSQL()
-	if err != nil {
-		return types.Result{}, fmt.Errorf("failed to build command: %w", err)
-	}
-
-	// Create a raw query with session
-	rawQuery := NewMongoDBRawQuery(t.db.client.Database(t.db.dbName), t.session, t.db, sql, args...)
-	return rawQuery.Exec(ctx)
-}
-
-// transactionDeleteQuery wraps a DeleteQuery to use the transaction session
-type transactionDeleteQuery struct {
-	types.DeleteQuery
-	session mongo.Session
-	db      *MongoDB
-}
-
-// WhereCondition overrides the base WhereCondition to maintain transaction wrapper
-func (t *transactionDeleteQuery) WhereCondition(condition types.Condition) types.DeleteQuery {
-	baseQuery := t.DeleteQuery.WhereCondition(condition)
-	return &transactionDeleteQuery{
-		DeleteQuery: baseQuery,
-		session:     t.session,
-		db:          t.db,
-	}
-}
-
-// Exec executes the delete within the transaction
-func (t *transactionDeleteQuery) Exec(ctx context.Context) (types.Result, error) {
-	// Get the MongoDB-specific delete query
-	mongoDelete, ok := t.DeleteQuery.(*MongoDBDeleteQuery)
-	if !ok {
-		return types.Result{}, fmt.Errorf("expected MongoDBDeleteQuery, got %T", t.DeleteQuery)
-	}
-
-	// Build the command
-	sql, args, err := mongoDelete.BuildSQL()
-	if err != nil {
-		return types.Result{}, fmt.Errorf("failed to build command: %w", err)
-	}
-
-	// Create a raw query with session
-	rawQuery := NewMongoDBRawQuery(t.db.client.Database(t.db.dbName), t.session, t.db, sql, args...)
-	return rawQuery.Exec(ctx)
+// withSession returns a copy of the database whose queries run in session
+func (m *MongoDB) withSession(session mongo.Session) *MongoDB {
+	bound := *m
+	bound.session = session
+	return &bound
 }
@@ -28,6 +28,11 @@ func NewMongoDBUpdateQuery(baseQuery *query.ModelQueryImpl, data any, db *MongoD
 	}
 }
 
+// ToSQL returns the MongoDB command of the query, without executing it
+func (q *MongoDBUpdateQuery) ToSQL() (string, []any, error) {
+	return q.BuildSQL()
+}
+
 // BuildSQL builds a MongoDB update command instead of SQL
 func (q *MongoDBUpdateQuery) BuildSQL() (string, []any, error) {
 	// Get collection name
@@ -98,17 +103,26 @@ func (q *MongoDBUpdateQuery) buildFilter() (bson.M, error) {
 
 // buildUpdateDocument builds MongoDB update document
 func (q *MongoDBUpdateQuery) buildUpdateDocument() (bson.M, error) {
-	setData := q.UpdateQueryImpl.GetSetData()
+	if err := query.ValidateData(q.db, q.modelName, q.UpdateQueryImpl.GetSetData()); err != nil {
+		return nil, err
+	}
+	setData := q.UpdateQueryImpl.GetSetDataWithUpdatedAt()
 	atomicOps := q.UpdateQueryImpl.GetAtomicOps()
 
 	// Map schema field names to column names for set operations
 	mappedData := make(map[string]any)
 	if setData != nil {
+		mongoMapper, _ := q.fieldMapper.(*MongoDBFieldMapper)
 		for field, value := range setData {
 			columnName, err := q.fieldMapper.SchemaToColumn(q.modelName, field)
 			if err != nil {
 				columnName = field
 			}
+			if mongoMapper != nil {
+				if value, err = mongoMapper.MapValueToColumn(q.modelName, field, value); err != nil {
+					return nil, err
+				}
+			}
 			mappedData[columnName] = value
 		}
 	}
@@ -121,39 +135,42 @@ func (q *MongoDBUpdateQuery) buildUpdateDocument() (bson.M, error) {
 		updateDoc["$set"] = mappedData
 	}
 
-	// Add atomic operations ($inc, $dec, etc.)
-	if len(atomicOps) > 0 {
-		incDoc := bson.M{}
-		decDoc := bson.M{}
-
-		for field, op := range atomicOps {
-			// Map field name to column name
-			columnName, err := q.fieldMapper.SchemaToColumn(q.modelName, field)
-			if err != nil {
-				columnName = field
-			}
+	// Add atomic operations ($inc, $mul, $push)
+	for field, op := range atomicOps {
+		// Map field name to column name
+		columnName, err := q.fieldMapper.SchemaToColumn(q.modelName, field)
+		if err != nil {
+			columnName = field
+		}
 
-			switch op.Type {
-			case "increment":
-				incDoc[columnName] = op.Value
-			case "decrement":
-				decDoc[columnName] = -op.Value // MongoDB uses positive values with $inc for increment, negative for decrement
+		var operator string
+		value := op.Value
+		switch op.Type {
+		case "increment":
+			operator = "$inc"
+		case "decrement":
+			// MongoDB decrements with a negative $inc
+			operator = "$inc"
+			negated, ok := negateNumber(op.Value)
+			if !ok {
+				return nil, fmt.Errorf("decrement of field %s requires a number, got %T", field, op.Value)
 			}
+			value = negated
+		case "multiply":
+			operator = "$mul"
+		case "push":
+			operator = "$push"
+			value = bson.M{"$each": op.Value}
+		default:
+			return nil, fmt.Errorf("unsupported atomic operation %s on field %s", op.Type, field)
 		}
 
-		if len(incDoc) > 0 {
-			updateDoc["$inc"] = incDoc
-		}
-		if len(decDoc) > 0 {
-			if existing, ok := updateDoc["$inc"]; ok {
-				// Merge with existing $inc operations
-				for k, v := range decDoc {
-					existing.(bson.M)[k] = v
-				}
-			} else {
-				updateDoc["$inc"] = decDoc
-			}
+		opDoc, ok := updateDoc[operator].(bson.M)
+		if !ok {
+			opDoc = bson.M{}
+			updateDoc[operator] = opDoc
 		}
+		opDoc[columnName] = value
 	}
 
 	return updateDoc, nil
@@ -161,6 +178,10 @@ func (q *MongoDBUpdateQuery) buildUpdateDocument() (bson.M, error) {
 
 // Exec executes the update query
 func (q *MongoDBUpdateQuery) Exec(ctx context.Context) (types.Result, error) {
+	ctx, cancel := q.StatementContext(ctx)
+	defer cancel()
+	defer types.ForgetDeduplicated(ctx)
+
 	sql, args, err := q.BuildSQL()
 	if err != nil {
 		return types.Result{}, fmt.Errorf("failed to build MongoDB command: %w", err)
@@ -213,7 +234,7 @@ func (q *MongoDBUpdateQuery) Returning(fieldNames ...string) types.UpdateQuery {
 	}
 }
 
-func (q *MongoDBUpdateQuery) Increment(fieldName string, value int64) types.UpdateQuery {
+func (q *MongoDBUpdateQuery) Increment(fieldName string, value any) types.UpdateQuery {
 	newBase := q.UpdateQueryImpl.Increment(fieldName, value).(*query.UpdateQueryImpl)
 	return &MongoDBUpdateQuery{
 		UpdateQueryImpl: newBase,
@@ -223,7 +244,7 @@ func (q *MongoDBUpdateQuery) Increment(fieldName string, value int64) types.Upda
 	}
 }
 
-func (q *MongoDBUpdateQuery) Decrement(fieldName string, value int64) types.UpdateQuery {
+func (q *MongoDBUpdateQuery) Decrement(fieldName string, value any) types.UpdateQuery {
 	newBase := q.UpdateQueryImpl.Decrement(fieldName, value).(*query.UpdateQueryImpl)
 	return &MongoDBUpdateQuery{
 		UpdateQueryImpl: newBase,
@@ -232,3 +253,23 @@ func (q *MongoDBUpdateQuery) Decrement(fieldName string, value int64) types.Upda
 		modelName:       q.modelName,
 	}
 }
+
+func (q *MongoDBUpdateQuery) Multiply(fieldName string, value any) types.UpdateQuery {
+	newBase := q.UpdateQueryImpl.Multiply(fieldName, value).(*query.UpdateQueryImpl)
+	return &MongoDBUpdateQuery{
+		UpdateQueryImpl: newBase,
+		db:              q.db,
+		fieldMapper:     q.fieldMapper,
+		modelName:       q.modelName,
+	}
+}
+
+func (q *MongoDBUpdateQuery) Push(fieldName string, values ...any) types.UpdateQuery {
+	newBase := q.UpdateQueryImpl.Push(fieldName, values...).(*query.UpdateQueryImpl)
+	return &MongoDBUpdateQuery{
+		UpdateQueryImpl: newBase,
+		db:              q.db,
+		fieldMapper:     q.fieldMapper,
+		modelName:       q.modelName,
+	}
+}
@@ -0,0 +1,135 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/rediwo/redi-orm/query"
+	"github.com/rediwo/redi-orm/types"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// MongoDBUpsertQuery implements UpsertQuery as an update command with the upsert option:
+// the conflict fields form the filter, the update data is $set and the create data is
+// $setOnInsert
+type MongoDBUpsertQuery struct {
+	*query.UpsertQueryImpl
+	db          *MongoDB
+	fieldMapper types.FieldMapper
+	modelName   string
+}
+
+// NewMongoDBUpsertQuery creates a new MongoDB upsert query
+func NewMongoDBUpsertQuery(baseQuery *query.ModelQueryImpl, create any, update any, conflictFields []string, db *MongoDB, fieldMapper types.FieldMapper, modelName string) types.UpsertQuery {
+	return &MongoDBUpsertQuery{
+		UpsertQueryImpl: query.NewUpsertQuery(baseQuery, create, update, conflictFields),
+		db:              db,
+		fieldMapper:     fieldMapper,
+		modelName:       modelName,
+	}
+}
+
+// ToSQL returns the MongoDB command of the query, without executing it
+func (q *MongoDBUpsertQuery) ToSQL() (string, []any, error) {
+	return q.BuildSQL()
+}
+
+// BuildSQL builds a MongoDB upsert command instead of SQL
+func (q *MongoDBUpsertQuery) BuildSQL() (string, []any, error) {
+	tableName, err := q.fieldMapper.ModelToTable(q.modelName)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to resolve collection name: %w", err)
+	}
+
+	mongoMapper, ok := q.fieldMapper.(*MongoDBFieldMapper)
+	if !ok {
+		return "", nil, fmt.Errorf("expected MongoDB field mapper, got %T", q.fieldMapper)
+	}
+
+	conflictFields, err := q.GetConflictFields()
+	if err != nil {
+		return "", nil, err
+	}
+
+	createData, err := documentData(q.GetCreateData())
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to convert create data: %w", err)
+	}
+	filterData := make(map[string]any, len(conflictFields))
+	for _, field := range conflictFields {
+		value, ok := createData[field]
+		if !ok {
+			return "", nil, fmt.Errorf("upsert create data must contain conflict field %s", field)
+		}
+		filterData[field] = value
+	}
+	filter, err := mongoMapper.MapSchemaToColumnData(q.modelName, filterData)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to map conflict fields: %w", err)
+	}
+
+	// The insert path applies defaults and generates auto-increment IDs
+	insert := NewMongoDBInsertQuery(q.ModelQueryImpl, nil, q.db, q.fieldMapper, q.modelName).(*MongoDBInsertQuery)
+	createDoc, err := insert.convertToDocument(maps.Clone(createData))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to convert create data: %w", err)
+	}
+
+	setDoc := bson.M{}
+	if q.GetUpdateData() != nil {
+		updateData, err := documentData(query.WithUpdatedAt(q.db, q.modelName, q.GetUpdateData(), time.Now()))
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to convert update data: %w", err)
+		}
+		if err := query.ValidateData(q.db, q.modelName, updateData); err != nil {
+			return "", nil, err
+		}
+		if setDoc, err = mongoMapper.MapSchemaToColumnData(q.modelName, updateData); err != nil {
+			return "", nil, fmt.Errorf("failed to map update fields: %w", err)
+		}
+	}
+
+	// A path may only appear in one update operator
+	for column := range setDoc {
+		delete(createDoc, column)
+	}
+	update := bson.M{"$setOnInsert": createDoc}
+	if len(setDoc) > 0 {
+		update["$set"] = setDoc
+	}
+
+	cmd := MongoDBCommand{
+		Operation:    "update",
+		Collection:   tableName,
+		Filter:       filter,
+		Update:       update,
+		Options:      bson.M{"upsert": true},
+		LastInsertID: insert.lastInsertID,
+	}
+
+	jsonCmd, err := cmd.ToJSON()
+	if err != nil {
+		return "", nil, err
+	}
+	return jsonCmd, nil, nil
+}
+
+// Exec executes the upsert command
+func (q *MongoDBUpsertQuery) Exec(ctx context.Context) (types.Result, error) {
+	ctx, cancel := q.StatementContext(ctx)
+	defer cancel()
+	defer types.ForgetDeduplicated(ctx)
+
+	sql, args, err := q.BuildSQL()
+	if err != nil {
+		return types.Result{}, fmt.Errorf("failed to build MongoDB command: %w", err)
+	}
+
+	result, err := q.db.Raw(sql, args...).Exec(ctx)
+	if err != nil {
+		return types.Result{}, fmt.Errorf("failed to execute upsert: %w", err)
+	}
+	return result, nil
+}
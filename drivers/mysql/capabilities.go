@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
 )
 
 // MySQLCapabilities implements types.DriverCapabilities for MySQL
@@ -37,10 +38,30 @@ func (c *MySQLCapabilities) SupportsForeignKeys() bool {
 	return true // MySQL (InnoDB) supports foreign key constraints
 }
 
+func (c *MySQLCapabilities) SupportsUpsert() bool {
+	// INSERT ... ON DUPLICATE KEY UPDATE
+	return true
+}
+
+func (c *MySQLCapabilities) SupportsJSONOperators() bool {
+	// JSON_EXTRACT and the ->/->> operators (MySQL 5.7+)
+	return true
+}
+
+func (c *MySQLCapabilities) SupportsFullText() bool {
+	// MATCH ... AGAINST on FULLTEXT indexes
+	return true
+}
+
+func (c *MySQLCapabilities) MaxParameters() int {
+	// Prepared statements are limited to 65535 placeholders
+	return 65535
+}
+
 // Identifier quoting
 
 func (c *MySQLCapabilities) QuoteIdentifier(name string) string {
-	return fmt.Sprintf("`%s`", name)
+	return utils.QuoteIdentifier(name, '`')
 }
 
 func (c *MySQLCapabilities) GetPlaceholder(index int) string {
@@ -68,6 +89,65 @@ func (c *MySQLCapabilities) GetNullsOrderingSQL(direction types.Order, nullsFirs
 	return ""
 }
 
+// GetUpsertSQL returns the ON DUPLICATE KEY UPDATE clause of an upsert, with a ? placeholder
+// per update column. MySQL matches on any unique key, so the conflict columns only serve as
+// a no-op assignment when there is nothing to update.
+func (c *MySQLCapabilities) GetUpsertSQL(conflictColumns []string, updateColumns []string) string {
+	if len(updateColumns) == 0 {
+		if len(conflictColumns) == 0 {
+			return ""
+		}
+		column := c.QuoteIdentifier(conflictColumns[0])
+		return fmt.Sprintf(" ON DUPLICATE KEY UPDATE %s = %s", column, column)
+	}
+
+	assignments := make([]string, len(updateColumns))
+	for i, column := range updateColumns {
+		assignments[i] = c.QuoteIdentifier(column) + " = ?"
+	}
+	return " ON DUPLICATE KEY UPDATE " + strings.Join(assignments, ", ")
+}
+
+// GetSkipDuplicatesSQL returns a no-op ON DUPLICATE KEY UPDATE clause. Unlike INSERT IGNORE
+// it only skips duplicate rows and still reports other errors such as NOT NULL violations.
+func (c *MySQLCapabilities) GetSkipDuplicatesSQL(insertColumns []string) string {
+	return c.GetUpsertSQL(insertColumns, nil)
+}
+
+// GetArrayAppendSQL returns an expression appending the JSON array bound to ? to a list
+// column. MySQL has no native arrays, so lists are stored as JSON arrays.
+func (c *MySQLCapabilities) GetArrayAppendSQL(quotedColumn string) string {
+	return fmt.Sprintf("JSON_MERGE_PRESERVE(COALESCE(%s, JSON_ARRAY()), CAST(? AS JSON))", quotedColumn)
+}
+
+// GetArrayFilterSQL returns the condition for a list filter on a JSON array column
+func (c *MySQLCapabilities) GetArrayFilterSQL(operator string, quotedColumn string) string {
+	switch operator {
+	case "has":
+		return fmt.Sprintf("JSON_CONTAINS(%s, JSON_ARRAY(?))", quotedColumn)
+	case "hasSome":
+		return fmt.Sprintf("JSON_OVERLAPS(%s, CAST(? AS JSON))", quotedColumn)
+	case "hasEvery":
+		return fmt.Sprintf("JSON_CONTAINS(%s, CAST(? AS JSON))", quotedColumn)
+	case "isEmpty":
+		return fmt.Sprintf("JSON_LENGTH(%s) = 0", quotedColumn)
+	case "isNotEmpty":
+		return fmt.Sprintf("JSON_LENGTH(%s) > 0", quotedColumn)
+	default:
+		return ""
+	}
+}
+
+// EncodeArrayValue encodes a list as a JSON array
+func (c *MySQLCapabilities) EncodeArrayValue(values []any) (any, error) {
+	return utils.EncodeJSONArray(values)
+}
+
+// DecodeArrayValue decodes a list stored as a JSON array
+func (c *MySQLCapabilities) DecodeArrayValue(value any) ([]any, error) {
+	return utils.DecodeJSONArray(value)
+}
+
 // Index/Table detection
 
 func (c *MySQLCapabilities) IsSystemIndex(indexName string) bool {
@@ -121,6 +201,15 @@ func (c *MySQLCapabilities) SupportsTransactions() bool {
 	return true
 }
 
+func (c *MySQLCapabilities) SupportsSavepoints() bool {
+	return true
+}
+
+func (c *MySQLCapabilities) SupportsTransactionalDDL() bool {
+	// DDL statements commit the open transaction implicitly
+	return false
+}
+
 func (c *MySQLCapabilities) SupportsNestedDocuments() bool {
 	return false // MySQL has JSON but not full document support
 }
@@ -48,6 +48,8 @@ func TestMySQLConformance(t *testing.T) {
 			MigrationTableName:                  "_migrations",
 			SystemIndexPatterns:                 []string{"PRIMARY", "fk_*", "mysql_*"},
 			AutoIncrementIntegerType:            "INT AUTO_INCREMENT",
+			MaxOpenConnections:                  25,
+			SlowQuery:                           "SELECT SLEEP(10)",
 		},
 	}
 
@@ -13,6 +13,7 @@ import (
 	"github.com/rediwo/redi-orm/registry"
 	"github.com/rediwo/redi-orm/schema"
 	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
 )
 
 func init() {
@@ -47,7 +48,7 @@ func NewMySQLDB(nativeURI string) (*MySQLDB, error) {
 
 // Connect establishes connection to MySQL database
 func (m *MySQLDB) Connect(ctx context.Context) error {
-	db, err := sql.Open("mysql", m.nativeURI)
+	db, err := sql.Open("mysql", dsnWithStatementTimeout(m.nativeURI, m.GetStatementTimeout()))
 	if err != nil {
 		return fmt.Errorf("failed to open MySQL database: %w", err)
 	}
@@ -82,7 +83,7 @@ func (m *MySQLDB) DropModel(ctx context.Context, modelName string) error {
 		return fmt.Errorf("failed to resolve table name: %w", err)
 	}
 
-	sql := fmt.Sprintf("DROP TABLE IF EXISTS `%s`", tableName)
+	sql := fmt.Sprintf("DROP TABLE IF EXISTS %s", m.quoteIdentifier(tableName))
 	_, err = m.DB.ExecContext(ctx, sql)
 	if err != nil {
 		return fmt.Errorf("failed to drop table: %w", err)
@@ -98,6 +99,11 @@ func (m *MySQLDB) Model(modelName string) types.ModelQuery {
 
 // Raw creates a new raw query
 func (m *MySQLDB) Raw(sql string, args ...any) types.RawQuery {
+	sql, args, err := utils.ExpandSQLArgs(sql, args)
+	if err != nil {
+		return base.NewErrorRawQuery(err)
+	}
+	args = utils.NormalizeTimeArgs(args)
 	return NewMySQLRawQuery(m.DB, sql, args...)
 }
 
@@ -149,6 +155,16 @@ func (m *MySQLDB) Query(query string, args ...any) (*sql.Rows, error) {
 	return m.DB.Query(query, args...)
 }
 
+// QueryContext executes a raw SQL query that returns rows, canceled when ctx is done
+func (m *MySQLDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return m.Driver.QueryContext(ctx, withExecutionTime(ctx, query), args...)
+}
+
+// QueryRowContext executes a raw SQL query that returns a single row, canceled when ctx is done
+func (m *MySQLDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return m.Driver.QueryRowContext(ctx, withExecutionTime(ctx, query), args...)
+}
+
 // QueryRow executes a raw SQL query that returns a single row
 func (m *MySQLDB) QueryRow(query string, args ...any) *sql.Row {
 	return m.DB.QueryRow(query, args...)
@@ -167,7 +183,7 @@ func (m *MySQLDB) generateCreateTableSQL(schema *schema.Schema) (string, error)
 		columns = append(columns, column)
 
 		if field.PrimaryKey {
-			primaryKeys = append(primaryKeys, fmt.Sprintf("`%s`", field.GetColumnName()))
+			primaryKeys = append(primaryKeys, m.quoteIdentifier(field.GetColumnName()))
 		}
 	}
 
@@ -176,8 +192,12 @@ func (m *MySQLDB) generateCreateTableSQL(schema *schema.Schema) (string, error)
 		columns = append(columns, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(primaryKeys, ", ")))
 	}
 
-	// Add foreign key constraints
-	for _, relation := range schema.Relations {
+	// Add foreign key constraints, unless the ORM emulates them (relationMode = "prisma")
+	relations := schema.Relations
+	if schema.EmulatesForeignKeys() {
+		relations = nil
+	}
+	for _, relation := range relations {
 		if relation.Type == "manyToOne" ||
 			(relation.Type == "oneToOne" && relation.ForeignKey != "") {
 			// Get the referenced table name
@@ -223,12 +243,11 @@ func (m *MySQLDB) generateCreateTableSQL(schema *schema.Schema) (string, error)
 			}
 
 			fkConstraint := fmt.Sprintf(
-				"CONSTRAINT `fk_%s_%s` FOREIGN KEY (`%s`) REFERENCES `%s`(`%s`)",
-				strings.ReplaceAll(schema.GetTableName(), ".", "_"),
-				foreignKeyColumn,
-				foreignKeyColumn,
-				referencedSchema.GetTableName(),
-				referencesColumn,
+				"CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s(%s)",
+				m.quoteIdentifier("fk_"+strings.ReplaceAll(schema.GetTableName(), ".", "_")+"_"+foreignKeyColumn),
+				m.quoteIdentifier(foreignKeyColumn),
+				m.quoteIdentifier(referencedSchema.GetTableName()),
+				m.quoteIdentifier(referencesColumn),
 			)
 
 			// Add ON DELETE/UPDATE rules if specified
@@ -243,8 +262,8 @@ func (m *MySQLDB) generateCreateTableSQL(schema *schema.Schema) (string, error)
 		}
 	}
 
-	sql := fmt.Sprintf("CREATE TABLE IF NOT EXISTS `%s` (\n  %s\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci",
-		schema.GetTableName(),
+	sql := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n  %s\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci",
+		m.quoteIdentifier(schema.GetTableName()),
 		strings.Join(columns, ",\n  "))
 
 	return sql, nil
@@ -253,10 +272,10 @@ func (m *MySQLDB) generateCreateTableSQL(schema *schema.Schema) (string, error)
 // generateColumnSQL generates SQL for a single column
 func (m *MySQLDB) generateColumnSQL(field schema.Field) (string, error) {
 	columnName := field.GetColumnName()
-	sqlType := m.mapFieldTypeToSQL(field.Type)
+	sqlType := m.columnType(field)
 
 	var parts []string
-	parts = append(parts, fmt.Sprintf("`%s` %s", columnName, sqlType))
+	parts = append(parts, fmt.Sprintf("%s %s", m.quoteIdentifier(columnName), sqlType))
 
 	if !field.Nullable {
 		parts = append(parts, "NOT NULL")
@@ -270,7 +289,7 @@ func (m *MySQLDB) generateColumnSQL(field schema.Field) (string, error) {
 		parts = append(parts, "UNIQUE")
 	}
 
-	if field.Default != nil {
+	if field.ColumnDefault() != nil {
 		defaultValue := m.formatDefaultValue(field.Default)
 		parts = append(parts, fmt.Sprintf("DEFAULT %s", defaultValue))
 	}
@@ -278,6 +297,27 @@ func (m *MySQLDB) generateColumnSQL(field schema.Field) (string, error) {
 	return strings.Join(parts, " "), nil
 }
 
+// columnType returns the column type of a field, honouring the @db native types of
+// DateTime fields. MySQL has no time zone aware type, so @db.Timestamptz columns stay
+// DATETIME and hold UTC times like every other DateTime column. Fields holding UUIDs are
+// CHAR(36), the length of their text.
+func (m *MySQLDB) columnType(field schema.Field) string {
+	if field.HoldsUUID() {
+		return "CHAR(36)"
+	}
+	if field.Type == schema.FieldTypeDateTime {
+		switch field.NativeType() {
+		case "Date":
+			return "DATE"
+		case "Time":
+			return "TIME"
+		case "Timestamp":
+			return "TIMESTAMP"
+		}
+	}
+	return m.mapFieldTypeToSQL(field.Type)
+}
+
 // mapFieldTypeToSQL maps schema field types to MySQL SQL types
 func (m *MySQLDB) mapFieldTypeToSQL(fieldType schema.FieldType) string {
 	switch fieldType {
@@ -297,6 +337,12 @@ func (m *MySQLDB) mapFieldTypeToSQL(fieldType schema.FieldType) string {
 		return "JSON"
 	case schema.FieldTypeDecimal:
 		return "DECIMAL(10,2)"
+	case schema.FieldTypeBytes:
+		return "VARBINARY(255)"
+	case schema.FieldTypeStringArray, schema.FieldTypeIntArray, schema.FieldTypeInt64Array,
+		schema.FieldTypeFloatArray, schema.FieldTypeBoolArray, schema.FieldTypeDecimalArray,
+		schema.FieldTypeDateTimeArray:
+		return "JSON" // Lists are stored as JSON arrays
 	default:
 		return "VARCHAR(255)"
 	}
@@ -324,6 +370,13 @@ func (m *MySQLDB) formatDefaultValue(value any) string {
 			return "TRUE"
 		}
 		return "FALSE"
+	case []any:
+		// JSON columns only take expression defaults
+		text, err := utils.EncodeJSONArray(v)
+		if err != nil {
+			text = "[]"
+		}
+		return fmt.Sprintf("(CAST('%s' AS JSON))", strings.ReplaceAll(text, "'", "''"))
 	default:
 		return fmt.Sprintf("%v", value)
 	}
@@ -340,6 +393,16 @@ func (m *MySQLDB) GetCapabilities() types.DriverCapabilities {
 	return NewMySQLCapabilities()
 }
 
+// Watch opens a polling-based change stream for the given models
+func (m *MySQLDB) Watch(ctx context.Context, models ...string) (types.ChangeStream, error) {
+	return m.Driver.Watch(ctx, m, models...)
+}
+
 func (m *MySQLDB) GetMigrator() types.DatabaseMigrator {
 	return NewMySQLMigrator(m.DB, m)
 }
+
+// quoteIdentifier quotes an identifier for MySQL
+func (m *MySQLDB) quoteIdentifier(name string) string {
+	return utils.QuoteIdentifier(name, '`')
+}
@@ -0,0 +1,45 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+)
+
+// migrationLockName names the lock serializing migrations. MySQL named locks are global to the
+// server, so the name includes the database to not make other databases wait.
+const migrationLockName = "LEFT(CONCAT('redi_orm_migrations.', DATABASE()), 64)"
+
+// LockMigrations takes a named lock with GET_LOCK, which MySQL releases by itself if the
+// process dies while migrating
+func (m *MySQLDB) LockMigrations(ctx context.Context, timeout time.Duration) (func() error, error) {
+	if m.DB == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+
+	// Named locks belong to the session, so the lock is taken and released on one connection
+	conn, err := m.DB.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	// GET_LOCK waits by itself, in whole seconds
+	var locked sql.NullInt64
+	seconds := int(math.Ceil(timeout.Seconds()))
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK("+migrationLockName+", ?)", seconds).Scan(&locked); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	if !locked.Valid || locked.Int64 != 1 {
+		conn.Close()
+		return nil, fmt.Errorf("timed out after %s waiting for the migration lock", timeout)
+	}
+
+	return func() error {
+		defer conn.Close()
+		_, err := conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK("+migrationLockName+")")
+		return err
+	}, nil
+}
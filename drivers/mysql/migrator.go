@@ -76,6 +76,16 @@ func (m *MySQLMigrator) GetTableInfo(tableName string) (*types.TableInfo, error)
 		ForeignKeys: []types.ForeignKeyInfo{},
 	}
 
+	// Get table comment
+	commentQuery := `
+		SELECT TABLE_COMMENT
+		FROM information_schema.TABLES
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?
+	`
+	if err := m.db.QueryRow(commentQuery, tableName).Scan(&tableInfo.Comment); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get table comment: %w", err)
+	}
+
 	// Get column information
 	query := `
 		SELECT 
@@ -87,7 +97,8 @@ func (m *MySQLMigrator) GetTableInfo(tableName string) (*types.TableInfo, error)
 			EXTRA,
 			CHARACTER_MAXIMUM_LENGTH,
 			NUMERIC_PRECISION,
-			NUMERIC_SCALE
+			NUMERIC_SCALE,
+			COLUMN_COMMENT
 		FROM information_schema.COLUMNS 
 		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?
 		ORDER BY ORDINAL_POSITION
@@ -109,6 +120,7 @@ func (m *MySQLMigrator) GetTableInfo(tableName string) (*types.TableInfo, error)
 		var charMaxLength sql.NullInt64
 		var numPrecision sql.NullInt64
 		var numScale sql.NullInt64
+		var columnComment string
 
 		if err := rows.Scan(
 			&columnName,
@@ -120,6 +132,7 @@ func (m *MySQLMigrator) GetTableInfo(tableName string) (*types.TableInfo, error)
 			&charMaxLength,
 			&numPrecision,
 			&numScale,
+			&columnComment,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan column info: %w", err)
 		}
@@ -131,6 +144,7 @@ func (m *MySQLMigrator) GetTableInfo(tableName string) (*types.TableInfo, error)
 			PrimaryKey:    columnKey == "PRI",
 			AutoIncrement: strings.Contains(extra, "auto_increment"),
 			Unique:        columnKey == "UNI",
+			Comment:       columnComment,
 		}
 
 		if columnDefault.Valid {
@@ -281,7 +295,7 @@ func (m *MySQLMigrator) GenerateCreateTableSQL(s *schema.Schema) (string, error)
 
 // GenerateDropTableSQL generates DROP TABLE SQL
 func (m *MySQLMigrator) GenerateDropTableSQL(tableName string) string {
-	return fmt.Sprintf("DROP TABLE IF EXISTS `%s`", tableName)
+	return fmt.Sprintf("DROP TABLE IF EXISTS %s", m.QuoteIdentifier(tableName))
 }
 
 // GenerateAddColumnSQL generates ADD COLUMN SQL
@@ -296,7 +310,7 @@ func (m *MySQLMigrator) GenerateAddColumnSQL(tableName string, field any) (strin
 		return "", fmt.Errorf("failed to generate column definition: %w", err)
 	}
 
-	return fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN %s", tableName, columnDef), nil
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", m.QuoteIdentifier(tableName), columnDef), nil
 }
 
 // GenerateModifyColumnSQL generates MODIFY COLUMN SQL
@@ -308,12 +322,12 @@ func (m *MySQLMigrator) GenerateModifyColumnSQL(change types.ColumnChange) ([]st
 
 	columnDef := m.GenerateColumnDefinitionFromColumnInfo(*change.NewColumn)
 
-	sql := fmt.Sprintf("ALTER TABLE `%s` MODIFY COLUMN %s", change.TableName, columnDef)
+	sql := fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s", m.QuoteIdentifier(change.TableName), columnDef)
 
 	// If renaming column, use CHANGE instead of MODIFY
 	if change.OldColumn != nil && change.OldColumn.Name != change.NewColumn.Name {
-		sql = fmt.Sprintf("ALTER TABLE `%s` CHANGE COLUMN `%s` %s",
-			change.TableName, change.OldColumn.Name, columnDef)
+		sql = fmt.Sprintf("ALTER TABLE %s CHANGE COLUMN %s %s",
+			m.QuoteIdentifier(change.TableName), m.QuoteIdentifier(change.OldColumn.Name), columnDef)
 	}
 
 	return []string{sql}, nil
@@ -322,7 +336,7 @@ func (m *MySQLMigrator) GenerateModifyColumnSQL(change types.ColumnChange) ([]st
 // GenerateDropColumnSQL generates DROP COLUMN SQL
 func (m *MySQLMigrator) GenerateDropColumnSQL(tableName, columnName string) ([]string, error) {
 	return []string{
-		fmt.Sprintf("ALTER TABLE `%s` DROP COLUMN `%s`", tableName, columnName),
+		fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", m.QuoteIdentifier(tableName), m.QuoteIdentifier(columnName)),
 	}, nil
 }
 
@@ -335,11 +349,24 @@ func (m *MySQLMigrator) GenerateCreateIndexSQL(tableName, indexName string, colu
 
 	quotedColumns := make([]string, len(columns))
 	for i, col := range columns {
-		quotedColumns[i] = fmt.Sprintf("`%s`", col)
+		quotedColumns[i] = m.QuoteIdentifier(col)
 	}
 
-	return fmt.Sprintf("CREATE %sINDEX `%s` ON `%s` (%s)",
-		uniqueStr, indexName, tableName, strings.Join(quotedColumns, ", "))
+	return fmt.Sprintf("CREATE %sINDEX %s ON %s (%s)",
+		uniqueStr, m.QuoteIdentifier(indexName), m.QuoteIdentifier(tableName), strings.Join(quotedColumns, ", "))
+}
+
+// GenerateUpdatedAtTriggerSQL generates a BEFORE UPDATE trigger setting the columns to the
+// current time when an update leaves them unchanged
+func (m *MySQLMigrator) GenerateUpdatedAtTriggerSQL(tableName string, columns []string) []string {
+	assignments := make([]string, len(columns))
+	for i, column := range columns {
+		quoted := m.QuoteIdentifier(column)
+		assignments[i] = fmt.Sprintf("NEW.%s = IF(NEW.%s <=> OLD.%s, CURRENT_TIMESTAMP, NEW.%s)",
+			quoted, quoted, quoted, quoted)
+	}
+	return []string{fmt.Sprintf("CREATE TRIGGER %s BEFORE UPDATE ON %s FOR EACH ROW SET %s",
+		m.QuoteIdentifier(tableName+"_updated_at"), m.QuoteIdentifier(tableName), strings.Join(assignments, ", "))}
 }
 
 // GenerateDropIndexSQL generates DROP INDEX SQL
@@ -367,7 +394,7 @@ func (m *MySQLMigrator) GetDatabaseType() string {
 
 // MapFieldType maps schema field types to MySQL types
 func (m *MySQLMigrator) MapFieldType(field schema.Field) string {
-	return m.mysqlDB.mapFieldTypeToSQL(field.Type)
+	return m.mysqlDB.columnType(field)
 }
 
 // FormatDefaultValue formats a default value for MySQL
@@ -375,9 +402,14 @@ func (m *MySQLMigrator) FormatDefaultValue(value any) string {
 	return m.mysqlDB.formatDefaultValue(value)
 }
 
+// QuoteIdentifier quotes an identifier for MySQL
+func (m *MySQLMigrator) QuoteIdentifier(name string) string {
+	return m.mysqlDB.quoteIdentifier(name)
+}
+
 // GenerateColumnDefinitionFromColumnInfo generates column definition from ColumnInfo
 func (m *MySQLMigrator) GenerateColumnDefinitionFromColumnInfo(col types.ColumnInfo) string {
-	parts := []string{fmt.Sprintf("`%s`", col.Name), col.Type}
+	parts := []string{m.QuoteIdentifier(col.Name), col.Type}
 
 	if !col.Nullable {
 		parts = append(parts, "NOT NULL")
@@ -408,7 +440,7 @@ func (m *MySQLMigrator) ConvertFieldToColumnInfo(field schema.Field) *types.Colu
 		Name:          field.GetColumnName(),
 		Type:          m.MapFieldType(field),
 		Nullable:      field.Nullable,
-		Default:       field.Default,
+		Default:       field.ColumnDefault(),
 		PrimaryKey:    field.PrimaryKey,
 		AutoIncrement: field.AutoIncrement,
 		Unique:        field.Unique,
@@ -576,9 +608,9 @@ func (m *MySQLMigrator) MapDatabaseTypeToFieldType(dbType string) schema.FieldTy
 
 	// Binary types
 	case "binary", "varbinary":
-		return schema.FieldTypeBinary
+		return schema.FieldTypeBytes
 	case "blob", "tinyblob", "mediumblob", "longblob":
-		return schema.FieldTypeBinary
+		return schema.FieldTypeBytes
 
 	// JSON type
 	case "json":
@@ -27,6 +27,7 @@ func NewMySQLRawQuery(db *sql.DB, sql string, args ...any) types.RawQuery {
 
 // Exec executes the query and returns the result
 func (q *MySQLRawQuery) Exec(ctx context.Context) (types.Result, error) {
+	defer types.ForgetDeduplicated(ctx)
 	result, err := q.db.ExecContext(ctx, q.sql, q.args...)
 	if err != nil {
 		return types.Result{}, fmt.Errorf("failed to execute query: %w", err)
@@ -51,7 +52,7 @@ func (q *MySQLRawQuery) Exec(ctx context.Context) (types.Result, error) {
 
 // Find executes the query and scans results into dest
 func (q *MySQLRawQuery) Find(ctx context.Context, dest any) error {
-	rows, err := q.db.QueryContext(ctx, q.sql, q.args...)
+	rows, err := q.db.QueryContext(ctx, withExecutionTime(ctx, q.sql), q.args...)
 	if err != nil {
 		return fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -62,5 +63,5 @@ func (q *MySQLRawQuery) Find(ctx context.Context, dest any) error {
 
 // FindOne executes the query and scans a single result into dest
 func (q *MySQLRawQuery) FindOne(ctx context.Context, dest any) error {
-	return utils.ScanRowContext(q.db, ctx, q.sql, q.args, dest)
+	return utils.ScanRowContext(q.db, ctx, withExecutionTime(ctx, q.sql), q.args, dest)
 }
@@ -0,0 +1,37 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// withExecutionTime adds a MAX_EXECUTION_TIME optimizer hint with the time left until the
+// deadline of ctx to a SELECT statement, so that the server stops it too instead of only
+// the client giving up on it. Other statements are returned as they are.
+func withExecutionTime(ctx context.Context, query string) string {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return query
+	}
+	trimmed := strings.TrimLeft(query, " \t\r\n")
+	if len(trimmed) < 6 || !strings.EqualFold(trimmed[:6], "SELECT") || strings.Contains(strings.ToUpper(trimmed), "MAX_EXECUTION_TIME") {
+		return query
+	}
+	ms := max(time.Until(deadline).Milliseconds(), 1)
+	return fmt.Sprintf("%s /*+ MAX_EXECUTION_TIME(%d) */%s", trimmed[:6], ms, trimmed[6:])
+}
+
+// dsnWithStatementTimeout sets the max_execution_time session variable of the connections of
+// dsn to the default statement timeout, bounding SELECT statements on the server
+func dsnWithStatementTimeout(dsn string, timeout time.Duration) string {
+	if timeout <= 0 || strings.Contains(dsn, "max_execution_time=") {
+		return dsn
+	}
+	separator := "?"
+	if strings.Contains(dsn, "?") {
+		separator = "&"
+	}
+	return fmt.Sprintf("%s%smax_execution_time=%d", dsn, separator, timeout.Milliseconds())
+}
@@ -0,0 +1,33 @@
+package mysql
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithExecutionTime(t *testing.T) {
+	query := "SELECT `id` FROM `users` WHERE `id` = ?"
+	assert.Equal(t, query, withExecutionTime(context.Background(), query))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	hinted := withExecutionTime(ctx, query)
+	assert.True(t, strings.HasPrefix(hinted, "SELECT /*+ MAX_EXECUTION_TIME("), hinted)
+	assert.True(t, strings.HasSuffix(hinted, "*/ `id` FROM `users` WHERE `id` = ?"), hinted)
+
+	// Only SELECT statements without a hint of their own are bounded
+	assert.Equal(t, hinted, withExecutionTime(ctx, hinted))
+	update := "UPDATE `users` SET `name` = ?"
+	assert.Equal(t, update, withExecutionTime(ctx, update))
+}
+
+func TestDSNWithStatementTimeout(t *testing.T) {
+	dsn := "user:password@tcp(localhost:3306)/mydb?charset=utf8mb4&parseTime=true"
+	assert.Equal(t, dsn, dsnWithStatementTimeout(dsn, 0))
+	assert.Equal(t, dsn+"&max_execution_time=5000", dsnWithStatementTimeout(dsn, 5*time.Second))
+	assert.Equal(t, "user@tcp(localhost)/mydb?max_execution_time=250", dsnWithStatementTimeout("user@tcp(localhost)/mydb", 250*time.Millisecond))
+}
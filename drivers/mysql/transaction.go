@@ -40,22 +40,29 @@ func (t *MySQLTransaction) Model(modelName string) types.ModelQuery {
 
 // Raw creates a new raw query within the transaction
 func (t *MySQLTransaction) Raw(sql string, args ...any) types.RawQuery {
+	sql, args, err := utils.ExpandSQLArgs(sql, args)
+	if err != nil {
+		return base.NewErrorRawQuery(err)
+	}
+	args = utils.NormalizeTimeArgs(args)
 	return &MySQLTransactionRawQuery{tx: t.tx, sql: sql, args: args, db: t.db}
 }
 
 // Commit commits the transaction
 func (t *MySQLTransaction) Commit(ctx context.Context) error {
+	defer types.ForgetDeduplicated(ctx)
 	return t.tx.Commit()
 }
 
 // Rollback rolls back the transaction
 func (t *MySQLTransaction) Rollback(ctx context.Context) error {
+	defer types.ForgetDeduplicated(ctx)
 	return t.tx.Rollback()
 }
 
 // Savepoint creates a new savepoint
 func (t *MySQLTransaction) Savepoint(ctx context.Context, name string) error {
-	_, err := t.tx.ExecContext(ctx, fmt.Sprintf("SAVEPOINT `%s`", name))
+	_, err := t.tx.ExecContext(ctx, fmt.Sprintf("SAVEPOINT %s", t.db.quoteIdentifier(name)))
 	if err != nil {
 		return fmt.Errorf("failed to create savepoint: %w", err)
 	}
@@ -64,7 +71,7 @@ func (t *MySQLTransaction) Savepoint(ctx context.Context, name string) error {
 
 // RollbackTo rolls back to a specific savepoint
 func (t *MySQLTransaction) RollbackTo(ctx context.Context, name string) error {
-	_, err := t.tx.ExecContext(ctx, fmt.Sprintf("ROLLBACK TO SAVEPOINT `%s`", name))
+	_, err := t.tx.ExecContext(ctx, fmt.Sprintf("ROLLBACK TO SAVEPOINT %s", t.db.quoteIdentifier(name)))
 	if err != nil {
 		return fmt.Errorf("failed to rollback to savepoint: %w", err)
 	}
@@ -130,6 +137,21 @@ func (tdb *MySQLTransactionDB) GetModelSchema(modelName string) (*schema.Schema,
 	return tdb.db.GetModelSchema(modelName)
 }
 
+// GetModelField delegates to the main database
+func (tdb *MySQLTransactionDB) GetModelField(modelName, fieldName string) (*schema.Field, error) {
+	return tdb.db.GetModelField(modelName, fieldName)
+}
+
+// GetModelRelations delegates to the main database
+func (tdb *MySQLTransactionDB) GetModelRelations(modelName string) ([]types.RelationInfo, error) {
+	return tdb.db.GetModelRelations(modelName)
+}
+
+// ResolveRelation delegates to the main database
+func (tdb *MySQLTransactionDB) ResolveRelation(modelName, relationName string) (types.RelationInfo, error) {
+	return tdb.db.ResolveRelation(modelName, relationName)
+}
+
 // GetDriverType delegates to the main database
 func (tdb *MySQLTransactionDB) GetDriverType() string {
 	return tdb.db.GetDriverType()
@@ -185,9 +207,9 @@ func (tdb *MySQLTransactionDB) Begin(ctx context.Context) (types.Transaction, er
 	return nil, fmt.Errorf("cannot begin transaction within a transaction")
 }
 
-// Transaction - not supported in transaction
+// Transaction runs fn in the enclosing transaction
 func (tdb *MySQLTransactionDB) Transaction(ctx context.Context, fn func(tx types.Transaction) error) error {
-	return fmt.Errorf("cannot start transaction within a transaction")
+	return fn(tdb.tx)
 }
 
 // GetMigrator delegates to the main database
@@ -217,7 +239,7 @@ func (tdb *MySQLTransactionDB) Exec(query string, args ...any) (sql.Result, erro
 	duration := time.Since(start)
 
 	if l := tdb.db.GetLogger(); l != nil {
-		dbLogger := base.NewDBLogger(l)
+		dbLogger := base.NewDBLogger(tdb.db.Log())
 		dbLogger.LogSQL(query, args, duration)
 	}
 
@@ -231,7 +253,7 @@ func (tdb *MySQLTransactionDB) Query(query string, args ...any) (*sql.Rows, erro
 	duration := time.Since(start)
 
 	if l := tdb.db.GetLogger(); l != nil {
-		dbLogger := base.NewDBLogger(l)
+		dbLogger := base.NewDBLogger(tdb.db.Log())
 		dbLogger.LogSQL(query, args, duration)
 	}
 
@@ -245,13 +267,61 @@ func (tdb *MySQLTransactionDB) QueryRow(query string, args ...any) *sql.Row {
 	duration := time.Since(start)
 
 	if l := tdb.db.GetLogger(); l != nil {
-		dbLogger := base.NewDBLogger(l)
+		dbLogger := base.NewDBLogger(tdb.db.Log())
 		dbLogger.LogSQL(query, args, duration)
 	}
 
 	return row
 }
 
+// QueryContext executes a query within the transaction, canceled when ctx is done
+func (tdb *MySQLTransactionDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := tdb.tx.tx.QueryContext(ctx, withExecutionTime(ctx, query), args...)
+	duration := time.Since(start)
+
+	if l := tdb.db.GetLogger(); l != nil {
+		dbLogger := base.NewDBLogger(tdb.db.Log())
+		dbLogger.LogSQLContext(ctx, query, args, duration)
+	}
+
+	return rows, err
+}
+
+// QueryRowContext executes a query within the transaction, canceled when ctx is done
+func (tdb *MySQLTransactionDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	start := time.Now()
+	row := tdb.tx.tx.QueryRowContext(ctx, withExecutionTime(ctx, query), args...)
+	duration := time.Since(start)
+
+	if l := tdb.db.GetLogger(); l != nil {
+		dbLogger := base.NewDBLogger(tdb.db.Log())
+		dbLogger.LogSQLContext(ctx, query, args, duration)
+	}
+
+	return row
+}
+
+// SetStatementTimeout delegates to the main database
+func (tdb *MySQLTransactionDB) SetStatementTimeout(timeout time.Duration) {
+	tdb.db.SetStatementTimeout(timeout)
+}
+
+// GetStatementTimeout delegates to the main database
+func (tdb *MySQLTransactionDB) GetStatementTimeout() time.Duration {
+	return tdb.db.GetStatementTimeout()
+}
+
+// SetMaxIncludeDepth delegates to the main database
+func (tdb *MySQLTransactionDB) SetMaxIncludeDepth(depth int) {
+	tdb.db.SetMaxIncludeDepth(depth)
+}
+
+// GetMaxIncludeDepth delegates to the main database
+func (tdb *MySQLTransactionDB) GetMaxIncludeDepth() int {
+	return tdb.db.GetMaxIncludeDepth()
+}
+
 // SetLogger delegates to the main database
 func (tdb *MySQLTransactionDB) SetLogger(l logger.Logger) {
 	tdb.db.SetLogger(l)
@@ -272,13 +342,14 @@ type MySQLTransactionRawQuery struct {
 
 // Exec executes the query within a transaction
 func (q *MySQLTransactionRawQuery) Exec(ctx context.Context) (types.Result, error) {
+	defer types.ForgetDeduplicated(ctx)
 	start := time.Now()
 	result, err := q.tx.ExecContext(ctx, q.sql, q.args...)
 	duration := time.Since(start)
 
 	if l := q.db.GetLogger(); l != nil {
-		dbLogger := base.NewDBLogger(l)
-		dbLogger.LogSQL(q.sql, q.args, duration)
+		dbLogger := base.NewDBLogger(q.db.Log())
+		dbLogger.LogSQLContext(ctx, q.sql, q.args, duration)
 	}
 
 	if err != nil {
@@ -304,12 +375,12 @@ func (q *MySQLTransactionRawQuery) Exec(ctx context.Context) (types.Result, erro
 // Find executes the query and scans results into dest within a transaction
 func (q *MySQLTransactionRawQuery) Find(ctx context.Context, dest any) error {
 	start := time.Now()
-	rows, err := q.tx.QueryContext(ctx, q.sql, q.args...)
+	rows, err := q.tx.QueryContext(ctx, withExecutionTime(ctx, q.sql), q.args...)
 	duration := time.Since(start)
 
 	if l := q.db.GetLogger(); l != nil {
-		dbLogger := base.NewDBLogger(l)
-		dbLogger.LogSQL(q.sql, q.args, duration)
+		dbLogger := base.NewDBLogger(q.db.Log())
+		dbLogger.LogSQLContext(ctx, q.sql, q.args, duration)
 	}
 
 	if err != nil {
@@ -323,12 +394,12 @@ func (q *MySQLTransactionRawQuery) Find(ctx context.Context, dest any) error {
 // FindOne executes the query and scans a single result into dest within a transaction
 func (q *MySQLTransactionRawQuery) FindOne(ctx context.Context, dest any) error {
 	start := time.Now()
-	err := utils.ScanRowContext(q.tx, ctx, q.sql, q.args, dest)
+	err := utils.ScanRowContext(q.tx, ctx, withExecutionTime(ctx, q.sql), q.args, dest)
 	duration := time.Since(start)
 
 	if l := q.db.GetLogger(); l != nil {
-		dbLogger := base.NewDBLogger(l)
-		dbLogger.LogSQL(q.sql, q.args, duration)
+		dbLogger := base.NewDBLogger(q.db.Log())
+		dbLogger.LogSQLContext(ctx, q.sql, q.args, duration)
 	}
 
 	return err
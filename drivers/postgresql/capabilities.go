@@ -1,10 +1,13 @@
 package postgresql
 
 import (
+	"database/sql"
 	"fmt"
 	"strings"
 
+	"github.com/lib/pq"
 	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
 )
 
 // PostgreSQLCapabilities implements types.DriverCapabilities for PostgreSQL
@@ -37,10 +40,30 @@ func (c *PostgreSQLCapabilities) SupportsForeignKeys() bool {
 	return true // PostgreSQL supports foreign key constraints
 }
 
+func (c *PostgreSQLCapabilities) SupportsUpsert() bool {
+	// INSERT ... ON CONFLICT
+	return true
+}
+
+func (c *PostgreSQLCapabilities) SupportsJSONOperators() bool {
+	// ->, ->>, @> and friends on json/jsonb columns
+	return true
+}
+
+func (c *PostgreSQLCapabilities) SupportsFullText() bool {
+	// tsvector/tsquery full-text search
+	return true
+}
+
+func (c *PostgreSQLCapabilities) MaxParameters() int {
+	// The wire protocol sends the parameter count as int16
+	return 65535
+}
+
 // Identifier quoting
 
 func (c *PostgreSQLCapabilities) QuoteIdentifier(name string) string {
-	return fmt.Sprintf(`"%s"`, name)
+	return utils.QuoteIdentifier(name, '"')
 }
 
 func (c *PostgreSQLCapabilities) GetPlaceholder(index int) string {
@@ -70,6 +93,82 @@ func (c *PostgreSQLCapabilities) GetNullsOrderingSQL(direction types.Order, null
 	return nullsOrder
 }
 
+// GetUpsertSQL returns the ON CONFLICT clause of an upsert, with a ? placeholder per update column
+func (c *PostgreSQLCapabilities) GetUpsertSQL(conflictColumns []string, updateColumns []string) string {
+	target := make([]string, len(conflictColumns))
+	for i, column := range conflictColumns {
+		target[i] = c.QuoteIdentifier(column)
+	}
+	if len(updateColumns) == 0 {
+		return fmt.Sprintf(" ON CONFLICT (%s) DO NOTHING", strings.Join(target, ", "))
+	}
+
+	assignments := make([]string, len(updateColumns))
+	for i, column := range updateColumns {
+		assignments[i] = c.QuoteIdentifier(column) + " = ?"
+	}
+	return fmt.Sprintf(" ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(target, ", "), strings.Join(assignments, ", "))
+}
+
+// GetSkipDuplicatesSQL returns the ON CONFLICT clause that skips rows violating any unique constraint
+func (c *PostgreSQLCapabilities) GetSkipDuplicatesSQL(insertColumns []string) string {
+	return " ON CONFLICT DO NOTHING"
+}
+
+// GetArrayAppendSQL returns an expression appending the array bound to ? to an array column
+func (c *PostgreSQLCapabilities) GetArrayAppendSQL(quotedColumn string) string {
+	return fmt.Sprintf("array_cat(COALESCE(%s, '{}'), ?)", quotedColumn)
+}
+
+// GetArrayFilterSQL returns the condition for a list filter on an array column
+func (c *PostgreSQLCapabilities) GetArrayFilterSQL(operator string, quotedColumn string) string {
+	switch operator {
+	case "has":
+		return fmt.Sprintf("? = ANY(%s)", quotedColumn)
+	case "hasSome":
+		return fmt.Sprintf("%s && ?", quotedColumn)
+	case "hasEvery":
+		return fmt.Sprintf("%s @> ?", quotedColumn)
+	case "isEmpty":
+		return fmt.Sprintf("cardinality(%s) = 0", quotedColumn)
+	case "isNotEmpty":
+		return fmt.Sprintf("cardinality(%s) > 0", quotedColumn)
+	default:
+		return ""
+	}
+}
+
+// EncodeArrayValue encodes a list as an array literal such as {"a","b"}
+func (c *PostgreSQLCapabilities) EncodeArrayValue(values []any) (any, error) {
+	if values == nil {
+		values = []any{}
+	}
+	return pq.GenericArray{A: values}.Value()
+}
+
+// DecodeArrayValue decodes an array column. Elements are returned as strings and
+// converted to the element type of the field by the caller.
+func (c *PostgreSQLCapabilities) DecodeArrayValue(value any) ([]any, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []any:
+		return v, nil
+	}
+
+	var elements []sql.NullString
+	if err := (pq.GenericArray{A: &elements}).Scan(value); err != nil {
+		return nil, fmt.Errorf("failed to decode array: %w", err)
+	}
+	values := make([]any, len(elements))
+	for i, element := range elements {
+		if element.Valid {
+			values[i] = element.String
+		}
+	}
+	return values, nil
+}
+
 // Index/Table detection
 
 func (c *PostgreSQLCapabilities) IsSystemIndex(indexName string) bool {
@@ -120,6 +219,14 @@ func (c *PostgreSQLCapabilities) SupportsTransactions() bool {
 	return true
 }
 
+func (c *PostgreSQLCapabilities) SupportsSavepoints() bool {
+	return true
+}
+
+func (c *PostgreSQLCapabilities) SupportsTransactionalDDL() bool {
+	return true
+}
+
 func (c *PostgreSQLCapabilities) SupportsNestedDocuments() bool {
 	return false // PostgreSQL has JSON/JSONB but not full document support
 }
@@ -0,0 +1,201 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rediwo/redi-orm/base"
+	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
+)
+
+const (
+	// changeNotifyChannel prefixes the LISTEN/NOTIFY channel of each change stream
+	changeNotifyChannel = "redi_orm_changes"
+	// changeNotifyFunction is the trigger function that publishes row changes
+	changeNotifyFunction = "redi_orm_notify_change"
+	// changeTriggerName prefixes the triggers of each change stream
+	changeTriggerName = "redi_orm_change_trigger"
+)
+
+// changeNotifyFunctionSQL publishes every row change as a JSON payload on the
+// channel given as the trigger argument. Payloads are limited to 8000 bytes by
+// PostgreSQL; wide rows are reduced to their table/operation so the consumer
+// still learns about the change.
+var changeNotifyFunctionSQL = fmt.Sprintf(`CREATE OR REPLACE FUNCTION %s() RETURNS trigger AS $$
+DECLARE
+	rec RECORD;
+	payload TEXT;
+BEGIN
+	IF TG_OP = 'DELETE' THEN
+		rec := OLD;
+	ELSE
+		rec := NEW;
+	END IF;
+	payload := json_build_object('table', TG_TABLE_NAME, 'op', TG_OP, 'data', row_to_json(rec))::text;
+	IF octet_length(payload) > 7900 THEN
+		payload := json_build_object('table', TG_TABLE_NAME, 'op', TG_OP)::text;
+	END IF;
+	PERFORM pg_notify(TG_ARGV[0], payload);
+	RETURN rec;
+END;
+$$ LANGUAGE plpgsql`, changeNotifyFunction)
+
+// changeNotification is the payload produced by the trigger function
+type changeNotification struct {
+	Table string         `json:"table"`
+	Op    string         `json:"op"`
+	Data  map[string]any `json:"data"`
+}
+
+// Watch opens a change stream backed by triggers and LISTEN/NOTIFY. Each stream installs
+// triggers of its own, publishing on a channel of its own, and drops them when it closes;
+// the trigger function is dropped with the last triggers using it.
+func (p *PostgreSQLDB) Watch(ctx context.Context, models ...string) (types.ChangeStream, error) {
+	models, err := p.ResolveWatchModels(models)
+	if err != nil {
+		return nil, err
+	}
+
+	tableToModel := make(map[string]string, len(models))
+	for _, modelName := range models {
+		tableName, err := p.ResolveTableName(modelName)
+		if err != nil {
+			return nil, err
+		}
+		tableToModel[tableName] = modelName
+	}
+
+	suffix := strings.ReplaceAll(utils.NewUUID(), "-", "")[:16]
+	channel := changeNotifyChannel + "_" + suffix
+	trigger := changeTriggerName + "_" + suffix
+
+	// Listen before installing the triggers, so that no change is published unheard
+	listener := pq.NewListener(p.nativeURI, 10*time.Second, time.Minute, nil)
+	if err := listener.Listen(channel); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to listen for changes: %w", err)
+	}
+	if err := p.installChangeTriggers(ctx, tableToModel, trigger, channel); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	feed, feedCtx := base.NewChangeFeed(ctx)
+	// The triggers are dropped before the feed finishes, so they are gone once Close returns
+	finish := func(err error) {
+		listener.Close()
+		p.dropChangeTriggers(tableToModel, trigger)
+		feed.Finish(err)
+	}
+	go func() {
+		for {
+			select {
+			case <-feedCtx.Done():
+				finish(feedCtx.Err())
+				return
+			case n := <-listener.Notify:
+				if n == nil {
+					// Connection was re-established; notifications in between are lost
+					continue
+				}
+				event, ok := p.parseChangeNotification(n.Extra, tableToModel)
+				if !ok {
+					continue
+				}
+				if !feed.Emit(feedCtx, event) {
+					finish(feedCtx.Err())
+					return
+				}
+			}
+		}
+	}()
+
+	return feed, nil
+}
+
+// installChangeTriggers creates the notify function and attaches it to each table with a
+// trigger publishing on channel
+func (p *PostgreSQLDB) installChangeTriggers(ctx context.Context, tableToModel map[string]string, trigger, channel string) error {
+	// In one transaction, so that a stream closing meanwhile cannot drop the function
+	// before the triggers use it
+	tx, err := p.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to install change triggers: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, changeNotifyFunctionSQL); err != nil {
+		return fmt.Errorf("failed to create change notify function: %w", err)
+	}
+	for tableName := range tableToModel {
+		stmt := fmt.Sprintf("CREATE TRIGGER %s AFTER INSERT OR UPDATE OR DELETE ON %s FOR EACH ROW EXECUTE PROCEDURE %s(%s)",
+			trigger, p.quoteIdentifier(tableName), changeNotifyFunction, pq.QuoteLiteral(channel))
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to install change trigger on %s: %w", tableName, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// dropChangeTriggers drops the triggers of a closed stream, and the notify function when no
+// other triggers use it
+func (p *PostgreSQLDB) dropChangeTriggers(tableToModel map[string]string, trigger string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for tableName := range tableToModel {
+		stmt := fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s", trigger, p.quoteIdentifier(tableName))
+		if _, err := p.DB.ExecContext(ctx, stmt); err != nil {
+			p.Log().Warn("Failed to drop change trigger on %s: %v", tableName, err)
+		}
+	}
+
+	// Fails while the triggers of other streams, in this or another process, depend on it
+	p.DB.ExecContext(ctx, fmt.Sprintf("DROP FUNCTION IF EXISTS %s()", changeNotifyFunction))
+}
+
+// parseChangeNotification converts a NOTIFY payload into a change event
+func (p *PostgreSQLDB) parseChangeNotification(payload string, tableToModel map[string]string) (types.ChangeEvent, bool) {
+	var n changeNotification
+	if err := json.Unmarshal([]byte(payload), &n); err != nil {
+		return types.ChangeEvent{}, false
+	}
+
+	modelName, watched := tableToModel[n.Table]
+	if !watched {
+		return types.ChangeEvent{}, false
+	}
+
+	var op types.ChangeOperation
+	switch n.Op {
+	case "INSERT":
+		op = types.ChangeCreate
+	case "UPDATE":
+		op = types.ChangeUpdate
+	case "DELETE":
+		op = types.ChangeDelete
+	default:
+		return types.ChangeEvent{}, false
+	}
+
+	data := map[string]any{}
+	if n.Data != nil {
+		if sch, err := p.GetSchema(modelName); err == nil {
+			data, _ = sch.MapColumnDataToSchema(n.Data)
+		} else {
+			data = n.Data
+		}
+	}
+
+	return types.ChangeEvent{
+		Model:     modelName,
+		Operation: op,
+		Data:      data,
+		Timestamp: time.Now(),
+	}, true
+}
@@ -43,11 +43,13 @@ func TestPostgreSQLConformance(t *testing.T) {
 		},
 		Characteristics: test.DriverCharacteristics{
 			ReturnsZeroRowsAffectedForUnchanged: false,
-			SupportsLastInsertID:                false,
+			SupportsLastInsertID:                true,
 			SupportsReturningClause:             true,
 			MigrationTableName:                  "_migrations",
 			SystemIndexPatterns:                 []string{"_pkey", "_key", "_fkey", "pg_*"},
 			AutoIncrementIntegerType:            "SERIAL",
+			MaxOpenConnections:                  25,
+			SlowQuery:                           "SELECT pg_sleep(10)",
 		},
 	}
 
@@ -8,12 +8,13 @@ import (
 	"strings"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/rediwo/redi-orm/base"
 	"github.com/rediwo/redi-orm/query"
 	"github.com/rediwo/redi-orm/registry"
 	"github.com/rediwo/redi-orm/schema"
 	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
 )
 
 // Add init function to register the driver
@@ -77,7 +78,7 @@ func (p *PostgreSQLDB) Connect(ctx context.Context) error {
 		return nil
 	}
 
-	db, err := sql.Open("postgres", p.nativeURI)
+	db, err := sql.Open("postgres", dsnWithStatementTimeout(p.nativeURI, p.GetStatementTimeout()))
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
@@ -96,6 +97,22 @@ func (p *PostgreSQLDB) Connect(ctx context.Context) error {
 	return nil
 }
 
+// dsnWithStatementTimeout sets the statement_timeout of the sessions of dsn to the default
+// statement timeout, so that the server cancels statements that run longer
+func dsnWithStatementTimeout(dsn string, timeout time.Duration) string {
+	if timeout <= 0 || strings.Contains(dsn, "statement_timeout=") {
+		return dsn
+	}
+	setting := fmt.Sprintf("statement_timeout=%d", timeout.Milliseconds())
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		if strings.Contains(dsn, "?") {
+			return dsn + "&" + setting
+		}
+		return dsn + "?" + setting
+	}
+	return dsn + " " + setting
+}
+
 // SyncSchemas synchronizes all loaded schemas with the database
 func (p *PostgreSQLDB) SyncSchemas(ctx context.Context) error {
 	return p.Driver.SyncSchemas(ctx, p)
@@ -123,8 +140,37 @@ func (p *PostgreSQLDB) Model(modelName string) types.ModelQuery {
 	return query.NewModelQuery(modelName, p, p.GetFieldMapper())
 }
 
+// InsertReturningID runs an insert with a RETURNING clause of column, its primary key, for
+// the last insert ID that lib/pq does not report
+func (p *PostgreSQLDB) InsertReturningID(ctx context.Context, sql string, args []any, column string) (types.Result, error) {
+	return insertReturningID(ctx, p.Raw(sql+" RETURNING "+p.quoteIdentifier(column), args...), column)
+}
+
+// insertReturningID runs an insert returning its primary key column, reporting the keys
+func insertReturningID(ctx context.Context, raw types.RawQuery, column string) (types.Result, error) {
+	var rows []map[string]any
+	if err := raw.Find(ctx, &rows); err != nil {
+		return types.Result{}, err
+	}
+
+	result := types.Result{RowsAffected: int64(len(rows))}
+	for _, row := range rows {
+		id := row[column]
+		result.InsertedIDs = append(result.InsertedIDs, id)
+		if n, ok := id.(int64); ok {
+			result.LastInsertID = n
+		}
+	}
+	return result, nil
+}
+
 // Raw creates a raw query
 func (p *PostgreSQLDB) Raw(query string, args ...any) types.RawQuery {
+	query, args, err := utils.ExpandSQLArgs(query, args)
+	if err != nil {
+		return base.NewErrorRawQuery(err)
+	}
+	args = utils.NormalizeTimeArgs(args)
 	return &PostgreSQLRawQuery{
 		db:   p.DB,
 		sql:  query,
@@ -187,6 +233,16 @@ func (p *PostgreSQLDB) Query(query string, args ...any) (*sql.Rows, error) {
 	return p.DB.Query(query, args...)
 }
 
+// QueryContext executes a raw SQL query and returns rows, canceled when ctx is done
+func (p *PostgreSQLDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return p.Driver.QueryContext(ctx, convertPlaceholders(query), args...)
+}
+
+// QueryRowContext executes a raw SQL query and returns a single row, canceled when ctx is done
+func (p *PostgreSQLDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return p.Driver.QueryRowContext(ctx, convertPlaceholders(query), args...)
+}
+
 // QueryRow executes a raw SQL query and returns a single row
 func (p *PostgreSQLDB) QueryRow(query string, args ...any) *sql.Row {
 	// Convert ? placeholders to $1, $2, etc.
@@ -194,6 +250,27 @@ func (p *PostgreSQLDB) QueryRow(query string, args ...any) *sql.Row {
 	return p.DB.QueryRow(query, args...)
 }
 
+// SyncSequences sets the sequences of the auto-increment fields of a model to continue after
+// the largest values in its table
+func (p *PostgreSQLDB) SyncSequences(ctx context.Context, modelName string) error {
+	sch, err := p.GetSchema(modelName)
+	if err != nil {
+		return err
+	}
+	for _, field := range sch.Fields {
+		if !field.AutoIncrement {
+			continue
+		}
+		column := field.GetColumnName()
+		query := fmt.Sprintf("SELECT setval(pg_get_serial_sequence($1, $2), COALESCE(MAX(%s), 0) + 1, false) FROM %s",
+			p.quoteIdentifier(column), p.quoteIdentifier(sch.TableName))
+		if _, err := p.DB.ExecContext(ctx, query, p.quoteIdentifier(sch.TableName), column); err != nil {
+			return fmt.Errorf("failed to sync the sequence of %s.%s: %w", modelName, field.Name, err)
+		}
+	}
+	return nil
+}
+
 // GetMigrator returns the migrator for this database
 func (p *PostgreSQLDB) GetMigrator() types.DatabaseMigrator {
 	return NewPostgreSQLMigrator(p.DB, p)
@@ -220,8 +297,12 @@ func (p *PostgreSQLDB) generateCreateTableSQL(schema *schema.Schema) (string, er
 		columns = append(columns, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(primaryKeys, ", ")))
 	}
 
-	// Add foreign key constraints
-	for _, relation := range schema.Relations {
+	// Add foreign key constraints, unless the ORM emulates them (relationMode = "prisma")
+	relations := schema.Relations
+	if schema.EmulatesForeignKeys() {
+		relations = nil
+	}
+	for _, relation := range relations {
 		if relation.Type == "manyToOne" ||
 			(relation.Type == "oneToOne" && relation.ForeignKey != "") {
 			// Get the referenced table name
@@ -299,7 +380,7 @@ func (p *PostgreSQLDB) generateColumnSQL(field schema.Field) (string, error) {
 	var parts []string
 
 	columnName := p.quoteIdentifier(field.GetColumnName())
-	columnType := p.mapFieldTypeToSQL(field.Type)
+	columnType := p.columnType(field)
 
 	// Handle SERIAL for auto increment primary keys
 	if field.PrimaryKey && field.AutoIncrement {
@@ -323,7 +404,7 @@ func (p *PostgreSQLDB) generateColumnSQL(field schema.Field) (string, error) {
 	}
 
 	// Add DEFAULT value
-	if field.Default != nil && !field.AutoIncrement {
+	if field.ColumnDefault() != nil && !field.AutoIncrement {
 		defaultValue := p.formatDefaultValue(field.Default, field.Type)
 		parts = append(parts, fmt.Sprintf("DEFAULT %s", defaultValue))
 	}
@@ -331,6 +412,25 @@ func (p *PostgreSQLDB) generateColumnSQL(field schema.Field) (string, error) {
 	return strings.Join(parts, " "), nil
 }
 
+// columnType returns the column type of a field, honouring the @db native types of
+// DateTime fields. Fields holding UUIDs are of the UUID type.
+func (p *PostgreSQLDB) columnType(field schema.Field) string {
+	if field.HoldsUUID() {
+		return "UUID"
+	}
+	if field.Type == schema.FieldTypeDateTime {
+		switch field.NativeType() {
+		case "Date":
+			return "DATE"
+		case "Time":
+			return "TIME"
+		case "Timestamptz":
+			return "TIMESTAMPTZ"
+		}
+	}
+	return p.mapFieldTypeToSQL(field.Type)
+}
+
 // mapFieldTypeToSQL maps schema field types to PostgreSQL data types
 func (p *PostgreSQLDB) mapFieldTypeToSQL(fieldType schema.FieldType) string {
 	switch fieldType {
@@ -350,6 +450,22 @@ func (p *PostgreSQLDB) mapFieldTypeToSQL(fieldType schema.FieldType) string {
 		return "JSONB"
 	case schema.FieldTypeDecimal:
 		return "DECIMAL(10,2)"
+	case schema.FieldTypeBytes:
+		return "BYTEA"
+	case schema.FieldTypeStringArray:
+		return "TEXT[]"
+	case schema.FieldTypeIntArray:
+		return "INTEGER[]"
+	case schema.FieldTypeInt64Array:
+		return "BIGINT[]"
+	case schema.FieldTypeFloatArray:
+		return "DOUBLE PRECISION[]"
+	case schema.FieldTypeBoolArray:
+		return "BOOLEAN[]"
+	case schema.FieldTypeDecimalArray:
+		return "DECIMAL[]"
+	case schema.FieldTypeDateTimeArray:
+		return "TIMESTAMP[]"
 	default:
 		return "TEXT"
 	}
@@ -373,6 +489,13 @@ func (p *PostgreSQLDB) formatDefaultValue(value any, fieldType schema.FieldType)
 			return "TRUE"
 		}
 		return "FALSE"
+	case []any:
+		// An array literal such as '{"a","b"}'
+		literal, err := pq.GenericArray{A: v}.Value()
+		if err != nil {
+			return "'{}'"
+		}
+		return pq.QuoteLiteral(fmt.Sprint(literal))
 	case nil:
 		return "NULL"
 	default:
@@ -382,7 +505,12 @@ func (p *PostgreSQLDB) formatDefaultValue(value any, fieldType schema.FieldType)
 
 // quoteIdentifier quotes an identifier for PostgreSQL
 func (p *PostgreSQLDB) quoteIdentifier(name string) string {
-	return fmt.Sprintf(`"%s"`, name)
+	return utils.QuoteIdentifier(name, '"')
+}
+
+// PrepareSQL returns the SQL of a query as it is run, with numbered placeholders
+func (p *PostgreSQLDB) PrepareSQL(sql string) string {
+	return convertPlaceholders(sql)
 }
 
 // convertPlaceholders converts ? placeholders to $1, $2, etc. for PostgreSQL
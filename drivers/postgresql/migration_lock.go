@@ -0,0 +1,42 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rediwo/redi-orm/base"
+)
+
+// migrationLockKey is the key of the advisory lock serializing migrations. Advisory locks are
+// scoped to the database, so databases sharing a server do not wait for each other.
+const migrationLockKey int64 = 0x7265646d6967
+
+// LockMigrations takes a session advisory lock, which PostgreSQL releases by itself if the
+// process dies while migrating
+func (p *PostgreSQLDB) LockMigrations(ctx context.Context, timeout time.Duration) (func() error, error) {
+	if p.DB == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+
+	// Advisory locks belong to the session, so the lock is taken and released on one connection
+	conn, err := p.DB.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	err = base.WaitForMigrationLock(ctx, timeout, func() (bool, error) {
+		var locked bool
+		err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", migrationLockKey).Scan(&locked)
+		return locked, err
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return func() error {
+		defer conn.Close()
+		_, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockKey)
+		return err
+	}, nil
+}
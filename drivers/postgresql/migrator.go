@@ -3,14 +3,19 @@ package postgresql
 import (
 	"database/sql"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/rediwo/redi-orm/base"
 	"github.com/rediwo/redi-orm/schema"
 	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
 )
 
+// castLiteralPattern matches quoted default values with a type cast, e.g. 'active'::status
+var castLiteralPattern = regexp.MustCompile(`^'((?:[^']|'')*)'::.+$`)
+
 // PostgreSQLMigrator implements types.DatabaseSpecificMigrator for PostgreSQL
 type PostgreSQLMigrator struct {
 	db           *sql.DB
@@ -77,11 +82,20 @@ func (m *PostgreSQLMigrator) GetTableInfo(tableName string) (*types.TableInfo, e
 		Indexes: []types.IndexInfo{},
 	}
 
+	// Get table comment
+	var tableComment sql.NullString
+	commentQuery := `SELECT obj_description(('public.' || quote_ident($1))::regclass, 'pg_class')`
+	if err := m.db.QueryRow(commentQuery, tableName).Scan(&tableComment); err != nil {
+		return nil, fmt.Errorf("failed to query table comment: %w", err)
+	}
+	tableInfo.Comment = tableComment.String
+
 	// Get column information
 	columnQuery := `
 		SELECT 
 			column_name,
 			data_type,
+			udt_name,
 			character_maximum_length,
 			numeric_precision,
 			numeric_scale,
@@ -90,7 +104,8 @@ func (m *PostgreSQLMigrator) GetTableInfo(tableName string) (*types.TableInfo, e
 			CASE 
 				WHEN column_default LIKE 'nextval%' THEN true
 				ELSE false
-			END as is_auto_increment
+			END as is_auto_increment,
+			col_description(('public.' || quote_ident(table_name))::regclass, ordinal_position) as column_comment
 		FROM information_schema.columns
 		WHERE table_schema = 'public' AND table_name = $1
 		ORDER BY ordinal_position
@@ -104,37 +119,47 @@ func (m *PostgreSQLMigrator) GetTableInfo(tableName string) (*types.TableInfo, e
 
 	for rows.Next() {
 		var colInfo types.ColumnInfo
-		var dataType string
+		var dataType, udtName string
 		var charMaxLength, numericPrecision, numericScale sql.NullInt64
 		var isNullable string
-		var columnDefault sql.NullString
+		var columnDefault, columnComment sql.NullString
 		var isAutoIncrement bool
 
 		err := rows.Scan(
 			&colInfo.Name,
 			&dataType,
+			&udtName,
 			&charMaxLength,
 			&numericPrecision,
 			&numericScale,
 			&isNullable,
 			&columnDefault,
 			&isAutoIncrement,
+			&columnComment,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan column info: %w", err)
 		}
 
 		// Build full type string
-		colInfo.Type = m.buildColumnType(dataType, charMaxLength, numericPrecision, numericScale)
+		if dataType == "ARRAY" {
+			colInfo.Type = arrayColumnType(udtName)
+		} else {
+			colInfo.Type = m.buildColumnType(dataType, charMaxLength, numericPrecision, numericScale)
+		}
 		colInfo.Nullable = isNullable == "YES"
 		colInfo.AutoIncrement = isAutoIncrement
+		colInfo.Comment = columnComment.String
+		if dataType == "USER-DEFINED" {
+			colInfo.Enum = udtName
+		}
 
 		if columnDefault.Valid {
-			// Clean up PostgreSQL default values
+			// Clean up PostgreSQL default values: 'value'::type becomes value
 			defaultValue := columnDefault.String
-			defaultValue = strings.TrimPrefix(defaultValue, "'")
-			defaultValue = strings.TrimSuffix(defaultValue, "'::character varying")
-			defaultValue = strings.TrimSuffix(defaultValue, "'::text")
+			if match := castLiteralPattern.FindStringSubmatch(defaultValue); match != nil {
+				defaultValue = strings.ReplaceAll(match[1], "''", "'")
+			}
 
 			// Handle boolean defaults
 			if dataType == "boolean" {
@@ -154,6 +179,24 @@ func (m *PostgreSQLMigrator) GetTableInfo(tableName string) (*types.TableInfo, e
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("failed to iterate columns: %w", err)
 	}
+	rows.Close()
+
+	// Get the values of native enum columns
+	for i := range tableInfo.Columns {
+		if tableInfo.Columns[i].Enum == "" {
+			continue
+		}
+		values, err := m.getEnumValues(tableInfo.Columns[i].Enum)
+		if err != nil {
+			return nil, err
+		}
+		if len(values) == 0 {
+			// Other user-defined types (domains, composites) are not enums
+			tableInfo.Columns[i].Enum = ""
+			continue
+		}
+		tableInfo.Columns[i].EnumValues = values
+	}
 
 	// Get primary key information
 	pkQuery := `
@@ -190,9 +233,9 @@ func (m *PostgreSQLMigrator) GetTableInfo(tableName string) (*types.TableInfo, e
 		}
 	}
 
-	// Get unique constraint information
+	// Get single-column unique constraints; composite ones are reported as indexes
 	uniqueQuery := `
-		SELECT kcu.column_name
+		SELECT MIN(kcu.column_name)
 		FROM information_schema.table_constraints tc
 		JOIN information_schema.key_column_usage kcu
 			ON tc.constraint_name = kcu.constraint_name
@@ -200,6 +243,8 @@ func (m *PostgreSQLMigrator) GetTableInfo(tableName string) (*types.TableInfo, e
 		WHERE tc.constraint_type = 'UNIQUE'
 			AND tc.table_schema = 'public'
 			AND tc.table_name = $1
+		GROUP BY tc.constraint_name
+		HAVING COUNT(*) = 1
 	`
 
 	uniqueRows, err := m.db.Query(uniqueQuery, tableName)
@@ -266,7 +311,66 @@ func (m *PostgreSQLMigrator) GetTableInfo(tableName string) (*types.TableInfo, e
 	return tableInfo, nil
 }
 
+// getEnumValues returns the values of a native enum type in declaration order
+func (m *PostgreSQLMigrator) getEnumValues(typeName string) ([]string, error) {
+	rows, err := m.db.Query(`
+		SELECT e.enumlabel
+		FROM pg_type t
+		JOIN pg_enum e ON e.enumtypid = t.oid
+		JOIN pg_namespace n ON n.oid = t.typnamespace
+		WHERE t.typname = $1 AND n.nspname = 'public'
+		ORDER BY e.enumsortorder
+	`, typeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query enum values of %s: %w", typeName, err)
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, fmt.Errorf("failed to scan enum value: %w", err)
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
 // buildColumnType builds the full column type string
+// arrayColumnType returns the column type of an array column from its udt_name, which is
+// the element type prefixed with an underscore (e.g. _int4 for INTEGER[])
+func arrayColumnType(udtName string) string {
+	switch strings.TrimPrefix(udtName, "_") {
+	case "text":
+		return "TEXT[]"
+	case "varchar":
+		return "VARCHAR[]"
+	case "int4":
+		return "INTEGER[]"
+	case "int8":
+		return "BIGINT[]"
+	case "int2":
+		return "SMALLINT[]"
+	case "float8":
+		return "DOUBLE PRECISION[]"
+	case "float4":
+		return "REAL[]"
+	case "bool":
+		return "BOOLEAN[]"
+	case "numeric":
+		return "DECIMAL[]"
+	case "timestamp":
+		return "TIMESTAMP[]"
+	case "timestamptz":
+		return "TIMESTAMPTZ[]"
+	case "date":
+		return "DATE[]"
+	default:
+		return strings.ToUpper(strings.TrimPrefix(udtName, "_")) + "[]"
+	}
+}
+
 func (m *PostgreSQLMigrator) buildColumnType(dataType string, charMaxLength, numericPrecision, numericScale sql.NullInt64) string {
 	switch dataType {
 	case "character varying":
@@ -326,7 +430,7 @@ func (m *PostgreSQLMigrator) GenerateCreateTableSQL(schema *schema.Schema) (stri
 
 // GenerateDropTableSQL generates DROP TABLE SQL
 func (m *PostgreSQLMigrator) GenerateDropTableSQL(tableName string) string {
-	return fmt.Sprintf("DROP TABLE IF EXISTS %s CASCADE", m.quote(tableName))
+	return fmt.Sprintf("DROP TABLE IF EXISTS %s CASCADE", m.QuoteIdentifier(tableName))
 }
 
 // GenerateAddColumnSQL generates ALTER TABLE ADD COLUMN SQL
@@ -334,11 +438,11 @@ func (m *PostgreSQLMigrator) GenerateAddColumnSQL(tableName string, column any)
 	switch col := column.(type) {
 	case types.ColumnInfo:
 		columnDef := m.GenerateColumnDefinitionFromColumnInfo(col)
-		sql := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", m.quote(tableName), columnDef)
+		sql := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", m.QuoteIdentifier(tableName), columnDef)
 		return sql, nil
 	case schema.Field:
 		columnDef := m.GenerateColumnDefinition(col)
-		sql := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", m.quote(tableName), columnDef)
+		sql := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", m.QuoteIdentifier(tableName), columnDef)
 		return sql, nil
 	default:
 		return "", fmt.Errorf("unsupported column type: %T", column)
@@ -352,13 +456,13 @@ func (m *PostgreSQLMigrator) GenerateModifyColumnSQL(change types.ColumnChange)
 	}
 
 	var sqls []string
-	tableName := m.quote(change.TableName)
-	columnName := m.quote(change.NewColumn.Name)
+	tableName := m.QuoteIdentifier(change.TableName)
+	columnName := m.QuoteIdentifier(change.NewColumn.Name)
 
 	// Handle column rename
 	if change.OldColumn != nil && change.OldColumn.Name != change.NewColumn.Name {
 		sql := fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s",
-			tableName, m.quote(change.OldColumn.Name), columnName)
+			tableName, m.QuoteIdentifier(change.OldColumn.Name), columnName)
 		sqls = append(sqls, sql)
 	}
 
@@ -396,7 +500,7 @@ func (m *PostgreSQLMigrator) GenerateModifyColumnSQL(change types.ColumnChange)
 			// Add unique constraint
 			constraintName := fmt.Sprintf("uk_%s_%s", change.TableName, change.NewColumn.Name)
 			sql := fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s UNIQUE (%s)",
-				tableName, m.quote(constraintName), columnName)
+				tableName, m.QuoteIdentifier(constraintName), columnName)
 			sqls = append(sqls, sql)
 		} else {
 			// Drop unique constraint - need to find the constraint name
@@ -430,7 +534,7 @@ func (m *PostgreSQLMigrator) GenerateModifyColumnSQL(change types.ColumnChange)
 
 // GenerateDropColumnSQL generates ALTER TABLE DROP COLUMN SQL
 func (m *PostgreSQLMigrator) GenerateDropColumnSQL(tableName, columnName string) ([]string, error) {
-	sql := fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", m.quote(tableName), m.quote(columnName))
+	sql := fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", m.QuoteIdentifier(tableName), m.QuoteIdentifier(columnName))
 	return []string{sql}, nil
 }
 
@@ -438,7 +542,7 @@ func (m *PostgreSQLMigrator) GenerateDropColumnSQL(tableName, columnName string)
 func (m *PostgreSQLMigrator) GenerateCreateIndexSQL(tableName, indexName string, columns []string, unique bool) string {
 	quotedColumns := make([]string, len(columns))
 	for i, col := range columns {
-		quotedColumns[i] = m.quote(col)
+		quotedColumns[i] = m.QuoteIdentifier(col)
 	}
 
 	indexType := "INDEX"
@@ -447,17 +551,36 @@ func (m *PostgreSQLMigrator) GenerateCreateIndexSQL(tableName, indexName string,
 	}
 
 	return fmt.Sprintf("CREATE %s %s ON %s (%s)",
-		indexType, m.quote(indexName), m.quote(tableName), strings.Join(quotedColumns, ", "))
+		indexType, m.QuoteIdentifier(indexName), m.QuoteIdentifier(tableName), strings.Join(quotedColumns, ", "))
 }
 
 // GenerateDropIndexSQL generates DROP INDEX SQL
 func (m *PostgreSQLMigrator) GenerateDropIndexSQL(indexName string) string {
-	return fmt.Sprintf("DROP INDEX IF EXISTS %s", m.quote(indexName))
+	return fmt.Sprintf("DROP INDEX IF EXISTS %s", m.QuoteIdentifier(indexName))
+}
+
+// GenerateUpdatedAtTriggerSQL generates a trigger function and a BEFORE UPDATE trigger
+// setting the columns to the current time when an update leaves them unchanged
+func (m *PostgreSQLMigrator) GenerateUpdatedAtTriggerSQL(tableName string, columns []string) []string {
+	var body strings.Builder
+	for _, column := range columns {
+		quoted := m.QuoteIdentifier(column)
+		body.WriteString(fmt.Sprintf("IF NEW.%s IS NOT DISTINCT FROM OLD.%s THEN NEW.%s = CURRENT_TIMESTAMP; END IF; ",
+			quoted, quoted, quoted))
+	}
+
+	function := m.QuoteIdentifier(tableName + "_set_updated_at")
+	return []string{
+		fmt.Sprintf("CREATE OR REPLACE FUNCTION %s() RETURNS TRIGGER AS $$ BEGIN %sRETURN NEW; END; $$ LANGUAGE plpgsql",
+			function, body.String()),
+		fmt.Sprintf("CREATE TRIGGER %s BEFORE UPDATE ON %s FOR EACH ROW EXECUTE FUNCTION %s()",
+			m.QuoteIdentifier(tableName+"_updated_at"), m.QuoteIdentifier(tableName), function),
+	}
 }
 
 // GenerateColumnDefinitionFromColumnInfo generates column definition from ColumnInfo
 func (m *PostgreSQLMigrator) GenerateColumnDefinitionFromColumnInfo(column types.ColumnInfo) string {
-	parts := []string{m.quote(column.Name), column.Type}
+	parts := []string{m.QuoteIdentifier(column.Name), column.Type}
 
 	if column.PrimaryKey {
 		parts = append(parts, "PRIMARY KEY")
@@ -485,14 +608,14 @@ func (m *PostgreSQLMigrator) GenerateColumnDefinitionFromColumnInfo(column types
 func (m *PostgreSQLMigrator) GenerateColumnDefinition(field schema.Field) string {
 	column := types.ColumnInfo{
 		Name:          field.GetColumnName(),
-		Type:          m.postgresqlDB.mapFieldTypeToSQL(field.Type),
+		Type:          m.postgresqlDB.columnType(field),
 		Nullable:      field.Nullable,
 		PrimaryKey:    field.PrimaryKey,
 		Unique:        field.Unique,
 		AutoIncrement: field.AutoIncrement,
 	}
 
-	if field.Default != nil {
+	if field.ColumnDefault() != nil {
 		// Store the formatted default value as a string in the any field
 		formattedDefault := m.postgresqlDB.formatDefaultValue(field.Default, field.Type)
 		column.Default = formattedDefault
@@ -628,7 +751,15 @@ func (m *PostgreSQLMigrator) NormalizeDefaultToPrismaFunction(value any, fieldTy
 // MapDatabaseTypeToFieldType converts PostgreSQL column types to schema field types
 func (m *PostgreSQLMigrator) MapDatabaseTypeToFieldType(dbType string) schema.FieldType {
 	// Normalize the type to lowercase and remove size specifications
-	dbType = strings.ToLower(dbType)
+	dbType = strings.ToLower(strings.TrimSpace(dbType))
+
+	// Array types map to the list of their element type
+	if elementType, ok := strings.CutSuffix(dbType, "[]"); ok {
+		if fieldType := schema.ArrayFieldType(m.MapDatabaseTypeToFieldType(elementType)); fieldType != "" {
+			return fieldType
+		}
+		return schema.FieldTypeJSON
+	}
 
 	// Remove size specifications like (255) or (10,2)
 	if idx := strings.Index(dbType, "("); idx != -1 {
@@ -686,7 +817,7 @@ func (m *PostgreSQLMigrator) MapDatabaseTypeToFieldType(dbType string) schema.Fi
 
 	// Binary types
 	case "bytea":
-		return schema.FieldTypeBinary
+		return schema.FieldTypeBytes
 
 	// JSON types
 	case "json", "jsonb":
@@ -711,9 +842,9 @@ func (m *PostgreSQLMigrator) ApplyMigration(sql string) error {
 	return nil
 }
 
-// quote quotes an identifier for PostgreSQL
-func (m *PostgreSQLMigrator) quote(name string) string {
-	return fmt.Sprintf(`"%s"`, name)
+// QuoteIdentifier quotes an identifier for PostgreSQL
+func (m *PostgreSQLMigrator) QuoteIdentifier(name string) string {
+	return utils.QuoteIdentifier(name, '"')
 }
 
 // MapFieldType maps a schema field to PostgreSQL column type
@@ -726,7 +857,7 @@ func (m *PostgreSQLMigrator) MapFieldType(field schema.Field) string {
 			return "BIGSERIAL"
 		}
 	}
-	return m.postgresqlDB.mapFieldTypeToSQL(field.Type)
+	return m.postgresqlDB.columnType(field)
 }
 
 // FormatDefaultValue formats a default value for PostgreSQL
@@ -738,13 +869,13 @@ func (m *PostgreSQLMigrator) FormatDefaultValue(value any) string {
 func (m *PostgreSQLMigrator) ConvertFieldToColumnInfo(field schema.Field) *types.ColumnInfo {
 	// For PostgreSQL, use the actual column type, not SERIAL
 	// SERIAL is only used during CREATE TABLE
-	colType := m.postgresqlDB.mapFieldTypeToSQL(field.Type)
+	colType := m.postgresqlDB.columnType(field)
 
 	return &types.ColumnInfo{
 		Name:          field.GetColumnName(),
 		Type:          colType,
 		Nullable:      field.Nullable,
-		Default:       field.Default,
+		Default:       field.ColumnDefault(),
 		PrimaryKey:    field.PrimaryKey,
 		AutoIncrement: field.AutoIncrement,
 		Unique:        field.Unique,
@@ -3,9 +3,12 @@ package postgresql
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/rediwo/redi-orm/database"
+	"github.com/rediwo/redi-orm/schema"
 	"github.com/rediwo/redi-orm/test"
+	"github.com/rediwo/redi-orm/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -68,6 +71,34 @@ func TestPostgreSQLArrayTypes(t *testing.T) {
 	assert.Len(t, results, 1) // Should find Henry because 'developer' matches
 }
 
+func TestPostgreSQLArrayValues(t *testing.T) {
+	caps := NewPostgreSQLCapabilities()
+
+	encoded, err := caps.EncodeArrayValue([]any{"go", `say "hi"`, nil, 3})
+	require.NoError(t, err)
+	assert.Equal(t, `{"go","say \"hi\"",NULL,3}`, encoded)
+
+	empty, err := caps.EncodeArrayValue(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "{}", empty)
+
+	decoded, err := caps.DecodeArrayValue([]byte(`{go,"say \"hi\"",NULL,3}`))
+	require.NoError(t, err)
+	assert.Equal(t, []any{"go", `say "hi"`, nil, "3"}, decoded)
+
+	decoded, err = caps.DecodeArrayValue(nil)
+	require.NoError(t, err)
+	assert.Nil(t, decoded)
+
+	assert.Equal(t, `"tags" @> ?`, caps.GetArrayFilterSQL("hasEvery", `"tags"`))
+	assert.Equal(t, "", caps.GetArrayFilterSQL("unknown", `"tags"`))
+
+	migrator := &PostgreSQLMigrator{}
+	assert.Equal(t, "TEXT[]", arrayColumnType("_text"))
+	assert.Equal(t, schema.FieldTypeIntArray, migrator.MapDatabaseTypeToFieldType(arrayColumnType("_int4")))
+	assert.Equal(t, schema.FieldTypeStringArray, migrator.MapDatabaseTypeToFieldType("varchar(255)[]"))
+}
+
 func TestPostgreSQLJSONTypes(t *testing.T) {
 	uri := test.GetTestDatabaseUri("postgresql")
 
@@ -194,3 +225,120 @@ func TestPostgreSQLCaseSensitivity(t *testing.T) {
 	assert.Len(t, results, 1)
 	assert.Equal(t, "Alice", results[0]["name"])
 }
+
+func TestPostgreSQLInsertedIDs(t *testing.T) {
+	uri := test.GetTestDatabaseUri("postgresql")
+
+	db, err := database.NewFromURI(uri)
+	if err != nil {
+		t.Skipf("Failed to create PostgreSQL database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	err = db.Connect(ctx)
+	if err != nil {
+		t.Skip("PostgreSQL test connection not available")
+	}
+
+	// Create test database with cleanup
+	pgDB, _ := db.(*PostgreSQLDB)
+	cleanupTables(t, pgDB)
+	td := test.NewTestDatabase(t, db, uri, func() {
+		cleanupTables(t, pgDB)
+		db.Close()
+	})
+	defer td.Cleanup()
+
+	err = td.CreateStandardSchemas()
+	require.NoError(t, err)
+
+	// Integer primary keys are reported in LastInsertID
+	result, err := db.Model("User").Insert(map[string]any{
+		"name":  "Alice",
+		"email": "alice@example.com",
+	}).Exec(ctx)
+	require.NoError(t, err)
+	require.Greater(t, result.LastInsertID, int64(0))
+
+	var user map[string]any
+	err = db.Raw("SELECT name FROM users WHERE id = $1", result.LastInsertID).FindOne(ctx, &user)
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", user["name"])
+
+	// Keys generated by the database, like UUIDs, are reported in InsertedIDs
+	_, err = db.Raw("CREATE TABLE tokens (id UUID PRIMARY KEY DEFAULT gen_random_uuid(), name TEXT)").Exec(ctx)
+	require.NoError(t, err)
+
+	tokenSchema := schema.New("Token").
+		AddField(schema.Field{Name: "id", Type: schema.FieldTypeString, PrimaryKey: true}).
+		AddField(schema.Field{Name: "name", Type: schema.FieldTypeString})
+	err = db.RegisterSchema("Token", tokenSchema)
+	require.NoError(t, err)
+
+	result, err = db.Model("Token").Insert(map[string]any{"name": "api"}).Exec(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.RowsAffected)
+	assert.Equal(t, int64(0), result.LastInsertID)
+	require.Len(t, result.InsertedIDs, 1)
+	assert.Len(t, result.InsertedIDs[0], 36)
+}
+
+func TestPostgreSQLWatchDropsTriggers(t *testing.T) {
+	uri := test.GetTestDatabaseUri("postgresql")
+
+	db, err := database.NewFromURI(uri)
+	if err != nil {
+		t.Skipf("Failed to create PostgreSQL database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	err = db.Connect(ctx)
+	if err != nil {
+		t.Skip("PostgreSQL test connection not available")
+	}
+
+	pgDB, _ := db.(*PostgreSQLDB)
+	cleanupTables(t, pgDB)
+	td := test.NewTestDatabase(t, db, uri, func() {
+		cleanupTables(t, pgDB)
+		db.Close()
+	})
+	defer td.Cleanup()
+	require.NoError(t, td.CreateStandardSchemas())
+
+	countTriggers := func() int {
+		var count int
+		require.NoError(t, pgDB.DB.QueryRowContext(ctx,
+			"SELECT COUNT(*) FROM pg_trigger WHERE tgname LIKE 'redi_orm_change_trigger%'").Scan(&count))
+		return count
+	}
+
+	first, err := database.Watch(ctx, db, "User")
+	require.NoError(t, err)
+	second, err := database.Watch(ctx, db, "User")
+	require.NoError(t, err)
+	assert.Equal(t, 2, countTriggers())
+
+	// The triggers of a stream are dropped when it closes; the function stays while others use it
+	require.NoError(t, first.Close())
+	assert.Equal(t, 1, countTriggers())
+
+	_, err = db.Exec("INSERT INTO users (name, email, age, active) VALUES ($1, $2, $3, $4)", "Ivy", "ivy@example.com", 30, true)
+	require.NoError(t, err)
+	select {
+	case event := <-second.Events():
+		assert.Equal(t, types.ChangeCreate, event.Operation)
+		assert.Equal(t, "Ivy", event.Data["name"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for change event")
+	}
+
+	require.NoError(t, second.Close())
+	assert.Equal(t, 0, countTriggers())
+	var functions int
+	require.NoError(t, pgDB.DB.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM pg_proc WHERE proname = 'redi_orm_notify_change'").Scan(&functions))
+	assert.Equal(t, 0, functions)
+}
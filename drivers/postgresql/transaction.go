@@ -22,11 +22,13 @@ type PostgreSQLTransaction struct {
 
 // Commit commits the transaction
 func (t *PostgreSQLTransaction) Commit(ctx context.Context) error {
+	defer types.ForgetDeduplicated(ctx)
 	return t.tx.Commit()
 }
 
 // Rollback rolls back the transaction
 func (t *PostgreSQLTransaction) Rollback(ctx context.Context) error {
+	defer types.ForgetDeduplicated(ctx)
 	return t.tx.Rollback()
 }
 
@@ -87,6 +89,11 @@ func (t *PostgreSQLTransaction) DeleteMany(ctx context.Context, modelName string
 
 // Raw creates a raw query within the transaction
 func (t *PostgreSQLTransaction) Raw(query string, args ...any) types.RawQuery {
+	query, args, err := utils.ExpandSQLArgs(query, args)
+	if err != nil {
+		return base.NewErrorRawQuery(err)
+	}
+	args = utils.NormalizeTimeArgs(args)
 	return &PostgreSQLTransactionRawQuery{
 		tx:   t.tx,
 		sql:  query,
@@ -105,6 +112,7 @@ type PostgreSQLTransactionRawQuery struct {
 
 // Exec executes the query within the transaction
 func (q *PostgreSQLTransactionRawQuery) Exec(ctx context.Context) (types.Result, error) {
+	defer types.ForgetDeduplicated(ctx)
 	// Convert ? placeholders to $1, $2, etc.
 	sql := convertPlaceholders(q.sql)
 	start := time.Now()
@@ -112,8 +120,8 @@ func (q *PostgreSQLTransactionRawQuery) Exec(ctx context.Context) (types.Result,
 	duration := time.Since(start)
 
 	if l := q.db.GetLogger(); l != nil {
-		dbLogger := base.NewDBLogger(l)
-		dbLogger.LogSQL(sql, q.args, duration)
+		dbLogger := base.NewDBLogger(q.db.Log())
+		dbLogger.LogSQLContext(ctx, sql, q.args, duration)
 	}
 
 	if err != nil {
@@ -138,8 +146,8 @@ func (q *PostgreSQLTransactionRawQuery) Find(ctx context.Context, dest any) erro
 	duration := time.Since(start)
 
 	if l := q.db.GetLogger(); l != nil {
-		dbLogger := base.NewDBLogger(l)
-		dbLogger.LogSQL(sql, q.args, duration)
+		dbLogger := base.NewDBLogger(q.db.Log())
+		dbLogger.LogSQLContext(ctx, sql, q.args, duration)
 	}
 
 	if err != nil {
@@ -159,8 +167,8 @@ func (q *PostgreSQLTransactionRawQuery) FindOne(ctx context.Context, dest any) e
 	duration := time.Since(start)
 
 	if l := q.db.GetLogger(); l != nil {
-		dbLogger := base.NewDBLogger(l)
-		dbLogger.LogSQL(sql, q.args, duration)
+		dbLogger := base.NewDBLogger(q.db.Log())
+		dbLogger.LogSQLContext(ctx, sql, q.args, duration)
 	}
 
 	return err
@@ -182,8 +190,18 @@ func (t *PostgreSQLTransactionDB) GetCapabilities() types.DriverCapabilities {
 	return t.PostgreSQLDB.GetCapabilities()
 }
 
+// InsertReturningID runs an insert returning its primary key column within the transaction
+func (t *PostgreSQLTransactionDB) InsertReturningID(ctx context.Context, sql string, args []any, column string) (types.Result, error) {
+	return insertReturningID(ctx, t.Raw(sql+" RETURNING "+t.quoteIdentifier(column), args...), column)
+}
+
 // Raw creates a raw query within the transaction
 func (t *PostgreSQLTransactionDB) Raw(query string, args ...any) types.RawQuery {
+	query, args, err := utils.ExpandSQLArgs(query, args)
+	if err != nil {
+		return base.NewErrorRawQuery(err)
+	}
+	args = utils.NormalizeTimeArgs(args)
 	return &PostgreSQLTransactionRawQuery{
 		tx:   t.tx,
 		sql:  query,
@@ -192,9 +210,9 @@ func (t *PostgreSQLTransactionDB) Raw(query string, args ...any) types.RawQuery
 	}
 }
 
-// Transaction within a transaction is not supported
+// Transaction runs fn in the enclosing transaction
 func (t *PostgreSQLTransactionDB) Transaction(ctx context.Context, fn func(tx types.Transaction) error) error {
-	return fmt.Errorf("nested transactions are not supported, use savepoints instead")
+	return fn(&PostgreSQLTransaction{tx: t.tx, db: t.PostgreSQLDB})
 }
 
 // Begin within a transaction is not supported
@@ -286,7 +304,7 @@ func (t *PostgreSQLTransactionDB) Exec(query string, args ...any) (sql.Result, e
 	duration := time.Since(start)
 
 	if l := t.PostgreSQLDB.GetLogger(); l != nil {
-		dbLogger := base.NewDBLogger(l)
+		dbLogger := base.NewDBLogger(t.PostgreSQLDB.Log())
 		dbLogger.LogSQL(query, args, duration)
 	}
 
@@ -302,7 +320,7 @@ func (t *PostgreSQLTransactionDB) Query(query string, args ...any) (*sql.Rows, e
 	duration := time.Since(start)
 
 	if l := t.PostgreSQLDB.GetLogger(); l != nil {
-		dbLogger := base.NewDBLogger(l)
+		dbLogger := base.NewDBLogger(t.PostgreSQLDB.Log())
 		dbLogger.LogSQL(query, args, duration)
 	}
 
@@ -318,13 +336,47 @@ func (t *PostgreSQLTransactionDB) QueryRow(query string, args ...any) *sql.Row {
 	duration := time.Since(start)
 
 	if l := t.PostgreSQLDB.GetLogger(); l != nil {
-		dbLogger := base.NewDBLogger(l)
+		dbLogger := base.NewDBLogger(t.PostgreSQLDB.Log())
 		dbLogger.LogSQL(query, args, duration)
 	}
 
 	return row
 }
 
+// QueryContext executes a raw SQL query that returns rows within the transaction, canceled
+// when ctx is done
+func (t *PostgreSQLTransactionDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	// Convert ? placeholders to $1, $2, etc.
+	query = convertPlaceholders(query)
+	start := time.Now()
+	rows, err := t.tx.QueryContext(ctx, query, args...)
+	duration := time.Since(start)
+
+	if l := t.PostgreSQLDB.GetLogger(); l != nil {
+		dbLogger := base.NewDBLogger(t.PostgreSQLDB.Log())
+		dbLogger.LogSQLContext(ctx, query, args, duration)
+	}
+
+	return rows, err
+}
+
+// QueryRowContext executes a raw SQL query that returns a single row within the transaction,
+// canceled when ctx is done
+func (t *PostgreSQLTransactionDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	// Convert ? placeholders to $1, $2, etc.
+	query = convertPlaceholders(query)
+	start := time.Now()
+	row := t.tx.QueryRowContext(ctx, query, args...)
+	duration := time.Since(start)
+
+	if l := t.PostgreSQLDB.GetLogger(); l != nil {
+		dbLogger := base.NewDBLogger(t.PostgreSQLDB.Log())
+		dbLogger.LogSQLContext(ctx, query, args, duration)
+	}
+
+	return row
+}
+
 // Connect is not supported within a transaction
 func (t *PostgreSQLTransactionDB) Connect(ctx context.Context) error {
 	return fmt.Errorf("cannot connect within a transaction")
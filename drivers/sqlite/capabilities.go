@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
 )
 
 // SQLiteCapabilities implements types.DriverCapabilities for SQLite
@@ -37,10 +38,30 @@ func (c *SQLiteCapabilities) SupportsForeignKeys() bool {
 	return true // SQLite supports foreign key constraints
 }
 
+func (c *SQLiteCapabilities) SupportsUpsert() bool {
+	// INSERT ... ON CONFLICT (SQLite 3.24+)
+	return true
+}
+
+func (c *SQLiteCapabilities) SupportsJSONOperators() bool {
+	// json_extract and the ->/->> operators (SQLite 3.38+)
+	return true
+}
+
+func (c *SQLiteCapabilities) SupportsFullText() bool {
+	// Full-text search needs FTS5 virtual tables
+	return false
+}
+
+func (c *SQLiteCapabilities) MaxParameters() int {
+	// SQLITE_MAX_VARIABLE_NUMBER default since SQLite 3.32
+	return 32766
+}
+
 // Identifier quoting
 
 func (c *SQLiteCapabilities) QuoteIdentifier(name string) string {
-	return fmt.Sprintf("`%s`", name)
+	return utils.QuoteIdentifier(name, '`')
 }
 
 func (c *SQLiteCapabilities) GetPlaceholder(index int) string {
@@ -70,6 +91,62 @@ func (c *SQLiteCapabilities) GetNullsOrderingSQL(direction types.Order, nullsFir
 	return nullsOrder
 }
 
+// GetUpsertSQL returns the ON CONFLICT clause of an upsert, with a ? placeholder per update column
+func (c *SQLiteCapabilities) GetUpsertSQL(conflictColumns []string, updateColumns []string) string {
+	target := make([]string, len(conflictColumns))
+	for i, column := range conflictColumns {
+		target[i] = c.QuoteIdentifier(column)
+	}
+	if len(updateColumns) == 0 {
+		return fmt.Sprintf(" ON CONFLICT (%s) DO NOTHING", strings.Join(target, ", "))
+	}
+
+	assignments := make([]string, len(updateColumns))
+	for i, column := range updateColumns {
+		assignments[i] = c.QuoteIdentifier(column) + " = ?"
+	}
+	return fmt.Sprintf(" ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(target, ", "), strings.Join(assignments, ", "))
+}
+
+// GetSkipDuplicatesSQL returns the ON CONFLICT clause that skips rows violating any unique constraint
+func (c *SQLiteCapabilities) GetSkipDuplicatesSQL(insertColumns []string) string {
+	return " ON CONFLICT DO NOTHING"
+}
+
+// GetArrayAppendSQL returns an expression appending the JSON array bound to ? to a list
+// column. SQLite has no native arrays, so lists are stored as JSON arrays.
+func (c *SQLiteCapabilities) GetArrayAppendSQL(quotedColumn string) string {
+	return fmt.Sprintf("(SELECT json_group_array(value) FROM (SELECT value FROM json_each(COALESCE(%s, '[]')) UNION ALL SELECT value FROM json_each(?)))", quotedColumn)
+}
+
+// GetArrayFilterSQL returns the condition for a list filter on a JSON array column
+func (c *SQLiteCapabilities) GetArrayFilterSQL(operator string, quotedColumn string) string {
+	switch operator {
+	case "has":
+		return fmt.Sprintf("EXISTS (SELECT 1 FROM json_each(%s) WHERE value = ?)", quotedColumn)
+	case "hasSome":
+		return fmt.Sprintf("EXISTS (SELECT 1 FROM json_each(%s) WHERE value IN (SELECT value FROM json_each(?)))", quotedColumn)
+	case "hasEvery":
+		return fmt.Sprintf("%s IS NOT NULL AND NOT EXISTS (SELECT 1 FROM json_each(?) WHERE value NOT IN (SELECT value FROM json_each(%s)))", quotedColumn, quotedColumn)
+	case "isEmpty":
+		return fmt.Sprintf("json_array_length(%s) = 0", quotedColumn)
+	case "isNotEmpty":
+		return fmt.Sprintf("json_array_length(%s) > 0", quotedColumn)
+	default:
+		return ""
+	}
+}
+
+// EncodeArrayValue encodes a list as a JSON array
+func (c *SQLiteCapabilities) EncodeArrayValue(values []any) (any, error) {
+	return utils.EncodeJSONArray(values)
+}
+
+// DecodeArrayValue decodes a list stored as a JSON array
+func (c *SQLiteCapabilities) DecodeArrayValue(value any) ([]any, error) {
+	return utils.DecodeJSONArray(value)
+}
+
 // Index/Table detection
 
 func (c *SQLiteCapabilities) IsSystemIndex(indexName string) bool {
@@ -115,6 +192,14 @@ func (c *SQLiteCapabilities) SupportsTransactions() bool {
 	return true
 }
 
+func (c *SQLiteCapabilities) SupportsSavepoints() bool {
+	return true
+}
+
+func (c *SQLiteCapabilities) SupportsTransactionalDDL() bool {
+	return true
+}
+
 func (c *SQLiteCapabilities) SupportsNestedDocuments() bool {
 	return false
 }
@@ -41,6 +41,7 @@ func TestSQLiteConformance(t *testing.T) {
 			MigrationTableName:                  "_migrations",
 			SystemIndexPatterns:                 []string{"sqlite_*", "pk_*"},
 			AutoIncrementIntegerType:            "INTEGER",
+			SlowQuery:                           "WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c WHERE x < 1000000000) SELECT COUNT(*) AS n FROM c",
 		},
 	}
 
@@ -4,19 +4,36 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"math/big"
 	"strings"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 	"github.com/rediwo/redi-orm/base"
 	"github.com/rediwo/redi-orm/query"
 	"github.com/rediwo/redi-orm/registry"
 	"github.com/rediwo/redi-orm/schema"
 	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
 )
 
+// sqlDriverName is the database/sql driver of the connections, with the collations of the
+// columns registered on each of them
+const sqlDriverName = "sqlite3_redi"
+
+// decimalColumnType is the column type of Decimal fields. It has TEXT affinity, so that
+// decimals keep all their digits rather than becoming 15-digit REAL values, and its columns
+// use the decimal collation to compare and sort as numbers.
+const decimalColumnType = "DECIMAL_TEXT"
+
 func init() {
 	driverType := types.DriverSQLite
 
+	sql.Register(sqlDriverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterCollation("decimal", compareDecimals)
+		},
+	})
+
 	// Register SQLite driver
 	registry.Register(string(driverType), func(uri string) (types.Database, error) {
 		return NewSQLiteDB(uri)
@@ -46,7 +63,7 @@ func NewSQLiteDB(nativeURI string) (*SQLiteDB, error) {
 
 // Connect establishes connection to SQLite database
 func (s *SQLiteDB) Connect(ctx context.Context) error {
-	db, err := sql.Open("sqlite3", s.nativeURI)
+	db, err := sql.Open(sqlDriverName, s.nativeURI)
 	if err != nil {
 		return fmt.Errorf("failed to open SQLite database: %w", err)
 	}
@@ -83,7 +100,7 @@ func (s *SQLiteDB) DropModel(ctx context.Context, modelName string) error {
 		return fmt.Errorf("failed to resolve table name: %w", err)
 	}
 
-	sql := fmt.Sprintf("DROP TABLE IF EXISTS %s", tableName)
+	sql := fmt.Sprintf("DROP TABLE IF EXISTS %s", s.quoteIdentifier(tableName))
 	_, err = s.Exec(sql)
 	if err != nil {
 		return fmt.Errorf("failed to drop table: %w", err)
@@ -99,6 +116,11 @@ func (s *SQLiteDB) Model(modelName string) types.ModelQuery {
 
 // Raw creates a new raw query
 func (s *SQLiteDB) Raw(sql string, args ...any) types.RawQuery {
+	sql, args, err := utils.ExpandSQLArgs(sql, args)
+	if err != nil {
+		return base.NewErrorRawQuery(err)
+	}
+	args = utils.NormalizeTimeArgs(args)
 	return NewSQLiteRawQuery(s, sql, args...)
 }
 
@@ -150,6 +172,11 @@ func (s *SQLiteDB) GetCapabilities() types.DriverCapabilities {
 	return NewSQLiteCapabilities()
 }
 
+// Watch opens a polling-based change stream for the given models
+func (s *SQLiteDB) Watch(ctx context.Context, models ...string) (types.ChangeStream, error) {
+	return s.Driver.Watch(ctx, s, models...)
+}
+
 // GetMigrator returns a migrator for SQLite
 func (s *SQLiteDB) GetMigrator() types.DatabaseMigrator {
 	return NewSQLiteMigrator(s.DB, s)
@@ -169,7 +196,7 @@ func (s *SQLiteDB) generateCreateTableSQL(schema *schema.Schema) (string, error)
 
 		if field.PrimaryKey && !field.AutoIncrement {
 			// For composite primary keys (non-autoincrement)
-			primaryKeys = append(primaryKeys, field.GetColumnName())
+			primaryKeys = append(primaryKeys, s.quoteIdentifier(field.GetColumnName()))
 		}
 	}
 
@@ -178,8 +205,12 @@ func (s *SQLiteDB) generateCreateTableSQL(schema *schema.Schema) (string, error)
 		columns = append(columns, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(primaryKeys, ", ")))
 	}
 
-	// Add foreign key constraints
-	for _, relation := range schema.Relations {
+	// Add foreign key constraints, unless the ORM emulates them (relationMode = "prisma")
+	relations := schema.Relations
+	if schema.EmulatesForeignKeys() {
+		relations = nil
+	}
+	for _, relation := range relations {
 		if relation.Type == "manyToOne" ||
 			(relation.Type == "oneToOne" && relation.ForeignKey != "") {
 			// Get the referenced table name
@@ -224,9 +255,9 @@ func (s *SQLiteDB) generateCreateTableSQL(schema *schema.Schema) (string, error)
 
 			fkConstraint := fmt.Sprintf(
 				"FOREIGN KEY (%s) REFERENCES %s(%s)",
-				foreignKeyColumn,
-				referencedSchema.GetTableName(),
-				referencesColumn,
+				s.quoteIdentifier(foreignKeyColumn),
+				s.quoteIdentifier(referencedSchema.GetTableName()),
+				s.quoteIdentifier(referencesColumn),
 			)
 
 			// Add ON DELETE/UPDATE rules if specified
@@ -242,7 +273,7 @@ func (s *SQLiteDB) generateCreateTableSQL(schema *schema.Schema) (string, error)
 	}
 
 	sql := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n  %s\n)",
-		schema.GetTableName(),
+		s.quoteIdentifier(schema.GetTableName()),
 		strings.Join(columns, ",\n  "))
 
 	return sql, nil
@@ -250,11 +281,11 @@ func (s *SQLiteDB) generateCreateTableSQL(schema *schema.Schema) (string, error)
 
 // generateColumnSQL generates SQL for a single column
 func (s *SQLiteDB) generateColumnSQL(field schema.Field) (string, error) {
-	columnName := field.GetColumnName()
-	sqlType := s.mapFieldTypeToSQL(field.Type)
+	columnName := s.quoteIdentifier(field.GetColumnName())
+	sqlType := s.columnType(field)
 
 	var parts []string
-	parts = append(parts, fmt.Sprintf("%s %s", columnName, sqlType))
+	parts = append(parts, fmt.Sprintf("%s %s", columnName, columnTypeSQL(sqlType)))
 
 	if field.PrimaryKey {
 		parts = append(parts, "PRIMARY KEY")
@@ -271,7 +302,7 @@ func (s *SQLiteDB) generateColumnSQL(field schema.Field) (string, error) {
 		parts = append(parts, "UNIQUE")
 	}
 
-	if field.Default != nil {
+	if field.ColumnDefault() != nil {
 		defaultValue := s.formatDefaultValue(field.Default)
 		parts = append(parts, fmt.Sprintf("DEFAULT %s", defaultValue))
 	}
@@ -308,6 +339,13 @@ func (s *SQLiteDB) formatDefaultValue(value any) string {
 			return "1"
 		}
 		return "0"
+	case []any:
+		// Lists are stored as JSON arrays in text
+		text, err := utils.EncodeJSONArray(v)
+		if err != nil {
+			return "'[]'"
+		}
+		return fmt.Sprintf("'%s'", strings.ReplaceAll(text, "'", "''"))
 	case nil:
 		return "NULL"
 	default:
@@ -315,6 +353,20 @@ func (s *SQLiteDB) formatDefaultValue(value any) string {
 	}
 }
 
+// columnType returns the column type of a field, honouring the @db native types of
+// DateTime fields
+func (s *SQLiteDB) columnType(field schema.Field) string {
+	if field.Type == schema.FieldTypeDateTime {
+		switch field.NativeType() {
+		case "Date":
+			return "DATE"
+		case "Time":
+			return "TIME"
+		}
+	}
+	return s.mapFieldTypeToSQL(field.Type)
+}
+
 // mapFieldTypeToSQL maps schema field types to SQLite SQL types
 func (s *SQLiteDB) mapFieldTypeToSQL(fieldType schema.FieldType) string {
 	switch fieldType {
@@ -333,8 +385,44 @@ func (s *SQLiteDB) mapFieldTypeToSQL(fieldType schema.FieldType) string {
 	case schema.FieldTypeJSON:
 		return "TEXT" // Store JSON as text in SQLite
 	case schema.FieldTypeDecimal:
-		return "DECIMAL"
+		return decimalColumnType
+	case schema.FieldTypeBytes:
+		return "BLOB"
+	case schema.FieldTypeStringArray, schema.FieldTypeIntArray, schema.FieldTypeInt64Array,
+		schema.FieldTypeFloatArray, schema.FieldTypeBoolArray, schema.FieldTypeDecimalArray,
+		schema.FieldTypeDateTimeArray:
+		return "TEXT" // Lists are stored as JSON arrays in text
 	default:
 		return "TEXT"
 	}
 }
+
+// columnTypeSQL returns the SQL of a column type in a column definition, with the collation
+// of the type
+func columnTypeSQL(sqlType string) string {
+	if strings.EqualFold(sqlType, decimalColumnType) {
+		return sqlType + " COLLATE decimal"
+	}
+	return sqlType
+}
+
+// compareDecimals is the decimal collation, ordering the text of decimals by the numbers it
+// holds. Text that is not a number sorts after the numbers.
+func compareDecimals(a, b string) int {
+	x, okA := new(big.Rat).SetString(a)
+	y, okB := new(big.Rat).SetString(b)
+	switch {
+	case okA && okB:
+		return x.Cmp(y)
+	case okA:
+		return -1
+	case okB:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// quoteIdentifier quotes an identifier for SQLite
+func (s *SQLiteDB) quoteIdentifier(name string) string {
+	return utils.QuoteIdentifier(name, '`')
+}
@@ -0,0 +1,48 @@
+//go:build unix
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rediwo/redi-orm/base"
+)
+
+// LockMigrations locks the file named after the database file with a .migration.lock suffix.
+// The operating system releases the lock if the process dies while migrating. In-memory
+// databases are private to the process, so they need no lock.
+func (s *SQLiteDB) LockMigrations(ctx context.Context, timeout time.Duration) (func() error, error) {
+	path := strings.TrimPrefix(s.nativeURI, "file:")
+	path, _, _ = strings.Cut(path, "?")
+	if path == "" || strings.Contains(path, ":memory:") {
+		return func() error { return nil }, nil
+	}
+
+	file, err := os.OpenFile(path+".migration.lock", os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	err = base.WaitForMigrationLock(ctx, timeout, func() (bool, error) {
+		err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return false, nil
+		}
+		return err == nil, err
+	})
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	// The file is left in place, as removing it would let a process lock a new file while
+	// another one waits on the removed one
+	return func() error {
+		defer file.Close()
+		return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+	}, nil
+}
@@ -0,0 +1,16 @@
+//go:build !unix
+
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/rediwo/redi-orm/types"
+)
+
+// LockMigrations leaves locking migrations to the migration table lock where files cannot be
+// locked with flock
+func (s *SQLiteDB) LockMigrations(ctx context.Context, timeout time.Duration) (func() error, error) {
+	return nil, types.ErrLockUnsupported
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/rediwo/redi-orm/base"
 	"github.com/rediwo/redi-orm/schema"
 	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
 )
 
 // SQLiteMigrator implements types.DatabaseSpecificMigrator for SQLite
@@ -111,7 +112,7 @@ func (m *SQLiteMigrator) GetTableInfo(tableName string) (*types.TableInfo, error
 	}
 
 	// Get column information using PRAGMA table_info
-	query := fmt.Sprintf("PRAGMA table_info(%s)", tableName)
+	query := fmt.Sprintf("PRAGMA table_info(%s)", m.QuoteIdentifier(tableName))
 	rows, err := m.db.Query(query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get table info: %w", err)
@@ -159,7 +160,7 @@ func (m *SQLiteMigrator) GetTableInfo(tableName string) (*types.TableInfo, error
 	}
 
 	// Get index information
-	indexRows, err := m.db.Query(fmt.Sprintf("PRAGMA index_list(%s)", tableName))
+	indexRows, err := m.db.Query(fmt.Sprintf("PRAGMA index_list(%s)", m.QuoteIdentifier(tableName)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get index list: %w", err)
 	}
@@ -207,7 +208,7 @@ func (m *SQLiteMigrator) GetTableInfo(tableName string) (*types.TableInfo, error
 	for _, idx := range indexes {
 		// Get columns for this index
 		columns := []string{}
-		indexColRows, err := m.db.Query("PRAGMA index_info(" + idx.name + ")")
+		indexColRows, err := m.db.Query("PRAGMA index_info(" + m.QuoteIdentifier(idx.name) + ")")
 		if err != nil {
 			return nil, fmt.Errorf("failed to get index columns for %s: %w", idx.name, err)
 		}
@@ -247,7 +248,7 @@ func (m *SQLiteMigrator) GetTableInfo(tableName string) (*types.TableInfo, error
 	}
 
 	// Get foreign key information
-	fkRows, err := m.db.Query(fmt.Sprintf("PRAGMA foreign_key_list(%s)", tableName))
+	fkRows, err := m.db.Query(fmt.Sprintf("PRAGMA foreign_key_list(%s)", m.QuoteIdentifier(tableName)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get foreign keys: %w", err)
 	}
@@ -293,7 +294,7 @@ func (m *SQLiteMigrator) GenerateCreateTableSQL(s *schema.Schema) (string, error
 
 // GenerateDropTableSQL generates DROP TABLE SQL
 func (m *SQLiteMigrator) GenerateDropTableSQL(tableName string) string {
-	return fmt.Sprintf("DROP TABLE IF EXISTS %s", tableName)
+	return fmt.Sprintf("DROP TABLE IF EXISTS %s", m.QuoteIdentifier(tableName))
 }
 
 // GenerateAddColumnSQL generates ADD COLUMN SQL
@@ -308,7 +309,7 @@ func (m *SQLiteMigrator) GenerateAddColumnSQL(tableName string, field any) (stri
 		return "", fmt.Errorf("failed to generate column definition: %w", err)
 	}
 
-	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", tableName, columnDef), nil
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", m.QuoteIdentifier(tableName), columnDef), nil
 }
 
 // GenerateModifyColumnSQL generates SQL to modify a column (not directly supported in SQLite)
@@ -345,7 +346,7 @@ func (m *SQLiteMigrator) GenerateModifyColumnSQL(change types.ColumnChange) ([]s
 
 	// Create temporary table with new schema
 	createSQL := fmt.Sprintf("CREATE TABLE %s (\n  %s\n)",
-		tempTableName,
+		m.QuoteIdentifier(tempTableName),
 		strings.Join(columnDefs, ",\n  "))
 	sqls = append(sqls, createSQL)
 
@@ -358,29 +359,29 @@ func (m *SQLiteMigrator) GenerateModifyColumnSQL(change types.ColumnChange) ([]s
 		if col.Name == change.ColumnName {
 			// Handle column name changes or type conversions
 			if change.NewColumn != nil && change.NewColumn.Name != "" {
-				insertColumns = append(insertColumns, change.NewColumn.Name)
+				insertColumns = append(insertColumns, m.QuoteIdentifier(change.NewColumn.Name))
 				// SQLite will attempt automatic type conversion
-				selectColumns = append(selectColumns, col.Name)
+				selectColumns = append(selectColumns, m.QuoteIdentifier(col.Name))
 			}
 		} else {
-			insertColumns = append(insertColumns, col.Name)
-			selectColumns = append(selectColumns, col.Name)
+			insertColumns = append(insertColumns, m.QuoteIdentifier(col.Name))
+			selectColumns = append(selectColumns, m.QuoteIdentifier(col.Name))
 		}
 	}
 
 	copySQL := fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s",
-		tempTableName,
+		m.QuoteIdentifier(tempTableName),
 		strings.Join(insertColumns, ", "),
 		strings.Join(selectColumns, ", "),
-		change.TableName)
+		m.QuoteIdentifier(change.TableName))
 	sqls = append(sqls, copySQL)
 
 	// Drop the old table
-	dropSQL := fmt.Sprintf("DROP TABLE %s", change.TableName)
+	dropSQL := fmt.Sprintf("DROP TABLE %s", m.QuoteIdentifier(change.TableName))
 	sqls = append(sqls, dropSQL)
 
 	// Rename temporary table to original name
-	renameSQL := fmt.Sprintf("ALTER TABLE %s RENAME TO %s", tempTableName, change.TableName)
+	renameSQL := fmt.Sprintf("ALTER TABLE %s RENAME TO %s", m.QuoteIdentifier(tempTableName), m.QuoteIdentifier(change.TableName))
 	sqls = append(sqls, renameSQL)
 
 	// Recreate indexes
@@ -419,7 +420,7 @@ func (m *SQLiteMigrator) GenerateModifyColumnSQL(change types.ColumnChange) ([]s
 func (m *SQLiteMigrator) GenerateDropColumnSQL(tableName, columnName string) ([]string, error) {
 	// SQLite 3.35.0+ supports DROP COLUMN
 	return []string{
-		fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", tableName, columnName),
+		fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", m.QuoteIdentifier(tableName), m.QuoteIdentifier(columnName)),
 	}, nil
 }
 
@@ -429,13 +430,34 @@ func (m *SQLiteMigrator) GenerateCreateIndexSQL(tableName, indexName string, col
 	if unique {
 		uniqueStr = "UNIQUE "
 	}
+	quotedColumns := make([]string, len(columns))
+	for i, col := range columns {
+		quotedColumns[i] = m.QuoteIdentifier(col)
+	}
 	return fmt.Sprintf("CREATE %sINDEX %s ON %s (%s)",
-		uniqueStr, indexName, tableName, strings.Join(columns, ", "))
+		uniqueStr, m.QuoteIdentifier(indexName), m.QuoteIdentifier(tableName), strings.Join(quotedColumns, ", "))
 }
 
 // GenerateDropIndexSQL generates DROP INDEX SQL
 func (m *SQLiteMigrator) GenerateDropIndexSQL(indexName string) string {
-	return fmt.Sprintf("DROP INDEX IF EXISTS %s", indexName)
+	return fmt.Sprintf("DROP INDEX IF EXISTS %s", m.QuoteIdentifier(indexName))
+}
+
+// GenerateUpdatedAtTriggerSQL generates a trigger setting the columns to the current time
+// after updates that leave them unchanged
+func (m *SQLiteMigrator) GenerateUpdatedAtTriggerSQL(tableName string, columns []string) []string {
+	unchanged := make([]string, len(columns))
+	assignments := make([]string, len(columns))
+	for i, column := range columns {
+		quoted := m.QuoteIdentifier(column)
+		unchanged[i] = fmt.Sprintf("NEW.%s IS OLD.%s", quoted, quoted)
+		assignments[i] = fmt.Sprintf("%s = CURRENT_TIMESTAMP", quoted)
+	}
+	table := m.QuoteIdentifier(tableName)
+	return []string{fmt.Sprintf(
+		"CREATE TRIGGER IF NOT EXISTS %s AFTER UPDATE ON %s FOR EACH ROW WHEN %s BEGIN UPDATE %s SET %s WHERE rowid = NEW.rowid; END",
+		m.QuoteIdentifier(tableName+"_updated_at"), table, strings.Join(unchanged, " AND "), table, strings.Join(assignments, ", "),
+	)}
 }
 
 // ApplyMigration executes a migration SQL
@@ -453,9 +475,14 @@ func (m *SQLiteMigrator) GetDatabaseType() string {
 	return "sqlite"
 }
 
+// QuoteIdentifier quotes an identifier for SQLite
+func (m *SQLiteMigrator) QuoteIdentifier(name string) string {
+	return utils.QuoteIdentifier(name, '`')
+}
+
 // MapFieldType maps schema field types to SQLite types
 func (m *SQLiteMigrator) MapFieldType(field schema.Field) string {
-	return m.sqliteDB.mapFieldTypeToSQL(field.Type)
+	return m.sqliteDB.columnType(field)
 }
 
 // FormatDefaultValue formats a default value for SQLite
@@ -472,6 +499,8 @@ func (m *SQLiteMigrator) FormatDefaultValue(value any) string {
 			return "1"
 		}
 		return "0"
+	case []any:
+		return m.sqliteDB.formatDefaultValue(v)
 	default:
 		return fmt.Sprintf("%v", value)
 	}
@@ -479,7 +508,7 @@ func (m *SQLiteMigrator) FormatDefaultValue(value any) string {
 
 // GenerateColumnDefinitionFromColumnInfo generates column definition from ColumnInfo
 func (m *SQLiteMigrator) GenerateColumnDefinitionFromColumnInfo(col types.ColumnInfo) string {
-	parts := []string{col.Name, col.Type}
+	parts := []string{m.QuoteIdentifier(col.Name), columnTypeSQL(col.Type)}
 
 	if col.PrimaryKey {
 		parts = append(parts, "PRIMARY KEY")
@@ -506,8 +535,8 @@ func (m *SQLiteMigrator) GenerateColumnDefinitionFromColumnInfo(col types.Column
 // ConvertFieldToColumnInfo converts a schema field to column info
 func (m *SQLiteMigrator) ConvertFieldToColumnInfo(field schema.Field) *types.ColumnInfo {
 	// Normalize the default value for SQLite
-	var normalizedDefault any = field.Default
-	if field.Default != nil {
+	var normalizedDefault any = field.ColumnDefault()
+	if normalizedDefault != nil {
 		if defaultStr, ok := field.Default.(string); ok {
 			normalizedDefault = m.normalizeDefaultValue(defaultStr)
 		} else if defaultBool, ok := field.Default.(bool); ok {
@@ -650,7 +679,7 @@ func (m *SQLiteMigrator) MapDatabaseTypeToFieldType(dbType string) schema.FieldT
 	// Floating point types
 	case "real", "double", "double precision", "float":
 		return schema.FieldTypeFloat
-	case "decimal", "numeric":
+	case "decimal", "numeric", "decimal_text":
 		return schema.FieldTypeDecimal
 
 	// String types
@@ -669,7 +698,7 @@ func (m *SQLiteMigrator) MapDatabaseTypeToFieldType(dbType string) schema.FieldT
 
 	// Binary types
 	case "blob":
-		return schema.FieldTypeBinary
+		return schema.FieldTypeBytes
 
 	// Default to string for unknown types
 	default:
@@ -27,7 +27,8 @@ func NewSQLiteRawQuery(driver *SQLiteDB, sql string, args ...any) types.RawQuery
 
 // Exec executes the raw query and returns the result
 func (q *SQLiteRawQuery) Exec(ctx context.Context) (types.Result, error) {
-	result, err := q.driver.Exec(q.sql, q.args...)
+	defer types.ForgetDeduplicated(ctx)
+	result, err := q.driver.ExecContext(ctx, q.sql, q.args...)
 	if err != nil {
 		return types.Result{}, err
 	}
@@ -43,7 +44,7 @@ func (q *SQLiteRawQuery) Exec(ctx context.Context) (types.Result, error) {
 
 // Find executes the raw query and returns multiple results
 func (q *SQLiteRawQuery) Find(ctx context.Context, dest any) error {
-	rows, err := q.driver.Query(q.sql, q.args...)
+	rows, err := q.driver.QueryContext(ctx, q.sql, q.args...)
 	if err != nil {
 		return fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -58,7 +59,7 @@ func (q *SQLiteRawQuery) FindOne(ctx context.Context, dest any) error {
 	upperSQL := strings.ToUpper(strings.TrimSpace(q.sql))
 	if strings.HasPrefix(upperSQL, "INSERT") && strings.Contains(upperSQL, "RETURNING") {
 		// First try to execute the query
-		rows, err := q.driver.Query(q.sql, q.args...)
+		rows, err := q.driver.QueryContext(ctx, q.sql, q.args...)
 		if err != nil {
 			return err
 		}
@@ -74,7 +75,7 @@ func (q *SQLiteRawQuery) FindOne(ctx context.Context, dest any) error {
 				nonReturningSQL = strings.TrimSpace(q.sql[:idx])
 			}
 
-			_, execErr := q.driver.Exec(nonReturningSQL, q.args...)
+			_, execErr := q.driver.ExecContext(ctx, nonReturningSQL, q.args...)
 			if execErr != nil {
 				return execErr // Return the actual constraint error
 			}
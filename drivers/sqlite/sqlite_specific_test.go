@@ -2,10 +2,18 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/rediwo/redi-orm/database"
+	"github.com/rediwo/redi-orm/migration"
+	"github.com/rediwo/redi-orm/orm"
+	"github.com/rediwo/redi-orm/schema"
 	"github.com/rediwo/redi-orm/test"
+	"github.com/rediwo/redi-orm/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -113,3 +121,689 @@ func TestSQLiteCaseSensitivity(t *testing.T) {
 	assert.Len(t, results, 1)
 	assert.Equal(t, "Alice", results[0]["name"])
 }
+
+func TestSQLiteWatchPolling(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewSQLiteDB(t.TempDir() + "/watch.db")
+	require.NoError(t, err)
+	require.NoError(t, db.Connect(ctx))
+	defer db.Close()
+
+	db.ChangePollInterval = 20 * time.Millisecond
+
+	err = db.LoadSchema(ctx, `
+model Note {
+  id    Int    @id @default(autoincrement())
+  title String
+}`)
+	require.NoError(t, err)
+	require.NoError(t, db.SyncSchemas(ctx))
+
+	stream, err := database.Watch(ctx, db, "Note")
+	require.NoError(t, err)
+	defer stream.Close()
+
+	next := func() types.ChangeEvent {
+		select {
+		case event := <-stream.Events():
+			return event
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for change event")
+		}
+		return types.ChangeEvent{}
+	}
+
+	_, err = db.Model("Note").Insert(map[string]any{"title": "first"}).Exec(ctx)
+	require.NoError(t, err)
+	event := next()
+	assert.Equal(t, "Note", event.Model)
+	assert.Equal(t, types.ChangeCreate, event.Operation)
+	assert.Equal(t, "first", event.Data["title"])
+
+	Note := db.Model("Note")
+	_, err = Note.Update(map[string]any{"title": "second"}).WhereCondition(Note.Where("title").Equals("first")).Exec(ctx)
+	require.NoError(t, err)
+	event = next()
+	assert.Equal(t, types.ChangeUpdate, event.Operation)
+	assert.Equal(t, "second", event.Data["title"])
+
+	_, err = Note.Delete().WhereCondition(Note.Where("title").Equals("second")).Exec(ctx)
+	require.NoError(t, err)
+	event = next()
+	assert.Equal(t, types.ChangeDelete, event.Operation)
+	assert.Equal(t, "second", event.Data["title"])
+
+	require.NoError(t, stream.Close())
+	_, open := <-stream.Events()
+	assert.False(t, open)
+	assert.NoError(t, stream.Err())
+}
+
+func TestSQLiteWatchUpdatedAtCursor(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewSQLiteDB(t.TempDir() + "/watch.db")
+	require.NoError(t, err)
+	require.NoError(t, db.Connect(ctx))
+	defer db.Close()
+
+	db.ChangePollInterval = 20 * time.Millisecond
+
+	err = db.LoadSchema(ctx, `
+model Note {
+  id        Int      @id @default(autoincrement())
+  title     String
+  updatedAt DateTime @updatedAt
+}`)
+	require.NoError(t, err)
+	require.NoError(t, db.SyncSchemas(ctx))
+
+	_, err = db.Model("Note").Insert(map[string]any{"title": "existing"}).Exec(ctx)
+	require.NoError(t, err)
+
+	stream, err := database.Watch(ctx, db, "Note")
+	require.NoError(t, err)
+	defer stream.Close()
+
+	next := func() types.ChangeEvent {
+		select {
+		case event := <-stream.Events():
+			return event
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for change event")
+		}
+		return types.ChangeEvent{}
+	}
+
+	_, err = db.Model("Note").Insert(map[string]any{"title": "first"}).Exec(ctx)
+	require.NoError(t, err)
+	event := next()
+	assert.Equal(t, types.ChangeCreate, event.Operation)
+	assert.Equal(t, "first", event.Data["title"])
+
+	Note := db.Model("Note")
+	_, err = Note.Update(map[string]any{"title": "second"}).WhereCondition(Note.Where("title").Equals("first")).Exec(ctx)
+	require.NoError(t, err)
+	event = next()
+	assert.Equal(t, types.ChangeUpdate, event.Operation)
+	assert.Equal(t, "second", event.Data["title"])
+
+	// A create and a delete between two polls are both reported
+	_, err = Note.Delete().WhereCondition(Note.Where("title").Equals("existing")).Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.Model("Note").Insert(map[string]any{"title": "third"}).Exec(ctx)
+	require.NoError(t, err)
+	seen := map[types.ChangeOperation]any{}
+	for range 2 {
+		event = next()
+		seen[event.Operation] = event.Data["title"]
+	}
+	assert.Equal(t, map[types.ChangeOperation]any{types.ChangeCreate: "third", types.ChangeDelete: "existing"}, seen)
+
+	// Rows read again within the lookback are not reported twice
+	select {
+	case event := <-stream.Events():
+		t.Fatalf("unexpected event %+v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func BenchmarkSQLiteRelationLoadStrategy(b *testing.B) {
+	db, err := database.NewFromURI("sqlite://" + filepath.Join(b.TempDir(), "bench.db"))
+	require.NoError(b, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	require.NoError(b, db.Connect(ctx))
+	require.NoError(b, db.LoadSchema(ctx, `
+		model User {
+			id    Int    @id @default(autoincrement())
+			name  String
+			posts Post[]
+		}
+
+		model Post {
+			id       Int    @id @default(autoincrement())
+			title    String
+			authorId Int
+			author   User   @relation(fields: [authorId], references: [id])
+		}
+	`))
+	require.NoError(b, db.SyncSchemas(ctx))
+
+	// 50 users with 20 posts each
+	for i := 0; i < 50; i++ {
+		result, err := db.Model("User").Insert(map[string]any{"name": fmt.Sprintf("User %d", i)}).Exec(ctx)
+		require.NoError(b, err)
+		for j := 0; j < 20; j++ {
+			_, err := db.Model("Post").Insert(map[string]any{
+				"title":    fmt.Sprintf("Post %d-%d", i, j),
+				"authorId": result.LastInsertID,
+			}).Exec(ctx)
+			require.NoError(b, err)
+		}
+	}
+
+	for _, strategy := range []types.RelationLoadStrategy{types.RelationLoadJoin, types.RelationLoadQuery} {
+		b.Run(string(strategy), func(b *testing.B) {
+			query := db.Model("User").Select().Include("posts").RelationLoadStrategy(strategy)
+			for i := 0; i < b.N; i++ {
+				var users []map[string]any
+				if err := query.FindMany(ctx, &users); err != nil {
+					b.Fatal(err)
+				}
+				if len(users) != 50 {
+					b.Fatalf("expected 50 users, got %d", len(users))
+				}
+				if posts, _ := users[0]["posts"].([]any); len(posts) != 20 {
+					b.Fatalf("expected 20 posts, got %d", len(posts))
+				}
+			}
+		})
+	}
+}
+
+func TestSQLiteUpdatedAtTriggers(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewSQLiteDB(t.TempDir() + "/triggers.db")
+	require.NoError(t, err)
+	require.NoError(t, db.Connect(ctx))
+	defer db.Close()
+
+	noteSchema := schema.New("Note").
+		AddField(schema.Field{Name: "id", Type: schema.FieldTypeInt, PrimaryKey: true, AutoIncrement: true}).
+		AddField(schema.Field{Name: "title", Type: schema.FieldTypeString}).
+		AddField(schema.Field{Name: "updatedAt", Type: schema.FieldTypeDateTime, UpdatedAt: true})
+	require.NoError(t, db.RegisterSchema("Note", noteSchema))
+
+	manager, err := migration.NewManager(db, types.MigrationOptions{UpdatedAtTriggers: true})
+	require.NoError(t, err)
+	require.NoError(t, manager.Migrate(map[string]*schema.Schema{"Note": noteSchema}))
+
+	// Raw SQL bypasses the ORM, so only the trigger can maintain updated_at
+	_, err = db.Exec("INSERT INTO notes (title, updated_at) VALUES ('draft', '2000-01-01 00:00:00')")
+	require.NoError(t, err)
+	_, err = db.Exec("UPDATE notes SET title = 'published'")
+	require.NoError(t, err)
+
+	var updatedAt time.Time
+	require.NoError(t, db.DB.QueryRow("SELECT updated_at FROM notes").Scan(&updatedAt))
+	assert.True(t, updatedAt.Year() > 2000, "trigger should refresh updated_at, got %v", updatedAt)
+
+	// An explicit value is kept
+	_, err = db.Exec("UPDATE notes SET updated_at = '2001-01-01 00:00:00'")
+	require.NoError(t, err)
+	require.NoError(t, db.DB.QueryRow("SELECT updated_at FROM notes").Scan(&updatedAt))
+	assert.Equal(t, 2001, updatedAt.Year())
+}
+
+func TestSQLiteLockMigrations(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "app.db")
+	first, err := NewSQLiteDB(path)
+	require.NoError(t, err)
+	second, err := NewSQLiteDB(path)
+	require.NoError(t, err)
+
+	unlock, err := first.LockMigrations(ctx, time.Second)
+	require.NoError(t, err)
+	assert.FileExists(t, path+".migration.lock")
+
+	// Other connections to the file wait for the lock
+	_, err = second.LockMigrations(ctx, 20*time.Millisecond)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out")
+
+	require.NoError(t, unlock())
+	unlock, err = second.LockMigrations(ctx, time.Second)
+	require.NoError(t, err)
+	require.NoError(t, unlock())
+
+	// In-memory databases need no lock
+	memory, err := NewSQLiteDB(":memory:")
+	require.NoError(t, err)
+	unlock, err = memory.LockMigrations(ctx, 0)
+	require.NoError(t, err)
+	require.NoError(t, unlock())
+}
+
+func TestSQLiteReferentialActions(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewSQLiteDB(t.TempDir() + "/actions.db")
+	require.NoError(t, err)
+	require.NoError(t, db.Connect(ctx))
+	defer db.Close()
+
+	err = db.LoadSchema(ctx, `
+model User {
+  id       Int       @id @default(autoincrement())
+  posts    Post[]
+  profiles Profile[]
+}
+
+model Post {
+  id       Int  @id @default(autoincrement())
+  authorId Int
+  author   User @relation(fields: [authorId], references: [id], onDelete: Cascade)
+}
+
+model Profile {
+  id     Int   @id @default(autoincrement())
+  userId Int?
+  user   User? @relation(fields: [userId], references: [id], onDelete: SetNull)
+}`)
+	require.NoError(t, err)
+	require.NoError(t, db.SyncSchemas(ctx))
+
+	_, err = db.Exec("INSERT INTO users (id) VALUES (1)")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO posts (author_id) VALUES (1), (1)")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO profiles (user_id) VALUES (1)")
+	require.NoError(t, err)
+
+	_, err = db.Model("User").Delete().WhereCondition(db.Model("User").Where("id").Equals(1)).Exec(ctx)
+	require.NoError(t, err)
+
+	var posts int
+	require.NoError(t, db.DB.QueryRow("SELECT COUNT(*) FROM posts").Scan(&posts))
+	assert.Equal(t, 0, posts, "posts should be deleted with their author")
+	var userID sql.NullInt64
+	require.NoError(t, db.DB.QueryRow("SELECT user_id FROM profiles").Scan(&userID))
+	assert.False(t, userID.Valid, "the profile should no longer reference the user")
+}
+
+func TestSQLiteRelationModePrisma(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewSQLiteDB(t.TempDir() + "/relation_mode.db")
+	require.NoError(t, err)
+	require.NoError(t, db.Connect(ctx))
+	defer db.Close()
+
+	err = db.LoadSchema(ctx, `
+datasource db {
+  provider     = "sqlite"
+  url          = "file:./dev.db"
+  relationMode = "prisma"
+}
+
+model User {
+  id       Int       @id @default(autoincrement())
+  posts    Post[]
+  profiles Profile[]
+}
+
+model Post {
+  id       Int       @id @default(autoincrement())
+  authorId Int
+  author   User      @relation(fields: [authorId], references: [id], onDelete: Cascade)
+  comments Comment[]
+}
+
+model Comment {
+  id     Int  @id @default(autoincrement())
+  postId Int
+  post   Post @relation(fields: [postId], references: [id])
+}
+
+model Profile {
+  id     Int   @id @default(autoincrement())
+  userId Int?
+  user   User? @relation(fields: [userId], references: [id], onDelete: SetNull)
+}`)
+	require.NoError(t, err)
+	require.NoError(t, db.SyncSchemas(ctx))
+
+	// No foreign keys are created
+	var foreignKeys int
+	require.NoError(t, db.DB.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE sql LIKE '%FOREIGN KEY%'").Scan(&foreignKeys))
+	assert.Equal(t, 0, foreignKeys)
+
+	_, err = db.Exec("INSERT INTO users (id) VALUES (1), (2)")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO posts (id, author_id) VALUES (1, 1), (2, 2)")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO comments (post_id) VALUES (2)")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO profiles (user_id) VALUES (1)")
+	require.NoError(t, err)
+
+	count := func(table string) int {
+		var n int
+		require.NoError(t, db.DB.QueryRow("SELECT COUNT(*) FROM "+table).Scan(&n))
+		return n
+	}
+	deleteUser := func(id int) error {
+		_, err := db.Model("User").Delete().WhereCondition(db.Model("User").Where("id").Equals(id)).Exec(ctx)
+		return err
+	}
+
+	// The ORM cascades to posts and clears the profile's reference
+	require.NoError(t, deleteUser(1))
+	assert.Equal(t, 1, count("users"))
+	assert.Equal(t, 1, count("posts"))
+	var userID sql.NullInt64
+	require.NoError(t, db.DB.QueryRow("SELECT user_id FROM profiles").Scan(&userID))
+	assert.False(t, userID.Valid)
+
+	// Comments without onDelete refuse deleting their post, so the whole delete is rolled back
+	err = deleteUser(2)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "referenced by Comment.post")
+	assert.Equal(t, 1, count("users"))
+	assert.Equal(t, 1, count("posts"))
+
+	// In a transaction, the actions join it
+	err = db.Transaction(ctx, func(tx types.Transaction) error {
+		if _, err := tx.Model("Comment").Delete().WhereCondition(tx.Model("Comment").Where("postId").Equals(2)).Exec(ctx); err != nil {
+			return err
+		}
+		_, err := tx.Model("User").Delete().WhereCondition(tx.Model("User").Where("id").Equals(2)).Exec(ctx)
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, count("users"))
+	assert.Equal(t, 0, count("posts"))
+}
+
+func TestSQLitePolymorphicRelations(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewSQLiteDB(t.TempDir() + "/polymorphic.db")
+	require.NoError(t, err)
+	require.NoError(t, db.Connect(ctx))
+	defer db.Close()
+
+	err = db.LoadSchema(ctx, `
+model User {
+  id    Int    @id @default(autoincrement())
+  name  String
+  posts Post[]
+}
+
+model Post {
+  id       Int       @id @default(autoincrement())
+  title    String
+  authorId Int
+  author   User      @relation(fields: [authorId], references: [id])
+  comments Comment[]
+}
+
+model Photo {
+  id       Int       @id @default(autoincrement())
+  url      String
+  comments Comment[]
+}
+
+model Comment {
+  id              Int          @id @default(autoincrement())
+  body            String
+  commentableId   Int
+  commentableType String
+  commentable     Post | Photo @relation(fields: [commentableId], references: [id], type: commentableType)
+}`)
+	require.NoError(t, err)
+	require.NoError(t, db.SyncSchemas(ctx))
+
+	_, err = db.Exec("INSERT INTO users (id, name) VALUES (1, 'Alice')")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO posts (id, title, author_id) VALUES (1, 'Hello', 1)")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO photos (id, url) VALUES (1, 'cat.png')")
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO comments (body, commentable_id, commentable_type) VALUES
+		('on post', 1, 'Post'), ('on photo', 1, 'Photo'), ('also on photo', 1, 'Photo')`)
+	require.NoError(t, err)
+
+	// Each comment holds the record of the model its type names
+	var comments []map[string]any
+	err = db.Model("Comment").Select().Include("commentable.author").OrderBy("id", types.ASC).FindMany(ctx, &comments)
+	require.NoError(t, err)
+	require.Len(t, comments, 3)
+	post := comments[0]["commentable"].(map[string]any)
+	assert.Equal(t, "Hello", post["title"])
+	assert.Equal(t, "Alice", post["author"].(map[string]any)["name"])
+	photo := comments[1]["commentable"].(map[string]any)
+	assert.Equal(t, "cat.png", photo["url"])
+	assert.NotContains(t, photo, "author")
+
+	// Selected comments still load the relation, without the keys it needed
+	var bodies []map[string]any
+	err = db.Model("Comment").Select("body").Include("commentable").OrderBy("id", types.ASC).FindMany(ctx, &bodies)
+	require.NoError(t, err)
+	require.Len(t, bodies, 3)
+	assert.NotContains(t, bodies[0], "commentableType")
+	assert.Equal(t, "Hello", bodies[0]["commentable"].(map[string]any)["title"])
+
+	// The inverse relations only hold the comments naming their model
+	var photos []map[string]any
+	require.NoError(t, db.Model("Photo").Select().Include("comments").FindMany(ctx, &photos))
+	require.Len(t, photos, 1)
+	assert.Len(t, photos[0]["comments"], 2)
+
+	var first map[string]any
+	require.NoError(t, db.Model("Post").Select().Include("comments").FindFirst(ctx, &first))
+	require.Len(t, first["comments"], 1)
+	assert.Equal(t, "on post", first["comments"].([]any)[0].(map[string]any)["body"])
+}
+
+func TestSQLiteSelfAndNamedRelations(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewSQLiteDB(t.TempDir() + "/named_relations.db")
+	require.NoError(t, err)
+	require.NoError(t, db.Connect(ctx))
+	defer db.Close()
+
+	err = db.LoadSchema(ctx, `
+model User {
+  id          Int    @id @default(autoincrement())
+  name        String
+  managerId   Int?
+  manager     User?  @relation("Management", fields: [managerId], references: [id])
+  reports     User[] @relation("Management")
+  writtenPosts Post[] @relation("WrittenPosts")
+  editedPosts  Post[] @relation(name: "EditedPosts")
+}
+
+model Post {
+  id       Int    @id @default(autoincrement())
+  title    String
+  authorId Int
+  editorId Int?
+  author   User   @relation("WrittenPosts", fields: [authorId], references: [id])
+  editor   User?  @relation(name: "EditedPosts", fields: [editorId], references: [id])
+}`)
+	require.NoError(t, err)
+	require.NoError(t, db.SyncSchemas(ctx))
+
+	// Each relation gets its own foreign key
+	var foreignKeys []string
+	rows, err := db.DB.Query("SELECT \"from\" || '->' || \"table\" FROM pragma_foreign_key_list('users') UNION ALL SELECT \"from\" || '->' || \"table\" FROM pragma_foreign_key_list('posts')")
+	require.NoError(t, err)
+	for rows.Next() {
+		var fk string
+		require.NoError(t, rows.Scan(&fk))
+		foreignKeys = append(foreignKeys, fk)
+	}
+	require.NoError(t, rows.Close())
+	assert.ElementsMatch(t, []string{"manager_id->users", "author_id->users", "editor_id->users"}, foreignKeys)
+
+	_, err = db.Exec("INSERT INTO users (id, name, manager_id) VALUES (1, 'Boss', NULL), (2, 'Alice', 1), (3, 'Bob', 1)")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO posts (id, title, author_id, editor_id) VALUES (1, 'Draft', 2, 1), (2, 'Notes', 3, NULL)")
+	require.NoError(t, err)
+
+	for _, strategy := range []types.RelationLoadStrategy{types.RelationLoadJoin, types.RelationLoadQuery} {
+		t.Run(string(strategy), func(t *testing.T) {
+			var users []map[string]any
+			err := db.Model("User").Select().Include("manager", "reports", "writtenPosts.editor", "editedPosts").
+				RelationLoadStrategy(strategy).OrderBy("id", types.ASC).FindMany(ctx, &users)
+			require.NoError(t, err)
+			require.Len(t, users, 3)
+
+			boss, alice := users[0], users[1]
+			assert.Nil(t, boss["manager"])
+			assert.Len(t, boss["reports"], 2)
+			assert.Empty(t, boss["writtenPosts"])
+			require.Len(t, boss["editedPosts"], 1)
+			assert.Equal(t, "Draft", boss["editedPosts"].([]any)[0].(map[string]any)["title"])
+
+			assert.Equal(t, "Boss", alice["manager"].(map[string]any)["name"])
+			assert.Empty(t, alice["reports"])
+			require.Len(t, alice["writtenPosts"], 1)
+			written := alice["writtenPosts"].([]any)[0].(map[string]any)
+			assert.Equal(t, "Draft", written["title"])
+			assert.Equal(t, "Boss", written["editor"].(map[string]any)["name"])
+			assert.Empty(t, alice["editedPosts"])
+		})
+	}
+}
+
+func TestSQLiteDeepIncludes(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewSQLiteDB(t.TempDir() + "/deep_includes.db")
+	require.NoError(t, err)
+	require.NoError(t, db.Connect(ctx))
+	defer db.Close()
+
+	err = db.LoadSchema(ctx, `
+model User {
+  id       Int       @id @default(autoincrement())
+  name     String
+  posts    Post[]
+  comments Comment[]
+}
+
+model Post {
+  id       Int       @id @default(autoincrement())
+  title    String
+  authorId Int
+  author   User      @relation(fields: [authorId], references: [id])
+  comments Comment[]
+}
+
+model Comment {
+  id       Int    @id @default(autoincrement())
+  body     String
+  postId   Int
+  authorId Int
+  post     Post   @relation(fields: [postId], references: [id])
+  author   User   @relation(fields: [authorId], references: [id])
+}`)
+	require.NoError(t, err)
+	require.NoError(t, db.SyncSchemas(ctx))
+
+	_, err = db.Exec("INSERT INTO users (id, name) VALUES (1, 'Alice'), (2, 'Bob')")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO posts (id, title, author_id) VALUES (1, 'Hello', 1)")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO comments (id, body, post_id, author_id) VALUES (1, 'Nice', 1, 2)")
+	require.NoError(t, err)
+
+	include := "posts.comments.author.posts.comments"
+	for _, strategy := range []types.RelationLoadStrategy{types.RelationLoadJoin, types.RelationLoadQuery} {
+		t.Run(string(strategy), func(t *testing.T) {
+			var user map[string]any
+			err := db.Model("User").Select().WhereCondition(db.Model("User").Where("id").Equals(1)).Include(include).
+				RelationLoadStrategy(strategy).FindFirst(ctx, &user)
+			require.NoError(t, err)
+
+			post := user["posts"].([]any)[0].(map[string]any)
+			comment := post["comments"].([]any)[0].(map[string]any)
+			author := comment["author"].(map[string]any)
+			assert.Equal(t, "Bob", author["name"])
+			assert.Empty(t, author["posts"])
+		})
+	}
+
+	// Includes deeper than the limit fail
+	require.NoError(t, database.SetMaxIncludeDepth(db, 4))
+	var users []map[string]any
+	err = db.Model("User").Select().Include(include).FindMany(ctx, &users)
+	assert.ErrorContains(t, err, "5 relations deep, more than the limit of 4")
+	assert.Error(t, database.SetMaxIncludeDepth(db, 0))
+}
+
+func TestSQLiteDeduplicationAfterRawWrites(t *testing.T) {
+	ctx := types.WithDeduplication(context.Background())
+	db, err := NewSQLiteDB(t.TempDir() + "/dedup.db")
+	require.NoError(t, err)
+	require.NoError(t, db.Connect(ctx))
+	defer db.Close()
+
+	err = db.LoadSchema(ctx, `
+model User {
+  id   Int    @id @default(autoincrement())
+  name String
+}`)
+	require.NoError(t, err)
+	require.NoError(t, db.SyncSchemas(ctx))
+	_, err = db.Exec("INSERT INTO users (id, name) VALUES (1, 'Alice')")
+	require.NoError(t, err)
+
+	users := orm.NewClient(db).Model("User")
+	name := func() any {
+		result, err := users.QueryContext(ctx, `{"findUnique": {"where": {"id": 1}}}`)
+		require.NoError(t, err)
+		return result.(map[string]any)["name"]
+	}
+	assert.Equal(t, "Alice", name())
+
+	// A raw write drops the deduplicated lookups
+	_, err = db.Raw("UPDATE users SET name = ? WHERE id = ?", "Bob", 1).Exec(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "Bob", name())
+
+	// So does the commit of a transaction, whose writes ran in another context
+	err = db.Transaction(ctx, func(tx types.Transaction) error {
+		_, err := tx.Raw("UPDATE users SET name = ? WHERE id = ?", "Carol", 1).Exec(context.Background())
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Carol", name())
+}
+
+func TestSQLiteDecimalPrecision(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewSQLiteDB(t.TempDir() + "/decimal.db")
+	require.NoError(t, err)
+	require.NoError(t, db.Connect(ctx))
+	defer db.Close()
+
+	err = db.LoadSchema(ctx, `
+model Wallet {
+  id      Int     @id @default(autoincrement())
+  owner   String
+  balance Decimal
+}`)
+	require.NoError(t, err)
+	require.NoError(t, db.SyncSchemas(ctx))
+
+	// Decimals keep digits beyond the 15 of a REAL value
+	client := orm.NewClient(db)
+	for owner, balance := range map[string]string{"alice": "12345678901234567.89", "bob": "9.5", "carol": "100"} {
+		_, err := client.Model("Wallet").Create(fmt.Sprintf(`{"data": {"owner": %q, "balance": %q}}`, owner, balance))
+		require.NoError(t, err)
+	}
+	found, err := client.Model("Wallet").FindFirst(`{"where": {"owner": "alice"}}`)
+	require.NoError(t, err)
+	assert.Equal(t, "12345678901234567.89", found["balance"])
+
+	// They compare and sort as numbers rather than as text
+	wallets, err := client.Model("Wallet").FindMany(`{"where": {"balance": {"gt": 10}}, "orderBy": {"balance": "asc"}}`)
+	require.NoError(t, err)
+	require.Len(t, wallets, 2)
+	assert.Equal(t, "carol", wallets[0]["owner"])
+	assert.Equal(t, "alice", wallets[1]["owner"])
+
+	found, err = client.Model("Wallet").FindFirst(`{"where": {"balance": "9.50"}}`)
+	require.NoError(t, err)
+	assert.Equal(t, "bob", found["owner"])
+
+	// The decimal columns are left alone by later syncs
+	tableInfo, err := db.GetMigrator().GetTableInfo("wallets")
+	require.NoError(t, err)
+	walletSchema, err := db.GetModelSchema("Wallet")
+	require.NoError(t, err)
+	plan, err := db.GetMigrator().CompareSchema(tableInfo, walletSchema)
+	require.NoError(t, err)
+	assert.Empty(t, plan.ModifyColumns)
+}
@@ -40,6 +40,11 @@ func (t *SQLiteTransaction) Model(modelName string) types.ModelQuery {
 
 // Raw creates a new raw query within the transaction
 func (t *SQLiteTransaction) Raw(sql string, args ...any) types.RawQuery {
+	sql, args, err := utils.ExpandSQLArgs(sql, args)
+	if err != nil {
+		return base.NewErrorRawQuery(err)
+	}
+	args = utils.NormalizeTimeArgs(args)
 	return &SQLiteTransactionRawQuery{
 		tx:       t.tx,
 		sql:      sql,
@@ -50,11 +55,13 @@ func (t *SQLiteTransaction) Raw(sql string, args ...any) types.RawQuery {
 
 // Commit commits the transaction
 func (t *SQLiteTransaction) Commit(ctx context.Context) error {
+	defer types.ForgetDeduplicated(ctx)
 	return t.tx.Commit()
 }
 
 // Rollback rolls back the transaction
 func (t *SQLiteTransaction) Rollback(ctx context.Context) error {
+	defer types.ForgetDeduplicated(ctx)
 	return t.tx.Rollback()
 }
 
@@ -147,6 +154,18 @@ func (td *SQLiteTransactionDB) GetModelSchema(modelName string) (*schema.Schema,
 	return td.database.GetModelSchema(modelName)
 }
 
+func (td *SQLiteTransactionDB) GetModelField(modelName, fieldName string) (*schema.Field, error) {
+	return td.database.GetModelField(modelName, fieldName)
+}
+
+func (td *SQLiteTransactionDB) GetModelRelations(modelName string) ([]types.RelationInfo, error) {
+	return td.database.GetModelRelations(modelName)
+}
+
+func (td *SQLiteTransactionDB) ResolveRelation(modelName, relationName string) (types.RelationInfo, error) {
+	return td.database.ResolveRelation(modelName, relationName)
+}
+
 func (td *SQLiteTransactionDB) GetDriverType() string {
 	return td.database.GetDriverType()
 }
@@ -173,7 +192,7 @@ func (td *SQLiteTransactionDB) Exec(query string, args ...any) (sql.Result, erro
 	duration := time.Since(start)
 
 	if l := td.database.GetLogger(); l != nil {
-		dbLogger := base.NewDBLogger(l)
+		dbLogger := base.NewDBLogger(td.database.Log())
 		dbLogger.LogSQL(query, args, duration)
 	}
 
@@ -186,7 +205,7 @@ func (td *SQLiteTransactionDB) Query(query string, args ...any) (*sql.Rows, erro
 	duration := time.Since(start)
 
 	if l := td.database.GetLogger(); l != nil {
-		dbLogger := base.NewDBLogger(l)
+		dbLogger := base.NewDBLogger(td.database.Log())
 		dbLogger.LogSQL(query, args, duration)
 	}
 
@@ -199,17 +218,59 @@ func (td *SQLiteTransactionDB) QueryRow(query string, args ...any) *sql.Row {
 	duration := time.Since(start)
 
 	if l := td.database.GetLogger(); l != nil {
-		dbLogger := base.NewDBLogger(l)
+		dbLogger := base.NewDBLogger(td.database.Log())
 		dbLogger.LogSQL(query, args, duration)
 	}
 
 	return row
 }
 
+func (td *SQLiteTransactionDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := td.transaction.tx.QueryContext(ctx, query, args...)
+	duration := time.Since(start)
+
+	if l := td.database.GetLogger(); l != nil {
+		dbLogger := base.NewDBLogger(td.database.Log())
+		dbLogger.LogSQLContext(ctx, query, args, duration)
+	}
+
+	return rows, err
+}
+
+func (td *SQLiteTransactionDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	start := time.Now()
+	row := td.transaction.tx.QueryRowContext(ctx, query, args...)
+	duration := time.Since(start)
+
+	if l := td.database.GetLogger(); l != nil {
+		dbLogger := base.NewDBLogger(td.database.Log())
+		dbLogger.LogSQLContext(ctx, query, args, duration)
+	}
+
+	return row
+}
+
 func (td *SQLiteTransactionDB) GetMigrator() types.DatabaseMigrator {
 	return td.database.GetMigrator()
 }
 
+func (td *SQLiteTransactionDB) SetStatementTimeout(timeout time.Duration) {
+	td.database.SetStatementTimeout(timeout)
+}
+
+func (td *SQLiteTransactionDB) GetStatementTimeout() time.Duration {
+	return td.database.GetStatementTimeout()
+}
+
+func (td *SQLiteTransactionDB) SetMaxIncludeDepth(depth int) {
+	td.database.SetMaxIncludeDepth(depth)
+}
+
+func (td *SQLiteTransactionDB) GetMaxIncludeDepth() int {
+	return td.database.GetMaxIncludeDepth()
+}
+
 func (td *SQLiteTransactionDB) SetLogger(l logger.Logger) {
 	td.database.SetLogger(l)
 }
@@ -242,13 +303,14 @@ type SQLiteTransactionRawQuery struct {
 }
 
 func (q *SQLiteTransactionRawQuery) Exec(ctx context.Context) (types.Result, error) {
+	defer types.ForgetDeduplicated(ctx)
 	start := time.Now()
 	result, err := q.tx.ExecContext(ctx, q.sql, q.args...)
 	duration := time.Since(start)
 
 	if l := q.database.GetLogger(); l != nil {
-		dbLogger := base.NewDBLogger(l)
-		dbLogger.LogSQL(q.sql, q.args, duration)
+		dbLogger := base.NewDBLogger(q.database.Log())
+		dbLogger.LogSQLContext(ctx, q.sql, q.args, duration)
 	}
 
 	if err != nil {
@@ -270,8 +332,8 @@ func (q *SQLiteTransactionRawQuery) Find(ctx context.Context, dest any) error {
 	duration := time.Since(start)
 
 	if l := q.database.GetLogger(); l != nil {
-		dbLogger := base.NewDBLogger(l)
-		dbLogger.LogSQL(q.sql, q.args, duration)
+		dbLogger := base.NewDBLogger(q.database.Log())
+		dbLogger.LogSQLContext(ctx, q.sql, q.args, duration)
 	}
 
 	if err != nil {
@@ -288,8 +350,8 @@ func (q *SQLiteTransactionRawQuery) FindOne(ctx context.Context, dest any) error
 	duration := time.Since(start)
 
 	if l := q.database.GetLogger(); l != nil {
-		dbLogger := base.NewDBLogger(l)
-		dbLogger.LogSQL(q.sql, q.args, duration)
+		dbLogger := base.NewDBLogger(q.database.Log())
+		dbLogger.LogSQLContext(ctx, q.sql, q.args, duration)
 	}
 
 	return err
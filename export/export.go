@@ -0,0 +1,191 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
+)
+
+// Formats records can be exported in
+const (
+	// CSV writes a header row and a row per record. Bytes are written in base64 and nested
+	// values as JSON, like in NDJSON.
+	CSV = "csv"
+	// NDJSON writes a JSON object per line
+	NDJSON = "ndjson"
+)
+
+// DefaultBatchSize is the number of records read per query by default
+const DefaultBatchSize = 500
+
+// Options configures an export
+type Options struct {
+	Format    string   // CSV or NDJSON
+	Fields    []string // CSV columns, the fields of the model by default
+	BatchSize int      // Records read per query, DefaultBatchSize by default
+
+	// Optional: rewrites each batch of records before it is written, e.g. to mask fields
+	Transform func(records []map[string]any) []map[string]any
+}
+
+// ContentType returns the MIME type of format, or "" for unknown formats
+func ContentType(format string) string {
+	switch format {
+	case CSV:
+		return "text/csv; charset=utf-8"
+	case NDJSON:
+		return "application/x-ndjson"
+	}
+	return ""
+}
+
+// Export writes the records of a query of modelName to w. The records are read with Batches,
+// so memory use does not grow with their number, and w is flushed after each batch when it
+// has a Flush() error method. It returns the number of records written.
+func Export(ctx context.Context, w io.Writer, db types.Database, modelName string, query types.SelectQuery, opts Options) (int, error) {
+	sch, err := db.GetSchema(modelName)
+	if err != nil {
+		return 0, err
+	}
+
+	var write func(record map[string]any) error
+	var flush func() error
+	switch opts.Format {
+	case CSV:
+		columns := opts.Fields
+		if len(columns) == 0 {
+			for _, field := range sch.Fields {
+				columns = append(columns, field.Name)
+			}
+		}
+		writer := csv.NewWriter(w)
+		if err := writer.Write(columns); err != nil {
+			return 0, err
+		}
+		row := make([]string, len(columns))
+		write = func(record map[string]any) error {
+			for i, column := range columns {
+				cell, err := formatCell(record[column])
+				if err != nil {
+					return fmt.Errorf("failed to format %s: %w", column, err)
+				}
+				row[i] = cell
+			}
+			return writer.Write(row)
+		}
+		flush = func() error {
+			writer.Flush()
+			return writer.Error()
+		}
+	case NDJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		write = func(record map[string]any) error {
+			return encoder.Encode(record)
+		}
+		flush = func() error { return nil }
+	default:
+		return 0, fmt.Errorf("unsupported export format %q: must be %s or %s", opts.Format, CSV, NDJSON)
+	}
+
+	flushAll := func() error {
+		if err := flush(); err != nil {
+			return err
+		}
+		if flusher, ok := w.(interface{ Flush() error }); ok {
+			return flusher.Flush()
+		}
+		return nil
+	}
+
+	count := 0
+	err = Batches(ctx, db, modelName, query, opts.BatchSize, func(records []map[string]any) error {
+		if opts.Transform != nil {
+			records = opts.Transform(records)
+		}
+		for _, record := range records {
+			if err := write(record); err != nil {
+				return err
+			}
+			count++
+		}
+		return flushAll()
+	})
+	if err != nil {
+		return count, err
+	}
+	// The CSV header of an empty export
+	return count, flushAll()
+}
+
+// Batches reads the records of a query of modelName in batches of batchSize (DefaultBatchSize
+// when 0) and calls fn with each of them. Batches are ordered by the primary key after the
+// ordering of the query, which must not have an offset or limit.
+func Batches(ctx context.Context, db types.Database, modelName string, query types.SelectQuery, batchSize int, fn func(records []map[string]any) error) error {
+	sch, err := db.GetSchema(modelName)
+	if err != nil {
+		return err
+	}
+
+	// Break ties of the ordering of the query so that batches do not overlap
+	if len(sch.CompositeKey) > 0 {
+		for _, field := range sch.CompositeKey {
+			query = query.OrderBy(field, types.ASC)
+		}
+	} else if pk, err := sch.GetPrimaryKey(); err == nil {
+		query = query.OrderBy(pk.Name, types.ASC)
+	}
+
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	for offset := 0; ; offset += batchSize {
+		var records []map[string]any
+		if err := query.Offset(offset).Limit(batchSize).FindMany(ctx, &records); err != nil {
+			return err
+		}
+		read := len(records)
+		if read > 0 {
+			if err := fn(records); err != nil {
+				return err
+			}
+		}
+		if read < batchSize {
+			return nil
+		}
+	}
+}
+
+// formatCell formats a value as a CSV cell
+func formatCell(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		return utils.EncodeBytes(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
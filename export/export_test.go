@@ -0,0 +1,141 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rediwo/redi-orm/database"
+	_ "github.com/rediwo/redi-orm/drivers/sqlite"
+	"github.com/rediwo/redi-orm/types"
+)
+
+func newTestDatabase(t *testing.T) database.Database {
+	t.Helper()
+	ctx := context.Background()
+	db, err := database.NewFromURI("sqlite://:memory:")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	if err := db.Connect(ctx); err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := db.LoadSchema(ctx, `
+model User {
+  id   Int    @id @default(autoincrement())
+  name String
+  age  Int?
+}
+`); err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	if err := db.SyncSchemas(ctx); err != nil {
+		t.Fatalf("Failed to sync schemas: %v", err)
+	}
+	for _, user := range []map[string]any{
+		{"name": "Alice", "age": 30},
+		{"name": `Bob, "the builder"`},
+		{"name": "Carol", "age": 25},
+		{"name": "Dave", "age": 41},
+		{"name": "Eve", "age": 19},
+	} {
+		if _, err := db.Model("User").Insert(user).Exec(ctx); err != nil {
+			t.Fatalf("Failed to insert user: %v", err)
+		}
+	}
+	return db
+}
+
+func TestExportCSV(t *testing.T) {
+	db := newTestDatabase(t)
+
+	var out bytes.Buffer
+	count, err := Export(context.Background(), &out, db, "User", db.Model("User").Select(), Options{Format: CSV, BatchSize: 2})
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if count != 5 {
+		t.Errorf("Expected 5 records across the batches, got %d", count)
+	}
+	expected := `id,name,age
+1,Alice,30
+2,"Bob, ""the builder""",
+3,Carol,25
+4,Dave,41
+5,Eve,19
+`
+	if out.String() != expected {
+		t.Errorf("Unexpected CSV:\n%s", out.String())
+	}
+}
+
+func TestExportEmpty(t *testing.T) {
+	db := newTestDatabase(t)
+
+	query := db.Model("User").Select()
+	query = query.WhereCondition(query.Where("age").GreaterThan(100))
+	var out bytes.Buffer
+	count, err := Export(context.Background(), &out, db, "User", query, Options{Format: CSV})
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if count != 0 || out.String() != "id,name,age\n" {
+		t.Errorf("Expected only the header, got %d records:\n%s", count, out.String())
+	}
+}
+
+func TestExportNDJSON(t *testing.T) {
+	db := newTestDatabase(t)
+
+	// Batches follow the ordering of the query
+	query := db.Model("User").Select()
+	query = query.WhereCondition(query.Where("age").GreaterThan(20)).OrderBy("age", types.DESC)
+	var out bytes.Buffer
+	count, err := Export(context.Background(), &out, db, "User", query, Options{
+		Format:    NDJSON,
+		BatchSize: 1,
+		Transform: func(records []map[string]any) []map[string]any {
+			for _, record := range records {
+				record["name"] = "***"
+			}
+			return records
+		},
+	})
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if count != 3 {
+		t.Fatalf("Expected 3 records, got %d", count)
+	}
+
+	var ages []float64
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Invalid NDJSON line %q: %v", line, err)
+		}
+		if record["name"] != "***" {
+			t.Errorf("Expected the transformed name, got %v", record["name"])
+		}
+		ages = append(ages, record["age"].(float64))
+	}
+	if len(ages) != 3 || ages[0] != 41 || ages[1] != 30 || ages[2] != 25 {
+		t.Errorf("Expected ages 41, 30, 25, got %v", ages)
+	}
+}
+
+func TestExportErrors(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := context.Background()
+
+	if _, err := Export(ctx, &bytes.Buffer{}, db, "User", db.Model("User").Select(), Options{Format: "xml"}); err == nil {
+		t.Error("Expected an error for an unsupported format")
+	}
+	if _, err := Export(ctx, &bytes.Buffer{}, db, "Missing", db.Model("User").Select(), Options{Format: CSV}); err == nil {
+		t.Error("Expected an error for an unknown model")
+	}
+}
@@ -0,0 +1,308 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/rediwo/redi-orm/masking"
+	"github.com/rediwo/redi-orm/orm"
+	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/types"
+)
+
+// aggregateGroups are the aggregations of aggregate and groupBy queries, selected as fields
+// of their results
+var aggregateGroups = []string{"_count", "_sum", "_avg", "_min", "_max"}
+
+// AggregateFilter filters groups by the value of an aggregation in having
+var AggregateFilter = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "AggregateFilter",
+	Description: "Filter on the value of an aggregation",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"equals": &graphql.InputObjectFieldConfig{Type: graphql.Float},
+		"not":    &graphql.InputObjectFieldConfig{Type: graphql.Float},
+		"lt":     &graphql.InputObjectFieldConfig{Type: graphql.Float},
+		"lte":    &graphql.InputObjectFieldConfig{Type: graphql.Float},
+		"gt":     &graphql.InputObjectFieldConfig{Type: graphql.Float},
+		"gte":    &graphql.InputObjectFieldConfig{Type: graphql.Float},
+	},
+})
+
+// aggregateTypes are the types of the aggregate and groupBy queries of a model
+type aggregateTypes struct {
+	aggregate *graphql.Object      // <Model>Aggregate, the result of aggregate<Model>
+	groupBy   *graphql.Object      // <Model>GroupBy, a group of groupBy<Model>
+	by        *graphql.Enum        // <Model>ScalarField, the fields groups are formed by
+	having    *graphql.InputObject // <Model>HavingInput
+	orderBy   *graphql.InputObject // <Model>GroupByOrderByInput
+}
+
+// isNumericField reports whether sums and averages of a field can be taken
+func isNumericField(field schema.Field) bool {
+	switch field.Type {
+	case schema.FieldTypeInt, schema.FieldTypeInt64, schema.FieldTypeFloat, schema.FieldTypeDecimal:
+		return true
+	}
+	return false
+}
+
+// createAggregateTypes creates the types of the aggregate and groupBy queries of a model.
+// Every field can be counted; sums, averages, minimums and maximums are of numeric fields,
+// and are omitted for models without any.
+func (g *SchemaGenerator) createAggregateTypes(modelName string) error {
+	modelSchema, ok := g.schemas[modelName]
+	if !ok {
+		return fmt.Errorf("schema not found for model %s", modelName)
+	}
+
+	countFields := graphql.Fields{"_all": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)}}
+	countHaving := graphql.InputObjectConfigFieldMap{"_all": &graphql.InputObjectFieldConfig{Type: AggregateFilter}}
+	numberFields := graphql.Fields{}
+	numberHaving := graphql.InputObjectConfigFieldMap{}
+	byValues := graphql.EnumValueConfigMap{}
+	groupFields := graphql.Fields{}
+	orderByFields := graphql.InputObjectConfigFieldMap{}
+	for _, field := range modelSchema.Fields {
+		countFields[field.Name] = &graphql.Field{Type: graphql.NewNonNull(graphql.Int)}
+		countHaving[field.Name] = &graphql.InputObjectFieldConfig{Type: AggregateFilter}
+		if isNumericField(field) {
+			numberFields[field.Name] = &graphql.Field{Type: graphql.Float}
+			numberHaving[field.Name] = &graphql.InputObjectFieldConfig{Type: AggregateFilter}
+		}
+		byValues[field.Name] = &graphql.EnumValueConfig{Value: field.Name}
+		groupFields[field.Name] = &graphql.Field{Type: MapFieldTypeToGraphQL(field.Type)}
+		orderByFields[field.Name] = &graphql.InputObjectFieldConfig{Type: OrderByEnum}
+	}
+
+	countType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        modelName + "CountAggregate",
+		Description: fmt.Sprintf("Numbers of %s records, and of their non-null values of each field", modelName),
+		Fields:      countFields,
+	})
+	countOrderBy := graphql.InputObjectConfigFieldMap{}
+	for name := range countHaving {
+		countOrderBy[name] = &graphql.InputObjectFieldConfig{Type: OrderByEnum}
+	}
+
+	aggregateFields := graphql.Fields{"_count": &graphql.Field{Type: countType}}
+	groupFields["_count"] = &graphql.Field{Type: countType}
+	havingFields := graphql.InputObjectConfigFieldMap{
+		"_count": &graphql.InputObjectFieldConfig{Type: graphql.NewInputObject(graphql.InputObjectConfig{
+			Name:   modelName + "CountHavingInput",
+			Fields: countHaving,
+		})},
+	}
+	orderByFields["_count"] = &graphql.InputObjectFieldConfig{Type: graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:   modelName + "CountOrderByInput",
+		Fields: countOrderBy,
+	})}
+
+	if len(numberFields) > 0 {
+		numberType := graphql.NewObject(graphql.ObjectConfig{
+			Name:        modelName + "NumberAggregate",
+			Description: fmt.Sprintf("Aggregations of the numeric fields of %s", modelName),
+			Fields:      numberFields,
+		})
+		numberHavingInput := graphql.NewInputObject(graphql.InputObjectConfig{
+			Name:   modelName + "NumberHavingInput",
+			Fields: numberHaving,
+		})
+		numberOrderBy := graphql.InputObjectConfigFieldMap{}
+		for name := range numberHaving {
+			numberOrderBy[name] = &graphql.InputObjectFieldConfig{Type: OrderByEnum}
+		}
+		numberOrderByInput := graphql.NewInputObject(graphql.InputObjectConfig{
+			Name:   modelName + "NumberOrderByInput",
+			Fields: numberOrderBy,
+		})
+		for _, group := range aggregateGroups[1:] {
+			aggregateFields[group] = &graphql.Field{Type: numberType}
+			groupFields[group] = &graphql.Field{Type: numberType}
+			havingFields[group] = &graphql.InputObjectFieldConfig{Type: numberHavingInput}
+			orderByFields[group] = &graphql.InputObjectFieldConfig{Type: numberOrderByInput}
+		}
+	}
+
+	g.aggregates[modelName] = &aggregateTypes{
+		aggregate: graphql.NewObject(graphql.ObjectConfig{
+			Name:        modelName + "Aggregate",
+			Description: fmt.Sprintf("Aggregations of %s records", modelName),
+			Fields:      aggregateFields,
+		}),
+		groupBy: graphql.NewObject(graphql.ObjectConfig{
+			Name:        modelName + "GroupBy",
+			Description: fmt.Sprintf("A group of %s records with the same values of the grouped fields", modelName),
+			Fields:      groupFields,
+		}),
+		by: graphql.NewEnum(graphql.EnumConfig{
+			Name:   modelName + "ScalarField",
+			Values: byValues,
+		}),
+		having: graphql.NewInputObject(graphql.InputObjectConfig{
+			Name:   modelName + "HavingInput",
+			Fields: havingFields,
+		}),
+		orderBy: graphql.NewInputObject(graphql.InputObjectConfig{
+			Name:   modelName + "GroupByOrderByInput",
+			Fields: orderByFields,
+		}),
+	}
+	return nil
+}
+
+// addAggregateQueries adds the aggregate and groupBy queries of a model to the query fields
+func (g *SchemaGenerator) addAggregateQueries(queryFields graphql.Fields, modelName string) {
+	aggregates := g.aggregates[modelName]
+
+	queryFields["aggregate"+modelName] = &graphql.Field{
+		Type:        graphql.NewNonNull(aggregates.aggregate),
+		Description: fmt.Sprintf("Aggregations of the %s records matching where, computed for the fields selected", modelName),
+		Args: graphql.FieldConfigArgument{
+			"where": &graphql.ArgumentConfig{
+				Type: g.whereInputs[modelName],
+			},
+		},
+		Resolve: createAggregateResolver(g.sources[modelName], modelName, g.masking),
+	}
+
+	queryFields["groupBy"+modelName] = &graphql.Field{
+		Type:        graphql.NewList(graphql.NewNonNull(aggregates.groupBy)),
+		Description: fmt.Sprintf("Groups of the %s records matching where, with the aggregations selected", modelName),
+		Args: graphql.FieldConfigArgument{
+			"by": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(aggregates.by))),
+			},
+			"where": &graphql.ArgumentConfig{
+				Type: g.whereInputs[modelName],
+			},
+			"having": &graphql.ArgumentConfig{
+				Type: aggregates.having,
+			},
+			"orderBy": &graphql.ArgumentConfig{
+				Type: graphql.NewList(graphql.NewNonNull(aggregates.orderBy)),
+			},
+			"limit": &graphql.ArgumentConfig{
+				Type: graphql.Int,
+			},
+			"offset": &graphql.ArgumentConfig{
+				Type: graphql.Int,
+			},
+		},
+		Resolve: createGroupByResolver(g.sources[modelName], modelName, g.masking),
+	}
+}
+
+// createAggregateResolver creates a resolver for aggregate queries
+func createAggregateResolver(source dataSource, modelName string, policy *masking.Policy) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		options := aggregateOptions(p)
+		if where, ok := p.Args["where"]; ok {
+			options["where"] = where
+		}
+
+		db := source(p.Context)
+		result, err := runORMQuery(p, db, modelName, "aggregate", options)
+		if err != nil {
+			return nil, err
+		}
+		return policy.Apply(p.Context, db, modelName, result), nil
+	}
+}
+
+// createGroupByResolver creates a resolver for groupBy queries
+func createGroupByResolver(source dataSource, modelName string, policy *masking.Policy) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		options := aggregateOptions(p)
+		options["by"] = p.Args["by"]
+		if where, ok := p.Args["where"]; ok {
+			options["where"] = where
+		}
+		if having, ok := p.Args["having"]; ok {
+			options["having"] = having
+		}
+		// The entries of orderBy apply in the order they are written
+		if fields := orderByFields(p); len(fields) > 0 {
+			orderBy := make([]any, len(fields))
+			for i, field := range fields {
+				orderBy[i] = map[string]any{field.name: field.direction}
+			}
+			options["orderBy"] = orderBy
+		}
+		if limit, ok := p.Args["limit"].(int); ok {
+			options["take"] = limit
+		}
+		if offset, ok := p.Args["offset"].(int); ok {
+			options["skip"] = offset
+		}
+
+		db := source(p.Context)
+		result, err := runORMQuery(p, db, modelName, "groupBy", options)
+		if err != nil {
+			return nil, err
+		}
+		return policy.Apply(p.Context, db, modelName, result), nil
+	}
+}
+
+// runORMQuery runs an operation of the ORM on a model with the given options
+func runORMQuery(p graphql.ResolveParams, db types.Database, modelName, operation string, options map[string]any) (any, error) {
+	query, err := json.Marshal(map[string]any{operation: options})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s query: %w", operation, err)
+	}
+	return orm.NewClient(db).Model(modelName).QueryContext(p.Context, string(query))
+}
+
+// aggregateOptions returns the aggregations of the fields selected in the result of an
+// aggregate or groupBy query, as the ORM options {_sum: {field: true}, ...}. Counts are
+// always taken per field, so that _count holds an object.
+func aggregateOptions(p graphql.ResolveParams) map[string]any {
+	options := make(map[string]any)
+	if len(p.Info.FieldASTs) == 0 {
+		return options
+	}
+	selected := make(map[string][]*ast.SelectionSet)
+	collectSelections(p.Info, p.Info.FieldASTs[0].SelectionSet, selected)
+	for _, group := range aggregateGroups {
+		sets, ok := selected[group]
+		if !ok {
+			continue
+		}
+		fields := make(map[string][]*ast.SelectionSet)
+		for _, set := range sets {
+			collectSelections(p.Info, set, fields)
+		}
+		enabled := make(map[string]any)
+		for field := range fields {
+			if field != "__typename" {
+				enabled[field] = true
+			}
+		}
+		if len(enabled) > 0 {
+			options[group] = enabled
+		}
+	}
+	return options
+}
+
+// collectSelections adds the fields of a selection set, and of the fragments it uses, to
+// fields by name with their own selection sets
+func collectSelections(info graphql.ResolveInfo, set *ast.SelectionSet, fields map[string][]*ast.SelectionSet) {
+	if set == nil {
+		return
+	}
+	for _, selection := range set.Selections {
+		switch s := selection.(type) {
+		case *ast.Field:
+			fields[s.Name.Value] = append(fields[s.Name.Value], s.SelectionSet)
+		case *ast.InlineFragment:
+			collectSelections(info, s.SelectionSet, fields)
+		case *ast.FragmentSpread:
+			if fragment, ok := info.Fragments[s.Name.Value].(*ast.FragmentDefinition); ok {
+				collectSelections(info, fragment.SelectionSet, fields)
+			}
+		}
+	}
+}
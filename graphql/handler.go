@@ -2,6 +2,7 @@ package graphql
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -9,8 +10,12 @@ import (
 	"time"
 
 	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
 	"github.com/graphql-go/handler"
+	"github.com/rediwo/redi-orm/database"
 	"github.com/rediwo/redi-orm/logger"
+	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/types"
 )
 
 // Handler provides a generic HTTP handler for GraphQL requests
@@ -19,6 +24,8 @@ type Handler struct {
 	pretty            bool
 	graphiQLEnabled   bool
 	playgroundEnabled bool
+	deduplicate       bool
+	batchWindow       time.Duration
 	logger            logger.Logger
 }
 
@@ -53,6 +60,20 @@ func (h *Handler) EnablePlayground() *Handler {
 	return h
 }
 
+// EnableDeduplication runs identical findUnique lookups of a request once, like those of
+// the relation fields of records sharing a parent
+func (h *Handler) EnableDeduplication() *Handler {
+	h.deduplicate = true
+	return h
+}
+
+// SetBatchWindow loads the findUnique lookups of a request by the same field, started within
+// window of each other, with one IN query. A window of 0 disables batching.
+func (h *Handler) SetBatchWindow(window time.Duration) *Handler {
+	h.batchWindow = window
+	return h
+}
+
 // SetLogger sets the logger for this handler
 func (h *Handler) SetLogger(l logger.Logger) *Handler {
 	h.logger = l
@@ -184,14 +205,32 @@ func (h *Handler) ServeGraphQL(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Run the resolvers on the connection named by the request, if any
+	ctx := r.Context()
+	if name := r.Header.Get("X-Connection-Name"); name != "" {
+		db, err := database.Connection(name)
+		if err != nil {
+			h.writeError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		ctx = database.WithConnection(ctx, db)
+	}
+	if h.deduplicate {
+		ctx = types.WithDeduplication(ctx)
+	}
+	if h.batchWindow > 0 {
+		ctx = types.WithBatching(ctx, h.batchWindow)
+	}
+
 	// Execute GraphQL query
 	result := graphql.Do(graphql.Params{
 		Schema:         *h.schema,
 		RequestString:  params.Query,
 		VariableValues: params.Variables,
 		OperationName:  params.OperationName,
-		Context:        r.Context(),
+		Context:        ctx,
 	})
+	addErrorExtensions(result.Errors)
 
 	// Log execution results
 	duration := time.Since(startTime)
@@ -222,6 +261,20 @@ func (h *Handler) ServeGraphQL(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// addErrorExtensions adds the structured details of validation errors to their GraphQL errors
+func addErrorExtensions(errs []gqlerrors.FormattedError) {
+	for i, formatted := range errs {
+		err := formatted.OriginalError()
+		if located, ok := err.(*gqlerrors.Error); ok {
+			err = located.OriginalError
+		}
+		var validationErr *schema.ValidationError
+		if errors.As(err, &validationErr) {
+			errs[i].Extensions = validationErr.Extensions()
+		}
+	}
+}
+
 // ServePlayground serves the GraphQL Playground interface
 func (h *Handler) ServePlayground(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html")
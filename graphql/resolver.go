@@ -2,17 +2,25 @@ package graphql
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
 	"github.com/rediwo/redi-orm/schema"
 	"github.com/rediwo/redi-orm/types"
 )
 
+// dataSource returns the database that a resolver runs on for a request
+type dataSource func(ctx context.Context) types.Database
+
 // createFindUniqueResolver creates a resolver for findUnique queries
-func createFindUniqueResolver(db types.Database, modelName string) graphql.FieldResolveFn {
+func createFindUniqueResolver(source dataSource, modelName string) graphql.FieldResolveFn {
 	return func(p graphql.ResolveParams) (any, error) {
 		ctx := p.Context
+		db := source(ctx)
 		where := p.Args["where"].(map[string]any)
 
 		// Build where conditions
@@ -56,24 +64,15 @@ func createFindUniqueResolver(db types.Database, modelName string) graphql.Field
 		}
 
 		// Execute and return first result
-		var results []map[string]any
-		err := query.Limit(1).FindMany(ctx, &results)
-		if err != nil {
-			return nil, err
-		}
-
-		if len(results) == 0 {
-			return nil, nil
-		}
-
-		return results[0], nil
+		return findUnique(ctx, db, query, modelName, conditions)
 	}
 }
 
 // createFindManyResolver creates a resolver for findMany queries
-func createFindManyResolver(db types.Database, modelName string) graphql.FieldResolveFn {
+func createFindManyResolver(source dataSource, modelName string) graphql.FieldResolveFn {
 	return func(p graphql.ResolveParams) (any, error) {
 		ctx := p.Context
+		db := source(ctx)
 
 		// Build query
 		query := db.Model(modelName).Select()
@@ -115,15 +114,13 @@ func createFindManyResolver(db types.Database, modelName string) graphql.FieldRe
 			}
 		}
 
-		// Apply orderBy
-		if orderBy, ok := p.Args["orderBy"].(map[string]any); ok {
-			for field, direction := range orderBy {
-				order := types.ASC
-				if dir, ok := direction.(string); ok && dir == "DESC" {
-					order = types.DESC
-				}
-				query = query.OrderBy(field, order)
+		// Apply orderBy, a list of objects that apply in order
+		for _, field := range orderByFields(p) {
+			order := types.ASC
+			if dir, ok := field.direction.(string); ok && dir == "DESC" {
+				order = types.DESC
 			}
+			query = query.OrderBy(field.name, order)
 		}
 
 		// Apply limit
@@ -148,9 +145,10 @@ func createFindManyResolver(db types.Database, modelName string) graphql.FieldRe
 }
 
 // createCountResolver creates a resolver for count queries
-func createCountResolver(db types.Database, modelName string) graphql.FieldResolveFn {
+func createCountResolver(source dataSource, modelName string) graphql.FieldResolveFn {
 	return func(p graphql.ResolveParams) (any, error) {
 		ctx := p.Context
+		db := source(ctx)
 
 		// Build query
 		query := db.Model(modelName).Select()
@@ -203,9 +201,10 @@ func createCountResolver(db types.Database, modelName string) graphql.FieldResol
 }
 
 // createCreateResolver creates a resolver for create mutations
-func createCreateResolver(db types.Database, modelName string) graphql.FieldResolveFn {
+func createCreateResolver(source dataSource, modelName string) graphql.FieldResolveFn {
 	return func(p graphql.ResolveParams) (any, error) {
 		ctx := p.Context
+		db := source(ctx)
 		data := p.Args["data"].(map[string]any)
 
 		// Debug: log the data being inserted
@@ -218,9 +217,9 @@ func createCreateResolver(db types.Database, modelName string) graphql.FieldReso
 		}
 
 		// Always fetch the created record to ensure proper field mapping
-		if result.LastInsertID > 0 {
+		if id, ok := result.InsertedID(); ok {
 			query := db.Model(modelName).Select()
-			query = query.WhereCondition(query.Where("id").Equals(result.LastInsertID))
+			query = query.WhereCondition(query.Where("id").Equals(id))
 
 			var results []map[string]any
 			err = query.Limit(1).FindMany(ctx, &results)
@@ -239,9 +238,10 @@ func createCreateResolver(db types.Database, modelName string) graphql.FieldReso
 }
 
 // createUpdateResolver creates a resolver for update mutations
-func createUpdateResolver(db types.Database, modelName string) graphql.FieldResolveFn {
+func createUpdateResolver(source dataSource, modelName string) graphql.FieldResolveFn {
 	return func(p graphql.ResolveParams) (any, error) {
 		ctx := p.Context
+		db := source(ctx)
 		where := p.Args["where"].(map[string]any)
 		data := p.Args["data"].(map[string]any)
 
@@ -323,9 +323,10 @@ func createUpdateResolver(db types.Database, modelName string) graphql.FieldReso
 }
 
 // createDeleteResolver creates a resolver for delete mutations
-func createDeleteResolver(db types.Database, modelName string) graphql.FieldResolveFn {
+func createDeleteResolver(source dataSource, modelName string) graphql.FieldResolveFn {
 	return func(p graphql.ResolveParams) (any, error) {
 		ctx := p.Context
+		db := source(ctx)
 		where := p.Args["where"].(map[string]any)
 
 		// Build where conditions
@@ -385,9 +386,10 @@ func createDeleteResolver(db types.Database, modelName string) graphql.FieldReso
 }
 
 // createCreateManyResolver creates a resolver for createMany mutations
-func createCreateManyResolver(db types.Database, modelName string) graphql.FieldResolveFn {
+func createCreateManyResolver(source dataSource, modelName string) graphql.FieldResolveFn {
 	return func(p graphql.ResolveParams) (any, error) {
 		ctx := p.Context
+		db := source(ctx)
 		dataList := p.Args["data"].([]any)
 
 		// Convert to slice of maps
@@ -418,9 +420,10 @@ func createCreateManyResolver(db types.Database, modelName string) graphql.Field
 }
 
 // createUpdateManyResolver creates a resolver for updateMany mutations
-func createUpdateManyResolver(db types.Database, modelName string) graphql.FieldResolveFn {
+func createUpdateManyResolver(source dataSource, modelName string) graphql.FieldResolveFn {
 	return func(p graphql.ResolveParams) (any, error) {
 		ctx := p.Context
+		db := source(ctx)
 		data := p.Args["data"].(map[string]any)
 
 		// Build query
@@ -474,9 +477,10 @@ func createUpdateManyResolver(db types.Database, modelName string) graphql.Field
 }
 
 // createDeleteManyResolver creates a resolver for deleteMany mutations
-func createDeleteManyResolver(db types.Database, modelName string) graphql.FieldResolveFn {
+func createDeleteManyResolver(source dataSource, modelName string) graphql.FieldResolveFn {
 	return func(p graphql.ResolveParams) (any, error) {
 		ctx := p.Context
+		db := source(ctx)
 
 		// Build query
 		query := db.Model(modelName).Delete()
@@ -529,9 +533,10 @@ func createDeleteManyResolver(db types.Database, modelName string) graphql.Field
 }
 
 // createRelationResolver creates a resolver for relation fields
-func createRelationResolver(db types.Database, modelName string, relation schema.Relation) graphql.FieldResolveFn {
+func createRelationResolver(source dataSource, modelName string, relation schema.Relation) graphql.FieldResolveFn {
 	return func(p graphql.ResolveParams) (any, error) {
-		ctx := context.Background()
+		ctx := p.Context
+		db := source(ctx)
 
 		// Get the parent record
 		parent, ok := p.Source.(map[string]any)
@@ -563,6 +568,10 @@ func createRelationResolver(db types.Database, modelName string, relation schema
 		if relation.Type == schema.RelationOneToMany {
 			// Find all children where foreign key matches parent's primary key
 			query = query.WhereCondition(query.Where(relation.ForeignKey).Equals(foreignKeyValue))
+			if relation.IsPolymorphic() {
+				// Children of a polymorphic relation name the model they belong to
+				query = query.WhereCondition(query.Where(relation.TypeField).Equals(modelName))
+			}
 
 			var results []map[string]any
 			err := query.FindMany(ctx, &results)
@@ -577,22 +586,201 @@ func createRelationResolver(db types.Database, modelName string, relation schema
 				referencesField = "id" // Default to id
 			}
 			query = query.WhereCondition(query.Where(referencesField).Equals(foreignKeyValue))
+			return findUnique(ctx, db, query, relation.Model, map[string]any{referencesField: map[string]any{"equals": foreignKeyValue}})
+		}
 
-			var results []map[string]any
-			err := query.Limit(1).FindMany(ctx, &results)
-			if err != nil {
-				return nil, err
-			}
+		// TODO: Handle many-to-many relations
+		return nil, fmt.Errorf("many-to-many relations not yet implemented")
+	}
+}
 
-			if len(results) > 0 {
-				return results[0], nil
-			}
+// findUnique returns the first record of query, which looks up the record of modelName
+// matching the conditions of where. Identical lookups, like those of the parents of many
+// records sharing one, run once in a context of types.WithDeduplication. In a context of
+// types.WithBatching, lookups by the value of a single field return a thunk, resolved once
+// the lookups of the same field started alongside load with one IN query.
+func findUnique(ctx context.Context, db types.Database, query types.SelectQuery, modelName string, where map[string]any) (any, error) {
+	find := func() (any, error) {
+		var results []map[string]any
+		if err := query.Limit(1).FindMany(ctx, &results); err != nil {
+			return nil, err
+		}
+		if len(results) == 0 {
 			return nil, nil
 		}
+		return results[0], nil
+	}
 
-		// TODO: Handle many-to-many relations
-		return nil, fmt.Errorf("many-to-many relations not yet implemented")
+	if field, value, ok := equalsField(where); ok && types.Batching(ctx) {
+		find = func() (any, error) {
+			return types.BatchLoad(ctx, modelName, field, value, func(values []any) (map[string]any, error) {
+				var results []map[string]any
+				query := db.Model(modelName).Select()
+				if err := query.WhereCondition(query.Where(field).In(values...)).FindMany(ctx, &results); err != nil {
+					return nil, err
+				}
+				records := make(map[string]any, len(results))
+				for _, record := range results {
+					records[fmt.Sprint(record[field])] = record
+				}
+				return records, nil
+			})
+		}
+	}
+
+	if key, err := json.Marshal(where); err == nil {
+		deduplicated := find
+		find = func() (any, error) {
+			return types.Deduplicate(ctx, "findUnique "+modelName+" "+string(key), deduplicated)
+		}
+	}
+
+	if !types.Batching(ctx) {
+		return find()
+	}
+
+	// Resolve in the background, so that graphql resolves the sibling fields meanwhile
+	type lookup struct {
+		result any
+		err    error
+	}
+	done := make(chan lookup, 1)
+	go func() {
+		result, err := find()
+		done <- lookup{result, err}
+	}()
+	return func() (any, error) {
+		l := <-done
+		return l.result, l.err
+	}, nil
+}
+
+// equalsField returns the field and value of conditions matching a single field by equality
+func equalsField(conditions map[string]any) (string, any, bool) {
+	if len(conditions) != 1 {
+		return "", nil, false
+	}
+	for field, condition := range conditions {
+		operators, ok := condition.(map[string]any)
+		if !ok || len(operators) != 1 || operators["equals"] == nil {
+			return "", nil, false
+		}
+		return field, operators["equals"], true
+	}
+	return "", nil, false
+}
+
+// typenameKey holds the model of a record resolved through a polymorphic relation, which
+// selects its object type in the union of the relation
+const typenameKey = "__typename"
+
+// createPolymorphicRelationResolver creates a resolver for a polymorphic relation, loading
+// the record from the model named by the type field of the parent
+func createPolymorphicRelationResolver(sources map[string]dataSource, relation schema.Relation) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		parent, ok := p.Source.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid parent object")
+		}
+
+		model := relation.RelatedModel(parent)
+		source, ok := sources[model]
+		foreignKeyValue := parent[relation.ForeignKey]
+		if !ok || foreignKeyValue == nil {
+			return nil, nil
+		}
+
+		referencesField := relation.References
+		if referencesField == "" {
+			referencesField = "id"
+		}
+		query := source(p.Context).Model(model).Select()
+		query = query.WhereCondition(query.Where(referencesField).Equals(foreignKeyValue))
+
+		var results []map[string]any
+		if err := query.Limit(1).FindMany(p.Context, &results); err != nil {
+			return nil, err
+		}
+		if len(results) == 0 {
+			return nil, nil
+		}
+
+		record := maps.Clone(results[0])
+		record[typenameKey] = model
+		return record, nil
+	}
+}
+
+// orderByField is a field of the orderBy argument with its direction
+type orderByField struct {
+	name      string
+	direction any
+}
+
+// orderByFields lists the fields of the orderBy argument in the order they apply: the
+// objects of the list in turn, and the fields of each object in the order they are written
+// in the query. The fields of objects passed in variables, whose order is lost in decoding,
+// apply by name.
+func orderByFields(p graphql.ResolveParams) []orderByField {
+	var objects []any
+	switch orderBy := p.Args["orderBy"].(type) {
+	case []any:
+		objects = orderBy
+	case map[string]any:
+		objects = []any{orderBy}
+	}
+
+	literals := orderByLiterals(p)
+	var fields []orderByField
+	for i, object := range objects {
+		values, ok := object.(map[string]any)
+		if !ok {
+			continue
+		}
+		var names []string
+		if i < len(literals) && len(literals) == len(objects) {
+			names = literals[i]
+		}
+		if len(names) != len(values) {
+			names = slices.Sorted(maps.Keys(values))
+		}
+		for _, name := range names {
+			if direction, ok := values[name]; ok {
+				fields = append(fields, orderByField{name: name, direction: direction})
+			}
+		}
+	}
+	return fields
+}
+
+// orderByLiterals returns the field names of the objects of an orderBy argument written in
+// the query, in the order they are written
+func orderByLiterals(p graphql.ResolveParams) [][]string {
+	if len(p.Info.FieldASTs) == 0 {
+		return nil
+	}
+	for _, arg := range p.Info.FieldASTs[0].Arguments {
+		if arg.Name == nil || arg.Name.Value != "orderBy" {
+			continue
+		}
+		var objects []ast.Value
+		switch value := arg.Value.(type) {
+		case *ast.ListValue:
+			objects = value.Values
+		case *ast.ObjectValue:
+			objects = []ast.Value{value}
+		}
+		literals := make([][]string, len(objects))
+		for i, object := range objects {
+			if value, ok := object.(*ast.ObjectValue); ok {
+				for _, field := range value.Fields {
+					literals[i] = append(literals[i], field.Name.Value)
+				}
+			}
+		}
+		return literals
 	}
+	return nil
 }
 
 // buildWhereConditions builds where conditions from GraphQL input
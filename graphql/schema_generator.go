@@ -1,9 +1,12 @@
 package graphql
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/graphql-go/graphql"
+	"github.com/rediwo/redi-orm/database"
+	"github.com/rediwo/redi-orm/masking"
 	"github.com/rediwo/redi-orm/schema"
 	"github.com/rediwo/redi-orm/types"
 	"github.com/rediwo/redi-orm/utils"
@@ -17,6 +20,9 @@ type SchemaGenerator struct {
 	inputTypes    map[string]*graphql.InputObject
 	whereInputs   map[string]*graphql.InputObject
 	orderByInputs map[string]*graphql.InputObject
+	aggregates    map[string]*aggregateTypes
+	masking       *masking.Policy
+	sources       map[string]dataSource
 }
 
 // NewSchemaGenerator creates a new schema generator
@@ -28,9 +34,45 @@ func NewSchemaGenerator(db types.Database, schemas map[string]*schema.Schema) *S
 		inputTypes:    make(map[string]*graphql.InputObject),
 		whereInputs:   make(map[string]*graphql.InputObject),
 		orderByInputs: make(map[string]*graphql.InputObject),
+		aggregates:    make(map[string]*aggregateTypes),
+		sources:       make(map[string]dataSource),
 	}
 }
 
+// SetMaskingPolicy masks sensitive fields in query results for unprivileged callers
+func (g *SchemaGenerator) SetMaskingPolicy(policy *masking.Policy) {
+	g.masking = policy
+}
+
+// createDataSources binds each model to the database its resolvers run on. Models with
+// @@datasource use the connection registered under the datasource name, so one GraphQL
+// schema can serve models from several databases. Other models use the generator's
+// database, or the connection selected by the request's X-Connection-Name header.
+func (g *SchemaGenerator) createDataSources() error {
+	defaultSource := func(ctx context.Context) types.Database {
+		if selected := database.ConnectionFromContext(ctx); selected != nil {
+			return selected
+		}
+		return g.db
+	}
+
+	for modelName, modelSchema := range g.schemas {
+		if modelSchema.Datasource == "" {
+			g.sources[modelName] = defaultSource
+			continue
+		}
+
+		db, err := database.Connection(modelSchema.Datasource)
+		if err != nil {
+			return fmt.Errorf("datasource of model %s: %w", modelName, err)
+		}
+		g.sources[modelName] = func(context.Context) types.Database {
+			return db
+		}
+	}
+	return nil
+}
+
 // GetObjectTypes returns the object types for debugging
 func (g *SchemaGenerator) GetObjectTypes() map[string]*graphql.Object {
 	return g.objectTypes
@@ -38,6 +80,11 @@ func (g *SchemaGenerator) GetObjectTypes() map[string]*graphql.Object {
 
 // Generate creates the complete GraphQL schema
 func (g *SchemaGenerator) Generate() (*graphql.Schema, error) {
+	// Bind models to their datasources before creating resolvers
+	if err := g.createDataSources(); err != nil {
+		return nil, err
+	}
+
 	// First pass: create basic object types (without relations)
 	for modelName := range g.schemas {
 		if err := g.createBasicObjectType(modelName); err != nil {
@@ -57,6 +104,9 @@ func (g *SchemaGenerator) Generate() (*graphql.Schema, error) {
 		if err := g.createInputTypes(modelName); err != nil {
 			return nil, fmt.Errorf("failed to create input types for %s: %w", modelName, err)
 		}
+		if err := g.createAggregateTypes(modelName); err != nil {
+			return nil, fmt.Errorf("failed to create aggregate types for %s: %w", modelName, err)
+		}
 	}
 
 	// Create query type
@@ -92,7 +142,9 @@ func (g *SchemaGenerator) createBasicObjectType(modelName string) error {
 	// Add fields from schema
 	for _, field := range modelSchema.Fields {
 		fieldType := MapFieldTypeToGraphQL(field.Type)
-		if !field.Nullable {
+		// Masked values that are not strings resolve to null
+		_, masked := g.masking.FieldStrategy(modelName, field.Name)
+		if !field.Nullable && (!masked || fieldType == graphql.String) {
 			fieldType = graphql.NewNonNull(fieldType)
 		}
 
@@ -101,7 +153,7 @@ func (g *SchemaGenerator) createBasicObjectType(modelName string) error {
 			Resolve: func(p graphql.ResolveParams) (any, error) {
 				// Field resolver - returns the field value from the source object
 				if source, ok := p.Source.(map[string]any); ok {
-					return source[p.Info.FieldName], nil
+					return g.masking.MaskValue(p.Context, modelName, p.Info.FieldName, source[p.Info.FieldName]), nil
 				}
 				return nil, nil
 			},
@@ -139,6 +191,14 @@ func (g *SchemaGenerator) addRelationFields(modelName string) error {
 
 	// Add relation fields using AddFieldConfig
 	for relationName, relation := range modelSchema.Relations {
+		if len(relation.Models) > 0 {
+			objectType.AddFieldConfig(relationName, &graphql.Field{
+				Type:    g.polymorphicUnion(modelName, relationName, relation),
+				Resolve: createPolymorphicRelationResolver(g.sources, relation),
+			})
+			continue
+		}
+
 		relatedType, ok := g.objectTypes[relation.Model]
 		if !ok {
 			continue // Skip if related model not found
@@ -154,13 +214,37 @@ func (g *SchemaGenerator) addRelationFields(modelName string) error {
 		// Add the field to the existing object type
 		objectType.AddFieldConfig(relationName, &graphql.Field{
 			Type:    fieldType,
-			Resolve: createRelationResolver(g.db, modelName, relation),
+			Resolve: createRelationResolver(g.sources[relation.Model], modelName, relation),
 		})
 	}
 
 	return nil
 }
 
+// polymorphicUnion creates the union of the object types of the models a polymorphic
+// relation references, such as CommentCommentable for Comment.commentable
+func (g *SchemaGenerator) polymorphicUnion(modelName, relationName string, relation schema.Relation) *graphql.Union {
+	var members []*graphql.Object
+	for _, model := range relation.Models {
+		if objectType, ok := g.objectTypes[model]; ok {
+			members = append(members, objectType)
+		}
+	}
+
+	return graphql.NewUnion(graphql.UnionConfig{
+		Name:        modelName + utils.ToPascalCase(relationName),
+		Description: fmt.Sprintf("Models referenced by %s.%s", modelName, relationName),
+		Types:       members,
+		ResolveType: func(p graphql.ResolveTypeParams) *graphql.Object {
+			if record, ok := p.Value.(map[string]any); ok {
+				model, _ := record[typenameKey].(string)
+				return g.objectTypes[model]
+			}
+			return nil
+		},
+	})
+}
+
 // createInputTypes creates input types for create and update operations
 func (g *SchemaGenerator) createInputTypes(modelName string) error {
 	modelSchema, ok := g.schemas[modelName]
@@ -255,7 +339,7 @@ func (g *SchemaGenerator) createQueryType() *graphql.Object {
 					Type: graphql.NewNonNull(g.whereInputs[modelName]),
 				},
 			},
-			Resolve: createFindUniqueResolver(g.db, modelName),
+			Resolve: createFindUniqueResolver(g.sources[modelName], modelName),
 		}
 
 		// findMany query
@@ -266,7 +350,8 @@ func (g *SchemaGenerator) createQueryType() *graphql.Object {
 					Type: g.whereInputs[modelName],
 				},
 				"orderBy": &graphql.ArgumentConfig{
-					Type: g.orderByInputs[modelName],
+					// A single object is taken as a list of one
+					Type: graphql.NewList(graphql.NewNonNull(g.orderByInputs[modelName])),
 				},
 				"limit": &graphql.ArgumentConfig{
 					Type: graphql.Int,
@@ -275,7 +360,7 @@ func (g *SchemaGenerator) createQueryType() *graphql.Object {
 					Type: graphql.Int,
 				},
 			},
-			Resolve: createFindManyResolver(g.db, modelName),
+			Resolve: createFindManyResolver(g.sources[modelName], modelName),
 		}
 
 		// count query
@@ -286,8 +371,11 @@ func (g *SchemaGenerator) createQueryType() *graphql.Object {
 					Type: g.whereInputs[modelName],
 				},
 			},
-			Resolve: createCountResolver(g.db, modelName),
+			Resolve: createCountResolver(g.sources[modelName], modelName),
 		}
+
+		// aggregate and groupBy queries
+		g.addAggregateQueries(queryFields, modelName)
 	}
 
 	return graphql.NewObject(graphql.ObjectConfig{
@@ -309,7 +397,7 @@ func (g *SchemaGenerator) createMutationType() *graphql.Object {
 					Type: graphql.NewNonNull(g.inputTypes[modelName+"CreateInput"]),
 				},
 			},
-			Resolve: createCreateResolver(g.db, modelName),
+			Resolve: createCreateResolver(g.sources[modelName], modelName),
 		}
 
 		// update mutation
@@ -323,7 +411,7 @@ func (g *SchemaGenerator) createMutationType() *graphql.Object {
 					Type: graphql.NewNonNull(g.inputTypes[modelName+"UpdateInput"]),
 				},
 			},
-			Resolve: createUpdateResolver(g.db, modelName),
+			Resolve: createUpdateResolver(g.sources[modelName], modelName),
 		}
 
 		// delete mutation
@@ -334,7 +422,7 @@ func (g *SchemaGenerator) createMutationType() *graphql.Object {
 					Type: graphql.NewNonNull(g.whereInputs[modelName]),
 				},
 			},
-			Resolve: createDeleteResolver(g.db, modelName),
+			Resolve: createDeleteResolver(g.sources[modelName], modelName),
 		}
 
 		// createMany mutation
@@ -350,7 +438,7 @@ func (g *SchemaGenerator) createMutationType() *graphql.Object {
 					Type: graphql.NewNonNull(graphql.NewList(g.inputTypes[modelName+"CreateInput"])),
 				},
 			},
-			Resolve: createCreateManyResolver(g.db, modelName),
+			Resolve: createCreateManyResolver(g.sources[modelName], modelName),
 		}
 
 		// updateMany mutation
@@ -369,7 +457,7 @@ func (g *SchemaGenerator) createMutationType() *graphql.Object {
 					Type: graphql.NewNonNull(g.inputTypes[modelName+"UpdateInput"]),
 				},
 			},
-			Resolve: createUpdateManyResolver(g.db, modelName),
+			Resolve: createUpdateManyResolver(g.sources[modelName], modelName),
 		}
 
 		// deleteMany mutation
@@ -385,7 +473,7 @@ func (g *SchemaGenerator) createMutationType() *graphql.Object {
 					Type: g.whereInputs[modelName],
 				},
 			},
-			Resolve: createDeleteManyResolver(g.db, modelName),
+			Resolve: createDeleteManyResolver(g.sources[modelName], modelName),
 		}
 	}
 
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/rediwo/redi-orm/database"
 	"github.com/rediwo/redi-orm/logger"
+	"github.com/rediwo/redi-orm/masking"
 	"github.com/rediwo/redi-orm/prisma"
 	"github.com/rediwo/redi-orm/schema"
 	"github.com/rediwo/redi-orm/types"
@@ -15,54 +17,69 @@ import (
 
 // Server represents a GraphQL server
 type Server struct {
-	db      types.Database
-	schemas map[string]*schema.Schema
-	handler *Handler
-	port    int
-	cors    bool
+	db          types.Database
+	ownsDB      bool     // Whether the server connected db itself and closes it on Stop
+	datasources []string // Connections registered for @@datasource models
+	schemas     map[string]*schema.Schema
+	handler     *Handler
+	port        int
+	cors        bool
+	masking     *masking.Policy
 }
 
 // ServerConfig contains configuration for the GraphQL server
 type ServerConfig struct {
 	DatabaseURI string
+	Database    types.Database // Optional: serve this connected database instead of connecting to DatabaseURI
 	SchemaPath  string
 	Port        int
 	Playground  bool
 	CORS        bool
 	LogLevel    string
+	Masking     *masking.Policy // Optional: mask sensitive fields for unprivileged callers
+	BatchWindow time.Duration   // Optional: load findUnique lookups started within this window with one IN query
 }
 
 // NewServer creates a new GraphQL server
 func NewServer(config ServerConfig) (*Server, error) {
-	// Create database connection
-	db, err := database.NewFromURI(config.DatabaseURI)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create database: %w", err)
-	}
-
-	// Connect to database
 	ctx := context.Background()
-	if err := db.Connect(ctx); err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
-	}
+	db := config.Database
+	ownsDB := db == nil
+	if ownsDB {
+		// Create database connection
+		var err error
+		db, err = database.NewFromURI(config.DatabaseURI)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create database: %w", err)
+		}
 
-	// Set up database logger based on log level
-	if config.LogLevel != "" {
-		dbLogger := logger.NewDefaultLogger("DB")
-		logLevel := logger.ParseLogLevel(config.LogLevel)
-		dbLogger.SetLevel(logLevel)
-		db.SetLogger(dbLogger)
+		// Connect to database
+		if err := db.Connect(ctx); err != nil {
+			return nil, fmt.Errorf("failed to connect to database: %w", err)
+		}
+
+		// Set up database logger based on log level
+		if config.LogLevel != "" {
+			dbLogger := logger.NewDefaultLogger("DB")
+			logLevel := logger.ParseLogLevel(config.LogLevel)
+			dbLogger.SetLevel(logLevel)
+			db.SetLogger(dbLogger)
+		}
 	}
 
 	// Load schemas from file or directory
-	schemas, err := prisma.LoadSchemaFromPath(config.SchemaPath)
+	def, err := prisma.LoadDefinitionFromPath(config.SchemaPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load schema: %w", err)
 	}
+	schemas := def.Schemas
 	log.Printf("Loaded %d models from schema", len(schemas))
 
-	// Register schemas with database
+	// Register schemas with database, except those stored in another datasource
 	for modelName, schema := range schemas {
+		if schema.Datasource != "" {
+			continue
+		}
 		if err := db.RegisterSchema(modelName, schema); err != nil {
 			return nil, fmt.Errorf("failed to register schema %s: %w", modelName, err)
 		}
@@ -73,8 +90,18 @@ func NewServer(config ServerConfig) (*Server, error) {
 		return nil, fmt.Errorf("failed to sync schemas: %w", err)
 	}
 
+	// Connect the datasources of the other models
+	datasources, err := registerDatasources(ctx, def, db.GetLogger())
+	if err != nil {
+		for _, name := range datasources {
+			database.Unregister(name)
+		}
+		return nil, err
+	}
+
 	// Generate GraphQL schema
 	generator := NewSchemaGenerator(db, schemas)
+	generator.SetMaskingPolicy(config.Masking)
 	graphqlSchema, err := generator.Generate()
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate GraphQL schema: %w", err)
@@ -85,6 +112,7 @@ func NewServer(config ServerConfig) (*Server, error) {
 	if config.Playground {
 		handler.EnablePlayground()
 	}
+	handler.SetBatchWindow(config.BatchWindow)
 
 	// Set logger with appropriate log level
 	if config.LogLevel != "" {
@@ -94,14 +122,66 @@ func NewServer(config ServerConfig) (*Server, error) {
 	}
 
 	return &Server{
-		db:      db,
-		schemas: schemas,
-		handler: handler,
-		port:    config.Port,
-		cors:    config.CORS,
+		db:          db,
+		ownsDB:      ownsDB,
+		datasources: datasources,
+		schemas:     schemas,
+		handler:     handler,
+		port:        config.Port,
+		cors:        config.CORS,
+		masking:     config.Masking,
 	}, nil
 }
 
+// registerDatasources registers a connection for each datasource that models select with
+// @@datasource, from the url of its datasource block, and syncs the models stored there.
+// Connections that are already registered under the datasource name are used as they are,
+// without syncing. It returns the names of the connections it registered.
+func registerDatasources(ctx context.Context, def *prisma.Definition, l logger.Logger) ([]string, error) {
+	bound := make(map[string][]*schema.Schema)
+	for _, s := range def.Schemas {
+		if s.Datasource != "" {
+			bound[s.Datasource] = append(bound[s.Datasource], s)
+		}
+	}
+
+	var registered []string
+	for name, schemas := range bound {
+		db, err := database.Connection(name)
+		existing := err == nil
+		if !existing {
+			ds := def.DatasourceByName(name)
+			if ds == nil {
+				return registered, fmt.Errorf("model %s uses unknown datasource %s", schemas[0].Name, name)
+			}
+			url, err := ds.ResolveURL()
+			if err != nil {
+				return registered, err
+			}
+			if db, err = database.Register(name, url); err != nil {
+				return registered, fmt.Errorf("failed to connect datasource %s: %w", name, err)
+			}
+			registered = append(registered, name)
+			if l != nil {
+				db.SetLogger(l)
+			}
+		}
+
+		for _, s := range schemas {
+			if err := db.RegisterSchema(s.Name, s); err != nil {
+				return registered, fmt.Errorf("failed to register schema %s: %w", s.Name, err)
+			}
+		}
+		if existing {
+			continue
+		}
+		if err := db.SyncSchemas(ctx); err != nil {
+			return registered, fmt.Errorf("failed to sync datasource %s: %w", name, err)
+		}
+	}
+	return registered, nil
+}
+
 // Start starts the GraphQL server
 func (s *Server) Start() error {
 	mux := http.NewServeMux()
@@ -134,17 +214,25 @@ func (s *Server) Start() error {
 	log.Printf("🚀 GraphQL server ready at http://localhost%s/graphql", addr)
 	log.Printf("🎮 GraphQL Playground available at http://localhost%s/graphql", addr)
 
-	return http.ListenAndServe(addr, mux)
+	return http.ListenAndServe(addr, s.masking.Middleware(mux))
 }
 
-// Stop stops the GraphQL server
+// Stop stops the GraphQL server, closing the connections it made
 func (s *Server) Stop() error {
-	if s.db != nil {
+	for _, name := range s.datasources {
+		database.Unregister(name)
+	}
+	if s.db != nil && s.ownsDB {
 		return s.db.Close()
 	}
 	return nil
 }
 
+// Schemas returns the models the server serves by name
+func (s *Server) Schemas() map[string]*schema.Schema {
+	return s.schemas
+}
+
 // Handler returns the GraphQL handler
 func (s *Server) Handler() http.Handler {
 	return s.handler
@@ -156,7 +244,7 @@ func (s *Server) corsMiddleware(next http.Handler) http.Handler {
 		if s.cors {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Connection-Name")
 
 			// Handle preflight requests
 			if r.Method == "OPTIONS" {
@@ -6,10 +6,18 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
 	"github.com/rediwo/redi-orm/database"
 	"github.com/rediwo/redi-orm/graphql"
+	"github.com/rediwo/redi-orm/logger"
+	"github.com/rediwo/redi-orm/masking"
 	"github.com/rediwo/redi-orm/prisma"
 	"github.com/rediwo/redi-orm/schema"
 	"github.com/stretchr/testify/assert"
@@ -400,6 +408,51 @@ func TestGraphQLHandler(t *testing.T) {
 		// This should be the second user when ordered by ID
 	})
 
+	t.Run("OrderByList", func(t *testing.T) {
+		createQuery := `
+			mutation {
+				createManyUser(data: [
+					{name: "Order B", email: "order1@example.com"},
+					{name: "Order A", email: "order2@example.com"},
+					{name: "Order B", email: "order3@example.com"}
+				]) {
+					count
+				}
+			}
+		`
+		testGraphQL(createQuery, nil)
+
+		emails := func(orderBy string) []string {
+			query := `
+				query {
+					findManyUser(where: {name: {startsWith: "Order"}}, orderBy: ` + orderBy + `) {
+						email
+					}
+				}
+			`
+			response := testGraphQL(query, nil)
+			require.Nil(t, response["errors"])
+			users := response["data"].(map[string]any)["findManyUser"].([]any)
+			var result []string
+			for _, user := range users {
+				result = append(result, user.(map[string]any)["email"].(string))
+			}
+			return result
+		}
+
+		// The objects of a list apply in order
+		assert.Equal(t, []string{"order3@example.com", "order1@example.com", "order2@example.com"},
+			emails(`[{name: DESC}, {id: DESC}]`))
+		assert.Equal(t, []string{"order2@example.com", "order1@example.com", "order3@example.com"},
+			emails(`[{name: ASC}, {id: ASC}]`))
+
+		// So do the fields of an object, in the order they are written
+		assert.Equal(t, []string{"order2@example.com", "order3@example.com", "order1@example.com"},
+			emails(`{name: ASC, id: DESC}`))
+		assert.Equal(t, []string{"order1@example.com", "order2@example.com", "order3@example.com"},
+			emails(`{id: ASC, name: DESC}`))
+	})
+
 	t.Run("BatchOperations", func(t *testing.T) {
 		// Test createMany
 		createManyQuery := `
@@ -624,3 +677,748 @@ func TestPlayground(t *testing.T) {
 	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
 	assert.Contains(t, w.Body.String(), "GraphQL Playground")
 }
+
+func TestGraphQLFieldMasking(t *testing.T) {
+	db, err := database.NewFromURI("sqlite://:memory:")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, db.Connect(ctx))
+	defer db.Close()
+
+	schemas, err := prisma.ParseSchema(`
+		model User {
+			id    Int    @id @default(autoincrement())
+			name  String
+			email String @unique
+			age   Int
+		}
+	`)
+	require.NoError(t, err)
+	for modelName, schema := range schemas {
+		require.NoError(t, db.RegisterSchema(modelName, schema))
+	}
+	require.NoError(t, db.SyncSchemas(ctx))
+
+	_, err = db.Model("User").Insert(map[string]any{"name": "Alice", "email": "alice@example.com", "age": 30}).Exec(ctx)
+	require.NoError(t, err)
+
+	policy := &masking.Policy{
+		Fields: map[string]masking.Strategy{
+			"User.email": masking.StrategyEmail,
+			"User.age":   masking.StrategyFull,
+		},
+		PrivilegedRoles: []string{"admin"},
+		RoleHeader:      "X-Role",
+	}
+
+	generator := graphql.NewSchemaGenerator(db, schemas)
+	generator.SetMaskingPolicy(policy)
+	graphqlSchema, err := generator.Generate()
+	require.NoError(t, err)
+	handler := policy.Middleware(graphql.NewHandler(graphqlSchema))
+
+	findUser := func(role string) map[string]any {
+		body, err := json.Marshal(map[string]any{"query": `{ findManyUser { name email age } }`})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest("POST", "/graphql", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if role != "" {
+			req.Header.Set("X-Role", role)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Nil(t, response["errors"])
+		users := response["data"].(map[string]any)["findManyUser"].([]any)
+		require.Len(t, users, 1)
+		return users[0].(map[string]any)
+	}
+
+	user := findUser("")
+	assert.Equal(t, "Alice", user["name"])
+	assert.Equal(t, "a***@example.com", user["email"])
+	assert.Nil(t, user["age"])
+
+	user = findUser("admin")
+	assert.Equal(t, "alice@example.com", user["email"])
+	assert.Equal(t, float64(30), user["age"])
+}
+
+func TestGraphQLBytesField(t *testing.T) {
+	db, err := database.NewFromURI("sqlite://:memory:")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, db.Connect(ctx))
+	defer db.Close()
+
+	schemas, err := prisma.ParseSchema(`
+		model File {
+			id   Int    @id @default(autoincrement())
+			name String
+			data Bytes
+		}
+	`)
+	require.NoError(t, err)
+	for modelName, schema := range schemas {
+		require.NoError(t, db.RegisterSchema(modelName, schema))
+	}
+	require.NoError(t, db.SyncSchemas(ctx))
+
+	generator := graphql.NewSchemaGenerator(db, schemas)
+	graphqlSchema, err := generator.Generate()
+	require.NoError(t, err)
+	handler := graphql.NewHandler(graphqlSchema)
+
+	execute := func(query string, variables map[string]any) map[string]any {
+		body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+		require.NoError(t, err)
+		req := httptest.NewRequest("POST", "/graphql", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	// Binary data is written and read as base64, from literals and from variables
+	response := execute(`mutation { createFile(data: {name: "a.bin", data: "AAH/"}) { data } }`, nil)
+	require.Nil(t, response["errors"])
+	assert.Equal(t, "AAH/", response["data"].(map[string]any)["createFile"].(map[string]any)["data"])
+
+	response = execute(`mutation Create($data: FileCreateInput!) { createFile(data: $data) { data } }`,
+		map[string]any{"data": map[string]any{"name": "b.bin", "data": "aGk="}})
+	require.Nil(t, response["errors"])
+
+	var record map[string]any
+	query := db.Model("File").Select()
+	require.NoError(t, query.WhereCondition(query.Where("name").Equals("b.bin")).FindFirst(ctx, &record))
+	assert.Equal(t, []byte("hi"), record["data"])
+
+	response = execute(`mutation { createFile(data: {name: "c.bin", data: "not base64!"}) { data } }`, nil)
+	assert.NotNil(t, response["errors"])
+}
+
+func TestGraphQLDecimalAndBigIntFields(t *testing.T) {
+	db, err := database.NewFromURI("sqlite://:memory:")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, db.Connect(ctx))
+	defer db.Close()
+
+	schemas, err := prisma.ParseSchema(`
+		model Invoice {
+			id     Int     @id @default(autoincrement())
+			amount Decimal
+			number BigInt
+		}
+	`)
+	require.NoError(t, err)
+	for modelName, schema := range schemas {
+		require.NoError(t, db.RegisterSchema(modelName, schema))
+	}
+	require.NoError(t, db.SyncSchemas(ctx))
+
+	generator := graphql.NewSchemaGenerator(db, schemas)
+	graphqlSchema, err := generator.Generate()
+	require.NoError(t, err)
+	handler := graphql.NewHandler(graphqlSchema)
+
+	execute := func(query string, variables map[string]any) map[string]any {
+		body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+		require.NoError(t, err)
+		req := httptest.NewRequest("POST", "/graphql", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		// Decode numbers exactly to check that no digits were lost
+		var response map[string]any
+		decoder := json.NewDecoder(w.Body)
+		decoder.UseNumber()
+		require.NoError(t, decoder.Decode(&response))
+		return response
+	}
+
+	// Decimals are written as strings and 64-bit integers as exact numbers
+	response := execute(`mutation { createInvoice(data: {amount: 1234.56, number: 9007199254740993}) { amount number } }`, nil)
+	require.Nil(t, response["errors"])
+	invoice := response["data"].(map[string]any)["createInvoice"].(map[string]any)
+	assert.Equal(t, "1234.56", invoice["amount"])
+	assert.Equal(t, json.Number("9007199254740993"), invoice["number"])
+
+	// Variables may give both as strings
+	response = execute(`mutation Create($data: InvoiceCreateInput!) { createInvoice(data: $data) { amount number } }`,
+		map[string]any{"data": map[string]any{"amount": "0.10", "number": "9007199254740995"}})
+	require.Nil(t, response["errors"])
+	invoice = response["data"].(map[string]any)["createInvoice"].(map[string]any)
+	assert.Equal(t, json.Number("9007199254740995"), invoice["number"])
+
+	response = execute(`{ findManyInvoice(where: {number: {equals: "9007199254740993"}}) { amount } }`, nil)
+	require.Nil(t, response["errors"])
+	assert.Len(t, response["data"].(map[string]any)["findManyInvoice"], 1)
+
+	response = execute(`mutation { createInvoice(data: {amount: "ten", number: 1}) { amount } }`, nil)
+	assert.NotNil(t, response["errors"])
+}
+
+func TestGraphQLDateTimeAndJSONFields(t *testing.T) {
+	db, err := database.NewFromURI("sqlite://:memory:")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, db.Connect(ctx))
+	defer db.Close()
+
+	schemas, err := prisma.ParseSchema(`
+		model Event {
+			id        Int      @id @default(autoincrement())
+			startsAt  DateTime
+			settings  Json?
+		}
+	`)
+	require.NoError(t, err)
+	for modelName, schema := range schemas {
+		require.NoError(t, db.RegisterSchema(modelName, schema))
+	}
+	require.NoError(t, db.SyncSchemas(ctx))
+
+	generator := graphql.NewSchemaGenerator(db, schemas)
+	graphqlSchema, err := generator.Generate()
+	require.NoError(t, err)
+	handler := graphql.NewHandler(graphqlSchema)
+
+	execute := func(query string, variables map[string]any) map[string]any {
+		body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+		require.NoError(t, err)
+		req := httptest.NewRequest("POST", "/graphql", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	// JSON literals may be objects, and dates are written in RFC 3339 format
+	response := execute(`mutation {
+		createEvent(data: {startsAt: "2024-03-01T09:30:00Z", settings: {theme: "dark", seats: [1, 2], public: true}}) {
+			startsAt settings
+		}
+	}`, nil)
+	require.Nil(t, response["errors"])
+	event := response["data"].(map[string]any)["createEvent"].(map[string]any)
+	assert.Equal(t, "2024-03-01T09:30:00Z", event["startsAt"])
+	assert.Equal(t, map[string]any{"theme": "dark", "seats": []any{float64(1), float64(2)}, "public": true}, event["settings"])
+
+	response = execute(`mutation Create($data: EventCreateInput!) { createEvent(data: $data) { startsAt settings } }`,
+		map[string]any{"data": map[string]any{"startsAt": "2024-03-02", "settings": []any{"a", "b"}}})
+	require.Nil(t, response["errors"])
+	event = response["data"].(map[string]any)["createEvent"].(map[string]any)
+	assert.Equal(t, "2024-03-02T00:00:00Z", event["startsAt"])
+	assert.Equal(t, []any{"a", "b"}, event["settings"])
+
+	response = execute(`{ findManyEvent(where: {startsAt: {gt: "2024-03-01T12:00:00Z"}}) { settings } }`, nil)
+	require.Nil(t, response["errors"])
+	assert.Len(t, response["data"].(map[string]any)["findManyEvent"], 1)
+
+	// Dates must be ISO 8601
+	response = execute(`mutation { createEvent(data: {startsAt: "next tuesday"}) { id } }`, nil)
+	assert.NotNil(t, response["errors"])
+	response = execute(`mutation Create($data: EventCreateInput!) { createEvent(data: $data) { id } }`,
+		map[string]any{"data": map[string]any{"startsAt": "03/01/2024"}})
+	assert.NotNil(t, response["errors"])
+}
+
+func TestGraphQLRegisterScalar(t *testing.T) {
+	db, err := database.NewFromURI("sqlite://:memory:")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, db.Connect(ctx))
+	defer db.Close()
+
+	// Slugs are strings of a field type of their own, with a scalar validating them
+	const slugType schema.FieldType = "slug"
+	parseSlug := func(value any) any {
+		if text, ok := value.(string); ok && text != "" && strings.ToLower(text) == text && !strings.Contains(text, " ") {
+			return text
+		}
+		return nil
+	}
+	graphql.RegisterScalar(slugType, gql.NewScalar(gql.ScalarConfig{
+		Name:       "Slug",
+		Serialize:  func(value any) any { return value },
+		ParseValue: parseSlug,
+		ParseLiteral: func(valueAST ast.Value) any {
+			if stringValue, ok := valueAST.(*ast.StringValue); ok {
+				return parseSlug(stringValue.Value)
+			}
+			return nil
+		},
+	}))
+
+	schemas, err := prisma.ParseSchema(`
+		model Article {
+			id   Int    @id @default(autoincrement())
+			slug String
+		}
+	`)
+	require.NoError(t, err)
+	schemas["Article"].Fields[1].Type = slugType
+	for modelName, schema := range schemas {
+		require.NoError(t, db.RegisterSchema(modelName, schema))
+	}
+	require.NoError(t, db.SyncSchemas(ctx))
+
+	generator := graphql.NewSchemaGenerator(db, schemas)
+	graphqlSchema, err := generator.Generate()
+	require.NoError(t, err)
+	assert.Equal(t, "Slug!", graphqlSchema.Type("Article").(*gql.Object).Fields()["slug"].Type.String())
+	assert.NotNil(t, graphqlSchema.Type("SlugFilter"))
+	handler := graphql.NewHandler(graphqlSchema)
+
+	execute := func(query string) map[string]any {
+		body, err := json.Marshal(map[string]any{"query": query})
+		require.NoError(t, err)
+		req := httptest.NewRequest("POST", "/graphql", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	response := execute(`mutation { createArticle(data: {slug: "hello-world"}) { slug } }`)
+	require.Nil(t, response["errors"])
+	assert.Equal(t, "hello-world", response["data"].(map[string]any)["createArticle"].(map[string]any)["slug"])
+
+	response = execute(`mutation { createArticle(data: {slug: "Hello World"}) { slug } }`)
+	assert.NotNil(t, response["errors"])
+}
+
+func TestGraphQLAggregateAndGroupBy(t *testing.T) {
+	db, err := database.NewFromURI("sqlite://:memory:")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, db.Connect(ctx))
+	defer db.Close()
+
+	schemas, err := prisma.ParseSchema(`
+		model Order {
+			id       Int     @id @default(autoincrement())
+			region   String
+			status   String
+			amount   Float
+			note     String?
+		}
+	`)
+	require.NoError(t, err)
+	for modelName, schema := range schemas {
+		require.NoError(t, db.RegisterSchema(modelName, schema))
+	}
+	require.NoError(t, db.SyncSchemas(ctx))
+
+	orders := []map[string]any{
+		{"region": "east", "status": "paid", "amount": 100.0, "note": "gift"},
+		{"region": "east", "status": "paid", "amount": 50.0},
+		{"region": "east", "status": "open", "amount": 25.0},
+		{"region": "west", "status": "paid", "amount": 200.0},
+	}
+	for _, order := range orders {
+		_, err := db.Model("Order").Insert(order).Exec(ctx)
+		require.NoError(t, err)
+	}
+
+	generator := graphql.NewSchemaGenerator(db, schemas)
+	graphqlSchema, err := generator.Generate()
+	require.NoError(t, err)
+	handler := graphql.NewHandler(graphqlSchema)
+
+	execute := func(query string) map[string]any {
+		body, err := json.Marshal(map[string]any{"query": query})
+		require.NoError(t, err)
+		req := httptest.NewRequest("POST", "/graphql", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Nil(t, response["errors"], query)
+		return response["data"].(map[string]any)
+	}
+
+	// Aggregations are computed for the fields selected, fragments included
+	data := execute(`{
+		aggregateOrder(where: {region: {equals: "east"}}) {
+			_count { _all note }
+			_sum { amount }
+			... on OrderAggregate { _avg { amount } }
+			_min { ...Amount }
+			_max { amount }
+		}
+	}
+	fragment Amount on OrderNumberAggregate { amount }`)
+	assert.Equal(t, map[string]any{
+		"_count": map[string]any{"_all": float64(3), "note": float64(1)},
+		"_sum":   map[string]any{"amount": float64(175)},
+		"_avg":   map[string]any{"amount": 175.0 / 3},
+		"_min":   map[string]any{"amount": float64(25)},
+		"_max":   map[string]any{"amount": float64(100)},
+	}, data["aggregateOrder"])
+
+	// Groups may be filtered by their aggregations and ordered by them
+	data = execute(`{
+		groupByOrder(by: [region, status], having: {_sum: {amount: {gte: 50}}}, orderBy: [{_sum: {amount: DESC}}]) {
+			region status
+			_count { _all }
+			_sum { amount }
+		}
+	}`)
+	assert.Equal(t, []any{
+		map[string]any{"region": "west", "status": "paid", "_count": map[string]any{"_all": float64(1)}, "_sum": map[string]any{"amount": float64(200)}},
+		map[string]any{"region": "east", "status": "paid", "_count": map[string]any{"_all": float64(2)}, "_sum": map[string]any{"amount": float64(150)}},
+	}, data["groupByOrder"])
+
+	data = execute(`{
+		groupByOrder(by: [region], where: {status: {equals: "paid"}}, orderBy: [{region: ASC}], limit: 1) {
+			region
+			_avg { amount }
+		}
+	}`)
+	assert.Equal(t, []any{map[string]any{"region": "east", "_avg": map[string]any{"amount": float64(75)}}}, data["groupByOrder"])
+}
+
+func TestGraphQLConnectionHeader(t *testing.T) {
+	ctx := context.Background()
+	schemas, err := prisma.ParseSchema(`
+		model Report {
+			id    Int    @id @default(autoincrement())
+			title String
+		}
+	`)
+	require.NoError(t, err)
+
+	open := func(title string) database.Database {
+		db, err := database.NewFromURI("sqlite://:memory:")
+		require.NoError(t, err)
+		require.NoError(t, db.Connect(ctx))
+		for modelName, schema := range schemas {
+			require.NoError(t, db.RegisterSchema(modelName, schema))
+		}
+		require.NoError(t, db.SyncSchemas(ctx))
+		_, err = db.Model("Report").Insert(map[string]any{"title": title}).Exec(ctx)
+		require.NoError(t, err)
+		return db
+	}
+
+	db := open("main")
+	defer db.Close()
+	require.NoError(t, database.RegisterDatabase("reports", open("archived")))
+	defer database.Unregister("reports")
+
+	graphqlSchema, err := graphql.NewSchemaGenerator(db, schemas).Generate()
+	require.NoError(t, err)
+	handler := graphql.NewHandler(graphqlSchema)
+
+	execute := func(connection string) *httptest.ResponseRecorder {
+		body, err := json.Marshal(map[string]any{"query": `{ findManyReport { title } }`})
+		require.NoError(t, err)
+		req := httptest.NewRequest("POST", "/graphql", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if connection != "" {
+			req.Header.Set("X-Connection-Name", connection)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+	titles := func(w *httptest.ResponseRecorder) any {
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Nil(t, response["errors"])
+		return response["data"].(map[string]any)["findManyReport"]
+	}
+
+	assert.Equal(t, []any{map[string]any{"title": "main"}}, titles(execute("")))
+	assert.Equal(t, []any{map[string]any{"title": "archived"}}, titles(execute("reports")))
+	assert.Equal(t, http.StatusBadRequest, execute("missing").Code)
+}
+
+func TestGraphQLDatasourceFederation(t *testing.T) {
+	dir := t.TempDir()
+	schemaPath := filepath.Join(dir, "schema.prisma")
+	require.NoError(t, os.WriteFile(schemaPath, []byte(`
+		datasource db {
+			provider = "sqlite"
+			url      = "file:`+filepath.Join(dir, "app.db")+`"
+		}
+
+		datasource analytics {
+			provider = "sqlite"
+			url      = "file:`+filepath.Join(dir, "analytics.db")+`"
+		}
+
+		model User {
+			id     Int     @id @default(autoincrement())
+			name   String
+			events Event[]
+		}
+
+		model Event {
+			id     Int    @id @default(autoincrement())
+			kind   String
+			userId Int
+			user   User   @relation(fields: [userId], references: [id])
+
+			@@datasource(analytics)
+		}
+	`), 0644))
+
+	server, err := graphql.NewServer(graphql.ServerConfig{
+		DatabaseURI: "sqlite://" + filepath.Join(dir, "app.db"),
+		SchemaPath:  schemaPath,
+	})
+	require.NoError(t, err)
+	defer server.Stop()
+
+	execute := func(query string) map[string]any {
+		body, err := json.Marshal(map[string]any{"query": query})
+		require.NoError(t, err)
+		req := httptest.NewRequest("POST", "/graphql", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		server.Handler().ServeHTTP(w, req)
+
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Nil(t, response["errors"])
+		return response["data"].(map[string]any)
+	}
+
+	execute(`mutation { createUser(data: {name: "Alice"}) { id } }`)
+	execute(`mutation { createEvent(data: {kind: "login", userId: 1}) { id } }`)
+
+	// Events are stored in the analytics database only
+	analytics, err := database.Connection("analytics")
+	require.NoError(t, err)
+	count, err := analytics.Model("Event").Select().Count(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// Relations across the two databases are resolved by the server
+	data := execute(`{ findManyUser { name events { kind } } }`)
+	assert.Equal(t, []any{map[string]any{"name": "Alice", "events": []any{map[string]any{"kind": "login"}}}}, data["findManyUser"])
+
+	data = execute(`{ findManyEvent { kind user { name } } }`)
+	assert.Equal(t, []any{map[string]any{"kind": "login", "user": map[string]any{"name": "Alice"}}}, data["findManyEvent"])
+}
+
+func TestGraphQLRequestCancellation(t *testing.T) {
+	ctx := context.Background()
+	db, err := database.NewFromURI("sqlite://:memory:")
+	require.NoError(t, err)
+	require.NoError(t, db.Connect(ctx))
+	defer db.Close()
+
+	schemas, err := prisma.ParseSchema(`
+		model Report {
+			id    Int    @id @default(autoincrement())
+			title String
+		}
+	`)
+	require.NoError(t, err)
+	for modelName, schema := range schemas {
+		require.NoError(t, db.RegisterSchema(modelName, schema))
+	}
+	require.NoError(t, db.SyncSchemas(ctx))
+
+	graphqlSchema, err := graphql.NewSchemaGenerator(db, schemas).Generate()
+	require.NoError(t, err)
+	handler := graphql.NewHandler(graphqlSchema)
+
+	// The database work of a canceled request is canceled with it
+	requestCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	execute := func(query string) {
+		body, err := json.Marshal(map[string]any{"query": query})
+		require.NoError(t, err)
+		req := httptest.NewRequest("POST", "/graphql", bytes.NewReader(body)).WithContext(requestCtx)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.NotNil(t, response["errors"], query)
+		assert.Contains(t, w.Body.String(), context.Canceled.Error())
+	}
+	execute(`mutation { createReport(data: {title: "late"}) { id } }`)
+	execute(`{ findManyReport { title } }`)
+
+	count, err := db.Model("Report").Select().Count(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestGraphQLPolymorphicRelation(t *testing.T) {
+	db, err := database.NewFromURI("sqlite://:memory:")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, db.Connect(ctx))
+	defer db.Close()
+
+	schemas, err := prisma.ParseSchema(`
+		model Post {
+			id       Int       @id
+			title    String
+			comments Comment[]
+		}
+
+		model Photo {
+			id       Int       @id
+			url      String
+			comments Comment[]
+		}
+
+		model Comment {
+			id              Int          @id
+			body            String
+			commentableId   Int
+			commentableType String
+			commentable     Post | Photo @relation(fields: [commentableId], references: [id], type: commentableType)
+		}
+	`)
+	require.NoError(t, err)
+	for modelName, schema := range schemas {
+		require.NoError(t, db.RegisterSchema(modelName, schema))
+	}
+	require.NoError(t, db.SyncSchemas(ctx))
+
+	_, err = db.Exec("INSERT INTO posts (id, title) VALUES (1, 'Hello')")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO photos (id, url) VALUES (1, 'cat.png')")
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO comments (id, body, commentable_id, commentable_type) VALUES
+		(1, 'on post', 1, 'Post'), (2, 'on photo', 1, 'Photo')`)
+	require.NoError(t, err)
+
+	generator := graphql.NewSchemaGenerator(db, schemas)
+	graphqlSchema, err := generator.Generate()
+	require.NoError(t, err)
+	handler := graphql.NewHandler(graphqlSchema)
+
+	body, err := json.Marshal(map[string]any{"query": `query {
+		findManyComment(orderBy: {id: ASC}) {
+			body
+			commentable {
+				__typename
+				... on Post { title }
+				... on Photo { url }
+			}
+		}
+		findManyPhoto { comments { body } }
+	}`})
+	require.NoError(t, err)
+	req := httptest.NewRequest("POST", "/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	var response map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Nil(t, response["errors"])
+	data := response["data"].(map[string]any)
+
+	comments := data["findManyComment"].([]any)
+	require.Len(t, comments, 2)
+	assert.Equal(t, map[string]any{"__typename": "Post", "title": "Hello"}, comments[0].(map[string]any)["commentable"])
+	assert.Equal(t, map[string]any{"__typename": "Photo", "url": "cat.png"}, comments[1].(map[string]any)["commentable"])
+
+	// The inverse relation only holds the comments naming its model
+	photos := data["findManyPhoto"].([]any)
+	require.Len(t, photos, 1)
+	assert.Equal(t, []any{map[string]any{"body": "on photo"}}, photos[0].(map[string]any)["comments"])
+}
+
+func TestGraphQLFindUniqueBatching(t *testing.T) {
+	ctx := context.Background()
+	db, err := database.NewFromURI("sqlite://:memory:")
+	require.NoError(t, err)
+	require.NoError(t, db.Connect(ctx))
+	defer db.Close()
+
+	schemas, err := prisma.ParseSchema(`
+		model User {
+			id    Int    @id @default(autoincrement())
+			name  String
+			posts Post[]
+		}
+
+		model Post {
+			id       Int    @id @default(autoincrement())
+			title    String
+			authorId Int
+			author   User   @relation(fields: [authorId], references: [id])
+		}
+	`)
+	require.NoError(t, err)
+	for modelName, schema := range schemas {
+		require.NoError(t, db.RegisterSchema(modelName, schema))
+	}
+	require.NoError(t, db.SyncSchemas(ctx))
+	_, err = db.Exec(`INSERT INTO users (id, name) VALUES (1, 'Alice'), (2, 'Bob'), (3, 'Carol')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO posts (id, title, author_id) VALUES
+		(1, 'a', 1), (2, 'b', 2), (3, 'c', 1), (4, 'd', 3), (5, 'e', 2)`)
+	require.NoError(t, err)
+
+	graphqlSchema, err := graphql.NewSchemaGenerator(db, schemas).Generate()
+	require.NoError(t, err)
+	handler := graphql.NewHandler(graphqlSchema).EnableDeduplication().SetBatchWindow(5 * time.Millisecond)
+
+	// Count the statements of the request
+	var statements bytes.Buffer
+	dbLogger := logger.NewDefaultLogger("DB")
+	dbLogger.SetLevel(logger.LogLevelDebug)
+	dbLogger.SetOutput(&statements)
+	db.SetLogger(dbLogger)
+
+	body, err := json.Marshal(map[string]any{"query": `{
+		findManyPost(orderBy: {id: ASC}) { title author { name } }
+		findUniqueUser(where: {id: {equals: 3}}) { name }
+	}`})
+	require.NoError(t, err)
+	req := httptest.NewRequest("POST", "/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	var response map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Nil(t, response["errors"])
+	data := response["data"].(map[string]any)
+
+	var authors []any
+	for _, post := range data["findManyPost"].([]any) {
+		authors = append(authors, post.(map[string]any)["author"].(map[string]any)["name"])
+	}
+	assert.Equal(t, []any{"Alice", "Bob", "Alice", "Carol", "Bob"}, authors)
+	assert.Equal(t, map[string]any{"name": "Carol"}, data["findUniqueUser"])
+
+	// The posts, then the users of all the lookups in one IN query
+	assert.Equal(t, 2, strings.Count(statements.String(), "SQL ("), statements.String())
+}
@@ -1,20 +1,53 @@
 package graphql
 
 import (
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/graphql/language/ast"
 	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/utils"
 )
 
+var (
+	scalarsMu sync.RWMutex
+	scalars   = make(map[schema.FieldType]*graphql.Scalar)
+)
+
+// RegisterScalar makes fields of fieldType use scalar in the schemas generated afterwards,
+// replacing the built-in mapping. Scalars convert values both ways: Serialize turns the
+// values of records into responses, and ParseValue and ParseLiteral turn variables and
+// query literals into the values written and compared, returning nil for invalid ones.
+func RegisterScalar(fieldType schema.FieldType, scalar *graphql.Scalar) {
+	scalarsMu.Lock()
+	defer scalarsMu.Unlock()
+	scalars[fieldType] = scalar
+}
+
+// registeredScalar returns the scalar registered for fieldType
+func registeredScalar(fieldType schema.FieldType) (*graphql.Scalar, bool) {
+	scalarsMu.RLock()
+	defer scalarsMu.RUnlock()
+	scalar, ok := scalars[fieldType]
+	return scalar, ok
+}
+
 // MapFieldTypeToGraphQL converts RediORM field types to GraphQL types
 func MapFieldTypeToGraphQL(fieldType schema.FieldType) graphql.Type {
+	if scalar, ok := registeredScalar(fieldType); ok {
+		return scalar
+	}
 	switch fieldType {
 	case schema.FieldTypeString:
 		return graphql.String
-	case schema.FieldTypeInt, schema.FieldTypeInt64:
+	case schema.FieldTypeInt:
 		return graphql.Int
+	case schema.FieldTypeInt64:
+		return GraphQLBigInt // Custom scalar
 	case schema.FieldTypeFloat:
 		return graphql.Float
 	case schema.FieldTypeBool:
@@ -24,47 +57,202 @@ func MapFieldTypeToGraphQL(fieldType schema.FieldType) graphql.Type {
 	case schema.FieldTypeJSON:
 		return GraphQLJSON // Custom scalar
 	case schema.FieldTypeDecimal:
-		return graphql.Float // Map decimal to float for simplicity
+		return GraphQLDecimal // Custom scalar
+	case schema.FieldTypeBytes:
+		return GraphQLBase64 // Custom scalar
 	default:
 		return graphql.String
 	}
 }
 
-// GraphQLDateTime is a custom scalar for DateTime fields
+// dateTimeLayouts are the ISO 8601 forms DateTime inputs may take. Values without a time
+// zone are in UTC.
+var dateTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+// parseDateTime returns the time of an ISO 8601 string, or nil when it is not one
+func parseDateTime(value any) any {
+	text, ok := value.(string)
+	if !ok {
+		return nil
+	}
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t
+		}
+	}
+	return nil
+}
+
+// GraphQLDateTime is a custom scalar for DateTime fields. Values are written in RFC 3339
+// format whatever form the driver returns them in, and read from ISO 8601 strings.
 var GraphQLDateTime = graphql.NewScalar(graphql.ScalarConfig{
 	Name:        "DateTime",
 	Description: "DateTime scalar type represents a date and time in ISO 8601 format",
 	Serialize: func(value any) any {
-		// Convert to string in ISO format
-		return value
-	},
-	ParseValue: func(value any) any {
-		// Parse from variable value
-		return value
+		if t, err := utils.ToTime(value); err == nil {
+			return t.Format(time.RFC3339Nano)
+		}
+		return nil
 	},
+	ParseValue: parseDateTime,
 	ParseLiteral: func(valueAST ast.Value) any {
-		// Parse from AST
 		if stringValue, ok := valueAST.(*ast.StringValue); ok {
-			return stringValue.Value
+			return parseDateTime(stringValue.Value)
 		}
 		return nil
 	},
 })
 
-// GraphQLJSON is a custom scalar for JSON fields
+// GraphQLJSON is a custom scalar for JSON fields. Values are written as JSON, decoding the
+// text drivers return for JSON columns, and read from any JSON value: objects, lists,
+// strings, numbers and booleans.
 var GraphQLJSON = graphql.NewScalar(graphql.ScalarConfig{
 	Name:        "JSON",
 	Description: "JSON scalar type represents JSON data",
 	Serialize: func(value any) any {
-		return value
+		var text []byte
+		switch v := value.(type) {
+		case string:
+			text = []byte(v)
+		case []byte:
+			text = v
+		default:
+			return value
+		}
+		var decoded any
+		if err := utils.UnmarshalJSON(text, &decoded); err != nil {
+			// Text that is not JSON is a string value
+			return string(text)
+		}
+		return decoded
 	},
 	ParseValue: func(value any) any {
 		return value
 	},
+	ParseLiteral: parseJSONLiteral,
+})
+
+// parseJSONLiteral returns the value of a JSON literal of a query
+func parseJSONLiteral(valueAST ast.Value) any {
+	switch v := valueAST.(type) {
+	case *ast.StringValue:
+		return v.Value
+	case *ast.BooleanValue:
+		return v.Value
+	case *ast.IntValue:
+		if n, err := strconv.ParseInt(v.Value, 10, 64); err == nil {
+			return n
+		}
+		return json.Number(v.Value)
+	case *ast.FloatValue:
+		if f, err := strconv.ParseFloat(v.Value, 64); err == nil {
+			return f
+		}
+		return nil
+	case *ast.EnumValue:
+		return v.Value
+	case *ast.ListValue:
+		values := make([]any, len(v.Values))
+		for i, element := range v.Values {
+			values[i] = parseJSONLiteral(element)
+		}
+		return values
+	case *ast.ObjectValue:
+		object := make(map[string]any, len(v.Fields))
+		for _, field := range v.Fields {
+			object[field.Name.Value] = parseJSONLiteral(field.Value)
+		}
+		return object
+	}
+	return nil
+}
+
+// GraphQLBase64 is a custom scalar for binary fields
+var GraphQLBase64 = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Base64",
+	Description: "Base64 scalar type represents binary data as base64 encoded text",
+	Serialize: func(value any) any {
+		switch v := value.(type) {
+		case []byte:
+			return utils.EncodeBytes(v)
+		case string:
+			return utils.EncodeBytes([]byte(v))
+		}
+		return nil
+	},
+	ParseValue: func(value any) any {
+		// Invalid base64 parses to nil, which GraphQL reports as an invalid value
+		if data, err := utils.DecodeBytes(value); err == nil && data != nil {
+			return data
+		}
+		return nil
+	},
 	ParseLiteral: func(valueAST ast.Value) any {
-		// Parse from AST
 		if stringValue, ok := valueAST.(*ast.StringValue); ok {
-			return stringValue.Value
+			if data, err := utils.DecodeBytes(stringValue.Value); err == nil {
+				return data
+			}
+		}
+		return nil
+	},
+})
+
+// GraphQLDecimal is a custom scalar for decimal fields. Decimals are written as strings
+// holding their exact digits, and read from strings or numbers.
+var GraphQLDecimal = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Decimal",
+	Description: "Decimal scalar type represents an exact decimal number as a string",
+	Serialize: func(value any) any {
+		if text, err := utils.ToDecimalString(value); err == nil {
+			return text
+		}
+		return nil
+	},
+	ParseValue: func(value any) any {
+		if text, err := utils.ToDecimalString(value); err == nil {
+			return text
+		}
+		return nil
+	},
+	ParseLiteral: func(valueAST ast.Value) any {
+		// Literals keep the digits written in the query
+		switch v := valueAST.(type) {
+		case *ast.StringValue, *ast.IntValue, *ast.FloatValue:
+			if text, err := utils.ToDecimalString(v.GetValue()); err == nil {
+				return text
+			}
+		}
+		return nil
+	},
+})
+
+// GraphQLBigInt is a custom scalar for 64-bit integer fields, which do not fit the 32-bit
+// GraphQL Int. Values are written as numbers and read from numbers or strings.
+var GraphQLBigInt = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "BigInt",
+	Description: "BigInt scalar type represents a 64-bit integer",
+	Serialize: func(value any) any {
+		if n, err := utils.ToBigInt(value); err == nil {
+			return n
+		}
+		return nil
+	},
+	ParseValue: func(value any) any {
+		if n, err := utils.ToBigInt(value); err == nil {
+			return n
+		}
+		return nil
+	},
+	ParseLiteral: func(valueAST ast.Value) any {
+		switch v := valueAST.(type) {
+		case *ast.StringValue, *ast.IntValue:
+			if n, err := utils.ToBigInt(v.GetValue()); err == nil {
+				return n
+			}
 		}
 		return nil
 	},
@@ -121,19 +309,28 @@ func WhereInputField(fieldType schema.FieldType) *graphql.InputObjectFieldConfig
 
 // getFieldTypeName returns a string representation of the field type for naming
 func getFieldTypeName(fieldType schema.FieldType) string {
+	if scalar, ok := registeredScalar(fieldType); ok {
+		return scalar.Name()
+	}
 	switch fieldType {
 	case schema.FieldTypeString:
 		return "String"
-	case schema.FieldTypeInt, schema.FieldTypeInt64:
+	case schema.FieldTypeInt:
 		return "Int"
+	case schema.FieldTypeInt64:
+		return "BigInt"
 	case schema.FieldTypeFloat:
 		return "Float"
+	case schema.FieldTypeDecimal:
+		return "Decimal"
 	case schema.FieldTypeBool:
 		return "Boolean"
 	case schema.FieldTypeDateTime:
 		return "DateTime"
 	case schema.FieldTypeJSON:
 		return "JSON"
+	case schema.FieldTypeBytes:
+		return "Base64"
 	default:
 		return "String"
 	}
@@ -0,0 +1,266 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/rediwo/redi-orm/database"
+	"github.com/rediwo/redi-orm/migration"
+	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/types"
+)
+
+// Check statuses
+const (
+	StatusOK      = "ok"
+	StatusFail    = "fail"
+	StatusSkipped = "skipped"
+)
+
+// defaultTimeout bounds each database ping
+const defaultTimeout = 2 * time.Second
+
+// Config holds the configuration of a Checker
+type Config struct {
+	Database      types.Database
+	Schemas       map[string]*schema.Schema // Models the server expects to be registered
+	MigrationsDir string                    // Optional: report migrations in this directory that are not applied
+	Timeout       time.Duration             // Timeout of each database ping, 2 seconds by default
+}
+
+// Checker reports the health of a server's databases for liveness and readiness probes
+type Checker struct {
+	db            types.Database
+	schemas       map[string]*schema.Schema
+	migrationsDir string
+	timeout       time.Duration
+}
+
+// Report is the JSON body of the health endpoints
+type Report struct {
+	Status      string                   `json:"status"`
+	Database    DatabaseCheck            `json:"database"`
+	Connections map[string]DatabaseCheck `json:"connections,omitempty"`
+	Migrations  *MigrationsCheck         `json:"migrations,omitempty"`
+	Schemas     *SchemasCheck            `json:"schemas,omitempty"`
+	Timestamp   time.Time                `json:"timestamp"`
+}
+
+// DatabaseCheck reports whether a database answered a ping in time, and the state of its
+// connection when its health checks are started
+type DatabaseCheck struct {
+	Status     string           `json:"status"`
+	Driver     string           `json:"driver,omitempty"`
+	LatencyMs  int64            `json:"latencyMs"`
+	Error      string           `json:"error,omitempty"`
+	Connection *ConnectionCheck `json:"connection,omitempty"`
+}
+
+// ConnectionCheck reports the state of the connection of a database to its server
+type ConnectionCheck struct {
+	State      types.ConnectionState `json:"state"`
+	Since      time.Time             `json:"since"`
+	Reconnects int                   `json:"reconnects"`
+	Error      string                `json:"error,omitempty"`
+}
+
+// MigrationsCheck reports the migrations in the migrations directory that are not applied
+type MigrationsCheck struct {
+	Status  string   `json:"status"`
+	Pending []string `json:"pending,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// SchemasCheck reports the models that are not registered with their database
+type SchemasCheck struct {
+	Status     string   `json:"status"`
+	Registered int      `json:"registered"`
+	Missing    []string `json:"missing,omitempty"`
+}
+
+// NewChecker creates a health checker
+func NewChecker(config Config) *Checker {
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTimeout
+	}
+	return &Checker{
+		db:            config.Database,
+		schemas:       config.Schemas,
+		migrationsDir: config.MigrationsDir,
+		timeout:       config.Timeout,
+	}
+}
+
+// Live reports whether the database and the named connections answer a ping
+func (c *Checker) Live(ctx context.Context) *Report {
+	report := &Report{
+		Database:  c.ping(ctx, c.db),
+		Timestamp: time.Now().UTC(),
+	}
+	for _, name := range database.Connections() {
+		conn, err := database.Connection(name)
+		if err != nil {
+			continue
+		}
+		if report.Connections == nil {
+			report.Connections = make(map[string]DatabaseCheck)
+		}
+		report.Connections[name] = c.ping(ctx, conn)
+	}
+
+	report.Status = StatusOK
+	if report.Database.Status != StatusOK {
+		report.Status = StatusFail
+	}
+	for _, check := range report.Connections {
+		if check.Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+// Ready reports whether the server can serve requests: its databases answer a ping, all
+// migrations are applied and all models are registered
+func (c *Checker) Ready(ctx context.Context) *Report {
+	report := c.Live(ctx)
+	report.Migrations = c.checkMigrations()
+	report.Schemas = c.checkSchemas()
+
+	if report.Migrations.Status == StatusFail || report.Schemas.Status == StatusFail {
+		report.Status = StatusFail
+	}
+	return report
+}
+
+// ping pings db, failing when it does not answer within the timeout
+func (c *Checker) ping(ctx context.Context, db types.Database) DatabaseCheck {
+	if db == nil {
+		return DatabaseCheck{Status: StatusFail, Error: "no database configured"}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- db.Ping(ctx)
+	}()
+
+	check := DatabaseCheck{Driver: db.GetDriverType()}
+	if monitor, ok := db.(types.ConnectionMonitor); ok {
+		if status, ok := monitor.ConnectionStatus(); ok {
+			check.Connection = &ConnectionCheck{
+				State:      status.State,
+				Since:      status.Since.UTC(),
+				Reconnects: status.Reconnects,
+				Error:      status.Error,
+			}
+		}
+	}
+	select {
+	case err := <-errCh:
+		if err != nil {
+			check.Status = StatusFail
+			check.Error = err.Error()
+		} else {
+			check.Status = StatusOK
+		}
+	case <-ctx.Done():
+		check.Status = StatusFail
+		check.Error = "ping timed out after " + c.timeout.String()
+	}
+	check.LatencyMs = time.Since(start).Milliseconds()
+	return check
+}
+
+// checkMigrations looks for migrations in the migrations directory that are not applied.
+// It is skipped when there is no migrations directory or the database has no migrator.
+func (c *Checker) checkMigrations() *MigrationsCheck {
+	if c.migrationsDir == "" || c.db == nil || c.db.GetMigrator() == nil {
+		return &MigrationsCheck{Status: StatusSkipped}
+	}
+	if _, err := os.Stat(c.migrationsDir); err != nil {
+		return &MigrationsCheck{Status: StatusSkipped}
+	}
+
+	runner, err := migration.NewRunner(c.db, migration.NewFileManager(c.migrationsDir))
+	if err != nil {
+		return &MigrationsCheck{Status: StatusFail, Error: err.Error()}
+	}
+	pending, err := runner.PendingMigrations()
+	if err != nil {
+		return &MigrationsCheck{Status: StatusFail, Error: err.Error()}
+	}
+	if len(pending) == 0 {
+		return &MigrationsCheck{Status: StatusOK}
+	}
+
+	check := &MigrationsCheck{Status: StatusFail}
+	for _, m := range pending {
+		check.Pending = append(check.Pending, m.Version+"_"+m.Name)
+	}
+	return check
+}
+
+// checkSchemas checks that every model is registered with its database: the named
+// connection of its @@datasource, or the server database
+func (c *Checker) checkSchemas() *SchemasCheck {
+	check := &SchemasCheck{Status: StatusOK}
+	for name, s := range c.schemas {
+		db := c.db
+		if s.Datasource != "" {
+			conn, err := database.Connection(s.Datasource)
+			if err != nil {
+				check.Missing = append(check.Missing, name)
+				continue
+			}
+			db = conn
+		}
+		if db == nil {
+			check.Missing = append(check.Missing, name)
+			continue
+		}
+		if _, err := db.GetSchema(name); err != nil {
+			check.Missing = append(check.Missing, name)
+			continue
+		}
+		check.Registered++
+	}
+
+	if len(check.Missing) > 0 {
+		sort.Strings(check.Missing)
+		check.Status = StatusFail
+	}
+	return check
+}
+
+// LivenessHandler serves the liveness report, with status 503 when a database is down
+func (c *Checker) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, c.Live(r.Context()))
+	})
+}
+
+// ReadinessHandler serves the readiness report, with status 503 when the server is not ready
+func (c *Checker) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, c.Ready(r.Context()))
+	})
+}
+
+func writeReport(w http.ResponseWriter, report *Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if report.Status == StatusOK {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
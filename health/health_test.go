@@ -0,0 +1,153 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rediwo/redi-orm/database"
+	_ "github.com/rediwo/redi-orm/drivers/sqlite"
+	"github.com/rediwo/redi-orm/migration"
+	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/types"
+)
+
+func newTestDatabase(t *testing.T) database.Database {
+	t.Helper()
+	db, err := database.NewFromURI("sqlite://:memory:")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	if err := db.Connect(context.Background()); err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	return db
+}
+
+func getReport(t *testing.T, handler http.Handler, path string) (int, Report) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var report Report
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatalf("Invalid report %s: %v", w.Body.String(), err)
+	}
+	return w.Code, report
+}
+
+func TestChecker(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+
+	user := schema.New("User").AddField(schema.NewField("id").Int().PrimaryKey().AutoIncrement().Build())
+	post := schema.New("Post").AddField(schema.NewField("id").Int().PrimaryKey().AutoIncrement().Build())
+	if err := db.RegisterSchema("User", user); err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+
+	migrationsDir := filepath.Join(t.TempDir(), "migrations")
+	fileManager := migration.NewFileManager(migrationsDir)
+	if err := fileManager.WriteMigration(&types.MigrationFile{
+		Version: "20240101000000",
+		Name:    "create_users",
+		UpSQL:   "CREATE TABLE users (id INTEGER PRIMARY KEY)",
+		DownSQL: "DROP TABLE users",
+		Metadata: types.MigrationMetadata{
+			Version:  "20240101000000",
+			Name:     "create_users",
+			Checksum: "abc123",
+		},
+	}); err != nil {
+		t.Fatalf("Failed to write migration: %v", err)
+	}
+
+	checker := NewChecker(Config{
+		Database:      db,
+		Schemas:       map[string]*schema.Schema{"User": user, "Post": post},
+		MigrationsDir: migrationsDir,
+	})
+
+	code, report := getReport(t, checker.LivenessHandler(), "/healthz")
+	if code != http.StatusOK || report.Status != StatusOK || report.Database.Status != StatusOK {
+		t.Errorf("Expected a live database, got %d %+v", code, report)
+	}
+	if report.Database.Driver != "sqlite" {
+		t.Errorf("Expected the driver type, got %q", report.Database.Driver)
+	}
+	if report.Migrations != nil || report.Schemas != nil {
+		t.Errorf("Expected liveness to only check databases, got %+v", report)
+	}
+
+	code, report = getReport(t, checker.ReadinessHandler(), "/readyz")
+	if code != http.StatusServiceUnavailable || report.Status != StatusFail {
+		t.Errorf("Expected not ready, got %d %+v", code, report)
+	}
+	if len(report.Migrations.Pending) != 1 || report.Migrations.Pending[0] != "20240101000000_create_users" {
+		t.Errorf("Expected the pending migration, got %+v", report.Migrations)
+	}
+	if report.Schemas.Registered != 1 || len(report.Schemas.Missing) != 1 || report.Schemas.Missing[0] != "Post" {
+		t.Errorf("Expected Post to be missing, got %+v", report.Schemas)
+	}
+
+	// Apply the migration and register the missing model
+	runner, err := migration.NewRunner(db, fileManager)
+	if err != nil {
+		t.Fatalf("Failed to create runner: %v", err)
+	}
+	if err := runner.RunMigrations(context.Background()); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+	if err := db.RegisterSchema("Post", post); err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+
+	code, report = getReport(t, checker.ReadinessHandler(), "/readyz")
+	if code != http.StatusOK || report.Status != StatusOK {
+		t.Errorf("Expected ready, got %d %+v", code, report)
+	}
+	if report.Migrations.Status != StatusOK || report.Schemas.Status != StatusOK {
+		t.Errorf("Expected passing checks, got %+v %+v", report.Migrations, report.Schemas)
+	}
+}
+
+func TestCheckerDatabaseDown(t *testing.T) {
+	db := newTestDatabase(t)
+	db.Close()
+
+	checker := NewChecker(Config{Database: db})
+	code, report := getReport(t, checker.LivenessHandler(), "/healthz")
+	if code != http.StatusServiceUnavailable || report.Database.Status != StatusFail || report.Database.Error == "" {
+		t.Errorf("Expected a failing database check, got %d %+v", code, report)
+	}
+
+	code, report = getReport(t, checker.ReadinessHandler(), "/readyz")
+	if code != http.StatusServiceUnavailable || report.Migrations.Status != StatusSkipped {
+		t.Errorf("Expected not ready without migrations check, got %d %+v", code, report)
+	}
+}
+
+func TestCheckerConnectionState(t *testing.T) {
+	db := newTestDatabase(t)
+	defer db.Close()
+
+	checker := NewChecker(Config{Database: db})
+	if _, report := getReport(t, checker.LivenessHandler(), "/healthz"); report.Database.Connection != nil {
+		t.Errorf("Expected no connection state without health checks, got %+v", report.Database.Connection)
+	}
+
+	stop, err := database.StartHealthCheck(db, time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to start health checks: %v", err)
+	}
+	defer stop()
+
+	_, report := getReport(t, checker.LivenessHandler(), "/healthz")
+	if report.Database.Connection == nil || report.Database.Connection.State != types.ConnectionConnected {
+		t.Errorf("Expected a connected state, got %+v", report.Database.Connection)
+	}
+}
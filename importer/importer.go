@@ -0,0 +1,413 @@
+package importer
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rediwo/redi-orm/export"
+	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
+)
+
+// Strategies for records that already exist
+const (
+	// OnDuplicateError fails the import at the first duplicate
+	OnDuplicateError = "error"
+	// OnDuplicateSkip keeps existing records and skips the rows that duplicate them
+	OnDuplicateSkip = "skip"
+	// OnDuplicateUpsert updates existing records with the rows that duplicate them
+	OnDuplicateUpsert = "upsert"
+)
+
+// DefaultBatchSize is the number of rows inserted per statement by default
+const DefaultBatchSize = 500
+
+// Options configures an import
+type Options struct {
+	Format      string            // export.CSV or export.NDJSON
+	Mapping     map[string]string // Field of the model by column (CSV) or key (NDJSON); others are imported into the field of their name
+	BatchSize   int               // Rows inserted per statement, DefaultBatchSize by default
+	OnDuplicate string            // OnDuplicateError (default), OnDuplicateSkip or OnDuplicateUpsert
+
+	// Fields identifying the record a row duplicates with OnDuplicateUpsert, the primary key
+	// by default. They must be unique.
+	ConflictFields []string
+
+	// Optional: called after each batch with the progress so far
+	Progress func(Progress)
+}
+
+// Progress counts the rows of an import
+type Progress struct {
+	Rows     int // Rows read from the file
+	Inserted int // Records inserted, or inserted or updated with OnDuplicateUpsert
+	Skipped  int // Duplicates skipped with OnDuplicateSkip
+	Invalid  int // Rows that could not be converted or broke validation rules
+}
+
+// Result is the outcome of an import
+type Result struct {
+	Progress
+	Errors  []*RowError // Invalid rows, which were not imported
+	Ignored []string    // Columns or keys that are not fields of the model
+}
+
+// RowError is a row of the file that was not imported
+type RowError struct {
+	Line int // Line of the row in the file
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Import reads rows of modelName from r and inserts them in batches, so that files of any
+// size are imported without loading them into memory. Rows are converted to the types of
+// their fields and checked against the validation rules of the model; rows that fail are
+// reported in the Errors of the result and skipped. Database errors stop the import, with the
+// batches written before them kept.
+func Import(ctx context.Context, r io.Reader, db types.Database, modelName string, opts Options) (*Result, error) {
+	sch, err := db.GetSchema(modelName)
+	if err != nil {
+		return nil, err
+	}
+
+	im := &importer{
+		db:     db,
+		schema: sch,
+		opts:   opts,
+		result: &Result{},
+	}
+	if im.opts.BatchSize <= 0 {
+		im.opts.BatchSize = DefaultBatchSize
+	}
+	switch im.opts.OnDuplicate {
+	case "":
+		im.opts.OnDuplicate = OnDuplicateError
+	case OnDuplicateError, OnDuplicateSkip:
+	case OnDuplicateUpsert:
+		if len(im.opts.ConflictFields) == 0 {
+			im.opts.ConflictFields = sch.CompositeKey
+			if pk, err := sch.GetPrimaryKey(); err == nil && len(im.opts.ConflictFields) == 0 {
+				im.opts.ConflictFields = []string{pk.Name}
+			}
+		}
+		if len(im.opts.ConflictFields) == 0 {
+			return nil, fmt.Errorf("upserts of %s require conflict fields", modelName)
+		}
+	default:
+		return nil, fmt.Errorf("invalid duplicate strategy %q: must be %s, %s or %s", opts.OnDuplicate, OnDuplicateError, OnDuplicateSkip, OnDuplicateUpsert)
+	}
+	for column, field := range opts.Mapping {
+		if sch.GetFieldByName(field) == nil {
+			return nil, fmt.Errorf("cannot map %s to %s: no such field in %s", column, field, modelName)
+		}
+	}
+
+	switch opts.Format {
+	case export.CSV:
+		err = im.readCSV(ctx, r)
+	case export.NDJSON:
+		err = im.readNDJSON(ctx, r)
+	default:
+		return nil, fmt.Errorf("unsupported import format %q: must be %s or %s", opts.Format, export.CSV, export.NDJSON)
+	}
+	if err == nil {
+		err = im.flush(ctx)
+	}
+	slices.Sort(im.result.Ignored)
+	return im.result, err
+}
+
+// importer holds the state of an import
+type importer struct {
+	db      types.Database
+	schema  *schema.Schema
+	opts    Options
+	result  *Result
+	batch   []map[string]any
+	ignored map[string]bool
+}
+
+// field returns the field a column or key is imported into, or nil when it is ignored
+func (im *importer) field(column string) *schema.Field {
+	name := column
+	if mapped, ok := im.opts.Mapping[column]; ok {
+		name = mapped
+	}
+	field := im.schema.GetFieldByName(name)
+	if field == nil && !im.ignored[column] {
+		if im.ignored == nil {
+			im.ignored = make(map[string]bool)
+		}
+		im.ignored[column] = true
+		im.result.Ignored = append(im.result.Ignored, column)
+	}
+	return field
+}
+
+func (im *importer) readCSV(ctx context.Context, r io.Reader) error {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fields := make([]*schema.Field, len(header))
+	for i, column := range header {
+		fields[i] = im.field(strings.TrimSpace(column))
+	}
+
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line, _ := reader.FieldPos(0)
+
+		record := make(map[string]any, len(fields))
+		var rowErr error
+		for i, text := range row {
+			if i >= len(fields) || fields[i] == nil {
+				continue
+			}
+			value, omit, err := parseText(fields[i], text)
+			if err != nil {
+				rowErr = fmt.Errorf("%s: %w", fields[i].Name, err)
+				break
+			}
+			if !omit {
+				record[fields[i].Name] = value
+			}
+		}
+		if err := im.add(ctx, line, record, rowErr); err != nil {
+			return err
+		}
+	}
+}
+
+func (im *importer) readNDJSON(ctx context.Context, r io.Reader) error {
+	reader := bufio.NewReader(r)
+	for line := 1; ; line++ {
+		text, err := reader.ReadString('\n')
+		if errors.Is(err, io.EOF) && text == "" {
+			return nil
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if text = strings.TrimSpace(text); text == "" {
+			continue
+		}
+
+		var data map[string]any
+		var record map[string]any
+		rowErr := utils.UnmarshalJSON([]byte(text), &data)
+		if rowErr == nil {
+			record, rowErr = im.convertRecord(data)
+		}
+		if err := im.add(ctx, line, record, rowErr); err != nil {
+			return err
+		}
+	}
+}
+
+// convertRecord returns the record of the fields of the keys of an NDJSON object
+func (im *importer) convertRecord(data map[string]any) (map[string]any, error) {
+	record := make(map[string]any, len(data))
+	for key, value := range data {
+		field := im.field(key)
+		if field == nil {
+			continue
+		}
+		converted, err := convertJSON(field, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field.Name, err)
+		}
+		record[field.Name] = converted
+	}
+	return record, nil
+}
+
+// add validates the record of a row and adds it to the batch, writing the batch when it is full
+func (im *importer) add(ctx context.Context, line int, record map[string]any, rowErr error) error {
+	im.result.Rows++
+	if rowErr == nil {
+		rowErr = im.validate(record)
+	}
+	if rowErr != nil {
+		im.result.Invalid++
+		im.result.Errors = append(im.result.Errors, &RowError{Line: line, Err: rowErr})
+		return nil
+	}
+
+	im.batch = append(im.batch, record)
+	if len(im.batch) < im.opts.BatchSize {
+		return nil
+	}
+	return im.flush(ctx)
+}
+
+// validate checks that a record has the required fields and follows the validation rules
+func (im *importer) validate(record map[string]any) error {
+	for _, field := range im.schema.Fields {
+		if _, ok := record[field.Name]; ok || field.Nullable || field.Default != nil || field.AutoIncrement || field.UpdatedAt {
+			continue
+		}
+		return fmt.Errorf("missing required field %s", field.Name)
+	}
+	if im.opts.OnDuplicate == OnDuplicateUpsert {
+		for _, name := range im.opts.ConflictFields {
+			if record[name] == nil {
+				return fmt.Errorf("missing conflict field %s", name)
+			}
+		}
+	}
+	return im.schema.ValidateData(record)
+}
+
+// flush writes the records of the batch
+func (im *importer) flush(ctx context.Context) error {
+	if len(im.batch) == 0 {
+		return nil
+	}
+	written, skipped, err := Write(ctx, im.db, im.schema.Name, im.batch, im.opts.OnDuplicate, im.opts.ConflictFields)
+	im.result.Inserted += written
+	im.result.Skipped += skipped
+	im.batch = im.batch[:0]
+	if im.opts.Progress != nil {
+		im.opts.Progress(im.result.Progress)
+	}
+	return err
+}
+
+// Write inserts records of modelName in multi-row statements, and handles the records that
+// exist with the onDuplicate strategy. Upserts are written one record at a time, identified
+// by conflictFields. It returns the number of records written and of duplicates skipped.
+func Write(ctx context.Context, db types.Database, modelName string, records []map[string]any, onDuplicate string, conflictFields []string) (written, skipped int, err error) {
+	model := db.Model(modelName)
+	if onDuplicate == OnDuplicateUpsert {
+		for _, record := range records {
+			update := maps.Clone(record)
+			for _, name := range conflictFields {
+				delete(update, name)
+			}
+			if _, err := model.Upsert(record, update, conflictFields...).Exec(ctx); err != nil {
+				return written, skipped, err
+			}
+			written++
+		}
+		return written, skipped, nil
+	}
+
+	// Multi-row statements need the same fields in every row
+	maxParameters := db.GetCapabilities().MaxParameters()
+	for start := 0; start < len(records); {
+		fields := slices.Sorted(maps.Keys(records[start]))
+		size := len(records)
+		if maxParameters > 0 && len(fields) > 0 {
+			size = max(maxParameters/len(fields), 1)
+		}
+		end := start + 1
+		for end < len(records) && end-start < size && slices.Equal(slices.Sorted(maps.Keys(records[end])), fields) {
+			end++
+		}
+
+		rows := make([]any, 0, end-start-1)
+		for _, record := range records[start+1 : end] {
+			rows = append(rows, record)
+		}
+		query := model.Insert(records[start])
+		if len(rows) > 0 {
+			query = query.Values(rows...)
+		}
+		if onDuplicate == OnDuplicateSkip {
+			query = query.OnConflict(types.ConflictDoNothing)
+		}
+		result, err := query.Exec(ctx)
+		if err != nil {
+			return written, skipped, err
+		}
+		inserted := end - start
+		if onDuplicate == OnDuplicateSkip {
+			inserted = int(result.RowsAffected)
+		}
+		written += inserted
+		skipped += end - start - inserted
+		start = end
+	}
+	return written, skipped, nil
+}
+
+// parseText converts the text of a CSV cell to the type of its field. Empty cells are
+// omitted, so that the field gets its default, except for required strings.
+func parseText(field *schema.Field, text string) (value any, omit bool, err error) {
+	if text == "" {
+		return nil, field.Type != schema.FieldTypeString || field.Nullable, nil
+	}
+	switch field.Type {
+	case schema.FieldTypeInt, schema.FieldTypeInt64:
+		value, err = strconv.ParseInt(strings.TrimSpace(text), 10, 64)
+	case schema.FieldTypeFloat:
+		value, err = strconv.ParseFloat(strings.TrimSpace(text), 64)
+	case schema.FieldTypeBool:
+		value, err = strconv.ParseBool(strings.TrimSpace(text))
+	case schema.FieldTypeDateTime, schema.FieldTypeTimestamp:
+		value, err = utils.ToTime(strings.TrimSpace(text))
+	case schema.FieldTypeDecimal, schema.FieldTypeDecimal128:
+		value, err = utils.ToDecimalString(strings.TrimSpace(text))
+	case schema.FieldTypeBytes:
+		value, err = utils.DecodeBytes(text)
+	case schema.FieldTypeJSON, schema.FieldTypeDocument, schema.FieldTypeArray,
+		schema.FieldTypeStringArray, schema.FieldTypeIntArray, schema.FieldTypeInt64Array, schema.FieldTypeFloatArray,
+		schema.FieldTypeBoolArray, schema.FieldTypeDecimalArray, schema.FieldTypeDateTimeArray:
+		// Nested values are exported as JSON
+		err = utils.UnmarshalJSON([]byte(text), &value)
+	default:
+		value = text
+	}
+	return value, false, err
+}
+
+// convertJSON converts a value of an NDJSON record to the type of its field
+func convertJSON(field *schema.Field, value any) (any, error) {
+	switch field.Type {
+	case schema.FieldTypeInt, schema.FieldTypeInt64:
+		if f, ok := value.(float64); ok {
+			if f != math.Trunc(f) {
+				return nil, fmt.Errorf("%v is not an integer", f)
+			}
+			return int64(f), nil
+		}
+	case schema.FieldTypeDateTime, schema.FieldTypeTimestamp:
+		if text, ok := value.(string); ok {
+			return utils.ToTime(text)
+		}
+	case schema.FieldTypeBytes:
+		if value != nil {
+			return utils.DecodeBytes(value)
+		}
+	}
+	return value, nil
+}
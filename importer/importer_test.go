@@ -0,0 +1,158 @@
+package importer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rediwo/redi-orm/database"
+	_ "github.com/rediwo/redi-orm/drivers/sqlite"
+	"github.com/rediwo/redi-orm/export"
+	"github.com/rediwo/redi-orm/types"
+)
+
+func newTestDatabase(t *testing.T) database.Database {
+	t.Helper()
+	ctx := context.Background()
+	db, err := database.NewFromURI("sqlite://:memory:")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	if err := db.Connect(ctx); err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := db.LoadSchema(ctx, `
+model User {
+  id    Int    @id @default(autoincrement())
+  name  String
+  email String @unique @email
+  age   Int?
+}
+`); err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	if err := db.SyncSchemas(ctx); err != nil {
+		t.Fatalf("Failed to sync schemas: %v", err)
+	}
+	return db
+}
+
+func findUsers(t *testing.T, db database.Database) []map[string]any {
+	t.Helper()
+	var users []map[string]any
+	if err := db.Model("User").Select().OrderBy("id", types.ASC).FindMany(context.Background(), &users); err != nil {
+		t.Fatalf("Failed to find users: %v", err)
+	}
+	return users
+}
+
+func TestImportCSV(t *testing.T) {
+	db := newTestDatabase(t)
+	file := `Full Name,Email,age,notes
+Alice,alice@example.com,30,first
+Bob,not-an-email,25,
+"Carol, C",carol@example.com,old,
+Dave,dave@example.com,,
+Eve,eve@example.com,41,
+`
+	var batches []Progress
+	result, err := Import(context.Background(), strings.NewReader(file), db, "User", Options{
+		Format:    export.CSV,
+		Mapping:   map[string]string{"Full Name": "name", "Email": "email"},
+		BatchSize: 2,
+		Progress:  func(progress Progress) { batches = append(batches, progress) },
+	})
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if result.Rows != 5 || result.Inserted != 3 || result.Invalid != 2 {
+		t.Errorf("Expected 3 of 5 rows imported and 2 invalid, got %+v", result.Progress)
+	}
+	if len(result.Errors) != 2 || result.Errors[0].Line != 3 || result.Errors[1].Line != 4 {
+		t.Errorf("Expected the invalid rows at lines 3 and 4, got %v", result.Errors)
+	}
+	if len(result.Ignored) != 1 || result.Ignored[0] != "notes" {
+		t.Errorf("Expected the notes column to be ignored, got %v", result.Ignored)
+	}
+	if len(batches) != 2 || batches[0].Inserted != 2 {
+		t.Errorf("Expected progress after each batch of 2 rows, got %+v", batches)
+	}
+
+	users := findUsers(t, db)
+	if len(users) != 3 || users[1]["name"] != "Dave" || users[1]["age"] != nil {
+		t.Errorf("Expected Alice, Dave without an age and Eve, got %v", users)
+	}
+
+	// Duplicates fail the import by default, and are skipped on request
+	if _, err := Import(context.Background(), strings.NewReader(file), db, "User", Options{
+		Format:  export.CSV,
+		Mapping: map[string]string{"Full Name": "name", "Email": "email"},
+	}); err == nil {
+		t.Error("Expected an error for duplicates")
+	}
+	result, err = Import(context.Background(), strings.NewReader(file+"Frank,frank@example.com,50,\n"), db, "User", Options{
+		Format:      export.CSV,
+		Mapping:     map[string]string{"Full Name": "name", "Email": "email"},
+		OnDuplicate: OnDuplicateSkip,
+	})
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if result.Inserted != 1 || result.Skipped != 3 {
+		t.Errorf("Expected 1 row imported and 3 duplicates skipped, got %+v", result.Progress)
+	}
+}
+
+func TestImportNDJSONUpsert(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := context.Background()
+	if _, err := db.Model("User").Insert(map[string]any{"name": "Alice", "email": "alice@example.com", "age": 30}).Exec(ctx); err != nil {
+		t.Fatalf("Failed to insert user: %v", err)
+	}
+
+	file := `{"name":"Alicia","email":"alice@example.com","age":31}
+
+{"name":"Bob","email":"bob@example.com","age":25.5}
+{"name":"Carol","email":"carol@example.com"}
+{"name":"Dave"
+`
+	result, err := Import(ctx, strings.NewReader(file), db, "User", Options{
+		Format:         export.NDJSON,
+		OnDuplicate:    OnDuplicateUpsert,
+		ConflictFields: []string{"email"},
+	})
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if result.Rows != 4 || result.Inserted != 2 || result.Invalid != 2 {
+		t.Errorf("Expected 2 of 4 rows imported and 2 invalid, got %+v", result.Progress)
+	}
+	if len(result.Errors) != 2 || result.Errors[0].Line != 3 || result.Errors[1].Line != 5 {
+		t.Errorf("Expected the invalid rows at lines 3 and 5, got %v", result.Errors)
+	}
+
+	users := findUsers(t, db)
+	if len(users) != 2 || users[0]["name"] != "Alicia" || users[1]["name"] != "Carol" {
+		t.Errorf("Expected Alice to be updated and Carol to be inserted, got %v", users)
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := context.Background()
+
+	for _, opts := range []Options{
+		{Format: "xml"},
+		{Format: export.CSV, OnDuplicate: "replace"},
+		{Format: export.CSV, Mapping: map[string]string{"Email": "mail"}},
+	} {
+		if _, err := Import(ctx, strings.NewReader(""), db, "User", opts); err == nil {
+			t.Errorf("Expected an error for %+v", opts)
+		}
+	}
+	if _, err := Import(ctx, strings.NewReader(""), db, "Missing", Options{Format: export.CSV}); err == nil {
+		t.Error("Expected an error for an unknown model")
+	}
+}
@@ -0,0 +1,52 @@
+package logger
+
+import "strings"
+
+// ComponentLogger prefixes the messages of a logger with the name of the component that
+// logs them, such as a database driver
+type ComponentLogger struct {
+	Logger
+	component string
+}
+
+// WithComponent returns a logger writing to l with messages prefixed by "[component] ".
+// A nil l logs nothing.
+func WithComponent(l Logger, component string) *ComponentLogger {
+	if l == nil {
+		l = NewNullLogger()
+	}
+	if c, ok := l.(*ComponentLogger); ok {
+		l = c.Logger
+	}
+	return &ComponentLogger{Logger: l, component: component}
+}
+
+// Component returns the name of the component
+func (c *ComponentLogger) Component() string {
+	return c.component
+}
+
+// Debug logs a debug message
+func (c *ComponentLogger) Debug(format string, args ...any) {
+	c.Logger.Debug(c.prefix(format), args...)
+}
+
+// Info logs an info message
+func (c *ComponentLogger) Info(format string, args ...any) {
+	c.Logger.Info(c.prefix(format), args...)
+}
+
+// Warn logs a warning message
+func (c *ComponentLogger) Warn(format string, args ...any) {
+	c.Logger.Warn(c.prefix(format), args...)
+}
+
+// Error logs an error message
+func (c *ComponentLogger) Error(format string, args ...any) {
+	c.Logger.Error(c.prefix(format), args...)
+}
+
+// prefix prepends the component name, escaped for use in a format string
+func (c *ComponentLogger) prefix(format string) string {
+	return "[" + strings.ReplaceAll(c.component, "%", "%%") + "] " + format
+}
@@ -0,0 +1,21 @@
+package logger
+
+import "context"
+
+type correlationIDKey struct{}
+
+// WithCorrelationID returns a context carrying the ID of the request it belongs to. The
+// statements a database runs with that context are logged with the ID, so they can be
+// matched with the request.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationID returns the ID stored by WithCorrelationID, or an empty string
+func CorrelationID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
@@ -134,3 +134,33 @@ func TestLogLevelString(t *testing.T) {
 		})
 	}
 }
+
+func TestComponentLogger(t *testing.T) {
+	var buf bytes.Buffer
+	base := NewDefaultLogger("TestApp")
+	base.SetOutput(&buf)
+	base.SetLevel(LogLevelInfo)
+
+	logger := WithComponent(base, "mongodb")
+	logger.Info("Found %d documents", 3)
+	if output := buf.String(); !strings.Contains(output, "[mongodb] Found 3 documents") {
+		t.Errorf("Expected the component to prefix the message, got %q", output)
+	}
+
+	// Levels are those of the wrapped logger
+	buf.Reset()
+	logger.Debug("This should not appear")
+	if buf.Len() > 0 {
+		t.Errorf("Expected no debug output, got %q", buf.String())
+	}
+
+	// Wrapping again replaces the component
+	buf.Reset()
+	WithComponent(logger, "sqlite").Warn("100%% done")
+	if output := buf.String(); !strings.Contains(output, "[sqlite] 100% done") || strings.Contains(output, "mongodb") {
+		t.Errorf("Expected only the new component, got %q", output)
+	}
+
+	// Without a logger nothing is logged
+	WithComponent(nil, "mysql").Error("Ignored")
+}
@@ -0,0 +1,317 @@
+package masking
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/rediwo/redi-orm/schema"
+)
+
+// Strategy selects how a sensitive value is masked
+type Strategy string
+
+const (
+	// StrategyFull replaces the whole value: "****"
+	StrategyFull Strategy = "full"
+	// StrategyEmail keeps the first character and the domain: "a***@example.com"
+	StrategyEmail Strategy = "email"
+	// StrategyPartial keeps the last four characters: "****1234"
+	StrategyPartial Strategy = "partial"
+)
+
+// maskChars replaces the hidden part of a masked value
+const maskChars = "****"
+
+// Policy marks model fields as sensitive. Server outputs (GraphQL, REST and MCP)
+// mask those fields unless the caller has one of the privileged roles.
+type Policy struct {
+	// Fields maps "Model.field" (or "*.field" for every model) to a masking strategy
+	Fields map[string]Strategy `json:"fields"`
+	// PrivilegedRoles see sensitive values unmasked
+	PrivilegedRoles []string `json:"privilegedRoles"`
+	// RoleHeader names the request header carrying the caller's comma-separated roles.
+	// It must only be set when a trusted proxy controls that header.
+	RoleHeader string `json:"roleHeader,omitempty"`
+}
+
+// SchemaSource resolves model schemas, used to follow relations in nested results
+type SchemaSource interface {
+	GetSchema(modelName string) (*schema.Schema, error)
+}
+
+// LoadPolicy reads a policy from a JSON file
+func LoadPolicy(path string) (*Policy, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read masking policy: %w", err)
+	}
+
+	var policy Policy
+	if err := json.Unmarshal(content, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse masking policy: %w", err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Validate checks field keys and strategies
+func (p *Policy) Validate() error {
+	for key, strategy := range p.Fields {
+		model, field, ok := strings.Cut(key, ".")
+		if !ok || model == "" || field == "" {
+			return fmt.Errorf("invalid masked field %q: expected Model.field", key)
+		}
+		switch strategy {
+		case StrategyFull, StrategyEmail, StrategyPartial:
+		default:
+			return fmt.Errorf("unknown masking strategy %q for field %s", strategy, key)
+		}
+	}
+	return nil
+}
+
+type rolesKey struct{}
+
+// WithRoles returns a context carrying the caller's roles
+func WithRoles(ctx context.Context, roles ...string) context.Context {
+	return context.WithValue(ctx, rolesKey{}, append(RolesFromContext(ctx), roles...))
+}
+
+// RolesFromContext returns the caller's roles stored by WithRoles
+func RolesFromContext(ctx context.Context) []string {
+	roles, _ := ctx.Value(rolesKey{}).([]string)
+	return roles
+}
+
+// Middleware adds the roles of the configured role header to the request context
+func (p *Policy) Middleware(next http.Handler) http.Handler {
+	if p == nil || p.RoleHeader == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var roles []string
+		for _, role := range strings.Split(r.Header.Get(p.RoleHeader), ",") {
+			if role = strings.TrimSpace(role); role != "" {
+				roles = append(roles, role)
+			}
+		}
+		if len(roles) > 0 {
+			r = r.WithContext(WithRoles(r.Context(), roles...))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Privileged reports whether the caller may see unmasked values.
+// A nil policy masks nothing.
+func (p *Policy) Privileged(ctx context.Context) bool {
+	if p == nil || len(p.Fields) == 0 {
+		return true
+	}
+	for _, role := range RolesFromContext(ctx) {
+		if slices.Contains(p.PrivilegedRoles, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldStrategy returns the strategy of a sensitive field
+func (p *Policy) FieldStrategy(modelName, fieldName string) (Strategy, bool) {
+	if p == nil {
+		return "", false
+	}
+	if strategy, ok := p.Fields[modelName+"."+fieldName]; ok {
+		return strategy, true
+	}
+	strategy, ok := p.Fields["*."+fieldName]
+	return strategy, ok
+}
+
+// MaskValue masks a single field value unless the caller is privileged
+func (p *Policy) MaskValue(ctx context.Context, modelName, fieldName string, value any) any {
+	strategy, ok := p.FieldStrategy(modelName, fieldName)
+	if !ok || p.Privileged(ctx) {
+		return value
+	}
+	return Mask(strategy, value)
+}
+
+// Apply masks the sensitive fields of a result of the given model. It accepts
+// records, lists of records and aggregate results, follows relations through
+// the schemas, and returns masked copies without modifying data.
+func (p *Policy) Apply(ctx context.Context, schemas SchemaSource, modelName string, data any) any {
+	if p.Privileged(ctx) {
+		return data
+	}
+	return p.apply(schemas, modelName, data)
+}
+
+func (p *Policy) apply(schemas SchemaSource, modelName string, data any) any {
+	switch v := data.(type) {
+	case map[string]any:
+		return p.applyRecord(schemas, modelName, v)
+	case []map[string]any:
+		masked := make([]map[string]any, len(v))
+		for i, record := range v {
+			masked[i] = p.applyRecord(schemas, modelName, record)
+		}
+		return masked
+	case []any:
+		masked := make([]any, len(v))
+		for i, item := range v {
+			masked[i] = p.apply(schemas, modelName, item)
+		}
+		return masked
+	default:
+		return data
+	}
+}
+
+func (p *Policy) applyRecord(schemas SchemaSource, modelName string, record map[string]any) map[string]any {
+	var relations map[string]schema.Relation
+	if schemas != nil {
+		if modelSchema, err := schemas.GetSchema(modelName); err == nil {
+			relations = modelSchema.Relations
+		}
+	}
+
+	masked := make(map[string]any, len(record))
+	for key, value := range record {
+		if relation, ok := relations[key]; ok {
+			masked[key] = p.apply(schemas, relation.RelatedModel(record), value)
+		} else if strings.HasPrefix(key, "_") && key != "_count" {
+			// Aggregates such as _min and _max hold field values of the same model
+			masked[key] = p.apply(schemas, modelName, value)
+		} else if strategy, ok := p.FieldStrategy(modelName, key); ok {
+			masked[key] = Mask(strategy, value)
+		} else {
+			masked[key] = value
+		}
+	}
+	return masked
+}
+
+// Redact masks the sensitive fields of the arguments of an operation on the given model,
+// whatever the roles of the caller, for records such as audit logs. Unlike Apply it walks
+// every nested value, so filters like {"email": {"contains": "x"}} are masked as well.
+func (p *Policy) Redact(schemas SchemaSource, modelName string, data any) any {
+	if p == nil || len(p.Fields) == 0 {
+		return data
+	}
+
+	switch v := data.(type) {
+	case map[string]any:
+		var relations map[string]schema.Relation
+		if schemas != nil {
+			if modelSchema, err := schemas.GetSchema(modelName); err == nil {
+				relations = modelSchema.Relations
+			}
+		}
+		redacted := make(map[string]any, len(v))
+		for key, value := range v {
+			if strategy, ok := p.FieldStrategy(modelName, key); ok {
+				redacted[key] = redactValue(strategy, value)
+			} else if relation, ok := relations[key]; ok {
+				redacted[key] = p.Redact(schemas, relation.RelatedModel(v), value)
+			} else {
+				// Operators such as AND, and nested writes such as create, keep the model
+				redacted[key] = p.Redact(schemas, modelName, value)
+			}
+		}
+		return redacted
+	case []any:
+		redacted := make([]any, len(v))
+		for i, item := range v {
+			redacted[i] = p.Redact(schemas, modelName, item)
+		}
+		return redacted
+	default:
+		return data
+	}
+}
+
+// redactValue masks the value of a sensitive field, and the values of the filter
+// operators it may hold
+func redactValue(strategy Strategy, value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		redacted := make(map[string]any, len(v))
+		for key, item := range v {
+			redacted[key] = redactValue(strategy, item)
+		}
+		return redacted
+	case []any:
+		redacted := make([]any, len(v))
+		for i, item := range v {
+			redacted[i] = redactValue(strategy, item)
+		}
+		return redacted
+	default:
+		return Mask(strategy, value)
+	}
+}
+
+// Mask applies a strategy to a value. Values that are not strings are redacted to nil.
+func Mask(strategy Strategy, value any) any {
+	s, ok := value.(string)
+	if !ok {
+		return nil
+	}
+
+	switch strategy {
+	case StrategyEmail:
+		local, domain, found := strings.Cut(s, "@")
+		if !found || local == "" {
+			return maskChars
+		}
+		first := []rune(local)[0]
+		return string(first) + "***@" + domain
+	case StrategyPartial:
+		runes := []rune(s)
+		if len(runes) <= 4 {
+			return maskChars
+		}
+		return maskChars + string(runes[len(runes)-4:])
+	default:
+		return maskChars
+	}
+}
+
+// Anonymize replaces a value with a pseudonym derived from it and key, for copies of data
+// such as staging databases. Unlike Mask, equal values get equal pseudonyms and different
+// values different ones, so unique constraints hold. The strategy keeps the same parts as
+// Mask: the domain of emails and the last four characters of partial values. Values that
+// are not strings are redacted to nil.
+func Anonymize(strategy Strategy, value any, key []byte) any {
+	s, ok := value.(string)
+	if !ok {
+		return nil
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(s))
+	pseudonym := hex.EncodeToString(mac.Sum(nil))[:16]
+
+	switch strategy {
+	case StrategyEmail:
+		if _, domain, found := strings.Cut(s, "@"); found {
+			return pseudonym + "@" + domain
+		}
+	case StrategyPartial:
+		if runes := []rune(s); len(runes) > 4 {
+			return pseudonym + string(runes[len(runes)-4:])
+		}
+	}
+	return pseudonym
+}
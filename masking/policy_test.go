@@ -0,0 +1,205 @@
+package masking
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rediwo/redi-orm/schema"
+)
+
+type testSchemas map[string]*schema.Schema
+
+func (s testSchemas) GetSchema(modelName string) (*schema.Schema, error) {
+	if sch, ok := s[modelName]; ok {
+		return sch, nil
+	}
+	return nil, os.ErrNotExist
+}
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		strategy Strategy
+		value    any
+		expected any
+	}{
+		{StrategyEmail, "alice@example.com", "a***@example.com"},
+		{StrategyEmail, "not-an-email", "****"},
+		{StrategyPartial, "4111111111111111", "****1111"},
+		{StrategyPartial, "123", "****"},
+		{StrategyFull, "secret", "****"},
+		{StrategyFull, 42, nil},
+		{StrategyEmail, nil, nil},
+	}
+
+	for _, tt := range tests {
+		if got := Mask(tt.strategy, tt.value); got != tt.expected {
+			t.Errorf("Mask(%s, %v) = %v, expected %v", tt.strategy, tt.value, got, tt.expected)
+		}
+	}
+}
+
+func TestAnonymize(t *testing.T) {
+	key := []byte("key")
+	email := Anonymize(StrategyEmail, "alice@example.com", key).(string)
+	if !strings.HasSuffix(email, "@example.com") || strings.HasPrefix(email, "alice") {
+		t.Errorf("Expected a pseudonym at the same domain, got %s", email)
+	}
+	if again := Anonymize(StrategyEmail, "alice@example.com", key); again != email {
+		t.Errorf("Expected equal values to get equal pseudonyms, got %v and %s", again, email)
+	}
+	if other := Anonymize(StrategyEmail, "bob@example.com", key); other == email {
+		t.Errorf("Expected different values to get different pseudonyms, got %v", other)
+	}
+	if other := Anonymize(StrategyEmail, "alice@example.com", []byte("other")); other == email {
+		t.Errorf("Expected pseudonyms to depend on the key, got %v", other)
+	}
+	if card := Anonymize(StrategyPartial, "4111111111111111", key).(string); !strings.HasSuffix(card, "1111") || strings.HasPrefix(card, "4111") {
+		t.Errorf("Expected the last four characters to be kept, got %s", card)
+	}
+	if secret := Anonymize(StrategyFull, "secret", key).(string); len(secret) != 16 {
+		t.Errorf("Expected a 16 character pseudonym, got %s", secret)
+	}
+	if got := Anonymize(StrategyFull, 42, key); got != nil {
+		t.Errorf("Expected values that are not strings to be redacted, got %v", got)
+	}
+}
+
+func TestPolicyApply(t *testing.T) {
+	schemas := testSchemas{
+		"User": schema.New("User").AddRelation("posts", schema.Relation{Type: schema.RelationOneToMany, Model: "Post"}),
+		"Post": schema.New("Post"),
+	}
+	policy := &Policy{
+		Fields: map[string]Strategy{
+			"User.email": StrategyEmail,
+			"*.secret":   StrategyFull,
+		},
+		PrivilegedRoles: []string{"admin"},
+	}
+
+	record := map[string]any{
+		"name":  "Alice",
+		"email": "alice@example.com",
+		"posts": []any{map[string]any{"title": "Hello", "secret": "draft"}},
+		"_max":  map[string]any{"email": "zed@example.com"},
+	}
+
+	masked := policy.Apply(context.Background(), schemas, "User", []map[string]any{record}).([]map[string]any)[0]
+	if masked["email"] != "a***@example.com" {
+		t.Errorf("Expected masked email, got %v", masked["email"])
+	}
+	if masked["name"] != "Alice" {
+		t.Errorf("Expected name to be unmasked, got %v", masked["name"])
+	}
+	if post := masked["posts"].([]any)[0].(map[string]any); post["secret"] != "****" || post["title"] != "Hello" {
+		t.Errorf("Expected related post secret to be masked, got %v", post)
+	}
+	if aggregate := masked["_max"].(map[string]any); aggregate["email"] != "z***@example.com" {
+		t.Errorf("Expected aggregate email to be masked, got %v", aggregate["email"])
+	}
+	if record["email"] != "alice@example.com" {
+		t.Error("Apply must not modify the original record")
+	}
+
+	ctx := WithRoles(context.Background(), "admin")
+	unmasked := policy.Apply(ctx, schemas, "User", record).(map[string]any)
+	if unmasked["email"] != "alice@example.com" {
+		t.Errorf("Expected privileged caller to see the email, got %v", unmasked["email"])
+	}
+
+	var nilPolicy *Policy
+	if got := nilPolicy.MaskValue(context.Background(), "User", "email", "alice@example.com"); got != "alice@example.com" {
+		t.Errorf("Expected nil policy to mask nothing, got %v", got)
+	}
+}
+
+func TestPolicyRedact(t *testing.T) {
+	schemas := testSchemas{
+		"User": schema.New("User").AddRelation("posts", schema.Relation{Type: schema.RelationOneToMany, Model: "Post"}),
+		"Post": schema.New("Post"),
+	}
+	policy := &Policy{
+		Fields:          map[string]Strategy{"User.email": StrategyEmail, "Post.secret": StrategyFull},
+		PrivilegedRoles: []string{"admin"},
+	}
+
+	arguments := map[string]any{
+		"model": "User",
+		"data": map[string]any{
+			"name":  "Alice",
+			"email": "alice@example.com",
+			"posts": map[string]any{"create": []any{map[string]any{"title": "Hello", "secret": "draft"}}},
+		},
+		"where": map[string]any{"OR": []any{map[string]any{"email": map[string]any{"in": []any{"bob@example.com"}}}}},
+	}
+
+	redacted := policy.Redact(schemas, "User", arguments).(map[string]any)
+	data := redacted["data"].(map[string]any)
+	if data["email"] != "a***@example.com" || data["name"] != "Alice" {
+		t.Errorf("Expected the email only to be masked, got %v", data)
+	}
+	if post := data["posts"].(map[string]any)["create"].([]any)[0].(map[string]any); post["secret"] != "****" || post["title"] != "Hello" {
+		t.Errorf("Expected the secret of the nested post to be masked, got %v", post)
+	}
+	filter := redacted["where"].(map[string]any)["OR"].([]any)[0].(map[string]any)["email"].(map[string]any)
+	if in := filter["in"].([]any); in[0] != "b***@example.com" {
+		t.Errorf("Expected the email filter to be masked, got %v", filter)
+	}
+	if arguments["data"].(map[string]any)["email"] != "alice@example.com" {
+		t.Error("Redact must not modify the arguments")
+	}
+}
+
+func TestPolicyMiddleware(t *testing.T) {
+	policy := &Policy{
+		Fields:          map[string]Strategy{"User.email": StrategyEmail},
+		PrivilegedRoles: []string{"admin"},
+		RoleHeader:      "X-Role",
+	}
+
+	var privileged bool
+	handler := policy.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		privileged = policy.Privileged(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Role", "viewer, admin")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if !privileged {
+		t.Error("Expected admin role from header to be privileged")
+	}
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if privileged {
+		t.Error("Expected request without roles to be unprivileged")
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "masking.json")
+	content := `{"fields": {"User.email": "email"}, "privilegedRoles": ["admin"], "roleHeader": "X-Role"}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatalf("LoadPolicy failed: %v", err)
+	}
+	if strategy, ok := policy.FieldStrategy("User", "email"); !ok || strategy != StrategyEmail {
+		t.Errorf("Expected email strategy, got %q", strategy)
+	}
+
+	invalid := filepath.Join(t.TempDir(), "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"fields": {"User.email": "scramble"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPolicy(invalid); err == nil {
+		t.Error("Expected error for unknown strategy")
+	}
+}
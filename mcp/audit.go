@@ -0,0 +1,423 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rediwo/redi-orm/logger"
+	"github.com/rediwo/redi-orm/masking"
+	"github.com/rediwo/redi-orm/types"
+)
+
+// DefaultAuditTable is the table of the table audit sink when none is named
+const DefaultAuditTable = "mcp_audit_log"
+
+// AuditEntry records a tool call
+type AuditEntry struct {
+	Time       time.Time       `json:"time"`
+	Tool       string          `json:"tool"`
+	Access     string          `json:"access"` // "read" or "write"
+	Arguments  json.RawMessage `json:"arguments,omitempty"`
+	Caller     string          `json:"caller,omitempty"`
+	Roles      []string        `json:"roles,omitempty"`
+	Session    string          `json:"session,omitempty"`
+	DurationMs int64           `json:"durationMs"`
+	Rows       int             `json:"rows"` // Records returned or changed
+	Error      string          `json:"error,omitempty"`
+}
+
+// AuditSink stores the entries of the audit log
+type AuditSink interface {
+	Record(ctx context.Context, entry AuditEntry) error
+	Close() error
+}
+
+// NewAuditSink creates the sink described by spec:
+//   - "https://..." or "http://..." posts each entry as JSON to a webhook
+//   - "table:<name>" inserts entries into a table of db, created when missing
+//     ("table" alone uses DefaultAuditTable)
+//   - "file:<path>" or a plain path appends entries to a file as JSON lines
+//
+// l logs the entries a webhook fails to receive.
+func NewAuditSink(spec string, db types.Database, l logger.Logger) (AuditSink, error) {
+	switch {
+	case spec == "":
+		return nil, fmt.Errorf("audit sink is empty")
+	case strings.HasPrefix(spec, "http://"), strings.HasPrefix(spec, "https://"):
+		return NewWebhookAuditSink(spec, l), nil
+	case spec == "table", strings.HasPrefix(spec, "table:"):
+		table := strings.TrimPrefix(strings.TrimPrefix(spec, "table"), ":")
+		if table == "" {
+			table = DefaultAuditTable
+		}
+		return NewTableAuditSink(context.Background(), db, table)
+	default:
+		return NewFileAuditSink(strings.TrimPrefix(spec, "file:"))
+	}
+}
+
+// FileAuditSink appends entries to a file, one JSON object per line
+type FileAuditSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileAuditSink opens path for appending, creating it when missing
+func NewFileAuditSink(path string) (*FileAuditSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &FileAuditSink{file: file}, nil
+}
+
+func (f *FileAuditSink) Record(ctx context.Context, entry AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, err = f.file.Write(append(line, '\n'))
+	return err
+}
+
+func (f *FileAuditSink) Close() error {
+	return f.file.Close()
+}
+
+// auditTimeFormat keeps the fractional seconds of the times of the audit table, so that
+// they sort in order
+const auditTimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+// TableAuditSink inserts entries into a table of a SQL database. Entries are written
+// outside of any transaction of the session, so they are kept when it is rolled back.
+type TableAuditSink struct {
+	db     types.Database
+	insert string
+}
+
+// NewTableAuditSink creates the audit table of db when it does not exist
+func NewTableAuditSink(ctx context.Context, db types.Database, table string) (*TableAuditSink, error) {
+	if db == nil {
+		return nil, fmt.Errorf("table audit sink requires a database")
+	}
+	caps := db.GetCapabilities()
+	if caps.IsNoSQL() {
+		return nil, fmt.Errorf("table audit sink requires a SQL database")
+	}
+
+	columns := []string{"occurred_at", "tool", "access", "arguments", "caller", "roles", "session_id", "duration_ms", "row_count", "error"}
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = caps.QuoteIdentifier(column)
+	}
+	quotedTable := caps.QuoteIdentifier(table)
+
+	create := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+  %s VARCHAR(64) NOT NULL,
+  %s VARCHAR(255) NOT NULL,
+  %s VARCHAR(16) NOT NULL,
+  %s TEXT,
+  %s TEXT,
+  %s TEXT,
+  %s VARCHAR(255),
+  %s BIGINT NOT NULL,
+  %s BIGINT NOT NULL,
+  %s TEXT
+)`, append([]any{quotedTable}, toAny(quoted)...)...)
+	if _, err := db.Raw(create).Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to create audit table %s: %w", table, err)
+	}
+
+	return &TableAuditSink{
+		db: db,
+		insert: fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quotedTable,
+			strings.Join(quoted, ", "), strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")),
+	}, nil
+}
+
+func (t *TableAuditSink) Record(ctx context.Context, entry AuditEntry) error {
+	// The call may have been canceled, but it is still recorded
+	ctx = context.WithoutCancel(ctx)
+	_, err := t.db.Raw(t.insert,
+		entry.Time.UTC().Format(auditTimeFormat),
+		entry.Tool,
+		entry.Access,
+		string(entry.Arguments),
+		entry.Caller,
+		strings.Join(entry.Roles, ","),
+		entry.Session,
+		entry.DurationMs,
+		entry.Rows,
+		entry.Error,
+	).Exec(ctx)
+	return err
+}
+
+func (t *TableAuditSink) Close() error {
+	return nil
+}
+
+const (
+	// webhookAuditQueue is the number of entries waiting for the webhook; more are dropped
+	webhookAuditQueue = 1024
+	// webhookAuditDrain is how long Close waits for the queued entries to be sent
+	webhookAuditDrain = 10 * time.Second
+)
+
+// WebhookAuditSink posts each entry as JSON to a URL. Entries are queued and sent by a
+// background worker, so tool calls do not wait for the webhook; when the queue is full,
+// Record drops the entry and returns an error.
+type WebhookAuditSink struct {
+	url     string
+	client  *http.Client
+	logger  logger.Logger
+	entries chan AuditEntry
+	ctx     context.Context
+	cancel  context.CancelFunc
+	done    chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewWebhookAuditSink creates a sink posting to url, logging the entries it fails to send to l
+func NewWebhookAuditSink(url string, l logger.Logger) *WebhookAuditSink {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &WebhookAuditSink{
+		url:     url,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		logger:  l,
+		entries: make(chan AuditEntry, webhookAuditQueue),
+		ctx:     ctx,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+func (w *WebhookAuditSink) Record(ctx context.Context, entry AuditEntry) error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return fmt.Errorf("audit webhook is closed")
+	}
+
+	select {
+	case w.entries <- entry:
+		return nil
+	default:
+		return fmt.Errorf("audit webhook queue is full, entry dropped")
+	}
+}
+
+// Close sends the queued entries, waiting up to webhookAuditDrain, and stops the worker
+func (w *WebhookAuditSink) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.entries)
+	w.mu.Unlock()
+
+	select {
+	case <-w.done:
+		return nil
+	case <-time.After(webhookAuditDrain):
+		// Abort the request in flight and drop the remaining entries
+		w.cancel()
+		<-w.done
+		return fmt.Errorf("audit webhook did not receive every entry within %v", webhookAuditDrain)
+	}
+}
+
+// run sends the queued entries until the queue is closed
+func (w *WebhookAuditSink) run() {
+	defer close(w.done)
+	defer w.cancel()
+	for entry := range w.entries {
+		if w.ctx.Err() != nil {
+			w.logf("Dropped %s of the audit log: the webhook is closed", entry.Tool)
+			continue
+		}
+		if err := w.send(entry); err != nil {
+			w.logf("Failed to send %s to the audit webhook: %v", entry.Tool, err)
+		}
+	}
+}
+
+func (w *WebhookAuditSink) send(entry AuditEntry) error {
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(w.ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("audit webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+func (w *WebhookAuditSink) logf(format string, args ...any) {
+	if w.logger != nil {
+		w.logger.Error(format, args...)
+	}
+}
+
+// readTools are the tools that do not change the database or the schemas
+var readTools = map[string]bool{
+	"model.findMany":   true,
+	"model.findUnique": true,
+	"model.count":      true,
+	"model.aggregate":  true,
+	"schema.models":    true,
+	"schema.describe":  true,
+	"schema.propose":   true,
+	"migration.status": true,
+	"sql.query":        true,
+}
+
+// toolAccess classifies a tool as "read" or "write"
+func toolAccess(tool string) string {
+	if readTools[tool] {
+		return "read"
+	}
+	return "write"
+}
+
+// resultRows returns the number of records in a tool result: the length of a list, the
+// count of a batch operation, or 1 for a single record
+func resultRows(content []mcp.Content) int {
+	if len(content) == 0 {
+		return 0
+	}
+	text, ok := content[0].(*mcp.TextContent)
+	if !ok {
+		return 0
+	}
+
+	var result any
+	if err := json.Unmarshal([]byte(text.Text), &result); err != nil {
+		return 0
+	}
+	switch r := result.(type) {
+	case []any:
+		return len(r)
+	case map[string]any:
+		if rows, ok := r["rows"].([]any); ok {
+			return len(rows)
+		}
+		if count, ok := r["count"].(float64); ok {
+			return int(count)
+		}
+		return 1
+	}
+	return 0
+}
+
+type callerKey struct{}
+
+// withCaller returns a context carrying a description of the caller
+func withCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// callerMiddleware describes the caller of HTTP requests for the audit log: the common
+// name of its client certificate, if any, and its address
+func callerMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		caller := r.RemoteAddr
+		if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+			caller = fmt.Sprintf("%s (%s)", r.TLS.PeerCertificates[0].Subject.CommonName, r.RemoteAddr)
+		}
+		next.ServeHTTP(w, r.WithContext(withCaller(r.Context(), caller)))
+	})
+}
+
+// recordAudit adds a tool call to the audit log. Failing to record it is logged, but does
+// not fail the call.
+func (s *SDKServer) recordAudit(ctx context.Context, session *mcp.ServerSession, tool string, arguments any, content []mcp.Content, duration time.Duration, callErr error) {
+	if s.audit == nil {
+		return
+	}
+
+	entry := AuditEntry{
+		Time:       time.Now().Add(-duration),
+		Tool:       tool,
+		Access:     toolAccess(tool),
+		Roles:      masking.RolesFromContext(ctx),
+		DurationMs: duration.Milliseconds(),
+	}
+	if args, err := json.Marshal(s.redactArguments(arguments)); err == nil {
+		entry.Arguments = args
+	}
+	if caller, ok := ctx.Value(callerKey{}).(string); ok {
+		entry.Caller = caller
+	} else {
+		entry.Caller = s.config.Transport
+	}
+	if session != nil {
+		entry.Session = session.ID()
+	}
+	if callErr != nil {
+		entry.Error = callErr.Error()
+	} else {
+		entry.Rows = resultRows(content)
+	}
+
+	if err := s.audit.Record(ctx, entry); err != nil {
+		s.logger.Error("Failed to record %s in the audit log: %v", tool, err)
+	}
+}
+
+// redactArguments masks the sensitive fields of the arguments of a tool call before they
+// are recorded, whatever the roles of the caller, as the audit log outlives the call
+func (s *SDKServer) redactArguments(arguments any) any {
+	if s.config.Masking == nil || len(s.config.Masking.Fields) == 0 {
+		return arguments
+	}
+
+	// Work on the JSON form, which names fields as the policy does
+	raw, err := json.Marshal(arguments)
+	if err != nil {
+		return arguments
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return arguments
+	}
+	model, _ := decoded["model"].(string)
+	return s.config.Masking.Redact(s.db, model, decoded)
+}
+
+func toAny(values []string) []any {
+	result := make([]any, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+	return result
+}
@@ -0,0 +1,179 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rediwo/redi-orm/masking"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// callTools runs tool calls through a client session, as the audit log is written by the
+// tool wrapper
+func callTools(t *testing.T, s *SDKServer, calls ...*mcp.CallToolParams) {
+	ctx := context.Background()
+	s.mcpServer = mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	s.registerTools()
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	_, err := s.mcpServer.Connect(ctx, serverTransport)
+	require.NoError(t, err)
+	clientSession, err := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "1.0.0"}, nil).Connect(ctx, clientTransport)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	for _, call := range calls {
+		clientSession.CallTool(ctx, call)
+	}
+}
+
+var auditTestCalls = []*mcp.CallToolParams{
+	{Name: "model.create", Arguments: map[string]any{"model": "User", "data": map[string]any{"name": "alice", "email": "alice@example.com"}}},
+	{Name: "model.create", Arguments: map[string]any{"model": "User", "data": map[string]any{"name": "bob", "email": "bob@example.com"}}},
+	{Name: "model.findMany", Arguments: map[string]any{"model": "User"}},
+	{Name: "model.findMany", Arguments: map[string]any{"model": "Missing"}},
+}
+
+func assertAuditEntries(t *testing.T, entries []AuditEntry) {
+	require.Len(t, entries, 4)
+
+	assert.Equal(t, "model.create", entries[0].Tool)
+	assert.Equal(t, "write", entries[0].Access)
+	assert.Equal(t, 1, entries[0].Rows)
+	assert.JSONEq(t, `{"model":"User","data":{"name":"alice","email":"alice@example.com"}}`, string(entries[0].Arguments))
+
+	assert.Equal(t, "model.findMany", entries[2].Tool)
+	assert.Equal(t, "read", entries[2].Access)
+	assert.Equal(t, 2, entries[2].Rows)
+	assert.Empty(t, entries[2].Error)
+	assert.False(t, entries[2].Time.IsZero())
+
+	assert.Contains(t, entries[3].Error, "Missing")
+	assert.Equal(t, 0, entries[3].Rows)
+}
+
+func TestAuditLog(t *testing.T) {
+	t.Run("file", func(t *testing.T) {
+		s := newTransactionTestServer(t, SecurityConfig{})
+		path := filepath.Join(t.TempDir(), "audit.log")
+		sink, err := NewAuditSink(path, s.db, s.logger)
+		require.NoError(t, err)
+		s.audit = sink
+
+		callTools(t, s, auditTestCalls...)
+		require.NoError(t, sink.Close())
+
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		defer file.Close()
+
+		var entries []AuditEntry
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var entry AuditEntry
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+			entries = append(entries, entry)
+		}
+		assertAuditEntries(t, entries)
+	})
+
+	t.Run("table", func(t *testing.T) {
+		s := newTransactionTestServer(t, SecurityConfig{})
+		sink, err := NewAuditSink("table:tool_calls", s.db, s.logger)
+		require.NoError(t, err)
+		s.audit = sink
+
+		callTools(t, s, auditTestCalls...)
+
+		var rows []map[string]any
+		require.NoError(t, s.db.Raw("SELECT tool, access, row_count, error FROM tool_calls ORDER BY occurred_at").Find(context.Background(), &rows))
+		require.Len(t, rows, 4)
+		assert.Equal(t, "model.findMany", rows[2]["tool"])
+		assert.Equal(t, "read", rows[2]["access"])
+		assert.EqualValues(t, 2, rows[2]["row_count"])
+		assert.Contains(t, rows[3]["error"], "Missing")
+
+		// The table is kept when the sink is opened again
+		_, err = NewAuditSink("table:tool_calls", s.db, s.logger)
+		require.NoError(t, err)
+	})
+
+	t.Run("webhook", func(t *testing.T) {
+		var mu sync.Mutex
+		var entries []AuditEntry
+		webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var entry AuditEntry
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&entry))
+			mu.Lock()
+			entries = append(entries, entry)
+			mu.Unlock()
+		}))
+		defer webhook.Close()
+
+		s := newTransactionTestServer(t, SecurityConfig{})
+		sink, err := NewAuditSink(webhook.URL, s.db, s.logger)
+		require.NoError(t, err)
+		s.audit = sink
+
+		callTools(t, s, auditTestCalls...)
+		require.NoError(t, sink.Close())
+
+		mu.Lock()
+		defer mu.Unlock()
+		assertAuditEntries(t, entries)
+	})
+
+	t.Run("webhook queue overflow", func(t *testing.T) {
+		release := make(chan struct{})
+		webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer webhook.Close()
+
+		sink := NewWebhookAuditSink(webhook.URL, nil)
+		ctx := context.Background()
+		entry := AuditEntry{Tool: "model.findMany"}
+
+		// The worker holds one entry while the webhook blocks, and the queue the others
+		var err error
+		for i := 0; i < webhookAuditQueue+2 && err == nil; i++ {
+			err = sink.Record(ctx, entry)
+		}
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "queue is full")
+
+		close(release)
+		require.NoError(t, sink.Close())
+		assert.Error(t, sink.Record(ctx, entry))
+	})
+
+	t.Run("masked arguments", func(t *testing.T) {
+		s := newTransactionTestServer(t, SecurityConfig{})
+		s.config.Masking = &masking.Policy{Fields: map[string]masking.Strategy{"User.email": masking.StrategyEmail}}
+		path := filepath.Join(t.TempDir(), "audit.log")
+		sink, err := NewAuditSink(path, s.db, s.logger)
+		require.NoError(t, err)
+		s.audit = sink
+
+		callTools(t, s, &mcp.CallToolParams{Name: "model.findMany", Arguments: map[string]any{
+			"model": "User",
+			"where": map[string]any{"email": map[string]any{"equals": "alice@example.com"}},
+		}})
+		require.NoError(t, sink.Close())
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var entry AuditEntry
+		require.NoError(t, json.Unmarshal(content, &entry))
+		assert.JSONEq(t, `{"model":"User","where":{"email":{"equals":"a***@example.com"}}}`, string(entry.Arguments))
+	})
+}
@@ -4,9 +4,14 @@ import (
 	"crypto/subtle"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/rediwo/redi-orm/ratelimit"
+	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/sql"
 )
 
 // SecurityConfig holds security configuration
@@ -25,12 +30,20 @@ type SecurityConfig struct {
 	ReadOnlyMode bool
 	MaxQueryRows int
 	QueryTimeout time.Duration
+
+	// TransactionTimeout is how long a transaction begun with transaction.begin may stay
+	// open before it is rolled back. Defaults to 5 minutes.
+	TransactionTimeout time.Duration
+
+	// SQLTables lists the models (or their tables) the sql.query tool may read.
+	// Every model may be read when empty.
+	SQLTables []string
 }
 
 // SecurityManager handles authentication, authorization, and rate limiting
 type SecurityManager struct {
 	config      SecurityConfig
-	rateLimiter *RateLimiter
+	rateLimiter *ratelimit.Limiter
 	mu          sync.RWMutex
 }
 
@@ -40,13 +53,8 @@ func NewSecurityManager(config SecurityConfig) *SecurityManager {
 	if config.MaxQueryRows == 0 {
 		config.MaxQueryRows = 1000
 	}
-
-	// Calculate burst limit as 25% of rate limit
-	if config.EnableRateLimit && config.BurstLimit == 0 && config.RequestsPerMin > 0 {
-		config.BurstLimit = config.RequestsPerMin / 4
-		if config.BurstLimit < 1 {
-			config.BurstLimit = 1
-		}
+	if config.TransactionTimeout == 0 {
+		config.TransactionTimeout = 5 * time.Minute
 	}
 
 	sm := &SecurityManager{
@@ -54,7 +62,8 @@ func NewSecurityManager(config SecurityConfig) *SecurityManager {
 	}
 
 	if config.EnableRateLimit {
-		sm.rateLimiter = NewRateLimiter(config.RequestsPerMin, config.BurstLimit)
+		// The burst limit defaults to 25% of the rate limit
+		sm.rateLimiter = ratelimit.NewLimiter(config.RequestsPerMin, config.BurstLimit)
 	}
 
 	return sm
@@ -81,6 +90,65 @@ func (sm *SecurityManager) CheckReadOnly(operation string) error {
 	return nil
 }
 
+// SQLFunctions are the functions the sql.query tool may call. They compute values from
+// their arguments only, unlike functions such as setval, pg_read_file or pg_sleep, which
+// change or read state a SELECT would not.
+var SQLFunctions = map[string]bool{
+	// Aggregates
+	"COUNT": true, "SUM": true, "AVG": true, "MIN": true, "MAX": true,
+	"GROUP_CONCAT": true, "STRING_AGG": true,
+	// Window functions
+	"ROW_NUMBER": true, "RANK": true, "DENSE_RANK": true, "LAG": true, "LEAD": true,
+	"FIRST_VALUE": true, "LAST_VALUE": true,
+	// Strings
+	"LOWER": true, "UPPER": true, "LENGTH": true, "CHAR_LENGTH": true, "SUBSTR": true,
+	"SUBSTRING": true, "TRIM": true, "LTRIM": true, "RTRIM": true, "REPLACE": true,
+	"CONCAT": true, "INSTR": true,
+	// Numbers
+	"ABS": true, "ROUND": true, "FLOOR": true, "CEIL": true, "CEILING": true, "MOD": true,
+	// Nulls and conditions
+	"COALESCE": true, "NULLIF": true, "IFNULL": true,
+	// Dates
+	"DATE": true, "STRFTIME": true, "EXTRACT": true, "DATE_TRUNC": true,
+}
+
+// CheckSQLQuery checks that a statement of the sql.query tool only reads tables it may,
+// those of the given models, limited to SQLTables when set, and only calls SQLFunctions
+func (sm *SecurityManager) CheckSQLQuery(query *sql.NormalizedQuery, models []*schema.Schema) error {
+	if query.Statement.GetType() != sql.StatementTypeSelect {
+		return fmt.Errorf("only SELECT statements are allowed")
+	}
+
+	for _, function := range query.Functions {
+		if !SQLFunctions[function] {
+			return fmt.Errorf("function not allowed: %s", function)
+		}
+	}
+
+	for _, table := range sql.TableNames(query.Statement) {
+		model := findTableModel(models, table)
+		if model == nil {
+			return fmt.Errorf("unknown table: %s", table)
+		}
+		if len(sm.config.SQLTables) > 0 && !slices.ContainsFunc(sm.config.SQLTables, func(allowed string) bool {
+			return strings.EqualFold(allowed, model.Name) || strings.EqualFold(allowed, model.GetTableName())
+		}) {
+			return fmt.Errorf("table not allowed: %s", table)
+		}
+	}
+	return nil
+}
+
+// findTableModel returns the model named table or stored in it
+func findTableModel(models []*schema.Schema, table string) *schema.Schema {
+	for _, model := range models {
+		if strings.EqualFold(model.Name, table) || strings.EqualFold(model.GetTableName(), table) {
+			return model
+		}
+	}
+	return nil
+}
+
 // AuthenticateRequest validates authentication for HTTP requests
 func (sm *SecurityManager) AuthenticateRequest(r *http.Request) error {
 	if !sm.config.EnableAuth {
@@ -130,117 +198,27 @@ func (sm *SecurityManager) ValidateHost(r *http.Request) error {
 	return fmt.Errorf("host not allowed: %s", host)
 }
 
-// CheckRateLimit verifies if the request is within rate limits
-func (sm *SecurityManager) CheckRateLimit(clientIP string) error {
+// CheckRateLimit verifies if the request is within rate limits, reporting the remaining
+// quota in the X-RateLimit-* headers of w
+func (sm *SecurityManager) CheckRateLimit(w http.ResponseWriter, clientIP string) error {
 	if !sm.config.EnableRateLimit || sm.rateLimiter == nil {
 		return nil
 	}
 
-	if !sm.rateLimiter.Allow(clientIP) {
+	result := sm.rateLimiter.Allow(clientIP)
+	result.WriteHeaders(w)
+	if !result.Allowed {
 		return fmt.Errorf("rate limit exceeded")
 	}
 
 	return nil
 }
 
-// RateLimiter implements token bucket rate limiting
-type RateLimiter struct {
-	mu          sync.RWMutex
-	clients     map[string]*ClientBucket
-	limit       int
-	burst       int
-	cleanupTick *time.Ticker
-}
-
-// ClientBucket represents a token bucket for a specific client
-type ClientBucket struct {
-	tokens   int
-	lastSeen time.Time
-}
-
-// NewRateLimiter creates a new rate limiter
-func NewRateLimiter(requestsPerMin, burstLimit int) *RateLimiter {
-	rl := &RateLimiter{
-		clients: make(map[string]*ClientBucket),
-		limit:   requestsPerMin,
-		burst:   burstLimit,
-	}
-
-	// Start cleanup routine
-	rl.cleanupTick = time.NewTicker(5 * time.Minute)
-	go rl.cleanup()
-
-	return rl
-}
-
-// Allow checks if a request from the given client IP is allowed
-func (rl *RateLimiter) Allow(clientIP string) bool {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
-
-	now := time.Now()
-
-	// Get or create client bucket
-	bucket, exists := rl.clients[clientIP]
-	if !exists {
-		bucket = &ClientBucket{
-			tokens:   rl.burst,
-			lastSeen: now,
-		}
-		rl.clients[clientIP] = bucket
-	}
-
-	// Calculate tokens to add based on time elapsed
-	elapsed := now.Sub(bucket.lastSeen)
-	tokensToAdd := int(elapsed.Minutes() * float64(rl.limit))
-
-	bucket.tokens += tokensToAdd
-	if bucket.tokens > rl.burst {
-		bucket.tokens = rl.burst
-	}
-	bucket.lastSeen = now
-
-	// Check if request is allowed
-	if bucket.tokens > 0 {
-		bucket.tokens--
-		return true
-	}
-
-	return false
-}
-
-// cleanup removes old client buckets
-func (rl *RateLimiter) cleanup() {
-	for range rl.cleanupTick.C {
-		rl.mu.Lock()
-		now := time.Now()
-		for clientIP, bucket := range rl.clients {
-			if now.Sub(bucket.lastSeen) > 10*time.Minute {
-				delete(rl.clients, clientIP)
-			}
-		}
-		rl.mu.Unlock()
-	}
-}
-
-// Stop stops the rate limiter cleanup routine
-func (rl *RateLimiter) Stop() {
-	if rl.cleanupTick != nil {
-		rl.cleanupTick.Stop()
-	}
-}
-
 // SecurityMiddleware returns an HTTP middleware for security validation
 func (sm *SecurityManager) SecurityMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Extract client IP
-		clientIP := r.RemoteAddr
-		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
-			clientIP = strings.Split(forwarded, ",")[0]
-		}
-		if real := r.Header.Get("X-Real-IP"); real != "" {
-			clientIP = real
-		}
+		clientIP := ratelimit.ClientIP(r, true)
 
 		// Validate host
 		if err := sm.ValidateHost(r); err != nil {
@@ -249,7 +227,7 @@ func (sm *SecurityManager) SecurityMiddleware(next http.Handler) http.Handler {
 		}
 
 		// Check rate limit
-		if err := sm.CheckRateLimit(clientIP); err != nil {
+		if err := sm.CheckRateLimit(w, clientIP); err != nil {
 			http.Error(w, "Too Many Requests: "+err.Error(), http.StatusTooManyRequests)
 			return
 		}
@@ -274,9 +252,7 @@ func (sm *SecurityManager) GetStats() map[string]any {
 	}
 
 	if sm.rateLimiter != nil {
-		sm.rateLimiter.mu.RLock()
-		stats["active_clients"] = len(sm.rateLimiter.clients)
-		sm.rateLimiter.mu.RUnlock()
+		stats["active_clients"] = sm.rateLimiter.Clients()
 	}
 
 	return stats
@@ -2,14 +2,20 @@ package mcp
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/rediwo/redi-orm/database"
 	"github.com/rediwo/redi-orm/logger"
+	"github.com/rediwo/redi-orm/masking"
 	"github.com/rediwo/redi-orm/schema"
 	"github.com/rediwo/redi-orm/schema/generator"
 )
@@ -23,7 +29,10 @@ type ServerConfig struct {
 	LogLevel    string
 	ReadOnly    bool
 	Security    SecurityConfig
-	Version     string // Version of the MCP server
+	Masking     *masking.Policy // Optional: mask sensitive fields for unprivileged callers
+	TLS         *tls.Config     // Optional: serve the HTTP transport over TLS
+	Audit       string          // Optional: sink recording every tool call (see NewAuditSink)
+	Version     string          // Version of the MCP server
 }
 
 // SDKServer wraps the MCP SDK server
@@ -36,6 +45,9 @@ type SDKServer struct {
 	security             *SecurityManager
 	persistence          *generator.SchemaPersistence
 	pendingSchemaManager *PendingSchemaManager
+	proposals            *schemaProposals
+	transactions         *sessionTransactions
+	audit                AuditSink
 }
 
 // NewSDKServer creates a new MCP server using the official SDK
@@ -151,6 +163,15 @@ func NewSDKServer(config ServerConfig) (*SDKServer, error) {
 	// Create security manager
 	security := NewSecurityManager(config.Security)
 
+	// Open the audit log if configured
+	var audit AuditSink
+	if config.Audit != "" {
+		audit, err = NewAuditSink(config.Audit, db, l)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audit log: %w", err)
+		}
+	}
+
 	// Create server instance first (without mcpServer)
 	server := &SDKServer{
 		config:      config,
@@ -159,6 +180,7 @@ func NewSDKServer(config ServerConfig) (*SDKServer, error) {
 		logger:      l,
 		security:    security,
 		persistence: persistence,
+		audit:       audit,
 	}
 
 	// Create MCP server with comprehensive logging handlers
@@ -217,6 +239,9 @@ func NewSDKServer(config ServerConfig) (*SDKServer, error) {
 	// Register all tools
 	server.registerTools()
 	server.registerSchemaModificationTools()
+	server.registerSQLTools()
+	server.registerSchemaProposalTools()
+	server.registerTransactionTools()
 
 	// Add receiving middleware to log all incoming method calls
 	receivingMiddleware := func(next mcp.MethodHandler[*mcp.ServerSession]) mcp.MethodHandler[*mcp.ServerSession] {
@@ -278,7 +303,10 @@ func (s *SDKServer) GetLogger() logger.Logger {
 func (s *SDKServer) Start() error {
 	s.logger.Info("Starting MCP server with transport: %s", s.config.Transport)
 
-	ctx := context.Background()
+	// Stop on interrupt, so that the audit log is closed
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	defer s.closeAudit()
 
 	switch s.config.Transport {
 	case "stdio":
@@ -410,19 +438,62 @@ func (s *SDKServer) startHTTPServer(ctx context.Context) error {
 	// Add CORS support
 	handler = s.corsMiddleware(handler)
 
+	// Read caller roles for field masking
+	handler = s.config.Masking.Middleware(handler)
+
+	// Describe the caller for the audit log
+	handler = callerMiddleware(handler)
+
 	// Add security middleware (authentication, rate limiting) if configured
 	if s.config.Security.EnableAuth || s.config.Security.EnableRateLimit {
 		handler = s.security.SecurityMiddleware(handler)
 	}
 
 	addr := fmt.Sprintf(":%d", s.config.Port)
+	scheme := "http"
+	if s.config.TLS != nil {
+		scheme = "https"
+	}
 	s.logger.Info("Starting HTTP server on %s", addr)
 	s.logger.Info("Transport endpoints:")
-	s.logger.Info("  - Intelligent routing: http://localhost%s/ (supports both streamable and SSE)", addr)
-	s.logger.Info("  - Dedicated SSE: http://localhost%s/sse", addr)
-	s.logger.Info("  - Transport info: http://localhost%s/transports", addr)
+	s.logger.Info("  - Intelligent routing: %s://localhost%s/ (supports both streamable and SSE)", scheme, addr)
+	s.logger.Info("  - Dedicated SSE: %s://localhost%s/sse", scheme, addr)
+	s.logger.Info("  - Transport info: %s://localhost%s/transports", scheme, addr)
+
+	httpServer := &http.Server{
+		Addr:      addr,
+		Handler:   handler,
+		TLSConfig: s.config.TLS,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	var err error
+	if s.config.TLS != nil {
+		// The certificate is in the TLS config
+		err = httpServer.ListenAndServeTLS("", "")
+	} else {
+		err = httpServer.ListenAndServe()
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		s.logger.Info("MCP HTTP server stopped gracefully")
+		return nil
+	}
+	return err
+}
 
-	return http.ListenAndServe(addr, handler)
+// closeAudit closes the audit sink once the server has stopped, flushing its entries
+func (s *SDKServer) closeAudit() {
+	if s.audit == nil {
+		return
+	}
+	if err := s.audit.Close(); err != nil {
+		s.logger.Error("Failed to close the audit log: %v", err)
+	}
 }
 
 // corsMiddleware adds CORS headers
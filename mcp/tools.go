@@ -9,7 +9,6 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/rediwo/redi-orm/logger"
-	"github.com/rediwo/redi-orm/orm"
 	"github.com/rediwo/redi-orm/schema"
 )
 
@@ -40,6 +39,13 @@ func addToolWithLogging[In, Out any](s *SDKServer, tool *mcp.Tool, handler func(
 			s.logger.Info("Tool %s completed in %v", tool.Name, duration)
 		}
 
+		// Record the call in the audit log
+		var content []mcp.Content
+		if result != nil {
+			content = result.Content
+		}
+		s.recordAudit(ctx, session, tool.Name, params.Arguments, content, duration, err)
+
 		return result, err
 	}
 
@@ -255,15 +261,15 @@ func (s *SDKServer) handleModelFindMany(ctx context.Context, session *mcp.Server
 		return nil, fmt.Errorf("failed to marshal query: %w", err)
 	}
 
-	client := orm.NewClient(s.db)
-	result, err := client.Model(params.Arguments.Model).Query(string(queryJSON))
+	client := s.client(session)
+	result, err := client.Model(params.Arguments.Model).QueryContext(ctx, string(queryJSON))
 	if err != nil {
 		s.logger.Error("model.findMany query failed for model %s: %v", params.Arguments.Model, err)
 		return nil, fmt.Errorf("query failed: %w", err)
 	}
 
-	// Return result
-	resultJSON, err := json.MarshalIndent(result, "", "  ")
+	// Return result, masking sensitive fields for unprivileged callers
+	resultJSON, err := json.MarshalIndent(s.config.Masking.Apply(ctx, s.db, params.Arguments.Model, result), "", "  ")
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal result: %w", err)
 	}
@@ -300,15 +306,15 @@ func (s *SDKServer) handleModelFindUnique(ctx context.Context, session *mcp.Serv
 		return nil, fmt.Errorf("failed to marshal query: %w", err)
 	}
 
-	client := orm.NewClient(s.db)
-	result, err := client.Model(params.Arguments.Model).Query(string(queryJSON))
+	client := s.client(session)
+	result, err := client.Model(params.Arguments.Model).QueryContext(ctx, string(queryJSON))
 	if err != nil {
 		s.logger.Error("model.findUnique query failed for model %s: %v", params.Arguments.Model, err)
 		return nil, fmt.Errorf("query failed: %w", err)
 	}
 
-	// Return result
-	resultJSON, err := json.MarshalIndent(result, "", "  ")
+	// Return result, masking sensitive fields for unprivileged callers
+	resultJSON, err := json.MarshalIndent(s.config.Masking.Apply(ctx, s.db, params.Arguments.Model, result), "", "  ")
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal result: %w", err)
 	}
@@ -341,15 +347,15 @@ func (s *SDKServer) handleModelCreate(ctx context.Context, session *mcp.ServerSe
 		return nil, fmt.Errorf("failed to marshal query: %w", err)
 	}
 
-	client := orm.NewClient(s.db)
-	result, err := client.Model(params.Arguments.Model).Query(string(queryJSON))
+	client := s.client(session)
+	result, err := client.Model(params.Arguments.Model).QueryContext(ctx, string(queryJSON))
 	if err != nil {
 		s.logger.Error("model.create failed for model %s: %v", params.Arguments.Model, err)
 		return nil, fmt.Errorf("create failed: %w", err)
 	}
 
-	// Return result
-	resultJSON, err := json.MarshalIndent(result, "", "  ")
+	// Return result, masking sensitive fields for unprivileged callers
+	resultJSON, err := json.MarshalIndent(s.config.Masking.Apply(ctx, s.db, params.Arguments.Model, result), "", "  ")
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal result: %w", err)
 	}
@@ -382,15 +388,15 @@ func (s *SDKServer) handleModelUpdate(ctx context.Context, session *mcp.ServerSe
 		return nil, fmt.Errorf("failed to marshal query: %w", err)
 	}
 
-	client := orm.NewClient(s.db)
-	result, err := client.Model(params.Arguments.Model).Query(string(queryJSON))
+	client := s.client(session)
+	result, err := client.Model(params.Arguments.Model).QueryContext(ctx, string(queryJSON))
 	if err != nil {
 		s.logger.Error("model.update failed for model %s: %v", params.Arguments.Model, err)
 		return nil, fmt.Errorf("update failed: %w", err)
 	}
 
-	// Return result
-	resultJSON, err := json.MarshalIndent(result, "", "  ")
+	// Return result, masking sensitive fields for unprivileged callers
+	resultJSON, err := json.MarshalIndent(s.config.Masking.Apply(ctx, s.db, params.Arguments.Model, result), "", "  ")
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal result: %w", err)
 	}
@@ -422,15 +428,15 @@ func (s *SDKServer) handleModelDelete(ctx context.Context, session *mcp.ServerSe
 		return nil, fmt.Errorf("failed to marshal query: %w", err)
 	}
 
-	client := orm.NewClient(s.db)
-	result, err := client.Model(params.Arguments.Model).Query(string(queryJSON))
+	client := s.client(session)
+	result, err := client.Model(params.Arguments.Model).QueryContext(ctx, string(queryJSON))
 	if err != nil {
 		s.logger.Error("model.delete failed for model %s: %v", params.Arguments.Model, err)
 		return nil, fmt.Errorf("delete failed: %w", err)
 	}
 
-	// Return result
-	resultJSON, err := json.MarshalIndent(result, "", "  ")
+	// Return result, masking sensitive fields for unprivileged callers
+	resultJSON, err := json.MarshalIndent(s.config.Masking.Apply(ctx, s.db, params.Arguments.Model, result), "", "  ")
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal result: %w", err)
 	}
@@ -463,14 +469,14 @@ func (s *SDKServer) handleModelCount(ctx context.Context, session *mcp.ServerSes
 		return nil, fmt.Errorf("failed to marshal query: %w", err)
 	}
 
-	client := orm.NewClient(s.db)
-	result, err := client.Model(params.Arguments.Model).Query(string(queryJSON))
+	client := s.client(session)
+	result, err := client.Model(params.Arguments.Model).QueryContext(ctx, string(queryJSON))
 	if err != nil {
 		return nil, fmt.Errorf("count failed: %w", err)
 	}
 
-	// Return result
-	resultJSON, err := json.MarshalIndent(result, "", "  ")
+	// Return result, masking sensitive fields for unprivileged callers
+	resultJSON, err := json.MarshalIndent(s.config.Masking.Apply(ctx, s.db, params.Arguments.Model, result), "", "  ")
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal result: %w", err)
 	}
@@ -523,14 +529,14 @@ func (s *SDKServer) handleModelAggregate(ctx context.Context, session *mcp.Serve
 		return nil, fmt.Errorf("failed to marshal query: %w", err)
 	}
 
-	client := orm.NewClient(s.db)
-	result, err := client.Model(params.Arguments.Model).Query(string(queryJSON))
+	client := s.client(session)
+	result, err := client.Model(params.Arguments.Model).QueryContext(ctx, string(queryJSON))
 	if err != nil {
 		return nil, fmt.Errorf("aggregate failed: %w", err)
 	}
 
-	// Return result
-	resultJSON, err := json.MarshalIndent(result, "", "  ")
+	// Return result, masking sensitive fields for unprivileged callers
+	resultJSON, err := json.MarshalIndent(s.config.Masking.Apply(ctx, s.db, params.Arguments.Model, result), "", "  ")
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal result: %w", err)
 	}
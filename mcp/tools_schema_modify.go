@@ -134,41 +134,9 @@ func (s *SDKServer) handleSchemaCreate(ctx context.Context, session *mcp.ServerS
 		}
 	}
 
-	// Create new schema
-	newSchema := schema.New(params.Arguments.Model)
-
-	// Set custom table name if provided
-	if params.Arguments.TableName != "" {
-		newSchema.WithTableName(params.Arguments.TableName)
-	}
-
-	// Add fields
-	for _, fieldDef := range params.Arguments.Fields {
-		field, err := s.createSchemaField(fieldDef)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create field %s: %w", fieldDef.Name, err)
-		}
-		newSchema.AddField(field)
-	}
-
-	// Add relations
-	for _, relDef := range params.Arguments.Relations {
-		relation := s.createSchemaRelation(relDef)
-		newSchema.AddRelation(relDef.Name, relation)
-	}
-
-	// Add indexes
-	for _, indexDef := range params.Arguments.Indexes {
-		index := schema.Index{
-			Fields: indexDef.Fields,
-			Unique: indexDef.Unique,
-			Name:   indexDef.Name,
-		}
-		if index.Name == "" {
-			// Use the utility function to generate index name
-			index.Name = utils.GenerateIndexName(params.Arguments.Model, indexDef.Fields, index.Unique, "")
-		}
-		newSchema.AddIndex(index)
+	newSchema, err := s.buildSchema(params.Arguments)
+	if err != nil {
+		return nil, err
 	}
 
 	// Save to file
@@ -516,6 +484,47 @@ func (s *SDKServer) handleSchemaAddRelation(ctx context.Context, session *mcp.Se
 }
 
 // Helper methods
+// buildSchema creates a model schema from its definition
+func (s *SDKServer) buildSchema(def SchemaCreateParams) (*schema.Schema, error) {
+	newSchema := schema.New(def.Model)
+
+	// Set custom table name if provided
+	if def.TableName != "" {
+		newSchema.WithTableName(def.TableName)
+	}
+
+	// Add fields
+	for _, fieldDef := range def.Fields {
+		field, err := s.createSchemaField(fieldDef)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create field %s: %w", fieldDef.Name, err)
+		}
+		newSchema.AddField(field)
+	}
+
+	// Add relations
+	for _, relDef := range def.Relations {
+		relation := s.createSchemaRelation(relDef)
+		newSchema.AddRelation(relDef.Name, relation)
+	}
+
+	// Add indexes
+	for _, indexDef := range def.Indexes {
+		index := schema.Index{
+			Fields: indexDef.Fields,
+			Unique: indexDef.Unique,
+			Name:   indexDef.Name,
+		}
+		if index.Name == "" {
+			// Use the utility function to generate index name
+			index.Name = utils.GenerateIndexName(def.Model, indexDef.Fields, index.Unique, "")
+		}
+		newSchema.AddIndex(index)
+	}
+
+	return newSchema, nil
+}
+
 func (s *SDKServer) createSchemaField(def SchemaFieldDefinition) (schema.Field, error) {
 	fieldType, err := s.parseFieldType(def.Type)
 	if err != nil {
@@ -719,7 +728,7 @@ func (s *SDKServer) parseFieldType(typeStr string) (schema.FieldType, error) {
 	case "Decimal":
 		return schema.FieldTypeDecimal, nil
 	case "Bytes":
-		return schema.FieldTypeBinary, nil
+		return schema.FieldTypeBytes, nil
 	default:
 		return "", fmt.Errorf("unknown field type: %s", typeStr)
 	}
@@ -0,0 +1,361 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rediwo/redi-orm/base"
+	"github.com/rediwo/redi-orm/migration"
+	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/types"
+)
+
+// Schema proposal tool parameters
+type SchemaProposeParams struct {
+	Models []SchemaCreateParams   `json:"models,omitempty" jsonschema:"New models to add"`
+	Fields []SchemaAddFieldParams `json:"fields,omitempty" jsonschema:"Fields to add to existing models"`
+}
+
+type SchemaApplyProposalParams struct {
+	ID string `json:"id" jsonschema:"Proposal ID returned by schema.propose"`
+}
+
+// schemaProposal is a schema change previewed by schema.propose, waiting to be applied
+type schemaProposal struct {
+	ID        string
+	Params    SchemaProposeParams
+	Checksum  string // Checksum of the previewed changes
+	CreatedAt time.Time
+}
+
+// schemaProposals holds the proposals of a server
+type schemaProposals struct {
+	mu        sync.Mutex
+	proposals map[string]*schemaProposal
+	nextID    int
+}
+
+func newSchemaProposals() *schemaProposals {
+	return &schemaProposals{proposals: make(map[string]*schemaProposal)}
+}
+
+func (p *schemaProposals) add(params SchemaProposeParams, checksum string) *schemaProposal {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.nextID++
+	proposal := &schemaProposal{
+		ID:        fmt.Sprintf("proposal-%d", p.nextID),
+		Params:    params,
+		Checksum:  checksum,
+		CreatedAt: time.Now(),
+	}
+	p.proposals[proposal.ID] = proposal
+	return proposal
+}
+
+func (p *schemaProposals) get(id string) (*schemaProposal, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	proposal, ok := p.proposals[id]
+	return proposal, ok
+}
+
+func (p *schemaProposals) remove(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.proposals, id)
+}
+
+// registerSchemaProposalTools registers the tools proposing and applying schema changes
+func (s *SDKServer) registerSchemaProposalTools() {
+	if s.proposals == nil {
+		s.proposals = newSchemaProposals()
+	}
+
+	proposeSchema, _ := jsonschema.For[SchemaProposeParams]()
+	addToolWithLogging[SchemaProposeParams, any](s, &mcp.Tool{
+		Name:        "schema.propose",
+		Description: "Propose new models or fields, returning the migration SQL they need without changing anything",
+		InputSchema: proposeSchema,
+	}, s.handleSchemaPropose)
+
+	applySchema, _ := jsonschema.For[SchemaApplyProposalParams]()
+	addToolWithLogging[SchemaApplyProposalParams, any](s, &mcp.Tool{
+		Name:        "schema.applyProposal",
+		Description: "Apply a schema change previewed by schema.propose",
+		InputSchema: applySchema,
+	}, s.handleSchemaApplyProposal)
+}
+
+// handleSchemaPropose diffs the proposed schemas against the database and keeps the
+// proposal until it is applied. Proposing is allowed in read-only mode, as it changes
+// neither the schemas nor the database.
+func (s *SDKServer) handleSchemaPropose(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[SchemaProposeParams]) (*mcp.CallToolResultFor[any], error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+	if len(params.Arguments.Models) == 0 && len(params.Arguments.Fields) == 0 {
+		return nil, fmt.Errorf("proposal has no models or fields")
+	}
+
+	proposed, err := s.proposedSchemas(params.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := s.proposalChanges(proposed)
+	if err != nil {
+		return nil, err
+	}
+
+	proposal := s.proposals.add(params.Arguments, proposalChecksum(changes))
+
+	previews := make([]map[string]any, len(changes))
+	statements := make([]string, len(changes))
+	for i, change := range changes {
+		preview := map[string]any{
+			"type":  change.Type,
+			"table": change.TableName,
+			"sql":   change.SQL,
+		}
+		if change.ColumnName != "" {
+			preview["column"] = change.ColumnName
+		}
+		if change.IndexName != "" {
+			preview["index"] = change.IndexName
+		}
+		previews[i] = preview
+		statements[i] = change.SQL
+	}
+
+	message := fmt.Sprintf("Call schema.applyProposal with id %s to apply %d changes", proposal.ID, len(changes))
+	if s.security.config.ReadOnlyMode {
+		message = "Preview only: the server is read-only, so the proposal cannot be applied"
+	}
+
+	result := map[string]any{
+		"id":      proposal.ID,
+		"models":  getSchemaNames(proposed),
+		"changes": previews,
+		"sql":     statements,
+		"applied": false,
+		"message": message,
+	}
+
+	resultJSON, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: string(resultJSON)},
+		},
+	}, nil
+}
+
+// handleSchemaApplyProposal applies a proposal when the changes it needs are still the
+// ones previewed, then saves and registers the changed schemas
+func (s *SDKServer) handleSchemaApplyProposal(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[SchemaApplyProposalParams]) (*mcp.CallToolResultFor[any], error) {
+	// Check read-only mode
+	if err := s.security.CheckReadOnly("schema.applyProposal"); err != nil {
+		return nil, err
+	}
+
+	if s.db == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+
+	proposal, ok := s.proposals.get(params.Arguments.ID)
+	if !ok {
+		return nil, fmt.Errorf("proposal %s not found", params.Arguments.ID)
+	}
+
+	// The schemas or the database may have changed since the preview
+	proposed, err := s.proposedSchemas(proposal.Params)
+	if err != nil {
+		return nil, fmt.Errorf("proposal %s no longer applies: %w", proposal.ID, err)
+	}
+	changes, err := s.proposalChanges(proposed)
+	if err != nil {
+		return nil, err
+	}
+	if proposalChecksum(changes) != proposal.Checksum {
+		return nil, fmt.Errorf("proposal %s no longer matches the database, propose the change again", proposal.ID)
+	}
+
+	migrator := s.db.GetMigrator()
+	statements := make([]string, 0, len(changes))
+	for _, change := range changes {
+		if err := migrator.ApplyMigration(change.SQL); err != nil {
+			return nil, fmt.Errorf("failed to apply %s after %d statements: %w", change.SQL, len(statements), err)
+		}
+		statements = append(statements, change.SQL)
+	}
+
+	for _, sch := range proposed {
+		if s.persistence != nil {
+			if err := s.persistence.SaveSchema(sch); err != nil {
+				return nil, fmt.Errorf("failed to save schema: %w", err)
+			}
+		}
+		if err := s.db.RegisterSchema(sch.Name, sch); err != nil {
+			s.logger.Warn("Failed to register schema with database: %v", err)
+		}
+
+		if i := slices.IndexFunc(s.schemas, func(existing *schema.Schema) bool { return existing.Name == sch.Name }); i >= 0 {
+			s.schemas[i] = sch
+		} else {
+			s.schemas = append(s.schemas, sch)
+		}
+	}
+	s.proposals.remove(proposal.ID)
+
+	result := map[string]any{
+		"id":      proposal.ID,
+		"models":  getSchemaNames(proposed),
+		"sql":     statements,
+		"applied": true,
+		"message": fmt.Sprintf("Applied %d changes", len(statements)),
+	}
+
+	resultJSON, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: string(resultJSON)},
+		},
+	}, nil
+}
+
+// proposedSchemas returns the schemas a proposal changes as they would be after it: new
+// models, and copies of existing models with the new fields
+func (s *SDKServer) proposedSchemas(params SchemaProposeParams) ([]*schema.Schema, error) {
+	var proposed []*schema.Schema
+	find := func(name string) *schema.Schema {
+		for _, sch := range proposed {
+			if sch.Name == name {
+				return sch
+			}
+		}
+		return nil
+	}
+
+	for _, def := range params.Models {
+		if def.Model == "" {
+			return nil, fmt.Errorf("model name is required")
+		}
+		if s.findSchema(def.Model) != nil || find(def.Model) != nil {
+			return nil, fmt.Errorf("model %s already exists", def.Model)
+		}
+		newSchema, err := s.buildSchema(def)
+		if err != nil {
+			return nil, err
+		}
+		proposed = append(proposed, newSchema)
+	}
+
+	for _, def := range params.Fields {
+		target := find(def.Model)
+		if target == nil {
+			existing := s.findSchema(def.Model)
+			if existing == nil {
+				return nil, fmt.Errorf("model %s not found", def.Model)
+			}
+			target = copySchema(existing)
+			proposed = append(proposed, target)
+		}
+		if target.GetFieldByName(def.Field.Name) != nil {
+			return nil, fmt.Errorf("field %s already exists in model %s", def.Field.Name, def.Model)
+		}
+		field, err := s.createSchemaField(def.Field)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create field %s: %w", def.Field.Name, err)
+		}
+		target.AddField(field)
+	}
+
+	for _, sch := range proposed {
+		if err := sch.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid model %s: %w", sch.Name, err)
+		}
+	}
+	return proposed, nil
+}
+
+// proposalChanges diffs proposed schemas against the database. Only additive changes are
+// kept, so a proposal never drops or alters what the database already holds.
+func (s *SDKServer) proposalChanges(proposed []*schema.Schema) ([]types.SchemaChange, error) {
+	bySchema := make(map[string]*schema.Schema, len(proposed))
+	for _, sch := range proposed {
+		bySchema[sch.Name] = sch
+	}
+	// Tables referenced by others are created first
+	order, err := base.AnalyzeSchemasDependencies(bySchema)
+	if err != nil {
+		order = getSchemaNames(proposed)
+	}
+
+	differ := migration.NewDiffer(s.db.GetMigrator())
+	var changes []types.SchemaChange
+	for _, name := range order {
+		sch := bySchema[name]
+		diff, err := differ.ComputeDiff(map[string]*schema.Schema{name: sch})
+		if err != nil {
+			return nil, fmt.Errorf("failed to diff model %s: %w", name, err)
+		}
+		for _, change := range diff {
+			if change.TableName != sch.TableName {
+				continue
+			}
+			switch change.Type {
+			case types.ChangeTypeCreateTable, types.ChangeTypeAddColumn, types.ChangeTypeAddIndex, types.ChangeTypeAddFK, types.ChangeTypeAddTrigger:
+				changes = append(changes, change)
+			}
+		}
+	}
+	return changes, nil
+}
+
+// proposalChecksum returns the checksum of proposal changes, which does not depend on the
+// order of the tables that do not reference each other
+func proposalChecksum(changes []types.SchemaChange) string {
+	sorted := slices.Clone(changes)
+	slices.SortFunc(sorted, func(a, b types.SchemaChange) int {
+		return strings.Compare(a.TableName+"\x00"+a.SQL, b.TableName+"\x00"+b.SQL)
+	})
+	return migration.ComputeChecksum(sorted)
+}
+
+// findSchema returns the loaded schema of a model, or nil
+func (s *SDKServer) findSchema(name string) *schema.Schema {
+	for _, sch := range s.schemas {
+		if sch.Name == name {
+			return sch
+		}
+	}
+	return nil
+}
+
+// copySchema copies a schema so that fields can be added to it without changing the original
+func copySchema(src *schema.Schema) *schema.Schema {
+	dst := *src
+	dst.Fields = slices.Clone(src.Fields)
+	dst.Relations = maps.Clone(src.Relations)
+	dst.Indexes = slices.Clone(src.Indexes)
+	dst.CompositeKey = slices.Clone(src.CompositeKey)
+	return &dst
+}
@@ -0,0 +1,199 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rediwo/redi-orm/database"
+	_ "github.com/rediwo/redi-orm/drivers/sqlite"
+	"github.com/rediwo/redi-orm/logger"
+	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/schema/generator"
+)
+
+const proposeTestSchema = `
+model User {
+  id    Int    @id @default(autoincrement())
+  name  String
+  email String @unique
+}
+`
+
+func newProposeTestServer(t *testing.T, security SecurityConfig) (*SDKServer, string) {
+	db, err := database.NewFromURI("sqlite://:memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	ctx := context.Background()
+	require.NoError(t, db.Connect(ctx))
+	require.NoError(t, db.LoadSchema(ctx, proposeTestSchema))
+	require.NoError(t, db.SyncSchemas(ctx))
+
+	var schemas []*schema.Schema
+	for _, name := range db.GetModels() {
+		sch, err := db.GetSchema(name)
+		require.NoError(t, err)
+		schemas = append(schemas, sch)
+	}
+
+	l := logger.NewDefaultLogger("TEST")
+	l.SetLevel(logger.LogLevelError)
+	schemaPath := filepath.Join(t.TempDir(), "schema.prisma")
+	return &SDKServer{
+		db:          db,
+		schemas:     schemas,
+		logger:      l,
+		security:    NewSecurityManager(security),
+		persistence: generator.NewSchemaPersistence(schemaPath, l, db.GetMigrator()),
+		proposals:   newSchemaProposals(),
+	}, schemaPath
+}
+
+func proposeSchemaChange(t *testing.T, s *SDKServer, args SchemaProposeParams) (map[string]any, error) {
+	result, err := s.handleSchemaPropose(context.Background(), nil, &mcp.CallToolParamsFor[SchemaProposeParams]{Arguments: args})
+	if err != nil {
+		return nil, err
+	}
+	var output map[string]any
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &output))
+	return output, nil
+}
+
+func applySchemaProposal(t *testing.T, s *SDKServer, id string) (map[string]any, error) {
+	result, err := s.handleSchemaApplyProposal(context.Background(), nil, &mcp.CallToolParamsFor[SchemaApplyProposalParams]{Arguments: SchemaApplyProposalParams{ID: id}})
+	if err != nil {
+		return nil, err
+	}
+	var output map[string]any
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &output))
+	return output, nil
+}
+
+func tagProposal() SchemaProposeParams {
+	return SchemaProposeParams{
+		Models: []SchemaCreateParams{{
+			Model: "Tag",
+			Fields: []SchemaFieldDefinition{
+				{Name: "id", Type: "Int", PrimaryKey: true, AutoIncrement: true},
+				{Name: "label", Type: "String", Unique: true},
+			},
+		}},
+		Fields: []SchemaAddFieldParams{{
+			Model: "User",
+			Field: SchemaFieldDefinition{Name: "bio", Type: "String", Optional: true},
+		}},
+	}
+}
+
+func TestSchemaProposalTools(t *testing.T) {
+	t.Run("previews the migration and applies it on request", func(t *testing.T) {
+		s, schemaPath := newProposeTestServer(t, SecurityConfig{})
+
+		output, err := proposeSchemaChange(t, s, tagProposal())
+		require.NoError(t, err)
+		assert.Equal(t, false, output["applied"])
+		assert.ElementsMatch(t, []any{"Tag", "User"}, output["models"])
+
+		var types []any
+		for _, change := range output["changes"].([]any) {
+			types = append(types, change.(map[string]any)["type"])
+		}
+		assert.Contains(t, types, "CREATE_TABLE")
+		assert.Contains(t, types, "ADD_COLUMN")
+		assert.NotEmpty(t, output["sql"])
+
+		// Nothing changes before the proposal is applied
+		tables, err := s.db.GetMigrator().GetTables()
+		require.NoError(t, err)
+		assert.NotContains(t, tables, "tags")
+		assert.Nil(t, s.findSchema("User").GetFieldByName("bio"))
+
+		applied, err := applySchemaProposal(t, s, output["id"].(string))
+		require.NoError(t, err)
+		assert.Equal(t, true, applied["applied"])
+
+		tables, err = s.db.GetMigrator().GetTables()
+		require.NoError(t, err)
+		assert.Contains(t, tables, "tags")
+		assert.NotNil(t, s.findSchema("Tag"))
+		assert.NotNil(t, s.findSchema("User").GetFieldByName("bio"))
+
+		ctx := context.Background()
+		_, err = s.db.Model("User").Insert(map[string]any{"name": "Alice", "email": "alice@example.com", "bio": "Hi"}).Exec(ctx)
+		require.NoError(t, err)
+		_, err = s.db.Model("Tag").Insert(map[string]any{"label": "go"}).Exec(ctx)
+		require.NoError(t, err)
+
+		content, err := os.ReadFile(schemaPath)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "model Tag")
+
+		// A proposal is applied once
+		_, err = applySchemaProposal(t, s, output["id"].(string))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+
+	t.Run("only previews in read-only mode", func(t *testing.T) {
+		s, _ := newProposeTestServer(t, SecurityConfig{ReadOnlyMode: true})
+
+		output, err := proposeSchemaChange(t, s, tagProposal())
+		require.NoError(t, err)
+		assert.Contains(t, output["message"], "read-only")
+
+		_, err = applySchemaProposal(t, s, output["id"].(string))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "read-only")
+	})
+
+	t.Run("rejects stale proposals", func(t *testing.T) {
+		s, _ := newProposeTestServer(t, SecurityConfig{})
+
+		first, err := proposeSchemaChange(t, s, tagProposal())
+		require.NoError(t, err)
+		second, err := proposeSchemaChange(t, s, tagProposal())
+		require.NoError(t, err)
+
+		_, err = applySchemaProposal(t, s, second["id"].(string))
+		require.NoError(t, err)
+
+		_, err = applySchemaProposal(t, s, first["id"].(string))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no longer applies")
+	})
+
+	t.Run("rejects invalid proposals", func(t *testing.T) {
+		s, _ := newProposeTestServer(t, SecurityConfig{})
+
+		_, err := proposeSchemaChange(t, s, SchemaProposeParams{})
+		require.Error(t, err)
+
+		_, err = proposeSchemaChange(t, s, SchemaProposeParams{Fields: []SchemaAddFieldParams{{
+			Model: "User",
+			Field: SchemaFieldDefinition{Name: "email", Type: "String"},
+		}}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field email already exists")
+
+		_, err = proposeSchemaChange(t, s, SchemaProposeParams{Models: []SchemaCreateParams{{
+			Model:  "User",
+			Fields: []SchemaFieldDefinition{{Name: "id", Type: "Int", PrimaryKey: true}},
+		}}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "model User already exists")
+
+		_, err = proposeSchemaChange(t, s, SchemaProposeParams{Fields: []SchemaAddFieldParams{{
+			Model: "Missing",
+			Field: SchemaFieldDefinition{Name: "x", Type: "String"},
+		}}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "model Missing not found")
+	})
+}
@@ -0,0 +1,101 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/sql"
+)
+
+// SQL tool parameters
+type SQLQueryParams struct {
+	SQL        string `json:"sql" jsonschema:"SELECT statement over the tables of the models"`
+	Parameters []any  `json:"parameters,omitempty" jsonschema:"Values of the ? placeholders"`
+	Limit      *int   `json:"limit,omitempty" jsonschema:"Maximum number of rows to return"`
+}
+
+// registerSQLTools registers the raw SQL tools
+func (s *SDKServer) registerSQLTools() {
+	querySchema, _ := jsonschema.For[SQLQueryParams]()
+	addToolWithLogging[SQLQueryParams, any](s, &mcp.Tool{
+		Name:        "sql.query",
+		Description: "Run a read-only SQL SELECT over model tables, returning the rows and the normalized query",
+		InputSchema: querySchema,
+	}, s.handleSQLQuery)
+}
+
+// handleSQLQuery runs a raw SELECT statement after checking it: the statement is parsed
+// and normalized, may only read the tables of models allowed by the security config and
+// call the functions of SQLFunctions, and its rows are capped by a LIMIT.
+func (s *SDKServer) handleSQLQuery(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[SQLQueryParams]) (*mcp.CallToolResultFor[any], error) {
+	maxRows := s.security.config.MaxQueryRows
+	if limit := params.Arguments.Limit; limit != nil && *limit > 0 && (maxRows <= 0 || *limit < maxRows) {
+		maxRows = *limit
+	}
+
+	quotes := sql.DoubleQuotedStrings
+	if strings.HasPrefix(s.db.GetCapabilities().QuoteIdentifier("x"), `"`) {
+		quotes = sql.DoubleQuotedIdentifiers
+	}
+	query, err := sql.Normalize(params.Arguments.SQL, params.Arguments.Parameters, maxRows, quotes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SQL: %w", err)
+	}
+
+	models := make([]*schema.Schema, 0, len(s.db.GetModels()))
+	for _, name := range s.db.GetModels() {
+		if model, err := s.db.GetSchema(name); err == nil {
+			models = append(models, model)
+		}
+	}
+	if err := s.security.CheckSQLQuery(query, models); err != nil {
+		return nil, fmt.Errorf("query rejected: %w", err)
+	}
+
+	// Raw rows cannot be masked reliably, as columns may be computed from sensitive fields
+	if !s.config.Masking.Privileged(ctx) {
+		for _, table := range sql.TableNames(query.Statement) {
+			model := findTableModel(models, table)
+			for _, field := range model.Fields {
+				if _, ok := s.config.Masking.FieldStrategy(model.Name, field.Name); ok {
+					return nil, fmt.Errorf("query rejected: %s has masked fields, use the model tools instead", model.Name)
+				}
+			}
+		}
+	}
+
+	if timeout := s.security.config.QueryTimeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var rows []map[string]any
+	if err := s.client(session).GetDB().Raw(query.SQL, query.Args...).Find(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	if rows == nil {
+		rows = []map[string]any{}
+	}
+
+	resultJSON, err := json.MarshalIndent(map[string]any{
+		"query":      query.SQL,
+		"parameters": query.Args,
+		"rows":       rows,
+		"count":      len(rows),
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: string(resultJSON)},
+		},
+	}, nil
+}
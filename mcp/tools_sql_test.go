@@ -0,0 +1,164 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rediwo/redi-orm/database"
+	_ "github.com/rediwo/redi-orm/drivers/sqlite"
+	"github.com/rediwo/redi-orm/logger"
+	"github.com/rediwo/redi-orm/masking"
+)
+
+const sqlTestSchema = `
+model User {
+  id    Int    @id @default(autoincrement())
+  name  String
+  email String @unique
+  posts Post[]
+}
+
+model Post {
+  id       Int    @id @default(autoincrement())
+  title    String
+  authorId Int
+  author   User   @relation(fields: [authorId], references: [id])
+}
+
+model Secret {
+  id    Int    @id @default(autoincrement())
+  value String
+}
+`
+
+func newSQLTestServer(t *testing.T, security SecurityConfig, policy *masking.Policy) *SDKServer {
+	db, err := database.NewFromURI("sqlite://:memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	ctx := context.Background()
+	require.NoError(t, db.Connect(ctx))
+	require.NoError(t, db.LoadSchema(ctx, sqlTestSchema))
+	require.NoError(t, db.SyncSchemas(ctx))
+
+	for _, name := range []string{"Alice", "Bob", "Charlie"} {
+		_, err := db.Model("User").Insert(map[string]any{"name": name, "email": name + "@example.com"}).Exec(ctx)
+		require.NoError(t, err)
+	}
+	_, err = db.Model("Post").Insert(map[string]any{"title": "Hello", "authorId": 1}).Exec(ctx)
+	require.NoError(t, err)
+
+	l := logger.NewDefaultLogger("TEST")
+	l.SetLevel(logger.LogLevelError)
+	return &SDKServer{
+		config:   ServerConfig{Masking: policy},
+		db:       db,
+		logger:   l,
+		security: NewSecurityManager(security),
+	}
+}
+
+func runSQLQuery(t *testing.T, s *SDKServer, ctx context.Context, args SQLQueryParams) (map[string]any, error) {
+	result, err := s.handleSQLQuery(ctx, nil, &mcp.CallToolParamsFor[SQLQueryParams]{Arguments: args})
+	if err != nil {
+		return nil, err
+	}
+	var output map[string]any
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &output))
+	return output, nil
+}
+
+func TestSQLQueryTool(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("returns rows and the normalized query", func(t *testing.T) {
+		s := newSQLTestServer(t, SecurityConfig{}, nil)
+		output, err := runSQLQuery(t, s, ctx, SQLQueryParams{
+			SQL:        "select u.name, p.title from users u join posts p on p.author_id = u.id where u.name = 'Alice' and u.id >= ?;",
+			Parameters: []any{1},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "SELECT u.name, p.title FROM users u JOIN posts p ON p.author_id = u.id WHERE u.name = ? AND u.id >= ? LIMIT 1000", output["query"])
+		assert.Equal(t, []any{"Alice", float64(1)}, output["parameters"])
+		assert.Equal(t, float64(1), output["count"])
+		rows := output["rows"].([]any)
+		assert.Equal(t, "Hello", rows[0].(map[string]any)["title"])
+	})
+
+	t.Run("caps rows with a LIMIT", func(t *testing.T) {
+		s := newSQLTestServer(t, SecurityConfig{MaxQueryRows: 10}, nil)
+		limit := 2
+		output, err := runSQLQuery(t, s, ctx, SQLQueryParams{SQL: "SELECT name FROM users ORDER BY name LIMIT 500", Limit: &limit})
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT name FROM users ORDER BY name LIMIT 2", output["query"])
+		assert.Equal(t, float64(2), output["count"])
+	})
+
+	t.Run("rejects writes", func(t *testing.T) {
+		s := newSQLTestServer(t, SecurityConfig{}, nil)
+		_, err := runSQLQuery(t, s, ctx, SQLQueryParams{SQL: "DELETE FROM users WHERE id = 1"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "only SELECT statements are allowed")
+
+		_, err = runSQLQuery(t, s, ctx, SQLQueryParams{SQL: "SELECT * FROM users; DELETE FROM users"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "single statement")
+	})
+
+	t.Run("rejects functions with side effects", func(t *testing.T) {
+		s := newSQLTestServer(t, SecurityConfig{}, nil)
+		output, err := runSQLQuery(t, s, ctx, SQLQueryParams{SQL: "SELECT COUNT(*) AS total, lower(name) FROM users GROUP BY name"})
+		require.NoError(t, err)
+		assert.Equal(t, float64(3), output["count"])
+
+		for _, query := range []string{
+			"SELECT setval('users_id_seq', 1) FROM users",
+			"SELECT pg_terminate_backend(1) FROM users",
+			"SELECT pg_read_file('/etc/passwd') FROM users",
+			"SELECT lo_import('/etc/passwd') FROM users",
+			"SELECT pg_catalog.pg_sleep(10) FROM users",
+		} {
+			_, err := runSQLQuery(t, s, ctx, SQLQueryParams{SQL: query})
+			require.Error(t, err, query)
+			assert.Contains(t, err.Error(), "function not allowed", query)
+		}
+	})
+
+	t.Run("checks the table allowlist", func(t *testing.T) {
+		s := newSQLTestServer(t, SecurityConfig{SQLTables: []string{"User", "posts"}}, nil)
+		_, err := runSQLQuery(t, s, ctx, SQLQueryParams{SQL: "SELECT * FROM users WHERE id IN (SELECT author_id FROM posts)"})
+		require.NoError(t, err)
+
+		_, err = runSQLQuery(t, s, ctx, SQLQueryParams{SQL: "SELECT * FROM users WHERE EXISTS (SELECT 1 FROM secrets)"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "table not allowed: secrets")
+
+		_, err = runSQLQuery(t, s, ctx, SQLQueryParams{SQL: "SELECT * FROM sqlite_master"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown table: sqlite_master")
+	})
+
+	t.Run("rejects models with masked fields for unprivileged callers", func(t *testing.T) {
+		policy := &masking.Policy{
+			Fields:          map[string]masking.Strategy{"User.email": masking.StrategyEmail},
+			PrivilegedRoles: []string{"admin"},
+		}
+		s := newSQLTestServer(t, SecurityConfig{}, policy)
+
+		_, err := runSQLQuery(t, s, ctx, SQLQueryParams{SQL: "SELECT title FROM posts"})
+		require.NoError(t, err)
+
+		_, err = runSQLQuery(t, s, ctx, SQLQueryParams{SQL: "SELECT name FROM users"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "User has masked fields")
+
+		_, err = runSQLQuery(t, s, masking.WithRoles(ctx, "admin"), SQLQueryParams{SQL: "SELECT email FROM users"})
+		require.NoError(t, err)
+	})
+}
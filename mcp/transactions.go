@@ -0,0 +1,250 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rediwo/redi-orm/logger"
+	"github.com/rediwo/redi-orm/orm"
+	"github.com/rediwo/redi-orm/types"
+)
+
+// Transaction tool parameters
+type TransactionBeginParams struct{}
+
+type TransactionCommitParams struct{}
+
+type TransactionRollbackParams struct{}
+
+// sessionTransaction is a transaction begun by a session, used by its tools until it ends
+type sessionTransaction struct {
+	tx        types.Transaction
+	client    *orm.Client
+	startedAt time.Time
+	expiresAt time.Time
+	timer     *time.Timer
+}
+
+// sessionTransactions holds the open transactions of the sessions of a server. A
+// transaction is rolled back when it times out or its session ends.
+type sessionTransactions struct {
+	mu      sync.Mutex
+	active  map[*mcp.ServerSession]*sessionTransaction
+	watched map[*mcp.ServerSession]bool
+	logger  logger.Logger
+}
+
+func newSessionTransactions(l logger.Logger) *sessionTransactions {
+	return &sessionTransactions{
+		active:  make(map[*mcp.ServerSession]*sessionTransaction),
+		watched: make(map[*mcp.ServerSession]bool),
+		logger:  l,
+	}
+}
+
+// begin starts a transaction for a session
+func (t *sessionTransactions) begin(session *mcp.ServerSession, db types.Database, timeout time.Duration) (*sessionTransaction, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.active[session]; ok {
+		return nil, fmt.Errorf("a transaction is already active for this session")
+	}
+
+	// The transaction outlives the tool call, so it must not be bound to its context
+	tx, err := db.Begin(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	now := time.Now()
+	st := &sessionTransaction{
+		tx:        tx,
+		client:    orm.NewTransactionClient(db, tx),
+		startedAt: now,
+		expiresAt: now.Add(timeout),
+	}
+	st.timer = time.AfterFunc(timeout, func() {
+		t.abandon(session, st, "timed out")
+	})
+	t.active[session] = st
+
+	// Sessions end without notifying the server, but Wait returns once they do
+	if session != nil && !t.watched[session] {
+		t.watched[session] = true
+		go func() {
+			session.Wait()
+			t.abandon(session, nil, "session ended")
+
+			t.mu.Lock()
+			delete(t.watched, session)
+			t.mu.Unlock()
+		}()
+	}
+
+	return st, nil
+}
+
+// get returns the active transaction of a session, or nil
+func (t *sessionTransactions) get(session *mcp.ServerSession) *sessionTransaction {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.active[session]
+}
+
+// end removes the active transaction of a session so it can be committed or rolled back
+func (t *sessionTransactions) end(session *mcp.ServerSession) (*sessionTransaction, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	st, ok := t.active[session]
+	if !ok {
+		return nil, fmt.Errorf("no transaction is active for this session")
+	}
+	st.timer.Stop()
+	delete(t.active, session)
+	return st, nil
+}
+
+// abandon rolls back the active transaction of a session; when st is not nil, only if it
+// is still that transaction
+func (t *sessionTransactions) abandon(session *mcp.ServerSession, st *sessionTransaction, reason string) {
+	t.mu.Lock()
+	active, ok := t.active[session]
+	if !ok || (st != nil && active != st) {
+		t.mu.Unlock()
+		return
+	}
+	active.timer.Stop()
+	delete(t.active, session)
+	t.mu.Unlock()
+
+	if err := active.tx.Rollback(context.Background()); err != nil {
+		t.logger.Error("Failed to roll back transaction that %s: %v", reason, err)
+		return
+	}
+	t.logger.Warn("Rolled back transaction that %s after %v", reason, time.Since(active.startedAt).Round(time.Millisecond))
+}
+
+// client returns the ORM client of a session, which runs in its transaction when one is active
+func (s *SDKServer) client(session *mcp.ServerSession) *orm.Client {
+	if s.transactions != nil {
+		if st := s.transactions.get(session); st != nil {
+			return st.client
+		}
+	}
+	return orm.NewClient(s.db)
+}
+
+// registerTransactionTools registers the tools grouping the calls of a session in a transaction
+func (s *SDKServer) registerTransactionTools() {
+	if s.transactions == nil {
+		s.transactions = newSessionTransactions(s.logger)
+	}
+
+	beginSchema, _ := jsonschema.For[TransactionBeginParams]()
+	addToolWithLogging[TransactionBeginParams, any](s, &mcp.Tool{
+		Name:        "transaction.begin",
+		Description: "Begin a transaction that the following tool calls of this session run in, until it is committed or rolled back",
+		InputSchema: beginSchema,
+	}, s.handleTransactionBegin)
+
+	commitSchema, _ := jsonschema.For[TransactionCommitParams]()
+	addToolWithLogging[TransactionCommitParams, any](s, &mcp.Tool{
+		Name:        "transaction.commit",
+		Description: "Commit the transaction of this session",
+		InputSchema: commitSchema,
+	}, s.handleTransactionCommit)
+
+	rollbackSchema, _ := jsonschema.For[TransactionRollbackParams]()
+	addToolWithLogging[TransactionRollbackParams, any](s, &mcp.Tool{
+		Name:        "transaction.rollback",
+		Description: "Roll back the transaction of this session",
+		InputSchema: rollbackSchema,
+	}, s.handleTransactionRollback)
+}
+
+func (s *SDKServer) handleTransactionBegin(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[TransactionBeginParams]) (*mcp.CallToolResultFor[any], error) {
+	// Check read-only mode
+	if err := s.security.CheckReadOnly("transaction.begin"); err != nil {
+		return nil, err
+	}
+
+	if s.db == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+
+	timeout := s.security.config.TransactionTimeout
+	st, err := s.transactions.begin(session, s.db, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return transactionResult(map[string]any{
+		"status":    "active",
+		"startedAt": st.startedAt.Format(time.RFC3339),
+		"expiresAt": st.expiresAt.Format(time.RFC3339),
+		"message":   fmt.Sprintf("Transaction begun; it is rolled back unless committed within %v", timeout),
+	})
+}
+
+func (s *SDKServer) handleTransactionCommit(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[TransactionCommitParams]) (*mcp.CallToolResultFor[any], error) {
+	// Check read-only mode
+	if err := s.security.CheckReadOnly("transaction.commit"); err != nil {
+		return nil, err
+	}
+
+	st, err := s.transactions.end(session)
+	if err != nil {
+		return nil, err
+	}
+	if err := st.tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return transactionResult(map[string]any{
+		"status":   "committed",
+		"duration": time.Since(st.startedAt).Round(time.Millisecond).String(),
+		"message":  "Transaction committed",
+	})
+}
+
+func (s *SDKServer) handleTransactionRollback(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[TransactionRollbackParams]) (*mcp.CallToolResultFor[any], error) {
+	// Check read-only mode
+	if err := s.security.CheckReadOnly("transaction.rollback"); err != nil {
+		return nil, err
+	}
+
+	st, err := s.transactions.end(session)
+	if err != nil {
+		return nil, err
+	}
+	if err := st.tx.Rollback(ctx); err != nil {
+		return nil, fmt.Errorf("failed to roll back transaction: %w", err)
+	}
+
+	return transactionResult(map[string]any{
+		"status":   "rolled back",
+		"duration": time.Since(st.startedAt).Round(time.Millisecond).String(),
+		"message":  "Transaction rolled back",
+	})
+}
+
+func transactionResult(result map[string]any) (*mcp.CallToolResultFor[any], error) {
+	resultJSON, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: string(resultJSON)},
+		},
+	}, nil
+}
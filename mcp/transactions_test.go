@@ -0,0 +1,168 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rediwo/redi-orm/database"
+	_ "github.com/rediwo/redi-orm/drivers/sqlite"
+	"github.com/rediwo/redi-orm/logger"
+)
+
+const transactionTestSchema = `
+model User {
+  id    Int    @id @default(autoincrement())
+  name  String
+  email String @unique
+}
+`
+
+// newTransactionTestServer uses a database file, as every connection to an in-memory
+// SQLite database opens a database of its own
+func newTransactionTestServer(t *testing.T, security SecurityConfig) *SDKServer {
+	db, err := database.NewFromURI("sqlite://" + filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	ctx := context.Background()
+	require.NoError(t, db.Connect(ctx))
+	require.NoError(t, db.LoadSchema(ctx, transactionTestSchema))
+	require.NoError(t, db.SyncSchemas(ctx))
+
+	l := logger.NewDefaultLogger("TEST")
+	l.SetLevel(logger.LogLevelError)
+	return &SDKServer{
+		db:           db,
+		logger:       l,
+		security:     NewSecurityManager(security),
+		transactions: newSessionTransactions(l),
+	}
+}
+
+func countUsers(t *testing.T, s *SDKServer) int64 {
+	count, err := s.db.Model("User").Select().Count(context.Background())
+	require.NoError(t, err)
+	return count
+}
+
+func createUser(t *testing.T, s *SDKServer, session *mcp.ServerSession, name string) {
+	_, err := s.handleModelCreate(context.Background(), session, &mcp.CallToolParamsFor[ModelCreateParams]{Arguments: ModelCreateParams{
+		Model: "User",
+		Data:  map[string]any{"name": name, "email": name + "@example.com"},
+	}})
+	require.NoError(t, err)
+}
+
+func TestSessionTransactions(t *testing.T) {
+	ctx := context.Background()
+	begin := &mcp.CallToolParamsFor[TransactionBeginParams]{}
+	commit := &mcp.CallToolParamsFor[TransactionCommitParams]{}
+	rollback := &mcp.CallToolParamsFor[TransactionRollbackParams]{}
+
+	t.Run("commits the calls of the session", func(t *testing.T) {
+		s := newTransactionTestServer(t, SecurityConfig{})
+
+		_, err := s.handleTransactionBegin(ctx, nil, begin)
+		require.NoError(t, err)
+		createUser(t, s, nil, "alice")
+		createUser(t, s, nil, "bob")
+
+		// The session reads its own writes, which are not visible outside until committed
+		result, err := s.handleModelCount(ctx, nil, &mcp.CallToolParamsFor[ModelCountParams]{Arguments: ModelCountParams{Model: "User"}})
+		require.NoError(t, err)
+		assert.Equal(t, "2", result.Content[0].(*mcp.TextContent).Text)
+		assert.Equal(t, int64(0), countUsers(t, s))
+
+		_, err = s.handleTransactionCommit(ctx, nil, commit)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), countUsers(t, s))
+
+		// Later calls run outside of a transaction
+		createUser(t, s, nil, "carol")
+		assert.Equal(t, int64(3), countUsers(t, s))
+	})
+
+	t.Run("rolls back the calls of the session", func(t *testing.T) {
+		s := newTransactionTestServer(t, SecurityConfig{})
+
+		_, err := s.handleTransactionBegin(ctx, nil, begin)
+		require.NoError(t, err)
+		createUser(t, s, nil, "alice")
+
+		_, err = s.handleTransactionRollback(ctx, nil, rollback)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), countUsers(t, s))
+	})
+
+	t.Run("allows one transaction per session", func(t *testing.T) {
+		s := newTransactionTestServer(t, SecurityConfig{})
+
+		_, err := s.handleTransactionCommit(ctx, nil, commit)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no transaction is active")
+
+		_, err = s.handleTransactionBegin(ctx, nil, begin)
+		require.NoError(t, err)
+		_, err = s.handleTransactionBegin(ctx, nil, begin)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already active")
+
+		_, err = s.handleTransactionRollback(ctx, nil, rollback)
+		require.NoError(t, err)
+	})
+
+	t.Run("rolls back on timeout", func(t *testing.T) {
+		s := newTransactionTestServer(t, SecurityConfig{TransactionTimeout: 50 * time.Millisecond})
+
+		_, err := s.handleTransactionBegin(ctx, nil, begin)
+		require.NoError(t, err)
+		createUser(t, s, nil, "alice")
+
+		require.Eventually(t, func() bool { return s.transactions.get(nil) == nil }, time.Second, 10*time.Millisecond)
+		assert.Equal(t, int64(0), countUsers(t, s))
+
+		_, err = s.handleTransactionCommit(ctx, nil, commit)
+		require.Error(t, err)
+	})
+
+	t.Run("rolls back when the session ends", func(t *testing.T) {
+		s := newTransactionTestServer(t, SecurityConfig{})
+		s.mcpServer = mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+		s.registerTools()
+		s.registerTransactionTools()
+
+		serverTransport, clientTransport := mcp.NewInMemoryTransports()
+		serverSession, err := s.mcpServer.Connect(ctx, serverTransport)
+		require.NoError(t, err)
+		clientSession, err := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "1.0.0"}, nil).Connect(ctx, clientTransport)
+		require.NoError(t, err)
+
+		_, err = clientSession.CallTool(ctx, &mcp.CallToolParams{Name: "transaction.begin", Arguments: map[string]any{}})
+		require.NoError(t, err)
+		result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{Name: "model.create", Arguments: map[string]any{
+			"model": "User",
+			"data":  map[string]any{"name": "alice", "email": "alice@example.com"},
+		}})
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+		require.NotNil(t, s.transactions.get(serverSession))
+
+		require.NoError(t, clientSession.Close())
+		require.Eventually(t, func() bool { return s.transactions.get(serverSession) == nil }, time.Second, 10*time.Millisecond)
+		assert.Equal(t, int64(0), countUsers(t, s))
+	})
+
+	t.Run("requires write mode", func(t *testing.T) {
+		s := newTransactionTestServer(t, SecurityConfig{ReadOnlyMode: true})
+
+		_, err := s.handleTransactionBegin(ctx, nil, begin)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "read-only")
+	})
+}
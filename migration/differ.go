@@ -5,12 +5,15 @@ import (
 	"fmt"
 
 	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/schema/generator"
 	"github.com/rediwo/redi-orm/types"
 )
 
 // Differ compares schemas and generates migration plans
 type Differ struct {
 	migrator types.DatabaseMigrator
+	// updatedAtTriggers adds triggers maintaining the @updatedAt columns of created tables
+	updatedAtTriggers bool
 }
 
 // NewDiffer creates a new schema differ
@@ -47,7 +50,11 @@ func (d *Differ) ComputeDiff(schemas map[string]*schema.Schema) ([]types.SchemaC
 				Type:      types.ChangeTypeCreateTable,
 				TableName: s.TableName,
 				SQL:       sql,
+				DownSQL:   []string{d.migrator.GenerateDropTableSQL(s.TableName)},
 			})
+			if d.updatedAtTriggers {
+				changes = append(changes, d.updatedAtTriggerChanges(s)...)
+			}
 		} else {
 			// Table exists, check for column changes
 			tableChanges, err := d.computeTableDiff(s)
@@ -66,7 +73,7 @@ func (d *Differ) ComputeDiff(schemas map[string]*schema.Schema) ([]types.SchemaC
 
 	for _, table := range currentTables {
 		// Skip system tables (like migrations table)
-		if table == MigrationsTableName {
+		if IsMigrationTable(table) {
 			continue
 		}
 
@@ -75,6 +82,7 @@ func (d *Differ) ComputeDiff(schemas map[string]*schema.Schema) ([]types.SchemaC
 				Type:      types.ChangeTypeDropTable,
 				TableName: table,
 				SQL:       d.migrator.GenerateDropTableSQL(table),
+				DownSQL:   d.recreateTableSQL(table),
 			})
 		}
 	}
@@ -108,11 +116,14 @@ func (d *Differ) computeTableDiff(s *schema.Schema) ([]types.SchemaChange, error
 	// Process columns
 	for i, change := range plan.AddColumns {
 		if i < len(sqlStatements) {
+			// Without down SQL, the generated migration notes the change cannot be reverted
+			downSQL, _ := d.migrator.GenerateDropColumnSQL(change.TableName, change.ColumnName)
 			changes = append(changes, types.SchemaChange{
 				Type:       types.ChangeTypeAddColumn,
 				TableName:  change.TableName,
 				ColumnName: change.ColumnName,
 				SQL:        sqlStatements[i],
+				DownSQL:    downSQL,
 			})
 		}
 	}
@@ -132,7 +143,8 @@ func (d *Differ) computeTableDiff(s *schema.Schema) ([]types.SchemaChange, error
 			Type:       types.ChangeTypeDropColumn,
 			TableName:  change.TableName,
 			ColumnName: change.ColumnName,
-			SQL:        fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", change.TableName, change.ColumnName),
+			SQL:        fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", d.quote(change.TableName), d.quote(change.ColumnName)),
+			DownSQL:    d.readdColumnSQL(tableInfo, change.ColumnName),
 		})
 	}
 
@@ -143,6 +155,7 @@ func (d *Differ) computeTableDiff(s *schema.Schema) ([]types.SchemaChange, error
 			TableName: change.TableName,
 			IndexName: change.IndexName,
 			SQL:       d.migrator.GenerateCreateIndexSQL(change.TableName, change.IndexName, change.NewIndex.Columns, change.NewIndex.Unique),
+			DownSQL:   []string{d.migrator.GenerateDropIndexSQL(change.IndexName)},
 		})
 	}
 
@@ -161,6 +174,9 @@ func (d *Differ) computeTableDiff(s *schema.Schema) ([]types.SchemaChange, error
 				Columns: change.OldIndex.Columns,
 				Unique:  change.OldIndex.Unique,
 			}
+			schemaChange.DownSQL = []string{
+				d.migrator.GenerateCreateIndexSQL(change.TableName, change.OldIndex.Name, change.OldIndex.Columns, change.OldIndex.Unique),
+			}
 		}
 
 		changes = append(changes, schemaChange)
@@ -169,6 +185,82 @@ func (d *Differ) computeTableDiff(s *schema.Schema) ([]types.SchemaChange, error
 	return changes, nil
 }
 
+// specific returns the database specific migrator, which describes existing tables as
+// schemas, or nil when the migrator has none
+func (d *Differ) specific() types.DatabaseSpecificMigrator {
+	if wrapper, ok := d.migrator.(interface {
+		GetSpecific() types.DatabaseSpecificMigrator
+	}); ok {
+		return wrapper.GetSpecific()
+	}
+	return nil
+}
+
+// quote quotes a name with the quoter of the database-specific migrator, if it has one
+func (d *Differ) quote(name string) string {
+	if quoter, ok := d.specific().(types.IdentifierQuoter); ok {
+		return quoter.QuoteIdentifier(name)
+	}
+	return name
+}
+
+// recreateTableSQL returns the statements creating a table as it is in the database, with
+// its indexes, to revert dropping it. Its rows are not restored. It returns nil when the
+// table cannot be described, leaving the change without down SQL.
+func (d *Differ) recreateTableSQL(table string) []string {
+	specific := d.specific()
+	if specific == nil {
+		return nil
+	}
+	tableInfo, err := d.migrator.GetTableInfo(table)
+	if err != nil {
+		return nil
+	}
+	s, err := generator.GenerateSchemaFromTable(tableInfo, specific)
+	if err != nil {
+		return nil
+	}
+
+	createSQL, err := d.migrator.GenerateCreateTableSQL(s)
+	if err != nil {
+		return nil
+	}
+	statements := []string{createSQL}
+
+	// Single-column unique indexes are part of the columns
+	for _, index := range tableInfo.Indexes {
+		if specific.IsPrimaryKeyIndex(index.Name) || specific.IsSystemIndex(index.Name) || (index.Unique && len(index.Columns) == 1) {
+			continue
+		}
+		statements = append(statements, d.migrator.GenerateCreateIndexSQL(table, index.Name, index.Columns, index.Unique))
+	}
+	return statements
+}
+
+// readdColumnSQL returns the statement adding a column as it is in the database, to revert
+// dropping it. Its values are not restored. It returns nil when the column cannot be
+// described, leaving the change without down SQL.
+func (d *Differ) readdColumnSQL(tableInfo *types.TableInfo, columnName string) []string {
+	specific := d.specific()
+	if specific == nil {
+		return nil
+	}
+	s, err := generator.GenerateSchemaFromTable(tableInfo, specific)
+	if err != nil {
+		return nil
+	}
+	field, err := s.GetFieldByColumnName(columnName)
+	if err != nil {
+		return nil
+	}
+
+	addSQL, err := d.migrator.GenerateAddColumnSQL(tableInfo.Name, *field)
+	if err != nil {
+		return nil
+	}
+	return []string{addSQL}
+}
+
 // ComputeChecksum computes a checksum for a migration plan
 func ComputeChecksum(changes []types.SchemaChange) string {
 	h := sha256.New()
@@ -181,3 +273,38 @@ func ComputeChecksum(changes []types.SchemaChange) string {
 
 	return fmt.Sprintf("%x", h.Sum(nil))
 }
+
+// updatedAtTriggerChanges returns the trigger changes maintaining the @updatedAt columns of
+// a table, or nil when the table has none or the database cannot generate triggers
+func (d *Differ) updatedAtTriggerChanges(s *schema.Schema) []types.SchemaChange {
+	var columns []string
+	for _, fieldName := range s.GetUpdatedAtFields() {
+		if field := s.GetFieldByName(fieldName); field != nil {
+			columns = append(columns, field.GetColumnName())
+		}
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+
+	wrapper, ok := d.migrator.(interface {
+		GetSpecific() types.DatabaseSpecificMigrator
+	})
+	if !ok {
+		return nil
+	}
+	generator, ok := wrapper.GetSpecific().(types.UpdatedAtTriggerGenerator)
+	if !ok {
+		return nil
+	}
+
+	var changes []types.SchemaChange
+	for _, sql := range generator.GenerateUpdatedAtTriggerSQL(s.TableName, columns) {
+		changes = append(changes, types.SchemaChange{
+			Type:      types.ChangeTypeAddTrigger,
+			TableName: s.TableName,
+			SQL:       sql,
+		})
+	}
+	return changes
+}
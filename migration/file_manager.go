@@ -1,6 +1,7 @@
 package migration
 
 import (
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -94,8 +95,9 @@ func (f *FileManager) ReadMigration(version string) (*types.MigrationFile, error
 		return nil, fmt.Errorf("failed to read up.sql: %w", err)
 	}
 
+	// down.sql is optional, migrations without it cannot be rolled back
 	downSQL, err := os.ReadFile(filepath.Join(migrationDir, "down.sql"))
-	if err != nil {
+	if err != nil && !os.IsNotExist(err) {
 		return nil, fmt.Errorf("failed to read down.sql: %w", err)
 	}
 
@@ -151,7 +153,7 @@ func (f *FileManager) ListMigrations() ([]*types.MigrationFile, error) {
 		migration, err := f.ReadMigration(version)
 		if err != nil {
 			// Log but don't fail on individual migration read errors
-			fmt.Printf("Warning: failed to read migration %s: %v\n", version, err)
+			fmt.Fprintf(os.Stderr, "Warning: failed to read migration %s: %v\n", version, err)
 			continue
 		}
 
@@ -166,6 +168,14 @@ func (f *FileManager) ListMigrations() ([]*types.MigrationFile, error) {
 	return migrations, nil
 }
 
+// FileChecksum computes the checksum of the up SQL of a migration, which is recorded when it
+// is applied to detect changes to the file afterwards. Line endings are normalized, so that
+// checkouts converting them do not count as changes.
+func FileChecksum(migration *types.MigrationFile) string {
+	upSQL := strings.ReplaceAll(migration.UpSQL, "\r\n", "\n")
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(upSQL)))
+}
+
 // GetPendingMigrations returns migrations that haven't been applied yet
 func (f *FileManager) GetPendingMigrations(appliedVersions map[string]bool) ([]*types.MigrationFile, error) {
 	allMigrations, err := f.ListMigrations()
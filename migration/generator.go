@@ -38,6 +38,11 @@ func (g *Generator) GenerateMigration(name string, schemas map[string]*schema.Sc
 		return nil, fmt.Errorf("no changes detected")
 	}
 
+	return g.generateMigration(name, changes, schemas)
+}
+
+// generateMigration generates a new migration applying changes
+func (g *Generator) generateMigration(name string, changes []types.SchemaChange, schemas map[string]*schema.Schema) (*types.MigrationFile, error) {
 	// Generate version
 	version := GenerateVersion()
 
@@ -94,8 +99,7 @@ func (g *Generator) generateSQL(changes []types.SchemaChange) (upSQL, downSQL st
 			if change.Type == types.ChangeTypeDropIndex {
 				upStatements = append(upStatements, change.SQL)
 				// For down SQL, we need to recreate the index
-				// This would need to be stored in metadata
-				downStatements = append([]string{g.generateCreateIndexSQL(change)}, downStatements...)
+				downStatements = append(revertStatements(change, g.generateCreateIndexSQL(change)), downStatements...)
 			}
 		}
 
@@ -104,28 +108,31 @@ func (g *Generator) generateSQL(changes []types.SchemaChange) (upSQL, downSQL st
 			switch change.Type {
 			case types.ChangeTypeCreateTable:
 				upStatements = append(upStatements, change.SQL)
-				downStatements = append([]string{fmt.Sprintf("DROP TABLE %s", tableName)}, downStatements...)
+				downStatements = append(revertStatements(change, fmt.Sprintf("DROP TABLE %s", g.differ.quote(tableName))), downStatements...)
 
 			case types.ChangeTypeDropTable:
 				upStatements = append(upStatements, change.SQL)
-				// For down SQL, we would need the full table definition stored
-				// This is a limitation - we can't recreate dropped tables without storing their schema
-				downStatements = append([]string{
+				// For down SQL, the table is recreated as it was in the database. Its rows are lost.
+				downStatements = append(revertStatements(change,
 					fmt.Sprintf("-- Cannot recreate table %s without stored schema", tableName),
-				}, downStatements...)
+				), downStatements...)
 
 			case types.ChangeTypeAddColumn:
 				upStatements = append(upStatements, change.SQL)
-				downStatements = append([]string{
-					fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", tableName, change.ColumnName),
-				}, downStatements...)
+				downStatements = append(revertStatements(change,
+					fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", g.differ.quote(tableName), g.differ.quote(change.ColumnName)),
+				), downStatements...)
 
 			case types.ChangeTypeDropColumn:
 				upStatements = append(upStatements, change.SQL)
-				// For down SQL, we would need the column definition stored
-				downStatements = append([]string{
+				// For down SQL, the column is added back as it was in the database. Its values are lost.
+				downStatements = append(revertStatements(change,
 					fmt.Sprintf("-- Cannot recreate column %s.%s without stored definition", tableName, change.ColumnName),
-				}, downStatements...)
+				), downStatements...)
+
+			case types.ChangeTypeAddTrigger:
+				// Triggers are dropped together with their table
+				upStatements = append(upStatements, change.SQL)
 
 			case types.ChangeTypeAlterColumn:
 				upStatements = append(upStatements, change.SQL)
@@ -140,9 +147,9 @@ func (g *Generator) generateSQL(changes []types.SchemaChange) (upSQL, downSQL st
 		for _, change := range changes {
 			if change.Type == types.ChangeTypeAddIndex {
 				upStatements = append(upStatements, change.SQL)
-				downStatements = append([]string{
-					fmt.Sprintf("DROP INDEX %s", change.IndexName),
-				}, downStatements...)
+				downStatements = append(revertStatements(change,
+					fmt.Sprintf("DROP INDEX %s", g.differ.quote(change.IndexName)),
+				), downStatements...)
 			}
 		}
 	}
@@ -154,6 +161,15 @@ func (g *Generator) generateSQL(changes []types.SchemaChange) (upSQL, downSQL st
 	return upSQL, downSQL, nil
 }
 
+// revertStatements returns the statements reverting a change, which the differ computes by
+// diffing back to the database, or fallback when it could not
+func revertStatements(change types.SchemaChange, fallback string) []string {
+	if len(change.DownSQL) > 0 {
+		return change.DownSQL
+	}
+	return []string{fallback}
+}
+
 // groupChangesByTable groups changes by table name
 func (g *Generator) groupChangesByTable(changes []types.SchemaChange) map[string][]types.SchemaChange {
 	grouped := make(map[string][]types.SchemaChange)
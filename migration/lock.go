@@ -0,0 +1,125 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rediwo/redi-orm/base"
+	"github.com/rediwo/redi-orm/types"
+)
+
+// DefaultLockTimeout is how long migrations wait for the migration lock by default
+const DefaultLockTimeout = time.Minute
+
+// migrationLock is the migration lock held by this process
+type migrationLock struct {
+	db    types.Database
+	owner string
+}
+
+// lock takes the migration lock unless the lock timeout is negative, and returns the function
+// releasing it. Databases implementing types.MigrationLocker lock with their own means, such
+// as PostgreSQL advisory locks, and the others with the lock table.
+func (r *Runner) lock(ctx context.Context) (func(), error) {
+	timeout := r.options.LockTimeout
+	if timeout < 0 {
+		return func() {}, nil
+	}
+	if timeout == 0 {
+		timeout = DefaultLockTimeout
+	}
+
+	if locker, ok := r.db.(types.MigrationLocker); ok {
+		unlock, err := locker.LockMigrations(ctx, timeout)
+		if err == nil {
+			return r.releaseWith(unlock), nil
+		}
+		if !errors.Is(err, types.ErrLockUnsupported) {
+			return nil, err
+		}
+	}
+
+	lock, err := r.acquireLock(ctx, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return r.releaseWith(lock.release), nil
+}
+
+// releaseWith returns a function releasing the lock with unlock, which only logs failures as
+// the lock is released after migrating
+func (r *Runner) releaseWith(unlock func() error) func() {
+	return func() {
+		if err := unlock(); err != nil {
+			r.warnf("Failed to release the migration lock: %v", err)
+		}
+	}
+}
+
+// acquireLock takes the lock of the lock table, waiting for the process holding it up to
+// timeout. The lock is a row of MigrationLockTableName, so it works across the processes
+// sharing a database whatever the driver.
+func (r *Runner) acquireLock(ctx context.Context, timeout time.Duration) (*migrationLock, error) {
+	create := `CREATE TABLE IF NOT EXISTS ` + MigrationLockTableName + ` (
+		id INTEGER PRIMARY KEY,
+		owner VARCHAR(255) NOT NULL,
+		locked_at BIGINT NOT NULL
+	)`
+	if _, err := r.db.Exec(create); err != nil {
+		return nil, fmt.Errorf("failed to create migration lock table: %w", err)
+	}
+
+	lock := &migrationLock{db: r.db, owner: base.MigrationLockOwner()}
+	deadline := time.Now().Add(timeout)
+	waiting := false
+	for attempt := 1; ; attempt++ {
+		_, insertErr := r.db.Exec(
+			"INSERT INTO "+MigrationLockTableName+" (id, owner, locked_at) VALUES (1, ?, ?)",
+			lock.owner, time.Now().Unix(),
+		)
+		if insertErr == nil {
+			return lock, nil
+		}
+
+		// The insert fails when another process holds the lock
+		var owner string
+		var lockedAt int64
+		err := r.db.QueryRow("SELECT owner, locked_at FROM "+MigrationLockTableName+" WHERE id = 1").Scan(&owner, &lockedAt)
+		if errors.Is(err, sql.ErrNoRows) && attempt < 3 {
+			// Released in the meantime
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to acquire migration lock: %w", insertErr)
+		}
+		if time.Since(time.Unix(lockedAt, 0)) > base.StaleMigrationLockAge {
+			r.warnf("Taking over the stale migration lock of %s", owner)
+			if _, err := r.db.Exec("DELETE FROM "+MigrationLockTableName+" WHERE id = 1 AND owner = ? AND locked_at = ?", owner, lockedAt); err != nil {
+				return nil, fmt.Errorf("failed to take over migration lock: %w", err)
+			}
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out after %s waiting for the migration lock held by %s", timeout, owner)
+		}
+		if !waiting {
+			r.infof("Waiting for the migration lock held by %s...", owner)
+			waiting = true
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(base.MigrationLockPollInterval):
+		}
+	}
+}
+
+// release gives the lock up
+func (l *migrationLock) release() error {
+	_, err := l.db.Exec("DELETE FROM "+MigrationLockTableName+" WHERE id = 1 AND owner = ?", l.owner)
+	return err
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/rediwo/redi-orm/schema"
 	"github.com/rediwo/redi-orm/types"
@@ -39,6 +40,7 @@ func NewManager(db types.Database, options types.MigrationOptions) (*Manager, er
 
 	history := NewHistoryManager(sqlDB.GetDB())
 	differ := NewDiffer(migrator)
+	differ.updatedAtTriggers = options.UpdatedAtTriggers
 
 	manager := &Manager{
 		db:       sqlDB.GetDB(),
@@ -53,31 +55,68 @@ func NewManager(db types.Database, options types.MigrationOptions) (*Manager, er
 	if options.Mode == types.MigrationModeFile && options.MigrationsDir != "" {
 		manager.fileManager = NewFileManager(options.MigrationsDir)
 		manager.generator = NewGenerator(migrator, manager.fileManager)
+		manager.generator.differ.updatedAtTriggers = options.UpdatedAtTriggers
 
 		runner, err := NewRunner(db, manager.fileManager)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create migration runner: %w", err)
 		}
+		runner.options = options
 		manager.runner = runner
 	}
 
 	return manager, nil
 }
 
-// Migrate performs migration based on schemas and configured mode
+// infof logs the progress of a migration
+func (m *Manager) infof(format string, args ...any) {
+	if m.options.Logger != nil {
+		m.options.Logger.Info(format, args...)
+	} else {
+		log.Printf(format, args...)
+	}
+}
+
+// warnf logs a warning of a migration
+func (m *Manager) warnf(format string, args ...any) {
+	if m.options.Logger != nil {
+		m.options.Logger.Warn(format, args...)
+	} else {
+		log.Printf("Warning: "+format, args...)
+	}
+}
+
+// Plan returns the changes auto-migration would make for schemas, without applying them
+func (m *Manager) Plan(schemas map[string]*schema.Schema) ([]types.SchemaChange, error) {
+	return m.differ.ComputeDiff(schemas)
+}
+
+// Migrate performs migration based on schemas and configured mode. Unless the LockTimeout
+// option is negative it holds the migration lock meanwhile, so that processes starting
+// together migrate one after another.
 func (m *Manager) Migrate(schemas map[string]*schema.Schema) error {
+	return m.migrate(context.Background(), schemas)
+}
+
+func (m *Manager) migrate(ctx context.Context, schemas map[string]*schema.Schema) error {
+	unlock, err := (&Runner{db: m.database, options: m.options}).lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// Handle file-based migrations
 	if m.options.Mode == types.MigrationModeFile {
-		return m.runFileMigrations()
+		return m.runFileMigrations(ctx)
 	}
 
 	// Default to auto-migration mode
-	return m.autoMigrate(schemas)
+	return m.autoMigrate(ctx, schemas)
 }
 
 // autoMigrate performs automatic migration based on schemas
-func (m *Manager) autoMigrate(schemas map[string]*schema.Schema) error {
-	log.Printf("Starting auto-migration process...")
+func (m *Manager) autoMigrate(ctx context.Context, schemas map[string]*schema.Schema) error {
+	m.infof("Starting auto-migration process...")
 
 	// Ensure migration history table exists
 	if err := m.history.EnsureMigrationTable(); err != nil {
@@ -91,7 +130,7 @@ func (m *Manager) autoMigrate(schemas map[string]*schema.Schema) error {
 	}
 
 	if len(changes) == 0 {
-		log.Printf("No schema changes detected.")
+		m.infof("No schema changes detected.")
 		return nil
 	}
 
@@ -99,7 +138,7 @@ func (m *Manager) autoMigrate(schemas map[string]*schema.Schema) error {
 	version := GenerateVersion()
 	checksum := ComputeChecksum(changes)
 
-	log.Printf("Generated migration plan with %d changes (version: %s)", len(changes), version)
+	m.infof("Generated migration plan with %d changes (version: %s)", len(changes), version)
 
 	// Check if this is a dry run
 	if m.options.DryRun {
@@ -109,12 +148,13 @@ func (m *Manager) autoMigrate(schemas map[string]*schema.Schema) error {
 
 	// Check for destructive changes
 	if m.hasDestructiveChanges(changes) && !m.options.Force {
-		log.Printf("Warning: Migration contains destructive changes:")
+		var destructive []string
 		for _, change := range changes {
 			if m.isDestructive(change) {
-				log.Printf("  - %s: %s.%s", change.Type, change.TableName, change.ColumnName)
+				destructive = append(destructive, fmt.Sprintf("  - %s: %s.%s", change.Type, change.TableName, change.ColumnName))
 			}
 		}
+		m.warnf("Migration contains destructive changes:\n%s", strings.Join(destructive, "\n"))
 
 		if !m.options.Force {
 			return fmt.Errorf("migration contains destructive changes. Use --force to proceed")
@@ -122,45 +162,77 @@ func (m *Manager) autoMigrate(schemas map[string]*schema.Schema) error {
 	}
 
 	// Execute migration
+	record := types.Migration{Version: version, Name: "auto-migration", Checksum: checksum}
+	if m.options.BeforeMigration != nil {
+		if err := m.options.BeforeMigration(ctx, record); err != nil {
+			return err
+		}
+	}
 	if err := m.executeMigration(version, changes, checksum); err != nil {
 		return fmt.Errorf("migration failed: %w", err)
 	}
 
-	log.Printf("Migration completed successfully (version: %s)", version)
+	m.infof("Migration completed successfully (version: %s)", version)
+	if m.options.AfterMigration != nil {
+		record.AppliedAt = time.Now()
+		m.options.AfterMigration(ctx, record)
+	}
 	return nil
 }
 
 // runFileMigrations runs file-based migrations from the configured directory
-func (m *Manager) runFileMigrations() error {
+func (m *Manager) runFileMigrations(ctx context.Context) error {
 	if m.runner == nil {
 		return fmt.Errorf("file-based migrations not configured")
 	}
 
-	ctx := context.Background()
 	return m.runner.RunMigrations(ctx)
 }
 
-// GenerateMigration generates a new migration file
-func (m *Manager) GenerateMigration(name string, schemas map[string]*schema.Schema) error {
+// GenerateMigration generates a new migration file and returns it
+func (m *Manager) GenerateMigration(name string, schemas map[string]*schema.Schema) (*types.MigrationFile, error) {
 	if m.generator == nil {
-		return fmt.Errorf("file-based migrations not configured")
+		return nil, fmt.Errorf("file-based migrations not configured")
 	}
 
 	migration, err := m.generator.GenerateMigration(name, schemas)
 	if err != nil {
-		return fmt.Errorf("failed to generate migration: %w", err)
+		return nil, fmt.Errorf("failed to generate migration: %w", err)
+	}
+
+	return m.writeMigration(migration)
+}
+
+// GenerateMigrationFromChanges generates a new migration file applying some of the changes
+// Plan returns for schemas, e.g. those approved one by one, and returns it
+func (m *Manager) GenerateMigrationFromChanges(name string, changes []types.SchemaChange, schemas map[string]*schema.Schema) (*types.MigrationFile, error) {
+	if m.generator == nil {
+		return nil, fmt.Errorf("file-based migrations not configured")
+	}
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("no changes to generate a migration from")
+	}
+
+	migration, err := m.generator.generateMigration(name, changes, schemas)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate migration: %w", err)
 	}
 
+	return m.writeMigration(migration)
+}
+
+// writeMigration writes a generated migration to the migrations directory
+func (m *Manager) writeMigration(migration *types.MigrationFile) (*types.MigrationFile, error) {
 	// Write migration to disk
 	if err := m.fileManager.WriteMigration(migration); err != nil {
-		return fmt.Errorf("failed to write migration: %w", err)
+		return nil, fmt.Errorf("failed to write migration: %w", err)
 	}
 
-	log.Printf("Generated migration: %s_%s", migration.Version, migration.Name)
-	log.Printf("  Up SQL: %s/up.sql", migration.Version+"_"+migration.Name)
-	log.Printf("  Down SQL: %s/down.sql", migration.Version+"_"+migration.Name)
+	m.infof("Generated migration: %s_%s", migration.Version, migration.Name)
+	m.infof("  Up SQL: %s/up.sql", migration.Version+"_"+migration.Name)
+	m.infof("  Down SQL: %s/down.sql", migration.Version+"_"+migration.Name)
 
-	return nil
+	return migration, nil
 }
 
 // RollbackMigration rolls back the last applied migration
@@ -184,10 +256,10 @@ func (m *Manager) executeMigration(version string, changes []types.SchemaChange,
 
 	// Execute each change
 	for i, change := range changes {
-		log.Printf("Executing change %d/%d: %s", i+1, len(changes), change.Type)
+		m.infof("Executing change %d/%d: %s", i+1, len(changes), change.Type)
 
 		if change.SQL == "" || strings.HasPrefix(change.SQL, "--") {
-			log.Printf("Skipping: %s", change.SQL)
+			m.infof("Skipping: %s", change.SQL)
 			continue
 		}
 
@@ -242,7 +314,7 @@ func (m *Manager) GetMigrationStatus() (*MigrationStatus, error) {
 
 // ResetMigrations drops all tables and clears migration history
 func (m *Manager) ResetMigrations() error {
-	log.Printf("Resetting all migrations...")
+	m.infof("Resetting all migrations...")
 
 	// Get all tables
 	tables, err := m.migrator.GetTables()
@@ -255,19 +327,19 @@ func (m *Manager) ResetMigrations() error {
 		sql := m.migrator.GenerateDropTableSQL(table)
 		_, err := m.db.Exec(sql)
 		if err != nil {
-			log.Printf("Warning: Failed to drop table %s: %v", table, err)
+			m.warnf("Failed to drop table %s: %v", table, err)
 		} else {
-			log.Printf("Dropped table: %s", table)
+			m.infof("Dropped table: %s", table)
 		}
 	}
 
 	// Clear migration history
 	_, err = m.db.Exec("DELETE FROM " + MigrationsTableName)
 	if err != nil {
-		log.Printf("Warning: Failed to clear migration history: %v", err)
+		m.warnf("Failed to clear migration history: %v", err)
 	}
 
-	log.Printf("Migration reset completed.")
+	m.infof("Migration reset completed.")
 	return nil
 }
 
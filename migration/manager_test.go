@@ -0,0 +1,106 @@
+package migration
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagerGenerateMigrationFromChanges(t *testing.T) {
+	db, schemas := newRunnerTestDatabase(t)
+	schemas["Post"] = schema.New("Post").
+		AddField(schema.NewField("id").Int().PrimaryKey().AutoIncrement().Build()).
+		AddField(schema.NewField("title").String().Build())
+	migrationsDir := filepath.Join(t.TempDir(), "migrations")
+	manager, err := NewManager(db, types.MigrationOptions{Mode: types.MigrationModeFile, MigrationsDir: migrationsDir})
+	require.NoError(t, err)
+
+	changes, err := manager.Plan(schemas)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+
+	// Only the given changes are written
+	var approved types.SchemaChange
+	for _, change := range changes {
+		if change.TableName == "posts" {
+			approved = change
+		}
+	}
+	file, err := manager.GenerateMigrationFromChanges("create_posts", []types.SchemaChange{approved}, schemas)
+	require.NoError(t, err)
+
+	written, err := NewFileManager(migrationsDir).ReadMigration(file.Version)
+	require.NoError(t, err)
+	assert.Equal(t, "create_posts", written.Name)
+	assert.Contains(t, written.UpSQL, "posts")
+	assert.False(t, strings.Contains(written.UpSQL, "users"), "unapproved changes should be left out")
+
+	_, err = manager.GenerateMigrationFromChanges("nothing", nil, schemas)
+	assert.Error(t, err)
+}
+
+func TestManagerDownScripts(t *testing.T) {
+	db, schemas := newRunnerTestDatabase(t)
+	migrationsDir := filepath.Join(t.TempDir(), "migrations")
+	newManager := func(force bool) *Manager {
+		manager, err := NewManager(db, types.MigrationOptions{Mode: types.MigrationModeFile, MigrationsDir: migrationsDir, Force: force})
+		require.NoError(t, err)
+		return manager
+	}
+	tables := func() []string {
+		tables, err := db.GetMigrator().GetTables()
+		require.NoError(t, err)
+		return tables
+	}
+	manager := newManager(false)
+
+	_, err := manager.GenerateMigration("create_users", schemas)
+	require.NoError(t, err)
+	require.NoError(t, manager.Migrate(schemas))
+
+	// Replacing users with posts is reverted by diffing the other way
+	posts := schema.New("Post").
+		AddField(schema.NewField("id").Int().PrimaryKey().AutoIncrement().Build()).
+		AddField(schema.NewField("title").String().Build())
+	file, err := manager.GenerateMigration("replace_users", map[string]*schema.Schema{"Post": posts})
+	require.NoError(t, err)
+	assert.Contains(t, file.DownSQL, "DROP TABLE")
+	assert.Contains(t, file.DownSQL, "CREATE TABLE")
+	assert.NotContains(t, file.DownSQL, "Cannot recreate")
+	require.NoError(t, manager.Migrate(nil))
+	assert.Contains(t, tables(), "posts")
+	assert.NotContains(t, tables(), "users")
+
+	require.NoError(t, manager.RollbackMigration())
+	assert.Contains(t, tables(), "users")
+	assert.NotContains(t, tables(), "posts")
+
+	// The recreated table has its columns and unique constraint back
+	_, err = db.Exec("INSERT INTO users (email) VALUES ('a@example.com')")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO users (email) VALUES ('a@example.com')")
+	assert.Error(t, err)
+
+	t.Run("WithoutDownScript", func(t *testing.T) {
+		require.NoError(t, os.Remove(filepath.Join(migrationsDir, file.Version+"_"+file.Name, "down.sql")))
+		require.NoError(t, manager.Migrate(nil))
+
+		err := manager.RollbackMigration()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "has no down script")
+		assert.Contains(t, tables(), "posts")
+
+		// Forcing removes the record only
+		require.NoError(t, newManager(true).RollbackMigration())
+		assert.Contains(t, tables(), "posts")
+		applied, err := manager.history.GetAppliedMigrations()
+		require.NoError(t, err)
+		assert.Len(t, applied, 1)
+	})
+}
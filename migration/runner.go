@@ -2,19 +2,30 @@ package migration
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
 	"fmt"
+	"log"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/rediwo/redi-orm/schema"
 	"github.com/rediwo/redi-orm/types"
 )
 
-// Runner executes file-based migrations
+// Runner executes file-based migrations. Applications embedding redi-orm create one with
+// New to migrate their database at startup with Run.
 type Runner struct {
 	db          types.Database
 	migrator    types.DatabaseMigrator
 	fileManager *FileManager
+	options     types.MigrationOptions
+}
+
+// Result is the outcome of Runner.Run
+type Result struct {
+	Applied []types.Migration // Migrations applied, oldest first
 }
 
 // NewRunner creates a new migration runner
@@ -28,8 +39,73 @@ func NewRunner(db types.Database, fileManager *FileManager) (*Runner, error) {
 	}, nil
 }
 
+// New creates a runner migrating db as options configure: auto-migration of the schemas
+// given to Run by default, or the migrations of options.MigrationsDir in file mode
+func New(db types.Database, options types.MigrationOptions) (*Runner, error) {
+	migrator := db.GetMigrator()
+	if migrator == nil {
+		return nil, fmt.Errorf("database does not support migrations")
+	}
+
+	runner := &Runner{
+		db:       db,
+		migrator: migrator,
+		options:  options,
+	}
+	if options.MigrationsDir != "" {
+		runner.fileManager = NewFileManager(options.MigrationsDir)
+	}
+	return runner, nil
+}
+
+// Run migrates the database: it auto-migrates schemas, or applies the pending migrations in
+// file mode. Unless LockTimeout is negative it holds the migration lock meanwhile, so that
+// processes starting together migrate one after another. The options' callbacks are called
+// around each migration.
+func (r *Runner) Run(ctx context.Context, schemas map[string]*schema.Schema) (*Result, error) {
+	// Collect the applied migrations through the callback
+	result := &Result{}
+	options := r.options
+	options.AfterMigration = func(ctx context.Context, migration types.Migration) {
+		result.Applied = append(result.Applied, migration)
+		if r.options.AfterMigration != nil {
+			r.options.AfterMigration(ctx, migration)
+		}
+	}
+
+	manager, err := NewManager(r.db, options)
+	if err != nil {
+		return nil, err
+	}
+	return result, manager.migrate(ctx, schemas)
+}
+
+// infof logs the progress of migrations
+func (r *Runner) infof(format string, args ...any) {
+	if r.options.Logger != nil {
+		r.options.Logger.Info(format, args...)
+	} else if r.db.GetLogger() != nil {
+		r.db.GetLogger().Info(format, args...)
+	}
+}
+
+// warnf logs a warning of migrations, with the standard logger when there is no other
+func (r *Runner) warnf(format string, args ...any) {
+	if r.options.Logger != nil {
+		r.options.Logger.Warn(format, args...)
+	} else if r.db.GetLogger() != nil {
+		r.db.GetLogger().Warn(format, args...)
+	} else {
+		log.Printf("Warning: "+format, args...)
+	}
+}
+
 // RunMigrations applies all pending migrations
 func (r *Runner) RunMigrations(ctx context.Context) error {
+	if r.fileManager == nil {
+		return fmt.Errorf("file-based migrations not configured")
+	}
+
 	// Ensure migrations table exists
 	if err := r.ensureMigrationsTable(); err != nil {
 		return fmt.Errorf("failed to ensure migrations table: %w", err)
@@ -41,6 +117,12 @@ func (r *Runner) RunMigrations(ctx context.Context) error {
 		return fmt.Errorf("failed to get applied migrations: %w", err)
 	}
 
+	// Refuse to go on when applied migrations were edited, as the database would not match
+	// the files anymore
+	if err := r.verifyChecksums(); err != nil {
+		return err
+	}
+
 	// Get pending migrations
 	pending, err := r.fileManager.GetPendingMigrations(applied)
 	if err != nil {
@@ -74,8 +156,34 @@ func (r *Runner) RunMigrations(ctx context.Context) error {
 	return nil
 }
 
+// PendingMigrations returns the migrations in the migrations directory that have not been
+// applied yet. Unlike RunMigrations it does not create the migrations table, so it can be
+// used to check a database without changing it.
+func (r *Runner) PendingMigrations() ([]*types.MigrationFile, error) {
+	if r.fileManager == nil {
+		return nil, fmt.Errorf("file-based migrations not configured")
+	}
+	applied := make(map[string]bool)
+
+	tables, err := r.migrator.GetTables()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tables: %w", err)
+	}
+	if slices.Contains(tables, MigrationsTableName) {
+		if applied, err = r.getAppliedMigrations(); err != nil {
+			return nil, fmt.Errorf("failed to get applied migrations: %w", err)
+		}
+	}
+
+	return r.fileManager.GetPendingMigrations(applied)
+}
+
 // RollbackMigration rolls back the last applied migration
 func (r *Runner) RollbackMigration(ctx context.Context) error {
+	if r.fileManager == nil {
+		return fmt.Errorf("file-based migrations not configured")
+	}
+
 	// Ensure migrations table exists
 	if err := r.ensureMigrationsTable(); err != nil {
 		return fmt.Errorf("failed to ensure migrations table: %w", err)
@@ -101,8 +209,14 @@ func (r *Runner) RollbackMigration(ctx context.Context) error {
 		r.db.GetLogger().Info("Rolling back migration %s: %s", migration.Version, migration.Name)
 	}
 
-	// Execute down SQL
-	if err := r.executeSQLScript(ctx, migration.DownSQL); err != nil {
+	// A migration without down script cannot be reverted. Forcing the rollback only removes
+	// its record, leaving its changes in the database.
+	if len(r.scriptStatements(migration.DownSQL)) == 0 {
+		if !r.options.Force {
+			return fmt.Errorf("migration %s_%s has no down script and cannot be rolled back; write its down.sql, or use --force to remove its record without reverting its changes", migration.Version, migration.Name)
+		}
+		r.warnf("Migration %s_%s has no down script, removing its record without reverting its changes", migration.Version, migration.Name)
+	} else if err := r.executeSQLScript(ctx, migration.DownSQL); err != nil {
 		return fmt.Errorf("failed to execute down SQL: %w", err)
 	}
 
@@ -128,31 +242,86 @@ func (r *Runner) applyMigration(ctx context.Context, migration *types.MigrationF
 		return fmt.Errorf("migration has no checksum")
 	}
 
-	// Execute up SQL
-	if err := r.executeSQLScript(ctx, migration.UpSQL); err != nil {
-		return fmt.Errorf("failed to execute up SQL: %w", err)
+	record := types.Migration{Version: migration.Version, Name: migration.Name, Checksum: FileChecksum(migration)}
+	if r.options.BeforeMigration != nil {
+		if err := r.options.BeforeMigration(ctx, record); err != nil {
+			return err
+		}
 	}
 
-	// Record migration
-	if err := r.recordMigration(migration); err != nil {
-		return fmt.Errorf("failed to record migration: %w", err)
+	// Execute up SQL and record migration
+	if r.db.GetCapabilities().SupportsTransactionalDDL() {
+		if err := r.applyInTransaction(ctx, migration); err != nil {
+			return err
+		}
+	} else if err := r.applyStatements(ctx, migration); err != nil {
+		return err
 	}
 
+	if r.options.AfterMigration != nil {
+		record.AppliedAt = time.Now()
+		r.options.AfterMigration(ctx, record)
+	}
 	return nil
 }
 
-// executeSQLScript executes a SQL script
-func (r *Runner) executeSQLScript(ctx context.Context, script string) error {
-	// Split script into individual statements
-	statements := r.splitSQLStatements(script)
+// applyInTransaction executes the up SQL of a migration and records it in one transaction, so
+// a failing statement leaves neither schema changes nor a record behind
+func (r *Runner) applyInTransaction(ctx context.Context, migration *types.MigrationFile) error {
+	return r.db.Transaction(ctx, func(tx types.Transaction) error {
+		for _, stmt := range r.scriptStatements(migration.UpSQL) {
+			if _, err := tx.Raw(stmt).Exec(ctx); err != nil {
+				return fmt.Errorf("failed to execute up SQL: failed to execute SQL: %w\nStatement: %s", err, stmt)
+			}
+		}
+		if _, err := tx.Raw(recordMigrationSQL(migration)).Exec(ctx); err != nil {
+			return fmt.Errorf("failed to record migration: %w", err)
+		}
+		return nil
+	})
+}
 
-	// Execute each statement
-	for _, stmt := range statements {
-		stmt = strings.TrimSpace(stmt)
-		if stmt == "" || strings.HasPrefix(stmt, "--") {
-			continue
+// applyStatements executes the up SQL of a migration statement by statement, for databases
+// whose schema changes commit by themselves, such as MySQL. The statements executed so far
+// are kept in MigrationProgressTableName, so that once the failing statement is fixed,
+// applying the migration again resumes after them instead of failing on the changes they
+// already made.
+func (r *Runner) applyStatements(ctx context.Context, migration *types.MigrationFile) error {
+	if err := r.ensureProgressTable(); err != nil {
+		return fmt.Errorf("failed to ensure migration progress table: %w", err)
+	}
+
+	statements := r.scriptStatements(migration.UpSQL)
+	done, checksum, err := r.getProgress(migration.Version)
+	if err != nil {
+		return fmt.Errorf("failed to get migration progress: %w", err)
+	}
+	if done > 0 {
+		if done > len(statements) || statementsChecksum(statements[:done]) != checksum {
+			return fmt.Errorf("migration %s was partially applied and its first %d statements changed since; "+
+				"complete or revert them by hand, then delete its row from %s", migration.Version, done, MigrationProgressTableName)
 		}
+		r.infof("Resuming migration %s after %d executed statement(s)", migration.Version, done)
+	}
+
+	for i := done; i < len(statements); i++ {
+		if err := r.migrator.ApplyMigration(statements[i]); err != nil {
+			return fmt.Errorf("failed to execute up SQL: failed to execute SQL: %w\nStatement: %s", err, statements[i])
+		}
+		if err := r.saveProgress(migration.Version, statements[:i+1]); err != nil {
+			return fmt.Errorf("failed to save migration progress: %w", err)
+		}
+	}
+
+	if err := r.recordMigration(migration); err != nil {
+		return fmt.Errorf("failed to record migration: %w", err)
+	}
+	return r.migrator.ApplyMigration(fmt.Sprintf(`DELETE FROM %s WHERE version = '%s'`, MigrationProgressTableName, migration.Version))
+}
 
+// executeSQLScript executes a SQL script
+func (r *Runner) executeSQLScript(ctx context.Context, script string) error {
+	for _, stmt := range r.scriptStatements(script) {
 		if err := r.migrator.ApplyMigration(stmt); err != nil {
 			return fmt.Errorf("failed to execute SQL: %w\nStatement: %s", err, stmt)
 		}
@@ -161,6 +330,19 @@ func (r *Runner) executeSQLScript(ctx context.Context, script string) error {
 	return nil
 }
 
+// scriptStatements returns the statements of a SQL script to execute
+func (r *Runner) scriptStatements(script string) []string {
+	var statements []string
+	for _, stmt := range r.splitSQLStatements(script) {
+		stmt = strings.TrimSpace(stmt)
+		if stmt == "" || strings.HasPrefix(stmt, "--") {
+			continue
+		}
+		statements = append(statements, stmt)
+	}
+	return statements
+}
+
 // splitSQLStatements splits a SQL script into individual statements
 func (r *Runner) splitSQLStatements(script string) []string {
 	var statements []string
@@ -234,6 +416,74 @@ func (r *Runner) getAppliedMigrations() (map[string]bool, error) {
 	return applied, rows.Err()
 }
 
+// ChecksumMismatchError reports applied migrations whose files changed after they were applied
+type ChecksumMismatchError struct {
+	Migrations []string // The version and name of each changed migration
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("applied migrations were modified after they were applied: %s", strings.Join(e.Migrations, ", "))
+}
+
+// verifyChecksums checks that the files of applied migrations did not change since they were
+// applied, unless the IgnoreChecksums option is set. Migrations recorded by older versions
+// hold the checksum of their metadata instead, and are recorded with the checksum of their
+// file.
+func (r *Runner) verifyChecksums() error {
+	recorded, err := r.getAppliedChecksums()
+	if err != nil {
+		return fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+	migrations, err := r.fileManager.ListMigrations()
+	if err != nil {
+		return err
+	}
+
+	var modified []string
+	for _, migration := range migrations {
+		checksum, ok := recorded[migration.Version]
+		if !ok || checksum == FileChecksum(migration) {
+			continue
+		}
+		if checksum == migration.Metadata.Checksum {
+			if err := r.updateChecksum(migration); err != nil {
+				return fmt.Errorf("failed to record checksum of migration %s: %w", migration.Version, err)
+			}
+			continue
+		}
+		modified = append(modified, migration.Version+"_"+migration.Name)
+	}
+
+	if len(modified) == 0 {
+		return nil
+	}
+	if r.options.IgnoreChecksums {
+		r.warnf("Ignoring changes to applied migrations: %s", strings.Join(modified, ", "))
+		return nil
+	}
+	return &ChecksumMismatchError{Migrations: modified}
+}
+
+// getAppliedChecksums returns the checksums of the applied migrations by version
+func (r *Runner) getAppliedChecksums() (map[string]string, error) {
+	rows, err := r.db.Query(`SELECT version, checksum FROM ` + MigrationsTableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	checksums := make(map[string]string)
+	for rows.Next() {
+		var version, checksum string
+		if err := rows.Scan(&version, &checksum); err != nil {
+			return nil, err
+		}
+		checksums[version] = checksum
+	}
+
+	return checksums, rows.Err()
+}
+
 // getLastMigration returns the last applied migration
 func (r *Runner) getLastMigration() (*types.Migration, error) {
 	query := `SELECT version, name, checksum, applied_at FROM ` + MigrationsTableName +
@@ -253,13 +503,65 @@ func (r *Runner) getLastMigration() (*types.Migration, error) {
 
 // recordMigration records a migration in the migrations table
 func (r *Runner) recordMigration(migration *types.MigrationFile) error {
-	query := fmt.Sprintf(`INSERT INTO %s (version, name, checksum, applied_at) VALUES ('%s', '%s', '%s', '%s')`,
+	return r.migrator.ApplyMigration(recordMigrationSQL(migration))
+}
+
+// recordMigrationSQL returns the statement recording a migration in the migrations table
+func recordMigrationSQL(migration *types.MigrationFile) string {
+	return fmt.Sprintf(`INSERT INTO %s (version, name, checksum, applied_at) VALUES ('%s', '%s', '%s', '%s')`,
 		MigrationsTableName,
 		migration.Version,
 		migration.Name,
-		migration.Metadata.Checksum,
+		FileChecksum(migration),
 		time.Now().Format("2006-01-02 15:04:05"),
 	)
+}
+
+// ensureProgressTable creates the table of the statements executed of partially applied
+// migrations if it doesn't exist
+func (r *Runner) ensureProgressTable() error {
+	sql := `CREATE TABLE IF NOT EXISTS ` + MigrationProgressTableName + ` (
+		version VARCHAR(255) NOT NULL PRIMARY KEY,
+		statements INTEGER NOT NULL,
+		checksum VARCHAR(64) NOT NULL
+	)`
+
+	return r.migrator.ApplyMigration(sql)
+}
+
+// getProgress returns the number of statements of a migration executed so far and their
+// checksum, or 0 when the migration was not started
+func (r *Runner) getProgress(version string) (int, string, error) {
+	query := `SELECT statements, checksum FROM ` + MigrationProgressTableName + ` WHERE version = ?`
+
+	var statements int
+	var checksum string
+	err := r.db.QueryRow(query, version).Scan(&statements, &checksum)
+	if err == sql.ErrNoRows {
+		return 0, "", nil
+	}
+	return statements, checksum, err
+}
+
+// saveProgress records the statements of a migration executed so far
+func (r *Runner) saveProgress(version string, executed []string) error {
+	if err := r.migrator.ApplyMigration(fmt.Sprintf(`DELETE FROM %s WHERE version = '%s'`, MigrationProgressTableName, version)); err != nil {
+		return err
+	}
+	return r.migrator.ApplyMigration(fmt.Sprintf(`INSERT INTO %s (version, statements, checksum) VALUES ('%s', %d, '%s')`,
+		MigrationProgressTableName, version, len(executed), statementsChecksum(executed)))
+}
+
+// statementsChecksum computes the checksum of executed statements, to tell whether they
+// changed when resuming a migration
+func statementsChecksum(statements []string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(statements, "\n"))))
+}
+
+// updateChecksum records the checksum of the file of an applied migration
+func (r *Runner) updateChecksum(migration *types.MigrationFile) error {
+	query := fmt.Sprintf(`UPDATE %s SET checksum = '%s' WHERE version = '%s'`,
+		MigrationsTableName, FileChecksum(migration), migration.Version)
 
 	return r.migrator.ApplyMigration(query)
 }
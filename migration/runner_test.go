@@ -0,0 +1,367 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rediwo/redi-orm/base"
+	"github.com/rediwo/redi-orm/database"
+	_ "github.com/rediwo/redi-orm/drivers/sqlite"
+	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRunnerTestDatabase(t *testing.T) (database.Database, map[string]*schema.Schema) {
+	t.Helper()
+	db, err := database.NewFromURI("sqlite://" + filepath.Join(t.TempDir(), "app.db"))
+	require.NoError(t, err)
+	require.NoError(t, db.Connect(context.Background()))
+	t.Cleanup(func() { db.Close() })
+
+	user := schema.New("User").
+		AddField(schema.NewField("id").Int().PrimaryKey().AutoIncrement().Build()).
+		AddField(schema.NewField("email").String().Unique().Build())
+	return db, map[string]*schema.Schema{"User": user}
+}
+
+func lockRows(t *testing.T, db database.Database) int {
+	t.Helper()
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM "+MigrationLockTableName).Scan(&count))
+	return count
+}
+
+func assertUnlocked(t *testing.T, db database.Database) {
+	t.Helper()
+	unlock, err := db.(types.MigrationLocker).LockMigrations(context.Background(), 0)
+	require.NoError(t, err, "the lock should be released")
+	require.NoError(t, unlock())
+}
+
+func TestRunnerRun(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("AutoMigrate", func(t *testing.T) {
+		db, schemas := newRunnerTestDatabase(t)
+		var before, after []types.Migration
+		runner, err := New(db, types.MigrationOptions{
+			BeforeMigration: func(ctx context.Context, m types.Migration) error {
+				before = append(before, m)
+				return nil
+			},
+			AfterMigration: func(ctx context.Context, m types.Migration) {
+				after = append(after, m)
+			},
+		})
+		require.NoError(t, err)
+
+		result, err := runner.Run(ctx, schemas)
+		require.NoError(t, err)
+		require.Len(t, result.Applied, 1)
+		assert.Equal(t, "auto-migration", result.Applied[0].Name)
+		assert.Len(t, before, 1)
+		assert.Equal(t, result.Applied, after)
+
+		tables, err := db.GetMigrator().GetTables()
+		require.NoError(t, err)
+		assert.Contains(t, tables, "users")
+		assertUnlocked(t, db)
+
+		// Nothing is applied when the schemas are up to date
+		result, err = runner.Run(ctx, schemas)
+		require.NoError(t, err)
+		assert.Empty(t, result.Applied)
+	})
+
+	t.Run("BeforeMigrationError", func(t *testing.T) {
+		db, schemas := newRunnerTestDatabase(t)
+		stop := errors.New("not now")
+		runner, err := New(db, types.MigrationOptions{
+			BeforeMigration: func(ctx context.Context, m types.Migration) error { return stop },
+		})
+		require.NoError(t, err)
+
+		_, err = runner.Run(ctx, schemas)
+		assert.ErrorIs(t, err, stop)
+		tables, err := db.GetMigrator().GetTables()
+		require.NoError(t, err)
+		assert.NotContains(t, tables, "users")
+		assertUnlocked(t, db)
+	})
+
+	t.Run("FileMigrations", func(t *testing.T) {
+		db, _ := newRunnerTestDatabase(t)
+		migrationsDir := filepath.Join(t.TempDir(), "migrations")
+		require.NoError(t, NewFileManager(migrationsDir).WriteMigration(&types.MigrationFile{
+			Version:  "20240101000000",
+			Name:     "create_users",
+			UpSQL:    "CREATE TABLE users (id INTEGER PRIMARY KEY);",
+			DownSQL:  "DROP TABLE users;",
+			Metadata: types.MigrationMetadata{Version: "20240101000000", Name: "create_users", Checksum: "abc"},
+		}))
+
+		runner, err := New(db, types.MigrationOptions{Mode: types.MigrationModeFile, MigrationsDir: migrationsDir})
+		require.NoError(t, err)
+		result, err := runner.Run(ctx, nil)
+		require.NoError(t, err)
+		require.Len(t, result.Applied, 1)
+		assert.Equal(t, "create_users", result.Applied[0].Name)
+
+		pending, err := runner.PendingMigrations()
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+	})
+}
+
+func TestRunnerLock(t *testing.T) {
+	ctx := context.Background()
+	defer func(interval time.Duration) { base.MigrationLockPollInterval = interval }(base.MigrationLockPollInterval)
+	base.MigrationLockPollInterval = 10 * time.Millisecond
+
+	// Databases with a lock of their own are locked with it
+	db, schemas := newRunnerTestDatabase(t)
+	unlock, err := db.(types.MigrationLocker).LockMigrations(ctx, time.Second)
+	require.NoError(t, err)
+
+	// Other processes wait for the lock, up to their timeout
+	runner, err := New(db, types.MigrationOptions{LockTimeout: 50 * time.Millisecond})
+	require.NoError(t, err)
+	_, err = runner.Run(ctx, schemas)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out after 50ms waiting for the migration lock")
+
+	// and migrate once it is released
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		unlock()
+	}()
+	runner, err = New(db, types.MigrationOptions{LockTimeout: time.Second})
+	require.NoError(t, err)
+	result, err := runner.Run(ctx, schemas)
+	require.NoError(t, err)
+	assert.Len(t, result.Applied, 1)
+
+	// Without the lock, runs do not wait
+	unlock, err = db.(types.MigrationLocker).LockMigrations(ctx, time.Second)
+	require.NoError(t, err)
+	defer unlock()
+	runner, err = New(db, types.MigrationOptions{LockTimeout: -1})
+	require.NoError(t, err)
+	_, err = runner.Run(ctx, schemas)
+	assert.NoError(t, err)
+}
+
+func TestRunnerTableLock(t *testing.T) {
+	ctx := context.Background()
+	defer func(interval time.Duration) { base.MigrationLockPollInterval = interval }(base.MigrationLockPollInterval)
+	base.MigrationLockPollInterval = 10 * time.Millisecond
+
+	db, _ := newRunnerTestDatabase(t)
+	holder, err := New(db, types.MigrationOptions{})
+	require.NoError(t, err)
+	lock, err := holder.acquireLock(ctx, time.Second)
+	require.NoError(t, err)
+
+	// Other processes wait for the lock, up to their timeout
+	runner, err := New(db, types.MigrationOptions{})
+	require.NoError(t, err)
+	_, err = runner.acquireLock(ctx, 50*time.Millisecond)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "waiting for the migration lock held by "+lock.owner)
+
+	// and take it once it is released
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		lock.release()
+	}()
+	lock, err = runner.acquireLock(ctx, time.Second)
+	require.NoError(t, err)
+	require.NoError(t, lock.release())
+	assert.Equal(t, 0, lockRows(t, db))
+
+	// Stale locks of crashed processes are taken over
+	_, err = db.Exec("INSERT INTO "+MigrationLockTableName+" (id, owner, locked_at) VALUES (1, 'crashed', ?)",
+		time.Now().Add(-base.StaleMigrationLockAge-time.Minute).Unix())
+	require.NoError(t, err)
+	lock, err = runner.acquireLock(ctx, time.Second)
+	require.NoError(t, err)
+	require.NoError(t, lock.release())
+	assert.Equal(t, 0, lockRows(t, db))
+}
+
+func TestRunnerChecksums(t *testing.T) {
+	ctx := context.Background()
+	db, _ := newRunnerTestDatabase(t)
+	migrationsDir := filepath.Join(t.TempDir(), "migrations")
+	fileManager := NewFileManager(migrationsDir)
+	usersMigration := &types.MigrationFile{
+		Version:  "20240101000000",
+		Name:     "create_users",
+		UpSQL:    "CREATE TABLE users (id INTEGER PRIMARY KEY);",
+		DownSQL:  "DROP TABLE users;",
+		Metadata: types.MigrationMetadata{Version: "20240101000000", Name: "create_users", Checksum: "abc"},
+	}
+	require.NoError(t, fileManager.WriteMigration(usersMigration))
+
+	runner, err := New(db, types.MigrationOptions{Mode: types.MigrationModeFile, MigrationsDir: migrationsDir})
+	require.NoError(t, err)
+	result, err := runner.Run(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, result.Applied, 1)
+	assert.Equal(t, FileChecksum(usersMigration), result.Applied[0].Checksum)
+
+	// Line endings do not count as changes
+	assert.Equal(t,
+		FileChecksum(&types.MigrationFile{UpSQL: "CREATE TABLE users (id INTEGER PRIMARY KEY);\n"}),
+		FileChecksum(&types.MigrationFile{UpSQL: "CREATE TABLE users (id INTEGER PRIMARY KEY);\r\n"}))
+
+	// Editing an applied migration stops the next migrations
+	upPath := filepath.Join(migrationsDir, "20240101000000_create_users", "up.sql")
+	require.NoError(t, os.WriteFile(upPath, []byte("CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT);"), 0644))
+	require.NoError(t, fileManager.WriteMigration(&types.MigrationFile{
+		Version:  "20240102000000",
+		Name:     "create_posts",
+		UpSQL:    "CREATE TABLE posts (id INTEGER PRIMARY KEY);",
+		DownSQL:  "DROP TABLE posts;",
+		Metadata: types.MigrationMetadata{Version: "20240102000000", Name: "create_posts", Checksum: "def"},
+	}))
+	_, err = runner.Run(ctx, nil)
+	var mismatch *ChecksumMismatchError
+	require.ErrorAs(t, err, &mismatch)
+	assert.Equal(t, []string{"20240101000000_create_users"}, mismatch.Migrations)
+
+	// unless checksums are ignored
+	runner, err = New(db, types.MigrationOptions{Mode: types.MigrationModeFile, MigrationsDir: migrationsDir, IgnoreChecksums: true})
+	require.NoError(t, err)
+	result, err = runner.Run(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, result.Applied, 1)
+	assert.Equal(t, "create_posts", result.Applied[0].Name)
+
+	// Migrations recorded with the checksum of their metadata get the checksum of their file
+	_, err = db.Exec("UPDATE " + MigrationsTableName + " SET checksum = 'abc' WHERE version = '20240101000000'")
+	require.NoError(t, err)
+	runner, err = New(db, types.MigrationOptions{Mode: types.MigrationModeFile, MigrationsDir: migrationsDir})
+	require.NoError(t, err)
+	_, err = runner.Run(ctx, nil)
+	require.NoError(t, err)
+	var checksum string
+	require.NoError(t, db.QueryRow("SELECT checksum FROM "+MigrationsTableName+" WHERE version = '20240101000000'").Scan(&checksum))
+	edited, err := fileManager.ReadMigration("20240101000000")
+	require.NoError(t, err)
+	assert.Equal(t, FileChecksum(edited), checksum)
+}
+
+// statementDDLDatabase reports schema changes as not transactional, like MySQL
+type statementDDLDatabase struct {
+	database.Database
+}
+
+func (d statementDDLDatabase) GetDB() *sql.DB {
+	return d.Database.(interface{ GetDB() *sql.DB }).GetDB()
+}
+
+func (d statementDDLDatabase) GetCapabilities() types.DriverCapabilities {
+	return statementDDLCapabilities{d.Database.GetCapabilities()}
+}
+
+type statementDDLCapabilities struct {
+	types.DriverCapabilities
+}
+
+func (statementDDLCapabilities) SupportsTransactionalDDL() bool { return false }
+
+func TestRunnerFailedMigrations(t *testing.T) {
+	ctx := context.Background()
+	writeMigration := func(t *testing.T, migrationsDir, upSQL string) {
+		t.Helper()
+		require.NoError(t, NewFileManager(migrationsDir).WriteMigration(&types.MigrationFile{
+			Version:  "20240101000000",
+			Name:     "create_tables",
+			UpSQL:    upSQL,
+			DownSQL:  "DROP TABLE posts;\nDROP TABLE users;",
+			Metadata: types.MigrationMetadata{Version: "20240101000000", Name: "create_tables", Checksum: "abc"},
+		}))
+	}
+	count := func(t *testing.T, db database.Database, table string) int {
+		t.Helper()
+		var count int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM "+table).Scan(&count))
+		return count
+	}
+	failing := "CREATE TABLE users (id INTEGER PRIMARY KEY);\nCREATE TABLE posts (id INTEGER PRIMARY KEY,);\n"
+	fixed := "CREATE TABLE users (id INTEGER PRIMARY KEY);\nCREATE TABLE posts (id INTEGER PRIMARY KEY);\n"
+
+	t.Run("Transactional", func(t *testing.T) {
+		db, _ := newRunnerTestDatabase(t)
+		migrationsDir := filepath.Join(t.TempDir(), "migrations")
+		writeMigration(t, migrationsDir, failing)
+
+		runner, err := New(db, types.MigrationOptions{Mode: types.MigrationModeFile, MigrationsDir: migrationsDir})
+		require.NoError(t, err)
+		_, err = runner.Run(ctx, nil)
+		require.Error(t, err)
+
+		// The statements before the failing one are rolled back with it
+		tables, err := db.GetMigrator().GetTables()
+		require.NoError(t, err)
+		assert.NotContains(t, tables, "users")
+		assert.Equal(t, 0, count(t, db, MigrationsTableName))
+
+		writeMigration(t, migrationsDir, fixed)
+		result, err := runner.Run(ctx, nil)
+		require.NoError(t, err)
+		assert.Len(t, result.Applied, 1)
+	})
+
+	t.Run("StatementByStatement", func(t *testing.T) {
+		sqliteDB, _ := newRunnerTestDatabase(t)
+		db := statementDDLDatabase{sqliteDB}
+		migrationsDir := filepath.Join(t.TempDir(), "migrations")
+		writeMigration(t, migrationsDir, failing)
+
+		runner, err := New(db, types.MigrationOptions{Mode: types.MigrationModeFile, MigrationsDir: migrationsDir})
+		require.NoError(t, err)
+		_, err = runner.Run(ctx, nil)
+		require.Error(t, err)
+
+		// The statements executed are kept, but the migration is not recorded
+		tables, err := db.GetMigrator().GetTables()
+		require.NoError(t, err)
+		assert.Contains(t, tables, "users")
+		assert.Equal(t, 0, count(t, db, MigrationsTableName))
+		assert.Equal(t, 1, count(t, db, MigrationProgressTableName))
+
+		// The fixed migration resumes after them
+		writeMigration(t, migrationsDir, fixed)
+		result, err := runner.Run(ctx, nil)
+		require.NoError(t, err)
+		assert.Len(t, result.Applied, 1)
+		assert.Equal(t, 1, count(t, db, MigrationsTableName))
+		assert.Equal(t, 0, count(t, db, MigrationProgressTableName))
+	})
+
+	t.Run("ChangedExecutedStatements", func(t *testing.T) {
+		sqliteDB, _ := newRunnerTestDatabase(t)
+		db := statementDDLDatabase{sqliteDB}
+		migrationsDir := filepath.Join(t.TempDir(), "migrations")
+		writeMigration(t, migrationsDir, failing)
+
+		runner, err := New(db, types.MigrationOptions{Mode: types.MigrationModeFile, MigrationsDir: migrationsDir})
+		require.NoError(t, err)
+		_, err = runner.Run(ctx, nil)
+		require.Error(t, err)
+
+		writeMigration(t, migrationsDir, "CREATE TABLE accounts (id INTEGER PRIMARY KEY);\nCREATE TABLE posts (id INTEGER PRIMARY KEY);\n")
+		_, err = runner.Run(ctx, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "was partially applied and its first 1 statements changed")
+	})
+}
@@ -3,4 +3,17 @@ package migration
 const (
 	// MigrationsTableName is the name of the table that stores migration history
 	MigrationsTableName = "redi_migrations"
+
+	// MigrationLockTableName is the name of the table whose row is held by the process
+	// migrating the database
+	MigrationLockTableName = "redi_migration_lock"
+
+	// MigrationProgressTableName is the name of the table that stores the statements executed
+	// of migrations that failed midway on databases without transactional schema changes
+	MigrationProgressTableName = "redi_migration_progress"
 )
+
+// IsMigrationTable reports whether a table is one of those keeping the state of migrations
+func IsMigrationTable(name string) bool {
+	return name == MigrationsTableName || name == MigrationLockTableName || name == MigrationProgressTableName
+}
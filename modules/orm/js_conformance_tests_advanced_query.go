@@ -332,4 +332,39 @@ model Sale {
 		
 		// await db.close();
 	`)
+
+	// Test ordering by several fields
+	jct.runWithCleanup(t, runner, "MultiFieldOrderBy", `
+		const db = fromUri(TEST_DATABASE_URI);
+		await db.connect();
+		
+		await db.loadSchema(`+"`"+`
+model Task {
+	id       Int    @id @default(autoincrement())
+	title    String
+	priority Int
+}
+`+"`"+`);
+		await db.syncSchemas();
+		
+		// Create test data
+		await db.models.Task.create({ data: { title: 'B', priority: 1 } });
+		await db.models.Task.create({ data: { title: 'A', priority: 2 } });
+		await db.models.Task.create({ data: { title: 'A', priority: 1 } });
+		await db.models.Task.create({ data: { title: 'B', priority: 2 } });
+		
+		const order = async (orderBy) => {
+			const tasks = await db.models.Task.findMany({ orderBy });
+			return tasks.map(t => t.title + t.priority);
+		};
+		
+		// The keys of an object apply in the order they are written
+		assert.deepEqual(await order({ title: 'asc', priority: 'desc' }), ['A2', 'A1', 'B2', 'B1']);
+		assert.deepEqual(await order({ priority: 'desc', title: 'asc' }), ['A2', 'B2', 'A1', 'B1']);
+		
+		// The objects of an array apply in order
+		assert.deepEqual(await order([{ priority: 'asc' }, { title: 'desc' }]), ['B1', 'A1', 'B2', 'A2']);
+		
+		// await db.close();
+	`)
 }
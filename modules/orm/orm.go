@@ -3,9 +3,12 @@ package orm
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	js "github.com/dop251/goja"
 	"github.com/dop251/goja_nodejs/eventloop"
@@ -41,6 +44,28 @@ func normalizeError(err error) error {
 	return err
 }
 
+// newQueryError converts a query error to a JS error. Validation errors get the name
+// ValidationError, code VALIDATION_ERROR and an errors list of {field, rule, message}.
+func newQueryError(vm *js.Runtime, err error) *js.Object {
+	jsErr := vm.NewGoError(normalizeError(err))
+	var validationErr *schema.ValidationError
+	if errors.As(err, &validationErr) {
+		fieldErrors := make([]any, len(validationErr.Errors))
+		for i, fieldErr := range validationErr.Errors {
+			fieldErrors[i] = map[string]any{
+				"field":   fieldErr.Field,
+				"rule":    fieldErr.Rule,
+				"message": fieldErr.Message,
+			}
+		}
+		jsErr.Set("name", "ValidationError")
+		jsErr.Set("code", "VALIDATION_ERROR")
+		jsErr.Set("model", validationErr.Model)
+		jsErr.Set("errors", fieldErrors)
+	}
+	return jsErr
+}
+
 // ModelsModule provides Prisma-like database operations using ORM as the backend
 type ModelsModule struct {
 	loop    *eventloop.EventLoop
@@ -67,6 +92,7 @@ func initModelsModule(config modules.ModuleConfig) error {
 	config.Registry.RegisterNativeModule("redi/orm", func(vm *js.Runtime, module *js.Object) {
 		exports := vm.NewObject()
 		exports.Set("fromUri", modelsModule.createFromUriFunction(vm))
+		exports.Set("fromConnection", modelsModule.createFromConnectionFunction(vm))
 
 		// Logger utilities
 		exports.Set("createLogger", modelsModule.createLoggerFunction(vm))
@@ -130,6 +156,7 @@ func (m *ModelsModule) createMethod(vm *js.Runtime, modelName, methodName string
 		if len(call.Arguments) > 0 && !js.IsUndefined(call.Arguments[0]) && !js.IsNull(call.Arguments[0]) {
 			exported := call.Arguments[0].Export()
 			if optMap, ok := exported.(map[string]any); ok {
+				keepOrderByOrder(vm, optMap, call.Arguments[0])
 				options = optMap
 			}
 		}
@@ -153,7 +180,7 @@ func (m *ModelsModule) createMethod(vm *js.Runtime, modelName, methodName string
 			// Execute using ORM
 			result, err := client.Model(modelName).Query(string(jsonQuery))
 			if err != nil {
-				reject(vm.NewGoError(normalizeError(err)))
+				reject(newQueryError(vm, err))
 				return
 			}
 
@@ -184,7 +211,6 @@ func (m *ModelsModule) createFromUriFunction(vm *js.Runtime) func(call js.Functi
 		}
 
 		uri := call.Arguments[0].String()
-		dbInstance := vm.NewObject()
 
 		// Create database connection immediately to validate URI
 		db, err := database.NewFromURI(uri)
@@ -192,125 +218,154 @@ func (m *ModelsModule) createFromUriFunction(vm *js.Runtime) func(call js.Functi
 			panic(vm.NewGoError(err))
 		}
 
-		var connected bool
-
 		// Parse URI to determine database type
 		parts := strings.Split(uri, "://")
 		if len(parts) < 2 {
 			panic(vm.NewTypeError("Invalid database URI format"))
 		}
 
-		dbType := parts[0]
-
-		// Create connection function
-		dbInstance.Set("connect", func(call js.FunctionCall) js.Value {
-			promise, resolve, reject := vm.NewPromise()
+		return m.newDatabaseObject(vm, db, parts[0], false)
+	}
+}
 
-			m.loop.RunOnLoop(func(vm *js.Runtime) {
-				if connected {
-					resolve(js.Undefined())
-					return
-				}
+// createFromConnectionFunction creates a function that returns the database instance of a
+// connection registered by the host program with database.Register
+func (m *ModelsModule) createFromConnectionFunction(vm *js.Runtime) func(call js.FunctionCall) js.Value {
+	return func(call js.FunctionCall) js.Value {
+		if len(call.Arguments) == 0 {
+			panic(vm.NewTypeError("fromConnection requires a connection name"))
+		}
 
-				// Connect to database
-				ctx := context.Background()
-				if err := db.Connect(ctx); err != nil {
-					reject(vm.NewGoError(err))
-					return
-				}
+		db, err := database.Connection(call.Arguments[0].String())
+		if err != nil {
+			panic(vm.NewGoError(err))
+		}
 
-				connected = true
-				resolve(js.Undefined())
-			})
+		// Registered connections are already connected, and their models can be used right away
+		dbInstance := m.newDatabaseObject(vm, db, db.GetDriverType(), true)
+		modelsObj := dbInstance.Get("models").ToObject(vm)
+		for _, modelName := range db.GetModels() {
+			m.registerModel(vm, modelsObj, modelName, db)
+		}
+		return dbInstance
+	}
+}
 
-			return vm.ToValue(promise)
-		})
+// newDatabaseObject creates the JavaScript database instance of db
+func (m *ModelsModule) newDatabaseObject(vm *js.Runtime, db types.Database, dbType string, connected bool) *js.Object {
+	dbInstance := vm.NewObject()
 
-		// Create close function
-		dbInstance.Set("close", func(call js.FunctionCall) js.Value {
-			promise, resolve, reject := vm.NewPromise()
+	// Create connection function
+	dbInstance.Set("connect", func(call js.FunctionCall) js.Value {
+		promise, resolve, reject := vm.NewPromise()
 
-			m.loop.RunOnLoop(func(vm *js.Runtime) {
-				if db != nil {
-					if err := db.Close(); err != nil {
-						reject(vm.NewGoError(err))
-						return
-					}
-				}
-				connected = false
+		m.loop.RunOnLoop(func(vm *js.Runtime) {
+			if connected {
 				resolve(js.Undefined())
-			})
+				return
+			}
+
+			// Connect to database
+			ctx := context.Background()
+			if err := db.Connect(ctx); err != nil {
+				reject(vm.NewGoError(err))
+				return
+			}
 
-			return vm.ToValue(promise)
+			connected = true
+			resolve(js.Undefined())
 		})
 
-		// Schema management functions
-		dbInstance.Set("loadSchema", m.createLoadSchemaFunction(vm, db, &connected))
-		dbInstance.Set("loadSchemaFrom", m.createLoadSchemaFromFunction(vm, db, &connected))
-		dbInstance.Set("syncSchemas", m.createSyncSchemasFunction(vm, db, &connected, dbInstance))
+		return vm.ToValue(promise)
+	})
 
-		// Database operation methods
-		dbInstance.Set("ping", m.createPingFunction(vm, db, &connected))
-		dbInstance.Set("createModel", m.createModelFunction(vm, db, &connected))
-		dbInstance.Set("dropModel", m.dropModelFunction(vm, db, &connected))
-		dbInstance.Set("getModels", func(call js.FunctionCall) js.Value {
-			if !connected || db == nil {
-				return vm.NewArray(0)
+	// Create close function
+	dbInstance.Set("close", func(call js.FunctionCall) js.Value {
+		promise, resolve, reject := vm.NewPromise()
+
+		m.loop.RunOnLoop(func(vm *js.Runtime) {
+			if db != nil {
+				if err := db.Close(); err != nil {
+					reject(vm.NewGoError(err))
+					return
+				}
 			}
-			return vm.ToValue(db.GetModels())
+			connected = false
+			resolve(js.Undefined())
 		})
 
-		// Raw query functions using ORM
-		dbInstance.Set("queryRaw", m.createQueryRawFunction(vm, db, &connected))
-		dbInstance.Set("executeRaw", m.createExecuteRawFunction(vm, db, &connected))
+		return vm.ToValue(promise)
+	})
 
-		// Transaction function using ORM
-		dbInstance.Set("transaction", m.createTransactionFunction(vm, db, &connected))
+	// Schema management functions
+	dbInstance.Set("loadSchema", m.createLoadSchemaFunction(vm, db, &connected))
+	dbInstance.Set("loadSchemaFrom", m.createLoadSchemaFromFunction(vm, db, &connected))
+	dbInstance.Set("syncSchemas", m.createSyncSchemasFunction(vm, db, &connected, dbInstance))
+
+	// Database operation methods
+	dbInstance.Set("ping", m.createPingFunction(vm, db, &connected))
+	dbInstance.Set("createModel", m.createModelFunction(vm, db, &connected))
+	dbInstance.Set("dropModel", m.dropModelFunction(vm, db, &connected))
+	dbInstance.Set("getModels", func(call js.FunctionCall) js.Value {
+		if !connected || db == nil {
+			return vm.NewArray(0)
+		}
+		return vm.ToValue(db.GetModels())
+	})
 
-		// Logger functions
-		dbInstance.Set("setLogger", func(call js.FunctionCall) js.Value {
-			if len(call.Arguments) == 0 {
-				panic(vm.NewTypeError("setLogger requires a logger argument"))
-			}
+	// Raw query functions using ORM
+	dbInstance.Set("queryRaw", m.createQueryRawFunction(vm, db, &connected))
+	dbInstance.Set("executeRaw", m.createExecuteRawFunction(vm, db, &connected))
 
-			// Try to extract the Go logger from the JS logger object
-			jsLogger := call.Arguments[0].ToObject(vm)
-			if jsLogger != nil {
-				if wrapperValue := jsLogger.Get("__wrapper"); wrapperValue != nil && !js.IsUndefined(wrapperValue) && !js.IsNull(wrapperValue) {
-					if wrapper, ok := wrapperValue.Export().(*LoggerWrapper); ok {
-						db.SetLogger(wrapper.logger)
-					}
+	// Transaction function using ORM
+	dbInstance.Set("transaction", m.createTransactionFunction(vm, db, &connected))
+
+	// Change stream subscription
+	dbInstance.Set("watch", m.createWatchFunction(vm, db, &connected))
+
+	// Logger functions
+	dbInstance.Set("setLogger", func(call js.FunctionCall) js.Value {
+		if len(call.Arguments) == 0 {
+			panic(vm.NewTypeError("setLogger requires a logger argument"))
+		}
+
+		// Try to extract the Go logger from the JS logger object
+		jsLogger := call.Arguments[0].ToObject(vm)
+		if jsLogger != nil {
+			if wrapperValue := jsLogger.Get("__wrapper"); wrapperValue != nil && !js.IsUndefined(wrapperValue) && !js.IsNull(wrapperValue) {
+				if wrapper, ok := wrapperValue.Export().(*LoggerWrapper); ok {
+					db.SetLogger(wrapper.logger)
 				}
 			}
-			return js.Undefined()
-		})
+		}
+		return js.Undefined()
+	})
 
-		dbInstance.Set("getLogger", func(call js.FunctionCall) js.Value {
-			logger := db.GetLogger()
-			if logger == nil {
-				return js.Null()
-			}
-			return vm.ToValue(logger)
-		})
+	dbInstance.Set("getLogger", func(call js.FunctionCall) js.Value {
+		logger := db.GetLogger()
+		if logger == nil {
+			return js.Null()
+		}
+		return vm.ToValue(logger)
+	})
 
-		// Models object (will be populated after syncSchemas)
-		modelsObj := vm.NewObject()
-		dbInstance.Set("models", modelsObj)
+	// Models object (will be populated after syncSchemas)
+	modelsObj := vm.NewObject()
+	dbInstance.Set("models", modelsObj)
 
-		// Store reference for model registration
-		dbInstance.Set("__registerModels", func() {
-			if db != nil {
-				for _, modelName := range db.GetModels() {
-					m.registerModel(vm, modelsObj, modelName, db)
-				}
+	// Store reference for model registration
+	dbInstance.Set("__registerModels", func() {
+		if db != nil {
+			for _, modelName := range db.GetModels() {
+				m.registerModel(vm, modelsObj, modelName, db)
 			}
-		})
+		}
+	})
 
-		// Additional metadata
-		dbInstance.Set("driverType", dbType)
+	// Additional metadata
+	dbInstance.Set("driverType", dbType)
 
-		return dbInstance
-	}
+	return dbInstance
 }
 
 // Transaction support using ORM
@@ -454,6 +509,7 @@ func (m *ModelsModule) registerTransactionModel(vm *js.Runtime, modelsObj *js.Ob
 			if len(call.Arguments) > 0 && !js.IsUndefined(call.Arguments[0]) && !js.IsNull(call.Arguments[0]) {
 				exported := call.Arguments[0].Export()
 				if optMap, ok := exported.(map[string]any); ok {
+					keepOrderByOrder(vm, optMap, call.Arguments[0])
 					options = optMap
 				}
 			}
@@ -469,7 +525,7 @@ func (m *ModelsModule) registerTransactionModel(vm *js.Runtime, modelsObj *js.Ob
 			// Execute using transaction client
 			result, err := tx.Model(modelName).Query(string(jsonQuery))
 			if err != nil {
-				panic(vm.NewGoError(err))
+				panic(newQueryError(vm, err))
 			}
 
 			// Special handling for count
@@ -671,6 +727,87 @@ func (m *ModelsModule) createPingFunction(vm *js.Runtime, db types.Database, con
 	}
 }
 
+// createWatchFunction creates the watch method.
+// Usage: const sub = await db.watch(['User', 'Post'], (event) => {...}); await sub.close();
+// The model list is optional; all models are watched when omitted.
+func (m *ModelsModule) createWatchFunction(vm *js.Runtime, db types.Database, connected *bool) func(call js.FunctionCall) js.Value {
+	return func(call js.FunctionCall) js.Value {
+		if !*connected || db == nil {
+			panic(vm.NewTypeError("Database not connected"))
+		}
+
+		var models []string
+		var callbackArg js.Value
+		switch len(call.Arguments) {
+		case 0:
+			panic(vm.NewTypeError("watch requires a callback function"))
+		case 1:
+			callbackArg = call.Arguments[0]
+		default:
+			if exported, ok := call.Arguments[0].Export().([]any); ok {
+				for _, model := range exported {
+					models = append(models, fmt.Sprintf("%v", model))
+				}
+			} else if !js.IsUndefined(call.Arguments[0]) && !js.IsNull(call.Arguments[0]) {
+				models = []string{call.Arguments[0].String()}
+			}
+			callbackArg = call.Arguments[1]
+		}
+
+		callback, ok := js.AssertFunction(callbackArg)
+		if !ok {
+			panic(vm.NewTypeError("watch requires a callback function"))
+		}
+
+		promise, resolve, reject := vm.NewPromise()
+
+		go func() {
+			stream, err := database.Watch(context.Background(), db, models...)
+			if err != nil {
+				m.loop.RunOnLoop(func(vm *js.Runtime) {
+					reject(vm.NewGoError(err))
+				})
+				return
+			}
+
+			// Keep the event loop alive while the subscription is open
+			keepAlive := m.loop.SetInterval(func(*js.Runtime) {}, time.Hour)
+
+			go func() {
+				for event := range stream.Events() {
+					event := event
+					m.loop.RunOnLoop(func(vm *js.Runtime) {
+						callback(nil, vm.ToValue(map[string]any{
+							"model":     event.Model,
+							"operation": string(event.Operation),
+							"data":      event.Data,
+							"timestamp": event.Timestamp.Format(time.RFC3339Nano),
+						}))
+					})
+				}
+				m.loop.ClearInterval(keepAlive)
+			}()
+
+			m.loop.RunOnLoop(func(vm *js.Runtime) {
+				subscription := vm.NewObject()
+				subscription.Set("close", func(call js.FunctionCall) js.Value {
+					closePromise, closeResolve, _ := vm.NewPromise()
+					go func() {
+						stream.Close()
+						m.loop.RunOnLoop(func(vm *js.Runtime) {
+							closeResolve(js.Undefined())
+						})
+					}()
+					return vm.ToValue(closePromise)
+				})
+				resolve(subscription)
+			})
+		}()
+
+		return vm.ToValue(promise)
+	}
+}
+
 // createModelFunction creates the createModel method
 func (m *ModelsModule) createModelFunction(vm *js.Runtime, db types.Database, connected *bool) func(call js.FunctionCall) js.Value {
 	return func(call js.FunctionCall) js.Value {
@@ -827,6 +964,38 @@ func (m *ModelsModule) createNestedTransactionFunction(vm *js.Runtime, parentTx
 	}
 }
 
+// keepOrderByOrder replaces the orderBy objects of more than one field in exported options
+// with arrays of single-field objects, in the order the fields were written in the
+// JavaScript object. The exported map does not keep that order, and it is the order the
+// fields apply in.
+func keepOrderByOrder(vm *js.Runtime, exported any, value js.Value) {
+	if value == nil || js.IsUndefined(value) || js.IsNull(value) {
+		return
+	}
+	switch v := exported.(type) {
+	case map[string]any:
+		obj := value.ToObject(vm)
+		for key, item := range v {
+			if orderBy, ok := item.(map[string]any); ok && key == "orderBy" && len(orderBy) > 1 {
+				ordered := make([]any, 0, len(orderBy))
+				for _, field := range obj.Get(key).ToObject(vm).Keys() {
+					if direction, ok := orderBy[field]; ok {
+						ordered = append(ordered, map[string]any{field: direction})
+					}
+				}
+				v[key] = ordered
+				continue
+			}
+			keepOrderByOrder(vm, item, obj.Get(key))
+		}
+	case []any:
+		obj := value.ToObject(vm)
+		for i, item := range v {
+			keepOrderByOrder(vm, item, obj.Get(strconv.Itoa(i)))
+		}
+	}
+}
+
 // Export utility functions for backward compatibility
 func ConvertValue(value any) any {
 	return utils.ToInterface(value)
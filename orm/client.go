@@ -1,14 +1,17 @@
 package orm
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/rediwo/redi-orm/logger"
 	"github.com/rediwo/redi-orm/schema"
 	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
 )
 
 // Client is the main entry point for the ORM API
@@ -22,9 +25,11 @@ type ClientOption func(*Client)
 
 // NewClient creates a new ORM client
 func NewClient(db types.Database, opts ...ClientOption) *Client {
+	typeConverter := NewTypeConverter(db.GetCapabilities())
+	typeConverter.schemas = db
 	client := &Client{
 		db:            db,
-		typeConverter: NewTypeConverter(db.GetCapabilities()),
+		typeConverter: typeConverter,
 	}
 
 	// Apply options
@@ -58,8 +63,12 @@ func (c *Client) GetDB() types.Database {
 
 // Transaction executes a function within a database transaction
 func (c *Client) Transaction(fn func(tx *Client) error) error {
-	ctx := context.Background()
+	return c.TransactionContext(context.Background(), fn)
+}
 
+// TransactionContext executes a function within a database transaction that is rolled back
+// when ctx is canceled
+func (c *Client) TransactionContext(ctx context.Context, fn func(tx *Client) error) error {
 	// Use the Transaction method provided by the Database interface
 	return c.db.Transaction(ctx, func(tx types.Transaction) error {
 		// Create a new client with transaction-wrapped database
@@ -73,6 +82,12 @@ func (c *Client) Transaction(fn func(tx *Client) error) error {
 	})
 }
 
+// NewTransactionClient creates a client running its queries in tx, a transaction begun on db.
+// Unlike TransactionContext, committing or rolling back tx is left to the caller.
+func NewTransactionClient(db types.Database, tx types.Transaction, opts ...ClientOption) *Client {
+	return NewClient(&transactionDatabase{tx: tx, originalDB: db}, opts...)
+}
+
 // transactionDatabase wraps a Transaction to implement the Database interface
 type transactionDatabase struct {
 	tx         types.Transaction
@@ -147,6 +162,18 @@ func (td *transactionDatabase) GetModelSchema(modelName string) (*schema.Schema,
 	return td.originalDB.GetModelSchema(modelName)
 }
 
+func (td *transactionDatabase) GetModelField(modelName, fieldName string) (*schema.Field, error) {
+	return td.originalDB.GetModelField(modelName, fieldName)
+}
+
+func (td *transactionDatabase) GetModelRelations(modelName string) ([]types.RelationInfo, error) {
+	return td.originalDB.GetModelRelations(modelName)
+}
+
+func (td *transactionDatabase) ResolveRelation(modelName, relationName string) (types.RelationInfo, error) {
+	return td.originalDB.ResolveRelation(modelName, relationName)
+}
+
 func (td *transactionDatabase) GetDriverType() string {
 	return td.originalDB.GetDriverType()
 }
@@ -221,11 +248,71 @@ func (r *sqlResult) RowsAffected() (int64, error) {
 	return r.rowsAffected, nil
 }
 
-// parseJSON parses a JSON string into a map
+// parseJSON parses a JSON string into a map. Numbers a float64 cannot hold exactly, such
+// as large integers and long decimals, keep their digits.
 func parseJSON(jsonStr string) (map[string]any, error) {
 	var result map[string]any
-	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
+	if err := utils.UnmarshalJSON([]byte(jsonStr), &result); err != nil {
 		return nil, fmt.Errorf("invalid JSON: %w", err)
 	}
+	if strings.Contains(jsonStr, `"orderBy"`) {
+		keepOrderByOrder(result, json.RawMessage(jsonStr))
+	}
 	return result, nil
 }
+
+// keepOrderByOrder replaces the orderBy objects of more than one field in a decoded value
+// with arrays of single-field objects, in the order the fields were written in raw. A Go map
+// does not keep that order, and it is the order the fields apply in.
+func keepOrderByOrder(value any, raw json.RawMessage) {
+	switch v := value.(type) {
+	case map[string]any:
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(raw, &fields) != nil {
+			return
+		}
+		for key, item := range v {
+			if orderBy, ok := item.(map[string]any); ok && key == "orderBy" && len(orderBy) > 1 {
+				ordered := make([]any, 0, len(orderBy))
+				for _, field := range objectKeys(fields[key]) {
+					if direction, ok := orderBy[field]; ok {
+						ordered = append(ordered, map[string]any{field: direction})
+						delete(orderBy, field)
+					}
+				}
+				v[key] = ordered
+				continue
+			}
+			keepOrderByOrder(item, fields[key])
+		}
+	case []any:
+		var items []json.RawMessage
+		if json.Unmarshal(raw, &items) != nil || len(items) != len(v) {
+			return
+		}
+		for i, item := range v {
+			keepOrderByOrder(item, items[i])
+		}
+	}
+}
+
+// objectKeys returns the keys of a JSON object in the order they are written
+func objectKeys(raw json.RawMessage) []string {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil
+	}
+	var keys []string
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return keys
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return keys
+		}
+		keys = append(keys, token.(string))
+	}
+	return keys
+}
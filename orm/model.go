@@ -18,6 +18,12 @@ type Model struct {
 
 // Query executes a query with the given JSON string
 func (m *Model) Query(jsonQuery string) (any, error) {
+	return m.QueryContext(context.Background(), jsonQuery)
+}
+
+// QueryContext executes a query with the given JSON string. Canceling ctx cancels the
+// database work of the query.
+func (m *Model) QueryContext(ctx context.Context, jsonQuery string) (any, error) {
 	options, err := parseJSON(jsonQuery)
 	if err != nil {
 		return nil, err
@@ -31,7 +37,7 @@ func (m *Model) Query(jsonQuery string) (any, error) {
 			paramsMap = make(map[string]any)
 		}
 
-		return executeOperation(m.db, m.modelName, operation, paramsMap, m.client.typeConverter)
+		return executeOperation(ctx, m.db, m.modelName, operation, paramsMap, m.client.typeConverter)
 	}
 
 	return nil, fmt.Errorf("no operation specified in query")
@@ -220,6 +226,21 @@ func (m *Model) Update(jsonQuery string) (map[string]any, error) {
 	return nil, fmt.Errorf("unexpected result type: %T", result)
 }
 
+// Upsert updates the record matching a unique where clause or creates it
+func (m *Model) Upsert(jsonQuery string) (map[string]any, error) {
+	query := fmt.Sprintf(`{"upsert": %s}`, jsonQuery)
+	result, err := m.Query(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if resultMap, ok := result.(map[string]any); ok {
+		return m.client.typeConverter.ConvertResult(m.modelName, resultMap), nil
+	}
+
+	return nil, fmt.Errorf("unexpected result type: %T", result)
+}
+
 // UpdateMany updates multiple records
 func (m *Model) UpdateMany(jsonQuery string) (map[string]any, error) {
 	query := fmt.Sprintf(`{"updateMany": %s}`, jsonQuery)
@@ -2,9 +2,12 @@ package orm
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
 )
 
 // Aggregation Tests
@@ -192,6 +195,178 @@ func (act *OrmConformanceTests) runAggregationTests(t *testing.T, client *Client
 		})
 	})
 
+	// Test field counts and aggregates over no rows
+	act.runWithCleanup(t, db, func() {
+		t.Run("AggregationCountFields", func(t *testing.T) {
+			ctx := context.Background()
+
+			// Load schema
+			err := db.LoadSchema(ctx, `
+				model Ticket {
+					id       Int     @id @default(autoincrement())
+					priority Int
+					assignee String?
+				}
+			`)
+			assertNoError(t, err, "Failed to load schema")
+
+			err = db.SyncSchemas(ctx)
+			assertNoError(t, err, "Failed to sync schemas")
+
+			tickets := []string{
+				`{"data": {"priority": 1, "assignee": "alice"}}`,
+				`{"data": {"priority": 2}}`,
+				`{"data": {"priority": 3, "assignee": "bob"}}`,
+				`{"data": {"priority": 3, "assignee": "alice"}}`,
+			}
+			for _, ticket := range tickets {
+				_, err = client.Model("Ticket").Create(ticket)
+				assertNoError(t, err, "Failed to create ticket")
+			}
+
+			// Field counts skip nulls, _all counts every row
+			result, err := client.Model("Ticket").Aggregate(`{
+				"_count": {"_all": true, "assignee": true, "priority": {"distinct": true}},
+				"_sum": {"priority": true},
+				"_max": {"priority": true}
+			}`)
+			assertNoError(t, err, "Failed to aggregate")
+
+			if counts, ok := result["_count"].(map[string]any); ok {
+				assertEqual(t, int64(4), counts["_all"], "_all count mismatch")
+				assertEqual(t, int64(3), counts["assignee"], "Field count mismatch")
+				assertEqual(t, int64(3), counts["priority"], "Distinct count mismatch")
+			} else {
+				t.Fatalf("_count is not a map: %T", result["_count"])
+			}
+			if sumMap, ok := result["_sum"].(map[string]any); ok {
+				assertEqual(t, float64(9), sumMap["priority"], "Sum priority mismatch")
+			}
+
+			// Field and distinct counts per group
+			groups, err := client.Model("Ticket").GroupBy(`{
+				"by": ["priority"],
+				"_count": {"_all": true, "assignee": {"distinct": true}},
+				"orderBy": {"priority": "asc"}
+			}`)
+			assertNoError(t, err, "Failed to group tickets")
+			assertEqual(t, 3, len(groups), "Group count mismatch")
+			for _, group := range groups {
+				counts, ok := group["_count"].(map[string]any)
+				if !ok {
+					t.Fatalf("_count is not a map: %T", group["_count"])
+				}
+				switch utils.ToInt64(group["priority"]) {
+				case 2:
+					assertEqual(t, int64(1), counts["_all"], "Priority 2 _all count mismatch")
+					assertEqual(t, int64(0), counts["assignee"], "Priority 2 assignee count mismatch")
+				case 3:
+					assertEqual(t, int64(2), counts["_all"], "Priority 3 _all count mismatch")
+					assertEqual(t, int64(2), counts["assignee"], "Priority 3 assignee count mismatch")
+				}
+			}
+
+			// Aggregates over no matching rows
+			result, err = client.Model("Ticket").Aggregate(`{
+				"where": {"priority": {"gt": 10}},
+				"_count": true,
+				"_sum": {"priority": true},
+				"_max": {"priority": true}
+			}`)
+			assertNoError(t, err, "Failed to aggregate empty set")
+			assertEqual(t, int64(0), result["_count"], "Empty count mismatch")
+			if maxMap, ok := result["_max"].(map[string]any); ok {
+				if maxMap["priority"] != nil {
+					t.Errorf("Expected nil max for empty set, got %v", maxMap["priority"])
+				}
+			}
+		})
+	})
+
+	// Test groupBy with where and having conditions
+	act.runWithCleanup(t, db, func() {
+		t.Run("GroupByWhereAndHaving", func(t *testing.T) {
+			ctx := context.Background()
+
+			err := db.LoadSchema(ctx, `
+				model Purchase {
+					id         Int    @id @default(autoincrement())
+					customerId Int    @map("customer_id")
+					amount     Float
+					note       String
+				}
+			`)
+			assertNoError(t, err, "Failed to load schema")
+
+			err = db.SyncSchemas(ctx)
+			assertNoError(t, err, "Failed to sync schemas")
+
+			purchases := []string{
+				`{"data": {"customerId": 1, "amount": 100, "note": "it's paid"}}`,
+				`{"data": {"customerId": 1, "amount": 300, "note": "it's paid"}}`,
+				`{"data": {"customerId": 1, "amount": 50, "note": "refunded"}}`,
+				`{"data": {"customerId": 2, "amount": 20, "note": "it's paid"}}`,
+				`{"data": {"customerId": 2, "amount": 700, "note": "it's paid"}}`,
+				`{"data": {"customerId": 3, "amount": 80, "note": "it's paid"}}`,
+			}
+			for _, purchase := range purchases {
+				_, err = client.Model("Purchase").Create(purchase)
+				assertNoError(t, err, "Failed to create purchase")
+			}
+
+			customers := func(query string) string {
+				t.Helper()
+				groups, err := client.Model("Purchase").GroupBy(query)
+				assertNoError(t, err, "Failed to group purchases")
+				var found []string
+				for _, group := range groups {
+					found = append(found, fmt.Sprint(utils.ToInt64(group["customerId"])))
+				}
+				return strings.Join(found, ", ")
+			}
+
+			// Where conditions with operators and quoted values
+			assertEqual(t, "1, 2", customers(`{
+				"by": ["customerId"],
+				"where": {"note": "it's paid", "amount": {"gte": 100}},
+				"_count": true,
+				"orderBy": {"customerId": "asc"}
+			}`), "groupBy where mismatch")
+
+			// Having conditions on mapped fields
+			assertEqual(t, "1, 2", customers(`{
+				"by": ["customerId"],
+				"_sum": {"amount": true, "customerId": true},
+				"having": {"_sum": {"customerId": {"lt": 5}, "amount": {"gt": 400}}},
+				"orderBy": {"customerId": "asc"}
+			}`), "groupBy having mismatch")
+
+			// Where and having together
+			assertEqual(t, "1", customers(`{
+				"by": ["customerId"],
+				"where": {"note": "it's paid"},
+				"_count": true,
+				"_min": {"amount": true},
+				"having": {"_count": {"_all": {"gte": 2}}, "_min": {"amount": {"gte": 100}}},
+				"orderBy": {"customerId": "asc"}
+			}`), "groupBy where and having mismatch")
+
+			// Fields outside the schema are rejected rather than written into the SQL
+			if !db.GetCapabilities().SupportsAggregationPipeline() {
+				for _, query := range []string{
+					`{"by": ["customerId\" FROM purchases; --"], "_count": true}`,
+					`{"by": ["customerId"], "_sum": {"amount) FROM purchases; --": true}}`,
+					`{"by": ["customerId"], "_count": true, "orderBy": {"customerId; --": "asc"}}`,
+					`{"by": ["customerId"], "_count": true, "having": {"_sum": {"amount) > 0; --": {"gt": 1}}}}`,
+				} {
+					if _, err := client.Model("Purchase").GroupBy(query); err == nil {
+						t.Errorf("Expected groupBy %s to fail", query)
+					}
+				}
+			}
+		})
+	})
+
 	// Test MySQL string number conversion
 	if act.Characteristics.ReturnsStringForNumbers {
 		t.Run("MySQLStringConversion", func(t *testing.T) {
@@ -2,9 +2,12 @@ package orm
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/rediwo/redi-orm/schema"
 	"github.com/rediwo/redi-orm/types"
 )
 
@@ -44,6 +47,52 @@ func (act *OrmConformanceTests) runCRUDTests(t *testing.T, client *Client, db ty
 		})
 	})
 
+	// Test create with generated UUIDs
+	act.runWithCleanup(t, db, func() {
+		t.Run("CreateWithUUID", func(t *testing.T) {
+			ctx := context.Background()
+
+			err := db.LoadSchema(ctx, `
+				model Account {
+					id       String    @id @default(uuid())
+					name     String
+					sessions Session[]
+				}
+
+				model Session {
+					id        String  @id @default(uuid())
+					accountId String
+					account   Account @relation(fields: [accountId], references: [id])
+				}
+			`)
+			assertNoError(t, err, "Failed to load schema")
+
+			err = db.SyncSchemas(ctx)
+			assertNoError(t, err, "Failed to sync schemas")
+
+			// Each create returns its own record with its generated key
+			_, err = client.Model("Account").Create(`{"data": {"name": "Alice"}}`)
+			assertNoError(t, err, "Failed to create account")
+			account, err := client.Model("Account").Create(`{"data": {"name": "Bob"}}`)
+			assertNoError(t, err, "Failed to create account")
+
+			id, ok := account["id"].(string)
+			if !ok || len(id) != 36 {
+				t.Fatalf("Expected a generated UUID, got %v", account["id"])
+			}
+			assertEqual(t, "Bob", account["name"], "Account name mismatch")
+
+			// Records reference the generated keys
+			session, err := client.Model("Session").Create(fmt.Sprintf(`{"data": {"accountId": %q}}`, id))
+			assertNoError(t, err, "Failed to create session")
+			assertEqual(t, id, session["accountId"], "Session account mismatch")
+
+			found, err := client.Model("Account").FindUnique(fmt.Sprintf(`{"where": {"id": %q}}`, id))
+			assertNoError(t, err, "Failed to find account")
+			assertEqual(t, "Bob", found["name"], "Found account name mismatch")
+		})
+	})
+
 	// Test findMany
 	act.runWithCleanup(t, db, func() {
 		t.Run("FindMany", func(t *testing.T) {
@@ -198,6 +247,66 @@ func (act *OrmConformanceTests) runCRUDTests(t *testing.T, client *Client, db ty
 		})
 	})
 
+	// Test select combined with where, orderBy and pagination
+	act.runWithCleanup(t, db, func() {
+		t.Run("SelectWithWhere", func(t *testing.T) {
+			ctx := context.Background()
+
+			err := db.LoadSchema(ctx, `
+				model User {
+					id    Int    @id @default(autoincrement())
+					name  String
+					email String @unique
+					age   Int
+				}
+			`)
+			assertNoError(t, err, "Failed to load schema")
+
+			err = db.SyncSchemas(ctx)
+			assertNoError(t, err, "Failed to sync schemas")
+
+			for _, user := range []string{
+				`{"data": {"name": "Alice", "email": "alice@example.com", "age": 30}}`,
+				`{"data": {"name": "Bob", "email": "bob@example.com", "age": 25}}`,
+				`{"data": {"name": "Carol", "email": "carol@example.com", "age": 35}}`,
+			} {
+				_, err = client.Model("User").Create(user)
+				assertNoError(t, err, "Failed to create user")
+			}
+
+			// findUnique keeps its where conditions
+			unique, err := client.Model("User").FindUnique(`{
+				"where": {"email": "bob@example.com"},
+				"select": {"name": true}
+			}`)
+			assertNoError(t, err, "Failed to find unique user with select")
+			assertEqual(t, "Bob", unique["name"], "findUnique with select mismatch")
+			if _, ok := unique["email"]; ok {
+				t.Errorf("Expected email not to be selected, got %v", unique)
+			}
+
+			// findFirst keeps its where conditions and ordering
+			first, err := client.Model("User").FindFirst(`{
+				"where": {"age": {"gte": 30}},
+				"orderBy": {"age": "desc"},
+				"select": {"name": true, "age": true}
+			}`)
+			assertNoError(t, err, "Failed to find first user with select")
+			assertEqual(t, "Carol", first["name"], "findFirst with select mismatch")
+
+			// findMany keeps its where conditions, ordering and pagination
+			many, err := client.Model("User").FindMany(`{
+				"where": {"age": {"lt": 35}},
+				"orderBy": {"name": "desc"},
+				"take": 1,
+				"select": ["name"]
+			}`)
+			assertNoError(t, err, "Failed to find users with select")
+			assertEqual(t, 1, len(many), "findMany with select count mismatch")
+			assertEqual(t, "Bob", many[0]["name"], "findMany with select mismatch")
+		})
+	})
+
 	// Test count
 	act.runWithCleanup(t, db, func() {
 		t.Run("Count", func(t *testing.T) {
@@ -306,6 +415,216 @@ func (act *OrmConformanceTests) runCRUDTests(t *testing.T, client *Client, db ty
 		})
 	})
 
+	// Test createMany with skipDuplicates
+	act.runWithCleanup(t, db, func() {
+		t.Run("CreateManySkipDuplicates", func(t *testing.T) {
+			ctx := context.Background()
+
+			err := db.LoadSchema(ctx, `
+				model Subscriber {
+					id    Int    @id @default(autoincrement())
+					email String @unique
+					name  String
+				}
+			`)
+			assertNoError(t, err, "Failed to load schema")
+
+			err = db.SyncSchemas(ctx)
+			assertNoError(t, err, "Failed to sync schemas")
+
+			_, err = client.Model("Subscriber").Create(`{"data": {"email": "alice@example.com", "name": "Alice"}}`)
+			assertNoError(t, err, "Failed to create subscriber")
+
+			result, err := client.Model("Subscriber").Query(`{
+				"createMany": {
+					"data": [
+						{"email": "alice@example.com", "name": "Alice Again"},
+						{"email": "bob@example.com", "name": "Bob"},
+						{"email": "carol@example.com", "name": "Carol"}
+					],
+					"skipDuplicates": true
+				}
+			}`)
+			assertNoError(t, err, "Failed to create many subscribers")
+
+			resultMap, ok := result.(map[string]any)
+			if !ok {
+				t.Fatalf("Expected map result, got %T", result)
+			}
+			assertEqual(t, 2, resultMap["count"], "CreateMany count should exclude duplicates")
+
+			subscribers, err := client.Model("Subscriber").FindMany(`{"orderBy": {"email": "asc"}}`)
+			assertNoError(t, err, "Failed to find subscribers")
+			assertEqual(t, 3, len(subscribers), "Subscriber count mismatch")
+			assertEqual(t, "Alice", subscribers[0]["name"], "Existing subscriber should be unchanged")
+
+			// Without skipDuplicates the duplicate fails the insert
+			_, err = client.Model("Subscriber").Query(`{
+				"createMany": {
+					"data": [{"email": "bob@example.com", "name": "Bob Again"}]
+				}
+			}`)
+			if err == nil {
+				t.Error("Expected duplicate createMany to fail without skipDuplicates")
+			}
+		})
+	})
+
+	// Test atomic update operators
+	act.runWithCleanup(t, db, func() {
+		t.Run("UpdateOperators", func(t *testing.T) {
+			ctx := context.Background()
+
+			err := db.LoadSchema(ctx, `
+				model Article {
+					id     Int    @id @default(autoincrement())
+					title  String
+					views  Int    @default(0)
+					likes  Int    @default(0)
+					rating Float  @default(1)
+				}
+			`)
+			assertNoError(t, err, "Failed to load schema")
+
+			err = db.SyncSchemas(ctx)
+			assertNoError(t, err, "Failed to sync schemas")
+
+			article, err := client.Model("Article").Create(`{"data": {"title": "Draft", "views": 10, "likes": 5, "rating": 2}}`)
+			assertNoError(t, err, "Failed to create article")
+			id := idToString(article["id"])
+
+			updated, err := client.Model("Article").Update(fmt.Sprintf(`{
+				"where": {"id": %s},
+				"data": {
+					"title": {"set": "Published"},
+					"views": {"increment": 3},
+					"likes": {"decrement": 2},
+					"rating": {"multiply": 1.5}
+				}
+			}`, id))
+			assertNoError(t, err, "Failed to update article with operators")
+			assertEqual(t, "Published", updated["title"], "set operator mismatch")
+			assertEqual(t, 13, updated["views"], "increment operator mismatch")
+			assertEqual(t, 3, updated["likes"], "decrement operator mismatch")
+			assertEqual(t, 3.0, updated["rating"], "multiply operator mismatch")
+
+			_, err = client.Model("Article").Create(`{"data": {"title": "Second", "views": 1}}`)
+			assertNoError(t, err, "Failed to create article")
+
+			result, err := client.Model("Article").Query(`{
+				"updateMany": {"data": {"views": {"increment": 1}}}
+			}`)
+			assertNoError(t, err, "Failed to updateMany with operators")
+			assertEqual(t, 2, result.(map[string]any)["count"], "updateMany count mismatch")
+
+			articles, err := client.Model("Article").FindMany(`{"orderBy": {"views": "asc"}}`)
+			assertNoError(t, err, "Failed to find articles")
+			assertEqual(t, 2, articles[0]["views"], "Second article views mismatch")
+			assertEqual(t, 14, articles[1]["views"], "First article views mismatch")
+		})
+	})
+
+	// Test @updatedAt maintenance
+	act.runWithCleanup(t, db, func() {
+		t.Run("UpdatedAt", func(t *testing.T) {
+			ctx := context.Background()
+
+			err := db.LoadSchema(ctx, `
+				model Document {
+					id        Int      @id @default(autoincrement())
+					title     String
+					updatedAt DateTime @updatedAt
+				}
+			`)
+			assertNoError(t, err, "Failed to load schema")
+
+			err = db.SyncSchemas(ctx)
+			assertNoError(t, err, "Failed to sync schemas")
+
+			created, err := client.Model("Document").Create(`{"data": {"title": "Draft"}}`)
+			assertNoError(t, err, "Failed to create document")
+			id := idToString(created["id"])
+
+			document, err := client.Model("Document").FindUnique(fmt.Sprintf(`{"where": {"id": %s}}`, id))
+			assertNoError(t, err, "Failed to find document")
+			assertNotNil(t, document["updatedAt"], "updatedAt should be set on create")
+
+			// An explicit value is kept
+			_, err = client.Model("Document").Update(fmt.Sprintf(`{
+				"where": {"id": %s},
+				"data": {"updatedAt": "2020-01-01 00:00:00"}
+			}`, id))
+			assertNoError(t, err, "Failed to set updatedAt")
+			document, err = client.Model("Document").FindUnique(fmt.Sprintf(`{"where": {"id": %s}}`, id))
+			assertNoError(t, err, "Failed to find document")
+			if !strings.HasPrefix(fmt.Sprint(document["updatedAt"]), "2020-01-01") {
+				t.Errorf("Expected explicit updatedAt to be kept, got %v", document["updatedAt"])
+			}
+
+			// Any other update refreshes it
+			_, err = client.Model("Document").Update(fmt.Sprintf(`{
+				"where": {"id": %s},
+				"data": {"title": "Published"}
+			}`, id))
+			assertNoError(t, err, "Failed to update document")
+			document, err = client.Model("Document").FindUnique(fmt.Sprintf(`{"where": {"id": %s}}`, id))
+			assertNoError(t, err, "Failed to find document")
+			if strings.HasPrefix(fmt.Sprint(document["updatedAt"]), "2020-01-01") {
+				t.Errorf("Expected updatedAt to be refreshed, got %v", document["updatedAt"])
+			}
+		})
+	})
+
+	// Test validation rules
+	act.runWithCleanup(t, db, func() {
+		t.Run("Validation", func(t *testing.T) {
+			ctx := context.Background()
+
+			err := db.LoadSchema(ctx, `
+				model Account {
+					id       Int    @id @default(autoincrement())
+					username String @length(min: 3, max: 12) @regex("^[a-z0-9_]+$")
+					email    String @email
+					age      Int?   @min(0) @max(150)
+				}
+			`)
+			assertNoError(t, err, "Failed to load schema")
+
+			err = db.SyncSchemas(ctx)
+			assertNoError(t, err, "Failed to sync schemas")
+
+			created, err := client.Model("Account").Create(`{"data": {"username": "alice_1", "email": "alice@example.com", "age": 30}}`)
+			assertNoError(t, err, "Failed to create valid account")
+
+			_, err = client.Model("Account").Create(`{"data": {"username": "Al", "email": "not-an-email", "age": -1}}`)
+			var validationErr *schema.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected validation error, got %v", err)
+			}
+			rules := make(map[string]string)
+			for _, fieldErr := range validationErr.Errors {
+				rules[fieldErr.Field+"."+fieldErr.Rule] = fieldErr.Message
+			}
+			for _, expected := range []string{"username.length", "username.regex", "email.email", "age.min"} {
+				if _, ok := rules[expected]; !ok {
+					t.Errorf("Expected %s to be reported, got %v", expected, validationErr.Errors)
+				}
+			}
+
+			_, err = client.Model("Account").Update(fmt.Sprintf(`{
+				"where": {"id": %s},
+				"data": {"age": 200}
+			}`, idToString(created["id"])))
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected validation error on update, got %v", err)
+			}
+
+			count, err := client.Model("Account").Count(`{}`)
+			assertNoError(t, err, "Failed to count accounts")
+			assertEqual(t, 1, count, "Invalid data must not be written")
+		})
+	})
+
 	// Test updateMany
 	act.runWithCleanup(t, db, func() {
 		t.Run("UpdateMany", func(t *testing.T) {
@@ -357,6 +676,60 @@ func (act *OrmConformanceTests) runCRUDTests(t *testing.T, client *Client, db ty
 		})
 	})
 
+	// Test upsert
+	act.runWithCleanup(t, db, func() {
+		t.Run("Upsert", func(t *testing.T) {
+			ctx := context.Background()
+
+			// Load schema
+			err := db.LoadSchema(ctx, `
+				model User {
+					id    Int    @id @default(autoincrement())
+					name  String
+					email String @unique
+				}
+			`)
+			assertNoError(t, err, "Failed to load schema")
+
+			err = db.SyncSchemas(ctx)
+			assertNoError(t, err, "Failed to sync schemas")
+
+			// No match: creates the record, taking the email from where
+			created, err := client.Model("User").Upsert(`{
+				"where": {"email": "upsert@example.com"},
+				"create": {"name": "Created"},
+				"update": {"name": "Updated"}
+			}`)
+			assertNoError(t, err, "Failed to upsert new user")
+			assertNotNil(t, created["id"], "Upserted user ID should not be nil")
+			assertEqual(t, "Created", created["name"], "Created user name mismatch")
+			assertEqual(t, "upsert@example.com", created["email"], "Created user email mismatch")
+
+			// Match: updates the existing record
+			updated, err := client.Model("User").Upsert(`{
+				"where": {"email": "upsert@example.com"},
+				"create": {"name": "Created again"},
+				"update": {"name": "Updated"}
+			}`)
+			assertNoError(t, err, "Failed to upsert existing user")
+			assertEqual(t, created["id"], updated["id"], "Upsert created a second user")
+			assertEqual(t, "Updated", updated["name"], "Existing user was not updated")
+
+			// Upsert by primary key
+			byID, err := client.Model("User").Upsert(`{
+				"where": {"id": ` + idToString(created["id"]) + `},
+				"create": {"name": "Unused", "email": "unused@example.com"},
+				"update": {"name": "By ID"}
+			}`)
+			assertNoError(t, err, "Failed to upsert by id")
+			assertEqual(t, "By ID", byID["name"], "Upsert by id did not update")
+
+			count, err := client.Model("User").Count(`{}`)
+			assertNoError(t, err, "Failed to count users")
+			assertEqual(t, int64(1), count, "Upserts should leave a single user")
+		})
+	})
+
 	// Test deleteMany
 	act.runWithCleanup(t, db, func() {
 		t.Run("DeleteMany", func(t *testing.T) {
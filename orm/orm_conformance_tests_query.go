@@ -2,7 +2,11 @@ package orm
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/rediwo/redi-orm/types"
 )
@@ -196,6 +200,56 @@ func (act *OrmConformanceTests) runQueryTests(t *testing.T, client *Client, db t
 		})
 	})
 
+	// Test string filters with LIKE wildcards in their values
+	act.runWithCleanup(t, db, func() {
+		t.Run("StringFilters", func(t *testing.T) {
+			ctx := context.Background()
+
+			err := db.LoadSchema(ctx, `
+				model Label {
+					id   Int    @id @default(autoincrement())
+					text String
+				}
+			`)
+			assertNoError(t, err, "Failed to load schema")
+
+			err = db.SyncSchemas(ctx)
+			assertNoError(t, err, "Failed to sync schemas")
+
+			for _, text := range []string{"100% cotton", "100 cotton", "snake_case", "snakeXcase", "wow!", "a.b"} {
+				_, err = client.Model("Label").Create(fmt.Sprintf(`{"data": {"text": %q}}`, text))
+				assertNoError(t, err, "Failed to create label")
+			}
+
+			texts := func(where string) string {
+				t.Helper()
+				result, err := client.Model("Label").FindMany(`{"where": ` + where + `, "orderBy": {"id": "asc"}}`)
+				assertNoError(t, err, "Failed to find with "+where)
+				var found []string
+				for _, r := range result {
+					found = append(found, fmt.Sprint(r["text"]))
+				}
+				return strings.Join(found, ", ")
+			}
+
+			// Wildcards in values match literally
+			assertEqual(t, "100% cotton", texts(`{"text": {"contains": "%"}}`), "contains % mismatch")
+			assertEqual(t, "100% cotton", texts(`{"text": {"startsWith": "100%"}}`), "startsWith 100% mismatch")
+			assertEqual(t, "snake_case", texts(`{"text": {"contains": "_"}}`), "contains _ mismatch")
+			assertEqual(t, "wow!", texts(`{"text": {"endsWith": "!"}}`), "endsWith ! mismatch")
+			assertEqual(t, "a.b", texts(`{"text": {"endsWith": ".b"}}`), "endsWith .b mismatch")
+
+			// not negates values and nested filters
+			assertEqual(t, "100% cotton, 100 cotton, wow!, a.b", texts(`{"text": {"not": {"startsWith": "snake"}}}`), "not startsWith mismatch")
+			assertEqual(t, "snake_case, snakeXcase", texts(`{"text": {"not": {"in": ["100% cotton", "100 cotton", "wow!", "a.b"]}}}`), "not in mismatch")
+			assertEqual(t, "100% cotton, 100 cotton, snake_case, snakeXcase, a.b", texts(`{"text": {"not": "wow!"}}`), "not value mismatch")
+
+			// Filters of a field combine
+			assertEqual(t, "snakeXcase", texts(`{"text": {"startsWith": "snake", "notIn": ["snake_case"]}}`), "combined filters mismatch")
+			assertEqual(t, "100 cotton", texts(`{"text": {"in": ["100 cotton", "none"], "contains": "cotton"}}`), "in and contains mismatch")
+		})
+	})
+
 	// Test sorting and pagination
 	act.runWithCleanup(t, db, func() {
 		t.Run("SortingAndPagination", func(t *testing.T) {
@@ -288,6 +342,97 @@ func (act *OrmConformanceTests) runQueryTests(t *testing.T, client *Client, db t
 		})
 	})
 
+	// Test placing null values first or last
+	act.runWithCleanup(t, db, func() {
+		t.Run("NullsOrdering", func(t *testing.T) {
+			ctx := context.Background()
+
+			err := db.LoadSchema(ctx, `
+				model Task {
+					id       Int    @id @default(autoincrement())
+					title    String
+					priority Int?
+				}
+			`)
+			assertNoError(t, err, "Failed to load schema")
+
+			err = db.SyncSchemas(ctx)
+			assertNoError(t, err, "Failed to sync schemas")
+
+			for _, task := range []string{
+				`{"data": {"title": "B", "priority": 2}}`,
+				`{"data": {"title": "None"}}`,
+				`{"data": {"title": "A", "priority": 1}}`,
+			} {
+				_, err = client.Model("Task").Create(task)
+				assertNoError(t, err, "Failed to create task")
+			}
+
+			titles := func(orderBy string) string {
+				t.Helper()
+				result, err := client.Model("Task").FindMany(`{"orderBy": ` + orderBy + `}`)
+				assertNoError(t, err, "Failed to find with "+orderBy)
+				var found []string
+				for _, r := range result {
+					found = append(found, fmt.Sprint(r["title"]))
+				}
+				return strings.Join(found, ", ")
+			}
+
+			assertEqual(t, "None, A, B", titles(`{"priority": {"sort": "asc", "nulls": "first"}}`), "asc nulls first mismatch")
+			assertEqual(t, "A, B, None", titles(`{"priority": {"sort": "asc", "nulls": "last"}}`), "asc nulls last mismatch")
+			assertEqual(t, "None, B, A", titles(`{"priority": {"sort": "desc", "nulls": "first"}}`), "desc nulls first mismatch")
+			assertEqual(t, "B, A, None", titles(`[{"priority": {"sort": "desc", "nulls": "last"}}, {"title": "asc"}]`), "desc nulls last mismatch")
+		})
+	})
+
+	// Test ordering by several fields
+	act.runWithCleanup(t, db, func() {
+		t.Run("MultiFieldOrderBy", func(t *testing.T) {
+			ctx := context.Background()
+
+			err := db.LoadSchema(ctx, `
+				model Task {
+					id       Int    @id @default(autoincrement())
+					title    String
+					priority Int
+				}
+			`)
+			assertNoError(t, err, "Failed to load schema")
+
+			err = db.SyncSchemas(ctx)
+			assertNoError(t, err, "Failed to sync schemas")
+
+			for _, task := range []string{
+				`{"data": {"title": "B", "priority": 1}}`,
+				`{"data": {"title": "A", "priority": 2}}`,
+				`{"data": {"title": "A", "priority": 1}}`,
+				`{"data": {"title": "B", "priority": 2}}`,
+			} {
+				_, err = client.Model("Task").Create(task)
+				assertNoError(t, err, "Failed to create task")
+			}
+
+			tasks := func(orderBy string) string {
+				t.Helper()
+				result, err := client.Model("Task").FindMany(`{"orderBy": ` + orderBy + `}`)
+				assertNoError(t, err, "Failed to find with "+orderBy)
+				var found []string
+				for _, r := range result {
+					found = append(found, fmt.Sprint(r["title"], r["priority"]))
+				}
+				return strings.Join(found, ", ")
+			}
+
+			// The fields of an object apply in the order they are written
+			assertEqual(t, "A2, A1, B2, B1", tasks(`{"title": "asc", "priority": "desc"}`), "title then priority mismatch")
+			assertEqual(t, "A2, B2, A1, B1", tasks(`{"priority": "desc", "title": "asc"}`), "priority then title mismatch")
+
+			// The objects of an array apply in order
+			assertEqual(t, "B1, A1, B2, A2", tasks(`[{"priority": "asc"}, {"title": "desc"}]`), "array order mismatch")
+		})
+	})
+
 	// Test distinct
 	act.runWithCleanup(t, db, func() {
 		t.Run("Distinct", func(t *testing.T) {
@@ -333,7 +478,7 @@ func (act *OrmConformanceTests) runQueryTests(t *testing.T, client *Client, db t
 			}
 
 			// Test distinct on specific fields (if supported)
-			if db.GetDriverType() == "postgresql" {
+			if db.GetCapabilities().SupportsDistinctOn() {
 				result, err = client.Model("Event").FindMany(`{
 					"distinct": ["type"],
 					"orderBy": {"type": "asc"}
@@ -342,4 +487,278 @@ func (act *OrmConformanceTests) runQueryTests(t *testing.T, client *Client, db t
 			}
 		})
 	})
+
+	// Test scalar list fields and list filters
+	act.runWithCleanup(t, db, func() {
+		t.Run("ScalarLists", func(t *testing.T) {
+			ctx := context.Background()
+
+			err := db.LoadSchema(ctx, `
+				model Post {
+					id     Int      @id @default(autoincrement())
+					title  String
+					tags   String[]
+					scores Int[]
+				}
+			`)
+			assertNoError(t, err, "Failed to load schema")
+
+			err = db.SyncSchemas(ctx)
+			assertNoError(t, err, "Failed to sync schemas")
+
+			posts := []string{
+				`{"data": {"title": "A", "tags": ["go", "orm"], "scores": [1, 2]}}`,
+				`{"data": {"title": "B", "tags": ["js"], "scores": [3]}}`,
+				`{"data": {"title": "C", "tags": [], "scores": []}}`,
+			}
+			for _, post := range posts {
+				_, err = client.Model("Post").Create(post)
+				assertNoError(t, err, "Failed to create post")
+			}
+
+			post, err := client.Model("Post").FindFirst(`{"where": {"title": "A"}}`)
+			assertNoError(t, err, "Failed to find post")
+			assertEqual(t, "[go orm]", fmt.Sprint(post["tags"]), "String list mismatch")
+			assertEqual(t, "[1 2]", fmt.Sprint(post["scores"]), "Int list mismatch")
+
+			titles := func(where string) string {
+				t.Helper()
+				result, err := client.Model("Post").FindMany(fmt.Sprintf(`{"where": %s, "orderBy": {"title": "asc"}}`, where))
+				assertNoError(t, err, "Failed to filter posts by "+where)
+				var names []string
+				for _, r := range result {
+					names = append(names, fmt.Sprint(r["title"]))
+				}
+				return strings.Join(names, ",")
+			}
+
+			assertEqual(t, "A", titles(`{"tags": {"has": "go"}}`), "has filter mismatch")
+			assertEqual(t, "B", titles(`{"scores": {"has": 3}}`), "has filter on Int list mismatch")
+			assertEqual(t, "A,B", titles(`{"tags": {"hasSome": ["orm", "js"]}}`), "hasSome filter mismatch")
+			assertEqual(t, "A", titles(`{"tags": {"hasEvery": ["go", "orm"]}}`), "hasEvery filter mismatch")
+			assertEqual(t, "", titles(`{"tags": {"hasEvery": ["go", "js"]}}`), "hasEvery filter mismatch")
+			assertEqual(t, "C", titles(`{"tags": {"isEmpty": true}}`), "isEmpty filter mismatch")
+			assertEqual(t, "A,B", titles(`{"tags": {"isEmpty": false}}`), "isEmpty false filter mismatch")
+
+			updated, err := client.Model("Post").Update(`{
+				"where": {"title": "B"},
+				"data": {"tags": {"push": "ts"}, "scores": {"set": [4, 5]}}
+			}`)
+			assertNoError(t, err, "Failed to update lists")
+			assertEqual(t, "[js ts]", fmt.Sprint(updated["tags"]), "push mismatch")
+			assertEqual(t, "[4 5]", fmt.Sprint(updated["scores"]), "set list mismatch")
+		})
+
+		t.Run("ScalarListDefaults", func(t *testing.T) {
+			ctx := context.Background()
+
+			err := db.LoadSchema(ctx, `
+				model Item {
+					id     Int      @id @default(autoincrement())
+					title  String   @unique
+					tags   String[]
+					labels String[] @default(["new"])
+					flags  String[] @default([])
+				}
+			`)
+			assertNoError(t, err, "Failed to load schema")
+
+			err = db.SyncSchemas(ctx)
+			assertNoError(t, err, "Failed to sync schemas")
+
+			created, err := client.Model("Item").Create(`{"data": {"title": "A"}}`)
+			assertNoError(t, err, "Failed to create an item without lists")
+			assertEqual(t, "[]", fmt.Sprint(created["tags"]), "Omitted list mismatch")
+			assertEqual(t, "[new]", fmt.Sprint(created["labels"]), "List default mismatch")
+			assertEqual(t, "[]", fmt.Sprint(created["flags"]), "Empty list default mismatch")
+
+			_, err = client.Model("Item").Query(`{"createMany": {"data": [{"title": "B", "tags": ["go"]}, {"title": "C"}]}}`)
+			assertNoError(t, err, "Failed to create items without lists")
+
+			_, err = client.Model("Item").Upsert(`{"where": {"title": "D"}, "create": {"title": "D"}, "update": {}}`)
+			assertNoError(t, err, "Failed to upsert an item without lists")
+
+			for _, title := range []string{"C", "D"} {
+				item, err := client.Model("Item").FindFirst(fmt.Sprintf(`{"where": {"title": %q}}`, title))
+				assertNoError(t, err, "Failed to find item "+title)
+				assertEqual(t, "[]", fmt.Sprint(item["tags"]), "Omitted list mismatch of "+title)
+				assertEqual(t, "[new]", fmt.Sprint(item["labels"]), "List default mismatch of "+title)
+			}
+
+			count, err := client.Model("Item").Count(`{"where": {"tags": {"has": "go"}}}`)
+			assertNoError(t, err, "Failed to filter omitted lists with has")
+			assertEqual(t, int64(1), count, "has filter mismatch")
+
+			count, err = client.Model("Item").Count(`{"where": {"labels": {"has": "new"}, "flags": {"isEmpty": true}}}`)
+			assertNoError(t, err, "Failed to filter list defaults")
+			assertEqual(t, int64(4), count, "List default filter mismatch")
+		})
+
+		t.Run("BytesFields", func(t *testing.T) {
+			ctx := context.Background()
+
+			err := db.LoadSchema(ctx, `
+				model Attachment {
+					id        Int    @id @default(autoincrement())
+					name      String
+					data      Bytes
+					thumbnail Bytes?
+				}
+			`)
+			assertNoError(t, err, "Failed to load schema")
+
+			err = db.SyncSchemas(ctx)
+			assertNoError(t, err, "Failed to sync schemas")
+
+			bytesValue := func(record map[string]any, field string) string {
+				t.Helper()
+				data, ok := record[field].([]byte)
+				if !ok {
+					t.Fatalf("Expected []byte for %s, got %T", field, record[field])
+				}
+				return string(data)
+			}
+
+			// JSON carries binary data as base64 text
+			created, err := client.Model("Attachment").Create(`{"data": {"name": "a.bin", "data": "AAH/"}}`)
+			assertNoError(t, err, "Failed to create attachment")
+			assertEqual(t, "\x00\x01\xff", bytesValue(created, "data"), "Bytes value mismatch")
+			assertEqual(t, nil, created["thumbnail"], "NULL bytes value mismatch")
+
+			encoded, err := json.Marshal(created)
+			assertNoError(t, err, "Failed to encode attachment")
+			if !strings.Contains(string(encoded), `"data":"AAH/"`) {
+				t.Errorf("Expected bytes to encode as base64 in JSON, got %s", encoded)
+			}
+
+			// []byte values are written as they are
+			_, err = db.Model("Attachment").Insert(map[string]any{"name": "b.bin", "data": []byte("hello")}).Exec(ctx)
+			assertNoError(t, err, "Failed to insert attachment")
+			found, err := client.Model("Attachment").FindFirst(`{"where": {"name": "b.bin"}}`)
+			assertNoError(t, err, "Failed to find attachment")
+			assertEqual(t, "hello", bytesValue(found, "data"), "Bytes value mismatch")
+
+			updated, err := client.Model("Attachment").Update(`{"where": {"name": "a.bin"}, "data": {"thumbnail": "aGk="}}`)
+			assertNoError(t, err, "Failed to update attachment")
+			assertEqual(t, "hi", bytesValue(updated, "thumbnail"), "Updated bytes value mismatch")
+
+			_, err = client.Model("Attachment").Create(`{"data": {"name": "c.bin", "data": "not base64!"}}`)
+			if err == nil {
+				t.Error("Expected an error for invalid base64 data")
+			}
+		})
+
+		t.Run("DateTimeNativeTypes", func(t *testing.T) {
+			ctx := context.Background()
+
+			err := db.LoadSchema(ctx, `
+				model Shift {
+					id       Int      @id @default(autoincrement())
+					name     String
+					startsAt DateTime @db.Timestamptz
+					day      DateTime @db.Date
+					opensAt  DateTime @db.Time
+					endsAt   DateTime?
+				}
+			`)
+			assertNoError(t, err, "Failed to load schema")
+
+			err = db.SyncSchemas(ctx)
+			assertNoError(t, err, "Failed to sync schemas")
+
+			timeValue := func(record map[string]any, field string) time.Time {
+				t.Helper()
+				value, ok := record[field].(time.Time)
+				if !ok {
+					t.Fatalf("Expected time.Time for %s, got %T", field, record[field])
+				}
+				if value.Location() != time.UTC {
+					t.Errorf("Expected %s in UTC, got %v", field, value.Location())
+				}
+				return value
+			}
+
+			// Times with an offset are stored in UTC, and @db.Date and @db.Time keep the UTC
+			// date and time of day
+			_, err = client.Model("Shift").Create(`{"data": {"name": "early", "startsAt": "2024-03-01T08:30:00+02:00", "day": "2024-03-01T08:30:00+02:00", "opensAt": "2024-03-01T08:30:00+02:00"}}`)
+			assertNoError(t, err, "Failed to create shift")
+
+			early, err := client.Model("Shift").FindFirst(`{"where": {"name": "early"}}`)
+			assertNoError(t, err, "Failed to find shift")
+			assertEqual(t, time.Date(2024, 3, 1, 6, 30, 0, 0, time.UTC), timeValue(early, "startsAt"), "DateTime value mismatch")
+			assertEqual(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), timeValue(early, "day"), "Date value mismatch")
+			assertEqual(t, time.Date(1970, 1, 1, 6, 30, 0, 0, time.UTC), timeValue(early, "opensAt"), "Time value mismatch")
+
+			// time.Time values in another zone are the same instant
+			local := time.Date(2024, 3, 1, 20, 0, 0, 0, time.FixedZone("UTC-5", -5*60*60))
+			_, err = db.Model("Shift").Insert(map[string]any{
+				"name": "late", "startsAt": local, "day": local, "opensAt": local, "endsAt": local,
+			}).Exec(ctx)
+			assertNoError(t, err, "Failed to insert shift")
+
+			found, err := client.Model("Shift").FindFirst(`{"where": {"name": "late"}}`)
+			assertNoError(t, err, "Failed to find shift")
+			assertEqual(t, true, timeValue(found, "startsAt").Equal(local), "DateTime instant mismatch")
+			assertEqual(t, true, timeValue(found, "endsAt").Equal(local), "DateTime instant mismatch")
+			assertEqual(t, time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), timeValue(found, "day"), "Date value mismatch")
+			assertEqual(t, time.Date(1970, 1, 1, 1, 0, 0, 0, time.UTC), timeValue(found, "opensAt"), "Time value mismatch")
+
+			selectQuery := db.Model("Shift").Select()
+			var shifts []map[string]any
+			err = selectQuery.WhereCondition(selectQuery.Where("startsAt").GreaterThan(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))).FindMany(ctx, &shifts)
+			assertNoError(t, err, "Failed to filter shifts")
+			assertEqual(t, 1, len(shifts), "DateTime filter mismatch")
+
+			_, err = client.Model("Shift").Create(`{"data": {"name": "bad", "startsAt": "tomorrow", "day": "2024-03-01", "opensAt": "08:00:00"}}`)
+			if err == nil {
+				t.Error("Expected an error for an invalid DateTime")
+			}
+		})
+
+		t.Run("DecimalAndBigIntFields", func(t *testing.T) {
+			ctx := context.Background()
+
+			err := db.LoadSchema(ctx, `
+				model Wallet {
+					id      Int      @id @default(autoincrement())
+					owner   String
+					balance Decimal
+					ceiling Decimal?
+					points  BigInt
+				}
+			`)
+			assertNoError(t, err, "Failed to load schema")
+
+			err = db.SyncSchemas(ctx)
+			assertNoError(t, err, "Failed to sync schemas")
+
+			// Decimals are returned as their exact text and integers beyond 2^53 stay exact
+			created, err := client.Model("Wallet").Create(`{"data": {"owner": "alice", "balance": "1234.56", "points": 9007199254740993}}`)
+			assertNoError(t, err, "Failed to create wallet")
+			assertEqual(t, "1234.56", created["balance"], "Decimal value mismatch")
+			assertEqual(t, nil, created["ceiling"], "NULL decimal value mismatch")
+			assertEqual(t, int64(9007199254740993), created["points"], "BigInt value mismatch")
+
+			// Decimal numbers in JSON are written with their digits
+			_, err = client.Model("Wallet").Create(`{"data": {"owner": "bob", "balance": 0.1, "ceiling": 250, "points": 1}}`)
+			assertNoError(t, err, "Failed to create wallet")
+
+			found, err := client.Model("Wallet").FindFirst(`{"where": {"points": 9007199254740993}}`)
+			assertNoError(t, err, "Failed to find wallet")
+			assertEqual(t, "alice", found["owner"], "Wallet found by BigInt mismatch")
+
+			found, err = client.Model("Wallet").FindFirst(`{"where": {"owner": "bob"}}`)
+			assertNoError(t, err, "Failed to find wallet")
+			assertEqual(t, "0.1", strings.TrimRight(found["balance"].(string), "0"), "Decimal value mismatch")
+
+			wallets, err := client.Model("Wallet").FindMany(`{"where": {"balance": {"gt": 100}}}`)
+			assertNoError(t, err, "Failed to filter wallets")
+			assertEqual(t, 1, len(wallets), "Decimal filter mismatch")
+
+			_, err = client.Model("Wallet").Create(`{"data": {"owner": "carol", "balance": "ten", "points": 1}}`)
+			if err == nil {
+				t.Error("Expected an error for an invalid decimal")
+			}
+		})
+	})
 }
@@ -3,6 +3,7 @@ package orm
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/rediwo/redi-orm/types"
@@ -259,6 +260,36 @@ func (act *OrmConformanceTests) runRelationTests(t *testing.T, client *Client, d
 			} else {
 				t.Log("Comments not included or wrong type - nested includes may not be fully supported")
 			}
+
+			// Includes go as deep as the relations do
+			result, err = client.Model("Post").FindUnique(fmt.Sprintf(`{
+				"where": {"id": %v},
+				"include": {
+					"comments": {
+						"orderBy": {"id": "asc"},
+						"include": {
+							"author": {
+								"include": {"posts": {"include": {"author": true}}}
+							}
+						}
+					}
+				}
+			}`, post["id"]))
+			assertNoError(t, err, "Failed to find post with deep includes")
+			comments, _ := result["comments"].([]any)
+			assertEqual(t, 2, len(comments), "Comments count mismatch")
+			commenter, _ := comments[1].(map[string]any)["author"].(map[string]any)
+			commenterPosts, _ := commenter["posts"].([]any)
+			assertEqual(t, 1, len(commenterPosts), "Comment author posts count mismatch")
+			postAuthor, _ := commenterPosts[0].(map[string]any)["author"].(map[string]any)
+			assertEqual(t, "Alice", postAuthor["name"], "Deeply included author name mismatch")
+
+			// Include options that contain themselves are rejected
+			cyclic := map[string]any{}
+			cyclic["include"] = map[string]any{"author": cyclic}
+			if _, err := applyInclude(db.Model("Post").Select(), map[string]any{"comments": cyclic}); err == nil {
+				t.Fatal("Expected an error for cyclic include options")
+			}
 		})
 	})
 
@@ -349,4 +380,372 @@ func (act *OrmConformanceTests) runRelationTests(t *testing.T, client *Client, d
 			}
 		})
 	})
+
+	// Test relation load strategies
+	act.runWithCleanup(t, db, func() {
+		t.Run("RelationLoadStrategy", func(t *testing.T) {
+			ctx := context.Background()
+
+			// Load schema
+			err := db.LoadSchema(ctx, `
+				model User {
+					id    Int    @id @default(autoincrement())
+					name  String
+					posts Post[]
+				}
+
+				model Post {
+					id       Int       @id @default(autoincrement())
+					title    String
+					views    Int       @default(0)
+					authorId Int
+					author   User      @relation(fields: [authorId], references: [id])
+					comments Comment[]
+				}
+
+				model Comment {
+					id      Int    @id @default(autoincrement())
+					content String
+					postId  Int
+					post    Post   @relation(fields: [postId], references: [id])
+				}
+			`)
+			assertNoError(t, err, "Failed to load schema")
+
+			err = db.SyncSchemas(ctx)
+			assertNoError(t, err, "Failed to sync schemas")
+
+			// Create test data
+			alice, err := client.Model("User").Create(`{"data": {"name": "Alice"}}`)
+			assertNoError(t, err, "Failed to create user 1")
+			_, err = client.Model("User").Create(`{"data": {"name": "Bob"}}`)
+			assertNoError(t, err, "Failed to create user 2")
+
+			for i, views := range []int{10, 30, 20} {
+				post, err := client.Model("Post").Create(fmt.Sprintf(`{"data": {"title": "Post %d", "views": %d, "authorId": %v}}`, i+1, views, alice["id"]))
+				assertNoError(t, err, "Failed to create post")
+				_, err = client.Model("Comment").Create(fmt.Sprintf(`{"data": {"content": "Comment on post %d", "postId": %v}}`, i+1, post["id"]))
+				assertNoError(t, err, "Failed to create comment")
+			}
+
+			for _, strategy := range []string{"join", "query"} {
+				users, err := client.Model("User").FindMany(fmt.Sprintf(`{
+					"relationLoadStrategy": %q,
+					"orderBy": {"id": "asc"},
+					"include": {
+						"posts": {
+							"orderBy": {"views": "desc"},
+							"take": 2,
+							"include": {"comments": true}
+						}
+					}
+				}`, strategy))
+				assertNoError(t, err, "Failed to find users with "+strategy+" strategy")
+				assertEqual(t, 2, len(users), "Users count mismatch with "+strategy+" strategy")
+
+				posts, ok := users[0]["posts"].([]any)
+				if !ok {
+					t.Fatalf("Posts not included with %s strategy", strategy)
+				}
+				assertEqual(t, 2, len(posts), "Paginated posts count mismatch with "+strategy+" strategy")
+				if post, ok := posts[0].(map[string]any); ok {
+					assertEqual(t, "Post 2", post["title"], "Posts should be ordered by views with "+strategy+" strategy")
+					comments, ok := post["comments"].([]any)
+					if !ok || len(comments) != 1 {
+						t.Errorf("Expected 1 nested comment with %s strategy, got %v", strategy, post["comments"])
+					}
+				}
+
+				if posts, _ := users[1]["posts"].([]any); len(posts) != 0 {
+					t.Errorf("Expected no posts for Bob with %s strategy, got %v", strategy, users[1]["posts"])
+				}
+			}
+
+			// Test to-one relation with query strategy
+			post, err := client.Model("Post").FindFirst(`{
+				"relationLoadStrategy": "query",
+				"where": {"title": "Post 3"},
+				"include": {"author": true}
+			}`)
+			assertNoError(t, err, "Failed to find post with author")
+			if author, ok := post["author"].(map[string]any); ok {
+				assertEqual(t, "Alice", author["name"], "Post author name mismatch")
+			} else {
+				t.Fatal("Author not included or wrong type")
+			}
+
+			// Test invalid strategy
+			_, err = client.Model("User").FindMany(`{"relationLoadStrategy": "lateral"}`)
+			if err == nil {
+				t.Error("Expected error for invalid relationLoadStrategy")
+			}
+		})
+	})
+
+	// Test the shape of to-one includes
+	act.runWithCleanup(t, db, func() {
+		t.Run("ToOneIncludeShape", func(t *testing.T) {
+			ctx := context.Background()
+
+			// Load schema
+			err := db.LoadSchema(ctx, `
+				model User {
+					id      Int      @id @default(autoincrement())
+					name    String
+					profile Profile?
+					posts   Post[]
+				}
+
+				model Profile {
+					id     Int    @id @default(autoincrement())
+					bio    String
+					userId Int    @unique
+					user   User   @relation(fields: [userId], references: [id])
+				}
+
+				model Post {
+					id       Int    @id @default(autoincrement())
+					title    String
+					authorId Int?
+					author   User?  @relation(fields: [authorId], references: [id])
+				}
+			`)
+			assertNoError(t, err, "Failed to load schema")
+
+			err = db.SyncSchemas(ctx)
+			assertNoError(t, err, "Failed to sync schemas")
+
+			// Create test data
+			alice, err := client.Model("User").Create(`{"data": {"name": "Alice"}}`)
+			assertNoError(t, err, "Failed to create user 1")
+			bob, err := client.Model("User").Create(`{"data": {"name": "Bob"}}`)
+			assertNoError(t, err, "Failed to create user 2")
+			_, err = client.Model("Profile").Create(fmt.Sprintf(`{"data": {"bio": "Hello", "userId": %v}}`, alice["id"]))
+			assertNoError(t, err, "Failed to create profile")
+			_, err = client.Model("Post").Create(fmt.Sprintf(`{"data": {"title": "Written", "authorId": %v}}`, alice["id"]))
+			assertNoError(t, err, "Failed to create post 1")
+			_, err = client.Model("Post").Create(`{"data": {"title": "Anonymous"}}`)
+			assertNoError(t, err, "Failed to create post 2")
+
+			// assertRecord checks that a to-one relation is a single record with the given field value
+			assertRecord := func(t *testing.T, value any, field string, expected any, msg string) {
+				t.Helper()
+				record, ok := value.(map[string]any)
+				if !ok {
+					t.Fatalf("%s: expected a record, got %#v", msg, value)
+				}
+				assertEqual(t, expected, record[field], msg)
+			}
+			// assertNull checks that a to-one relation is present and null
+			assertNull := func(t *testing.T, record map[string]any, relation string, msg string) {
+				t.Helper()
+				if value, exists := record[relation]; !exists || value != nil {
+					t.Fatalf("%s: expected %s to be null, got %#v", msg, relation, value)
+				}
+			}
+
+			for _, strategy := range []string{"join", "query"} {
+				t.Run(strategy, func(t *testing.T) {
+					user, err := client.Model("User").FindUnique(fmt.Sprintf(`{
+						"relationLoadStrategy": %q,
+						"where": {"id": %v},
+						"include": {"profile": true}
+					}`, strategy, alice["id"]))
+					assertNoError(t, err, "Failed to find user with profile")
+					assertRecord(t, user["profile"], "bio", "Hello", "Profile mismatch")
+
+					user, err = client.Model("User").FindUnique(fmt.Sprintf(`{
+						"relationLoadStrategy": %q,
+						"where": {"id": %v},
+						"include": {"profile": true}
+					}`, strategy, bob["id"]))
+					assertNoError(t, err, "Failed to find user without profile")
+					assertNull(t, user, "profile", "User without profile")
+
+					post, err := client.Model("Post").FindFirst(fmt.Sprintf(`{
+						"relationLoadStrategy": %q,
+						"where": {"title": "Written"},
+						"include": {"author": {"include": {"profile": true}}}
+					}`, strategy))
+					assertNoError(t, err, "Failed to find post with author")
+					assertRecord(t, post["author"], "name", "Alice", "Post author mismatch")
+					assertRecord(t, post["author"].(map[string]any)["profile"], "bio", "Hello", "Nested profile mismatch")
+
+					post, err = client.Model("Post").FindFirst(fmt.Sprintf(`{
+						"relationLoadStrategy": %q,
+						"where": {"title": "Anonymous"},
+						"include": {"author": {"include": {"profile": true}}}
+					}`, strategy))
+					assertNoError(t, err, "Failed to find post without author")
+					assertNull(t, post, "author", "Post without author")
+
+					users, err := client.Model("User").FindMany(fmt.Sprintf(`{
+						"relationLoadStrategy": %q,
+						"orderBy": {"id": "asc"},
+						"include": {"profile": true, "posts": {"include": {"author": true}}}
+					}`, strategy))
+					assertNoError(t, err, "Failed to find users with profiles")
+					assertEqual(t, 2, len(users), "Users count mismatch")
+					assertRecord(t, users[0]["profile"], "bio", "Hello", "First user profile mismatch")
+					posts, _ := users[0]["posts"].([]any)
+					assertEqual(t, 1, len(posts), "First user posts count mismatch")
+					assertRecord(t, posts[0].(map[string]any)["author"], "name", "Alice", "Nested author mismatch")
+					assertNull(t, users[1], "profile", "Second user")
+					if posts, ok := users[1]["posts"].([]any); !ok || len(posts) != 0 {
+						t.Fatalf("Expected an empty posts array for the second user, got %#v", users[1]["posts"])
+					}
+				})
+			}
+		})
+	})
+
+	// Test filtering on the record of to-one relations
+	act.runWithCleanup(t, db, func() {
+		t.Run("RelationFilters", func(t *testing.T) {
+			ctx := context.Background()
+
+			// Load schema
+			err := db.LoadSchema(ctx, `
+				model User {
+					id      Int      @id @default(autoincrement())
+					name    String
+					profile Profile?
+					posts   Post[]
+				}
+
+				model Profile {
+					id     Int    @id @default(autoincrement())
+					bio    String
+					userId Int    @unique
+					user   User   @relation(fields: [userId], references: [id])
+				}
+
+				model Post {
+					id       Int    @id @default(autoincrement())
+					title    String
+					authorId Int?
+					author   User?  @relation(fields: [authorId], references: [id])
+				}
+			`)
+			assertNoError(t, err, "Failed to load schema")
+
+			err = db.SyncSchemas(ctx)
+			assertNoError(t, err, "Failed to sync schemas")
+
+			// Create test data
+			alice, err := client.Model("User").Create(`{"data": {"name": "Alice"}}`)
+			assertNoError(t, err, "Failed to create user 1")
+			bob, err := client.Model("User").Create(`{"data": {"name": "Bob"}}`)
+			assertNoError(t, err, "Failed to create user 2")
+			_, err = client.Model("Profile").Create(fmt.Sprintf(`{"data": {"bio": "Hello", "userId": %v}}`, alice["id"]))
+			assertNoError(t, err, "Failed to create profile")
+			for _, post := range []string{
+				fmt.Sprintf(`{"data": {"title": "By Alice", "authorId": %v}}`, alice["id"]),
+				fmt.Sprintf(`{"data": {"title": "By Bob", "authorId": %v}}`, bob["id"]),
+				`{"data": {"title": "Anonymous"}}`,
+			} {
+				_, err = client.Model("Post").Create(post)
+				assertNoError(t, err, "Failed to create post")
+			}
+
+			titles := func(where string) string {
+				t.Helper()
+				result, err := client.Model("Post").FindMany(`{"where": ` + where + `, "orderBy": {"id": "asc"}}`)
+				assertNoError(t, err, "Failed to find posts with "+where)
+				var found []string
+				for _, r := range result {
+					found = append(found, fmt.Sprint(r["title"]))
+				}
+				return strings.Join(found, ", ")
+			}
+
+			// Foreign key on the filtered model
+			assertEqual(t, "By Alice", titles(`{"author": {"is": {"name": "Alice"}}}`), "is mismatch")
+			assertEqual(t, "By Bob, Anonymous", titles(`{"author": {"isNot": {"name": "Alice"}}}`), "isNot mismatch")
+			assertEqual(t, "Anonymous", titles(`{"author": {"is": null}}`), "is null mismatch")
+			assertEqual(t, "By Alice, By Bob", titles(`{"author": {"isNot": null}}`), "isNot null mismatch")
+			assertEqual(t, "By Alice, By Bob", titles(`{"author": {"is": {}}}`), "is empty mismatch")
+
+			// Nested relations and other filters
+			assertEqual(t, "By Alice", titles(`{"author": {"is": {"profile": {"is": {"bio": {"startsWith": "Hel"}}}}}}`), "nested is mismatch")
+			assertEqual(t, "By Bob", titles(`{"title": {"startsWith": "By"}, "author": {"is": {"profile": {"is": null}}}}`), "nested is null mismatch")
+			assertEqual(t, "By Bob, Anonymous", titles(`{"OR": [{"author": {"is": {"name": "Bob"}}}, {"authorId": null}]}`), "OR mismatch")
+
+			// Foreign key on the related model
+			users, err := client.Model("User").FindMany(`{"where": {"profile": {"is": {"bio": "Hello"}}}}`)
+			assertNoError(t, err, "Failed to find users with profile")
+			assertEqual(t, 1, len(users), "Users with profile count mismatch")
+			assertEqual(t, "Alice", users[0]["name"], "User with profile mismatch")
+
+			count, err := client.Model("User").Count(`{"where": {"profile": {"is": null}}}`)
+			assertNoError(t, err, "Failed to count users without profile")
+			assertEqual(t, int64(1), count, "Users without profile count mismatch")
+		})
+	})
+
+	// Test ordering by the number of records of a relation
+	act.runWithCleanup(t, db, func() {
+		t.Run("OrderByRelationCount", func(t *testing.T) {
+			ctx := context.Background()
+
+			// Load schema
+			err := db.LoadSchema(ctx, `
+				model User {
+					id    Int    @id @default(autoincrement())
+					name  String
+					posts Post[]
+				}
+
+				model Post {
+					id       Int    @id @default(autoincrement())
+					title    String
+					authorId Int
+					author   User   @relation(fields: [authorId], references: [id])
+				}
+			`)
+			assertNoError(t, err, "Failed to load schema")
+
+			err = db.SyncSchemas(ctx)
+			assertNoError(t, err, "Failed to sync schemas")
+
+			// Bob writes two posts, Alice one and Carol none
+			counts := map[string]int{"Alice": 1, "Bob": 2, "Carol": 0}
+			for _, name := range []string{"Alice", "Bob", "Carol"} {
+				user, err := client.Model("User").Create(fmt.Sprintf(`{"data": {"name": %q}}`, name))
+				assertNoError(t, err, "Failed to create user")
+				for i := 0; i < counts[name]; i++ {
+					_, err = client.Model("Post").Create(fmt.Sprintf(`{"data": {"title": "Post %d", "authorId": %v}}`, i, user["id"]))
+					assertNoError(t, err, "Failed to create post")
+				}
+			}
+
+			names := func(orderBy string) string {
+				t.Helper()
+				result, err := client.Model("User").FindMany(`{"orderBy": ` + orderBy + `}`)
+				assertNoError(t, err, "Failed to find with "+orderBy)
+				var found []string
+				for _, r := range result {
+					found = append(found, fmt.Sprint(r["name"]))
+				}
+				return strings.Join(found, ", ")
+			}
+
+			assertEqual(t, "Bob, Alice, Carol", names(`{"posts": {"_count": "desc"}}`), "count desc mismatch")
+			assertEqual(t, "Carol, Alice, Bob", names(`{"posts": {"_count": "asc"}}`), "count asc mismatch")
+
+			// Combined with filters, pagination and includes
+			result, err := client.Model("User").FindMany(`{
+				"where": {"name": {"not": "Bob"}},
+				"orderBy": [{"posts": {"_count": "desc"}}, {"name": "asc"}],
+				"take": 1,
+				"include": {"posts": true}
+			}`)
+			assertNoError(t, err, "Failed to find users by post count")
+			assertEqual(t, 1, len(result), "Paginated users count mismatch")
+			assertEqual(t, "Alice", result[0]["name"], "Paginated user mismatch")
+			if posts, ok := result[0]["posts"].([]any); !ok || len(posts) != 1 {
+				t.Fatalf("Expected the posts of Alice, got %#v", result[0]["posts"])
+			}
+		})
+	})
 }
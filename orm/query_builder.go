@@ -2,8 +2,13 @@ package orm
 
 import (
 	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
 	"strings"
 
+	"github.com/rediwo/redi-orm/schema"
 	"github.com/rediwo/redi-orm/types"
 	"github.com/rediwo/redi-orm/utils"
 )
@@ -106,6 +111,11 @@ func buildFieldCondition(field string, value any) types.Condition {
 			case "not":
 				if val == nil {
 					cond = fieldCond.IsNotNull()
+				} else if _, ok := val.(map[string]any); ok {
+					// Nested filter, such as { not: { contains: "test" } }
+					if notCond := buildFieldCondition(field, val); notCond != nil {
+						cond = types.NewNotCondition(notCond)
+					}
 				} else {
 					cond = fieldCond.NotEquals(val)
 				}
@@ -143,6 +153,27 @@ func buildFieldCondition(field string, value any) types.Condition {
 				} else {
 					cond = fieldCond.EndsWith(fmt.Sprintf("%v", val))
 				}
+			case "has":
+				cond = fieldCond.Has(val)
+			case "hasSome":
+				if values, ok := val.([]any); ok {
+					cond = fieldCond.HasSome(values...)
+				}
+			case "hasEvery":
+				if values, ok := val.([]any); ok {
+					cond = fieldCond.HasEvery(values...)
+				}
+			case "isEmpty":
+				if empty, ok := val.(bool); ok {
+					if empty {
+						cond = fieldCond.IsEmpty()
+					} else {
+						cond = fieldCond.IsNotEmpty()
+					}
+				}
+			case "is", "isNot":
+				// Filter on the record of a to-one relation, such as { author: { is: { name: "Alice" } } }
+				cond = buildRelationCondition(field, op == "isNot", val)
 			}
 
 			if cond != nil {
@@ -167,6 +198,18 @@ func buildFieldCondition(field string, value any) types.Condition {
 	return fieldCond.Equals(value)
 }
 
+// buildRelationCondition builds the is and isNot filters of a to-one relation. A null filter
+// matches records without a related record, and an empty filter any related record.
+func buildRelationCondition(relation string, negate bool, filter any) types.Condition {
+	var nested types.Condition
+	if filter != nil {
+		if nested = BuildCondition(filter); nested == nil {
+			negate = !negate
+		}
+	}
+	return &types.RelationCondition{Relation: relation, Condition: nested, Negate: negate}
+}
+
 // applyOrderBy applies orderBy conditions to a query
 func applyOrderBy(query any, orderBy any) any {
 	return applyOrderByToQuery(query, orderBy)
@@ -174,54 +217,82 @@ func applyOrderBy(query any, orderBy any) any {
 
 // applyOrderByToQuery handles different orderBy formats
 func applyOrderByToQuery(query any, orderBy any) any {
-	// Handle single orderBy object: { field: 'asc' }
-	if orderMap, ok := orderBy.(map[string]any); ok {
-		for field, direction := range orderMap {
-			dir := types.ASC
-			if dirStr, ok := direction.(string); ok && dirStr == "desc" {
-				dir = types.DESC
-			}
+	for _, entry := range orderByEntries(orderBy) {
+		query = applyOrderByField(query, entry.Field, entry.Direction)
+	}
+	return query
+}
 
-			switch q := query.(type) {
-			case types.SelectQuery:
-				query = q.OrderBy(field, dir)
-			case types.ModelQuery:
-				query = q.OrderBy(field, dir)
-			}
+// orderByEntry is a field of an orderBy with its direction
+type orderByEntry struct {
+	Field     string
+	Direction any
+}
+
+// orderByEntries lists the fields of an orderBy in the order they apply. An array of
+// objects, as in [{ name: 'asc' }, { age: 'desc' }], applies in order. The keys of a single
+// object apply in the order they were written when the client kept it (see
+// keepOrderByOrder), and by name otherwise, so that the order does not vary between runs.
+func orderByEntries(orderBy any) []orderByEntry {
+	var entries []orderByEntry
+	switch v := orderBy.(type) {
+	case map[string]any:
+		fields := make([]string, 0, len(v))
+		for field := range v {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			entries = append(entries, orderByEntry{Field: field, Direction: v[field]})
+		}
+	case []any:
+		for _, item := range v {
+			entries = append(entries, orderByEntries(item)...)
 		}
-		return query
 	}
+	return entries
+}
 
-	// Handle array of orderBy objects: [{ field: 'asc' }, { field2: 'desc' }]
-	if orderArray, ok := orderBy.([]any); ok {
-		for _, item := range orderArray {
-			if orderMap, ok := item.(map[string]any); ok {
-				for field, direction := range orderMap {
-					dir := types.ASC
-					if dirStr, ok := direction.(string); ok && dirStr == "desc" {
-						dir = types.DESC
-					}
+// applyOrderByField orders a query by a field. Besides 'asc' and 'desc', direction may place
+// null values, as in { sort: 'asc', nulls: 'last' }, or order by the number of records of a
+// relation, as in { _count: 'desc' }.
+func applyOrderByField(query any, field string, direction any) any {
+	options, ok := direction.(map[string]any)
+	if !ok {
+		options = map[string]any{"sort": direction}
+	}
 
-					switch q := query.(type) {
-					case types.SelectQuery:
-						query = q.OrderBy(field, dir)
-					case types.ModelQuery:
-						query = q.OrderBy(field, dir)
-					}
-				}
-			}
+	if q, ok := query.(types.SelectQuery); ok {
+		if count, ok := options["_count"]; ok {
+			return q.OrderByRelationCount(field, orderDirection(count))
 		}
-		return query
+		switch options["nulls"] {
+		case "first":
+			return q.OrderByNulls(field, orderDirection(options["sort"]), types.NullsFirst)
+		case "last":
+			return q.OrderByNulls(field, orderDirection(options["sort"]), types.NullsLast)
+		}
+		return q.OrderBy(field, orderDirection(options["sort"]))
+	}
+	if q, ok := query.(types.ModelQuery); ok {
+		return q.OrderBy(field, orderDirection(options["sort"]))
 	}
-
 	return query
 }
 
+// orderDirection returns the order of a direction, ascending unless it is 'desc'
+func orderDirection(direction any) types.Order {
+	if dirStr, ok := direction.(string); ok && dirStr == "desc" {
+		return types.DESC
+	}
+	return types.ASC
+}
+
 // applyInclude applies include options to a query
-func applyInclude(query any, include any) any {
+func applyInclude(query any, include any) (any, error) {
 	selectQuery, ok := query.(types.SelectQuery)
 	if !ok {
-		return query
+		return query, nil
 	}
 
 	// Handle different include formats
@@ -239,7 +310,10 @@ func applyInclude(query any, include any) any {
 				}
 			case map[string]any:
 				// Handle nested include with options
-				includeOpts := parseNestedIncludes(relationName, opts)
+				includeOpts, err := parseNestedIncludes(relationName, opts, nil)
+				if err != nil {
+					return nil, err
+				}
 				for path, opt := range includeOpts {
 					allNestedIncludes[path] = opt
 				}
@@ -267,7 +341,7 @@ func applyInclude(query any, include any) any {
 			}
 		}
 	}
-	return selectQuery
+	return selectQuery, nil
 }
 
 // applyIncludeOption applies a single include option to the query
@@ -276,8 +350,27 @@ func applyIncludeOption(query types.SelectQuery, path string, opt *types.Include
 	return query.IncludeWithOptions(path, opt)
 }
 
-// parseNestedIncludes parses nested include options and returns include options
-func parseNestedIncludes(relationName string, options map[string]any) map[string]*types.IncludeOption {
+// applyRelationLoadStrategy applies the relationLoadStrategy option ("join" or "query")
+func applyRelationLoadStrategy(query types.SelectQuery, strategy any) (types.SelectQuery, error) {
+	name, _ := strategy.(string)
+	switch types.RelationLoadStrategy(name) {
+	case types.RelationLoadJoin, types.RelationLoadQuery:
+		return query.RelationLoadStrategy(types.RelationLoadStrategy(name)), nil
+	default:
+		return nil, fmt.Errorf("invalid relationLoadStrategy %v: expected \"join\" or \"query\"", strategy)
+	}
+}
+
+// parseNestedIncludes parses nested include options and returns include options.
+// ancestors holds the option maps of the enclosing relations, so that options which
+// contain themselves are reported instead of being followed forever.
+func parseNestedIncludes(relationName string, options map[string]any, ancestors []uintptr) (map[string]*types.IncludeOption, error) {
+	pointer := reflect.ValueOf(options).Pointer()
+	if slices.Contains(ancestors, pointer) {
+		return nil, fmt.Errorf("include of %s refers back to itself", relationName)
+	}
+	ancestors = append(ancestors, pointer)
+
 	result := make(map[string]*types.IncludeOption)
 
 	// Create the include option for this relation
@@ -306,20 +399,18 @@ func parseNestedIncludes(relationName string, options map[string]any) map[string
 
 	// Check for orderBy
 	if orderBy, hasOrderBy := options["orderBy"]; hasOrderBy {
-		if orderMap, ok := orderBy.(map[string]any); ok {
-			var orders []types.OrderByOption
-			for field, direction := range orderMap {
-				dir := types.ASC
-				if dirStr, ok := direction.(string); ok && strings.ToLower(dirStr) == "desc" {
-					dir = types.DESC
-				}
-				orders = append(orders, types.OrderByOption{
-					Field:     field,
-					Direction: dir,
-				})
+		var orders []types.OrderByOption
+		for _, entry := range orderByEntries(orderBy) {
+			dir := types.ASC
+			if dirStr, ok := entry.Direction.(string); ok && strings.ToLower(dirStr) == "desc" {
+				dir = types.DESC
 			}
-			includeOpt.OrderBy = orders
+			orders = append(orders, types.OrderByOption{
+				Field:     entry.Field,
+				Direction: dir,
+			})
 		}
+		includeOpt.OrderBy = orders
 	}
 
 	// Check for limit
@@ -350,7 +441,10 @@ func parseNestedIncludes(relationName string, options map[string]any) map[string
 					}
 				case map[string]any:
 					// Recursively parse deeper nesting
-					deeperIncludes := parseNestedIncludes(fullPath, opts)
+					deeperIncludes, err := parseNestedIncludes(fullPath, opts, ancestors)
+					if err != nil {
+						return nil, err
+					}
 					for k, v := range deeperIncludes {
 						result[k] = v
 					}
@@ -363,5 +457,124 @@ func parseNestedIncludes(relationName string, options map[string]any) map[string
 	// The join builder will handle deduplication if needed
 	result[relationName] = includeOpt
 
-	return result
+	return result, nil
+}
+
+// updateOperators are the Prisma atomic update operators, e.g. {"views": {"increment": 1}}
+var updateOperators = map[string]bool{
+	"set":       true,
+	"increment": true,
+	"decrement": true,
+	"multiply":  true,
+	"push":      true,
+}
+
+// updateOperator returns the operator and operand of an update value written as
+// {"<operator>": operand}. JSON fields take objects as values, so they never use operators.
+func updateOperator(modelSchema *schema.Schema, fieldName string, value any) (string, any, bool) {
+	valueMap, ok := value.(map[string]any)
+	if !ok || len(valueMap) != 1 {
+		return "", nil, false
+	}
+	if modelSchema != nil {
+		if field, err := modelSchema.GetField(fieldName); err == nil && field.Type == schema.FieldTypeJSON {
+			return "", nil, false
+		}
+	}
+	for operator, operand := range valueMap {
+		if updateOperators[operator] {
+			return operator, operand, true
+		}
+	}
+	return "", nil, false
+}
+
+// hasUpdateOperators reports whether update data uses atomic update operators
+func hasUpdateOperators(modelSchema *schema.Schema, data any) bool {
+	dataMap, ok := data.(map[string]any)
+	if !ok {
+		return false
+	}
+	for field, value := range dataMap {
+		if _, _, ok := updateOperator(modelSchema, field, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// buildUpdateQuery creates an update query from update data, turning atomic update
+// operators into Increment, Decrement, Multiply and Push operations
+func buildUpdateQuery(model types.ModelQuery, modelName string, data any, db types.Database) (types.UpdateQuery, error) {
+	dataMap, ok := data.(map[string]any)
+	if !ok {
+		return model.Update(data), nil
+	}
+
+	type operation struct {
+		field    string
+		operator string
+		operand  any
+	}
+
+	modelSchema, _ := db.GetModelSchema(modelName)
+	setData := make(map[string]any, len(dataMap))
+	var operations []operation
+	for field, value := range dataMap {
+		operator, operand, ok := updateOperator(modelSchema, field, value)
+		switch {
+		case !ok:
+			setData[field] = value
+		case operator == "set":
+			setData[field] = operand
+		default:
+			operations = append(operations, operation{field, operator, operand})
+		}
+	}
+
+	updateQuery := model.Update(setData)
+	for _, op := range operations {
+		if op.operator == "push" {
+			if values, ok := op.operand.([]any); ok {
+				updateQuery = updateQuery.Push(op.field, values...)
+			} else {
+				updateQuery = updateQuery.Push(op.field, op.operand)
+			}
+			continue
+		}
+
+		number, err := updateOperand(modelSchema, op.field, op.operator, op.operand)
+		if err != nil {
+			return nil, err
+		}
+		switch op.operator {
+		case "increment":
+			updateQuery = updateQuery.Increment(op.field, number)
+		case "decrement":
+			updateQuery = updateQuery.Decrement(op.field, number)
+		case "multiply":
+			updateQuery = updateQuery.Multiply(op.field, number)
+		}
+	}
+	return updateQuery, nil
+}
+
+// updateOperand checks that the operand of an arithmetic operator is a number. JSON numbers
+// decode as float64, so whole numbers for integer fields become int64 to keep the column type.
+func updateOperand(modelSchema *schema.Schema, fieldName, operator string, operand any) (any, error) {
+	number, ok := operand.(float64)
+	if !ok {
+		switch operand.(type) {
+		case int, int32, int64, float32:
+			return operand, nil
+		}
+		return nil, fmt.Errorf("%s on field %s requires a number, got %T", operator, fieldName, operand)
+	}
+	if modelSchema != nil && number == math.Trunc(number) {
+		if field, err := modelSchema.GetField(fieldName); err == nil &&
+			(field.Type == schema.FieldTypeInt || field.Type == schema.FieldTypeInt64) {
+			return int64(number), nil
+		}
+	}
+	return number, nil
 }
@@ -4,16 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"reflect"
+	"maps"
+	"slices"
 	"strings"
 
+	"github.com/rediwo/redi-orm/database"
 	"github.com/rediwo/redi-orm/types"
 	"github.com/rediwo/redi-orm/utils"
 )
 
 // executeOperation executes a database operation based on the method name
-func executeOperation(db types.Database, modelName, methodName string, options map[string]any, typeConverter *TypeConverter) (any, error) {
-	ctx := context.Background()
+func executeOperation(ctx context.Context, db types.Database, modelName, methodName string, options map[string]any, typeConverter *TypeConverter) (any, error) {
 	model := db.Model(modelName)
 
 	switch methodName {
@@ -41,9 +42,9 @@ func executeOperation(db types.Database, modelName, methodName string, options m
 
 	// Update operations
 	case "update":
-		return executeUpdate(ctx, model, options)
+		return executeUpdate(ctx, model, modelName, options, db)
 	case "updateMany":
-		return executeUpdateMany(ctx, model, modelName, options)
+		return executeUpdateMany(ctx, model, modelName, options, db)
 	case "updateManyAndReturn":
 		return executeUpdateManyAndReturn(ctx, model, modelName, options)
 	case "upsert":
@@ -104,6 +105,10 @@ func executeCreate(ctx context.Context, model types.ModelQuery, options map[stri
 		if err != nil {
 			return nil, err
 		}
+		// The returned columns are named after their fields
+		if modelSchema, err := db.GetSchema(modelName); err == nil {
+			createdRecord, _ = modelSchema.MapColumnDataToSchema(createdRecord)
+		}
 	} else {
 		// Database doesn't support RETURNING, use the traditional method
 		result, err := query.Exec(ctx)
@@ -111,17 +116,18 @@ func executeCreate(ctx context.Context, model types.ModelQuery, options map[stri
 			return nil, err
 		}
 
-		// Fetch the created record using LastInsertID
+		// Fetch the created record by its inserted ID
 		selectQuery := model.Select()
-		if result.LastInsertID > 0 {
-			selectQuery = applySimpleWhereConditions(selectQuery, map[string]any{"id": result.LastInsertID}).(types.SelectQuery)
+		id, hasID := result.InsertedID()
+		if hasID {
+			selectQuery = applySimpleWhereConditions(selectQuery, map[string]any{"id": id}).(types.SelectQuery)
 		}
 
 		err = selectQuery.FindFirst(ctx, &createdRecord)
 		if err != nil {
 			// If we can't fetch the created record, return what we have
 			if dataMap, ok := processedData.(map[string]any); ok {
-				dataMap["id"] = result.LastInsertID
+				dataMap["id"] = id
 				return dataMap, nil
 			}
 			return processedData, nil
@@ -153,25 +159,61 @@ func executeCreateMany(ctx context.Context, model types.ModelQuery, modelName st
 		processedData = append(processedData, processNestedWrites(item, "create", modelName, db))
 	}
 
-	// Create records one by one (batch insert would be more efficient)
-	created := 0
-	for _, item := range processedData {
-		query := model.Insert(item)
-		_, err := query.Exec(ctx)
+	// Rows are inserted in multi-row statements; duplicates are skipped by the database
+	// rather than by failing statements one row at a time
+	created := int64(0)
+	for _, batch := range createManyBatches(processedData, db.GetCapabilities().MaxParameters()) {
+		query := model.Insert(batch[0])
+		if len(batch) > 1 {
+			query = query.Values(batch[1:]...)
+		}
+		if skipDuplicates {
+			query = query.OnConflict(types.ConflictDoNothing)
+		}
+		result, err := query.Exec(ctx)
 		if err != nil {
-			if skipDuplicates && isUniqueConstraintError(err) {
-				continue
-			}
 			return nil, err
 		}
-		created++
+		created += result.RowsAffected
 	}
 
 	return map[string]any{
-		"count": created,
+		"count": int(created),
 	}, nil
 }
 
+// createManyBatches splits createMany rows into insert batches. A batch holds consecutive
+// rows with the same fields, and no more rows than the parameter limit of the driver allows.
+func createManyBatches(items []any, maxParameters int) [][]any {
+	var batches [][]any
+	var batchKey string
+	batchSize := 0
+
+	for _, item := range items {
+		data, ok := item.(map[string]any)
+		if !ok || len(data) == 0 {
+			// Structs and rows of defaults only are inserted on their own
+			batches = append(batches, []any{item})
+			batchKey = ""
+			continue
+		}
+
+		fields := slices.Sorted(maps.Keys(data))
+		key := "\x00" + strings.Join(fields, "\x00")
+		if maxParameters > 0 {
+			batchSize = max(maxParameters/len(fields), 1)
+		}
+		last := len(batches) - 1
+		if key != batchKey || (maxParameters > 0 && len(batches[last]) >= batchSize) {
+			batches = append(batches, []any{item})
+			batchKey = key
+			continue
+		}
+		batches[last] = append(batches[last], item)
+	}
+	return batches
+}
+
 func executeCreateManyAndReturn(ctx context.Context, model types.ModelQuery, modelName string, options map[string]any, db types.Database) (any, error) {
 	// Similar to createMany but returns created records
 	// This is a simplified implementation
@@ -196,7 +238,7 @@ func executeCreateManyAndReturn(ctx context.Context, model types.ModelQuery, mod
 
 		// Add ID to the created item
 		if itemMap, ok := processedItem.(map[string]any); ok {
-			itemMap["id"] = result.LastInsertID
+			itemMap["id"], _ = result.InsertedID()
 			created = append(created, itemMap)
 		}
 	}
@@ -207,70 +249,63 @@ func executeCreateManyAndReturn(ctx context.Context, model types.ModelQuery, mod
 // Read operations
 
 func executeFindUnique(ctx context.Context, model types.ModelQuery, options map[string]any) (any, error) {
-	where, ok := options["where"]
-	if !ok {
+	if _, ok := options["where"]; !ok {
 		return nil, fmt.Errorf("findUnique requires 'where' field")
 	}
 
-	query := model.Select()
-
-	// Apply where conditions
-	query = applySimpleWhereConditions(query, where).(types.SelectQuery)
-
-	// Handle select fields
-	if selectFields, ok := options["select"]; ok {
-		fields := extractFieldNames(selectFields)
-		query = model.Select(fields...)
+	query, err := buildFindQuery(model, options)
+	if err != nil {
+		return nil, err
 	}
 
-	// Handle include (relations)
-	if include, ok := options["include"]; ok {
-		query = applyInclude(query, include).(types.SelectQuery)
+	find := func() (any, error) {
+		result := make(map[string]any)
+		if err := query.FindFirst(ctx, &result); err != nil {
+			return nil, err
+		}
+		return result, nil
 	}
 
-	result := make(map[string]any)
-	err := query.FindFirst(ctx, &result)
+	// Identical lookups run once in a context of types.WithDeduplication
+	key, err := json.Marshal(options)
 	if err != nil {
-		return nil, err
+		return find()
 	}
-
-	return result, nil
+	return types.Deduplicate(ctx, "findUnique "+model.GetModelName()+" "+string(key), find)
 }
 
 func executeFindFirst(ctx context.Context, model types.ModelQuery, options map[string]any) (any, error) {
-	query := model.Select()
-
-	// Apply where conditions
-	if where, ok := options["where"]; ok {
-		query = applySimpleWhereConditions(query, where).(types.SelectQuery)
+	query, err := buildFindQuery(model, options)
+	if err != nil {
+		return nil, err
 	}
 
-	// Apply orderBy if provided
-	if orderBy, ok := options["orderBy"]; ok {
-		query = applyOrderBy(query, orderBy).(types.SelectQuery)
+	result := make(map[string]any)
+	if err := query.FindFirst(ctx, &result); err != nil {
+		return nil, err
 	}
 
-	// Handle select fields
-	if selectFields, ok := options["select"]; ok {
-		fields := extractFieldNames(selectFields)
-		query = model.Select(fields...)
-	}
+	return result, nil
+}
 
-	// Handle include (relations)
-	if include, ok := options["include"]; ok {
-		query = applyInclude(query, include).(types.SelectQuery)
+func executeFindMany(ctx context.Context, model types.ModelQuery, options map[string]any) (any, error) {
+	query, err := buildFindQuery(model, options)
+	if err != nil {
+		return nil, err
 	}
 
-	result := make(map[string]any)
-	err := query.FindFirst(ctx, &result)
-	if err != nil {
+	results := []map[string]any{}
+	if err := query.FindMany(ctx, &results); err != nil {
 		return nil, err
 	}
 
-	return result, nil
+	return results, nil
 }
 
-func executeFindMany(ctx context.Context, model types.ModelQuery, options map[string]any) (any, error) {
+// buildFindQuery builds the select query of findUnique, findFirst and findMany. The
+// selected fields, where conditions, ordering, pagination, includes and distinct of the
+// options are all applied to the one query, created with the selected fields first.
+func buildFindQuery(model types.ModelQuery, options map[string]any) (types.SelectQuery, error) {
 	// First determine which fields to select
 	var selectedFields []string
 	var includesFromSelect map[string]any
@@ -290,12 +325,7 @@ func executeFindMany(ctx context.Context, model types.ModelQuery, options map[st
 	}
 
 	// Create query with selected fields (or all fields if none specified)
-	var query types.SelectQuery
-	if len(selectedFields) > 0 {
-		query = model.Select(selectedFields...)
-	} else {
-		query = model.Select()
-	}
+	query := model.Select(selectedFields...)
 
 	// Apply where conditions
 	if where, ok := options["where"]; ok {
@@ -317,12 +347,26 @@ func executeFindMany(ctx context.Context, model types.ModelQuery, options map[st
 
 	// Handle include (relations)
 	if include, ok := options["include"]; ok {
-		query = applyInclude(query, include).(types.SelectQuery)
+		included, err := applyInclude(query, include)
+		if err != nil {
+			return nil, err
+		}
+		query = included.(types.SelectQuery)
 	}
 
 	// Apply includes from select if any
-	if includesFromSelect != nil && len(includesFromSelect) > 0 {
-		query = applyInclude(query, includesFromSelect).(types.SelectQuery)
+	if len(includesFromSelect) > 0 {
+		included, err := applyInclude(query, includesFromSelect)
+		if err != nil {
+			return nil, err
+		}
+		query = included.(types.SelectQuery)
+	}
+	if strategy, ok := options["relationLoadStrategy"]; ok {
+		var err error
+		if query, err = applyRelationLoadStrategy(query, strategy); err != nil {
+			return nil, err
+		}
 	}
 
 	// Handle distinct
@@ -352,13 +396,7 @@ func executeFindMany(ctx context.Context, model types.ModelQuery, options map[st
 		}
 	}
 
-	results := []map[string]any{}
-	err := query.FindMany(ctx, &results)
-	if err != nil {
-		return nil, err
-	}
-
-	return results, nil
+	return query, nil
 }
 
 func executeCount(ctx context.Context, model types.ModelQuery, options map[string]any) (any, error) {
@@ -410,100 +448,129 @@ func executeAggregate(ctx context.Context, model types.ModelQuery, options map[s
 
 	result := make(map[string]any)
 
-	// Handle different aggregation types
+	// Collect every requested aggregate into a single query
+	query := model.Aggregate()
+	var outputs []aggregateOutput
+	addOutput := func(group, field string) string {
+		alias := fmt.Sprintf("agg_%d", len(outputs))
+		outputs = append(outputs, aggregateOutput{group: group, field: field, alias: alias})
+		return alias
+	}
+
 	if count, ok := options["_count"]; ok {
 		switch c := count.(type) {
 		case bool:
 			if c {
-				// Simple count
-				cnt, err := model.Count(ctx)
-				if err != nil {
-					return nil, err
-				}
-				result["_count"] = cnt
+				query = query.CountAll(addOutput("_count", ""))
 			}
 		case map[string]any:
-			// Field-specific count
-			for field := range c {
-				// For simplicity, just count all records
-				cnt, err := model.Count(ctx)
-				if err != nil {
-					return nil, err
+			result["_count"] = make(map[string]any)
+			for _, count := range parseCountFields(c) {
+				alias := addOutput("_count", count.field)
+				switch {
+				case count.field == "_all":
+					query = query.CountAll(alias)
+				case count.distinct:
+					query = query.CountDistinct(count.field, alias)
+				default:
+					query = query.Count(count.field, alias)
 				}
-				if _, ok := result["_count"]; !ok {
-					result["_count"] = make(map[string]any)
-				}
-				result["_count"].(map[string]any)[field] = cnt
 			}
 		}
 	}
 
-	if avg, ok := options["_avg"]; ok {
-		if avgMap, ok := avg.(map[string]any); ok {
-			result["_avg"] = make(map[string]any)
-			for field, val := range avgMap {
-				if enabled, ok := val.(bool); ok && enabled {
-					a, err := model.Avg(ctx, field)
-					if err != nil {
-						return nil, err
-					}
-					result["_avg"].(map[string]any)[field] = a
-				}
+	aggregates := []struct {
+		group string
+		add   func(types.AggregationQuery, string, string) types.AggregationQuery
+	}{
+		{"_avg", types.AggregationQuery.Avg},
+		{"_sum", types.AggregationQuery.Sum},
+		{"_min", types.AggregationQuery.Min},
+		{"_max", types.AggregationQuery.Max},
+	}
+	for _, aggregate := range aggregates {
+		fieldMap, ok := options[aggregate.group].(map[string]any)
+		if !ok {
+			continue
+		}
+		result[aggregate.group] = make(map[string]any)
+		for field, val := range fieldMap {
+			if enabled, ok := val.(bool); ok && enabled {
+				query = aggregate.add(query, field, addOutput(aggregate.group, field))
 			}
 		}
 	}
 
-	if sum, ok := options["_sum"]; ok {
-		if sumMap, ok := sum.(map[string]any); ok {
-			result["_sum"] = make(map[string]any)
-			for field, val := range sumMap {
-				if enabled, ok := val.(bool); ok && enabled {
-					s, err := model.Sum(ctx, field)
-					if err != nil {
-						return nil, err
-					}
-					result["_sum"].(map[string]any)[field] = s
-				}
-			}
-		}
+	if len(outputs) == 0 {
+		return result, nil
 	}
 
-	if min, ok := options["_min"]; ok {
-		if minMap, ok := min.(map[string]any); ok {
-			result["_min"] = make(map[string]any)
-			for field, val := range minMap {
-				if enabled, ok := val.(bool); ok && enabled {
-					m, err := model.Min(ctx, field)
-					if err != nil {
-						return nil, err
-					}
-					result["_min"].(map[string]any)[field] = m
-				}
-			}
+	var rows []map[string]any
+	if err := query.Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	// An aggregate over no documents may return no row (MongoDB)
+	row := map[string]any{}
+	if len(rows) > 0 {
+		row = rows[0]
+	}
+
+	for _, output := range outputs {
+		value := row[output.alias]
+		switch output.group {
+		case "_count":
+			value = utils.ToInt64(value)
+		case "_avg", "_sum":
+			// Sums and averages of no rows are reported as 0
+			value = utils.ToFloat64(value)
+		}
+
+		if output.field == "" {
+			result[output.group] = value
+		} else {
+			result[output.group].(map[string]any)[output.field] = value
 		}
 	}
 
-	if max, ok := options["_max"]; ok {
-		if maxMap, ok := max.(map[string]any); ok {
-			result["_max"] = make(map[string]any)
-			for field, val := range maxMap {
-				if enabled, ok := val.(bool); ok && enabled {
-					m, err := model.Max(ctx, field)
-					if err != nil {
-						return nil, err
-					}
-					result["_max"].(map[string]any)[field] = m
-				}
+	return result, nil
+}
+
+// countField is a field of a _count map: {field: true} counts non-null values,
+// {field: {distinct: true}} counts distinct non-null values and _all counts every row
+type countField struct {
+	field    string
+	distinct bool
+}
+
+// parseCountFields returns the enabled fields of a _count map
+func parseCountFields(count map[string]any) []countField {
+	var fields []countField
+	for field, value := range count {
+		switch v := value.(type) {
+		case bool:
+			if v {
+				fields = append(fields, countField{field: field})
 			}
+		case map[string]any:
+			distinct, _ := v["distinct"].(bool)
+			fields = append(fields, countField{field: field, distinct: distinct && field != "_all"})
 		}
 	}
+	return fields
+}
 
-	return result, nil
+// aggregateOutput maps an aggregate column of the combined aggregate() query back to its
+// place in the result
+type aggregateOutput struct {
+	group string // _count, _avg, _sum, _min or _max
+	field string // empty for _count: true
+	alias string
 }
 
 // Update operations
 
-func executeUpdate(ctx context.Context, model types.ModelQuery, options map[string]any) (any, error) {
+func executeUpdate(ctx context.Context, model types.ModelQuery, modelName string, options map[string]any, db types.Database) (any, error) {
 	where, ok := options["where"]
 	if !ok {
 		return nil, fmt.Errorf("update requires 'where' field")
@@ -525,7 +592,10 @@ func executeUpdate(ctx context.Context, model types.ModelQuery, options map[stri
 	}
 
 	// Now update it
-	updateQuery := model.Update(data)
+	updateQuery, err := buildUpdateQuery(model, modelName, data, db)
+	if err != nil {
+		return nil, err
+	}
 	updateQuery = applySimpleWhereConditions(updateQuery, where).(types.UpdateQuery)
 
 	_, err = updateQuery.Exec(ctx)
@@ -550,14 +620,16 @@ func executeUpdate(ctx context.Context, model types.ModelQuery, options map[stri
 	return updated, nil
 }
 
-func executeUpdateMany(ctx context.Context, model types.ModelQuery, modelName string, options map[string]any) (any, error) {
-	_ = modelName // TODO: might be needed for future enhancements
+func executeUpdateMany(ctx context.Context, model types.ModelQuery, modelName string, options map[string]any, db types.Database) (any, error) {
 	data, ok := options["data"]
 	if !ok {
 		return nil, fmt.Errorf("updateMany requires 'data' field")
 	}
 
-	updateQuery := model.Update(data)
+	updateQuery, err := buildUpdateQuery(model, modelName, data, db)
+	if err != nil {
+		return nil, err
+	}
 
 	// Apply where conditions
 	if where, ok := options["where"]; ok {
@@ -592,6 +664,15 @@ func executeUpsert(ctx context.Context, model types.ModelQuery, options map[stri
 		return nil, fmt.Errorf("upsert requires both 'create' and 'update' fields")
 	}
 
+	// Upserts on a unique key run as one native statement, so concurrent upserts cannot
+	// both insert. Update operators are applied by the update query instead.
+	modelSchema, _ := db.GetModelSchema(modelName)
+	if createMap, ok := createData.(map[string]any); ok && !hasUpdateOperators(modelSchema, updateData) {
+		if conflictFields := upsertConflictFields(where, modelName, db); conflictFields != nil {
+			return executeNativeUpsert(ctx, model, where, createMap, updateData, conflictFields)
+		}
+	}
+
 	// First, try to find the existing record
 	selectQuery := model.Select()
 	selectQuery = applySimpleWhereConditions(selectQuery, where).(types.SelectQuery)
@@ -608,13 +689,16 @@ func executeUpsert(ctx context.Context, model types.ModelQuery, options map[stri
 		}
 		// Add ID to created data
 		if createMap, ok := createData.(map[string]any); ok {
-			createMap["id"] = result.LastInsertID
+			createMap["id"], _ = result.InsertedID()
 			return createMap, nil
 		}
 		return createData, nil
 	} else {
 		// Record exists, update it
-		updateQuery := model.Update(updateData)
+		updateQuery, err := buildUpdateQuery(model, modelName, updateData, db)
+		if err != nil {
+			return nil, err
+		}
 		updateQuery = applySimpleWhereConditions(updateQuery, where).(types.UpdateQuery)
 
 		_, err = updateQuery.Exec(ctx)
@@ -639,6 +723,54 @@ func executeUpsert(ctx context.Context, model types.ModelQuery, options map[stri
 	}
 }
 
+// upsertConflictFields returns the fields of a where clause when the driver supports
+// native upserts and the clause matches a unique key by equality, nil otherwise
+func upsertConflictFields(where any, modelName string, db types.Database) []string {
+	whereMap, ok := where.(map[string]any)
+	if !ok || len(whereMap) == 0 || !db.GetCapabilities().SupportsUpsert() {
+		return nil
+	}
+
+	fields := make([]string, 0, len(whereMap))
+	for field, value := range whereMap {
+		switch value.(type) {
+		case nil, map[string]any, []any:
+			return nil
+		}
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+
+	modelSchema, err := db.GetModelSchema(modelName)
+	if err != nil || !modelSchema.IsUniqueKey(fields) {
+		return nil
+	}
+	return fields
+}
+
+// executeNativeUpsert runs an upsert as a single statement and returns the resulting record
+func executeNativeUpsert(ctx context.Context, model types.ModelQuery, where any, createData map[string]any, updateData any, conflictFields []string) (any, error) {
+	// The unique values of the where clause belong to the created record
+	whereMap := where.(map[string]any)
+	data := maps.Clone(createData)
+	for _, field := range conflictFields {
+		if _, ok := data[field]; !ok {
+			data[field] = whereMap[field]
+		}
+	}
+
+	if _, err := model.Upsert(data, updateData, conflictFields...).Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	var record map[string]any
+	selectQuery := applySimpleWhereConditions(model.Select(), where).(types.SelectQuery)
+	if err := selectQuery.FindFirst(ctx, &record); err != nil {
+		return nil, fmt.Errorf("failed to fetch upserted record: %w", err)
+	}
+	return record, nil
+}
+
 // Delete operations
 
 func executeDelete(ctx context.Context, model types.ModelQuery, options map[string]any) (any, error) {
@@ -752,18 +884,10 @@ func extractFieldNames(selectFields any) []string {
 }
 
 // Check if error is a unique constraint violation
-func isUniqueConstraintError(err error) bool {
-	errStr := err.Error()
-	return reflect.ValueOf(errStr).String() == "UNIQUE constraint failed" ||
-		reflect.ValueOf(errStr).String() == "duplicate key"
-}
-
 // executeGroupBy handles groupBy queries
 func executeGroupBy(ctx context.Context, model types.ModelQuery, modelName string, options map[string]any, db types.Database) (any, error) {
-	// Check if database supports raw SQL queries
-	// For NoSQL databases like MongoDB, use the query builder instead of generating SQL
-	capabilities := db.GetCapabilities()
-	if capabilities.IsNoSQL() {
+	// Databases with an aggregation pipeline (MongoDB) group through it instead of generated SQL
+	if db.GetCapabilities().SupportsAggregationPipeline() {
 		return executeAggregationQuery(ctx, model, modelName, options, db)
 	}
 
@@ -786,45 +910,72 @@ func executeGroupBy(ctx context.Context, model types.ModelQuery, modelName strin
 		return nil, fmt.Errorf("groupBy requires 'by' field")
 	}
 
+	// Fields and aliases are resolved against the schema and quoted, and values are bound as
+	// arguments, so that nothing of the options is written into the SQL as given
+	quote := db.GetCapabilities().QuoteIdentifier
+	column := func(field string) (string, error) {
+		columnName, err := db.ResolveFieldName(modelName, field)
+		if err != nil {
+			return "", fmt.Errorf("unknown field %s of model %s: %w", field, modelName, err)
+		}
+		return quote(columnName), nil
+	}
+
 	// Build SELECT clause
 	var selectParts []string
 
 	// Add grouped fields
+	var groupByColumns []string
 	for _, field := range groupByFields {
-		// Resolve field name to column name
-		columnName, err := db.ResolveFieldName(modelName, field)
+		columnName, err := column(field)
 		if err != nil {
-			// Fall back to field name if not found
-			columnName = field
+			return nil, err
 		}
 		// Use column AS field to maintain the original field name in results
-		// Quote the alias to preserve case in PostgreSQL
-		selectParts = append(selectParts, fmt.Sprintf("%s AS \"%s\"", columnName, field))
+		selectParts = append(selectParts, fmt.Sprintf("%s AS %s", columnName, quote(field)))
+		groupByColumns = append(groupByColumns, columnName)
 	}
 
-	// Handle _count, _sum, _avg, _min, _max aggregations
-	aggregations := []string{"_count", "_sum", "_avg", "_min", "_max"}
+	// Handle _count aggregations
+	switch c := options["_count"].(type) {
+	case bool:
+		if c {
+			// Simple count(*)
+			selectParts = append(selectParts, "COUNT(*) AS "+quote("_count"))
+		}
+	case map[string]any:
+		for _, count := range parseCountFields(c) {
+			expr := "*"
+			if count.field != "_all" {
+				columnName, err := column(count.field)
+				if err != nil {
+					return nil, err
+				}
+				expr = columnName
+				if count.distinct {
+					expr = "DISTINCT " + columnName
+				}
+			}
+			selectParts = append(selectParts, fmt.Sprintf("COUNT(%s) AS %s", expr, quote(count.field+"_count")))
+		}
+	}
+
+	// Handle _sum, _avg, _min, _max aggregations
+	aggregations := []string{"_sum", "_avg", "_min", "_max"}
 	for _, agg := range aggregations {
 		if aggValue, ok := options[agg]; ok {
 			// Parse aggregation options
 			switch av := aggValue.(type) {
-			case bool:
-				if av && agg == "_count" {
-					// Simple count(*)
-					selectParts = append(selectParts, "COUNT(*) as _count")
-				}
 			case map[string]any:
 				// Field-specific aggregations
 				for field, enabled := range av {
 					if e, ok := enabled.(bool); ok && e {
 						aggFunc := strings.ToUpper(strings.TrimPrefix(agg, "_"))
-						// Resolve field name to column name
-						columnName, err := db.ResolveFieldName(modelName, field)
+						columnName, err := column(field)
 						if err != nil {
-							// Fall back to field name
-							columnName = field
+							return nil, err
 						}
-						selectParts = append(selectParts, fmt.Sprintf("%s(%s) as %s%s", aggFunc, columnName, field, agg))
+						selectParts = append(selectParts, fmt.Sprintf("%s(%s) AS %s", aggFunc, columnName, quote(field+agg)))
 					}
 				}
 			}
@@ -837,42 +988,54 @@ func executeGroupBy(ctx context.Context, model types.ModelQuery, modelName strin
 		return nil, err
 	}
 
-	sql := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selectParts, ", "), tableName)
+	sql := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selectParts, ", "), quote(tableName))
+	var args []any
 
-	// Add WHERE clause if provided
+	// Add WHERE clause if provided, built like the where of other queries
 	if where, ok := options["where"]; ok {
-		// Build simple WHERE conditions for groupBy
-		whereSQL := buildSimpleWhereSQL(where, modelName, db)
-		if whereSQL != "" {
-			sql += " WHERE " + whereSQL
+		if condition := BuildCondition(where); condition != nil {
+			whereSQL, whereArgs := groupByConditionSQL(model, modelName, db, condition)
+			if whereSQL != "" {
+				sql += " WHERE " + whereSQL
+				args = append(args, whereArgs...)
+			}
 		}
 	}
 
 	// Add GROUP BY clause
-	if len(groupByFields) > 0 {
-		var groupByColumns []string
-		for _, field := range groupByFields {
-			columnName, err := db.ResolveFieldName(modelName, field)
-			if err != nil {
-				columnName = field
-			}
-			groupByColumns = append(groupByColumns, columnName)
-		}
-		sql += fmt.Sprintf(" GROUP BY %s", strings.Join(groupByColumns, ", "))
-	}
+	sql += fmt.Sprintf(" GROUP BY %s", strings.Join(groupByColumns, ", "))
 
 	// Add HAVING clause if provided
-	if having, ok := options["having"]; ok {
-		// Build simple HAVING conditions
-		havingSQL := buildSimpleHavingSQL(having)
-		if havingSQL != "" {
-			sql += " HAVING " + havingSQL
+	if having, ok := options["having"].(map[string]any); ok {
+		condition, err := buildAggregationHavingCondition(having)
+		if err != nil {
+			return nil, err
+		}
+		for _, fields := range having {
+			for field := range fields.(map[string]any) {
+				if field == "_all" {
+					continue
+				}
+				if _, err := column(field); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if condition != nil {
+			havingSQL, havingArgs := groupByConditionSQL(model, modelName, db, condition)
+			if havingSQL != "" {
+				sql += " HAVING " + havingSQL
+				args = append(args, havingArgs...)
+			}
 		}
 	}
 
 	// Add ORDER BY if provided
 	if orderBy, ok := options["orderBy"]; ok {
-		orderSQL := buildOrderBySQL(orderBy, modelName, db)
+		orderSQL, err := buildOrderBySQL(orderBy, modelName, db)
+		if err != nil {
+			return nil, err
+		}
 		if orderSQL != "" {
 			sql += " ORDER BY " + orderSQL
 		}
@@ -880,14 +1043,16 @@ func executeGroupBy(ctx context.Context, model types.ModelQuery, modelName strin
 
 	// Apply pagination
 	if take, ok := options["take"]; ok {
-		sql += fmt.Sprintf(" LIMIT %d", int(utils.ToInt64(take)))
+		sql += " LIMIT ?"
+		args = append(args, utils.ToInt64(take))
 	}
 	if skip, ok := options["skip"]; ok {
-		sql += fmt.Sprintf(" OFFSET %d", int(utils.ToInt64(skip)))
+		sql += " OFFSET ?"
+		args = append(args, utils.ToInt64(skip))
 	}
 
-	// Execute raw query for SQL databases
-	rows, err := db.Query(sql)
+	// Run the generated SQL with every value of the options bound as an argument
+	rows, err := database.QueryWithArgs(ctx, db, sql, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -915,12 +1080,12 @@ func executeGroupBy(ctx context.Context, model types.ModelQuery, modelName strin
 		}
 
 		// Transform field_agg to nested format
-		aggregations := []string{"_sum", "_avg", "_min", "_max"}
+		aggregations := []string{"_count", "_sum", "_avg", "_min", "_max"}
 		for _, agg := range aggregations {
 			aggMap := make(map[string]any)
 			for k, v := range result {
 				// Check if this is a field_agg pattern
-				if strings.HasSuffix(k, agg) && strings.Contains(k, "_") {
+				if k != agg && strings.HasSuffix(k, agg) {
 					// Remove the _agg suffix to get the field name
 					fieldName := strings.TrimSuffix(k, agg)
 					// Remove the trailing underscore
@@ -944,160 +1109,64 @@ func executeGroupBy(ctx context.Context, model types.ModelQuery, modelName strin
 	return results, nil
 }
 
-// buildOrderBySQL builds ORDER BY SQL from orderBy options
-func buildOrderBySQL(orderBy any, modelName string, db types.Database) string {
+// buildOrderBySQL builds ORDER BY SQL from orderBy options. Fields are resolved against the
+// schema and quoted, and aggregations refer to the quoted aliases of the SELECT.
+func buildOrderBySQL(orderBy any, modelName string, db types.Database) (string, error) {
+	quote := db.GetCapabilities().QuoteIdentifier
 	var orderParts []string
 
-	switch ob := orderBy.(type) {
-	case map[string]any:
-		// Single orderBy object: {field: "asc"|"desc"} or {_sum: {field: "asc"}}
-		for field, direction := range ob {
-			// Check if it's an aggregation orderBy
-			if strings.HasPrefix(field, "_") {
-				// Handle aggregation ordering like _sum, _avg, etc.
-				if dirMap, ok := direction.(map[string]any); ok {
-					for aggField, dir := range dirMap {
-						direction := "ASC"
-						if dirStr, ok := dir.(string); ok && strings.ToLower(dirStr) == "desc" {
-							direction = "DESC"
-						}
-						// Use the aliased column name from SELECT
-						orderParts = append(orderParts, fmt.Sprintf("%s%s %s", aggField, field, direction))
+	// A single object, {field: "asc"|"desc"} or {_sum: {field: "asc"}}, or an array of them
+	for _, entry := range orderByEntries(orderBy) {
+		field := entry.Field
+		// Check if it's an aggregation orderBy
+		if strings.HasPrefix(field, "_") {
+			switch field {
+			case "_count", "_sum", "_avg", "_min", "_max":
+			default:
+				return "", fmt.Errorf("unsupported orderBy aggregation %s", field)
+			}
+			// Handle aggregation ordering like _sum, _avg, etc.
+			for _, agg := range orderByEntries(entry.Direction) {
+				if agg.Field != "_all" {
+					if _, err := db.ResolveFieldName(modelName, agg.Field); err != nil {
+						return "", fmt.Errorf("unknown field %s of model %s: %w", agg.Field, modelName, err)
 					}
 				}
-			} else {
-				// Regular field ordering
-				columnName, err := db.ResolveFieldName(modelName, field)
-				if err != nil {
-					columnName = field
-				}
-				dir := "ASC"
-				if dirStr, ok := direction.(string); ok && strings.ToLower(dirStr) == "desc" {
-					dir = "DESC"
+				direction := "ASC"
+				if dirStr, ok := agg.Direction.(string); ok && strings.ToLower(dirStr) == "desc" {
+					direction = "DESC"
 				}
-				orderParts = append(orderParts, fmt.Sprintf("%s %s", columnName, dir))
+				// Use the aliased column name from SELECT
+				orderParts = append(orderParts, fmt.Sprintf("%s %s", quote(agg.Field+field), direction))
 			}
-		}
-	case []any:
-		// Array of orderBy objects: [{field: "asc"}, {field2: "desc"}]
-		for _, item := range ob {
-			if orderMap, ok := item.(map[string]any); ok {
-				for field, direction := range orderMap {
-					columnName, err := db.ResolveFieldName(modelName, field)
-					if err != nil {
-						columnName = field
-					}
-					dir := "ASC"
-					if dirStr, ok := direction.(string); ok && strings.ToLower(dirStr) == "desc" {
-						dir = "DESC"
-					}
-					orderParts = append(orderParts, fmt.Sprintf("%s %s", columnName, dir))
-				}
+		} else {
+			// Regular field ordering
+			columnName, err := db.ResolveFieldName(modelName, field)
+			if err != nil {
+				return "", fmt.Errorf("unknown field %s of model %s: %w", field, modelName, err)
 			}
+			dir := "ASC"
+			if dirStr, ok := entry.Direction.(string); ok && strings.ToLower(dirStr) == "desc" {
+				dir = "DESC"
+			}
+			orderParts = append(orderParts, fmt.Sprintf("%s %s", quote(columnName), dir))
 		}
 	}
 
-	return strings.Join(orderParts, ", ")
-}
-
-// buildSimpleWhereSQL builds WHERE SQL from simple where conditions (for raw SQL queries)
-func buildSimpleWhereSQL(where any, modelName string, db types.Database) string {
-	whereMap, ok := where.(map[string]any)
-	if !ok {
-		return ""
-	}
-
-	var whereParts []string
-
-	for field, value := range whereMap {
-		// Skip complex operators for now
-		if _, isMap := value.(map[string]any); isMap {
-			continue
-		}
-
-		// Resolve field name to column name
-		columnName, err := db.ResolveFieldName(modelName, field)
-		if err != nil {
-			columnName = field
-		}
-
-		// Format value based on type
-		var valueStr string
-		switch v := value.(type) {
-		case string:
-			// Escape single quotes in string values
-			valueStr = fmt.Sprintf("'%s'", strings.ReplaceAll(v, "'", "''"))
-		case nil:
-			valueStr = "NULL"
-		default:
-			valueStr = fmt.Sprintf("%v", v)
-		}
-
-		whereParts = append(whereParts, fmt.Sprintf("%s = %s", columnName, valueStr))
-	}
-
-	return strings.Join(whereParts, " AND ")
+	return strings.Join(orderParts, ", "), nil
 }
 
-// buildSimpleHavingSQL builds HAVING SQL from having conditions (for raw SQL queries)
-func buildSimpleHavingSQL(having any) string {
-	havingMap, ok := having.(map[string]any)
-	if !ok {
-		return ""
-	}
-
-	var havingParts []string
-
-	// Handle aggregation conditions like _sum, _avg, etc.
-	for aggType, conditions := range havingMap {
-		if !strings.HasPrefix(aggType, "_") {
-			continue
-		}
-
-		// Get the aggregation function name
-		aggFunc := strings.ToUpper(strings.TrimPrefix(aggType, "_"))
-
-		if condMap, ok := conditions.(map[string]any); ok {
-			for field, operators := range condMap {
-				// Build the aggregation expression
-				var aggExpr string
-				if field == "_all" && aggType == "_count" {
-					// Special case for COUNT(*)
-					aggExpr = "COUNT(*)"
-				} else {
-					aggExpr = fmt.Sprintf("%s(%s)", aggFunc, field)
-				}
-
-				if opMap, ok := operators.(map[string]any); ok {
-					for op, value := range opMap {
-						// Handle different operators
-						var condition string
-						switch op {
-						case "gte":
-							condition = fmt.Sprintf("%s >= %v", aggExpr, value)
-						case "gt":
-							condition = fmt.Sprintf("%s > %v", aggExpr, value)
-						case "lte":
-							condition = fmt.Sprintf("%s <= %v", aggExpr, value)
-						case "lt":
-							condition = fmt.Sprintf("%s < %v", aggExpr, value)
-						case "equals":
-							condition = fmt.Sprintf("%s = %v", aggExpr, value)
-						default:
-							// Default to equals
-							condition = fmt.Sprintf("%s = %v", aggExpr, value)
-						}
-
-						if condition != "" {
-							havingParts = append(havingParts, condition)
-						}
-					}
-				}
-			}
-		}
-	}
-
-	return strings.Join(havingParts, " AND ")
+// groupByConditionSQL compiles a condition on the model of a groupBy to SQL and its
+// arguments, mapping its fields to columns
+func groupByConditionSQL(model types.ModelQuery, modelName string, db types.Database, condition types.Condition) (string, []any) {
+	var fieldMapper types.FieldMapper
+	if mapped, ok := model.(interface{ GetFieldMapper() types.FieldMapper }); ok {
+		fieldMapper = mapped.GetFieldMapper()
+	}
+	ctx := types.NewConditionContext(fieldMapper, modelName, "")
+	ctx.QuoteIdentifier = db.GetCapabilities().QuoteIdentifier
+	ctx.Capabilities = db.GetCapabilities()
+	return condition.ToSQL(ctx)
 }
 
 // executeAggregationQuery executes groupBy using the query builder for NoSQL databases
@@ -1146,45 +1215,52 @@ func applyAggregationWhereConditions(query types.AggregationQuery, where any) ty
 	return query
 }
 
-// buildAggregationHavingCondition builds having condition for aggregation
-func buildAggregationHavingCondition(having map[string]any) types.Condition {
-	// This is a simplified implementation
-	// Full implementation would need to handle aggregation function references
-	// For now, return nil to skip having conditions in NoSQL
-	return nil
+// buildAggregationHavingCondition builds the condition of a groupBy having, as in
+// { _sum: { amount: { gte: 500 } } } or { _count: { _all: { gt: 1 } } }. A value in place of
+// the operators is compared for equality.
+func buildAggregationHavingCondition(having map[string]any) (types.Condition, error) {
+	var conditions []types.Condition
+	for aggType, fields := range having {
+		if !strings.HasPrefix(aggType, "_") {
+			return nil, fmt.Errorf("having on %s requires an aggregation such as _sum or _count", aggType)
+		}
+		fieldMap, ok := fields.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("having %s requires an object of fields", aggType)
+		}
+		for field, operators := range fieldMap {
+			opMap, ok := operators.(map[string]any)
+			if !ok {
+				opMap = map[string]any{"equals": operators}
+			}
+			for op, value := range opMap {
+				switch op {
+				case "equals", "not", "gt", "gte", "lt", "lte":
+				default:
+					return nil, fmt.Errorf("unsupported having operator %s on %s of %s", op, aggType, field)
+				}
+				conditions = append(conditions, types.NewAggregationCondition(strings.TrimPrefix(aggType, "_"), field, op, value))
+			}
+		}
+	}
+
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+	if len(conditions) == 1 {
+		return conditions[0], nil
+	}
+	return types.NewAndCondition(conditions...), nil
 }
 
 // applyAggregationOrderBy applies order by to aggregation query
 func applyAggregationOrderBy(query types.AggregationQuery, orderBy any) types.AggregationQuery {
-	switch ob := orderBy.(type) {
-	case map[string]any:
-		for field, direction := range ob {
-			dir := types.ASC
-			if dirStr, ok := direction.(string); ok && strings.ToLower(dirStr) == "desc" {
-				dir = types.DESC
-			}
-
-			// Check if it's an aggregation field ordering
-			if strings.HasPrefix(field, "_") {
-				// This would be aggregation ordering - simplified for now
-				query = query.OrderBy(field, dir)
-			} else {
-				// Regular field ordering
-				query = query.OrderBy(field, dir)
-			}
-		}
-	case []any:
-		for _, item := range ob {
-			if orderMap, ok := item.(map[string]any); ok {
-				for field, direction := range orderMap {
-					dir := types.ASC
-					if dirStr, ok := direction.(string); ok && strings.ToLower(dirStr) == "desc" {
-						dir = types.DESC
-					}
-					query = query.OrderBy(field, dir)
-				}
-			}
+	for _, entry := range orderByEntries(orderBy) {
+		dir := types.ASC
+		if dirStr, ok := entry.Direction.(string); ok && strings.ToLower(dirStr) == "desc" {
+			dir = types.DESC
 		}
+		query = query.OrderBy(entry.Field, dir)
 	}
 	return query
 }
@@ -1270,6 +1346,22 @@ func executeMongoDBGroupBy(ctx context.Context, modelName string, groupByFields
 	groupStage := buildMongoDBGroupStage(groupByFields, options, modelName, db)
 	pipeline = append(pipeline, map[string]any{"$group": groupStage})
 
+	// Turn the value sets of distinct counts into counts
+	if count, ok := options["_count"].(map[string]any); ok {
+		countStage := make(map[string]any)
+		for _, field := range parseCountFields(count) {
+			if field.distinct {
+				countStage["_count_"+field.field] = map[string]any{"$size": map[string]any{"$filter": map[string]any{
+					"input": "$_count_" + field.field,
+					"cond":  map[string]any{"$ne": []any{"$$this", nil}},
+				}}}
+			}
+		}
+		if len(countStage) > 0 {
+			pipeline = append(pipeline, map[string]any{"$addFields": countStage})
+		}
+	}
+
 	// Add $match stage for HAVING conditions
 	if having, ok := options["having"]; ok {
 		havingFilter := buildMongoDBHavingFilter(having)
@@ -1319,13 +1411,35 @@ func buildMongoDBMatchFilter(where any, modelName string, db types.Database) map
 			if err != nil {
 				columnName = field
 			}
-			filter[columnName] = value
+			filter[columnName] = mongoDBFieldFilter(value)
 		}
 		return filter
 	}
 	return nil
 }
 
+// mongoDBFieldFilter maps the comparison operators of a field filter, as in { gt: 10 }, to
+// their MongoDB operators; other values are matched as they are
+func mongoDBFieldFilter(value any) any {
+	opMap, ok := value.(map[string]any)
+	if !ok {
+		return value
+	}
+	operators := map[string]string{
+		"equals": "$eq", "not": "$ne", "gt": "$gt", "gte": "$gte",
+		"lt": "$lt", "lte": "$lte", "in": "$in", "notIn": "$nin",
+	}
+	filter := make(map[string]any, len(opMap))
+	for op, operand := range opMap {
+		mongoOp, ok := operators[op]
+		if !ok {
+			return value
+		}
+		filter[mongoOp] = operand
+	}
+	return filter
+}
+
 // buildMongoDBGroupStage builds MongoDB group stage with aggregations
 func buildMongoDBGroupStage(groupByFields []string, options map[string]any, modelName string, db types.Database) map[string]any {
 	// Build _id for grouping
@@ -1359,9 +1473,22 @@ func buildMongoDBGroupStage(groupByFields []string, options map[string]any, mode
 				groupStage["_count"] = map[string]any{"$sum": 1}
 			}
 		case map[string]any:
-			for field, enabled := range c {
-				if e, ok := enabled.(bool); ok && e {
-					groupStage["_count_"+field] = map[string]any{"$sum": 1}
+			for _, count := range parseCountFields(c) {
+				columnName, err := db.ResolveFieldName(modelName, count.field)
+				if err != nil {
+					columnName = count.field
+				}
+				switch {
+				case count.field == "_all":
+					groupStage["_count__all"] = map[string]any{"$sum": 1}
+				case count.distinct:
+					// Counted by the distinct count stage
+					groupStage["_count_"+count.field] = map[string]any{"$addToSet": "$" + columnName}
+				default:
+					// Missing and null values sort below every other value
+					groupStage["_count_"+count.field] = map[string]any{
+						"$sum": map[string]any{"$cond": []any{map[string]any{"$gt": []any{"$" + columnName, nil}}, 1, 0}},
+					}
 				}
 			}
 		}
@@ -1473,44 +1600,43 @@ func buildMongoDBHavingFilter(having any) map[string]any {
 	return nil
 }
 
-// buildMongoDBSortStage builds MongoDB sort stage
-func buildMongoDBSortStage(orderBy any, modelName string, db types.Database) map[string]any {
-	sort := make(map[string]any)
-
-	switch ob := orderBy.(type) {
-	case map[string]any:
-		for field, direction := range ob {
-			dir := 1
-			if dirStr, ok := direction.(string); ok && strings.ToLower(dirStr) == "desc" {
-				dir = -1
-			}
-
-			// Check if it's a regular field or aggregation result
-			if strings.HasPrefix(field, "_") {
-				// Handle nested aggregation ordering like {_sum: {amount: 'desc'}}
-				if dirMap, ok := direction.(map[string]any); ok {
-					// This is nested aggregation ordering
-					for aggField, aggDir := range dirMap {
-						aggDirInt := 1
-						if dirStr, ok := aggDir.(string); ok && strings.ToLower(dirStr) == "desc" {
-							aggDirInt = -1
-						}
-						// Create the aggregation field name: field_aggField (e.g., _sum_amount)
-						aggFieldName := fmt.Sprintf("%s_%s", field, aggField)
-						sort[aggFieldName] = aggDirInt
+// buildMongoDBSortStage builds MongoDB sort stage. The keys are listed as single-key
+// documents, as the order they apply in would not survive a JSON object.
+func buildMongoDBSortStage(orderBy any, modelName string, db types.Database) []any {
+	var sort []any
+
+	for _, entry := range orderByEntries(orderBy) {
+		field := entry.Field
+		dir := 1
+		if dirStr, ok := entry.Direction.(string); ok && strings.ToLower(dirStr) == "desc" {
+			dir = -1
+		}
+
+		// Check if it's a regular field or aggregation result
+		if strings.HasPrefix(field, "_") {
+			// Handle nested aggregation ordering like {_sum: {amount: 'desc'}}
+			if _, ok := entry.Direction.(map[string]any); ok {
+				// This is nested aggregation ordering
+				for _, agg := range orderByEntries(entry.Direction) {
+					aggDirInt := 1
+					if dirStr, ok := agg.Direction.(string); ok && strings.ToLower(dirStr) == "desc" {
+						aggDirInt = -1
 					}
-				} else {
-					// Simple aggregation field - use as is
-					sort[field] = dir
+					// Create the aggregation field name: field_aggField (e.g., _sum_amount)
+					aggFieldName := fmt.Sprintf("%s_%s", field, agg.Field)
+					sort = append(sort, map[string]any{aggFieldName: aggDirInt})
 				}
 			} else {
-				// Regular field - resolve column name
-				columnName, err := db.ResolveFieldName(modelName, field)
-				if err != nil {
-					columnName = field
-				}
-				sort[columnName] = dir
+				// Simple aggregation field - use as is
+				sort = append(sort, map[string]any{field: dir})
 			}
+		} else {
+			// Regular field - resolve column name
+			columnName, err := db.ResolveFieldName(modelName, field)
+			if err != nil {
+				columnName = field
+			}
+			sort = append(sort, map[string]any{columnName: dir})
 		}
 	}
 
@@ -1540,10 +1666,11 @@ func processMongoDBGroupByResults(results []map[string]any, groupByFields []stri
 
 		// Process aggregation results
 		aggregations := map[string]map[string]any{
-			"_sum": make(map[string]any),
-			"_avg": make(map[string]any),
-			"_min": make(map[string]any),
-			"_max": make(map[string]any),
+			"_count": make(map[string]any),
+			"_sum":   make(map[string]any),
+			"_avg":   make(map[string]any),
+			"_min":   make(map[string]any),
+			"_max":   make(map[string]any),
 		}
 
 		for k, v := range result {
@@ -1,6 +1,9 @@
 package orm
 
 import (
+	"strconv"
+
+	"github.com/rediwo/redi-orm/schema"
 	"github.com/rediwo/redi-orm/types"
 	"github.com/rediwo/redi-orm/utils"
 )
@@ -8,6 +11,12 @@ import (
 // TypeConverter handles database-specific type conversions
 type TypeConverter struct {
 	capabilities types.DriverCapabilities
+	schemas      schemaSource
+}
+
+// schemaSource looks up the schemas of models; types.Database is one
+type schemaSource interface {
+	GetSchema(modelName string) (*schema.Schema, error)
 }
 
 // NewTypeConverter creates a new type converter for the specified driver
@@ -24,14 +33,53 @@ func (tc *TypeConverter) ConvertResult(modelName string, result map[string]any)
 	}
 
 	// Convert MySQL string numbers to proper types
+	modelSchema := tc.schema(modelName)
 	converted := make(map[string]any, len(result))
 	for key, value := range result {
-		converted[key] = tc.convertValue(value)
+		converted[key] = tc.convertFieldValue(modelSchema, result, key, value)
 	}
 
 	return converted
 }
 
+// schema returns the schema of a model, or nil when it is not known
+func (tc *TypeConverter) schema(modelName string) *schema.Schema {
+	if tc.schemas == nil || modelName == "" {
+		return nil
+	}
+	modelSchema, err := tc.schemas.GetSchema(modelName)
+	if err != nil {
+		return nil
+	}
+	return modelSchema
+}
+
+// convertFieldValue converts the value of a field of a result. Decimal fields keep their
+// exact text, and included relations are converted with the schema of their model.
+func (tc *TypeConverter) convertFieldValue(modelSchema *schema.Schema, record map[string]any, key string, value any) any {
+	if modelSchema == nil {
+		return tc.convertValue(value)
+	}
+	if field, err := modelSchema.GetField(key); err == nil && field.Type == schema.FieldTypeDecimal {
+		return value
+	}
+	relation, ok := modelSchema.Relations[key]
+	if !ok {
+		return tc.convertValue(value)
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		return tc.ConvertResult(relation.RelatedModel(record), v)
+	case []any:
+		converted := make([]any, len(v))
+		for i, item := range v {
+			converted[i] = tc.convertFieldValue(modelSchema, nil, key, item)
+		}
+		return converted
+	}
+	return tc.convertValue(value)
+}
+
 // ConvertAggregateResult converts aggregation results
 func (tc *TypeConverter) ConvertAggregateResult(result map[string]any) map[string]any {
 	converted := make(map[string]any, len(result))
@@ -67,7 +115,10 @@ func (tc *TypeConverter) convertValue(value any) any {
 
 	// For MySQL, check if it's a string that should be a number
 	if strVal, ok := value.(string); ok {
-		// Try to convert to float first (handles both int and float)
+		// Integers are parsed exactly, as they may be beyond what a float64 holds
+		if intVal, err := strconv.ParseInt(strVal, 10, 64); err == nil {
+			return intVal
+		}
 		floatVal := utils.ToFloat64(strVal)
 		if floatVal != 0 || strVal == "0" {
 			// Check if it's actually an integer
@@ -1,6 +1,9 @@
 package prisma
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // Node represents an AST node
 type Node interface {
@@ -32,17 +35,27 @@ func (ps *PrismaSchema) String() string {
 	return out
 }
 
+// Position is the line and column of a node in the schema source, both counted from 1
+type Position struct {
+	Line   int
+	Column int
+}
+
 // ModelStatement represents a model definition
 type ModelStatement struct {
+	Position
 	Name            string
 	Fields          []*Field
 	BlockAttributes []*BlockAttribute
+	Comment         string // Documentation comment (/// lines) preceding the model
 }
 
 func (ms *ModelStatement) statementNode() {}
 func (ms *ModelStatement) String() string {
-	out := fmt.Sprintf("model %s {\n", ms.Name)
+	out := docComment(ms.Comment, "")
+	out += fmt.Sprintf("model %s {\n", ms.Name)
 	for _, field := range ms.Fields {
+		out += docComment(field.Comment, "  ")
 		out += "  " + field.String() + "\n"
 	}
 	for _, attr := range ms.BlockAttributes {
@@ -52,6 +65,38 @@ func (ms *ModelStatement) String() string {
 	return out
 }
 
+// TypeStatement represents a composite type definition (the structure of embedded documents)
+type TypeStatement struct {
+	Position
+	Name    string
+	Fields  []*Field
+	Comment string // Documentation comment (/// lines) preceding the type
+}
+
+func (ts *TypeStatement) statementNode() {}
+func (ts *TypeStatement) String() string {
+	out := docComment(ts.Comment, "")
+	out += fmt.Sprintf("type %s {\n", ts.Name)
+	for _, field := range ts.Fields {
+		out += docComment(field.Comment, "  ")
+		out += "  " + field.String() + "\n"
+	}
+	out += "}"
+	return out
+}
+
+// docComment renders a documentation comment as /// lines
+func docComment(comment, indent string) string {
+	if comment == "" {
+		return ""
+	}
+	var out string
+	for _, line := range strings.Split(comment, "\n") {
+		out += strings.TrimRight(indent+"/// "+line, " ") + "\n"
+	}
+	return out
+}
+
 // EnumValue represents an enum value with optional mapping
 type EnumValue struct {
 	Name       string
@@ -60,6 +105,7 @@ type EnumValue struct {
 
 // EnumStatement represents an enum definition
 type EnumStatement struct {
+	Position
 	Name   string
 	Values []*EnumValue
 }
@@ -112,11 +158,13 @@ func (gs *GeneratorStatement) String() string {
 
 // Field represents a field in a model
 type Field struct {
+	Position
 	Name       string
 	Type       *FieldType
 	Optional   bool
 	List       bool
 	Attributes []*Attribute
+	Comment    string // Documentation comment (/// lines) preceding the field
 }
 
 func (f *Field) String() string {
@@ -133,17 +181,24 @@ func (f *Field) String() string {
 	return out
 }
 
-// FieldType represents a field type
+// FieldType represents a field type. The type of a polymorphic relation is a union of
+// models, such as Post | Photo, whose first model is also Name.
 type FieldType struct {
-	Name string
+	Position
+	Name  string
+	Union []string
 }
 
 func (ft *FieldType) String() string {
+	if len(ft.Union) > 0 {
+		return strings.Join(ft.Union, " | ")
+	}
 	return ft.Name
 }
 
 // Attribute represents a field attribute
 type Attribute struct {
+	Position
 	Name string
 	Args []Expression
 }
@@ -165,6 +220,7 @@ func (a *Attribute) String() string {
 
 // BlockAttribute represents a block-level attribute (@@)
 type BlockAttribute struct {
+	Position
 	Name string
 	Args []Expression
 }
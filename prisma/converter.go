@@ -2,6 +2,9 @@ package prisma
 
 import (
 	"fmt"
+	"maps"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -13,6 +16,8 @@ import (
 type Converter struct {
 	schemas    map[string]*schema.Schema
 	enums      map[string][]*EnumValue
+	types      map[string]*TypeStatement
+	composites map[string]*schema.CompositeType
 	datasource *DatasourceStatement
 	generator  *GeneratorStatement
 }
@@ -20,22 +25,29 @@ type Converter struct {
 // NewConverter creates a new converter instance
 func NewConverter() *Converter {
 	return &Converter{
-		schemas: make(map[string]*schema.Schema),
-		enums:   make(map[string][]*EnumValue),
+		schemas:    make(map[string]*schema.Schema),
+		enums:      make(map[string][]*EnumValue),
+		types:      make(map[string]*TypeStatement),
+		composites: make(map[string]*schema.CompositeType),
 	}
 }
 
 // Convert converts a Prisma schema AST to ReORM schemas
 func (c *Converter) Convert(prismaSchema *PrismaSchema) (map[string]*schema.Schema, error) {
-	// First pass: collect datasource, generator, and enums
+	// First pass: collect datasource, generator, enums, and composite types
 	for _, stmt := range prismaSchema.Statements {
 		switch s := stmt.(type) {
 		case *DatasourceStatement:
-			c.datasource = s
+			// The first block is the default datasource, others are selected with @@datasource
+			if c.datasource == nil {
+				c.datasource = s
+			}
 		case *GeneratorStatement:
 			c.generator = s
 		case *EnumStatement:
 			c.enums[s.Name] = s.Values
+		case *TypeStatement:
+			c.types[s.Name] = s
 		}
 	}
 
@@ -70,12 +82,14 @@ func (c *Converter) Convert(prismaSchema *PrismaSchema) (map[string]*schema.Sche
 // convertModel converts a Prisma model to a ReORM schema
 func (c *Converter) convertModel(modelStmt *ModelStatement) (*schema.Schema, error) {
 	s := schema.New(modelStmt.Name)
+	s.Comment = modelStmt.Comment
 
 	// Extract table name from block attributes if specified
 	tableName := c.extractTableName(modelStmt.BlockAttributes)
 	if tableName != "" {
 		s.WithTableName(tableName)
 	}
+	s.Datasource = c.extractDatasource(modelStmt.BlockAttributes)
 
 	// Convert fields
 	for _, field := range modelStmt.Fields {
@@ -111,6 +125,7 @@ func (c *Converter) convertField(field *Field) (schema.Field, error) {
 	f := schema.Field{
 		Name:     field.Name,
 		Nullable: field.Optional,
+		Comment:  field.Comment,
 	}
 
 	// Convert type
@@ -119,6 +134,17 @@ func (c *Converter) convertField(field *Field) (schema.Field, error) {
 		return f, fmt.Errorf("failed to convert field %s type %s: %v", field.Name, field.Type.Name, err)
 	}
 	f.Type = fieldType
+	if values, isEnum := c.enums[field.Type.Name]; isEnum {
+		f.Enum = field.Type.Name
+		f.EnumValues = enumValueNames(values)
+	}
+	if _, isComposite := c.types[field.Type.Name]; isComposite {
+		composite, err := c.convertCompositeType(field.Type.Name)
+		if err != nil {
+			return f, err
+		}
+		f.Composite = composite
+	}
 
 	// Process attributes
 	for _, attr := range field.Attributes {
@@ -143,6 +169,18 @@ func (c *Converter) convertField(field *Field) (schema.Field, error) {
 			}
 		case "autoincrement":
 			f.AutoIncrement = true
+		case "updatedAt":
+			f.UpdatedAt = true
+		case "map":
+			if len(attr.Args) > 0 {
+				if str, ok := attr.Args[0].(*StringLiteral); ok {
+					f.Map = str.Value
+				}
+			}
+		case "length", "regex", "min", "max", "email":
+			if err := c.convertValidation(&f, attr); err != nil {
+				return f, fmt.Errorf("invalid @%s on field %s: %v", attr.Name, field.Name, err)
+			}
 		default:
 			// Handle @db.* attributes (e.g., @db.VarChar(255), @db.Money)
 			if strings.HasPrefix(attr.Name, "db.") {
@@ -169,6 +207,110 @@ func (c *Converter) convertField(field *Field) (schema.Field, error) {
 	return f, nil
 }
 
+// convertCompositeType converts a composite type. Types are converted once, so fields
+// of the same type share the result and recursive types refer to themselves.
+func (c *Converter) convertCompositeType(name string) (*schema.CompositeType, error) {
+	if composite, ok := c.composites[name]; ok {
+		return composite, nil
+	}
+
+	composite := &schema.CompositeType{Name: name}
+	c.composites[name] = composite
+	for _, field := range c.types[name].Fields {
+		if c.isRelationField(field) {
+			return nil, fmt.Errorf("type %s field %s: composite types cannot have relations", name, field.Name)
+		}
+		f, err := c.convertField(field)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert type %s: %v", name, err)
+		}
+		composite.Fields = append(composite.Fields, f)
+	}
+	return composite, nil
+}
+
+// convertValidation adds a validation attribute (@length, @regex, @min, @max, @email) to the field
+func (c *Converter) convertValidation(f *schema.Field, attr *Attribute) error {
+	if f.Validation == nil {
+		f.Validation = &schema.Validation{}
+	}
+	v := f.Validation
+
+	switch attr.Name {
+	case "length":
+		// @length(max), @length(min, max) or @length(min: 1, max: 50)
+		if len(attr.Args) == 0 {
+			return fmt.Errorf("expected min and/or max")
+		}
+		for i, arg := range attr.Args {
+			name := ""
+			if named, ok := arg.(*NamedArgument); ok {
+				name, arg = named.Name, named.Value
+			} else if len(attr.Args) == 1 {
+				name = "max"
+			} else if i == 0 {
+				name = "min"
+			} else {
+				name = "max"
+			}
+			length, err := c.intArgument(arg)
+			if err != nil {
+				return err
+			}
+			switch name {
+			case "min":
+				v.MinLength = &length
+			case "max":
+				v.MaxLength = &length
+			default:
+				return fmt.Errorf("unknown argument %s", name)
+			}
+		}
+	case "regex":
+		if len(attr.Args) != 1 {
+			return fmt.Errorf("expected a pattern")
+		}
+		str, ok := attr.Args[0].(*StringLiteral)
+		if !ok {
+			return fmt.Errorf("pattern must be a string")
+		}
+		pattern, err := regexp.Compile(strings.NewReplacer(`\\`, `\`, `\"`, `"`).Replace(str.Value))
+		if err != nil {
+			return err
+		}
+		v.Pattern = pattern
+	case "min", "max":
+		if len(attr.Args) != 1 {
+			return fmt.Errorf("expected a number")
+		}
+		number, ok := attr.Args[0].(*NumberLiteral)
+		if !ok {
+			return fmt.Errorf("expected a number")
+		}
+		value, err := strconv.ParseFloat(number.Value, 64)
+		if err != nil {
+			return err
+		}
+		if attr.Name == "min" {
+			v.Min = &value
+		} else {
+			v.Max = &value
+		}
+	case "email":
+		v.Email = true
+	}
+	return nil
+}
+
+// intArgument converts a number literal argument to an int
+func (c *Converter) intArgument(arg Expression) (int, error) {
+	number, ok := arg.(*NumberLiteral)
+	if !ok {
+		return 0, fmt.Errorf("expected an integer")
+	}
+	return strconv.Atoi(number.Value)
+}
+
 // convertType converts Prisma type to ReORM FieldType
 func (c *Converter) convertType(typeName string, isList bool) (schema.FieldType, error) {
 	// Handle array types
@@ -193,6 +335,10 @@ func (c *Converter) convertType(typeName string, isList bool) (schema.FieldType,
 			if _, exists := c.enums[typeName]; exists {
 				return schema.FieldTypeStringArray, nil // Enum arrays are stored as string arrays
 			}
+			// Lists of composite types are arrays of embedded documents
+			if _, exists := c.types[typeName]; exists {
+				return schema.FieldTypeArray, nil
+			}
 			// Unknown array type, default to JSON
 			return schema.FieldTypeJSON, nil
 		}
@@ -216,11 +362,17 @@ func (c *Converter) convertType(typeName string, isList bool) (schema.FieldType,
 		return schema.FieldTypeJSON, nil
 	case "Decimal":
 		return schema.FieldTypeDecimal, nil
+	case "Bytes":
+		return schema.FieldTypeBytes, nil
 	default:
 		// Check if it's an enum
 		if _, exists := c.enums[typeName]; exists {
 			return schema.FieldTypeString, nil // Enums are stored as strings
 		}
+		// Composite types are embedded documents
+		if _, exists := c.types[typeName]; exists {
+			return schema.FieldTypeDocument, nil
+		}
 		// Unknown type, default to string
 		return schema.FieldTypeString, nil
 	}
@@ -301,13 +453,17 @@ func (c *Converter) convertExpression(expr Expression) (any, error) {
 func (c *Converter) isRelationField(field *Field) bool {
 	// Check if field type is not a primitive type
 	switch field.Type.Name {
-	case "String", "Int", "BigInt", "Float", "Boolean", "DateTime", "Json", "Decimal":
+	case "String", "Int", "BigInt", "Float", "Boolean", "DateTime", "Json", "Decimal", "Bytes":
 		return false
 	default:
 		// Check if it's an enum
 		if _, exists := c.enums[field.Type.Name]; exists {
 			return false
 		}
+		// Composite types are embedded, not related
+		if _, exists := c.types[field.Type.Name]; exists {
+			return false
+		}
 		// If it's an array of a model, it's a relation
 		if field.List {
 			return true
@@ -331,11 +487,13 @@ func (c *Converter) addRelations(modelStmt *ModelStatement) error {
 
 		relationName := field.Name
 		relatedModel := field.Type.Name
+		var typeField string
 
 		// Determine relation type and foreign key
 		var relationType schema.RelationType
 		var foreignKey string
 		var references string = "id" // Default reference field
+		var onDelete, onUpdate string
 
 		if field.List {
 			// Array field indicates one-to-many relation
@@ -345,9 +503,10 @@ func (c *Converter) addRelations(modelStmt *ModelStatement) error {
 		} else {
 			// Single field could be many-to-one or one-to-one
 			// Check if there's a @relation attribute to determine the correct type
+			// A @relation only naming the relation may be on the inverse side too
 			hasRelationAttribute := false
 			for _, attr := range field.Attributes {
-				if attr.Name == "relation" {
+				if attr.Name == "relation" && (len(attr.Args) == 0 || slices.ContainsFunc(attr.Args, func(arg Expression) bool { return !isRelationNameArg(arg) })) {
 					hasRelationAttribute = true
 					break
 				}
@@ -390,6 +549,20 @@ func (c *Converter) addRelations(modelStmt *ModelStatement) error {
 									references = ident.Value
 								}
 							}
+						case "type":
+							if ident, ok := na.Value.(*Identifier); ok {
+								typeField = ident.Value
+							}
+						case "onDelete", "onUpdate":
+							action, err := referentialAction(na.Value)
+							if err != nil {
+								return fmt.Errorf("invalid %s of relation %s.%s: %w", na.Name, modelStmt.Name, field.Name, err)
+							}
+							if na.Name == "onDelete" {
+								onDelete = action
+							} else {
+								onUpdate = action
+							}
 						}
 					}
 					// Handle legacy function call style
@@ -420,8 +593,17 @@ func (c *Converter) addRelations(modelStmt *ModelStatement) error {
 		relation := schema.Relation{
 			Type:       relationType,
 			Model:      relatedModel,
+			Name:       relationNameOf(field.Attributes),
 			ForeignKey: foreignKey,
 			References: references,
+			OnDelete:   onDelete,
+			OnUpdate:   onUpdate,
+		}
+
+		if len(field.Type.Union) > 0 || typeField != "" {
+			if err := c.makePolymorphic(&relation, currentSchema, field, typeField); err != nil {
+				return err
+			}
 		}
 
 		currentSchema.AddRelation(relationName, relation)
@@ -430,6 +612,92 @@ func (c *Converter) addRelations(modelStmt *ModelStatement) error {
 	return nil
 }
 
+// relationNameOf returns the name given to a relation by @relation("name") or
+// @relation(name: "name"), which tells relations between the same models apart
+func relationNameOf(attrs []*Attribute) string {
+	for _, attr := range attrs {
+		if attr.Name != "relation" {
+			continue
+		}
+		for _, arg := range attr.Args {
+			if !isRelationNameArg(arg) {
+				continue
+			}
+			if na, ok := arg.(*NamedArgument); ok {
+				arg = na.Value
+			}
+			if str, ok := arg.(*StringLiteral); ok {
+				return str.Value
+			}
+		}
+	}
+	return ""
+}
+
+// isRelationNameArg reports whether an argument of @relation is the name of the relation
+func isRelationNameArg(arg Expression) bool {
+	switch a := arg.(type) {
+	case *StringLiteral:
+		return true
+	case *NamedArgument:
+		return a.Name == "name"
+	}
+	return false
+}
+
+// makePolymorphic turns relation into a polymorphic relation to the union of models of
+// field, whose type field holds the name of the model each record references
+func (c *Converter) makePolymorphic(relation *schema.Relation, currentSchema *schema.Schema, field *Field, typeField string) error {
+	switch {
+	case len(field.Type.Union) == 0:
+		return fmt.Errorf("relation %s.%s has a type field but a single model, polymorphic relations reference a union of models such as A | B", currentSchema.Name, field.Name)
+	case typeField == "":
+		return fmt.Errorf("polymorphic relation %s.%s needs a type field, as in @relation(fields: [...], references: [...], type: ...)", currentSchema.Name, field.Name)
+	case field.List:
+		return fmt.Errorf("polymorphic relation %s.%s cannot be a list, it is declared on the model holding the foreign key", currentSchema.Name, field.Name)
+	}
+
+	for _, model := range field.Type.Union {
+		if _, exists := c.schemas[model]; !exists {
+			return fmt.Errorf("polymorphic relation %s.%s references unknown model %s", currentSchema.Name, field.Name, model)
+		}
+	}
+	if _, err := currentSchema.GetField(typeField); err != nil {
+		return fmt.Errorf("type field %s of polymorphic relation %s.%s not found", typeField, currentSchema.Name, field.Name)
+	}
+	if _, err := currentSchema.GetField(relation.ForeignKey); err != nil {
+		return fmt.Errorf("foreign key %s of polymorphic relation %s.%s not found", relation.ForeignKey, currentSchema.Name, field.Name)
+	}
+
+	relation.Type = schema.RelationManyToOne
+	relation.Model = ""
+	relation.Models = field.Type.Union
+	relation.TypeField = typeField
+	return nil
+}
+
+// referentialActions maps the referential actions of Prisma to their SQL form
+var referentialActions = map[string]string{
+	"Cascade":    schema.ActionCascade,
+	"SetNull":    schema.ActionSetNull,
+	"SetDefault": schema.ActionSetDefault,
+	"Restrict":   schema.ActionRestrict,
+	"NoAction":   schema.ActionNoAction,
+}
+
+// referentialAction returns the SQL form of the referential action of onDelete or onUpdate
+func referentialAction(value Expression) (string, error) {
+	ident, ok := value.(*Identifier)
+	if !ok {
+		return "", fmt.Errorf("expected one of Cascade, SetNull, SetDefault, Restrict or NoAction")
+	}
+	action, ok := referentialActions[ident.Value]
+	if !ok {
+		return "", fmt.Errorf("unknown referential action %s, expected one of Cascade, SetNull, SetDefault, Restrict or NoAction", ident.Value)
+	}
+	return action, nil
+}
+
 // findForeignKeyField looks for a foreign key field in the model
 func (c *Converter) findForeignKeyField(modelStmt *ModelStatement, relatedModel string) string {
 	expectedFK := strings.ToLower(relatedModel) + "_id"
@@ -453,49 +721,63 @@ func (c *Converter) extractTableName(attrs []*BlockAttribute) string {
 	return ""
 }
 
+// extractDatasource extracts the datasource name of @@datasource(name) from block attributes
+func (c *Converter) extractDatasource(attrs []*BlockAttribute) string {
+	for _, attr := range attrs {
+		if attr.Name == "datasource" && len(attr.Args) > 0 {
+			switch arg := attr.Args[0].(type) {
+			case *StringLiteral:
+				return arg.Value
+			case *Identifier:
+				return arg.Value
+			}
+		}
+	}
+	return ""
+}
+
 // extractIndexes extracts indexes from block attributes
 func (c *Converter) extractIndexes(attrs []*BlockAttribute) []schema.Index {
 	var indexes []schema.Index
 
 	for _, attr := range attrs {
-		switch attr.Name {
-		case "index":
-			if len(attr.Args) > 0 {
-				if arr, ok := attr.Args[0].(*ArrayExpression); ok {
-					var fields []string
-					for _, elem := range arr.Elements {
-						if ident, ok := elem.(*Identifier); ok {
-							fields = append(fields, ident.Value)
-						}
-					}
-					if len(fields) > 0 {
-						indexes = append(indexes, schema.Index{
-							Name:   utils.GenerateIndexName("", fields, false, ""),
-							Fields: fields,
-							Unique: false,
-						})
-					}
-				}
+		if attr.Name != "index" && attr.Name != "unique" {
+			continue
+		}
+		if len(attr.Args) == 0 {
+			continue
+		}
+		arr, ok := attr.Args[0].(*ArrayExpression)
+		if !ok {
+			continue
+		}
+
+		var fields []string
+		for _, elem := range arr.Elements {
+			if ident, ok := elem.(*Identifier); ok {
+				fields = append(fields, ident.Value)
 			}
-		case "unique":
-			if len(attr.Args) > 0 {
-				if arr, ok := attr.Args[0].(*ArrayExpression); ok {
-					var fields []string
-					for _, elem := range arr.Elements {
-						if ident, ok := elem.(*Identifier); ok {
-							fields = append(fields, ident.Value)
-						}
-					}
-					if len(fields) > 0 {
-						indexes = append(indexes, schema.Index{
-							Name:   utils.GenerateIndexName("", fields, true, ""),
-							Fields: fields,
-							Unique: true,
-						})
-					}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		// map: "name" keeps the database name of the index
+		unique := attr.Name == "unique"
+		var mapName string
+		for _, arg := range attr.Args[1:] {
+			if named, ok := arg.(*NamedArgument); ok && named.Name == "map" {
+				if str, ok := named.Value.(*StringLiteral); ok {
+					mapName = str.Value
 				}
 			}
 		}
+
+		indexes = append(indexes, schema.Index{
+			Name:   utils.GenerateIndexName("", fields, unique, mapName),
+			Fields: fields,
+			Unique: unique,
+		})
 	}
 
 	return indexes
@@ -511,6 +793,24 @@ func (c *Converter) GetGenerator() *GeneratorStatement {
 	return c.generator
 }
 
+// GetEnums returns the declared enums and their value names
+func (c *Converter) GetEnums() map[string][]string {
+	enums := make(map[string][]string, len(c.enums))
+	for name, values := range c.enums {
+		enums[name] = enumValueNames(values)
+	}
+	return enums
+}
+
+// enumValueNames returns the names of enum values
+func enumValueNames(values []*EnumValue) []string {
+	names := make([]string, len(values))
+	for i, value := range values {
+		names[i] = value.Name
+	}
+	return names
+}
+
 // GetDatabaseProvider extracts the database provider from datasource
 func (c *Converter) GetDatabaseProvider() string {
 	if c.datasource == nil {
@@ -601,26 +901,33 @@ func (c *Converter) fixInverseRelationForeignKeys() error {
 				}
 
 				// Look for the inverse relation
-				for _, relatedRelation := range relatedSchema.Relations {
-					if relatedRelation.Model == modelName {
-						// Found a relation pointing back to the current model
-						if relatedRelation.ForeignKey != "" {
-							// Copy the foreign key from the related model
-							relation.ForeignKey = relatedRelation.ForeignKey
-
-							// If the related relation has a unique foreign key, this is a one-to-one
-							if relation.Type == "oneToOne" {
-								// Keep it as OneToOne
-							} else if c.isForeignKeyUnique(relatedSchema, relatedRelation.ForeignKey) {
-								// Convert OneToMany to OneToOne if the foreign key is unique
-								relation.Type = "oneToOne"
-							}
-
-							// Update the relation in the schema
-							currentSchema.AddRelation(relationName, relation)
-						}
-						break
+				relatedRelation, found := c.inverseRelation(modelName, relationName, relation, relatedSchema)
+				if !found {
+					continue
+				}
+				if relatedRelation.IsPolymorphic() {
+					// Records of the related model referencing this model hold its name
+					// in the type field
+					relation.ForeignKey = relatedRelation.ForeignKey
+					relation.References = relatedRelation.References
+					relation.TypeField = relatedRelation.TypeField
+					currentSchema.AddRelation(relationName, relation)
+					continue
+				}
+				if relatedRelation.ForeignKey != "" {
+					// Copy the foreign key from the related model
+					relation.ForeignKey = relatedRelation.ForeignKey
+
+					// If the related relation has a unique foreign key, this is a one-to-one
+					if relation.Type == "oneToOne" {
+						// Keep it as OneToOne
+					} else if c.isForeignKeyUnique(relatedSchema, relatedRelation.ForeignKey) {
+						// Convert OneToMany to OneToOne if the foreign key is unique
+						relation.Type = "oneToOne"
 					}
+
+					// Update the relation in the schema
+					currentSchema.AddRelation(relationName, relation)
 				}
 			}
 		}
@@ -629,6 +936,35 @@ func (c *Converter) fixInverseRelationForeignKeys() error {
 	return nil
 }
 
+// inverseRelation finds the relation of relatedSchema pointing back to relation of
+// modelName. Relations with the same name come first, then those holding the foreign key,
+// so that several relations between the same models, or of a model to itself, pair up.
+func (c *Converter) inverseRelation(modelName, relationName string, relation schema.Relation, relatedSchema *schema.Schema) (schema.Relation, bool) {
+	var best schema.Relation
+	bestScore := -1
+	for _, name := range slices.Sorted(maps.Keys(relatedSchema.Relations)) {
+		candidate := relatedSchema.Relations[name]
+		if relation.Model == modelName && name == relationName {
+			continue // A self-relation is not its own inverse
+		}
+		if candidate.Model != modelName && !slices.Contains(candidate.Models, modelName) {
+			continue
+		}
+
+		score := 0
+		if candidate.Name == relation.Name {
+			score += 2
+		}
+		if _, err := relatedSchema.GetField(candidate.ForeignKey); err == nil {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = candidate, score
+		}
+	}
+	return best, bestScore >= 0
+}
+
 // isForeignKeyUnique checks if a foreign key field has a unique constraint
 func (c *Converter) isForeignKeyUnique(s *schema.Schema, foreignKey string) bool {
 	// Check if the field itself is marked as unique
@@ -0,0 +1,115 @@
+package prisma
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rediwo/redi-orm/schema"
+)
+
+// ResolveURL returns the database URI of the datasource. A url written as env("NAME")
+// is read from the environment variable NAME, so schemas can be committed without
+// credentials. Prisma's file: URLs of SQLite datasources become sqlite:// URIs.
+func (ds *DatasourceStatement) ResolveURL() (string, error) {
+	var provider, url string
+	for _, prop := range ds.Properties {
+		switch prop.Name {
+		case "provider":
+			if str, ok := prop.Value.(*StringLiteral); ok {
+				provider = str.Value
+			}
+		case "url":
+			switch value := prop.Value.(type) {
+			case *StringLiteral:
+				url = value.Value
+			case *FunctionCall:
+				name, err := envVariableName(value)
+				if err != nil {
+					return "", fmt.Errorf("datasource %s: %w", ds.Name, err)
+				}
+				if url = os.Getenv(name); url == "" {
+					return "", fmt.Errorf("datasource %s: environment variable %s is not set", ds.Name, name)
+				}
+			}
+		}
+	}
+
+	if path, ok := strings.CutPrefix(url, "file:"); ok && provider == "sqlite" {
+		url = "sqlite://" + path
+	}
+	return url, nil
+}
+
+// RelationMode returns how the references of relations are kept intact in the datasource:
+// by foreign keys, the default, or by the ORM with relationMode = "prisma"
+func (ds *DatasourceStatement) RelationMode() (string, error) {
+	for _, prop := range ds.Properties {
+		if prop.Name != "relationMode" {
+			continue
+		}
+		str, ok := prop.Value.(*StringLiteral)
+		if !ok || (str.Value != schema.RelationModeForeignKeys && str.Value != schema.RelationModePrisma) {
+			return "", fmt.Errorf("datasource %s: relationMode must be \"foreignKeys\" or \"prisma\"", ds.Name)
+		}
+		return str.Value, nil
+	}
+	return schema.RelationModeForeignKeys, nil
+}
+
+// applyRelationModes sets the relation mode of each model from the datasource it is stored in
+func (d *Definition) applyRelationModes() error {
+	for _, s := range d.Schemas {
+		ds := d.Datasource
+		if s.Datasource != "" {
+			ds = d.DatasourceByName(s.Datasource)
+		}
+		if ds == nil {
+			continue
+		}
+		mode, err := ds.RelationMode()
+		if err != nil {
+			return err
+		}
+		s.RelationMode = mode
+	}
+	return nil
+}
+
+// envVariableName returns NAME of an env("NAME") call
+func envVariableName(fc *FunctionCall) (string, error) {
+	if fc.Name != "env" {
+		return "", fmt.Errorf("unsupported url function %s()", fc.Name)
+	}
+	if len(fc.Args) != 1 {
+		return "", fmt.Errorf("env() takes the name of one environment variable")
+	}
+	str, ok := fc.Args[0].(*StringLiteral)
+	if !ok || str.Value == "" {
+		return "", fmt.Errorf("env() takes the name of one environment variable")
+	}
+	return str.Value, nil
+}
+
+// DatabaseURLFromPath returns the database URI of the datasource declared in a schema file
+// or directory, or an empty string when there is no datasource url
+func DatabaseURLFromPath(path string) (string, error) {
+	def, err := LoadDefinitionFromPath(path)
+	if err != nil {
+		return "", err
+	}
+	if def.Datasource == nil {
+		return "", nil
+	}
+	return def.Datasource.ResolveURL()
+}
+
+// DatasourceByName returns the datasource block with the given name, or nil
+func (d *Definition) DatasourceByName(name string) *DatasourceStatement {
+	for _, ds := range d.Datasources {
+		if ds.Name == name {
+			return ds
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package prisma
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseError is an error at a position of a Prisma schema
+type ParseError struct {
+	File       string // The schema file, empty for schema content
+	Line       int
+	Column     int
+	Message    string
+	Snippet    string // The source line, with a caret under the column
+	Suggestion string // The name an unknown name was likely meant to be
+}
+
+func (e *ParseError) Error() string {
+	var b strings.Builder
+	if e.File != "" {
+		b.WriteString(e.File + ":")
+	}
+	fmt.Fprintf(&b, "%d:%d: %s", e.Line, e.Column, e.Message)
+	if e.Suggestion != "" {
+		fmt.Fprintf(&b, ", did you mean %s?", e.Suggestion)
+	}
+	if e.Snippet != "" {
+		b.WriteString("\n" + e.Snippet)
+	}
+	return b.String()
+}
+
+// ParseErrors holds the errors of a schema in source order
+type ParseErrors []*ParseError
+
+func (e ParseErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// inFile sets the file of the errors
+func (e ParseErrors) inFile(file string) ParseErrors {
+	for _, err := range e {
+		err.File = file
+	}
+	return e
+}
+
+// newParseError returns the error of message at a position of source
+func newParseError(source string, line, column int, message string) *ParseError {
+	return &ParseError{Line: line, Column: column, Message: message, Snippet: snippet(source, line, column)}
+}
+
+// snippet returns a line of source numbered as in an editor, followed by a caret under
+// column, or "" when source has no such line
+func snippet(source string, line, column int) string {
+	lines := strings.Split(source, "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	text := strings.TrimRight(lines[line-1], "\r")
+	number := fmt.Sprint(line)
+	gutter := strings.Repeat(" ", len(number))
+
+	// Tabs are kept under the caret so that it lines up with the source
+	var indent strings.Builder
+	for i := 0; i < column-1 && i < len(text); i++ {
+		if text[i] == '\t' {
+			indent.WriteByte('\t')
+		} else {
+			indent.WriteByte(' ')
+		}
+	}
+	return fmt.Sprintf("%s | %s\n%s | %s^", number, text, gutter, indent.String())
+}
+
+// suggest returns the candidate closest to an unknown name, or "" when none is close enough
+// to be a likely typo of it
+func suggest(name string, candidates []string) string {
+	best, bestDistance := "", len(name)/3+1
+	for _, candidate := range candidates {
+		if strings.EqualFold(candidate, name) {
+			return candidate
+		}
+		if distance := editDistance(strings.ToLower(name), strings.ToLower(candidate)); distance <= bestDistance && (best == "" || distance < bestDistance) {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance of a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,55 @@
+package prisma
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Config returns the properties of the generator block, such as provider and output, as
+// text. Values written as env("NAME") are read from the environment variable NAME, and
+// lists such as binaryTargets are joined with commas.
+func (gs *GeneratorStatement) Config() (map[string]string, error) {
+	config := make(map[string]string, len(gs.Properties))
+	for _, prop := range gs.Properties {
+		value, err := generatorValue(prop.Value)
+		if err != nil {
+			return nil, fmt.Errorf("generator %s: property %s: %w", gs.Name, prop.Name, err)
+		}
+		config[prop.Name] = value
+	}
+	return config, nil
+}
+
+// generatorValue returns the text of a generator property value
+func generatorValue(expr Expression) (string, error) {
+	switch value := expr.(type) {
+	case *StringLiteral:
+		return value.Value, nil
+	case *NumberLiteral:
+		return value.Value, nil
+	case *Identifier:
+		return value.Value, nil
+	case *ArrayExpression:
+		elements := make([]string, len(value.Elements))
+		for i, element := range value.Elements {
+			text, err := generatorValue(element)
+			if err != nil {
+				return "", err
+			}
+			elements[i] = text
+		}
+		return strings.Join(elements, ","), nil
+	case *FunctionCall:
+		if value.Name != "env" {
+			return "", fmt.Errorf("unsupported function %s()", value.Name)
+		}
+		name, err := envVariableName(value)
+		if err != nil {
+			return "", err
+		}
+		return os.Getenv(name), nil
+	default:
+		return "", fmt.Errorf("unsupported value %s", expr)
+	}
+}
@@ -44,6 +44,7 @@ const (
 	AT       // @
 	BLOCK_AT // @@
 	QUESTION // ?
+	PIPE     // |
 	COMMA    // ,
 	EQUALS   // =
 	COLON    // :
@@ -227,14 +228,15 @@ func (l *Lexer) NextToken() Token {
 		tok = Token{Type: RPAREN, Literal: string(l.ch), Line: l.line, Column: l.column}
 	case '@':
 		if l.peekChar() == '@' {
-			ch := l.ch
+			tok = Token{Type: BLOCK_AT, Literal: "@@", Line: l.line, Column: l.column}
 			l.readChar()
-			tok = Token{Type: BLOCK_AT, Literal: string(ch) + string(l.ch), Line: l.line, Column: l.column}
 		} else {
 			tok = Token{Type: AT, Literal: string(l.ch), Line: l.line, Column: l.column}
 		}
 	case '?':
 		tok = Token{Type: QUESTION, Literal: string(l.ch), Line: l.line, Column: l.column}
+	case '|':
+		tok = Token{Type: PIPE, Literal: string(l.ch), Line: l.line, Column: l.column}
 	case ',':
 		tok = Token{Type: COMMA, Literal: string(l.ch), Line: l.line, Column: l.column}
 	case '=':
@@ -245,15 +247,15 @@ func (l *Lexer) NextToken() Token {
 		tok = Token{Type: DOT, Literal: string(l.ch), Line: l.line, Column: l.column}
 	case '"':
 		tok.Type = STRING
-		tok.Literal = l.readString()
 		tok.Line = l.line
 		tok.Column = l.column
+		tok.Literal = l.readString()
 	case '/':
 		if l.peekChar() == '/' {
 			tok.Type = COMMENT
-			tok.Literal = l.readComment()
 			tok.Line = l.line
 			tok.Column = l.column
+			tok.Literal = l.readComment()
 		} else {
 			tok = Token{Type: ILLEGAL, Literal: string(l.ch), Line: l.line, Column: l.column}
 		}
@@ -268,7 +270,7 @@ func (l *Lexer) NextToken() Token {
 			tok.Type = lookupIdent(tok.Literal)
 			// Don't call l.readChar() here as readIdentifier() already advanced past the identifier
 			return tok
-		} else if isDigit(l.ch) {
+		} else if isDigit(l.ch) || (l.ch == '-' && isDigit(l.peekChar())) {
 			tok.Line = l.line
 			tok.Column = l.column
 			tok.Type = NUMBER
@@ -300,20 +302,34 @@ func (l *Lexer) readIdentifier() string {
 	return l.input[position:l.position]
 }
 
-// readNumber reads a number
+// readNumber reads a number, optionally negative and with a fraction
 func (l *Lexer) readNumber() string {
 	position := l.position
+	if l.ch == '-' {
+		l.readChar()
+	}
 	for isDigit(l.ch) {
 		l.readChar()
 	}
+	if l.ch == '.' && isDigit(l.peekChar()) {
+		l.readChar()
+		for isDigit(l.ch) {
+			l.readChar()
+		}
+	}
 	return l.input[position:l.position]
 }
 
-// readString reads a string literal
+// readString reads a string literal. Escaped quotes (\") do not end the string;
+// escape sequences are kept as written.
 func (l *Lexer) readString() string {
 	position := l.position + 1
 	for {
 		l.readChar()
+		if l.ch == '\\' && l.peekChar() != 0 {
+			l.readChar()
+			continue
+		}
 		if l.ch == '"' || l.ch == 0 {
 			break
 		}
@@ -401,6 +417,8 @@ func (t TokenType) String() string {
 		return "@@"
 	case QUESTION:
 		return "?"
+	case PIPE:
+		return "|"
 	case COMMA:
 		return ","
 	case EQUALS:
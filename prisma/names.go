@@ -0,0 +1,105 @@
+package prisma
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// scalarTypes are the built-in field types
+var scalarTypes = []string{"String", "Int", "BigInt", "Float", "Boolean", "DateTime", "Json", "Decimal", "Bytes"}
+
+// fieldAttributes and blockAttributes are the attributes models may use besides native
+// types such as @db.VarChar. Those of Prisma without effect here, like @ignore, are accepted.
+var (
+	fieldAttributes = []string{"id", "unique", "default", "relation", "map", "updatedAt", "ignore",
+		"autoincrement", "length", "regex", "min", "max", "email"}
+	blockAttributes = []string{"id", "unique", "index", "map", "datasource", "ignore", "schema", "fulltext"}
+)
+
+// sourceFile is a parsed schema
+type sourceFile struct {
+	name    string // The schema file, empty for schema content
+	content string
+	ast     *PrismaSchema
+}
+
+// checkNames reports the unknown field types and attributes of files, which may use the
+// models, enums and types declared in any of them, suggesting the names likely meant
+func checkNames(files []sourceFile) error {
+	types := slices.Clone(scalarTypes)
+	var models []string
+	for _, file := range files {
+		for _, stmt := range file.ast.Statements {
+			switch stmt := stmt.(type) {
+			case *ModelStatement:
+				models = append(models, stmt.Name)
+				types = append(types, stmt.Name)
+			case *EnumStatement:
+				types = append(types, stmt.Name)
+			case *TypeStatement:
+				types = append(types, stmt.Name)
+			}
+		}
+	}
+
+	var errs ParseErrors
+	for _, file := range files {
+		report := func(position Position, message, suggestion string) {
+			err := newParseError(file.content, position.Line, position.Column, message)
+			err.File = file.name
+			err.Suggestion = suggestion
+			errs = append(errs, err)
+		}
+
+		for _, stmt := range file.ast.Statements {
+			var fields []*Field
+			switch stmt := stmt.(type) {
+			case *ModelStatement:
+				fields = stmt.Fields
+				for _, attr := range stmt.BlockAttributes {
+					if !knownAttribute(attr.Name, blockAttributes) {
+						report(attr.Position, "unknown attribute @@"+attr.Name, attributeSuggestion("@@", attr.Name, blockAttributes))
+					}
+				}
+			case *TypeStatement:
+				fields = stmt.Fields
+			}
+
+			for _, field := range fields {
+				// Polymorphic relations reference a union of models
+				for _, name := range field.Type.Union {
+					if !slices.Contains(models, name) {
+						report(field.Type.Position, fmt.Sprintf("unknown model %s of field %s", name, field.Name), suggest(name, models))
+					}
+				}
+				if len(field.Type.Union) == 0 && !slices.Contains(types, field.Type.Name) {
+					report(field.Type.Position, fmt.Sprintf("unknown type %s of field %s", field.Type.Name, field.Name), suggest(field.Type.Name, types))
+				}
+				for _, attr := range field.Attributes {
+					if !knownAttribute(attr.Name, fieldAttributes) {
+						report(attr.Position, "unknown attribute @"+attr.Name, attributeSuggestion("@", attr.Name, fieldAttributes))
+					}
+				}
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// knownAttribute reports whether name is one of attributes or a native type like db.VarChar
+func knownAttribute(name string, attributes []string) bool {
+	return strings.HasPrefix(name, "db.") || slices.Contains(attributes, name)
+}
+
+// attributeSuggestion returns the attribute closest to name, written with prefix
+func attributeSuggestion(prefix, name string, attributes []string) string {
+	if suggestion := suggest(name, attributes); suggestion != "" {
+		return prefix + suggestion
+	}
+	return ""
+}
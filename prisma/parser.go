@@ -2,6 +2,7 @@ package prisma
 
 import (
 	"fmt"
+	"strings"
 )
 
 // Parser represents the parser
@@ -11,14 +12,13 @@ type Parser struct {
 	curToken  Token
 	peekToken Token
 
-	errors []string
+	errors ParseErrors
 }
 
 // New creates a new parser instance
 func NewParser(lexer *Lexer) *Parser {
 	p := &Parser{
-		lexer:  lexer,
-		errors: []string{},
+		lexer: lexer,
 	}
 
 	// Read two tokens, so curToken and peekToken are both set
@@ -36,6 +36,15 @@ func (p *Parser) nextToken() {
 
 // Errors returns parsing errors
 func (p *Parser) Errors() []string {
+	messages := make([]string, len(p.errors))
+	for i, err := range p.errors {
+		messages[i] = err.Error()
+	}
+	return messages
+}
+
+// ParseErrors returns parsing errors with their positions
+func (p *Parser) ParseErrors() ParseErrors {
 	return p.errors
 }
 
@@ -44,14 +53,29 @@ func (p *Parser) ParseSchema() *PrismaSchema {
 	schema := &PrismaSchema{}
 	schema.Statements = []Statement{}
 
+	var docLines []string
 	for p.curToken.Type != EOF {
-		// Skip comments
+		// Skip comments, keeping documentation comments for the next model or type
 		if p.curToken.Type == COMMENT {
+			if line, ok := docCommentLine(p.curToken); ok {
+				docLines = append(docLines, line)
+			}
 			p.nextToken()
 			continue
 		}
 
 		stmt := p.parseStatement()
+		switch stmt := stmt.(type) {
+		case *ModelStatement:
+			if stmt != nil {
+				stmt.Comment = strings.Join(docLines, "\n")
+			}
+		case *TypeStatement:
+			if stmt != nil {
+				stmt.Comment = strings.Join(docLines, "\n")
+			}
+		}
+		docLines = nil
 		if stmt != nil {
 			schema.Statements = append(schema.Statements, stmt)
 		}
@@ -72,15 +96,21 @@ func (p *Parser) parseStatement() Statement {
 		return p.parseDatasourceStatement()
 	case GENERATOR:
 		return p.parseGeneratorStatement()
+	case IDENT:
+		// "type" is not a keyword, so fields may still be named type
+		if p.curToken.Literal == "type" && p.peekToken.Type == IDENT {
+			return p.parseTypeStatement()
+		}
+		fallthrough
 	default:
-		p.addError(fmt.Sprintf("unexpected token %s at line %d", p.curToken.Type, p.curToken.Line))
+		p.addError(fmt.Sprintf("unexpected token %s", p.curToken.Type))
 		return nil
 	}
 }
 
 // parseModelStatement parses a model statement
 func (p *Parser) parseModelStatement() *ModelStatement {
-	stmt := &ModelStatement{}
+	stmt := &ModelStatement{Position: tokenPosition(p.curToken)}
 
 	if !p.expectPeek(IDENT) {
 		return nil
@@ -97,7 +127,31 @@ func (p *Parser) parseModelStatement() *ModelStatement {
 
 	// parseFields should have left us positioned right before RBRACE
 	if p.curToken.Type != RBRACE {
-		p.addError(fmt.Sprintf("expected }, got %s at line %d", p.curToken.Type, p.curToken.Line))
+		p.addError(fmt.Sprintf("expected }, got %s", p.curToken.Type))
+		return nil
+	}
+
+	return stmt
+}
+
+// parseTypeStatement parses a composite type statement
+func (p *Parser) parseTypeStatement() *TypeStatement {
+	stmt := &TypeStatement{Position: tokenPosition(p.curToken)}
+
+	if !p.expectPeek(IDENT) {
+		return nil
+	}
+
+	stmt.Name = p.curToken.Literal
+
+	if !p.expectPeek(LBRACE) {
+		return nil
+	}
+
+	stmt.Fields = p.parseFields()
+
+	if p.curToken.Type != RBRACE {
+		p.addError(fmt.Sprintf("expected }, got %s", p.curToken.Type))
 		return nil
 	}
 
@@ -106,7 +160,7 @@ func (p *Parser) parseModelStatement() *ModelStatement {
 
 // parseEnumStatement parses an enum statement
 func (p *Parser) parseEnumStatement() *EnumStatement {
-	stmt := &EnumStatement{}
+	stmt := &EnumStatement{Position: tokenPosition(p.curToken)}
 
 	if !p.expectPeek(IDENT) {
 		return nil
@@ -122,7 +176,7 @@ func (p *Parser) parseEnumStatement() *EnumStatement {
 
 	// parseEnumValues should have left us positioned at RBRACE
 	if p.curToken.Type != RBRACE {
-		p.addError(fmt.Sprintf("expected }, got %s at line %d", p.curToken.Type, p.curToken.Line))
+		p.addError(fmt.Sprintf("expected }, got %s", p.curToken.Type))
 		return nil
 	}
 
@@ -138,7 +192,7 @@ func (p *Parser) parseDatasourceStatement() *DatasourceStatement {
 	if p.curToken.Type == IDENT || p.curToken.Type == DB {
 		stmt.Name = p.curToken.Literal
 	} else {
-		p.addError(fmt.Sprintf("expected datasource name, got %s at line %d", p.curToken.Type, p.curToken.Line))
+		p.addError(fmt.Sprintf("expected datasource name, got %s", p.curToken.Type))
 		return nil
 	}
 
@@ -150,7 +204,7 @@ func (p *Parser) parseDatasourceStatement() *DatasourceStatement {
 
 	// parseProperties should have left us positioned at RBRACE
 	if p.curToken.Type != RBRACE {
-		p.addError(fmt.Sprintf("expected }, got %s at line %d", p.curToken.Type, p.curToken.Line))
+		p.addError(fmt.Sprintf("expected }, got %s", p.curToken.Type))
 		return nil
 	}
 
@@ -175,7 +229,7 @@ func (p *Parser) parseGeneratorStatement() *GeneratorStatement {
 
 	// parseProperties should have left us positioned at RBRACE
 	if p.curToken.Type != RBRACE {
-		p.addError(fmt.Sprintf("expected }, got %s at line %d", p.curToken.Type, p.curToken.Line))
+		p.addError(fmt.Sprintf("expected }, got %s", p.curToken.Type))
 		return nil
 	}
 
@@ -188,17 +242,28 @@ func (p *Parser) parseFields() []*Field {
 
 	p.nextToken()
 
+	var docLines []string
+	lastFieldLine := 0
 	for p.curToken.Type != RBRACE && p.curToken.Type != EOF {
 		if p.curToken.Type == BLOCK_AT {
 			// This is a block attribute, break to handle it separately
 			break
 		}
 
-		if p.curToken.Type == IDENT {
+		// Documentation comments belong to the next field, unless they trail a field on its line
+		if line, ok := docCommentLine(p.curToken); ok && p.curToken.Line != lastFieldLine {
+			docLines = append(docLines, line)
+		}
+
+		// Keywords such as updatedAt are also valid field names
+		if p.curToken.Type == IDENT || p.isAttributeKeyword(p.curToken.Type) {
+			lastFieldLine = p.curToken.Line
 			field := p.parseField()
 			if field != nil {
+				field.Comment = strings.Join(docLines, "\n")
 				fields = append(fields, field)
 			}
+			docLines = nil
 		}
 		p.nextToken()
 	}
@@ -208,7 +273,7 @@ func (p *Parser) parseFields() []*Field {
 
 // parseField parses a single field
 func (p *Parser) parseField() *Field {
-	field := &Field{}
+	field := &Field{Position: tokenPosition(p.curToken)}
 	field.Name = p.curToken.Literal
 
 	// Advance to the type token
@@ -218,7 +283,19 @@ func (p *Parser) parseField() *Field {
 		return nil
 	}
 
-	field.Type = &FieldType{Name: p.curToken.Literal}
+	field.Type = &FieldType{Position: tokenPosition(p.curToken), Name: p.curToken.Literal}
+
+	// Check for a union of models, the type of a polymorphic relation
+	if p.peekToken.Type == PIPE {
+		field.Type.Union = []string{field.Type.Name}
+		for p.peekToken.Type == PIPE {
+			p.nextToken() // consume '|'
+			if !p.expectPeek(IDENT) {
+				return nil
+			}
+			field.Type.Union = append(field.Type.Union, p.curToken.Literal)
+		}
+	}
 
 	// Check for array type
 	if p.peekToken.Type == LBRACKET {
@@ -247,11 +324,12 @@ func (p *Parser) parseAttributes() []*Attribute {
 
 	for p.peekToken.Type == AT {
 		p.nextToken() // consume '@'
+		position := tokenPosition(p.curToken)
 		p.nextToken() // advance to attribute name
 
 		// Handle both IDENT and keyword tokens as attribute names
 		if p.curToken.Type != IDENT && !p.isAttributeKeyword(p.curToken.Type) {
-			p.addError(fmt.Sprintf("expected attribute name, got %s at line %d", p.curToken.Type, p.curToken.Line))
+			p.addError(fmt.Sprintf("expected attribute name, got %s", p.curToken.Type))
 			break
 		}
 
@@ -262,13 +340,13 @@ func (p *Parser) parseAttributes() []*Attribute {
 			p.nextToken() // consume '.'
 			p.nextToken() // advance to property name
 			if p.curToken.Type != IDENT && !p.isAttributeKeyword(p.curToken.Type) {
-				p.addError(fmt.Sprintf("expected identifier after dot in attribute, got %s at line %d", p.curToken.Type, p.curToken.Line))
+				p.addError(fmt.Sprintf("expected identifier after dot in attribute, got %s", p.curToken.Type))
 				break
 			}
 			attrName += "." + p.curToken.Literal
 		}
 
-		attr := &Attribute{Name: attrName}
+		attr := &Attribute{Position: position, Name: attrName}
 
 		// Check for arguments
 		if p.peekToken.Type == LPAREN {
@@ -276,7 +354,7 @@ func (p *Parser) parseAttributes() []*Attribute {
 			attr.Args = p.parseArgumentList()
 			// parseArgumentList should leave us at the closing parenthesis
 			if p.curToken.Type != RPAREN {
-				p.addError(fmt.Sprintf("expected ), got %s at line %d", p.curToken.Type, p.curToken.Line))
+				p.addErrorAt(p.peekToken, fmt.Sprintf("expected ), got %s", p.peekToken.Type))
 				break
 			}
 		}
@@ -292,10 +370,11 @@ func (p *Parser) parseBlockAttributes() []*BlockAttribute {
 	attributes := []*BlockAttribute{}
 
 	for p.curToken.Type == BLOCK_AT {
+		position := tokenPosition(p.curToken)
 		p.nextToken() // advance to attribute name
 
 		if p.curToken.Type != IDENT && !p.isAttributeKeyword(p.curToken.Type) {
-			p.addError(fmt.Sprintf("expected block attribute name, got %s at line %d", p.curToken.Type, p.curToken.Line))
+			p.addError(fmt.Sprintf("expected block attribute name, got %s", p.curToken.Type))
 			break
 		}
 
@@ -306,13 +385,13 @@ func (p *Parser) parseBlockAttributes() []*BlockAttribute {
 			p.nextToken() // consume '.'
 			p.nextToken() // advance to property name
 			if p.curToken.Type != IDENT && !p.isAttributeKeyword(p.curToken.Type) {
-				p.addError(fmt.Sprintf("expected identifier after dot in block attribute, got %s at line %d", p.curToken.Type, p.curToken.Line))
+				p.addError(fmt.Sprintf("expected identifier after dot in block attribute, got %s", p.curToken.Type))
 				break
 			}
 			attrName += "." + p.curToken.Literal
 		}
 
-		attr := &BlockAttribute{Name: attrName}
+		attr := &BlockAttribute{Position: position, Name: attrName}
 
 		// Check for arguments
 		if p.peekToken.Type == LPAREN {
@@ -320,7 +399,7 @@ func (p *Parser) parseBlockAttributes() []*BlockAttribute {
 			attr.Args = p.parseArgumentList()
 			// parseArgumentList should leave us at the closing parenthesis
 			if p.curToken.Type != RPAREN {
-				p.addError(fmt.Sprintf("expected ), got %s at line %d", p.curToken.Type, p.curToken.Line))
+				p.addErrorAt(p.peekToken, fmt.Sprintf("expected ), got %s", p.peekToken.Type))
 				break
 			}
 		}
@@ -384,7 +463,7 @@ func (p *Parser) parseExpression() Expression {
 			p.nextToken() // consume '.'
 			p.nextToken() // advance to property name
 			if p.curToken.Type != IDENT {
-				p.addError(fmt.Sprintf("expected identifier after dot, got %s at line %d", p.curToken.Type, p.curToken.Line))
+				p.addError(fmt.Sprintf("expected identifier after dot, got %s", p.curToken.Type))
 				return nil
 			}
 
@@ -431,7 +510,7 @@ func (p *Parser) parseExpression() Expression {
 			p.nextToken() // consume '.'
 			p.nextToken() // advance to property name
 			if p.curToken.Type != IDENT {
-				p.addError(fmt.Sprintf("expected identifier after dot, got %s at line %d", p.curToken.Type, p.curToken.Line))
+				p.addError(fmt.Sprintf("expected identifier after dot, got %s", p.curToken.Type))
 				return nil
 			}
 
@@ -538,20 +617,28 @@ func (p *Parser) expectPeek(t TokenType) bool {
 
 // peekError adds an error for unexpected peek token
 func (p *Parser) peekError(t TokenType) {
-	msg := fmt.Sprintf("expected next token to be %s, got %s instead at line %d",
-		t, p.peekToken.Type, p.peekToken.Line)
-	p.errors = append(p.errors, msg)
+	p.addErrorAt(p.peekToken, fmt.Sprintf("expected next token to be %s, got %s instead", t, p.peekToken.Type))
 }
 
-// addError adds an error message
+// addError adds an error message at the current token
 func (p *Parser) addError(msg string) {
-	p.errors = append(p.errors, msg)
+	p.addErrorAt(p.curToken, msg)
+}
+
+// addErrorAt adds an error message at tok
+func (p *Parser) addErrorAt(tok Token, msg string) {
+	p.errors = append(p.errors, newParseError(p.lexer.input, tok.Line, tok.Column, msg))
+}
+
+// tokenPosition returns the position of tok
+func tokenPosition(tok Token) Position {
+	return Position{Line: tok.Line, Column: tok.Column}
 }
 
 // isTypeToken checks if token is a type token
 func (p *Parser) isTypeToken(tokenType TokenType) bool {
 	switch tokenType {
-	case INT, STRING_TYPE, BOOLEAN, DATETIME, JSON, FLOAT, DECIMAL:
+	case INT, STRING_TYPE, BOOLEAN, DATETIME, JSON, FLOAT, DECIMAL, BIGINT_TYPE:
 		return true
 	default:
 		return false
@@ -561,9 +648,18 @@ func (p *Parser) isTypeToken(tokenType TokenType) bool {
 // isAttributeKeyword checks if token can be used as an attribute name
 func (p *Parser) isAttributeKeyword(tokenType TokenType) bool {
 	switch tokenType {
-	case DEFAULT, AUTOINCREMENT, NOW, UUID, ENV, UPDATEDAT, CUID, DB, TEXT, VARCHAR, MONEY, JSONB, UUID_TYPE, TIMESTAMP, DATE_TYPE, TIME_TYPE, DECIMAL_TYPE, DOUBLEPRECISION, REAL, SMALLINT, BIGINT_TYPE, SERIAL, BIGSERIAL, CHAR, INET, BIT, VARBIT, XML, DECIMAL:
+	case DEFAULT, AUTOINCREMENT, NOW, UUID, ENV, UPDATEDAT, CUID, DB, TEXT, VARCHAR, MONEY, JSONB, UUID_TYPE, TIMESTAMP, DATE_TYPE, TIME_TYPE, DECIMAL_TYPE, DOUBLEPRECISION, REAL, SMALLINT, BIGINT_TYPE, SERIAL, BIGSERIAL, CHAR, INET, BIT, VARBIT, XML, DECIMAL, DATASOURCE:
 		return true
 	default:
 		return false
 	}
 }
+
+// docCommentLine returns the text of a /// documentation comment token
+func docCommentLine(tok Token) (string, bool) {
+	if tok.Type != COMMENT || !strings.HasPrefix(tok.Literal, "///") {
+		return "", false
+	}
+	text := strings.TrimPrefix(tok.Literal, "///")
+	return strings.TrimRight(strings.TrimPrefix(text, " "), " \t\r"), true
+}
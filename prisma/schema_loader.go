@@ -10,24 +10,123 @@ import (
 	"github.com/rediwo/redi-orm/schema"
 )
 
+// Definition holds everything declared in a Prisma schema besides models
+type Definition struct {
+	Schemas     map[string]*schema.Schema
+	Enums       map[string][]string
+	Datasource  *DatasourceStatement   // The first datasource block
+	Datasources []*DatasourceStatement // All datasource blocks, which models select with @@datasource
+	Generators  []*GeneratorStatement
+}
+
 // ParseSchema parses Prisma schema content and returns schemas map
 func ParseSchema(content string) (map[string]*schema.Schema, error) {
-	if strings.TrimSpace(content) == "" {
-		return nil, fmt.Errorf("schema content is empty")
+	def, err := ParseDefinition(content)
+	if err != nil {
+		return nil, err
 	}
+	return def.Schemas, nil
+}
 
-	// Create lexer and parser
-	lexer := NewLexer(content)
-	parser := NewParser(lexer)
+// ParseDefinition parses Prisma schema content and returns models together with enums,
+// datasource and generator blocks
+func ParseDefinition(content string) (*Definition, error) {
+	return parseDefinition(content, "")
+}
 
-	// Parse the schema
-	prismaSchema := parser.ParseSchema()
+// parseDefinition parses the content of a schema file, or schema content when file is empty
+func parseDefinition(content, file string) (*Definition, error) {
+	source, err := parseSource(content, file)
+	if err != nil {
+		return nil, err
+	}
+	return definitionFromSources([]sourceFile{source})
+}
+
+// definitionFromSources checks and converts parsed schema files into one Definition. Files
+// may use the models, enums and types declared in any of them.
+func definitionFromSources(sources []sourceFile) (*Definition, error) {
+	if err := checkNames(sources); err != nil {
+		return nil, err
+	}
+	merged, err := mergeSources(sources)
+	if err != nil {
+		return nil, err
+	}
+	return convertDefinition(merged)
+}
 
-	// Check for parsing errors
-	if errors := parser.Errors(); len(errors) > 0 {
-		return nil, fmt.Errorf("schema parsing errors: %v", errors)
+// mergeSources combines the statements of schema files in their order. A model, enum or
+// type declared again with the same definition, as by files sharing an enum, is kept once;
+// declared differently, it is an error naming both declarations.
+func mergeSources(sources []sourceFile) (*PrismaSchema, error) {
+	type declaration struct {
+		kind     string
+		location string
+		text     string
 	}
+	declared := make(map[string]declaration)
+	merged := &PrismaSchema{}
+	var errs ParseErrors
+	for _, source := range sources {
+		for _, stmt := range source.ast.Statements {
+			var kind, name string
+			var position Position
+			switch stmt := stmt.(type) {
+			case *ModelStatement:
+				kind, name, position = "model", stmt.Name, stmt.Position
+			case *EnumStatement:
+				kind, name, position = "enum", stmt.Name, stmt.Position
+			case *TypeStatement:
+				kind, name, position = "type", stmt.Name, stmt.Position
+			}
+			if name == "" {
+				merged.Statements = append(merged.Statements, stmt)
+				continue
+			}
+
+			location := fmt.Sprintf("%d:%d", position.Line, position.Column)
+			if source.name != "" {
+				location = source.name + ":" + location
+			}
+			text := stmt.String()
+			previous, exists := declared[name]
+			if !exists {
+				declared[name] = declaration{kind: kind, location: location, text: text}
+				merged.Statements = append(merged.Statements, stmt)
+				continue
+			}
+			if previous.kind != kind || previous.text != text {
+				err := newParseError(source.content, position.Line, position.Column,
+					fmt.Sprintf("%s %s conflicts with %s %s declared at %s", kind, name, previous.kind, name, previous.location))
+				err.File = source.name
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return merged, nil
+}
+
+// parseSource parses schema content into its AST. Syntax errors are returned as ParseErrors.
+func parseSource(content, file string) (sourceFile, error) {
+	if strings.TrimSpace(content) == "" {
+		return sourceFile{}, fmt.Errorf("schema content is empty")
+	}
+
+	parser := NewParser(NewLexer(content))
+	prismaSchema := parser.ParseSchema()
+	if errs := parser.ParseErrors(); len(errs) > 0 {
+		return sourceFile{}, errs.inFile(file)
+	}
+	return sourceFile{name: file, content: content, ast: prismaSchema}, nil
+}
 
+// convertDefinition converts the AST of a schema into a Definition
+func convertDefinition(prismaSchema *PrismaSchema) (*Definition, error) {
 	// Convert to ReORM schemas
 	converter := NewConverter()
 	schemas, err := converter.Convert(prismaSchema)
@@ -39,83 +138,116 @@ func ParseSchema(content string) (map[string]*schema.Schema, error) {
 		return nil, fmt.Errorf("no models found in schema")
 	}
 
-	return schemas, nil
+	def := &Definition{
+		Schemas:    schemas,
+		Enums:      converter.GetEnums(),
+		Datasource: converter.GetDatasource(),
+	}
+	for _, stmt := range prismaSchema.Statements {
+		switch stmt := stmt.(type) {
+		case *GeneratorStatement:
+			def.Generators = append(def.Generators, stmt)
+		case *DatasourceStatement:
+			def.Datasources = append(def.Datasources, stmt)
+		}
+	}
+	if err := def.applyRelationModes(); err != nil {
+		return nil, err
+	}
+
+	return def, nil
 }
 
 // ParseSchemaFile reads and parses Prisma schema file
 func ParseSchemaFile(filename string) (map[string]*schema.Schema, error) {
+	def, err := ParseDefinitionFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return def.Schemas, nil
+}
+
+// ParseDefinitionFile reads and parses a Prisma schema file into a Definition
+func ParseDefinitionFile(filename string) (*Definition, error) {
+	content, err := readSchemaFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return parseDefinition(content, filename)
+}
+
+// readSchemaFile returns the content of a Prisma schema file
+func readSchemaFile(filename string) (string, error) {
 	if filename == "" {
-		return nil, fmt.Errorf("filename is required")
+		return "", fmt.Errorf("filename is required")
 	}
 
 	// Open file
 	file, err := os.Open(filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open schema file %s: %w", filename, err)
+		return "", fmt.Errorf("failed to open schema file %s: %w", filename, err)
 	}
 	defer file.Close()
 
 	// Read file content
 	content, err := io.ReadAll(file)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read schema file %s: %w", filename, err)
+		return "", fmt.Errorf("failed to read schema file %s: %w", filename, err)
 	}
-
-	// Parse the content
-	return ParseSchema(string(content))
+	return string(content), nil
 }
 
 // LoadSchemaFromPath loads Prisma schemas from a file or directory
 // This function supports loading a single .prisma file or all .prisma files from a directory
 func LoadSchemaFromPath(path string) (map[string]*schema.Schema, error) {
+	def, err := LoadDefinitionFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return def.Schemas, nil
+}
+
+// LoadDefinitionFromPath loads a Prisma definition from a file or a directory of .prisma files
+func LoadDefinitionFromPath(path string) (*Definition, error) {
 	// Check if path exists
 	info, err := os.Stat(path)
 	if os.IsNotExist(err) {
 		return nil, fmt.Errorf("schema path not found: %s", path)
 	}
 
-	allSchemas := make(map[string]*schema.Schema)
-
-	// If it's a directory, load all .prisma files
-	if info.IsDir() {
-		entries, err := os.ReadDir(path)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read directory: %w", err)
-		}
+	// It's a single file
+	if !info.IsDir() {
+		return ParseDefinitionFile(path)
+	}
 
-		var prismaFiles []string
-		for _, entry := range entries {
-			if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".prisma") {
-				prismaFiles = append(prismaFiles, filepath.Join(path, entry.Name()))
-			}
-		}
+	// If it's a directory, load all .prisma files, in the order of their names
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
 
-		if len(prismaFiles) == 0 {
-			return nil, fmt.Errorf("no .prisma files found in directory: %s", path)
+	var prismaFiles []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".prisma") {
+			prismaFiles = append(prismaFiles, filepath.Join(path, entry.Name()))
 		}
+	}
 
-		for _, file := range prismaFiles {
-			schemas, err := ParseSchemaFile(file)
-			if err != nil {
-				return nil, fmt.Errorf("failed to load %s: %w", filepath.Base(file), err)
-			}
+	if len(prismaFiles) == 0 {
+		return nil, fmt.Errorf("no .prisma files found in directory: %s", path)
+	}
 
-			// Merge schemas
-			for name, schema := range schemas {
-				if _, exists := allSchemas[name]; exists {
-					return nil, fmt.Errorf("duplicate model '%s' found in %s", name, filepath.Base(file))
-				}
-				allSchemas[name] = schema
-			}
+	sources := make([]sourceFile, 0, len(prismaFiles))
+	for _, file := range prismaFiles {
+		content, err := readSchemaFile(file)
+		if err != nil {
+			return nil, err
 		}
-	} else {
-		// It's a single file
-		schemas, err := ParseSchemaFile(path)
+		source, err := parseSource(content, file)
 		if err != nil {
 			return nil, err
 		}
-		allSchemas = schemas
+		sources = append(sources, source)
 	}
-
-	return allSchemas, nil
+	return definitionFromSources(sources)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/rediwo/redi-orm/types"
 )
@@ -12,22 +13,23 @@ import (
 type AggregationQueryImpl struct {
 	*ModelQueryImpl
 	selectedFields    []string
-	aggregations      []aggregation
+	aggregations      []Aggregation
 	groupByFields     []string
 	havingCondition   types.Condition
 	orderByClauses    []OrderClause
-	aggregationOrders []aggregationOrder
+	aggregationOrders []AggregationOrder
 }
 
-// aggregation represents a single aggregation function
-type aggregation struct {
+// Aggregation represents a single aggregation function
+type Aggregation struct {
 	Type      string // "COUNT", "SUM", "AVG", "MIN", "MAX"
 	FieldName string // Field to aggregate on (empty for COUNT(*))
 	Alias     string // Alias for the result
+	Distinct  bool   // Aggregate distinct values only (COUNT(DISTINCT field))
 }
 
-// aggregationOrder represents ordering by an aggregation result
-type aggregationOrder struct {
+// AggregationOrder represents ordering by an aggregation result
+type AggregationOrder struct {
 	Type      string // "COUNT", "SUM", "AVG", "MIN", "MAX"
 	FieldName string
 	Direction types.Order
@@ -57,7 +59,7 @@ func (q *AggregationQueryImpl) Having(condition types.Condition) types.Aggregati
 // Count adds a COUNT aggregation
 func (q *AggregationQueryImpl) Count(fieldName string, alias string) types.AggregationQuery {
 	newQuery := q.clone()
-	newQuery.aggregations = append(newQuery.aggregations, aggregation{
+	newQuery.aggregations = append(newQuery.aggregations, Aggregation{
 		Type:      "COUNT",
 		FieldName: fieldName,
 		Alias:     alias,
@@ -65,10 +67,22 @@ func (q *AggregationQueryImpl) Count(fieldName string, alias string) types.Aggre
 	return newQuery
 }
 
+// CountDistinct adds a COUNT(DISTINCT field) aggregation
+func (q *AggregationQueryImpl) CountDistinct(fieldName string, alias string) types.AggregationQuery {
+	newQuery := q.clone()
+	newQuery.aggregations = append(newQuery.aggregations, Aggregation{
+		Type:      "COUNT",
+		FieldName: fieldName,
+		Alias:     alias,
+		Distinct:  true,
+	})
+	return newQuery
+}
+
 // CountAll adds a COUNT(*) aggregation
 func (q *AggregationQueryImpl) CountAll(alias string) types.AggregationQuery {
 	newQuery := q.clone()
-	newQuery.aggregations = append(newQuery.aggregations, aggregation{
+	newQuery.aggregations = append(newQuery.aggregations, Aggregation{
 		Type:      "COUNT",
 		FieldName: "",
 		Alias:     alias,
@@ -79,7 +93,7 @@ func (q *AggregationQueryImpl) CountAll(alias string) types.AggregationQuery {
 // Sum adds a SUM aggregation
 func (q *AggregationQueryImpl) Sum(fieldName string, alias string) types.AggregationQuery {
 	newQuery := q.clone()
-	newQuery.aggregations = append(newQuery.aggregations, aggregation{
+	newQuery.aggregations = append(newQuery.aggregations, Aggregation{
 		Type:      "SUM",
 		FieldName: fieldName,
 		Alias:     alias,
@@ -90,7 +104,7 @@ func (q *AggregationQueryImpl) Sum(fieldName string, alias string) types.Aggrega
 // Avg adds an AVG aggregation
 func (q *AggregationQueryImpl) Avg(fieldName string, alias string) types.AggregationQuery {
 	newQuery := q.clone()
-	newQuery.aggregations = append(newQuery.aggregations, aggregation{
+	newQuery.aggregations = append(newQuery.aggregations, Aggregation{
 		Type:      "AVG",
 		FieldName: fieldName,
 		Alias:     alias,
@@ -101,7 +115,7 @@ func (q *AggregationQueryImpl) Avg(fieldName string, alias string) types.Aggrega
 // Min adds a MIN aggregation
 func (q *AggregationQueryImpl) Min(fieldName string, alias string) types.AggregationQuery {
 	newQuery := q.clone()
-	newQuery.aggregations = append(newQuery.aggregations, aggregation{
+	newQuery.aggregations = append(newQuery.aggregations, Aggregation{
 		Type:      "MIN",
 		FieldName: fieldName,
 		Alias:     alias,
@@ -112,7 +126,7 @@ func (q *AggregationQueryImpl) Min(fieldName string, alias string) types.Aggrega
 // Max adds a MAX aggregation
 func (q *AggregationQueryImpl) Max(fieldName string, alias string) types.AggregationQuery {
 	newQuery := q.clone()
-	newQuery.aggregations = append(newQuery.aggregations, aggregation{
+	newQuery.aggregations = append(newQuery.aggregations, Aggregation{
 		Type:      "MAX",
 		FieldName: fieldName,
 		Alias:     alias,
@@ -152,7 +166,7 @@ func (q *AggregationQueryImpl) OrderBy(fieldName string, direction types.Order)
 // OrderByAggregation adds ordering by an aggregation result
 func (q *AggregationQueryImpl) OrderByAggregation(aggregationType string, fieldName string, direction types.Order) types.AggregationQuery {
 	newQuery := q.clone()
-	newQuery.aggregationOrders = append(newQuery.aggregationOrders, aggregationOrder{
+	newQuery.aggregationOrders = append(newQuery.aggregationOrders, AggregationOrder{
 		Type:      aggregationType,
 		FieldName: fieldName,
 		Direction: direction,
@@ -174,8 +188,19 @@ func (q *AggregationQueryImpl) Offset(offset int) types.AggregationQuery {
 	return newQuery
 }
 
+// WithTimeout cancels the query after timeout, overriding the default statement timeout of
+// the database
+func (q *AggregationQueryImpl) WithTimeout(timeout time.Duration) types.AggregationQuery {
+	newQuery := q.clone()
+	newQuery.timeout = timeout
+	return newQuery
+}
+
 // Exec executes the aggregation query
 func (q *AggregationQueryImpl) Exec(ctx context.Context, dest any) error {
+	ctx, cancel := q.StatementContext(ctx)
+	defer cancel()
+
 	sql, args, err := q.BuildSQL()
 	if err != nil {
 		return fmt.Errorf("failed to build SQL: %w", err)
@@ -186,6 +211,12 @@ func (q *AggregationQueryImpl) Exec(ctx context.Context, dest any) error {
 	return rawQuery.Find(ctx, dest)
 }
 
+// ToSQL returns the SQL of the query as the database runs it, with its arguments, without
+// executing it
+func (q *AggregationQueryImpl) ToSQL() (string, []any, error) {
+	return q.compileSQL(q.BuildSQL)
+}
+
 // BuildSQL builds the SQL query
 func (q *AggregationQueryImpl) BuildSQL() (string, []any, error) {
 	// Get table name from model
@@ -203,7 +234,7 @@ func (q *AggregationQueryImpl) BuildSQL() (string, []any, error) {
 		if err != nil {
 			return "", nil, fmt.Errorf("failed to map field %s: %w", field, err)
 		}
-		selectParts = append(selectParts, columnName)
+		selectParts = append(selectParts, q.quote(columnName))
 	}
 
 	// Add aggregations
@@ -211,13 +242,17 @@ func (q *AggregationQueryImpl) BuildSQL() (string, []any, error) {
 		var aggExpr string
 		if agg.FieldName == "" {
 			// COUNT(*)
-			aggExpr = fmt.Sprintf("COUNT(*) AS %s", agg.Alias)
+			aggExpr = fmt.Sprintf("COUNT(*) AS %s", q.quote(agg.Alias))
 		} else {
 			columnName, err := q.fieldMapper.SchemaToColumn(q.modelName, agg.FieldName)
 			if err != nil {
 				return "", nil, fmt.Errorf("failed to map field %s: %w", agg.FieldName, err)
 			}
-			aggExpr = fmt.Sprintf("%s(%s) AS %s", agg.Type, columnName, agg.Alias)
+			columnName = q.quote(columnName)
+			if agg.Distinct {
+				columnName = "DISTINCT " + columnName
+			}
+			aggExpr = fmt.Sprintf("%s(%s) AS %s", agg.Type, columnName, q.quote(agg.Alias))
 		}
 		selectParts = append(selectParts, aggExpr)
 	}
@@ -229,7 +264,7 @@ func (q *AggregationQueryImpl) BuildSQL() (string, []any, error) {
 	selectClause := fmt.Sprintf("SELECT %s", strings.Join(selectParts, ", "))
 
 	// Build FROM clause
-	fromClause := fmt.Sprintf("FROM %s", tableName)
+	fromClause := fmt.Sprintf("FROM %s", q.quote(tableName))
 
 	// Build WHERE clause
 	whereClause, args, err := q.buildWhereClause()
@@ -244,6 +279,9 @@ func (q *AggregationQueryImpl) BuildSQL() (string, []any, error) {
 		if err != nil {
 			return "", nil, fmt.Errorf("failed to map group by fields: %w", err)
 		}
+		for i, columnName := range columnNames {
+			columnNames[i] = q.quote(columnName)
+		}
 		groupByClause = fmt.Sprintf("GROUP BY %s", strings.Join(columnNames, ", "))
 	}
 
@@ -254,6 +292,7 @@ func (q *AggregationQueryImpl) BuildSQL() (string, []any, error) {
 		// Create condition context without table alias for aggregations
 		ctx := types.NewConditionContext(q.fieldMapper, q.modelName, "")
 		ctx.QuoteIdentifier = q.database.GetCapabilities().QuoteIdentifier
+		ctx.Capabilities = q.database.GetCapabilities()
 
 		sql, args := q.havingCondition.ToSQL(ctx)
 		if sql != "" {
@@ -297,8 +336,6 @@ func (q *AggregationQueryImpl) BuildSQL() (string, []any, error) {
 
 	sql := strings.Join(sqlParts, " ")
 	// Debug logging
-	// fmt.Printf("[DEBUG AggregationQuery] SQL: %s\n", sql)
-	// fmt.Printf("[DEBUG AggregationQuery] Args: %v\n", args)
 	return sql, args, nil
 }
 
@@ -311,6 +348,7 @@ func (q *AggregationQueryImpl) buildWhereClause() (string, []any, error) {
 	// Create condition context
 	ctx := types.NewConditionContext(q.fieldMapper, q.modelName, "")
 	ctx.QuoteIdentifier = q.database.GetCapabilities().QuoteIdentifier
+	ctx.Capabilities = q.database.GetCapabilities()
 
 	// Combine all conditions with AND
 	var conditionSQLs []string
@@ -328,10 +366,15 @@ func (q *AggregationQueryImpl) buildWhereClause() (string, []any, error) {
 		return "", nil, nil
 	}
 
-	whereSQL := fmt.Sprintf("WHERE %s", strings.Join(conditionSQLs, " AND "))
+	whereSQL := "WHERE " + joinConditionSQL(conditionSQLs)
 	return whereSQL, args, nil
 }
 
+// quote quotes an identifier for the database
+func (q *AggregationQueryImpl) quote(name string) string {
+	return q.database.GetCapabilities().QuoteIdentifier(name)
+}
+
 // buildOrderByClause builds the ORDER BY clause
 func (q *AggregationQueryImpl) buildOrderByClause() (string, error) {
 	var orderParts []string
@@ -348,7 +391,7 @@ func (q *AggregationQueryImpl) buildOrderByClause() (string, error) {
 			direction = "DESC"
 		}
 
-		orderParts = append(orderParts, fmt.Sprintf("%s %s", columnName, direction))
+		orderParts = append(orderParts, fmt.Sprintf("%s %s", q.quote(columnName), direction))
 	}
 
 	// Add aggregation ordering
@@ -362,7 +405,7 @@ func (q *AggregationQueryImpl) buildOrderByClause() (string, error) {
 			if err != nil {
 				return "", fmt.Errorf("failed to map field name %s: %w", aggOrder.FieldName, err)
 			}
-			aggExpr = fmt.Sprintf("%s(%s)", aggOrder.Type, columnName)
+			aggExpr = fmt.Sprintf("%s(%s)", aggOrder.Type, q.quote(columnName))
 		}
 
 		direction := "ASC"
@@ -406,16 +449,41 @@ func (q *AggregationQueryImpl) GetModelName() string {
 	return q.modelName
 }
 
+// GetAggregations returns the aggregation functions
+func (q *AggregationQueryImpl) GetAggregations() []Aggregation {
+	return q.aggregations
+}
+
+// GetGroupBy returns the grouping fields
+func (q *AggregationQueryImpl) GetGroupBy() []string {
+	return q.groupByFields
+}
+
+// GetHaving returns the having condition
+func (q *AggregationQueryImpl) GetHaving() types.Condition {
+	return q.havingCondition
+}
+
+// GetOrderBy returns the field ordering
+func (q *AggregationQueryImpl) GetOrderBy() []OrderClause {
+	return q.orderByClauses
+}
+
+// GetAggregationOrders returns the ordering by aggregation results
+func (q *AggregationQueryImpl) GetAggregationOrders() []AggregationOrder {
+	return q.aggregationOrders
+}
+
 // clone creates a copy of the aggregation query
 func (q *AggregationQueryImpl) clone() *AggregationQueryImpl {
 	newQuery := &AggregationQueryImpl{
 		ModelQueryImpl:    q.ModelQueryImpl.clone(),
 		selectedFields:    append([]string{}, q.selectedFields...),
-		aggregations:      append([]aggregation{}, q.aggregations...),
+		aggregations:      append([]Aggregation{}, q.aggregations...),
 		groupByFields:     append([]string{}, q.groupByFields...),
 		havingCondition:   q.havingCondition,
 		orderByClauses:    append([]OrderClause{}, q.orderByClauses...),
-		aggregationOrders: append([]aggregationOrder{}, q.aggregationOrders...),
+		aggregationOrders: append([]AggregationOrder{}, q.aggregationOrders...),
 	}
 	return newQuery
 }
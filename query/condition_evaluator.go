@@ -3,6 +3,7 @@ package query
 import (
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/rediwo/redi-orm/types"
 	"github.com/rediwo/redi-orm/utils"
@@ -36,6 +37,8 @@ func (ce *ConditionEvaluator) Evaluate(condition types.Condition, record map[str
 		return ce.evaluateNotCondition(cond, record, modelName)
 	case *types.MappedFieldCondition:
 		return ce.evaluateMappedFieldCondition(cond, record, modelName)
+	case *types.ArrayFieldCondition:
+		return ce.evaluateArrayFieldCondition(cond, record)
 	case *types.BaseCondition:
 		// For base conditions, we need to parse the SQL
 		return ce.evaluateBaseCondition(cond, record, modelName)
@@ -97,6 +100,49 @@ func (ce *ConditionEvaluator) evaluateMappedFieldCondition(cond *types.MappedFie
 	return ce.evaluateOperator(fieldValue, operator, value)
 }
 
+// evaluateArrayFieldCondition evaluates a list filter. NULL lists match no list filter.
+func (ce *ConditionEvaluator) evaluateArrayFieldCondition(cond *types.ArrayFieldCondition, record map[string]any) bool {
+	elements, ok := record[cond.FieldName].([]any)
+	if !ok {
+		return false
+	}
+
+	contains := func(value any) bool {
+		for _, element := range elements {
+			if ce.compareValues(element, value) == 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	values, _ := cond.Value.([]any)
+	switch cond.Operator {
+	case "has":
+		return contains(cond.Value)
+	case "hasSome":
+		for _, value := range values {
+			if contains(value) {
+				return true
+			}
+		}
+		return false
+	case "hasEvery":
+		for _, value := range values {
+			if !contains(value) {
+				return false
+			}
+		}
+		return true
+	case "isEmpty":
+		return len(elements) == 0
+	case "isNotEmpty":
+		return len(elements) > 0
+	default:
+		return true // Unknown operator - be permissive
+	}
+}
+
 // evaluateBaseCondition evaluates a base condition
 func (ce *ConditionEvaluator) evaluateBaseCondition(cond *types.BaseCondition, record map[string]any, modelName string) bool {
 	// For base conditions, we need to parse the SQL
@@ -141,6 +187,7 @@ func (ce *ConditionEvaluator) parseFieldCondition(sql string, args []any) (field
 		{`^(\w+)\s*<=\s*\?$`, 1, "<=", true},
 		{`^(\w+)\s+LIKE\s+\?$`, 1, "LIKE", true},
 		{`^(\w+)\s+NOT\s+LIKE\s+\?$`, 1, "NOT LIKE", true},
+		{`^(\w+)\s+LIKE\s+\?\s+ESCAPE\s+'!'$`, 1, "LIKE ESCAPE", true},
 		{`^(\w+)\s+IS\s+NULL$`, 1, "IS NULL", false},
 		{`^(\w+)\s+IS\s+NOT\s+NULL$`, 1, "IS NOT NULL", false},
 		{`^(\w+)\s+IN\s+\(\?\)$`, 1, "IN", true},
@@ -203,23 +250,15 @@ func (ce *ConditionEvaluator) evaluateOperator(fieldValue any, operator string,
 		}
 		return true
 	case "LIKE":
-		// Simple pattern matching
-		pattern := utils.ToString(value)
-		text := utils.ToString(fieldValue)
-		// Convert SQL LIKE pattern to Go pattern
-		// % -> .*
-		// _ -> .
-		pattern = strings.ReplaceAll(pattern, "%", ".*")
-		pattern = strings.ReplaceAll(pattern, "_", ".")
-		matched, _ := regexp.MatchString("^"+pattern+"$", text)
+		matched, _ := regexp.MatchString(utils.LikeToRegexp(utils.ToString(value), 0), utils.ToString(fieldValue))
+		return matched
+	case "LIKE ESCAPE":
+		// Patterns of string filters, with wildcards of the value escaped
+		matched, _ := regexp.MatchString(utils.LikeToRegexp(utils.ToString(value), utils.LikeEscape), utils.ToString(fieldValue))
 		return matched
 	case "NOT LIKE":
 		// Inverse of LIKE
-		pattern := utils.ToString(value)
-		text := utils.ToString(fieldValue)
-		pattern = strings.ReplaceAll(pattern, "%", ".*")
-		pattern = strings.ReplaceAll(pattern, "_", ".")
-		matched, _ := regexp.MatchString("^"+pattern+"$", text)
+		matched, _ := regexp.MatchString(utils.LikeToRegexp(utils.ToString(value), 0), utils.ToString(fieldValue))
 		return !matched
 	case "IS NULL":
 		return fieldValue == nil
@@ -293,6 +332,10 @@ func (ce *ConditionEvaluator) compareValues(a, b any) int {
 			return 1
 		}
 		return 0
+	case time.Time:
+		if vb, ok := b.(time.Time); ok {
+			return va.Compare(vb)
+		}
 	}
 
 	// Fall back to string comparison
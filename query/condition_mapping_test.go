@@ -89,7 +89,7 @@ func TestConditionFieldMapping(t *testing.T) {
 				cond3 := types.NewFieldCondition("User", "emailAddress").Contains("example")
 				return cond1.And(cond2).Or(cond3)
 			},
-			expectedSQL:  "((u.first_name = ?) AND (u.last_name = ?)) OR (u.email LIKE ?)",
+			expectedSQL:  "((u.first_name = ?) AND (u.last_name = ?)) OR (u.email LIKE ? ESCAPE '!')",
 			expectedArgs: []any{"John", "Doe", "%example%"},
 		},
 		{
@@ -181,3 +181,54 @@ func (m *testFieldMapper) MapColumnToSchemaData(modelName string, data map[strin
 func (m *testFieldMapper) ModelToTable(modelName string) (string, error) {
 	return strings.ToLower(modelName) + "s", nil
 }
+
+// TestArrayConditionMapping tests that list filters map field names and use the driver SQL
+func TestArrayConditionMapping(t *testing.T) {
+	mapper := &testFieldMapper{
+		mappings: map[string]map[string]string{
+			"User": {"roleNames": "role_names"},
+		},
+	}
+	field := types.NewFieldCondition("User", "roleNames")
+
+	tests := []struct {
+		name         string
+		cond         types.Condition
+		expectedSQL  string
+		expectedArgs []any
+	}{
+		{"has", field.Has("admin"), "? = ANY(u.role_names)", []any{"admin"}},
+		{"hasSome", field.HasSome("admin", "staff"), "u.role_names && ?", []any{`["admin","staff"]`}},
+		{"hasEvery", field.HasEvery("admin"), "u.role_names @> ?", []any{`["admin"]`}},
+		{"hasSome without values", field.HasSome(), "1 = 0", nil},
+		{"hasEvery without values", field.HasEvery(), "u.role_names IS NOT NULL", nil},
+		{"isEmpty", field.IsEmpty(), "cardinality(u.role_names) = 0", nil},
+		{"isNotEmpty", field.IsNotEmpty(), "cardinality(u.role_names) > 0", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := types.NewConditionContext(mapper, "User", "u")
+			ctx.Capabilities = &mockCapabilities{}
+
+			sql, args := tt.cond.ToSQL(ctx)
+			if sql != tt.expectedSQL {
+				t.Errorf("SQL mismatch\nGot:      %s\nExpected: %s", sql, tt.expectedSQL)
+			}
+			if len(args) != len(tt.expectedArgs) {
+				t.Fatalf("Args length mismatch: got %v, expected %v", args, tt.expectedArgs)
+			}
+			for i, arg := range args {
+				if arg != tt.expectedArgs[i] {
+					t.Errorf("Arg[%d] mismatch: got %v, expected %v", i, arg, tt.expectedArgs[i])
+				}
+			}
+		})
+	}
+
+	// Without capabilities the operator is left in the SQL for the database to reject
+	sql, _ := field.Has("admin").ToSQL(types.NewConditionContext(mapper, "User", ""))
+	if sql != "role_names has ?" {
+		t.Errorf("Unexpected SQL without capabilities: %s", sql)
+	}
+}
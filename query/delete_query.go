@@ -43,13 +43,47 @@ func (q *DeleteQueryImpl) Returning(fieldNames ...string) types.DeleteQuery {
 	return newQuery
 }
 
-// Exec executes the delete query
+// Exec executes the delete query. Relations of models in the prisma relation mode referencing
+// the deleted records get their onDelete actions applied first, in the same transaction.
 func (q *DeleteQueryImpl) Exec(ctx context.Context) (types.Result, error) {
+	ctx, cancel := q.StatementContext(ctx)
+	defer cancel()
+	defer types.ForgetDeduplicated(ctx)
+
 	sql, args, err := q.BuildSQL()
 	if err != nil {
 		return types.Result{}, fmt.Errorf("failed to build SQL: %w", err)
 	}
 
+	// Deletes of IN lists longer than the driver allows run in a transaction, one per chunk
+	queries, err := q.splitQueries(args)
+	if err != nil {
+		return types.Result{}, err
+	}
+	if relations := emulatedRelations(q.database, q.modelName); len(relations) > 0 || len(queries) > 1 {
+		var result types.Result
+		err := q.database.Transaction(ctx, func(tx types.Transaction) error {
+			for _, query := range queries {
+				if len(relations) > 0 {
+					if err := applyOnDelete(ctx, tx, q.database, q.modelName, query.condition(), relations); err != nil {
+						return err
+					}
+				}
+				sql, args, err := query.BuildSQL()
+				if err != nil {
+					return fmt.Errorf("failed to build SQL: %w", err)
+				}
+				r, err := tx.Raw(sql, args...).Exec(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to execute delete: %w", err)
+				}
+				result.RowsAffected += r.RowsAffected
+			}
+			return nil
+		})
+		return result, err
+	}
+
 	rawQuery := q.database.Raw(sql, args...)
 	result, err := rawQuery.Exec(ctx)
 	if err != nil {
@@ -59,6 +93,12 @@ func (q *DeleteQueryImpl) Exec(ctx context.Context) (types.Result, error) {
 	return result, nil
 }
 
+// ToSQL returns the SQL of the query as the database runs it, with its arguments, without
+// executing it
+func (q *DeleteQueryImpl) ToSQL() (string, []any, error) {
+	return q.compileSQL(q.BuildSQL)
+}
+
 // BuildSQL builds the delete SQL query
 func (q *DeleteQueryImpl) BuildSQL() (string, []any, error) {
 	// Get table name
@@ -70,7 +110,7 @@ func (q *DeleteQueryImpl) BuildSQL() (string, []any, error) {
 	var sql strings.Builder
 	var args []any
 
-	sql.WriteString(fmt.Sprintf("DELETE FROM %s", tableName))
+	sql.WriteString(fmt.Sprintf("DELETE FROM %s", q.database.GetCapabilities().QuoteIdentifier(tableName)))
 
 	// Build WHERE clause
 	allConditions := append(q.conditions, q.whereConditions...)
@@ -107,6 +147,7 @@ func (q *DeleteQueryImpl) buildWhereClause(conditions []types.Condition) (string
 	// Create condition context (no table alias for DELETE)
 	ctx := types.NewConditionContext(q.fieldMapper, q.modelName, "")
 	ctx.QuoteIdentifier = q.database.GetCapabilities().QuoteIdentifier
+	ctx.Capabilities = q.database.GetCapabilities()
 
 	var conditionSQLs []string
 	var args []any
@@ -123,10 +164,23 @@ func (q *DeleteQueryImpl) buildWhereClause(conditions []types.Condition) (string
 		return "", nil, nil
 	}
 
-	whereSQL := fmt.Sprintf("WHERE %s", strings.Join(conditionSQLs, " AND "))
+	whereSQL := "WHERE " + joinConditionSQL(conditionSQLs)
 	return whereSQL, args, nil
 }
 
+// condition returns the conditions of the delete combined, or nil without any
+func (q *DeleteQueryImpl) condition() types.Condition {
+	var combined types.Condition
+	for _, cond := range append(append([]types.Condition{}, q.conditions...), q.whereConditions...) {
+		if combined == nil {
+			combined = cond
+		} else {
+			combined = combined.And(cond)
+		}
+	}
+	return combined
+}
+
 // GetWhereConditions returns the where conditions
 func (q *DeleteQueryImpl) GetWhereConditions() []types.Condition {
 	return q.whereConditions
@@ -184,7 +184,7 @@ func TestDeleteQuery_BuildSQL(t *testing.T) {
 				&deleteMockCondition{field: "id", value: "123"},
 			},
 			driverType:    "mysql",
-			wantSQL:       "DELETE FROM users WHERE",
+			wantSQL:       "DELETE FROM `users` WHERE",
 			wantArgsCount: 1,
 		},
 		{
@@ -195,7 +195,7 @@ func TestDeleteQuery_BuildSQL(t *testing.T) {
 				&deleteMockCondition{field: "name", value: "John"},
 			},
 			driverType:    "postgresql",
-			wantSQL:       "DELETE FROM users WHERE",
+			wantSQL:       "DELETE FROM `users` WHERE",
 			wantArgsCount: 2,
 		},
 	}
@@ -0,0 +1,256 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
+)
+
+// encodeFieldValue converts a value to the form its field is stored in. Binary values given
+// as base64 text become []byte, decimals are bound as their exact text and 64-bit integers
+// as int64, so that neither goes through float64. DateTime values, given as time.Time or
+// text, are bound in UTC; @db.Date and @db.Time fields get the UTC date or time of day as
+// text. JSON values given as objects or lists are bound as their JSON text. Scalar lists are encoded with the list encoding of the driver so that they are
+// bound as one parameter. Values of other fields, NULL and lists that are already encoded
+// are returned as they are.
+func encodeFieldValue(database types.Database, modelName, fieldName string, value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	modelSchema, err := database.GetModelSchema(modelName)
+	if err != nil {
+		return value, nil
+	}
+	field, err := modelSchema.GetField(fieldName)
+	if err != nil {
+		return value, nil
+	}
+
+	switch field.Type {
+	case schema.FieldTypeBytes:
+		data, err := utils.DecodeBytes(value)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", fieldName, err)
+		}
+		return data, nil
+	case schema.FieldTypeDecimal:
+		text, err := utils.ToDecimalString(value)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", fieldName, err)
+		}
+		return text, nil
+	case schema.FieldTypeInt64:
+		n, err := utils.ToBigInt(value)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", fieldName, err)
+		}
+		return n, nil
+	case schema.FieldTypeDateTime:
+		t, err := utils.ToTime(value)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", fieldName, err)
+		}
+		switch field.NativeType() {
+		case "Date":
+			return t.UTC().Format(utils.DateLayout), nil
+		case "Time":
+			return t.UTC().Format(utils.TimeOfDayLayout), nil
+		}
+		return t.UTC(), nil
+	case schema.FieldTypeJSON:
+		switch value.(type) {
+		case string, []byte:
+			// Text is stored as it is, as JSON written by the caller
+			return value, nil
+		}
+		text, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", fieldName, err)
+		}
+		return string(text), nil
+	}
+	if !schema.IsArrayFieldType(field.Type) {
+		return value, nil
+	}
+	values, ok := listValues(value)
+	if !ok {
+		return value, nil
+	}
+	return database.GetCapabilities().EncodeArrayValue(values)
+}
+
+// WithListDefaults returns data with the scalar list fields it leaves out set to their
+// @default, or to an empty list, as lists are never NULL. The original map is never
+// modified, and data other than maps is returned unchanged.
+func WithListDefaults(database types.Database, modelName string, data any) any {
+	dataMap, ok := data.(map[string]any)
+	if !ok {
+		return data
+	}
+	modelSchema, err := database.GetModelSchema(modelName)
+	if err != nil {
+		return data
+	}
+
+	result := dataMap
+	cloned := false
+	for _, field := range modelSchema.Fields {
+		if !schema.IsArrayFieldType(field.Type) {
+			continue
+		}
+		if _, ok := dataMap[field.Name]; ok {
+			continue
+		}
+		if !cloned {
+			result = make(map[string]any, len(dataMap)+1)
+			maps.Copy(result, dataMap)
+			cloned = true
+		}
+		if list, ok := field.Default.([]any); ok {
+			result[field.Name] = slices.Clone(list)
+		} else {
+			result[field.Name] = []any{}
+		}
+	}
+	return result
+}
+
+// encodeFieldValues is encodeFieldValue for data given as parallel field and value lists.
+// The given values are not modified.
+func encodeFieldValues(database types.Database, modelName string, fields []string, values []any) ([]any, error) {
+	encoded := make([]any, len(values))
+	for i, value := range values {
+		var err error
+		if encoded[i], err = encodeFieldValue(database, modelName, fields[i], value); err != nil {
+			return nil, err
+		}
+	}
+	return encoded, nil
+}
+
+// decodeFieldValue converts a column read from the database to the value of its field.
+// Binary columns are returned as []byte, decimal columns as their exact text and 64-bit
+// integer columns as int64. DateTime columns are returned as UTC times, at midnight for
+// @db.Date fields and on 1970-01-01 for @db.Time fields. Scalar list columns are decoded into a list of the element
+// type of the field. Values of other fields, and values that cannot be converted, are
+// returned as they are.
+func decodeFieldValue(capabilities types.DriverCapabilities, modelSchema *schema.Schema, fieldName string, value any) any {
+	if value == nil || modelSchema == nil {
+		return value
+	}
+	field, err := modelSchema.GetField(fieldName)
+	if err != nil {
+		return value
+	}
+
+	switch field.Type {
+	case schema.FieldTypeBytes:
+		// Scanned []byte values are turned into strings before their field is known
+		if s, ok := value.(string); ok {
+			return []byte(s)
+		}
+		return value
+	case schema.FieldTypeDecimal:
+		if text, err := utils.ToDecimalString(value); err == nil {
+			return text
+		}
+		return value
+	case schema.FieldTypeInt64:
+		if n, err := utils.ToBigInt(value); err == nil {
+			return n
+		}
+		return value
+	case schema.FieldTypeDateTime:
+		t, err := utils.ToTime(value)
+		if err != nil {
+			return value
+		}
+		switch field.NativeType() {
+		case "Date":
+			return utils.UTCDate(t)
+		case "Time":
+			return utils.UTCTimeOfDay(t)
+		}
+		return t.UTC()
+	}
+	if !schema.IsArrayFieldType(field.Type) || capabilities == nil {
+		return value
+	}
+	values, err := capabilities.DecodeArrayValue(value)
+	if err != nil {
+		return value
+	}
+
+	elementType := schema.ArrayElementType(field.Type)
+	for i, element := range values {
+		values[i] = convertArrayElement(elementType, element)
+	}
+	return values
+}
+
+// decodeRecordValues decodes, in place, the columns of a record read with a raw query,
+// which is keyed by column names
+func decodeRecordValues(database types.Database, modelName string, record map[string]any) {
+	modelSchema, err := database.GetSchema(modelName)
+	if err != nil {
+		return
+	}
+	for column, value := range record {
+		fieldName, err := modelSchema.GetFieldNameByColumnName(column)
+		if err != nil {
+			fieldName = column
+		}
+		record[column] = decodeFieldValue(database.GetCapabilities(), modelSchema, fieldName, value)
+	}
+}
+
+// convertArrayElement converts a decoded list element, which may be a string or a JSON
+// number, to the element type of the list
+func convertArrayElement(elementType schema.FieldType, value any) any {
+	if value == nil {
+		return nil
+	}
+	switch elementType {
+	case schema.FieldTypeInt, schema.FieldTypeInt64:
+		return utils.ToInt64(value)
+	case schema.FieldTypeFloat:
+		return utils.ToFloat64(value)
+	case schema.FieldTypeDecimal:
+		if text, err := utils.ToDecimalString(value); err == nil {
+			return text
+		}
+	case schema.FieldTypeBool:
+		// PostgreSQL writes booleans in arrays as t and f
+		if value == "t" {
+			return true
+		}
+		return utils.ToBool(value)
+	case schema.FieldTypeDateTime:
+		if t, err := utils.ToTime(value); err == nil {
+			return t
+		}
+	}
+	return value
+}
+
+// listValues returns the elements of a slice value; []byte is not a list
+func listValues(value any) ([]any, bool) {
+	if values, ok := value.([]any); ok {
+		return values, true
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() == reflect.Uint8 {
+		return nil, false
+	}
+	values := make([]any, rv.Len())
+	for i := range values {
+		values[i] = rv.Index(i).Interface()
+	}
+	return values, true
+}
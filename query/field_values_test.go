@@ -0,0 +1,119 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rediwo/redi-orm/schema"
+)
+
+func newArrayMockDatabase() *mockDatabase {
+	mockDB := &mockDatabase{}
+	mockDB.RegisterSchema("Post", schema.New("Post").
+		AddField(schema.Field{Name: "id", Type: schema.FieldTypeInt, PrimaryKey: true}).
+		AddField(schema.Field{Name: "title", Type: schema.FieldTypeString}).
+		AddField(schema.Field{Name: "tags", Type: schema.FieldTypeStringArray, Nullable: true}).
+		AddField(schema.Field{Name: "scores", Type: schema.FieldTypeIntArray}).
+		AddField(schema.Field{Name: "flags", Type: schema.FieldTypeBoolArray}).
+		AddField(schema.Field{Name: "dates", Type: schema.FieldTypeDateTimeArray}).
+		AddField(schema.Field{Name: "cover", Type: schema.FieldTypeBytes, Nullable: true}).
+		AddField(schema.Field{Name: "price", Type: schema.FieldTypeDecimal, Nullable: true}).
+		AddField(schema.Field{Name: "views", Type: schema.FieldTypeInt64, Nullable: true}).
+		AddField(schema.Field{Name: "meta", Type: schema.FieldTypeJSON, Nullable: true}))
+	return mockDB
+}
+
+func TestEncodeFieldValues(t *testing.T) {
+	mockDB := newArrayMockDatabase()
+
+	fields := []string{"title", "tags", "scores", "id", "cover", "price", "views", "meta"}
+	values := []any{"Hello", []any{"go", "orm"}, []int{1, 2}, 1, "AAH/", 19.99, "9007199254740993", map[string]any{"draft": true}}
+	encoded, err := encodeFieldValues(mockDB, "Post", fields, values)
+	if err != nil {
+		t.Fatalf("encodeFieldValues() unexpected error: %v", err)
+	}
+
+	want := []any{"Hello", `["go","orm"]`, `[1,2]`, 1, []byte{0, 1, 255}, "19.99", int64(9007199254740993), `{"draft":true}`}
+	if !reflect.DeepEqual(encoded, want) {
+		t.Errorf("encodeFieldValues() = %#v, want %#v", encoded, want)
+	}
+	if _, ok := values[1].([]any); !ok {
+		t.Error("encodeFieldValues() modified the given values")
+	}
+
+	// NULL lists and values that are already encoded are kept
+	encoded, err = encodeFieldValues(mockDB, "Post", []string{"tags", "scores", "meta"}, []any{nil, "[3]", `{"a":1}`})
+	if err != nil {
+		t.Fatalf("encodeFieldValues() unexpected error: %v", err)
+	}
+	if encoded[0] != nil || encoded[1] != "[3]" || encoded[2] != `{"a":1}` {
+		t.Errorf("encodeFieldValues() = %#v, want NULL and the encoded list unchanged", encoded)
+	}
+
+	if _, err := encodeFieldValues(mockDB, "Post", []string{"cover"}, []any{"not base64!"}); err == nil {
+		t.Error("encodeFieldValues() expected an error for invalid base64 data")
+	}
+	if _, err := encodeFieldValues(mockDB, "Post", []string{"price"}, []any{"ten"}); err == nil {
+		t.Error("encodeFieldValues() expected an error for an invalid decimal")
+	}
+}
+
+func TestDecodeFieldValue(t *testing.T) {
+	mockDB := newArrayMockDatabase()
+	postSchema, _ := mockDB.GetSchema("Post")
+	caps := &mockCapabilities{}
+
+	tests := []struct {
+		name  string
+		field string
+		value any
+		want  any
+	}{
+		{"string list", "tags", `["go","orm"]`, []any{"go", "orm"}},
+		{"int list", "scores", `[1,2]`, []any{int64(1), int64(2)}},
+		{"bool list", "flags", `[true,"t",false]`, []any{true, true, false}},
+		{"datetime list", "dates", `["2024-01-02T03:04:05Z"]`, []any{time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}},
+		{"NULL list", "tags", nil, nil},
+		{"bytes", "cover", []byte{0, 1, 255}, []byte{0, 1, 255}},
+		{"scanned bytes", "cover", string([]byte{0, 1, 255}), []byte{0, 1, 255}},
+		{"decimal text", "price", "12345678901234567.89", "12345678901234567.89"},
+		{"decimal float", "price", 19.99, "19.99"},
+		{"decimal integer", "price", int64(20), "20"},
+		{"bigint text", "views", "9007199254740993", int64(9007199254740993)},
+		{"other field", "title", `["not","a","list"]`, `["not","a","list"]`},
+		{"undecodable value", "tags", "oops", "oops"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decodeFieldValue(caps, postSchema, tt.field, tt.value)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeFieldValue() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateQuery_BuildSQL_ArrayFields(t *testing.T) {
+	mockDB := newArrayMockDatabase()
+	mapper := &testFieldMapper{}
+
+	query := NewUpdateQuery(&ModelQueryImpl{
+		database:    mockDB,
+		modelName:   "Post",
+		fieldMapper: mapper,
+	}, map[string]any{"scores": []any{4, 5}}).Push("tags", "db", "sql")
+
+	sql, args, err := query.BuildSQL()
+	if err != nil {
+		t.Fatalf("BuildSQL() unexpected error: %v", err)
+	}
+	want := "UPDATE `posts` SET `scores` = ?, `tags` = array_cat(COALESCE(`tags`, '{}'), ?)"
+	if sql != want {
+		t.Errorf("BuildSQL() SQL = %q, want %q", sql, want)
+	}
+	if !reflect.DeepEqual(args, []any{`[4,5]`, `["db","sql"]`}) {
+		t.Errorf("BuildSQL() args = %#v, want the encoded lists", args)
+	}
+}
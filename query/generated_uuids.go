@@ -0,0 +1,80 @@
+package query
+
+import (
+	"maps"
+
+	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
+)
+
+// WithGeneratedUUIDs returns data with the fields of the model defaulting to generated UUIDs
+// (@default(uuid())) set to new ones. Fields the data sets explicitly are kept, and data other
+// than maps is returned unchanged. The original map is never modified.
+func WithGeneratedUUIDs(database types.Database, modelName string, data any) any {
+	dataMap, ok := data.(map[string]any)
+	if !ok {
+		return data
+	}
+	modelSchema, err := database.GetModelSchema(modelName)
+	if err != nil {
+		return data
+	}
+
+	result := dataMap
+	cloned := false
+	for _, field := range modelSchema.GetGeneratedUUIDFields() {
+		if value, ok := dataMap[field]; ok && value != nil {
+			continue
+		}
+		if !cloned {
+			result = make(map[string]any, len(dataMap)+1)
+			maps.Copy(result, dataMap)
+			cloned = true
+		}
+		result[field] = utils.NewUUID()
+	}
+	return result
+}
+
+// withGeneratedUUIDs returns the insert with the UUIDs generated by the model set in its rows,
+// generated once for the statements of an execution
+func (q *InsertQueryImpl) withGeneratedUUIDs() *InsertQueryImpl {
+	generated := q.clone()
+	generated.data = make([]any, len(q.data))
+	for i, item := range q.data {
+		generated.data[i] = WithGeneratedUUIDs(q.database, q.modelName, item)
+	}
+	return generated
+}
+
+// withInsertedIDs returns result with the UUIDs generated for the primary keys of the rows,
+// once they are all inserted
+func (q *InsertQueryImpl) withInsertedIDs(result types.Result) types.Result {
+	if ids := q.generatedIDs(); ids != nil && result.RowsAffected == int64(len(ids)) {
+		result.InsertedIDs = ids
+	}
+	return result
+}
+
+// generatedIDs returns the primary keys of the rows of the insert when the model generates
+// them as UUIDs, or nil when the database does
+func (q *InsertQueryImpl) generatedIDs() []any {
+	modelSchema, err := q.database.GetModelSchema(q.modelName)
+	if err != nil || len(modelSchema.CompositeKey) > 0 {
+		return nil
+	}
+	primaryKey, err := modelSchema.GetPrimaryKey()
+	if err != nil || !primaryKey.GeneratesUUID() {
+		return nil
+	}
+
+	ids := make([]any, 0, len(q.data))
+	for _, item := range q.data {
+		data, ok := item.(map[string]any)
+		if !ok {
+			return nil
+		}
+		ids = append(ids, data[primaryKey.Name])
+	}
+	return ids
+}
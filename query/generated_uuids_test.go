@@ -0,0 +1,32 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/rediwo/redi-orm/schema"
+)
+
+func TestWithGeneratedUUIDs(t *testing.T) {
+	mockDB := &mockDatabase{}
+	mockDB.RegisterSchema("Account", schema.New("Account").
+		AddField(schema.Field{Name: "id", Type: schema.FieldTypeString, PrimaryKey: true, Default: "UUID()"}).
+		AddField(schema.Field{Name: "name", Type: schema.FieldTypeString}))
+
+	original := map[string]any{"name": "Alice"}
+	first := WithGeneratedUUIDs(mockDB, "Account", original).(map[string]any)
+	second := WithGeneratedUUIDs(mockDB, "Account", original).(map[string]any)
+	if id, ok := first["id"].(string); !ok || len(id) != 36 {
+		t.Errorf("WithGeneratedUUIDs() id = %v, want a UUID", first["id"])
+	}
+	if first["id"] == second["id"] {
+		t.Errorf("WithGeneratedUUIDs() generated %v twice", first["id"])
+	}
+	if _, ok := original["id"]; ok {
+		t.Error("WithGeneratedUUIDs() modified the original data")
+	}
+
+	explicit := map[string]any{"id": "2b4f9c3e-8d1a-4f6b-9c2d-7e5a3b1c0d9f", "name": "Bob"}
+	if got := WithGeneratedUUIDs(mockDB, "Account", explicit).(map[string]any)["id"]; got != explicit["id"] {
+		t.Errorf("WithGeneratedUUIDs() id = %v, want the explicit %v", got, explicit["id"])
+	}
+}
@@ -8,15 +8,18 @@ import (
 	"strings"
 
 	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
 )
 
 // HierarchicalScanner handles scanning results with nested relations
 type HierarchicalScanner struct {
 	mainSchema       *schema.Schema
 	mainAlias        string
-	joinInfo         map[string]*JoinInfo // alias -> join information
-	relationPaths    map[string]string    // alias -> full relation path (e.g., "posts.comments")
-	includeProcessor *IncludeProcessor    // For filtering and field selection
+	joinInfo         map[string]*JoinInfo     // alias -> join information
+	relationPaths    map[string]string        // alias -> full relation path (e.g., "posts.comments")
+	includeProcessor *IncludeProcessor        // For filtering and field selection
+	capabilities     types.DriverCapabilities // For decoding list columns
 }
 
 // JoinInfo contains information about a joined table
@@ -49,9 +52,13 @@ func (hs *HierarchicalScanner) SetIncludeProcessor(processor *IncludeProcessor)
 	hs.includeProcessor = processor
 }
 
+// SetCapabilities sets the driver capabilities used to decode list columns
+func (hs *HierarchicalScanner) SetCapabilities(capabilities types.DriverCapabilities) {
+	hs.capabilities = capabilities
+}
+
 // AddJoinedTable adds information about a joined table with its parent
 func (hs *HierarchicalScanner) AddJoinedTable(alias string, schema *schema.Schema, relation *schema.Relation, relationName string, parentAlias string, path string) {
-	// fmt.Printf("[DEBUG] AddJoinedTable: alias=%s, relation=%s, path=%s, parent=%s\n", alias, relationName, path, parentAlias)
 	hs.joinInfo[alias] = &JoinInfo{
 		Schema:       schema,
 		Relation:     relation,
@@ -80,7 +87,12 @@ func (hs *HierarchicalScanner) ScanRowsWithRelations(rows *sql.Rows, dest any) e
 		return hs.scanRowsToMapsHierarchical(rows, dest)
 	}
 
-	return fmt.Errorf("struct scanning with hierarchical relations not yet implemented")
+	// For structs, build the nested maps first and hydrate nested structs from them
+	var records []map[string]any
+	if err := hs.scanRowsToMapsHierarchical(rows, &records); err != nil {
+		return err
+	}
+	return utils.HydrateStructs(records, dest)
 }
 
 // scanRowsToMapsHierarchical scans rows into a hierarchical structure
@@ -135,16 +147,17 @@ func (hs *HierarchicalScanner) scanRowsToMapsHierarchical(rows *sql.Rows, dest a
 			}
 
 			// Map column back to schema field name
+			var tableSchema *schema.Schema
 			if tableAlias == hs.mainAlias {
-				if hs.mainSchema != nil {
-					if mapped, err := hs.mainSchema.GetFieldNameByColumnName(fieldName); err == nil {
-						fieldName = mapped
-					}
-				}
-			} else if info, exists := hs.joinInfo[tableAlias]; exists && info.Schema != nil {
-				if mapped, err := info.Schema.GetFieldNameByColumnName(fieldName); err == nil {
+				tableSchema = hs.mainSchema
+			} else if info, exists := hs.joinInfo[tableAlias]; exists {
+				tableSchema = info.Schema
+			}
+			if tableSchema != nil {
+				if mapped, err := tableSchema.GetFieldNameByColumnName(fieldName); err == nil {
 					fieldName = mapped
 				}
+				val = decodeFieldValue(hs.capabilities, tableSchema, fieldName, val)
 			}
 
 			recordMaps[tableAlias][fieldName] = val
@@ -208,10 +221,8 @@ func (hs *HierarchicalScanner) scanRowsToMapsHierarchical(rows *sql.Rows, dest a
 
 			info, exists := hs.joinInfo[alias]
 			if !exists {
-				// fmt.Printf("[DEBUG] No join info for alias: %s\n", alias)
 				continue
 			}
-			// fmt.Printf("[DEBUG] Processing joined record for alias: %s, relation: %s, path: %s, parent: %s\n", alias, info.RelationName, info.Path, info.ParentAlias)
 
 			recordID := recordData["id"]
 			if recordID == nil {
@@ -251,8 +262,6 @@ func (hs *HierarchicalScanner) scanRowsToMapsHierarchical(rows *sql.Rows, dest a
 						parentID = parentRecordData["id"]
 					}
 
-					// fmt.Printf("[DEBUG] Nested child: looking for parent %s with ID %v (relation type: %v)\n", info.ParentAlias, parentID, info.Relation.Type)
-
 					if parentID != nil {
 						if parentNode, exists := allRecords[info.ParentAlias][parentID]; exists {
 							if _, exists := parentNode.Children[info.RelationName]; !exists {
@@ -281,7 +290,7 @@ func (hs *HierarchicalScanner) scanRowsToMapsHierarchical(rows *sql.Rows, dest a
 	results := make([]map[string]any, 0, len(recordOrder))
 	for _, id := range recordOrder {
 		if node, exists := mainRecords[id]; exists {
-			results = append(results, hs.nodeToMap(node))
+			results = append(results, hs.nodeToMap(node, hs.mainAlias))
 		}
 	}
 
@@ -292,8 +301,10 @@ func (hs *HierarchicalScanner) scanRowsToMapsHierarchical(rows *sql.Rows, dest a
 	return nil
 }
 
-// nodeToMap converts a RecordNode to a map with nested relations
-func (hs *HierarchicalScanner) nodeToMap(node *RecordNode) map[string]any {
+// nodeToMap converts a RecordNode of the table with the given alias to a map with nested
+// relations. Every relation joined to the table is set, to an empty array or nil when no
+// records matched.
+func (hs *HierarchicalScanner) nodeToMap(node *RecordNode, alias string) map[string]any {
 	result := make(map[string]any)
 
 	// Copy data fields
@@ -302,62 +313,36 @@ func (hs *HierarchicalScanner) nodeToMap(node *RecordNode) map[string]any {
 	}
 
 	// Add nested relations
-	for relationName, children := range node.Children {
-		// fmt.Printf("[DEBUG] nodeToMap: processing relation %s with %d children\n", relationName, len(children))
-		// Find the relation info to determine if it's one-to-many or many-to-one
-		var relationType schema.RelationType
-		for _, info := range hs.joinInfo {
-			if info.RelationName == relationName {
-				relationType = info.Relation.Type
-				break
-			}
+	for childAlias, info := range hs.joinInfo {
+		if info.ParentAlias != alias {
+			continue
 		}
+		children := node.Children[info.RelationName]
 
-		switch relationType {
+		switch info.Relation.Type {
 		case schema.RelationOneToMany:
 			// Convert to array
-			childArray := make([]any, 0, len(children))
+			childArray := make([]map[string]any, 0, len(children))
 			for _, childNode := range children {
-				childArray = append(childArray, hs.nodeToMap(childNode))
+				childArray = append(childArray, hs.nodeToMap(childNode, childAlias))
 			}
 
 			// Apply include processor filtering if available
 			if hs.includeProcessor != nil {
-				// Find the relation path for this relation
-				relationPath := ""
-				for _, info := range hs.joinInfo {
-					if info.RelationName == relationName {
-						relationPath = info.Path
-						break
-					}
-				}
-
-				if relationPath != "" {
-					// Convert to map array for processing
-					mapArray := make([]map[string]any, len(childArray))
-					for i, child := range childArray {
-						if m, ok := child.(map[string]any); ok {
-							mapArray[i] = m
-						}
-					}
-
-					// Process with include options
-					mapArray = hs.includeProcessor.ProcessRelationData(relationPath, mapArray)
-
-					// Convert back to any array
-					childArray = make([]any, len(mapArray))
-					for i, m := range mapArray {
-						childArray[i] = m
-					}
-				}
+				childArray = hs.includeProcessor.ProcessRelationData(info.Path, childArray)
 			}
 
-			result[relationName] = childArray
+			relationArray := make([]any, len(childArray))
+			for i, m := range childArray {
+				relationArray[i] = m
+			}
+			result[info.RelationName] = relationArray
 
 		case schema.RelationManyToOne, schema.RelationOneToOne:
 			// Single value - take the first (should only be one)
+			result[info.RelationName] = nil
 			for _, childNode := range children {
-				result[relationName] = hs.nodeToMap(childNode)
+				result[info.RelationName] = hs.nodeToMap(childNode, childAlias)
 				break
 			}
 		}
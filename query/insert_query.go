@@ -3,8 +3,11 @@ package query
 import (
 	"context"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/rediwo/redi-orm/types"
 )
@@ -48,24 +51,62 @@ func (q *InsertQueryImpl) Returning(fieldNames ...string) types.InsertQuery {
 	return newQuery
 }
 
+// GetConflictAction returns the conflict resolution action
+func (q *InsertQueryImpl) GetConflictAction() types.ConflictAction {
+	return q.conflictAction
+}
+
 // Exec executes the insert query
 func (q *InsertQueryImpl) Exec(ctx context.Context) (types.Result, error) {
+	ctx, cancel := q.StatementContext(ctx)
+	defer cancel()
+	defer types.ForgetDeduplicated(ctx)
+	q = q.withGeneratedUUIDs()
+
 	sql, args, err := q.BuildSQL()
 	if err != nil {
 		return types.Result{}, fmt.Errorf("failed to build SQL: %w", err)
 	}
 
+	// Rows binding more parameters than the driver allows are inserted in a transaction,
+	// by as many statements as it takes
+	statements, err := q.splitStatements(sql, args)
+	if err != nil {
+		return types.Result{}, err
+	}
+	if len(statements) > 1 {
+		result, err := execStatements(ctx, q.database, statements, "insert")
+		return q.withInsertedIDs(result), err
+	}
+
+	// Drivers reporting no last insert ID return the primary keys of the rows instead
+	if returner, ok := q.database.(types.InsertIDReturner); ok && len(q.returningFields) == 0 {
+		if column, ok := q.primaryKeyColumn(); ok {
+			result, err := returner.InsertReturningID(ctx, sql, args, column)
+			if err != nil {
+				return types.Result{}, fmt.Errorf("failed to execute insert: %w", err)
+			}
+			return result, nil
+		}
+	}
+
 	rawQuery := q.database.Raw(sql, args...)
 	result, err := rawQuery.Exec(ctx)
 	if err != nil {
 		return types.Result{}, fmt.Errorf("failed to execute insert: %w", err)
 	}
 
-	return result, nil
+	return q.withInsertedIDs(result), nil
 }
 
 // ExecAndReturn executes the insert and returns the inserted data
 func (q *InsertQueryImpl) ExecAndReturn(ctx context.Context, dest any) error {
+	ctx, cancel := q.StatementContext(ctx)
+	defer cancel()
+	defer types.ForgetDeduplicated(ctx)
+
+	q = q.withGeneratedUUIDs()
+
 	if len(q.returningFields) == 0 {
 		return fmt.Errorf("no returning fields specified")
 	}
@@ -81,7 +122,19 @@ func (q *InsertQueryImpl) ExecAndReturn(ctx context.Context, dest any) error {
 	}
 
 	rawQuery := q.database.Raw(sql, args...)
-	return rawQuery.FindOne(ctx, dest)
+	if err := rawQuery.FindOne(ctx, dest); err != nil {
+		return err
+	}
+	if record, ok := dest.(*map[string]any); ok {
+		decodeRecordValues(q.database, q.modelName, *record)
+	}
+	return nil
+}
+
+// ToSQL returns the SQL of the query as the database runs it, with its arguments, without
+// executing it
+func (q *InsertQueryImpl) ToSQL() (string, []any, error) {
+	return q.compileSQL(q.BuildSQL)
 }
 
 // BuildSQL builds the insert SQL query
@@ -96,8 +149,15 @@ func (q *InsertQueryImpl) BuildSQL() (string, []any, error) {
 		return "", nil, fmt.Errorf("failed to resolve table name: %w", err)
 	}
 
+	// @updatedAt fields are set on create as well, and omitted lists are empty
+	now := time.Now()
+	items := make([]any, len(q.data))
+	for i, item := range q.data {
+		items[i] = WithListDefaults(q.database, q.modelName, WithUpdatedAt(q.database, q.modelName, item, now))
+	}
+
 	// Extract fields and values from the first data item
-	firstItem := q.data[0]
+	firstItem := items[0]
 	fields, values, err := q.extractFieldsAndValues(firstItem)
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to extract fields and values: %w", err)
@@ -107,7 +167,7 @@ func (q *InsertQueryImpl) BuildSQL() (string, []any, error) {
 	var sql strings.Builder
 	var args []any
 
-	sql.WriteString(fmt.Sprintf("INSERT INTO %s", tableName))
+	sql.WriteString(fmt.Sprintf("INSERT INTO %s", q.database.GetCapabilities().QuoteIdentifier(tableName)))
 
 	// Handle conflict resolution
 	switch q.conflictAction {
@@ -166,18 +226,23 @@ func (q *InsertQueryImpl) BuildSQL() (string, []any, error) {
 
 	// Process all data items
 	var valuePlaceholders []string
-	for i, dataItem := range q.data {
-		if i == 0 {
-			// First item - we already have its values
-			args = append(args, values...)
-		} else {
+	for i, dataItem := range items {
+		itemValues := values
+		if i > 0 {
 			// Additional items - extract their values
-			_, itemValues, err := q.extractFieldsAndValues(dataItem)
+			_, itemValues, err = q.extractFieldsAndValues(dataItem)
 			if err != nil {
 				return "", nil, fmt.Errorf("failed to extract values from item %d: %w", i, err)
 			}
-			args = append(args, itemValues...)
 		}
+		if err := ValidateFields(q.database, q.modelName, fields, itemValues); err != nil {
+			return "", nil, err
+		}
+		itemValues, err = encodeFieldValues(q.database, q.modelName, fields, itemValues)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to encode values: %w", err)
+		}
+		args = append(args, itemValues...)
 
 		// Create placeholders for this row
 		placeholders := make([]string, len(fields))
@@ -189,6 +254,10 @@ func (q *InsertQueryImpl) BuildSQL() (string, []any, error) {
 
 	sql.WriteString(strings.Join(valuePlaceholders, ", "))
 
+	if q.conflictAction == types.ConflictDoNothing {
+		sql.WriteString(q.database.GetCapabilities().GetSkipDuplicatesSQL(columnNames))
+	}
+
 	// Add RETURNING clause if specified and supported
 	if len(q.returningFields) > 0 && q.database.GetCapabilities().SupportsReturning() {
 		returningColumns, err := q.fieldMapper.SchemaFieldsToColumns(q.modelName, q.returningFields)
@@ -217,12 +286,12 @@ func (q *InsertQueryImpl) extractFieldsAndValues(data any) ([]string, []any, err
 
 // extractFromMap extracts fields and values from a map
 func (q *InsertQueryImpl) extractFromMap(data map[string]any) ([]string, []any, error) {
-	fields := make([]string, 0, len(data))
+	// Sorted so that every row of a multi-row insert lists its values in the same order
+	fields := slices.Sorted(maps.Keys(data))
 	values := make([]any, 0, len(data))
 
-	for field, value := range data {
-		fields = append(fields, field)
-		values = append(values, value)
+	for _, field := range fields {
+		values = append(values, data[field])
 	}
 
 	return fields, values, nil
@@ -273,6 +342,24 @@ func (q *InsertQueryImpl) extractFromStruct(data any) ([]string, []any, error) {
 	return fields, values, nil
 }
 
+// primaryKeyColumn returns the column of the primary key of the model, unless it has none or
+// a composite one
+func (q *InsertQueryImpl) primaryKeyColumn() (string, bool) {
+	s, err := q.database.GetSchema(q.modelName)
+	if err != nil || len(s.CompositeKey) > 0 {
+		return "", false
+	}
+	field, err := s.GetPrimaryKey()
+	if err != nil {
+		return "", false
+	}
+	column, err := q.fieldMapper.SchemaToColumn(q.modelName, field.Name)
+	if err != nil {
+		return "", false
+	}
+	return column, true
+}
+
 // clone creates a copy of the insert query
 func (q *InsertQueryImpl) clone() *InsertQueryImpl {
 	return &InsertQueryImpl{
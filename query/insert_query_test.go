@@ -148,7 +148,7 @@ func TestInsertQuery_BuildSQL(t *testing.T) {
 				map[string]any{"name": "John", "email": "john@example.com"},
 			},
 			driverType:    "sqlite",
-			wantSQL:       "INSERT INTO users",
+			wantSQL:       "INSERT INTO `users`",
 			wantArgsCount: 2,
 		},
 		{
@@ -159,7 +159,7 @@ func TestInsertQuery_BuildSQL(t *testing.T) {
 				map[string]any{"name": "Jane", "email": "jane@example.com"},
 			},
 			driverType:    "sqlite",
-			wantSQL:       "INSERT INTO users",
+			wantSQL:       "INSERT INTO `users`",
 			wantArgsCount: 4,
 		},
 		{
@@ -170,7 +170,7 @@ func TestInsertQuery_BuildSQL(t *testing.T) {
 			},
 			returningFields: []string{"id", "createdAt"},
 			driverType:      "postgresql",
-			wantSQL:         "INSERT INTO users (`name`) VALUES (?)",
+			wantSQL:         "INSERT INTO `users` (`name`) VALUES (?)",
 			wantArgsCount:   1,
 		},
 		{
@@ -179,9 +179,21 @@ func TestInsertQuery_BuildSQL(t *testing.T) {
 			data:           []any{map[string]any{"id": 1, "name": "John"}},
 			conflictAction: types.ConflictReplace,
 			driverType:     "sqlite",
-			wantSQL:        "INSERT INTO users OR REPLACE",
+			wantSQL:        "INSERT INTO `users` OR REPLACE",
 			wantArgsCount:  2,
 		},
+		{
+			name:      "on conflict do nothing",
+			modelName: "User",
+			data: []any{
+				map[string]any{"name": "John", "email": "john@example.com"},
+				map[string]any{"email": "jane@example.com", "name": "Jane"},
+			},
+			conflictAction: types.ConflictDoNothing,
+			driverType:     "sqlite",
+			wantSQL:        "INSERT INTO `users` (`email`, `name`) VALUES (?, ?), (?, ?) ON CONFLICT DO NOTHING",
+			wantArgsCount:  4,
+		},
 		{
 			name:      "empty data array",
 			modelName: "User",
@@ -193,7 +205,7 @@ func TestInsertQuery_BuildSQL(t *testing.T) {
 			modelName:     "User",
 			data:          []any{map[string]any{}},
 			driverType:    "sqlite",
-			wantSQL:       "INSERT INTO users DEFAULT VALUES",
+			wantSQL:       "INSERT INTO `users` DEFAULT VALUES",
 			wantArgsCount: 0,
 		},
 		{
@@ -202,7 +214,7 @@ func TestInsertQuery_BuildSQL(t *testing.T) {
 			data:            []any{map[string]any{}},
 			returningFields: []string{"id", "createdAt"},
 			driverType:      "postgresql",
-			wantSQL:         "INSERT INTO users DEFAULT VALUES",
+			wantSQL:         "INSERT INTO `users` DEFAULT VALUES",
 			wantArgsCount:   0,
 		},
 		{
@@ -224,7 +236,7 @@ func TestInsertQuery_BuildSQL(t *testing.T) {
 				},
 			},
 			driverType:    "mysql",
-			wantSQL:       "INSERT INTO users",
+			wantSQL:       "INSERT INTO `users`",
 			wantArgsCount: 2,
 		},
 	}
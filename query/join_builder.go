@@ -122,7 +122,6 @@ func (b *JoinBuilder) AddRelationJoin(
 		RelationPath: relationName, // Will be updated in AddNestedRelationJoin for nested paths
 	}
 
-	// fmt.Printf("[DEBUG] Adding join: %s AS %s (relation: %s, parent: %s)\n", relatedTable, alias, relationName, fromAlias)
 	b.joins = append(b.joins, join)
 	return nil
 }
@@ -238,6 +237,7 @@ func (b *JoinBuilder) BuildSQL() string {
 				fieldMapper.RegisterSchema(join.Relation.Model, join.Schema)
 				ctx := types.NewConditionContext(fieldMapper, join.Relation.Model, join.Alias)
 				ctx.QuoteIdentifier = b.database.QuoteIdentifier
+				ctx.Capabilities = b.database.GetCapabilities()
 
 				// Build the WHERE condition SQL
 				whereSql, whereArgs := includeOpt.Where.ToSQL(ctx)
@@ -263,8 +263,8 @@ func (b *JoinBuilder) BuildSQL() string {
 
 		parts = append(parts, fmt.Sprintf("%s %s AS %s ON %s",
 			join.Type,
-			join.Table,
-			join.Alias,
+			b.quote(join.Table),
+			b.quote(join.Alias),
 			condition,
 		))
 	}
@@ -299,6 +299,16 @@ func (b *JoinBuilder) generateAlias(tableName string) string {
 	return fmt.Sprintf("%s%d", alias, count+1)
 }
 
+// quote quotes an identifier for the database
+func (b *JoinBuilder) quote(name string) string {
+	return b.database.GetCapabilities().QuoteIdentifier(name)
+}
+
+// column returns the quoted column of a table alias, as in alias.column
+func (b *JoinBuilder) column(alias, columnName string) string {
+	return b.quote(alias) + "." + b.quote(columnName)
+}
+
 // getSchema retrieves a schema from cache or database
 func (b *JoinBuilder) getSchema(modelName string) (*schema.Schema, error) {
 	if cached, exists := b.schemaCache[modelName]; exists {
@@ -333,7 +343,7 @@ func (b *JoinBuilder) buildJoinCondition(
 		if err != nil {
 			return "", err
 		}
-		return fmt.Sprintf("%s.%s = %s.%s", fromAlias, fromCol, toAlias, toCol), nil
+		return b.column(fromAlias, fromCol) + " = " + b.column(toAlias, toCol), nil
 
 	case schema.RelationOneToMany:
 		// TO.foreign_key = FROM.id (or references field)
@@ -350,7 +360,7 @@ func (b *JoinBuilder) buildJoinCondition(
 		if err != nil {
 			return "", err
 		}
-		return fmt.Sprintf("%s.%s = %s.%s", toAlias, toCol, fromAlias, fromCol), nil
+		return b.column(toAlias, toCol) + " = " + b.column(fromAlias, fromCol), nil
 
 	case schema.RelationOneToOne:
 		// Check which side has the foreign key
@@ -364,7 +374,7 @@ func (b *JoinBuilder) buildJoinCondition(
 			if err != nil {
 				return "", err
 			}
-			return fmt.Sprintf("%s.%s = %s.%s", fromAlias, fromCol, toAlias, toCol), nil
+			return b.column(fromAlias, fromCol) + " = " + b.column(toAlias, toCol), nil
 		} else {
 			// Foreign key in to table
 			toCol, err := toSchema.GetColumnNameByFieldName(relation.ForeignKey)
@@ -379,7 +389,7 @@ func (b *JoinBuilder) buildJoinCondition(
 			if err != nil {
 				return "", err
 			}
-			return fmt.Sprintf("%s.%s = %s.%s", toAlias, toCol, fromAlias, fromCol), nil
+			return b.column(toAlias, toCol) + " = " + b.column(fromAlias, fromCol), nil
 		}
 
 	case schema.RelationManyToMany:
@@ -433,7 +443,7 @@ func (b *JoinBuilder) AddManyToManyJoin(
 		Type:      joinType,
 		Table:     junctionTable,
 		Alias:     junctionAlias,
-		Condition: fmt.Sprintf("%s.%s = %s.%s", fromAlias, fromCol, junctionAlias, junctionFromCol),
+		Condition: b.column(fromAlias, fromCol) + " = " + b.column(junctionAlias, junctionFromCol),
 	}
 	b.joins = append(b.joins, join1)
 
@@ -450,7 +460,7 @@ func (b *JoinBuilder) AddManyToManyJoin(
 		Type:      joinType,
 		Table:     relatedSchema.GetTableName(),
 		Alias:     relatedAlias,
-		Condition: fmt.Sprintf("%s.%s = %s.%s", junctionAlias, junctionToCol, relatedAlias, relatedCol),
+		Condition: b.column(junctionAlias, junctionToCol) + " = " + b.column(relatedAlias, relatedCol),
 		Schema:    relatedSchema,
 		Relation:  &relation,
 	}
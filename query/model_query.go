@@ -5,8 +5,10 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
 )
 
 // ModelQueryImpl implements the ModelQuery interface
@@ -23,11 +25,14 @@ type ModelQueryImpl struct {
 	limit          *int
 	offset         *int
 	tableAlias     string
+	timeout        time.Duration
 }
 
 type OrderClause struct {
-	FieldName string
-	Direction types.Order
+	FieldName     string
+	Direction     types.Order
+	Nulls         types.NullsOrder
+	RelationCount bool // FieldName names a to-many relation, ordered by its number of records
 }
 
 // NewModelQuery creates a new model query
@@ -76,6 +81,11 @@ func (q *ModelQueryImpl) Delete() types.DeleteQuery {
 	return NewDeleteQuery(q.clone())
 }
 
+// Upsert creates a new upsert query
+func (q *ModelQueryImpl) Upsert(create any, update any, conflictFields ...string) types.UpsertQuery {
+	return NewUpsertQuery(q.clone(), create, update, conflictFields)
+}
+
 // Aggregate creates a new aggregation query
 func (q *ModelQueryImpl) Aggregate() types.AggregationQuery {
 	return NewAggregationQuery(q.clone())
@@ -100,6 +110,45 @@ func (q *ModelQueryImpl) WhereRaw(sql string, args ...any) types.ModelQuery {
 	return newQuery
 }
 
+// OrWhere matches records matching the conditions added so far, or condition
+func (q *ModelQueryImpl) OrWhere(condition types.Condition) types.ModelQuery {
+	newQuery := q.clone()
+	if len(newQuery.conditions) == 0 {
+		newQuery.conditions = []types.Condition{condition}
+	} else {
+		newQuery.conditions = []types.Condition{types.Or(types.And(newQuery.conditions...), condition)}
+	}
+	return newQuery
+}
+
+// WhereNot adds a condition that records must not match
+func (q *ModelQueryImpl) WhereNot(condition types.Condition) types.ModelQuery {
+	return q.WhereCondition(types.Not(condition))
+}
+
+// WhereAny adds a condition matching records that match any of conditions
+func (q *ModelQueryImpl) WhereAny(conditions ...types.Condition) types.ModelQuery {
+	if len(conditions) == 0 {
+		return q.clone()
+	}
+	return q.WhereCondition(types.Or(conditions...))
+}
+
+// WhereGroup adds the conditions built by fn as one condition, so that OrWhere within the
+// group only applies to the conditions of the group:
+//
+//	q.WhereCondition(q.Where("active").Equals(true)).WhereGroup(func(g types.ModelQuery) types.ModelQuery {
+//		return g.WhereCondition(g.Where("role").Equals("admin")).OrWhere(g.Where("age").GreaterThan(30))
+//	})
+func (q *ModelQueryImpl) WhereGroup(fn func(group types.ModelQuery) types.ModelQuery) types.ModelQuery {
+	group := fn(NewModelQuery(q.modelName, q.database, q.fieldMapper))
+	withConditions, ok := group.(interface{ GetConditions() []types.Condition })
+	if !ok || len(withConditions.GetConditions()) == 0 {
+		return q.clone()
+	}
+	return q.WhereCondition(types.And(withConditions.GetConditions()...))
+}
+
 // Include adds relations to include
 func (q *ModelQueryImpl) Include(relations ...string) types.ModelQuery {
 	newQuery := q.clone()
@@ -158,6 +207,14 @@ func (q *ModelQueryImpl) Offset(offset int) types.ModelQuery {
 	return newQuery
 }
 
+// WithTimeout cancels the statements of the query after timeout, overriding the default
+// statement timeout of the database
+func (q *ModelQueryImpl) WithTimeout(timeout time.Duration) types.ModelQuery {
+	newQuery := q.clone()
+	newQuery.timeout = timeout
+	return newQuery
+}
+
 // FindMany executes the query and returns multiple results
 func (q *ModelQueryImpl) FindMany(ctx context.Context, dest any) error {
 	selectQuery := q.Select()
@@ -191,8 +248,17 @@ func (q *ModelQueryImpl) Exists(ctx context.Context) (bool, error) {
 	return count > 0, nil
 }
 
+// quotedColumn returns the quoted column of the table alias, as in alias.column
+func (q *ModelQueryImpl) quotedColumn(columnName string) string {
+	quote := q.database.GetCapabilities().QuoteIdentifier
+	return quote(q.tableAlias) + "." + quote(columnName)
+}
+
 // Aggregation methods
 func (q *ModelQueryImpl) Sum(ctx context.Context, fieldName string) (float64, error) {
+	ctx, cancel := q.StatementContext(ctx)
+	defer cancel()
+
 	// Create a select query to leverage its SQL building capabilities
 	selectQuery := q.Select()
 
@@ -216,11 +282,11 @@ func (q *ModelQueryImpl) Sum(ctx context.Context, fieldName string) (float64, er
 	}
 
 	// Build new SQL with SUM
-	sqlQuery := fmt.Sprintf("SELECT SUM(%s.%s)%s", q.tableAlias, columnName, baseSql[fromIndex:])
+	sqlQuery := fmt.Sprintf("SELECT SUM(%s)%s", q.quotedColumn(columnName), baseSql[fromIndex:])
 
 	// Execute query
 	var result sql.NullFloat64
-	err = q.database.QueryRow(sqlQuery, args...).Scan(&result)
+	err = queryRow(ctx, q.database, sqlQuery, args...).Scan(&result)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute sum query: %w", err)
 	}
@@ -232,6 +298,9 @@ func (q *ModelQueryImpl) Sum(ctx context.Context, fieldName string) (float64, er
 }
 
 func (q *ModelQueryImpl) Avg(ctx context.Context, fieldName string) (float64, error) {
+	ctx, cancel := q.StatementContext(ctx)
+	defer cancel()
+
 	// Create a select query to leverage its SQL building capabilities
 	selectQuery := q.Select()
 
@@ -255,11 +324,11 @@ func (q *ModelQueryImpl) Avg(ctx context.Context, fieldName string) (float64, er
 	}
 
 	// Build new SQL with AVG
-	sqlQuery := fmt.Sprintf("SELECT AVG(%s.%s)%s", q.tableAlias, columnName, baseSql[fromIndex:])
+	sqlQuery := fmt.Sprintf("SELECT AVG(%s)%s", q.quotedColumn(columnName), baseSql[fromIndex:])
 
 	// Execute query
 	var result sql.NullFloat64
-	err = q.database.QueryRow(sqlQuery, args...).Scan(&result)
+	err = queryRow(ctx, q.database, sqlQuery, args...).Scan(&result)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute avg query: %w", err)
 	}
@@ -271,6 +340,9 @@ func (q *ModelQueryImpl) Avg(ctx context.Context, fieldName string) (float64, er
 }
 
 func (q *ModelQueryImpl) Max(ctx context.Context, fieldName string) (any, error) {
+	ctx, cancel := q.StatementContext(ctx)
+	defer cancel()
+
 	// Create a select query to leverage its SQL building capabilities
 	selectQuery := q.Select()
 
@@ -294,11 +366,11 @@ func (q *ModelQueryImpl) Max(ctx context.Context, fieldName string) (any, error)
 	}
 
 	// Build new SQL with MAX
-	sqlQuery := fmt.Sprintf("SELECT MAX(%s.%s)%s", q.tableAlias, columnName, baseSql[fromIndex:])
+	sqlQuery := fmt.Sprintf("SELECT MAX(%s)%s", q.quotedColumn(columnName), baseSql[fromIndex:])
 
 	// Execute query
 	var result any
-	err = q.database.QueryRow(sqlQuery, args...).Scan(&result)
+	err = queryRow(ctx, q.database, sqlQuery, args...).Scan(&result)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -310,6 +382,9 @@ func (q *ModelQueryImpl) Max(ctx context.Context, fieldName string) (any, error)
 }
 
 func (q *ModelQueryImpl) Min(ctx context.Context, fieldName string) (any, error) {
+	ctx, cancel := q.StatementContext(ctx)
+	defer cancel()
+
 	// Create a select query to leverage its SQL building capabilities
 	selectQuery := q.Select()
 
@@ -333,11 +408,11 @@ func (q *ModelQueryImpl) Min(ctx context.Context, fieldName string) (any, error)
 	}
 
 	// Build new SQL with MIN
-	sqlQuery := fmt.Sprintf("SELECT MIN(%s.%s)%s", q.tableAlias, columnName, baseSql[fromIndex:])
+	sqlQuery := fmt.Sprintf("SELECT MIN(%s)%s", q.quotedColumn(columnName), baseSql[fromIndex:])
 
 	// Execute query
 	var result any
-	err = q.database.QueryRow(sqlQuery, args...).Scan(&result)
+	err = queryRow(ctx, q.database, sqlQuery, args...).Scan(&result)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -361,6 +436,7 @@ func (q *ModelQueryImpl) clone() *ModelQueryImpl {
 		groupBy:        make([]string, len(q.groupBy)),
 		having:         q.having,
 		tableAlias:     q.tableAlias,
+		timeout:        q.timeout,
 	}
 
 	copy(newQuery.conditions, q.conditions)
@@ -436,12 +512,69 @@ func (q *ModelQueryImpl) GetOffset() *int {
 	return q.offset
 }
 
+// GetTimeout returns the statement timeout of the query, 0 for the default (for internal use)
+func (q *ModelQueryImpl) GetTimeout() time.Duration {
+	return q.timeout
+}
+
+// StatementContext returns ctx with the deadline of the statement timeout of the query
+// (for internal use)
+func (q *ModelQueryImpl) StatementContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return types.StatementContext(ctx, q.database, q.timeout)
+}
+
 // GetDatabase returns the database (for internal use)
 func (q *ModelQueryImpl) GetDatabase() types.Database {
 	return q.database
 }
 
+// joinConditionSQL joins the SQL of conditions with AND, grouping each one when there are
+// several so that OR conditions keep their precedence
+func joinConditionSQL(conditionSQLs []string) string {
+	if len(conditionSQLs) == 1 {
+		return conditionSQLs[0]
+	}
+	grouped := make([]string, len(conditionSQLs))
+	for i, sql := range conditionSQLs {
+		grouped[i] = "(" + sql + ")"
+	}
+	return strings.Join(grouped, " AND ")
+}
+
+// compileSQL returns the SQL built by build as the database runs it, with its arguments
+func (q *ModelQueryImpl) compileSQL(build func() (string, []any, error)) (string, []any, error) {
+	sql, args, err := build()
+	if err != nil {
+		return "", nil, err
+	}
+	sql, args, err = utils.ExpandSQLArgs(sql, args)
+	if err != nil {
+		return "", nil, err
+	}
+	if preparer, ok := q.database.(types.SQLPreparer); ok {
+		sql = preparer.PrepareSQL(sql)
+	}
+	return sql, utils.NormalizeTimeArgs(args), nil
+}
+
 // GetFieldMapper returns the field mapper (for internal use)
 func (q *ModelQueryImpl) GetFieldMapper() types.FieldMapper {
 	return q.fieldMapper
 }
+
+// queryRows runs a query that returns rows, canceled when ctx is done if the database supports it
+func queryRows(ctx context.Context, database types.Database, statement string, args ...any) (*sql.Rows, error) {
+	if querier, ok := database.(types.ContextQuerier); ok {
+		return querier.QueryContext(ctx, statement, args...)
+	}
+	return database.Query(statement, args...)
+}
+
+// queryRow runs a query that returns a single row, canceled when ctx is done if the database
+// supports it
+func queryRow(ctx context.Context, database types.Database, statement string, args ...any) *sql.Row {
+	if querier, ok := database.(types.ContextQuerier); ok {
+		return querier.QueryRowContext(ctx, statement, args...)
+	}
+	return database.QueryRow(statement, args...)
+}
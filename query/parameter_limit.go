@@ -0,0 +1,328 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/rediwo/redi-orm/types"
+)
+
+// statement is a SQL statement with its arguments
+type statement struct {
+	sql  string
+	args []any
+}
+
+// splitConditions returns the where conditions of a statement binding more parameters than the
+// driver allows, split into conditions whose statements fit. They are split on their largest IN
+// list, the records being those matching any of the conditions, or failing that on their
+// largest NOT IN list, with intersect set as the records are those matching all of them. It
+// returns nil when the statement fits, and an error when it has no list to split.
+func splitConditions(db types.Database, conditions []types.Condition, parameters int) (split []types.Condition, intersect bool, err error) {
+	maxParameters := db.GetCapabilities().MaxParameters()
+	if maxParameters == 0 || parameters <= maxParameters {
+		return nil, false, nil
+	}
+	condition := types.NewAndCondition(conditions...)
+	if split := types.SplitIn(condition, parameters, maxParameters); split != nil {
+		return split, false, nil
+	}
+	if split := types.SplitNotIn(condition, parameters, maxParameters); split != nil {
+		return split, true, nil
+	}
+	return nil, false, tooManyParameters(db, parameters, "the where clause has no IN or NOT IN list to split")
+}
+
+// tooManyParameters returns the error of a statement binding more parameters than the driver
+// allows, which cannot be split for reason
+func tooManyParameters(db types.Database, parameters int, reason string) error {
+	return fmt.Errorf("%w: the statement binds %d parameters, more than the %d allowed, and %s",
+		types.ErrTooManyParameters, parameters, db.GetCapabilities().MaxParameters(), reason)
+}
+
+// execStatements runs statements in a transaction, summing the rows they affect
+func execStatements(ctx context.Context, db types.Database, statements []statement, operation string) (types.Result, error) {
+	var result types.Result
+	err := db.Transaction(ctx, func(tx types.Transaction) error {
+		for _, stmt := range statements {
+			r, err := tx.Raw(stmt.sql, stmt.args...).Exec(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to execute %s: %w", operation, err)
+			}
+			result.LastInsertID = r.LastInsertID
+			result.RowsAffected += r.RowsAffected
+		}
+		return nil
+	})
+	return result, err
+}
+
+// findManySplit finds the records of the query with one query per condition, storing the
+// merged results in dest. The results are concatenated, unless they have to be intersected,
+// ordered, deduplicated or paginated as the query would, which is done in memory on results
+// found as maps.
+func (q *SelectQueryImpl) findManySplit(ctx context.Context, conditions []types.Condition, intersect bool, parameters int, dest any) error {
+	if len(q.includes) > 0 {
+		// Relations are loaded by queries of their own, from the records found in chunks
+		return q.findManyWithRelationQueries(ctx, dest)
+	}
+	if len(q.groupBy) > 0 || q.having != nil {
+		return tooManyParameters(q.database, parameters, "grouped results cannot be merged")
+	}
+
+	if !intersect && len(q.orderBy) == 0 && q.limit == nil && q.offset == nil && !q.distinct {
+		results := reflect.ValueOf(dest).Elem()
+		merged := reflect.MakeSlice(results.Type(), 0, 0)
+		for _, condition := range conditions {
+			splitQuery := q.clone()
+			splitQuery.conditions = []types.Condition{condition}
+			part := reflect.New(results.Type())
+			if err := splitQuery.FindMany(ctx, part.Interface()); err != nil {
+				return err
+			}
+			merged = reflect.AppendSlice(merged, part.Elem())
+		}
+		results.Set(merged)
+		return nil
+	}
+
+	records, ok := dest.(*[]map[string]any)
+	if !ok {
+		return tooManyParameters(q.database, parameters, "only results found as maps are merged in memory")
+	}
+	for _, order := range q.orderBy {
+		if order.RelationCount {
+			return tooManyParameters(q.database, parameters, "results ordered by a relation count cannot be merged")
+		}
+	}
+
+	// The chunks are found whole and distinct in memory, with the fields they are merged on
+	base := q.clone()
+	base.limit, base.offset = nil, nil
+	base.distinct, base.distinctOn = false, nil
+	var addedFields []string
+	if len(base.selectedFields) > 0 {
+		var fields []string
+		if intersect {
+			keys, err := q.primaryKeyFields()
+			if err != nil {
+				return err
+			}
+			fields = append(fields, keys...)
+		}
+		for _, order := range q.orderBy {
+			fields = append(fields, order.FieldName)
+		}
+		fields = append(fields, q.distinctOn...)
+		for _, field := range fields {
+			if !slices.Contains(base.selectedFields, field) && !slices.Contains(addedFields, field) {
+				addedFields = append(addedFields, field)
+			}
+		}
+		base.selectedFields = append(base.selectedFields, addedFields...)
+	}
+
+	var merged []map[string]any
+	matches := make(map[string]int)
+	for i, condition := range conditions {
+		splitQuery := base.clone()
+		splitQuery.conditions = []types.Condition{condition}
+		var part []map[string]any
+		if err := splitQuery.FindMany(ctx, &part); err != nil {
+			return err
+		}
+		if !intersect {
+			merged = append(merged, part...)
+			continue
+		}
+		// The records of a NOT IN list are those of every chunk, whole records being unique
+		for _, record := range part {
+			key := fmt.Sprint(record)
+			if matches[key] == i {
+				matches[key] = i + 1
+			}
+			if i == 0 {
+				merged = append(merged, record)
+			}
+		}
+	}
+	if intersect {
+		merged = slices.DeleteFunc(merged, func(record map[string]any) bool {
+			return matches[fmt.Sprint(record)] < len(conditions)
+		})
+	}
+
+	q.sortRecords(merged)
+	if len(q.distinctOn) > 0 {
+		merged = distinctRecords(merged, q.distinctOn)
+	}
+	for _, record := range merged {
+		for _, field := range addedFields {
+			delete(record, field)
+		}
+	}
+	if q.distinct && len(q.distinctOn) == 0 {
+		merged = distinctRecords(merged, nil)
+	}
+	if q.offset != nil {
+		merged = merged[min(max(*q.offset, 0), len(merged)):]
+	}
+	if q.limit != nil && *q.limit >= 0 && *q.limit < len(merged) {
+		merged = merged[:*q.limit]
+	}
+	*records = merged
+	return nil
+}
+
+// primaryKeyFields returns the names of the fields of the primary key of the model
+func (q *SelectQueryImpl) primaryKeyFields() ([]string, error) {
+	modelSchema, err := q.database.GetModelSchema(q.modelName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema for model %s: %w", q.modelName, err)
+	}
+	if len(modelSchema.CompositeKey) > 0 {
+		return modelSchema.CompositeKey, nil
+	}
+	var keys []string
+	for _, field := range modelSchema.Fields {
+		if field.PrimaryKey {
+			keys = append(keys, field.Name)
+		}
+	}
+	return keys, nil
+}
+
+// sortRecords sorts records by the order of the query, placing null values as the database
+// does
+func (q *SelectQueryImpl) sortRecords(records []map[string]any) {
+	if len(q.orderBy) == 0 {
+		return
+	}
+	evaluator := NewConditionEvaluator(q.fieldMapper)
+	capabilities := q.database.GetCapabilities()
+	slices.SortStableFunc(records, func(a, b map[string]any) int {
+		for _, order := range q.orderBy {
+			valueA, valueB := a[order.FieldName], b[order.FieldName]
+			if valueA == nil || valueB == nil {
+				if valueA == nil && valueB == nil {
+					continue
+				}
+				// Null values are last unless placed first, or the smallest values on
+				// databases without NULLS FIRST/LAST ordering them
+				nullsFirst := order.Nulls == types.NullsFirst
+				if order.Nulls == types.NullsDefault && capabilities.GetNullsOrderingSQL(order.Direction, false) == "" {
+					nullsFirst = order.Direction == types.ASC
+				}
+				if (valueA == nil) == nullsFirst {
+					return -1
+				}
+				return 1
+			}
+			if c := evaluator.compareValues(valueA, valueB); c != 0 {
+				if order.Direction == types.DESC {
+					return -c
+				}
+				return c
+			}
+		}
+		return 0
+	})
+}
+
+// distinctRecords returns the first of the records with the same values of fields, or of all
+// their fields when fields is empty
+func distinctRecords(records []map[string]any, fields []string) []map[string]any {
+	seen := make(map[string]bool)
+	return slices.DeleteFunc(records, func(record map[string]any) bool {
+		key := fmt.Sprint(record)
+		if len(fields) > 0 {
+			values := make([]any, len(fields))
+			for i, field := range fields {
+				values[i] = record[field]
+			}
+			key = fmt.Sprint(values)
+		}
+		if seen[key] {
+			return true
+		}
+		seen[key] = true
+		return false
+	})
+}
+
+// splitStatements returns the statements of the update, one per condition of a where clause
+// binding more parameters than the driver allows
+func (q *UpdateQueryImpl) splitStatements(sql string, args []any) ([]statement, error) {
+	conditions, intersect, err := splitConditions(q.database, append(append([]types.Condition{}, q.conditions...), q.whereConditions...), len(args))
+	if err != nil {
+		return nil, err
+	}
+	if intersect {
+		return nil, tooManyParameters(q.database, len(args), "updates of NOT IN lists cannot be split")
+	}
+	if conditions == nil {
+		return []statement{{sql, args}}, nil
+	}
+
+	var statements []statement
+	for _, condition := range conditions {
+		splitQuery := q.clone()
+		splitQuery.conditions = []types.Condition{condition}
+		splitQuery.whereConditions = nil
+		sql, args, err := splitQuery.BuildSQL()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build SQL: %w", err)
+		}
+		statements = append(statements, statement{sql, args})
+	}
+	return statements, nil
+}
+
+// splitQueries returns the delete as one query per condition of a where clause binding more
+// parameters than the driver allows, or the delete itself when it fits
+func (q *DeleteQueryImpl) splitQueries(args []any) ([]*DeleteQueryImpl, error) {
+	conditions, intersect, err := splitConditions(q.database, append(append([]types.Condition{}, q.conditions...), q.whereConditions...), len(args))
+	if err != nil {
+		return nil, err
+	}
+	if intersect {
+		return nil, tooManyParameters(q.database, len(args), "deletes of NOT IN lists cannot be split")
+	}
+	if conditions == nil {
+		return []*DeleteQueryImpl{q}, nil
+	}
+
+	var queries []*DeleteQueryImpl
+	for _, condition := range conditions {
+		splitQuery := q.clone()
+		splitQuery.conditions = []types.Condition{condition}
+		splitQuery.whereConditions = nil
+		queries = append(queries, splitQuery)
+	}
+	return queries, nil
+}
+
+// splitStatements returns the statements of the insert, splitting its rows over as many
+// statements as the parameter limit of the driver requires
+func (q *InsertQueryImpl) splitStatements(sql string, args []any) ([]statement, error) {
+	maxParameters := q.database.GetCapabilities().MaxParameters()
+	if maxParameters == 0 || len(args) <= maxParameters || len(q.data) < 2 {
+		return []statement{{sql, args}}, nil
+	}
+
+	rowParameters := (len(args) + len(q.data) - 1) / len(q.data)
+	rows := max(maxParameters/rowParameters, 1)
+	var statements []statement
+	for start := 0; start < len(q.data); start += rows {
+		splitQuery := q.clone()
+		splitQuery.data = q.data[start:min(start+rows, len(q.data))]
+		sql, args, err := splitQuery.BuildSQL()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build SQL: %w", err)
+		}
+		statements = append(statements, statement{sql, args})
+	}
+	return statements, nil
+}
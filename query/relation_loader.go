@@ -0,0 +1,398 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
+)
+
+// findManyWithRelationQueries implements the "query" relation load strategy: the main
+// records are loaded without joins, then every relation level is loaded with one query
+// filtered by the keys of the records above it.
+func (q *SelectQueryImpl) findManyWithRelationQueries(ctx context.Context, dest any) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("dest must be a pointer to slice")
+	}
+
+	mainSchema, err := q.database.GetModelSchema(q.modelName)
+	if err != nil {
+		return fmt.Errorf("failed to get schema for model %s: %w", q.modelName, err)
+	}
+
+	base := q.clone()
+	base.includes = []string{}
+	base.includeOptions = make(types.IncludeOptions)
+	base.joinBuilder = NewJoinBuilderWithReservedAliases(q.database, q.tableAlias)
+
+	// Relation keys must be loaded even when they are not selected
+	var addedFields []string
+	if len(base.selectedFields) > 0 {
+		for _, path := range q.includes {
+			name := strings.Split(path, ".")[0]
+			relation, err := mainSchema.GetRelation(name)
+			if err != nil {
+				continue
+			}
+			parentKey, _, _ := relationKeys(mainSchema, relation)
+			keys := []string{parentKey}
+			if len(relation.Models) > 0 {
+				keys = append(keys, relation.TypeField)
+			}
+			for _, key := range keys {
+				if !slices.Contains(base.selectedFields, key) && !slices.Contains(addedFields, key) {
+					addedFields = append(addedFields, key)
+				}
+			}
+		}
+		base.selectedFields = append(base.selectedFields, addedFields...)
+	}
+
+	var records []map[string]any
+	if err := base.FindMany(ctx, &records); err != nil {
+		return err
+	}
+
+	loader := &relationLoader{database: q.database, includeOptions: q.includeOptions}
+	if err := loader.load(ctx, mainSchema, "", records, q.includes); err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		for _, field := range addedFields {
+			delete(record, field)
+		}
+	}
+
+	elemType := destValue.Elem().Type().Elem()
+	if elemType.Kind() == reflect.Map &&
+		elemType.Key().Kind() == reflect.String &&
+		elemType.Elem().Kind() == reflect.Interface {
+		if records == nil {
+			records = []map[string]any{}
+		}
+		destValue.Elem().Set(reflect.ValueOf(records))
+		return nil
+	}
+	return utils.HydrateStructs(records, dest)
+}
+
+// findFirstWithRelationQueries loads the first record with the "query" relation load strategy
+func (q *SelectQueryImpl) findFirstWithRelationQueries(ctx context.Context, dest any) error {
+	destType := reflect.TypeOf(dest)
+	if destType.Kind() != reflect.Ptr {
+		return fmt.Errorf("dest must be a pointer")
+	}
+
+	slicePtr := reflect.New(reflect.SliceOf(destType.Elem()))
+	if err := q.findManyWithRelationQueries(ctx, slicePtr.Interface()); err != nil {
+		return err
+	}
+
+	sliceValue := slicePtr.Elem()
+	if sliceValue.Len() == 0 {
+		return fmt.Errorf("no records found")
+	}
+	reflect.ValueOf(dest).Elem().Set(sliceValue.Index(0))
+	return nil
+}
+
+// relationLoader attaches included relations to already loaded records
+type relationLoader struct {
+	database       types.Database
+	includeOptions types.IncludeOptions
+}
+
+// load attaches the relations in paths (relative to prefix) to records of the model
+// described by parentSchema, then recurses into nested paths
+func (l *relationLoader) load(ctx context.Context, parentSchema *schema.Schema, prefix string, records []map[string]any, paths []string) error {
+	// Group nested paths under their first relation, keeping include order
+	var names []string
+	nested := make(map[string][]string)
+	for _, path := range paths {
+		name, rest, _ := strings.Cut(path, ".")
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+		if rest != "" {
+			nested[name] = append(nested[name], rest)
+		}
+	}
+
+	for _, name := range names {
+		fullPath := name
+		if prefix != "" {
+			fullPath = prefix + "." + name
+		}
+
+		relation, err := parentSchema.GetRelation(name)
+		if err != nil {
+			return fmt.Errorf("failed to get relation %s: %w", fullPath, err)
+		}
+		if len(relation.Models) > 0 {
+			if err := l.loadPolymorphic(ctx, parentSchema, name, relation, fullPath, records, nested[name]); err != nil {
+				return err
+			}
+			continue
+		}
+		relatedSchema, err := l.database.GetModelSchema(relation.Model)
+		if err != nil {
+			return fmt.Errorf("failed to get schema for related model %s: %w", relation.Model, err)
+		}
+
+		related, err := l.loadRelation(ctx, parentSchema, name, relation, fullPath, records)
+		if err != nil {
+			return err
+		}
+
+		if len(nested[name]) > 0 {
+			if err := l.load(ctx, relatedSchema, fullPath, related, nested[name]); err != nil {
+				return err
+			}
+		}
+
+		l.applySelect(fullPath, nested[name], related)
+	}
+
+	return nil
+}
+
+// loadPolymorphic loads a polymorphic relation with one query per model named by the type
+// field of records. Nested paths apply to the models having their first relation.
+func (l *relationLoader) loadPolymorphic(ctx context.Context, parentSchema *schema.Schema, name string, relation schema.Relation, fullPath string, records []map[string]any, nestedPaths []string) error {
+	// Records grouped by the model they reference, in the order the models appear
+	var models []string
+	byModel := make(map[string][]map[string]any)
+	for _, record := range records {
+		record[name] = nil
+		model := relation.RelatedModel(record)
+		if !slices.Contains(relation.Models, model) {
+			continue
+		}
+		if _, exists := byModel[model]; !exists {
+			models = append(models, model)
+		}
+		byModel[model] = append(byModel[model], record)
+	}
+
+	for _, model := range models {
+		relatedSchema, err := l.database.GetModelSchema(model)
+		if err != nil {
+			return fmt.Errorf("failed to get schema for related model %s: %w", model, err)
+		}
+
+		target := relation
+		target.Model = model
+		target.Models = nil
+		target.TypeField = ""
+		related, err := l.loadRelation(ctx, parentSchema, name, target, fullPath, byModel[model])
+		if err != nil {
+			return err
+		}
+
+		var paths []string
+		for _, path := range nestedPaths {
+			first, _, _ := strings.Cut(path, ".")
+			if relatedSchema.HasRelation(first) {
+				paths = append(paths, path)
+			}
+		}
+		if len(paths) > 0 {
+			if err := l.load(ctx, relatedSchema, fullPath, related, paths); err != nil {
+				return err
+			}
+		}
+
+		l.applySelect(fullPath, paths, related)
+	}
+
+	return nil
+}
+
+// includesPolymorphic reports whether an include path of the query goes through a
+// polymorphic relation, which JOINs cannot load
+func (q *SelectQueryImpl) includesPolymorphic() bool {
+	for _, path := range q.includes {
+		current, err := q.database.GetModelSchema(q.modelName)
+		if err != nil {
+			return false
+		}
+		for _, name := range strings.Split(path, ".") {
+			relation, err := current.GetRelation(name)
+			if err != nil {
+				break
+			}
+			if relation.IsPolymorphic() {
+				return true
+			}
+			if current, err = q.database.GetModelSchema(relation.Model); err != nil {
+				break
+			}
+		}
+	}
+	return false
+}
+
+// loadRelation runs the query for one relation and attaches the related records to their
+// parents: a slice for one-to-many relations, a record or nil otherwise. It returns the
+// attached related records.
+func (l *relationLoader) loadRelation(ctx context.Context, parentSchema *schema.Schema, name string, relation schema.Relation, fullPath string, records []map[string]any) ([]map[string]any, error) {
+	if relation.Type == schema.RelationManyToMany {
+		return nil, fmt.Errorf("relation %s: many-to-many relations are not supported by the query load strategy", fullPath)
+	}
+	parentKey, childKey, many := relationKeys(parentSchema, relation)
+
+	var keys []any
+	seen := make(map[string]bool)
+	for _, record := range records {
+		value := record[parentKey]
+		if value == nil || seen[fmt.Sprint(value)] {
+			continue
+		}
+		seen[fmt.Sprint(value)] = true
+		keys = append(keys, value)
+	}
+
+	// Related records grouped by their key, in query order
+	groups := make(map[string][]map[string]any)
+	if len(keys) > 0 {
+		opt := l.includeOptions[fullPath]
+		query := l.database.Model(relation.Model).Select()
+		conditions := []types.Condition{types.NewFieldCondition(relation.Model, childKey).In(keys...)}
+		if relation.IsPolymorphic() {
+			// The inverse of a polymorphic relation holds the records naming this model
+			conditions = append(conditions, types.NewFieldCondition(relation.Model, relation.TypeField).Equals(parentSchema.Name))
+		}
+		if opt != nil {
+			if opt.Where != nil {
+				conditions = append(conditions, opt.Where)
+			}
+			for _, order := range opt.OrderBy {
+				query = query.OrderBy(order.Field, order.Direction)
+			}
+		}
+
+		// More keys than the driver binds in a statement are loaded in chunks. The records of
+		// a key all load in the same chunk, so their groups keep the order of the relation.
+		_, args, err := query.WhereCondition(types.NewAndCondition(conditions...)).BuildSQL()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load relation %s: %w", fullPath, err)
+		}
+		chunks, intersect, err := splitConditions(l.database, conditions, len(args))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load relation %s: %w", fullPath, err)
+		}
+		if chunks == nil || intersect {
+			chunks = []types.Condition{types.NewAndCondition(conditions...)}
+		}
+
+		var children []map[string]any
+		for _, chunk := range chunks {
+			var chunkChildren []map[string]any
+			if err := query.WhereCondition(chunk).FindMany(ctx, &chunkChildren); err != nil {
+				return nil, fmt.Errorf("failed to load relation %s: %w", fullPath, err)
+			}
+			children = append(children, chunkChildren...)
+		}
+		for _, child := range children {
+			key := fmt.Sprint(child[childKey])
+			groups[key] = append(groups[key], child)
+		}
+	}
+
+	var related []map[string]any
+	for _, record := range records {
+		var group []map[string]any
+		if value := record[parentKey]; value != nil {
+			group = paginate(groups[fmt.Sprint(value)], l.includeOptions[fullPath])
+		}
+		related = append(related, group...)
+
+		if !many {
+			if len(group) > 0 {
+				record[name] = group[0]
+			} else {
+				record[name] = nil
+			}
+			continue
+		}
+
+		items := make([]any, len(group))
+		for i, child := range group {
+			items[i] = child
+		}
+		record[name] = items
+	}
+
+	return related, nil
+}
+
+// applySelect trims related records to the selected fields of their include option,
+// keeping the id and nested relations
+func (l *relationLoader) applySelect(fullPath string, nestedPaths []string, records []map[string]any) {
+	opt := l.includeOptions[fullPath]
+	if opt == nil || len(opt.Select) == 0 {
+		return
+	}
+
+	keep := map[string]bool{"id": true}
+	for _, field := range opt.Select {
+		keep[field] = true
+	}
+	for _, path := range nestedPaths {
+		name, _, _ := strings.Cut(path, ".")
+		keep[name] = true
+	}
+
+	for _, record := range records {
+		for field := range record {
+			if !keep[field] {
+				delete(record, field)
+			}
+		}
+	}
+}
+
+// paginate applies the per-parent offset and limit of an include option
+func paginate(records []map[string]any, opt *types.IncludeOption) []map[string]any {
+	if opt == nil {
+		return records
+	}
+	if opt.Offset != nil {
+		if *opt.Offset >= len(records) {
+			return nil
+		}
+		records = records[*opt.Offset:]
+	}
+	if opt.Limit != nil && *opt.Limit < len(records) {
+		records = records[:*opt.Limit]
+	}
+	return records
+}
+
+// relationKeys returns the parent field and related field a relation joins on, and whether
+// the relation holds many records. It mirrors the join conditions of JoinBuilder.
+func relationKeys(parentSchema *schema.Schema, relation schema.Relation) (parentKey, childKey string, many bool) {
+	references := relation.References
+	if references == "" {
+		references = "id"
+	}
+
+	switch relation.Type {
+	case schema.RelationManyToOne:
+		return relation.ForeignKey, references, false
+	case schema.RelationOneToOne:
+		if _, err := parentSchema.GetField(relation.ForeignKey); err == nil {
+			return relation.ForeignKey, references, false
+		}
+		return references, relation.ForeignKey, false
+	default:
+		return references, relation.ForeignKey, true
+	}
+}
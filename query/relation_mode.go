@@ -0,0 +1,131 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/types"
+)
+
+// maxCascadeDepth bounds cascading deletes, which would not end on records referencing
+// each other in a cycle
+const maxCascadeDepth = 64
+
+type cascadeDepthKey struct{}
+
+// emulatedRelation is a relation whose foreign key the ORM emulates, referencing the model
+// of a delete
+type emulatedRelation struct {
+	model    string
+	name     string
+	relation schema.Relation
+}
+
+// emulatedRelations returns the relations of models in the prisma relation mode referencing
+// modelName. Those restricting deletes come first, so that deletes they refuse change nothing.
+func emulatedRelations(db types.Database, modelName string) []emulatedRelation {
+	var relations []emulatedRelation
+	for _, model := range db.GetModels() {
+		s, err := db.GetSchema(model)
+		if err != nil || !s.EmulatesForeignKeys() {
+			continue
+		}
+		modelRelations, err := db.GetModelRelations(model)
+		if err != nil {
+			continue
+		}
+		for _, info := range modelRelations {
+			relation := info.Relation
+			if relation.Model != modelName || s.GetFieldByName(relation.ForeignKey) == nil {
+				continue
+			}
+			if relation.Type != schema.RelationManyToOne && relation.Type != schema.RelationOneToOne {
+				continue
+			}
+			relations = append(relations, emulatedRelation{model: model, name: info.Name, relation: relation})
+		}
+	}
+
+	// Models and their relations come sorted by name
+	sort.SliceStable(relations, func(i, j int) bool {
+		return restrictsDelete(relations[i]) && !restrictsDelete(relations[j])
+	})
+	return relations
+}
+
+// restrictsDelete reports whether a relation refuses deleting the records it references.
+// Without onDelete, foreign keys take no action, which refuses it too.
+func restrictsDelete(r emulatedRelation) bool {
+	switch r.relation.OnDelete {
+	case "", schema.ActionRestrict, schema.ActionNoAction:
+		return true
+	}
+	return false
+}
+
+// applyOnDelete applies the onDelete actions of relations to the records of modelName
+// matching condition, before they are deleted in tx
+func applyOnDelete(ctx context.Context, tx types.Transaction, db types.Database, modelName string, condition types.Condition, relations []emulatedRelation) error {
+	depth, _ := ctx.Value(cascadeDepthKey{}).(int)
+	if depth > maxCascadeDepth {
+		return fmt.Errorf("cascading delete of %s nested more than %d levels, the records may reference each other in a cycle", modelName, maxCascadeDepth)
+	}
+	ctx = context.WithValue(ctx, cascadeDepthKey{}, depth+1)
+
+	for _, r := range relations {
+		references := r.relation.References
+		if references == "" {
+			references = "id"
+		}
+		deleted := tx.Model(modelName)
+		if condition != nil {
+			deleted = deleted.WhereCondition(condition)
+		}
+		var rows []map[string]any
+		if err := deleted.Select(references).FindMany(ctx, &rows); err != nil {
+			return fmt.Errorf("failed to find the deleted %s: %w", modelName, err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		values := make([]any, len(rows))
+		for i, row := range rows {
+			values[i] = row[references]
+		}
+		referencing := tx.Model(r.model).Where(r.relation.ForeignKey).In(values...)
+
+		switch r.relation.OnDelete {
+		case "", schema.ActionRestrict, schema.ActionNoAction:
+			count, err := tx.Model(r.model).WhereCondition(referencing).Count(ctx)
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				return fmt.Errorf("cannot delete %s: it is referenced by %s.%s", modelName, r.model, r.name)
+			}
+
+		case schema.ActionCascade:
+			// The delete applies the actions of relations referencing r.model in turn
+			if _, err := tx.Model(r.model).Delete().WhereCondition(referencing).Exec(ctx); err != nil {
+				return fmt.Errorf("failed to cascade delete to %s: %w", r.model, err)
+			}
+
+		case schema.ActionSetNull, schema.ActionSetDefault:
+			var value any
+			if r.relation.OnDelete == schema.ActionSetDefault {
+				if s, err := db.GetSchema(r.model); err == nil {
+					if field := s.GetFieldByName(r.relation.ForeignKey); field != nil {
+						value = field.Default
+					}
+				}
+			}
+			data := map[string]any{r.relation.ForeignKey: value}
+			if _, err := tx.Model(r.model).Update(data).WhereCondition(referencing).Exec(ctx); err != nil {
+				return fmt.Errorf("failed to update %s.%s: %w", r.model, r.relation.ForeignKey, err)
+			}
+		}
+	}
+	return nil
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
 )
 
 // RelationScanner handles scanning results from queries with joins
@@ -17,6 +19,7 @@ type RelationScanner struct {
 	relations        map[string]*schema.Relation // alias -> relation
 	relationNames    map[string]string           // alias -> relation field name
 	includeProcessor *IncludeProcessor           // processor for include options
+	capabilities     types.DriverCapabilities    // driver capabilities for decoding list columns
 }
 
 // NewRelationScanner creates a new relation scanner
@@ -35,6 +38,11 @@ func (rs *RelationScanner) SetIncludeProcessor(processor *IncludeProcessor) {
 	rs.includeProcessor = processor
 }
 
+// SetCapabilities sets the driver capabilities used to decode list columns
+func (rs *RelationScanner) SetCapabilities(capabilities types.DriverCapabilities) {
+	rs.capabilities = capabilities
+}
+
 // AddJoinedTable adds information about a joined table
 func (rs *RelationScanner) AddJoinedTable(alias string, schema *schema.Schema, relation *schema.Relation, relationName string) {
 	rs.joinedSchemas[alias] = schema
@@ -60,9 +68,12 @@ func (rs *RelationScanner) ScanRowsWithRelations(rows *sql.Rows, dest any) error
 		return rs.scanRowsToMapsWithRelations(rows, dest)
 	}
 
-	// For struct scanning, we'd need more complex logic
-	// For now, return an error
-	return fmt.Errorf("struct scanning with relations not yet implemented")
+	// For structs, build the relation maps first and hydrate nested structs from them
+	var records []map[string]any
+	if err := rs.scanRowsToMapsWithRelations(rows, &records); err != nil {
+		return err
+	}
+	return utils.HydrateStructs(records, dest)
 }
 
 // scanRowsToMapsWithRelations scans rows with joins into maps
@@ -110,16 +121,15 @@ func (rs *RelationScanner) scanRowsToMapsWithRelations(rows *sql.Rows, dest any)
 			}
 
 			// Map column back to schema field name
+			tableSchema := rs.joinedSchemas[tableAlias]
 			if tableAlias == rs.mainAlias {
-				if rs.mainSchema != nil {
-					if mapped, err := rs.mainSchema.GetFieldNameByColumnName(fieldName); err == nil {
-						fieldName = mapped
-					}
-				}
-			} else if schema, exists := rs.joinedSchemas[tableAlias]; exists {
-				if mapped, err := schema.GetFieldNameByColumnName(fieldName); err == nil {
+				tableSchema = rs.mainSchema
+			}
+			if tableSchema != nil {
+				if mapped, err := tableSchema.GetFieldNameByColumnName(fieldName); err == nil {
 					fieldName = mapped
 				}
+				val = decodeFieldValue(rs.capabilities, tableSchema, fieldName, val)
 			}
 
 			recordMaps[tableAlias][fieldName] = val
@@ -140,11 +150,14 @@ func (rs *RelationScanner) scanRowsToMapsWithRelations(rows *sql.Rows, dest any)
 			mainRecords[mainID] = mainRecord
 			recordOrder = append(recordOrder, mainID)
 
-			// Initialize relation fields as empty slices/maps
+			// Initialize relation fields as empty slices, or nil for single records
 			for alias, relation := range rs.relations {
 				relationName := rs.relationNames[alias]
-				if relation.Type == schema.RelationOneToMany {
+				switch relation.Type {
+				case schema.RelationOneToMany:
 					mainRecord[relationName] = []any{}
+				case schema.RelationManyToOne, schema.RelationOneToOne:
+					mainRecord[relationName] = nil
 				}
 			}
 		}
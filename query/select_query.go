@@ -6,7 +6,9 @@ import (
 	"reflect"
 	"slices"
 	"strings"
+	"time"
 
+	"github.com/rediwo/redi-orm/schema"
 	"github.com/rediwo/redi-orm/types"
 )
 
@@ -17,6 +19,7 @@ type SelectQueryImpl struct {
 	distinct       bool
 	distinctOn     []string
 	joinBuilder    *JoinBuilder
+	loadStrategy   types.RelationLoadStrategy
 }
 
 // NewSelectQuery creates a new select query
@@ -105,6 +108,13 @@ func (q *SelectQueryImpl) IncludeWithOptions(path string, opt *types.IncludeOpti
 	return newQuery
 }
 
+// RelationLoadStrategy selects how included relations are loaded
+func (q *SelectQueryImpl) RelationLoadStrategy(strategy types.RelationLoadStrategy) types.SelectQuery {
+	newQuery := q.clone()
+	newQuery.loadStrategy = strategy
+	return newQuery
+}
+
 // OrderBy adds ordering
 func (q *SelectQueryImpl) OrderBy(fieldName string, direction types.Order) types.SelectQuery {
 	newQuery := q.clone()
@@ -115,6 +125,28 @@ func (q *SelectQueryImpl) OrderBy(fieldName string, direction types.Order) types
 	return newQuery
 }
 
+// OrderByNulls adds ordering with null values placed first or last
+func (q *SelectQueryImpl) OrderByNulls(fieldName string, direction types.Order, nulls types.NullsOrder) types.SelectQuery {
+	newQuery := q.clone()
+	newQuery.orderBy = append(newQuery.orderBy, OrderClause{
+		FieldName: fieldName,
+		Direction: direction,
+		Nulls:     nulls,
+	})
+	return newQuery
+}
+
+// OrderByRelationCount adds ordering by the number of records of a one-to-many relation
+func (q *SelectQueryImpl) OrderByRelationCount(relationName string, direction types.Order) types.SelectQuery {
+	newQuery := q.clone()
+	newQuery.orderBy = append(newQuery.orderBy, OrderClause{
+		FieldName:     relationName,
+		Direction:     direction,
+		RelationCount: true,
+	})
+	return newQuery
+}
+
 // GroupBy adds grouping
 func (q *SelectQueryImpl) GroupBy(fieldNames ...string) types.SelectQuery {
 	newQuery := q.clone()
@@ -158,13 +190,40 @@ func (q *SelectQueryImpl) DistinctOn(fieldNames ...string) types.SelectQuery {
 	return newQuery
 }
 
+// WithTimeout cancels the statements of the query after timeout, overriding the default
+// statement timeout of the database
+func (q *SelectQueryImpl) WithTimeout(timeout time.Duration) types.SelectQuery {
+	newQuery := q.clone()
+	newQuery.timeout = timeout
+	return newQuery
+}
+
 // FindMany executes the query and returns multiple results
 func (q *SelectQueryImpl) FindMany(ctx context.Context, dest any) error {
+	ctx, cancel := q.StatementContext(ctx)
+	defer cancel()
+
+	if err := types.CheckIncludeDepth(q.database, q.includes); err != nil {
+		return err
+	}
+	if len(q.includes) > 0 && (q.loadStrategy == types.RelationLoadQuery || q.includesPolymorphic()) {
+		return q.findManyWithRelationQueries(ctx, dest)
+	}
+
 	sql, args, err := q.BuildSQL()
 	if err != nil {
 		return fmt.Errorf("failed to build SQL: %w", err)
 	}
 
+	// IN and NOT IN lists longer than the driver allows are queried in chunks
+	conditions, intersect, err := splitConditions(q.database, q.conditions, len(args))
+	if err != nil {
+		return err
+	}
+	if conditions != nil {
+		return q.findManySplit(ctx, conditions, intersect, len(args), dest)
+	}
+
 	// Check if we have includes - if so, we need to use relation scanner
 	if len(q.includes) > 0 && q.joinBuilder != nil && len(q.joinBuilder.GetJoinedTables()) > 0 {
 		return q.findManyWithRelations(ctx, sql, args, dest)
@@ -189,11 +248,21 @@ func (q *SelectQueryImpl) FindMany(ctx context.Context, dest any) error {
 
 // FindFirst executes the query and returns the first result
 func (q *SelectQueryImpl) FindFirst(ctx context.Context, dest any) error {
+	ctx, cancel := q.StatementContext(ctx)
+	defer cancel()
+
 	// Don't add LIMIT 1 if we have includes, as we might need multiple rows
 	var sql string
 	var args []any
 	var err error
 
+	if err := types.CheckIncludeDepth(q.database, q.includes); err != nil {
+		return err
+	}
+	if len(q.includes) > 0 && (q.loadStrategy == types.RelationLoadQuery || q.includesPolymorphic()) {
+		return q.Limit(1).(*SelectQueryImpl).findFirstWithRelationQueries(ctx, dest)
+	}
+
 	if len(q.includes) > 0 && q.joinBuilder != nil && len(q.joinBuilder.GetJoinedTables()) > 0 {
 		// For includes, we need to get all rows for the first main record
 		// We'll add a subquery or handle it differently
@@ -236,6 +305,21 @@ func (q *SelectQueryImpl) FindFirst(ctx context.Context, dest any) error {
 			return fmt.Errorf("failed to build SQL: %w", err)
 		}
 
+		// IN and NOT IN lists longer than the driver allows find the record in chunks
+		if conditions, _, err := splitConditions(q.database, q.conditions, len(args)); err != nil {
+			return err
+		} else if conditions != nil {
+			records := reflect.New(reflect.SliceOf(reflect.TypeOf(dest).Elem()))
+			if err := limitedQuery.FindMany(ctx, records.Interface()); err != nil {
+				return err
+			}
+			if records.Elem().Len() == 0 {
+				return fmt.Errorf("no rows found")
+			}
+			reflect.ValueOf(dest).Elem().Set(records.Elem().Index(0))
+			return nil
+		}
+
 		// Check if dest is map[string]any - if so, we need field mapping
 		destType := reflect.TypeOf(dest)
 		if destType.Kind() == reflect.Ptr && destType.Elem().Kind() == reflect.Map &&
@@ -253,12 +337,44 @@ func (q *SelectQueryImpl) FindFirst(ctx context.Context, dest any) error {
 
 // Count returns the count of matching records
 func (q *SelectQueryImpl) Count(ctx context.Context) (int64, error) {
+	ctx, cancel := q.StatementContext(ctx)
+	defer cancel()
+
 	// Create a count query
 	countSQL, args, err := q.buildCountSQL()
 	if err != nil {
 		return 0, fmt.Errorf("failed to build count SQL: %w", err)
 	}
 
+	conditions, intersect, err := splitConditions(q.database, q.conditions, len(args))
+	if err != nil {
+		return 0, err
+	}
+	if conditions != nil {
+		if intersect || q.distinct || len(q.groupBy) > 0 || q.having != nil {
+			// The records are counted once merged
+			countQuery := q.clone()
+			countQuery.orderBy, countQuery.limit, countQuery.offset = nil, nil, nil
+			var records []map[string]any
+			if err := countQuery.findManySplit(ctx, conditions, intersect, len(args), &records); err != nil {
+				return 0, err
+			}
+			return int64(len(records)), nil
+		}
+
+		var total int64
+		for _, condition := range conditions {
+			splitQuery := q.clone()
+			splitQuery.conditions = []types.Condition{condition}
+			count, err := splitQuery.Count(ctx)
+			if err != nil {
+				return 0, err
+			}
+			total += count
+		}
+		return total, nil
+	}
+
 	// Execute count query
 	var count int64
 	rawQuery := q.database.Raw(countSQL, args...)
@@ -270,6 +386,12 @@ func (q *SelectQueryImpl) Count(ctx context.Context) (int64, error) {
 	return count, nil
 }
 
+// ToSQL returns the SQL of the query as the database runs it, with its arguments, without
+// executing it
+func (q *SelectQueryImpl) ToSQL() (string, []any, error) {
+	return q.compileSQL(q.BuildSQL)
+}
+
 // BuildSQL builds the SQL query
 func (q *SelectQueryImpl) BuildSQL() (string, []any, error) {
 	// Get table name from model
@@ -305,7 +427,7 @@ func (q *SelectQueryImpl) BuildSQL() (string, []any, error) {
 	}
 
 	// Build FROM clause with alias
-	fromClause := fmt.Sprintf("FROM %s AS %s", tableName, q.tableAlias)
+	fromClause := fmt.Sprintf("FROM %s AS %s", q.quote(tableName), q.quote(q.tableAlias))
 
 	// Add JOINs if any
 	if q.joinBuilder != nil {
@@ -318,6 +440,15 @@ func (q *SelectQueryImpl) BuildSQL() (string, []any, error) {
 		}
 	}
 
+	// Join the record counts of the relations the query is ordered by
+	countJoins, err := q.buildRelationCountJoins()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to build ORDER BY clause: %w", err)
+	}
+	if countJoins != "" {
+		fromClause += " " + countJoins
+	}
+
 	// Build WHERE clause
 	whereClause, args, err := q.buildWhereClause()
 	if err != nil {
@@ -399,12 +530,12 @@ func (q *SelectQueryImpl) buildSelectClause() string {
 				for _, field := range mainSchema.Fields {
 					columnName := field.GetColumnName()
 					// Alias format: tableAlias.column AS tableAlias_column
-					selectParts = append(selectParts, fmt.Sprintf("%s.%s AS %s_%s",
-						q.tableAlias, columnName, q.tableAlias, columnName))
+					selectParts = append(selectParts, fmt.Sprintf("%s.%s AS %s",
+						q.quote(q.tableAlias), q.quote(columnName), q.quote(q.tableAlias+"_"+columnName)))
 				}
 			} else {
 				// Fallback to wildcard if schema not available
-				selectParts = append(selectParts, fmt.Sprintf("%s.*", q.tableAlias))
+				selectParts = append(selectParts, fmt.Sprintf("%s.*", q.quote(q.tableAlias)))
 			}
 
 			// Add columns from joined tables
@@ -420,26 +551,26 @@ func (q *SelectQueryImpl) buildSelectClause() string {
 							continue // Skip invalid fields
 						}
 						columnName := field.GetColumnName()
-						selectParts = append(selectParts, fmt.Sprintf("%s.%s AS %s_%s",
-							join.Alias, columnName, join.Alias, columnName))
+						selectParts = append(selectParts, fmt.Sprintf("%s.%s AS %s",
+							q.quote(join.Alias), q.quote(columnName), q.quote(join.Alias+"_"+columnName)))
 					}
 				} else if join.Schema != nil {
 					// Select all fields from the joined table
 					for _, field := range join.Schema.Fields {
 						columnName := field.GetColumnName()
-						selectParts = append(selectParts, fmt.Sprintf("%s.%s AS %s_%s",
-							join.Alias, columnName, join.Alias, columnName))
+						selectParts = append(selectParts, fmt.Sprintf("%s.%s AS %s",
+							q.quote(join.Alias), q.quote(columnName), q.quote(join.Alias+"_"+columnName)))
 					}
 				} else {
 					// Fallback to wildcard if schema not available
-					selectParts = append(selectParts, fmt.Sprintf("%s.*", join.Alias))
+					selectParts = append(selectParts, fmt.Sprintf("%s.*", q.quote(join.Alias)))
 				}
 			}
 
 			return fmt.Sprintf("SELECT %s%s", distinctStr, strings.Join(selectParts, ", "))
 		} else {
 			// No joins, simple case
-			return fmt.Sprintf("SELECT %s%s.*", distinctStr, q.tableAlias)
+			return fmt.Sprintf("SELECT %s%s.*", distinctStr, q.quote(q.tableAlias))
 		}
 	}
 
@@ -456,7 +587,7 @@ func (q *SelectQueryImpl) buildSelectClause() string {
 				columnName = fieldName
 			}
 			// Add table alias
-			columnNames[i] = fmt.Sprintf("%s.%s", q.tableAlias, columnName)
+			columnNames[i] = fmt.Sprintf("%s.%s", q.quote(q.tableAlias), q.quote(columnName))
 		}
 	}
 
@@ -472,6 +603,7 @@ func (q *SelectQueryImpl) buildWhereClause() (string, []any, error) {
 	// Create condition context
 	ctx := types.NewConditionContext(q.fieldMapper, q.modelName, q.tableAlias)
 	ctx.QuoteIdentifier = q.database.GetCapabilities().QuoteIdentifier
+	ctx.Capabilities = q.database.GetCapabilities()
 
 	// Combine all conditions with AND
 	var conditionSQLs []string
@@ -489,7 +621,7 @@ func (q *SelectQueryImpl) buildWhereClause() (string, []any, error) {
 		return "", nil, nil
 	}
 
-	whereSQL := fmt.Sprintf("WHERE %s", strings.Join(conditionSQLs, " AND "))
+	whereSQL := "WHERE " + joinConditionSQL(conditionSQLs)
 	return whereSQL, args, nil
 }
 
@@ -501,24 +633,39 @@ func (q *SelectQueryImpl) buildOrderByClause() (string, error) {
 
 	var orderParts []string
 	for _, order := range q.orderBy {
-		columnName, err := q.fieldMapper.SchemaToColumn(q.modelName, order.FieldName)
-		if err != nil {
-			return "", fmt.Errorf("failed to map field name %s: %w", order.FieldName, err)
-		}
-
 		direction := "ASC"
-		nullsLast := true // We want NULL values at the end by default
 		if order.Direction == types.DESC {
 			direction = "DESC"
 		}
 
-		// Get database-specific NULL ordering SQL
-		nullsClause := q.database.GetCapabilities().GetNullsOrderingSQL(order.Direction, !nullsLast)
+		// Relations without records have no joined count
+		if order.RelationCount {
+			orderParts = append(orderParts, fmt.Sprintf("COALESCE(%s.record_count, 0) %s",
+				q.quote(q.relationCountAlias(order.FieldName)), direction))
+			continue
+		}
+
+		columnName, err := q.fieldMapper.SchemaToColumn(q.modelName, order.FieldName)
+		if err != nil {
+			return "", fmt.Errorf("failed to map field name %s: %w", order.FieldName, err)
+		}
 
 		// Add table alias if present to avoid ambiguity
-		fullColumnName := columnName
+		fullColumnName := q.quote(columnName)
 		if q.tableAlias != "" {
-			fullColumnName = fmt.Sprintf("%s.%s", q.tableAlias, columnName)
+			fullColumnName = fmt.Sprintf("%s.%s", q.quote(q.tableAlias), fullColumnName)
+		}
+
+		// Get database-specific NULL ordering SQL, with NULL values at the end by default
+		nullsFirst := order.Nulls == types.NullsFirst
+		nullsClause := q.database.GetCapabilities().GetNullsOrderingSQL(order.Direction, nullsFirst)
+		if nullsClause == "" && order.Nulls != types.NullsDefault {
+			// Without NULLS FIRST/LAST, NULL values are placed by ordering on IS NULL first
+			isNullDirection := "ASC"
+			if nullsFirst {
+				isNullDirection = "DESC"
+			}
+			orderParts = append(orderParts, fmt.Sprintf("%s IS NULL %s", fullColumnName, isNullDirection))
 		}
 
 		orderParts = append(orderParts, fmt.Sprintf("%s %s%s", fullColumnName, direction, nullsClause))
@@ -527,6 +674,63 @@ func (q *SelectQueryImpl) buildOrderByClause() (string, error) {
 	return fmt.Sprintf("ORDER BY %s", strings.Join(orderParts, ", ")), nil
 }
 
+// buildRelationCountJoins builds the LEFT JOINs of the record counts of the relations the
+// query is ordered by, counted per value of the foreign key
+func (q *SelectQueryImpl) buildRelationCountJoins() (string, error) {
+	var joins []string
+	joined := make(map[string]bool)
+	for _, order := range q.orderBy {
+		if !order.RelationCount || joined[order.FieldName] {
+			continue
+		}
+		joined[order.FieldName] = true
+
+		localSchema, err := q.database.GetModelSchema(q.modelName)
+		if err != nil {
+			return "", err
+		}
+		relation, err := localSchema.GetRelation(order.FieldName)
+		if err != nil {
+			return "", err
+		}
+		if relation.Type != schema.RelationOneToMany {
+			return "", fmt.Errorf("ordering by the count of relation %s requires a one-to-many relation", order.FieldName)
+		}
+		relatedSchema, err := q.database.GetModelSchema(relation.Model)
+		if err != nil {
+			return "", err
+		}
+
+		localField, relatedField := relation.JoinFields(localSchema)
+		localColumn, err := localSchema.GetColumnNameByFieldName(localField)
+		if err != nil {
+			return "", err
+		}
+		relatedColumn, err := relatedSchema.GetColumnNameByFieldName(relatedField)
+		if err != nil {
+			return "", err
+		}
+
+		alias := q.quote(q.relationCountAlias(order.FieldName))
+		relatedColumn = q.quote(relatedColumn)
+		joins = append(joins, fmt.Sprintf(
+			"LEFT JOIN (SELECT %s, COUNT(*) AS record_count FROM %s GROUP BY %s) AS %s ON %s.%s = %s.%s",
+			relatedColumn, q.quote(relatedSchema.GetTableName()), relatedColumn, alias,
+			alias, relatedColumn, q.quote(q.tableAlias), q.quote(localColumn)))
+	}
+	return strings.Join(joins, " "), nil
+}
+
+// relationCountAlias returns the alias of the joined record counts of a relation
+func (q *SelectQueryImpl) relationCountAlias(relationName string) string {
+	return q.tableAlias + "_" + relationName + "_count"
+}
+
+// quote quotes an identifier for the database
+func (q *SelectQueryImpl) quote(name string) string {
+	return q.database.GetCapabilities().QuoteIdentifier(name)
+}
+
 // buildGroupByClause builds the GROUP BY part of the query
 func (q *SelectQueryImpl) buildGroupByClause() (string, error) {
 	if len(q.groupBy) == 0 {
@@ -537,6 +741,9 @@ func (q *SelectQueryImpl) buildGroupByClause() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed to map group by fields: %w", err)
 	}
+	for i, columnName := range columnNames {
+		columnNames[i] = q.quote(columnName)
+	}
 
 	return fmt.Sprintf("GROUP BY %s", strings.Join(columnNames, ", ")), nil
 }
@@ -550,6 +757,7 @@ func (q *SelectQueryImpl) buildHavingClause() (string, []any, error) {
 	// Create condition context
 	ctx := types.NewConditionContext(q.fieldMapper, q.modelName, q.tableAlias)
 	ctx.QuoteIdentifier = q.database.GetCapabilities().QuoteIdentifier
+	ctx.Capabilities = q.database.GetCapabilities()
 
 	sql, args := q.having.ToSQL(ctx)
 	if sql == "" {
@@ -621,7 +829,7 @@ func (q *SelectQueryImpl) buildCountSQL() (string, []any, error) {
 			if err != nil {
 				return "", nil, fmt.Errorf("failed to map field %s: %w", field, err)
 			}
-			distinctCols = append(distinctCols, col)
+			distinctCols = append(distinctCols, q.quote(col))
 		}
 		countExpr = fmt.Sprintf("COUNT(DISTINCT %s)", strings.Join(distinctCols, ", "))
 	} else if q.distinct {
@@ -632,7 +840,7 @@ func (q *SelectQueryImpl) buildCountSQL() (string, []any, error) {
 		countExpr = "COUNT(*)"
 	}
 
-	countSQL := fmt.Sprintf("SELECT %s FROM %s", countExpr, tableName)
+	countSQL := fmt.Sprintf("SELECT %s FROM %s", countExpr, q.quote(tableName))
 
 	if whereClause != "" {
 		countSQL += " " + whereClause
@@ -648,9 +856,9 @@ func (q *SelectQueryImpl) buildCountSQL() (string, []any, error) {
 }
 
 // findManyWithRelations executes the query and scans results with relation support
-func (q *SelectQueryImpl) findManyWithRelations(_ context.Context, sql string, args []any, dest any) error {
+func (q *SelectQueryImpl) findManyWithRelations(ctx context.Context, sql string, args []any, dest any) error {
 	// Execute query using database's Query method
-	rows, err := q.database.Query(sql, args...)
+	rows, err := queryRows(ctx, q.database, sql, args...)
 	if err != nil {
 		return fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -682,6 +890,7 @@ func (q *SelectQueryImpl) findManyWithRelations(_ context.Context, sql string, a
 			processor := NewIncludeProcessor(q.database, q.fieldMapper, q.includeOptions)
 			scanner.SetIncludeProcessor(processor)
 		}
+		scanner.SetCapabilities(q.database.GetCapabilities())
 
 		// Add joined table information to scanner
 		for _, join := range q.joinBuilder.GetJoinedTables() {
@@ -701,6 +910,7 @@ func (q *SelectQueryImpl) findManyWithRelations(_ context.Context, sql string, a
 			processor := NewIncludeProcessor(q.database, q.fieldMapper, q.includeOptions)
 			scanner.SetIncludeProcessor(processor)
 		}
+		scanner.SetCapabilities(q.database.GetCapabilities())
 
 		// Add joined table information to scanner
 		for _, join := range q.joinBuilder.GetJoinedTables() {
@@ -715,9 +925,9 @@ func (q *SelectQueryImpl) findManyWithRelations(_ context.Context, sql string, a
 }
 
 // findManyMapsWithFieldMapping executes the query and scans results into maps with field name mapping
-func (q *SelectQueryImpl) findManyMapsWithFieldMapping(_ context.Context, sql string, args []any, dest any) error {
+func (q *SelectQueryImpl) findManyMapsWithFieldMapping(ctx context.Context, sql string, args []any, dest any) error {
 	// Execute query using database's Query method
-	rows, err := q.database.Query(sql, args...)
+	rows, err := queryRows(ctx, q.database, sql, args...)
 	if err != nil {
 		return fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -773,7 +983,7 @@ func (q *SelectQueryImpl) findManyMapsWithFieldMapping(_ context.Context, sql st
 				fieldName = mapped
 			}
 
-			rowMap[fieldName] = val
+			rowMap[fieldName] = decodeFieldValue(q.database.GetCapabilities(), mainSchema, fieldName, val)
 		}
 		results = append(results, rowMap)
 	}
@@ -790,9 +1000,9 @@ func (q *SelectQueryImpl) findManyMapsWithFieldMapping(_ context.Context, sql st
 }
 
 // findOneMapsWithFieldMapping executes the query and scans a single result into a map with field name mapping
-func (q *SelectQueryImpl) findOneMapsWithFieldMapping(_ context.Context, sql string, args []any, dest any) error {
+func (q *SelectQueryImpl) findOneMapsWithFieldMapping(ctx context.Context, sql string, args []any, dest any) error {
 	// Execute query using database's Query method
-	rows, err := q.database.Query(sql, args...)
+	rows, err := queryRows(ctx, q.database, sql, args...)
 	if err != nil {
 		return fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -847,7 +1057,7 @@ func (q *SelectQueryImpl) findOneMapsWithFieldMapping(_ context.Context, sql str
 			fieldName = mapped
 		}
 
-		rowMap[fieldName] = val
+		rowMap[fieldName] = decodeFieldValue(q.database.GetCapabilities(), mainSchema, fieldName, val)
 	}
 
 	// Set the map to the destination
@@ -877,8 +1087,10 @@ func (q *SelectQueryImpl) GetOrderBy() []types.OrderByClause {
 	result := make([]types.OrderByClause, len(q.orderBy))
 	for i, clause := range q.orderBy {
 		result[i] = types.OrderByClause{
-			Field:     clause.FieldName,
-			Direction: clause.Direction,
+			Field:         clause.FieldName,
+			Direction:     clause.Direction,
+			Nulls:         clause.Nulls,
+			RelationCount: clause.RelationCount,
 		}
 	}
 	return result
@@ -923,6 +1135,7 @@ func (q *SelectQueryImpl) clone() *SelectQueryImpl {
 		distinct:       q.distinct,
 		distinctOn:     append([]string{}, q.distinctOn...),
 		joinBuilder:    NewJoinBuilderWithReservedAliases(q.database, q.tableAlias),
+		loadStrategy:   q.loadStrategy,
 	}
 
 	// Copy existing joins if any
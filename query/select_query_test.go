@@ -97,12 +97,12 @@ func TestSelectQuery_Include(t *testing.T) {
 	assert.Contains(t, sql, "posts")
 
 	// Verify columns are aliased to avoid ambiguity
-	assert.Contains(t, sql, "u.id AS u_id")
-	assert.Contains(t, sql, "u.name AS u_name")
-	assert.Contains(t, sql, "u.email AS u_email")
-	assert.Contains(t, sql, "p.id AS p_id")
-	assert.Contains(t, sql, "p.title AS p_title")
-	assert.Contains(t, sql, "p.user_id AS p_user_id")
+	assert.Contains(t, sql, "`u`.`id` AS `u_id`")
+	assert.Contains(t, sql, "`u`.`name` AS `u_name`")
+	assert.Contains(t, sql, "`u`.`email` AS `u_email`")
+	assert.Contains(t, sql, "`p`.`id` AS `p_id`")
+	assert.Contains(t, sql, "`p`.`title` AS `p_title`")
+	assert.Contains(t, sql, "`p`.`user_id` AS `p_user_id`")
 }
 
 func TestSelectQuery_IncludeWithWhere(t *testing.T) {
@@ -153,3 +153,39 @@ func TestSelectQuery_IncludeWithWhere(t *testing.T) {
 	// Verify join is present
 	assert.Contains(t, sql, "LEFT JOIN")
 }
+
+func TestSelectQuery_OrderByNullsAndRelationCount(t *testing.T) {
+	db := &mockDatabase{
+		schemas: make(map[string]*schema.Schema),
+	}
+	userSchema := schema.New("User").
+		AddField(schema.Field{Name: "id", Type: schema.FieldTypeInt, PrimaryKey: true}).
+		AddField(schema.Field{Name: "name", Type: schema.FieldTypeString, Nullable: true}).
+		AddRelation("posts", schema.Relation{
+			Type:       schema.RelationOneToMany,
+			Model:      "Post",
+			ForeignKey: "userId",
+			References: "id",
+		})
+	postSchema := schema.New("Post").
+		AddField(schema.Field{Name: "id", Type: schema.FieldTypeInt, PrimaryKey: true}).
+		AddField(schema.Field{Name: "userId", Type: schema.FieldTypeInt})
+	db.RegisterSchema("User", userSchema)
+	db.RegisterSchema("Post", postSchema)
+
+	selectQuery := NewSelectQuery(NewModelQuery("User", db, &mockFieldMapper{}), []string{})
+
+	// Without NULLS FIRST/LAST, null values are placed by ordering on IS NULL
+	sql, _, err := selectQuery.OrderByNulls("name", types.DESC, types.NullsFirst).BuildSQL()
+	require.NoError(t, err)
+	assert.Contains(t, sql, "ORDER BY `u`.`name` IS NULL DESC, `u`.`name` DESC")
+
+	// Relation counts are joined per foreign key
+	sql, _, err = selectQuery.OrderByRelationCount("posts", types.DESC).BuildSQL()
+	require.NoError(t, err)
+	assert.Contains(t, sql, "LEFT JOIN (SELECT `user_id`, COUNT(*) AS record_count FROM `posts` GROUP BY `user_id`) AS `u_posts_count` ON `u_posts_count`.`user_id` = `u`.`id`")
+	assert.Contains(t, sql, "ORDER BY COALESCE(`u_posts_count`.record_count, 0) DESC")
+
+	_, _, err = selectQuery.OrderByRelationCount("name", types.DESC).BuildSQL()
+	assert.Error(t, err)
+}
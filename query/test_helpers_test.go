@@ -4,10 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	"github.com/rediwo/redi-orm/logger"
 	"github.com/rediwo/redi-orm/schema"
 	"github.com/rediwo/redi-orm/types"
+	"github.com/rediwo/redi-orm/utils"
 )
 
 // mockDatabase is a shared mock implementation for testing
@@ -46,6 +48,15 @@ func (m *mockDatabase) GetModels() []string { return nil }
 func (m *mockDatabase) GetModelSchema(modelName string) (*schema.Schema, error) {
 	return m.GetSchema(modelName)
 }
+func (m *mockDatabase) GetModelField(modelName, fieldName string) (*schema.Field, error) {
+	return nil, fmt.Errorf("field not found")
+}
+func (m *mockDatabase) GetModelRelations(modelName string) ([]types.RelationInfo, error) {
+	return nil, nil
+}
+func (m *mockDatabase) ResolveRelation(modelName, relationName string) (types.RelationInfo, error) {
+	return types.RelationInfo{}, fmt.Errorf("relation not found")
+}
 func (m *mockDatabase) ResolveTableName(modelName string) (string, error)            { return "", nil }
 func (m *mockDatabase) ResolveFieldName(modelName, fieldName string) (string, error) { return "", nil }
 func (m *mockDatabase) ResolveFieldNames(modelName string, fieldNames []string) ([]string, error) {
@@ -125,6 +136,50 @@ func (m *mockCapabilities) GetNullsOrderingSQL(direction types.Order, nullsFirst
 	return ""
 }
 
+func (m *mockCapabilities) GetUpsertSQL(conflictColumns []string, updateColumns []string) string {
+	assignments := make([]string, len(updateColumns))
+	for i, column := range updateColumns {
+		assignments[i] = m.QuoteIdentifier(column) + " = ?"
+	}
+	target := make([]string, len(conflictColumns))
+	for i, column := range conflictColumns {
+		target[i] = m.QuoteIdentifier(column)
+	}
+	return fmt.Sprintf(" ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(target, ", "), strings.Join(assignments, ", "))
+}
+
+func (m *mockCapabilities) GetSkipDuplicatesSQL(insertColumns []string) string {
+	return " ON CONFLICT DO NOTHING"
+}
+
+func (m *mockCapabilities) GetArrayAppendSQL(quotedColumn string) string {
+	return fmt.Sprintf("array_cat(COALESCE(%s, '{}'), ?)", quotedColumn)
+}
+
+func (m *mockCapabilities) GetArrayFilterSQL(operator string, quotedColumn string) string {
+	switch operator {
+	case "has":
+		return fmt.Sprintf("? = ANY(%s)", quotedColumn)
+	case "hasSome":
+		return fmt.Sprintf("%s && ?", quotedColumn)
+	case "hasEvery":
+		return fmt.Sprintf("%s @> ?", quotedColumn)
+	case "isEmpty":
+		return fmt.Sprintf("cardinality(%s) = 0", quotedColumn)
+	case "isNotEmpty":
+		return fmt.Sprintf("cardinality(%s) > 0", quotedColumn)
+	}
+	return ""
+}
+
+func (m *mockCapabilities) EncodeArrayValue(values []any) (any, error) {
+	return utils.EncodeJSONArray(values)
+}
+
+func (m *mockCapabilities) DecodeArrayValue(value any) ([]any, error) {
+	return utils.DecodeJSONArray(value)
+}
+
 func (m *mockCapabilities) RequiresLimitForOffset() bool {
 	return true
 }
@@ -165,6 +220,14 @@ func (m *mockCapabilities) SupportsTransactions() bool {
 	return true
 }
 
+func (m *mockCapabilities) SupportsSavepoints() bool {
+	return true
+}
+
+func (m *mockCapabilities) SupportsTransactionalDDL() bool {
+	return false
+}
+
 func (m *mockCapabilities) SupportsNestedDocuments() bool {
 	return false
 }
@@ -180,3 +243,19 @@ func (m *mockCapabilities) SupportsAggregationPipeline() bool {
 func (m *mockCapabilities) SupportsForeignKeys() bool {
 	return true
 }
+
+func (m *mockCapabilities) SupportsUpsert() bool {
+	return true
+}
+
+func (m *mockCapabilities) SupportsJSONOperators() bool {
+	return false
+}
+
+func (m *mockCapabilities) SupportsFullText() bool {
+	return false
+}
+
+func (m *mockCapabilities) MaxParameters() int {
+	return 0
+}
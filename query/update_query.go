@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/rediwo/redi-orm/types"
 )
@@ -19,8 +20,8 @@ type UpdateQueryImpl struct {
 }
 
 type AtomicOperation struct {
-	Type  string // "increment", "decrement"
-	Value int64
+	Type  string // "increment", "decrement", "multiply", "push"
+	Value any    // A number, or the []any of values to push
 }
 
 // NewUpdateQuery creates a new update query
@@ -98,7 +99,7 @@ func (q *UpdateQueryImpl) Returning(fieldNames ...string) types.UpdateQuery {
 }
 
 // Increment adds an atomic increment operation
-func (q *UpdateQueryImpl) Increment(fieldName string, value int64) types.UpdateQuery {
+func (q *UpdateQueryImpl) Increment(fieldName string, value any) types.UpdateQuery {
 	newQuery := q.clone()
 	newQuery.atomicOps[fieldName] = AtomicOperation{
 		Type:  "increment",
@@ -108,7 +109,7 @@ func (q *UpdateQueryImpl) Increment(fieldName string, value int64) types.UpdateQ
 }
 
 // Decrement adds an atomic decrement operation
-func (q *UpdateQueryImpl) Decrement(fieldName string, value int64) types.UpdateQuery {
+func (q *UpdateQueryImpl) Decrement(fieldName string, value any) types.UpdateQuery {
 	newQuery := q.clone()
 	newQuery.atomicOps[fieldName] = AtomicOperation{
 		Type:  "decrement",
@@ -117,13 +118,46 @@ func (q *UpdateQueryImpl) Decrement(fieldName string, value int64) types.UpdateQ
 	return newQuery
 }
 
+// Multiply adds an atomic multiply operation
+func (q *UpdateQueryImpl) Multiply(fieldName string, value any) types.UpdateQuery {
+	newQuery := q.clone()
+	newQuery.atomicOps[fieldName] = AtomicOperation{
+		Type:  "multiply",
+		Value: value,
+	}
+	return newQuery
+}
+
+// Push adds an atomic operation appending values to an array field
+func (q *UpdateQueryImpl) Push(fieldName string, values ...any) types.UpdateQuery {
+	newQuery := q.clone()
+	newQuery.atomicOps[fieldName] = AtomicOperation{
+		Type:  "push",
+		Value: values,
+	}
+	return newQuery
+}
+
 // Exec executes the update query
 func (q *UpdateQueryImpl) Exec(ctx context.Context) (types.Result, error) {
+	ctx, cancel := q.StatementContext(ctx)
+	defer cancel()
+	defer types.ForgetDeduplicated(ctx)
+
 	sql, args, err := q.BuildSQL()
 	if err != nil {
 		return types.Result{}, fmt.Errorf("failed to build SQL: %w", err)
 	}
 
+	// Updates of IN lists longer than the driver allows run in a transaction, one per chunk
+	statements, err := q.splitStatements(sql, args)
+	if err != nil {
+		return types.Result{}, err
+	}
+	if len(statements) > 1 {
+		return execStatements(ctx, q.database, statements, "update")
+	}
+
 	rawQuery := q.database.Raw(sql, args...)
 	result, err := rawQuery.Exec(ctx)
 	if err != nil {
@@ -135,6 +169,10 @@ func (q *UpdateQueryImpl) Exec(ctx context.Context) (types.Result, error) {
 
 // ExecAndReturn executes the update and returns the updated data
 func (q *UpdateQueryImpl) ExecAndReturn(ctx context.Context, dest any) error {
+	ctx, cancel := q.StatementContext(ctx)
+	defer cancel()
+	defer types.ForgetDeduplicated(ctx)
+
 	if len(q.returningFields) == 0 {
 		return fmt.Errorf("no returning fields specified")
 	}
@@ -148,6 +186,12 @@ func (q *UpdateQueryImpl) ExecAndReturn(ctx context.Context, dest any) error {
 	return rawQuery.FindOne(ctx, dest)
 }
 
+// ToSQL returns the SQL of the query as the database runs it, with its arguments, without
+// executing it
+func (q *UpdateQueryImpl) ToSQL() (string, []any, error) {
+	return q.compileSQL(q.BuildSQL)
+}
+
 // BuildSQL builds the update SQL query
 func (q *UpdateQueryImpl) BuildSQL() (string, []any, error) {
 	if len(q.setData) == 0 && len(q.atomicOps) == 0 {
@@ -163,19 +207,27 @@ func (q *UpdateQueryImpl) BuildSQL() (string, []any, error) {
 	var sql strings.Builder
 	var args []any
 
-	sql.WriteString(fmt.Sprintf("UPDATE %s SET ", tableName))
+	sql.WriteString(fmt.Sprintf("UPDATE %s SET ", q.database.GetCapabilities().QuoteIdentifier(tableName)))
 
 	// Build SET clause
 	var setParts []string
 
+	if err := ValidateData(q.database, q.modelName, q.setData); err != nil {
+		return "", nil, err
+	}
+
 	// Add regular set operations
-	for fieldName, value := range q.setData {
+	for fieldName, value := range q.GetSetDataWithUpdatedAt() {
 		columnName, err := q.fieldMapper.SchemaToColumn(q.modelName, fieldName)
 		if err != nil {
 			return "", nil, fmt.Errorf("failed to map field %s: %w", fieldName, err)
 		}
 		quotedColumnName := q.database.GetCapabilities().QuoteIdentifier(columnName)
 		setParts = append(setParts, fmt.Sprintf("%s = ?", quotedColumnName))
+		value, err = encodeFieldValue(q.database, q.modelName, fieldName, value)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to encode value of field %s: %w", fieldName, err)
+		}
 		args = append(args, value)
 	}
 
@@ -192,6 +244,23 @@ func (q *UpdateQueryImpl) BuildSQL() (string, []any, error) {
 			setParts = append(setParts, fmt.Sprintf("%s = %s + ?", quotedColumnName, quotedColumnName))
 		case "decrement":
 			setParts = append(setParts, fmt.Sprintf("%s = %s - ?", quotedColumnName, quotedColumnName))
+		case "multiply":
+			setParts = append(setParts, fmt.Sprintf("%s = %s * ?", quotedColumnName, quotedColumnName))
+		case "push":
+			appendSQL := q.database.GetCapabilities().GetArrayAppendSQL(quotedColumnName)
+			if appendSQL == "" {
+				return "", nil, fmt.Errorf("database does not support push on array field %s", fieldName)
+			}
+			setParts = append(setParts, fmt.Sprintf("%s = %s", quotedColumnName, appendSQL))
+			values, _ := op.Value.([]any)
+			encoded, err := q.database.GetCapabilities().EncodeArrayValue(values)
+			if err != nil {
+				return "", nil, fmt.Errorf("failed to encode values to push to field %s: %w", fieldName, err)
+			}
+			args = append(args, encoded)
+			continue
+		default:
+			return "", nil, fmt.Errorf("unsupported atomic operation %s on field %s", op.Type, fieldName)
 		}
 		args = append(args, op.Value)
 	}
@@ -234,6 +303,7 @@ func (q *UpdateQueryImpl) buildWhereClause(conditions []types.Condition) (string
 	// Create condition context (no table alias for UPDATE)
 	ctx := types.NewConditionContext(q.fieldMapper, q.modelName, "")
 	ctx.QuoteIdentifier = q.database.GetCapabilities().QuoteIdentifier
+	ctx.Capabilities = q.database.GetCapabilities()
 
 	var conditionSQLs []string
 	var args []any
@@ -250,7 +320,7 @@ func (q *UpdateQueryImpl) buildWhereClause(conditions []types.Condition) (string
 		return "", nil, nil
 	}
 
-	whereSQL := fmt.Sprintf("WHERE %s", strings.Join(conditionSQLs, " AND "))
+	whereSQL := "WHERE " + joinConditionSQL(conditionSQLs)
 	return whereSQL, args, nil
 }
 
@@ -326,6 +396,12 @@ func (q *UpdateQueryImpl) GetSetData() map[string]any {
 	return q.setData
 }
 
+// GetSetDataWithUpdatedAt returns the set data plus the @updatedAt fields of the model,
+// which every update sets to the current time unless given explicitly
+func (q *UpdateQueryImpl) GetSetDataWithUpdatedAt() map[string]any {
+	return withUpdatedAtMap(q.database, q.modelName, q.setData, time.Now())
+}
+
 // GetAtomicOps returns the atomic operations
 func (q *UpdateQueryImpl) GetAtomicOps() map[string]AtomicOperation {
 	return q.atomicOps
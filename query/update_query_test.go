@@ -291,7 +291,7 @@ func TestUpdateQuery_BuildSQL(t *testing.T) {
 			modelName:     "User",
 			setData:       map[string]any{"name": "John", "email": "john@example.com"},
 			driverType:    "sqlite",
-			wantSQL:       "UPDATE users SET",
+			wantSQL:       "UPDATE `users` SET",
 			wantArgsCount: 2,
 		},
 		{
@@ -302,7 +302,7 @@ func TestUpdateQuery_BuildSQL(t *testing.T) {
 				&updateMockCondition{field: "id", value: "123"},
 			},
 			driverType:    "mysql",
-			wantSQL:       "UPDATE users SET `name` = ? WHERE",
+			wantSQL:       "UPDATE `users` SET `name` = ? WHERE",
 			wantArgsCount: 2,
 		},
 		{
@@ -313,7 +313,7 @@ func TestUpdateQuery_BuildSQL(t *testing.T) {
 				"loginCount": {Type: "increment", Value: 1},
 			},
 			driverType:    "postgresql",
-			wantSQL:       "UPDATE users SET `login_count` = `login_count` +",
+			wantSQL:       "UPDATE `users` SET `login_count` = `login_count` +",
 			wantArgsCount: 1,
 		},
 		{
@@ -324,16 +324,47 @@ func TestUpdateQuery_BuildSQL(t *testing.T) {
 				"loginCount": {Type: "decrement", Value: 5},
 			},
 			driverType:    "postgresql",
-			wantSQL:       "UPDATE users SET `login_count` = `login_count` -",
+			wantSQL:       "UPDATE `users` SET `login_count` = `login_count` -",
 			wantArgsCount: 1,
 		},
+		{
+			name:      "update with multiply",
+			modelName: "User",
+			setData:   map[string]any{},
+			atomicOps: map[string]AtomicOperation{
+				"loginCount": {Type: "multiply", Value: 2},
+			},
+			driverType:    "postgresql",
+			wantSQL:       "UPDATE `users` SET `login_count` = `login_count` * ?",
+			wantArgsCount: 1,
+		},
+		{
+			name:      "update with push",
+			modelName: "User",
+			setData:   map[string]any{},
+			atomicOps: map[string]AtomicOperation{
+				"name": {Type: "push", Value: []any{"a", "b"}},
+			},
+			driverType:    "postgresql",
+			wantSQL:       "UPDATE `users` SET `name` = array_cat(COALESCE(`name`, '{}'), ?)",
+			wantArgsCount: 1,
+		},
+		{
+			name:      "unknown atomic operation",
+			modelName: "User",
+			setData:   map[string]any{},
+			atomicOps: map[string]AtomicOperation{
+				"loginCount": {Type: "divide", Value: 2},
+			},
+			wantErr: true,
+		},
 		{
 			name:            "update with returning",
 			modelName:       "User",
 			setData:         map[string]any{"name": "John"},
 			returningFields: []string{"id", "updatedAt"},
 			driverType:      "postgresql",
-			wantSQL:         "UPDATE users SET `name` = ?",
+			wantSQL:         "UPDATE `users` SET `name` = ?",
 			wantArgsCount:   1,
 		},
 		{
@@ -350,7 +381,7 @@ func TestUpdateQuery_BuildSQL(t *testing.T) {
 				"loginCount": {Type: "increment", Value: 1},
 			},
 			driverType:    "sqlite",
-			wantSQL:       "UPDATE users SET",
+			wantSQL:       "UPDATE `users` SET",
 			wantArgsCount: 2,
 		},
 	}
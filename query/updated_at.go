@@ -0,0 +1,41 @@
+package query
+
+import (
+	"maps"
+	"time"
+
+	"github.com/rediwo/redi-orm/types"
+)
+
+// WithUpdatedAt returns data with the @updatedAt fields of the model set to now. Fields the
+// data sets explicitly are kept, and data other than maps is returned unchanged.
+func WithUpdatedAt(database types.Database, modelName string, data any, now time.Time) any {
+	dataMap, ok := data.(map[string]any)
+	if !ok {
+		return data
+	}
+	return withUpdatedAtMap(database, modelName, dataMap, now)
+}
+
+// withUpdatedAtMap is WithUpdatedAt for map data. The original map is never modified.
+func withUpdatedAtMap(database types.Database, modelName string, data map[string]any, now time.Time) map[string]any {
+	modelSchema, err := database.GetModelSchema(modelName)
+	if err != nil {
+		return data
+	}
+
+	result := data
+	cloned := false
+	for _, field := range modelSchema.GetUpdatedAtFields() {
+		if _, ok := data[field]; ok {
+			continue
+		}
+		if !cloned {
+			result = make(map[string]any, len(data)+1)
+			maps.Copy(result, data)
+			cloned = true
+		}
+		result[field] = now
+	}
+	return result
+}
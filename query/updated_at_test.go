@@ -0,0 +1,90 @@
+package query
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rediwo/redi-orm/schema"
+)
+
+func TestWithUpdatedAt(t *testing.T) {
+	mockDB := &mockDatabase{}
+	mockDB.RegisterSchema("Post", schema.New("Post").
+		AddField(schema.Field{Name: "id", Type: schema.FieldTypeInt, PrimaryKey: true}).
+		AddField(schema.Field{Name: "title", Type: schema.FieldTypeString}).
+		AddField(schema.Field{Name: "updatedAt", Type: schema.FieldTypeDateTime, UpdatedAt: true}))
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	explicit := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		modelName string
+		data      any
+		want      any
+	}{
+		{
+			name:      "sets missing field",
+			modelName: "Post",
+			data:      map[string]any{"title": "Hello"},
+			want:      now,
+		},
+		{
+			name:      "keeps explicit value",
+			modelName: "Post",
+			data:      map[string]any{"title": "Hello", "updatedAt": explicit},
+			want:      explicit,
+		},
+		{
+			name:      "unknown model",
+			modelName: "Comment",
+			data:      map[string]any{"title": "Hello"},
+			want:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := tt.data.(map[string]any)
+			originalLen := len(original)
+
+			result := WithUpdatedAt(mockDB, tt.modelName, tt.data, now).(map[string]any)
+			if got := result["updatedAt"]; got != tt.want {
+				t.Errorf("WithUpdatedAt() updatedAt = %v, want %v", got, tt.want)
+			}
+			if len(original) != originalLen {
+				t.Error("WithUpdatedAt() modified the original data")
+			}
+		})
+	}
+}
+
+func TestUpdateQuery_BuildSQL_UpdatedAt(t *testing.T) {
+	mockDB := &mockDatabase{}
+	mockDB.RegisterSchema("Post", schema.New("Post").
+		AddField(schema.Field{Name: "id", Type: schema.FieldTypeInt, PrimaryKey: true}).
+		AddField(schema.Field{Name: "title", Type: schema.FieldTypeString}).
+		AddField(schema.Field{Name: "updatedAt", Type: schema.FieldTypeDateTime, UpdatedAt: true}))
+	mapper := &testFieldMapper{
+		mappings: map[string]map[string]string{
+			"Post": {"id": "id", "title": "title", "updatedAt": "updated_at"},
+		},
+	}
+
+	query := NewUpdateQuery(&ModelQueryImpl{
+		database:    mockDB,
+		modelName:   "Post",
+		fieldMapper: mapper,
+	}, map[string]any{"title": "Hello"})
+
+	sql, args, err := query.BuildSQL()
+	if err != nil {
+		t.Fatalf("BuildSQL() unexpected error: %v", err)
+	}
+	if !strings.Contains(sql, "`updated_at` = ?") {
+		t.Errorf("BuildSQL() SQL = %q, want updated_at to be set", sql)
+	}
+	if len(args) != 2 {
+		t.Errorf("BuildSQL() args count = %d, want 2", len(args))
+	}
+}
@@ -0,0 +1,145 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/rediwo/redi-orm/types"
+)
+
+// UpsertQueryImpl implements the UpsertQuery interface with the native upsert clause of
+// the driver (ON CONFLICT or ON DUPLICATE KEY UPDATE)
+type UpsertQueryImpl struct {
+	*ModelQueryImpl
+	create         any
+	update         any
+	conflictFields []string
+}
+
+// NewUpsertQuery creates a new upsert query. Without conflict fields the primary key is used.
+func NewUpsertQuery(baseQuery *ModelQueryImpl, create any, update any, conflictFields []string) *UpsertQueryImpl {
+	return &UpsertQueryImpl{
+		ModelQueryImpl: baseQuery,
+		create:         create,
+		update:         update,
+		conflictFields: conflictFields,
+	}
+}
+
+// GetCreateData returns the data inserted when no record conflicts
+func (q *UpsertQueryImpl) GetCreateData() any {
+	return q.create
+}
+
+// GetUpdateData returns the data applied to a conflicting record
+func (q *UpsertQueryImpl) GetUpdateData() any {
+	return q.update
+}
+
+// GetConflictFields returns the fields identifying an existing record, defaulting to the
+// primary key of the model
+func (q *UpsertQueryImpl) GetConflictFields() ([]string, error) {
+	if len(q.conflictFields) > 0 {
+		return q.conflictFields, nil
+	}
+
+	schema, err := q.database.GetModelSchema(q.modelName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema for model %s: %w", q.modelName, err)
+	}
+	if len(schema.CompositeKey) > 0 {
+		return schema.CompositeKey, nil
+	}
+	primaryKey, err := schema.GetPrimaryKey()
+	if err != nil {
+		return nil, fmt.Errorf("upsert requires conflict fields: %w", err)
+	}
+	return []string{primaryKey.Name}, nil
+}
+
+// Exec executes the upsert query
+func (q *UpsertQueryImpl) Exec(ctx context.Context) (types.Result, error) {
+	ctx, cancel := q.StatementContext(ctx)
+	defer cancel()
+	defer types.ForgetDeduplicated(ctx)
+
+	// UUIDs the model generates are set in the created record
+	generated := *q
+	generated.create = WithGeneratedUUIDs(q.database, q.modelName, q.create)
+	sql, args, err := generated.BuildSQL()
+	if err != nil {
+		return types.Result{}, fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	result, err := q.database.Raw(sql, args...).Exec(ctx)
+	if err != nil {
+		return types.Result{}, fmt.Errorf("failed to execute upsert: %w", err)
+	}
+	return result, nil
+}
+
+// ToSQL returns the SQL of the query as the database runs it, with its arguments, without
+// executing it
+func (q *UpsertQueryImpl) ToSQL() (string, []any, error) {
+	return q.compileSQL(q.BuildSQL)
+}
+
+// BuildSQL builds the INSERT statement followed by the upsert clause of the driver
+func (q *UpsertQueryImpl) BuildSQL() (string, []any, error) {
+	if !q.database.GetCapabilities().SupportsUpsert() {
+		return "", nil, fmt.Errorf("database does not support upsert")
+	}
+
+	conflictFields, err := q.GetConflictFields()
+	if err != nil {
+		return "", nil, err
+	}
+
+	insert := NewInsertQuery(q.ModelQueryImpl, q.create)
+	createFields, _, err := insert.extractFieldsAndValues(q.create)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to extract create data: %w", err)
+	}
+	for _, field := range conflictFields {
+		if !slices.Contains(createFields, field) {
+			return "", nil, fmt.Errorf("upsert create data must contain conflict field %s", field)
+		}
+	}
+
+	sql, args, err := insert.BuildSQL()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var updateFields []string
+	var updateValues []any
+	if q.update != nil {
+		update := WithUpdatedAt(q.database, q.modelName, q.update, time.Now())
+		updateFields, updateValues, err = insert.extractFieldsAndValues(update)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to extract update data: %w", err)
+		}
+		if err := ValidateFields(q.database, q.modelName, updateFields, updateValues); err != nil {
+			return "", nil, err
+		}
+		updateValues, err = encodeFieldValues(q.database, q.modelName, updateFields, updateValues)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to encode values: %w", err)
+		}
+	}
+
+	conflictColumns, err := q.fieldMapper.SchemaFieldsToColumns(q.modelName, conflictFields)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to map conflict fields: %w", err)
+	}
+	updateColumns, err := q.fieldMapper.SchemaFieldsToColumns(q.modelName, updateFields)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to map update fields: %w", err)
+	}
+
+	sql += q.database.GetCapabilities().GetUpsertSQL(conflictColumns, updateColumns)
+	args = append(args, updateValues...)
+	return sql, args, nil
+}
@@ -0,0 +1,89 @@
+package query
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rediwo/redi-orm/schema"
+)
+
+func TestUpsertQuery_BuildSQL(t *testing.T) {
+	mockDB := &mockDatabase{}
+	mockDB.RegisterSchema("User", schema.New("User").
+		AddField(schema.Field{Name: "id", Type: schema.FieldTypeInt, PrimaryKey: true}).
+		AddField(schema.Field{Name: "email", Type: schema.FieldTypeString, Unique: true}).
+		AddField(schema.Field{Name: "loginCount", Type: schema.FieldTypeInt}))
+	mapper := &testFieldMapper{
+		mappings: map[string]map[string]string{
+			"User": {
+				"id":         "id",
+				"email":      "email",
+				"loginCount": "login_count",
+			},
+		},
+	}
+	baseQuery := &ModelQueryImpl{
+		database:    mockDB,
+		modelName:   "User",
+		fieldMapper: mapper,
+	}
+
+	tests := []struct {
+		name           string
+		create         map[string]any
+		update         map[string]any
+		conflictFields []string
+		wantClause     string
+		wantArgsCount  int
+		wantErr        string
+	}{
+		{
+			name:           "update on unique field",
+			create:         map[string]any{"email": "a@example.com", "loginCount": 1},
+			update:         map[string]any{"loginCount": 2},
+			conflictFields: []string{"email"},
+			wantClause:     " ON CONFLICT (`email`) DO UPDATE SET `login_count` = ?",
+			wantArgsCount:  3,
+		},
+		{
+			name:          "defaults to primary key",
+			create:        map[string]any{"id": 1, "email": "a@example.com"},
+			update:        map[string]any{"email": "b@example.com"},
+			wantClause:    " ON CONFLICT (`id`) DO UPDATE SET `email` = ?",
+			wantArgsCount: 3,
+		},
+		{
+			name:           "conflict field missing from create data",
+			create:         map[string]any{"loginCount": 1},
+			update:         map[string]any{"loginCount": 2},
+			conflictFields: []string{"email"},
+			wantErr:        "must contain conflict field email",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := NewUpsertQuery(baseQuery, tt.create, tt.update, tt.conflictFields)
+			sql, args, err := query.BuildSQL()
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("BuildSQL() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BuildSQL() unexpected error: %v", err)
+			}
+			if !strings.HasPrefix(sql, "INSERT INTO `users`") {
+				t.Errorf("BuildSQL() SQL = %q, want INSERT INTO `users` prefix", sql)
+			}
+			if !strings.HasSuffix(sql, tt.wantClause) {
+				t.Errorf("BuildSQL() SQL = %q, want suffix %q", sql, tt.wantClause)
+			}
+			if len(args) != tt.wantArgsCount {
+				t.Errorf("BuildSQL() args count = %d, want %d", len(args), tt.wantArgsCount)
+			}
+		})
+	}
+}
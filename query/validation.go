@@ -0,0 +1,25 @@
+package query
+
+import (
+	"github.com/rediwo/redi-orm/types"
+)
+
+// ValidateData checks data against the validation rules of the model (@length, @regex,
+// @min, @max, @email). Broken rules are reported as a *schema.ValidationError.
+func ValidateData(database types.Database, modelName string, data map[string]any) error {
+	modelSchema, err := database.GetModelSchema(modelName)
+	if err != nil {
+		// Unknown models are reported by the query itself
+		return nil
+	}
+	return modelSchema.ValidateData(data)
+}
+
+// ValidateFields is ValidateData for data given as parallel field and value lists
+func ValidateFields(database types.Database, modelName string, fields []string, values []any) error {
+	data := make(map[string]any, len(fields))
+	for i, field := range fields {
+		data[field] = values[i]
+	}
+	return ValidateData(database, modelName, data)
+}
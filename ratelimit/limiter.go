@@ -0,0 +1,123 @@
+package ratelimit
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Limiter implements token bucket rate limiting per client. A client can make up to burst
+// requests at once and gets requestsPerMin tokens back per minute.
+type Limiter struct {
+	mu          sync.Mutex
+	clients     map[string]*bucket
+	limit       int
+	burst       int
+	cleanupTick *time.Ticker
+	done        chan struct{}
+	stopOnce    sync.Once
+}
+
+// bucket holds the tokens of a client
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// Result is the outcome of a rate limit check
+type Result struct {
+	Allowed    bool
+	Limit      int           // Requests per minute
+	Remaining  int           // Requests the client can make right away
+	RetryAfter time.Duration // Time until the next request is allowed, when not allowed
+}
+
+// NewLimiter creates a rate limiter. A burst of 0 defaults to a quarter of requestsPerMin.
+func NewLimiter(requestsPerMin, burst int) *Limiter {
+	if burst <= 0 {
+		burst = max(requestsPerMin/4, 1)
+	}
+	l := &Limiter{
+		clients:     make(map[string]*bucket),
+		limit:       requestsPerMin,
+		burst:       burst,
+		cleanupTick: time.NewTicker(5 * time.Minute),
+		done:        make(chan struct{}),
+	}
+	go l.cleanup()
+	return l
+}
+
+// Allow takes a token of the client identified by key, if it has one
+func (l *Limiter) Allow(key string) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	b, exists := l.clients[key]
+	if !exists {
+		b = &bucket{tokens: float64(l.burst), lastSeen: now}
+		l.clients[key] = b
+	}
+
+	// Refill the tokens earned since the last request
+	perSecond := float64(l.limit) / 60
+	b.tokens = math.Min(float64(l.burst), b.tokens+now.Sub(b.lastSeen).Seconds()*perSecond)
+	b.lastSeen = now
+
+	result := Result{Limit: l.limit}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else if perSecond > 0 {
+		result.RetryAfter = time.Duration((1 - b.tokens) / perSecond * float64(time.Second))
+	}
+	result.Remaining = int(b.tokens)
+	return result
+}
+
+// Clients returns the number of clients being tracked
+func (l *Limiter) Clients() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.clients)
+}
+
+// cleanup removes the buckets of clients that have been idle long enough to be full again
+func (l *Limiter) cleanup() {
+	for {
+		select {
+		case <-l.done:
+			return
+		case <-l.cleanupTick.C:
+			l.mu.Lock()
+			now := time.Now()
+			for key, b := range l.clients {
+				if now.Sub(b.lastSeen) > 10*time.Minute {
+					delete(l.clients, key)
+				}
+			}
+			l.mu.Unlock()
+		}
+	}
+}
+
+// Stop stops the cleanup routine of the limiter
+func (l *Limiter) Stop() {
+	l.stopOnce.Do(func() {
+		l.cleanupTick.Stop()
+		close(l.done)
+	})
+}
+
+// WriteHeaders sets the X-RateLimit-Limit and X-RateLimit-Remaining headers of the result,
+// and Retry-After in seconds when the request is not allowed
+func (r Result) WriteHeaders(w http.ResponseWriter) {
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(r.Limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(r.Remaining))
+	if !r.Allowed {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(r.RetryAfter.Seconds()))))
+	}
+}
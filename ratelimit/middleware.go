@@ -0,0 +1,177 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Ways of identifying the client a request is counted for
+const (
+	// ByIP counts requests per client IP address
+	ByIP = "ip"
+	// ByAPIKey counts requests per API key (X-API-Key or Authorization: Bearer), and requests
+	// without a key per IP address. Keys are not validated, so it belongs behind a proxy
+	// that authenticates them.
+	ByAPIKey = "api-key"
+)
+
+// Config holds the rate limits of an HTTP server
+type Config struct {
+	RequestsPerMin int            // Requests per minute per client, 0 for no limit
+	Burst          int            // Requests a client can make at once, a quarter of RequestsPerMin by default
+	By             string         // ByIP (default) or ByAPIKey
+	Endpoints      map[string]int // Requests per minute by path prefix, overriding RequestsPerMin; 0 for no limit
+	TrustProxy     bool           // Take client IPs from X-Forwarded-For and X-Real-IP
+}
+
+// Limits applies the rate limits of a Config to requests. Each endpoint with its own limit
+// has its own quota; the other paths share the default quota.
+type Limits struct {
+	by         string
+	trustProxy bool
+	prefixes   []string // Endpoint path prefixes, longest first
+	endpoints  map[string]*Limiter
+	fallback   *Limiter
+}
+
+// NewLimits creates the rate limits of config
+func NewLimits(config Config) (*Limits, error) {
+	switch config.By {
+	case "":
+		config.By = ByIP
+	case ByIP, ByAPIKey:
+	default:
+		return nil, fmt.Errorf("invalid rate limit key %q: must be %s or %s", config.By, ByIP, ByAPIKey)
+	}
+	if config.RequestsPerMin < 0 || config.Burst < 0 {
+		return nil, fmt.Errorf("rate limits cannot be negative")
+	}
+
+	l := &Limits{
+		by:         config.By,
+		trustProxy: config.TrustProxy,
+		endpoints:  make(map[string]*Limiter),
+	}
+	if config.RequestsPerMin > 0 {
+		l.fallback = NewLimiter(config.RequestsPerMin, config.Burst)
+	}
+	for prefix, limit := range config.Endpoints {
+		if !strings.HasPrefix(prefix, "/") {
+			return nil, fmt.Errorf("invalid rate limit endpoint %q: must be a path starting with /", prefix)
+		}
+		if limit < 0 {
+			return nil, fmt.Errorf("rate limit of %s cannot be negative", prefix)
+		}
+		l.prefixes = append(l.prefixes, prefix)
+		if limit > 0 {
+			l.endpoints[prefix] = NewLimiter(limit, 0)
+		}
+	}
+	sort.Slice(l.prefixes, func(i, j int) bool {
+		return len(l.prefixes[i]) > len(l.prefixes[j])
+	})
+	return l, nil
+}
+
+// ParseEndpoints parses per-endpoint limits written as "/graphql=30,/api/=120"
+func ParseEndpoints(value string) (map[string]int, error) {
+	endpoints := make(map[string]int)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		prefix, limit, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rate limit endpoint %q: expected /path=requestsPerMin", entry)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(limit))
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit of %s: %w", prefix, err)
+		}
+		endpoints[strings.TrimSpace(prefix)] = n
+	}
+	return endpoints, nil
+}
+
+// limiter returns the limiter of the endpoint of path, or nil when the path is not limited
+func (l *Limits) limiter(path string) *Limiter {
+	for _, prefix := range l.prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return l.endpoints[prefix]
+		}
+	}
+	return l.fallback
+}
+
+// Middleware rejects requests over their limit with 429 Too Many Requests, and reports the
+// remaining quota in the X-RateLimit-* headers. A nil Limits lets every request through.
+func (l *Limits) Middleware(next http.Handler) http.Handler {
+	if l == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limiter := l.limiter(r.URL.Path)
+		if limiter == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		result := limiter.Allow(l.clientKey(r))
+		result.WriteHeaders(w)
+		if !result.Allowed {
+			http.Error(w, "Too Many Requests: rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientKey identifies the client a request is counted for
+func (l *Limits) clientKey(r *http.Request) string {
+	if l.by == ByAPIKey {
+		key := r.Header.Get("X-API-Key")
+		if key == "" {
+			key, _ = strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		}
+		if key != "" {
+			return "key:" + key
+		}
+	}
+	return "ip:" + ClientIP(r, l.trustProxy)
+}
+
+// ClientIP returns the IP address of the client of a request. With trustProxy it is read from
+// the X-Forwarded-For or X-Real-IP header set by a reverse proxy.
+func ClientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+		}
+		if real := r.Header.Get("X-Real-IP"); real != "" {
+			return real
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// Stop stops the cleanup routines of the limiters
+func (l *Limits) Stop() {
+	if l == nil {
+		return
+	}
+	if l.fallback != nil {
+		l.fallback.Stop()
+	}
+	for _, limiter := range l.endpoints {
+		limiter.Stop()
+	}
+}
@@ -0,0 +1,146 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+	l := NewLimiter(60, 2)
+	defer l.Stop()
+
+	for i, remaining := range []int{1, 0} {
+		result := l.Allow("client")
+		if !result.Allowed || result.Remaining != remaining {
+			t.Errorf("Request %d: expected allowed with %d remaining, got %+v", i+1, remaining, result)
+		}
+	}
+
+	result := l.Allow("client")
+	if result.Allowed {
+		t.Fatal("Expected the request over the burst to be rejected")
+	}
+	if result.RetryAfter <= 0 || result.RetryAfter > time.Second {
+		t.Errorf("Expected to retry within a second at 60 req/min, got %v", result.RetryAfter)
+	}
+	if !l.Allow("other").Allowed {
+		t.Error("Expected other clients to have their own bucket")
+	}
+
+	// Tokens are refilled continuously, not in whole minutes
+	l.clients["client"].lastSeen = time.Now().Add(-1500 * time.Millisecond)
+	if !l.Allow("client").Allowed {
+		t.Error("Expected a token back after a second at 60 req/min")
+	}
+	if l.Clients() != 2 {
+		t.Errorf("Expected 2 clients, got %d", l.Clients())
+	}
+}
+
+func TestLimitsMiddleware(t *testing.T) {
+	limits, err := NewLimits(Config{
+		RequestsPerMin: 60,
+		Burst:          1,
+		Endpoints:      map[string]int{"/graphql": 4, "/api/public": 0},
+	})
+	if err != nil {
+		t.Fatalf("NewLimits() error = %v", err)
+	}
+	defer limits.Stop()
+
+	handler := limits.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	get := func(path, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/api/users", "10.0.0.1:1234")
+	if w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Limit") != "60" || w.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Errorf("Expected the default limit headers, got %d %v", w.Code, w.Header())
+	}
+	w = get("/api/posts", "10.0.0.1:5678")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected 429 with Retry-After for the same IP, got %d %v", w.Code, w.Header())
+	}
+	if w := get("/api/posts", "10.0.0.2:1234"); w.Code != http.StatusOK {
+		t.Errorf("Expected another IP to be allowed, got %d", w.Code)
+	}
+
+	// Endpoints with their own limit have their own quota
+	if w := get("/graphql", "10.0.0.1:1234"); w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Limit") != "4" {
+		t.Errorf("Expected the endpoint limit, got %d %v", w.Code, w.Header())
+	}
+	for range 5 {
+		if w := get("/api/public/stats", "10.0.0.1:1234"); w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Limit") != "" {
+			t.Fatalf("Expected an unlimited endpoint, got %d %v", w.Code, w.Header())
+		}
+	}
+}
+
+func TestLimitsClientKey(t *testing.T) {
+	limits, err := NewLimits(Config{RequestsPerMin: 60, By: ByAPIKey})
+	if err != nil {
+		t.Fatalf("NewLimits() error = %v", err)
+	}
+	defer limits.Stop()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	if key := limits.clientKey(req); key != "ip:10.0.0.1" {
+		t.Errorf("Expected the IP without an API key, got %s", key)
+	}
+	req.Header.Set("Authorization", "Bearer secret")
+	if key := limits.clientKey(req); key != "key:secret" {
+		t.Errorf("Expected the bearer token, got %s", key)
+	}
+	req.Header.Set("X-API-Key", "other")
+	if key := limits.clientKey(req); key != "key:other" {
+		t.Errorf("Expected the X-API-Key header, got %s", key)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+	if ip := ClientIP(req, false); ip != "10.0.0.1" {
+		t.Errorf("Expected X-Forwarded-For to be ignored without a trusted proxy, got %s", ip)
+	}
+	if ip := ClientIP(req, true); ip != "203.0.113.7" {
+		t.Errorf("Expected the forwarded client IP, got %s", ip)
+	}
+}
+
+func TestNewLimitsErrors(t *testing.T) {
+	for _, config := range []Config{
+		{RequestsPerMin: 60, By: "user"},
+		{RequestsPerMin: -1},
+		{Endpoints: map[string]int{"graphql": 10}},
+		{Endpoints: map[string]int{"/graphql": -5}},
+	} {
+		if _, err := NewLimits(config); err == nil {
+			t.Errorf("Expected an error for %+v", config)
+		}
+	}
+}
+
+func TestParseEndpoints(t *testing.T) {
+	endpoints, err := ParseEndpoints("/graphql=30, /api/=120")
+	if err != nil {
+		t.Fatalf("ParseEndpoints() error = %v", err)
+	}
+	if len(endpoints) != 2 || endpoints["/graphql"] != 30 || endpoints["/api/"] != 120 {
+		t.Errorf("Unexpected endpoints %v", endpoints)
+	}
+	if _, err := ParseEndpoints("/graphql"); err == nil {
+		t.Error("Expected an error without a limit")
+	}
+	if _, err := ParseEndpoints("/graphql=fast"); err == nil {
+		t.Error("Expected an error for a non-numeric limit")
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/rediwo/redi-orm/orm"
+	"github.com/rediwo/redi-orm/rest/types"
+)
+
+// Aggregate handles aggregating the records of a model, as GET /api/{model}/aggregate
+// with query parameters or POST with the options of the ORM as JSON
+func (h *DataHandler) Aggregate(w http.ResponseWriter, r *http.Request) {
+	h.aggregate(w, r, "aggregate")
+}
+
+// GroupBy handles grouping the records of a model and aggregating each group, as
+// GET /api/{model}/groupBy with query parameters or POST with the options of the ORM as JSON
+func (h *DataHandler) GroupBy(w http.ResponseWriter, r *http.Request) {
+	h.aggregate(w, r, "groupBy")
+}
+
+// aggregate runs an aggregate or groupBy operation of the ORM. The result keeps the
+// nested structure of the ORM, as in {"_count": {...}, "_sum": {...}}.
+func (h *DataHandler) aggregate(w http.ResponseWriter, r *http.Request, operation string) {
+	start := time.Now()
+	modelName := extractModelName(r.URL.Path)
+	connectionName := r.Header.Get("X-Connection-Name")
+
+	db, err := h.connHandler.GetConnection(connectionName)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, types.NewErrorResponse("NO_CONNECTION", "No database connection available"))
+		return
+	}
+
+	var options map[string]any
+	switch r.Method {
+	case http.MethodGet:
+		options, err = types.ParseAggregateParams(r.URL.Query())
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, types.NewErrorResponse("INVALID_PARAMS", "Invalid query parameters", err.Error()))
+			return
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&options); err != nil {
+			writeJSON(w, http.StatusBadRequest, types.NewErrorResponse("INVALID_REQUEST", "Invalid request body", err.Error()))
+			return
+		}
+		if options == nil {
+			options = make(map[string]any)
+		}
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, types.NewErrorResponse("METHOD_NOT_ALLOWED", "Only GET or POST methods are allowed"))
+		return
+	}
+
+	if _, err := db.GetSchema(modelName); err != nil {
+		writeJSON(w, http.StatusNotFound, types.NewErrorResponse("MODEL_NOT_FOUND", "Model not found", err.Error()))
+		return
+	}
+	if _, ok := options["by"]; operation == "groupBy" && !ok {
+		writeJSON(w, http.StatusBadRequest, types.NewErrorResponse("INVALID_PARAMS", "groupBy requires the fields to group by", "missing by"))
+		return
+	}
+
+	query, err := json.Marshal(map[string]any{operation: options})
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, types.NewErrorResponse("INVALID_PARAMS", "Invalid aggregation options", err.Error()))
+		return
+	}
+	result, err := orm.NewClient(db).Model(modelName).QueryContext(r.Context(), string(query))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, types.NewErrorResponse("QUERY_ERROR", "Failed to execute query", err.Error()))
+		return
+	}
+
+	response := types.NewSuccessResponse(h.masking.Apply(r.Context(), db, modelName, result)).WithExecutionTime(time.Since(start))
+	writeJSON(w, http.StatusOK, response)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"slices"
 	"sync"
 
 	"github.com/rediwo/redi-orm/database"
@@ -57,7 +58,7 @@ func (h *ConnectionHandler) Connect(w http.ResponseWriter, r *http.Request) {
 
 	// Check if connection already exists
 	h.mu.RLock()
-	if _, exists := h.connections[req.Name]; exists {
+	if _, exists := h.connections[req.Name]; exists || slices.Contains(database.Connections(), req.Name) {
 		h.mu.RUnlock()
 		writeJSON(w, http.StatusConflict, types.NewErrorResponse("CONNECTION_EXISTS", "Connection with this name already exists"))
 		return
@@ -163,12 +164,25 @@ func (h *ConnectionHandler) List(w http.ResponseWriter, r *http.Request) {
 			"status": "connected",
 		})
 	}
+	for _, name := range database.Connections() {
+		if _, exists := h.connections[name]; exists {
+			continue
+		}
+		if db, err := database.Connection(name); err == nil {
+			connections = append(connections, map[string]string{
+				"name":   name,
+				"driver": db.GetDriverType(),
+				"status": "connected",
+			})
+		}
+	}
 	h.mu.RUnlock()
 
 	writeJSON(w, http.StatusOK, types.NewSuccessResponse(connections))
 }
 
-// GetConnection returns a database connection by name
+// GetConnection returns a database connection by name. Connections made through this
+// handler come first, then those registered with database.Register.
 func (h *ConnectionHandler) GetConnection(name string) (database.Database, error) {
 	if name == "" {
 		name = "default"
@@ -179,6 +193,9 @@ func (h *ConnectionHandler) GetConnection(name string) (database.Database, error
 	h.mu.RUnlock()
 
 	if !exists {
+		if db, err := database.Connection(name); err == nil {
+			return db, nil
+		}
 		return nil, errors.New("no database connection")
 	}
 
@@ -3,14 +3,17 @@ package handlers
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/rediwo/redi-orm/logger"
+	"github.com/rediwo/redi-orm/masking"
 	"github.com/rediwo/redi-orm/rest/services"
 	"github.com/rediwo/redi-orm/rest/types"
+	"github.com/rediwo/redi-orm/schema"
 	ormTypes "github.com/rediwo/redi-orm/types"
 )
 
@@ -19,6 +22,7 @@ type DataHandler struct {
 	connHandler  *ConnectionHandler
 	queryBuilder *services.QueryBuilder
 	logger       logger.Logger
+	masking      *masking.Policy
 }
 
 // NewDataHandler creates a new data handler
@@ -30,6 +34,11 @@ func NewDataHandler(connHandler *ConnectionHandler, l logger.Logger) *DataHandle
 	}
 }
 
+// SetMaskingPolicy sets the policy masking sensitive fields of returned records
+func (h *DataHandler) SetMaskingPolicy(policy *masking.Policy) {
+	h.masking = policy
+}
+
 // Find handles finding multiple records
 func (h *DataHandler) Find(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -54,6 +63,12 @@ func (h *DataHandler) Find(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Stream all the records as CSV or NDJSON when the client accepts them
+	if format := exportFormat(r); format != "" {
+		h.export(w, r, db, modelName, params, format)
+		return
+	}
+
 	// Build query
 	query, err := h.queryBuilder.BuildFindQuery(db, modelName, params)
 	if err != nil {
@@ -70,7 +85,7 @@ func (h *DataHandler) Find(w http.ResponseWriter, r *http.Request) {
 
 	// Get total count for pagination
 	var total int
-	if params.Page > 0 {
+	if params.Page > 0 || params.Meta {
 		countQuery := h.queryBuilder.BuildCountQuery(db, modelName, params)
 		count, err := countQuery.Count(r.Context())
 		if err != nil {
@@ -82,11 +97,23 @@ func (h *DataHandler) Find(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Build response
+	masked := h.masking.Apply(r.Context(), db, modelName, results)
 	var response *types.Response
 	if params.Page > 0 {
-		response = types.NewPaginatedResponse(results, params.Page, params.Limit, total)
+		response = types.NewPaginatedResponse(masked, params.Page, params.Limit, total)
 	} else {
-		response = types.NewSuccessResponse(results)
+		response = types.NewSuccessResponse(masked)
+	}
+	if params.Meta {
+		if params.Page > 0 {
+			response.WithPageMeta(params.Page, params.Limit, (params.Page-1)*params.Limit, total)
+		} else {
+			pageSize := params.Limit
+			if pageSize <= 0 {
+				pageSize = len(results)
+			}
+			response.WithPageMeta(1, pageSize, 0, total)
+		}
 	}
 
 	response.WithExecutionTime(time.Since(start))
@@ -136,7 +163,7 @@ func (h *DataHandler) FindOne(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := types.NewSuccessResponse(result).WithExecutionTime(time.Since(start))
+	response := types.NewSuccessResponse(h.masking.Apply(r.Context(), db, modelName, result)).WithExecutionTime(time.Since(start))
 	writeJSON(w, http.StatusOK, response)
 }
 
@@ -168,18 +195,19 @@ func (h *DataHandler) Create(w http.ResponseWriter, r *http.Request) {
 	result, err := db.Model(modelName).Insert(req.Data).Exec(r.Context())
 	if err != nil {
 		h.logger.Error("Failed to create record: %v", err)
-		writeJSON(w, http.StatusInternalServerError, types.NewErrorResponse("CREATE_ERROR", "Failed to create record", err.Error()))
+		writeWriteError(w, "CREATE_ERROR", "Failed to create record", err)
 		return
 	}
 
 	// Fetch the created record
-	if result.LastInsertID > 0 {
+	id, hasID := result.InsertedID()
+	if hasID {
 		query := db.Model(modelName).Select()
-		query = query.WhereCondition(query.Where("id").Equals(result.LastInsertID))
+		query = query.WhereCondition(query.Where("id").Equals(id))
 
 		var created map[string]any
 		if err := query.FindFirst(r.Context(), &created); err == nil {
-			response := types.NewSuccessResponse(created).WithExecutionTime(time.Since(start))
+			response := types.NewSuccessResponse(h.masking.Apply(r.Context(), db, modelName, created)).WithExecutionTime(time.Since(start))
 			writeJSON(w, http.StatusCreated, response)
 			return
 		}
@@ -187,7 +215,7 @@ func (h *DataHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	// Fallback response
 	response := types.NewSuccessResponse(map[string]any{
-		"id":      result.LastInsertID,
+		"id":      id,
 		"created": true,
 	}).WithExecutionTime(time.Since(start))
 	writeJSON(w, http.StatusCreated, response)
@@ -225,7 +253,7 @@ func (h *DataHandler) Update(w http.ResponseWriter, r *http.Request) {
 	// Execute update
 	result, err := query.Exec(r.Context())
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, types.NewErrorResponse("UPDATE_ERROR", "Failed to update record", err.Error()))
+		writeWriteError(w, "UPDATE_ERROR", "Failed to update record", err)
 		return
 	}
 
@@ -240,7 +268,7 @@ func (h *DataHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	var updated map[string]any
 	if err := selectQuery.FindFirst(r.Context(), &updated); err == nil {
-		response := types.NewSuccessResponse(updated).WithExecutionTime(time.Since(start))
+		response := types.NewSuccessResponse(h.masking.Apply(r.Context(), db, modelName, updated)).WithExecutionTime(time.Since(start))
 		writeJSON(w, http.StatusOK, response)
 		return
 	}
@@ -330,7 +358,7 @@ func (h *DataHandler) BatchCreate(w http.ResponseWriter, r *http.Request) {
 	})
 
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, types.NewErrorResponse("BATCH_CREATE_ERROR", "Failed to create records", err.Error()))
+		writeWriteError(w, "BATCH_CREATE_ERROR", "Failed to create records", err)
 		return
 	}
 
@@ -340,6 +368,17 @@ func (h *DataHandler) BatchCreate(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusCreated, response)
 }
 
+// writeWriteError writes the error of a failed write. Validation errors are client
+// errors and list the broken rules.
+func writeWriteError(w http.ResponseWriter, code, message string, err error) {
+	var validationErr *schema.ValidationError
+	if errors.As(err, &validationErr) {
+		writeJSON(w, http.StatusBadRequest, types.NewValidationErrorResponse(validationErr))
+		return
+	}
+	writeJSON(w, http.StatusInternalServerError, types.NewErrorResponse(code, message, err.Error()))
+}
+
 // extractModelName extracts model name from URL path
 func extractModelName(path string) string {
 	parts := strings.Split(strings.Trim(path, "/"), "/")
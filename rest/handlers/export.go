@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/rediwo/redi-orm/database"
+	"github.com/rediwo/redi-orm/export"
+	"github.com/rediwo/redi-orm/rest/types"
+)
+
+// exportFormat returns the export format the Accept header of r asks for, or "" for JSON
+func exportFormat(r *http.Request) string {
+	accept := r.Header.Get("Accept")
+	switch {
+	case strings.Contains(accept, "text/csv"):
+		return export.CSV
+	case strings.Contains(accept, "application/x-ndjson"), strings.Contains(accept, "application/ndjson"):
+		return export.NDJSON
+	}
+	return ""
+}
+
+// export streams all the records matching params as CSV or NDJSON. Pagination is ignored.
+func (h *DataHandler) export(w http.ResponseWriter, r *http.Request, db database.Database, modelName string, params *types.QueryParams, format string) {
+	if _, err := db.GetSchema(modelName); err != nil {
+		writeJSON(w, http.StatusNotFound, types.NewErrorResponse("MODEL_NOT_FOUND", "Model not found", err.Error()))
+		return
+	}
+
+	params.Page = 0
+	params.Limit = 0
+	query, err := h.queryBuilder.BuildFindQuery(db, modelName, params)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, types.NewErrorResponse("QUERY_BUILD_ERROR", "Failed to build query", err.Error()))
+		return
+	}
+
+	fields := params.Select
+	if len(fields) == 0 {
+		fields = params.Fields
+	}
+	out := &exportWriter{
+		w:          w,
+		controller: http.NewResponseController(w),
+		format:     format,
+		filename:   modelName + "." + format,
+	}
+	count, err := export.Export(r.Context(), out, db, modelName, query, export.Options{
+		Format: format,
+		Fields: fields,
+		Transform: func(records []map[string]any) []map[string]any {
+			if masked, ok := h.masking.Apply(r.Context(), db, modelName, records).([]map[string]any); ok {
+				return masked
+			}
+			return records
+		},
+	})
+	if err != nil {
+		if !out.started {
+			writeJSON(w, http.StatusInternalServerError, types.NewErrorResponse("QUERY_ERROR", "Failed to execute query", err.Error()))
+			return
+		}
+		// The status is sent already, the client sees a truncated export
+		h.logger.Error("Export of %s failed after %d records: %v", modelName, count, err)
+	}
+}
+
+// exportWriter sends the export headers with the first write, so that errors before any
+// record is read can still be reported as JSON, and flushes each batch to the client
+type exportWriter struct {
+	w          http.ResponseWriter
+	controller *http.ResponseController
+	format     string
+	filename   string
+	started    bool
+}
+
+func (e *exportWriter) Write(p []byte) (int, error) {
+	if !e.started {
+		e.started = true
+		e.w.Header().Set("Content-Type", export.ContentType(e.format))
+		e.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", e.filename))
+		e.w.WriteHeader(http.StatusOK)
+	}
+	return e.w.Write(p)
+}
+
+func (e *exportWriter) Flush() error {
+	if !e.started {
+		return nil
+	}
+	return e.controller.Flush()
+}
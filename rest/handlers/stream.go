@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rediwo/redi-orm/database"
+	"github.com/rediwo/redi-orm/logger"
+	"github.com/rediwo/redi-orm/masking"
+	"github.com/rediwo/redi-orm/rest/services"
+	"github.com/rediwo/redi-orm/rest/types"
+	ormTypes "github.com/rediwo/redi-orm/types"
+)
+
+// sseKeepAliveInterval is how often a comment line is sent to keep idle SSE connections open
+const sseKeepAliveInterval = 15 * time.Second
+
+// StreamHandler serves change events as Server-Sent Events
+type StreamHandler struct {
+	connHandler  *ConnectionHandler
+	queryBuilder *services.QueryBuilder
+	logger       logger.Logger
+	masking      *masking.Policy
+}
+
+// NewStreamHandler creates a new stream handler
+func NewStreamHandler(connHandler *ConnectionHandler, l logger.Logger) *StreamHandler {
+	return &StreamHandler{
+		connHandler:  connHandler,
+		queryBuilder: services.NewQueryBuilder(),
+		logger:       l,
+	}
+}
+
+// SetMaskingPolicy sets the policy masking sensitive fields of streamed records
+func (h *StreamHandler) SetMaskingPolicy(policy *masking.Policy) {
+	h.masking = policy
+}
+
+// Changes streams change events for the models listed in ?models=A,B (all models if omitted)
+func (h *StreamHandler) Changes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, types.NewErrorResponse("METHOD_NOT_ALLOWED", "Only GET method is allowed"))
+		return
+	}
+
+	db, err := h.connHandler.GetConnection(r.Header.Get("X-Connection-Name"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, types.NewErrorResponse("NO_CONNECTION", "No database connection available"))
+		return
+	}
+
+	var models []string
+	for _, model := range strings.Split(r.URL.Query().Get("models"), ",") {
+		if model = strings.TrimSpace(model); model != "" {
+			models = append(models, model)
+		}
+	}
+
+	h.serveEvents(w, r, db, models, func(event ormTypes.ChangeEvent) (ormTypes.ChangeEvent, bool) {
+		return event, true
+	})
+}
+
+// Stream pushes created/updated records of a model matching ?where=... as SSE events.
+// Each changed record is re-read through the regular find query so that where,
+// select and include parameters behave exactly like GET /api/{model}.
+func (h *StreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, types.NewErrorResponse("METHOD_NOT_ALLOWED", "Only GET method is allowed"))
+		return
+	}
+
+	modelName := extractModelName(r.URL.Path)
+	db, err := h.connHandler.GetConnection(r.Header.Get("X-Connection-Name"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, types.NewErrorResponse("NO_CONNECTION", "No database connection available"))
+		return
+	}
+
+	sch, err := db.GetSchema(modelName)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, types.NewErrorResponse("MODEL_NOT_FOUND", "Model not found", err.Error()))
+		return
+	}
+	pk, err := sch.GetPrimaryKey()
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, types.NewErrorResponse("UNSUPPORTED_MODEL", "Streaming requires a single-field primary key", err.Error()))
+		return
+	}
+
+	params, err := types.ParseQueryParams(r.URL.Query())
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, types.NewErrorResponse("INVALID_PARAMS", "Invalid query parameters", err.Error()))
+		return
+	}
+	// Pagination has no meaning for a live stream
+	params.Page = 0
+	params.Limit = 0
+
+	h.serveEvents(w, r, db, []string{modelName}, func(event ormTypes.ChangeEvent) (ormTypes.ChangeEvent, bool) {
+		if event.Operation == ormTypes.ChangeDelete {
+			return event, false
+		}
+		id, ok := event.Data[pk.Name]
+		if !ok || id == nil {
+			return event, false
+		}
+
+		query, err := h.queryBuilder.BuildFindQuery(db, modelName, params)
+		if err != nil {
+			h.logger.Error("Failed to build stream query for %s: %v", modelName, err)
+			return event, false
+		}
+		query = query.WhereCondition(query.Where(pk.Name).Equals(id))
+
+		var records []map[string]any
+		if err := query.FindMany(r.Context(), &records); err != nil {
+			h.logger.Error("Failed to load streamed %s record: %v", modelName, err)
+			return event, false
+		}
+		if len(records) == 0 {
+			// The changed record does not match the where conditions
+			return event, false
+		}
+		event.Data = records[0]
+		return event, true
+	})
+}
+
+// serveEvents opens a change stream and writes every accepted event as SSE until the client disconnects.
+// transform may rewrite an event and returns false to skip it.
+func (h *StreamHandler) serveEvents(w http.ResponseWriter, r *http.Request, db database.Database, models []string, transform func(ormTypes.ChangeEvent) (ormTypes.ChangeEvent, bool)) {
+	stream, err := database.Watch(r.Context(), db, models...)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, types.NewErrorResponse("STREAM_ERROR", "Failed to open change stream", err.Error()))
+		return
+	}
+	defer stream.Close()
+
+	controller := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	if err := controller.Flush(); err != nil {
+		h.logger.Error("Streaming not supported by response writer: %v", err)
+		return
+	}
+
+	keepAlive := time.NewTicker(sseKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case event, ok := <-stream.Events():
+			if !ok {
+				if err := stream.Err(); err != nil {
+					writeSSE(w, "error", map[string]string{"message": err.Error()})
+					controller.Flush()
+				}
+				return
+			}
+			event, accepted := transform(event)
+			if !accepted {
+				continue
+			}
+			if data, ok := h.masking.Apply(r.Context(), db, event.Model, event.Data).(map[string]any); ok {
+				event.Data = data
+			}
+			if err := writeSSE(w, string(event.Operation), event); err != nil {
+				return
+			}
+		}
+		if err := controller.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeSSE writes a single Server-Sent Event with a JSON payload
+func writeSSE(w http.ResponseWriter, event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	return err
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rediwo/redi-orm/database"
+	"github.com/rediwo/redi-orm/orm"
+	"github.com/rediwo/redi-orm/rest/types"
+)
+
+// Upsert handles creating the record with the unique key of where, or updating it when
+// it exists, as PUT /api/{model} with {"where": ..., "create": ..., "update": ...}
+func (h *DataHandler) Upsert(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		writeJSON(w, http.StatusMethodNotAllowed, types.NewErrorResponse("METHOD_NOT_ALLOWED", "Only PUT method is allowed"))
+		return
+	}
+
+	start := time.Now()
+	modelName := extractModelName(r.URL.Path)
+	connectionName := r.Header.Get("X-Connection-Name")
+
+	db, err := h.connHandler.GetConnection(connectionName)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, types.NewErrorResponse("NO_CONNECTION", "No database connection available"))
+		return
+	}
+
+	// Parse request body
+	var req types.UpsertRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, types.NewErrorResponse("INVALID_REQUEST", "Invalid request body", err.Error()))
+		return
+	}
+	if req.Create == nil {
+		req.Create = req.Data
+	}
+	if req.Update == nil {
+		req.Update = req.Data
+	}
+	if req.Create == nil || req.Update == nil {
+		writeJSON(w, http.StatusBadRequest, types.NewErrorResponse("INVALID_REQUEST", "Invalid request body", "upsert requires data, or create and update"))
+		return
+	}
+	if !h.checkUniqueWhere(w, db, modelName, req.Where) {
+		return
+	}
+
+	// The values of the unique key belong to the created record
+	create := maps.Clone(req.Create)
+	for field, value := range req.Where {
+		if _, ok := create[field]; !ok {
+			create[field] = value
+		}
+	}
+
+	query, err := json.Marshal(map[string]any{
+		"upsert": map[string]any{"where": req.Where, "create": create, "update": req.Update},
+	})
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, types.NewErrorResponse("INVALID_REQUEST", "Invalid request body", err.Error()))
+		return
+	}
+	result, err := orm.NewClient(db).Model(modelName).QueryContext(r.Context(), string(query))
+	if err != nil {
+		h.logger.Error("Failed to upsert record: %v", err)
+		writeWriteError(w, "UPSERT_ERROR", "Failed to upsert record", err)
+		return
+	}
+
+	response := types.NewSuccessResponse(h.masking.Apply(r.Context(), db, modelName, result)).WithExecutionTime(time.Since(start))
+	writeJSON(w, http.StatusOK, response)
+}
+
+// FindOrCreate handles returning the record with the unique key of where, creating it
+// when it does not exist, as POST /api/{model}/find-or-create with {"where": ..., "create": ...}.
+// A created record is returned with 201 Created.
+func (h *DataHandler) FindOrCreate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, types.NewErrorResponse("METHOD_NOT_ALLOWED", "Only POST method is allowed"))
+		return
+	}
+
+	start := time.Now()
+	modelName := extractModelName(r.URL.Path)
+	connectionName := r.Header.Get("X-Connection-Name")
+
+	db, err := h.connHandler.GetConnection(connectionName)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, types.NewErrorResponse("NO_CONNECTION", "No database connection available"))
+		return
+	}
+
+	// Parse request body
+	var req types.FindOrCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, types.NewErrorResponse("INVALID_REQUEST", "Invalid request body", err.Error()))
+		return
+	}
+	if !h.checkUniqueWhere(w, db, modelName, req.Where) {
+		return
+	}
+
+	find := func() (map[string]any, error) {
+		query := db.Model(modelName).Select()
+		for field, value := range req.Where {
+			query = query.WhereCondition(query.Where(field).Equals(value))
+		}
+		var record map[string]any
+		if err := query.FindFirst(r.Context(), &record); err != nil {
+			if errors.Is(err, sql.ErrNoRows) || strings.Contains(err.Error(), "no rows") {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return record, nil
+	}
+	respond := func(status int, record map[string]any) {
+		response := types.NewSuccessResponse(h.masking.Apply(r.Context(), db, modelName, record)).WithExecutionTime(time.Since(start))
+		writeJSON(w, status, response)
+	}
+
+	record, err := find()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, types.NewErrorResponse("QUERY_ERROR", "Failed to execute query", err.Error()))
+		return
+	}
+	if record != nil {
+		respond(http.StatusOK, record)
+		return
+	}
+
+	// The values of the unique key belong to the created record
+	create := maps.Clone(req.Create)
+	if create == nil {
+		create = make(map[string]any)
+	}
+	for field, value := range req.Where {
+		create[field] = value
+	}
+
+	if _, err := db.Model(modelName).Insert(create).Exec(r.Context()); err != nil {
+		// A concurrent request may have created the record first
+		if record, findErr := find(); findErr == nil && record != nil {
+			respond(http.StatusOK, record)
+			return
+		}
+		h.logger.Error("Failed to create record: %v", err)
+		writeWriteError(w, "CREATE_ERROR", "Failed to create record", err)
+		return
+	}
+
+	record, err = find()
+	if err != nil || record == nil {
+		writeJSON(w, http.StatusInternalServerError, types.NewErrorResponse("QUERY_ERROR", "Failed to fetch the created record", fmt.Sprint(err)))
+		return
+	}
+	respond(http.StatusCreated, record)
+}
+
+// checkUniqueWhere checks that where matches a unique key of the model by equality,
+// writing the error response when it does not
+func (h *DataHandler) checkUniqueWhere(w http.ResponseWriter, db database.Database, modelName string, where map[string]any) bool {
+	modelSchema, err := db.GetSchema(modelName)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, types.NewErrorResponse("MODEL_NOT_FOUND", "Model not found", err.Error()))
+		return false
+	}
+
+	fields := slices.Sorted(maps.Keys(where))
+	for _, field := range fields {
+		switch where[field].(type) {
+		case nil, map[string]any, []any:
+			writeJSON(w, http.StatusBadRequest, types.NewErrorResponse("INVALID_REQUEST", "where must match a unique key by equality", fmt.Sprintf("invalid value of %s", field)))
+			return false
+		}
+	}
+	if len(fields) == 0 || !modelSchema.IsUniqueKey(fields) {
+		writeJSON(w, http.StatusBadRequest, types.NewErrorResponse("INVALID_REQUEST", "where must match a unique key by equality", fmt.Sprintf("%s is not a unique key of %s", strings.Join(fields, ", "), modelName)))
+		return false
+	}
+	return true
+}
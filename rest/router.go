@@ -5,16 +5,18 @@ import (
 	"strings"
 
 	"github.com/rediwo/redi-orm/logger"
+	"github.com/rediwo/redi-orm/masking"
 	"github.com/rediwo/redi-orm/rest/handlers"
 	"github.com/rediwo/redi-orm/rest/middleware"
 )
 
 // Router handles REST API routing
 type Router struct {
-	mux         *http.ServeMux
-	connHandler *handlers.ConnectionHandler
-	dataHandler *handlers.DataHandler
-	logger      logger.Logger
+	mux           *http.ServeMux
+	connHandler   *handlers.ConnectionHandler
+	dataHandler   *handlers.DataHandler
+	streamHandler *handlers.StreamHandler
+	logger        logger.Logger
 }
 
 // NewRouter creates a new REST API router
@@ -25,18 +27,27 @@ func NewRouter(l logger.Logger) *Router {
 
 	connHandler := handlers.NewConnectionHandler(l)
 	dataHandler := handlers.NewDataHandler(connHandler, l)
+	streamHandler := handlers.NewStreamHandler(connHandler, l)
 
 	router := &Router{
-		mux:         http.NewServeMux(),
-		connHandler: connHandler,
-		dataHandler: dataHandler,
-		logger:      l,
+		mux:           http.NewServeMux(),
+		connHandler:   connHandler,
+		dataHandler:   dataHandler,
+		streamHandler: streamHandler,
+		logger:        l,
 	}
 
 	router.setupRoutes()
 	return router
 }
 
+// SetMaskingPolicy masks sensitive fields in responses for unprivileged callers.
+// Caller roles are read from the request context (see masking.Policy.Middleware).
+func (r *Router) SetMaskingPolicy(policy *masking.Policy) {
+	r.dataHandler.SetMaskingPolicy(policy)
+	r.streamHandler.SetMaskingPolicy(policy)
+}
+
 // setupRoutes configures all API routes
 func (r *Router) setupRoutes() {
 	// Connection management
@@ -44,8 +55,11 @@ func (r *Router) setupRoutes() {
 	r.mux.HandleFunc("/api/connections/connect", r.withMiddleware(r.connHandler.Connect))
 	r.mux.HandleFunc("/api/connections/disconnect", r.withMiddleware(r.connHandler.Disconnect))
 
+	// Change feed as Server-Sent Events
+	r.mux.HandleFunc("/api/changes", r.withStreamMiddleware(r.streamHandler.Changes))
+
 	// Data operations - using a pattern that matches model names
-	r.mux.HandleFunc("/api/", r.withMiddleware(r.handleDataOperations))
+	r.mux.HandleFunc("/api/", r.withDataMiddleware(r.handleDataOperations))
 }
 
 // handleDataOperations routes data operations based on URL pattern
@@ -63,6 +77,41 @@ func (r *Router) handleDataOperations(w http.ResponseWriter, req *http.Request)
 		return
 	}
 
+	// Live query stream (Server-Sent Events)
+	if isStreamOperation(path) {
+		if method == http.MethodGet {
+			r.streamHandler.Stream(w, req)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	// Aggregations of the records of a model
+	if isAggregateOperation(path) {
+		switch method {
+		case http.MethodGet, http.MethodPost:
+			if endsWith(strings.TrimSuffix(path, "/"), "/groupBy") {
+				r.dataHandler.GroupBy(w, req)
+			} else {
+				r.dataHandler.Aggregate(w, req)
+			}
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	// Find a record by a unique key or create it
+	if isFindOrCreateOperation(path) {
+		if method == http.MethodPost {
+			r.dataHandler.FindOrCreate(w, req)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
 	// Check if it has an ID (single record operation)
 	if hasID(path) {
 		switch method {
@@ -84,6 +133,8 @@ func (r *Router) handleDataOperations(w http.ResponseWriter, req *http.Request)
 		r.dataHandler.Find(w, req)
 	case http.MethodPost:
 		r.dataHandler.Create(w, req)
+	case http.MethodPut:
+		r.dataHandler.Upsert(w, req)
 	default:
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 	}
@@ -99,6 +150,27 @@ func (r *Router) withMiddleware(handler http.HandlerFunc) http.HandlerFunc {
 	)
 }
 
+// withDataMiddleware wraps data operations, leaving the content type of
+// streaming responses to the stream handler
+func (r *Router) withDataMiddleware(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if isStreamOperation(req.URL.Path) {
+			r.withStreamMiddleware(handler)(w, req)
+			return
+		}
+		r.withMiddleware(handler)(w, req)
+	}
+}
+
+// withStreamMiddleware wraps a streaming handler (no JSON content type)
+func (r *Router) withStreamMiddleware(handler http.HandlerFunc) http.HandlerFunc {
+	return middleware.Chain(
+		handler,
+		middleware.CORS(),
+		middleware.Logging(r.logger),
+	)
+}
+
 // ServeHTTP implements http.Handler
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	r.mux.ServeHTTP(w, req)
@@ -109,6 +181,21 @@ func isBatchOperation(path string) bool {
 	return endsWith(path, "/batch")
 }
 
+func isStreamOperation(path string) bool {
+	parts := splitPath(path[5:]) // Remove "/api/"
+	return len(parts) == 2 && parts[1] == "stream"
+}
+
+func isAggregateOperation(path string) bool {
+	parts := splitPath(path[5:]) // Remove "/api/"
+	return len(parts) == 2 && (parts[1] == "aggregate" || parts[1] == "groupBy")
+}
+
+func isFindOrCreateOperation(path string) bool {
+	parts := splitPath(path[5:]) // Remove "/api/"
+	return len(parts) == 2 && parts[1] == "find-or-create"
+}
+
 func hasID(path string) bool {
 	// Remove /api/ prefix and check if there's an ID component
 	trimmed := path[5:] // Remove "/api/"
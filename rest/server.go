@@ -7,14 +7,16 @@ import (
 
 	"github.com/rediwo/redi-orm/database"
 	"github.com/rediwo/redi-orm/logger"
+	"github.com/rediwo/redi-orm/masking"
 )
 
 // Server represents a REST API server
 type Server struct {
-	db     database.Database
-	Router *Router // Exported for testing
-	port   int
-	logger logger.Logger
+	db      database.Database
+	Router  *Router // Exported for testing
+	port    int
+	logger  logger.Logger
+	masking *masking.Policy
 }
 
 // ServerConfig holds server configuration
@@ -23,6 +25,7 @@ type ServerConfig struct {
 	Port       int
 	LogLevel   string
 	SchemaFile string
+	Masking    *masking.Policy // Optional: mask sensitive fields for unprivileged callers
 }
 
 // NewServer creates a new REST API server
@@ -64,6 +67,7 @@ func NewServer(config ServerConfig) (*Server, error) {
 
 	// Create router
 	router := NewRouter(l)
+	router.SetMaskingPolicy(config.Masking)
 
 	// Add default database connection if provided
 	if config.Database != nil {
@@ -76,10 +80,11 @@ func NewServer(config ServerConfig) (*Server, error) {
 	}
 
 	return &Server{
-		db:     config.Database,
-		Router: router,
-		port:   config.Port,
-		logger: l,
+		db:      config.Database,
+		Router:  router,
+		port:    config.Port,
+		logger:  l,
+		masking: config.Masking,
 	}, nil
 }
 
@@ -91,8 +96,10 @@ func (s *Server) Start() error {
 	s.logger.Info("  - POST   /api/connections/connect")
 	s.logger.Info("  - DELETE /api/connections/disconnect")
 	s.logger.Info("  - GET    /api/connections")
+	s.logger.Info("  - GET    /api/changes?models=...")
 	s.logger.Info("  - GET    /api/{model}")
 	s.logger.Info("  - GET    /api/{model}/{id}")
+	s.logger.Info("  - GET    /api/{model}/stream?where=...")
 	s.logger.Info("  - POST   /api/{model}")
 	s.logger.Info("  - PUT    /api/{model}/{id}")
 	s.logger.Info("  - DELETE /api/{model}/{id}")
@@ -100,7 +107,7 @@ func (s *Server) Start() error {
 
 	// Default database is already added in NewServer
 
-	return http.ListenAndServe(addr, s.Router)
+	return http.ListenAndServe(addr, s.masking.Middleware(s.Router))
 }
 
 // Stop stops the server (cleanup)
@@ -0,0 +1,154 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/rediwo/redi-orm/database"
+	"github.com/rediwo/redi-orm/rest"
+)
+
+// TestAggregateAndGroupBy tests the aggregate and groupBy endpoints
+func TestAggregateAndGroupBy(t *testing.T) {
+	db, err := database.NewFromURI("sqlite://:memory:")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if err := db.Connect(ctx); err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	if err := db.LoadSchema(ctx, testSchema); err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	if err := db.SyncSchemas(ctx); err != nil {
+		t.Fatalf("Failed to sync schemas: %v", err)
+	}
+	createTestData(t, db)
+	for _, user := range []map[string]any{
+		{"name": "Alice", "email": "alice2@example.com", "age": 45},
+		{"name": "Bob", "email": "bob2@example.com", "age": 40},
+	} {
+		if _, err := db.Model("User").Insert(user).Exec(ctx); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+
+	server, err := rest.NewServer(rest.ServerConfig{Database: db, LogLevel: "error"})
+	if err != nil {
+		t.Fatalf("Failed to create REST server: %v", err)
+	}
+	defer server.Stop()
+
+	ts := httptest.NewServer(server.Router)
+	defer ts.Close()
+
+	t.Run("AggregateQueryParams", func(t *testing.T) {
+		where := url.QueryEscape(`{"age":{"gte":30}}`)
+		resp := makeRequest(t, ts, http.MethodGet, "/api/User/aggregate?_count=true&_sum=age&_avg=age&_min=age,name&_max=age&where="+where, nil)
+		if !resp.Success {
+			t.Fatalf("Expected success, got error: %v", resp.Error)
+		}
+
+		result := resp.Data.(map[string]any)
+		if count := result["_count"]; count != float64(4) {
+			t.Errorf("Expected _count 4, got %v", count)
+		}
+		if sum := result["_sum"].(map[string]any)["age"]; sum != float64(150) {
+			t.Errorf("Expected _sum.age 150, got %v", sum)
+		}
+		if avg := result["_avg"].(map[string]any)["age"]; avg != float64(37.5) {
+			t.Errorf("Expected _avg.age 37.5, got %v", avg)
+		}
+		minimum := result["_min"].(map[string]any)
+		if minimum["age"] != float64(30) || minimum["name"] != "Alice" {
+			t.Errorf("Expected _min {age: 30, name: Alice}, got %v", minimum)
+		}
+		if maximum := result["_max"].(map[string]any)["age"]; maximum != float64(45) {
+			t.Errorf("Expected _max.age 45, got %v", maximum)
+		}
+	})
+
+	t.Run("AggregatePostedJSON", func(t *testing.T) {
+		resp := makeRequest(t, ts, http.MethodPost, "/api/User/aggregate", map[string]any{
+			"where": map[string]any{"name": "Alice"},
+			"_sum":  map[string]any{"age": true},
+		})
+		if !resp.Success {
+			t.Fatalf("Expected success, got error: %v", resp.Error)
+		}
+
+		result := resp.Data.(map[string]any)
+		if sum := result["_sum"].(map[string]any)["age"]; sum != float64(70) {
+			t.Errorf("Expected _sum.age 70, got %v", sum)
+		}
+	})
+
+	t.Run("GroupByQueryParams", func(t *testing.T) {
+		orderBy := url.QueryEscape(`{"name":"asc"}`)
+		resp := makeRequest(t, ts, http.MethodGet, "/api/User/groupBy?by=name&_sum=age&_count=true&orderBy="+orderBy, nil)
+		if !resp.Success {
+			t.Fatalf("Expected success, got error: %v", resp.Error)
+		}
+
+		groups := resp.Data.([]any)
+		if len(groups) != 3 {
+			t.Fatalf("Expected 3 groups, got %d", len(groups))
+		}
+		expected := []struct {
+			name string
+			sum  float64
+		}{{"Alice", 70}, {"Bob", 70}, {"Charlie", 35}}
+		for i, group := range groups {
+			group := group.(map[string]any)
+			if group["name"] != expected[i].name {
+				t.Errorf("Expected group %d to be %s, got %v", i, expected[i].name, group["name"])
+			}
+			if sum := group["_sum"].(map[string]any)["age"]; sum != expected[i].sum {
+				t.Errorf("Expected _sum.age of %s to be %v, got %v", expected[i].name, expected[i].sum, sum)
+			}
+		}
+	})
+
+	t.Run("GroupByPostedJSON", func(t *testing.T) {
+		resp := makeRequest(t, ts, http.MethodPost, "/api/User/groupBy", map[string]any{
+			"by":      []string{"name"},
+			"_sum":    map[string]any{"age": true},
+			"having":  map[string]any{"_sum": map[string]any{"age": map[string]any{"gte": 50}}},
+			"orderBy": []any{map[string]any{"name": "desc"}},
+		})
+		if !resp.Success {
+			t.Fatalf("Expected success, got error: %v", resp.Error)
+		}
+
+		groups := resp.Data.([]any)
+		if len(groups) != 2 {
+			t.Fatalf("Expected 2 groups, got %d", len(groups))
+		}
+		if name := groups[0].(map[string]any)["name"]; name != "Bob" {
+			t.Errorf("Expected Bob first, got %v", name)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		resp := makeRequest(t, ts, http.MethodGet, "/api/User/groupBy?_sum=age", nil)
+		if resp.Success || resp.Error.Code != "INVALID_PARAMS" {
+			t.Errorf("Expected groupBy without by to fail with INVALID_PARAMS, got %+v", resp)
+		}
+
+		resp = makeRequest(t, ts, http.MethodGet, "/api/User/aggregate?take=ten", nil)
+		if resp.Success || resp.Error.Code != "INVALID_PARAMS" {
+			t.Errorf("Expected an invalid take to fail with INVALID_PARAMS, got %+v", resp)
+		}
+
+		resp = makeRequest(t, ts, http.MethodGet, "/api/Unknown/aggregate?_count=true", nil)
+		if resp.Success || resp.Error.Code != "MODEL_NOT_FOUND" {
+			t.Errorf("Expected an unknown model to fail with MODEL_NOT_FOUND, got %+v", resp)
+		}
+	})
+}
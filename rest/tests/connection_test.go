@@ -404,6 +404,57 @@ func TestDefaultConnection(t *testing.T) {
 	})
 }
 
+// TestRegisteredConnection tests selecting a connection registered with database.Register
+func TestRegisteredConnection(t *testing.T) {
+	db, err := database.Register("registered-db", "sqlite://:memory:")
+	if err != nil {
+		t.Fatalf("Failed to register connection: %v", err)
+	}
+	defer database.Unregister("registered-db")
+
+	ctx := context.Background()
+	if err := db.LoadSchema(ctx, testConnectionSchema); err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	if err := db.SyncSchemas(ctx); err != nil {
+		t.Fatalf("Failed to sync schemas: %v", err)
+	}
+
+	server, err := rest.NewServer(rest.ServerConfig{LogLevel: "error"})
+	if err != nil {
+		t.Fatalf("Failed to create REST server: %v", err)
+	}
+	defer server.Stop()
+
+	ts := httptest.NewServer(server.Router)
+	defer ts.Close()
+
+	req, err := createRequestWithHeaders(t, ts.URL+"/api/Product", "POST", map[string]any{
+		"data": map[string]any{"name": "Registered Product", "price": 5.0},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	req.Header.Set("X-Connection-Name", "registered-db")
+	if resp := executeRequest(t, req); !resp.Success {
+		t.Fatalf("Expected success, got error: %s", resp.Error.Message)
+	}
+
+	count, err := db.Model("Product").Select().Count(ctx)
+	if err != nil {
+		t.Fatalf("Failed to count products: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected the product in the registered database, got %d products", count)
+	}
+
+	resp := makeRequest(t, ts, "GET", "/api/connections", nil)
+	connections, ok := resp.Data.([]any)
+	if !ok || len(connections) != 1 || connections[0].(map[string]any)["name"] != "registered-db" {
+		t.Errorf("Expected the registered connection to be listed, got %v", resp.Data)
+	}
+}
+
 const testConnectionSchema = `
 model Product {
   id    Int    @id @default(autoincrement())
@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/rediwo/redi-orm/database"
+	"github.com/rediwo/redi-orm/rest"
+)
+
+// TestExport tests streaming lists as CSV and NDJSON with the Accept header
+func TestExport(t *testing.T) {
+	db, err := database.NewFromURI("sqlite://:memory:")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if err := db.Connect(ctx); err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	if err := db.LoadSchema(ctx, testSchema); err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	if err := db.SyncSchemas(ctx); err != nil {
+		t.Fatalf("Failed to sync schemas: %v", err)
+	}
+	createTestData(t, db)
+
+	server, err := rest.NewServer(rest.ServerConfig{Database: db, LogLevel: "error"})
+	if err != nil {
+		t.Fatalf("Failed to create REST server: %v", err)
+	}
+	defer server.Stop()
+
+	ts := httptest.NewServer(server.Router)
+	defer ts.Close()
+
+	get := func(path, accept string) (*http.Response, string) {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, ts.URL+path, nil)
+		req.Header.Set("Accept", accept)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET %s error = %v", path, err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body)
+	}
+
+	t.Run("CSV", func(t *testing.T) {
+		// Pagination is ignored, the export holds every matching record
+		where := url.QueryEscape(`{"age":{"gte":30}}`)
+		resp, body := get("/api/User?limit=1&select=name,age&where="+where, "text/csv")
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected 200, got %d %s", resp.StatusCode, body)
+		}
+		if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+			t.Errorf("Expected a CSV content type, got %s", ct)
+		}
+		if cd := resp.Header.Get("Content-Disposition"); cd != `attachment; filename="User.csv"` {
+			t.Errorf("Expected an attachment, got %s", cd)
+		}
+		if body != "name,age\nBob,30\nCharlie,35\n" {
+			t.Errorf("Unexpected CSV:\n%s", body)
+		}
+	})
+
+	t.Run("NDJSON", func(t *testing.T) {
+		resp, body := get("/api/User?sort=-age", "application/x-ndjson")
+		if ct := resp.Header.Get("Content-Type"); ct != "application/x-ndjson" {
+			t.Fatalf("Expected an NDJSON content type, got %s", ct)
+		}
+		lines := strings.Split(strings.TrimSpace(body), "\n")
+		if len(lines) != 3 || !strings.Contains(lines[0], `"name":"Charlie"`) || !strings.Contains(lines[2], `"name":"Alice"`) {
+			t.Errorf("Expected the users from the oldest, got:\n%s", body)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		resp, body := get("/api/Missing", "text/csv")
+		if resp.StatusCode != http.StatusNotFound || !strings.Contains(body, "MODEL_NOT_FOUND") {
+			t.Errorf("Expected a JSON 404 for an unknown model, got %d %s", resp.StatusCode, body)
+		}
+	})
+}
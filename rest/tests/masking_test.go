@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rediwo/redi-orm/database"
+	_ "github.com/rediwo/redi-orm/drivers/sqlite"
+	"github.com/rediwo/redi-orm/masking"
+	"github.com/rediwo/redi-orm/rest"
+	"github.com/rediwo/redi-orm/rest/types"
+)
+
+// TestFieldMasking tests that sensitive fields are masked for unprivileged callers
+func TestFieldMasking(t *testing.T) {
+	db, err := database.NewFromURI("sqlite://:memory:")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if err := db.Connect(ctx); err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	if err := db.LoadSchema(ctx, testSchema); err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	if err := db.SyncSchemas(ctx); err != nil {
+		t.Fatalf("Failed to sync schemas: %v", err)
+	}
+	createTestDataWithRelations(t, db)
+
+	policy := &masking.Policy{
+		Fields: map[string]masking.Strategy{
+			"User.email": masking.StrategyEmail,
+			"User.age":   masking.StrategyFull,
+		},
+		PrivilegedRoles: []string{"admin"},
+		RoleHeader:      "X-Role",
+	}
+
+	server, err := rest.NewServer(rest.ServerConfig{
+		Database: db,
+		LogLevel: "error",
+		Masking:  policy,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create REST server: %v", err)
+	}
+	defer server.Stop()
+
+	ts := httptest.NewServer(policy.Middleware(server.Router))
+	defer ts.Close()
+
+	get := func(t *testing.T, path, role string) *types.Response {
+		req, err := http.NewRequest(http.MethodGet, ts.URL+path, nil)
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		if role != "" {
+			req.Header.Set("X-Role", role)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		var response types.Response
+		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if !response.Success {
+			t.Fatalf("Expected success, got error: %s", response.Error.Message)
+		}
+		return &response
+	}
+
+	t.Run("FindMasked", func(t *testing.T) {
+		users := get(t, "/api/User", "").Data.([]any)
+		user := users[0].(map[string]any)
+		if user["email"] != "a***@example.com" {
+			t.Errorf("Expected masked email, got %v", user["email"])
+		}
+		if user["age"] != nil {
+			t.Errorf("Expected age to be redacted, got %v", user["age"])
+		}
+		if user["name"] != "Alice" {
+			t.Errorf("Expected name to be unmasked, got %v", user["name"])
+		}
+	})
+
+	t.Run("IncludedRelationMasked", func(t *testing.T) {
+		posts := get(t, "/api/Post?include=author", "viewer").Data.([]any)
+		author := posts[0].(map[string]any)["author"].(map[string]any)
+		if author["email"] != "a***@example.com" {
+			t.Errorf("Expected masked author email, got %v", author["email"])
+		}
+	})
+
+	t.Run("PrivilegedRoleUnmasked", func(t *testing.T) {
+		user := get(t, "/api/User/1", "viewer,admin").Data.(map[string]any)
+		if user["email"] != "alice@example.com" {
+			t.Errorf("Expected unmasked email, got %v", user["email"])
+		}
+		if user["age"] != float64(25) {
+			t.Errorf("Expected unmasked age, got %v", user["age"])
+		}
+	})
+}
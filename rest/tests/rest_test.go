@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/rediwo/redi-orm/database"
@@ -270,6 +271,32 @@ func TestComplexQueries(t *testing.T) {
 		}
 	})
 
+	t.Run("SortingWithOrderBy", func(t *testing.T) {
+		names := func(path string) string {
+			resp := makeRequest(t, ts, "GET", path, nil)
+			if !resp.Success {
+				t.Fatalf("Expected success, got error: %s", resp.Error.Message)
+			}
+			var result []string
+			for _, user := range resp.Data.([]any) {
+				result = append(result, user.(map[string]any)["name"].(string))
+			}
+			return strings.Join(result, ",")
+		}
+
+		if got := names(`/api/User?sort=[{"age":"desc"},{"name":"asc"}]`); got != "Charlie,Bob,Alice" {
+			t.Errorf("Expected users sorted by age descending, got %s", got)
+		}
+		if got := names(`/api/User?order_by={"name":"asc","age":"desc"}`); got != "Alice,Bob,Charlie" {
+			t.Errorf("Expected users sorted by name, got %s", got)
+		}
+
+		resp := makeRequest(t, ts, "GET", `/api/User?sort={"name":"up"}`, nil)
+		if resp.Success {
+			t.Error("Expected an invalid sort direction to be rejected")
+		}
+	})
+
 	// Test 3: Pagination
 	t.Run("Pagination", func(t *testing.T) {
 		resp := makeRequest(t, ts, "GET", "/api/User?page=1&limit=2", nil)
@@ -300,6 +327,30 @@ func TestComplexQueries(t *testing.T) {
 		}
 	})
 
+	t.Run("PageMeta", func(t *testing.T) {
+		resp := makeRequest(t, ts, "GET", "/api/User?page=1&limit=2&meta=true", nil)
+		if !resp.Success {
+			t.Fatalf("Expected success, got error: %s", resp.Error.Message)
+		}
+		meta := resp.Meta
+		if meta == nil || meta.Total == nil || meta.HasNext == nil {
+			t.Fatalf("Expected the page in the metadata, got %+v", meta)
+		}
+		if *meta.Total != 3 || meta.Page != 1 || meta.PageSize != 2 || !*meta.HasNext {
+			t.Errorf("Expected page 1 of 2 users out of 3 with a next page, got %+v", meta)
+		}
+
+		resp = makeRequest(t, ts, "GET", "/api/User?page=2&limit=2&meta=true", nil)
+		if resp.Meta == nil || resp.Meta.HasNext == nil || *resp.Meta.HasNext {
+			t.Errorf("Expected no page after the last one, got %+v", resp.Meta)
+		}
+
+		resp = makeRequest(t, ts, "GET", "/api/User?page=1&limit=2", nil)
+		if resp.Meta == nil || resp.Meta.Total != nil || resp.Meta.PageSize != 0 {
+			t.Errorf("Expected no page metadata without meta=true, got %+v", resp.Meta)
+		}
+	})
+
 	// Test 4: Field selection
 	t.Run("FieldSelection", func(t *testing.T) {
 		resp := makeRequest(t, ts, "GET", "/api/User?select=id,name", nil)
@@ -610,7 +661,36 @@ func TestErrorHandling(t *testing.T) {
 		}
 	})
 
-	// Test 4: Method not allowed
+	// Test 4: Validation rules from the schema
+	t.Run("ValidationError", func(t *testing.T) {
+		reqBody := map[string]any{
+			"data": map[string]any{
+				"name":  "Invalid User",
+				"email": "not-an-email",
+				"age":   -1,
+			},
+		}
+
+		resp := makeRequest(t, ts, "POST", "/api/User", reqBody)
+
+		if resp.Success {
+			t.Fatal("Expected error, got success")
+		}
+
+		if resp.Error.Code != "VALIDATION_ERROR" {
+			t.Errorf("Expected VALIDATION_ERROR, got %s", resp.Error.Code)
+		}
+
+		rules := map[string]string{}
+		for _, fieldErr := range resp.Error.Fields {
+			rules[fieldErr.Field] = fieldErr.Rule
+		}
+		if rules["email"] != "email" || rules["age"] != "min" {
+			t.Errorf("Expected email and age field errors, got %v", resp.Error.Fields)
+		}
+	})
+
+	// Test 5: Method not allowed
 	t.Run("MethodNotAllowed", func(t *testing.T) {
 		// Use GET on a POST-only endpoint
 		resp := makeRequest(t, ts, "GET", "/api/User/batch", nil)
@@ -628,6 +708,65 @@ func TestErrorHandling(t *testing.T) {
 
 // Helper functions
 
+// TestBytesField tests that binary fields are written and returned as base64
+func TestBytesField(t *testing.T) {
+	db, err := database.NewFromURI("sqlite://:memory:")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if err := db.Connect(ctx); err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	err = db.LoadSchema(ctx, `
+model File {
+  id   Int    @id @default(autoincrement())
+  name String
+  data Bytes
+}`)
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	if err := db.SyncSchemas(ctx); err != nil {
+		t.Fatalf("Failed to sync schemas: %v", err)
+	}
+
+	server, err := rest.NewServer(rest.ServerConfig{Database: db, LogLevel: "error"})
+	if err != nil {
+		t.Fatalf("Failed to create REST server: %v", err)
+	}
+	defer server.Stop()
+	ts := httptest.NewServer(server.Router)
+	defer ts.Close()
+
+	resp := makeRequest(t, ts, "POST", "/api/File", map[string]any{
+		"data": map[string]any{"name": "a.bin", "data": "AAH/"},
+	})
+	if !resp.Success {
+		t.Fatalf("Expected success, got error: %s", resp.Error.Message)
+	}
+	if data := resp.Data.(map[string]any)["data"]; data != "AAH/" {
+		t.Errorf("Expected base64 data in the created record, got %v", data)
+	}
+
+	resp = makeRequest(t, ts, "GET", "/api/File/1", nil)
+	if !resp.Success {
+		t.Fatalf("Expected success, got error: %s", resp.Error.Message)
+	}
+	if data := resp.Data.(map[string]any)["data"]; data != "AAH/" {
+		t.Errorf("Expected base64 data, got %v", data)
+	}
+
+	resp = makeRequest(t, ts, "POST", "/api/File", map[string]any{
+		"data": map[string]any{"name": "b.bin", "data": "not base64!"},
+	})
+	if resp.Success {
+		t.Error("Expected invalid base64 data to be rejected")
+	}
+}
+
 func makeRequest(t *testing.T, ts *httptest.Server, method, path string, body any) *types.Response {
 	var bodyReader io.Reader
 	if body != nil {
@@ -745,8 +884,8 @@ const testSchema = `
 model User {
   id    Int     @id @default(autoincrement())
   name  String
-  email String  @unique
-  age   Int?
+  email String  @unique @email
+  age   Int?    @min(0)
   posts Post[]
 }
 
@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rediwo/redi-orm/drivers/sqlite"
+	"github.com/rediwo/redi-orm/rest"
+)
+
+// TestModelStream tests the live query SSE endpoint
+func TestModelStream(t *testing.T) {
+	ctx := context.Background()
+
+	// A file database is required because the polling feed uses its own connections
+	db, err := sqlite.NewSQLiteDB(t.TempDir() + "/stream.db")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	if err := db.Connect(ctx); err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	db.ChangePollInterval = 20 * time.Millisecond
+
+	if err := db.LoadSchema(ctx, testSchema); err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	if err := db.SyncSchemas(ctx); err != nil {
+		t.Fatalf("Failed to sync schemas: %v", err)
+	}
+
+	server, err := rest.NewServer(rest.ServerConfig{Database: db, LogLevel: "error"})
+	if err != nil {
+		t.Fatalf("Failed to create REST server: %v", err)
+	}
+	defer server.Stop()
+
+	ts := httptest.NewServer(server.Router)
+	defer ts.Close()
+
+	reqCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	where := url.QueryEscape(`{"name":"Alice"}`)
+	req, _ := http.NewRequestWithContext(reqCtx, http.MethodGet, ts.URL+"/api/User/stream?where="+where, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Expected text/event-stream content type, got %s", ct)
+	}
+
+	// Non-matching row first, then a matching one
+	if _, err := db.Model("User").Insert(map[string]any{"name": "Bob", "email": "bob@example.com"}).Exec(ctx); err != nil {
+		t.Fatalf("Failed to insert: %v", err)
+	}
+	if _, err := db.Model("User").Insert(map[string]any{"name": "Alice", "email": "alice@example.com"}).Exec(ctx); err != nil {
+		t.Fatalf("Failed to insert: %v", err)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	var eventName string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			eventName = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			var event struct {
+				Model     string         `json:"model"`
+				Operation string         `json:"operation"`
+				Data      map[string]any `json:"data"`
+			}
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event); err != nil {
+				t.Fatalf("Invalid event payload: %v", err)
+			}
+			if eventName != "create" || event.Operation != "create" {
+				t.Fatalf("Expected create event, got %s/%s", eventName, event.Operation)
+			}
+			if event.Data["name"] != "Alice" {
+				t.Fatalf("Expected only matching rows, got %v", event.Data["name"])
+			}
+			return
+		}
+	}
+	t.Fatalf("Stream ended without a matching event: %v", scanner.Err())
+}
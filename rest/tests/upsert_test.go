@@ -0,0 +1,184 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rediwo/redi-orm/database"
+	"github.com/rediwo/redi-orm/rest"
+)
+
+// TestUpsertAndFindOrCreate tests upserting with PUT and the find-or-create endpoint
+func TestUpsertAndFindOrCreate(t *testing.T) {
+	db, err := database.NewFromURI("sqlite://:memory:")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if err := db.Connect(ctx); err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	if err := db.LoadSchema(ctx, testSchema); err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	if err := db.SyncSchemas(ctx); err != nil {
+		t.Fatalf("Failed to sync schemas: %v", err)
+	}
+	createTestData(t, db)
+
+	server, err := rest.NewServer(rest.ServerConfig{Database: db, LogLevel: "error"})
+	if err != nil {
+		t.Fatalf("Failed to create REST server: %v", err)
+	}
+	defer server.Stop()
+
+	ts := httptest.NewServer(server.Router)
+	defer ts.Close()
+
+	countUsers := func(t *testing.T) int64 {
+		count, err := db.Model("User").Select().Count(ctx)
+		if err != nil {
+			t.Fatalf("Failed to count users: %v", err)
+		}
+		return count
+	}
+
+	t.Run("UpsertCreates", func(t *testing.T) {
+		resp := makeRequest(t, ts, http.MethodPut, "/api/User", map[string]any{
+			"where":  map[string]any{"email": "dave@example.com"},
+			"create": map[string]any{"name": "Dave", "age": 40},
+			"update": map[string]any{"age": 41},
+		})
+		if !resp.Success {
+			t.Fatalf("Expected success, got error: %v", resp.Error)
+		}
+
+		user := resp.Data.(map[string]any)
+		if user["name"] != "Dave" || user["email"] != "dave@example.com" || user["age"] != float64(40) {
+			t.Errorf("Expected the created user, got %v", user)
+		}
+		if count := countUsers(t); count != 4 {
+			t.Errorf("Expected 4 users, got %d", count)
+		}
+	})
+
+	t.Run("UpsertUpdates", func(t *testing.T) {
+		resp := makeRequest(t, ts, http.MethodPut, "/api/User", map[string]any{
+			"where":  map[string]any{"email": "dave@example.com"},
+			"create": map[string]any{"name": "Dave", "age": 40},
+			"update": map[string]any{"age": 41},
+		})
+		if !resp.Success {
+			t.Fatalf("Expected success, got error: %v", resp.Error)
+		}
+
+		if age := resp.Data.(map[string]any)["age"]; age != float64(41) {
+			t.Errorf("Expected age 41, got %v", age)
+		}
+		if count := countUsers(t); count != 4 {
+			t.Errorf("Expected 4 users, got %d", count)
+		}
+	})
+
+	t.Run("UpsertData", func(t *testing.T) {
+		resp := makeRequest(t, ts, http.MethodPut, "/api/User", map[string]any{
+			"where": map[string]any{"email": "alice@example.com"},
+			"data":  map[string]any{"name": "Alice Smith", "age": 26},
+		})
+		if !resp.Success {
+			t.Fatalf("Expected success, got error: %v", resp.Error)
+		}
+
+		user := resp.Data.(map[string]any)
+		if user["name"] != "Alice Smith" || user["age"] != float64(26) {
+			t.Errorf("Expected the updated user, got %v", user)
+		}
+	})
+
+	t.Run("FindOrCreate", func(t *testing.T) {
+		body := map[string]any{
+			"where":  map[string]any{"email": "erin@example.com"},
+			"create": map[string]any{"name": "Erin", "age": 28},
+		}
+
+		resp := makeRequest(t, ts, http.MethodPost, "/api/User/find-or-create", body)
+		if !resp.Success {
+			t.Fatalf("Expected success, got error: %v", resp.Error)
+		}
+		created := resp.Data.(map[string]any)
+		if created["name"] != "Erin" || created["email"] != "erin@example.com" {
+			t.Errorf("Expected the created user, got %v", created)
+		}
+
+		// The second request finds the record instead of creating another
+		body["create"] = map[string]any{"name": "Someone Else"}
+		resp = makeRequest(t, ts, http.MethodPost, "/api/User/find-or-create", body)
+		if !resp.Success {
+			t.Fatalf("Expected success, got error: %v", resp.Error)
+		}
+		found := resp.Data.(map[string]any)
+		if found["id"] != created["id"] || found["name"] != "Erin" {
+			t.Errorf("Expected the existing user %v, got %v", created, found)
+		}
+		if count := countUsers(t); count != 5 {
+			t.Errorf("Expected 5 users, got %d", count)
+		}
+	})
+
+	t.Run("StatusCodes", func(t *testing.T) {
+		post := func() int {
+			body := strings.NewReader(`{"where":{"email":"frank@example.com"},"create":{"name":"Frank"}}`)
+			resp, err := http.Post(ts.URL+"/api/User/find-or-create", "application/json", body)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			resp.Body.Close()
+			return resp.StatusCode
+		}
+
+		if status := post(); status != http.StatusCreated {
+			t.Errorf("Expected 201 when the record is created, got %d", status)
+		}
+		if status := post(); status != http.StatusOK {
+			t.Errorf("Expected 200 when the record is found, got %d", status)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		resp := makeRequest(t, ts, http.MethodPut, "/api/User", map[string]any{
+			"where": map[string]any{"name": "Bob"},
+			"data":  map[string]any{"age": 31},
+		})
+		if resp.Success || resp.Error.Code != "INVALID_REQUEST" {
+			t.Errorf("Expected a where on a field that is not unique to fail, got %+v", resp)
+		}
+
+		resp = makeRequest(t, ts, http.MethodPost, "/api/User/find-or-create", map[string]any{
+			"where":  map[string]any{"email": map[string]any{"contains": "bob"}},
+			"create": map[string]any{"name": "Bob"},
+		})
+		if resp.Success || resp.Error.Code != "INVALID_REQUEST" {
+			t.Errorf("Expected a where with operators to fail, got %+v", resp)
+		}
+
+		resp = makeRequest(t, ts, http.MethodPut, "/api/User", map[string]any{
+			"where": map[string]any{"email": "gina@example.com"},
+		})
+		if resp.Success || resp.Error.Code != "INVALID_REQUEST" {
+			t.Errorf("Expected an upsert without data to fail, got %+v", resp)
+		}
+
+		resp = makeRequest(t, ts, http.MethodPost, "/api/User/find-or-create", map[string]any{
+			"where":  map[string]any{"email": "not-an-email"},
+			"create": map[string]any{"name": "Invalid"},
+		})
+		if resp.Success || resp.Error.Code != "VALIDATION_ERROR" {
+			t.Errorf("Expected an invalid record to fail validation, got %+v", resp)
+		}
+	})
+}
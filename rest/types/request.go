@@ -2,6 +2,7 @@ package types
 
 import (
 	"encoding/json"
+	"fmt"
 	"strconv"
 	"strings"
 )
@@ -9,8 +10,9 @@ import (
 // QueryParams represents common query parameters for data operations
 type QueryParams struct {
 	// Pagination
-	Page  int `json:"page"`
-	Limit int `json:"limit"`
+	Page  int  `json:"page"`
+	Limit int  `json:"limit"`
+	Meta  bool `json:"meta"` // Report the total and the page in the response metadata
 
 	// Filtering
 	Where  map[string]any `json:"where"`
@@ -55,6 +57,10 @@ func ParseQueryParams(params map[string][]string) (*QueryParams, error) {
 		}
 	}
 
+	if meta := params["meta"]; len(meta) > 0 {
+		qp.Meta, _ = strconv.ParseBool(meta[0])
+	}
+
 	// Parse where conditions
 	if where := params["where"]; len(where) > 0 {
 		if err := json.Unmarshal([]byte(where[0]), &qp.Where); err != nil {
@@ -67,10 +73,18 @@ func ParseQueryParams(params map[string][]string) (*QueryParams, error) {
 
 	// Parse sorting
 	if sort := params["sort"]; len(sort) > 0 {
-		qp.Sort = strings.Split(sort[0], ",")
+		fields, err := parseSortParam(sort[0])
+		if err != nil {
+			return nil, err
+		}
+		qp.Sort = fields
 	}
 	if orderBy := params["order_by"]; len(orderBy) > 0 {
-		qp.OrderBy = strings.Split(orderBy[0], ",")
+		fields, err := parseSortParam(orderBy[0])
+		if err != nil {
+			return nil, err
+		}
+		qp.OrderBy = fields
 	}
 
 	// Parse field selection
@@ -104,6 +118,171 @@ func ParseQueryParams(params map[string][]string) (*QueryParams, error) {
 	return qp, nil
 }
 
+// aggregateParams are the aggregations of aggregate and groupBy requests
+var aggregateParams = []string{"_count", "_sum", "_avg", "_min", "_max"}
+
+// ParseAggregateParams parses the query parameters of an aggregate or groupBy request
+// into the options of the ORM. Aggregations take a comma-separated list of fields, as in
+// _sum=amount,quantity, or the ORM option as JSON; _count=true counts every record.
+// where, having and orderBy are JSON, by is a comma-separated list or a JSON array.
+func ParseAggregateParams(params map[string][]string) (map[string]any, error) {
+	options := make(map[string]any)
+
+	if where := params["where"]; len(where) > 0 {
+		var conditions map[string]any
+		if err := json.Unmarshal([]byte(where[0]), &conditions); err != nil {
+			return nil, fmt.Errorf("invalid where: %w", err)
+		}
+		options["where"] = conditions
+	} else if filter := parseFilterParams(params); len(filter) > 0 {
+		options["where"] = filter
+	}
+
+	for _, name := range aggregateParams {
+		values := params[name]
+		if len(values) == 0 {
+			continue
+		}
+		value := strings.TrimSpace(values[0])
+		if name == "_count" {
+			if count, err := strconv.ParseBool(value); err == nil {
+				options[name] = count
+				continue
+			}
+		}
+		fields, err := parseAggregateFields(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		options[name] = fields
+	}
+
+	if by := params["by"]; len(by) > 0 {
+		value := strings.TrimSpace(by[0])
+		if strings.HasPrefix(value, "[") {
+			var fields []string
+			if err := json.Unmarshal([]byte(value), &fields); err != nil {
+				return nil, fmt.Errorf("invalid by: %w", err)
+			}
+			options["by"] = fields
+		} else {
+			options["by"] = splitFields(value)
+		}
+	}
+
+	for _, name := range []string{"having", "orderBy"} {
+		if values := params[name]; len(values) > 0 {
+			var value any
+			if err := json.Unmarshal([]byte(values[0]), &value); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", name, err)
+			}
+			options[name] = value
+		}
+	}
+
+	for _, name := range []string{"take", "skip"} {
+		if values := params[name]; len(values) > 0 {
+			n, err := strconv.Atoi(values[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", name, err)
+			}
+			options[name] = n
+		}
+	}
+
+	return options, nil
+}
+
+// parseAggregateFields parses the fields of an aggregation, given as a comma-separated
+// list or as a JSON object of the ORM, into {field: true, ...}
+func parseAggregateFields(value string) (map[string]any, error) {
+	if strings.HasPrefix(value, "{") {
+		var fields map[string]any
+		if err := json.Unmarshal([]byte(value), &fields); err != nil {
+			return nil, err
+		}
+		return fields, nil
+	}
+	fields := make(map[string]any)
+	for _, field := range splitFields(value) {
+		fields[field] = true
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no fields")
+	}
+	return fields, nil
+}
+
+// splitFields splits a comma-separated list of fields, skipping empty entries
+func splitFields(value string) []string {
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// parseSortParam parses a sort parameter into fields in the order they apply, prefixed
+// with - when descending. Besides a comma-separated list, as in -age,name, it takes the
+// orderBy of the ORM as JSON, as in [{"age":"desc"},{"name":"asc"}] or
+// {"age":"desc","name":"asc"}, whose fields apply in the order they are written.
+func parseSortParam(value string) ([]string, error) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "[") && !strings.HasPrefix(value, "{") {
+		var fields []string
+		for _, field := range strings.Split(value, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				fields = append(fields, field)
+			}
+		}
+		return fields, nil
+	}
+
+	var fields []string
+	decoder := json.NewDecoder(strings.NewReader(value))
+	list := strings.HasPrefix(value, "[")
+	if list {
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+	}
+	for !list || decoder.More() {
+		if token, err := decoder.Token(); err != nil {
+			return nil, err
+		} else if token != json.Delim('{') {
+			return nil, fmt.Errorf("invalid sort: expected an object of fields and directions")
+		}
+		for decoder.More() {
+			token, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			var direction string
+			if err := decoder.Decode(&direction); err != nil {
+				return nil, fmt.Errorf("invalid sort direction of %v: %w", token, err)
+			}
+			field := token.(string)
+			switch strings.ToLower(direction) {
+			case "asc":
+				fields = append(fields, field)
+			case "desc":
+				fields = append(fields, "-"+field)
+			default:
+				return nil, fmt.Errorf("invalid sort direction of %s: %q", field, direction)
+			}
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		if !list {
+			break
+		}
+	}
+	return fields, nil
+}
+
 // parseFilterParams parses filter[field]=value style parameters
 func parseFilterParams(params map[string][]string) map[string]any {
 	filters := make(map[string]any)
@@ -169,6 +348,20 @@ type UpdateRequest struct {
 	Data any `json:"data"`
 }
 
+// UpsertRequest represents a request to create or update the record with a unique key
+type UpsertRequest struct {
+	Where  map[string]any `json:"where"`
+	Create map[string]any `json:"create"`
+	Update map[string]any `json:"update"`
+	Data   map[string]any `json:"data"` // Used as create and update when they are omitted
+}
+
+// FindOrCreateRequest represents a request to find the record with a unique key or create it
+type FindOrCreateRequest struct {
+	Where  map[string]any `json:"where"`
+	Create map[string]any `json:"create"`
+}
+
 // BatchUpdateRequest represents a request to update multiple records
 type BatchUpdateRequest struct {
 	Where map[string]any `json:"where"`
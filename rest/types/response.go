@@ -1,6 +1,10 @@
 package types
 
-import "time"
+import (
+	"time"
+
+	"github.com/rediwo/redi-orm/schema"
+)
 
 // Response represents the standard API response format
 type Response struct {
@@ -24,13 +28,20 @@ type Meta struct {
 	ExecutionTime string `json:"execution_time"`
 	QueryCount    int    `json:"query_count"`
 	Timestamp     string `json:"timestamp"`
+
+	// Page of a list, requested with ?meta=true
+	Total    *int  `json:"total,omitempty"`
+	Page     int   `json:"page,omitempty"`
+	PageSize int   `json:"pageSize,omitempty"`
+	HasNext  *bool `json:"hasNext,omitempty"`
 }
 
 // ErrorDetail contains error information
 type ErrorDetail struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-	Details string `json:"details,omitempty"`
+	Code    string              `json:"code"`
+	Message string              `json:"message"`
+	Details string              `json:"details,omitempty"`
+	Fields  []schema.FieldError `json:"fields,omitempty"` // Broken validation rules
 }
 
 // NewSuccessResponse creates a successful response
@@ -81,6 +92,13 @@ func NewErrorResponse(code, message string, details ...string) *Response {
 	}
 }
 
+// NewValidationErrorResponse creates an error response listing the broken validation rules
+func NewValidationErrorResponse(err *schema.ValidationError) *Response {
+	response := NewErrorResponse("VALIDATION_ERROR", err.Error())
+	response.Error.Fields = err.Errors
+	return response
+}
+
 // WithExecutionTime adds execution time to the response
 func (r *Response) WithExecutionTime(duration time.Duration) *Response {
 	if r.Meta == nil {
@@ -98,3 +116,17 @@ func (r *Response) WithQueryCount(count int) *Response {
 	r.Meta.QueryCount = count
 	return r
 }
+
+// WithPageMeta adds the page of a list to the metadata of the response. The page holds
+// pageSize records starting at offset, out of total.
+func (r *Response) WithPageMeta(page, pageSize, offset, total int) *Response {
+	if r.Meta == nil {
+		r.Meta = &Meta{}
+	}
+	hasNext := pageSize > 0 && offset+pageSize < total
+	r.Meta.Total = &total
+	r.Meta.Page = page
+	r.Meta.PageSize = pageSize
+	r.Meta.HasNext = &hasNext
+	return r
+}
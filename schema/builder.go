@@ -1,5 +1,7 @@
 package schema
 
+import "regexp"
+
 type FieldBuilder struct {
 	field Field
 }
@@ -49,6 +51,11 @@ func (fb *FieldBuilder) JSON() *FieldBuilder {
 	return fb
 }
 
+func (fb *FieldBuilder) Bytes() *FieldBuilder {
+	fb.field.Type = FieldTypeBytes
+	return fb
+}
+
 func (fb *FieldBuilder) PrimaryKey() *FieldBuilder {
 	fb.field.PrimaryKey = true
 	fb.field.Nullable = false
@@ -75,6 +82,45 @@ func (fb *FieldBuilder) Default(value any) *FieldBuilder {
 	return fb
 }
 
+func (fb *FieldBuilder) UpdatedAt() *FieldBuilder {
+	fb.field.UpdatedAt = true
+	return fb
+}
+
+func (fb *FieldBuilder) Length(min, max int) *FieldBuilder {
+	v := fb.validation()
+	v.MinLength = &min
+	v.MaxLength = &max
+	return fb
+}
+
+func (fb *FieldBuilder) Regex(pattern string) *FieldBuilder {
+	fb.validation().Pattern = regexp.MustCompile(pattern)
+	return fb
+}
+
+func (fb *FieldBuilder) Min(min float64) *FieldBuilder {
+	fb.validation().Min = &min
+	return fb
+}
+
+func (fb *FieldBuilder) Max(max float64) *FieldBuilder {
+	fb.validation().Max = &max
+	return fb
+}
+
+func (fb *FieldBuilder) Email() *FieldBuilder {
+	fb.validation().Email = true
+	return fb
+}
+
+func (fb *FieldBuilder) validation() *Validation {
+	if fb.field.Validation == nil {
+		fb.field.Validation = &Validation{}
+	}
+	return fb.field.Validation
+}
+
 func (fb *FieldBuilder) Index() *FieldBuilder {
 	fb.field.Index = true
 	return fb
@@ -0,0 +1,132 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CompositeType describes the structure of embedded documents, declared with Prisma
+// type blocks. Fields using it are documents, or arrays of documents.
+type CompositeType struct {
+	Name   string
+	Fields []Field
+}
+
+// GetFieldByName returns the field with the given name, or nil
+func (c *CompositeType) GetFieldByName(name string) *Field {
+	for i := range c.Fields {
+		if c.Fields[i].Name == name {
+			return &c.Fields[i]
+		}
+	}
+	return nil
+}
+
+// GetFieldByColumnName returns the field stored under the given document key, or nil
+func (c *CompositeType) GetFieldByColumnName(columnName string) *Field {
+	for i := range c.Fields {
+		if c.Fields[i].GetColumnName() == columnName {
+			return &c.Fields[i]
+		}
+	}
+	return nil
+}
+
+// ColumnPath maps a dot-separated path of field names inside the type, e.g. geo.lat, to
+// the stored document keys. Array indexes and unknown names are kept as given.
+func (c *CompositeType) ColumnPath(path string) string {
+	return c.mapPath(path, (*CompositeType).GetFieldByName, Field.GetColumnName)
+}
+
+// FieldPath maps a dot-separated path of document keys inside the type to field names
+func (c *CompositeType) FieldPath(columnPath string) string {
+	return c.mapPath(columnPath, (*CompositeType).GetFieldByColumnName, func(f Field) string { return f.Name })
+}
+
+func (c *CompositeType) mapPath(path string, lookup func(*CompositeType, string) *Field, name func(Field) string) string {
+	segments := strings.Split(path, ".")
+	current := c
+	for i, segment := range segments {
+		if current == nil || isArrayIndex(segment) {
+			continue
+		}
+		field := lookup(current, segment)
+		if field == nil {
+			current = nil
+			continue
+		}
+		segments[i] = name(*field)
+		current = field.Composite
+	}
+	return strings.Join(segments, ".")
+}
+
+// GetFieldByPath returns the field at a dot-separated path through composite types,
+// e.g. address.city. Plain field names are looked up like GetField.
+func (s *Schema) GetFieldByPath(path string) (*Field, error) {
+	head, rest, nested := strings.Cut(path, ".")
+	field, err := s.GetField(head)
+	if err != nil || !nested {
+		return field, err
+	}
+
+	for _, segment := range strings.Split(rest, ".") {
+		if isArrayIndex(segment) {
+			continue
+		}
+		if field.Composite == nil {
+			return nil, fmt.Errorf("field %s in path %s is not a composite type", field.Name, path)
+		}
+		next := field.Composite.GetFieldByName(segment)
+		if next == nil {
+			return nil, fmt.Errorf("field %s not found in type %s", segment, field.Composite.Name)
+		}
+		field = next
+	}
+	return field, nil
+}
+
+// GetColumnPathByFieldPath maps a dot-separated field path through composite types to the
+// stored column path, e.g. homeAddress.zipCode to home_address.zip_code
+func (s *Schema) GetColumnPathByFieldPath(path string) (string, error) {
+	head, rest, nested := strings.Cut(path, ".")
+	field, err := s.GetField(head)
+	if err != nil {
+		return "", err
+	}
+	if !nested {
+		return field.GetColumnName(), nil
+	}
+	if field.Composite == nil {
+		return "", fmt.Errorf("field %s in path %s is not a composite type", field.Name, path)
+	}
+	return field.GetColumnName() + "." + field.Composite.ColumnPath(rest), nil
+}
+
+// GetFieldPathByColumnPath maps a stored column path through composite types to the field path
+func (s *Schema) GetFieldPathByColumnPath(columnPath string) (string, error) {
+	head, rest, nested := strings.Cut(columnPath, ".")
+	field, err := s.GetFieldByColumnName(head)
+	if err != nil {
+		return "", err
+	}
+	if !nested {
+		return field.Name, nil
+	}
+	if field.Composite == nil {
+		return "", fmt.Errorf("field %s in path %s is not a composite type", field.Name, columnPath)
+	}
+	return field.Name + "." + field.Composite.FieldPath(rest), nil
+}
+
+func isArrayIndex(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	for _, ch := range segment {
+		if ch < '0' || ch > '9' {
+			return false
+		}
+	}
+	return true
+}
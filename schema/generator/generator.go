@@ -2,9 +2,12 @@ package generator
 
 import (
 	"fmt"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/rediwo/redi-orm/prisma"
 	"github.com/rediwo/redi-orm/schema"
@@ -39,6 +42,7 @@ func (g *SchemaGenerator) SchemaToPrismaAST(s *schema.Schema) (*prisma.ModelStat
 		Name:            s.Name,
 		Fields:          []*prisma.Field{},
 		BlockAttributes: []*prisma.BlockAttribute{},
+		Comment:         s.Comment,
 	}
 
 	// Add table name mapping if different from model name
@@ -50,6 +54,12 @@ func (g *SchemaGenerator) SchemaToPrismaAST(s *schema.Schema) (*prisma.ModelStat
 			},
 		})
 	}
+	if s.Datasource != "" {
+		model.BlockAttributes = append(model.BlockAttributes, &prisma.BlockAttribute{
+			Name: "datasource",
+			Args: []prisma.Expression{&prisma.Identifier{Value: s.Datasource}},
+		})
+	}
 
 	// Convert fields
 	for _, field := range s.Fields {
@@ -92,20 +102,69 @@ func (g *SchemaGenerator) SchemaToPrismaAST(s *schema.Schema) (*prisma.ModelStat
 			attrName = "unique"
 		}
 
+		args := []prisma.Expression{
+			&prisma.ArrayExpression{Elements: fieldExprs},
+		}
+		// Keep index names that differ from the generated default, e.g. pulled from a database
+		if index.Name != "" && index.Name != utils.GenerateIndexName("", index.Fields, index.Unique, "") {
+			args = append(args, &prisma.NamedArgument{
+				Name:  "map",
+				Value: &prisma.StringLiteral{Value: index.Name},
+			})
+		}
+
 		model.BlockAttributes = append(model.BlockAttributes, &prisma.BlockAttribute{
 			Name: attrName,
-			Args: []prisma.Expression{
-				&prisma.ArrayExpression{Elements: fieldExprs},
-			},
+			Args: args,
 		})
 	}
 
 	return model, nil
 }
 
+// validationAttributes converts validation rules to @length, @regex, @min, @max and @email
+func validationAttributes(v *schema.Validation) []*prisma.Attribute {
+	var attributes []*prisma.Attribute
+	number := func(f float64) prisma.Expression {
+		return &prisma.NumberLiteral{Value: strconv.FormatFloat(f, 'f', -1, 64)}
+	}
+
+	var lengthArgs []prisma.Expression
+	if v.MinLength != nil {
+		lengthArgs = append(lengthArgs, &prisma.NamedArgument{Name: "min", Value: number(float64(*v.MinLength))})
+	}
+	if v.MaxLength != nil {
+		lengthArgs = append(lengthArgs, &prisma.NamedArgument{Name: "max", Value: number(float64(*v.MaxLength))})
+	}
+	if len(lengthArgs) > 0 {
+		attributes = append(attributes, &prisma.Attribute{Name: "length", Args: lengthArgs})
+	}
+	if v.Pattern != nil {
+		pattern := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(v.Pattern.String())
+		attributes = append(attributes, &prisma.Attribute{Name: "regex", Args: []prisma.Expression{&prisma.StringLiteral{Value: pattern}}})
+	}
+	if v.Min != nil {
+		attributes = append(attributes, &prisma.Attribute{Name: "min", Args: []prisma.Expression{number(*v.Min)}})
+	}
+	if v.Max != nil {
+		attributes = append(attributes, &prisma.Attribute{Name: "max", Args: []prisma.Expression{number(*v.Max)}})
+	}
+	if v.Email {
+		attributes = append(attributes, &prisma.Attribute{Name: "email"})
+	}
+	return attributes
+}
+
 // fieldToPrismaField converts a Field to prisma.Field
 func (g *SchemaGenerator) fieldToPrismaField(f schema.Field) (*prisma.Field, error) {
 	prismaType := g.fieldTypeToPrismaType(f.Type)
+	isEnum := f.Enum != "" && len(f.EnumValues) > 0
+	if isEnum {
+		prismaType = f.Enum
+	}
+	if f.Composite != nil {
+		prismaType = f.Composite.Name
+	}
 
 	field := &prisma.Field{
 		Name:       f.Name,
@@ -113,6 +172,7 @@ func (g *SchemaGenerator) fieldToPrismaField(f schema.Field) (*prisma.Field, err
 		Optional:   f.Nullable,
 		List:       g.isArrayType(f.Type),
 		Attributes: []*prisma.Attribute{},
+		Comment:    f.Comment,
 	}
 
 	// Add attributes
@@ -127,6 +187,10 @@ func (g *SchemaGenerator) fieldToPrismaField(f schema.Field) (*prisma.Field, err
 	if f.Default != nil {
 		// Always use convertDefaultValue with migrator
 		defaultExpr := g.convertDefaultValue(f.Default, f.Type, f.AutoIncrement, g.migrator)
+		// Enum defaults are value names, not strings
+		if str, ok := defaultExpr.(*prisma.StringLiteral); ok && isEnum && slices.Contains(f.EnumValues, str.Value) {
+			defaultExpr = &prisma.Identifier{Value: str.Value}
+		}
 		if defaultExpr != nil {
 			field.Attributes = append(field.Attributes, &prisma.Attribute{
 				Name: "default",
@@ -142,6 +206,14 @@ func (g *SchemaGenerator) fieldToPrismaField(f schema.Field) (*prisma.Field, err
 		})
 	}
 
+	if f.UpdatedAt {
+		field.Attributes = append(field.Attributes, &prisma.Attribute{Name: "updatedAt"})
+	}
+
+	if f.Validation != nil {
+		field.Attributes = append(field.Attributes, validationAttributes(f.Validation)...)
+	}
+
 	// Add @map attribute if field has custom column mapping
 	if f.Map != "" {
 		field.Attributes = append(field.Attributes, &prisma.Attribute{
@@ -184,11 +256,19 @@ func (g *SchemaGenerator) relationToPrismaField(name string, r schema.Relation)
 		List:       r.Type == schema.RelationOneToMany || r.Type == schema.RelationManyToMany,
 		Attributes: []*prisma.Attribute{},
 	}
+	if len(r.Models) > 0 {
+		field.Type = &prisma.FieldType{Name: r.Models[0], Union: r.Models}
+	}
 
 	// Add @relation attribute if we have foreign key info
-	if r.ForeignKey != "" || r.References != "" {
+	if r.ForeignKey != "" || r.References != "" || r.Name != "" {
 		var args []prisma.Expression
 
+		// The name pairs relations between the same models
+		if r.Name != "" {
+			args = append(args, &prisma.StringLiteral{Value: r.Name})
+		}
+
 		if r.ForeignKey != "" {
 			args = append(args, &prisma.NamedArgument{
 				Name: "fields",
@@ -211,6 +291,24 @@ func (g *SchemaGenerator) relationToPrismaField(name string, r schema.Relation)
 			})
 		}
 
+		// The type field selects the model of a polymorphic relation
+		if len(r.Models) > 0 {
+			args = append(args, &prisma.NamedArgument{
+				Name:  "type",
+				Value: &prisma.Identifier{Value: r.TypeField},
+			})
+		}
+
+		// NO ACTION is the default, so only other actions are written
+		for _, action := range []struct{ name, value string }{{"onDelete", r.OnDelete}, {"onUpdate", r.OnUpdate}} {
+			if name, ok := prismaReferentialActions[strings.ToUpper(action.value)]; ok {
+				args = append(args, &prisma.NamedArgument{
+					Name:  action.name,
+					Value: &prisma.Identifier{Value: name},
+				})
+			}
+		}
+
 		if len(args) > 0 {
 			field.Attributes = append(field.Attributes, &prisma.Attribute{
 				Name: "relation",
@@ -222,6 +320,14 @@ func (g *SchemaGenerator) relationToPrismaField(name string, r schema.Relation)
 	return field
 }
 
+// prismaReferentialActions maps the referential actions of foreign keys to Prisma
+var prismaReferentialActions = map[string]string{
+	schema.ActionCascade:    "Cascade",
+	schema.ActionSetNull:    "SetNull",
+	schema.ActionSetDefault: "SetDefault",
+	schema.ActionRestrict:   "Restrict",
+}
+
 // fieldTypeToPrismaType converts schema.FieldType to Prisma type string
 func (g *SchemaGenerator) fieldTypeToPrismaType(ft schema.FieldType) string {
 	switch ft {
@@ -243,7 +349,7 @@ func (g *SchemaGenerator) fieldTypeToPrismaType(ft schema.FieldType) string {
 		return "Decimal"
 	case schema.FieldTypeObjectId:
 		return "String" // ObjectId is represented as String in Prisma
-	case schema.FieldTypeBinary:
+	case schema.FieldTypeBytes:
 		return "Bytes"
 	case schema.FieldTypeDecimal128:
 		return "Decimal"
@@ -288,8 +394,10 @@ func (g *SchemaGenerator) convertDefaultValue(value any, fieldType schema.FieldT
 		return &prisma.StringLiteral{Value: v}
 	case int, int32, int64:
 		return &prisma.NumberLiteral{Value: fmt.Sprintf("%d", v)}
-	case float32, float64:
-		return &prisma.NumberLiteral{Value: fmt.Sprintf("%f", v)}
+	case float32:
+		return &prisma.NumberLiteral{Value: strconv.FormatFloat(float64(v), 'f', -1, 32)}
+	case float64:
+		return &prisma.NumberLiteral{Value: strconv.FormatFloat(v, 'f', -1, 64)}
 	case bool:
 		if v {
 			return &prisma.Identifier{Value: "true"}
@@ -359,6 +467,26 @@ func (g *SchemaGenerator) GenerateFullPrismaFile(schemas []*schema.Schema, datas
 		return sortedSchemas[i].Name < sortedSchemas[j].Name
 	})
 
+	// Add the enums used by the models
+	for _, enum := range collectEnums(sortedSchemas) {
+		builder.WriteString(enum.String())
+		builder.WriteString("\n\n")
+	}
+
+	// Add the composite types used by the models
+	for _, composite := range collectCompositeTypes(sortedSchemas) {
+		stmt := &prisma.TypeStatement{Name: composite.Name}
+		for _, f := range composite.Fields {
+			field, err := g.fieldToPrismaField(f)
+			if err != nil {
+				return "", fmt.Errorf("failed to convert type %s: %w", composite.Name, err)
+			}
+			stmt.Fields = append(stmt.Fields, field)
+		}
+		builder.WriteString(stmt.String())
+		builder.WriteString("\n\n")
+	}
+
 	// Add models
 	for i, s := range sortedSchemas {
 		if i > 0 {
@@ -377,6 +505,83 @@ func (g *SchemaGenerator) GenerateFullPrismaFile(schemas []*schema.Schema, datas
 	return builder.String(), nil
 }
 
+// collectEnums returns the enum definitions referenced by the fields of the schemas, sorted by name
+func collectEnums(schemas []*schema.Schema) []*prisma.EnumStatement {
+	var enums []*prisma.EnumStatement
+	seen := make(map[string]bool)
+	fields := allModelFields(schemas)
+	for _, composite := range collectCompositeTypes(schemas) {
+		fields = append(fields, composite.Fields...)
+	}
+	for _, field := range fields {
+		if field.Enum == "" || len(field.EnumValues) == 0 || seen[field.Enum] {
+			continue
+		}
+		seen[field.Enum] = true
+
+		enum := &prisma.EnumStatement{Name: field.Enum}
+		for _, value := range field.EnumValues {
+			enum.Values = append(enum.Values, &prisma.EnumValue{Name: value})
+		}
+		enums = append(enums, enum)
+	}
+	sort.Slice(enums, func(i, j int) bool {
+		return enums[i].Name < enums[j].Name
+	})
+	return enums
+}
+
+// collectCompositeTypes returns the composite types referenced by the fields of the
+// schemas, including types nested in other types, sorted by name
+func collectCompositeTypes(schemas []*schema.Schema) []*schema.CompositeType {
+	var composites []*schema.CompositeType
+	seen := make(map[string]bool)
+	var collect func(fields []schema.Field)
+	collect = func(fields []schema.Field) {
+		for _, field := range fields {
+			if field.Composite == nil || seen[field.Composite.Name] {
+				continue
+			}
+			seen[field.Composite.Name] = true
+			composites = append(composites, field.Composite)
+			collect(field.Composite.Fields)
+		}
+	}
+	collect(allModelFields(schemas))
+
+	sort.Slice(composites, func(i, j int) bool {
+		return composites[i].Name < composites[j].Name
+	})
+	return composites
+}
+
+func allModelFields(schemas []*schema.Schema) []schema.Field {
+	var fields []schema.Field
+	for _, s := range schemas {
+		fields = append(fields, s.Fields...)
+	}
+	return fields
+}
+
+// dateTimeDbType returns the @db native type of a date or time column that does not hold a
+// plain timestamp, or "" for timestamp columns
+func dateTimeDbType(columnType string) string {
+	columnType = strings.ToLower(strings.TrimSpace(columnType))
+	// Drop a precision such as TIME(6)
+	if i := strings.Index(columnType, "("); i >= 0 {
+		columnType = columnType[:i] + columnType[strings.Index(columnType, ")")+1:]
+	}
+	switch columnType {
+	case "date":
+		return "@db.Date"
+	case "time", "time without time zone":
+		return "@db.Time"
+	case "timestamptz", "timestamp with time zone":
+		return "@db.Timestamptz"
+	}
+	return ""
+}
+
 // GenerateSchemaFromTable creates a schema from database table information
 func GenerateSchemaFromTable(tableInfo *types.TableInfo, migrator types.DatabaseSpecificMigrator) (*schema.Schema, error) {
 	// Create new schema with model name from table name
@@ -384,6 +589,7 @@ func GenerateSchemaFromTable(tableInfo *types.TableInfo, migrator types.Database
 	modelName := utils.Singularize(utils.ToPascalCase(tableInfo.Name))
 	s := schema.New(modelName)
 	s.WithTableName(tableInfo.Name)
+	s.Comment = tableInfo.Comment
 
 	// Track primary key fields for composite key detection
 	var primaryKeyFields []string
@@ -400,6 +606,20 @@ func GenerateSchemaFromTable(tableInfo *types.TableInfo, migrator types.Database
 			PrimaryKey:    col.PrimaryKey,
 			AutoIncrement: col.AutoIncrement,
 			Unique:        col.Unique,
+			Comment:       col.Comment,
+		}
+
+		// Native enum columns hold strings; values that are not valid Prisma identifiers
+		// cannot be declared, so such columns stay plain strings
+		if col.Enum != "" && len(col.EnumValues) > 0 && allIdentifiers(col.EnumValues) {
+			field.Type = schema.FieldTypeString
+			field.Enum = utils.ToPascalCase(col.Enum)
+			field.EnumValues = col.EnumValues
+		}
+
+		// Date and time columns keep their native type, which decides how values are stored
+		if field.Type == schema.FieldTypeDateTime {
+			field.DbType = dateTimeDbType(col.Type)
 		}
 
 		// Use migrator to parse and normalize default value
@@ -443,8 +663,22 @@ func GenerateSchemaFromTable(tableInfo *types.TableInfo, migrator types.Database
 			fieldNames[i] = utils.ToCamelCase(colName)
 		}
 
+		// Single-column unique indexes are expressed as @unique on the field
+		if idx.Unique && len(fieldNames) == 1 {
+			if field := s.GetFieldByName(fieldNames[0]); field != nil {
+				field.Unique = true
+				continue
+			}
+		}
+
+		// Names the database generated for constraints are not kept
+		indexName := idx.Name
+		if migrator.IsSystemIndex(indexName) {
+			indexName = utils.GenerateIndexName("", fieldNames, idx.Unique, "")
+		}
+
 		s.AddIndex(schema.Index{
-			Name:   idx.Name,
+			Name:   indexName,
 			Fields: fieldNames,
 			Unique: idx.Unique,
 		})
@@ -456,8 +690,28 @@ func GenerateSchemaFromTable(tableInfo *types.TableInfo, migrator types.Database
 	return s, nil
 }
 
+// allIdentifiers reports whether all values are valid Prisma identifiers
+func allIdentifiers(values []string) bool {
+	for _, value := range values {
+		for i, ch := range value {
+			if !(ch == '_' || unicode.IsLetter(ch) || (i > 0 && unicode.IsDigit(ch))) {
+				return false
+			}
+		}
+		if value == "" {
+			return false
+		}
+	}
+	return true
+}
+
 // GenerateSchemasFromTablesWithRelations generates schemas with relations inferred from foreign keys
+// Schemaless databases infer their schemas from sampled documents instead.
 func GenerateSchemasFromTablesWithRelations(migrator types.DatabaseMigrator) ([]*schema.Schema, error) {
+	if inferrer, ok := migrator.(types.SchemaInferrer); ok {
+		return inferrer.InferSchemas(types.DefaultSampleSize)
+	}
+
 	// Get all tables from database
 	tables, err := migrator.GetTables()
 	if err != nil {
@@ -519,9 +773,28 @@ func GenerateSchemasFromTablesWithRelations(migrator types.DatabaseMigrator) ([]
 				relationName = utils.ToCamelCase(utils.Singularize(fk.ReferencedTable))
 			}
 
+			// Use the plural form of the model name (not table name) for the inverse
+			// First convert to lowercase for proper pluralization
+			inverseName := utils.Pluralize(strings.ToLower(schemaObj.Name))
+
+			// Relations of a table to itself, or several between two tables, are named
+			// after their foreign key so that each pairs with its own inverse
+			var name string
+			references := 0
+			for _, other := range tableInfo.ForeignKeys {
+				if other.ReferencedTable == fk.ReferencedTable {
+					references++
+				}
+			}
+			if references > 1 || fk.ReferencedTable == tableName {
+				name = schemaObj.Name + utils.ToPascalCase(relationName)
+				inverseName = relationName + utils.ToPascalCase(inverseName)
+			}
+
 			schemaObj.AddRelation(relationName, schema.Relation{
 				Type:       schema.RelationManyToOne,
 				Model:      referencedSchema.Name,
+				Name:       name,
 				ForeignKey: utils.ToCamelCase(fk.Column),
 				References: utils.ToCamelCase(fk.ReferencedColumn),
 				OnDelete:   fk.OnDelete,
@@ -529,12 +802,10 @@ func GenerateSchemasFromTablesWithRelations(migrator types.DatabaseMigrator) ([]
 			})
 
 			// Add one-to-many relation on referenced model
-			// Use the plural form of the model name (not table name)
-			// First convert to lowercase for proper pluralization
-			inverseName := utils.Pluralize(strings.ToLower(schemaObj.Name))
 			referencedSchema.AddRelation(inverseName, schema.Relation{
 				Type:       schema.RelationOneToMany,
 				Model:      schemaObj.Name,
+				Name:       name,
 				ForeignKey: utils.ToCamelCase(fk.Column),
 				References: utils.ToCamelCase(fk.ReferencedColumn),
 			})
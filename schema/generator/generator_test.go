@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/rediwo/redi-orm/prisma"
 	"github.com/rediwo/redi-orm/schema"
 	"github.com/rediwo/redi-orm/types"
 )
@@ -42,7 +43,7 @@ func (m *MockMigrator) GetTableInfo(tableName string) (*types.TableInfo, error)
 				{Name: "created_at", Type: "TIMESTAMP", Default: "CURRENT_TIMESTAMP"},
 			},
 			ForeignKeys: []types.ForeignKeyInfo{
-				{Name: "fk_posts_users", Column: "user_id", ReferencedTable: "users", ReferencedColumn: "id"},
+				{Name: "fk_posts_users", Column: "user_id", ReferencedTable: "users", ReferencedColumn: "id", OnDelete: "CASCADE", OnUpdate: "NO ACTION"},
 			},
 		}, nil
 	default:
@@ -192,10 +193,25 @@ func TestGenerateSchemasFromTablesWithRelations(t *testing.T) {
 		if userRelation.ForeignKey != "userId" {
 			t.Errorf("Expected Post.user foreign key to be userId, got %s", userRelation.ForeignKey)
 		}
+		if userRelation.OnDelete != schema.ActionCascade {
+			t.Errorf("Expected Post.user onDelete to be CASCADE, got %s", userRelation.OnDelete)
+		}
 	} else {
 		t.Error("Post schema missing 'user' relation")
 	}
 
+	// The referential actions are written unless they are the default NO ACTION
+	prismaSchema, err := NewSchemaGenerator(migrator.MockSpecificMigrator).GeneratePrismaSchema(postSchema)
+	if err != nil {
+		t.Fatalf("Failed to generate Prisma schema: %v", err)
+	}
+	if !strings.Contains(prismaSchema, "onDelete: Cascade") {
+		t.Errorf("Expected Post.user to have onDelete: Cascade, got:\n%s", prismaSchema)
+	}
+	if strings.Contains(prismaSchema, "onUpdate") {
+		t.Errorf("Expected Post.user to have no onUpdate, got:\n%s", prismaSchema)
+	}
+
 	// User should have a "posts" relation (one-to-many)
 	if postsRelation, exists := userSchema.Relations["posts"]; exists {
 		if postsRelation.Type != schema.RelationOneToMany {
@@ -309,7 +325,7 @@ func (m *MockSpecificMigrator) MapDatabaseTypeToFieldType(dbType string) schema.
 		return schema.FieldTypeString
 	case "integer", "int":
 		return schema.FieldTypeInt
-	case "timestamp", "datetime":
+	case "timestamp", "datetime", "date", "time(6)", "timestamp with time zone":
 		return schema.FieldTypeDateTime
 	default:
 		return schema.FieldTypeString
@@ -355,6 +371,40 @@ func TestGenerateSchemaFromTable(t *testing.T) {
 	}
 }
 
+func TestGenerateSchemaFromTableWithDateTimeTypes(t *testing.T) {
+	tableInfo := &types.TableInfo{
+		Name: "events",
+		Columns: []types.ColumnInfo{
+			{Name: "id", Type: "INTEGER", PrimaryKey: true},
+			{Name: "day", Type: "DATE"},
+			{Name: "opens_at", Type: "TIME(6)"},
+			{Name: "starts_at", Type: "timestamp with time zone"},
+			{Name: "created_at", Type: "TIMESTAMP"},
+		},
+	}
+
+	generatedSchema, err := GenerateSchemaFromTable(tableInfo, &MockSpecificMigrator{})
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+
+	expected := map[string]string{
+		"day":       "@db.Date",
+		"opensAt":   "@db.Time",
+		"startsAt":  "@db.Timestamptz",
+		"createdAt": "",
+	}
+	for fieldName, dbType := range expected {
+		field, err := generatedSchema.GetField(fieldName)
+		if err != nil {
+			t.Fatalf("%s field not found: %v", fieldName, err)
+		}
+		if field.Type != schema.FieldTypeDateTime || field.DbType != dbType {
+			t.Errorf("%s: expected DateTime with native type %q, got %s with %q", fieldName, dbType, field.Type, field.DbType)
+		}
+	}
+}
+
 func TestGeneratePrismaSchemaWithNowDefault(t *testing.T) {
 	migrator := &MockSpecificMigrator{}
 	generator := NewSchemaGenerator(migrator)
@@ -399,3 +449,225 @@ func TestGeneratePrismaSchemaWithNowDefault(t *testing.T) {
 		t.Logf("Generated schema:\n%s", prismaOutput)
 	}
 }
+
+func TestGeneratePrismaSchemaWithValidation(t *testing.T) {
+	generator := NewSchemaGenerator(&MockSpecificMigrator{})
+
+	testSchema := schema.New("Account").
+		AddField(schema.NewField("id").Int().PrimaryKey().AutoIncrement().Build()).
+		AddField(schema.NewField("username").String().Length(3, 12).Regex(`^[a-z\d]+$`).Build()).
+		AddField(schema.NewField("email").String().Email().Build()).
+		AddField(schema.NewField("balance").Float().Min(-10.5).Max(1000).Build())
+
+	prismaOutput, err := generator.GeneratePrismaSchema(testSchema)
+	if err != nil {
+		t.Fatalf("Failed to generate Prisma schema: %v", err)
+	}
+
+	for _, expected := range []string{
+		`@length(min: 3, max: 12)`,
+		`@regex("^[a-z\\d]+$")`,
+		`@email`,
+		`@min(-10.5)`,
+		`@max(1000)`,
+	} {
+		if !strings.Contains(prismaOutput, expected) {
+			t.Errorf("Expected %s in generated schema", expected)
+			t.Logf("Generated schema:\n%s", prismaOutput)
+		}
+	}
+}
+
+func TestGeneratePrismaSchemaWithDatasource(t *testing.T) {
+	generator := NewSchemaGenerator(&MockSpecificMigrator{})
+
+	testSchema := schema.New("Event").
+		AddField(schema.NewField("id").Int().PrimaryKey().AutoIncrement().Build())
+	testSchema.Datasource = "analytics"
+
+	prismaOutput, err := generator.GeneratePrismaSchema(testSchema)
+	if err != nil {
+		t.Fatalf("Failed to generate Prisma schema: %v", err)
+	}
+
+	if !strings.Contains(prismaOutput, "@@datasource(analytics)") {
+		t.Errorf("Expected @@datasource(analytics) in generated schema")
+		t.Logf("Generated schema:\n%s", prismaOutput)
+	}
+}
+
+func TestGenerateSchemaFromTableWithConstraints(t *testing.T) {
+	migrator := &MockSpecificMigrator{}
+
+	tableInfo := &types.TableInfo{
+		Name:    "orders",
+		Comment: "Customer orders",
+		Columns: []types.ColumnInfo{
+			{Name: "id", Type: "INTEGER", PrimaryKey: true, AutoIncrement: true},
+			{Name: "reference", Type: "VARCHAR", Comment: "External reference"},
+			{Name: "status", Type: "USER-DEFINED", Default: "PENDING", Enum: "order_status", EnumValues: []string{"PENDING", "SHIPPED"}},
+			{Name: "label", Type: "USER-DEFINED", Enum: "label_kind", EnumValues: []string{"in-progress"}},
+			{Name: "customer_id", Type: "INTEGER"},
+			{Name: "placed_at", Type: "TIMESTAMP"},
+		},
+		Indexes: []types.IndexInfo{
+			{Name: "orders_reference_unique", Columns: []string{"reference"}, Unique: true},
+			{Name: "idx_orders_customer_placed", Columns: []string{"customer_id", "placed_at"}},
+			{Name: "uniq__customerId_reference", Columns: []string{"customer_id", "reference"}, Unique: true},
+		},
+	}
+
+	generatedSchema, err := GenerateSchemaFromTable(tableInfo, migrator)
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+
+	if generatedSchema.Comment != "Customer orders" {
+		t.Errorf("Expected table comment, got %q", generatedSchema.Comment)
+	}
+	reference := generatedSchema.GetFieldByName("reference")
+	if !reference.Unique || reference.Comment != "External reference" {
+		t.Errorf("Expected unique reference field with comment, got %+v", reference)
+	}
+	if status := generatedSchema.GetFieldByName("status"); status.Enum != "OrderStatus" || len(status.EnumValues) != 2 {
+		t.Errorf("Expected status to use the OrderStatus enum, got %+v", status)
+	}
+	if label := generatedSchema.GetFieldByName("label"); label.Enum != "" {
+		t.Errorf("Expected enum with invalid identifiers to stay a string, got %s", label.Enum)
+	}
+	if len(generatedSchema.Indexes) != 2 {
+		t.Fatalf("Expected the single-column unique index to become @unique, got %v", generatedSchema.Indexes)
+	}
+
+	output, err := NewSchemaGenerator(migrator).GenerateFullPrismaFile([]*schema.Schema{generatedSchema}, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate Prisma schema: %v", err)
+	}
+	for _, expected := range []string{
+		"enum OrderStatus {\n  PENDING\n  SHIPPED\n}",
+		"/// Customer orders\nmodel Order {",
+		"  /// External reference\n  reference String @unique\n",
+		"status OrderStatus @default(PENDING)",
+		`@@index([customerId, placedAt], map: "idx_orders_customer_placed")`,
+		"@@unique([customerId, reference])\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in generated schema:\n%s", expected, output)
+		}
+	}
+
+	// The generated schema parses back to the same definitions
+	parsed, err := prisma.ParseSchema(output)
+	if err != nil {
+		t.Fatalf("Failed to parse generated schema: %v", err)
+	}
+	order := parsed["Order"]
+	if order.Comment != generatedSchema.Comment || order.GetFieldByName("status").Enum != "OrderStatus" {
+		t.Errorf("Round trip lost the comment or enum: %+v", order)
+	}
+	if order.Indexes[0].Name != "idx_orders_customer_placed" {
+		t.Errorf("Round trip lost the index name, got %s", order.Indexes[0].Name)
+	}
+}
+
+func TestGenerateFullPrismaFileWithCompositeTypes(t *testing.T) {
+	generator := NewSchemaGenerator(nil)
+
+	geo := &schema.CompositeType{Name: "CustomerAddressGeo", Fields: []schema.Field{
+		{Name: "lat", Type: schema.FieldTypeFloat},
+		{Name: "lng", Type: schema.FieldTypeFloat},
+	}}
+	address := &schema.CompositeType{Name: "CustomerAddress", Fields: []schema.Field{
+		{Name: "street", Type: schema.FieldTypeString},
+		{Name: "kind", Type: schema.FieldTypeString, Enum: "AddressKind", EnumValues: []string{"HOME", "WORK"}},
+		{Name: "geo", Type: schema.FieldTypeDocument, Composite: geo, Nullable: true},
+	}}
+	customer := schema.New("Customer").WithTableName("customers").
+		AddField(schema.Field{Name: "id", Type: schema.FieldTypeObjectId, PrimaryKey: true, DbType: "@db.ObjectId"}).
+		AddField(schema.Field{Name: "address", Type: schema.FieldTypeDocument, Composite: address}).
+		AddField(schema.Field{Name: "previousAddresses", Type: schema.FieldTypeArray, Composite: address, Map: "previousAddresses"})
+
+	content, err := generator.GenerateFullPrismaFile([]*schema.Schema{customer}, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate Prisma file: %v", err)
+	}
+
+	for _, expected := range []string{
+		"enum AddressKind {",
+		"type CustomerAddress {\n  street String\n  kind AddressKind\n  geo CustomerAddressGeo?\n}",
+		"type CustomerAddressGeo {",
+		"id String @id @db.ObjectId",
+		"address CustomerAddress\n",
+		`previousAddresses CustomerAddress[] @map("previousAddresses")`,
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("Expected %q in generated file:\n%s", expected, content)
+		}
+	}
+	if strings.Count(content, "type CustomerAddress {") != 1 {
+		t.Errorf("Expected shared types to be declared once:\n%s", content)
+	}
+
+	// The generated file parses back to the same structure
+	schemas, err := prisma.ParseSchema(content)
+	if err != nil {
+		t.Fatalf("Failed to parse generated file: %v", err)
+	}
+	parsed := schemas["Customer"].GetFieldByName("previousAddresses")
+	if parsed == nil || parsed.Type != schema.FieldTypeArray || parsed.Composite.Fields[2].Composite.Name != "CustomerAddressGeo" {
+		t.Errorf("Expected the composite list to round trip, got %+v", parsed)
+	}
+}
+
+// selfRelationMigrator reports a users table whose manager_id references itself
+type selfRelationMigrator struct {
+	*MockMigratorWrapper
+}
+
+func (m *selfRelationMigrator) GetTables() ([]string, error) {
+	return []string{"users"}, nil
+}
+
+func (m *selfRelationMigrator) GetTableInfo(tableName string) (*types.TableInfo, error) {
+	return &types.TableInfo{
+		Name: "users",
+		Columns: []types.ColumnInfo{
+			{Name: "id", Type: "INTEGER", PrimaryKey: true, AutoIncrement: true},
+			{Name: "manager_id", Type: "INTEGER", Nullable: true},
+		},
+		ForeignKeys: []types.ForeignKeyInfo{
+			{Name: "fk_users_manager", Column: "manager_id", ReferencedTable: "users", ReferencedColumn: "id"},
+		},
+	}, nil
+}
+
+func TestGenerateSchemasWithSelfRelation(t *testing.T) {
+	schemas, err := GenerateSchemasFromTablesWithRelations(&selfRelationMigrator{
+		MockMigratorWrapper: &MockMigratorWrapper{MockSpecificMigrator: &MockSpecificMigrator{}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to generate schemas: %v", err)
+	}
+	if len(schemas) != 1 {
+		t.Fatalf("Expected 1 schema, got %d", len(schemas))
+	}
+	userSchema := schemas[0]
+
+	// Both sides of the self-relation share its name
+	manager := userSchema.Relations["manager"]
+	if manager.Type != schema.RelationManyToOne || manager.Name != "UserManager" || manager.ForeignKey != "managerId" {
+		t.Errorf("Expected User.manager to be the many-to-one UserManager relation, got %+v", manager)
+	}
+	reports, exists := userSchema.Relations["managerUsers"]
+	if !exists || reports.Type != schema.RelationOneToMany || reports.Name != "UserManager" {
+		t.Errorf("Expected User.managerUsers to be the inverse of User.manager, got %+v", userSchema.Relations)
+	}
+
+	prismaSchema, err := NewSchemaGenerator(&MockSpecificMigrator{}).GeneratePrismaSchema(userSchema)
+	if err != nil {
+		t.Fatalf("Failed to generate Prisma schema: %v", err)
+	}
+	if !strings.Contains(prismaSchema, `@relation("UserManager", fields: [managerId], references: [id])`) {
+		t.Errorf("Expected the relation name in the Prisma schema, got:\n%s", prismaSchema)
+	}
+}
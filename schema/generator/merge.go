@@ -0,0 +1,152 @@
+package generator
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/rediwo/redi-orm/schema"
+)
+
+// RemovedColumnNote is added to the documentation comment of fields whose column no
+// longer exists in the database
+const RemovedColumnNote = "Column not found in the database"
+
+// MergeResult describes the database changes merged into an existing schema
+type MergeResult struct {
+	AddedFields    []string // Fields added for new columns
+	RemovedFields  []string // Fields whose column no longer exists; they are flagged, not removed
+	AddedIndexes   []schema.Index
+	AddedRelations []string
+
+	changed bool
+}
+
+// HasChanges reports whether the merged schema differs from the existing one
+func (r *MergeResult) HasChanges() bool {
+	return r.changed
+}
+
+// MergeSchema merges a schema pulled from the database into an existing schema of the
+// same table. The existing definitions win: new columns are appended as fields, new
+// indexes and foreign key relations are added, and fields whose column no longer exists
+// are flagged with RemovedColumnNote in their documentation comment. The existing schema
+// is not modified.
+func MergeSchema(existing, pulled *schema.Schema) (*schema.Schema, *MergeResult) {
+	merged := *existing
+	merged.Fields = slices.Clone(existing.Fields)
+	merged.Indexes = slices.Clone(existing.Indexes)
+	merged.Relations = maps.Clone(existing.Relations)
+	if merged.Relations == nil {
+		merged.Relations = make(map[string]schema.Relation)
+	}
+	result := &MergeResult{}
+
+	pulledColumns := make(map[string]bool, len(pulled.Fields))
+	for _, field := range pulled.Fields {
+		pulledColumns[field.GetColumnName()] = true
+	}
+
+	// Flag fields without a column, and unflag fields whose column is back
+	existingColumns := make(map[string]bool, len(merged.Fields))
+	for i := range merged.Fields {
+		field := &merged.Fields[i]
+		existingColumns[field.GetColumnName()] = true
+
+		flagged := hasNote(field.Comment, RemovedColumnNote)
+		if !pulledColumns[field.GetColumnName()] {
+			result.RemovedFields = append(result.RemovedFields, field.Name)
+			if !flagged {
+				field.Comment = addNote(field.Comment, RemovedColumnNote)
+				result.changed = true
+			}
+		} else if flagged {
+			field.Comment = removeNote(field.Comment, RemovedColumnNote)
+			result.changed = true
+		}
+	}
+
+	// Append new columns
+	for _, field := range pulled.Fields {
+		if existingColumns[field.GetColumnName()] || merged.GetFieldByName(field.Name) != nil {
+			continue
+		}
+		merged.Fields = append(merged.Fields, field)
+		result.AddedFields = append(result.AddedFields, field.Name)
+		result.changed = true
+	}
+
+	// Add indexes over columns that are not indexed the same way yet
+	for _, index := range pulled.Indexes {
+		if hasIndex(merged.Indexes, index) || !hasFields(&merged, index.Fields) {
+			continue
+		}
+		merged.Indexes = append(merged.Indexes, index)
+		result.AddedIndexes = append(result.AddedIndexes, index)
+		result.changed = true
+	}
+
+	// Add relations inferred from foreign keys the schema does not declare yet
+	relationNames := slices.Sorted(maps.Keys(pulled.Relations))
+	for _, name := range relationNames {
+		relation := pulled.Relations[name]
+		if _, taken := merged.Relations[name]; taken || hasRelation(merged.Relations, relation) {
+			continue
+		}
+		if relation.Type == schema.RelationManyToOne && merged.GetFieldByName(relation.ForeignKey) == nil {
+			continue
+		}
+		merged.Relations[name] = relation
+		result.AddedRelations = append(result.AddedRelations, name)
+		result.changed = true
+	}
+
+	return &merged, result
+}
+
+// hasIndex reports whether an index over the same fields and with the same uniqueness exists
+func hasIndex(indexes []schema.Index, index schema.Index) bool {
+	for _, existing := range indexes {
+		if existing.Unique == index.Unique && slices.Equal(existing.Fields, index.Fields) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasFields(s *schema.Schema, fieldNames []string) bool {
+	for _, name := range fieldNames {
+		if s.GetFieldByName(name) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// hasRelation reports whether a relation to the same model over the same foreign key exists
+func hasRelation(relations map[string]schema.Relation, relation schema.Relation) bool {
+	for _, existing := range relations {
+		if existing.Model == relation.Model && existing.ForeignKey == relation.ForeignKey {
+			return true
+		}
+	}
+	return false
+}
+
+func hasNote(comment, note string) bool {
+	return slices.Contains(strings.Split(comment, "\n"), note)
+}
+
+func addNote(comment, note string) string {
+	if comment == "" {
+		return note
+	}
+	return comment + "\n" + note
+}
+
+func removeNote(comment, note string) string {
+	lines := slices.DeleteFunc(strings.Split(comment, "\n"), func(line string) bool {
+		return line == note
+	})
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,124 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rediwo/redi-orm/logger"
+	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/types"
+)
+
+func TestMergeSchema(t *testing.T) {
+	existing := schema.New("Account").WithTableName("users").
+		AddField(schema.NewField("id").Int().PrimaryKey().AutoIncrement().Build()).
+		AddField(schema.NewField("login").String().Map("username").Length(3, 20).Build()).
+		AddField(schema.NewField("nickname").String().Nullable().Build())
+
+	pulled, err := GenerateSchemaFromTable(&types.TableInfo{
+		Name: "users",
+		Columns: []types.ColumnInfo{
+			{Name: "id", Type: "INTEGER", PrimaryKey: true, AutoIncrement: true},
+			{Name: "username", Type: "VARCHAR"},
+			{Name: "email", Type: "VARCHAR"},
+			{Name: "team_id", Type: "INTEGER", Nullable: true},
+		},
+		Indexes: []types.IndexInfo{
+			{Name: "idx_users_email", Columns: []string{"email"}},
+		},
+	}, &MockSpecificMigrator{})
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+	pulled.AddRelation("team", schema.Relation{Type: schema.RelationManyToOne, Model: "Team", ForeignKey: "teamId", References: "id"})
+
+	merged, result := MergeSchema(existing, pulled)
+	if !result.HasChanges() {
+		t.Fatal("Expected changes")
+	}
+
+	// Existing definitions win
+	if login := merged.GetFieldByName("login"); login == nil || login.Validation == nil || login.Comment != "" {
+		t.Errorf("Expected login to be kept unchanged, got %+v", login)
+	}
+	if merged.GetFieldByName("username") != nil {
+		t.Error("Expected the mapped username column not to be added again")
+	}
+
+	if strings.Join(result.AddedFields, ",") != "email,teamId" {
+		t.Errorf("Expected email and teamId to be added, got %v", result.AddedFields)
+	}
+	if len(merged.Fields) != 5 || merged.Fields[3].Name != "email" {
+		t.Errorf("Expected new fields to be appended, got %v", merged.Fields)
+	}
+	if len(result.AddedIndexes) != 1 || len(result.AddedRelations) != 1 {
+		t.Errorf("Expected the email index and team relation to be added, got %v and %v", result.AddedIndexes, result.AddedRelations)
+	}
+
+	if strings.Join(result.RemovedFields, ",") != "nickname" {
+		t.Errorf("Expected nickname to be reported as removed, got %v", result.RemovedFields)
+	}
+	if nickname := merged.GetFieldByName("nickname"); nickname == nil || nickname.Comment != RemovedColumnNote {
+		t.Errorf("Expected nickname to be flagged, got %+v", nickname)
+	}
+	if existing.GetFieldByName("nickname").Comment != "" || len(existing.Fields) != 3 {
+		t.Error("MergeSchema must not modify the existing schema")
+	}
+
+	// Merging again changes nothing; the flag is removed once the column is back
+	if _, again := MergeSchema(merged, pulled); again.HasChanges() {
+		t.Errorf("Expected a second merge to be a no-op, got %+v", again)
+	}
+	pulled.AddField(schema.NewField("nickname").String().Nullable().Build())
+	restored, result := MergeSchema(merged, pulled)
+	if !result.HasChanges() || restored.GetFieldByName("nickname").Comment != "" {
+		t.Errorf("Expected the flag to be removed, got %q", restored.GetFieldByName("nickname").Comment)
+	}
+}
+
+func TestMergeSchemaPersistence(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "schema.prisma")
+	content := `model Account {
+  id       Int     @id @default(autoincrement())
+  /// Shown to other users
+  nickname String?
+  @@map("users")
+}
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	persistence := NewSchemaPersistence(path, logger.NewDefaultLogger("Test"), nil)
+	schemas, err := persistence.LoadSchemas()
+	if err != nil {
+		t.Fatalf("Failed to load schemas: %v", err)
+	}
+
+	pulled := schema.New("User").WithTableName("users").
+		AddField(schema.NewField("id").Int().PrimaryKey().AutoIncrement().Build()).
+		AddField(schema.NewField("email").String().Build())
+
+	merged, _ := MergeSchema(schemas[0], pulled)
+	if err := persistence.SaveSchema(merged); err != nil {
+		t.Fatalf("Failed to save schema: %v", err)
+	}
+
+	saved, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"model Account {",
+		"  /// Shown to other users\n  /// " + RemovedColumnNote + "\n  nickname String?\n",
+		"  email String\n",
+		`@@map("users")`,
+	} {
+		if !strings.Contains(string(saved), expected) {
+			t.Errorf("Expected %q in saved schema:\n%s", expected, saved)
+		}
+	}
+}
@@ -117,6 +117,51 @@ func IsArrayFieldType(fieldType FieldType) bool {
 	}
 }
 
+// ArrayElementType returns the element type of an array field type, or "" for other types
+func ArrayElementType(fieldType FieldType) FieldType {
+	switch fieldType {
+	case FieldTypeStringArray:
+		return FieldTypeString
+	case FieldTypeIntArray:
+		return FieldTypeInt
+	case FieldTypeInt64Array:
+		return FieldTypeInt64
+	case FieldTypeFloatArray:
+		return FieldTypeFloat
+	case FieldTypeBoolArray:
+		return FieldTypeBool
+	case FieldTypeDecimalArray:
+		return FieldTypeDecimal
+	case FieldTypeDateTimeArray:
+		return FieldTypeDateTime
+	default:
+		return ""
+	}
+}
+
+// ArrayFieldType returns the array field type holding elements of the given type, or ""
+// when there is no list type for it
+func ArrayFieldType(elementType FieldType) FieldType {
+	switch elementType {
+	case FieldTypeString:
+		return FieldTypeStringArray
+	case FieldTypeInt:
+		return FieldTypeIntArray
+	case FieldTypeInt64:
+		return FieldTypeInt64Array
+	case FieldTypeFloat:
+		return FieldTypeFloatArray
+	case FieldTypeBool:
+		return FieldTypeBoolArray
+	case FieldTypeDecimal:
+		return FieldTypeDecimalArray
+	case FieldTypeDateTime:
+		return FieldTypeDateTimeArray
+	default:
+		return ""
+	}
+}
+
 // BuildJoinCondition builds the SQL join condition for a relation
 func BuildJoinCondition(relation *Relation, currentTable, relatedTable string, currentSchema, relatedSchema *Schema) (string, error) {
 	switch relation.Type {
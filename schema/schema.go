@@ -3,6 +3,8 @@ package schema
 import (
 	"fmt"
 	"reflect"
+	"slices"
+	"strings"
 
 	"github.com/rediwo/redi-orm/utils"
 )
@@ -18,6 +20,7 @@ const (
 	FieldTypeDateTime FieldType = "datetime"
 	FieldTypeJSON     FieldType = "json"
 	FieldTypeDecimal  FieldType = "decimal"
+	FieldTypeBytes    FieldType = "bytes" // Binary data, written as []byte or base64 text
 
 	// Array types
 	FieldTypeStringArray   FieldType = "string[]"
@@ -30,7 +33,7 @@ const (
 
 	// MongoDB specific types
 	FieldTypeObjectId   FieldType = "objectid"
-	FieldTypeBinary     FieldType = "binary"
+	FieldTypeBinary     FieldType = FieldTypeBytes // Deprecated: use FieldTypeBytes
 	FieldTypeDecimal128 FieldType = "decimal128"
 	FieldTypeTimestamp  FieldType = "timestamp"
 	FieldTypeDocument   FieldType = "document" // Embedded document
@@ -46,9 +49,15 @@ type Field struct {
 	Unique        bool
 	Default       any
 	Index         bool
-	DbType        string   // Database-specific type (e.g., "@db.VarChar(255)", "@db.Money")
-	DbAttributes  []string // Additional database attributes
-	Map           string   // Column name mapping (@map("column_name"))
+	DbType        string         // Database-specific type (e.g., "@db.VarChar(255)", "@db.Money")
+	DbAttributes  []string       // Additional database attributes
+	Map           string         // Column name mapping (@map("column_name"))
+	Enum          string         // Enum type backing the field, if any (values are stored as strings)
+	EnumValues    []string       // Values of the enum type
+	UpdatedAt     bool           // Set to the current time on every create and update (@updatedAt)
+	Validation    *Validation    // Value rules checked before writes (@length, @regex, @min, @max, @email)
+	Comment       string         // Documentation comment (/// in Prisma schemas)
+	Composite     *CompositeType // Structure of embedded documents (Prisma type blocks), if any
 }
 
 // GetColumnName returns the actual database column name for this field
@@ -60,6 +69,40 @@ func (f Field) GetColumnName() string {
 	return utils.ToSnakeCase(f.Name)
 }
 
+// NativeType returns the name of the database native type of the field, without the @db.
+// prefix and arguments ("Date" for @db.Date, "VarChar" for @db.VarChar(255)), or "" when
+// the field has none
+func (f Field) NativeType() string {
+	name, ok := strings.CutPrefix(f.DbType, "@db.")
+	if !ok {
+		return ""
+	}
+	name, _, _ = strings.Cut(name, "(")
+	return name
+}
+
+// GeneratesUUID reports whether the field defaults to UUIDs generated by the ORM on create,
+// as with @default(uuid())
+func (f Field) GeneratesUUID() bool {
+	v, ok := f.Default.(string)
+	return ok && strings.EqualFold(v, "UUID()")
+}
+
+// HoldsUUID reports whether the field holds UUIDs, generated ones or ones of the native type
+// @db.Uuid, which drivers store in a column of their UUID type
+func (f Field) HoldsUUID() bool {
+	return f.Type == FieldTypeString && (f.GeneratesUUID() || f.NativeType() == "Uuid")
+}
+
+// ColumnDefault returns the default of the column of the field: its default, unless the ORM
+// generates its values
+func (f Field) ColumnDefault() any {
+	if f.GeneratesUUID() {
+		return nil
+	}
+	return f.Default
+}
+
 type Relation struct {
 	Type       RelationType
 	Model      string
@@ -67,8 +110,60 @@ type Relation struct {
 	References string
 	OnDelete   string
 	OnUpdate   string
+
+	// Name tells apart relations between the same models, as in @relation("name")
+	Name string
+
+	// TypeField names the field holding the model a polymorphic relation references. On
+	// the polymorphic side, Models lists the models it may reference and Model is empty;
+	// on their inverse relations, TypeField holds the name of their own model.
+	TypeField string
+	Models    []string
+}
+
+// IsPolymorphic reports whether the relation references one of several models, selected
+// by the value of its type field
+func (r Relation) IsPolymorphic() bool {
+	return r.TypeField != ""
 }
 
+// RelatedModel returns the model of the records the relation of record holds, which for
+// the polymorphic side is the model named by the type field of record
+func (r Relation) RelatedModel(record map[string]any) string {
+	if len(r.Models) == 0 {
+		return r.Model
+	}
+	model, _ := record[r.TypeField].(string)
+	return model
+}
+
+// JoinFields returns the fields joining a relation of model s other than many-to-many: the
+// field of s and the field of the related model holding the same value. The foreign key is
+// on s for many-to-one relations and for the owning side of one-to-one relations, and on
+// the related model otherwise.
+func (r Relation) JoinFields(s *Schema) (localField, relatedField string) {
+	references := r.References
+	if references == "" {
+		references = "id"
+	}
+	if r.Type == RelationManyToOne {
+		return r.ForeignKey, references
+	}
+	if _, err := s.GetField(r.ForeignKey); err == nil {
+		return r.ForeignKey, references
+	}
+	return references, r.ForeignKey
+}
+
+// Referential actions of foreign keys, as stored in Relation.OnDelete and OnUpdate
+const (
+	ActionCascade    = "CASCADE"
+	ActionSetNull    = "SET NULL"
+	ActionSetDefault = "SET DEFAULT"
+	ActionRestrict   = "RESTRICT"
+	ActionNoAction   = "NO ACTION"
+)
+
 type RelationType string
 
 const (
@@ -85,8 +180,17 @@ type Schema struct {
 	Relations    map[string]Relation
 	Indexes      []Index
 	CompositeKey []string // Fields that form the composite primary key
+	Comment      string   // Documentation comment (/// in Prisma schemas)
+	Datasource   string   // Named datasource the model is stored in (@@datasource), empty for the default
+	RelationMode string   // relationMode of its datasource: RelationModeForeignKeys or RelationModePrisma
 }
 
+// Relation modes, setting how the references of relations are kept intact
+const (
+	RelationModeForeignKeys = "foreignKeys" // By foreign keys of the database, the default
+	RelationModePrisma      = "prisma"      // By the ORM, for databases without foreign keys
+)
+
 type Index struct {
 	Name   string
 	Fields []string
@@ -124,6 +228,12 @@ func (s *Schema) AddRelation(name string, relation Relation) *Schema {
 	return s
 }
 
+// EmulatesForeignKeys reports whether the ORM keeps the relations of the model intact
+// instead of foreign keys, as relationMode = "prisma" sets
+func (s *Schema) EmulatesForeignKeys() bool {
+	return s.RelationMode == RelationModePrisma
+}
+
 func (s *Schema) AddIndex(index Index) *Schema {
 	s.Indexes = append(s.Indexes, index)
 	return s
@@ -162,6 +272,59 @@ func (s *Schema) GetPrimaryKey() (*Field, error) {
 	return nil, fmt.Errorf("no primary key found")
 }
 
+// GetUpdatedAtFields returns the names of the fields marked with @updatedAt
+func (s *Schema) GetUpdatedAtFields() []string {
+	var fields []string
+	for _, field := range s.Fields {
+		if field.UpdatedAt {
+			fields = append(fields, field.Name)
+		}
+	}
+	return fields
+}
+
+// GetGeneratedUUIDFields returns the names of the fields defaulting to generated UUIDs
+func (s *Schema) GetGeneratedUUIDFields() []string {
+	var fields []string
+	for _, field := range s.Fields {
+		if field.GeneratesUUID() {
+			fields = append(fields, field.Name)
+		}
+	}
+	return fields
+}
+
+// IsUniqueKey reports whether the fields identify at most one record: the primary key,
+// a unique field or the fields of a unique index, in any order
+func (s *Schema) IsUniqueKey(fieldNames []string) bool {
+	sameFields := func(fields []string) bool {
+		if len(fields) != len(fieldNames) {
+			return false
+		}
+		for _, name := range fieldNames {
+			if !slices.Contains(fields, name) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if len(fieldNames) == 1 {
+		if field := s.GetFieldByName(fieldNames[0]); field != nil && (field.PrimaryKey || field.Unique) {
+			return true
+		}
+	}
+	if len(s.CompositeKey) > 0 && sameFields(s.CompositeKey) {
+		return true
+	}
+	for _, index := range s.Indexes {
+		if index.Unique && sameFields(index.Fields) {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Schema) Validate() error {
 	if s.Name == "" {
 		return fmt.Errorf("schema name cannot be empty")
@@ -331,13 +494,23 @@ func (s *Schema) GetRelationsToModel(modelName string) []Relation {
 // ValidateRelations validates all relations in the schema
 func (s *Schema) ValidateRelations(schemas map[string]*Schema) error {
 	for name, relation := range s.Relations {
-		relatedSchema, exists := schemas[relation.Model]
-		if !exists {
-			return fmt.Errorf("relation %s references unknown model %s", name, relation.Model)
+		models := []string{relation.Model}
+		if len(relation.Models) > 0 {
+			if _, err := s.GetField(relation.TypeField); err != nil {
+				return fmt.Errorf("relation %s: type field %s not found in model %s", name, relation.TypeField, s.Name)
+			}
+			models = relation.Models
 		}
 
-		if err := ValidateRelation(&relation, s, relatedSchema); err != nil {
-			return fmt.Errorf("invalid relation %s: %w", name, err)
+		for _, model := range models {
+			relatedSchema, exists := schemas[model]
+			if !exists {
+				return fmt.Errorf("relation %s references unknown model %s", name, model)
+			}
+
+			if err := ValidateRelation(&relation, s, relatedSchema); err != nil {
+				return fmt.Errorf("invalid relation %s: %w", name, err)
+			}
 		}
 	}
 	return nil
@@ -63,6 +63,34 @@ func TestField_GetColumnName(t *testing.T) {
 	}
 }
 
+func TestField_NativeType(t *testing.T) {
+	tests := map[string]string{
+		"@db.Date":         "Date",
+		"@db.Timestamptz":  "Timestamptz",
+		"@db.VarChar(255)": "VarChar",
+		"":                 "",
+		"VARCHAR(255)":     "",
+	}
+	for dbType, expected := range tests {
+		assert.Equal(t, expected, Field{Name: "value", DbType: dbType}.NativeType(), dbType)
+	}
+}
+
+func TestField_UUID(t *testing.T) {
+	generated := Field{Name: "id", Type: FieldTypeString, Default: "UUID()"}
+	assert.True(t, generated.GeneratesUUID())
+	assert.True(t, generated.HoldsUUID())
+	assert.Nil(t, generated.ColumnDefault())
+
+	native := Field{Name: "accountId", Type: FieldTypeString, DbType: "@db.Uuid"}
+	assert.False(t, native.GeneratesUUID())
+	assert.True(t, native.HoldsUUID())
+
+	text := Field{Name: "name", Type: FieldTypeString, Default: "UUID"}
+	assert.False(t, text.HoldsUUID())
+	assert.Equal(t, "UUID", text.ColumnDefault())
+}
+
 // Test Schema creation and basic operations
 func TestSchema_New(t *testing.T) {
 	schema := New("User")
@@ -170,6 +198,29 @@ func TestSchema_GetPrimaryKey(t *testing.T) {
 	assert.Contains(t, err.Error(), "no primary key found")
 }
 
+func TestSchema_IsUniqueKey(t *testing.T) {
+	schema := New("Membership").
+		AddField(Field{Name: "id", Type: FieldTypeInt64, PrimaryKey: true}).
+		AddField(Field{Name: "email", Type: FieldTypeString, Unique: true}).
+		AddField(Field{Name: "orgId", Type: FieldTypeInt64}).
+		AddField(Field{Name: "userId", Type: FieldTypeInt64}).
+		AddField(Field{Name: "role", Type: FieldTypeString}).
+		AddIndex(Index{Name: "org_user", Fields: []string{"orgId", "userId"}, Unique: true})
+
+	assert.True(t, schema.IsUniqueKey([]string{"id"}))
+	assert.True(t, schema.IsUniqueKey([]string{"email"}))
+	assert.True(t, schema.IsUniqueKey([]string{"userId", "orgId"}))
+	assert.False(t, schema.IsUniqueKey([]string{"role"}))
+	assert.False(t, schema.IsUniqueKey([]string{"orgId"}))
+	assert.False(t, schema.IsUniqueKey([]string{"orgId", "userId", "role"}))
+
+	composite := New("Tag").
+		AddField(Field{Name: "postId", Type: FieldTypeInt64}).
+		AddField(Field{Name: "name", Type: FieldTypeString}).
+		WithCompositeKey([]string{"postId", "name"})
+	assert.True(t, composite.IsUniqueKey([]string{"name", "postId"}))
+}
+
 // Test Schema validation
 func TestSchema_Validate(t *testing.T) {
 	t.Run("valid schema with single primary key", func(t *testing.T) {
@@ -573,3 +624,64 @@ func BenchmarkSchemaMapFieldNamesToColumns(b *testing.B) {
 		_, _ = schema.MapFieldNamesToColumns(fieldNames)
 	}
 }
+
+func TestSchema_ValidateData(t *testing.T) {
+	s := New("Account").
+		AddField(NewField("username").String().Length(3, 12).Regex("^[a-z]+$").Build()).
+		AddField(NewField("email").String().Email().Build()).
+		AddField(NewField("age").Int().Min(0).Max(150).Nullable().Build())
+
+	assert.NoError(t, s.ValidateData(map[string]any{"username": "alice", "email": "alice@example.com", "age": 30}))
+	assert.NoError(t, s.ValidateData(map[string]any{"age": nil}), "nil values are not validated")
+	assert.NoError(t, s.ValidateData(map[string]any{"other": "ignored"}))
+
+	err := s.ValidateData(map[string]any{"username": "Al", "email": "alice", "age": int64(200)})
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "Account", validationErr.Model)
+
+	var rules []string
+	for _, fieldErr := range validationErr.Errors {
+		rules = append(rules, fieldErr.Field+":"+fieldErr.Rule)
+	}
+	assert.ElementsMatch(t, []string{"username:length", "username:regex", "email:email", "age:max"}, rules)
+}
+
+func TestSchema_CompositePaths(t *testing.T) {
+	geo := &CompositeType{Name: "Geo", Fields: []Field{
+		{Name: "lat", Type: FieldTypeFloat, Map: "latitude"},
+	}}
+	address := &CompositeType{Name: "Address", Fields: []Field{
+		{Name: "zipCode", Type: FieldTypeString},
+		{Name: "geo", Type: FieldTypeDocument, Composite: geo},
+	}}
+	s := New("Customer").
+		AddField(Field{Name: "name", Type: FieldTypeString}).
+		AddField(Field{Name: "homeAddress", Type: FieldTypeDocument, Composite: address}).
+		AddField(Field{Name: "pastAddresses", Type: FieldTypeArray, Composite: address})
+
+	column, err := s.GetColumnPathByFieldPath("homeAddress.geo.lat")
+	require.NoError(t, err)
+	assert.Equal(t, "home_address.geo.latitude", column)
+
+	column, err = s.GetColumnPathByFieldPath("pastAddresses.0.zipCode")
+	require.NoError(t, err)
+	assert.Equal(t, "past_addresses.0.zip_code", column)
+
+	column, err = s.GetColumnPathByFieldPath("name")
+	require.NoError(t, err)
+	assert.Equal(t, "name", column)
+
+	fieldPath, err := s.GetFieldPathByColumnPath("home_address.geo.latitude")
+	require.NoError(t, err)
+	assert.Equal(t, "homeAddress.geo.lat", fieldPath)
+
+	field, err := s.GetFieldByPath("pastAddresses.geo")
+	require.NoError(t, err)
+	assert.Same(t, geo, field.Composite)
+
+	_, err = s.GetColumnPathByFieldPath("name.first")
+	assert.Error(t, err, "plain fields have no nested paths")
+	_, err = s.GetFieldByPath("homeAddress.street")
+	assert.Error(t, err)
+}
@@ -0,0 +1,165 @@
+package schema
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Validation holds the value rules of a field (@length, @regex, @min, @max, @email).
+// Rules are checked before writes reach the database; nil values are not validated.
+type Validation struct {
+	MinLength *int           // Minimum length of strings and lists
+	MaxLength *int           // Maximum length of strings and lists
+	Pattern   *regexp.Regexp // Strings must match the pattern
+	Min       *float64       // Minimum numeric value
+	Max       *float64       // Maximum numeric value
+	Email     bool           // Strings must be email addresses
+}
+
+// FieldError describes a field value that breaks a validation rule
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned when data breaks the validation rules of a model
+type ValidationError struct {
+	Model  string       `json:"model"`
+	Errors []FieldError `json:"errors"`
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, fieldErr := range e.Errors {
+		messages[i] = fieldErr.Field + " " + fieldErr.Message
+	}
+	return fmt.Sprintf("validation failed for %s: %s", e.Model, strings.Join(messages, "; "))
+}
+
+// Extensions returns the structured details of the error, e.g. for GraphQL error extensions
+func (e *ValidationError) Extensions() map[string]any {
+	return map[string]any{
+		"code":   "VALIDATION_ERROR",
+		"model":  e.Model,
+		"errors": e.Errors,
+	}
+}
+
+// ValidateData checks the fields present in data against their validation rules.
+// Keys that are not fields of the schema are ignored.
+func (s *Schema) ValidateData(data map[string]any) error {
+	var errs []FieldError
+	for _, field := range s.Fields {
+		if field.Validation == nil {
+			continue
+		}
+		value, ok := data[field.Name]
+		if !ok {
+			continue
+		}
+		for _, fieldErr := range field.Validation.Check(value) {
+			fieldErr.Field = field.Name
+			errs = append(errs, fieldErr)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Model: s.Name, Errors: errs}
+}
+
+// Check returns the rules a value breaks. The Field of the returned errors is left empty.
+func (v *Validation) Check(value any) []FieldError {
+	if value == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	var errs []FieldError
+	if v.MinLength != nil || v.MaxLength != nil {
+		if length, ok := valueLength(rv); ok {
+			if v.MinLength != nil && length < *v.MinLength {
+				errs = append(errs, FieldError{Rule: "length", Message: fmt.Sprintf("must be at least %d characters long", *v.MinLength)})
+			}
+			if v.MaxLength != nil && length > *v.MaxLength {
+				errs = append(errs, FieldError{Rule: "length", Message: fmt.Sprintf("must be at most %d characters long", *v.MaxLength)})
+			}
+		}
+	}
+
+	if rv.Kind() == reflect.String {
+		str := rv.String()
+		if v.Pattern != nil && !v.Pattern.MatchString(str) {
+			errs = append(errs, FieldError{Rule: "regex", Message: fmt.Sprintf("must match %s", v.Pattern.String())})
+		}
+		if v.Email && !isEmail(str) {
+			errs = append(errs, FieldError{Rule: "email", Message: "must be a valid email address"})
+		}
+	}
+
+	if v.Min != nil || v.Max != nil {
+		if number, ok := numericValue(rv); ok {
+			if v.Min != nil && number < *v.Min {
+				errs = append(errs, FieldError{Rule: "min", Message: fmt.Sprintf("must be at least %s", formatNumber(*v.Min))})
+			}
+			if v.Max != nil && number > *v.Max {
+				errs = append(errs, FieldError{Rule: "max", Message: fmt.Sprintf("must be at most %s", formatNumber(*v.Max))})
+			}
+		}
+	}
+
+	return errs
+}
+
+// valueLength returns the length of strings (in characters) and lists
+func valueLength(rv reflect.Value) (int, bool) {
+	switch rv.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(rv.String()), true
+	case reflect.Slice, reflect.Array:
+		return rv.Len(), true
+	default:
+		return 0, false
+	}
+}
+
+// numericValue returns numbers, and strings holding numbers (e.g. decimals), as float64
+func numericValue(rv reflect.Value) (float64, bool) {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	case reflect.String:
+		number, err := strconv.ParseFloat(rv.String(), 64)
+		return number, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// isEmail reports whether s is a bare email address (no display name)
+func isEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s && strings.Contains(s[strings.LastIndex(s, "@"):], ".")
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
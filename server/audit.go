@@ -0,0 +1,430 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/rediwo/redi-orm/logger"
+	"github.com/rediwo/redi-orm/masking"
+	"github.com/rediwo/redi-orm/ratelimit"
+	"github.com/rediwo/redi-orm/schema"
+	"github.com/rediwo/redi-orm/utils"
+)
+
+// CorrelationHeader carries the correlation ID of a request. An ID sent by the client is
+// kept, otherwise one is generated; the response carries it back.
+const CorrelationHeader = "X-Correlation-ID"
+
+// maxAuditBody is the size of the responses whose rows are counted
+const maxAuditBody = 8 << 20
+
+// AuditEntry describes an operation of the GraphQL or REST API
+type AuditEntry struct {
+	CorrelationID string
+	API           string // "graphql" or "rest"
+	Type          string // "query" or "mutation"
+	Operation     string // Such as "findMany", "create" or "deleteMany"
+	Model         string
+	Rows          int // Records returned or changed, -1 when unknown
+	Status        int // HTTP status of the response
+	Caller        string
+	Roles         []string
+	Duration      time.Duration
+	Error         string
+}
+
+// String formats the entry as key=value pairs on one line
+func (e AuditEntry) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "correlation=%s api=%s type=%s operation=%s", e.CorrelationID, e.API, e.Type, e.Operation)
+	if e.Model != "" {
+		fmt.Fprintf(&b, " model=%s", e.Model)
+	}
+	if e.Rows >= 0 {
+		fmt.Fprintf(&b, " rows=%d", e.Rows)
+	} else {
+		b.WriteString(" rows=?")
+	}
+	fmt.Fprintf(&b, " status=%d caller=%s", e.Status, e.Caller)
+	if len(e.Roles) > 0 {
+		fmt.Fprintf(&b, " roles=%s", strings.Join(e.Roles, ","))
+	}
+	fmt.Fprintf(&b, " duration=%v", e.Duration.Round(time.Microsecond))
+	if e.Error != "" {
+		fmt.Fprintf(&b, " error=%q", e.Error)
+	}
+	return b.String()
+}
+
+// auditor logs the operations of the API requests
+type auditor struct {
+	logger     logger.Logger
+	schemas    map[string]*schema.Schema
+	trustProxy bool
+}
+
+// auditOperation is an operation of a request; key is the field of a GraphQL response
+// holding its result
+type auditOperation struct {
+	key       string
+	typ       string
+	operation string
+	model     string
+}
+
+// Middleware gives the requests of api a correlation ID, which the database logs the
+// statements they run with, and logs their operations. A nil auditor passes requests through.
+func (a *auditor) Middleware(api string, next http.Handler) http.Handler {
+	if a == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		id := correlationID(r)
+		w.Header().Set(CorrelationHeader, id)
+		r = r.WithContext(logger.WithCorrelationID(r.Context(), id))
+
+		var operations []auditOperation
+		if api == "graphql" {
+			operations = a.graphqlOperations(r)
+		} else {
+			operations = restOperations(r)
+		}
+		if len(operations) == 0 {
+			// Such as the playground and introspection queries
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		recorder := &auditWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		caller := ratelimit.ClientIP(r, a.trustProxy)
+		if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+			caller = fmt.Sprintf("%s (%s)", r.TLS.PeerCertificates[0].Subject.CommonName, caller)
+		}
+		duration := time.Since(start)
+		roles := masking.RolesFromContext(r.Context())
+		results := recorder.results(api, operations)
+		for i, op := range operations {
+			entry := AuditEntry{
+				CorrelationID: id,
+				API:           api,
+				Type:          op.typ,
+				Operation:     op.operation,
+				Model:         op.model,
+				Rows:          results[i].rows,
+				Status:        recorder.status,
+				Caller:        caller,
+				Roles:         roles,
+				Duration:      duration,
+				Error:         results[i].err,
+			}
+			a.logger.Info("%s", entry)
+		}
+	})
+}
+
+// correlationID returns the correlation ID sent with a request, or a new one
+func correlationID(r *http.Request) string {
+	for _, header := range []string{CorrelationHeader, "X-Request-ID"} {
+		if id := r.Header.Get(header); validCorrelationID(id) {
+			return id
+		}
+	}
+	return utils.NewUUID()
+}
+
+// validCorrelationID accepts IDs that are safe to write to logs
+func validCorrelationID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.ContainsRune("-_.:", c)) {
+			return false
+		}
+	}
+	return true
+}
+
+// graphqlOperations returns the model operations selected by a GraphQL request, one for
+// each top-level field. The body is read and put back for the handler.
+func (a *auditor) graphqlOperations(r *http.Request) []auditOperation {
+	var params struct {
+		Query         string `json:"query"`
+		OperationName string `json:"operationName"`
+	}
+	switch r.Method {
+	case http.MethodGet:
+		params.Query = r.URL.Query().Get("query")
+		params.OperationName = r.URL.Query().Get("operationName")
+	case http.MethodPost:
+		body, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return nil
+		}
+		if strings.Contains(r.Header.Get("Content-Type"), "application/graphql") {
+			params.Query = string(body)
+		} else if json.Unmarshal(body, &params) != nil {
+			return nil
+		}
+	}
+	if params.Query == "" {
+		return nil
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: params.Query})
+	if err != nil {
+		return nil
+	}
+	for _, def := range doc.Definitions {
+		opDef, ok := def.(*ast.OperationDefinition)
+		if !ok || (params.OperationName != "" && (opDef.Name == nil || opDef.Name.Value != params.OperationName)) {
+			continue
+		}
+		var operations []auditOperation
+		for _, selection := range opDef.SelectionSet.Selections {
+			field, ok := selection.(*ast.Field)
+			if !ok {
+				continue
+			}
+			operation, model := a.splitField(field.Name.Value)
+			if model == "" {
+				continue
+			}
+			key := field.Name.Value
+			if field.Alias != nil {
+				key = field.Alias.Value
+			}
+			operations = append(operations, auditOperation{key: key, typ: opDef.Operation, operation: operation, model: model})
+		}
+		return operations
+	}
+	return nil
+}
+
+// graphqlPrefixes are the operations of the generated fields, longest first
+var graphqlPrefixes = []string{"findUnique", "findMany", "createMany", "updateMany", "deleteMany", "count", "create", "update", "delete"}
+
+// splitField splits a generated GraphQL field such as findManyUser into its operation and model
+func (a *auditor) splitField(name string) (operation, model string) {
+	for _, prefix := range graphqlPrefixes {
+		if model, ok := strings.CutPrefix(name, prefix); ok {
+			if _, ok := a.schemas[model]; ok {
+				return prefix, model
+			}
+		}
+	}
+	return "", ""
+}
+
+// restOperations returns the operation of a REST request from its path and method
+func restOperations(r *http.Request) []auditOperation {
+	parts := splitPath(strings.TrimPrefix(r.URL.Path, "/api/"))
+	if len(parts) == 0 || len(parts) > 2 {
+		return nil
+	}
+
+	op := auditOperation{typ: "query"}
+	switch {
+	case parts[0] == "connections":
+		op.operation = "listConnections"
+		if len(parts) == 2 {
+			op.typ, op.operation = "mutation", parts[1]
+		}
+	case parts[0] == "changes":
+		op.operation = "changes"
+	case len(parts) == 1:
+		op.model = parts[0]
+		switch r.Method {
+		case http.MethodGet:
+			op.operation = "findMany"
+		case http.MethodPost:
+			op.typ, op.operation = "mutation", "create"
+		case http.MethodPut:
+			op.typ, op.operation = "mutation", "upsert"
+		default:
+			return nil
+		}
+	default:
+		op.model = parts[0]
+		switch parts[1] {
+		case "batch":
+			op.typ, op.operation = "mutation", "createMany"
+		case "stream", "aggregate", "groupBy":
+			op.operation = parts[1]
+		case "find-or-create":
+			op.typ, op.operation = "mutation", "findOrCreate"
+		default:
+			switch r.Method {
+			case http.MethodGet:
+				op.operation = "findUnique"
+			case http.MethodPut, http.MethodPatch:
+				op.typ, op.operation = "mutation", "update"
+			case http.MethodDelete:
+				op.typ, op.operation = "mutation", "delete"
+			default:
+				return nil
+			}
+		}
+	}
+	return []auditOperation{op}
+}
+
+func splitPath(path string) []string {
+	var parts []string
+	for _, p := range strings.Split(path, "/") {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
+
+// auditWriter keeps the status and a copy of the body of a response. Event streams are
+// not copied.
+type auditWriter struct {
+	http.ResponseWriter
+	status    int
+	body      bytes.Buffer
+	truncated bool
+	streaming bool
+	written   bool
+}
+
+func (w *auditWriter) WriteHeader(code int) {
+	if !w.written {
+		w.written = true
+		w.status = code
+		w.streaming = strings.HasPrefix(w.Header().Get("Content-Type"), "text/event-stream")
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *auditWriter) Write(b []byte) (int, error) {
+	if !w.written {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.streaming && !w.truncated {
+		if w.body.Len()+len(b) > maxAuditBody {
+			w.truncated = true
+			w.body.Reset()
+		} else {
+			w.body.Write(b)
+		}
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying writer so http.ResponseController can reach Flush
+func (w *auditWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// auditResult is the outcome of an operation read from the response
+type auditResult struct {
+	rows int
+	err  string
+}
+
+// results reads the outcome of each operation from the response
+func (w *auditWriter) results(api string, operations []auditOperation) []auditResult {
+	results := make([]auditResult, len(operations))
+	for i := range results {
+		results[i].rows = -1
+	}
+	if w.streaming || w.truncated {
+		return results
+	}
+
+	if api == "graphql" {
+		var response struct {
+			Data   map[string]any `json:"data"`
+			Errors []struct {
+				Message string `json:"message"`
+				Path    []any  `json:"path"`
+			} `json:"errors"`
+		}
+		if json.Unmarshal(w.body.Bytes(), &response) != nil {
+			return results
+		}
+		for i, op := range operations {
+			results[i].rows = graphqlRows(op.operation, response.Data[op.key])
+			for _, e := range response.Errors {
+				// Errors without a path, such as validation errors, fail every operation
+				if len(e.Path) == 0 || e.Path[0] == op.key {
+					results[i].err = e.Message
+					break
+				}
+			}
+		}
+		return results
+	}
+
+	var response struct {
+		Success bool `json:"success"`
+		Data    any  `json:"data"`
+		Error   *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(w.body.Bytes(), &response) != nil {
+		if w.status >= http.StatusBadRequest {
+			results[0] = auditResult{err: strings.TrimSpace(w.body.String())}
+		}
+		return results
+	}
+	if response.Error != nil {
+		results[0] = auditResult{err: response.Error.Message}
+		return results
+	}
+	results[0].rows = restRows(response.Data)
+	return results
+}
+
+// graphqlRows counts the records returned or changed by a GraphQL field
+func graphqlRows(operation string, data any) int {
+	switch operation {
+	case "findMany":
+		records, _ := data.([]any)
+		return len(records)
+	case "count":
+		count, _ := data.(float64)
+		return int(count)
+	case "createMany", "updateMany", "deleteMany":
+		result, _ := data.(map[string]any)
+		count, _ := result["count"].(float64)
+		return int(count)
+	default:
+		if data == nil {
+			return 0
+		}
+		return 1
+	}
+}
+
+// restRows counts the records returned or changed by a REST request
+func restRows(data any) int {
+	switch d := data.(type) {
+	case []any:
+		return len(d)
+	case map[string]any:
+		if created, ok := d["created"].(float64); ok {
+			return int(created)
+		}
+		return 1
+	case nil:
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rediwo/redi-orm/database"
+	"github.com/rediwo/redi-orm/graphql"
+	"github.com/rediwo/redi-orm/health"
+	"github.com/rediwo/redi-orm/logger"
+	"github.com/rediwo/redi-orm/masking"
+	"github.com/rediwo/redi-orm/ratelimit"
+	"github.com/rediwo/redi-orm/rest"
+	"github.com/rediwo/redi-orm/schema"
+)
+
+// Config holds the configuration of a Server
+type Config struct {
+	Database      database.Database // Connected database that serves the models
+	SchemaPath    string            // Prisma schema file or directory of the models
+	MigrationsDir string            // Optional: report migrations in this directory that are not applied in /readyz
+	Playground    bool              // Serve the GraphQL playground at the root path
+	LogLevel      string
+	Masking       *masking.Policy   // Optional: mask sensitive fields for unprivileged callers
+	RateLimit     *ratelimit.Config // Optional: limit the API requests of each client
+
+	// Optional: log each operation of the APIs with a correlation ID, which the database
+	// logs the statements of the request with
+	Audit logger.Logger
+
+	// Optional: ping the database this often, reconnecting when it stops answering
+	HealthCheckInterval time.Duration
+}
+
+// Server holds the handlers of the GraphQL and REST APIs
+type Server struct {
+	graphql    *graphql.Server
+	rest       *rest.Server
+	health     *health.Checker
+	playground bool
+	masking    *masking.Policy
+	limits     *ratelimit.Limits
+	audit      *auditor
+	stopHealth func() // Stops the health checks the server started
+}
+
+// New registers the models of the schema with the database, syncs their tables and creates
+// the API handlers. Models of other datasources are served from their named connections.
+func New(config Config) (*Server, error) {
+	if config.Database == nil {
+		return nil, fmt.Errorf("database is required")
+	}
+
+	var limits *ratelimit.Limits
+	if config.RateLimit != nil {
+		var err error
+		if limits, err = ratelimit.NewLimits(*config.RateLimit); err != nil {
+			return nil, err
+		}
+	}
+
+	graphqlServer, err := graphql.NewServer(graphql.ServerConfig{
+		Database:   config.Database,
+		SchemaPath: config.SchemaPath,
+		Playground: config.Playground,
+		LogLevel:   config.LogLevel,
+		Masking:    config.Masking,
+	})
+	if err != nil {
+		limits.Stop()
+		return nil, fmt.Errorf("failed to create GraphQL server: %w", err)
+	}
+
+	// The GraphQL server has registered and synced the models
+	restServer, err := rest.NewServer(rest.ServerConfig{
+		Database: config.Database,
+		LogLevel: config.LogLevel,
+		Masking:  config.Masking,
+	})
+	if err != nil {
+		limits.Stop()
+		graphqlServer.Stop()
+		return nil, fmt.Errorf("failed to create REST server: %w", err)
+	}
+
+	stopHealth := func() {}
+	if config.HealthCheckInterval > 0 {
+		if stopHealth, err = database.StartHealthCheck(config.Database, config.HealthCheckInterval); err != nil {
+			limits.Stop()
+			graphqlServer.Stop()
+			return nil, err
+		}
+	}
+
+	var audit *auditor
+	if config.Audit != nil {
+		audit = &auditor{
+			logger:     config.Audit,
+			schemas:    graphqlServer.Schemas(),
+			trustProxy: config.RateLimit != nil && config.RateLimit.TrustProxy,
+		}
+	}
+
+	return &Server{
+		graphql: graphqlServer,
+		rest:    restServer,
+		health: health.NewChecker(health.Config{
+			Database:      config.Database,
+			Schemas:       graphqlServer.Schemas(),
+			MigrationsDir: config.MigrationsDir,
+		}),
+		playground: config.Playground,
+		masking:    config.Masking,
+		limits:     limits,
+		audit:      audit,
+		stopHealth: stopHealth,
+	}, nil
+}
+
+// Attach mounts the endpoints on mux: the GraphQL API at /graphql, the REST API under /api/,
+// the liveness and readiness probes at /healthz and /readyz, and the GraphQL playground at /
+// when enabled. The APIs are rate limited and audited when configured, the probes are not.
+// CORS is left to the application.
+func (s *Server) Attach(mux *http.ServeMux) {
+	mux.Handle("/graphql", s.limits.Middleware(s.masking.Middleware(s.audit.Middleware("graphql", s.graphql.Handler()))))
+	if s.playground {
+		mux.Handle("/", s.limits.Middleware(s.masking.Middleware(s.audit.Middleware("graphql", s.graphql.Handler()))))
+	}
+	mux.Handle("/api/", s.limits.Middleware(s.masking.Middleware(s.audit.Middleware("rest", s.rest.Router))))
+	mux.Handle("/healthz", s.health.LivenessHandler())
+	mux.Handle("/readyz", s.health.ReadinessHandler())
+}
+
+// Handler returns a handler serving the endpoints of Attach
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	s.Attach(mux)
+	return mux
+}
+
+// Schemas returns the models the server serves by name
+func (s *Server) Schemas() map[string]*schema.Schema {
+	return s.graphql.Schemas()
+}
+
+// Close closes the datasource connections the server made and stops its health checks. The
+// database of the Config stays open.
+func (s *Server) Close() error {
+	s.limits.Stop()
+	s.stopHealth()
+	return s.graphql.Stop()
+}
@@ -0,0 +1,226 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rediwo/redi-orm/database"
+	_ "github.com/rediwo/redi-orm/drivers/sqlite"
+	"github.com/rediwo/redi-orm/logger"
+	"github.com/rediwo/redi-orm/masking"
+	"github.com/rediwo/redi-orm/ratelimit"
+)
+
+// newTestServer creates a server for a User model in a temporary SQLite database
+func newTestServer(t *testing.T, config Config) *Server {
+	t.Helper()
+	config.Database, config.SchemaPath = newTestDatabase(t)
+	config.LogLevel = "none"
+	apiServer, err := New(config)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(func() { apiServer.Close() })
+	return apiServer
+}
+
+// newTestDatabase connects a temporary SQLite database and writes the schema of a User model
+func newTestDatabase(t *testing.T) (database.Database, string) {
+	t.Helper()
+	dir := t.TempDir()
+	schemaPath := filepath.Join(dir, "schema.prisma")
+	if err := os.WriteFile(schemaPath, []byte(`
+model User {
+  id    Int    @id @default(autoincrement())
+  name  String
+}
+`), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	db, err := database.NewFromURI("sqlite://" + filepath.Join(dir, "app.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	if err := db.Connect(context.Background()); err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, schemaPath
+}
+
+func TestAttach(t *testing.T) {
+	apiServer := newTestServer(t, Config{})
+
+	// Embed the APIs next to a route of the application
+	mux := http.NewServeMux()
+	mux.HandleFunc("/app", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("app"))
+	})
+	apiServer.Attach(mux)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	request := func(method, path, body string) (int, string) {
+		t.Helper()
+		req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("NewRequest() error = %v", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s error = %v", method, path, err)
+		}
+		defer resp.Body.Close()
+		content, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(content)
+	}
+
+	if code, body := request("POST", "/graphql", `{"query":"mutation { createUser(data: {name: \"Alice\"}) { id name } }"}`); code != http.StatusOK || !strings.Contains(body, "Alice") {
+		t.Errorf("Expected the GraphQL API at /graphql, got %d %s", code, body)
+	}
+
+	code, body := request("GET", "/api/User", "")
+	if code != http.StatusOK {
+		t.Fatalf("Expected the REST API at /api, got %d %s", code, body)
+	}
+	var list struct {
+		Data []map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(body), &list); err != nil || len(list.Data) != 1 {
+		t.Errorf("Expected the user created through GraphQL, got %s", body)
+	}
+
+	if code, body := request("GET", "/readyz", ""); code != http.StatusOK || !strings.Contains(body, `"registered":1`) {
+		t.Errorf("Expected the readiness probe at /readyz, got %d %s", code, body)
+	}
+	if code, body := request("GET", "/app", ""); code != http.StatusOK || body != "app" {
+		t.Errorf("Expected the application route to be kept, got %d %s", code, body)
+	}
+	if code, _ := request("GET", "/unknown", ""); code != http.StatusNotFound {
+		t.Errorf("Expected no playground at the root path, got %d", code)
+	}
+}
+
+func TestNewRequiresDatabase(t *testing.T) {
+	if _, err := New(Config{SchemaPath: "schema.prisma"}); err == nil {
+		t.Error("Expected an error without a database")
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	apiServer := newTestServer(t, Config{RateLimit: &ratelimit.Config{RequestsPerMin: 60, Burst: 1}})
+	handler := apiServer.Handler()
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	if w := get("/api/User"); w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Errorf("Expected the first request to be allowed, got %d %v", w.Code, w.Header())
+	}
+	if w := get("/api/User"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected 429 over the limit, got %d", w.Code)
+	}
+	for range 3 {
+		if w := get("/healthz"); w.Code != http.StatusOK {
+			t.Fatalf("Expected probes not to be rate limited, got %d", w.Code)
+		}
+	}
+}
+
+func TestAudit(t *testing.T) {
+	db, schemaPath := newTestDatabase(t)
+	var dbLog, auditLog bytes.Buffer
+	auditLogger := logger.NewDefaultLogger("AUDIT")
+	auditLogger.SetOutput(&auditLog)
+
+	apiServer, err := New(Config{
+		Database:   db,
+		SchemaPath: schemaPath,
+		LogLevel:   "none",
+		Masking:    &masking.Policy{RoleHeader: "X-Roles"},
+		Audit:      auditLogger,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer apiServer.Close()
+	handler := apiServer.Handler()
+
+	// The REST server sets a logger of its own
+	dbLogger := logger.NewDefaultLogger("DB")
+	dbLogger.SetOutput(&dbLog)
+	dbLogger.SetLevel(logger.LogLevelDebug)
+	db.SetLogger(dbLogger)
+
+	request := func(method, path, body string, header map[string]string) *httptest.ResponseRecorder {
+		t.Helper()
+		auditLog.Reset()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		for name, value := range header {
+			req.Header.Set(name, value)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+	assertLogged := func(parts ...string) {
+		t.Helper()
+		for _, part := range parts {
+			if !strings.Contains(auditLog.String(), part) {
+				t.Errorf("Expected %q in the audit log, got %s", part, auditLog.String())
+			}
+		}
+	}
+
+	// Each field of a GraphQL request is an operation, logged with the ID sent by the client
+	w := request("POST", "/graphql", `{"query":"mutation { a: createUser(data: {name: \"Alice\"}) { id } b: createUser(data: {name: \"Bob\"}) { id } }"}`,
+		map[string]string{CorrelationHeader: "req-1", "X-Roles": "admin"})
+	if w.Code != http.StatusOK || w.Header().Get(CorrelationHeader) != "req-1" {
+		t.Fatalf("Expected the correlation ID in the response, got %d %v", w.Code, w.Header())
+	}
+	if n := strings.Count(auditLog.String(), "correlation=req-1 api=graphql type=mutation operation=create model=User rows=1 status=200"); n != 2 {
+		t.Errorf("Expected two create operations, got %s", auditLog.String())
+	}
+	assertLogged("roles=admin", "caller=192.0.2.1")
+	if !strings.Contains(dbLog.String(), "[req-1] SQL") {
+		t.Errorf("Expected the statements of the request tagged with its ID, got %s", dbLog.String())
+	}
+
+	request("POST", "/graphql", `{"query":"{ countUser findManyUser { id } }"}`, nil)
+	assertLogged("type=query operation=count model=User rows=2", "type=query operation=findMany model=User rows=2")
+
+	// A correlation ID is generated when the client sends none, or one unsafe to log
+	w = request("GET", "/api/User", "", map[string]string{CorrelationHeader: "bad id"})
+	id := w.Header().Get(CorrelationHeader)
+	if id == "" || id == "bad id" {
+		t.Fatalf("Expected a generated correlation ID, got %q", id)
+	}
+	assertLogged("correlation=" + id + " api=rest type=query operation=findMany model=User rows=2 status=200")
+
+	request("POST", "/api/User/batch", `{"data":[{"name":"Carol"},{"name":"Dave"}]}`, nil)
+	assertLogged("api=rest type=mutation operation=createMany model=User rows=2 status=201")
+
+	request("DELETE", "/api/User/999", "", nil)
+	assertLogged("type=mutation operation=delete model=User rows=0 status=404", "error=")
+
+	// Introspection and the probes are not audited
+	request("POST", "/graphql", `{"query":"{ __schema { queryType { name } } }"}`, nil)
+	request("GET", "/healthz", "", nil)
+	if auditLog.Len() != 0 {
+		t.Errorf("Expected no operations logged, got %s", auditLog.String())
+	}
+}
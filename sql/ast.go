@@ -1,5 +1,10 @@
 package sql
 
+import (
+	"fmt"
+	"strings"
+)
+
 // StatementType represents the type of SQL statement
 type StatementType int
 
@@ -18,8 +23,37 @@ type SQLStatement interface {
 
 // SelectField represents a field in SELECT clause
 type SelectField struct {
-	Expression string // Field name or expression
-	Alias      string // Optional alias
+	Expression string      // Field name or expression
+	Alias      string      // Optional alias
+	Window     *WindowSpec // Set for window functions such as ROW_NUMBER() OVER (...)
+}
+
+// WindowSpec represents a window function call: FUNC(args) OVER (PARTITION BY ... ORDER BY ...)
+type WindowSpec struct {
+	Function    *Expression // The function call, e.g. ROW_NUMBER() or SUM(views)
+	PartitionBy []string
+	OrderBy     []*OrderByClause
+}
+
+// String renders the window function call
+func (w *WindowSpec) String() string {
+	return w.Function.String() + " OVER (" + w.OverString() + ")"
+}
+
+// OverString renders the OVER clause contents; equal strings describe the same window
+func (w *WindowSpec) OverString() string {
+	var parts []string
+	if len(w.PartitionBy) > 0 {
+		parts = append(parts, "PARTITION BY "+strings.Join(w.PartitionBy, ", "))
+	}
+	if len(w.OrderBy) > 0 {
+		terms := make([]string, len(w.OrderBy))
+		for i, clause := range w.OrderBy {
+			terms[i] = clause.String()
+		}
+		parts = append(parts, "ORDER BY "+strings.Join(terms, ", "))
+	}
+	return strings.Join(parts, " ")
 }
 
 // TableRef represents a table reference
@@ -57,6 +91,90 @@ const (
 type OrderByClause struct {
 	Field     string
 	Direction OrderDirection
+	Expr      *Expression // Set for computed terms (function calls, arithmetic, positions); nil for column references
+}
+
+// String renders the ORDER BY term
+func (o *OrderByClause) String() string {
+	term := o.Field
+	if o.Expr != nil {
+		term = o.Expr.String()
+	}
+	if o.Direction == OrderDirectionDesc {
+		term += " DESC"
+	}
+	return term
+}
+
+// Expression represents a computed term: a column reference, literal, function call or
+// arithmetic on those
+type Expression struct {
+	Field    string        // Column reference, possibly qualified (table.column); "*" inside COUNT(*)
+	Value    any           // Literal value when the expression has no field, function or operator
+	Function string        // Function name for calls such as COUNT(*) or LOWER(name)
+	Args     []*Expression // Function arguments
+	Operator string        // "+", "-", "*", "/" or "%" for binary expressions
+	Left     *Expression   // Left operand of a binary expression
+	Right    *Expression   // Right operand of a binary expression
+	Whens    []*WhenClause // Branches of a CASE expression
+	Else     *Expression   // ELSE result of a CASE expression, nil when absent
+}
+
+// WhenClause represents a WHEN ... THEN ... branch of a CASE expression
+type WhenClause struct {
+	Condition *WhereClause
+	Result    *Expression
+}
+
+// String renders the expression in the compact form used for SELECT field expressions,
+// e.g. COUNT(*) or SUM(views)
+func (e *Expression) String() string {
+	switch {
+	case len(e.Whens) > 0:
+		var b strings.Builder
+		b.WriteString("CASE")
+		for _, when := range e.Whens {
+			b.WriteString(" WHEN " + when.Condition.String() + " THEN " + when.Result.String())
+		}
+		if e.Else != nil {
+			b.WriteString(" ELSE " + e.Else.String())
+		}
+		b.WriteString(" END")
+		return b.String()
+	case e.Operator != "":
+		return e.operandString(e.Left) + " " + e.Operator + " " + e.operandString(e.Right)
+	case e.Function != "":
+		args := make([]string, len(e.Args))
+		for i, arg := range e.Args {
+			args[i] = arg.String()
+		}
+		return e.Function + "(" + strings.Join(args, ",") + ")"
+	case e.Field != "":
+		return e.Field
+	}
+	return formatLiteral(e.Value)
+}
+
+// formatLiteral renders a parsed literal value; "?" is the parameter placeholder
+func formatLiteral(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		if v == "?" {
+			return v
+		}
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	}
+	return fmt.Sprint(value)
+}
+
+// operandString parenthesizes nested binary expressions
+func (e *Expression) operandString(operand *Expression) string {
+	if operand.Operator != "" {
+		return "(" + operand.String() + ")"
+	}
+	return operand.String()
 }
 
 // WhereClause represents a WHERE condition
@@ -70,7 +188,7 @@ type WhereClause struct {
 // Condition represents a single condition
 type Condition struct {
 	Field    string           // Field name
-	Operator string           // "=", ">", "<", ">=", "<=", "!=", "LIKE", "IN", "NOT IN", "IS NULL", "IS NOT NULL"
+	Operator string           // "=", ">", "<", ">=", "<=", "!=", "LIKE", "IN", "NOT IN", "IS NULL", "IS NOT NULL", "EXISTS", "NOT EXISTS"
 	Value    any              // Single value
 	Values   []any            // Multiple values for IN clause
 	Subquery *SelectStatement // Subquery for IN/EXISTS clauses
@@ -97,6 +215,47 @@ func (s *SelectStatement) GetTableName() string {
 	return s.From.Table
 }
 
+// SetOperator represents the operator combining the rows of two queries
+type SetOperator int
+
+const (
+	SetOperatorUnion SetOperator = iota
+	SetOperatorIntersect
+	SetOperatorExcept
+)
+
+func (o SetOperator) String() string {
+	switch o {
+	case SetOperatorIntersect:
+		return "INTERSECT"
+	case SetOperatorExcept:
+		return "EXCEPT"
+	default:
+		return "UNION"
+	}
+}
+
+// SetOperationStatement represents two queries combined with UNION, INTERSECT or EXCEPT.
+// Left and Right are *SelectStatement or nested *SetOperationStatement values; ORDER BY,
+// LIMIT and OFFSET after the last query apply to the combined rows.
+type SetOperationStatement struct {
+	Left     SQLStatement
+	Operator SetOperator
+	All      bool // Keep duplicate rows (UNION ALL, INTERSECT ALL, EXCEPT ALL)
+	Right    SQLStatement
+	OrderBy  []*OrderByClause
+	Limit    *int
+	Offset   *int
+}
+
+func (s *SetOperationStatement) GetType() StatementType {
+	return StatementTypeSelect
+}
+
+func (s *SetOperationStatement) GetTableName() string {
+	return s.Left.GetTableName()
+}
+
 // InsertStatement represents an INSERT statement
 type InsertStatement struct {
 	Table  string
@@ -114,9 +273,10 @@ func (s *InsertStatement) GetTableName() string {
 
 // UpdateStatement represents an UPDATE statement
 type UpdateStatement struct {
-	Table string
-	Set   map[string]any
-	Where *WhereClause
+	Table   string
+	Set     map[string]any // Literal values, or *Expression for computed values
+	Columns []string       // Assigned columns in statement order
+	Where   *WhereClause
 }
 
 func (s *UpdateStatement) GetType() StatementType {
@@ -198,3 +358,37 @@ func (w *WhereClause) Not() *WhereClause {
 		Left:     w,
 	}
 }
+
+// String renders the clause as SQL
+func (w *WhereClause) String() string {
+	switch strings.ToUpper(w.Operator) {
+	case "AND", "OR":
+		return "(" + w.Left.String() + " " + strings.ToUpper(w.Operator) + " " + w.Right.String() + ")"
+	case "NOT":
+		return "NOT (" + w.Left.String() + ")"
+	}
+	if w.Condition != nil {
+		return w.Condition.String()
+	}
+	return ""
+}
+
+// String renders the condition as SQL
+func (c *Condition) String() string {
+	switch c.Operator {
+	case "EXISTS", "NOT EXISTS":
+		return c.Operator + " (SELECT ...)"
+	case "IS NULL", "IS NOT NULL":
+		return c.Field + " " + c.Operator
+	case "IN", "NOT IN":
+		if c.Subquery != nil {
+			return c.Field + " " + c.Operator + " (SELECT ...)"
+		}
+		values := make([]string, len(c.Values))
+		for i, v := range c.Values {
+			values[i] = formatLiteral(v)
+		}
+		return c.Field + " " + c.Operator + " (" + strings.Join(values, ", ") + ")"
+	}
+	return c.Field + " " + c.Operator + " " + formatLiteral(c.Value)
+}
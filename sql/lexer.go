@@ -53,6 +53,18 @@ const (
 	TokenDistinct
 	TokenTrue
 	TokenFalse
+	TokenCase
+	TokenWhen
+	TokenThen
+	TokenElse
+	TokenEnd
+	TokenExists
+	TokenUnion
+	TokenIntersect
+	TokenExcept
+	TokenAll
+	TokenOver
+	TokenPartition
 
 	// Operators
 	TokenEqual        // =
@@ -61,6 +73,10 @@ const (
 	TokenLessEqual    // <=
 	TokenGreater      // >
 	TokenGreaterEqual // >=
+	TokenPlus         // +
+	TokenMinus        // -
+	TokenSlash        // /
+	TokenPercent      // %
 
 	// Delimiters
 	TokenComma     // ,
@@ -78,6 +94,7 @@ type Token struct {
 	Literal string
 	Line    int
 	Column  int
+	Quote   byte // Quote character of a TokenString
 }
 
 // Lexer represents the lexical analyzer
@@ -92,39 +109,51 @@ type Lexer struct {
 
 // Keywords map
 var keywords = map[string]TokenType{
-	"SELECT":   TokenSelect,
-	"FROM":     TokenFrom,
-	"WHERE":    TokenWhere,
-	"INSERT":   TokenInsert,
-	"INTO":     TokenInto,
-	"VALUES":   TokenValues,
-	"UPDATE":   TokenUpdate,
-	"SET":      TokenSet,
-	"DELETE":   TokenDelete,
-	"ORDER":    TokenOrder,
-	"BY":       TokenBy,
-	"GROUP":    TokenGroup,
-	"HAVING":   TokenHaving,
-	"LIMIT":    TokenLimit,
-	"OFFSET":   TokenOffset,
-	"JOIN":     TokenJoin,
-	"INNER":    TokenInner,
-	"LEFT":     TokenLeft,
-	"RIGHT":    TokenRight,
-	"FULL":     TokenFull,
-	"ON":       TokenOn,
-	"AS":       TokenAs,
-	"AND":      TokenAnd,
-	"OR":       TokenOr,
-	"NOT":      TokenNot,
-	"IN":       TokenIn,
-	"IS":       TokenIs,
-	"NULL":     TokenNull,
-	"LIKE":     TokenLike,
-	"BETWEEN":  TokenBetween,
-	"DISTINCT": TokenDistinct,
-	"TRUE":     TokenTrue,
-	"FALSE":    TokenFalse,
+	"SELECT":    TokenSelect,
+	"FROM":      TokenFrom,
+	"WHERE":     TokenWhere,
+	"INSERT":    TokenInsert,
+	"INTO":      TokenInto,
+	"VALUES":    TokenValues,
+	"UPDATE":    TokenUpdate,
+	"SET":       TokenSet,
+	"DELETE":    TokenDelete,
+	"ORDER":     TokenOrder,
+	"BY":        TokenBy,
+	"GROUP":     TokenGroup,
+	"HAVING":    TokenHaving,
+	"LIMIT":     TokenLimit,
+	"OFFSET":    TokenOffset,
+	"JOIN":      TokenJoin,
+	"INNER":     TokenInner,
+	"LEFT":      TokenLeft,
+	"RIGHT":     TokenRight,
+	"FULL":      TokenFull,
+	"ON":        TokenOn,
+	"AS":        TokenAs,
+	"AND":       TokenAnd,
+	"OR":        TokenOr,
+	"NOT":       TokenNot,
+	"IN":        TokenIn,
+	"IS":        TokenIs,
+	"NULL":      TokenNull,
+	"LIKE":      TokenLike,
+	"BETWEEN":   TokenBetween,
+	"DISTINCT":  TokenDistinct,
+	"TRUE":      TokenTrue,
+	"FALSE":     TokenFalse,
+	"CASE":      TokenCase,
+	"WHEN":      TokenWhen,
+	"THEN":      TokenThen,
+	"ELSE":      TokenElse,
+	"END":       TokenEnd,
+	"EXISTS":    TokenExists,
+	"UNION":     TokenUnion,
+	"INTERSECT": TokenIntersect,
+	"EXCEPT":    TokenExcept,
+	"ALL":       TokenAll,
+	"OVER":      TokenOver,
+	"PARTITION": TokenPartition,
 }
 
 // NewLexer creates a new lexer instance
@@ -257,6 +286,14 @@ func (l *Lexer) NextToken() Token {
 		} else {
 			tok = Token{Type: TokenGreater, Literal: string(l.ch), Line: l.line, Column: l.column}
 		}
+	case '+':
+		tok = Token{Type: TokenPlus, Literal: string(l.ch), Line: l.line, Column: l.column}
+	case '-':
+		tok = Token{Type: TokenMinus, Literal: string(l.ch), Line: l.line, Column: l.column}
+	case '/':
+		tok = Token{Type: TokenSlash, Literal: string(l.ch), Line: l.line, Column: l.column}
+	case '%':
+		tok = Token{Type: TokenPercent, Literal: string(l.ch), Line: l.line, Column: l.column}
 	case ',':
 		tok = Token{Type: TokenComma, Literal: string(l.ch), Line: l.line, Column: l.column}
 	case ';':
@@ -279,6 +316,7 @@ func (l *Lexer) NextToken() Token {
 		}
 	case '\'', '"':
 		tok.Type = TokenString
+		tok.Quote = l.ch
 		tok.Literal = l.readString(l.ch)
 		tok.Line = l.line
 		tok.Column = l.column
@@ -344,6 +382,14 @@ func DetectSQL(input string) bool {
 	lexer := NewLexer(input)
 	tok := lexer.NextToken()
 
+	// Parenthesized SELECT starting a set operation
+	if tok.Type == TokenLParen {
+		for tok.Type == TokenLParen {
+			tok = lexer.NextToken()
+		}
+		return tok.Type == TokenSelect
+	}
+
 	return tok.Type == TokenSelect || tok.Type == TokenInsert ||
 		tok.Type == TokenUpdate || tok.Type == TokenDelete
 }
@@ -429,6 +475,30 @@ func (t TokenType) String() string {
 		return "TRUE"
 	case TokenFalse:
 		return "FALSE"
+	case TokenCase:
+		return "CASE"
+	case TokenWhen:
+		return "WHEN"
+	case TokenThen:
+		return "THEN"
+	case TokenElse:
+		return "ELSE"
+	case TokenEnd:
+		return "END"
+	case TokenExists:
+		return "EXISTS"
+	case TokenUnion:
+		return "UNION"
+	case TokenIntersect:
+		return "INTERSECT"
+	case TokenExcept:
+		return "EXCEPT"
+	case TokenAll:
+		return "ALL"
+	case TokenOver:
+		return "OVER"
+	case TokenPartition:
+		return "PARTITION"
 	case TokenEqual:
 		return "="
 	case TokenNotEqual:
@@ -441,6 +511,14 @@ func (t TokenType) String() string {
 		return ">"
 	case TokenGreaterEqual:
 		return ">="
+	case TokenPlus:
+		return "+"
+	case TokenMinus:
+		return "-"
+	case TokenSlash:
+		return "/"
+	case TokenPercent:
+		return "%"
 	case TokenComma:
 		return ","
 	case TokenSemicolon:
@@ -0,0 +1,303 @@
+package sql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NormalizedQuery is a single statement in the canonical form produced by Normalize
+type NormalizedQuery struct {
+	SQL       string       // Tokens separated by single spaces, keywords in upper case
+	Args      []any        // Values of the ? placeholders of SQL, in order
+	Statement SQLStatement // The parsed statement
+	Functions []string     // Names of the functions the statement calls, in upper case
+}
+
+// QuoteStyle tells how the database reads double-quoted tokens
+type QuoteStyle int
+
+const (
+	DoubleQuotedStrings     QuoteStyle = iota // "text" is a string, as in MySQL
+	DoubleQuotedIdentifiers                   // "name" is an identifier, as in PostgreSQL
+)
+
+// Normalize parses input as a single statement and renders it in a canonical form.
+// String literals are bound as ? parameters, so the database reads the same values as
+// the parser whatever its quoting rules; args are the values of the placeholders of
+// input. With DoubleQuotedIdentifiers, double-quoted tokens stay quoted identifiers
+// and must be plain names. A positive maxRows caps the rows of a query: LIMIT maxRows
+// is added when the query has no LIMIT, and a larger LIMIT is lowered to it.
+func Normalize(input string, args []any, maxRows int, quotes QuoteStyle) (*NormalizedQuery, error) {
+	var tokens []Token
+	lexer := NewLexer(input)
+	for {
+		tok := lexer.NextToken()
+		if tok.Type == TokenEOF {
+			break
+		}
+		tokens = append(tokens, tok)
+	}
+	for len(tokens) > 0 && tokens[len(tokens)-1].Type == TokenSemicolon {
+		tokens = tokens[:len(tokens)-1]
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty statement")
+	}
+
+	depth, placeholders := 0, 0
+	for _, tok := range tokens {
+		switch tok.Type {
+		case TokenIllegal:
+			return nil, fmt.Errorf("line %d, column %d: unexpected character %q", tok.Line, tok.Column, tok.Literal)
+		case TokenSemicolon:
+			return nil, fmt.Errorf("line %d, column %d: only a single statement is allowed", tok.Line, tok.Column)
+		case TokenQuestion:
+			placeholders++
+		case TokenLParen:
+			depth++
+		case TokenRParen:
+			if depth--; depth < 0 {
+				return nil, fmt.Errorf("line %d, column %d: unbalanced parentheses", tok.Line, tok.Column)
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced parentheses")
+	}
+	if placeholders != len(args) {
+		return nil, fmt.Errorf("expected %d parameters, got %d", placeholders, len(args))
+	}
+
+	// The parser reads a quoted identifier as its bare name, which must not be a keyword
+	quoted := make(map[int]bool)
+	if quotes == DoubleQuotedIdentifiers {
+		for i, tok := range tokens {
+			if tok.Type != TokenString || tok.Quote != '"' {
+				continue
+			}
+			if !isPlainIdentifier(tok.Literal) {
+				return nil, fmt.Errorf("line %d, column %d: quoted identifier %q must be a plain name", tok.Line, tok.Column, tok.Literal)
+			}
+			tokens[i].Type = TokenIdent
+			quoted[i] = true
+		}
+	}
+
+	if maxRows > 0 && (tokens[0].Type == TokenSelect || tokens[0].Type == TokenLParen) {
+		tokens = limitRows(tokens, maxRows)
+	}
+
+	// b is sent to the database and p to the parser; they differ by quoted identifiers only
+	var b, p strings.Builder
+	var boundArgs []any
+	for i, tok := range tokens {
+		if i > 0 && spaceBetween(tokens[i-1], tok) {
+			b.WriteByte(' ')
+			p.WriteByte(' ')
+		}
+		text := tok.Literal
+		switch {
+		case tok.Type == TokenString:
+			text = "?"
+			boundArgs = append(boundArgs, unescapeString(tok.Literal))
+		case tok.Type == TokenQuestion:
+			boundArgs = append(boundArgs, args[0])
+			args = args[1:]
+		case isKeyword(tok.Type):
+			text = strings.ToUpper(tok.Literal)
+		}
+		p.WriteString(text)
+		if quoted[i] {
+			text = `"` + text + `"`
+		}
+		b.WriteString(text)
+	}
+
+	// The parser must read the whole statement, so nothing reaches the database unchecked
+	normalized := b.String()
+	parser := NewParser(p.String())
+	stmt, err := parser.Parse()
+	if err != nil {
+		return nil, err
+	}
+	if parser.curToken.Type != TokenEOF {
+		return nil, fmt.Errorf("unexpected %s after the statement", parser.curToken.Type.String())
+	}
+
+	var functions []string
+	for i := 1; i < len(tokens); i++ {
+		if tokens[i].Type == TokenLParen && tokens[i-1].Type == TokenIdent {
+			functions = append(functions, strings.ToUpper(tokens[i-1].Literal))
+		}
+	}
+
+	return &NormalizedQuery{SQL: normalized, Args: boundArgs, Statement: stmt, Functions: functions}, nil
+}
+
+// limitRows caps the rows of a query at maxRows with the LIMIT outside any parentheses
+func limitRows(tokens []Token, maxRows int) []Token {
+	depth, limit, offset := 0, -1, -1
+	for i, tok := range tokens {
+		switch tok.Type {
+		case TokenLParen:
+			depth++
+		case TokenRParen:
+			depth--
+		case TokenLimit:
+			if depth == 0 {
+				limit = i
+			}
+		case TokenOffset:
+			if depth == 0 {
+				offset = i
+			}
+		}
+	}
+
+	maxLiteral := strconv.Itoa(maxRows)
+	if limit >= 0 {
+		if limit+1 < len(tokens) && tokens[limit+1].Type == TokenInt {
+			if n, err := strconv.Atoi(tokens[limit+1].Literal); err != nil || n > maxRows {
+				tokens[limit+1].Literal = maxLiteral
+			}
+		}
+		return tokens
+	}
+
+	clause := []Token{{Type: TokenLimit, Literal: "LIMIT"}, {Type: TokenInt, Literal: maxLiteral}}
+	if offset < 0 {
+		return append(tokens, clause...)
+	}
+	// LIMIT comes before OFFSET
+	return append(tokens[:offset], append(clause, tokens[offset:]...)...)
+}
+
+// spaceBetween reports whether normalized SQL separates two adjacent tokens with a space
+func spaceBetween(prev, tok Token) bool {
+	switch {
+	case tok.Type == TokenComma, tok.Type == TokenRParen, tok.Type == TokenDot:
+		return false
+	case prev.Type == TokenLParen, prev.Type == TokenDot:
+		return false
+	case tok.Type == TokenLParen && prev.Type == TokenIdent:
+		return false // Function call
+	}
+	return true
+}
+
+// isPlainIdentifier reports whether name reads as an identifier, not a keyword, unquoted
+func isPlainIdentifier(name string) bool {
+	if name == "" || !isLetter(name[0]) {
+		return false
+	}
+	for i := 1; i < len(name); i++ {
+		if !isLetter(name[i]) && !isDigit(name[i]) {
+			return false
+		}
+	}
+	return lookupIdent(name) == TokenIdent
+}
+
+// isKeyword reports whether a token type is a keyword
+func isKeyword(t TokenType) bool {
+	return t >= TokenSelect && t <= TokenPartition
+}
+
+// unescapeString returns the value of a string literal read by the lexer, whose
+// backslashes escape the following character
+func unescapeString(literal string) string {
+	if !strings.Contains(literal, "\\") {
+		return literal
+	}
+	var b strings.Builder
+	for i := 0; i < len(literal); i++ {
+		if literal[i] == '\\' && i+1 < len(literal) {
+			i++
+		}
+		b.WriteByte(literal[i])
+	}
+	return b.String()
+}
+
+// TableNames returns the tables a statement reads or writes, including those of its
+// joins and subqueries, in the order they first appear
+func TableNames(stmt SQLStatement) []string {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	var walkStatement func(SQLStatement)
+	var walkWhere func(*WhereClause)
+	var walkExpression func(*Expression)
+	walkWhere = func(where *WhereClause) {
+		if where == nil {
+			return
+		}
+		walkWhere(where.Left)
+		walkWhere(where.Right)
+		if where.Condition != nil && where.Condition.Subquery != nil {
+			walkStatement(where.Condition.Subquery)
+		}
+	}
+	walkExpression = func(expr *Expression) {
+		if expr == nil {
+			return
+		}
+		for _, arg := range expr.Args {
+			walkExpression(arg)
+		}
+		walkExpression(expr.Left)
+		walkExpression(expr.Right)
+		for _, when := range expr.Whens {
+			walkWhere(when.Condition)
+			walkExpression(when.Result)
+		}
+		walkExpression(expr.Else)
+	}
+	walkStatement = func(stmt SQLStatement) {
+		switch s := stmt.(type) {
+		case *SelectStatement:
+			if s == nil {
+				return
+			}
+			add(s.From.Table)
+			for _, join := range s.Joins {
+				add(join.Table.Table)
+				walkWhere(join.Condition)
+			}
+			walkWhere(s.Where)
+			walkWhere(s.Having)
+			for _, order := range s.OrderBy {
+				walkExpression(order.Expr)
+			}
+		case *SetOperationStatement:
+			walkStatement(s.Left)
+			walkStatement(s.Right)
+			for _, order := range s.OrderBy {
+				walkExpression(order.Expr)
+			}
+		case *InsertStatement:
+			add(s.Table)
+		case *UpdateStatement:
+			add(s.Table)
+			for _, value := range s.Set {
+				if expr, ok := value.(*Expression); ok {
+					walkExpression(expr)
+				}
+			}
+			walkWhere(s.Where)
+		case *DeleteStatement:
+			add(s.Table)
+			walkWhere(s.Where)
+		}
+	}
+
+	walkStatement(stmt)
+	return names
+}
@@ -0,0 +1,150 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		args      []any
+		maxRows   int
+		quotes    QuoteStyle
+		expected  string
+		boundArgs []any
+	}{
+		{
+			name:      "canonical form",
+			input:     "select  id, count(*)\n  from users u where u.age >= ? group by id;",
+			args:      []any{18},
+			expected:  "SELECT id, count(*) FROM users u WHERE u.age >= ? GROUP BY id",
+			boundArgs: []any{18},
+		},
+		{
+			name:      "string literals are bound",
+			input:     `SELECT * FROM users WHERE name = 'O\'Brien' AND age > ? AND email LIKE '%@example.com'`,
+			args:      []any{30},
+			expected:  "SELECT * FROM users WHERE name = ? AND age > ? AND email LIKE ?",
+			boundArgs: []any{"O'Brien", 30, "%@example.com"},
+		},
+		{
+			name:      "double-quoted strings",
+			input:     `SELECT * FROM users WHERE name = "Alice"`,
+			expected:  "SELECT * FROM users WHERE name = ?",
+			boundArgs: []any{"Alice"},
+		},
+		{
+			name:      "double-quoted identifiers",
+			input:     `SELECT "firstName", u."createdAt" FROM "Users" u WHERE "firstName" = 'Alice'`,
+			quotes:    DoubleQuotedIdentifiers,
+			expected:  `SELECT "firstName", u."createdAt" FROM "Users" u WHERE "firstName" = ?`,
+			boundArgs: []any{"Alice"},
+		},
+		{
+			name:     "limit added",
+			input:    "SELECT * FROM users ORDER BY id",
+			maxRows:  100,
+			expected: "SELECT * FROM users ORDER BY id LIMIT 100",
+		},
+		{
+			name:     "limit added before offset",
+			input:    "SELECT * FROM users OFFSET 20",
+			maxRows:  100,
+			expected: "SELECT * FROM users LIMIT 100 OFFSET 20",
+		},
+		{
+			name:     "larger limit lowered",
+			input:    "SELECT * FROM users LIMIT 5000 OFFSET 10",
+			maxRows:  100,
+			expected: "SELECT * FROM users LIMIT 100 OFFSET 10",
+		},
+		{
+			name:     "smaller limit kept",
+			input:    "SELECT * FROM users LIMIT 10",
+			maxRows:  100,
+			expected: "SELECT * FROM users LIMIT 10",
+		},
+		{
+			name:     "limits of subqueries ignored",
+			input:    "SELECT * FROM users WHERE id IN (SELECT authorId FROM posts LIMIT 5)",
+			maxRows:  100,
+			expected: "SELECT * FROM users WHERE id IN (SELECT authorId FROM posts LIMIT 5) LIMIT 100",
+		},
+		{
+			name:     "set operation",
+			input:    "SELECT name FROM users UNION SELECT title FROM posts",
+			maxRows:  100,
+			expected: "SELECT name FROM users UNION SELECT title FROM posts LIMIT 100",
+		},
+		{
+			name:     "writes are not limited",
+			input:    "delete from users where id = 1",
+			maxRows:  100,
+			expected: "DELETE FROM users WHERE id = 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := Normalize(tt.input, tt.args, tt.maxRows, tt.quotes)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, query.SQL)
+			assert.Equal(t, tt.boundArgs, query.Args)
+			assert.NotNil(t, query.Statement)
+		})
+	}
+}
+
+func TestNormalizeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		args  []any
+		err   string
+	}{
+		{"empty", " ; ", nil, "empty statement"},
+		{"multiple statements", "SELECT * FROM users; DROP TABLE users", nil, "single statement"},
+		{"trailing tokens", "SELECT * FROM users WHERE id = 1 )", nil, "unbalanced parentheses"},
+		{"unparsed tokens", "(SELECT * FROM users) LIMIT 5", nil, "after the statement"},
+		{"subquery in function arguments", "SELECT coalesce((SELECT password FROM secrets), name FROM users", nil, "unbalanced parentheses"},
+		{"illegal character", "SELECT * FROM `users`", nil, "unexpected character"},
+		{"missing parameters", "SELECT * FROM users WHERE id = ?", nil, "expected 1 parameters, got 0"},
+		{"parse error", "SELECT * FROM users WHERE", nil, "parse errors"},
+		{"quoted keyword", `SELECT "from" FROM users`, nil, "must be a plain name"},
+		{"quoted identifier with spaces", `SELECT "first name" FROM users`, nil, "must be a plain name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Normalize(tt.input, tt.args, 0, DoubleQuotedIdentifiers)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func TestTableNames(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"SELECT * FROM users", []string{"users"}},
+		{"SELECT u.name, p.title FROM users u JOIN posts p ON p.authorId = u.id", []string{"users", "posts"}},
+		{"SELECT * FROM users WHERE id IN (SELECT authorId FROM posts WHERE EXISTS (SELECT 1 FROM tags))", []string{"users", "posts", "tags"}},
+		{"SELECT name FROM users UNION SELECT title FROM posts", []string{"users", "posts"}},
+		{"SELECT * FROM users ORDER BY CASE WHEN EXISTS (SELECT 1 FROM secrets) THEN 1 ELSE 2 END", []string{"users", "secrets"}},
+		{"UPDATE users SET name = 'x' WHERE id IN (SELECT authorId FROM posts)", []string{"users", "posts"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			stmt, err := NewParser(tt.input).Parse()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, TableNames(stmt))
+		})
+	}
+}
@@ -13,6 +13,10 @@ type Parser struct {
 	curToken  Token
 	peekToken Token
 
+	// lastOperand is the most recent unparenthesized SELECT of a set operation, whose
+	// trailing ORDER BY and LIMIT apply to the combined result
+	lastOperand *SelectStatement
+
 	errors []string
 }
 
@@ -62,8 +66,8 @@ func (p *Parser) Parse() (SQLStatement, error) {
 	var stmt SQLStatement
 
 	switch p.curToken.Type {
-	case TokenSelect:
-		stmt = p.parseSelectStatement()
+	case TokenSelect, TokenLParen:
+		stmt = p.parseQueryExpression()
 	case TokenInsert:
 		stmt = p.parseInsertStatement()
 	case TokenUpdate:
@@ -81,6 +85,105 @@ func (p *Parser) Parse() (SQLStatement, error) {
 	return stmt, nil
 }
 
+// parseQueryExpression parses a SELECT optionally combined with other queries by UNION,
+// INTERSECT or EXCEPT. INTERSECT binds tighter than UNION and EXCEPT, which associate left.
+func (p *Parser) parseQueryExpression() SQLStatement {
+	left := p.parseIntersectExpression()
+	for left != nil && (p.curToken.Type == TokenUnion || p.curToken.Type == TokenExcept) {
+		operator := SetOperatorUnion
+		if p.curToken.Type == TokenExcept {
+			operator = SetOperatorExcept
+		}
+		left = p.parseSetOperation(left, operator, p.parseIntersectExpression)
+	}
+
+	set, ok := left.(*SetOperationStatement)
+	if !ok {
+		return left
+	}
+
+	// ORDER BY and LIMIT after an unparenthesized last query belong to the combined result
+	if last := p.lastOperand; last != nil {
+		set.OrderBy, set.Limit, set.Offset = last.OrderBy, last.Limit, last.Offset
+		last.OrderBy, last.Limit, last.Offset = nil, nil, nil
+	}
+	for {
+		switch p.curToken.Type {
+		case TokenOrder:
+			p.nextToken()
+			if !p.expectToken(TokenBy) {
+				return nil
+			}
+			set.OrderBy = p.parseOrderByClause()
+		case TokenLimit:
+			p.nextToken()
+			set.Limit = p.parseLimit()
+		case TokenOffset:
+			p.nextToken()
+			set.Offset = p.parseOffset()
+		default:
+			return set
+		}
+	}
+}
+
+// parseIntersectExpression parses queries combined by INTERSECT
+func (p *Parser) parseIntersectExpression() SQLStatement {
+	left := p.parseSetOperand()
+	for left != nil && p.curToken.Type == TokenIntersect {
+		left = p.parseSetOperation(left, SetOperatorIntersect, p.parseSetOperand)
+	}
+	return left
+}
+
+// parseSetOperation parses the operator keyword, optional ALL/DISTINCT and right operand
+func (p *Parser) parseSetOperation(left SQLStatement, operator SetOperator, parseOperand func() SQLStatement) SQLStatement {
+	p.nextToken() // consume UNION, INTERSECT or EXCEPT
+	all := false
+	switch p.curToken.Type {
+	case TokenAll:
+		all = true
+		p.nextToken()
+	case TokenDistinct:
+		p.nextToken()
+	}
+
+	right := parseOperand()
+	if right == nil {
+		return nil
+	}
+	return &SetOperationStatement{Left: left, Operator: operator, All: all, Right: right}
+}
+
+// parseSetOperand parses a SELECT or a parenthesized query expression
+func (p *Parser) parseSetOperand() SQLStatement {
+	if p.curToken.Type == TokenLParen {
+		p.nextToken()
+		stmt := p.parseQueryExpression()
+		if stmt == nil || !p.expectToken(TokenRParen) {
+			return nil
+		}
+		p.lastOperand = nil
+		return stmt
+	}
+
+	stmt := p.parseSelectStatement()
+	if stmt == nil {
+		return nil
+	}
+	p.lastOperand = stmt
+	return stmt
+}
+
+// isSetOperatorToken reports whether the current token starts a set operation
+func (p *Parser) isSetOperatorToken() bool {
+	switch p.curToken.Type {
+	case TokenUnion, TokenIntersect, TokenExcept:
+		return true
+	}
+	return false
+}
+
 // parseSelectStatement parses a SELECT statement
 func (p *Parser) parseSelectStatement() *SelectStatement {
 	stmt := &SelectStatement{}
@@ -107,7 +210,7 @@ func (p *Parser) parseSelectStatement() *SelectStatement {
 	stmt.Joins = p.parseJoinClauses()
 
 	// Parse optional clauses
-	for p.curToken.Type != TokenEOF && p.curToken.Type != TokenSemicolon && p.curToken.Type != TokenRParen {
+	for p.curToken.Type != TokenEOF && p.curToken.Type != TokenSemicolon && p.curToken.Type != TokenRParen && !p.isSetOperatorToken() {
 		switch p.curToken.Type {
 		case TokenWhere:
 			p.nextToken()
@@ -165,6 +268,20 @@ func (p *Parser) parseSelectFields() []SelectField {
 func (p *Parser) parseSelectField() SelectField {
 	field := SelectField{}
 
+	// Constants such as SELECT 1 in EXISTS subqueries
+	if p.curToken.Type == TokenInt || p.curToken.Type == TokenFloat || p.curToken.Type == TokenString {
+		field.Expression = p.curToken.Literal
+		p.nextToken()
+		if p.curToken.Type == TokenAs {
+			p.nextToken()
+			if p.curToken.Type == TokenIdent {
+				field.Alias = p.curToken.Literal
+				p.nextToken()
+			}
+		}
+		return field
+	}
+
 	if p.curToken.Type == TokenIdent {
 		fieldName := p.curToken.Literal
 		p.nextToken()
@@ -205,6 +322,15 @@ func (p *Parser) parseSelectField() SelectField {
 			}
 
 			field.Expression = functionCall
+
+			// Window function: FUNC(...) OVER (...)
+			if p.curToken.Type == TokenOver {
+				field.Window = p.parseWindow(functionCall)
+				if field.Window == nil {
+					return field
+				}
+				field.Expression = field.Window.String()
+			}
 		} else {
 			field.Expression = fieldName
 		}
@@ -226,6 +352,43 @@ func (p *Parser) parseSelectField() SelectField {
 	return field
 }
 
+// parseWindow parses the OVER clause following the window function call
+func (p *Parser) parseWindow(functionCall string) *WindowSpec {
+	function := NewParser(functionCall).parseExpression()
+	if function == nil || function.Function == "" {
+		p.addError(fmt.Sprintf("invalid window function: %s", functionCall))
+		return nil
+	}
+	window := &WindowSpec{Function: function}
+
+	p.nextToken() // consume OVER
+	if !p.expectToken(TokenLParen) {
+		return nil
+	}
+	if p.curToken.Type == TokenPartition {
+		p.nextToken()
+		if !p.expectToken(TokenBy) {
+			return nil
+		}
+		window.PartitionBy = p.parseGroupByClause()
+		if len(window.PartitionBy) == 0 {
+			p.addError(fmt.Sprintf("expected column after PARTITION BY, got %s", p.curToken.Type.String()))
+			return nil
+		}
+	}
+	if p.curToken.Type == TokenOrder {
+		p.nextToken()
+		if !p.expectToken(TokenBy) {
+			return nil
+		}
+		window.OrderBy = p.parseOrderByClause()
+	}
+	if !p.expectToken(TokenRParen) {
+		return nil
+	}
+	return window
+}
+
 // parseTableRef parses a table reference
 func (p *Parser) parseTableRef() TableRef {
 	ref := TableRef{}
@@ -294,6 +457,9 @@ func (p *Parser) parseAndExpression() *WhereClause {
 func (p *Parser) parseNotExpression() *WhereClause {
 	if p.curToken.Type == TokenNot {
 		p.nextToken()
+		if p.curToken.Type == TokenExists {
+			return p.parseExistsExpression("NOT EXISTS")
+		}
 		expr := p.parseComparisonExpression()
 		return &WhereClause{
 			Operator: "NOT",
@@ -306,6 +472,10 @@ func (p *Parser) parseNotExpression() *WhereClause {
 
 // parseComparisonExpression parses comparison expressions
 func (p *Parser) parseComparisonExpression() *WhereClause {
+	if p.curToken.Type == TokenExists {
+		return p.parseExistsExpression("EXISTS")
+	}
+
 	if p.curToken.Type == TokenLParen {
 		p.nextToken()
 		expr := p.parseOrExpression()
@@ -384,6 +554,19 @@ func (p *Parser) parseComparisonExpression() *WhereClause {
 	return &WhereClause{Condition: condition}
 }
 
+// parseExistsExpression parses EXISTS (SELECT ...) with the given operator
+func (p *Parser) parseExistsExpression(operator string) *WhereClause {
+	p.nextToken() // consume EXISTS
+	if !p.expectToken(TokenLParen) {
+		return nil
+	}
+	subquery := p.parseSelectStatement()
+	if subquery == nil || !p.expectToken(TokenRParen) {
+		return nil
+	}
+	return &WhereClause{Condition: &Condition{Operator: operator, Subquery: subquery}}
+}
+
 // parseCondition parses a single condition
 func (p *Parser) parseCondition(field string) *Condition {
 	condition := &Condition{Field: field}
@@ -417,8 +600,16 @@ func (p *Parser) parseCondition(field string) *Condition {
 		condition.Operator = "LIKE"
 		p.nextToken()
 		condition.Value = p.parseValue()
-	case TokenIn:
+	case TokenIn, TokenNot:
 		condition.Operator = "IN"
+		if p.curToken.Type == TokenNot {
+			p.nextToken()
+			if p.curToken.Type != TokenIn {
+				p.addError(fmt.Sprintf("expected IN after NOT, got %s", p.curToken.Type.String()))
+				return nil
+			}
+			condition.Operator = "NOT IN"
+		}
 		p.nextToken()
 		// Check if this is a subquery or value list
 		if p.curToken.Type == TokenLParen && p.peekToken.Type == TokenSelect {
@@ -534,66 +725,199 @@ func (p *Parser) parseInValues() []any {
 
 // parseOrderByClause parses ORDER BY clause
 func (p *Parser) parseOrderByClause() []*OrderByClause {
-	var clauses []*OrderByClause
+	clauses := []*OrderByClause{p.parseOrderByItem()}
 
+	// Parse additional ORDER BY fields
+	for p.curToken.Type == TokenComma {
+		p.nextToken()
+		clauses = append(clauses, p.parseOrderByItem())
+	}
+
+	return clauses
+}
+
+// parseOrderByItem parses a single ORDER BY term with its optional direction
+func (p *Parser) parseOrderByItem() *OrderByClause {
 	clause := &OrderByClause{}
+
+	if expr := p.parseExpression(); expr != nil {
+		clause.Field = expr.String()
+		// Plain column references are described by Field alone
+		if expr.Field == "" {
+			clause.Expr = expr
+		}
+	}
+
+	// Check for ASC/DESC
 	if p.curToken.Type == TokenIdent {
-		clause.Field = p.curToken.Literal
+		if strings.ToUpper(p.curToken.Literal) == "ASC" {
+			clause.Direction = OrderDirectionAsc
+			p.nextToken()
+		} else if strings.ToUpper(p.curToken.Literal) == "DESC" {
+			clause.Direction = OrderDirectionDesc
+			p.nextToken()
+		}
+	}
+
+	return clause
+}
+
+// parseExpression parses additive arithmetic (+, -) over terms
+func (p *Parser) parseExpression() *Expression {
+	left := p.parseTerm()
+	for left != nil && (p.curToken.Type == TokenPlus || p.curToken.Type == TokenMinus) {
+		operator := p.curToken.Literal
+		p.nextToken()
+		right := p.parseTerm()
+		if right == nil {
+			p.addError(fmt.Sprintf("expected expression after %s", operator))
+			return nil
+		}
+		left = &Expression{Operator: operator, Left: left, Right: right}
+	}
+	return left
+}
+
+// parseTerm parses multiplicative arithmetic (*, /, %) over primary expressions
+func (p *Parser) parseTerm() *Expression {
+	left := p.parsePrimaryExpression()
+	for left != nil && (p.curToken.Type == TokenStar || p.curToken.Type == TokenSlash || p.curToken.Type == TokenPercent) {
+		operator := p.curToken.Literal
+		p.nextToken()
+		right := p.parsePrimaryExpression()
+		if right == nil {
+			p.addError(fmt.Sprintf("expected expression after %s", operator))
+			return nil
+		}
+		left = &Expression{Operator: operator, Left: left, Right: right}
+	}
+	return left
+}
+
+// parsePrimaryExpression parses a column reference, function call, literal or
+// parenthesized expression; it returns nil without consuming anything for other tokens
+func (p *Parser) parsePrimaryExpression() *Expression {
+	switch p.curToken.Type {
+	case TokenIdent:
+		name := p.curToken.Literal
 		p.nextToken()
 
 		// Check for qualified name (table.column)
 		if p.curToken.Type == TokenDot {
 			p.nextToken() // consume dot
 			if p.curToken.Type == TokenIdent {
-				clause.Field += "." + p.curToken.Literal
+				name += "." + p.curToken.Literal
 				p.nextToken()
 			}
 		}
 
-		// Check for ASC/DESC
-		if p.curToken.Type == TokenIdent {
-			if strings.ToUpper(p.curToken.Literal) == "ASC" {
-				clause.Direction = OrderDirectionAsc
-				p.nextToken()
-			} else if strings.ToUpper(p.curToken.Literal) == "DESC" {
-				clause.Direction = OrderDirectionDesc
+		if p.curToken.Type != TokenLParen {
+			return &Expression{Field: name}
+		}
+
+		// Function call like COUNT(*) or LOWER(name)
+		expr := &Expression{Function: name}
+		p.nextToken() // consume '('
+		for p.curToken.Type != TokenRParen && p.curToken.Type != TokenEOF {
+			if p.curToken.Type == TokenStar {
+				expr.Args = append(expr.Args, &Expression{Field: "*"})
 				p.nextToken()
+			} else {
+				arg := p.parseExpression()
+				if arg == nil {
+					p.addError(fmt.Sprintf("unexpected token in %s arguments: %s", name, p.curToken.Type.String()))
+					return nil
+				}
+				expr.Args = append(expr.Args, arg)
+			}
+
+			if p.curToken.Type != TokenComma {
+				break
 			}
+			p.nextToken()
 		}
-	}
-	clauses = append(clauses, clause)
+		if !p.expectToken(TokenRParen) {
+			return nil
+		}
+		return expr
 
-	// Parse additional ORDER BY fields
-	for p.curToken.Type == TokenComma {
+	case TokenLParen:
 		p.nextToken()
-		clause := &OrderByClause{}
-		if p.curToken.Type == TokenIdent {
-			clause.Field = p.curToken.Literal
-			p.nextToken()
+		expr := p.parseExpression()
+		if expr == nil || !p.expectToken(TokenRParen) {
+			return nil
+		}
+		return expr
 
-			// Check for qualified name (table.column)
-			if p.curToken.Type == TokenDot {
-				p.nextToken() // consume dot
-				if p.curToken.Type == TokenIdent {
-					clause.Field += "." + p.curToken.Literal
-					p.nextToken()
-				}
-			}
+	case TokenCase:
+		return p.parseCaseExpression()
 
+	case TokenInt, TokenFloat, TokenString, TokenNull, TokenTrue, TokenFalse, TokenQuestion:
+		return &Expression{Value: p.parseValue()}
+	}
+
+	return nil
+}
+
+// parseCaseExpression parses CASE WHEN ... THEN ... [ELSE ...] END. The simple form
+// CASE x WHEN v THEN ... is converted to equality conditions on x.
+func (p *Parser) parseCaseExpression() *Expression {
+	p.nextToken() // consume CASE
+
+	// Simple CASE compares a column against each WHEN value
+	operand := ""
+	if p.curToken.Type == TokenIdent {
+		operand = p.curToken.Literal
+		p.nextToken()
+		if p.curToken.Type == TokenDot {
+			p.nextToken()
 			if p.curToken.Type == TokenIdent {
-				if strings.ToUpper(p.curToken.Literal) == "ASC" {
-					clause.Direction = OrderDirectionAsc
-					p.nextToken()
-				} else if strings.ToUpper(p.curToken.Literal) == "DESC" {
-					clause.Direction = OrderDirectionDesc
-					p.nextToken()
-				}
+				operand += "." + p.curToken.Literal
+				p.nextToken()
 			}
 		}
-		clauses = append(clauses, clause)
 	}
 
-	return clauses
+	expr := &Expression{}
+	for p.curToken.Type == TokenWhen {
+		p.nextToken()
+
+		var condition *WhereClause
+		if operand != "" {
+			condition = &WhereClause{Condition: NewCondition(operand, "=", p.parseValue())}
+		} else {
+			condition = p.parseWhereClause()
+		}
+		if condition == nil || !p.expectToken(TokenThen) {
+			return nil
+		}
+
+		result := p.parseExpression()
+		if result == nil {
+			p.addError("expected expression after THEN")
+			return nil
+		}
+		expr.Whens = append(expr.Whens, &WhenClause{Condition: condition, Result: result})
+	}
+
+	if len(expr.Whens) == 0 {
+		p.addError("CASE requires at least one WHEN clause")
+		return nil
+	}
+
+	if p.curToken.Type == TokenElse {
+		p.nextToken()
+		expr.Else = p.parseExpression()
+		if expr.Else == nil {
+			p.addError("expected expression after ELSE")
+			return nil
+		}
+	}
+
+	if !p.expectToken(TokenEnd) {
+		return nil
+	}
+	return expr
 }
 
 // parseGroupByClause parses GROUP BY clause
@@ -791,7 +1115,7 @@ func (p *Parser) parseUpdateStatement() *UpdateStatement {
 		return nil
 	}
 
-	stmt.Set = p.parseSetClause()
+	p.parseSetClause(stmt)
 
 	// Parse WHERE clause (optional)
 	if p.curToken.Type == TokenWhere {
@@ -803,31 +1127,42 @@ func (p *Parser) parseUpdateStatement() *UpdateStatement {
 }
 
 // parseSetClause parses SET clause for UPDATE
-func (p *Parser) parseSetClause() map[string]any {
-	setMap := make(map[string]any)
+func (p *Parser) parseSetClause(stmt *UpdateStatement) {
+	stmt.Set = make(map[string]any)
 
-	// Parse first assignment
-	if p.curToken.Type == TokenIdent {
-		field := p.curToken.Literal
+	p.parseAssignment(stmt)
+	for p.curToken.Type == TokenComma {
 		p.nextToken()
-		if p.expectToken(TokenEqual) {
-			setMap[field] = p.parseValue()
-		}
+		p.parseAssignment(stmt)
 	}
+}
 
-	// Parse additional assignments
-	for p.curToken.Type == TokenComma {
-		p.nextToken()
-		if p.curToken.Type == TokenIdent {
-			field := p.curToken.Literal
-			p.nextToken()
-			if p.expectToken(TokenEqual) {
-				setMap[field] = p.parseValue()
-			}
-		}
+// parseAssignment parses a single column = value assignment of a SET clause
+func (p *Parser) parseAssignment(stmt *UpdateStatement) {
+	if p.curToken.Type != TokenIdent {
+		return
+	}
+	field := p.curToken.Literal
+	p.nextToken()
+	if !p.expectToken(TokenEqual) {
+		return
+	}
+
+	expr := p.parseExpression()
+	if expr == nil {
+		p.addError(fmt.Sprintf("expected value for %s", field))
+		return
 	}
 
-	return setMap
+	if _, exists := stmt.Set[field]; !exists {
+		stmt.Columns = append(stmt.Columns, field)
+	}
+	// Literals are stored as plain values, anything computed as an expression
+	if expr.Field == "" && expr.Function == "" && expr.Operator == "" && len(expr.Whens) == 0 {
+		stmt.Set[field] = expr.Value
+	} else {
+		stmt.Set[field] = expr
+	}
 }
 
 // parseDeleteStatement parses DELETE statement
@@ -38,6 +38,11 @@ func TestDetectSQL(t *testing.T) {
 			input:    `{"operation": "find", "collection": "users"}`,
 			expected: false,
 		},
+		{
+			name:     "Parenthesized set operation",
+			input:    "(SELECT id FROM a) UNION (SELECT id FROM b)",
+			expected: true,
+		},
 		{
 			name:     "Empty string",
 			input:    "",
@@ -109,6 +114,35 @@ func TestParseSelectStatement(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "SELECT with ORDER BY expressions",
+			input: "SELECT role, COUNT(*) AS total FROM users GROUP BY role ORDER BY COUNT(*) DESC, (age + 1) * 2, u.name, 2",
+			expected: &SelectStatement{
+				Fields: []SelectField{
+					{Expression: "role"},
+					{Expression: "COUNT(*)", Alias: "total"},
+				},
+				From:    TableRef{Table: "users"},
+				GroupBy: []string{"role"},
+				OrderBy: []*OrderByClause{
+					{
+						Field:     "COUNT(*)",
+						Direction: OrderDirectionDesc,
+						Expr:      &Expression{Function: "COUNT", Args: []*Expression{{Field: "*"}}},
+					},
+					{
+						Field: "(age + 1) * 2",
+						Expr: &Expression{
+							Operator: "*",
+							Left:     &Expression{Operator: "+", Left: &Expression{Field: "age"}, Right: &Expression{Value: int64(1)}},
+							Right:    &Expression{Value: int64(2)},
+						},
+					},
+					{Field: "u.name"},
+					{Field: "2", Expr: &Expression{Value: int64(2)}},
+				},
+			},
+		},
 		{
 			name:  "SELECT with LIMIT",
 			input: "SELECT * FROM users LIMIT 10",
@@ -260,6 +294,31 @@ func TestParseUpdateStatement(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "UPDATE with expressions",
+			input: "UPDATE posts SET views = views + 1, status = CASE WHEN views > ? THEN 'hot' ELSE 'new' END WHERE id = 1",
+			expected: &UpdateStatement{
+				Table: "posts",
+				Set: map[string]any{
+					"views": &Expression{Operator: "+", Left: &Expression{Field: "views"}, Right: &Expression{Value: int64(1)}},
+					"status": &Expression{
+						Whens: []*WhenClause{{
+							Condition: &WhereClause{Condition: &Condition{Field: "views", Operator: ">", Value: "?"}},
+							Result:    &Expression{Value: "hot"},
+						}},
+						Else: &Expression{Value: "new"},
+					},
+				},
+				Columns: []string{"views", "status"},
+				Where: &WhereClause{
+					Condition: &Condition{
+						Field:    "id",
+						Operator: "=",
+						Value:    int64(1),
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -273,6 +332,9 @@ func TestParseUpdateStatement(t *testing.T) {
 
 			assert.Equal(t, tt.expected.Table, updateStmt.Table)
 			assert.Equal(t, tt.expected.Set, updateStmt.Set)
+			if tt.expected.Columns != nil {
+				assert.Equal(t, tt.expected.Columns, updateStmt.Columns)
+			}
 
 			if tt.expected.Where != nil {
 				require.NotNil(t, updateStmt.Where)
@@ -367,6 +429,22 @@ func TestParseComplexWhereClause(t *testing.T) {
 			name:  "IS NOT NULL condition",
 			input: "SELECT * FROM users WHERE email IS NOT NULL",
 		},
+		{
+			name:  "NOT IN condition",
+			input: "SELECT * FROM users WHERE id NOT IN (1, 2, 3)",
+		},
+		{
+			name:  "EXISTS subquery",
+			input: "SELECT * FROM users u WHERE EXISTS (SELECT 1 FROM posts p WHERE p.user_id = u.id)",
+		},
+		{
+			name:  "NOT EXISTS subquery",
+			input: "SELECT * FROM users u WHERE active = true AND NOT EXISTS (SELECT 1 FROM posts p WHERE p.user_id = u.id)",
+		},
+		{
+			name:  "NOT IN subquery",
+			input: "SELECT * FROM users WHERE id NOT IN (SELECT user_id FROM posts)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -382,6 +460,97 @@ func TestParseComplexWhereClause(t *testing.T) {
 	}
 }
 
+func TestParseExistsCondition(t *testing.T) {
+	stmt, err := NewParser("SELECT * FROM users u WHERE NOT EXISTS (SELECT 1 FROM posts p WHERE p.user_id = u.id)").Parse()
+	require.NoError(t, err)
+
+	where := stmt.(*SelectStatement).Where
+	require.NotNil(t, where.Condition)
+	assert.Equal(t, "NOT EXISTS", where.Condition.Operator)
+	require.NotNil(t, where.Condition.Subquery)
+	assert.Equal(t, "posts", where.Condition.Subquery.From.Table)
+	assert.Equal(t, []SelectField{{Expression: "1"}}, where.Condition.Subquery.Fields)
+	assert.Equal(t, "u.id", where.Condition.Subquery.Where.Condition.Value)
+}
+
+func TestParseWindowFunctions(t *testing.T) {
+	stmt, err := NewParser("SELECT title, ROW_NUMBER() OVER (PARTITION BY user_id, p.category ORDER BY views DESC, id) AS rn, SUM(views) OVER () total FROM posts p").Parse()
+	require.NoError(t, err)
+
+	fields := stmt.(*SelectStatement).Fields
+	require.Len(t, fields, 3)
+	assert.Nil(t, fields[0].Window)
+
+	rn := fields[1]
+	require.NotNil(t, rn.Window)
+	assert.Equal(t, "rn", rn.Alias)
+	assert.Equal(t, "ROW_NUMBER", rn.Window.Function.Function)
+	assert.Equal(t, []string{"user_id", "p.category"}, rn.Window.PartitionBy)
+	require.Len(t, rn.Window.OrderBy, 2)
+	assert.Equal(t, OrderDirectionDesc, rn.Window.OrderBy[0].Direction)
+	assert.Equal(t, "ROW_NUMBER() OVER (PARTITION BY user_id, p.category ORDER BY views DESC, id)", rn.Expression)
+
+	total := fields[2]
+	require.NotNil(t, total.Window)
+	assert.Equal(t, "total", total.Alias)
+	assert.Equal(t, "SUM(views) OVER ()", total.Expression)
+
+	_, err = NewParser("SELECT ROW_NUMBER() OVER (ORDER BY id FROM posts").Parse()
+	assert.Error(t, err)
+}
+
+func TestParseSetOperations(t *testing.T) {
+	t.Run("UNION ALL with trailing ORDER BY and LIMIT", func(t *testing.T) {
+		stmt, err := NewParser("SELECT name FROM users WHERE age > ? UNION ALL SELECT title FROM posts ORDER BY name LIMIT 5").Parse()
+		require.NoError(t, err)
+
+		set, ok := stmt.(*SetOperationStatement)
+		require.True(t, ok)
+		assert.Equal(t, SetOperatorUnion, set.Operator)
+		assert.True(t, set.All)
+		assert.Equal(t, StatementTypeSelect, set.GetType())
+		assert.Equal(t, "users", set.GetTableName())
+		require.Len(t, set.OrderBy, 1)
+		assert.Equal(t, "name", set.OrderBy[0].Field)
+		assert.Equal(t, 5, *set.Limit)
+
+		right := set.Right.(*SelectStatement)
+		assert.Equal(t, "posts", right.From.Table)
+		assert.Nil(t, right.OrderBy)
+		assert.Nil(t, right.Limit)
+	})
+
+	t.Run("INTERSECT binds tighter than UNION", func(t *testing.T) {
+		stmt, err := NewParser("SELECT id FROM a UNION SELECT id FROM b INTERSECT SELECT id FROM c EXCEPT SELECT id FROM d").Parse()
+		require.NoError(t, err)
+
+		except := stmt.(*SetOperationStatement)
+		assert.Equal(t, SetOperatorExcept, except.Operator)
+		union := except.Left.(*SetOperationStatement)
+		assert.Equal(t, SetOperatorUnion, union.Operator)
+		assert.False(t, union.All)
+		intersect := union.Right.(*SetOperationStatement)
+		assert.Equal(t, SetOperatorIntersect, intersect.Operator)
+		assert.Equal(t, "c", intersect.Right.GetTableName())
+	})
+
+	t.Run("parenthesized queries", func(t *testing.T) {
+		stmt, err := NewParser("(SELECT id FROM a ORDER BY id LIMIT 1) UNION (SELECT id FROM b) ORDER BY id DESC").Parse()
+		require.NoError(t, err)
+
+		set := stmt.(*SetOperationStatement)
+		left := set.Left.(*SelectStatement)
+		assert.Equal(t, 1, *left.Limit)
+		require.Len(t, set.OrderBy, 1)
+		assert.Equal(t, OrderDirectionDesc, set.OrderBy[0].Direction)
+	})
+
+	t.Run("missing right query", func(t *testing.T) {
+		_, err := NewParser("SELECT id FROM a UNION").Parse()
+		assert.Error(t, err)
+	})
+}
+
 // Helper functions
 
 func intPtr(i int) *int {
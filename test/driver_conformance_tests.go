@@ -32,6 +32,13 @@ type DriverCharacteristics struct {
 
 	// AutoIncrementIntegerType is the SQL type for an auto-incrementing integer primary key
 	AutoIncrementIntegerType string
+
+	// MaxOpenConnections is the size of the connection pool, 0 when it is not bounded
+	MaxOpenConnections int
+
+	// SlowQuery is a raw query running for seconds, to cancel while it runs. Empty skips
+	// the cancellation of running queries.
+	SlowQuery string
 }
 
 // DriverConformanceTests provides a comprehensive test suite for database drivers
@@ -71,6 +78,7 @@ func (dct *DriverConformanceTests) RunAll(t *testing.T) {
 		t.Run("Insert", dct.TestInsert)
 		t.Run("InsertWithDefaults", dct.TestInsertWithDefaults)
 		t.Run("InsertWithAutoIncrement", dct.TestInsertWithAutoIncrement)
+		t.Run("InsertWithGeneratedUUID", dct.TestInsertWithGeneratedUUID)
 		t.Run("EmptyInsert", dct.TestEmptyInsert)
 		t.Run("Select", dct.TestSelect)
 		t.Run("SelectWithFields", dct.TestSelectWithFields)
@@ -87,10 +95,13 @@ func (dct *DriverConformanceTests) RunAll(t *testing.T) {
 		t.Run("WhereComparisons", dct.TestWhereComparisons)
 		t.Run("WhereIn", dct.TestWhereIn)
 		t.Run("WhereNotIn", dct.TestWhereNotIn)
+		t.Run("WhereInBeyondParameterLimit", dct.TestWhereInBeyondParameterLimit)
+		t.Run("MergedListsBeyondParameterLimit", dct.TestMergedListsBeyondParameterLimit)
 		t.Run("WhereLike", dct.TestWhereLike)
 		t.Run("WhereNull", dct.TestWhereNull)
 		t.Run("WhereBetween", dct.TestWhereBetween)
 		t.Run("ComplexWhereConditions", dct.TestComplexWhereConditions)
+		t.Run("BooleanConditionHelpers", dct.TestBooleanConditionHelpers)
 	})
 
 	// Advanced Queries
@@ -106,6 +117,7 @@ func (dct *DriverConformanceTests) RunAll(t *testing.T) {
 		t.Run("Count", dct.TestCount)
 		t.Run("Aggregations", dct.TestAggregations)
 		t.Run("Include", dct.TestInclude)
+		t.Run("IncludeIntoStructs", dct.TestIncludeIntoStructs)
 		t.Run("ComplexQueries", dct.TestComplexQueries)
 	})
 
@@ -122,11 +134,22 @@ func (dct *DriverConformanceTests) RunAll(t *testing.T) {
 		t.Run("TransactionConcurrentAccess", dct.TestTransactionConcurrentAccess)
 	})
 
+	// Concurrency
+	t.Run("Concurrency", func(t *testing.T) {
+		t.Run("ConcurrentWriters", dct.TestConcurrentWriters)
+		t.Run("ConcurrentReadersAndWriters", dct.TestConcurrentReadersAndWriters)
+		t.Run("ConnectionPoolExhaustion", dct.TestConnectionPoolExhaustion)
+		t.Run("ContextCancellation", dct.TestContextCancellation)
+		t.Run("LongTransaction", dct.TestLongTransaction)
+	})
+
 	// Field Mapping
 	t.Run("FieldMapping", func(t *testing.T) {
 		t.Run("FieldNameMapping", dct.TestFieldNameMapping)
 		t.Run("TableNameMapping", dct.TestTableNameMapping)
 		t.Run("MapAnnotations", dct.TestMapAnnotations)
+		t.Run("ReservedWordIdentifiers", dct.TestReservedWordIdentifiers)
+		t.Run("MixedCaseIdentifiers", dct.TestMixedCaseIdentifiers)
 	})
 
 	// Data Types
@@ -154,8 +177,13 @@ func (dct *DriverConformanceTests) RunAll(t *testing.T) {
 		t.Run("RawSelect", dct.TestRawSelect)
 		t.Run("RawInsert", dct.TestRawInsert)
 		t.Run("RawUpdate", dct.TestRawUpdate)
+		t.Run("RawUpdateExpressions", dct.TestRawUpdateExpressions)
 		t.Run("RawDelete", dct.TestRawDelete)
 		t.Run("RawWithParameters", dct.TestRawWithParameters)
+		t.Run("RawNamedAndListParameters", dct.TestRawNamedAndListParameters)
+		t.Run("RawExistsSubqueries", dct.TestRawExistsSubqueries)
+		t.Run("RawSetOperations", dct.TestRawSetOperations)
+		t.Run("RawWindowFunctions", dct.TestRawWindowFunctions)
 		t.Run("RawQueryErrorHandling", dct.TestRawQueryErrorHandling)
 		t.Run("RawQueryWithDifferentDataTypes", dct.TestRawQueryWithDifferentDataTypes)
 		t.Run("RawQueryComplexQueries", dct.TestRawQueryComplexQueries)
@@ -181,6 +209,7 @@ func (dct *DriverConformanceTests) RunAll(t *testing.T) {
 		t.Run("DriverConfig", dct.TestDriverConfig)
 		t.Run("FieldTypeMapping", dct.TestFieldTypeMapping)
 		t.Run("GenerateColumnSQL", dct.TestGenerateColumnSQL)
+		t.Run("Logging", dct.TestLogging)
 	})
 
 	// Migration
@@ -196,6 +225,7 @@ func (dct *DriverConformanceTests) RunAll(t *testing.T) {
 		t.Run("GenerateDropIndexSQL", dct.TestGenerateDropIndexSQL)
 		t.Run("ApplyMigration", dct.TestApplyMigration)
 		t.Run("MigrationWorkflow", dct.TestMigrationWorkflow)
+		t.Run("ReservedWordMigration", dct.TestReservedWordMigration)
 	})
 
 }
@@ -534,7 +564,6 @@ func (dct *DriverConformanceTests) TestInsert(t *testing.T) {
 	}).Exec(ctx)
 	assert.NoError(t, err)
 	// Only check LastInsertID if the driver supports it
-	// PostgreSQL and MongoDB don't support LastInsertId - they return 0
 	if dct.Characteristics.SupportsLastInsertID {
 		assert.Greater(t, result.LastInsertID, int64(0))
 	}
@@ -617,6 +646,78 @@ func (dct *DriverConformanceTests) TestInsertWithAutoIncrement(t *testing.T) {
 	assert.Greater(t, ids[2], ids[1])
 }
 
+func (dct *DriverConformanceTests) TestInsertWithGeneratedUUID(t *testing.T) {
+	if dct.shouldSkip("TestInsertWithGeneratedUUID") {
+		t.Skip("Test skipped by driver")
+	}
+
+	td := dct.createTestDB(t)
+	defer td.Cleanup()
+
+	// model Account {
+	//   id       String    @id @default(uuid())
+	//   name     String
+	//   sessions Session[]
+	// }
+	// model Session {
+	//   id        String  @id @default(uuid())
+	//   accountId String
+	//   account   Account @relation(fields: [accountId], references: [id])
+	// }
+	accountSchema := schema.New("Account").
+		AddField(schema.Field{Name: "id", Type: schema.FieldTypeString, PrimaryKey: true, Default: "UUID()"}).
+		AddField(schema.Field{Name: "name", Type: schema.FieldTypeString})
+	sessionSchema := schema.New("Session").
+		AddField(schema.Field{Name: "id", Type: schema.FieldTypeString, PrimaryKey: true, Default: "UUID()"}).
+		AddField(schema.Field{Name: "accountId", Type: schema.FieldTypeString})
+	accountSchema.AddRelation("sessions", schema.Relation{
+		Type:       schema.RelationOneToMany,
+		Model:      "Session",
+		ForeignKey: "accountId",
+		References: "id",
+	})
+	sessionSchema.AddRelation("account", schema.Relation{
+		Type:       schema.RelationManyToOne,
+		Model:      "Account",
+		ForeignKey: "accountId",
+		References: "id",
+	})
+
+	ctx := context.Background()
+	require.NoError(t, td.DB.RegisterSchema("Account", accountSchema))
+	require.NoError(t, td.DB.RegisterSchema("Session", sessionSchema))
+	require.NoError(t, td.DB.SyncSchemas(ctx))
+
+	// The primary key is generated and reported
+	Account := td.DB.Model("Account")
+	result, err := Account.Insert(map[string]any{"name": "Alice"}).Exec(ctx)
+	require.NoError(t, err)
+	id, ok := result.InsertedID()
+	require.True(t, ok)
+	require.IsType(t, "", id)
+	assert.Len(t, id, 36)
+
+	var account map[string]any
+	err = Account.Select().WhereCondition(Account.Where("id").Equals(id)).FindFirst(ctx, &account)
+	require.NoError(t, err)
+	assert.Equal(t, id, account["id"])
+	assert.Equal(t, "Alice", account["name"])
+
+	// Explicit keys are kept
+	explicitID := "2b4f9c3e-8d1a-4f6b-9c2d-7e5a3b1c0d9f"
+	result, err = Account.Insert(map[string]any{"id": explicitID, "name": "Bob"}).Exec(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []any{explicitID}, result.InsertedIDs)
+
+	// Foreign keys hold the generated keys they reference
+	Session := td.DB.Model("Session")
+	_, err = Session.Insert(map[string]any{"accountId": id}).Exec(ctx)
+	require.NoError(t, err)
+	count, err := Session.Select().WhereCondition(Session.Where("accountId").Equals(id)).Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
 func (dct *DriverConformanceTests) TestSelect(t *testing.T) {
 	if dct.shouldSkip("TestSelect") {
 		t.Skip("Test skipped by driver")
@@ -0,0 +1,282 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rediwo/redi-orm/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ===== Concurrency Tests =====
+
+func (dct *DriverConformanceTests) TestConcurrentWriters(t *testing.T) {
+	if dct.shouldSkip("TestConcurrentWriters") {
+		t.Skip("Test skipped by driver")
+	}
+
+	td := dct.createTestDB(t)
+	defer td.Cleanup()
+
+	err := td.CreateStandardSchemas()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	const writers, usersPerWriter = 8, 10
+
+	// Writers insert on their own connections at the same time
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*usersPerWriter)
+	for w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range usersPerWriter {
+				_, err := td.DB.Model("User").Insert(map[string]any{
+					"name":   fmt.Sprintf("Writer%d-%d", w, i),
+					"email":  fmt.Sprintf("writer%d-%d@example.com", w, i),
+					"active": true,
+				}).Exec(ctx)
+				if err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err, "Concurrent inserts should not fail")
+	}
+	td.AssertCount("User", writers*usersPerWriter)
+}
+
+func (dct *DriverConformanceTests) TestConcurrentReadersAndWriters(t *testing.T) {
+	if dct.shouldSkip("TestConcurrentReadersAndWriters") {
+		t.Skip("Test skipped by driver")
+	}
+
+	td := dct.createTestDB(t)
+	defer td.Cleanup()
+
+	err := td.CreateStandardSchemas()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	const users, readers = 40, 4
+
+	// Readers count users while a writer inserts them; counts never go back
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	readErrs := make([]error, readers)
+	for r := range readers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var last int64
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				count, err := td.DB.Model("User").Select().Count(ctx)
+				if err != nil {
+					readErrs[r] = err
+					return
+				}
+				if count < last {
+					readErrs[r] = fmt.Errorf("count went back from %d to %d", last, count)
+					return
+				}
+				last = count
+
+				var found []TestUser
+				if err := td.DB.Model("User").Select().Limit(5).FindMany(ctx, &found); err != nil {
+					readErrs[r] = err
+					return
+				}
+			}
+		}()
+	}
+
+	for i := range users {
+		_, err := td.DB.Model("User").Insert(map[string]any{
+			"name":   fmt.Sprintf("User%d", i),
+			"email":  fmt.Sprintf("user%d@example.com", i),
+			"active": true,
+		}).Exec(ctx)
+		assert.NoError(t, err)
+	}
+	close(done)
+	wg.Wait()
+
+	for _, err := range readErrs {
+		assert.NoError(t, err, "Reads concurrent with writes should not fail")
+	}
+	td.AssertCount("User", users)
+}
+
+func (dct *DriverConformanceTests) TestConnectionPoolExhaustion(t *testing.T) {
+	if dct.shouldSkip("TestConnectionPoolExhaustion") {
+		t.Skip("Test skipped by driver")
+	}
+
+	td := dct.createTestDB(t)
+	defer td.Cleanup()
+
+	err := td.CreateStandardSchemas()
+	require.NoError(t, err)
+	err = td.InsertStandardTestData()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// More transactions than the pool holds wait for a connection instead of failing
+	transactions := 50
+	if dct.Characteristics.MaxOpenConnections > 0 {
+		transactions = 2 * dct.Characteristics.MaxOpenConnections
+	}
+	var wg sync.WaitGroup
+	errs := make(chan error, transactions)
+	for range transactions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- td.DB.Transaction(ctx, func(tx types.Transaction) error {
+				_, err := tx.Model("User").Select().Count(ctx)
+				time.Sleep(10 * time.Millisecond)
+				return err
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err, "Transactions waiting for a connection should not fail")
+	}
+
+	if dct.Characteristics.MaxOpenConnections == 0 {
+		return
+	}
+
+	// With every connection held by a transaction, a query waits until its context is done
+	var held []types.Transaction
+	defer func() {
+		for _, tx := range held {
+			tx.Rollback(ctx)
+		}
+	}()
+	for range dct.Characteristics.MaxOpenConnections {
+		tx, err := td.DB.Begin(ctx)
+		require.NoError(t, err)
+		held = append(held, tx)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer cancel()
+	_, err = td.DB.Model("User").Select().Count(waitCtx)
+	assert.Error(t, err, "A query should fail when no connection frees up before its deadline")
+
+	// Releasing a connection lets queries run again
+	require.NoError(t, held[0].Rollback(ctx))
+	held = held[1:]
+	count, err := td.DB.Model("User").Select().Count(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), count)
+}
+
+func (dct *DriverConformanceTests) TestContextCancellation(t *testing.T) {
+	if dct.shouldSkip("TestContextCancellation") {
+		t.Skip("Test skipped by driver")
+	}
+
+	td := dct.createTestDB(t)
+	defer td.Cleanup()
+
+	err := td.CreateStandardSchemas()
+	require.NoError(t, err)
+	err = td.InsertStandardTestData()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// Queries with a canceled context fail without running
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	var users []TestUser
+	err = td.DB.Model("User").Select().FindMany(canceled, &users)
+	assert.Error(t, err, "A query with a canceled context should fail")
+	_, err = td.DB.Model("User").Insert(map[string]any{
+		"name":   "Canceled",
+		"email":  "canceled@example.com",
+		"active": true,
+	}).Exec(canceled)
+	assert.Error(t, err, "An insert with a canceled context should fail")
+	td.AssertNotExists("User", td.DB.Model("User").Where("email").Equals("canceled@example.com"))
+
+	// A running query stops when its context is canceled
+	if dct.Characteristics.SlowQuery != "" {
+		queryCtx, cancel := context.WithCancel(ctx)
+		time.AfterFunc(100*time.Millisecond, cancel)
+
+		start := time.Now()
+		var result []map[string]any
+		err = td.DB.Raw(dct.Characteristics.SlowQuery).Find(queryCtx, &result)
+		assert.Error(t, err, "A query should fail when its context is canceled")
+		assert.Less(t, time.Since(start), 3*time.Second, "A canceled query should stop early")
+	}
+
+	// The connections remain usable
+	count, err := td.DB.Model("User").Select().Count(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), count)
+}
+
+func (dct *DriverConformanceTests) TestLongTransaction(t *testing.T) {
+	if dct.shouldSkip("TestLongTransaction") {
+		t.Skip("Test skipped by driver")
+	}
+
+	td := dct.createTestDB(t)
+	defer td.Cleanup()
+
+	err := td.CreateStandardSchemas()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	const users = 5
+
+	// A transaction stays usable across statements spread over time, and its changes are
+	// hidden from other connections until it commits
+	tx, err := td.DB.Begin(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx)
+
+	for i := range users {
+		_, err := tx.Model("User").Insert(map[string]any{
+			"name":   fmt.Sprintf("Slow%d", i),
+			"email":  fmt.Sprintf("slow%d@example.com", i),
+			"active": true,
+		}).Exec(ctx)
+		require.NoError(t, err)
+
+		count, err := td.DB.Model("User").Select().Count(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), count, "Uncommitted inserts should not be visible outside the transaction")
+
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	count, err := tx.Model("User").Select().Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(users), count)
+
+	require.NoError(t, tx.Commit(ctx))
+	td.AssertCount("User", users)
+}
@@ -1,10 +1,14 @@
 package test
 
 import (
+	"bytes"
 	"context"
+	"io"
+	"os"
 	"strings"
 	"testing"
 
+	"github.com/rediwo/redi-orm/logger"
 	"github.com/rediwo/redi-orm/schema"
 	"github.com/rediwo/redi-orm/utils"
 	"github.com/stretchr/testify/assert"
@@ -148,10 +152,8 @@ func (dct *DriverConformanceTests) TestGenerateColumnSQL(t *testing.T) {
 				}).Exec(ctx)
 				assert.NoError(t, err)
 
-				// Check if auto-increment worked (except PostgreSQL)
-				if !dct.Characteristics.SupportsLastInsertID {
-					assert.Equal(t, int64(0), result.LastInsertID) // PostgreSQL returns 0
-				} else {
+				// Check if auto-increment worked
+				if dct.Characteristics.SupportsLastInsertID {
 					assert.Greater(t, result.LastInsertID, int64(0))
 				}
 
@@ -284,3 +286,53 @@ func (dct *DriverConformanceTests) TestGenerateColumnSQL(t *testing.T) {
 		})
 	}
 }
+
+func (dct *DriverConformanceTests) TestLogging(t *testing.T) {
+	if dct.shouldSkip("TestLogging") {
+		t.Skip("Test skipped by driver")
+	}
+
+	td := dct.createTestDB(t)
+	defer td.Cleanup()
+
+	var logs bytes.Buffer
+	l := logger.NewDefaultLogger("Test")
+	l.SetOutput(&logs)
+	l.SetLevel(logger.LogLevelDebug)
+	td.DB.SetLogger(l)
+	defer td.DB.SetLogger(nil)
+
+	// Capture stdout while the driver works, which must only write to the logger
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	os.Stdout = w
+	captured := make(chan string)
+	go func() {
+		var out bytes.Buffer
+		io.Copy(&out, r)
+		captured <- out.String()
+	}()
+
+	func() {
+		defer func() { os.Stdout = stdout }()
+
+		require.NoError(t, td.CreateStandardSchemas())
+		require.NoError(t, td.InsertStandardTestData())
+
+		ctx := context.Background()
+		User := td.DB.Model("User")
+		var users []TestUser
+		err := User.Select().
+			WhereCondition(User.Where("name").In("Alice", "Bob").
+				And(User.Where("name").NotIn("Charlie")).
+				And(User.Where("email").Contains("@"))).
+			FindMany(ctx, &users)
+		assert.NoError(t, err)
+		assert.Len(t, users, 2)
+	}()
+	w.Close()
+
+	assert.Empty(t, <-captured, "Driver should not write to stdout")
+	assert.Contains(t, logs.String(), "["+td.DB.GetDriverType()+"]", "Driver logs should name the driver")
+}
@@ -380,3 +380,52 @@ func (dct *DriverConformanceTests) TestMigrationWorkflow(t *testing.T) {
 	// Clean up
 	_, _ = td.DB.Exec("DROP TABLE products")
 }
+
+func (dct *DriverConformanceTests) TestReservedWordMigration(t *testing.T) {
+	if dct.shouldSkip("TestReservedWordMigration") {
+		t.Skip("Test skipped by driver")
+	}
+
+	td := dct.createTestDB(t)
+	defer td.Cleanup()
+
+	migrator := td.DB.GetMigrator()
+
+	// A table named by a keyword, with keyword and mixed-case columns
+	orderSchema := schema.New("Order").
+		AddField(schema.Field{Name: "id", Type: schema.FieldTypeInt, PrimaryKey: true, AutoIncrement: true}).
+		AddField(schema.Field{Name: "group", Type: schema.FieldTypeString}).
+		AddField(schema.Field{Name: "select", Type: schema.FieldTypeInt, Nullable: true}).
+		AddField(schema.Field{Name: "userName", Type: schema.FieldTypeString, Map: "userName"})
+	orderSchema.TableName = "order"
+
+	createSQL, err := migrator.GenerateCreateTableSQL(orderSchema)
+	require.NoError(t, err)
+	require.NoError(t, migrator.ApplyMigration(createSQL))
+
+	addColumnSQL, err := migrator.GenerateAddColumnSQL("order", schema.Field{Name: "where", Type: schema.FieldTypeString, Nullable: true})
+	require.NoError(t, err)
+	require.NoError(t, migrator.ApplyMigration(addColumnSQL))
+
+	indexSQL := migrator.GenerateCreateIndexSQL("order", "group", []string{"group", "userName"}, false)
+	require.NoError(t, migrator.ApplyMigration(indexSQL))
+
+	dropColumnSQLs, err := migrator.GenerateDropColumnSQL("order", "select")
+	require.NoError(t, err)
+	for _, sql := range dropColumnSQLs {
+		require.NoError(t, migrator.ApplyMigration(sql))
+	}
+
+	tableInfo, err := migrator.GetTableInfo("order")
+	require.NoError(t, err)
+	columnNames := make(map[string]bool)
+	for _, col := range tableInfo.Columns {
+		columnNames[col.Name] = true
+	}
+	assert.True(t, columnNames["group"])
+	assert.True(t, columnNames["userName"])
+	assert.True(t, columnNames["where"])
+	assert.False(t, columnNames["select"])
+
+	require.NoError(t, migrator.ApplyMigration(migrator.GenerateDropTableSQL("order")))
+}
@@ -2,7 +2,9 @@ package test
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/rediwo/redi-orm/schema"
 	"github.com/rediwo/redi-orm/types"
@@ -189,6 +191,148 @@ func (dct *DriverConformanceTests) TestWhereNotIn(t *testing.T) {
 	assert.Len(t, users, 3)
 }
 
+func (dct *DriverConformanceTests) TestWhereInBeyondParameterLimit(t *testing.T) {
+	if dct.shouldSkip("TestWhereInBeyondParameterLimit") {
+		t.Skip("Test skipped by driver")
+	}
+
+	td := dct.createTestDB(t)
+	defer td.Cleanup()
+
+	err := td.CreateStandardSchemas()
+	require.NoError(t, err)
+
+	err = td.InsertStandardTestData()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// More values than the driver binds in a statement, with duplicates of the matching ones
+	limit := max(td.DB.GetCapabilities().MaxParameters(), 1000)
+	names := []any{"Alice", "Bob", "Charlie", "Alice"}
+	for i := len(names); i <= limit; i++ {
+		names = append(names, fmt.Sprintf("missing-%d", i))
+	}
+	names = append(names, "Bob")
+
+	User := td.DB.Model("User")
+	var users []TestUser
+	err = User.Select().
+		WhereCondition(User.Where("name").In(names...)).
+		WhereCondition(User.Where("active").Equals(true)).
+		FindMany(ctx, &users)
+	require.NoError(t, err)
+	assert.Len(t, users, 2)
+
+	count, err := User.Select().WhereCondition(User.Where("name").In(names...)).Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	result, err := User.Update(map[string]any{"age": 40}).
+		WhereCondition(User.Where("name").In(names...)).
+		Exec(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), result.RowsAffected)
+
+	// Rows binding more parameters than the driver allows are inserted in several statements
+	Comment := td.DB.Model("Comment")
+	var comments []any
+	for i := 0; i <= limit/3; i++ {
+		comments = append(comments, map[string]any{"content": fmt.Sprintf("bulk-%d", i), "postId": 2, "userId": 5})
+	}
+	result, err = Comment.Insert(comments[0]).Values(comments[1:]...).Exec(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(comments)), result.RowsAffected)
+
+	var contents []any
+	for i := range comments {
+		contents = append(contents, fmt.Sprintf("bulk-%d", i))
+	}
+	result, err = Comment.Delete().WhereCondition(Comment.Where("content").In(contents...)).Exec(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(comments)), result.RowsAffected)
+
+	count, err = Comment.Select().Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), count)
+}
+
+func (dct *DriverConformanceTests) TestMergedListsBeyondParameterLimit(t *testing.T) {
+	if dct.shouldSkip("TestMergedListsBeyondParameterLimit") {
+		t.Skip("Test skipped by driver")
+	}
+
+	td := dct.createTestDB(t)
+	defer td.Cleanup()
+
+	err := td.CreateStandardSchemas()
+	require.NoError(t, err)
+
+	err = td.InsertStandardTestData()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// Lists padded with more values than the driver binds in a statement
+	maxParameters := td.DB.GetCapabilities().MaxParameters()
+	pad := func(values ...any) []any {
+		for i := 0; i <= max(maxParameters, 1000); i++ {
+			values = append(values, fmt.Sprintf("missing-%d", i))
+		}
+		return values
+	}
+	names := func(users []map[string]any) []string {
+		var result []string
+		for _, user := range users {
+			result = append(result, fmt.Sprint(user["name"]))
+		}
+		return result
+	}
+
+	User := td.DB.Model("User")
+	in := User.Where("name").In(pad("Alice", "Bob", "Charlie", "David")...)
+	notIn := User.Where("name").NotIn(pad("Alice")...)
+
+	// Ordered and paginated results are merged, sorted and paginated in memory
+	var users []map[string]any
+	err = User.Select("name").WhereCondition(in).OrderBy("age", types.DESC).Offset(1).Limit(2).FindMany(ctx, &users)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Bob", "Alice"}, names(users))
+	assert.Equal(t, map[string]any{"name": "Bob"}, users[0], "Fields added to merge on are left out")
+
+	var first map[string]any
+	err = User.Select("name").WhereCondition(in).OrderBy("name", types.DESC).FindFirst(ctx, &first)
+	require.NoError(t, err)
+	assert.Equal(t, "David", first["name"])
+
+	users = nil
+	err = User.Select("active").WhereCondition(in).Distinct().FindMany(ctx, &users)
+	require.NoError(t, err)
+	assert.Len(t, users, 2)
+
+	// NOT IN lists match the records of every chunk
+	users = nil
+	err = User.Select("name").WhereCondition(notIn).OrderBy("name", types.ASC).Limit(3).FindMany(ctx, &users)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Bob", "Charlie", "David"}, names(users))
+
+	count, err := User.Select().WhereCondition(notIn).Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), count)
+
+	if maxParameters == 0 {
+		return
+	}
+
+	// Statements that cannot be split fail with a clear error
+	_, err = User.Update(map[string]any{"age": 40}).WhereCondition(notIn).Exec(ctx)
+	assert.ErrorIs(t, err, types.ErrTooManyParameters)
+
+	users = nil
+	err = User.Select().WhereCondition(types.Or(in, User.Where("active").Equals(true))).FindMany(ctx, &users)
+	assert.ErrorIs(t, err, types.ErrTooManyParameters)
+}
+
 func (dct *DriverConformanceTests) TestWhereLike(t *testing.T) {
 	if dct.shouldSkip("TestWhereLike") {
 		t.Skip("Test skipped by driver")
@@ -384,6 +528,75 @@ func (dct *DriverConformanceTests) TestComplexWhereConditions(t *testing.T) {
 	assert.Greater(t, len(posts), 0)
 }
 
+func (dct *DriverConformanceTests) TestBooleanConditionHelpers(t *testing.T) {
+	if dct.shouldSkip("TestBooleanConditionHelpers") {
+		t.Skip("Test skipped by driver")
+	}
+
+	td := dct.createTestDB(t)
+	defer td.Cleanup()
+
+	err := td.CreateStandardSchemas()
+	require.NoError(t, err)
+
+	err = td.InsertStandardTestData()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	User := td.DB.Model("User")
+	names := func(query types.ModelQuery) []string {
+		t.Helper()
+		var users []TestUser
+		err := query.OrderBy("name", types.ASC).FindMany(ctx, &users)
+		require.NoError(t, err)
+		result := make([]string, len(users))
+		for i, user := range users {
+			result[i] = user.Name
+		}
+		return result
+	}
+
+	// Or, And and Not nest, ignoring nil conditions
+	assert.Equal(t, []string{"Alice", "Bob", "Eve"}, names(User.WhereCondition(types.Or(
+		User.Where("name").Equals("Alice"),
+		User.Where("name").Equals("Bob"),
+		User.Where("name").Equals("Eve"),
+	))))
+	assert.Equal(t, []string{"David", "Eve"}, names(User.WhereCondition(types.And(
+		nil,
+		User.Where("active").Equals(true),
+		types.Not(types.Or(User.Where("name").Equals("Alice"), User.Where("name").Equals("Bob"))),
+	))))
+
+	// OrWhere ORs the conditions added so far
+	assert.Equal(t, []string{"Alice", "Charlie"}, names(User.
+		WhereCondition(User.Where("age").LessThan(26)).
+		OrWhere(User.Where("name").Equals("Charlie"))))
+	assert.Equal(t, []string{"Bob"}, names(User.OrWhere(User.Where("name").Equals("Bob"))))
+
+	// WhereNot and WhereAny are ANDed with the other conditions
+	assert.Equal(t, []string{"Charlie"}, names(User.WhereNot(User.Where("active").Equals(true))))
+	assert.Equal(t, []string{"Alice", "Bob"}, names(User.
+		WhereCondition(User.Where("active").Equals(true)).
+		WhereAny(User.Where("name").Equals("Alice"), User.Where("age").GreaterThan(29))))
+
+	// OrWhere within a group only applies to the conditions of the group
+	assert.Equal(t, []string{"Alice", "Bob"}, names(User.
+		WhereCondition(User.Where("active").Equals(true)).
+		WhereGroup(func(g types.ModelQuery) types.ModelQuery {
+			return g.WhereCondition(g.Where("name").Equals("Alice")).OrWhere(g.Where("age").GreaterThan(29))
+		})))
+	assert.Equal(t, []string{"Alice", "Bob", "Charlie"}, names(User.
+		WhereCondition(User.Where("active").Equals(true)).
+		WhereCondition(User.Where("name").Equals("Alice")).
+		OrWhere(User.Where("age").GreaterThan(29))))
+
+	// Helpers carry over to the queries built from the model query
+	count, err := User.WhereNot(User.Where("active").Equals(true)).OrWhere(User.Where("name").Equals("Eve")).Select().Count(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
 // ===== Advanced Query Tests =====
 
 func (dct *DriverConformanceTests) TestOrderBy(t *testing.T) {
@@ -768,6 +981,101 @@ func (dct *DriverConformanceTests) TestInclude(t *testing.T) {
 	})
 }
 
+// TestIncludeIntoStructs tests hydrating included relations into nested structs
+func (dct *DriverConformanceTests) TestIncludeIntoStructs(t *testing.T) {
+	if dct.shouldSkip("TestIncludeIntoStructs") {
+		t.Skip("Test skipped by driver")
+	}
+
+	td := dct.createTestDB(t)
+	defer td.Cleanup()
+
+	err := td.CreateStandardSchemas()
+	require.NoError(t, err)
+
+	err = td.InsertStandardTestData()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	type Comment struct {
+		ID      int    `db:"id"`
+		Content string `db:"content"`
+	}
+	type Post struct {
+		ID       int       `db:"id"`
+		Title    string    `db:"title"`
+		UserID   int       `db:"user_id"`
+		Comments []Comment `json:"comments"`
+	}
+	type User struct {
+		ID        int       `db:"id"`
+		Name      string    `db:"name"`
+		Age       *int      `db:"age"`
+		CreatedAt time.Time `db:"created_at"`
+		Posts     []Post    `json:"posts"`
+	}
+	type PostWithAuthor struct {
+		ID    int    `db:"id"`
+		Title string `db:"title"`
+		User  *User  `json:"user"`
+	}
+
+	t.Run("One-to-many into slice of structs", func(t *testing.T) {
+		model := td.DB.Model("User")
+		var users []User
+		err := model.Select().
+			WhereCondition(model.Where("id").In(1, 2)).
+			Include("posts").
+			OrderBy("id", types.ASC).
+			FindMany(ctx, &users)
+		require.NoError(t, err)
+		require.Len(t, users, 2)
+
+		assert.Equal(t, "Alice", users[0].Name)
+		require.NotNil(t, users[0].Age)
+		assert.Equal(t, 25, *users[0].Age)
+		assert.False(t, users[0].CreatedAt.IsZero())
+		assert.Len(t, users[0].Posts, 2)
+		assert.Len(t, users[1].Posts, 2)
+		for _, post := range users[0].Posts {
+			assert.Equal(t, 1, post.UserID)
+			assert.NotEmpty(t, post.Title)
+		}
+	})
+
+	t.Run("Many-to-one into struct pointer", func(t *testing.T) {
+		Post := td.DB.Model("Post")
+		var post PostWithAuthor
+		err := Post.Select().
+			WhereCondition(Post.Where("id").Equals(3)).
+			Include("user").
+			FindFirst(ctx, &post)
+		require.NoError(t, err)
+		assert.Equal(t, "Bob's Post", post.Title)
+		require.NotNil(t, post.User)
+		assert.Equal(t, "Bob", post.User.Name)
+	})
+
+	t.Run("Nested includes into nested structs", func(t *testing.T) {
+		model := td.DB.Model("User")
+		var user User
+		err := model.Select().
+			WhereCondition(model.Where("id").Equals(1)).
+			Include("posts.comments").
+			FindFirst(ctx, &user)
+		require.NoError(t, err)
+		assert.Equal(t, "Alice", user.Name)
+		require.Len(t, user.Posts, 2)
+
+		commentCount := 0
+		for _, post := range user.Posts {
+			commentCount += len(post.Comments)
+		}
+		assert.Equal(t, 2, commentCount)
+	})
+}
+
 // ===== Complex Query Tests =====
 
 func (dct *DriverConformanceTests) TestComplexQueries(t *testing.T) {
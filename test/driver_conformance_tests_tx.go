@@ -119,18 +119,10 @@ func (dct *DriverConformanceTests) TestTransactionFunction(t *testing.T) {
 
 		// Insert post for the user
 		PostTx := tx.Model("Post")
-		var userId int64
-		if td.DB.GetDriverType() == "postgresql" {
-			// For PostgreSQL, we need to find the user ID since LastInsertId() returns 0
-			// In a real application, you'd use RETURNING clause
-			userId = 1 // Assume first user has ID 1 for this test
-		} else {
-			userId = result.LastInsertID
-		}
 		_, err = PostTx.Insert(map[string]any{
 			"title":     "Transaction Post",
 			"content":   "Created in transaction",
-			"userId":    userId,
+			"userId":    result.LastInsertID,
 			"published": true,
 		}).Exec(ctx)
 		return err
@@ -242,6 +234,13 @@ func (dct *DriverConformanceTests) TestSavepoints(t *testing.T) {
 	require.NoError(t, err)
 	defer tx.Rollback(ctx)
 
+	// Drivers without savepoints must say so instead of failing arbitrarily
+	if !td.DB.GetCapabilities().SupportsSavepoints() {
+		assert.ErrorIs(t, tx.Savepoint(ctx, "sp1"), types.ErrSavepointsNotSupported)
+		assert.ErrorIs(t, tx.RollbackTo(ctx, "sp1"), types.ErrSavepointsNotSupported)
+		return
+	}
+
 	// Insert first user
 	UserTx := tx.Model("User")
 	_, err = UserTx.Insert(map[string]any{
@@ -396,6 +395,135 @@ func (dct *DriverConformanceTests) TestMapAnnotations(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func (dct *DriverConformanceTests) TestReservedWordIdentifiers(t *testing.T) {
+	if dct.shouldSkip("TestReservedWordIdentifiers") {
+		t.Skip("Test skipped by driver")
+	}
+
+	td := dct.createTestDB(t)
+	defer td.Cleanup()
+
+	// Table, column and relation names that are SQL keywords
+	orderSchema := schema.New("Order").
+		AddField(schema.Field{Name: "id", Type: schema.FieldTypeInt, PrimaryKey: true, AutoIncrement: true}).
+		AddField(schema.Field{Name: "group", Type: schema.FieldTypeString}).
+		AddField(schema.Field{Name: "select", Type: schema.FieldTypeInt}).
+		AddRelation("lines", schema.Relation{
+			Type:       schema.RelationOneToMany,
+			Model:      "OrderLine",
+			ForeignKey: "orderId",
+			References: "id",
+		})
+	orderSchema.TableName = "order"
+	lineSchema := schema.New("OrderLine").
+		AddField(schema.Field{Name: "id", Type: schema.FieldTypeInt, PrimaryKey: true, AutoIncrement: true}).
+		AddField(schema.Field{Name: "where", Type: schema.FieldTypeString}).
+		AddField(schema.Field{Name: "orderId", Type: schema.FieldTypeInt}).
+		AddRelation("order", schema.Relation{
+			Type:       schema.RelationManyToOne,
+			Model:      "Order",
+			ForeignKey: "orderId",
+			References: "id",
+		})
+
+	require.NoError(t, td.DB.RegisterSchema("Order", orderSchema))
+	require.NoError(t, td.DB.RegisterSchema("OrderLine", lineSchema))
+
+	ctx := context.Background()
+	require.NoError(t, td.DB.CreateModel(ctx, "Order"))
+	require.NoError(t, td.DB.CreateModel(ctx, "OrderLine"))
+
+	Order := td.DB.Model("Order")
+	OrderLine := td.DB.Model("OrderLine")
+	for i, group := range []string{"a", "b", "a"} {
+		_, err := Order.Insert(map[string]any{"id": i + 1, "group": group, "select": i + 1}).Exec(ctx)
+		require.NoError(t, err)
+	}
+	_, err := OrderLine.Insert(map[string]any{"where": "here", "orderId": 1}).Exec(ctx)
+	require.NoError(t, err)
+
+	var orders []map[string]any
+	err = Order.Select("id", "group", "select").
+		WhereCondition(Order.Where("group").Equals("a")).
+		OrderBy("select", types.DESC).
+		FindMany(ctx, &orders)
+	require.NoError(t, err)
+	require.Len(t, orders, 2)
+	assert.Equal(t, "a", orders[0]["group"])
+	assert.EqualValues(t, 3, orders[0]["select"])
+
+	count, err := Order.Select().WhereCondition(Order.Where("select").GreaterThan(1)).Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	var lines []map[string]any
+	err = OrderLine.Select().Include("order").FindMany(ctx, &lines)
+	require.NoError(t, err)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "here", lines[0]["where"])
+	order, ok := lines[0]["order"].(map[string]any)
+	require.True(t, ok, "order should be included")
+	assert.Equal(t, "a", order["group"])
+
+	_, err = Order.Update(map[string]any{"group": "c"}).
+		WhereCondition(Order.Where("select").Equals(2)).
+		Exec(ctx)
+	require.NoError(t, err)
+	_, err = Order.Delete().WhereCondition(Order.Where("group").Equals("c")).Exec(ctx)
+	require.NoError(t, err)
+
+	count, err = Order.Select().Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func (dct *DriverConformanceTests) TestMixedCaseIdentifiers(t *testing.T) {
+	if dct.shouldSkip("TestMixedCaseIdentifiers") {
+		t.Skip("Test skipped by driver")
+	}
+
+	td := dct.createTestDB(t)
+	defer td.Cleanup()
+
+	// Names mapped as they are keep their case, which unquoted names lose on PostgreSQL
+	accountSchema := schema.New("Account").
+		AddField(schema.Field{Name: "id", Type: schema.FieldTypeInt, PrimaryKey: true, AutoIncrement: true}).
+		AddField(schema.Field{Name: "userName", Type: schema.FieldTypeString, Map: "userName"}).
+		AddField(schema.Field{Name: "displayName", Type: schema.FieldTypeString, Map: "DisplayName"})
+	accountSchema.TableName = "UserAccounts"
+
+	require.NoError(t, td.DB.RegisterSchema("Account", accountSchema))
+
+	ctx := context.Background()
+	require.NoError(t, td.DB.CreateModel(ctx, "Account"))
+
+	Account := td.DB.Model("Account")
+	_, err := Account.Insert(map[string]any{"userName": "alice", "displayName": "Alice"}).Exec(ctx)
+	require.NoError(t, err)
+	_, err = Account.Insert(map[string]any{"userName": "bob", "displayName": "Bob"}).Exec(ctx)
+	require.NoError(t, err)
+
+	var accounts []map[string]any
+	err = Account.Select("userName", "displayName").
+		WhereCondition(Account.Where("userName").Equals("bob")).
+		OrderBy("displayName", types.ASC).
+		FindMany(ctx, &accounts)
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	assert.Equal(t, "bob", accounts[0]["userName"])
+	assert.Equal(t, "Bob", accounts[0]["displayName"])
+
+	_, err = Account.Update(map[string]any{"displayName": "Robert"}).
+		WhereCondition(Account.Where("userName").Equals("bob")).
+		Exec(ctx)
+	require.NoError(t, err)
+
+	var account map[string]any
+	err = Account.Select().WhereCondition(Account.Where("displayName").Equals("Robert")).FindFirst(ctx, &account)
+	require.NoError(t, err)
+	assert.Equal(t, "bob", account["userName"])
+}
+
 // ===== Data Type Tests =====
 
 func (dct *DriverConformanceTests) TestIntegerTypes(t *testing.T) {
@@ -900,6 +1028,167 @@ func (dct *DriverConformanceTests) TestRawUpdate(t *testing.T) {
 	}
 }
 
+func (dct *DriverConformanceTests) TestRawUpdateExpressions(t *testing.T) {
+	if dct.shouldSkip("TestRawUpdateExpressions") {
+		t.Skip("Test skipped by driver")
+	}
+
+	td := dct.createTestDB(t)
+	defer td.Cleanup()
+
+	err := td.CreateStandardSchemas()
+	require.NoError(t, err)
+
+	err = td.InsertStandardTestData()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// Atomic increment
+	_, err = td.DB.Raw("UPDATE posts SET views = views + ? WHERE title = ?", 5, "Second Post").Exec(ctx)
+	require.NoError(t, err)
+
+	var post map[string]any
+	err = td.DB.Raw("SELECT views FROM posts WHERE title = ?", "Second Post").FindOne(ctx, &post)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), utils.ToInt64(post["views"]))
+
+	// Arithmetic and CASE expressions
+	_, err = td.DB.Raw("UPDATE posts SET views = views * 2 + 1, content = CASE WHEN views > ? THEN 'busy' ELSE 'quiet' END WHERE title = ?",
+		3, "Second Post").Exec(ctx)
+	require.NoError(t, err)
+
+	err = td.DB.Raw("SELECT views, content FROM posts WHERE title = ?", "Second Post").FindOne(ctx, &post)
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), utils.ToInt64(post["views"]))
+	assert.Equal(t, "busy", post["content"])
+}
+
+func (dct *DriverConformanceTests) TestRawExistsSubqueries(t *testing.T) {
+	if dct.shouldSkip("TestRawExistsSubqueries") {
+		t.Skip("Test skipped by driver")
+	}
+
+	td := dct.createTestDB(t)
+	defer td.Cleanup()
+
+	err := td.CreateStandardSchemas()
+	require.NoError(t, err)
+
+	err = td.InsertStandardTestData()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	names := func(results []map[string]any) []string {
+		var out []string
+		for _, r := range results {
+			out = append(out, utils.ToString(r["name"]))
+		}
+		return out
+	}
+
+	// Users with at least one published post
+	var results []map[string]any
+	err = td.DB.Raw("SELECT name FROM users u WHERE EXISTS (SELECT 1 FROM posts p WHERE p.user_id = u.id AND p.published = ?) ORDER BY name",
+		true).Find(ctx, &results)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Alice", "Bob"}, names(results))
+
+	// Users without posts
+	results = nil
+	err = td.DB.Raw("SELECT name FROM users u WHERE NOT EXISTS (SELECT 1 FROM posts p WHERE p.user_id = u.id) ORDER BY name").Find(ctx, &results)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"David", "Eve"}, names(results))
+
+	// Correlated IN comparing against an outer column
+	results = nil
+	err = td.DB.Raw("SELECT name FROM users u WHERE u.age > ? AND u.id IN (SELECT p.user_id FROM posts p WHERE p.views >= u.age) ORDER BY name",
+		26).Find(ctx, &results)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Bob"}, names(results))
+}
+
+func (dct *DriverConformanceTests) TestRawSetOperations(t *testing.T) {
+	if dct.shouldSkip("TestRawSetOperations") {
+		t.Skip("Test skipped by driver")
+	}
+
+	td := dct.createTestDB(t)
+	defer td.Cleanup()
+
+	err := td.CreateStandardSchemas()
+	require.NoError(t, err)
+
+	err = td.InsertStandardTestData()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// UNION ALL keeps every row; columns take the names of the first query
+	var results []map[string]any
+	err = td.DB.Raw("SELECT name FROM users WHERE age > ? UNION ALL SELECT title FROM posts WHERE views >= ? ORDER BY name",
+		28, 100).Find(ctx, &results)
+	require.NoError(t, err)
+	var names []string
+	for _, r := range results {
+		names = append(names, utils.ToString(r["name"]))
+	}
+	assert.Equal(t, []string{"Bob", "Charlie", "First Post", "Popular Post"}, names)
+
+	// UNION removes duplicates
+	results = nil
+	err = td.DB.Raw("SELECT user_id FROM posts UNION SELECT user_id FROM comments ORDER BY user_id").Find(ctx, &results)
+	require.NoError(t, err)
+	var ids []int64
+	for _, r := range results {
+		ids = append(ids, utils.ToInt64(r["user_id"]))
+	}
+	assert.Equal(t, []int64{1, 2, 3, 4}, ids)
+}
+
+func (dct *DriverConformanceTests) TestRawWindowFunctions(t *testing.T) {
+	if dct.shouldSkip("TestRawWindowFunctions") {
+		t.Skip("Test skipped by driver")
+	}
+
+	td := dct.createTestDB(t)
+	defer td.Cleanup()
+
+	err := td.CreateStandardSchemas()
+	require.NoError(t, err)
+
+	err = td.InsertStandardTestData()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// Rank each user's posts by views and keep a running total
+	var results []map[string]any
+	err = td.DB.Raw(`SELECT title, user_id,
+		ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY views DESC) AS rn,
+		SUM(views) OVER (PARTITION BY user_id ORDER BY views DESC) AS running
+		FROM posts ORDER BY user_id, rn`).Find(ctx, &results)
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+
+	expected := []struct {
+		title   string
+		rn      int64
+		running int64
+	}{
+		{"First Post", 1, 100},
+		{"Second Post", 2, 100},
+		{"Popular Post", 1, 1000},
+		{"Bob's Post", 2, 1050},
+		{"Charlie's Draft", 1, 0},
+	}
+	for i, e := range expected {
+		assert.Equal(t, e.title, results[i]["title"])
+		assert.Equal(t, e.rn, utils.ToInt64(results[i]["rn"]), e.title)
+		assert.Equal(t, e.running, utils.ToInt64(results[i]["running"]), e.title)
+	}
+}
+
 func (dct *DriverConformanceTests) TestRawDelete(t *testing.T) {
 	if dct.shouldSkip("TestRawDelete") {
 		t.Skip("Test skipped by driver")
@@ -950,6 +1239,45 @@ func (dct *DriverConformanceTests) TestRawWithParameters(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func (dct *DriverConformanceTests) TestRawNamedAndListParameters(t *testing.T) {
+	if dct.shouldSkip("TestRawNamedAndListParameters") {
+		t.Skip("Test skipped by driver")
+	}
+
+	td := dct.createTestDB(t)
+	defer td.Cleanup()
+
+	err := td.CreateStandardSchemas()
+	require.NoError(t, err)
+
+	err = td.InsertStandardTestData()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// Named parameters from a map
+	var results []map[string]any
+	err = td.DB.Raw("SELECT * FROM posts WHERE user_id = :userId AND published = :published ORDER BY id",
+		map[string]any{"userId": 1, "published": true}).Find(ctx, &results)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	// Slice arguments expand into IN lists
+	err = td.DB.Raw("SELECT * FROM users WHERE id IN (?) ORDER BY id", []int{1, 2}).Find(ctx, &results)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+
+	// Named slice parameters
+	err = td.DB.Raw("SELECT * FROM users WHERE name IN (:names) AND id > :minId",
+		map[string]any{"names": []string{"Alice", "Bob"}, "minId": 1}).Find(ctx, &results)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	// Missing named parameter
+	err = td.DB.Raw("SELECT * FROM users WHERE id = :id", map[string]any{"name": "Alice"}).Find(ctx, &results)
+	assert.Error(t, err)
+}
+
 // Extended Raw Query Tests
 
 func (dct *DriverConformanceTests) TestRawQueryErrorHandling(t *testing.T) {
@@ -0,0 +1,76 @@
+package testutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rediwo/redi-orm/types"
+)
+
+// UpdateSnapshotsEnv is the environment variable rewriting SQL snapshots when set to 1, to
+// accept a change of the generated SQL
+const UpdateSnapshotsEnv = "UPDATE_SNAPSHOTS"
+
+// SnapshotDir is the directory of SQL snapshots, relative to the package of the test
+var SnapshotDir = filepath.Join("testdata", "snapshots")
+
+// SQLCompiler is a query compiled to SQL without executing it, such as the query builders
+type SQLCompiler interface {
+	ToSQL() (string, []any, error)
+}
+
+// MatchSQLSnapshot compiles q without executing it and compares its SQL and arguments with
+// the snapshot name of the driver of db, stored in SnapshotDir/<driver>/<name>.sql. Missing
+// snapshots are written, so that changes of the generated SQL show up in reviews as changes
+// of the snapshots.
+func MatchSQLSnapshot(t testing.TB, db types.Database, name string, q SQLCompiler) {
+	t.Helper()
+	sql, args, err := q.ToSQL()
+	if err != nil {
+		t.Fatalf("Failed to compile %s: %v", name, err)
+	}
+	actual, err := formatSnapshot(sql, args)
+	if err != nil {
+		t.Fatalf("Failed to format snapshot %s: %v", name, err)
+	}
+
+	path := filepath.Join(SnapshotDir, db.GetDriverType(), name+".sql")
+	expected, err := os.ReadFile(path)
+	missing := errors.Is(err, fs.ErrNotExist)
+	if missing || os.Getenv(UpdateSnapshotsEnv) == "1" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(actual), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if missing {
+			t.Logf("Wrote snapshot %s", path)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("Failed to read snapshot %s: %v", path, err)
+	}
+	if string(expected) != actual {
+		t.Errorf("SQL of %s does not match snapshot %s (run with %s=1 to update it)\nexpected:\n%s\nactual:\n%s",
+			name, path, UpdateSnapshotsEnv, expected, actual)
+	}
+}
+
+// formatSnapshot returns the snapshot of a statement: its SQL, and its arguments in a comment
+func formatSnapshot(sql string, args []any) (string, error) {
+	if args == nil {
+		args = []any{}
+	}
+	encoded, err := json.Marshal(args)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s\n-- args: %s\n", sql, encoded), nil
+}
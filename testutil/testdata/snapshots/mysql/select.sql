@@ -0,0 +1,2 @@
+SELECT `u`.`id`, `u`.`email` FROM `users` AS `u` WHERE `u`.`email` LIKE ? ESCAPE '!' ORDER BY `u`.`name` ASC LIMIT 10 OFFSET 20
+-- args: ["%example%"]
@@ -0,0 +1,2 @@
+SELECT `u`.* FROM `users` AS `u` WHERE `u`.`id` IN (?,?,?)
+-- args: [1,2,3]
@@ -0,0 +1,2 @@
+INSERT INTO "users" ("email", "name") VALUES ($1, $2)
+-- args: ["alice@example.com","Alice"]
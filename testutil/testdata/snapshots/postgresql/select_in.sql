@@ -0,0 +1,2 @@
+SELECT "u".* FROM "users" AS "u" WHERE "u"."id" IN ($1,$2,$3)
+-- args: [1,2,3]
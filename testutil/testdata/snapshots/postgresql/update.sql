@@ -0,0 +1,2 @@
+UPDATE "users" SET "name" = $1 WHERE "email" = $2
+-- args: ["Bob","bob@example.com"]
@@ -0,0 +1,2 @@
+SELECT COUNT(*) AS `count` FROM `users` GROUP BY `name` ORDER BY `name` DESC
+-- args: []
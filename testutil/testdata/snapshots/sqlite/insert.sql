@@ -0,0 +1,2 @@
+INSERT INTO `users` (`email`, `name`) VALUES (?, ?)
+-- args: ["alice@example.com","Alice"]
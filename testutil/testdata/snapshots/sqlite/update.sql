@@ -0,0 +1,2 @@
+UPDATE `users` SET `name` = ? WHERE `email` = ?
+-- args: ["Bob","bob@example.com"]
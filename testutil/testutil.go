@@ -0,0 +1,242 @@
+// Package testutil starts MySQL, PostgreSQL and MongoDB in Docker containers for integration
+// tests, and returns them connected with the models of a schema created:
+//
+//	func TestUsers(t *testing.T) {
+//		db := testutil.PostgreSQL(t, testutil.Options{SchemaPath: "../schema.prisma"})
+//		// ... the container is removed when the test ends
+//	}
+//
+// BeginTestTransaction runs the queries of a test in a transaction rolled back when it ends,
+// so that tests sharing a database leave no records behind. MatchSQLSnapshot compares the SQL
+// generated for a query by each driver with snapshots reviewed along with code changes.
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/rediwo/redi-orm/database"
+	_ "github.com/rediwo/redi-orm/drivers/mongodb"
+	_ "github.com/rediwo/redi-orm/drivers/mysql"
+	_ "github.com/rediwo/redi-orm/drivers/postgresql"
+	"github.com/rediwo/redi-orm/migration"
+	"github.com/rediwo/redi-orm/prisma"
+	"github.com/rediwo/redi-orm/types"
+	"github.com/testcontainers/testcontainers-go"
+	tcmongodb "github.com/testcontainers/testcontainers-go/modules/mongodb"
+	tcmysql "github.com/testcontainers/testcontainers-go/modules/mysql"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+)
+
+// Credentials of the databases in the containers
+const (
+	DatabaseName = "redi_test"
+	Username     = "redi"
+	Password     = "redi"
+)
+
+// DefaultImages are the images of the containers by driver
+var DefaultImages = map[types.DriverType]string{
+	types.DriverMySQL:      "mysql:8.0",
+	types.DriverPostgreSQL: "postgres:16-alpine",
+	types.DriverMongoDB:    "mongo:7",
+}
+
+// Options configures a database started in a container
+type Options struct {
+	Image         string // Image of the container, DefaultImages by default
+	Schema        string // Prisma schema source whose models are registered
+	SchemaPath    string // Prisma schema file or directory whose models are registered
+	MigrationsDir string // Migration files applied instead of creating the tables of the models
+}
+
+// Container is a database running in a Docker container
+type Container struct {
+	DB  types.Database
+	URI string
+
+	container testcontainers.Container
+}
+
+// Start starts a container of driverType, connects to its database, registers the models of
+// opts and creates their tables, or applies opts.MigrationsDir. Terminate removes the
+// container.
+func Start(ctx context.Context, driverType types.DriverType, opts Options) (*Container, error) {
+	image := opts.Image
+	if image == "" {
+		image = DefaultImages[driverType]
+	}
+
+	c := &Container{}
+	var err error
+	switch driverType {
+	case types.DriverMySQL:
+		var ctr *tcmysql.MySQLContainer
+		ctr, err = tcmysql.Run(ctx, image,
+			tcmysql.WithDatabase(DatabaseName), tcmysql.WithUsername(Username), tcmysql.WithPassword(Password))
+		if ctr != nil {
+			c.container = ctr
+		}
+		if err == nil {
+			c.URI, err = endpointURI(ctx, ctr, "3306/tcp", "mysql", "")
+		}
+	case types.DriverPostgreSQL:
+		var ctr *tcpostgres.PostgresContainer
+		ctr, err = tcpostgres.Run(ctx, image,
+			tcpostgres.WithDatabase(DatabaseName), tcpostgres.WithUsername(Username), tcpostgres.WithPassword(Password),
+			tcpostgres.BasicWaitStrategies())
+		if ctr != nil {
+			c.container = ctr
+		}
+		if err == nil {
+			c.URI, err = endpointURI(ctx, ctr, "5432/tcp", "postgresql", "sslmode=disable")
+		}
+	case types.DriverMongoDB:
+		// A replica set of one member, for transactions. The member is reached directly
+		// because its address is only known inside Docker.
+		var ctr *tcmongodb.MongoDBContainer
+		ctr, err = tcmongodb.Run(ctx, image, tcmongodb.WithReplicaSet("rs0"))
+		if ctr != nil {
+			c.container = ctr
+		}
+		if err == nil {
+			c.URI, err = endpointURI(ctx, ctr, "27017/tcp", "mongodb", "directConnection=true")
+		}
+	default:
+		return nil, fmt.Errorf("no container for driver %s", driverType)
+	}
+	if err != nil {
+		c.Terminate(ctx)
+		return nil, fmt.Errorf("failed to start %s container: %w", driverType, err)
+	}
+
+	if err := c.open(ctx, opts); err != nil {
+		c.Terminate(ctx)
+		return nil, err
+	}
+	return c, nil
+}
+
+// Terminate closes the database and removes its container
+func (c *Container) Terminate(ctx context.Context) error {
+	if c.DB != nil {
+		c.DB.Close()
+	}
+	if c.container == nil {
+		return nil
+	}
+	return c.container.Terminate(ctx)
+}
+
+// MySQL starts a MySQL container for the test, removed when it ends. The test is skipped
+// when Docker is not available.
+func MySQL(t testing.TB, opts Options) types.Database {
+	return start(t, types.DriverMySQL, opts)
+}
+
+// PostgreSQL starts a PostgreSQL container for the test, removed when it ends. The test is
+// skipped when Docker is not available.
+func PostgreSQL(t testing.TB, opts Options) types.Database {
+	return start(t, types.DriverPostgreSQL, opts)
+}
+
+// MongoDB starts a MongoDB replica set container for the test, removed when it ends. The
+// test is skipped when Docker is not available.
+func MongoDB(t testing.TB, opts Options) types.Database {
+	return start(t, types.DriverMongoDB, opts)
+}
+
+// start starts a container for the test, skipping it without Docker
+func start(t testing.TB, driverType types.DriverType, opts Options) types.Database {
+	t.Helper()
+	ctx := context.Background()
+	if err := dockerHealth(ctx); err != nil {
+		t.Skipf("Docker is not available: %v", err)
+	}
+
+	c, err := Start(ctx, driverType, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := c.Terminate(context.Background()); err != nil {
+			t.Logf("Failed to remove %s container: %v", driverType, err)
+		}
+	})
+	return c.DB
+}
+
+// dockerHealth returns why Docker cannot run containers, or nil
+func dockerHealth(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	provider, err := testcontainers.ProviderDocker.GetProvider()
+	if err != nil {
+		return err
+	}
+	defer provider.Close()
+	return provider.Health(ctx)
+}
+
+// open connects to the database of the container and creates the models of opts
+func (c *Container) open(ctx context.Context, opts Options) error {
+	db, err := database.NewFromURI(c.URI)
+	if err != nil {
+		return err
+	}
+	if err := db.Connect(ctx); err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", c.URI, err)
+	}
+	c.DB = db
+
+	if opts.Schema != "" {
+		if err := db.LoadSchema(ctx, opts.Schema); err != nil {
+			return fmt.Errorf("failed to load schema: %w", err)
+		}
+	}
+	if opts.SchemaPath != "" {
+		schemas, err := prisma.LoadSchemaFromPath(opts.SchemaPath)
+		if err != nil {
+			return fmt.Errorf("failed to load schema: %w", err)
+		}
+		for name, s := range schemas {
+			if err := db.RegisterSchema(name, s); err != nil {
+				return fmt.Errorf("failed to register model %s: %w", name, err)
+			}
+		}
+	}
+
+	if opts.MigrationsDir != "" {
+		runner, err := migration.New(db, types.MigrationOptions{Mode: types.MigrationModeFile, MigrationsDir: opts.MigrationsDir})
+		if err != nil {
+			return err
+		}
+		if _, err := runner.Run(ctx, nil); err != nil {
+			return fmt.Errorf("failed to apply migrations: %w", err)
+		}
+		return nil
+	}
+	if err := db.SyncSchemas(ctx); err != nil {
+		return fmt.Errorf("failed to create tables: %w", err)
+	}
+	return nil
+}
+
+// endpointURI returns the URI of the database of a container, reached at its mapped port
+func endpointURI(ctx context.Context, ctr testcontainers.Container, port nat.Port, scheme, query string) (string, error) {
+	endpoint, err := ctr.PortEndpoint(ctx, port, "")
+	if err != nil {
+		return "", err
+	}
+	u := url.URL{Scheme: scheme, Host: endpoint, Path: "/" + DatabaseName, RawQuery: query}
+	if scheme != "mongodb" {
+		u.User = url.UserPassword(Username, Password)
+	}
+	return u.String(), nil
+}
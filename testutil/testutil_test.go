@@ -0,0 +1,93 @@
+package testutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rediwo/redi-orm/migration"
+	"github.com/rediwo/redi-orm/types"
+)
+
+const testSchema = `
+model User {
+  id    Int    @id @default(autoincrement())
+  email String @unique
+  name  String
+}
+`
+
+func TestContainers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping container tests in short mode")
+	}
+
+	for name, start := range map[string]func(testing.TB, Options) types.Database{
+		"MySQL":      MySQL,
+		"PostgreSQL": PostgreSQL,
+		"MongoDB":    MongoDB,
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			db := start(t, Options{Schema: testSchema})
+
+			// The tables of the models are created
+			_, err := db.Model("User").Insert(map[string]any{"email": "alice@example.com", "name": "Alice"}).Exec(ctx)
+			if err != nil {
+				t.Fatalf("Failed to insert: %v", err)
+			}
+			count, err := db.Model("User").Select().Count(ctx)
+			if err != nil || count != 1 {
+				t.Errorf("Expected 1 user, got %d, %v", count, err)
+			}
+
+			// Transactions work, including on the MongoDB replica set
+			err = db.Transaction(ctx, func(tx types.Transaction) error {
+				_, err := tx.Model("User").Insert(map[string]any{"email": "bob@example.com", "name": "Bob"}).Exec(ctx)
+				return err
+			})
+			if err != nil {
+				t.Errorf("Transaction failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestMigrationsDir(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping container tests in short mode")
+	}
+
+	dir := t.TempDir()
+	err := migration.NewFileManager(dir).WriteMigration(&types.MigrationFile{
+		Version: "20240101000000",
+		Name:    "create_users",
+		UpSQL:   "CREATE TABLE users (id SERIAL PRIMARY KEY, email TEXT NOT NULL UNIQUE, name TEXT NOT NULL);",
+		DownSQL: "DROP TABLE users;",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	db := PostgreSQL(t, Options{Schema: testSchema, MigrationsDir: dir})
+	tables, err := db.GetMigrator().GetTables()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, table := range tables {
+		found = found || table == "users"
+	}
+	if !found {
+		t.Errorf("Expected the migration to create users, got %v", tables)
+	}
+	if _, err := db.Model("User").Insert(map[string]any{"email": "alice@example.com", "name": "Alice"}).Exec(ctx); err != nil {
+		t.Errorf("Failed to insert into the migrated table: %v", err)
+	}
+}
+
+func TestStartUnsupportedDriver(t *testing.T) {
+	if _, err := Start(context.Background(), types.DriverSQLite, Options{}); err == nil {
+		t.Error("Expected an error for a driver without container")
+	}
+}
@@ -0,0 +1,104 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/rediwo/redi-orm/types"
+)
+
+// TestTransactionDB is a database whose queries run in a transaction rolled back when the
+// test ends, so that the test leaves no records behind. Transactions begun on it are
+// savepoints of that transaction.
+type TestTransactionDB struct {
+	types.Database // The database of the transaction
+
+	tx         types.Transaction
+	mu         sync.Mutex
+	savepoints int
+}
+
+// BeginTestTransaction begins a transaction on db for the test and returns a database running
+// every query in it. The transaction is rolled back when the test and its subtests end.
+// Tables must exist before: schema changes cannot be made within the transaction.
+func BeginTestTransaction(t testing.TB, db types.Database) *TestTransactionDB {
+	t.Helper()
+	ctx := context.Background()
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Failed to begin test transaction: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := tx.Rollback(context.Background()); err != nil {
+			t.Errorf("Failed to roll back test transaction: %v", err)
+		}
+	})
+
+	// The database of queries built from the transaction
+	withDB, ok := tx.Model("").(interface{ GetDatabase() types.Database })
+	if !ok {
+		t.Fatalf("Test transactions are not supported by %s", db.GetDriverType())
+	}
+	return &TestTransactionDB{Database: withDB.GetDatabase(), tx: tx}
+}
+
+// Begin creates a savepoint of the test transaction. Committing the returned transaction
+// keeps its changes in the test transaction, and rolling it back undoes them.
+func (db *TestTransactionDB) Begin(ctx context.Context) (types.Transaction, error) {
+	db.mu.Lock()
+	db.savepoints++
+	name := fmt.Sprintf("test_tx_%d", db.savepoints)
+	db.mu.Unlock()
+
+	if err := db.tx.Savepoint(ctx, name); err != nil {
+		return nil, fmt.Errorf("failed to begin nested test transaction: %w", err)
+	}
+	return &testSavepoint{Transaction: db.tx, name: name}, nil
+}
+
+// Transaction runs fn in a savepoint of the test transaction, rolled back when fn returns an
+// error or panics
+func (db *TestTransactionDB) Transaction(ctx context.Context, fn func(tx types.Transaction) error) (err error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback(ctx)
+			panic(r)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			return fmt.Errorf("transaction failed: %w, rollback failed: %v", err, rbErr)
+		}
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// Close does nothing: the test transaction is rolled back when the test ends, and db is
+// closed by whoever opened it
+func (db *TestTransactionDB) Close() error {
+	return nil
+}
+
+// testSavepoint is a transaction begun within a test transaction
+type testSavepoint struct {
+	types.Transaction // The test transaction
+	name              string
+}
+
+// Commit keeps the changes made since the savepoint
+func (sp *testSavepoint) Commit(ctx context.Context) error {
+	return nil
+}
+
+// Rollback undoes the changes made since the savepoint
+func (sp *testSavepoint) Rollback(ctx context.Context) error {
+	return sp.Transaction.RollbackTo(ctx, sp.name)
+}
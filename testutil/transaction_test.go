@@ -0,0 +1,86 @@
+package testutil
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/rediwo/redi-orm/database"
+	_ "github.com/rediwo/redi-orm/drivers/sqlite"
+	"github.com/rediwo/redi-orm/types"
+)
+
+func TestBeginTestTransaction(t *testing.T) {
+	ctx := context.Background()
+	db, err := database.NewFromURI("sqlite://" + filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	if err := db.Connect(ctx); err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer db.Close()
+	if err := db.LoadSchema(ctx, testSchema); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SyncSchemas(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	insert := func(db types.Database, email string) error {
+		_, err := db.Model("User").Insert(map[string]any{"email": email, "name": email}).Exec(ctx)
+		return err
+	}
+	count := func(t *testing.T, db types.Database, expected int64) {
+		t.Helper()
+		n, err := db.Model("User").Select().Count(ctx)
+		if err != nil || n != expected {
+			t.Errorf("Expected %d users, got %d, %v", expected, n, err)
+		}
+	}
+
+	t.Run("Test", func(t *testing.T) {
+		txDB := BeginTestTransaction(t, db)
+		if err := insert(txDB, "alice@example.com"); err != nil {
+			t.Fatal(err)
+		}
+
+		// Failed inner transactions only undo their own changes
+		err := txDB.Transaction(ctx, func(tx types.Transaction) error {
+			if _, err := tx.Model("User").Insert(map[string]any{"email": "bob@example.com", "name": "Bob"}).Exec(ctx); err != nil {
+				return err
+			}
+			return errors.New("failed")
+		})
+		if err == nil {
+			t.Error("Expected the error of the inner transaction")
+		}
+		count(t, txDB, 1)
+
+		// Committed inner transactions keep their changes in the test transaction
+		tx, err := txDB.Begin(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tx.Model("User").Insert(map[string]any{"email": "carol@example.com", "name": "Carol"}).Exec(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			t.Fatal(err)
+		}
+		count(t, txDB, 2)
+
+		// Raw queries run in the test transaction too
+		var users []map[string]any
+		if err := txDB.Raw("SELECT email FROM users").Find(ctx, &users); err != nil || len(users) != 2 {
+			t.Errorf("Expected 2 users from a raw query, got %v, %v", users, err)
+		}
+		if err := txDB.Close(); err != nil {
+			t.Errorf("Close() error = %v", err)
+		}
+	})
+
+	// The test left nothing behind, and db is still open
+	count(t, db, 0)
+}
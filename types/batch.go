@@ -0,0 +1,79 @@
+package types
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type batchingKey struct{}
+
+// batching holds the lookups of a context waiting to load in batches
+type batching struct {
+	window  time.Duration
+	mu      sync.Mutex
+	batches map[string]*batch
+}
+
+// batch is the lookups of one model by one field, loaded once the window closes
+type batch struct {
+	values  []any
+	done    chan struct{}
+	records map[string]any
+	err     error
+}
+
+// WithBatching returns a context in which concurrent unique lookups of a model by the same
+// field, started within window of the first, load together with one IN query
+func WithBatching(ctx context.Context, window time.Duration) context.Context {
+	return context.WithValue(ctx, batchingKey{}, &batching{window: window, batches: make(map[string]*batch)})
+}
+
+// Batching reports whether ctx batches unique lookups
+func Batching(ctx context.Context) bool {
+	_, ok := ctx.Value(batchingKey{}).(*batching)
+	return ok
+}
+
+// BatchLoad returns the record of modelName whose field holds value, or nil when there is
+// none. load loads the records of values, keyed by their value formatted with fmt.Sprint; in
+// a context of WithBatching it runs once for the values of all the lookups of a batch.
+func BatchLoad(ctx context.Context, modelName, field string, value any, load func(values []any) (map[string]any, error)) (any, error) {
+	b, ok := ctx.Value(batchingKey{}).(*batching)
+	if !ok {
+		records, err := load([]any{value})
+		if err != nil {
+			return nil, err
+		}
+		return records[fmt.Sprint(value)], nil
+	}
+
+	key := modelName + "." + field
+	b.mu.Lock()
+	current, ok := b.batches[key]
+	if !ok {
+		current = &batch{done: make(chan struct{})}
+		b.batches[key] = current
+		time.AfterFunc(b.window, func() {
+			b.mu.Lock()
+			delete(b.batches, key)
+			b.mu.Unlock()
+
+			current.records, current.err = load(current.values)
+			close(current.done)
+		})
+	}
+	current.values = append(current.values, value)
+	b.mu.Unlock()
+
+	select {
+	case <-current.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if current.err != nil {
+		return nil, current.err
+	}
+	return copyResult(current.records[fmt.Sprint(value)]), nil
+}
@@ -0,0 +1,41 @@
+package types
+
+import (
+	"context"
+	"time"
+)
+
+// ChangeOperation represents the kind of row-level change captured by a change stream
+type ChangeOperation string
+
+const (
+	ChangeCreate ChangeOperation = "create"
+	ChangeUpdate ChangeOperation = "update"
+	ChangeDelete ChangeOperation = "delete"
+)
+
+// ChangeEvent represents a single create/update/delete event for a model
+type ChangeEvent struct {
+	Model     string          `json:"model"`
+	Operation ChangeOperation `json:"operation"`
+	// Data uses schema field names. For deletes it contains the last known
+	// state of the record (or only its key when the backend cannot provide more).
+	Data      map[string]any `json:"data"`
+	Timestamp time.Time      `json:"timestamp"`
+}
+
+// ChangeStream delivers change events until it is closed or its context is cancelled
+type ChangeStream interface {
+	// Events returns the channel of change events. It is closed when the stream stops.
+	Events() <-chan ChangeEvent
+	// Err returns the error that stopped the stream, if any
+	Err() error
+	// Close stops the stream and releases its resources
+	Close() error
+}
+
+// ChangeWatcher is implemented by databases that can emit change events
+type ChangeWatcher interface {
+	// Watch opens a change stream for the given models (all registered models if none given)
+	Watch(ctx context.Context, models ...string) (ChangeStream, error)
+}
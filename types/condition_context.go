@@ -7,6 +7,7 @@ type ConditionContext struct {
 	TableAlias      string
 	JoinedTables    map[string]JoinInfo // For complex queries with joins
 	QuoteIdentifier func(string) string // Function to quote identifiers
+	Capabilities    DriverCapabilities  // For conditions whose SQL differs between drivers
 }
 
 // JoinInfo contains information about a joined table
@@ -2,7 +2,10 @@ package types
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+
+	"github.com/rediwo/redi-orm/utils"
 )
 
 // BaseCondition implements common condition functionality
@@ -199,21 +202,8 @@ func (f *MappedFieldCondition) ToSQL(ctx *ConditionContext) (string, []any) {
 		return f.BaseCondition.ToSQL(ctx)
 	}
 
-	// Use the context with proper model name if needed
-	mappingCtx := ctx
-	if f.modelName != "" && ctx.ModelName != f.modelName {
-		// Create a new context with the field's model name for correct mapping
-		mappingCtx = &ConditionContext{
-			FieldMapper:     ctx.FieldMapper,
-			ModelName:       f.modelName,
-			TableAlias:      ctx.TableAlias,
-			JoinedTables:    ctx.JoinedTables,
-			QuoteIdentifier: ctx.QuoteIdentifier,
-		}
-	}
-
-	// Map field to column
-	columnRef, err := mappingCtx.MapFieldToColumn(f.fieldName)
+	// Map field to column, using the field's model name if needed
+	columnRef, err := modelContext(ctx, f.modelName).MapFieldToColumn(f.fieldName)
 	if err != nil {
 		// If mapping fails, use original field name
 		columnRef = f.fieldName
@@ -239,6 +229,172 @@ func (f *MappedFieldCondition) Not() Condition {
 	return NewNotCondition(f)
 }
 
+// SplitIn splits condition, binding parameters parameters, on its largest IN list into
+// conditions binding at most maxParameters parameters each. The IN list may be condition
+// itself or one of the conditions it ANDs; its values are deduplicated and spread over the
+// returned conditions, so they match the records of condition without matching any twice.
+// SplitIn returns nil when condition has no IN list or the rest of it leaves no room for one.
+func SplitIn(condition Condition, parameters, maxParameters int) []Condition {
+	return splitList(condition, "in", parameters, maxParameters)
+}
+
+// SplitNotIn splits condition like SplitIn, on its largest NOT IN list. The records of
+// condition are those matching all of the returned conditions rather than any of them.
+func SplitNotIn(condition Condition, parameters, maxParameters int) []Condition {
+	return splitList(condition, "notIn", parameters, maxParameters)
+}
+
+// splitList splits condition on its largest list of the IN or NOT IN operator
+func splitList(condition Condition, operator string, parameters, maxParameters int) []Condition {
+	list := largestList(condition, operator)
+	if list == nil {
+		return nil
+	}
+	size := maxParameters - (parameters - len(list.Args))
+	if size < 1 {
+		return nil
+	}
+
+	var values []any
+	seen := make(map[string]bool)
+	for _, value := range list.Args {
+		key := fmt.Sprintf("%T %v", value, value)
+		if !seen[key] {
+			seen[key] = true
+			values = append(values, value)
+		}
+	}
+
+	var conditions []Condition
+	for chunk := range slices.Chunk(values, size) {
+		field := &FieldConditionImpl{ModelName: list.modelName, FieldName: list.fieldName}
+		replacement := field.In(chunk...)
+		if operator == "notIn" {
+			replacement = field.NotIn(chunk...)
+		}
+		conditions = append(conditions, replaceList(condition, list, replacement))
+	}
+	return conditions
+}
+
+// largestList returns the list of operator with the most values among condition and the
+// conditions it ANDs
+func largestList(condition Condition, operator string) *MappedFieldCondition {
+	switch c := condition.(type) {
+	case *MappedFieldCondition:
+		if c.operator == operator {
+			return c
+		}
+	case *AndCondition:
+		var largest *MappedFieldCondition
+		for _, child := range c.Conditions {
+			if list := largestList(child, operator); list != nil && (largest == nil || len(list.Args) > len(largest.Args)) {
+				largest = list
+			}
+		}
+		return largest
+	}
+	return nil
+}
+
+// replaceList returns condition with list replaced by replacement
+func replaceList(condition Condition, list *MappedFieldCondition, replacement Condition) Condition {
+	switch c := condition.(type) {
+	case *MappedFieldCondition:
+		if c == list {
+			return replacement
+		}
+	case *AndCondition:
+		conditions := make([]Condition, len(c.Conditions))
+		for i, child := range c.Conditions {
+			conditions[i] = replaceList(child, list, replacement)
+		}
+		return NewAndCondition(conditions...)
+	}
+	return condition
+}
+
+// modelContext returns ctx for mapping the fields of modelName, which may differ from the
+// model of the query
+func modelContext(ctx *ConditionContext, modelName string) *ConditionContext {
+	if modelName == "" || ctx.ModelName == modelName {
+		return ctx
+	}
+	return &ConditionContext{
+		FieldMapper:     ctx.FieldMapper,
+		ModelName:       modelName,
+		TableAlias:      ctx.TableAlias,
+		JoinedTables:    ctx.JoinedTables,
+		QuoteIdentifier: ctx.QuoteIdentifier,
+		Capabilities:    ctx.Capabilities,
+	}
+}
+
+// ArrayFieldCondition filters a scalar list field on its elements. Lists are stored as
+// native arrays or as JSON depending on the driver, so the SQL comes from the driver
+// capabilities in the condition context.
+type ArrayFieldCondition struct {
+	FieldName string
+	ModelName string
+	Operator  string // "has", "hasSome", "hasEvery", "isEmpty" or "isNotEmpty"
+	Value     any    // The element for has, the []any of elements for hasSome and hasEvery
+}
+
+// ToSQL generates the driver's SQL for the list filter
+func (c *ArrayFieldCondition) ToSQL(ctx *ConditionContext) (string, []any) {
+	columnRef := c.FieldName
+	if ctx != nil {
+		if mapped, err := modelContext(ctx, c.ModelName).MapFieldToColumn(c.FieldName); err == nil {
+			columnRef = mapped
+		}
+	}
+
+	var filterSQL string
+	if ctx != nil && ctx.Capabilities != nil {
+		filterSQL = ctx.Capabilities.GetArrayFilterSQL(c.Operator, columnRef)
+	}
+	if filterSQL == "" {
+		// Leave the operator in the SQL so that the database reports it
+		return fmt.Sprintf("%s %s ?", columnRef, c.Operator), []any{c.Value}
+	}
+
+	switch c.Operator {
+	case "isEmpty", "isNotEmpty":
+		return filterSQL, nil
+	case "has":
+		return filterSQL, []any{c.Value}
+	}
+
+	values, _ := c.Value.([]any)
+	if len(values) == 0 {
+		if c.Operator == "hasEvery" {
+			return columnRef + " IS NOT NULL", nil // Every list contains all of no elements
+		}
+		return "1 = 0", nil
+	}
+	encoded, err := ctx.Capabilities.EncodeArrayValue(values)
+	if err != nil {
+		// Bind the elements as they are, the database rejects them
+		return filterSQL, []any{values}
+	}
+	return filterSQL, []any{encoded}
+}
+
+// And combines this condition with another using AND logic
+func (c *ArrayFieldCondition) And(condition Condition) Condition {
+	return NewAndCondition(c, condition)
+}
+
+// Or combines this condition with another using OR logic
+func (c *ArrayFieldCondition) Or(condition Condition) Condition {
+	return NewOrCondition(c, condition)
+}
+
+// Not negates this condition
+func (c *ArrayFieldCondition) Not() Condition {
+	return NewNotCondition(c)
+}
+
 // AggregationCondition represents a condition on an aggregated value
 type AggregationCondition struct {
 	BaseCondition
@@ -359,7 +515,7 @@ func (f *FieldConditionImpl) In(values ...any) Condition {
 	placeholders = placeholders[:len(placeholders)-1] // Remove trailing comma
 
 	sql := fmt.Sprintf("%s IN (%s)", f.FieldName, placeholders)
-	return &MappedFieldCondition{BaseCondition: *NewBaseCondition(sql, values...), fieldName: f.FieldName, modelName: f.ModelName}
+	return &MappedFieldCondition{BaseCondition: *NewBaseCondition(sql, values...), fieldName: f.FieldName, modelName: f.ModelName, operator: "in"}
 }
 
 func (f *FieldConditionImpl) NotIn(values ...any) Condition {
@@ -371,19 +527,23 @@ func (f *FieldConditionImpl) NotIn(values ...any) Condition {
 	placeholders = placeholders[:len(placeholders)-1] // Remove trailing comma
 
 	sql := fmt.Sprintf("%s NOT IN (%s)", f.FieldName, placeholders)
-	return &MappedFieldCondition{BaseCondition: *NewBaseCondition(sql, values...), fieldName: f.FieldName, modelName: f.ModelName}
+	return &MappedFieldCondition{BaseCondition: *NewBaseCondition(sql, values...), fieldName: f.FieldName, modelName: f.ModelName, operator: "notIn"}
 }
 
+// likeEscapeSQL declares the escape character of the patterns of Contains, StartsWith and
+// EndsWith, whose values match literally even with LIKE wildcards in them
+const likeEscapeSQL = " LIKE ? ESCAPE '" + string(utils.LikeEscape) + "'"
+
 func (f *FieldConditionImpl) Contains(value string) Condition {
-	return &MappedFieldCondition{BaseCondition: *NewBaseCondition(f.FieldName+" LIKE ?", "%"+value+"%"), fieldName: f.FieldName, modelName: f.ModelName}
+	return &MappedFieldCondition{BaseCondition: *NewBaseCondition(f.FieldName+likeEscapeSQL, "%"+utils.EscapeLike(value)+"%"), fieldName: f.FieldName, modelName: f.ModelName}
 }
 
 func (f *FieldConditionImpl) StartsWith(value string) Condition {
-	return &MappedFieldCondition{BaseCondition: *NewBaseCondition(f.FieldName+" LIKE ?", value+"%"), fieldName: f.FieldName, modelName: f.ModelName}
+	return &MappedFieldCondition{BaseCondition: *NewBaseCondition(f.FieldName+likeEscapeSQL, utils.EscapeLike(value)+"%"), fieldName: f.FieldName, modelName: f.ModelName}
 }
 
 func (f *FieldConditionImpl) EndsWith(value string) Condition {
-	return &MappedFieldCondition{BaseCondition: *NewBaseCondition(f.FieldName+" LIKE ?", "%"+value), fieldName: f.FieldName, modelName: f.ModelName}
+	return &MappedFieldCondition{BaseCondition: *NewBaseCondition(f.FieldName+likeEscapeSQL, "%"+utils.EscapeLike(value)), fieldName: f.FieldName, modelName: f.ModelName}
 }
 
 func (f *FieldConditionImpl) Like(pattern string) Condition {
@@ -402,6 +562,26 @@ func (f *FieldConditionImpl) Between(min, max any) Condition {
 	return &MappedFieldCondition{BaseCondition: *NewBaseCondition(f.FieldName+" BETWEEN ? AND ?", min, max), fieldName: f.FieldName, modelName: f.ModelName}
 }
 
+func (f *FieldConditionImpl) Has(value any) Condition {
+	return &ArrayFieldCondition{FieldName: f.FieldName, ModelName: f.ModelName, Operator: "has", Value: value}
+}
+
+func (f *FieldConditionImpl) HasSome(values ...any) Condition {
+	return &ArrayFieldCondition{FieldName: f.FieldName, ModelName: f.ModelName, Operator: "hasSome", Value: values}
+}
+
+func (f *FieldConditionImpl) HasEvery(values ...any) Condition {
+	return &ArrayFieldCondition{FieldName: f.FieldName, ModelName: f.ModelName, Operator: "hasEvery", Value: values}
+}
+
+func (f *FieldConditionImpl) IsEmpty() Condition {
+	return &ArrayFieldCondition{FieldName: f.FieldName, ModelName: f.ModelName, Operator: "isEmpty"}
+}
+
+func (f *FieldConditionImpl) IsNotEmpty() Condition {
+	return &ArrayFieldCondition{FieldName: f.FieldName, ModelName: f.ModelName, Operator: "isNotEmpty"}
+}
+
 // Helper function to create BaseCondition
 func NewBaseCondition(sql string, args ...any) *BaseCondition {
 	return &BaseCondition{
@@ -411,18 +591,45 @@ func NewBaseCondition(sql string, args ...any) *BaseCondition {
 }
 
 // Utility functions for building conditions
+
+// And matches when all conditions match. Nil conditions are ignored, and a single condition is
+// returned as it is, so that groups can be nested freely:
+//
+//	types.Or(user.Where("role").Equals("admin"), types.And(user.Where("active").Equals(true), user.Where("age").GreaterThan(18)))
 func And(conditions ...Condition) Condition {
+	conditions = compactConditions(conditions)
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
 	return NewAndCondition(conditions...)
 }
 
+// Or matches when any of the conditions matches. Nil conditions are ignored, and a single
+// condition is returned as it is.
 func Or(conditions ...Condition) Condition {
+	conditions = compactConditions(conditions)
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
 	return NewOrCondition(conditions...)
 }
 
+// Not matches when condition does not match
 func Not(condition Condition) Condition {
 	return NewNotCondition(condition)
 }
 
+// compactConditions returns the conditions that are not nil
+func compactConditions(conditions []Condition) []Condition {
+	compacted := make([]Condition, 0, len(conditions))
+	for _, condition := range conditions {
+		if condition != nil {
+			compacted = append(compacted, condition)
+		}
+	}
+	return compacted
+}
+
 func Raw(sql string, args ...any) Condition {
 	return NewRawCondition(sql, args...)
 }
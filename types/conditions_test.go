@@ -2,6 +2,8 @@ package types
 
 import (
 	"testing"
+
+	"github.com/rediwo/redi-orm/schema"
 )
 
 // Mock FieldMapper for testing
@@ -248,3 +250,150 @@ func TestComplexConditions_ToSQL(t *testing.T) {
 		t.Errorf("Complex condition Args = %v", args)
 	}
 }
+
+func TestLogicalHelpers(t *testing.T) {
+	mapper := &mockFieldMapper{
+		mappings: map[string]map[string]string{
+			"User": {"name": "name", "age": "age"},
+		},
+	}
+	ctx := NewConditionContext(mapper, "User", "u")
+	nameCond := NewFieldCondition("User", "name").Equals("John")
+	ageCond := NewFieldCondition("User", "age").GreaterThan(30)
+
+	// Nil conditions are ignored, and single conditions are not wrapped
+	if cond := Or(nil, nameCond); cond != nameCond {
+		t.Errorf("Or() of a single condition = %v, want the condition", cond)
+	}
+	if cond := And(nameCond, nil); cond != nameCond {
+		t.Errorf("And() of a single condition = %v, want the condition", cond)
+	}
+
+	sql, args := Not(Or(nameCond, And(nil, ageCond, nameCond))).ToSQL(ctx)
+	expectedSQL := "NOT ((u.name = ?) OR ((u.age > ?) AND (u.name = ?)))"
+	if sql != expectedSQL {
+		t.Errorf("Nested condition SQL = %v, want %v", sql, expectedSQL)
+	}
+	if len(args) != 3 {
+		t.Errorf("Nested condition Args = %v", args)
+	}
+}
+
+func TestRelationCondition(t *testing.T) {
+	mapper := NewDefaultFieldMapper()
+	mapper.RegisterSchema("User", schema.New("User").
+		AddField(schema.Field{Name: "id", Type: schema.FieldTypeInt, PrimaryKey: true}).
+		AddField(schema.Field{Name: "name", Type: schema.FieldTypeString}).
+		AddRelation("profile", schema.Relation{Type: schema.RelationOneToOne, Model: "Profile", ForeignKey: "userId", References: "id"}))
+	mapper.RegisterSchema("Profile", schema.New("Profile").
+		AddField(schema.Field{Name: "id", Type: schema.FieldTypeInt, PrimaryKey: true}).
+		AddField(schema.Field{Name: "userId", Type: schema.FieldTypeInt}))
+	mapper.RegisterSchema("Post", schema.New("Post").
+		AddField(schema.Field{Name: "id", Type: schema.FieldTypeInt, PrimaryKey: true}).
+		AddField(schema.Field{Name: "authorId", Type: schema.FieldTypeInt}).
+		AddRelation("author", schema.Relation{Type: schema.RelationManyToOne, Model: "User", ForeignKey: "authorId", References: "id"}))
+
+	tests := []struct {
+		name      string
+		condition Condition
+		alias     string
+		expected  string
+	}{
+		{
+			name:      "is",
+			condition: Is("author", NewFieldCondition("", "name").Equals("Alice")),
+			alias:     "t0",
+			expected:  "EXISTS (SELECT 1 FROM users AS t0_author WHERE t0_author.id = t0.author_id AND (t0_author.name = ?))",
+		},
+		{
+			name:      "is not without alias",
+			condition: IsNot("author", NewFieldCondition("", "name").Equals("Alice")),
+			expected:  "NOT EXISTS (SELECT 1 FROM users AS posts_author WHERE posts_author.id = posts.author_id AND (posts_author.name = ?))",
+		},
+		{
+			name:      "is null",
+			condition: Is("author", nil),
+			alias:     "t0",
+			expected:  "NOT EXISTS (SELECT 1 FROM users AS t0_author WHERE t0_author.id = t0.author_id)",
+		},
+		{
+			name:      "nested with foreign key on the related model",
+			condition: Is("author", Is("profile", nil)),
+			alias:     "t0",
+			expected:  "EXISTS (SELECT 1 FROM users AS t0_author WHERE t0_author.id = t0.author_id AND (NOT EXISTS (SELECT 1 FROM profiles AS t0_author_profile WHERE t0_author_profile.user_id = t0_author.id)))",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, _ := tt.condition.ToSQL(NewConditionContext(mapper, "Post", tt.alias))
+			if sql != tt.expected {
+				t.Errorf("ToSQL() = %v, want %v", sql, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSplitIn(t *testing.T) {
+	ctx := NewConditionContext(&mockFieldMapper{}, "User", "")
+	in := NewFieldCondition("User", "id").In(1, 2, 3, 2, 4, 5)
+	active := NewFieldCondition("User", "active").Equals(true)
+
+	// The IN list is deduplicated and split so that each condition binds at most 3 parameters
+	conditions := SplitIn(And(active, in), 7, 3)
+	expected := []string{
+		"(active = ?) AND (id IN (?,?))",
+		"(active = ?) AND (id IN (?,?))",
+		"(active = ?) AND (id IN (?))",
+	}
+	if len(conditions) != len(expected) {
+		t.Fatalf("SplitIn() returned %d conditions, want %d", len(conditions), len(expected))
+	}
+	var values []any
+	for i, condition := range conditions {
+		sql, args := condition.ToSQL(ctx)
+		if sql != expected[i] {
+			t.Errorf("SplitIn()[%d] SQL = %v, want %v", i, sql, expected[i])
+		}
+		values = append(values, args[1:]...)
+	}
+	if len(values) != 5 || values[0] != 1 || values[4] != 5 {
+		t.Errorf("SplitIn() values = %v, want [1 2 3 4 5]", values)
+	}
+
+	// Conditions without an IN list, or without room for one, are not split
+	if conditions := SplitIn(active, 1, 0); conditions != nil {
+		t.Errorf("SplitIn() without IN = %v, want nil", conditions)
+	}
+	if conditions := SplitIn(Or(active, in), 7, 3); conditions != nil {
+		t.Errorf("SplitIn() of an OR = %v, want nil", conditions)
+	}
+	if conditions := SplitIn(And(active, in), 7, 1); conditions != nil {
+		t.Errorf("SplitIn() without room = %v, want nil", conditions)
+	}
+}
+
+func TestSplitNotIn(t *testing.T) {
+	ctx := NewConditionContext(&mockFieldMapper{}, "User", "")
+	notIn := NewFieldCondition("User", "id").NotIn(1, 2, 3, 4)
+	active := NewFieldCondition("User", "active").Equals(true)
+
+	conditions := SplitNotIn(And(active, notIn), 5, 3)
+	expected := []string{
+		"(active = ?) AND (id NOT IN (?,?))",
+		"(active = ?) AND (id NOT IN (?,?))",
+	}
+	if len(conditions) != len(expected) {
+		t.Fatalf("SplitNotIn() returned %d conditions, want %d", len(conditions), len(expected))
+	}
+	for i, condition := range conditions {
+		if sql, _ := condition.ToSQL(ctx); sql != expected[i] {
+			t.Errorf("SplitNotIn()[%d] SQL = %v, want %v", i, sql, expected[i])
+		}
+	}
+
+	// IN lists are left to SplitIn
+	if conditions := SplitNotIn(NewFieldCondition("User", "id").In(1, 2, 3, 4), 4, 3); conditions != nil {
+		t.Errorf("SplitNotIn() of an IN list = %v, want nil", conditions)
+	}
+}
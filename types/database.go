@@ -3,6 +3,8 @@ package types
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"time"
 
 	"github.com/rediwo/redi-orm/logger"
 	"github.com/rediwo/redi-orm/schema"
@@ -16,10 +18,21 @@ const (
 	DESC
 )
 
+// NullsOrder places null values in an ordering
+type NullsOrder int
+
+const (
+	NullsDefault NullsOrder = iota // Placement of the database
+	NullsFirst
+	NullsLast
+)
+
 // OrderByClause represents an ORDER BY clause
 type OrderByClause struct {
-	Field     string
-	Direction Order
+	Field         string
+	Direction     Order
+	Nulls         NullsOrder
+	RelationCount bool // Field names a to-many relation, ordered by its number of records
 }
 
 // ConflictAction represents action to take on insert conflicts
@@ -29,12 +42,24 @@ const (
 	ConflictIgnore ConflictAction = iota
 	ConflictReplace
 	ConflictUpdate
+	// ConflictDoNothing skips rows that violate a unique constraint instead of failing
+	ConflictDoNothing
 )
 
 // Result represents operation result
 type Result struct {
 	LastInsertID int64
 	RowsAffected int64
+	InsertedIDs  []any // Primary keys of the inserted rows, from drivers returning them on insert
+}
+
+// InsertedID returns the primary key of the record an insert of one record created: the one
+// reported in InsertedIDs, like a generated UUID, or else LastInsertID
+func (r Result) InsertedID() (any, bool) {
+	if len(r.InsertedIDs) == 1 {
+		return r.InsertedIDs[0], true
+	}
+	return r.LastInsertID, r.LastInsertID > 0
 }
 
 // Database interface defines all database operations
@@ -64,8 +89,11 @@ type Database interface {
 	Transaction(ctx context.Context, fn func(tx Transaction) error) error
 
 	// Metadata
-	GetModels() []string
+	GetModels() []string // sorted by name
 	GetModelSchema(modelName string) (*schema.Schema, error)
+	GetModelField(modelName, fieldName string) (*schema.Field, error)
+	GetModelRelations(modelName string) ([]RelationInfo, error) // sorted by name
+	ResolveRelation(modelName, relationName string) (RelationInfo, error)
 	GetDriverType() string
 	GetCapabilities() DriverCapabilities
 
@@ -87,6 +115,108 @@ type Database interface {
 	GetLogger() logger.Logger
 }
 
+// RelationInfo is a relation of a registered model, resolved against the other registered
+// models
+type RelationInfo struct {
+	Model    string // the model declaring the relation
+	Name     string
+	Relation schema.Relation
+
+	// Related is the schema of the related model. It is nil for the polymorphic side of
+	// polymorphic relations, whose related models are listed in Relation.Models, and for
+	// models not registered with the database, like those of other datasources.
+	Related *schema.Schema
+
+	// LocalField and RelatedField are the fields joining the models, as returned by
+	// Relation.JoinFields; empty for many-to-many relations
+	LocalField   string
+	RelatedField string
+}
+
+// ContextQuerier is implemented by SQL databases and transactions that run raw queries with
+// a context, canceling the query when the context is done
+type ContextQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// SequenceSyncer is implemented by databases whose auto-increment sequences do not move past
+// values inserted explicitly, such as PostgreSQL. SyncSequences moves the sequences of the
+// fields of a model past the largest values in its table, e.g. after copying records.
+type SequenceSyncer interface {
+	SyncSequences(ctx context.Context, modelName string) error
+}
+
+// MigrationLocker is implemented by databases with a lock of their own to serialize
+// migrations across processes, such as PostgreSQL advisory locks. LockMigrations waits up to
+// timeout for the lock and returns the function releasing it, or ErrLockUnsupported when the
+// connection cannot be locked this way.
+type MigrationLocker interface {
+	LockMigrations(ctx context.Context, timeout time.Duration) (unlock func() error, err error)
+}
+
+// ErrLockUnsupported is returned by LockMigrations when migrations have to be locked otherwise
+var ErrLockUnsupported = errors.New("migration lock not supported")
+
+// StatementTimeouter is implemented by databases with a default statement timeout, which
+// bounds every query that does not set a timeout of its own
+type StatementTimeouter interface {
+	SetStatementTimeout(timeout time.Duration)
+	GetStatementTimeout() time.Duration
+}
+
+// InsertIDReturner is implemented by databases whose drivers report no last insert ID, like
+// PostgreSQL. InsertReturningID runs the insert with a RETURNING clause of its primary key
+// column, reporting the keys of the inserted rows in InsertedIDs, the last of them in
+// LastInsertID when integer.
+type InsertIDReturner interface {
+	InsertReturningID(ctx context.Context, sql string, args []any, column string) (Result, error)
+}
+
+// ConnectionState is the state of the connection of a database to its server
+type ConnectionState string
+
+const (
+	ConnectionConnected    ConnectionState = "connected"
+	ConnectionReconnecting ConnectionState = "reconnecting"
+)
+
+// ConnectionStatus reports the connection of a database monitored by health checks
+type ConnectionStatus struct {
+	State      ConnectionState
+	Since      time.Time // When the connection entered its state
+	Reconnects int       // Times the connection was restored after failing
+	Error      string    // Error of the last failed ping, while reconnecting
+}
+
+// ConnectionMonitor is implemented by databases that ping their server periodically,
+// reconnecting with backoff when it stops answering. StartHealthCheck returns the function
+// stopping the pings, which also stop when the database is closed.
+type ConnectionMonitor interface {
+	StartHealthCheck(interval time.Duration) (stop func())
+	ConnectionStatus() (ConnectionStatus, bool) // false until health checks are started
+}
+
+// StatementContext returns ctx with a deadline of timeout, or of the default statement
+// timeout of db when timeout is 0. Without either, ctx is returned as it is.
+func StatementContext(ctx context.Context, db Database, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		if timeouter, ok := db.(StatementTimeouter); ok {
+			timeout = timeouter.GetStatementTimeout()
+		}
+	}
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// SQLPreparer is implemented by databases rewriting the SQL of queries before running it,
+// such as PostgreSQL numbering its placeholders. PrepareSQL returns the SQL as it is run.
+type SQLPreparer interface {
+	PrepareSQL(sql string) string
+}
+
 // ModelQuery interface for model-based queries
 type ModelQuery interface {
 	// Query building (uses schema field names)
@@ -95,12 +225,19 @@ type ModelQuery interface {
 	Update(data any) UpdateQuery
 	Delete() DeleteQuery
 	Aggregate() AggregationQuery
+	Upsert(create any, update any, conflictFields ...string) UpsertQuery
 
 	// Condition building (uses schema field names)
 	Where(fieldName string) FieldCondition
 	WhereCondition(condition Condition) ModelQuery
 	WhereRaw(sql string, args ...any) ModelQuery
 
+	// Boolean composition, for conditions other than all of them matching
+	OrWhere(condition Condition) ModelQuery                     // (conditions so far) OR condition
+	WhereNot(condition Condition) ModelQuery                    // AND NOT condition
+	WhereAny(conditions ...Condition) ModelQuery                // AND (c1 OR c2 ...)
+	WhereGroup(fn func(group ModelQuery) ModelQuery) ModelQuery // AND (conditions of the group)
+
 	// Relation queries (uses relation names)
 	Include(relations ...string) ModelQuery
 	With(relations ...string) ModelQuery
@@ -112,6 +249,9 @@ type ModelQuery interface {
 	Limit(limit int) ModelQuery
 	Offset(offset int) ModelQuery
 
+	// Cancel the statements of the query after timeout, overriding the default statement timeout
+	WithTimeout(timeout time.Duration) ModelQuery
+
 	// Execution
 	FindMany(ctx context.Context, dest any) error
 	FindUnique(ctx context.Context, dest any) error
@@ -136,19 +276,26 @@ type SelectQuery interface {
 	WhereCondition(condition Condition) SelectQuery
 	Include(relations ...string) SelectQuery
 	IncludeWithOptions(path string, opt *IncludeOption) SelectQuery
+	RelationLoadStrategy(strategy RelationLoadStrategy) SelectQuery
 	OrderBy(fieldName string, direction Order) SelectQuery
+	OrderByNulls(fieldName string, direction Order, nulls NullsOrder) SelectQuery
+	OrderByRelationCount(relationName string, direction Order) SelectQuery
 	GroupBy(fieldNames ...string) SelectQuery
 	Having(condition Condition) SelectQuery
 	Limit(limit int) SelectQuery
 	Offset(offset int) SelectQuery
 	Distinct() SelectQuery
 	DistinctOn(fieldNames ...string) SelectQuery
+	WithTimeout(timeout time.Duration) SelectQuery
 
 	// Execution
 	FindMany(ctx context.Context, dest any) error
 	FindFirst(ctx context.Context, dest any) error
 	Count(ctx context.Context) (int64, error)
 
+	// Compilation: the SQL as the driver runs it and its arguments, without executing
+	ToSQL() (string, []any, error)
+
 	// Internal methods (for driver implementation)
 	BuildSQL() (string, []any, error)
 	GetModelName() string
@@ -162,6 +309,7 @@ type AggregationQuery interface {
 
 	// Aggregation functions
 	Count(fieldName string, alias string) AggregationQuery
+	CountDistinct(fieldName string, alias string) AggregationQuery
 	CountAll(alias string) AggregationQuery
 	Sum(fieldName string, alias string) AggregationQuery
 	Avg(fieldName string, alias string) AggregationQuery
@@ -178,10 +326,14 @@ type AggregationQuery interface {
 	OrderByAggregation(aggregationType string, fieldName string, direction Order) AggregationQuery
 	Limit(limit int) AggregationQuery
 	Offset(offset int) AggregationQuery
+	WithTimeout(timeout time.Duration) AggregationQuery
 
 	// Execution
 	Exec(ctx context.Context, dest any) error
 
+	// Compilation: the SQL as the driver runs it and its arguments, without executing
+	ToSQL() (string, []any, error)
+
 	// Internal methods
 	BuildSQL() (string, []any, error)
 	GetModelName() string
@@ -198,6 +350,24 @@ type InsertQuery interface {
 	Exec(ctx context.Context) (Result, error)
 	ExecAndReturn(ctx context.Context, dest any) error
 
+	// Compilation: the SQL as the driver runs it and its arguments, without executing
+	ToSQL() (string, []any, error)
+
+	// Internal methods
+	BuildSQL() (string, []any, error)
+	GetModelName() string
+}
+
+// UpsertQuery interface for insert-or-update operations. The create data is inserted
+// unless a record with the same conflict field values exists, which is then updated with
+// the update data, in a single statement.
+type UpsertQuery interface {
+	// Execution
+	Exec(ctx context.Context) (Result, error)
+
+	// Compilation: the SQL as the driver runs it and its arguments, without executing
+	ToSQL() (string, []any, error)
+
 	// Internal methods
 	BuildSQL() (string, []any, error)
 	GetModelName() string
@@ -212,13 +382,18 @@ type UpdateQuery interface {
 	Returning(fieldNames ...string) UpdateQuery
 
 	// Atomic operations (uses schema field names)
-	Increment(fieldName string, value int64) UpdateQuery
-	Decrement(fieldName string, value int64) UpdateQuery
+	Increment(fieldName string, value any) UpdateQuery
+	Decrement(fieldName string, value any) UpdateQuery
+	Multiply(fieldName string, value any) UpdateQuery
+	Push(fieldName string, values ...any) UpdateQuery
 
 	// Execution
 	Exec(ctx context.Context) (Result, error)
 	ExecAndReturn(ctx context.Context, dest any) error
 
+	// Compilation: the SQL as the driver runs it and its arguments, without executing
+	ToSQL() (string, []any, error)
+
 	// Internal methods
 	BuildSQL() (string, []any, error)
 	GetModelName() string
@@ -233,6 +408,9 @@ type DeleteQuery interface {
 	// Execution
 	Exec(ctx context.Context) (Result, error)
 
+	// Compilation: the SQL as the driver runs it and its arguments, without executing
+	ToSQL() (string, []any, error)
+
 	// Internal methods
 	BuildSQL() (string, []any, error)
 	GetModelName() string
@@ -280,11 +458,26 @@ type FieldCondition interface {
 	// Range operations
 	Between(min, max any) Condition
 
+	// List operations, for scalar list fields
+	Has(value any) Condition
+	HasSome(values ...any) Condition
+	HasEvery(values ...any) Condition
+	IsEmpty() Condition
+	IsNotEmpty() Condition
+
 	// Internal methods (for driver implementation)
 	GetFieldName() string
 	GetModelName() string
 }
 
+// ErrSavepointsNotSupported is returned by Savepoint and RollbackTo on drivers whose
+// capabilities report SupportsSavepoints() == false
+var ErrSavepointsNotSupported = errors.New("savepoints are not supported by this driver")
+
+// ErrTooManyParameters is returned by queries binding more parameters than the driver
+// allows that cannot be split over several statements
+var ErrTooManyParameters = errors.New("too many parameters for the database")
+
 // Transaction interface for database transactions
 type Transaction interface {
 	// Inherit all model query capabilities
@@ -329,6 +522,7 @@ type TableInfo struct {
 	Columns     []ColumnInfo
 	Indexes     []IndexInfo
 	ForeignKeys []ForeignKeyInfo
+	Comment     string // Table comment, if the database supports them
 }
 
 type ColumnInfo struct {
@@ -339,6 +533,9 @@ type ColumnInfo struct {
 	PrimaryKey    bool
 	AutoIncrement bool
 	Unique        bool
+	Comment       string   // Column comment, if the database supports them
+	Enum          string   // Native enum type of the column (PostgreSQL)
+	EnumValues    []string // Values of the native enum type
 }
 
 type IndexInfo struct {
@@ -419,6 +616,27 @@ type DatabaseSpecificMigrator interface {
 	MapDatabaseTypeToFieldType(dbType string) schema.FieldType
 }
 
+// UpdatedAtTriggerGenerator is implemented by database-specific migrators that can maintain
+// @updatedAt columns with triggers
+type UpdatedAtTriggerGenerator interface {
+	GenerateUpdatedAtTriggerSQL(tableName string, columns []string) []string
+}
+
+// IdentifierQuoter is implemented by database-specific migrators that quote the table,
+// column and index names of the SQL they generate
+type IdentifierQuoter interface {
+	QuoteIdentifier(name string) string
+}
+
+// DefaultSampleSize is the number of documents sampled per collection when inferring schemas
+const DefaultSampleSize = 100
+
+// SchemaInferrer is implemented by migrators of schemaless databases, which infer the
+// schemas of their collections from sampled documents instead of table definitions
+type SchemaInferrer interface {
+	InferSchemas(sampleSize int) ([]*schema.Schema, error)
+}
+
 type DatabaseMigrator interface {
 	// Introspection
 	GetTables() ([]string, error)
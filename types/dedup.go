@@ -0,0 +1,113 @@
+package types
+
+import (
+	"context"
+	"sync"
+)
+
+type deduplicationKey struct{}
+
+// deduplication holds the lookups deduplicated in a context. Writes bump the generation, so
+// that lookups started before a write don't store what they read.
+type deduplication struct {
+	mu         sync.Mutex
+	generation int
+	lookups    map[string]*lookup
+}
+
+// lookup is a deduplicated lookup, done once its result is set
+type lookup struct {
+	done   chan struct{}
+	result any
+	err    error
+}
+
+// WithDeduplication returns a context in which identical unique lookups, like the findUnique
+// calls of the resolvers of a GraphQL request, run once. Their callers share the result of the
+// first, receiving copies of it, until a write in the context, through the query builders or
+// a raw query, or the end of a transaction committed or rolled back with it.
+func WithDeduplication(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(deduplicationKey{}).(*deduplication); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, deduplicationKey{}, &deduplication{lookups: make(map[string]*lookup)})
+}
+
+// Deduplicate returns the result of find for key. In a context of WithDeduplication, find runs
+// once per key, with concurrent callers waiting for its result; errors are not kept, so that
+// later callers run find again.
+func Deduplicate(ctx context.Context, key string, find func() (any, error)) (any, error) {
+	d, ok := ctx.Value(deduplicationKey{}).(*deduplication)
+	if !ok {
+		return find()
+	}
+
+	d.mu.Lock()
+	if l, ok := d.lookups[key]; ok {
+		d.mu.Unlock()
+		select {
+		case <-l.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if l.err != nil {
+			return nil, l.err
+		}
+		return copyResult(l.result), nil
+	}
+	l := &lookup{done: make(chan struct{})}
+	d.lookups[key] = l
+	generation := d.generation
+	d.mu.Unlock()
+
+	l.result, l.err = find()
+	close(l.done)
+
+	d.mu.Lock()
+	if d.lookups[key] == l && (l.err != nil || d.generation != generation) {
+		delete(d.lookups, key)
+	}
+	d.mu.Unlock()
+
+	if l.err != nil {
+		return nil, l.err
+	}
+	return copyResult(l.result), nil
+}
+
+// ForgetDeduplicated drops the deduplicated lookups of ctx, after a write that may change
+// their results
+func ForgetDeduplicated(ctx context.Context) {
+	if d, ok := ctx.Value(deduplicationKey{}).(*deduplication); ok {
+		d.mu.Lock()
+		d.generation++
+		clear(d.lookups)
+		d.mu.Unlock()
+	}
+}
+
+// copyResult copies the records of a result, so that callers sharing it can change theirs
+func copyResult(result any) any {
+	switch v := result.(type) {
+	case map[string]any:
+		record := make(map[string]any, len(v))
+		for key, value := range v {
+			record[key] = copyResult(value)
+		}
+		return record
+	case []map[string]any:
+		records := make([]map[string]any, len(v))
+		for i, value := range v {
+			records[i] = copyResult(value).(map[string]any)
+		}
+		return records
+	case []any:
+		items := make([]any, len(v))
+		for i, value := range v {
+			items[i] = copyResult(value)
+		}
+		return items
+	default:
+		return result
+	}
+}
@@ -0,0 +1,56 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDeduplicate(t *testing.T) {
+	calls := 0
+	find := func() (any, error) {
+		calls++
+		return map[string]any{"id": 1, "tags": []any{"a"}}, nil
+	}
+
+	// Without deduplication every lookup runs
+	ctx := context.Background()
+	Deduplicate(ctx, "User 1", find)
+	Deduplicate(ctx, "User 1", find)
+	if calls != 2 {
+		t.Errorf("lookups without deduplication ran %d times, want 2", calls)
+	}
+
+	calls = 0
+	ctx = WithDeduplication(ctx)
+	first, _ := Deduplicate(ctx, "User 1", find)
+	second, _ := Deduplicate(ctx, "User 1", find)
+	Deduplicate(ctx, "User 2", find)
+	if calls != 2 {
+		t.Errorf("deduplicated lookups ran %d times, want 2", calls)
+	}
+
+	// Callers get copies of the result
+	first.(map[string]any)["tags"].([]any)[0] = "changed"
+	if tag := second.(map[string]any)["tags"].([]any)[0]; tag != "a" {
+		t.Errorf("shared result changed to %v", tag)
+	}
+
+	// Writes drop the results
+	ForgetDeduplicated(ctx)
+	Deduplicate(ctx, "User 1", find)
+	if calls != 3 {
+		t.Errorf("lookup after a write ran %d times in all, want 3", calls)
+	}
+
+	// Errors are not kept
+	failures := 0
+	fail := func() (any, error) {
+		failures++
+		return nil, errors.New("failed")
+	}
+	Deduplicate(ctx, "User 3", fail)
+	if _, err := Deduplicate(ctx, "User 3", fail); err == nil || failures != 2 {
+		t.Errorf("failed lookup ran %d times with error %v, want 2 and an error", failures, err)
+	}
+}
@@ -27,6 +27,12 @@ type DriverCapabilities interface {
 	RequiresLimitForOffset() bool
 	SupportsDistinctOn() bool
 	SupportsForeignKeys() bool
+	SupportsUpsert() bool
+	SupportsJSONOperators() bool
+	SupportsFullText() bool
+
+	// Limits
+	MaxParameters() int // bound parameters per statement, 0 when unlimited
 
 	// Identifier quoting
 	QuoteIdentifier(name string) string
@@ -36,6 +42,12 @@ type DriverCapabilities interface {
 	GetBooleanLiteral(value bool) string
 	NeedsTypeConversion() bool
 	GetNullsOrderingSQL(direction Order, nullsFirst bool) string
+	GetUpsertSQL(conflictColumns []string, updateColumns []string) string
+	GetSkipDuplicatesSQL(insertColumns []string) string
+	GetArrayAppendSQL(quotedColumn string) string
+	GetArrayFilterSQL(operator string, quotedColumn string) string
+	EncodeArrayValue(values []any) (any, error)
+	DecodeArrayValue(value any) ([]any, error)
 
 	// Index/Table detection
 	IsSystemIndex(indexName string) bool
@@ -48,6 +60,8 @@ type DriverCapabilities interface {
 	// NoSQL features
 	IsNoSQL() bool
 	SupportsTransactions() bool
+	SupportsSavepoints() bool
+	SupportsTransactionalDDL() bool // schema changes roll back with their transaction
 	SupportsNestedDocuments() bool
 	SupportsArrayFields() bool
 	SupportsAggregationPipeline() bool
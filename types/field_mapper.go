@@ -33,24 +33,25 @@ func (m *DefaultFieldMapper) GetSchema(modelName string) (*schema.Schema, error)
 	return s, nil
 }
 
-// SchemaToColumn converts a schema field name to database column name
+// SchemaToColumn converts a schema field name to database column name. Dot-separated
+// paths into composite types (e.g. address.city) map to the stored path.
 func (m *DefaultFieldMapper) SchemaToColumn(modelName, fieldName string) (string, error) {
 	s, err := m.GetSchema(modelName)
 	if err != nil {
 		return "", err
 	}
 
-	return s.GetColumnNameByFieldName(fieldName)
+	return s.GetColumnPathByFieldPath(fieldName)
 }
 
-// ColumnToSchema converts a database column name to schema field name
+// ColumnToSchema converts a database column name, or a stored composite path, to schema field name
 func (m *DefaultFieldMapper) ColumnToSchema(modelName, columnName string) (string, error) {
 	s, err := m.GetSchema(modelName)
 	if err != nil {
 		return "", err
 	}
 
-	return s.GetFieldNameByColumnName(columnName)
+	return s.GetFieldPathByColumnPath(columnName)
 }
 
 // SchemaFieldsToColumns converts multiple schema field names to column names
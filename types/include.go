@@ -1,5 +1,10 @@
 package types
 
+import (
+	"fmt"
+	"strings"
+)
+
 // IncludeOption represents options for including relations
 type IncludeOption struct {
 	// The relation path (e.g., "posts" or "posts.comments")
@@ -29,3 +34,49 @@ type OrderByOption struct {
 
 // IncludeOptions represents a collection of include options
 type IncludeOptions map[string]*IncludeOption
+
+// RelationLoadStrategy selects how included relations are loaded
+type RelationLoadStrategy string
+
+const (
+	// RelationLoadJoin loads the main records and their relations with a single JOIN query
+	// and de-duplicates the joined rows (the default)
+	RelationLoadJoin RelationLoadStrategy = "join"
+
+	// RelationLoadQuery loads the main records first, then each relation level with one
+	// additional query filtered by the keys of the records above it
+	RelationLoadQuery RelationLoadStrategy = "query"
+)
+
+// DefaultMaxIncludeDepth is the number of relations include paths may go through, such as
+// 3 for "posts.comments.author", unless the database sets another limit
+const DefaultMaxIncludeDepth = 10
+
+// IncludeDepthLimiter is implemented by databases bounding the depth of include paths
+type IncludeDepthLimiter interface {
+	SetMaxIncludeDepth(depth int)
+	GetMaxIncludeDepth() int
+}
+
+// MaxIncludeDepth returns the include depth limit of db, DefaultMaxIncludeDepth unless db
+// sets one
+func MaxIncludeDepth(db Database) int {
+	if limiter, ok := db.(IncludeDepthLimiter); ok {
+		if depth := limiter.GetMaxIncludeDepth(); depth > 0 {
+			return depth
+		}
+	}
+	return DefaultMaxIncludeDepth
+}
+
+// CheckIncludeDepth returns an error for the first include path going through more
+// relations than the include depth limit of db
+func CheckIncludeDepth(db Database, paths []string) error {
+	limit := MaxIncludeDepth(db)
+	for _, path := range paths {
+		if depth := strings.Count(path, ".") + 1; depth > limit {
+			return fmt.Errorf("include %s is %d relations deep, more than the limit of %d", path, depth, limit)
+		}
+	}
+	return nil
+}
@@ -1,7 +1,10 @@
 package types
 
 import (
+	"context"
 	"time"
+
+	"github.com/rediwo/redi-orm/logger"
 )
 
 // MigrationMode represents the migration execution mode
@@ -19,6 +22,23 @@ type MigrationOptions struct {
 	Force         bool          // Force destructive changes without confirmation
 	Mode          MigrationMode // Migration mode (auto or file)
 	MigrationsDir string        // Directory containing migration files
+	// UpdatedAtTriggers adds database triggers maintaining @updatedAt columns of created
+	// tables, so updates that bypass the ORM keep them current too
+	UpdatedAtTriggers bool
+	// IgnoreChecksums applies pending file migrations even when the files of applied ones
+	// changed since they were applied, which is an error otherwise
+	IgnoreChecksums bool
+	// Logger receives the progress of auto-migrations, generated migrations and resets.
+	// Optional: the standard logger by default.
+	Logger logger.Logger
+	// LockTimeout is how long migration.Runner waits for the migration lock of another
+	// process. 0 waits migration.DefaultLockTimeout; a negative timeout runs without the lock.
+	LockTimeout time.Duration
+	// BeforeMigration is called before each migration is applied. An error stops the
+	// migration before it is applied.
+	BeforeMigration func(ctx context.Context, migration Migration) error
+	// AfterMigration is called after each migration is applied
+	AfterMigration func(ctx context.Context, migration Migration)
 }
 
 // ChangeType represents the type of schema change
@@ -34,6 +54,7 @@ const (
 	ChangeTypeDropIndex   ChangeType = "DROP_INDEX"
 	ChangeTypeAddFK       ChangeType = "ADD_FOREIGN_KEY"
 	ChangeTypeDropFK      ChangeType = "DROP_FOREIGN_KEY"
+	ChangeTypeAddTrigger  ChangeType = "ADD_TRIGGER"
 )
 
 // SchemaChange represents a single schema change
@@ -46,6 +67,9 @@ type SchemaChange struct {
 	// IndexDef stores index definition for DROP_INDEX changes
 	// This allows recreating the index during rollback
 	IndexDef *IndexDefinition `json:"index_def,omitempty"`
+	// DownSQL holds the statements reverting the change, found by diffing the other way: from
+	// the schemas back to the database as it is
+	DownSQL []string `json:"down_sql,omitempty"`
 }
 
 // IndexDefinition stores the definition of an index
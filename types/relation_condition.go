@@ -0,0 +1,152 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/rediwo/redi-orm/schema"
+)
+
+// RelationCondition filters records on the record a to-one relation holds, as in
+// where: { author: { is: { name: "Alice" } } }. It is compiled to an EXISTS subquery on the
+// related table, whose conditions are mapped on the related model.
+type RelationCondition struct {
+	ModelName string    // Model holding the relation, the model of the query when empty
+	Relation  string    // Name of the relation field
+	Condition Condition // Condition on the related record, or nil for its existence alone
+	Negate    bool      // Whether the related record must not match, as in isNot
+}
+
+// Is matches records whose relation holds a record matching condition. With a nil
+// condition, it matches records whose relation holds no record, as in { author: { is: null } }.
+func Is(relation string, condition Condition) *RelationCondition {
+	return &RelationCondition{Relation: relation, Condition: condition}
+}
+
+// IsNot matches records whose relation holds no record matching condition. With a nil
+// condition, it matches records whose relation holds a record.
+func IsNot(relation string, condition Condition) *RelationCondition {
+	return &RelationCondition{Relation: relation, Condition: condition, Negate: true}
+}
+
+// Exists reports whether the condition holds when a related record matching Condition
+// exists, rather than when none does
+func (c *RelationCondition) Exists() bool {
+	return (c.Condition != nil) != c.Negate
+}
+
+// ToSQL generates an EXISTS subquery on the related table
+func (c *RelationCondition) ToSQL(ctx *ConditionContext) (string, []any) {
+	if ctx == nil {
+		return "", nil
+	}
+	ctx = modelContext(ctx, c.ModelName)
+	join, err := c.resolve(ctx)
+	if err != nil {
+		// Left to the database to reject, naming the relation
+		return c.quote(ctx, c.Relation) + " IS NOT NULL", nil
+	}
+
+	sql := fmt.Sprintf("SELECT 1 FROM %s AS %s WHERE %s.%s = %s.%s",
+		c.quote(ctx, join.table), c.quote(ctx, join.alias),
+		c.quote(ctx, join.alias), c.quote(ctx, join.relatedColumn),
+		join.qualifier, c.quote(ctx, join.localColumn))
+
+	var args []any
+	if c.Condition != nil {
+		nested := &ConditionContext{
+			FieldMapper:     ctx.FieldMapper,
+			ModelName:       join.model,
+			TableAlias:      join.alias,
+			JoinedTables:    ctx.JoinedTables,
+			QuoteIdentifier: ctx.QuoteIdentifier,
+			Capabilities:    ctx.Capabilities,
+		}
+		nestedSQL, nestedArgs := c.Condition.ToSQL(nested)
+		if nestedSQL != "" {
+			sql += " AND (" + nestedSQL + ")"
+			args = nestedArgs
+		}
+	}
+
+	if c.Exists() {
+		return "EXISTS (" + sql + ")", args
+	}
+	return "NOT EXISTS (" + sql + ")", args
+}
+
+// relationJoin holds how the related table of a relation condition joins the table of the
+// query
+type relationJoin struct {
+	model         string
+	table         string
+	alias         string
+	qualifier     string // Table or alias of the query, quoted
+	localColumn   string // Column of the query table
+	relatedColumn string // Column of the related table
+}
+
+// resolve looks up the tables and columns joining the relation from the schemas of the
+// field mapper
+func (c *RelationCondition) resolve(ctx *ConditionContext) (*relationJoin, error) {
+	mapper, ok := ctx.FieldMapper.(interface {
+		GetSchema(modelName string) (*schema.Schema, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("field mapper does not provide schemas")
+	}
+	localSchema, err := mapper.GetSchema(ctx.ModelName)
+	if err != nil {
+		return nil, err
+	}
+	relation, err := localSchema.GetRelation(c.Relation)
+	if err != nil {
+		return nil, err
+	}
+	if relation.Type != schema.RelationManyToOne && relation.Type != schema.RelationOneToOne {
+		return nil, fmt.Errorf("relation %s of model %s is not a to-one relation", c.Relation, ctx.ModelName)
+	}
+	relatedSchema, err := mapper.GetSchema(relation.Model)
+	if err != nil {
+		return nil, err
+	}
+
+	localField, relatedField := relation.JoinFields(localSchema)
+	join := &relationJoin{model: relation.Model, table: relatedSchema.GetTableName()}
+	if join.localColumn, err = localSchema.GetColumnNameByFieldName(localField); err != nil {
+		return nil, err
+	}
+	if join.relatedColumn, err = relatedSchema.GetColumnNameByFieldName(relatedField); err != nil {
+		return nil, err
+	}
+
+	// The alias derives from the outer one, so that nested relation conditions do not clash
+	outer := ctx.TableAlias
+	if outer == "" {
+		outer = localSchema.GetTableName()
+	}
+	join.qualifier = c.quote(ctx, outer)
+	join.alias = outer + "_" + c.Relation
+	return join, nil
+}
+
+func (c *RelationCondition) quote(ctx *ConditionContext, name string) string {
+	if ctx.QuoteIdentifier != nil {
+		return ctx.QuoteIdentifier(name)
+	}
+	return name
+}
+
+// And combines this condition with another using AND logic
+func (c *RelationCondition) And(condition Condition) Condition {
+	return NewAndCondition(c, condition)
+}
+
+// Or combines this condition with another using OR logic
+func (c *RelationCondition) Or(condition Condition) Condition {
+	return NewOrCondition(c, condition)
+}
+
+// Not negates this condition
+func (c *RelationCondition) Not() Condition {
+	return NewNotCondition(c)
+}
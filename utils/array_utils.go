@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// EncodeJSONArray encodes a list as a JSON array, for drivers that store scalar lists in
+// JSON columns. A nil list is encoded as an empty array.
+func EncodeJSONArray(values []any) (string, error) {
+	if values == nil {
+		return "[]", nil
+	}
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode list as JSON: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// DecodeJSONArray decodes a list stored as a JSON array. NULL decodes to a nil list, and
+// lists that are already decoded are returned as they are.
+func DecodeJSONArray(value any) ([]any, error) {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []any:
+		return v, nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return nil, fmt.Errorf("cannot decode %T as a JSON array", value)
+	}
+
+	var values []any
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON array: %w", err)
+	}
+	if values == nil {
+		values = []any{}
+	}
+	return values, nil
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEncodeJSONArray(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []any
+		expected string
+	}{
+		{"nil list", nil, "[]"},
+		{"empty list", []any{}, "[]"},
+		{"strings", []any{"a", `b"c`}, `["a","b\"c"]`},
+		{"mixed scalars", []any{1, 2.5, true, nil}, `[1,2.5,true,null]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := EncodeJSONArray(tt.input)
+			if err != nil {
+				t.Fatalf("EncodeJSONArray(%v) error: %v", tt.input, err)
+			}
+			if result != tt.expected {
+				t.Errorf("EncodeJSONArray(%v) = %s, want %s", tt.input, result, tt.expected)
+			}
+		})
+	}
+
+	if _, err := EncodeJSONArray([]any{func() {}}); err == nil {
+		t.Error("Expected an error for a value that cannot be encoded")
+	}
+}
+
+func TestDecodeJSONArray(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected []any
+		wantErr  bool
+	}{
+		{"NULL", nil, nil, false},
+		{"string", `["a","b"]`, []any{"a", "b"}, false},
+		{"bytes", []byte(`[1,2]`), []any{float64(1), float64(2)}, false},
+		{"empty", "[]", []any{}, false},
+		{"JSON null", "null", []any{}, false},
+		{"decoded list", []any{"x"}, []any{"x"}, false},
+		{"not an array", `{"a":1}`, nil, true},
+		{"unsupported type", 42, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := DecodeJSONArray(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeJSONArray(%v) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("DecodeJSONArray(%v) = %#v, want %#v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// DecodeBytes converts a value for a binary field to bytes. []byte is returned as it is and
+// strings are decoded as base64, with or without padding, since JSON carries binary data
+// as base64 text. NULL decodes to nil.
+func DecodeBytes(value any) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return v, nil
+	case string:
+		if data, err := base64.StdEncoding.DecodeString(v); err == nil {
+			return data, nil
+		}
+		data, err := base64.RawStdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 data: %w", err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("cannot convert %T to bytes", value)
+	}
+}
+
+// EncodeBytes returns the base64 text of binary data, as it is written in JSON
+func EncodeBytes(data []byte) string {
+	return base64.StdEncoding.EncodeToString(data)
+}
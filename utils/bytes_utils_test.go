@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecodeBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected []byte
+		wantErr  bool
+	}{
+		{"NULL", nil, nil, false},
+		{"bytes", []byte{0, 1, 255}, []byte{0, 1, 255}, false},
+		{"base64", "AAH/", []byte{0, 1, 255}, false},
+		{"padded base64", "aGk=", []byte("hi"), false},
+		{"unpadded base64", "aGk", []byte("hi"), false},
+		{"empty string", "", []byte{}, false},
+		{"invalid base64", "not base64!", nil, true},
+		{"unsupported type", 42, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := DecodeBytes(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeBytes(%v) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && !bytes.Equal(result, tt.expected) {
+				t.Errorf("DecodeBytes(%v) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEncodeBytes(t *testing.T) {
+	if result := EncodeBytes([]byte{0, 1, 255}); result != "AAH/" {
+		t.Errorf("EncodeBytes() = %s, want AAH/", result)
+	}
+	if result := EncodeBytes(nil); result != "" {
+		t.Errorf("EncodeBytes(nil) = %s, want empty string", result)
+	}
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// envURIPrefix marks a database URI that names the environment variable holding the URI
+const envURIPrefix = "env:"
+
+// ResolveEnvURI returns the database URI that uri stands for. A URI written as
+// env:NAME is read from the environment variable NAME, other URIs are returned as is.
+func ResolveEnvURI(uri string) (string, error) {
+	name, ok := strings.CutPrefix(uri, envURIPrefix)
+	if !ok {
+		return uri, nil
+	}
+	value := os.Getenv(name)
+	if value == "" {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return value, nil
+}
+
+// LoadEnvFile sets environment variables from a .env file of NAME=value lines.
+// Variables that are already set are kept, so the environment overrides the file, and a
+// missing file is not an error. Values may be quoted; double quoted values support \n
+// escapes, and # starts a comment outside quotes.
+func LoadEnvFile(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	values, err := ParseEnv(data)
+	if err != nil {
+		return fmt.Errorf("invalid env file %s: %w", path, err)
+	}
+	for name, value := range values {
+		if _, exists := os.LookupEnv(name); exists {
+			continue
+		}
+		if err := os.Setenv(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseEnv parses the content of a .env file
+func ParseEnv(data []byte) (map[string]string, error) {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		name, value, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("line %d: expected NAME=value", lineNum)
+		}
+
+		value, err := parseEnvValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		values[name] = value
+	}
+	return values, scanner.Err()
+}
+
+// parseEnvValue unquotes a .env value and strips its trailing comment
+func parseEnvValue(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	quote := value[0]
+	if quote != '"' && quote != '\'' {
+		if i := strings.Index(value, " #"); i >= 0 {
+			value = value[:i]
+		}
+		return strings.TrimSpace(value), nil
+	}
+
+	end := -1
+	for i := 1; i < len(value); i++ {
+		if quote == '"' && value[i] == '\\' {
+			i++
+			continue
+		}
+		if value[i] == quote {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return "", fmt.Errorf("unterminated quoted value")
+	}
+	unquoted := value[1:end]
+	if quote == '"' {
+		unquoted = strings.NewReplacer(`\n`, "\n", `\"`, `"`, `\\`, `\`).Replace(unquoted)
+	}
+	return unquoted, nil
+}
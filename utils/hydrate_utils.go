@@ -0,0 +1,244 @@
+package utils
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// timeLayouts are the datetime formats returned as strings by the supported drivers
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+	"15:04:05.999999999Z07:00",
+	"15:04:05.999999999",
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+// structFieldKey identifies a cached schema field → struct field lookup
+type structFieldKey struct {
+	structType reflect.Type
+	key        string
+}
+
+// structFieldCache holds struct field indexes keyed by structFieldKey
+var structFieldCache sync.Map
+
+// HydrateStructs copies records keyed by schema field names into dest, which must be a
+// pointer to a slice of structs (or struct pointers). Nested maps and slices of maps are
+// copied into struct, pointer and slice fields, so included relations fill nested structs.
+func HydrateStructs(records []map[string]any, dest any) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("dest must be a pointer to slice")
+	}
+
+	sliceValue := destValue.Elem()
+	result := reflect.MakeSlice(sliceValue.Type(), 0, len(records))
+	for i, record := range records {
+		elem := reflect.New(sliceValue.Type().Elem()).Elem()
+		if err := assignValue(elem, record); err != nil {
+			return fmt.Errorf("record %d: %w", i, err)
+		}
+		result = reflect.Append(result, elem)
+	}
+	sliceValue.Set(result)
+	return nil
+}
+
+// HydrateStruct copies a record keyed by schema field names into dest, which must be a
+// pointer to a struct
+func HydrateStruct(record map[string]any, dest any) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.IsNil() {
+		return fmt.Errorf("dest must be a non-nil pointer")
+	}
+	return assignValue(destValue.Elem(), record)
+}
+
+// hydrateFields sets the struct fields matching the record keys
+func hydrateFields(structValue reflect.Value, record map[string]any) error {
+	structType := structValue.Type()
+	for key, value := range record {
+		index := findStructField(structType, key)
+		if index < 0 {
+			continue
+		}
+		field := structValue.Field(index)
+		if !field.CanSet() {
+			continue
+		}
+		if err := assignValue(field, value); err != nil {
+			return fmt.Errorf("field %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// findStructField returns the index of the exported struct field matching a schema field name
+func findStructField(structType reflect.Type, key string) int {
+	cacheKey := structFieldKey{structType: structType, key: key}
+	if index, ok := structFieldCache.Load(cacheKey); ok {
+		return index.(int)
+	}
+
+	index := matchStructField(structType, key)
+	structFieldCache.Store(cacheKey, index)
+	return index
+}
+
+// matchStructField searches the struct fields for a schema field name
+func matchStructField(structType reflect.Type, key string) int {
+	snakeKey := ToSnakeCase(key)
+	fallback := -1
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		// Tags take precedence over names
+		if tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag != "" && tag != "-" {
+			if tag == key {
+				return i
+			}
+		}
+		if tag := field.Tag.Get("db"); tag != "" && tag != "-" {
+			if tag == key || tag == snakeKey {
+				return i
+			}
+		}
+
+		if fallback < 0 && (strings.EqualFold(field.Name, key) || ToSnakeCase(field.Name) == snakeKey) {
+			fallback = i
+		}
+	}
+	return fallback
+}
+
+// assignValue converts value to the type of target and stores it
+func assignValue(target reflect.Value, value any) error {
+	if value == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	// Custom types decode themselves
+	if target.CanAddr() && target.Addr().Type().Implements(scannerType) {
+		return target.Addr().Interface().(sql.Scanner).Scan(value)
+	}
+
+	source := reflect.ValueOf(value)
+	if source.Type().AssignableTo(target.Type()) {
+		target.Set(source)
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(target.Type().Elem())
+		if err := assignValue(elem.Elem(), value); err != nil {
+			return err
+		}
+		target.Set(elem)
+		return nil
+
+	case reflect.Struct:
+		if target.Type() == timeType {
+			t, err := ToTime(value)
+			if err != nil {
+				return err
+			}
+			target.Set(reflect.ValueOf(t))
+			return nil
+		}
+		if record, ok := value.(map[string]any); ok {
+			return hydrateFields(target, record)
+		}
+
+	case reflect.Slice:
+		if target.Type().Elem().Kind() == reflect.Uint8 {
+			if s, ok := value.(string); ok {
+				target.SetBytes([]byte(s))
+				return nil
+			}
+		}
+		if source.Kind() == reflect.Slice || source.Kind() == reflect.Array {
+			result := reflect.MakeSlice(target.Type(), source.Len(), source.Len())
+			for i := 0; i < source.Len(); i++ {
+				if err := assignValue(result.Index(i), source.Index(i).Interface()); err != nil {
+					return fmt.Errorf("index %d: %w", i, err)
+				}
+			}
+			target.Set(result)
+			return nil
+		}
+
+	case reflect.String:
+		target.SetString(ToString(value))
+		return nil
+
+	case reflect.Bool:
+		target.SetBool(ToBool(value))
+		return nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		target.SetInt(ToInt64(value))
+		return nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		target.SetUint(uint64(ToInt64(value)))
+		return nil
+
+	case reflect.Float32, reflect.Float64:
+		target.SetFloat(ToFloat64(value))
+		return nil
+	}
+
+	// JSON columns arrive as text
+	if s, ok := value.(string); ok {
+		switch target.Kind() {
+		case reflect.Map, reflect.Slice, reflect.Struct, reflect.Interface:
+			return json.Unmarshal([]byte(s), target.Addr().Interface())
+		}
+	}
+
+	if source.Type().ConvertibleTo(target.Type()) {
+		target.Set(source.Convert(target.Type()))
+		return nil
+	}
+
+	return fmt.Errorf("cannot assign %T to %s", value, target.Type())
+}
+
+// ToTime converts driver datetime representations to time.Time
+func ToTime(value any) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("cannot parse %q as time", v)
+	case []byte:
+		return ToTime(string(v))
+	case int64:
+		return time.Unix(v, 0).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("cannot convert %T to time", value)
+}
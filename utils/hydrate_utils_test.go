@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type hydrateComment struct {
+	ID   int64  `db:"id"`
+	Body string `json:"body"`
+}
+
+type hydratePost struct {
+	ID       int              `db:"id"`
+	Title    string           `db:"title"`
+	AuthorID int              `db:"author_id"`
+	Tags     map[string]any   `db:"tags"`
+	Comments []hydrateComment `db:"-" json:"comments"`
+}
+
+type hydrateUser struct {
+	ID        int           `db:"id"`
+	Name      *string       `db:"name"`
+	Active    bool          `db:"active"`
+	Score     float64       `db:"score"`
+	CreatedAt time.Time     `db:"created_at"`
+	Posts     []hydratePost `json:"posts"`
+	Manager   *hydrateUser  `json:"manager"`
+}
+
+func TestHydrateStructs(t *testing.T) {
+	records := []map[string]any{
+		{
+			"id":        int64(1),
+			"name":      "Alice",
+			"active":    int64(1),
+			"score":     "9.5",
+			"createdAt": "2024-01-15 12:34:56",
+			"unknown":   "skipped",
+			"posts": []any{
+				map[string]any{
+					"id":       int64(10),
+					"title":    "Hello",
+					"authorId": int64(1),
+					"tags":     `{"lang":"go"}`,
+					"comments": []any{map[string]any{"id": int64(100), "body": "Nice"}},
+				},
+			},
+			"manager": map[string]any{"id": int64(2), "name": nil},
+		},
+		{"id": int64(2), "name": nil, "active": false, "manager": nil},
+	}
+
+	var users []hydrateUser
+	require.NoError(t, HydrateStructs(records, &users))
+	require.Len(t, users, 2)
+
+	alice := users[0]
+	assert.Equal(t, 1, alice.ID)
+	require.NotNil(t, alice.Name)
+	assert.Equal(t, "Alice", *alice.Name)
+	assert.True(t, alice.Active)
+	assert.Equal(t, 9.5, alice.Score)
+	assert.Equal(t, time.Date(2024, 1, 15, 12, 34, 56, 0, time.UTC), alice.CreatedAt)
+
+	require.Len(t, alice.Posts, 1)
+	assert.Equal(t, "Hello", alice.Posts[0].Title)
+	assert.Equal(t, 1, alice.Posts[0].AuthorID)
+	assert.Equal(t, map[string]any{"lang": "go"}, alice.Posts[0].Tags)
+	require.Len(t, alice.Posts[0].Comments, 1)
+	assert.Equal(t, "Nice", alice.Posts[0].Comments[0].Body)
+
+	require.NotNil(t, alice.Manager)
+	assert.Equal(t, 2, alice.Manager.ID)
+	assert.Nil(t, alice.Manager.Name)
+
+	assert.Nil(t, users[1].Name)
+	assert.Nil(t, users[1].Manager)
+	assert.Empty(t, users[1].Posts)
+}
+
+func TestHydrateStructsPointerElements(t *testing.T) {
+	var users []*hydrateUser
+	require.NoError(t, HydrateStructs([]map[string]any{{"id": 7}}, &users))
+	require.Len(t, users, 1)
+	assert.Equal(t, 7, users[0].ID)
+}
+
+func TestHydrateStruct(t *testing.T) {
+	var post hydratePost
+	require.NoError(t, HydrateStruct(map[string]any{"id": "3", "title": []byte("Bytes")}, &post))
+	assert.Equal(t, 3, post.ID)
+	assert.Equal(t, "Bytes", post.Title)
+
+	assert.Error(t, HydrateStruct(map[string]any{}, post))
+	assert.Error(t, HydrateStructs(nil, &post))
+}
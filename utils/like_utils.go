@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// LikeEscape is the escape character of LIKE patterns built from user input, declared with
+// ESCAPE '!'. It is not a backslash, whose meaning in string literals differs between
+// databases.
+const LikeEscape = '!'
+
+// EscapeLike escapes the LIKE wildcards of value, so that it matches literally in a pattern
+// escaped with LikeEscape: "50%" becomes "50!%"
+func EscapeLike(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r == '%' || r == '_' || r == LikeEscape {
+			b.WriteRune(LikeEscape)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// LikeToRegexp converts a LIKE pattern to a regular expression matching the same strings.
+// escape is the escape character of the pattern, or 0 without any. The expression is only
+// anchored where the pattern does not start or end with %, so that prefixes stay usable by
+// indexes.
+func LikeToRegexp(pattern string, escape rune) string {
+	var b strings.Builder
+	runes := []rune(pattern)
+	if len(runes) == 0 || runes[0] != '%' {
+		b.WriteString("^")
+	}
+	anchorEnd := true
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case escape != 0 && r == escape && i+1 < len(runes):
+			i++
+			b.WriteString(regexp.QuoteMeta(string(runes[i])))
+		case r == '%':
+			// Leading and trailing wildcards are left to the missing anchors
+			if i == len(runes)-1 {
+				anchorEnd = false
+			} else if i > 0 {
+				b.WriteString(".*")
+			}
+		case r == '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	if anchorEnd {
+		b.WriteString("$")
+	}
+	return b.String()
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, "plain", EscapeLike("plain"))
+	assert.Equal(t, "50!%", EscapeLike("50%"))
+	assert.Equal(t, "snake!_case", EscapeLike("snake_case"))
+	assert.Equal(t, "wow!!", EscapeLike("wow!"))
+	assert.Equal(t, `back\slash`, EscapeLike(`back\slash`))
+}
+
+func TestLikeToRegexp(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		escape   rune
+		expected string
+		matches  []string
+		misses   []string
+	}{
+		{"%abc%", 0, "abc", []string{"abc", "xabcx"}, []string{"ab"}},
+		{"abc%", 0, "^abc", []string{"abcdef"}, []string{"xabc"}},
+		{"%abc", 0, "abc$", []string{"xabc"}, []string{"abcx"}},
+		{"a_c", 0, "^a.c$", []string{"abc"}, []string{"ac", "abbc"}},
+		{"a%c", 0, "^a.*c$", []string{"ac", "abbc"}, []string{"acb"}},
+		{"%", 0, "", []string{"", "anything"}, nil},
+		{"%1.5!%%", LikeEscape, `1\.5%`, []string{"v1.5%"}, []string{"v1x5%", "1.5"}},
+		{"!_id%", LikeEscape, `^_id`, []string{"_id"}, []string{"xid"}},
+		{"a!!b", LikeEscape, `^a!b$`, []string{"a!b"}, []string{"a!!b"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pattern, func(t *testing.T) {
+			expr := LikeToRegexp(test.pattern, test.escape)
+			assert.Equal(t, test.expected, expr)
+			re := regexp.MustCompile(expr)
+			for _, s := range test.matches {
+				assert.True(t, re.MatchString(s), "%q should match %q", test.pattern, s)
+			}
+			for _, s := range test.misses {
+				assert.False(t, re.MatchString(s), "%q should not match %q", test.pattern, s)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// maxExactFloatInt is the largest integer below which every integer is exact in a float64
+const maxExactFloatInt = 1 << 53
+
+// ToDecimalString converts a value for a decimal field to its exact decimal text. Strings
+// and JSON numbers are validated and kept as written, so that "20.00" keeps its scale, and
+// floats are written with the fewest digits that read back as the same float.
+func ToDecimalString(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return parseDecimalText(v)
+	case json.Number:
+		return parseDecimalText(v.String())
+	case []byte:
+		return parseDecimalText(string(v))
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v), nil
+	default:
+		return "", fmt.Errorf("cannot convert %T to decimal", value)
+	}
+}
+
+// parseDecimalText checks that text is a decimal number and returns it without surrounding
+// spaces
+func parseDecimalText(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if _, ok := new(big.Rat).SetString(text); !ok || strings.ContainsAny(text, "/xXpP") {
+		return "", fmt.Errorf("invalid decimal %q", text)
+	}
+	return text, nil
+}
+
+// ToBigInt converts a value for a 64-bit integer field to int64 without going through
+// float64, so that integers beyond 2^53 stay exact. Floats must be whole numbers.
+func ToBigInt(value any) (int64, error) {
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case string:
+		return strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+	case json.Number:
+		return strconv.ParseInt(v.String(), 10, 64)
+	case []byte:
+		return strconv.ParseInt(string(v), 10, 64)
+	case float64:
+		if v != float64(int64(v)) {
+			return 0, fmt.Errorf("%v is not an integer", v)
+		}
+		return int64(v), nil
+	case int8, int16, uint8, uint16, uint32:
+		return ToInt64(v), nil
+	case uint:
+		return ToBigInt(uint64(v))
+	case uint64:
+		if v > math.MaxInt64 {
+			return 0, fmt.Errorf("%d overflows int64", v)
+		}
+		return int64(v), nil
+	default:
+		return 0, fmt.Errorf("cannot convert %T to integer", value)
+	}
+}
+
+// NormalizeJSONNumbers replaces, in place, the json.Number values of JSON decoded with
+// UseNumber. Numbers that a float64 holds exactly become float64, as with a plain decode.
+// Larger integers become int64 and other numbers stay json.Number, so that their digits
+// reach the fields they are written to instead of being rounded.
+func NormalizeJSONNumbers(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		return normalizeJSONNumber(v)
+	case map[string]any:
+		for key, item := range v {
+			v[key] = NormalizeJSONNumbers(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = NormalizeJSONNumbers(item)
+		}
+	}
+	return value
+}
+
+func normalizeJSONNumber(number json.Number) any {
+	if n, err := number.Int64(); err == nil {
+		if n > -maxExactFloatInt && n < maxExactFloatInt {
+			return float64(n)
+		}
+		return n
+	}
+	f, err := number.Float64()
+	if err != nil {
+		return number
+	}
+	exact, ok := new(big.Rat).SetString(number.String())
+	shortest, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	if !ok || exact.Cmp(shortest) != 0 {
+		return number
+	}
+	return f
+}
+
+// UnmarshalJSON decodes JSON into v like json.Unmarshal, with numbers normalized by
+// NormalizeJSONNumbers. v is a *any, *map[string]any or *[]any.
+func UnmarshalJSON(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return fmt.Errorf("invalid data after top-level JSON value")
+	}
+	switch target := v.(type) {
+	case *any:
+		*target = NormalizeJSONNumbers(*target)
+	case *map[string]any:
+		NormalizeJSONNumbers(*target)
+	case *[]any:
+		NormalizeJSONNumbers(*target)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestToDecimalString(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected string
+		wantErr  bool
+	}{
+		{"text", "12345678901234567.89", "12345678901234567.89", false},
+		{"text keeps scale", "20.00", "20.00", false},
+		{"negative text", " -0.5 ", "-0.5", false},
+		{"json number", json.Number("1.10"), "1.10", false},
+		{"bytes", []byte("3.14"), "3.14", false},
+		{"float", 19.99, "19.99", false},
+		{"large float", 1e21, "1000000000000000000000", false},
+		{"int", 42, "42", false},
+		{"int64", int64(9007199254740993), "9007199254740993", false},
+		{"invalid text", "ten", "", true},
+		{"fraction", "1/3", "", true},
+		{"unsupported type", true, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ToDecimalString(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToDecimalString(%v) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if result != tt.expected {
+				t.Errorf("ToDecimalString(%v) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestToBigInt(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected int64
+		wantErr  bool
+	}{
+		{"int64", int64(9007199254740993), 9007199254740993, false},
+		{"int", 42, 42, false},
+		{"text", "9007199254740993", 9007199254740993, false},
+		{"json number", json.Number("-9007199254740993"), -9007199254740993, false},
+		{"whole float", float64(1 << 40), 1 << 40, false},
+		{"uint64", uint64(7), 7, false},
+		{"fractional float", 1.5, 0, true},
+		{"overflowing uint64", uint64(1 << 63), 0, true},
+		{"invalid text", "12.5", 0, true},
+		{"unsupported type", true, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ToBigInt(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToBigInt(%v) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if result != tt.expected {
+				t.Errorf("ToBigInt(%v) = %d, want %d", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	var result map[string]any
+	data := `{"take": 10, "price": 19.99, "id": 9007199254740993, "exact": 12345678901234567.89, "list": [1.5, 1e3]}`
+	if err := UnmarshalJSON([]byte(data), &result); err != nil {
+		t.Fatalf("UnmarshalJSON() unexpected error: %v", err)
+	}
+
+	expected := map[string]any{
+		"take":  float64(10),
+		"price": 19.99,
+		"id":    int64(9007199254740993),
+		"exact": json.Number("12345678901234567.89"),
+		"list":  []any{1.5, float64(1000)},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("UnmarshalJSON() = %#v, want %#v", result, expected)
+	}
+
+	if err := UnmarshalJSON([]byte(`{"a": 1} {}`), &result); err == nil {
+		t.Error("UnmarshalJSON() expected an error for data after the value")
+	}
+}
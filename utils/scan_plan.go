@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// scanPlan is a precomputed column → struct field assignment for one destination type
+// and result column list, so repeated scans skip the per-row field lookup
+type scanPlan struct {
+	fields [][]int // field index path per column, nil when no field matches
+}
+
+// scanPlanKey identifies a plan; the column list stands in for the queried schema
+type scanPlanKey struct {
+	destType reflect.Type
+	columns  string
+}
+
+// scanPlanCache holds compiled plans keyed by scanPlanKey
+var scanPlanCache sync.Map
+
+// getScanPlan returns the cached plan for scanning columns into destType, compiling it on first use
+func getScanPlan(destType reflect.Type, columns []string) *scanPlan {
+	key := scanPlanKey{destType: destType, columns: strings.Join(columns, "\x00")}
+	if plan, ok := scanPlanCache.Load(key); ok {
+		return plan.(*scanPlan)
+	}
+
+	plan := &scanPlan{fields: make([][]int, len(columns))}
+	for i, col := range columns {
+		if field, ok := destType.FieldByNameFunc(columnMatcher(destType, col)); ok {
+			plan.fields[i] = field.Index
+		}
+	}
+
+	actual, _ := scanPlanCache.LoadOrStore(key, plan)
+	return actual.(*scanPlan)
+}
+
+// columnMatcher returns the field name predicate used to find the field for a column
+func columnMatcher(destType reflect.Type, col string) func(string) bool {
+	return func(name string) bool {
+		field, _ := destType.FieldByName(name)
+
+		// 1. Check struct tag for db column name
+		if tag := field.Tag.Get("db"); tag != "" {
+			return tag == col
+		}
+
+		// 2. Check json tag as fallback
+		if tag := field.Tag.Get("json"); tag != "" {
+			return tag == col
+		}
+
+		// 3. Case-insensitive match
+		if strings.EqualFold(field.Name, col) {
+			return true
+		}
+
+		// 4. Try camelCase/snake_case conversion
+		return ToCamelCase(col) == name || ToSnakeCase(name) == col
+	}
+}
+
+// newScanDestinations allocates the scan argument slice for a plan; columns without a
+// field share discard holders that are reused across rows
+func (p *scanPlan) newScanDestinations() []any {
+	scanDest := make([]any, len(p.fields))
+	for i, index := range p.fields {
+		if index == nil {
+			var dummy any
+			scanDest[i] = &dummy
+		}
+	}
+	return scanDest
+}
+
+// bind points the scan arguments at the fields of destValue
+func (p *scanPlan) bind(destValue reflect.Value, scanDest []any) {
+	for i, index := range p.fields {
+		if index == nil {
+			continue
+		}
+
+		field, err := destValue.FieldByIndexErr(index)
+		if err != nil || !field.CanSet() {
+			// Nil embedded pointer or unexported field
+			var dummy any
+			scanDest[i] = &dummy
+			continue
+		}
+		scanDest[i] = field.Addr().Interface()
+	}
+}
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type planAudit struct {
+	CreatedBy string `db:"created_by"`
+}
+
+type planRecord struct {
+	ID       int    `db:"id"`
+	FullName string `json:"full_name"`
+	Score    float64
+	*planAudit
+}
+
+func TestGetScanPlan(t *testing.T) {
+	columns := []string{"id", "full_name", "score", "created_by", "unknown"}
+	plan := getScanPlan(reflect.TypeOf(planRecord{}), columns)
+
+	assert.Equal(t, []int{0}, plan.fields[0])
+	assert.Equal(t, []int{1}, plan.fields[1])
+	assert.Equal(t, []int{2}, plan.fields[2])
+	assert.Equal(t, []int{3, 0}, plan.fields[3])
+	assert.Nil(t, plan.fields[4])
+
+	// Plans are compiled once per destination type and column list
+	assert.Same(t, plan, getScanPlan(reflect.TypeOf(planRecord{}), columns))
+	assert.NotSame(t, plan, getScanPlan(reflect.TypeOf(planRecord{}), columns[:2]))
+}
+
+func TestScanPlanBind(t *testing.T) {
+	plan := getScanPlan(reflect.TypeOf(planRecord{}), []string{"id", "created_by", "unknown"})
+	scanDest := plan.newScanDestinations()
+
+	// Nil embedded pointers fall back to a discard holder
+	var record planRecord
+	plan.bind(reflect.ValueOf(&record).Elem(), scanDest)
+	assert.Same(t, &record.ID, scanDest[0])
+	assert.IsType(t, new(any), scanDest[1])
+
+	record.planAudit = &planAudit{}
+	plan.bind(reflect.ValueOf(&record).Elem(), scanDest)
+	assert.Same(t, &record.planAudit.CreatedBy, scanDest[1])
+	assert.IsType(t, new(any), scanDest[2])
+}
+
+func BenchmarkScanRowsStruct(b *testing.B) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(b, err)
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE records (id INTEGER PRIMARY KEY, full_name TEXT, score REAL)`)
+	require.NoError(b, err)
+	for i := 0; i < 100; i++ {
+		_, err = db.Exec(`INSERT INTO records (full_name, score) VALUES (?, ?)`, "name", float64(i))
+		require.NoError(b, err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rows, err := db.Query(`SELECT id, full_name, score FROM records`)
+		if err != nil {
+			b.Fatal(err)
+		}
+		var records []planRecord
+		if err := ScanRows(rows, &records); err != nil {
+			b.Fatal(err)
+		}
+		rows.Close()
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"database/sql"
 	"fmt"
 	"reflect"
-	"strings"
 )
 
 // ScanRows scans multiple rows into a slice with smart field mapping
@@ -45,12 +44,13 @@ func ScanRows(rows *sql.Rows, dest any) error {
 		return fmt.Errorf("failed to get columns: %w", err)
 	}
 
+	// Resolve the column → field assignment once for all rows
+	plan := getScanPlan(elementType, columns)
+	scanDest := plan.newScanDestinations()
+
 	for rows.Next() {
 		elem := reflect.New(elementType).Elem()
-		scanDest, err := prepareScanDestinations(elem, elementType, columns)
-		if err != nil {
-			return err
-		}
+		plan.bind(elem, scanDest)
 
 		if err := rows.Scan(scanDest...); err != nil {
 			return fmt.Errorf("failed to scan row: %w", err)
@@ -171,40 +171,9 @@ func ScanRowContext(db interface {
 
 // prepareScanDestinations prepares scan destinations with smart field mapping
 func prepareScanDestinations(destValue reflect.Value, destType reflect.Type, columns []string) ([]any, error) {
-	scanDest := make([]any, len(columns))
-
-	for i, col := range columns {
-		field := destValue.FieldByNameFunc(func(name string) bool {
-			field, _ := destType.FieldByName(name)
-
-			// 1. Check struct tag for db column name
-			if tag := field.Tag.Get("db"); tag != "" {
-				return tag == col
-			}
-
-			// 2. Check json tag as fallback
-			if tag := field.Tag.Get("json"); tag != "" {
-				return tag == col
-			}
-
-			// 3. Case-insensitive match
-			if strings.EqualFold(field.Name, col) {
-				return true
-			}
-
-			// 4. Try camelCase/snake_case conversion
-			return ToCamelCase(col) == name || ToSnakeCase(name) == col
-		})
-
-		if field.IsValid() && field.CanSet() {
-			scanDest[i] = field.Addr().Interface()
-		} else {
-			// Field not found or can't be set, use a dummy scanner
-			var dummy any
-			scanDest[i] = &dummy
-		}
-	}
-
+	plan := getScanPlan(destType, columns)
+	scanDest := plan.newScanDestinations()
+	plan.bind(destValue, scanDest)
 	return scanDest, nil
 }
 
@@ -0,0 +1,198 @@
+package utils
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ExpandSQLArgs rewrites a raw SQL statement so it can be executed with positional "?" arguments:
+//   - Named parameters (":name") are bound from a single map[string]any argument or from
+//     sql.NamedArg arguments (sql.Named("name", value))
+//   - Slice arguments are expanded into one placeholder per element, so "IN (?)" with
+//     []int{1, 2, 3} becomes "IN (?, ?, ?)"; an empty slice becomes NULL and matches nothing
+//
+// Quoted strings, quoted identifiers and comments are left untouched. []byte values are
+// passed through as single values.
+func ExpandSQLArgs(query string, args []any) (string, []any, error) {
+	named, isNamed := namedArgs(args)
+	if !isNamed && !hasSliceArg(args) {
+		return query, args, nil
+	}
+
+	var b strings.Builder
+	b.Grow(len(query))
+	var expanded []any
+	argIndex := 0
+
+	writeValue := func(value any) {
+		values, ok := sliceValues(value)
+		if !ok {
+			b.WriteByte('?')
+			expanded = append(expanded, value)
+			return
+		}
+		if len(values) == 0 {
+			b.WriteString("NULL")
+			return
+		}
+		for i, v := range values {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteByte('?')
+			expanded = append(expanded, v)
+		}
+	}
+
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		switch {
+		case ch == '\'' || ch == '"' || ch == '`':
+			end := skipQuoted(query, i, ch)
+			b.WriteString(query[i:end])
+			i = end - 1
+
+		case ch == '-' && i+1 < len(query) && query[i+1] == '-':
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query) - i
+			}
+			b.WriteString(query[i : i+end])
+			i += end - 1
+
+		case ch == '/' && i+1 < len(query) && query[i+1] == '*':
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				end = len(query) - i - 2
+			} else {
+				end += 2
+			}
+			b.WriteString(query[i : i+2+end])
+			i += 1 + end
+
+		case ch == '?' && !isNamed:
+			if argIndex >= len(args) {
+				return "", nil, fmt.Errorf("not enough arguments for placeholders: got %d", len(args))
+			}
+			writeValue(args[argIndex])
+			argIndex++
+
+		case ch == ':' && isNamed:
+			// "::" is a PostgreSQL cast and ":=" an assignment
+			if i+1 < len(query) && (query[i+1] == ':' || query[i+1] == '=') {
+				b.WriteString(query[i : i+2])
+				i++
+				continue
+			}
+			end := i + 1
+			for end < len(query) && isIdentChar(query[end], end == i+1) {
+				end++
+			}
+			if end == i+1 {
+				b.WriteByte(ch)
+				continue
+			}
+			name := query[i+1 : end]
+			value, ok := named[name]
+			if !ok {
+				return "", nil, fmt.Errorf("missing value for named parameter :%s", name)
+			}
+			writeValue(value)
+			i = end - 1
+
+		default:
+			b.WriteByte(ch)
+		}
+	}
+
+	if !isNamed && argIndex < len(args) {
+		// More arguments than placeholders (e.g. $1 style SQL) - leave the statement as is
+		return query, args, nil
+	}
+
+	return b.String(), expanded, nil
+}
+
+// namedArgs returns the named parameter values if args use named binding
+func namedArgs(args []any) (map[string]any, bool) {
+	if len(args) == 1 {
+		if m, ok := args[0].(map[string]any); ok {
+			return m, true
+		}
+	}
+
+	if len(args) == 0 {
+		return nil, false
+	}
+	named := make(map[string]any, len(args))
+	for _, arg := range args {
+		n, ok := arg.(sql.NamedArg)
+		if !ok {
+			return nil, false
+		}
+		named[n.Name] = n.Value
+	}
+	return named, true
+}
+
+// hasSliceArg reports whether any argument needs expansion
+func hasSliceArg(args []any) bool {
+	for _, arg := range args {
+		if _, ok := sliceValues(arg); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// sliceValues returns the elements of a slice or array argument; []byte is treated as a scalar
+func sliceValues(value any) ([]any, bool) {
+	switch v := value.(type) {
+	case nil, string, []byte, int, int64, float64, bool:
+		return nil, false
+	case []any:
+		return v, true
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	if rv.Type().Elem().Kind() == reflect.Uint8 {
+		return nil, false
+	}
+
+	values := make([]any, rv.Len())
+	for i := range values {
+		values[i] = rv.Index(i).Interface()
+	}
+	return values, true
+}
+
+// skipQuoted returns the index just past the quoted section starting at start; doubled
+// quotes and backslash escapes stay inside the section
+func skipQuoted(query string, start int, quote byte) int {
+	for i := start + 1; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			i++
+		case quote:
+			if i+1 < len(query) && query[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(query)
+}
+
+// isIdentChar reports whether ch can be part of a parameter name
+func isIdentChar(ch byte, first bool) bool {
+	if ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') {
+		return true
+	}
+	return !first && ch >= '0' && ch <= '9'
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandSQLArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		args      []any
+		wantQuery string
+		wantArgs  []any
+	}{
+		{
+			name:      "plain arguments unchanged",
+			query:     "SELECT * FROM users WHERE id = ?",
+			args:      []any{1},
+			wantQuery: "SELECT * FROM users WHERE id = ?",
+			wantArgs:  []any{1},
+		},
+		{
+			name:      "slice expands to placeholder list",
+			query:     "SELECT * FROM users WHERE id IN (?) AND active = ?",
+			args:      []any{[]int{1, 2, 3}, true},
+			wantQuery: "SELECT * FROM users WHERE id IN (?, ?, ?) AND active = ?",
+			wantArgs:  []any{1, 2, 3, true},
+		},
+		{
+			name:      "empty slice matches nothing",
+			query:     "SELECT * FROM users WHERE id IN (?)",
+			args:      []any{[]string{}},
+			wantQuery: "SELECT * FROM users WHERE id IN (NULL)",
+			wantArgs:  nil,
+		},
+		{
+			name:      "byte slice is a single value",
+			query:     "UPDATE files SET data = ? WHERE id IN (?)",
+			args:      []any{[]byte("abc"), []int64{7, 8}},
+			wantQuery: "UPDATE files SET data = ? WHERE id IN (?, ?)",
+			wantArgs:  []any{[]byte("abc"), int64(7), int64(8)},
+		},
+		{
+			name:      "named parameters from map",
+			query:     "SELECT * FROM users WHERE name = :name AND id IN (:ids) OR email = :name",
+			args:      []any{map[string]any{"name": "alice", "ids": []any{1, 2}}},
+			wantQuery: "SELECT * FROM users WHERE name = ? AND id IN (?, ?) OR email = ?",
+			wantArgs:  []any{"alice", 1, 2, "alice"},
+		},
+		{
+			name:      "named parameters from sql.Named",
+			query:     "SELECT * FROM users WHERE age > :min_age",
+			args:      []any{sql.Named("min_age", 18)},
+			wantQuery: "SELECT * FROM users WHERE age > ?",
+			wantArgs:  []any{18},
+		},
+		{
+			name:      "quotes, comments and casts are skipped",
+			query:     "SELECT ':skip', \"a?b\", created_at::date FROM t -- :c ?\nWHERE x = :x /* :y */",
+			args:      []any{map[string]any{"x": 1}},
+			wantQuery: "SELECT ':skip', \"a?b\", created_at::date FROM t -- :c ?\nWHERE x = ? /* :y */",
+			wantArgs:  []any{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := ExpandSQLArgs(tt.query, tt.args)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantQuery, query)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestExpandSQLArgsErrors(t *testing.T) {
+	_, _, err := ExpandSQLArgs("SELECT * FROM users WHERE id = :id", []any{map[string]any{"name": "x"}})
+	assert.ErrorContains(t, err, ":id")
+
+	_, _, err = ExpandSQLArgs("SELECT * FROM users WHERE id IN (?) AND name = ?", []any{[]int{1}})
+	assert.Error(t, err)
+}
@@ -238,3 +238,11 @@ func Singularize(s string) string {
 
 	return s
 }
+
+// QuoteIdentifier quotes a table, column or index name with the quote character of an SQL
+// dialect, doubling the quote characters within it, so that reserved words such as order or
+// group and mixed-case names can be used as identifiers
+func QuoteIdentifier(name string, quote byte) string {
+	q := string(quote)
+	return q + strings.ReplaceAll(name, q, q+q) + q
+}
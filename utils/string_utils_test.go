@@ -312,3 +312,23 @@ func TestEdgeCases(t *testing.T) {
 		})
 	})
 }
+
+func TestQuoteIdentifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		quote    byte
+		expected string
+	}{
+		{"order", '"', `"order"`},
+		{"group", '`', "`group`"},
+		{"userName", '"', `"userName"`},
+		{`say "hi"`, '"', `"say ""hi"""`},
+		{"a`b", '`', "`a``b`"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, QuoteIdentifier(test.name, test.quote))
+		})
+	}
+}
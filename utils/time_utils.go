@@ -0,0 +1,43 @@
+package utils
+
+import "time"
+
+// Layouts of the text that @db.Date and @db.Time values are bound as
+const (
+	DateLayout      = "2006-01-02"
+	TimeOfDayLayout = "15:04:05.999999"
+)
+
+// UTCDate returns midnight UTC of the UTC date of t, the value of a @db.Date field
+func UTCDate(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// UTCTimeOfDay returns the UTC time of day of t on 1970-01-01, the value of a @db.Time field
+func UTCTimeOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(1970, 1, 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// NormalizeTimeArgs converts the time.Time arguments of a statement to UTC. Columns without
+// a time zone keep the wall clock of the value they are given, so binding every time in UTC
+// makes DateTime values read back as the same instant on every driver. The given slice is
+// not modified.
+func NormalizeTimeArgs(args []any) []any {
+	var normalized []any
+	for i, arg := range args {
+		t, ok := arg.(time.Time)
+		if !ok || t.Location() == time.UTC {
+			continue
+		}
+		if normalized == nil {
+			normalized = append([]any(nil), args...)
+		}
+		normalized[i] = t.UTC()
+	}
+	if normalized == nil {
+		return args
+	}
+	return normalized
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUTCDateAndTimeOfDay(t *testing.T) {
+	value := time.Date(2024, 3, 1, 20, 15, 30, 500, time.FixedZone("UTC-5", -5*60*60))
+
+	if got, want := UTCDate(value), time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC); !got.Equal(want) || got.Location() != time.UTC {
+		t.Errorf("UTCDate() = %v, want %v", got, want)
+	}
+	if got, want := UTCTimeOfDay(value), time.Date(1970, 1, 1, 1, 15, 30, 500, time.UTC); !got.Equal(want) || got.Location() != time.UTC {
+		t.Errorf("UTCTimeOfDay() = %v, want %v", got, want)
+	}
+}
+
+func TestNormalizeTimeArgs(t *testing.T) {
+	utc := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	args := []any{1, utc}
+	if result := NormalizeTimeArgs(args); &result[0] != &args[0] {
+		t.Error("Expected args without local times to be returned as is")
+	}
+
+	local := utc.In(time.FixedZone("UTC+2", 2*60*60))
+	args = []any{"name", local}
+	result := NormalizeTimeArgs(args)
+	if got := result[1].(time.Time); got.Location() != time.UTC || !got.Equal(local) {
+		t.Errorf("Expected %v in UTC, got %v", local, got)
+	}
+	if args[1].(time.Time).Location() == time.UTC {
+		t.Error("Expected the given args to be left unchanged")
+	}
+}
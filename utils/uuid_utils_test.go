@@ -0,0 +1,17 @@
+package utils
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestNewUUID(t *testing.T) {
+	pattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	first, second := NewUUID(), NewUUID()
+	if !pattern.MatchString(first) {
+		t.Errorf("NewUUID() = %q, want a version 4 UUID", first)
+	}
+	if first == second {
+		t.Errorf("NewUUID() returned %q twice", first)
+	}
+}